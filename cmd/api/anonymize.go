@@ -0,0 +1,71 @@
+package main
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// =============================================================================
+// SOUS-COMMANDE "anonymize" : copie pseudonymisée pour la staging
+// =============================================================================
+
+// anonymizeOptions options de `api anonymize -out FICHIER [-password P]`
+// Les données lues sont celles de la persistance configurée (mode "sql" en pratique)
+type anonymizeOptions struct {
+	out      string
+	password string
+}
+
+func parseAnonymizeOptions(args []string) (*anonymizeOptions, error) {
+	opts := &anonymizeOptions{}
+	fs := flag.NewFlagSet("anonymize", flag.ContinueOnError)
+	fs.StringVar(&opts.out, "out", "", "fichier d'export (JSON lines) à produire")
+	fs.StringVar(&opts.password, "password", "", "mot de passe commun des comptes copiés ; vide = aucun compte utilisable par mot de passe")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if opts.out == "" {
+		return nil, fmt.Errorf("-out est obligatoire")
+	}
+	return opts, nil
+}
+
+// runAnonymize écrit la copie dans un fichier temporaire renommé à la fin :
+// un export interrompu ne laisse jamais de fichier partiel à importer
+func runAnonymize(ctx context.Context, opts *anonymizeOptions, anonymize usecases.UseCase[usecases.AnonymizeDataRequest, *usecases.AnonymizeDataResponse], logger usecases.Logger) error {
+	tmp := opts.out + ".partial"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer file.Close()
+
+	writer := services.NewJSONLinesExportWriter(file)
+	response, err := anonymize.Execute(ctx, usecases.AnonymizeDataRequest{Writer: writer, Password: opts.password})
+	if err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, opts.out); err != nil {
+		return err
+	}
+
+	logger.Info("Anonymized export written", map[string]interface{}{
+		"file":           opts.out,
+		"users":          response.Users,
+		"activities":     response.Activities,
+		"events":         response.Events,
+		"skipped_events": response.SkippedEvents,
+	})
+	return nil
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/bootstrap"
+	"clean-archi-analytics/internal/config"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"clean-archi-analytics/internal/infra/database"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+)
+
+// Longueurs minimales des secrets HMAC (RFC 7518 §3.2 pour HS256)
+const (
+	minJWTSecretLength  = 32
+	minCSRFSecretLength = 32
+)
+
+// configCheck résultat d'une vérification ; fatal = le démarrage échouerait ou serait dangereux
+type configCheck struct {
+	name   string
+	ok     bool
+	fatal  bool
+	detail string
+}
+
+// checkConfig valide la configuration en profondeur (dépendances joignables, secrets, port libre)
+// et affiche la configuration effective masquée. Retourne le code de sortie du processus
+func checkConfig(ctx context.Context, cfg *config.Config, logger usecases.Logger, out io.Writer) int {
+	// Proxy sortant : les appels au fournisseur de secrets passent déjà par lui
+	proxyCheck := configCheck{name: "OUTBOUND_PROXY_URL", ok: true, detail: "HTTPS_PROXY / NO_PROXY de l'environnement"}
+	clients, err := httpclient.NewFactory(httpclient.Config{ProxyURL: cfg.OutboundProxyURL})
+	if err != nil {
+		proxyCheck = configCheck{name: "OUTBOUND_PROXY_URL", fatal: true, detail: err.Error()}
+	} else if cfg.OutboundProxyURL != "" {
+		proxyCheck.detail = "proxy configuré"
+	}
+	// Les secrets gérés sont lus auprès du fournisseur : la configuration affichée est celle du démarrage
+	secretsCheck := configCheck{name: "SECRETS_PROVIDER", ok: true, detail: cfg.SecretsProvider}
+	if _, err := bootstrap.LoadSecrets(ctx, cfg, logger, clients); err != nil {
+		secretsCheck = configCheck{name: "SECRETS_PROVIDER", fatal: true, detail: err.Error()}
+	}
+
+	fmt.Fprintln(out, "Configuration effective :")
+	for _, setting := range cfg.Redacted() {
+		fmt.Fprintf(out, "  %-26s %s\n", setting.Name, setting.Value)
+	}
+
+	checks := []configCheck{checkHTTPAddr(cfg.HTTPAddr), proxyCheck, secretsCheck}
+	checks = append(checks, checkDatabase(ctx, cfg)...)
+	checks = append(checks, checkSecrets(cfg)...)
+	checks = append(checks, checkHTTPSecurity(cfg)...)
+	checks = append(checks, checkRemoteServices(ctx, cfg)...)
+
+	fmt.Fprintln(out, "\nVérifications :")
+	failed := 0
+	for _, check := range checks {
+		status := "ok"
+		switch {
+		case !check.ok && check.fatal:
+			status = "ÉCHEC"
+			failed++
+		case !check.ok:
+			status = "avert."
+		}
+		fmt.Fprintf(out, "  [%-6s] %-26s %s\n", status, check.name, check.detail)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(out, "\n%d vérification(s) en échec : corriger la configuration avant de déployer\n", failed)
+		return 1
+	}
+	fmt.Fprintln(out, "\nConfiguration valide")
+	return 0
+}
+
+func checkHTTPAddr(addr string) configCheck {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return configCheck{name: "HTTP_ADDR", fatal: true, detail: fmt.Sprintf("%s indisponible : %v", addr, err)}
+	}
+	listener.Close()
+	return configCheck{name: "HTTP_ADDR", ok: true, detail: addr + " disponible"}
+}
+
+func checkDatabase(ctx context.Context, cfg *config.Config) []configCheck {
+	if cfg.PersistenceMode != config.PersistenceSQL {
+		return []configCheck{{name: "DATABASE_URL", ok: true, detail: "non utilisée (mode " + cfg.PersistenceMode + ")"}}
+	}
+
+	pool := database.SQLPoolConfig{MaxOpenConns: 1, MaxIdleConns: 1}
+	ping := func(name, dsn string) configCheck {
+		db, err := database.OpenSQL(ctx, cfg.DatabaseDriver, dsn, pool)
+		if err != nil {
+			return configCheck{name: name, fatal: true, detail: fmt.Sprintf("connexion impossible (driver %q) : %v", cfg.DatabaseDriver, err)}
+		}
+		db.Close()
+		return configCheck{name: name, ok: true, detail: "joignable"}
+	}
+
+	checks := []configCheck{ping("DATABASE_URL", cfg.DatabaseURL)}
+	for i, dsn := range cfg.DatabaseReplicaURLs {
+		checks = append(checks, ping(fmt.Sprintf("DATABASE_REPLICA_URLS[%d]", i), dsn))
+	}
+	if cfg.DBMaxIdleConns > cfg.DBMaxOpenConns && cfg.DBMaxOpenConns > 0 {
+		checks = append(checks, configCheck{name: "DB_MAX_IDLE_CONNS", detail: "supérieur à DB_MAX_OPEN_CONNS (plafonné par database/sql)"})
+	}
+	return checks
+}
+
+func checkSecrets(cfg *config.Config) []configCheck {
+	var checks []configCheck
+
+	checks = append(checks, checkJWTKeys(cfg)...)
+
+	for source, secret := range cfg.WebhookSecrets {
+		name := "WEBHOOK_SECRETS[" + source + "]"
+		if len(secret) < config.MinWebhookSecretLength {
+			checks = append(checks, configCheck{name: name, fatal: true,
+				detail: fmt.Sprintf("%d octets, %d minimum", len(secret), config.MinWebhookSecretLength)})
+			continue
+		}
+		checks = append(checks, configCheck{name: name, ok: true, detail: fmt.Sprintf("%d octets", len(secret))})
+	}
+
+	if cfg.SessionCookieName != "" && len(cfg.CSRFSecret) < minCSRFSecretLength {
+		checks = append(checks, configCheck{name: "CSRF_SECRET", fatal: true,
+			detail: fmt.Sprintf("%d octets, %d minimum", len(cfg.CSRFSecret), minCSRFSecretLength)})
+	}
+
+	if (cfg.TwilioAccountSID == "") != (cfg.TwilioAuthToken == "") {
+		checks = append(checks, configCheck{name: "TWILIO_*", fatal: true,
+			detail: "TWILIO_ACCOUNT_SID et TWILIO_AUTH_TOKEN vont ensemble (sinon SMS journalisés)"})
+	} else if cfg.TwilioAccountSID != "" && cfg.TwilioFrom == "" {
+		checks = append(checks, configCheck{name: "TWILIO_FROM", fatal: true, detail: "obligatoire avec les identifiants Twilio"})
+	}
+
+	if cfg.FeatureFlagsFile != "" {
+		if _, err := os.Stat(cfg.FeatureFlagsFile); err != nil {
+			checks = append(checks, configCheck{name: "FEATURE_FLAGS_FILE", fatal: true, detail: err.Error()})
+		}
+	}
+
+	if cfg.PolicyFile != "" {
+		if _, err := services.LoadPolicyFile(cfg.PolicyFile); err != nil {
+			checks = append(checks, configCheck{name: "POLICY_FILE", fatal: true, detail: err.Error()})
+		}
+	}
+
+	if cfg.AttributeSchemaFile != "" {
+		if _, err := services.LoadAttributeSchemas(cfg.AttributeSchemaFile); err != nil {
+			checks = append(checks, configCheck{name: "ATTRIBUTE_SCHEMA_FILE", fatal: true, detail: err.Error()})
+		}
+	}
+	return checks
+}
+
+// checkJWTKeys contrôle chaque clé du jeu : la clé historique (JWT_SECRET) et les clés par kid
+func checkJWTKeys(cfg *config.Config) []configCheck {
+	keys, err := services.ParseJWTKeySet(cfg.JWTSigningKeys, cfg.JWTSecret)
+	if err != nil {
+		return []configCheck{{name: "JWT_SIGNING_KEYS", fatal: true, detail: err.Error()}}
+	}
+	lengths := keys.Keys()
+	if len(lengths) == 0 {
+		return []configCheck{{name: "JWT_SECRET", detail: "vide : toute connexion authentifiée sera refusée"}}
+	}
+
+	kids := make([]string, 0, len(lengths))
+	for kid := range lengths {
+		kids = append(kids, kid)
+	}
+	slices.Sort(kids)
+
+	var checks []configCheck
+	for _, kid := range kids {
+		name, role := "JWT_SIGNING_KEYS["+kid+"]", "vérification"
+		if kid == "" {
+			name = "JWT_SECRET"
+		}
+		if kid == keys.ActiveKID() {
+			role = "signature"
+		}
+		if lengths[kid] < minJWTSecretLength {
+			checks = append(checks, configCheck{name: name, fatal: true,
+				detail: fmt.Sprintf("%d octets, %d minimum pour HS256", lengths[kid], minJWTSecretLength)})
+			continue
+		}
+		checks = append(checks, configCheck{name: name, ok: true, detail: fmt.Sprintf("%d octets, %s", lengths[kid], role)})
+	}
+	return checks
+}
+
+// checkHTTPSecurity signale les réglages acceptables en développement mais pas en production
+func checkHTTPSecurity(cfg *config.Config) []configCheck {
+	if cfg.Environment != config.EnvironmentProduction {
+		return nil
+	}
+
+	var checks []configCheck
+	if slices.Contains(cfg.CORSAllowedOrigins, "*") {
+		checks = append(checks, configCheck{name: "CORS_ALLOWED_ORIGINS", detail: "\"*\" en production : toute origine peut appeler l'API"})
+	}
+	if cfg.HSTSMaxAge == 0 {
+		checks = append(checks, configCheck{name: "HSTS_MAX_AGE", detail: "désactivé en production"})
+	}
+	return checks
+}
+
+// checkRemoteServices vérifie que les services distants configurés sont joignables
+func checkRemoteServices(ctx context.Context, cfg *config.Config) []configCheck {
+	var checks []configCheck
+
+	if cfg.SentryDSN != "" {
+		if _, err := services.NewSentryErrorReporter(cfg.SentryDSN, cfg.SentryEnvironment, nil, nil); err != nil {
+			checks = append(checks, configCheck{name: "SENTRY_DSN", fatal: true, detail: err.Error()})
+		} else {
+			checks = append(checks, dialURL(ctx, "SENTRY_DSN", cfg.SentryDSN))
+		}
+	}
+	if cfg.FeatureFlagsURL != "" {
+		// Injoignable : les règles locales servent de repli, ce n'est qu'un avertissement
+		check := dialURL(ctx, "FEATURE_FLAGS_URL", cfg.FeatureFlagsURL)
+		check.fatal = false
+		checks = append(checks, check)
+	}
+	if cfg.TwilioAccountSID != "" {
+		checks = append(checks, dialURL(ctx, "TWILIO_*", "https://api.twilio.com"))
+	}
+	if cfg.LDAPURL != "" {
+		checks = append(checks, checkLDAP(ctx, cfg)...)
+	}
+	if cfg.EmailProvider != config.EmailViaLog {
+		checks = append(checks, checkMailProvider(ctx, cfg))
+	}
+	return checks
+}
+
+// checkMailProvider même vérification qu'au démarrage : identifiants, droit d'envoi, domaine vérifié
+func checkMailProvider(ctx context.Context, cfg *config.Config) configCheck {
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := bootstrap.NewMailProvider(cfg, nil, nil).HealthCheck(checkCtx); err != nil {
+		return configCheck{name: "EMAIL_PROVIDER", fatal: true, detail: err.Error()}
+	}
+	return configCheck{name: "EMAIL_PROVIDER", ok: true, detail: cfg.EmailProvider + " : envoi autorisé"}
+}
+
+func checkLDAP(ctx context.Context, cfg *config.Config) []configCheck {
+	_, err := services.NewLDAPDirectory(services.LDAPDirectoryConfig{URL: cfg.LDAPURL, BaseDN: cfg.LDAPBaseDN, UserFilter: cfg.LDAPUserFilter})
+	if err != nil {
+		return []configCheck{{name: "LDAP_URL", fatal: true, detail: err.Error()}}
+	}
+
+	// dialURL ne connaît que les ports HTTP : le port LDAP est rendu explicite
+	parsed, _ := url.Parse(cfg.LDAPURL)
+	if parsed.Port() == "" {
+		port := "636"
+		if parsed.Scheme == "ldap" {
+			port = "389"
+		}
+		parsed.Host = net.JoinHostPort(parsed.Hostname(), port)
+	}
+	checks := []configCheck{dialURL(ctx, "LDAP_URL", parsed.String())}
+
+	if parsed.Scheme == "ldap" && cfg.Environment == config.EnvironmentProduction {
+		checks = append(checks, configCheck{name: "LDAP_URL", detail: "ldap:// en production : les mots de passe circulent en clair"})
+	}
+	return checks
+}
+
+// dialURL ouvre une connexion TCP vers l'hôte de rawURL (sans requête applicative)
+func dialURL(ctx context.Context, name, rawURL string) configCheck {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return configCheck{name: name, fatal: true, detail: "URL invalide"}
+	}
+	host := parsed.Host
+	if parsed.Port() == "" {
+		port := "443"
+		if parsed.Scheme == "http" {
+			port = "80"
+		}
+		host = net.JoinHostPort(parsed.Hostname(), port)
+	}
+
+	dialer := net.Dialer{Timeout: 3 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return configCheck{name: name, fatal: true, detail: fmt.Sprintf("%s injoignable : %v", host, err)}
+	}
+	conn.Close()
+	return configCheck{name: name, ok: true, detail: host + " joignable"}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"clean-archi-analytics/internal/bootstrap"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// =============================================================================
+// SOUS-COMMANDE "dlq" : files des éléments abandonnés (outbox, webhooks)
+// =============================================================================
+
+// dlqOptions options de `api dlq list|show|replay|discard -queue outbox|webhooks [-id ID,...] [-all]
+// [-limit N] [-offset N]` ; le résultat est écrit en JSON sur la sortie standard
+type dlqOptions struct {
+	action string
+	queue  string
+	ids    []string
+	all    bool
+	limit  int
+	offset int
+}
+
+func parseDLQOptions(args []string) (*dlqOptions, error) {
+	if len(args) == 0 {
+		return nil, errors.New("action attendue : list, show, replay ou discard")
+	}
+	opts := &dlqOptions{action: args[0]}
+	switch opts.action {
+	case "list", "show", "replay", "discard":
+	default:
+		return nil, fmt.Errorf("action inconnue %q (list, show, replay, discard)", opts.action)
+	}
+
+	var ids string
+	fs := flag.NewFlagSet("dlq "+opts.action, flag.ContinueOnError)
+	fs.StringVar(&opts.queue, "queue", "", "file : outbox ou webhooks")
+	fs.StringVar(&ids, "id", "", "identifiants des éléments, séparés par des virgules (show : un seul)")
+	fs.BoolVar(&opts.all, "all", false, "replay/discard : tous les éléments de la file")
+	fs.IntVar(&opts.limit, "limit", 0, "list : éléments par page ; 0 = valeur par défaut")
+	fs.IntVar(&opts.offset, "offset", 0, "list : éléments à sauter")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if opts.queue == "" {
+		return nil, errors.New("-queue est obligatoire")
+	}
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			opts.ids = append(opts.ids, id)
+		}
+	}
+	if opts.action == "show" && len(opts.ids) != 1 {
+		return nil, errors.New("show : un -id est obligatoire")
+	}
+	return opts, nil
+}
+
+// runDLQ les identifiants affichés par list servent à show, replay et discard
+func runDLQ(ctx context.Context, opts *dlqOptions, app *bootstrap.App, out io.Writer) error {
+	var result any
+	var err error
+	switch opts.action {
+	case "list":
+		result, err = app.ListDeadLetters.Execute(ctx, usecases.ListDeadLettersRequest{Queue: opts.queue, Limit: opts.limit, Offset: opts.offset})
+	case "show":
+		result, err = app.GetDeadLetter.Execute(ctx, usecases.GetDeadLetterRequest{Queue: opts.queue, ID: opts.ids[0]})
+	case "replay":
+		result, err = app.ReplayDeadLetters.Execute(ctx, usecases.DeadLetterBatchRequest{Queue: opts.queue, IDs: opts.ids, All: opts.all})
+	case "discard":
+		result, err = app.DiscardDeadLetters.Execute(ctx, usecases.DeadLetterBatchRequest{Queue: opts.queue, IDs: opts.ids, All: opts.all})
+	}
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// =============================================================================
+// SOUS-COMMANDE "events-restore" : rechargement des archives d'événements
+// =============================================================================
+
+// eventsRestoreOptions options de `api events-restore -from AAAA-MM-JJ -to AAAA-MM-JJ`
+// Les jours restaurés restent en stockage chaud pendant EVENT_ARCHIVE_RESTORE_HOLD
+type eventsRestoreOptions struct {
+	from time.Time
+	to   time.Time
+}
+
+func parseEventsRestoreOptions(args []string) (*eventsRestoreOptions, error) {
+	var from, to string
+	fs := flag.NewFlagSet("events-restore", flag.ContinueOnError)
+	fs.StringVar(&from, "from", "", "premier jour restauré (AAAA-MM-JJ, UTC)")
+	fs.StringVar(&to, "to", "", "jour suivant le dernier restauré (AAAA-MM-JJ, exclu) ; vide = le seul jour -from")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if from == "" {
+		return nil, fmt.Errorf("-from est obligatoire")
+	}
+
+	opts := &eventsRestoreOptions{}
+	var err error
+	if opts.from, err = time.Parse(time.DateOnly, from); err != nil {
+		return nil, fmt.Errorf("-from : date AAAA-MM-JJ attendue")
+	}
+	opts.to = opts.from.AddDate(0, 0, 1)
+	if to != "" {
+		if opts.to, err = time.Parse(time.DateOnly, to); err != nil {
+			return nil, fmt.Errorf("-to : date AAAA-MM-JJ attendue")
+		}
+	}
+	return opts, nil
+}
+
+func runEventsRestore(ctx context.Context, opts *eventsRestoreOptions, restore usecases.UseCase[usecases.RestoreEventsRequest, *usecases.RestoreEventsResponse]) error {
+	// Journalisé par le use case (jours, fichiers, événements, fin du maintien)
+	_, err := restore.Execute(ctx, usecases.RestoreEventsRequest{From: opts.from, To: opts.to})
+	return err
+}
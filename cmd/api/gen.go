@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// =============================================================================
+// SOUS-COMMANDE "gen resource" : squelette d'un nouvel agrégat
+// =============================================================================
+
+//go:embed scaffold/*.tmpl
+var scaffoldTemplates embed.FS
+
+var resourceNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// scaffoldFiles template -> fichier généré (relatif à la racine du module), dans l'ordre des couches
+var scaffoldFiles = []struct {
+	template string
+	path     string
+}{
+	{"entity.go.tmpl", "internal/domain/entities/{{.Snake}}.go"},
+	{"repository.go.tmpl", "internal/domain/repositories/{{.Snake}}_repository.go"},
+	{"usecases.go.tmpl", "internal/domain/usecases/{{.Snake}}_usecases.go"},
+	{"usecases_test.go.tmpl", "internal/domain/usecases/{{.Snake}}_usecases_test.go"},
+	{"memory_repository.go.tmpl", "internal/infra/database/memory_{{.Snake}}_repository.go"},
+	{"sql_repository.go.tmpl", "internal/infra/database/sql_{{.Snake}}_repository.go"},
+	{"migration.sql.tmpl", "internal/infra/database/migrations/{{.Migration}}"},
+	{"handler.go.tmpl", "internal/app/handlers/{{.Snake}}_handler.go"},
+}
+
+// resourceNames déclinaisons du nom de l'agrégat utilisées par les templates
+type resourceNames struct {
+	Name        string // TeamMember
+	Plural      string // TeamMembers
+	Var         string // teamMember
+	Receiver    string // tm
+	Snake       string // team_member
+	PluralSnake string // team_members
+	Table       string // team_members
+	Label       string // team member
+	PluralLabel string // team members
+	Upper       string // TEAM MEMBER
+	UpperPlural string // TEAM MEMBERS
+	Route       string // /team-members
+	Migration   string // 0011_create_team_members.sql
+}
+
+type genOptions struct {
+	name   string
+	plural string
+	dir    string
+	force  bool
+}
+
+// parseGenOptions `api gen resource <Name> [-plural Pluriel] [-dir RACINE] [-force]`
+func parseGenOptions(args []string) (*genOptions, error) {
+	if len(args) == 0 || args[0] != "resource" {
+		return nil, errors.New("usage : api gen resource <Name> [-plural Pluriel] [-dir RACINE] [-force]")
+	}
+	args = args[1:]
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return nil, errors.New("nom de l'agrégat manquant (ex. api gen resource Project)")
+	}
+
+	opts := &genOptions{name: args[0]}
+	fs := flag.NewFlagSet("gen resource", flag.ContinueOnError)
+	fs.StringVar(&opts.plural, "plural", "", "pluriel de l'agrégat quand la règle anglaise par défaut ne convient pas (ex. People)")
+	fs.StringVar(&opts.dir, "dir", ".", "racine du module (dossier contenant go.mod)")
+	fs.BoolVar(&opts.force, "force", false, "écrase les fichiers existants")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("argument inattendu %q", fs.Arg(0))
+	}
+	if !resourceNamePattern.MatchString(opts.name) {
+		return nil, fmt.Errorf("nom %q invalide : PascalCase attendu (ex. Project, TeamMember)", opts.name)
+	}
+	if opts.plural != "" && !resourceNamePattern.MatchString(opts.plural) {
+		return nil, fmt.Errorf("pluriel %q invalide : PascalCase attendu", opts.plural)
+	}
+	if _, err := os.Stat(filepath.Join(opts.dir, "go.mod")); err != nil {
+		return nil, fmt.Errorf("%s n'est pas la racine du module : %w", opts.dir, err)
+	}
+	return opts, nil
+}
+
+// runGen génère tous les fichiers avant d'en écrire un seul : une erreur de template ou un
+// fichier déjà présent laisse l'arbre intact
+func runGen(opts *genOptions, stdout io.Writer) error {
+	migrationsDir := filepath.Join(opts.dir, "internal", "infra", "database", "migrations")
+	migration, err := nextMigrationNumber(migrationsDir)
+	if err != nil {
+		return err
+	}
+	names := newResourceNames(opts.name, opts.plural, migration)
+	// -force régénère la migration existante de la table au lieu d'en ajouter une seconde
+	if matches, _ := filepath.Glob(filepath.Join(migrationsDir, "*_create_"+names.Table+".sql")); len(matches) > 0 {
+		names.Migration = filepath.Base(matches[0])
+	}
+
+	type generatedFile struct {
+		path    string
+		content []byte
+	}
+	files := make([]generatedFile, 0, len(scaffoldFiles))
+	for _, file := range scaffoldFiles {
+		path, err := renderString(file.path, names)
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(opts.dir, filepath.FromSlash(path))
+		if _, err := os.Stat(path); err == nil && !opts.force {
+			return fmt.Errorf("%s existe déjà (-force pour écraser)", path)
+		}
+
+		content, err := renderTemplate(file.template, names)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(path, ".go") {
+			if content, err = format.Source(content); err != nil {
+				return fmt.Errorf("%s : %w", file.template, err)
+			}
+		}
+		files = append(files, generatedFile{path: path, content: content})
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(file.path, file.content, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "created", file.path)
+	}
+
+	wiring, err := renderTemplate("wiring.txt.tmpl", names)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "\n%s", wiring)
+	return nil
+}
+
+func renderTemplate(name string, names resourceNames) ([]byte, error) {
+	tmpl, err := template.ParseFS(scaffoldTemplates, "scaffold/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, names); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderString(text string, names resourceNames) (string, error) {
+	tmpl, err := template.New("path").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, names); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// nextMigrationNumber numéro suivant le plus grand préfixe NNNN_ du dossier des migrations
+func nextMigrationNumber(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	highest := 0
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(prefix); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest + 1, nil
+}
+
+func newResourceNames(name, plural string, migration int) resourceNames {
+	if plural == "" {
+		plural = pluralize(name)
+	}
+	words := splitWords(name)
+	pluralWords := splitWords(plural)
+
+	receiver := ""
+	for _, word := range words {
+		receiver += strings.ToLower(word[:1])
+	}
+	// Un récepteur d'une lettre ne doit pas masquer un paramètre courant (r, w, ctx...)
+	if receiver == "r" || receiver == "w" {
+		receiver = strings.ToLower(words[0])
+	}
+
+	snake := strings.ToLower(strings.Join(words, "_"))
+	pluralSnake := strings.ToLower(strings.Join(pluralWords, "_"))
+	label := strings.ToLower(strings.Join(words, " "))
+	pluralLabel := strings.ToLower(strings.Join(pluralWords, " "))
+	return resourceNames{
+		Name:        name,
+		Plural:      plural,
+		Var:         strings.ToLower(words[0]) + strings.Join(words[1:], ""),
+		Receiver:    receiver,
+		Snake:       snake,
+		PluralSnake: pluralSnake,
+		Table:       pluralSnake,
+		Label:       label,
+		PluralLabel: pluralLabel,
+		Upper:       strings.ToUpper(label),
+		UpperPlural: strings.ToUpper(pluralLabel),
+		Route:       "/" + strings.ToLower(strings.Join(pluralWords, "-")),
+		Migration:   fmt.Sprintf("%04d_create_%s.sql", migration, pluralSnake),
+	}
+}
+
+// splitWords découpe un nom PascalCase en mots, sigles compris : APIKey -> API, Key
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsUpper(cur) && (!unicode.IsUpper(prev) || nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// pluralize règle anglaise simple sur le dernier mot ; -plural pour les exceptions (Person -> People)
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
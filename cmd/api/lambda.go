@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// API RUNTIME LAMBDA
+// =============================================================================
+
+// lambdaRuntime client de l'API Runtime (runtime personnalisé provided.al2023, binaire "bootstrap"),
+// sans dépendance à aws-lambda-go :
+//
+//	GET  /2018-06-01/runtime/invocation/next              → événement (bloquant)
+//	POST /2018-06-01/runtime/invocation/{id}/response      ← réponse
+//	POST /2018-06-01/runtime/invocation/{id}/error         ← échec de l'invocation
+//
+// Un échec du démarrage arrête le processus : Lambda le journalise et recrée l'instance
+type lambdaRuntime struct {
+	base   string
+	client *http.Client
+}
+
+func newLambdaRuntime(api string) *lambdaRuntime {
+	// Pas de délai : l'attente de l'invocation suivante n'est pas bornée (instance gelée entre-temps)
+	return &lambdaRuntime{base: "http://" + api + "/2018-06-01/runtime", client: &http.Client{}}
+}
+
+type lambdaInvocation struct {
+	requestID string
+	deadline  time.Time
+	payload   []byte
+}
+
+func (r *lambdaRuntime) next(ctx context.Context) (*lambdaInvocation, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.base+"/invocation/next", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("lambda runtime: " + resp.Status)
+	}
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	invocation := &lambdaInvocation{requestID: resp.Header.Get("Lambda-Runtime-Aws-Request-Id"), payload: payload}
+	if ms, err := strconv.ParseInt(resp.Header.Get("Lambda-Runtime-Deadline-Ms"), 10, 64); err == nil {
+		invocation.deadline = time.UnixMilli(ms)
+	}
+	return invocation, nil
+}
+
+func (r *lambdaRuntime) respond(requestID string, body []byte) error {
+	return r.post("/invocation/"+requestID+"/response", body)
+}
+
+// fail l'événement n'a pas pu être rejoué (les erreurs de l'API restent des réponses HTTP)
+func (r *lambdaRuntime) fail(requestID string, err error) error {
+	body, _ := json.Marshal(map[string]string{"errorMessage": err.Error(), "errorType": "InvalidEvent"})
+	return r.post("/invocation/"+requestID+"/error", body)
+}
+
+func (r *lambdaRuntime) post(path string, body []byte) error {
+	resp, err := r.client.Post(r.base+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return errors.New("lambda runtime: " + resp.Status)
+	}
+	return nil
+}
+
+// runLambda traite les invocations une à une jusqu'à l'annulation de ctx ; afterInvoke est appelé
+// avant chaque réponse, tant que l'instance n'est pas gelée (écritures en tampon...)
+func runLambda(ctx context.Context, runtime *lambdaRuntime, handler http.Handler, logger usecases.Logger, afterInvoke func(ctx context.Context)) error {
+	for {
+		invocation, err := runtime.next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		invokeCtx, cancel := context.WithCancel(context.Background())
+		if !invocation.deadline.IsZero() {
+			invokeCtx, cancel = context.WithDeadline(context.Background(), invocation.deadline)
+		}
+		response, err := serveAPIGatewayEvent(invokeCtx, handler, invocation)
+		afterInvoke(invokeCtx)
+		cancel()
+		if err != nil {
+			logger.Error("Lambda invocation rejected", err, map[string]interface{}{"aws_request_id": invocation.requestID})
+			err = runtime.fail(invocation.requestID, err)
+		} else {
+			err = runtime.respond(invocation.requestID, response)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// =============================================================================
+// ÉVÉNEMENTS API GATEWAY
+// =============================================================================
+
+// apiGatewayEvent événement proxy d'API Gateway : REST API (format 1.0) ou HTTP API (format 2.0),
+// les deux formats partageant body et isBase64Encoded
+type apiGatewayEvent struct {
+	Version         string                   `json:"version"`
+	Headers         map[string]string        `json:"headers"`
+	Body            string                   `json:"body"`
+	IsBase64Encoded bool                     `json:"isBase64Encoded"`
+	Cookies         []string                 `json:"cookies"`
+	RequestContext  apiGatewayRequestContext `json:"requestContext"`
+
+	// Format 1.0
+	HTTPMethod                      string              `json:"httpMethod"`
+	Path                            string              `json:"path"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+
+	// Format 2.0
+	RawPath        string `json:"rawPath"`
+	RawQueryString string `json:"rawQueryString"`
+}
+
+type apiGatewayRequestContext struct {
+	Identity struct {
+		SourceIP string `json:"sourceIp"`
+	} `json:"identity"`
+	HTTP struct {
+		Method   string `json:"method"`
+		SourceIP string `json:"sourceIp"`
+	} `json:"http"`
+}
+
+func (e *apiGatewayEvent) v2() bool { return e.Version == "2.0" }
+
+// apiGatewayResponse réponse commune aux deux formats : multiValueHeaders est ignoré par
+// les HTTP API, cookies par les REST API
+type apiGatewayResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Cookies           []string            `json:"cookies,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+// serveAPIGatewayEvent rejoue l'événement sur le handler HTTP de l'API (mêmes middlewares, même routeur)
+// La réponse est mise en tampon : SSE et WebSocket ne sont pas disponibles sous Lambda
+func serveAPIGatewayEvent(ctx context.Context, handler http.Handler, invocation *lambdaInvocation) ([]byte, error) {
+	var event apiGatewayEvent
+	if err := json.Unmarshal(invocation.payload, &event); err != nil {
+		return nil, fmt.Errorf("événement API Gateway invalide : %w", err)
+	}
+	req, err := event.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Corrélation avec les journaux CloudWatch de l'invocation
+	if req.Header.Get("X-Request-ID") == "" {
+		req.Header.Set("X-Request-ID", invocation.requestID)
+	}
+
+	recorder := newLambdaResponseWriter()
+	handler.ServeHTTP(recorder, req)
+	return json.Marshal(recorder.response(event.v2()))
+}
+
+func (e *apiGatewayEvent) request(ctx context.Context) (*http.Request, error) {
+	body := []byte(e.Body)
+	if e.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(e.Body)
+		if err != nil {
+			return nil, fmt.Errorf("corps base64 invalide : %w", err)
+		}
+		body = decoded
+	}
+
+	method, path, rawQuery, sourceIP := e.HTTPMethod, e.Path, "", e.RequestContext.Identity.SourceIP
+	if e.v2() {
+		method, path, rawQuery, sourceIP = e.RequestContext.HTTP.Method, e.RawPath, e.RawQueryString, e.RequestContext.HTTP.SourceIP
+	} else {
+		query := url.Values{}
+		for name, values := range e.MultiValueQueryStringParameters {
+			query[name] = values
+		}
+		rawQuery = query.Encode()
+	}
+
+	target := &url.URL{Path: path, RawQuery: rawQuery}
+	req, err := http.NewRequestWithContext(ctx, method, target.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range e.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, values := range e.MultiValueHeaders {
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if len(e.Cookies) > 0 {
+		req.Header.Set("Cookie", strings.Join(e.Cookies, "; "))
+	}
+	req.Host = req.Header.Get("Host")
+	req.RemoteAddr = sourceIP + ":0"
+	req.ContentLength = int64(len(body))
+	return req, nil
+}
+
+// =============================================================================
+// RÉPONSE EN TAMPON
+// =============================================================================
+
+type lambdaResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newLambdaResponseWriter() *lambdaResponseWriter {
+	return &lambdaResponseWriter{header: http.Header{}}
+}
+
+func (w *lambdaResponseWriter) Header() http.Header { return w.header }
+
+func (w *lambdaResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *lambdaResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+func (w *lambdaResponseWriter) response(v2 bool) apiGatewayResponse {
+	response := apiGatewayResponse{StatusCode: w.status, Headers: make(map[string]string, len(w.header))}
+	if response.StatusCode == 0 {
+		response.StatusCode = http.StatusOK
+	}
+	for name, values := range w.header {
+		if v2 && name == "Set-Cookie" {
+			response.Cookies = values
+			continue
+		}
+		response.Headers[name] = strings.Join(values, ",")
+		if !v2 && len(values) > 1 {
+			if response.MultiValueHeaders == nil {
+				response.MultiValueHeaders = make(map[string][]string)
+			}
+			response.MultiValueHeaders[name] = values
+		}
+	}
+
+	// Corps compressé ou binaire : API Gateway le décode avant de répondre au client
+	if textualBody(w.header) {
+		response.Body = w.body.String()
+	} else {
+		response.Body = base64.StdEncoding.EncodeToString(w.body.Bytes())
+		response.IsBase64Encoded = true
+	}
+	return response
+}
+
+func textualBody(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") || mediaType == "application/javascript"
+}
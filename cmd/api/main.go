@@ -1,296 +1,247 @@
 package main
 
 import (
-	"fmt"
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/bootstrap"
+	"clean-archi-analytics/internal/config"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// Base des fuseaux IANA embarquée : images sans /usr/share/zoneinfo (DISPLAY_TIME_ZONE, préférences)
+	_ "time/tzdata"
 )
 
-type User struct {
-	ID   int
-	Name string
-}
-
 // =============================================================================
-// 1. DIFFÉRENCE ENTRE POINTEUR ET COPIE
+// POINT D'ENTRÉE : sous-commandes et serveur HTTP ; l'assemblage est dans internal/bootstrap
 // =============================================================================
 
-func demonstratePointerVsCopy() {
-	fmt.Println("=== 1. POINTEUR VS COPIE ===")
-
-	// Créer un utilisateur
-	user := User{ID: 1, Name: "Alice"}
-	fmt.Printf("user original: %+v (adresse: %p)\n", user, &user)
-
-	// COPIE : Passer par valeur
-	modifyByCopy(user)
-	fmt.Printf("après modifyByCopy: %+v (pas changé!)\n", user)
-
-	// POINTEUR : Passer par référence
-	modifyByPointer(&user)
-	fmt.Printf("après modifyByPointer: %+v (changé!)\n", user)
-}
-
-func modifyByCopy(u User) {
-	fmt.Printf("  dans modifyByCopy: %p (adresse différente!)\n", &u)
-	u.Name = "Bob" // Modifie la COPIE, pas l'original
-}
-
-func modifyByPointer(u *User) {
-	fmt.Printf("  dans modifyByPointer: %p (même adresse!)\n", u)
-	u.Name = "Charlie" // Modifie l'ORIGINAL
-}
-
-// =============================================================================
-// 2. CAS DU REPOSITORY LIST() - SLICE DE POINTEURS
-// =============================================================================
-
-func demonstrateList() {
-	fmt.Println("\n=== 2. LIST() - SLICE DE POINTEURS ===")
+func main() {
+	checkOnly := flag.Bool("check-config", false, "valide la configuration et ses dépendances, affiche la configuration effective puis quitte")
+	flag.Parse()
+
+	// Sous-commandes optionnelles : `api seed [-users N] [-seed S] [-serve]`, `api anonymize -out FICHIER`,
+	// `api search-reindex` (recopie tous les utilisateurs dans Elasticsearch),
+	// `api rollup-backfill -from AAAA-MM-JJ [-to AAAA-MM-JJ]` (agrégats quotidiens depuis l'historique),
+	// `api events-restore -from AAAA-MM-JJ [-to AAAA-MM-JJ]` (archives d'événements rechargées),
+	// `api rebuild-projections [-from N | -checkpoint FICHIER] [-only P,...]` (rejeu du magasin d'événements),
+	// `api dlq list|show|replay|discard -queue outbox|webhooks [-id ID,...]` (éléments abandonnés),
+	// `api gen resource <Name>` (squelette d'un nouvel agrégat, sans configuration ni dépendance),
+	// `api lambda` (fonction Lambda derrière API Gateway ; implicite sous Lambda sans sous-commande)
+	var seedOpts *seedOptions
+	var anonymizeOpts *anonymizeOptions
+	var rollupBackfillOpts *rollupBackfillOptions
+	var eventsRestoreOpts *eventsRestoreOptions
+	var rebuildProjectionsOpts *rebuildProjectionsOptions
+	var dlqOpts *dlqOptions
+	reindexSearch := false
+	lambdaMode := false
+	switch flag.Arg(0) {
+	case "":
+	case "lambda":
+		lambdaMode = true
+	case "search-reindex":
+		reindexSearch = true
+	case "seed":
+		var err error
+		if seedOpts, err = parseSeedOptions(flag.Args()[1:]); err != nil {
+			log.Fatalf("seed: %v", err)
+		}
+	case "anonymize":
+		var err error
+		if anonymizeOpts, err = parseAnonymizeOptions(flag.Args()[1:]); err != nil {
+			log.Fatalf("anonymize: %v", err)
+		}
+	case "rollup-backfill":
+		var err error
+		if rollupBackfillOpts, err = parseRollupBackfillOptions(flag.Args()[1:]); err != nil {
+			log.Fatalf("rollup-backfill: %v", err)
+		}
+	case "events-restore":
+		var err error
+		if eventsRestoreOpts, err = parseEventsRestoreOptions(flag.Args()[1:]); err != nil {
+			log.Fatalf("events-restore: %v", err)
+		}
+	case "rebuild-projections":
+		var err error
+		if rebuildProjectionsOpts, err = parseRebuildProjectionsOptions(flag.Args()[1:]); err != nil {
+			log.Fatalf("rebuild-projections: %v", err)
+		}
+	case "dlq":
+		var err error
+		if dlqOpts, err = parseDLQOptions(flag.Args()[1:]); err != nil {
+			log.Fatalf("dlq: %v", err)
+		}
+	case "gen":
+		genOpts, err := parseGenOptions(flag.Args()[1:])
+		if err != nil {
+			log.Fatalf("gen: %v", err)
+		}
+		if err := runGen(genOpts, os.Stdout); err != nil {
+			log.Fatalf("gen: %v", err)
+		}
+		return
+	default:
+		log.Fatalf("unknown command %q", flag.Arg(0))
+	}
 
-	// Simuler des données en "base"
-	storage := map[int]*User{
-		1: {ID: 1, Name: "Alice"},
-		2: {ID: 2, Name: "Bob"},
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	logger := services.NewSlogLogger()
+	if *checkOnly {
+		os.Exit(checkConfig(context.Background(), cfg, logger, os.Stdout))
+	}
+	lambdaMode = lambdaMode || (flag.Arg(0) == "" && cfg.LambdaRuntimeAPI != "")
+	if lambdaMode && cfg.LambdaRuntimeAPI == "" {
+		log.Fatalf("lambda: AWS_LAMBDA_RUNTIME_API non défini (hors environnement Lambda)")
 	}
 
-	// Version DANGEREUSE : retourne les pointeurs originaux
-	dangerousList := func() []*User {
-		var users []*User
-		for _, user := range storage {
-			users = append(users, user) // ⚠️ MÊME POINTEUR !
+	// Tâches planifiées et sous-commandes s'exécutent au nom du système (politiques d'autorisation)
+	ctx := usecases.ContextWithActor(context.Background(), usecases.SystemActor)
+	app, err := bootstrap.Build(ctx, cfg, bootstrap.Ports{Logger: logger})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	// Les traitements périodiques ne tournent pas sous Lambda : l'instance est gelée entre deux
+	// invocations et multipliée avec la charge
+	if !lambdaMode {
+		app.StartJobs()
+		if err := app.ServeDiagnostics(); err != nil {
+			log.Fatalf("%v", err)
 		}
-		return users
 	}
 
-	// Version SÉCURISÉE : retourne des copies
-	safeList := func() []*User {
-		var users []*User
-		for _, user := range storage {
-			userCopy := *user                // Copie la valeur
-			users = append(users, &userCopy) // Nouveau pointeur vers la copie
+	if reindexSearch {
+		if app.SearchIndexer == nil {
+			log.Fatalf("search-reindex: ELASTICSEARCH_URL non configuré")
+		}
+		indexed, err := app.SearchIndexer.Reindex(ctx)
+		if err != nil {
+			log.Fatalf("search-reindex: %v (%d utilisateurs indexés)", err, indexed)
 		}
-		return users
+		logger.Info("Search reindex finished", map[string]interface{}{"users": indexed})
+		_ = app.Shutdown(context.Background())
+		return
 	}
 
-	// Test version dangereuse
-	dangerousUsers := dangerousList()
-	fmt.Printf("Version dangereuse - user[0]: %p\n", dangerousUsers[0])
-	fmt.Printf("Storage user[1]: %p\n", storage[1])
-	fmt.Printf("Même adresse? %t\n", dangerousUsers[0] == storage[1])
-
-	// Si on modifie via dangerousUsers, on modifie le storage !
-	dangerousUsers[0].Name = "MODIFIED!"
-	fmt.Printf("Storage après modification: %+v\n", storage[1])
-
-	// Reset
-	storage[1].Name = "Bob"
-
-	// Test version sécurisée
-	safeUsers := safeList()
-	fmt.Printf("\nVersion sécurisée - user[0]: %p\n", safeUsers[0])
-	fmt.Printf("Storage user[1]: %p\n", storage[1])
-	fmt.Printf("Même adresse? %t\n", safeUsers[0] == storage[1])
-
-	// Modification n'affecte pas le storage
-	safeUsers[0].Name = "MODIFIED COPY!"
-	fmt.Printf("Storage après modification: %+v (inchangé!)\n", storage[1])
-}
-
-// =============================================================================
-// 3. CAS DE UPDATE() - MODIFICATION D'OBJET EXISTANT
-// =============================================================================
-
-func demonstrateUpdate() {
-	fmt.Println("\n=== 3. UPDATE() - MODIFICATION OBJET EXISTANT ===")
-
-	// Simuler le storage
-	storage := map[int]*User{
-		1: {ID: 1, Name: "Alice"},
+	if rollupBackfillOpts != nil {
+		if err := runRollupBackfill(ctx, rollupBackfillOpts, app.BackfillRollups(), logger); err != nil {
+			log.Fatalf("rollup-backfill: %v", err)
+		}
+		_ = app.Shutdown(context.Background())
+		return
 	}
 
-	fmt.Printf("Avant update - storage[1]: %+v (adresse: %p)\n",
-		storage[1], storage[1])
-
-	// Cas 1: Update qui MODIFIE l'objet existant (économique)
-	updateInPlace := func(user *User) {
-		existingUser := storage[user.ID]
-		// Modifier les champs un par un
-		existingUser.Name = user.Name
-		// L'objet reste à la même adresse mémoire
+	if eventsRestoreOpts != nil {
+		if err := runEventsRestore(ctx, eventsRestoreOpts, app.RestoreEvents); err != nil {
+			log.Fatalf("events-restore: %v", err)
+		}
+		_ = app.Shutdown(context.Background())
+		return
 	}
 
-	// Cas 2: Update qui REMPLACE l'objet (moins économique)
-	updateWithReplace := func(user *User) {
-		// Créer une nouvelle copie
-		newUser := *user
-		storage[user.ID] = &newUser // ⚠️ Nouvelle allocation !
+	if rebuildProjectionsOpts != nil {
+		if err := runRebuildProjections(ctx, rebuildProjectionsOpts, app.RebuildProjections(), logger); err != nil {
+			log.Fatalf("rebuild-projections: %v", err)
+		}
+		_ = app.Shutdown(context.Background())
+		return
 	}
 
-	// Test update in-place
-	modifiedUser := User{ID: 1, Name: "Alice Updated"}
-	oldAddr := storage[1]
-	updateInPlace(&modifiedUser)
-
-	fmt.Printf("Après updateInPlace - storage[1]: %+v (adresse: %p)\n",
-		storage[1], storage[1])
-	fmt.Printf("Même adresse? %t\n", oldAddr == storage[1])
-
-	// Test update with replace
-	modifiedUser2 := User{ID: 1, Name: "Alice Replaced"}
-	oldAddr2 := storage[1]
-	updateWithReplace(&modifiedUser2)
-
-	fmt.Printf("Après updateWithReplace - storage[1]: %+v (adresse: %p)\n",
-		storage[1], storage[1])
-	fmt.Printf("Même adresse? %t\n", oldAddr2 == storage[1])
-}
-
-// =============================================================================
-// 4. ALLOCATION MÉMOIRE - STACK VS HEAP
-// =============================================================================
-
-func demonstrateStackVsHeap() {
-	fmt.Println("\n=== 4. STACK VS HEAP ===")
-
-	// Variables locales (généralement sur la stack)
-	localUser := User{ID: 1, Name: "Local"}
-	fmt.Printf("Local user (stack probablement): %p\n", &localUser)
-
-	// Allocation explicite sur le heap avec new()
-	heapUser := new(User)
-	*heapUser = User{ID: 2, Name: "Heap"}
-	fmt.Printf("Heap user (heap): %p\n", heapUser)
-
-	// Allocation avec make pour un slice
-	users := make([]*User, 0, 10)
-	fmt.Printf("Slice (heap): %p\n", users)
-
-	// Quand une variable locale "s'échappe", Go la met automatiquement sur le heap
-	escapeToHeap := func() *User {
-		localUser := User{ID: 3, Name: "Escaped"}
-		return &localUser // ⚠️ Cette variable va sur le heap automatiquement !
+	if dlqOpts != nil {
+		if err := runDLQ(ctx, dlqOpts, app, os.Stdout); err != nil {
+			log.Fatalf("dlq: %v", err)
+		}
+		_ = app.Shutdown(context.Background())
+		return
 	}
 
-	escapedUser := escapeToHeap()
-	fmt.Printf("Escaped user (heap automatiquement): %p\n", escapedUser)
-}
-
-// =============================================================================
-// 5. EXEMPLES PRATIQUES REPOSITORY
-// =============================================================================
-
-// Mock Repository sécurisé
-type MockUserRepository struct {
-	users map[int]*User
-}
-
-// List() - Version sécurisée qui retourne des copies
-func (r *MockUserRepository) List() []*User {
-	var result []*User
-	for _, user := range r.users {
-		// Créer une COPIE pour éviter les modifications accidentelles
-		userCopy := *user                  // Copie la valeur
-		result = append(result, &userCopy) // Nouveau pointeur vers la copie
+	if anonymizeOpts != nil {
+		anonymizeData, err := app.AnonymizeData()
+		if err != nil {
+			log.Fatalf("anonymize: %v", err)
+		}
+		if err := runAnonymize(ctx, anonymizeOpts, anonymizeData, logger); err != nil {
+			log.Fatalf("anonymize: %v", err)
+		}
+		_ = app.Shutdown(context.Background())
+		return
 	}
-	return result
-}
 
-// Update() - Version qui modifie en place (efficace)
-func (r *MockUserRepository) Update(user *User) error {
-	existing, exists := r.users[user.ID]
-	if !exists {
-		return fmt.Errorf("user not found")
+	if seedOpts != nil {
+		err := runSeed(ctx, seedOpts, seedUseCases{
+			bulkCreateUsers:               app.BulkCreateUsers,
+			updateUser:                    app.UpdateUser,
+			updateDigestPreference:        app.UpdateDigestPreference,
+			updateNotificationPreferences: app.UpdateNotificationPreferences,
+		}, logger)
+		if err != nil {
+			log.Fatalf("seed: %v", err)
+		}
+		if !seedOpts.serve {
+			_ = app.Shutdown(context.Background())
+			return
+		}
 	}
 
-	// Option 1: Modifier en place (même allocation mémoire)
-	existing.Name = user.Name
-	// existing reste à la même adresse
-
-	// Option 2: Remplacer complètement (nouvelle allocation)
-	// userCopy := *user
-	// r.users[user.ID] = &userCopy
-
-	return nil
-}
-
-// GetByID() - Retourne une copie pour sécurité
-func (r *MockUserRepository) GetByID(id int) (*User, error) {
-	user, exists := r.users[id]
-	if !exists {
-		return nil, fmt.Errorf("user not found")
+	server := &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           app.Handler,
+		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	// Retourner une COPIE pour éviter les modifications externes
-	userCopy := *user
-	return &userCopy, nil
-}
-
-func demonstrateRepositoryMemory() {
-	fmt.Println("\n=== 5. REPOSITORY MEMORY MANAGEMENT ===")
-
-	repo := &MockUserRepository{
-		users: map[int]*User{
-			1: {ID: 1, Name: "Alice"},
-			2: {ID: 2, Name: "Bob"},
-		},
+	// Arrêt propre sur SIGINT/SIGTERM (envoyé par Lambda avant d'arrêter l'instance)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	if lambdaMode {
+		// Même handler que le serveur HTTP ; les événements en tampon sont écrits avant chaque réponse,
+		// l'instance pouvant être gelée ou arrêtée juste après
+		go func() {
+			logger.Info("Lambda runtime started", map[string]interface{}{"runtime_api": cfg.LambdaRuntimeAPI})
+			err := runLambda(ctx, newLambdaRuntime(cfg.LambdaRuntimeAPI), server.Handler, logger, func(ctx context.Context) {
+				if eventBuffer := app.EventBuffer; eventBuffer != nil {
+					if err := eventBuffer.Flush(ctx); err != nil {
+						logger.Error("Event buffer flush failed", err, map[string]interface{}{"pending": eventBuffer.Pending()})
+					}
+				}
+			})
+			if err != nil {
+				log.Fatalf("lambda runtime: %v", err)
+			}
+		}()
+	} else {
+		go func() {
+			logger.Info("HTTP server listening", map[string]interface{}{"addr": cfg.HTTPAddr})
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("http server: %v", err)
+			}
+		}()
 	}
+	// SIGHUP : relecture des réglages rechargeables (niveau de log, quotas, feature flags locaux)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			// Erreurs journalisées, réglages précédents conservés
+			_ = app.ReloadSettings()
+		}
+	}()
+	<-stop
 
-	// Test List() - doit retourner des copies
-	users := repo.List()
-	fmt.Printf("Original Alice: %p\n", repo.users[1])
-	fmt.Printf("Liste Alice: %p\n", users[0])
-	fmt.Printf("Même adresse? %t (doit être false pour sécurité)\n",
-		repo.users[1] == users[0])
-
-	// Modifier via la liste ne doit pas affecter l'original
-	users[0].Name = "Modified Alice"
-	fmt.Printf("Original après modification liste: %s (doit être inchangé)\n",
-		repo.users[1].Name)
-
-	// Test Update() - modifie en place
-	updateUser := &User{ID: 1, Name: "Updated Alice"}
-	oldAddr := repo.users[1]
-	repo.Update(updateUser)
-
-	fmt.Printf("Adresse avant update: %p\n", oldAddr)
-	fmt.Printf("Adresse après update: %p\n", repo.users[1])
-	fmt.Printf("Même adresse après update? %t (efficace si true)\n",
-		oldAddr == repo.users[1])
-}
-
-func main() {
-	demonstratePointerVsCopy()
-	demonstrateList()
-	demonstrateUpdate()
-	demonstrateStackVsHeap()
-	demonstrateRepositoryMemory()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("HTTP server shutdown failed", err, nil)
+	}
+	// Erreurs déjà journalisées
+	_ = app.Shutdown(shutdownCtx)
 }
-
-// =============================================================================
-// RÉSUMÉ DES BONNES PRATIQUES
-// =============================================================================
-
-/*
-1. SLICE DE POINTEURS []*User :
-   - Chaque élément pointe vers un User en mémoire
-   - Si vous retournez les pointeurs originaux → modifications possibles
-   - Si vous retournez des copies → sécurisé mais plus de mémoire
-
-2. UPDATE avec *User :
-   - Reçoit un pointeur vers l'objet à updater
-   - Peut modifier en place (efficace) ou remplacer (simple mais coûteux)
-
-3. RÈGLES DE SÉCURITÉ :
-   - Repository.List() → retourner des copies
-   - Repository.GetByID() → retourner une copie
-   - Repository.Update() → modifier en place si possible
-
-4. ALLOCATION MÉMOIRE :
-   - Variables locales → stack (rapide)
-   - new(), make(), variables qui "s'échappent" → heap
-   - Go gère automatiquement (garbage collector)
-
-5. PERFORMANCE :
-   - Copies = plus de mémoire mais sécurisé
-   - Pointeurs partagés = économique mais risqué
-   - Choisir selon le contexte
-*/
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// SOUS-COMMANDE "rebuild-projections" : rejeu du magasin d'événements
+// =============================================================================
+
+// rebuildProjectionsOptions options de `api rebuild-projections [-from N | -checkpoint FICHIER]
+// [-only read_model,rollups,search,cache] [-batch N]`
+// Sans checkpoint, le rejeu est complet : les projections sont vidées puis reconstruites
+type rebuildProjectionsOptions struct {
+	from        int64
+	checkpoint  string
+	projections []string
+	batchSize   int
+}
+
+func parseRebuildProjectionsOptions(args []string) (*rebuildProjectionsOptions, error) {
+	opts := &rebuildProjectionsOptions{}
+	var only string
+	fs := flag.NewFlagSet("rebuild-projections", flag.ContinueOnError)
+	fs.Int64Var(&opts.from, "from", 0, "position après laquelle reprendre le rejeu ; 0 = rejeu complet")
+	fs.StringVar(&opts.checkpoint, "checkpoint", "", "fichier de checkpoint : reprise à la position enregistrée (absent = rejeu complet), mis à jour après chaque lot")
+	fs.StringVar(&only, "only", "", "projections à reconstruire, séparées par des virgules ; vide = toutes")
+	fs.IntVar(&opts.batchSize, "batch", 0, "événements lus par lot ; 0 = valeur par défaut")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if opts.checkpoint != "" && opts.from != 0 {
+		return nil, fmt.Errorf("-from et -checkpoint sont exclusifs")
+	}
+	for _, name := range strings.Split(only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.projections = append(opts.projections, name)
+		}
+	}
+	return opts, nil
+}
+
+// runRebuildProjections rejoue jusqu'à la dernière position connue au démarrage ; interrompu,
+// il reprend au checkpoint du dernier lot écrit (-checkpoint, ou -from avec la position journalisée)
+func runRebuildProjections(ctx context.Context, opts *rebuildProjectionsOptions, rebuild usecases.UseCase[usecases.RebuildProjectionsRequest, *usecases.RebuildProjectionsResponse], logger usecases.Logger) error {
+	from := opts.from
+	if opts.checkpoint != "" {
+		var err error
+		if from, err = readCheckpoint(opts.checkpoint); err != nil {
+			return err
+		}
+	}
+
+	response, err := rebuild.Execute(ctx, usecases.RebuildProjectionsRequest{
+		From:        from,
+		Projections: opts.projections,
+		BatchSize:   opts.batchSize,
+		Progress: func(progress usecases.RebuildProgress) error {
+			percent := 100.0
+			if progress.Head > 0 {
+				percent = float64(progress.Position) * 100 / float64(progress.Head)
+			}
+			logger.Info("Projection rebuild progress", map[string]interface{}{
+				"position": progress.Position,
+				"head":     progress.Head,
+				"events":   progress.Events,
+				"percent":  fmt.Sprintf("%.1f", percent),
+			})
+			if opts.checkpoint == "" {
+				return nil
+			}
+			return writeCheckpoint(opts.checkpoint, progress.Position)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Projection rebuild finished", map[string]interface{}{
+		"projections": response.Projections,
+		"from":        response.From,
+		"position":    response.Position,
+		"events":      response.Events,
+		"skipped":     response.Skipped,
+	})
+	return nil
+}
+
+// readCheckpoint position enregistrée ; 0 si le fichier n'existe pas encore
+func readCheckpoint(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	position, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || position < 0 {
+		return 0, fmt.Errorf("checkpoint %s : position invalide", path)
+	}
+	return position, nil
+}
+
+// writeCheckpoint fichier temporaire renommé : une interruption ne laisse pas de checkpoint tronqué
+func writeCheckpoint(path string, position int64) error {
+	tmp := path + ".partial"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(position, 10)+"\n"), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// =============================================================================
+// SOUS-COMMANDE "rollup-backfill" : recalcul des agrégats quotidiens
+// =============================================================================
+
+// rollupBackfillOptions options de `api rollup-backfill -from AAAA-MM-JJ [-to AAAA-MM-JJ]`
+// L'historique est lu dans l'index Elasticsearch des événements (ELASTICSEARCH_URL obligatoire)
+type rollupBackfillOptions struct {
+	from time.Time
+	to   time.Time
+}
+
+func parseRollupBackfillOptions(args []string) (*rollupBackfillOptions, error) {
+	var from, to string
+	fs := flag.NewFlagSet("rollup-backfill", flag.ContinueOnError)
+	fs.StringVar(&from, "from", "", "premier jour recalculé (AAAA-MM-JJ, UTC)")
+	fs.StringVar(&to, "to", "", "jour suivant le dernier recalculé (AAAA-MM-JJ, exclu) ; vide = jusqu'à hier inclus")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if from == "" {
+		return nil, fmt.Errorf("-from est obligatoire")
+	}
+
+	opts := &rollupBackfillOptions{}
+	var err error
+	if opts.from, err = time.Parse(time.DateOnly, from); err != nil {
+		return nil, fmt.Errorf("-from : date AAAA-MM-JJ attendue")
+	}
+	if to != "" {
+		if opts.to, err = time.Parse(time.DateOnly, to); err != nil {
+			return nil, fmt.Errorf("-to : date AAAA-MM-JJ attendue")
+		}
+	}
+	return opts, nil
+}
+
+// runRollupBackfill rejouable : chaque période recalculée remplace entièrement ses agrégats
+func runRollupBackfill(ctx context.Context, opts *rollupBackfillOptions, backfill usecases.UseCase[usecases.BackfillRollupsRequest, *usecases.BackfillRollupsResponse], logger usecases.Logger) error {
+	response, err := backfill.Execute(ctx, usecases.BackfillRollupsRequest{From: opts.from, To: opts.to})
+	if err != nil {
+		return err
+	}
+	logger.Info("Rollup backfill finished", map[string]interface{}{
+		"days":    response.Days,
+		"rollups": response.Rollups,
+		"events":  response.Events,
+	})
+	return nil
+}
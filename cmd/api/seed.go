@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// =============================================================================
+// SOUS-COMMANDE "seed" : données de démo et de test de charge
+// =============================================================================
+
+// seedOptions options de `api seed` ; le même -seed produit toujours les mêmes données
+type seedOptions struct {
+	users    int
+	seed     uint64
+	password string
+	serve    bool
+	targets  string
+	baseURL  string
+}
+
+func parseSeedOptions(args []string) (*seedOptions, error) {
+	opts := &seedOptions{}
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.IntVar(&opts.users, "users", 100, "nombre d'utilisateurs à générer")
+	fs.Uint64Var(&opts.seed, "seed", 1, "graine du générateur (données reproductibles)")
+	fs.StringVar(&opts.password, "password", "seed-password", "mot de passe commun des comptes générés (tests de charge)")
+	fs.BoolVar(&opts.serve, "serve", false, "démarrer l'API après le seed (indispensable en persistance mémoire)")
+	fs.StringVar(&opts.targets, "targets", "", "fichier de scénario de charge à générer (JSON lines, format vegeta/k6)")
+	fs.StringVar(&opts.baseURL, "base-url", "http://localhost:8080", "URL de l'API visée par le scénario")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if opts.users <= 0 {
+		return nil, fmt.Errorf("-users doit être positif")
+	}
+	return opts, nil
+}
+
+// seedUseCases use cases empruntés par le seed : mêmes règles, mêmes événements que l'API
+type seedUseCases struct {
+	bulkCreateUsers               usecases.UseCase[usecases.BulkCreateUsersRequest, *usecases.BulkCreateUsersResponse]
+	updateUser                    usecases.UseCase[usecases.UpdateUserRequest, *usecases.UpdateUserResponse]
+	updateDigestPreference        usecases.UseCase[usecases.UpdateDigestPreferenceRequest, *usecases.UpdateDigestPreferenceResponse]
+	updateNotificationPreferences usecases.UseCase[usecases.UpdateNotificationPreferencesRequest, *usecases.NotificationPreferencesResponse]
+}
+
+var (
+	seedFirstNames = []string{
+		"Alice", "Bruno", "Camille", "David", "Emma", "Farid", "Gabrielle", "Hugo", "Inès", "Jules",
+		"Karim", "Léa", "Mathis", "Nora", "Oscar", "Pauline", "Quentin", "Rose", "Samuel", "Théo",
+		"Ulysse", "Valentine", "William", "Yasmine", "Zoé", "Aiko", "Björn", "Chen", "Diego", "Elif",
+	}
+	seedLastNames = []string{
+		"Martin", "Bernard", "Dubois", "Thomas", "Robert", "Richard", "Petit", "Durand", "Leroy", "Moreau",
+		"Simon", "Laurent", "Lefebvre", "Michel", "Garcia", "Roux", "Fournier", "Nguyen", "Haddad", "Schmidt",
+		"Rossi", "Kowalski", "Tanaka", "Silva", "O'Brien", "Andersen", "Yilmaz", "Popescu", "Novak", "Costa",
+	}
+	// Domaines réservés à la documentation (RFC 2606) : aucun email réel ne peut partir
+	seedDomains = []string{"example.com", "example.org", "example.net"}
+)
+
+// seedBatchSize lignes par import : bien sous MaxBulkSize, et une progression visible
+const seedBatchSize = 100
+
+// runSeed génère les utilisateurs par lots puis leur activité (profil, préférences),
+// en passant par les use cases : le modèle de lecture, l'activité et l'analytics sont alimentés
+// par les événements publiés, exactement comme en production
+func runSeed(ctx context.Context, opts *seedOptions, uc seedUseCases, logger usecases.Logger) error {
+	rng := rand.New(rand.NewPCG(opts.seed, opts.seed^0x9e3779b97f4a7c15))
+
+	var created []*usecases.CreateUserResponse
+	for start := 0; start < opts.users; start += seedBatchSize {
+		batch := usecases.BulkCreateUsersRequest{}
+		for i := start; i < min(start+seedBatchSize, opts.users); i++ {
+			first := seedFirstNames[rng.IntN(len(seedFirstNames))]
+			last := seedLastNames[rng.IntN(len(seedLastNames))]
+			batch.Users = append(batch.Users, usecases.CreateUserRequest{
+				// Le numéro de ligne garantit l'unicité sans dépendre du tirage
+				Email:    fmt.Sprintf("%s.%s.%d@%s", emailPart(first), emailPart(last), i+1, seedDomains[rng.IntN(len(seedDomains))]),
+				Name:     first + " " + last,
+				Password: opts.password,
+			})
+		}
+
+		response, err := uc.bulkCreateUsers.Execute(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("création des utilisateurs %d-%d : %w", start+1, start+len(batch.Users), err)
+		}
+		created = append(created, response.Users...)
+		logger.Info("Seed progress", map[string]interface{}{"created": len(created), "total": opts.users})
+	}
+
+	events := 0
+	for _, user := range created {
+		// Environ un tiers change de nom (mariage, faute de frappe corrigée...)
+		if rng.IntN(3) == 0 {
+			name := seedFirstNames[rng.IntN(len(seedFirstNames))] + " " + seedLastNames[rng.IntN(len(seedLastNames))]
+			if _, err := uc.updateUser.Execute(ctx, usecases.UpdateUserRequest{ID: user.ID, Email: user.Email, Name: name}); err != nil {
+				return fmt.Errorf("mise à jour de l'utilisateur %d : %w", user.ID, err)
+			}
+			events++
+		}
+
+		// La moitié s'abonne au résumé hebdomadaire
+		if rng.IntN(2) == 0 {
+			if _, err := uc.updateDigestPreference.Execute(ctx, usecases.UpdateDigestPreferenceRequest{UserID: user.ID, Enabled: true}); err != nil {
+				return fmt.Errorf("préférence digest de l'utilisateur %d : %w", user.ID, err)
+			}
+			events++
+		}
+
+		// Un sur cinq renseigne un numéro de mobile fictif (plage réservée aux fictions, Ofcom)
+		if rng.IntN(5) == 0 {
+			phone := fmt.Sprintf("+447700900%03d", rng.IntN(1000))
+			if _, err := uc.updateNotificationPreferences.Execute(ctx, usecases.UpdateNotificationPreferencesRequest{UserID: user.ID, Phone: &phone}); err != nil {
+				return fmt.Errorf("préférences de notification de l'utilisateur %d : %w", user.ID, err)
+			}
+			events++
+		}
+	}
+
+	logger.Info("Seed finished", map[string]interface{}{
+		"users":  len(created),
+		"events": events + len(created),
+		"seed":   opts.seed,
+	})
+
+	if opts.targets == "" {
+		return nil
+	}
+	count, err := writeLoadTargets(opts.targets, opts.baseURL, created, rng)
+	if err != nil {
+		return fmt.Errorf("scénario de charge : %w", err)
+	}
+	logger.Info("Load test targets written", map[string]interface{}{"file": opts.targets, "targets": count})
+	return nil
+}
+
+// loadTarget une requête du scénario, au format JSON de vegeta (`vegeta attack -format=json`)
+// Le script loadtest/k6.js lit le même fichier
+type loadTarget struct {
+	Method string              `json:"method"`
+	URL    string              `json:"url"`
+	Body   []byte              `json:"body,omitempty"` // base64, comme l'attend vegeta
+	Header map[string][]string `json:"header,omitempty"`
+}
+
+// writeLoadTargets écrit un mélange réaliste, majoritairement en lecture :
+// 70 % consultation d'un profil, 20 % pagination de la liste, 10 % mise à jour de profil
+func writeLoadTargets(path, baseURL string, users []*usecases.CreateUserResponse, rng *rand.Rand) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	jsonHeader := map[string][]string{"Content-Type": {"application/json"}}
+	pages := (len(users) + 9) / 10
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	count := len(users) * 10
+	for i := 0; i < count; i++ {
+		user := users[rng.IntN(len(users))]
+		var target loadTarget
+		switch roll := rng.IntN(10); {
+		case roll < 7:
+			target = loadTarget{Method: http.MethodGet, URL: fmt.Sprintf("%s/users/%d", baseURL, user.ID)}
+		case roll < 9:
+			target = loadTarget{Method: http.MethodGet, URL: fmt.Sprintf("%s/users?page=%d&page_size=10", baseURL, rng.IntN(pages)+1)}
+		default:
+			body, err := json.Marshal(map[string]string{"email": user.Email, "name": user.Name})
+			if err != nil {
+				return 0, err
+			}
+			target = loadTarget{Method: http.MethodPut, URL: fmt.Sprintf("%s/users/%d", baseURL, user.ID), Body: body, Header: jsonHeader}
+		}
+		if err := encoder.Encode(target); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+	return count, file.Close()
+}
+
+// emailPart normalise un prénom ou un nom pour la partie locale d'un email
+func emailPart(name string) string {
+	replacer := strings.NewReplacer("é", "e", "è", "e", "ë", "e", "ï", "i", "î", "i", "ö", "o", "ø", "o", "'", "", " ", "")
+	return strings.ToLower(replacer.Replace(name))
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// JOURNAL D'ACCÈS (séparé des logs applicatifs)
+// =============================================================================
+
+// Formats du journal d'accès
+const (
+	AccessLogCombined = "combined" // format "combined" d'Apache / nginx
+	AccessLogJSON     = "json"     // une ligne JSON par requête
+)
+
+// AccessLogOptions réglages de AccessLog
+type AccessLogOptions struct {
+	// Format AccessLogCombined (défaut) ou AccessLogJSON
+	Format string
+	// ErrorBodies ajoute le corps JSON de la requête aux lignes des réponses 4xx/5xx, champs
+	// sensibles (mot de passe, jeton, secret) masqués ; les autres corps ne sont jamais journalisés
+	ErrorBodies bool
+	// MaxBodySize octets du corps conservés pour ErrorBodies (défaut 4 Kio)
+	MaxBodySize int
+}
+
+// AccessLog écrit une ligne par requête dans out, une fois la réponse terminée ; out doit accepter
+// des écritures concurrentes (services.RotatingFile, os.Stdout). L'utilisateur est celui posé par Authenticate
+func AccessLog(next http.Handler, out io.Writer, options AccessLogOptions) http.Handler {
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = 4 << 10
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &accessLogEntry{}
+		r = r.WithContext(context.WithValue(r.Context(), accessLogEntryKey{}, entry))
+
+		var body *cappedBuffer
+		if options.ErrorBodies && r.Body != nil && r.Body != http.NoBody && isJSONRequest(r) {
+			body = &cappedBuffer{limit: options.MaxBodySize}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(r.Body, body), r.Body}
+		}
+
+		recorder := &accessLogWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			record := accessLogRecord{
+				Time:       start,
+				RemoteAddr: remoteHost(r.RemoteAddr),
+				Method:     r.Method,
+				URI:        redactURI(r.RequestURI),
+				Proto:      r.Proto,
+				Status:     recorder.status,
+				Bytes:      recorder.bytes,
+				DurationMS: time.Since(start).Milliseconds(),
+				Referer:    r.Referer(),
+				UserAgent:  r.UserAgent(),
+				RequestID:  usecases.RequestIDFromContext(r.Context()),
+				UserID:     entry.userID,
+			}
+			if body != nil && recorder.status >= http.StatusBadRequest {
+				record.RequestBody = redactBody(body.Bytes(), body.truncated)
+			}
+			writeAccessLogLine(out, options.Format, record)
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// accessLogEntryKey donne à Authenticate, placé sous AccessLog, l'entrée à compléter
+type accessLogEntryKey struct{}
+
+type accessLogEntry struct {
+	userID int
+}
+
+// noteAccessLogActor renseigne l'utilisateur authentifié de la ligne du journal d'accès
+func noteAccessLogActor(ctx context.Context, actor usecases.Actor) {
+	if entry, ok := ctx.Value(accessLogEntryKey{}).(*accessLogEntry); ok {
+		entry.userID = actor.UserID
+	}
+}
+
+type accessLogRecord struct {
+	Time        time.Time       `json:"time"`
+	RemoteAddr  string          `json:"remote_addr"`
+	Method      string          `json:"method"`
+	URI         string          `json:"uri"`
+	Proto       string          `json:"proto"`
+	Status      int             `json:"status"`
+	Bytes       int64           `json:"bytes"`
+	DurationMS  int64           `json:"duration_ms"`
+	Referer     string          `json:"referer,omitempty"`
+	UserAgent   string          `json:"user_agent,omitempty"`
+	RequestID   string          `json:"request_id,omitempty"`
+	UserID      int             `json:"user_id,omitempty"`
+	RequestBody json.RawMessage `json:"request_body,omitempty"`
+}
+
+func writeAccessLogLine(out io.Writer, format string, record accessLogRecord) {
+	var line []byte
+	if format == AccessLogJSON {
+		line, _ = json.Marshal(record)
+		line = append(line, '\n')
+	} else {
+		line = combinedLine(record)
+	}
+	// Une écriture par ligne : pas d'entrelacement entre requêtes concurrentes
+	_, _ = out.Write(line)
+}
+
+// combinedLine %h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i", suivi de
+// l'identifiant de requête et, le cas échéant, du corps masqué
+func combinedLine(record accessLogRecord) []byte {
+	user := "-"
+	if record.UserID > 0 {
+		user = strconv.Itoa(record.UserID)
+	}
+	size := "-"
+	if record.Bytes > 0 {
+		size = strconv.FormatInt(record.Bytes, 10)
+	}
+	var line bytes.Buffer
+	line.WriteString(record.RemoteAddr + " - " + user + " [" + record.Time.Format("02/Jan/2006:15:04:05 -0700") + "] ")
+	line.WriteString(quoteCombined(record.Method+" "+record.URI+" "+record.Proto) + " " + strconv.Itoa(record.Status) + " " + size + " ")
+	line.WriteString(quoteCombined(record.Referer) + " " + quoteCombined(record.UserAgent) + " " + quoteCombined(record.RequestID))
+	if record.RequestBody != nil {
+		line.WriteString(" " + quoteCombined(string(record.RequestBody)))
+	}
+	line.WriteByte('\n')
+	return line.Bytes()
+}
+
+// quoteCombined valeur entre guillemets, guillemets et caractères de contrôle échappés ; "-" si vide
+func quoteCombined(value string) string {
+	if value == "" {
+		return `"-"`
+	}
+	return strconv.Quote(value)
+}
+
+func remoteHost(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+func isJSONRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	// Sans Content-Type, les handlers décodent du JSON
+	return err != nil || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// =============================================================================
+// CORPS DES REQUÊTES EN ERREUR
+// =============================================================================
+
+// sensitiveFields fragments de noms de champs dont la valeur n'est jamais journalisée
+var sensitiveFields = []string{"password", "secret", "token", "key", "authorization", "assertion", "signature"}
+
+// redactBody corps JSON aux champs sensibles masqués ; un corps tronqué ou illisible n'est pas recopié
+func redactBody(raw []byte, truncated bool) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var value any
+	if truncated || json.Unmarshal(raw, &value) != nil {
+		summary, _ := json.Marshal(map[string]any{"unparsed_bytes": len(raw), "truncated": truncated})
+		return summary
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			if isSensitiveField(key) {
+				typed[key] = "[REDACTED]"
+				continue
+			}
+			typed[key] = redactValue(nested)
+		}
+	case []any:
+		for i, nested := range typed {
+			typed[i] = redactValue(nested)
+		}
+	}
+	return value
+}
+
+// redactURI masque les paramètres sensibles de la query (signature des liens /downloads/) : le
+// lien signé tient lieu d'autorisation jusqu'à son expiration
+func redactURI(uri string) string {
+	path, rawQuery, found := strings.Cut(uri, "?")
+	if !found {
+		return uri
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return path + "?[REDACTED]"
+	}
+	redacted := false
+	for name := range query {
+		if isSensitiveField(name) {
+			query.Set(name, "[REDACTED]")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return uri
+	}
+	return path + "?" + query.Encode()
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, fragment := range sensitiveFields {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// cappedBuffer retient les limit premiers octets lus
+type cappedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room < len(p) {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// =============================================================================
+// WRITER
+// =============================================================================
+
+// accessLogWriter relève le statut et la taille écrite
+type accessLogWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *accessLogWriter) WriteHeader(status int) {
+	if !w.wroteHeader && status >= 200 {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *accessLogWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack connexion détournée (WebSocket) : journalisée en 101
+func (w *accessLogWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack unsupported")
+	}
+	w.status, w.wroteHeader = http.StatusSwitchingProtocols, true
+	return hijacker.Hijack()
+}
+
+// Unwrap permet à http.ResponseController d'atteindre le writer d'origine
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// AlertHandler règles d'alerte sur les métriques (administration)
+type AlertHandler struct {
+	create usecases.UseCase[usecases.CreateAlertRuleRequest, *entities.AlertRule]
+	list   usecases.UseCase[usecases.ListAlertRulesRequest, *usecases.ListAlertRulesResponse]
+	delete usecases.UseCase[usecases.AlertRuleRequest, struct{}]
+}
+
+func NewAlertHandler(
+	create usecases.UseCase[usecases.CreateAlertRuleRequest, *entities.AlertRule],
+	list usecases.UseCase[usecases.ListAlertRulesRequest, *usecases.ListAlertRulesResponse],
+	delete usecases.UseCase[usecases.AlertRuleRequest, struct{}],
+) *AlertHandler {
+	return &AlertHandler{create: create, list: list, delete: delete}
+}
+
+// Create POST /admin/alerts {"name", "event", "per_event", "condition", "threshold", "window_minutes",
+// "min_count", "recipients", "webhook_url"}
+func (h *AlertHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req usecases.CreateAlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.create.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// List GET /admin/alerts
+func (h *AlertHandler) List(w http.ResponseWriter, r *http.Request) {
+	response, err := h.list.Execute(r.Context(), usecases.ListAlertRulesRequest{})
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Delete DELETE /admin/alerts/{id}
+func (h *AlertHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "alert rule id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.delete.Execute(r.Context(), usecases.AlertRuleRequest{ID: id}); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repositories.ErrAlertRuleNotFound) {
+			status = http.StatusNotFound
+		}
+		writeUseCaseError(w, status, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sseRetry délai de reconnexion suggéré au client EventSource (ms)
+const sseRetry = 3000
+
+// maxTrackBodySize taille maximale d'un événement envoyé à POST /analytics/track
+const maxTrackBodySize = 64 << 10
+
+// AnalyticsStreamSource source des agrégats d'événements temps réel
+type AnalyticsStreamSource interface {
+	Subscribe(lastSeq int64) ([]services.AnalyticsSnapshot, <-chan services.AnalyticsSnapshot, func())
+}
+
+// AnalyticsHandler reçoit les événements des clients, expose les compteurs d'événements en direct
+// et la recherche dans l'historique
+type AnalyticsHandler struct {
+	stream       AnalyticsStreamSource
+	searchEvents usecases.UseCase[usecases.SearchEventsRequest, *usecases.SearchEventsResponse]
+	rollups      usecases.UseCase[usecases.GetEventRollupsRequest, *usecases.GetEventRollupsResponse]
+	track        usecases.UseCase[usecases.TrackEventRequest, *usecases.TrackEventResponse]
+	display      DisplayFormatResolver
+}
+
+func NewAnalyticsHandler(
+	stream AnalyticsStreamSource,
+	searchEvents usecases.UseCase[usecases.SearchEventsRequest, *usecases.SearchEventsResponse],
+	rollups usecases.UseCase[usecases.GetEventRollupsRequest, *usecases.GetEventRollupsResponse],
+	track usecases.UseCase[usecases.TrackEventRequest, *usecases.TrackEventResponse],
+	display DisplayFormatResolver,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{stream: stream, searchEvents: searchEvents, rollups: rollups, track: track, display: display}
+}
+
+// trackRequestPool requêtes d'ingestion décodées : la map de propriétés est réutilisée (le décodeur
+// y ajoute les clés), le use case n'en garde aucune référence après Execute
+var trackRequestPool = sync.Pool{New: func() any { return new(usecases.TrackEventRequest) }}
+
+func releaseTrackRequest(req *usecases.TrackEventRequest) {
+	properties := req.Properties
+	if len(properties) > entities.MaxEventProperties {
+		properties = nil // requête refusée : la map ne garde pas sa taille
+	}
+	clear(properties)
+	*req = usecases.TrackEventRequest{Properties: properties}
+	trackRequestPool.Put(req)
+}
+
+// Track POST /analytics/track {"event": "checkout.completed", "properties": {...}, "timestamp": "..."}
+// 202 dans tous les cas : status indique si l'événement a été enregistré, échantillonné ou écarté
+// (limites de cardinalité), le client n'a pas à réessayer
+func (h *AnalyticsHandler) Track(w http.ResponseWriter, r *http.Request) {
+	serveTrack(w, r, h.track)
+}
+
+// serveTrack partagé avec l'ingestion publique (CollectHandler), qui n'en change que l'authentification
+func serveTrack(w http.ResponseWriter, r *http.Request, track usecases.UseCase[usecases.TrackEventRequest, *usecases.TrackEventResponse]) {
+	req := trackRequestPool.Get().(*usecases.TrackEventRequest)
+	defer releaseTrackRequest(req)
+	if err := decodeBody(w, r, maxTrackBodySize, req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := track.Execute(r.Context(), *req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, response)
+}
+
+// Rollups GET /analytics/rollups?events=&from=&to=&interval=day|week|month&display=true
+// Compteurs lus dans les agrégats quotidiens, avec ou sans moteur de recherche ; display ajoute
+// la période et les compteurs formatés selon la locale de l'appelant
+func (h *AnalyticsHandler) Rollups(w http.ResponseWriter, r *http.Request) {
+	var req usecases.GetEventRollupsRequest
+	b := bindRequest(r)
+	b.QueryString("events", &req.Events)
+	b.DateRange("from", "to", &req.From, &req.To)
+	b.QueryEnum("interval", usecases.EventRollupIntervals, &req.Interval)
+	display := bindDisplay(b, h.display)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.rollups.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	if display {
+		writeJSON(w, http.StatusOK, struct {
+			*usecases.GetEventRollupsResponse
+			Display usecases.EventRollupsDisplay `json:"display"`
+		}{response, usecases.NewEventRollupsDisplay(response, h.display.ForActor(r.Context()))})
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// SearchEvents GET /analytics/events?events=&user_id=&from=&to=&interval=&page=&page_size=
+// Événements les plus récents d'abord, avec leur répartition par nom et par intervalle ;
+// 501 sans moteur de recherche configuré
+func (h *AnalyticsHandler) SearchEvents(w http.ResponseWriter, r *http.Request) {
+	var req usecases.SearchEventsRequest
+	b := bindRequest(r)
+	b.QueryString("events", &req.Events)
+	b.QueryInt("user_id", &req.UserID, 1, 0)
+	b.DateRange("from", "to", &req.From, &req.To)
+	b.QueryEnum("interval", usecases.EventHistogramIntervals, &req.Interval)
+	b.Pagination(&req.Page, &req.PageSize)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.searchEvents.Execute(r.Context(), req)
+	if errors.Is(err, repositories.ErrSearchNotSupported) {
+		writeError(w, http.StatusNotImplemented, repositories.ErrSearchNotSupported.Error())
+		return
+	}
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Stream GET /analytics/stream?events=user.created,user.deleted
+// Le client EventSource renvoie Last-Event-ID à la reconnexion : les agrégats manqués sont rejoués
+func (h *AnalyticsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	var filter map[string]bool
+	if raw := r.URL.Query().Get("events"); raw != "" {
+		filter = make(map[string]bool)
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				filter[name] = true
+			}
+		}
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id") // Clients sans en-têtes personnalisés
+	}
+	var lastSeq int64
+	if lastEventID != "" {
+		parsed, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "invalid Last-Event-ID")
+			return
+		}
+		lastSeq = parsed
+	}
+
+	replay, snapshots, unsubscribe := h.stream.Subscribe(lastSeq)
+	defer unsubscribe()
+
+	flusher, ok := startSSE(w)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+
+	// Le rejeu est toujours écrit : il resynchronise les totaux du client
+	for _, snapshot := range replay {
+		writeAnalyticsSnapshot(w, snapshot, filter, true)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case snapshot, open := <-snapshots:
+			if !open {
+				return
+			}
+			if writeAnalyticsSnapshot(w, snapshot, filter, false) {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// writeAnalyticsSnapshot écrit l'agrégat filtré ; sauf always, rien n'est écrit si aucun compteur filtré n'a bougé
+func writeAnalyticsSnapshot(w http.ResponseWriter, snapshot services.AnalyticsSnapshot, filter map[string]bool, always bool) bool {
+	if filter != nil {
+		snapshot.Totals = filterCounts(snapshot.Totals, filter)
+		snapshot.Deltas = filterCounts(snapshot.Deltas, filter)
+		if len(snapshot.Deltas) == 0 && !always {
+			return false
+		}
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return false
+	}
+	// L'ID reste la séquence globale pour que Last-Event-ID fonctionne quel que soit le filtre
+	fmt.Fprintf(w, "id: %d\nevent: counts\ndata: %s\n\n", snapshot.Seq, data)
+	return true
+}
+
+func filterCounts(counts map[string]int, filter map[string]bool) map[string]int {
+	result := make(map[string]int)
+	for name, count := range counts {
+		if filter[name] {
+			result[name] = count
+		}
+	}
+	return result
+}
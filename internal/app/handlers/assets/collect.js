@@ -0,0 +1,26 @@
+// Traceur de l'ingestion publique, servi par GET /collect/snippet.js
+//
+//   <script async src="https://api.example.com/collect/snippet.js" data-write-key="wk_..."></script>
+//   <script>
+//     window.analytics = window.analytics || { q: [], track: function () { this.q.push(arguments) } }
+//     analytics.track("signup_started", { plan: "pro" })
+//   </script>
+//
+// Les appels faits avant le chargement sont mis en file (analytics.q) puis envoyés. sendBeacon
+// envoie un corps text/plain : ni pré-vol CORS, ni perte à la fermeture de la page
+(function () {
+  var script = document.currentScript
+  if (!script) return
+  var writeKey = script.getAttribute("data-write-key")
+  var endpoint = script.src.replace(/snippet\.js(\?.*)?$/, "track") + "?write_key=" + encodeURIComponent(writeKey)
+
+  function send(event, properties) {
+    var body = JSON.stringify({ event: event, properties: properties || {}, timestamp: new Date().toISOString() })
+    if (navigator.sendBeacon && navigator.sendBeacon(endpoint, body)) return
+    fetch(endpoint, { method: "POST", body: body, keepalive: true, credentials: "omit" }).catch(function () {})
+  }
+
+  var queued = (window.analytics && window.analytics.q) || []
+  window.analytics = { track: send }
+  for (var i = 0; i < queued.length; i++) send(queued[i][0], queued[i][1])
+})()
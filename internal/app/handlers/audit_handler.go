@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/csv"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// AuditHandler consultation et export du journal d'audit (rôle d'administration requis)
+type AuditHandler struct {
+	list    usecases.UseCase[usecases.ListAuditEntriesRequest, *usecases.ListAuditEntriesResponse]
+	export  usecases.UseCase[usecases.ExportAuditEntriesRequest, *usecases.ExportAuditEntriesResponse]
+	link    usecases.UseCase[usecases.AuditQuery, *usecases.DownloadLink]
+	display DisplayFormatResolver
+}
+
+func NewAuditHandler(
+	list usecases.UseCase[usecases.ListAuditEntriesRequest, *usecases.ListAuditEntriesResponse],
+	export usecases.UseCase[usecases.ExportAuditEntriesRequest, *usecases.ExportAuditEntriesResponse],
+	link usecases.UseCase[usecases.AuditQuery, *usecases.DownloadLink],
+	display DisplayFormatResolver,
+) *AuditHandler {
+	return &AuditHandler{list: list, export: export, link: link, display: display}
+}
+
+// List GET /admin/audit?actor_id=&target_user_id=&action=&from=&to=&cursor=&limit=
+// Entrées les plus récentes d'abord ; next_cursor est absent sur la dernière page
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ListAuditEntriesRequest
+	b := bindAuditQuery(r, &req.AuditQuery)
+	b.QueryString("cursor", &req.Cursor)
+	b.QueryInt("limit", &req.Limit, 1, 500)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.list.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Export GET /admin/audit/export?actor_id=&target_user_id=&action=&from=&to=&display=true
+// Toutes les entrées correspondantes en CSV, écrites au fil de la lecture ; une erreur en cours
+// d'export ne peut plus changer le statut : le fichier est alors tronqué. display ajoute une
+// colonne at_display : la date dans la locale et le fuseau de l'appelant
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ExportAuditEntriesRequest
+	b := bindAuditQuery(r, &req.AuditQuery)
+	display := bindDisplay(b, h.display)
+	if !b.Valid(w) {
+		return
+	}
+	var format *usecases.DisplayFormat
+	if display {
+		actorFormat := h.display.ForActor(r.Context())
+		format = &actorFormat
+	}
+	h.writeExport(w, r, req, format)
+}
+
+// ExportLink POST /admin/audit/export/link?actor_id=&target_user_id=&action=&from=&to=
+// Lien signé et temporaire vers le même export (GET /downloads/audit), téléchargeable sans jeton
+func (h *AuditHandler) ExportLink(w http.ResponseWriter, r *http.Request) {
+	var query usecases.AuditQuery
+	b := bindAuditQuery(r, &query)
+	if !b.Valid(w) {
+		return
+	}
+
+	link, err := h.link.Execute(r.Context(), query)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, link)
+}
+
+// Download GET /downloads/audit?...&expires=&signature= : export d'un lien émis par ExportLink,
+// monté derrière RequireSignedURL
+func (h *AuditHandler) Download(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ExportAuditEntriesRequest
+	b := bindAuditQuery(r, &req.AuditQuery)
+	if !b.Valid(w) {
+		return
+	}
+	h.writeExport(w, r, req, nil)
+}
+
+// writeExport format non nil : colonne at_display
+func (h *AuditHandler) writeExport(w http.ResponseWriter, r *http.Request, req usecases.ExportAuditEntriesRequest, format *usecases.DisplayFormat) {
+	header := usecases.AuditCSVHeader
+	if format != nil {
+		header = append(slices.Clip(usecases.AuditCSVHeader), "at_display")
+	}
+
+	out := csv.NewWriter(w)
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit-`+time.Now().UTC().Format("20060102T150405Z")+`.csv"`)
+		w.WriteHeader(http.StatusOK)
+		_ = out.Write(header)
+		started = true
+	}
+	req.Emit = func(entry usecases.AuditEntryResponse) error {
+		if !started {
+			start()
+		}
+		record := entry.CSVRecord()
+		if format != nil {
+			record = append(record, format.DateTime(entry.At))
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+		return out.Error()
+	}
+
+	_, err := h.export.Execute(r.Context(), req)
+	if err != nil && !started {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !started {
+		start()
+	}
+	out.Flush()
+}
+
+func bindAuditQuery(r *http.Request, query *usecases.AuditQuery) *requestBinder {
+	b := bindRequest(r)
+	b.QueryInt("actor_id", &query.ActorID, 1, 0)
+	b.QueryInt("target_user_id", &query.TargetUserID, 1, 0)
+	b.QueryString("action", &query.Action)
+	b.DateRange("from", "to", &query.From, &query.To)
+	return b
+}
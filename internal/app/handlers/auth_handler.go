@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// AuthHandler expose la connexion par email et mot de passe, et les sessions d'assistance des administrateurs
+type AuthHandler struct {
+	login       usecases.UseCase[usecases.LoginRequest, *usecases.LoginResponse]
+	verifyLogin usecases.UseCase[usecases.VerifyLoginRequest, *usecases.LoginResponse]
+	impersonate usecases.UseCase[usecases.ImpersonateUserRequest, *usecases.ImpersonateUserResponse]
+	session     SessionCookieOptions
+}
+
+func NewAuthHandler(
+	login usecases.UseCase[usecases.LoginRequest, *usecases.LoginResponse],
+	verifyLogin usecases.UseCase[usecases.VerifyLoginRequest, *usecases.LoginResponse],
+	impersonate usecases.UseCase[usecases.ImpersonateUserRequest, *usecases.ImpersonateUserResponse],
+	session SessionCookieOptions,
+) *AuthHandler {
+	return &AuthHandler{login: login, verifyLogin: verifyLogin, impersonate: impersonate, session: session}
+}
+
+// Login POST /auth/login
+// 401 invalid_credentials ou step_up_required (code envoyé par email, voir VerifyLogin),
+// 403 account_deactivated, account_banned ou login_blocked.
+// En mode session (SESSION_COOKIE), le jeton est aussi posé dans le cookie de session
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req usecases.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.login.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.session.set(w, response.AccessToken, response.ExpiresAt)
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, response)
+}
+
+// VerifyLogin POST /auth/login/verify {"challenge_id": "...", "code": "123456"}
+// 401 invalid_login_challenge (code faux, vérification expirée ou épuisée)
+func (h *AuthHandler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	var req usecases.VerifyLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.verifyLogin.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.session.set(w, response.AccessToken, response.ExpiresAt)
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Logout POST /auth/logout : retire le cookie de session (204). Le jeton reste valide jusqu'à
+// son expiration ; un client Bearer n'a qu'à l'oublier
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	h.session.clear(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Impersonate POST /users/{id}/impersonate {"reason": "..."}
+// 401 authentication_required, 403 impersonation_forbidden, 404 si le compte est inconnu
+func (h *AuthHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.ImpersonateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.UserID = id
+
+	response, err := h.impersonate.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, impersonationError(err), err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, response)
+}
+
+func impersonationError(err error) int {
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"net/http"
+)
+
+// AvailabilityHandler pilote les modes maintenance et lecture seule de l'instance
+type AvailabilityHandler struct {
+	get usecases.UseCase[struct{}, *usecases.AvailabilityResponse]
+	set usecases.UseCase[usecases.SetAvailabilityRequest, *usecases.AvailabilityResponse]
+}
+
+func NewAvailabilityHandler(
+	get usecases.UseCase[struct{}, *usecases.AvailabilityResponse],
+	set usecases.UseCase[usecases.SetAvailabilityRequest, *usecases.AvailabilityResponse],
+) *AvailabilityHandler {
+	return &AvailabilityHandler{get: get, set: set}
+}
+
+// Get GET /admin/availability : mode effectif et mode configuré
+func (h *AvailabilityHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.get.Execute(r.Context(), struct{}{})
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Set PUT /admin/availability {"mode": "available" | "read_only" | "maintenance"}
+func (h *AvailabilityHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req usecases.SetAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.set.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+const maxSubscriptionBodySize = 16 << 10 // 16 KiB
+
+// BillingHandler souscription d'une offre et compte de facturation d'un tenant
+type BillingHandler struct {
+	// subscribe nil sans fournisseur de facturation configuré (STRIPE_SECRET_KEY)
+	subscribe usecases.UseCase[usecases.SubscribeTenantRequest, *usecases.BillingAccountResponse]
+	get       usecases.UseCase[usecases.GetBillingAccountRequest, *usecases.BillingAccountResponse]
+}
+
+func NewBillingHandler(
+	subscribe usecases.UseCase[usecases.SubscribeTenantRequest, *usecases.BillingAccountResponse],
+	get usecases.UseCase[usecases.GetBillingAccountRequest, *usecases.BillingAccountResponse],
+) *BillingHandler {
+	return &BillingHandler{subscribe: subscribe, get: get}
+}
+
+// Subscribe POST /tenants/{tenant}/subscription {"plan": "pro", "email": "billing@acme.test"}
+func (h *BillingHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	if h.subscribe == nil {
+		writeError(w, http.StatusNotImplemented, "billing not configured")
+		return
+	}
+	var req usecases.SubscribeTenantRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubscriptionBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.TenantID = r.PathValue("tenant")
+
+	response, err := h.subscribe.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, billingErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// Get GET /tenants/{tenant}/subscription : offre, statut et fonctionnalités incluses
+func (h *BillingHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.get.Execute(r.Context(), usecases.GetBillingAccountRequest{TenantID: r.PathValue("tenant")})
+	if err != nil {
+		writeUseCaseError(w, billingErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func billingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrBillingAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecases.ErrAlreadySubscribed):
+		return http.StatusConflict
+	case errors.Is(err, usecases.ErrUnknownPlan):
+		return http.StatusBadRequest
+	}
+	var useCaseErr *usecases.Error
+	if errors.As(err, &useCaseErr) {
+		// Fournisseur de facturation ou stockage indisponible
+		return http.StatusBadGateway
+	}
+	return http.StatusBadRequest
+}
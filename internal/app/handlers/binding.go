@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// BINDING DES PARAMÈTRES DE CHEMIN ET DE REQUÊTE
+// =============================================================================
+
+// maxPageSize taille de page maximale des listes paginées
+const maxPageSize = 100
+
+// invalidParameterCode code des erreurs 400 de binding, quel que soit le paramètre
+const invalidParameterCode = "invalid_parameter"
+
+// bindingError premier paramètre invalide rencontré
+type bindingError struct {
+	parameter string
+	message   string
+}
+
+// requestBinder lit et valide les paramètres d'une requête vers les champs d'une requête de use case.
+// La première erreur est conservée, les lectures suivantes sont ignorées, et Valid répond
+// 400 {"error": "invalid page", "code": "invalid_parameter", "parameter": "page"} :
+//
+//	b := bindRequest(r)
+//	req := usecases.ListNotificationsRequest{UserID: b.PathID("id", "user id")}
+//	b.Pagination(&req.Page, &req.PageSize)
+//	if !b.Valid(w) {
+//		return
+//	}
+//
+// Un paramètre de requête absent laisse le champ à sa valeur initiale (valeur par défaut)
+type requestBinder struct {
+	r     *http.Request
+	query url.Values
+	err   *bindingError
+}
+
+func bindRequest(r *http.Request) *requestBinder {
+	return &requestBinder{r: r, query: r.URL.Query()}
+}
+
+// bindPathID raccourci des handlers qui ne lisent que l'identifiant du chemin
+func bindPathID(w http.ResponseWriter, r *http.Request, name, label string) (int, bool) {
+	b := bindRequest(r)
+	id := b.PathID(name, label)
+	return id, b.Valid(w)
+}
+
+// Valid répond 400 si un paramètre est invalide ; à appeler avant d'exécuter le use case
+func (b *requestBinder) Valid(w http.ResponseWriter) bool {
+	if b.err == nil {
+		return true
+	}
+	writeJSON(w, http.StatusBadRequest, ErrorResponse{
+		Error:     b.err.message,
+		Code:      invalidParameterCode,
+		Parameter: b.err.parameter,
+	})
+	return false
+}
+
+func (b *requestBinder) fail(parameter, message string) {
+	if b.err == nil {
+		b.err = &bindingError{parameter: parameter, message: message}
+	}
+}
+
+// PathID identifiant entier strictement positif du segment {name} ; label nomme la ressource
+// dans le message d'erreur ("user id" → "invalid user id")
+func (b *requestBinder) PathID(name, label string) int {
+	id, err := strconv.Atoi(b.r.PathValue(name))
+	if err != nil || id < 1 {
+		b.fail(name, "invalid "+label)
+		return 0
+	}
+	return id
+}
+
+// QueryString valeur brute du paramètre, si présent
+func (b *requestBinder) QueryString(name string, dst *string) {
+	if b.query.Has(name) {
+		*dst = b.query.Get(name)
+	}
+}
+
+// QueryInt entier compris entre min et max (max 0 : pas de borne haute)
+func (b *requestBinder) QueryInt(name string, dst *int, min, max int) {
+	raw := b.query.Get(name)
+	if raw == "" {
+		return
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || (max > 0 && value > max) {
+		b.fail(name, "invalid "+name)
+		return
+	}
+	*dst = value
+}
+
+// QueryBool booléen au format de strconv.ParseBool (true, false, 1, 0...)
+func (b *requestBinder) QueryBool(name string, dst *bool) {
+	raw := b.query.Get(name)
+	if raw == "" {
+		return
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		b.fail(name, "invalid "+name)
+		return
+	}
+	*dst = value
+}
+
+// QueryPrefixed filtres "<prefix><clé>=valeur" (une seule valeur chacun), indexés par clé
+func (b *requestBinder) QueryPrefixed(prefix string, dst *map[string]string) {
+	for param, values := range b.query {
+		key, found := strings.CutPrefix(param, prefix)
+		if !found {
+			continue
+		}
+		if key == "" || len(values) != 1 {
+			b.fail(param, "invalid attribute filter "+param)
+			return
+		}
+		if *dst == nil {
+			*dst = make(map[string]string)
+		}
+		(*dst)[key] = values[0]
+	}
+}
+
+// Pagination ?page= (à partir de 1) et ?page_size= (1 à maxPageSize)
+func (b *requestBinder) Pagination(page, pageSize *int) {
+	b.QueryInt("page", page, 1, 0)
+	b.QueryInt("page_size", pageSize, 1, maxPageSize)
+}
+
+// DateRange bornes RFC 3339 facultatives ; quand les deux sont fournies, from précède to
+// Les valeurs sont conservées telles quelles (les requêtes de use case portent des chaînes)
+func (b *requestBinder) DateRange(fromName, toName string, from, to *string) {
+	var start, end time.Time
+	if raw := b.query.Get(fromName); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			b.fail(fromName, "invalid "+fromName+" (RFC 3339 expected)")
+			return
+		}
+		start, *from = parsed, raw
+	}
+	if raw := b.query.Get(toName); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			b.fail(toName, "invalid "+toName+" (RFC 3339 expected)")
+			return
+		}
+		end, *to = parsed, raw
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		b.fail(toName, "invalid date range: "+toName+" must be after "+fromName)
+	}
+}
+
+// QueryEnum valeur parmi allowed
+func (b *requestBinder) QueryEnum(name string, allowed []string, dst *string) {
+	raw := b.query.Get(name)
+	if raw == "" {
+		return
+	}
+	if !slices.Contains(allowed, raw) {
+		b.fail(name, "invalid "+name+" (expected one of "+strings.Join(allowed, ", ")+")")
+		return
+	}
+	*dst = raw
+}
+
+// UserSort ?sort_by= (critères indexés du modèle de lecture) et ?order=asc|desc
+func (b *requestBinder) UserSort(sortBy, order *string) {
+	fields := make([]string, len(repositories.UserSortFields))
+	for i, field := range repositories.UserSortFields {
+		fields[i] = string(field)
+	}
+	b.QueryEnum("sort_by", fields, sortBy)
+	b.QueryEnum("order", []string{usecases.SortAscending, usecases.SortDescending}, order)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	_ "embed"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// INGESTION PUBLIQUE : ÉVÉNEMENTS AUTHENTIFIÉS PAR CLÉ D'ÉCRITURE
+// =============================================================================
+
+// collectPathPrefix routes de l'ingestion publique : CORS ouvert à toute origine, hors CSRF
+const collectPathPrefix = "/collect/"
+
+//go:embed assets/collect.js
+var collectSnippet []byte
+
+// WriteKeyVerifier vérifie la clé présentée par un client de l'ingestion publique
+// (usecases.WriteKeyAuthenticator)
+type WriteKeyVerifier interface {
+	Authenticate(ctx context.Context, secret string) (*entities.WriteKey, error)
+}
+
+// CollectLimits débit accepté par clé d'écriture (seau à jetons) ; Rate 0 = illimité
+type CollectLimits struct {
+	// Rate requêtes par seconde, en régime établi
+	Rate float64
+	// Burst requêtes acceptées d'un coup après une période calme
+	Burst int
+}
+
+// CollectHandler sert /collect/... aux sites et applications publiés : la clé d'écriture du tenant
+// (X-Write-Key, identifiant Basic comme les SDK Segment, ou paramètre write_key pour sendBeacon)
+// remplace le jeton utilisateur. Les événements sont ceux du tenant, sans utilisateur (anonymes),
+// et ne passent jamais par les jetons ni les cookies de l'API
+//   - POST /collect/track : même format que /analytics/track
+//   - POST /collect/v1/track, /page, /identify, /batch, /import : API HTTP Segment (hôte = /collect)
+//   - GET /collect/snippet.js : traceur JavaScript, sans clé
+type CollectHandler struct {
+	keys    WriteKeyVerifier
+	limiter *writeKeyLimiter
+	clock   usecases.Clock
+	mux     *http.ServeMux
+}
+
+func NewCollectHandler(
+	keys WriteKeyVerifier,
+	track usecases.UseCase[usecases.TrackEventRequest, *usecases.TrackEventResponse],
+	limits CollectLimits,
+	clock usecases.Clock,
+) *CollectHandler {
+	h := &CollectHandler{keys: keys, limiter: newWriteKeyLimiter(limits), clock: clock, mux: http.NewServeMux()}
+	segment := NewSegmentHandler(track, clock)
+	h.mux.HandleFunc("POST /collect/track", func(w http.ResponseWriter, r *http.Request) {
+		serveTrack(w, r, track)
+	})
+	h.mux.HandleFunc("POST /collect/v1/track", segment.Track)
+	h.mux.HandleFunc("POST /collect/v1/page", segment.Page)
+	h.mux.HandleFunc("POST /collect/v1/identify", segment.Identify)
+	h.mux.HandleFunc("POST /collect/v1/batch", segment.Batch)
+	h.mux.HandleFunc("POST /collect/v1/import", segment.Batch)
+	return h
+}
+
+// collectAllowedHeaders en-têtes acceptés au pré-vol : Authorization pour l'identifiant Basic des SDK Segment
+const collectAllowedHeaders = "Content-Type, Authorization, X-Write-Key"
+
+// ServeHTTP CORS ouvert à toute origine, sans credentials : la clé voyage dans la requête, jamais
+// dans un cookie (le middleware CORS global ne traite pas ces routes)
+func (h *CollectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Origin") != "" {
+		header := w.Header()
+		header.Set("Access-Control-Allow-Origin", "*")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", "GET, POST")
+			header.Set("Access-Control-Allow-Headers", collectAllowedHeaders)
+			header.Set("Access-Control-Max-Age", "86400") // 24 h
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	h.serve(w, r)
+}
+
+func (h *CollectHandler) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == collectPathPrefix+"snippet.js" && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		_, _ = w.Write(collectSnippet)
+		return
+	}
+	h.withWriteKey(w, r, h.mux)
+}
+
+// RequireWriteKey next servi au nom du tenant de la clé d'écriture présentée, avec le même débit
+// par clé que /collect/ : routes Segment à la racine de l'API (POST /v1/track, ...)
+func (h *CollectHandler) RequireWriteKey(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.withWriteKey(w, r, next)
+	})
+}
+
+// withWriteKey authentifie la clé, applique le débit et remplace l'acteur par le tenant de la clé
+func (h *CollectHandler) withWriteKey(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	key, err := h.keys.Authenticate(r.Context(), writeKeyFromRequest(r))
+	if errors.Is(err, usecases.ErrInvalidWriteKey) {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if allowed, wait := h.limiter.allow(key.ID, h.clock.Now()); !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		writeJSON(w, http.StatusTooManyRequests, ErrorResponse{Error: "write key rate limit exceeded", Code: "rate_limited"})
+		return
+	}
+
+	// L'acteur du jeton Bearer éventuel est remplacé : l'ingestion publique n'agit qu'au nom du tenant
+	actor := usecases.Actor{TenantID: key.TenantID}
+	noteAccessLogActor(r.Context(), actor)
+	next.ServeHTTP(w, r.WithContext(usecases.ContextWithActor(r.Context(), actor)))
+}
+
+// writeKeyFromRequest en-tête X-Write-Key, identifiant Basic (mot de passe vide), puis paramètre write_key
+func writeKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-Write-Key"); key != "" {
+		return key
+	}
+	if key, _, ok := r.BasicAuth(); ok && key != "" {
+		return key
+	}
+	return r.URL.Query().Get("write_key")
+}
+
+// isCollectPath l'ingestion publique applique sa propre politique CORS et n'utilise pas la session
+func isCollectPath(path string) bool {
+	return strings.HasPrefix(path, collectPathPrefix)
+}
+
+// writeKeyLimiter seau à jetons par clé d'écriture, en mémoire (par instance). Les seaux ne sont
+// jamais purgés : leur nombre est borné par celui des clés
+type writeKeyLimiter struct {
+	rate  float64
+	burst float64
+
+	mutex   sync.Mutex
+	buckets map[int]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func newWriteKeyLimiter(limits CollectLimits) *writeKeyLimiter {
+	return &writeKeyLimiter{rate: limits.Rate, burst: float64(max(limits.Burst, 1)), buckets: make(map[int]*tokenBucket)}
+}
+
+// allow consomme un jeton du seau de la clé ; sinon, délai avant le prochain jeton
+func (l *writeKeyLimiter) allow(keyID int, now time.Time) (bool, time.Duration) {
+	if l.rate <= 0 {
+		return true, 0
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	bucket, ok := l.buckets[keyID]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[keyID] = bucket
+	}
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens = min(l.burst, bucket.tokens+elapsed.Seconds()*l.rate)
+		bucket.updated = now
+	}
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func basicTestHeader(credentials string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+}
+
+// Un identifiant Basic malformé ne donne aucune clé : la requête est refusée (401), jamais
+// rattachée à un tenant
+func TestCollectRejectsMalformedBasicHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantKey       string
+	}{
+		{"identifiant Basic des SDK", basicTestHeader(segmentTestKey + ":"), segmentTestKey},
+		{"schéma en minuscules", "basic " + base64.StdEncoding.EncodeToString([]byte(segmentTestKey+":")), segmentTestKey},
+		{"base64 invalide", "Basic " + segmentTestKey, ""},
+		{"sans deux-points", basicTestHeader(segmentTestKey), ""},
+		{"clé en mot de passe", basicTestHeader(":" + segmentTestKey), ""},
+		{"identifiant vide", basicTestHeader(":"), ""},
+		{"schéma seul", "Basic", ""},
+		{"clé en jeton Bearer", "Bearer " + segmentTestKey, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/collect/v1/track", strings.NewReader(`{"event":"Order Completed"}`))
+			r.Header.Set("Authorization", tt.authorization)
+			if key := writeKeyFromRequest(r); key != tt.wantKey {
+				t.Fatalf("clé %q, attendu %q", key, tt.wantKey)
+			}
+
+			var actors []usecases.Actor
+			var requests []usecases.TrackEventRequest
+			w := httptest.NewRecorder()
+			segmentTestRouter(&actors, &requests).ServeHTTP(w, r)
+			wantCode := http.StatusUnauthorized
+			if tt.wantKey != "" {
+				wantCode = http.StatusOK
+			}
+			if w.Code != wantCode {
+				t.Fatalf("statut %d, attendu %d : %s", w.Code, wantCode, w.Body)
+			}
+			if wantCode != http.StatusOK && len(actors) != 0 {
+				t.Fatal("use case appelé sans clé valide")
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// COMPRESSION DES RÉPONSES
+// =============================================================================
+
+// EncodingWriter flux compressé : Flush pousse les données en attente (flux longs)
+type EncodingWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// Encoding algorithme de Content-Encoding proposé par Compress
+type Encoding struct {
+	Name      string
+	NewWriter func(w io.Writer) EncodingWriter
+}
+
+// GzipEncoding compression gzip de la bibliothèque standard (niveau par défaut)
+var GzipEncoding = Encoding{
+	Name:      "gzip",
+	NewWriter: func(w io.Writer) EncodingWriter { return gzip.NewWriter(w) },
+}
+
+// zstdWindowSize fenêtre maximale qu'un client HTTP doit accepter (RFC 8878, section 7.2) :
+// au-delà, les navigateurs refusent la réponse
+const zstdWindowSize = 8 << 20
+
+// ZstdEncoding compression zstd (klauspost/compress), plus rapide que gzip à taux égal ;
+// un encodeur mono-goroutine par réponse, comme gzip
+var ZstdEncoding = Encoding{
+	Name: "zstd",
+	NewWriter: func(w io.Writer) EncodingWriter {
+		encoder, err := zstd.NewWriter(w,
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithWindowSize(zstdWindowSize),
+			zstd.WithLowerEncoderMem(true),
+		)
+		if err != nil {
+			panic(err) // Options fixes : ne peut échouer
+		}
+		return encoder
+	},
+}
+
+// Compress compresse les réponses dont le corps atteint minSize octets, avec le premier
+// encodage de la liste accepté par le client (Accept-Encoding, q-values respectées)
+// Ne sont jamais compressés : les types déjà compressés ou en flux (SSE), les 204/304,
+// les réponses qui ont déjà un Content-Encoding, et les connexions détournées (WebSocket)
+func Compress(next http.Handler, minSize int, encodings ...Encoding) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding, ok := negotiateEncoding(r.Header.Get("Accept-Encoding"), encodings)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, status: http.StatusOK}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding retient le premier encodage du serveur dont la q-value client est > 0
+func negotiateEncoding(header string, encodings []Encoding) (Encoding, bool) {
+	if header == "" {
+		return Encoding{}, false
+	}
+	accepted := parseQualities(header)
+	for _, encoding := range encodings {
+		q, listed := accepted[encoding.Name]
+		if !listed {
+			q, listed = accepted["*"]
+		}
+		if listed && q > 0 {
+			return encoding, true
+		}
+	}
+	return Encoding{}, false
+}
+
+// parseQualities lit "gzip;q=0.8, br, *;q=0" en map valeur -> q (1 par défaut)
+func parseQualities(header string) map[string]float64 {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if key, raw, found := strings.Cut(strings.TrimSpace(param), "="); found && strings.TrimSpace(key) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		qualities[value] = q
+	}
+	return qualities
+}
+
+// compressWriter retient le début du corps jusqu'à minSize pour décider de compresser ou non
+type compressWriter struct {
+	http.ResponseWriter
+	encoding Encoding
+	minSize  int
+
+	status      int
+	wroteHeader bool // WriteHeader appelé par le handler (pas encore transmis)
+	decided     bool
+	compressed  EncodingWriter // nil = transmission telle quelle
+	buffer      []byte
+	hijacked    bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.decided || cw.wroteHeader {
+		return
+	}
+	if status < 200 {
+		cw.ResponseWriter.WriteHeader(status) // Réponse informative (103 Early Hints...)
+		return
+	}
+	cw.status = status
+	cw.wroteHeader = true
+
+	// Sans corps ou en flux : inutile d'attendre
+	if status == http.StatusNoContent || status == http.StatusNotModified || !compressible(cw.Header()) {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		cw.buffer = append(cw.buffer, p...)
+		if len(cw.buffer) < cw.minSize {
+			return len(p), nil
+		}
+		if err := cw.decideWithBuffer(compressible(cw.Header())); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.compressed != nil {
+		return cw.compressed.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush est appelé par les flux (SSE) : la réponse part telle quelle si rien n'est décidé
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		_ = cw.decideWithBuffer(false)
+	}
+	if cw.compressed != nil {
+		_ = cw.compressed.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack unsupported")
+	}
+	cw.hijacked = true
+	return hijacker.Hijack()
+}
+
+// Unwrap permet à http.ResponseController d'atteindre le writer d'origine
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+	header := cw.Header()
+	if compress && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", cw.encoding.Name)
+		header.Del("Content-Length")
+		cw.compressed = cw.encoding.NewWriter(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+func (cw *compressWriter) decideWithBuffer(compress bool) error {
+	cw.decide(compress)
+	buffered := cw.buffer
+	cw.buffer = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	_, err := cw.Write(buffered)
+	return err
+}
+
+// close termine la réponse : petit corps transmis tel quel, flux compressé finalisé
+func (cw *compressWriter) close() {
+	if cw.hijacked {
+		return
+	}
+	if !cw.decided {
+		if !cw.wroteHeader && len(cw.buffer) == 0 {
+			return // Le handler n'a rien écrit : net/http enverra son 200 vide
+		}
+		_ = cw.decideWithBuffer(false)
+	}
+	if cw.compressed != nil {
+		_ = cw.compressed.Close()
+	}
+}
+
+// compressible vrai pour les types textuels (JSON, MessagePack, texte) hors flux SSE
+func compressible(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		mediaType == contentTypeMessagePack,
+		strings.HasSuffix(mediaType, "+json"):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func compressionTestHandler(body string) http.Handler {
+	return Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}), 64, ZstdEncoding, GzipEncoding)
+}
+
+// decodeTestBody corps décompressé selon le Content-Encoding de la réponse
+func decodeTestBody(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var reader io.Reader = rec.Body
+	switch rec.Header().Get("Content-Encoding") {
+	case "zstd":
+		decoder, err := zstd.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer decoder.Close()
+		reader = decoder
+	case "gzip":
+		decoder, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = decoder
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(decoded)
+}
+
+func TestCompressNegotiatesEncoding(t *testing.T) {
+	body := `{"items":[` + strings.Repeat(`{"name":"alice"},`, 50) + `{}]}`
+
+	tests := []struct {
+		acceptEncoding string
+		body           string
+		want           string
+	}{
+		{"zstd, gzip", body, "zstd"},
+		{"gzip, zstd", body, "zstd"},
+		{"gzip", body, "gzip"},
+		{"zstd", body, "zstd"},
+		{"zstd;q=0, gzip", body, "gzip"},
+		{"*", body, "zstd"},
+		{"*;q=0, gzip", body, "gzip"},
+		{"br", body, ""},
+		{"", body, ""},
+		{"zstd", `{"small":true}`, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+		rec := httptest.NewRecorder()
+		compressionTestHandler(tt.body).ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+			t.Fatalf("%q : Content-Encoding %q, attendu %q", tt.acceptEncoding, got, tt.want)
+		}
+		if got := decodeTestBody(t, rec); got != tt.body {
+			t.Fatalf("%q : corps %q", tt.acceptEncoding, got)
+		}
+	}
+}
+
+// Flush pousse les données compressées déjà écrites : le client les décode sans attendre la fin
+func TestCompressZstdFlush(t *testing.T) {
+	chunk := `{"chunk":"` + strings.Repeat("a", 100) + `"}`
+	rec := httptest.NewRecorder()
+	var flushed []byte
+	handler := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, chunk)
+		w.(http.Flusher).Flush()
+		flushed = bytes.Clone(rec.Body.Bytes())
+		_, _ = io.WriteString(w, chunk)
+	}), 64, ZstdEncoding)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	req.Header.Set("Accept-Encoding", "zstd")
+	handler.ServeHTTP(rec, req)
+
+	decoder, err := zstd.NewReader(bytes.NewReader(flushed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer decoder.Close()
+	partial := make([]byte, len(chunk))
+	if _, err := io.ReadFull(decoder, partial); err != nil || string(partial) != chunk {
+		t.Fatalf("données poussées par Flush : %q, %v", partial, err)
+	}
+	if got := decodeTestBody(t, rec); got != chunk+chunk {
+		t.Fatalf("corps %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ConsentHandler consentement à la mesure d'audience et historique de conformité
+type ConsentHandler struct {
+	get    usecases.UseCase[int, *usecases.AnalyticsConsentResponse]
+	record usecases.UseCase[usecases.RecordAnalyticsConsentRequest, *usecases.AnalyticsConsentResponse]
+	list   usecases.UseCase[usecases.ListConsentChangesRequest, *usecases.ListConsentChangesResponse]
+}
+
+func NewConsentHandler(
+	get usecases.UseCase[int, *usecases.AnalyticsConsentResponse],
+	record usecases.UseCase[usecases.RecordAnalyticsConsentRequest, *usecases.AnalyticsConsentResponse],
+	list usecases.UseCase[usecases.ListConsentChangesRequest, *usecases.ListConsentChangesResponse],
+) *ConsentHandler {
+	return &ConsentHandler{get: get, record: record, list: list}
+}
+
+// Get GET /users/{id}/consent
+func (h *ConsentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	response, err := h.get.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Record PUT /users/{id}/consent {"status": "denied", "source": "banner"}
+func (h *ConsentHandler) Record(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.RecordAnalyticsConsentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.UserID = id
+
+	response, err := h.record.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, recordConsentError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func recordConsentError(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrInvalidConsentStatus), errors.Is(err, entities.ErrInvalidConsentSource):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// List GET /admin/consent-changes?user_id=&status=&from=&to=&cursor=&limit=
+// Changements les plus récents d'abord ; next_cursor est absent sur la dernière page
+func (h *ConsentHandler) List(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ListConsentChangesRequest
+	b := bindRequest(r)
+	b.QueryInt("user_id", &req.UserID, 1, 0)
+	b.QueryEnum("status", []string{entities.ConsentGranted, entities.ConsentDenied}, &req.Status)
+	b.DateRange("from", "to", &req.From, &req.To)
+	b.QueryString("cursor", &req.Cursor)
+	b.QueryInt("limit", &req.Limit, 1, 500)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.list.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
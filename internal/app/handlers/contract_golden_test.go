@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// CONTRAT DE L'API : une réponse golden par succès et par variante d'erreur
+// =============================================================================
+
+// Les use cases sont remplacés par des fonctions déterministes (horodatages fixes) : seule la
+// couche HTTP (routage, binding, DTO, statuts et codes d'erreur) est figée par ces fichiers
+
+var contractTime = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+// Identifiants réservés aux variantes d'erreur des use cases
+const (
+	contractUserID      = 42
+	contractMissingID   = 404
+	contractForbiddenID = 403
+	contractTimeoutID   = 504
+	contractPanicID     = 500
+)
+
+func contractUser(id int) *usecases.GetUserResponse {
+	return &usecases.GetUserResponse{
+		ID:      id,
+		Email:   "alice.martin@example.com",
+		Name:    "Alice Martin",
+		Created: contractTime,
+		Updated: contractTime.Add(time.Hour),
+		Status:  string(entities.UserStatusActive),
+		Handle:  "alice",
+	}
+}
+
+// contractUserError erreur de use case associée aux identifiants réservés
+func contractUserError(id int) error {
+	switch id {
+	case contractMissingID:
+		return repositories.ErrUserNotFound
+	case contractForbiddenID:
+		return &usecases.Error{Message: "accès refusé", Cause: usecases.ErrForbidden}
+	case contractTimeoutID:
+		return &usecases.Error{Message: "délai dépassé", Cause: usecases.ErrTimeout}
+	case contractPanicID:
+		panic("contract: panique simulée")
+	}
+	return nil
+}
+
+func contractRouter() http.Handler {
+	createUser := usecases.UseCaseFunc[usecases.CreateUserRequest, *usecases.CreateUserResponse](
+		func(ctx context.Context, req usecases.CreateUserRequest) (*usecases.CreateUserResponse, error) {
+			switch req.Email {
+			case "taken@example.com":
+				return nil, errors.New("un utilisateur avec cet email existe déjà")
+			case "quota@example.com":
+				return nil, &usecases.Error{Message: "quota users atteint pour le tenant acme (3/3)", Cause: usecases.ErrQuotaExceeded}
+			}
+			user, err := entities.NewUser(req.Email, req.Name, req.Password, contractTime)
+			if err != nil {
+				return nil, err
+			}
+			return &usecases.CreateUserResponse{ID: contractUserID, Email: user.Email, Name: user.Name, Created: contractTime}, nil
+		})
+	getUser := usecases.UseCaseFunc[int, *usecases.GetUserResponse](
+		func(ctx context.Context, id int) (*usecases.GetUserResponse, error) {
+			if err := contractUserError(id); err != nil {
+				return nil, err
+			}
+			return contractUser(id), nil
+		})
+	updateUser := usecases.UseCaseFunc[usecases.UpdateUserRequest, *usecases.UpdateUserResponse](
+		func(ctx context.Context, req usecases.UpdateUserRequest) (*usecases.UpdateUserResponse, error) {
+			if err := contractUserError(req.ID); err != nil {
+				return nil, err
+			}
+			current := contractUser(req.ID)
+			if req.ExpectedUpdated != nil && !req.ExpectedUpdated.Equal(current.Updated) {
+				return nil, repositories.ErrUserModified
+			}
+			return &usecases.UpdateUserResponse{
+				ID:      req.ID,
+				Email:   req.Email,
+				Name:    req.Name,
+				Updated: contractTime.Add(2 * time.Hour),
+				Changes: entities.DiffProfile(current.Name, current.Email, req.Name, req.Email),
+			}, nil
+		})
+	patchUser := usecases.UseCaseFunc[usecases.PatchUserRequest, *usecases.UpdateUserResponse](
+		func(ctx context.Context, req usecases.PatchUserRequest) (*usecases.UpdateUserResponse, error) {
+			current := contractUser(req.ID)
+			name := current.Name
+			if req.Name != nil {
+				name = *req.Name
+			}
+			return &usecases.UpdateUserResponse{
+				ID:         req.ID,
+				Email:      current.Email,
+				Name:       name,
+				Updated:    contractTime.Add(2 * time.Hour),
+				Attributes: req.Attributes,
+				Changes:    entities.DiffProfile(current.Name, current.Email, name, current.Email),
+			}, nil
+		})
+	deleteUser := usecases.UseCaseFunc[int, struct{}](
+		func(ctx context.Context, id int) (struct{}, error) {
+			return struct{}{}, contractUserError(id)
+		})
+	listUsers := usecases.UseCaseFunc[usecases.ListUsersRequest, *usecases.ListUsersResponse](
+		func(ctx context.Context, req usecases.ListUsersRequest) (*usecases.ListUsersResponse, error) {
+			if req.Status == "unknown" {
+				return nil, errors.New("statut invalide (active, deactivated ou banned)")
+			}
+			return &usecases.ListUsersResponse{
+				Users:      []*usecases.GetUserResponse{contractUser(contractUserID)},
+				Total:      21,
+				Page:       max(req.Page, 1),
+				PageSize:   1,
+				TotalPages: 21,
+				SortBy:     "created",
+				Order:      usecases.SortAscending,
+			}, nil
+		})
+	countUsers := usecases.UseCaseFunc[usecases.CountUsersRequest, *usecases.CountUsersResponse](
+		func(ctx context.Context, req usecases.CountUsersRequest) (*usecases.CountUsersResponse, error) {
+			return &usecases.CountUsersResponse{Total: 21}, nil
+		})
+	login := usecases.UseCaseFunc[usecases.LoginRequest, *usecases.LoginResponse](
+		func(ctx context.Context, req usecases.LoginRequest) (*usecases.LoginResponse, error) {
+			switch {
+			case req.Email == "deactivated@example.com":
+				return nil, usecases.ErrAccountDeactivated
+			case req.Password != "Secret123!":
+				return nil, usecases.ErrInvalidCredentials
+			}
+			return &usecases.LoginResponse{
+				AccessToken: "eyJhbGciOiJIUzI1NiJ9.contract.signature",
+				TokenType:   "Bearer",
+				ExpiresAt:   contractTime.Add(time.Hour),
+				UserID:      contractUserID,
+			}, nil
+		})
+	track := usecases.UseCaseFunc[usecases.TrackEventRequest, *usecases.TrackEventResponse](
+		func(ctx context.Context, req usecases.TrackEventRequest) (*usecases.TrackEventResponse, error) {
+			if err := req.Validate(); err != nil {
+				return nil, err
+			}
+			if req.Event == "over.quota" {
+				return nil, &usecases.Error{Message: "quota events atteint pour le tenant acme (100/100)", Cause: usecases.ErrQuotaExceeded}
+			}
+			return &usecases.TrackEventResponse{Status: "accepted"}, nil
+		})
+
+	router := NewRouter(Handlers{
+		User:      NewUserHandler(createUser, getUser, updateUser, patchUser, deleteUser, listUsers, countUsers, nil),
+		UserV2:    NewUserV2Handler(createUser, getUser, updateUser, deleteUser, listUsers),
+		Auth:      NewAuthHandler(login, nil, nil, SessionCookieOptions{}),
+		Analytics: NewAnalyticsHandler(nil, nil, nil, track, nil),
+		Realtime:  http.NotFoundHandler(),
+	})
+	return WithRequestID(Recover(router, discardReporter{}))
+}
+
+// discardReporter les paniques simulées sont attendues
+type discardReporter struct{}
+
+func (discardReporter) Report(context.Context, error, []byte, map[string]interface{}) {}
+
+var contractCases = []struct {
+	name   string
+	method string
+	target string
+	body   string
+	header map[string]string
+}{
+	{name: "users_create", method: "POST", target: "/v1/users", body: `{"email":" Alice.Martin@Example.com ","name":"Alice Martin","password":"Secret123!"}`},
+	{name: "users_create_invalid_json", method: "POST", target: "/v1/users", body: `{"email":`},
+	{name: "users_create_invalid_email", method: "POST", target: "/v1/users", body: `{"email":"alice","name":"Alice Martin","password":"Secret123!"}`},
+	{name: "users_create_email_taken", method: "POST", target: "/v1/users", body: `{"email":"taken@example.com","name":"Alice Martin","password":"Secret123!"}`},
+	{name: "users_create_quota_exceeded", method: "POST", target: "/v1/users", body: `{"email":"quota@example.com","name":"Alice Martin","password":"Secret123!"}`},
+
+	{name: "users_get", method: "GET", target: "/v1/users/42"},
+	{name: "users_get_unprefixed", method: "GET", target: "/users/42"},
+	{name: "users_get_not_modified", method: "GET", target: "/v1/users/42", header: map[string]string{"If-None-Match": userETag(contractUserID, contractTime.Add(time.Hour))}},
+	{name: "users_get_invalid_id", method: "GET", target: "/v1/users/abc"},
+	{name: "users_get_not_found", method: "GET", target: "/v1/users/404"},
+	{name: "users_get_forbidden", method: "GET", target: "/v1/users/403"},
+	{name: "users_get_timeout", method: "GET", target: "/v1/users/504"},
+	{name: "users_get_internal_error", method: "GET", target: "/v1/users/500", header: map[string]string{"X-Request-ID": "contract-request-id"}},
+
+	{name: "users_list", method: "GET", target: "/v1/users?page=2&page_size=1"},
+	{name: "users_list_invalid_page_size", method: "GET", target: "/v1/users?page_size=1000"},
+	{name: "users_list_invalid_status", method: "GET", target: "/v1/users?status=unknown"},
+	{name: "users_count", method: "HEAD", target: "/v1/users"},
+
+	{name: "users_update", method: "PUT", target: "/v1/users/42", body: `{"email":"alice.martin@example.com","name":"Alice Dupont"}`},
+	{name: "users_update_precondition_failed", method: "PUT", target: "/v1/users/42", body: `{"email":"alice.martin@example.com","name":"Alice Dupont"}`, header: map[string]string{"If-Match": `W/"42-stale"`}},
+	{name: "users_patch", method: "PATCH", target: "/v1/users/42", body: `{"name":"Alice Dupont","attributes":{"plan":"pro"}}`},
+	{name: "users_patch_remove_required", method: "PATCH", target: "/v1/users/42", body: `{"name":null}`},
+	{name: "users_patch_unknown_field", method: "PATCH", target: "/v1/users/42", body: `{"nickname":"ali"}`},
+	{name: "users_delete", method: "DELETE", target: "/v1/users/42"},
+	{name: "users_delete_not_found", method: "DELETE", target: "/v1/users/404"},
+	{name: "users_method_not_allowed", method: "PATCH", target: "/v1/users"},
+
+	{name: "users_v2_get", method: "GET", target: "/v2/users/" + formatUserUUID(contractUserID)},
+	{name: "users_v2_get_invalid_id", method: "GET", target: "/v2/users/42"},
+	{name: "users_v2_list", method: "GET", target: "/v2/users?page_size=1"},
+
+	{name: "auth_login", method: "POST", target: "/v1/auth/login", body: `{"email":"alice.martin@example.com","password":"Secret123!"}`},
+	{name: "auth_login_invalid_credentials", method: "POST", target: "/v1/auth/login", body: `{"email":"alice.martin@example.com","password":"wrong"}`},
+	{name: "auth_login_account_deactivated", method: "POST", target: "/v1/auth/login", body: `{"email":"deactivated@example.com","password":"Secret123!"}`},
+
+	{name: "analytics_track", method: "POST", target: "/v1/analytics/track", body: `{"event":"checkout.completed","properties":{"amount":49.9}}`},
+	{name: "analytics_track_invalid_event", method: "POST", target: "/v1/analytics/track", body: `{"event":"Checkout Completed"}`},
+	{name: "analytics_track_quota_exceeded", method: "POST", target: "/v1/analytics/track", body: `{"event":"over.quota"}`},
+}
+
+func TestAPIContract(t *testing.T) {
+	router := contractRouter()
+	for _, tc := range contractCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			for name, value := range tc.header {
+				req.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assertGolden(t, tc.name, recorder)
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	got := lineDiff("HTTP 200\n\n{\n  \"id\": 1\n}\n", "HTTP 200\n\n{\n  \"id\": 1,\n  \"name\": \"a\"\n}\n")
+	want := "  HTTP 200\n  \n  {\n-   \"id\": 1\n+   \"id\": 1,\n+   \"name\": \"a\"\n  }\n"
+	if got != want {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+}
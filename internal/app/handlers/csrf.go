@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// =============================================================================
+// PROTECTION CSRF (authentification par cookie de session)
+// =============================================================================
+
+// En-tête et cookie du jeton CSRF : le JavaScript du client lit le cookie et renvoie
+// sa valeur dans l'en-tête, ce qu'un site tiers ne peut pas faire
+const (
+	csrfCookieName = "csrf_token"
+	csrfHeaderName = "X-CSRF-Token"
+)
+
+// CSRFOptions configuration du double-submit signé
+type CSRFOptions struct {
+	// SessionCookie nom du cookie de session : seules les requêtes qui le portent sont concernées
+	SessionCookie string
+	// Secret clé HMAC liant le jeton à la session (un jeton volé ne sert pas pour une autre session)
+	Secret string
+	// Secure pose le cookie du jeton avec l'attribut Secure (HTTPS)
+	Secure bool
+}
+
+// CSRF applique le double-submit cookie signé aux requêtes authentifiées par cookie :
+// - méthodes sûres (GET, HEAD, OPTIONS) : émet le cookie csrf_token s'il manque ou ne correspond plus
+// - mutations : exige X-CSRF-Token égal au cookie et signé pour la session courante, sinon 403
+// Les clients authentifiés par jeton (Authorization: Bearer) ou sans cookie de session sont exemptés :
+// le navigateur n'ajoute jamais ces identifiants de lui-même. L'ACS SAML l'est aussi (voir isSAMLAssertionConsumer),
+// comme l'ingestion publique, authentifiée par clé d'écriture (isCollectPath)
+func CSRF(next http.Handler, opts CSRFOptions) http.Handler {
+	secret := []byte(opts.Secret)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := r.Cookie(opts.SessionCookie)
+		if err != nil || session.Value == "" || strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") || isSAMLAssertionConsumer(r.URL.Path) || isCollectPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var current string
+		if cookie, err := r.Cookie(csrfCookieName); err == nil {
+			current = cookie.Value
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if !validCSRFToken(secret, session.Value, current) {
+				http.SetCookie(w, &http.Cookie{
+					Name:     csrfCookieName,
+					Value:    newCSRFToken(secret, session.Value),
+					Path:     "/",
+					Secure:   opts.Secure,
+					HttpOnly: false, // lu par le JavaScript du client
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		submitted := r.Header.Get(csrfHeaderName)
+		if submitted == "" || !hmac.Equal([]byte(submitted), []byte(current)) || !validCSRFToken(secret, session.Value, submitted) {
+			writeError(w, http.StatusForbidden, "missing or invalid CSRF token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newCSRFToken "<nonce>.<HMAC(secret, session.nonce)>" en base64url
+func newCSRFToken(secret []byte, session string) string {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	encoded := base64.RawURLEncoding.EncodeToString(nonce)
+	return encoded + "." + signCSRF(secret, session, encoded)
+}
+
+func validCSRFToken(secret []byte, session, token string) bool {
+	nonce, signature, found := strings.Cut(token, ".")
+	if !found || nonce == "" {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(signCSRF(secret, session, nonce)))
+}
+
+func signCSRF(secret []byte, session, nonce string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(session + "." + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	csrfTestSecret  = "0123456789abcdef0123456789abcdef"
+	csrfTestSession = "session"
+	// csrfTestUser jeton du cookie de session, csrfTestOther celui d'une autre session
+	csrfTestUser   = "jwt-user-7"
+	csrfTestOther  = "jwt-user-8"
+	csrfTestBearer = "jwt-user-9"
+)
+
+// csrfTestVerifier jetons valides et leur acteur
+type csrfTestVerifier struct{}
+
+func (csrfTestVerifier) Verify(token string) (usecases.Actor, error) {
+	switch token {
+	case csrfTestUser:
+		return usecases.Actor{UserID: 7}, nil
+	case csrfTestOther:
+		return usecases.Actor{UserID: 8}, nil
+	case csrfTestBearer:
+		return usecases.Actor{UserID: 9}, nil
+	}
+	return usecases.Actor{}, errors.New("invalid token")
+}
+
+// csrfTestHandler CSRF puis Authenticate en mode session, comme bootstrap ; served reçoit l'acteur
+// de chaque requête arrivée jusqu'au routeur (0 si anonyme)
+func csrfTestHandler(served *[]int) http.Handler {
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, _ := usecases.ActorFromContext(r.Context())
+		*served = append(*served, actor.UserID)
+		w.WriteHeader(http.StatusOK)
+	})
+	return CSRF(Authenticate(router, csrfTestVerifier{}, csrfTestSession), CSRFOptions{
+		SessionCookie: csrfTestSession,
+		Secret:        csrfTestSecret,
+	})
+}
+
+func csrfTestRequest(method string, cookies map[string]string, header map[string]string) *http.Request {
+	req := httptest.NewRequest(method, "/v1/users/7/preferences/display", strings.NewReader(`{}`))
+	for name, value := range cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	return req
+}
+
+func TestCSRFIssuesTokenOnSafeRequest(t *testing.T) {
+	var served []int
+	rec := httptest.NewRecorder()
+	csrfTestHandler(&served).ServeHTTP(rec, csrfTestRequest(http.MethodGet, map[string]string{csrfTestSession: csrfTestUser}, nil))
+
+	if rec.Code != http.StatusOK || len(served) != 1 || served[0] != 7 {
+		t.Fatalf("code %d, acteurs %v", rec.Code, served)
+	}
+	var issued *http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == csrfCookieName {
+			issued = cookie
+		}
+	}
+	if issued == nil || issued.HttpOnly || !validCSRFToken([]byte(csrfTestSecret), csrfTestUser, issued.Value) {
+		t.Fatalf("cookie %v", issued)
+	}
+
+	// Jeton encore valide : pas de nouveau cookie
+	rec = httptest.NewRecorder()
+	csrfTestHandler(&served).ServeHTTP(rec, csrfTestRequest(http.MethodGet, map[string]string{csrfTestSession: csrfTestUser, csrfCookieName: issued.Value}, nil))
+	if cookies := rec.Result().Cookies(); len(cookies) != 0 {
+		t.Fatalf("cookies réémis : %v", cookies)
+	}
+}
+
+func TestCSRFRejectsMutations(t *testing.T) {
+	secret := []byte(csrfTestSecret)
+	token := newCSRFToken(secret, csrfTestUser)
+	otherNonce := newCSRFToken(secret, csrfTestUser)
+	otherSession := newCSRFToken(secret, csrfTestOther)
+	otherSecret := newCSRFToken([]byte(strings.Repeat("k", 32)), csrfTestUser)
+	nonce, _, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name     string
+		cookie   string
+		header   string
+		wantCode int
+	}{
+		{"jeton valide", token, token, http.StatusOK},
+		{"sans en-tête ni cookie", "", "", http.StatusForbidden},
+		{"sans en-tête", token, "", http.StatusForbidden},
+		{"sans cookie", "", token, http.StatusForbidden},
+		{"en-tête différent du cookie", token, otherNonce, http.StatusForbidden},
+		{"cookie différent de l'en-tête", otherNonce, token, http.StatusForbidden},
+		// Double-submit respecté avec un jeton volé à une autre session
+		{"jeton d'une autre session", otherSession, otherSession, http.StatusForbidden},
+		{"jeton d'une autre clé", otherSecret, otherSecret, http.StatusForbidden},
+		{"signature forgée", nonce + ".forged", nonce + ".forged", http.StatusForbidden},
+		{"sans signature", nonce, nonce, http.StatusForbidden},
+		{"sans nonce", "." + strings.SplitN(token, ".", 2)[1], "." + strings.SplitN(token, ".", 2)[1], http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookies := map[string]string{csrfTestSession: csrfTestUser}
+			if tt.cookie != "" {
+				cookies[csrfCookieName] = tt.cookie
+			}
+			header := map[string]string{}
+			if tt.header != "" {
+				header[csrfHeaderName] = tt.header
+			}
+
+			var served []int
+			rec := httptest.NewRecorder()
+			csrfTestHandler(&served).ServeHTTP(rec, csrfTestRequest(http.MethodPost, cookies, header))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code %d, attendu %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusForbidden && len(served) != 0 {
+				t.Fatalf("requête servie : %v", served)
+			}
+			if tt.wantCode == http.StatusOK && (len(served) != 1 || served[0] != 7) {
+				t.Fatalf("acteurs %v", served)
+			}
+		})
+	}
+}
+
+// Un en-tête Bearer exempte de CSRF et fixe seul l'acteur : le cookie de session, que le navigateur
+// ajoute de lui-même, n'est jamais utilisé à sa place, même si le jeton Bearer est invalide
+func TestCSRFBearerBypass(t *testing.T) {
+	session := map[string]string{csrfTestSession: csrfTestUser}
+
+	tests := []struct {
+		name      string
+		cookies   map[string]string
+		header    map[string]string
+		wantCode  int
+		wantActor int
+	}{
+		{"Bearer valide", session, map[string]string{"Authorization": "Bearer " + csrfTestBearer}, http.StatusOK, 9},
+		{"Bearer invalide", session, map[string]string{"Authorization": "Bearer forged"}, http.StatusOK, 0},
+		{"Bearer vide", session, map[string]string{"Authorization": "Bearer "}, http.StatusOK, 0},
+		{"Bearer sans cookie de session", nil, map[string]string{"Authorization": "Bearer " + csrfTestBearer}, http.StatusOK, 9},
+		{"autre schéma", session, map[string]string{"Authorization": "Basic " + csrfTestBearer}, http.StatusForbidden, 0},
+		{"bearer en minuscules", session, map[string]string{"Authorization": "bearer " + csrfTestBearer}, http.StatusForbidden, 0},
+		{"Bearer sans espace", session, map[string]string{"Authorization": "Bearer"}, http.StatusForbidden, 0},
+		{"sans cookie de session", nil, nil, http.StatusOK, 0},
+		{"cookie de session vide", map[string]string{csrfTestSession: ""}, nil, http.StatusOK, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var served []int
+			rec := httptest.NewRecorder()
+			csrfTestHandler(&served).ServeHTTP(rec, csrfTestRequest(http.MethodPost, tt.cookies, tt.header))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code %d, attendu %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && (len(served) != 1 || served[0] != tt.wantActor) {
+				t.Fatalf("acteurs %v, attendu %d", served, tt.wantActor)
+			}
+		})
+	}
+}
+
+func TestAuthenticateSessionCookie(t *testing.T) {
+	tests := []struct {
+		name          string
+		sessionCookie string
+		cookie        string
+		wantActor     int
+	}{
+		{"cookie de session", csrfTestSession, csrfTestUser, 7},
+		{"jeton invalide", csrfTestSession, "forged", 0},
+		{"mode session désactivé", "", csrfTestUser, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actor int
+			handler := Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ := usecases.ActorFromContext(r.Context())
+				actor = got.UserID
+			}), csrfTestVerifier{}, tt.sessionCookie)
+			handler.ServeHTTP(httptest.NewRecorder(), csrfTestRequest(http.MethodGet, map[string]string{csrfTestSession: tt.cookie}, nil))
+			if actor != tt.wantActor {
+				t.Fatalf("acteur %d, attendu %d", actor, tt.wantActor)
+			}
+		})
+	}
+}
+
+// Une session par cookie passe par l'acceptation des conditions comme un jeton Bearer
+func TestRequireTermsCoversSessionCookie(t *testing.T) {
+	getStatus := usecases.UseCaseFunc[int, *usecases.TermsStatusResponse](
+		func(_ context.Context, userID int) (*usecases.TermsStatusResponse, error) {
+			return &usecases.TermsStatusResponse{UserID: userID, UpToDate: false}, nil
+		})
+	handler := Authenticate(RequireTerms(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), getStatus), csrfTestVerifier{}, csrfTestSession)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, csrfTestRequest(http.MethodGet, map[string]string{csrfTestSession: csrfTestUser}, nil))
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "terms_not_accepted") {
+		t.Fatalf("code %d : %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, csrfTestRequest(http.MethodGet, nil, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("requête anonyme : code %d", rec.Code)
+	}
+}
+
+func TestLoginSetsSessionCookie(t *testing.T) {
+	expires := time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)
+	login := usecases.UseCaseFunc[usecases.LoginRequest, *usecases.LoginResponse](
+		func(context.Context, usecases.LoginRequest) (*usecases.LoginResponse, error) {
+			return &usecases.LoginResponse{AccessToken: csrfTestUser, TokenType: "Bearer", ExpiresAt: expires, UserID: 7}, nil
+		})
+
+	auth := NewAuthHandler(login, nil, nil, SessionCookieOptions{Name: csrfTestSession, Secure: true})
+	rec := httptest.NewRecorder()
+	auth.Login(rec, httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{}`)))
+	cookies := rec.Result().Cookies()
+	if rec.Code != http.StatusOK || len(cookies) != 1 {
+		t.Fatalf("code %d, cookies %v", rec.Code, cookies)
+	}
+	if c := cookies[0]; c.Name != csrfTestSession || c.Value != csrfTestUser || !c.HttpOnly || !c.Secure ||
+		c.SameSite != http.SameSiteLaxMode || c.Path != "/" || !c.Expires.Equal(expires) {
+		t.Fatalf("cookie %+v", c)
+	}
+
+	rec = httptest.NewRecorder()
+	auth.Logout(rec, httptest.NewRequest(http.MethodPost, "/auth/logout", nil))
+	cookies = rec.Result().Cookies()
+	if rec.Code != http.StatusNoContent || len(cookies) != 1 || cookies[0].Name != csrfTestSession || cookies[0].MaxAge >= 0 {
+		t.Fatalf("code %d, cookies %v", rec.Code, cookies)
+	}
+
+	// Sans SESSION_COOKIE, le jeton n'est rendu que dans la réponse
+	rec = httptest.NewRecorder()
+	NewAuthHandler(login, nil, nil, SessionCookieOptions{}).Login(rec, httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{}`)))
+	if cookies := rec.Result().Cookies(); rec.Code != http.StatusOK || len(cookies) != 0 {
+		t.Fatalf("code %d, cookies %v", rec.Code, cookies)
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Cibles de fuzzing des décodeurs de requêtes : aucune entrée ne doit faire paniquer un handler
+// ni produire une réponse 5xx ; `go test -fuzz=FuzzTrackHandler ./internal/app/handlers`
+
+func FuzzTrackHandler(f *testing.F) {
+	for _, seed := range []string{
+		`{"event":"checkout.completed","properties":{"amount":49.9,"plan":"pro"}}`,
+		`{"event":"page_viewed","timestamp":"2026-03-01T10:00:00Z"}`,
+		`{"event":"page_viewed","timestamp":"hier"}`,
+		`{"event":"x","properties":{"a":{"b":[1]}}}`,
+		`{"event":"x","properties":[]}`,
+		`{"event":1e999}`,
+		`[]`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	// Même validation que le pipeline, puis construction de l'entité comme le use case
+	track := usecases.UseCaseFunc[usecases.TrackEventRequest, *usecases.TrackEventResponse](
+		func(ctx context.Context, req usecases.TrackEventRequest) (*usecases.TrackEventResponse, error) {
+			if err := req.Validate(); err != nil {
+				return nil, err
+			}
+			var occurredAt time.Time
+			if req.Timestamp != "" {
+				parsed, err := time.Parse(time.RFC3339, req.Timestamp)
+				if err != nil {
+					return nil, err
+				}
+				occurredAt = parsed
+			}
+			if _, err := entities.NewTrackedEvent(req.Event, 0, req.Properties, occurredAt, time.Now()); err != nil {
+				return nil, err
+			}
+			return &usecases.TrackEventResponse{Status: "accepted"}, nil
+		})
+	handler := NewAnalyticsHandler(nil, nil, nil, track, nil)
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		recorder := httptest.NewRecorder()
+		handler.Track(recorder, httptest.NewRequest(http.MethodPost, "/analytics/track", bytes.NewReader(body)))
+
+		if recorder.Code != http.StatusAccepted && recorder.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d for %q: %s", recorder.Code, body, recorder.Body)
+		}
+		if !json.Valid(recorder.Body.Bytes()) {
+			t.Fatalf("response is not JSON: %q", recorder.Body)
+		}
+	})
+}
+
+func FuzzDecodeUserMergePatch(f *testing.F) {
+	for _, seed := range []string{
+		`{"name":"Alice"}`,
+		`{"email":"a@b.c","attributes":{"plan":"pro","seats":null}}`,
+		`{"name":null}`,
+		`{"unknown":1}`,
+		`{"name":1}`,
+		`{"attributes":[]}`,
+		`{}`,
+		`null`,
+		`"name"`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		req, err := decodeUserMergePatch(bytes.NewReader(body))
+		if err != nil {
+			if req.Name != nil || req.Email != nil || req.Attributes != nil {
+				t.Fatalf("partial patch returned alongside %v", err)
+			}
+			return
+		}
+		var members map[string]json.RawMessage
+		if json.NewDecoder(bytes.NewReader(body)).Decode(&members) != nil {
+			t.Fatalf("accepted a body that is not a JSON object: %q", body)
+		}
+		for member := range members {
+			if member != "name" && member != "email" && member != "attributes" {
+				t.Fatalf("accepted unknown member %q", member)
+			}
+		}
+	})
+}
+
+func FuzzDecodeV2Cursor(f *testing.F) {
+	f.Add(encodeV2Cursor(1, 20))
+	f.Add(encodeV2Cursor(3, 100))
+	f.Add("cDoxOjA")
+	f.Add("cDotMToxMA")
+	f.Add("====")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		page, pageSize, err := decodeV2Cursor(cursor)
+		if err != nil {
+			return
+		}
+		if page < 1 || pageSize < 1 || pageSize > 100 {
+			t.Fatalf("cursor %q decoded to out-of-range page %d / size %d", cursor, page, pageSize)
+		}
+		// Le curseur réémis pour la même page doit se relire à l'identique
+		if page2, size2, err := decodeV2Cursor(encodeV2Cursor(page, pageSize)); err != nil || page2 != page || size2 != pageSize {
+			t.Fatalf("cursor round trip failed for page %d / size %d: %v", page, pageSize, err)
+		}
+	})
+}
+
+func FuzzParseUserUUID(f *testing.F) {
+	f.Add(formatUserUUID(1))
+	f.Add(formatUserUUID(math.MaxInt32))
+	f.Add(strings.ToUpper(formatUserUUID(255)))
+	f.Add(userUUIDPrefix + "000000000000")
+	f.Add(userUUIDPrefix + "-00000000001")
+	f.Add("not-a-uuid")
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		id, err := parseUserUUID(raw)
+		if err != nil {
+			return
+		}
+		if id <= 0 {
+			t.Fatalf("%q parsed to non-positive id %d", raw, id)
+		}
+		if formatted := formatUserUUID(id); formatted != strings.ToLower(raw) {
+			t.Fatalf("%q parsed to %d, formatted back as %q", raw, id, formatted)
+		}
+	})
+}
+
+func FuzzParseQualities(f *testing.F) {
+	for _, seed := range []string{"gzip;q=0.8, br, *;q=0", "gzip;q=", ";;,,", "br;q=NaN", "GZIP ; Q = 1", "*"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, header string) {
+		for value := range parseQualities(header) {
+			if value == "" || value != strings.ToLower(strings.TrimSpace(value)) {
+				t.Fatalf("header %q produced a non-normalized value %q", header, value)
+			}
+		}
+		_, _ = negotiateEncoding(header, []Encoding{{Name: "gzip"}, {Name: "br"}})
+	})
+}
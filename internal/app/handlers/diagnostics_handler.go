@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+)
+
+// diagnosticsAction action soumise à l'Authorizer pour chaque requête de diagnostic (hors pipeline : usecases.AccessAdmin)
+const diagnosticsAction = "debug_diagnostics"
+
+// ProfileWriter écrit un profil runtime sur le disque de l'instance (services.ProfileStore)
+type ProfileWriter interface {
+	WriteProfile(kind string) (string, error)
+}
+
+// ProfileResponse profil écrit par POST /debug/profiles/{kind}
+type ProfileResponse struct {
+	Kind string `json:"kind"`
+	File string `json:"file"`
+}
+
+// NewDiagnosticsHandler sert les diagnostics de l'instance :
+//   - /debug/pprof/... : net/http/pprof (profils CPU, tas, goroutines, trace)
+//   - GET /debug/vars : métriques expvar
+//   - POST /debug/profiles/{kind} : profil ("heap", "goroutine"...) écrit sur disque, pour une
+//     capture au moment d'un incident sans garder la connexion ouverte
+//
+// authorizer nil = aucun contrôle (port interne DEBUG_ADDR) ; sinon chaque requête doit être autorisée
+func NewDiagnosticsHandler(profiles ProfileWriter, authorizer usecases.Authorizer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	mux.HandleFunc("POST /debug/profiles/{kind}", func(w http.ResponseWriter, r *http.Request) {
+		kind := r.PathValue("kind")
+		if runtimepprof.Lookup(kind) == nil {
+			writeError(w, http.StatusNotFound, "unknown profile")
+			return
+		}
+		file, err := profiles.WriteProfile(kind)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, ProfileResponse{Kind: kind, File: file})
+	})
+
+	if authorizer == nil {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authorizer.Authorize(r.Context(), diagnosticsAction, nil); err != nil {
+			writeUseCaseError(w, http.StatusForbidden, err)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+)
+
+// DisplayFormatResolver format d'affichage de l'appelant (usecases.DisplayFormats) pour les champs
+// optionnels ?display=true : dates et nombres selon sa locale et son fuseau. nil : paramètre ignoré
+type DisplayFormatResolver interface {
+	ForActor(ctx context.Context) usecases.DisplayFormat
+}
+
+// bindDisplay ?display=true demandé et un résolveur configuré
+func bindDisplay(b *requestBinder, resolver DisplayFormatResolver) bool {
+	var display bool
+	b.QueryBool("display", &display)
+	return display && resolver != nil
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// DownloadLinkVerifier vérifie les liens de téléchargement signés (usecases.DownloadLinks)
+type DownloadLinkVerifier interface {
+	Verify(path string, params url.Values) error
+}
+
+// RequireSignedURL sert next uniquement sur un lien signé valide : 403 si la signature ne correspond
+// pas (chemin ou paramètre modifié), 410 s'il a expiré. L'autorisation a été vérifiée à l'émission
+// du lien : next s'exécute en tâche interne, sans jeton Bearer ni cookie de session
+func RequireSignedURL(next http.Handler, verifier DownloadLinkVerifier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := verifier.Verify(r.URL.Path, r.URL.Query())
+		switch {
+		case errors.Is(err, usecases.ErrDownloadLinkExpired):
+			writeError(w, http.StatusGone, "download link expired")
+			return
+		case errors.Is(err, usecases.ErrDownloadLinksDisabled):
+			writeError(w, http.StatusNotFound, "download links are disabled")
+			return
+		case err != nil:
+			writeError(w, http.StatusForbidden, "invalid download link")
+			return
+		}
+
+		// Le lien donne accès au fichier : ni cache partagé, ni fuite par l'en-tête Referer
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		next.ServeHTTP(w, r.WithContext(usecases.ContextWithActor(r.Context(), usecases.SystemActor)))
+	})
+}
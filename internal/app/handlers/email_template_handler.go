@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// EmailTemplateHandler administration des modèles d'emails (welcome, reset, invite)
+type EmailTemplateHandler struct {
+	save     usecases.UseCase[usecases.SaveEmailTemplateRequest, *usecases.EmailTemplateResponse]
+	versions usecases.UseCase[usecases.ListEmailTemplateVersionsRequest, *usecases.ListEmailTemplateVersionsResponse]
+	preview  usecases.UseCase[usecases.PreviewEmailTemplateRequest, *usecases.PreviewEmailTemplateResponse]
+}
+
+func NewEmailTemplateHandler(
+	save usecases.UseCase[usecases.SaveEmailTemplateRequest, *usecases.EmailTemplateResponse],
+	versions usecases.UseCase[usecases.ListEmailTemplateVersionsRequest, *usecases.ListEmailTemplateVersionsResponse],
+	preview usecases.UseCase[usecases.PreviewEmailTemplateRequest, *usecases.PreviewEmailTemplateResponse],
+) *EmailTemplateHandler {
+	return &EmailTemplateHandler{save: save, versions: versions, preview: preview}
+}
+
+// Save PUT /admin/email-templates/{name} {"tenant_id", "locale", "subject", "text", "html"}
+// Ajoute une version ; tenant_id et locale vides = modèle par défaut
+func (h *EmailTemplateHandler) Save(w http.ResponseWriter, r *http.Request) {
+	var req usecases.SaveEmailTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.Name = r.PathValue("name")
+
+	response, err := h.save.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, emailTemplateErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// Versions GET /admin/email-templates/{name}/versions?tenant_id=&locale=
+func (h *EmailTemplateHandler) Versions(w http.ResponseWriter, r *http.Request) {
+	req := usecases.ListEmailTemplateVersionsRequest{}
+	req.Name = r.PathValue("name")
+	b := bindRequest(r)
+	b.QueryString("tenant_id", &req.TenantID)
+	b.QueryString("locale", &req.Locale)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.versions.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, emailTemplateErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Preview POST /admin/email-templates/{name}/preview {"tenant_id", "locale", "version", "draft", "data"}
+// Rend le modèle sans l'envoyer
+func (h *EmailTemplateHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req usecases.PreviewEmailTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.Name = r.PathValue("name")
+
+	response, err := h.preview.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, emailTemplateErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func emailTemplateErrorStatus(err error) int {
+	if errors.Is(err, entities.ErrUnknownEmailTemplate) || errors.Is(err, repositories.ErrEmailTemplateNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userETag ETag faible dérivé de l'ID et de la date de mise à jour : toute écriture le change
+// Faible car la représentation JSON peut varier (ordre, formatage) sans que la ressource change
+func userETag(id int, updated time.Time) string {
+	return `W/"` + strconv.Itoa(id) + "-" + strconv.FormatInt(updated.UnixNano(), 36) + `"`
+}
+
+// etagMatches applique la comparaison faible (RFC 9110 §8.8.3.2) à une liste d'ETags ou "*"
+// If-Match utilise aussi la comparaison faible : nos ETags sont tous faibles et changent à
+// chaque écriture, ce qui suffit à détecter une mise à jour perdue
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUserETag inverse de userETag (ETag faible ou fort, entre guillemets)
+func parseUserETag(etag string) (int, time.Time, bool) {
+	opaque := strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	opaque, found := strings.CutPrefix(opaque, `"`)
+	if !found {
+		return 0, time.Time{}, false
+	}
+	opaque, found = strings.CutSuffix(opaque, `"`)
+	if !found {
+		return 0, time.Time{}, false
+	}
+	rawID, rawUpdated, found := strings.Cut(opaque, "-")
+	if !found {
+		return 0, time.Time{}, false
+	}
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(rawUpdated, 36, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return id, time.Unix(0, nanos), true
+}
+
+// ifMatchUpdated date de modification exigée par If-Match pour l'utilisateur id, transmise au use
+// case qui conditionne l'écriture (UpdateIfUnchanged) : la vérification et l'écriture sont
+// atomiques. nil sans en-tête ou avec "*" (l'existence suffit). Dans une liste, seul le premier
+// ETag de cet utilisateur est retenu ; sans aucun, 412 immédiat (false, réponse écrite)
+func ifMatchUpdated(w http.ResponseWriter, r *http.Request, id int) (*time.Time, bool) {
+	match := r.Header.Get("If-Match")
+	if match == "" || strings.TrimSpace(match) == "*" {
+		return nil, true
+	}
+	for _, candidate := range strings.Split(match, ",") {
+		if etagID, updated, ok := parseUserETag(candidate); ok && etagID == id {
+			return &updated, true
+		}
+	}
+	writeError(w, http.StatusPreconditionFailed, "user was modified since it was read")
+	return nil, false
+}
+
+// writeUserModified 412 d'une écriture conditionnelle refusée, avec l'ETag courant de l'utilisateur
+func writeUserModified(w http.ResponseWriter, r *http.Request, getUser usecases.UseCase[int, *usecases.GetUserResponse], id int) {
+	if current, err := getUser.Execute(r.Context(), id); err == nil {
+		w.Header().Set("ETag", userETag(current.ID, current.Updated))
+	}
+	writeError(w, http.StatusPreconditionFailed, "user was modified since it was read")
+}
+
+// setUserCacheHeaders publie l'ETag ; no-cache impose une revalidation (304 si inchangé)
+func setUserCacheHeaders(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+}
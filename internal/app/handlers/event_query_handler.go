@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// EventQueryHandler requêtes sur les événements bruts filtrés par expression
+// (event = "signup" AND props.plan IN ("pro", "team")) et rapports enregistrés de l'appelant
+type EventQueryHandler struct {
+	query        usecases.UseCase[usecases.QueryEventsRequest, *usecases.GetEventRollupsResponse]
+	createReport usecases.UseCase[usecases.CreateSavedReportRequest, *entities.SavedReport]
+	listReports  usecases.UseCase[usecases.ListSavedReportsRequest, *usecases.ListSavedReportsResponse]
+	runReport    usecases.UseCase[usecases.SavedReportRequest, *usecases.RunSavedReportResponse]
+	deleteReport usecases.UseCase[usecases.SavedReportRequest, struct{}]
+}
+
+func NewEventQueryHandler(
+	query usecases.UseCase[usecases.QueryEventsRequest, *usecases.GetEventRollupsResponse],
+	createReport usecases.UseCase[usecases.CreateSavedReportRequest, *entities.SavedReport],
+	listReports usecases.UseCase[usecases.ListSavedReportsRequest, *usecases.ListSavedReportsResponse],
+	runReport usecases.UseCase[usecases.SavedReportRequest, *usecases.RunSavedReportResponse],
+	deleteReport usecases.UseCase[usecases.SavedReportRequest, struct{}],
+) *EventQueryHandler {
+	return &EventQueryHandler{query: query, createReport: createReport, listReports: listReports, runReport: runReport, deleteReport: deleteReport}
+}
+
+// Query GET /analytics/query?where=&from=&to=&interval=day|week|month
+// Mêmes séries que /analytics/rollups, sur 92 jours au plus
+func (h *EventQueryHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req usecases.QueryEventsRequest
+	b := bindRequest(r)
+	b.QueryString("where", &req.Where)
+	b.DateRange("from", "to", &req.From, &req.To)
+	b.QueryEnum("interval", usecases.EventRollupIntervals, &req.Interval)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.query.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateReport POST /analytics/reports {"name", "where", "interval", "days"}
+func (h *EventQueryHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	var req usecases.CreateSavedReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.createReport.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// ListReports GET /analytics/reports
+func (h *EventQueryHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	response, err := h.listReports.Execute(r.Context(), usecases.ListSavedReportsRequest{})
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// RunReport GET /analytics/reports/{id}/run : séries des Days derniers jours
+func (h *EventQueryHandler) RunReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "report id")
+	if !ok {
+		return
+	}
+
+	response, err := h.runReport.Execute(r.Context(), usecases.SavedReportRequest{ID: id})
+	if err != nil {
+		writeUseCaseError(w, savedReportErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// DeleteReport DELETE /analytics/reports/{id}
+func (h *EventQueryHandler) DeleteReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "report id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.deleteReport.Execute(r.Context(), usecases.SavedReportRequest{ID: id}); err != nil {
+		writeUseCaseError(w, savedReportErrorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func savedReportErrorStatus(err error) int {
+	if errors.Is(err, repositories.ErrSavedReportNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
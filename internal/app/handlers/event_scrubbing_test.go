@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/infra/database"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// NETTOYAGE DES DONNÉES PERSONNELLES : rien de brut n'atteint le stockage des événements
+// =============================================================================
+
+// scrubbingPayload propriétés personnelles envoyées par un client mal configuré
+const scrubbingPayload = `{"event":"checkout.completed","properties":{
+	"plan":"pro",
+	"client_ip":"203.0.113.77",
+	"forwarded_for":"2001:db8:85a3:8d3:1319:8a2e:370:7348",
+	"mapped_ip":"::ffff:198.51.100.23",
+	"email":"Jane.Doe@example.com",
+	"user_id":4242,
+	"password":"hunter2",
+	"phone":"+33612345678"
+}}`
+
+// scrubbingRawValues valeurs qui ne doivent apparaître dans aucun événement stocké
+var scrubbingRawValues = []string{
+	"203.0.113.77", "2001:db8:85a3:8d3:1319:8a2e:370:7348", "198.51.100.23",
+	"jane.doe@example.com", "4242", "hunter2", "+33612345678",
+}
+
+// newScrubbedTracking ingestion complète (handler, use case, échantillonneur) sur un dépôt nettoyé
+func newScrubbedTracking(t *testing.T, hasher usecases.IdentifierHasher, limits usecases.TrackingLimits) (*AnalyticsHandler, *usecases.FlushEventSamplesUseCase, *database.InMemoryEventRepository) {
+	t.Helper()
+	store := database.NewInMemoryEventRepository()
+	scrubber := usecases.NewEventScrubber(usecases.EventScrubPolicy{
+		IPv4Prefix:       24,
+		IPv6Prefix:       48,
+		HashedProperties: []string{"user_id", "email"},
+		DeniedProperties: []string{"password", "phone"},
+	}, hasher)
+	eventRepo := scrubber.TrackedEvents(store)
+	sampler := usecases.NewEventSampler(limits)
+	metrics := services.NewExpvarTrackingMetrics()
+	track := usecases.NewTrackEventUseCase(eventRepo, sampler, nil, nil, metrics,
+		services.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+	flush := usecases.NewFlushEventSamplesUseCase(eventRepo, sampler, metrics)
+	return NewAnalyticsHandler(nil, nil, nil, track, nil), flush, store
+}
+
+func postTrack(t *testing.T, handler *AnalyticsHandler, payload string) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.Track(recorder, httptest.NewRequest(http.MethodPost, "/analytics/track", strings.NewReader(payload)))
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("status %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+// assertNoRawPII aucune valeur brute, sous aucune clé, dans les événements stockés
+func assertNoRawPII(t *testing.T, store repositories.EventRepository) []map[string]any {
+	t.Helper()
+	stored, err := store.List(context.Background(), repositories.EventFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) == 0 {
+		t.Fatal("no event stored")
+	}
+	properties := make([]map[string]any, len(stored))
+	for i, event := range stored {
+		for key, value := range event.Properties {
+			text := strings.ToLower(fmt.Sprint(value))
+			for _, raw := range scrubbingRawValues {
+				if strings.Contains(text, strings.ToLower(raw)) {
+					t.Errorf("raw value %q stored under %q", raw, key)
+				}
+			}
+		}
+		properties[i] = event.Properties
+	}
+	return properties
+}
+
+func TestEventScrubbingStripsPII(t *testing.T) {
+	pseudonymizer, err := services.NewHMACPseudonymizer(strings.Repeat("k", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, _, store := newScrubbedTracking(t, pseudonymizer, usecases.TrackingLimits{MaxEventNames: 10, MaxPropertyValues: 100})
+	postTrack(t, handler, scrubbingPayload)
+	postTrack(t, handler, strings.Replace(scrubbingPayload, "Jane.Doe@", "jane.doe@", 1))
+
+	stored := assertNoRawPII(t, store)
+	first := stored[0]
+	for key, want := range map[string]any{
+		"plan":          "pro",
+		"client_ip":     "203.0.113.0",
+		"forwarded_for": "2001:db8:85a3::",
+		"mapped_ip":     "198.51.100.0",
+	} {
+		if first[key] != want {
+			t.Errorf("%s = %v, want %v", key, first[key], want)
+		}
+	}
+	for _, key := range []string{"password", "phone"} {
+		if _, ok := first[key]; ok {
+			t.Errorf("%s not removed", key)
+		}
+	}
+	// Empreintes stables : les utilisateurs distincts restent comptables
+	if first["email"] != pseudonymizer.Identifier("jane.doe@example.com") || stored[1]["email"] != first["email"] {
+		t.Errorf("email hashes %v / %v not stable", first["email"], stored[1]["email"])
+	}
+}
+
+func TestEventScrubbingWithoutKeyRemovesIdentifiers(t *testing.T) {
+	handler, _, store := newScrubbedTracking(t, nil, usecases.TrackingLimits{MaxEventNames: 10, MaxPropertyValues: 100})
+	postTrack(t, handler, scrubbingPayload)
+
+	for _, key := range []string{"email", "user_id"} {
+		if _, ok := assertNoRawPII(t, store)[0][key]; ok {
+			t.Errorf("%s kept without hashing key", key)
+		}
+	}
+}
+
+// Les événements retenus dans un réservoir sont écrits en fin de fenêtre, par un autre chemin
+func TestEventScrubbingCoversSampledEvents(t *testing.T) {
+	handler, flush, store := newScrubbedTracking(t, nil, usecases.TrackingLimits{MaxEventNames: 10, MaxPropertyValues: 1, ReservoirSize: 5})
+	postTrack(t, handler, scrubbingPayload)
+	postTrack(t, handler, strings.Replace(scrubbingPayload, `"pro"`, `"team"`, 1))
+
+	response, err := flush.Execute(context.Background(), usecases.FlushEventSamplesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Sampled != 1 {
+		t.Fatalf("sampled %d events, want 1", response.Sampled)
+	}
+	if stored := assertNoRawPII(t, store); len(stored) != 2 {
+		t.Fatalf("stored %d events, want 2", len(stored))
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// exportPollInterval délai (secondes) suggéré au client entre deux lectures du statut
+const exportPollInterval = 2
+
+// ExportHandler exports volumineux asynchrones :
+//
+//	POST /exports {"kind": "audit", "filters": {...}}  202 + Location, statut queued
+//	GET  /exports/{id}                                 statut ; lien de téléchargement signé une fois terminé
+//	GET  /exports/{id}/download                        fichier, pour le propriétaire du job
+//	GET  /downloads/exports/{id}?expires=&signature=   fichier, sur lien signé
+//
+// Kinds audit, events et rollups ; "format" csv (défaut) ou parquet, "compression" selon le format
+type ExportHandler struct {
+	start    usecases.UseCase[usecases.StartExportRequest, *usecases.ExportJobResponse]
+	status   usecases.UseCase[string, *usecases.ExportJobResponse]
+	download usecases.UseCase[string, *usecases.ExportFile]
+}
+
+func NewExportHandler(
+	start usecases.UseCase[usecases.StartExportRequest, *usecases.ExportJobResponse],
+	status usecases.UseCase[string, *usecases.ExportJobResponse],
+	download usecases.UseCase[string, *usecases.ExportFile],
+) *ExportHandler {
+	return &ExportHandler{start: start, status: status, download: download}
+}
+
+// Start POST /exports
+func (h *ExportHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var req usecases.StartExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.start.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Location", "/exports/"+response.ID)
+	w.Header().Set("Retry-After", strconv.Itoa(exportPollInterval))
+	writeJSON(w, http.StatusAccepted, response)
+}
+
+// Status GET /exports/{id} ; Retry-After tant que le job n'est pas terminé
+func (h *ExportHandler) Status(w http.ResponseWriter, r *http.Request) {
+	response, err := h.status.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeUseCaseError(w, exportErrorStatus(err), err)
+		return
+	}
+
+	if response.Status == entities.ExportQueued || response.Status == entities.ExportRunning {
+		w.Header().Set("Retry-After", strconv.Itoa(exportPollInterval))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Download GET /exports/{id}/download et GET /downloads/exports/{id} (derrière RequireSignedURL)
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	file, err := h.download.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeUseCaseError(w, exportErrorStatus(err), err)
+		return
+	}
+	defer file.Content.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.Job.Kind+"-"+file.Job.Created.UTC().Format("20060102T150405Z")+"."+file.Job.Format+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, file.Content)
+}
+
+func exportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrExportJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecases.ErrExportNotReady):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// FICHIERS GOLDEN : forme canonique des réponses de l'API
+// =============================================================================
+
+// -update réécrit les fichiers golden depuis les réponses actuelles :
+//
+//	go test ./internal/app/handlers -run TestAPIContract -update
+//
+// Le diff des fichiers dans la revue de code montre alors le changement de contrat
+var updateGolden = flag.Bool("update", false, "réécrit les fichiers testdata/golden au lieu de les comparer")
+
+// goldenHeaders en-têtes qui font partie du contrat ; les autres (X-Request-ID, Date...) varient
+var goldenHeaders = []string{"Allow", "Content-Type", "Cache-Control", "ETag", "Location", "Retry-After", "X-Total-Count"}
+
+// assertGolden compare la réponse enregistrée à testdata/golden/<name>.golden
+func assertGolden(t *testing.T, name string, recorder *httptest.ResponseRecorder) {
+	t.Helper()
+	got := canonicalResponse(recorder)
+	path := filepath.Join("testdata", "golden", name+".golden")
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file %s (run with -update to create it): %v", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("response differs from %s (run with -update if the change is intended):\n%s", path, lineDiff(string(want), got))
+	}
+}
+
+// canonicalResponse statut, en-têtes du contrat triés, puis corps ; un corps JSON est indenté
+// (l'ordre des champs est conservé : il fait partie de ce que voient les clients)
+func canonicalResponse(recorder *httptest.ResponseRecorder) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "HTTP %d\n", recorder.Code)
+	for _, name := range goldenHeaders {
+		for _, value := range recorder.Header().Values(name) {
+			fmt.Fprintf(&out, "%s: %s\n", name, value)
+		}
+	}
+
+	body := recorder.Body.Bytes()
+	if len(body) == 0 {
+		return out.String()
+	}
+	out.WriteString("\n")
+	var indented bytes.Buffer
+	if json.Valid(body) && json.Indent(&indented, body, "", "  ") == nil {
+		out.Write(bytes.TrimSpace(indented.Bytes()))
+	} else {
+		out.Write(bytes.TrimSpace(body))
+	}
+	out.WriteString("\n")
+	return out.String()
+}
+
+// lineDiff diff ligne à ligne (plus longue sous-suite commune) : "-" attendu, "+" obtenu
+func lineDiff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// lcs[i][j] longueur de la sous-suite commune de a[i:] et b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("  " + a[i] + "\n")
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// idempotencyKeyHeader clé choisie par le client pour un POST (posée par pkg/client à chaque appel)
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotentBodySize au-delà, la requête est traitée sans protection (imports en masse...)
+const maxIdempotentBodySize = 1 << 20 // 1 MiB
+
+// Idempotency rejoue la réponse d'un POST déjà traité avec la même clé Idempotency-Key (nouvel
+// essai d'un client après un timeout ou une coupure) au lieu de réexécuter le use case :
+//   - la clé est propre à l'acteur authentifié et à la route
+//   - la même clé avec un autre corps est refusée (422), une requête encore en cours répond 409
+//   - les réponses 5xx ne sont pas gardées : le nouvel essai est réexécuté
+//
+// Les réponses sont gardées ttl dans la mémoire du processus : derrière plusieurs instances,
+// un nouvel essai arrivé sur une autre instance est réexécuté
+func Idempotency(next http.Handler, ttl time.Duration) http.Handler {
+	if ttl <= 0 {
+		return next
+	}
+	store := &idempotencyStore{ttl: ttl, entries: make(map[string]*idempotentResponse)}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > 255 {
+			writeError(w, http.StatusBadRequest, "Idempotency-Key too long")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "unreadable request body")
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		actor, _ := usecases.ActorFromContext(r.Context())
+		scope := fmt.Sprintf("%d|%s|%s|%s", actor.UserID, actor.TenantID, r.URL.Path, key)
+		fingerprint := sha256.Sum256(body)
+
+		entry, state := store.reserve(scope, fingerprint)
+		switch state {
+		case idempotencyReplay:
+			maps.Copy(w.Header(), entry.header)
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(entry.status)
+			_, _ = w.Write(entry.body)
+			return
+		case idempotencyInProgress:
+			writeError(w, http.StatusConflict, "a request with this Idempotency-Key is in progress")
+			return
+		case idempotencyMismatch:
+			writeError(w, http.StatusUnprocessableEntity, "Idempotency-Key reused with a different request body")
+			return
+		}
+
+		// En-têtes posés en amont (X-Request-ID, CORS...) : propres à chaque essai, pas rejoués
+		upstream := w.Header().Clone()
+		recorder := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+		// Une panique du handler libère la clé avant de remonter jusqu'à Recover
+		completed := false
+		defer func() {
+			if !completed {
+				store.release(scope)
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+		completed = true
+
+		if recorder.status >= http.StatusInternalServerError {
+			store.release(scope)
+			return
+		}
+		header := w.Header().Clone()
+		for name, values := range upstream {
+			if slices.Equal(header[name], values) {
+				delete(header, name)
+			}
+		}
+		store.complete(scope, recorder.status, header, recorder.body.Bytes())
+	})
+}
+
+type idempotencyState int
+
+const (
+	idempotencyNew idempotencyState = iota
+	idempotencyReplay
+	idempotencyInProgress
+	idempotencyMismatch
+)
+
+type idempotentResponse struct {
+	fingerprint [sha256.Size]byte
+	done        bool
+	status      int
+	header      http.Header
+	body        []byte
+	expires     time.Time
+}
+
+type idempotencyStore struct {
+	ttl time.Duration
+
+	mutex     sync.Mutex
+	entries   map[string]*idempotentResponse
+	lastSweep time.Time
+}
+
+// reserve réserve la clé pour une première exécution, ou retourne la réponse gardée
+func (s *idempotencyStore) reserve(scope string, fingerprint [sha256.Size]byte) (*idempotentResponse, idempotencyState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if entry, ok := s.entries[scope]; ok && now.Before(entry.expires) {
+		switch {
+		case entry.fingerprint != fingerprint:
+			return nil, idempotencyMismatch
+		case !entry.done:
+			return nil, idempotencyInProgress
+		}
+		return entry, idempotencyReplay
+	}
+	s.entries[scope] = &idempotentResponse{fingerprint: fingerprint, expires: now.Add(s.ttl)}
+	return nil, idempotencyNew
+}
+
+func (s *idempotencyStore) complete(scope string, status int, header http.Header, body []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if entry, ok := s.entries[scope]; ok {
+		entry.done, entry.status, entry.header, entry.body = true, status, header, body
+	}
+}
+
+func (s *idempotencyStore) release(scope string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.entries, scope)
+}
+
+// sweep purge les clés expirées, au plus une fois par minute (mutex tenu)
+func (s *idempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for scope, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, scope)
+		}
+	}
+}
+
+// idempotencyRecorder transmet la réponse au client et en garde une copie
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status, rec.wroteHeader = status, true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *idempotencyRecorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
+
+func (rec *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
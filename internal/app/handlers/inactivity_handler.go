@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"net/http"
+)
+
+// InactivityHandler expose le rapport des comptes inactifs
+type InactivityHandler struct {
+	listInactive usecases.UseCase[usecases.ListInactiveUsersRequest, *usecases.ListInactiveUsersResponse]
+}
+
+func NewInactivityHandler(listInactive usecases.UseCase[usecases.ListInactiveUsersRequest, *usecases.ListInactiveUsersResponse]) *InactivityHandler {
+	return &InactivityHandler{listInactive: listInactive}
+}
+
+// List GET /users/inactive?days=90&page=1&page_size=10 (30 jours par défaut)
+func (h *InactivityHandler) List(w http.ResponseWriter, r *http.Request) {
+	req := usecases.ListInactiveUsersRequest{Days: 30}
+	b := bindRequest(r)
+	b.QueryInt("days", &req.Days, 1, 0)
+	b.Pagination(&req.Page, &req.PageSize)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.listInactive.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
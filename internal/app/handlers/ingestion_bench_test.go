@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/infra/database"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// discardResponseWriter ResponseWriter sans allocation propre : seules celles du handler sont mesurées
+type discardResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *discardResponseWriter) WriteHeader(status int)      { w.status = status }
+
+func (w *discardResponseWriter) reset() {
+	clear(w.header)
+	w.status = 0
+}
+
+// benchListBody liste représentative d'une page d'utilisateurs
+func benchListBody() *usecases.ListUsersResponse {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	response := &usecases.ListUsersResponse{Users: make([]*usecases.GetUserResponse, 20), Total: 20, Page: 1, PageSize: 20, TotalPages: 1}
+	for i := range response.Users {
+		response.Users[i] = &usecases.GetUserResponse{ID: i + 1, Email: "user@example.com", Name: "User", Created: now, Updated: now, Status: "active"}
+	}
+	return response
+}
+
+// BenchmarkWriteJSON pooled : writeJSON (tampon du pool, en-tête écrit après l'encodage) ;
+// direct : encodage directement dans la réponse. Les deux doivent rester à allocations égales
+func BenchmarkWriteJSON(b *testing.B) {
+	body := benchListBody()
+	w := &discardResponseWriter{header: make(http.Header)}
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			w.reset()
+			writeJSON(w, http.StatusOK, body)
+		}
+	})
+	b.Run("direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			w.reset()
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	})
+}
+
+// BenchmarkDecodeTrackRequest pooled : decodeBody et requête du pool, comme Track ; decoder :
+// json.Decoder et requête neufs à chaque corps
+func BenchmarkDecodeTrackRequest(b *testing.B) {
+	const payload = `{"event":"checkout.completed","properties":{"plan":"pro","amount":49.5,"trial":false}}`
+	body := strings.NewReader(payload)
+	r, err := http.NewRequest(http.MethodPost, "/analytics/track", body)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w := &discardResponseWriter{header: make(http.Header)}
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			body.Reset(payload)
+			req := trackRequestPool.Get().(*usecases.TrackEventRequest)
+			if err := decodeBody(w, r, maxTrackBodySize, req); err != nil {
+				b.Fatal(err)
+			}
+			releaseTrackRequest(req)
+		}
+	})
+	b.Run("decoder", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			body.Reset(payload)
+			var req usecases.TrackEventRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTrackBodySize)).Decode(&req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkAnalyticsTrack requête POST /analytics/track complète : décodage, échantillonneur,
+// écriture en mémoire et réponse
+func BenchmarkAnalyticsTrack(b *testing.B) {
+	track := usecases.NewTrackEventUseCase(
+		database.NewInMemoryEventRepository(),
+		usecases.NewEventSampler(usecases.TrackingLimits{MaxEventNames: 100, MaxPropertyValues: 1000, ReservoirSize: 10}),
+		nil,
+		nil,
+		services.NewExpvarTrackingMetrics(),
+		services.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+	)
+	handler := NewAnalyticsHandler(nil, nil, nil, track, nil)
+	const payload = `{"event":"checkout.completed","properties":{"plan":"pro","amount":49.5,"trial":false}}`
+	body := strings.NewReader(payload)
+	r, err := http.NewRequest(http.MethodPost, "/analytics/track", body)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w := &discardResponseWriter{header: make(http.Header)}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		body.Reset(payload)
+		w.reset()
+		handler.Track(w, r)
+		if w.status != http.StatusAccepted {
+			b.Fatalf("status %d", w.status)
+		}
+	}
+}
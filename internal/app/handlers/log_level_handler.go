@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"net/http"
+)
+
+// LogLevelHandler change le niveau de log de l'instance sans redéploiement
+type LogLevelHandler struct {
+	get usecases.UseCase[struct{}, *usecases.LogLevelResponse]
+	set usecases.UseCase[usecases.SetLogLevelRequest, *usecases.LogLevelResponse]
+}
+
+func NewLogLevelHandler(
+	get usecases.UseCase[struct{}, *usecases.LogLevelResponse],
+	set usecases.UseCase[usecases.SetLogLevelRequest, *usecases.LogLevelResponse],
+) *LogLevelHandler {
+	return &LogLevelHandler{get: get, set: set}
+}
+
+// Get GET /admin/log-level
+func (h *LogLevelHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.get.Execute(r.Context(), struct{}{})
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Set PUT /admin/log-level {"level": "debug" | "info" | "warn" | "error"}
+func (h *LogLevelHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req usecases.SetLogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.set.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+// requestIDHeader en-tête lu (si fourni par le proxy) et renvoyé au client
+const requestIDHeader = "X-Request-ID"
+
+// WithRequestID pose un identifiant de requête dans le context et dans la réponse
+// L'identifiant fourni par un proxy amont est conservé s'il est raisonnable
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(usecases.ContextWithRequestID(r.Context(), requestID)))
+	})
+}
+
+// WithClient pose dans le context l'adresse et le User-Agent du client (appareil et localisation
+// des sessions) et son jeton CAPTCHA (X-Captcha-Token) ; l'adresse est celle de la connexion : X-Forwarded-For, falsifiable, n'est pas lu
+func WithClient(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := usecases.ClientInfo{
+			IP:           remoteHost(r.RemoteAddr),
+			UserAgent:    r.UserAgent(),
+			CaptchaToken: r.Header.Get("X-Captcha-Token"),
+		}
+		next.ServeHTTP(w, r.WithContext(usecases.ContextWithClient(r.Context(), client)))
+	})
+}
+
+// Authenticate pose dans le context l'acteur d'un jeton Bearer valide, ou du cookie de session
+// (sessionCookie, vide = mode session désactivé), lu ensuite par les politiques d'autorisation.
+// Un jeton absent ou invalide laisse la requête anonyme : d'autres schémas (jeton SCIM, signature
+// de webhook) utilisent aussi l'en-tête Authorization
+func Authenticate(next http.Handler, verifier TokenVerifier, sessionCookie string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := accessToken(r, sessionCookie)
+		if !found {
+			next.ServeHTTP(w, r)
+			return
+		}
+		actor, err := verifier.Verify(token)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		noteAccessLogActor(r.Context(), actor)
+		next.ServeHTTP(w, r.WithContext(usecases.ContextWithActor(r.Context(), actor)))
+	})
+}
+
+// WithLocale pose dans le context la première locale valide d'Accept-Language (poids ignorés :
+// les navigateurs les envoient dans l'ordre de préférence) ; "*" et les étiquettes invalides sont sautés
+func WithLocale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+			tag, _, _ := strings.Cut(part, ";")
+			if locale, err := entities.NormalizeLocale(tag); err == nil && locale != "" {
+				r = r.WithContext(usecases.ContextWithLocale(r.Context(), locale))
+				break
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Recover convertit une panique d'un handler en 500 portant l'identifiant de requête,
+// et la signale avec sa stack. http.ErrAbortHandler est relancé (interruption voulue)
+func Recover(next http.Handler, reporter usecases.ErrorReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			reporter.Report(r.Context(), fmt.Errorf("panic: %v", recovered), debug.Stack(), map[string]interface{}{
+				"method": r.Method,
+				"path":   r.URL.Path,
+			})
+			// Sans effet si la réponse a déjà commencé (ex: flux SSE)
+			writeJSON(w, http.StatusInternalServerError, ErrorResponse{
+				Error:     "internal server error",
+				RequestID: usecases.RequestIDFromContext(r.Context()),
+			})
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// =============================================================================
+// NÉGOCIATION DU FORMAT DE RÉPONSE
+// =============================================================================
+
+const contentTypeMessagePack = "application/msgpack"
+
+// Negotiate sert les réponses JSON en MessagePack quand le client le préfère (Accept avec
+// une q-value strictement supérieure à celle de JSON ; JSON reste le format par défaut)
+// Les handlers écrivent toujours du JSON : la conversion a lieu ici, une fois le corps complet
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		if !prefersMessagePack(r.Header.Get("Accept")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		mw := &msgpackWriter{ResponseWriter: w, status: http.StatusOK}
+		defer mw.close()
+		next.ServeHTTP(mw, r)
+	})
+}
+
+func prefersMessagePack(accept string) bool {
+	if accept == "" {
+		return false
+	}
+	qualities := parseQualities(accept)
+	msgpack, listed := qualities[contentTypeMessagePack]
+	if !listed {
+		msgpack, listed = qualities["application/x-msgpack"]
+	}
+	if !listed || msgpack <= 0 {
+		return false
+	}
+	jsonQ, jsonListed := qualities["application/json"]
+	if !jsonListed {
+		jsonQ = max(qualities["application/*"], qualities["*/*"])
+	}
+	return msgpack > jsonQ || (!jsonListed && msgpack >= jsonQ)
+}
+
+// msgpackWriter retient un corps JSON pour le convertir ; les autres réponses passent telles quelles
+type msgpackWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	passthrough bool
+	buffer      bytes.Buffer
+	hijacked    bool
+}
+
+func (mw *msgpackWriter) WriteHeader(status int) {
+	if mw.wroteHeader || mw.passthrough {
+		return
+	}
+	if status < 200 {
+		mw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	mw.status = status
+	mw.wroteHeader = true
+	if !isJSON(mw.Header()) {
+		mw.startPassthrough()
+	}
+}
+
+func (mw *msgpackWriter) Write(p []byte) (int, error) {
+	if !mw.wroteHeader && !mw.passthrough && !isJSON(mw.Header()) {
+		mw.startPassthrough()
+	}
+	if mw.passthrough {
+		return mw.ResponseWriter.Write(p)
+	}
+	return mw.buffer.Write(p)
+}
+
+// Flush : une réponse en flux n'est pas convertible, elle part telle quelle
+func (mw *msgpackWriter) Flush() {
+	if !mw.passthrough {
+		mw.startPassthrough()
+		_, _ = mw.ResponseWriter.Write(mw.buffer.Bytes())
+		mw.buffer.Reset()
+	}
+	if flusher, ok := mw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (mw *msgpackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := mw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack unsupported")
+	}
+	mw.hijacked = true
+	return hijacker.Hijack()
+}
+
+func (mw *msgpackWriter) Unwrap() http.ResponseWriter {
+	return mw.ResponseWriter
+}
+
+func (mw *msgpackWriter) startPassthrough() {
+	mw.passthrough = true
+	mw.ResponseWriter.WriteHeader(mw.status)
+}
+
+func (mw *msgpackWriter) close() {
+	if mw.hijacked || mw.passthrough {
+		return
+	}
+	if !mw.wroteHeader && mw.buffer.Len() == 0 {
+		return
+	}
+
+	body, err := jsonToMessagePack(mw.buffer.Bytes())
+	if err != nil {
+		// Corps JSON invalide : mieux vaut le renvoyer tel quel que rien
+		mw.startPassthrough()
+		_, _ = mw.ResponseWriter.Write(mw.buffer.Bytes())
+		return
+	}
+	mw.Header().Set("Content-Type", contentTypeMessagePack)
+	mw.Header().Del("Content-Length")
+	mw.ResponseWriter.WriteHeader(mw.status)
+	_, _ = mw.ResponseWriter.Write(body)
+}
+
+func isJSON(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// =============================================================================
+// ENCODAGE MESSAGEPACK (sous-ensemble produit par encoding/json)
+// =============================================================================
+
+// jsonToMessagePack convertit un document JSON : objets, tableaux, chaînes, nombres
+// (entiers conservés grâce à json.Number), booléens et null. Les clés d'objet sont triées
+// pour une sortie déterministe
+func jsonToMessagePack(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := writeMessagePack(&out, value); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func writeMessagePack(out *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		out.WriteByte(0xc0)
+	case bool:
+		if v {
+			out.WriteByte(0xc3)
+		} else {
+			out.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			writeMessagePackInt(out, i)
+			return nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		out.WriteByte(0xcb)
+		_ = binary.Write(out, binary.BigEndian, math.Float64bits(f))
+	case string:
+		writeMessagePackHeader(out, len(v), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		out.WriteString(v)
+	case []interface{}:
+		writeMessagePackHeader(out, len(v), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := writeMessagePack(out, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		writeMessagePackHeader(out, len(v), 0x80, 15, 0, 0xde, 0xdf)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := writeMessagePack(out, key); err != nil {
+				return err
+			}
+			if err := writeMessagePack(out, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: type %T non supporté", value)
+	}
+	return nil
+}
+
+// writeMessagePackHeader écrit l'en-tête de taille : forme "fix" jusqu'à fixMax,
+// puis 8 bits (si le format en a un), 16 bits et 32 bits
+func writeMessagePackHeader(out *bytes.Buffer, size int, fixPrefix byte, fixMax int, prefix8, prefix16, prefix32 byte) {
+	switch {
+	case size <= fixMax:
+		out.WriteByte(fixPrefix | byte(size))
+	case prefix8 != 0 && size <= math.MaxUint8:
+		out.WriteByte(prefix8)
+		out.WriteByte(byte(size))
+	case size <= math.MaxUint16:
+		out.WriteByte(prefix16)
+		_ = binary.Write(out, binary.BigEndian, uint16(size))
+	default:
+		out.WriteByte(prefix32)
+		_ = binary.Write(out, binary.BigEndian, uint32(size))
+	}
+}
+
+func writeMessagePackInt(out *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= 127:
+		out.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		out.WriteByte(byte(int8(i)))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		out.WriteByte(0xd2)
+		_ = binary.Write(out, binary.BigEndian, int32(i))
+	default:
+		out.WriteByte(0xd3)
+		_ = binary.Write(out, binary.BigEndian, i)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// NotificationStream source des notifications temps réel d'un utilisateur
+type NotificationStream interface {
+	Subscribe(userID int) (<-chan entities.Notification, func())
+}
+
+// streamAction action soumise à l'Authorizer à l'ouverture d'un flux : celle de la boîte de
+// réception, le flux n'en est que la suite en temps réel
+const streamAction = "list_notifications"
+
+// NotificationHandler expose la boîte de réception in-app
+type NotificationHandler struct {
+	listNotifications usecases.UseCase[usecases.ListNotificationsRequest, *usecases.ListNotificationsResponse]
+	markAsRead        usecases.UseCase[usecases.MarkAsReadRequest, *usecases.MarkAsReadResponse]
+	stream            NotificationStream
+	authorizer        usecases.Authorizer
+}
+
+// NewNotificationHandler authorizer celui du pipeline : le flux SSE n'est pas un use case
+func NewNotificationHandler(
+	listNotifications usecases.UseCase[usecases.ListNotificationsRequest, *usecases.ListNotificationsResponse],
+	markAsRead usecases.UseCase[usecases.MarkAsReadRequest, *usecases.MarkAsReadResponse],
+	stream NotificationStream,
+	authorizer usecases.Authorizer,
+) *NotificationHandler {
+	return &NotificationHandler{
+		listNotifications: listNotifications,
+		markAsRead:        markAsRead,
+		stream:            stream,
+		authorizer:        authorizer,
+	}
+}
+
+// List GET /users/{id}/notifications?unread=true&page=1&page_size=20
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	b := bindRequest(r)
+	req := usecases.ListNotificationsRequest{UserID: b.PathID("id", "user id")}
+	b.QueryBool("unread", &req.UnreadOnly)
+	b.Pagination(&req.Page, &req.PageSize)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.listNotifications.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// MarkAsRead POST /users/{id}/notifications/{notificationID}/read
+func (h *NotificationHandler) MarkAsRead(w http.ResponseWriter, r *http.Request) {
+	b := bindRequest(r)
+	userID := b.PathID("id", "user id")
+	notificationID := b.PathID("notificationID", "notification id")
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.markAsRead.Execute(r.Context(), usecases.MarkAsReadRequest{
+		UserID:         userID,
+		NotificationID: notificationID,
+	})
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Stream GET /users/{id}/notifications/stream (Server-Sent Events) : à l'utilisateur {id}
+// ou à l'administration, comme la liste (401 sans jeton, 403 pour un autre utilisateur)
+func (h *NotificationHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+	if err := h.authorizer.Authorize(r.Context(), streamAction, usecases.ListNotificationsRequest{UserID: userID}); err != nil {
+		writeUseCaseError(w, http.StatusForbidden, err)
+		return
+	}
+
+	notifications, unsubscribe := h.stream.Subscribe(userID)
+	defer unsubscribe()
+
+	flusher, ok := startSSE(w)
+	if !ok {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case notification, open := <-notifications:
+			if !open {
+				return
+			}
+			data, err := json.Marshal(notification)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.ID, data)
+			flusher.Flush()
+		}
+	}
+}
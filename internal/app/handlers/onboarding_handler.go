@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// OnboardingHandler inscription en libre-service, confirmée par email
+type OnboardingHandler struct {
+	start   usecases.UseCase[usecases.StartOnboardingRequest, *usecases.OnboardingResponse]
+	confirm usecases.UseCase[usecases.ConfirmOnboardingRequest, *usecases.OnboardingResponse]
+	get     usecases.UseCase[string, *usecases.OnboardingResponse]
+}
+
+func NewOnboardingHandler(
+	start usecases.UseCase[usecases.StartOnboardingRequest, *usecases.OnboardingResponse],
+	confirm usecases.UseCase[usecases.ConfirmOnboardingRequest, *usecases.OnboardingResponse],
+	get usecases.UseCase[string, *usecases.OnboardingResponse],
+) *OnboardingHandler {
+	return &OnboardingHandler{start: start, confirm: confirm, get: get}
+}
+
+// Start POST /onboarding {"email", "name", "password"}
+// 202 awaiting_confirmation : le compte est créé, l'email de vérification envoyé ;
+// 422 si l'envoi a échoué (le compte est supprimé)
+func (h *OnboardingHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var req usecases.StartOnboardingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.start.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, onboardingErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, onboardingStatus(response), response)
+}
+
+// Confirm POST /onboarding/{id}/confirm {"token": "..."}
+// 200 completed ; 422 si une étape a échoué après la confirmation (l'inscription est annulée)
+func (h *OnboardingHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ConfirmOnboardingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.ID = r.PathValue("id")
+
+	response, err := h.confirm.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, onboardingErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, onboardingStatus(response), response)
+}
+
+// Get GET /onboarding/{id}
+func (h *OnboardingHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.get.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func onboardingStatus(response *usecases.OnboardingResponse) int {
+	if response.Status == usecases.OnboardingAwaitingConfirmation {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+// onboardingErrorStatus 422 pour une inscription annulée en cours de route, 400 sinon
+func onboardingErrorStatus(err error) int {
+	var sagaErr *usecases.SagaError
+	if errors.As(err, &sagaErr) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
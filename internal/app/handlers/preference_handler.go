@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"net/http"
+)
+
+// PreferenceHandler expose les préférences de l'utilisateur
+type PreferenceHandler struct {
+	updateDigest        usecases.UseCase[usecases.UpdateDigestPreferenceRequest, *usecases.UpdateDigestPreferenceResponse]
+	getNotifications    usecases.UseCase[int, *usecases.NotificationPreferencesResponse]
+	updateNotifications usecases.UseCase[usecases.UpdateNotificationPreferencesRequest, *usecases.NotificationPreferencesResponse]
+	updateDisplay       usecases.UseCase[usecases.UpdateDisplayPreferencesRequest, *usecases.UpdateDisplayPreferencesResponse]
+}
+
+func NewPreferenceHandler(
+	updateDigest usecases.UseCase[usecases.UpdateDigestPreferenceRequest, *usecases.UpdateDigestPreferenceResponse],
+	getNotifications usecases.UseCase[int, *usecases.NotificationPreferencesResponse],
+	updateNotifications usecases.UseCase[usecases.UpdateNotificationPreferencesRequest, *usecases.NotificationPreferencesResponse],
+	updateDisplay usecases.UseCase[usecases.UpdateDisplayPreferencesRequest, *usecases.UpdateDisplayPreferencesResponse],
+) *PreferenceHandler {
+	return &PreferenceHandler{
+		updateDigest:        updateDigest,
+		getNotifications:    getNotifications,
+		updateNotifications: updateNotifications,
+		updateDisplay:       updateDisplay,
+	}
+}
+
+// UpdateDigest PUT /users/{id}/preferences/digest
+func (h *PreferenceHandler) UpdateDigest(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.UpdateDigestPreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.UserID = id
+
+	response, err := h.updateDigest.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GetNotifications GET /users/{id}/preferences/notifications
+func (h *PreferenceHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	response, err := h.getNotifications.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// UpdateNotifications PUT /users/{id}/preferences/notifications
+func (h *PreferenceHandler) UpdateNotifications(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.UserID = id
+
+	response, err := h.updateNotifications.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// UpdateDisplay PUT /users/{id}/preferences/display {"locale": "fr-FR", "time_zone": "Europe/Paris"}
+// Valeurs vides = valeurs par défaut de l'instance
+func (h *PreferenceHandler) UpdateDisplay(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.UpdateDisplayPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.UserID = id
+
+	response, err := h.updateDisplay.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
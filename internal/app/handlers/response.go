@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrorResponse format commun des erreurs renvoyées par l'API
+type ErrorResponse struct {
+	Error string `json:"error"`
+	// Code identifiant stable d'erreur, pour les cas que le client doit distinguer (connexion refusée...)
+	Code string `json:"code,omitempty"`
+	// Parameter paramètre de chemin ou de requête invalide (code "invalid_parameter")
+	Parameter string `json:"parameter,omitempty"`
+	// RequestID permet au client de citer la requête au support (erreurs 500 uniquement)
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	writeEncoded(w, status, "application/json", body)
+}
+
+// maxPooledBufferSize au-delà (export, longue liste), le tampon n'est pas rendu au pool :
+// il garderait sa capacité pour toutes les petites requêtes suivantes
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool tampons des corps JSON, lus ou écrits, réutilisés d'une requête à l'autre
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() > maxPooledBufferSize {
+		return
+	}
+	buffer.Reset()
+	bufferPool.Put(buffer)
+}
+
+// writeEncoded encode le corps avant d'écrire l'en-tête : un corps non encodable donne une 500,
+// et non une 200 tronquée
+func writeEncoded(w http.ResponseWriter, status int, contentType string, body any) {
+	buffer := getBuffer()
+	defer putBuffer(buffer)
+
+	if err := json.NewEncoder(buffer).Encode(body); err != nil {
+		http.Error(w, `{"error":"internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(buffer.Bytes())
+}
+
+// decodeBody lit au plus limit octets du corps dans un tampon du pool puis les décode :
+// ni json.Decoder ni tampon de lecture alloués par requête
+func decodeBody(w http.ResponseWriter, r *http.Request, limit int64, target any) error {
+	buffer := getBuffer()
+	defer putBuffer(buffer)
+
+	if _, err := buffer.ReadFrom(http.MaxBytesReader(w, r.Body, limit)); err != nil {
+		return err
+	}
+	return json.Unmarshal(buffer.Bytes(), target)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+// authErrorCodes erreurs d'authentification, de quota et des règles de l'application hôte : statut HTTP et code exposé
+var authErrorCodes = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{usecases.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{usecases.ErrAccountDeactivated, http.StatusForbidden, "account_deactivated"},
+	{usecases.ErrAccountBanned, http.StatusForbidden, "account_banned"},
+	{usecases.ErrLoginBlocked, http.StatusForbidden, "login_blocked"},
+	{usecases.ErrInvalidLoginChallenge, http.StatusUnauthorized, "invalid_login_challenge"},
+	{usecases.ErrCaptchaRequired, http.StatusForbidden, "captcha_required"},
+	{usecases.ErrCaptchaFailed, http.StatusForbidden, "captcha_failed"},
+	{usecases.ErrTermsNotAccepted, http.StatusForbidden, "terms_not_accepted"},
+	{usecases.ErrInvalidSAMLResponse, http.StatusUnauthorized, "invalid_saml_response"},
+	{usecases.ErrExternalAccountNotLinked, http.StatusConflict, "account_not_linked"},
+	{usecases.ErrAuthenticationRequired, http.StatusUnauthorized, "authentication_required"},
+	{usecases.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{usecases.ErrImpersonationNotAllowed, http.StatusForbidden, "impersonation_forbidden"},
+	{usecases.ErrImpersonationScope, http.StatusForbidden, "impersonation_scope"},
+	{usecases.ErrQuotaExceeded, http.StatusTooManyRequests, "quota_exceeded"},
+	{usecases.ErrRejectedByHook, http.StatusUnprocessableEntity, "rejected_by_hook"},
+	{usecases.ErrContentRejected, http.StatusUnprocessableEntity, "content_rejected"},
+}
+
+// writeUseCaseError renvoie l'erreur d'un use case avec status, sauf dépassement de délai (504),
+// maintenance ou lecture seule (503 + Retry-After), vérification de connexion exigée (401) et
+// erreurs d'authentification, de quota ou d'un hook (401/403/429/422 avec leur code)
+func writeUseCaseError(w http.ResponseWriter, status int, err error) {
+	if writeUnavailable(w, err) || writeStepUpRequired(w, err) {
+		return
+	}
+	if errors.Is(err, usecases.ErrTimeout) {
+		status = http.StatusGatewayTimeout
+	}
+	for _, known := range authErrorCodes {
+		if errors.Is(err, known.err) {
+			writeJSON(w, known.status, ErrorResponse{Error: err.Error(), Code: known.code})
+			return
+		}
+	}
+	writeError(w, status, err.Error())
+}
+
+// writeUnavailable 503 avec Retry-After si le mode de disponibilité a refusé l'appel
+func writeUnavailable(w http.ResponseWriter, err error) bool {
+	var unavailable *usecases.UnavailableError
+	if !errors.As(err, &unavailable) {
+		return false
+	}
+	setRetryAfter(w, unavailable)
+	writeJSON(w, http.StatusServiceUnavailable, ErrorResponse{Error: err.Error(), Code: unavailable.Mode})
+	return true
+}
+
+// StepUpResponse connexion à confirmer avec le code envoyé par email (POST /auth/login/verify)
+type StepUpResponse struct {
+	Error       string    `json:"error"`
+	Code        string    `json:"code"`
+	ChallengeID string    `json:"challenge_id"`
+	Method      string    `json:"method"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// writeStepUpRequired 401 step_up_required si une règle de risque exige une vérification
+func writeStepUpRequired(w http.ResponseWriter, err error) bool {
+	var stepUp *usecases.StepUpRequiredError
+	if !errors.As(err, &stepUp) {
+		return false
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusUnauthorized, StepUpResponse{
+		Error:       err.Error(),
+		Code:        "step_up_required",
+		ChallengeID: stepUp.ChallengeID,
+		Method:      stepUp.Method,
+		ExpiresAt:   stepUp.ExpiresAt,
+	})
+	return true
+}
+
+func setRetryAfter(w http.ResponseWriter, unavailable *usecases.UnavailableError) {
+	if seconds := int(unavailable.RetryAfter.Seconds()); seconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+}
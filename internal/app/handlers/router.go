@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Handlers regroupe les handlers HTTP montés sur le routeur
+type Handlers struct {
+	User         *UserHandler
+	UserV2       *UserV2Handler
+	UserStatus   *UserStatusHandler
+	UserHandle   *UserHandleHandler
+	Inactivity   *InactivityHandler
+	UserSearch   *UserSearchHandler
+	UserSync     *UserSyncHandler
+	UserStats    *UserStatsHandler
+	Terms        *TermsHandler
+	Timeline     *TimelineHandler
+	Consent      *ConsentHandler
+	Session      *SessionHandler
+	Auth         *AuthHandler
+	Onboarding   *OnboardingHandler
+	SSO          *SSOHandler
+	SCIM         *SCIMHandler
+	UserBulk     *UserBulkHandler
+	Upload       *UploadHandler
+	Preference   *PreferenceHandler
+	Notification *NotificationHandler
+	Webhook      *WebhookHandler
+	Analytics    *AnalyticsHandler
+	EventQuery   *EventQueryHandler
+	Segment      *SegmentHandler
+	WriteKey     *WriteKeyHandler
+	// Collect ingestion publique authentifiée par clé d'écriture (/collect/)
+	Collect      *CollectHandler
+	Usage        *UsageHandler
+	Billing      *BillingHandler
+	Availability *AvailabilityHandler
+	LogLevel     *LogLevelHandler
+	Audit        *AuditHandler
+	Export       *ExportHandler
+	MailTemplate *EmailTemplateHandler
+	Alert        *AlertHandler
+	// Downloads vérification des liens signés des routes /downloads/
+	Downloads DownloadLinkVerifier
+	// Diagnostics pprof, expvar et profils sous /debug/ (nil quand ils sont servis sur DEBUG_ADDR)
+	Diagnostics http.Handler
+	// Realtime hub WebSocket (package ws)
+	Realtime http.Handler
+}
+
+// NewRouter déclare les routes de l'API
+//   - /v1/... : contrat historique, aussi servi sans préfixe pour les clients existants
+//   - /v2/... : nouveaux DTO (UUID, pagination par curseur) sur les mêmes use cases ;
+//     les routes non redéfinies en v2 sont encore absentes de cette version
+func NewRouter(h Handlers) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	if h.Diagnostics != nil {
+		mux.Handle("/debug/", h.Diagnostics)
+	}
+
+	// Administration de l'instance, hors versionnement de l'API
+	mux.HandleFunc("GET /admin/availability", h.Availability.Get)
+	mux.HandleFunc("PUT /admin/availability", h.Availability.Set)
+	mux.HandleFunc("GET /admin/log-level", h.LogLevel.Get)
+	mux.HandleFunc("PUT /admin/log-level", h.LogLevel.Set)
+	mux.HandleFunc("GET /admin/stats", h.UserStats.Get)
+	mux.HandleFunc("GET /admin/audit", h.Audit.List)
+	mux.HandleFunc("GET /admin/audit/export", h.Audit.Export)
+	mux.HandleFunc("POST /admin/audit/export/link", h.Audit.ExportLink)
+	mux.HandleFunc("GET /admin/consent-changes", h.Consent.List)
+	mux.HandleFunc("PUT /admin/email-templates/{name}", h.MailTemplate.Save)
+	mux.HandleFunc("GET /admin/email-templates/{name}/versions", h.MailTemplate.Versions)
+	mux.HandleFunc("POST /admin/email-templates/{name}/preview", h.MailTemplate.Preview)
+	mux.HandleFunc("POST /admin/alerts", h.Alert.Create)
+	mux.HandleFunc("GET /admin/alerts", h.Alert.List)
+	mux.HandleFunc("DELETE /admin/alerts/{id}", h.Alert.Delete)
+
+	// Téléchargements sur lien signé, hors versionnement : le chemin fait partie de la signature
+	mux.Handle("GET /downloads/audit", RequireSignedURL(http.HandlerFunc(h.Audit.Download), h.Downloads))
+	mux.Handle("GET /downloads/exports/{id}", RequireSignedURL(http.HandlerFunc(h.Export.Download), h.Downloads))
+
+	// SCIM 2.0 : chemin imposé par les fournisseurs d'identité, hors versionnement de l'API
+	mux.Handle("/scim/v2/", h.SCIM)
+	mux.Handle(collectPathPrefix, h.Collect)
+
+	v1 := newV1Router(h)
+	mux.Handle("/v1/", http.StripPrefix("/v1", v1))
+	mux.Handle("/", v1)
+
+	mux.HandleFunc("POST /v2/users", h.UserV2.Create)
+	mux.HandleFunc("GET /v2/users", h.UserV2.List)
+	mux.HandleFunc("GET /v2/users/{id}", h.UserV2.Get)
+	mux.HandleFunc("PUT /v2/users/{id}", h.UserV2.Update)
+	mux.HandleFunc("DELETE /v2/users/{id}", h.UserV2.Delete)
+
+	return mux
+}
+
+// newV1Router routes du contrat v1 (sans préfixe : NewRouter les monte aux deux endroits)
+func newV1Router(h Handlers) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /users", h.User.Create)
+	mux.HandleFunc("GET /users", h.User.List)
+	mux.HandleFunc("HEAD /users", h.User.Count)
+	mux.HandleFunc("GET /users/inactive", h.Inactivity.List)
+	mux.HandleFunc("GET /users/search", h.UserSearch.Search)
+	mux.HandleFunc("GET /users/{id}", userByIDOrHandle(h))
+	mux.HandleFunc("PUT /users/{id}", h.User.Update)
+	mux.HandleFunc("PATCH /users/{id}", h.User.Patch)
+	mux.HandleFunc("DELETE /users/{id}", h.User.Delete)
+	mux.HandleFunc("POST /users/{id}/deactivate", h.UserStatus.Deactivate)
+	mux.HandleFunc("POST /users/{id}/reactivate", h.UserStatus.Reactivate)
+	mux.HandleFunc("PUT /users/{id}/handle", h.UserHandle.Set)
+	mux.HandleFunc("GET /handles/{handle}/availability", h.UserHandle.Availability)
+	mux.HandleFunc("GET /users/{id}/terms", h.Terms.Status)
+	mux.HandleFunc("POST /users/{id}/terms", h.Terms.Accept)
+	mux.HandleFunc("GET /users/{id}/timeline", h.Timeline.Get)
+	mux.HandleFunc("GET /users/{id}/consent", h.Consent.Get)
+	mux.HandleFunc("PUT /users/{id}/consent", h.Consent.Record)
+	mux.HandleFunc("GET /users/{id}/sessions", h.Session.List)
+	mux.HandleFunc("POST /users/bulk", h.UserBulk.Create)
+	mux.HandleFunc("PUT /users/bulk", h.UserBulk.Update)
+	mux.HandleFunc("POST /users/bulk/delete", h.UserBulk.Delete)
+	mux.HandleFunc("POST /users/import", h.UserBulk.Import)
+	mux.HandleFunc("POST /uploads", h.Upload.Create)
+	mux.HandleFunc("GET /uploads/{id}", h.Upload.Get)
+	mux.HandleFunc("PUT /uploads/{id}/chunks/{index}", h.Upload.Chunk)
+	mux.HandleFunc("POST /uploads/{id}/complete", h.Upload.Complete)
+	mux.HandleFunc("POST /exports", h.Export.Start)
+	mux.HandleFunc("GET /exports/{id}", h.Export.Status)
+	mux.HandleFunc("GET /exports/{id}/download", h.Export.Download)
+	mux.HandleFunc("POST /sync/users", h.UserSync.Sync)
+	mux.HandleFunc("PUT /users/{id}/preferences/digest", h.Preference.UpdateDigest)
+	mux.HandleFunc("PUT /users/{id}/preferences/display", h.Preference.UpdateDisplay)
+	mux.HandleFunc("GET /users/{id}/preferences/notifications", h.Preference.GetNotifications)
+	mux.HandleFunc("PUT /users/{id}/preferences/notifications", h.Preference.UpdateNotifications)
+	mux.HandleFunc("GET /users/{id}/notifications", h.Notification.List)
+	mux.HandleFunc("GET /users/{id}/notifications/stream", h.Notification.Stream)
+	mux.HandleFunc("POST /users/{id}/notifications/{notificationID}/read", h.Notification.MarkAsRead)
+
+	mux.HandleFunc("POST /auth/login", h.Auth.Login)
+	mux.HandleFunc("POST /auth/login/verify", h.Auth.VerifyLogin)
+	mux.HandleFunc("POST /auth/logout", h.Auth.Logout)
+	mux.HandleFunc("POST /onboarding", h.Onboarding.Start)
+	mux.HandleFunc("GET /onboarding/{id}", h.Onboarding.Get)
+	mux.HandleFunc("POST /onboarding/{id}/confirm", h.Onboarding.Confirm)
+	mux.HandleFunc("POST /users/{id}/impersonate", h.Auth.Impersonate)
+	mux.HandleFunc("PUT /tenants/{tenant}/identity-provider", h.SSO.ConfigureIdentityProvider)
+	mux.HandleFunc("GET /tenants/{tenant}/identity-provider", h.SSO.GetIdentityProvider)
+	mux.HandleFunc("GET /tenants/{tenant}/usage", h.Usage.Get)
+	mux.HandleFunc("POST /tenants/{tenant}/subscription", h.Billing.Subscribe)
+	mux.HandleFunc("GET /tenants/{tenant}/subscription", h.Billing.Get)
+	mux.HandleFunc("POST /tenants/{tenant}/write-keys", h.WriteKey.Create)
+	mux.HandleFunc("GET /tenants/{tenant}/write-keys", h.WriteKey.List)
+	mux.HandleFunc("POST /tenants/{tenant}/write-keys/{id}/rotate", h.WriteKey.Rotate)
+	mux.HandleFunc("DELETE /tenants/{tenant}/write-keys/{id}", h.WriteKey.Revoke)
+	mux.HandleFunc("GET /saml/{tenant}/metadata", h.SSO.Metadata)
+	mux.HandleFunc("GET /saml/{tenant}/login", h.SSO.Login)
+	mux.HandleFunc("POST /saml/{tenant}/acs", h.SSO.AssertionConsumer)
+
+	mux.Handle("POST /webhooks/{source}", h.Webhook)
+
+	mux.HandleFunc("POST /analytics/track", h.Analytics.Track)
+	mux.HandleFunc("GET /analytics/stream", h.Analytics.Stream)
+	mux.HandleFunc("GET /analytics/events", h.Analytics.SearchEvents)
+	mux.HandleFunc("GET /analytics/rollups", h.Analytics.Rollups)
+	mux.HandleFunc("GET /analytics/query", h.EventQuery.Query)
+	mux.HandleFunc("POST /analytics/reports", h.EventQuery.CreateReport)
+	mux.HandleFunc("GET /analytics/reports", h.EventQuery.ListReports)
+	mux.HandleFunc("GET /analytics/reports/{id}/run", h.EventQuery.RunReport)
+	mux.HandleFunc("DELETE /analytics/reports/{id}", h.EventQuery.DeleteReport)
+
+	// API HTTP Segment : servie sous /v1, comme api.segment.io, et authentifiée par clé d'écriture
+	// comme /collect/v1 (le jeton Bearer éventuel est ignoré)
+	mux.Handle("POST /track", h.Collect.RequireWriteKey(h.Segment.Track))
+	mux.Handle("POST /page", h.Collect.RequireWriteKey(h.Segment.Page))
+	mux.Handle("POST /identify", h.Collect.RequireWriteKey(h.Segment.Identify))
+	mux.Handle("POST /batch", h.Collect.RequireWriteKey(h.Segment.Batch))
+	mux.Handle("POST /import", h.Collect.RequireWriteKey(h.Segment.Batch))
+
+	mux.Handle("GET /ws", h.Realtime)
+
+	return mux
+}
+
+// userByIDOrHandle /users/42 et /users/@alice partagent le même segment de chemin
+// (ServeMux ne sait pas filtrer sur un préfixe littéral : "@" n'est jamais un ID valide)
+func userByIDOrHandle(h Handlers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.PathValue("id"), "@") {
+			h.UserHandle.Get(w, r)
+			return
+		}
+		h.User.Get(w, r)
+	}
+}
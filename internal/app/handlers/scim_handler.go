@@ -0,0 +1,436 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// API SCIM 2.0 (RFC 7643 / 7644) : PROVISIONNEMENT PAR LES FOURNISSEURS D'IDENTITÉ
+// =============================================================================
+
+const (
+	scimContentType     = "application/scim+json"
+	scimUserSchemaURN   = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimListSchemaURN   = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimPatchSchemaURN  = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimErrorSchemaURN  = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimConfigSchemaURN = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	maxSCIMBodySize     = 1 << 20 // 1 MiB
+)
+
+// SCIMHandler sert /scim/v2/... ; le client s'authentifie par le jeton Bearer configuré
+// pour le provisionnement (SCIM_BEARER_TOKEN), distinct des jetons d'accès utilisateurs
+type SCIMHandler struct {
+	token   string
+	create  usecases.UseCase[usecases.SCIMUserRequest, *usecases.SCIMUserResponse]
+	get     usecases.UseCase[int, *usecases.SCIMUserResponse]
+	replace usecases.UseCase[usecases.ReplaceSCIMUserRequest, *usecases.SCIMUserResponse]
+	patch   usecases.UseCase[usecases.PatchSCIMUserRequest, *usecases.SCIMUserResponse]
+	delete  usecases.UseCase[int, struct{}]
+	list    usecases.UseCase[usecases.ListSCIMUsersRequest, *usecases.ListSCIMUsersResponse]
+	mux     *http.ServeMux
+}
+
+func NewSCIMHandler(
+	token string,
+	create usecases.UseCase[usecases.SCIMUserRequest, *usecases.SCIMUserResponse],
+	get usecases.UseCase[int, *usecases.SCIMUserResponse],
+	replace usecases.UseCase[usecases.ReplaceSCIMUserRequest, *usecases.SCIMUserResponse],
+	patch usecases.UseCase[usecases.PatchSCIMUserRequest, *usecases.SCIMUserResponse],
+	delete usecases.UseCase[int, struct{}],
+	list usecases.UseCase[usecases.ListSCIMUsersRequest, *usecases.ListSCIMUsersResponse],
+) *SCIMHandler {
+	h := &SCIMHandler{
+		token:   token,
+		create:  create,
+		get:     get,
+		replace: replace,
+		patch:   patch,
+		delete:  delete,
+		list:    list,
+		mux:     http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /scim/v2/ServiceProviderConfig", h.serviceProviderConfig)
+	h.mux.HandleFunc("GET /scim/v2/Users", h.listUsers)
+	h.mux.HandleFunc("POST /scim/v2/Users", h.createUser)
+	h.mux.HandleFunc("GET /scim/v2/Users/{id}", h.getUser)
+	h.mux.HandleFunc("PUT /scim/v2/Users/{id}", h.replaceUser)
+	h.mux.HandleFunc("PATCH /scim/v2/Users/{id}", h.patchUser)
+	h.mux.HandleFunc("DELETE /scim/v2/Users/{id}", h.deleteUser)
+	h.mux.HandleFunc("/scim/v2/", func(w http.ResponseWriter, r *http.Request) {
+		writeSCIMError(w, http.StatusNotFound, "", "ressource SCIM inconnue")
+	})
+	return h
+}
+
+// ServeHTTP sans jeton configuré, le provisionnement est désactivé (503)
+func (h *SCIMHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token == "" {
+		writeSCIMError(w, http.StatusServiceUnavailable, "", "provisionnement SCIM désactivé")
+		return
+	}
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+		writeSCIMError(w, http.StatusUnauthorized, "", "jeton SCIM invalide")
+		return
+	}
+	// Le client SCIM (IdP) n'est pas un utilisateur : les politiques le voient comme le système
+	h.mux.ServeHTTP(w, r.WithContext(usecases.ContextWithActor(r.Context(), usecases.SystemActor)))
+}
+
+// =============================================================================
+// REPRÉSENTATIONS SCIM
+// =============================================================================
+
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type scimMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// scimUser ressource User ; Password n'est lu qu'en entrée (jamais renvoyé)
+type scimUser struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *scimName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []scimEmail `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Password    string      `json:"password,omitempty"`
+	Meta        *scimMeta   `json:"meta,omitempty"`
+}
+
+type scimListResponse struct {
+	Schemas      []string   `json:"schemas"`
+	TotalResults int        `json:"totalResults"`
+	StartIndex   int        `json:"startIndex"`
+	ItemsPerPage int        `json:"itemsPerPage"`
+	Resources    []scimUser `json:"Resources"`
+}
+
+type scimPatchRequest struct {
+	Schemas    []string `json:"schemas"`
+	Operations []struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	} `json:"Operations"`
+}
+
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+func toSCIMUser(r *http.Request, user *usecases.SCIMUserResponse) scimUser {
+	id := strconv.Itoa(user.ID)
+	active := user.Active
+	return scimUser{
+		Schemas:     []string{scimUserSchemaURN},
+		ID:          id,
+		ExternalID:  user.ExternalID,
+		UserName:    user.UserName,
+		Name:        &scimName{Formatted: user.DisplayName},
+		DisplayName: user.DisplayName,
+		Emails:      []scimEmail{{Value: user.UserName, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &scimMeta{
+			ResourceType: "User",
+			Created:      user.Created,
+			LastModified: user.Updated,
+			Location:     scimBaseURL(r) + "/scim/v2/Users/" + id,
+		},
+	}
+}
+
+// scimBaseURL meta.location doit être absolue : elle est construite sur l'hôte appelé
+func scimBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// toSCIMUserRequest displayName, à défaut name.formatted ou prénom + nom ; active par défaut
+func (u scimUser) toSCIMUserRequest() usecases.SCIMUserRequest {
+	displayName := u.DisplayName
+	if displayName == "" && u.Name != nil {
+		displayName = u.Name.Formatted
+		if displayName == "" {
+			displayName = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+	return usecases.SCIMUserRequest{
+		UserName:    u.UserName,
+		ExternalID:  u.ExternalID,
+		DisplayName: displayName,
+		Active:      u.Active == nil || *u.Active,
+		Password:    u.Password,
+	}
+}
+
+// =============================================================================
+// ROUTES
+// =============================================================================
+
+// listUsers GET /scim/v2/Users?filter=userName eq "x"&startIndex=1&count=100
+func (h *SCIMHandler) listUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := usecases.ListSCIMUsersRequest{Filter: query.Get("filter")}
+	if raw := query.Get("startIndex"); raw != "" {
+		startIndex, err := strconv.Atoi(raw)
+		if err != nil {
+			writeSCIMError(w, http.StatusBadRequest, "invalidValue", "startIndex invalide")
+			return
+		}
+		req.StartIndex = startIndex
+	}
+	if raw := query.Get("count"); raw != "" {
+		count, err := strconv.Atoi(raw)
+		if err != nil {
+			writeSCIMError(w, http.StatusBadRequest, "invalidValue", "count invalide")
+			return
+		}
+		req.Count = &count
+	}
+
+	response, err := h.list.Execute(r.Context(), req)
+	if err != nil {
+		writeSCIMUseCaseError(w, err)
+		return
+	}
+
+	list := scimListResponse{
+		Schemas:      []string{scimListSchemaURN},
+		TotalResults: response.TotalResults,
+		StartIndex:   response.StartIndex,
+		ItemsPerPage: len(response.Resources),
+		Resources:    make([]scimUser, 0, len(response.Resources)),
+	}
+	for _, user := range response.Resources {
+		list.Resources = append(list.Resources, toSCIMUser(r, user))
+	}
+	writeSCIM(w, http.StatusOK, list)
+}
+
+// createUser POST /scim/v2/Users : 409 uniqueness si userName ou externalId est déjà pris
+func (h *SCIMHandler) createUser(w http.ResponseWriter, r *http.Request) {
+	var resource scimUser
+	if !decodeSCIM(w, r, &resource) {
+		return
+	}
+
+	user, err := h.create.Execute(r.Context(), resource.toSCIMUserRequest())
+	if err != nil {
+		writeSCIMUseCaseError(w, err)
+		return
+	}
+
+	created := toSCIMUser(r, user)
+	w.Header().Set("Location", created.Meta.Location)
+	writeSCIM(w, http.StatusCreated, created)
+}
+
+func (h *SCIMHandler) getUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.get.Execute(r.Context(), id)
+	if err != nil {
+		writeSCIMUseCaseError(w, err)
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMUser(r, user))
+}
+
+// replaceUser PUT /scim/v2/Users/{id} : un attribut absent est effacé (externalId) ou remis
+// à sa valeur par défaut (active)
+func (h *SCIMHandler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+	var resource scimUser
+	if !decodeSCIM(w, r, &resource) {
+		return
+	}
+
+	user, err := h.replace.Execute(r.Context(), usecases.ReplaceSCIMUserRequest{ID: id, SCIMUserRequest: resource.toSCIMUserRequest()})
+	if err != nil {
+		writeSCIMUseCaseError(w, err)
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMUser(r, user))
+}
+
+// patchUser PATCH /scim/v2/Users/{id} (PatchOp : add, replace, remove)
+func (h *SCIMHandler) patchUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+	var body scimPatchRequest
+	if !decodeSCIM(w, r, &body) {
+		return
+	}
+	if len(body.Schemas) > 0 && !containsFold(body.Schemas, scimPatchSchemaURN) {
+		writeSCIMError(w, http.StatusBadRequest, "invalidSyntax", "schéma PatchOp attendu")
+		return
+	}
+
+	req := usecases.PatchSCIMUserRequest{ID: id}
+	for _, operation := range body.Operations {
+		req.Operations = append(req.Operations, usecases.SCIMPatchOperation{Op: operation.Op, Path: operation.Path, Value: operation.Value})
+	}
+	user, err := h.patch.Execute(r.Context(), req)
+	if err != nil {
+		writeSCIMUseCaseError(w, err)
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMUser(r, user))
+}
+
+// deleteUser DELETE /scim/v2/Users/{id} : le compte est supprimé (déprovisionnement)
+func (h *SCIMHandler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.delete.Execute(r.Context(), id); err != nil {
+		writeSCIMUseCaseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serviceProviderConfig capacités annoncées aux clients (RFC 7643 §5)
+func (h *SCIMHandler) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	supported := func(value bool) map[string]bool { return map[string]bool{"supported": value} }
+	writeSCIM(w, http.StatusOK, map[string]any{
+		"schemas":        []string{scimConfigSchemaURN},
+		"patch":          supported(true),
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": usecases.SCIMMaxResults},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Jeton de provisionnement dans l'en-tête Authorization",
+			"primary":     true,
+		}},
+	})
+}
+
+// =============================================================================
+// ENCODAGE ET ERREURS
+// =============================================================================
+
+func scimUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeSCIMError(w, http.StatusNotFound, "", "utilisateur inconnu")
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeSCIM(w http.ResponseWriter, r *http.Request, target any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSCIMBodySize)).Decode(target); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidSyntax", "JSON invalide")
+		return false
+	}
+	return true
+}
+
+func writeSCIM(w http.ResponseWriter, status int, body any) {
+	writeEncoded(w, status, scimContentType, body)
+}
+
+func writeSCIMError(w http.ResponseWriter, status int, scimType, detail string) {
+	writeSCIM(w, status, scimError{
+		Schemas:  []string{scimErrorSchemaURN},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// scimErrorTypes erreurs métier : statut HTTP et scimType (RFC 7644 §3.12)
+var scimErrorTypes = []struct {
+	err      error
+	status   int
+	scimType string
+}{
+	{repositories.ErrUserNotFound, http.StatusNotFound, ""},
+	{usecases.ErrTimeout, http.StatusGatewayTimeout, ""},
+	{usecases.ErrInvalidSCIMFilter, http.StatusBadRequest, "invalidFilter"},
+	{usecases.ErrInvalidSCIMPatch, http.StatusBadRequest, "invalidSyntax"},
+	{usecases.ErrSCIMUserNameTaken, http.StatusConflict, "uniqueness"},
+	{usecases.ErrSCIMExternalIDTaken, http.StatusConflict, "uniqueness"},
+	{usecases.ErrForbidden, http.StatusForbidden, ""},
+	{usecases.ErrAuthenticationRequired, http.StatusForbidden, ""},
+}
+
+// writeSCIMUseCaseError les erreurs techniques (usecases.Error) sont des 500, les autres
+// des valeurs refusées par le domaine (400 invalidValue)
+func writeSCIMUseCaseError(w http.ResponseWriter, err error) {
+	var unavailable *usecases.UnavailableError
+	if errors.As(err, &unavailable) {
+		setRetryAfter(w, unavailable)
+		writeSCIMError(w, http.StatusServiceUnavailable, "", err.Error())
+		return
+	}
+	for _, known := range scimErrorTypes {
+		if errors.Is(err, known.err) {
+			writeSCIMError(w, known.status, known.scimType, err.Error())
+			return
+		}
+	}
+	var technical *usecases.Error
+	if errors.As(err, &technical) {
+		writeSCIMError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	writeSCIMError(w, http.StatusBadRequest, "invalidValue", err.Error())
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
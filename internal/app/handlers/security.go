@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// CORS
+// =============================================================================
+
+// CORSOptions politique CORS ; sans origine autorisée, aucun en-tête CORS n'est émis
+type CORSOptions struct {
+	// AllowedOrigins origines exactes ("https://app.example.com"), sous-domaines
+	// ("https://*.example.com") ou "*" (interdit avec AllowCredentials)
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge durée de mise en cache du pré-vol par le navigateur
+	MaxAge time.Duration
+}
+
+// corsExposedHeaders en-têtes de réponse lisibles par le JavaScript du client
+var corsExposedHeaders = strings.Join([]string{"ETag", "Location", requestIDHeader}, ", ")
+
+// CORS répond aux requêtes de pré-vol (OPTIONS) et ajoute les en-têtes CORS aux réponses
+// destinées à une origine autorisée. Une origine refusée ne reçoit aucun en-tête :
+// le navigateur bloque alors la réponse ; le pré-vol refusé répond 403
+// L'ingestion publique (/collect/) a sa propre politique, ouverte à toute origine (CollectHandler)
+func CORS(next http.Handler, opts CORSOptions) http.Handler {
+	if len(opts.AllowedOrigins) == 0 {
+		return next
+	}
+
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if origin == "" || isCollectPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		if !originAllowed(origin, opts.AllowedOrigins) {
+			if preflight {
+				writeError(w, http.StatusForbidden, "origin not allowed")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Avec credentials, le navigateur exige l'origine exacte (jamais "*")
+		if opts.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		} else if len(opts.AllowedOrigins) == 1 && opts.AllowedOrigins[0] == "*" {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Set("Access-Control-Allow-Methods", methods)
+		header.Set("Access-Control-Allow-Headers", headers)
+		header.Set("Access-Control-Max-Age", maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func originAllowed(origin string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+		// "https://*.example.com" : un seul niveau de joker, schéma identique, domaine nu exclu
+		if scheme, domain, found := strings.Cut(pattern, "://*."); found {
+			rest, ok := strings.CutPrefix(strings.ToLower(origin), strings.ToLower(scheme)+"://")
+			if ok && strings.HasSuffix(rest, "."+strings.ToLower(domain)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// EN-TÊTES DE SÉCURITÉ
+// =============================================================================
+
+// SecurityHeadersOptions en-têtes de sécurité ajoutés à chaque réponse
+type SecurityHeadersOptions struct {
+	// HSTSMaxAge durée de Strict-Transport-Security ; 0 = en-tête absent (développement en HTTP)
+	HSTSMaxAge time.Duration
+	// ContentSecurityPolicy politique par défaut : une API JSON ne charge aucune ressource
+	ContentSecurityPolicy string
+	// PathPolicies politique par préfixe de chemin (ex: "/docs/" pour Swagger UI, qui a besoin
+	// de scripts, styles et images) ; le préfixe le plus long l'emporte
+	PathPolicies map[string]string
+}
+
+// SecurityHeaders pose HSTS, X-Content-Type-Options, X-Frame-Options, Referrer-Policy et la CSP
+// Les en-têtes sont posés avant le handler, qui peut les surcharger pour une réponse donnée
+func SecurityHeaders(next http.Handler, opts SecurityHeadersOptions) http.Handler {
+	var hsts string
+	if opts.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(opts.HSTSMaxAge.Seconds())) + "; includeSubDomains"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		if hsts != "" {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		if policy := contentSecurityPolicy(r.URL.Path, opts); policy != "" {
+			header.Set("Content-Security-Policy", policy)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func contentSecurityPolicy(path string, opts SecurityHeadersOptions) string {
+	policy, matched := opts.ContentSecurityPolicy, ""
+	for prefix, candidate := range opts.PathPolicies {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(matched) {
+			policy, matched = candidate, prefix
+		}
+	}
+	return policy
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxSegmentMessageSize / maxSegmentBatchSize limites de l'API HTTP Segment (32 Ko par message,
+	// 500 Ko par lot)
+	maxSegmentMessageSize = 32 << 10
+	maxSegmentBatchSize   = 500 << 10
+)
+
+// Types de messages Segment traduits en événements ; screen, group et alias sont ignorés
+const (
+	segmentTrack    = "track"
+	segmentPage     = "page"
+	segmentIdentify = "identify"
+)
+
+// Noms des événements produits par page et identify (track garde le nom envoyé, normalisé)
+const (
+	segmentPageEvent     = "page_viewed"
+	segmentIdentifyEvent = "user_identified"
+)
+
+var errSegmentEventName = errors.New("event cannot be converted to an event name")
+
+// SegmentHandler ingestion au format de l'API HTTP Segment (POST /v1/track, /v1/page, /v1/identify,
+// /v1/batch) : les SDK existants pointent sur ce service sans modification. Chaque message devient
+// un TrackEventRequest, soumis aux mêmes règles que POST /analytics/track (consentement, échantillonnage)
+// Les routes sont servies derrière la clé d'écriture (CollectHandler.RequireWriteKey) : l'événement
+// est rattaché au tenant de la clé ; userId et anonymousId ne sont que des propriétés (user_id,
+// anonymous_id), empreintées par l'EventScrubber
+type SegmentHandler struct {
+	track usecases.UseCase[usecases.TrackEventRequest, *usecases.TrackEventResponse]
+	clock usecases.Clock
+}
+
+func NewSegmentHandler(track usecases.UseCase[usecases.TrackEventRequest, *usecases.TrackEventResponse], clock usecases.Clock) *SegmentHandler {
+	return &SegmentHandler{track: track, clock: clock}
+}
+
+// segmentMessage message Segment ; seuls les champs utiles à l'événement sont lus (context ignoré)
+type segmentMessage struct {
+	Type        string         `json:"type"`
+	UserID      any            `json:"userId"`
+	AnonymousID any            `json:"anonymousId"`
+	Event       string         `json:"event"`
+	Name        string         `json:"name"`
+	Category    string         `json:"category"`
+	Properties  map[string]any `json:"properties"`
+	Traits      map[string]any `json:"traits"`
+	Timestamp   string         `json:"timestamp"`
+	SentAt      string         `json:"sentAt"`
+}
+
+type segmentBatch struct {
+	Batch  []segmentMessage `json:"batch"`
+	SentAt string           `json:"sentAt"`
+}
+
+// SegmentResponse réponse attendue par les SDK ; Rejected messages d'un lot écartés car invalides
+type SegmentResponse struct {
+	Success  bool `json:"success"`
+	Rejected int  `json:"rejected,omitempty"`
+}
+
+// Track POST /v1/track {"userId", "anonymousId", "event", "properties", "timestamp", "sentAt"}
+func (h *SegmentHandler) Track(w http.ResponseWriter, r *http.Request) {
+	h.single(w, r, segmentTrack)
+}
+
+// Page POST /v1/page {"userId", "anonymousId", "name", "category", "properties", ...} : événement page_viewed
+func (h *SegmentHandler) Page(w http.ResponseWriter, r *http.Request) {
+	h.single(w, r, segmentPage)
+}
+
+// Identify POST /v1/identify {"userId", "anonymousId", "traits", ...} : événement user_identified
+func (h *SegmentHandler) Identify(w http.ResponseWriter, r *http.Request) {
+	h.single(w, r, segmentIdentify)
+}
+
+func (h *SegmentHandler) single(w http.ResponseWriter, r *http.Request, messageType string) {
+	var message segmentMessage
+	if err := decodeBody(w, r, maxSegmentMessageSize, &message); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	message.Type = messageType
+
+	req, err := message.trackRequest(h.clock.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := h.track.Execute(r.Context(), req); err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, SegmentResponse{Success: true})
+}
+
+// Batch POST /v1/batch {"batch": [...], "sentAt"} (aussi /v1/import)
+// Un message invalide est écarté sans bloquer les autres : le SDK ne renverrait que le même lot.
+// Une erreur d'enregistrement interrompt le lot, que le SDK réessaie (les messages déjà enregistrés
+// le seront deux fois : messageId n'est pas dédoublonné)
+func (h *SegmentHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var batch segmentBatch
+	if err := decodeBody(w, r, maxSegmentBatchSize, &batch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	received := h.clock.Now()
+	response := SegmentResponse{Success: true}
+	for _, message := range batch.Batch {
+		if !slices.Contains([]string{segmentTrack, segmentPage, segmentIdentify}, message.Type) {
+			continue
+		}
+		if message.SentAt == "" {
+			message.SentAt = batch.SentAt
+		}
+		req, err := message.trackRequest(received)
+		if err == nil {
+			err = req.Validate()
+		}
+		if err != nil {
+			response.Rejected++
+			continue
+		}
+		if _, err := h.track.Execute(r.Context(), req); err != nil {
+			// Horodatage vérifié contre l'horloge du serveur à l'exécution : écarté comme un message invalide
+			if errors.Is(err, usecases.ErrTrackTimestampInFuture) {
+				response.Rejected++
+				continue
+			}
+			writeUseCaseError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// trackRequest traduction du message : nom normalisé (Order Completed → order_completed),
+// propriétés aplaties (product.id → product_id) et ramenées aux clés et valeurs acceptées,
+// horodatage corrigé du décalage d'horloge du client (sentAt → received)
+func (m segmentMessage) trackRequest(received time.Time) (usecases.TrackEventRequest, error) {
+	req := usecases.TrackEventRequest{Timestamp: segmentTimestamp(m.Timestamp, m.SentAt, received)}
+
+	identity := make(map[string]any, 4)
+	if userID := segmentIdentifier(m.UserID); userID != "" {
+		identity["user_id"] = userID
+	}
+	if anonymousID := segmentIdentifier(m.AnonymousID); anonymousID != "" {
+		identity["anonymous_id"] = anonymousID
+	}
+
+	source := m.Properties
+	switch m.Type {
+	case segmentTrack:
+		if m.Event != "" {
+			req.Event = segmentEventName(m.Event)
+			if req.Event == "" {
+				return req, errSegmentEventName
+			}
+		}
+	case segmentPage:
+		req.Event = segmentPageEvent
+		if m.Name != "" {
+			identity["name"] = truncateSegmentValue(m.Name)
+		}
+		if m.Category != "" {
+			identity["category"] = truncateSegmentValue(m.Category)
+		}
+	case segmentIdentify:
+		req.Event = segmentIdentifyEvent
+		source = m.Traits
+	}
+
+	properties := make(map[string]any, len(source)+len(identity))
+	flattenSegmentProperties(properties, "", source)
+	for key := range properties {
+		if !entities.IsEventPropertyKey(key) {
+			delete(properties, key)
+		}
+	}
+	// Au-delà de la limite, les premières clés dans l'ordre alphabétique sont gardées :
+	// un même message donne toujours le même événement
+	if limit := entities.MaxEventProperties - len(identity); len(properties) > limit {
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys[limit:] {
+			delete(properties, key)
+		}
+	}
+	for key, value := range identity {
+		properties[key] = value
+	}
+	if len(properties) > 0 {
+		req.Properties = properties
+	}
+	return req, nil
+}
+
+// flattenSegmentProperties objets imbriqués aplatis (parent_enfant) ; tableaux et null ignorés
+func flattenSegmentProperties(target map[string]any, prefix string, source map[string]any) {
+	for raw, value := range source {
+		key := segmentKey(raw)
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch typed := value.(type) {
+		case map[string]any:
+			if len(key) < 64 {
+				flattenSegmentProperties(target, key, typed)
+			}
+		case string:
+			target[key] = truncateSegmentValue(typed)
+		case float64, bool:
+			target[key] = typed
+		}
+	}
+}
+
+// segmentEventName nom Segment ramené au format des événements ; vide si rien n'en reste
+func segmentEventName(event string) string {
+	segments := strings.Split(event, ".")
+	for i, segment := range segments {
+		segments[i] = segmentKey(segment)
+	}
+	name := strings.Join(segments, ".")
+	if entities.ValidateEventName(name) != nil {
+		return ""
+	}
+	return name
+}
+
+// segmentKey clé en snake_case (productId → product_id, "Order ID" → order_id) : minuscules,
+// chiffres et _, premier caractère alphabétique ; vide si rien n'en reste
+func segmentKey(raw string) string {
+	var key strings.Builder
+	afterLower := false
+	for _, char := range raw {
+		switch {
+		case char >= 'A' && char <= 'Z':
+			if afterLower {
+				key.WriteByte('_')
+			}
+			key.WriteRune(char + ('a' - 'A'))
+			afterLower = false
+		case char >= 'a' && char <= 'z', char >= '0' && char <= '9':
+			key.WriteRune(char)
+			afterLower = true
+		default:
+			if key.Len() > 0 && !strings.HasSuffix(key.String(), "_") {
+				key.WriteByte('_')
+			}
+			afterLower = false
+		}
+	}
+	return strings.TrimRight(strings.TrimLeft(key.String(), "0123456789_"), "_")
+}
+
+// segmentIdentifier userId / anonymousId : texte ou nombre selon les SDK
+func segmentIdentifier(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return truncateSegmentValue(typed)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	}
+	return ""
+}
+
+// truncateSegmentValue valeur texte tronquée à la longueur acceptée, sans couper de caractère
+func truncateSegmentValue(value string) string {
+	if len(value) <= entities.MaxEventPropertyLength {
+		return value
+	}
+	cut := entities.MaxEventPropertyLength
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut]
+}
+
+// segmentTimestamp horodatage client décalé de l'écart entre sentAt et la réception, comme le fait
+// Segment ; un horodatage illisible est transmis tel quel (refusé par la validation)
+func segmentTimestamp(timestamp, sentAt string, received time.Time) string {
+	if timestamp == "" {
+		return ""
+	}
+	occurredAt, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	if sent, err := time.Parse(time.RFC3339, sentAt); err == nil {
+		occurredAt = occurredAt.Add(received.Sub(sent))
+	}
+	return occurredAt.Format(time.RFC3339Nano)
+}
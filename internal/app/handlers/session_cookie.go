@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// MODE SESSION : jeton d'accès dans un cookie HttpOnly (clients navigateur)
+// =============================================================================
+
+// SessionCookieOptions cookie de session (SESSION_COOKIE) posé à la connexion ; Name vide = pas
+// de mode session, le jeton n'est rendu que dans la réponse. Les requêtes authentifiées par ce
+// cookie passent par la protection CSRF
+type SessionCookieOptions struct {
+	Name string
+	// Secure pose le cookie avec l'attribut Secure (HTTPS)
+	Secure bool
+}
+
+// set pose le jeton d'accès jusqu'à son expiration ; HttpOnly : le JavaScript ne le lit jamais
+func (o SessionCookieOptions) set(w http.ResponseWriter, token string, expires time.Time) {
+	if o.Name == "" || token == "" {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     o.Name,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   o.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// clear retire le cookie du navigateur ; le jeton reste valide jusqu'à son expiration
+func (o SessionCookieOptions) clear(w http.ResponseWriter) {
+	if o.Name == "" {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     o.Name,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   o.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// accessToken jeton Bearer, à défaut celui du cookie de session. Un en-tête Bearer, même
+// invalide, n'est jamais complété par le cookie : CSRF exempte ces requêtes
+func accessToken(r *http.Request, sessionCookie string) (string, bool) {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		return token, true
+	}
+	if sessionCookie == "" {
+		return "", false
+	}
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"net/http"
+)
+
+// SessionHandler historique des connexions d'un utilisateur : appareil, adresse, localisation
+type SessionHandler struct {
+	list usecases.UseCase[usecases.ListSessionsRequest, *usecases.ListSessionsResponse]
+}
+
+func NewSessionHandler(list usecases.UseCase[usecases.ListSessionsRequest, *usecases.ListSessionsResponse]) *SessionHandler {
+	return &SessionHandler{list: list}
+}
+
+// List GET /users/{id}/sessions?limit=
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	b := bindRequest(r)
+	req := usecases.ListSessionsRequest{UserID: b.PathID("id", "user id")}
+	b.QueryInt("limit", &req.Limit, 1, 100)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.list.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
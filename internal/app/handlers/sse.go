@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+)
+
+// sseHeartbeat intervalle des commentaires SSE qui gardent la connexion ouverte derrière les proxies
+const sseHeartbeat = 25 * time.Second
+
+// startSSE envoie les en-têtes Server-Sent Events ; retourne false si le flux est impossible
+func startSSE(w http.ResponseWriter) (http.Flusher, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return flusher, true
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// SSOHandler SSO SAML 2.0 des tenants : configuration de l'IdP, métadonnées SP,
+// départ vers l'IdP et réception de sa réponse (Assertion Consumer Service)
+type SSOHandler struct {
+	configure usecases.UseCase[usecases.ConfigureIdentityProviderRequest, *usecases.IdentityProviderResponse]
+	get       usecases.UseCase[string, *usecases.IdentityProviderResponse]
+	metadata  usecases.UseCase[string, []byte]
+	start     usecases.UseCase[usecases.StartSSOLoginRequest, *usecases.StartSSOLoginResponse]
+	consume   usecases.UseCase[usecases.ConsumeSSOResponseRequest, *usecases.ConsumeSSOResponseResponse]
+}
+
+func NewSSOHandler(
+	configure usecases.UseCase[usecases.ConfigureIdentityProviderRequest, *usecases.IdentityProviderResponse],
+	get usecases.UseCase[string, *usecases.IdentityProviderResponse],
+	metadata usecases.UseCase[string, []byte],
+	start usecases.UseCase[usecases.StartSSOLoginRequest, *usecases.StartSSOLoginResponse],
+	consume usecases.UseCase[usecases.ConsumeSSOResponseRequest, *usecases.ConsumeSSOResponseResponse],
+) *SSOHandler {
+	return &SSOHandler{
+		configure: configure,
+		get:       get,
+		metadata:  metadata,
+		start:     start,
+		consume:   consume,
+	}
+}
+
+// ConfigureIdentityProvider PUT /tenants/{tenant}/identity-provider
+func (h *SSOHandler) ConfigureIdentityProvider(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ConfigureIdentityProviderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.TenantID = r.PathValue("tenant")
+
+	response, err := h.configure.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GetIdentityProvider GET /tenants/{tenant}/identity-provider (sans le certificat)
+func (h *SSOHandler) GetIdentityProvider(w http.ResponseWriter, r *http.Request) {
+	response, err := h.get.Execute(r.Context(), r.PathValue("tenant"))
+	if err != nil {
+		writeUseCaseError(w, ssoErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Metadata GET /saml/{tenant}/metadata : à importer dans l'IdP
+func (h *SSOHandler) Metadata(w http.ResponseWriter, r *http.Request) {
+	metadata, err := h.metadata.Execute(r.Context(), r.PathValue("tenant"))
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(metadata)
+}
+
+// Login GET /saml/{tenant}/login?relay_state=... : redirige le navigateur vers l'IdP. Avec un jeton
+// Bearer, l'identité de l'IdP sera rattachée au compte authentifié
+func (h *SSOHandler) Login(w http.ResponseWriter, r *http.Request) {
+	response, err := h.start.Execute(r.Context(), usecases.StartSSOLoginRequest{
+		TenantID:   r.PathValue("tenant"),
+		RelayState: r.URL.Query().Get("relay_state"),
+	})
+	if err != nil {
+		writeUseCaseError(w, ssoErrorStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, response.RedirectURL, http.StatusFound)
+}
+
+// AssertionConsumer POST /saml/{tenant}/acs (binding HTTP-POST : SAMLResponse, RelayState)
+// 401 invalid_saml_response, 403 account_deactivated ou account_banned, 409 account_not_linked
+func (h *SSOHandler) AssertionConsumer(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form payload")
+		return
+	}
+
+	response, err := h.consume.Execute(r.Context(), usecases.ConsumeSSOResponseRequest{
+		TenantID:     r.PathValue("tenant"),
+		SAMLResponse: r.PostForm.Get("SAMLResponse"),
+		RelayState:   r.PostForm.Get("RelayState"),
+	})
+	if err != nil {
+		writeUseCaseError(w, ssoErrorStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ssoErrorStatus 404 si le tenant n'a pas (ou plus) de fournisseur d'identité actif
+func ssoErrorStatus(err error) int {
+	if errors.Is(err, usecases.ErrSSONotConfigured) || errors.Is(err, repositories.ErrIdentityProviderNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// isSAMLAssertionConsumer l'ACS reçoit un formulaire soumis depuis le domaine de l'IdP :
+// il est authentifié par l'assertion signée et la demande à usage unique, pas par la session
+func isSAMLAssertionConsumer(path string) bool {
+	path = strings.TrimPrefix(path, "/v1")
+	return strings.HasPrefix(path, "/saml/") && strings.HasSuffix(path, "/acs")
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// TermsHandler expose la version en vigueur des conditions d'utilisation et leur acceptation
+type TermsHandler struct {
+	getStatus usecases.UseCase[int, *usecases.TermsStatusResponse]
+	accept    usecases.UseCase[usecases.AcceptTermsRequest, *usecases.TermsStatusResponse]
+}
+
+func NewTermsHandler(
+	getStatus usecases.UseCase[int, *usecases.TermsStatusResponse],
+	accept usecases.UseCase[usecases.AcceptTermsRequest, *usecases.TermsStatusResponse],
+) *TermsHandler {
+	return &TermsHandler{
+		getStatus: getStatus,
+		accept:    accept,
+	}
+}
+
+// Status GET /users/{id}/terms
+func (h *TermsHandler) Status(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	response, err := h.getStatus.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Accept POST /users/{id}/terms {"version": "2024-06"}
+func (h *TermsHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.AcceptTermsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.UserID = id
+
+	response, err := h.accept.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, acceptTermsError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// acceptTermsError 409 si la version affichée n'est plus en vigueur (le client recharge les conditions)
+func acceptTermsError(err error) int {
+	switch {
+	case errors.Is(err, usecases.ErrTermsVersionMismatch):
+		return http.StatusConflict
+	case errors.Is(err, repositories.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrInvalidTermsVersion):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// =============================================================================
+// MIDDLEWARE : ACCEPTATION DES CONDITIONS EXIGÉE
+// =============================================================================
+
+// TokenVerifier vérifie un jeton d'accès et retourne son acteur
+type TokenVerifier interface {
+	Verify(token string) (usecases.Actor, error)
+}
+
+// RequireTerms refuse les requêtes authentifiées (jeton Bearer ou cookie de session) des utilisateurs
+// qui n'ont pas accepté la version en vigueur : 403 avec le code "terms_not_accepted".
+// Restent ouverts la connexion et la consultation/acceptation des conditions (/users/{id}/terms).
+// Placé derrière Authenticate : une requête anonyme n'est pas traitée ici et suit son cours
+func RequireTerms(next http.Handler, getStatus usecases.UseCase[int, *usecases.TermsStatusResponse]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := usecases.ActorFromContext(r.Context())
+		if !ok || termsExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		status, err := getStatus.Execute(r.Context(), actor.UserID)
+		if err != nil {
+			writeUseCaseError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if !status.UpToDate {
+			writeUseCaseError(w, http.StatusForbidden, usecases.ErrTermsNotAccepted)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func termsExempt(path string) bool {
+	path = strings.TrimPrefix(path, "/v1")
+	return path == "/health" || path == "/auth/login" || path == "/auth/login/verify" || strings.HasSuffix(path, "/terms")
+}
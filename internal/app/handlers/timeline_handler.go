@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"net/http"
+	"strings"
+)
+
+// TimelineHandler chronologie d'un utilisateur, réservée au rôle de support
+type TimelineHandler struct {
+	get usecases.UseCase[usecases.GetUserTimelineRequest, *usecases.GetUserTimelineResponse]
+}
+
+func NewTimelineHandler(get usecases.UseCase[usecases.GetUserTimelineRequest, *usecases.GetUserTimelineResponse]) *TimelineHandler {
+	return &TimelineHandler{get: get}
+}
+
+// Get GET /users/{id}/timeline?category=login,profile&cursor=&limit=
+func (h *TimelineHandler) Get(w http.ResponseWriter, r *http.Request) {
+	b := bindRequest(r)
+	req := usecases.GetUserTimelineRequest{UserID: b.PathID("id", "user id")}
+	var categories string
+	b.QueryString("category", &categories)
+	b.QueryString("cursor", &req.Cursor)
+	b.QueryInt("limit", &req.Limit, 1, 200)
+	if !b.Valid(w) {
+		return
+	}
+	if categories != "" {
+		req.Categories = strings.Split(categories, ",")
+	}
+
+	response, err := h.get.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
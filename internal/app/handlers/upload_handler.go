@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// UploadHandler upload fragmenté et reprenable des fichiers volumineux (imports CSV) :
+//
+//	POST /uploads {"purpose", "filename", "size", "checksum"}  ouvre la session (checksum : SHA-256 hex)
+//	PUT  /uploads/{id}/chunks/{index}                          corps brut du fragment, Content-Digest facultatif
+//	GET  /uploads/{id}                                         reprise : missing_chunks
+//	POST /uploads/{id}/complete                                assemblage et vérification
+type UploadHandler struct {
+	create   usecases.UseCase[usecases.CreateUploadRequest, *usecases.UploadResponse]
+	get      usecases.UseCase[string, *usecases.UploadResponse]
+	chunk    usecases.UseCase[usecases.UploadChunkRequest, *usecases.UploadResponse]
+	complete usecases.UseCase[string, *usecases.UploadResponse]
+}
+
+func NewUploadHandler(
+	create usecases.UseCase[usecases.CreateUploadRequest, *usecases.UploadResponse],
+	get usecases.UseCase[string, *usecases.UploadResponse],
+	chunk usecases.UseCase[usecases.UploadChunkRequest, *usecases.UploadResponse],
+	complete usecases.UseCase[string, *usecases.UploadResponse],
+) *UploadHandler {
+	return &UploadHandler{create: create, get: get, chunk: chunk, complete: complete}
+}
+
+// Create POST /uploads ; la réponse donne chunk_size et chunk_count
+func (h *UploadHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req usecases.CreateUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.create.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Location", "/uploads/"+response.ID)
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// Get GET /uploads/{id}
+func (h *UploadHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.get.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeUseCaseError(w, uploadErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Chunk PUT /uploads/{id}/chunks/{index}
+// Content-Digest: sha-256=:<base64>: (RFC 9530) fait vérifier le fragment dès sa réception
+func (h *UploadHandler) Chunk(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid chunk index")
+		return
+	}
+	checksum, err := parseContentDigest(r.Header.Get("Content-Digest"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.chunk.Execute(r.Context(), usecases.UploadChunkRequest{
+		UploadID: r.PathValue("id"),
+		Index:    index,
+		Checksum: checksum,
+		Body:     r.Body,
+	})
+	if err != nil {
+		writeUseCaseError(w, uploadErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Complete POST /uploads/{id}/complete ; 422 si le fichier assemblé ne correspond pas au checksum
+func (h *UploadHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	response, err := h.complete.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeUseCaseError(w, uploadErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrUploadSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrUploadExpired):
+		return http.StatusGone
+	case errors.Is(err, entities.ErrUploadClosed), errors.Is(err, entities.ErrUploadIncomplete):
+		return http.StatusConflict
+	case errors.Is(err, entities.ErrUploadChecksumMismatch), errors.Is(err, usecases.ErrChunkChecksumMismatch):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// parseContentDigest empreinte SHA-256 (hex) d'un en-tête Content-Digest ; "" sans en-tête ou
+// sans entrée sha-256 (autres algorithmes ignorés)
+func parseContentDigest(header string) (string, error) {
+	for _, entry := range strings.Split(header, ",") {
+		algorithm, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || !strings.EqualFold(algorithm, "sha-256") {
+			continue
+		}
+		encoded, hasPrefix := strings.CutPrefix(value, ":")
+		encoded, hasSuffix := strings.CutSuffix(encoded, ":")
+		digest, err := base64.StdEncoding.DecodeString(encoded)
+		if !hasPrefix || !hasSuffix || err != nil || len(digest) != 32 {
+			return "", errors.New("invalid Content-Digest: sha-256=:<base64>: expected")
+		}
+		return hex.EncodeToString(digest), nil
+	}
+	return "", nil
+}
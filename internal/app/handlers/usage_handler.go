@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"net/http"
+)
+
+// UsageHandler expose l'usage mesuré d'un tenant et ses quotas
+type UsageHandler struct {
+	get usecases.UseCase[usecases.GetTenantUsageRequest, *usecases.TenantUsageResponse]
+}
+
+func NewUsageHandler(get usecases.UseCase[usecases.GetTenantUsageRequest, *usecases.TenantUsageResponse]) *UsageHandler {
+	return &UsageHandler{get: get}
+}
+
+// Get GET /tenants/{tenant}/usage : appels du mois en cours, utilisateurs et événements stockés
+func (h *UsageHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.get.Execute(r.Context(), usecases.GetTenantUsageRequest{TenantID: r.PathValue("tenant")})
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"net/http"
+)
+
+const maxBulkBodySize = 4 << 20 // 4 MiB, assez pour usecases.MaxBulkSize lignes
+
+// UserBulkHandler expose les opérations en lot utilisées par les outils d'import
+type UserBulkHandler struct {
+	createUsers usecases.UseCase[usecases.BulkCreateUsersRequest, *usecases.BulkCreateUsersResponse]
+	updateUsers usecases.UseCase[usecases.BulkUpdateUsersRequest, *usecases.BulkUpdateUsersResponse]
+	deleteUsers usecases.UseCase[usecases.BulkDeleteUsersRequest, *usecases.BulkDeleteUsersResponse]
+	importUsers usecases.UseCase[usecases.ImportUsersRequest, *usecases.ImportUsersResponse]
+}
+
+func NewUserBulkHandler(
+	createUsers usecases.UseCase[usecases.BulkCreateUsersRequest, *usecases.BulkCreateUsersResponse],
+	updateUsers usecases.UseCase[usecases.BulkUpdateUsersRequest, *usecases.BulkUpdateUsersResponse],
+	deleteUsers usecases.UseCase[usecases.BulkDeleteUsersRequest, *usecases.BulkDeleteUsersResponse],
+	importUsers usecases.UseCase[usecases.ImportUsersRequest, *usecases.ImportUsersResponse],
+) *UserBulkHandler {
+	return &UserBulkHandler{
+		createUsers: createUsers,
+		updateUsers: updateUsers,
+		deleteUsers: deleteUsers,
+		importUsers: importUsers,
+	}
+}
+
+// Create POST /users/bulk
+func (h *UserBulkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req usecases.BulkCreateUsersRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.createUsers.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// Update PUT /users/bulk
+func (h *UserBulkHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req usecases.BulkUpdateUsersRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.updateUsers.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Delete POST /users/bulk/delete (un corps sur DELETE est mal supporté par les proxies)
+func (h *UserBulkHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req usecases.BulkDeleteUsersRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.deleteUsers.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Import POST /users/import {"upload_id"} : fichier CSV envoyé au préalable par POST /uploads
+func (h *UserBulkHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ImportUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.importUsers.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, uploadErrorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
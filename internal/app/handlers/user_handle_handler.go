@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// UserHandleHandler expose les handles publics : lookup /users/@handle, disponibilité et choix
+type UserHandleHandler struct {
+	getByHandle       usecases.UseCase[string, *usecases.GetUserResponse]
+	checkAvailability usecases.UseCase[string, *usecases.HandleAvailabilityResponse]
+	setHandle         usecases.UseCase[usecases.SetUserHandleRequest, *usecases.SetUserHandleResponse]
+}
+
+func NewUserHandleHandler(
+	getByHandle usecases.UseCase[string, *usecases.GetUserResponse],
+	checkAvailability usecases.UseCase[string, *usecases.HandleAvailabilityResponse],
+	setHandle usecases.UseCase[usecases.SetUserHandleRequest, *usecases.SetUserHandleResponse],
+) *UserHandleHandler {
+	return &UserHandleHandler{
+		getByHandle:       getByHandle,
+		checkAvailability: checkAvailability,
+		setHandle:         setHandle,
+	}
+}
+
+// Get GET /users/@{handle} (aiguillé par le routeur depuis GET /users/{id})
+func (h *UserHandleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	response, err := h.getByHandle.Execute(r.Context(), strings.TrimPrefix(r.PathValue("id"), "@"))
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+
+	etag := userETag(response.ID, response.Updated)
+	setUserCacheHeaders(w, etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Availability GET /handles/{handle}/availability
+func (h *UserHandleHandler) Availability(w http.ResponseWriter, r *http.Request) {
+	response, err := h.checkAvailability.Execute(r.Context(), r.PathValue("handle"))
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+type setUserHandleRequest struct {
+	Handle string `json:"handle"`
+}
+
+// Set PUT /users/{id}/handle {"handle": "alice"} ; un handle vide retire le handle
+func (h *UserHandleHandler) Set(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req setUserHandleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.setHandle.Execute(r.Context(), usecases.SetUserHandleRequest{UserID: id, Handle: req.Handle})
+	if err != nil {
+		writeUseCaseError(w, handleError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleError 409 si le handle appartient à un autre compte, 400 s'il est invalide ou réservé
+func handleError(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrHandleTaken):
+		return http.StatusConflict
+	case errors.Is(err, repositories.ErrUserNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
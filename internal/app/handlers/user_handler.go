@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// UserHandler expose les use cases utilisateur en HTTP
+// Les dépendances sont les interfaces génériques : la composition root y injecte
+// les use cases déjà décorés (logging, metrics, tracing...)
+type UserHandler struct {
+	createUser usecases.UseCase[usecases.CreateUserRequest, *usecases.CreateUserResponse]
+	getUser    usecases.UseCase[int, *usecases.GetUserResponse]
+	updateUser usecases.UseCase[usecases.UpdateUserRequest, *usecases.UpdateUserResponse]
+	patchUser  usecases.UseCase[usecases.PatchUserRequest, *usecases.UpdateUserResponse]
+	deleteUser usecases.UseCase[int, struct{}]
+	listUsers  usecases.UseCase[usecases.ListUsersRequest, *usecases.ListUsersResponse]
+	countUsers usecases.UseCase[usecases.CountUsersRequest, *usecases.CountUsersResponse]
+	display    DisplayFormatResolver
+}
+
+func NewUserHandler(
+	createUser usecases.UseCase[usecases.CreateUserRequest, *usecases.CreateUserResponse],
+	getUser usecases.UseCase[int, *usecases.GetUserResponse],
+	updateUser usecases.UseCase[usecases.UpdateUserRequest, *usecases.UpdateUserResponse],
+	patchUser usecases.UseCase[usecases.PatchUserRequest, *usecases.UpdateUserResponse],
+	deleteUser usecases.UseCase[int, struct{}],
+	listUsers usecases.UseCase[usecases.ListUsersRequest, *usecases.ListUsersResponse],
+	countUsers usecases.UseCase[usecases.CountUsersRequest, *usecases.CountUsersResponse],
+	display DisplayFormatResolver,
+) *UserHandler {
+	return &UserHandler{
+		createUser: createUser,
+		getUser:    getUser,
+		updateUser: updateUser,
+		patchUser:  patchUser,
+		deleteUser: deleteUser,
+		listUsers:  listUsers,
+		countUsers: countUsers,
+		display:    display,
+	}
+}
+
+// Create POST /users
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req usecases.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.createUser.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// Get GET /users/{id}?display=true
+// display ajoute les dates formatées selon la locale et le fuseau de l'appelant ; la réponse dépend
+// alors de ses préférences et n'a pas d'ETag
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	b := bindRequest(r)
+	id := b.PathID("id", "user id")
+	display := bindDisplay(b, h.display)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.getUser.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if display {
+		writeJSON(w, http.StatusOK, struct {
+			*usecases.GetUserResponse
+			Display usecases.UserDisplay `json:"display"`
+		}{response, usecases.NewUserDisplay(response, h.display.ForActor(r.Context()))})
+		return
+	}
+
+	etag := userETag(response.ID, response.Updated)
+	setUserCacheHeaders(w, etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// checkIfMatch vérifie la précondition If-Match avant une suppression ; les mises à jour la
+// transmettent au use case (ifMatchUpdated), qui conditionne l'écriture elle-même
+// Retourne false après avoir écrit la réponse d'erreur (404 ou 412)
+func (h *UserHandler) checkIfMatch(w http.ResponseWriter, r *http.Request, id int) bool {
+	match := r.Header.Get("If-Match")
+	if match == "" {
+		return true
+	}
+
+	current, err := h.getUser.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return false
+	}
+	etag := userETag(current.ID, current.Updated)
+	if !etagMatches(match, etag) {
+		w.Header().Set("ETag", etag)
+		writeError(w, http.StatusPreconditionFailed, "user was modified since it was read")
+		return false
+	}
+	return true
+}
+
+// Update PUT /users/{id}
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.ID = id
+
+	if req.ExpectedUpdated, ok = ifMatchUpdated(w, r, id); !ok {
+		return
+	}
+
+	response, err := h.updateUser.Execute(r.Context(), req)
+	if errors.Is(err, repositories.ErrUserModified) {
+		writeUserModified(w, r, h.getUser, id)
+		return
+	}
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	setUserCacheHeaders(w, userETag(response.ID, response.Updated))
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Patch PATCH /users/{id} (JSON Merge Patch, RFC 7396) : seuls les champs présents sont modifiés
+// email et name sont obligatoires : les mettre à null est refusé ; dans attributes, null supprime la clé
+func (h *UserHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	req, err := decodeUserMergePatch(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ID = id
+
+	if req.ExpectedUpdated, ok = ifMatchUpdated(w, r, id); !ok {
+		return
+	}
+
+	response, err := h.patchUser.Execute(r.Context(), req)
+	if errors.Is(err, repositories.ErrUserModified) {
+		writeUserModified(w, r, h.getUser, id)
+		return
+	}
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	setUserCacheHeaders(w, userETag(response.ID, response.Updated))
+	writeJSON(w, http.StatusOK, response)
+}
+
+// decodeUserMergePatch distingue un membre absent (inchangé) d'un membre à null (suppression),
+// ce qu'un décodage direct dans des pointeurs ne permet pas
+func decodeUserMergePatch(body io.Reader) (usecases.PatchUserRequest, error) {
+	var members map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&members); err != nil {
+		return usecases.PatchUserRequest{}, errors.New("invalid JSON merge patch")
+	}
+
+	var req usecases.PatchUserRequest
+	for member, raw := range members {
+		if string(raw) == "null" {
+			return usecases.PatchUserRequest{}, fmt.Errorf("%s cannot be removed", member)
+		}
+
+		var target any
+		switch member {
+		case "email":
+			target = &req.Email
+		case "name":
+			target = &req.Name
+		case "attributes":
+			target = &req.Attributes
+		default:
+			return usecases.PatchUserRequest{}, fmt.Errorf("unknown field %s", member)
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return usecases.PatchUserRequest{}, fmt.Errorf("invalid %s", member)
+		}
+	}
+	return req, nil
+}
+
+// Delete DELETE /users/{id}
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	if !h.checkIfMatch(w, r, id) {
+		return
+	}
+
+	if _, err := h.deleteUser.Execute(r.Context(), id); err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List GET /users?page=&page_size=&cursor=&status=&sort_by=created|name|email&order=asc|desc
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	var req usecases.ListUsersRequest
+	b := bindRequest(r)
+	b.Pagination(&req.Page, &req.PageSize)
+	b.QueryString("cursor", &req.Cursor)
+	b.QueryString("status", &req.Status)
+	b.UserSort(&req.SortBy, &req.Order)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.listUsers.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Count HEAD /users?status= : le total dans X-Total-Count, sans lire de page ni de corps
+// X-Total-Count-Estimated: true quand le total vient des statistiques du stockage (LIST_TOTALS=estimated)
+func (h *UserHandler) Count(w http.ResponseWriter, r *http.Request) {
+	var req usecases.CountUsersRequest
+	b := bindRequest(r)
+	b.QueryString("status", &req.Status)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.countUsers.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(response.Total))
+	if response.Estimated {
+		w.Header().Set("X-Total-Count-Estimated", "true")
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"errors"
+	"net/http"
+)
+
+// attributeParamPrefix préfixe des filtres d'attributs : ?attr.plan=pro&attr.seats=10
+const attributeParamPrefix = "attr."
+
+// UserSearchHandler expose la recherche multicritère
+type UserSearchHandler struct {
+	searchUsers usecases.UseCase[usecases.SearchUsersRequest, *usecases.SearchUsersResponse]
+}
+
+func NewUserSearchHandler(searchUsers usecases.UseCase[usecases.SearchUsersRequest, *usecases.SearchUsersResponse]) *UserSearchHandler {
+	return &UserSearchHandler{searchUsers: searchUsers}
+}
+
+// Search GET /users/search?q=&email=&name=&status=&created_from=&created_to=&attr.<clé>=&page=&page_size=
+// Les critères se combinent par ET ; un attribut absent du schéma du tenant donne 400
+// Avec q, les résultats sont classés par pertinence (501 si le stockage n'a pas d'index plein texte)
+func (h *UserSearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	var req usecases.SearchUsersRequest
+	b := bindRequest(r)
+	b.QueryString("q", &req.Query)
+	b.QueryString("email", &req.Email)
+	b.QueryString("name", &req.Name)
+	b.QueryString("status", &req.Status)
+	b.DateRange("created_from", "created_to", &req.CreatedFrom, &req.CreatedTo)
+	b.QueryPrefixed(attributeParamPrefix, &req.Attributes)
+	b.Pagination(&req.Page, &req.PageSize)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.searchUsers.Execute(r.Context(), req)
+	if errors.Is(err, repositories.ErrFullTextNotSupported) {
+		writeError(w, http.StatusNotImplemented, repositories.ErrFullTextNotSupported.Error())
+		return
+	}
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"net/http"
+)
+
+// UserStatsHandler statistiques des comptes pour l'administration
+type UserStatsHandler struct {
+	stats usecases.UseCase[usecases.GetUserStatsRequest, *usecases.GetUserStatsResponse]
+}
+
+func NewUserStatsHandler(stats usecases.UseCase[usecases.GetUserStatsRequest, *usecases.GetUserStatsResponse]) *UserStatsHandler {
+	return &UserStatsHandler{stats: stats}
+}
+
+// Get GET /admin/stats?from=&to=&interval=day|week|month
+// Totaux par statut, vérifiés ou non, et nouveaux comptes par intervalle de la période
+func (h *UserStatsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	var req usecases.GetUserStatsRequest
+	b := bindRequest(r)
+	b.DateRange("from", "to", &req.From, &req.To)
+	b.QueryEnum("interval", usecases.EventRollupIntervals, &req.Interval)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.stats.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
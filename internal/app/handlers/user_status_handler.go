@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// UserStatusHandler expose le cycle de vie des comptes (désactivation, bannissement, réactivation)
+type UserStatusHandler struct {
+	deactivate usecases.UseCase[usecases.DeactivateUserRequest, *usecases.UserStatusResponse]
+	reactivate usecases.UseCase[int, *usecases.UserStatusResponse]
+}
+
+func NewUserStatusHandler(
+	deactivate usecases.UseCase[usecases.DeactivateUserRequest, *usecases.UserStatusResponse],
+	reactivate usecases.UseCase[int, *usecases.UserStatusResponse],
+) *UserStatusHandler {
+	return &UserStatusHandler{
+		deactivate: deactivate,
+		reactivate: reactivate,
+	}
+}
+
+// Deactivate POST /users/{id}/deactivate {"reason": "...", "ban": false}
+func (h *UserStatusHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	var req usecases.DeactivateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.UserID = id
+
+	response, err := h.deactivate.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, statusTransitionError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Reactivate POST /users/{id}/reactivate
+func (h *UserStatusHandler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "user id")
+	if !ok {
+		return
+	}
+
+	response, err := h.reactivate.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, statusTransitionError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// statusTransitionError 409 pour une transition refusée par l'entité, 404 si le compte est inconnu
+func statusTransitionError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrUserAlreadyActive), errors.Is(err, entities.ErrUserBannedStatus):
+		return http.StatusConflict
+	case errors.Is(err, repositories.ErrUserNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"errors"
+	"net/http"
+)
+
+// UserSyncHandler déclenche à la demande la synchronisation avec le système externe
+type UserSyncHandler struct {
+	syncUsers usecases.UseCase[usecases.SyncUsersRequest, *usecases.SyncUsersResponse]
+}
+
+func NewUserSyncHandler(syncUsers usecases.UseCase[usecases.SyncUsersRequest, *usecases.SyncUsersResponse]) *UserSyncHandler {
+	return &UserSyncHandler{syncUsers: syncUsers}
+}
+
+// Sync POST /sync/users?dry_run=true : le rapport détaille chaque compte créé, rattaché ou modifié ;
+// réservé à l'administration (401 sans jeton, 403 sans le rôle)
+func (h *UserSyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	var req usecases.SyncUsersRequest
+	b := bindRequest(r)
+	b.QueryBool("dry_run", &req.DryRun)
+	if !b.Valid(w) {
+		return
+	}
+
+	response, err := h.syncUsers.Execute(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, usecases.ErrUserSyncDisabled) {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeUseCaseError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// API v2 : MÊMES USE CASES, NOUVEAU CONTRAT
+// =============================================================================
+
+// UserV2Handler expose les use cases utilisateur avec le contrat v2
+// - identifiants au format UUID (opaques pour le client)
+// - pagination par curseur uniquement, réponse {data, next_cursor}
+// - dates en created_at / updated_at
+// Seuls les DTO changent : validation, règles métier et décorateurs restent ceux de la v1
+type UserV2Handler struct {
+	createUser usecases.UseCase[usecases.CreateUserRequest, *usecases.CreateUserResponse]
+	getUser    usecases.UseCase[int, *usecases.GetUserResponse]
+	updateUser usecases.UseCase[usecases.UpdateUserRequest, *usecases.UpdateUserResponse]
+	deleteUser usecases.UseCase[int, struct{}]
+	listUsers  usecases.UseCase[usecases.ListUsersRequest, *usecases.ListUsersResponse]
+}
+
+func NewUserV2Handler(
+	createUser usecases.UseCase[usecases.CreateUserRequest, *usecases.CreateUserResponse],
+	getUser usecases.UseCase[int, *usecases.GetUserResponse],
+	updateUser usecases.UseCase[usecases.UpdateUserRequest, *usecases.UpdateUserResponse],
+	deleteUser usecases.UseCase[int, struct{}],
+	listUsers usecases.UseCase[usecases.ListUsersRequest, *usecases.ListUsersResponse],
+) *UserV2Handler {
+	return &UserV2Handler{
+		createUser: createUser,
+		getUser:    getUser,
+		updateUser: updateUser,
+		deleteUser: deleteUser,
+		listUsers:  listUsers,
+	}
+}
+
+// UserV2 représentation d'un utilisateur en v2
+type UserV2 struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	Status    string     `json:"status,omitempty"`
+	Handle    string     `json:"handle,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// UserListV2 page de résultats ; next_cursor est absent sur la dernière page
+type UserListV2 struct {
+	Data       []UserV2 `json:"data"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	Total      int      `json:"total"`
+}
+
+type createUserV2Request struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type updateUserV2Request struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// Create POST /v2/users
+func (h *UserV2Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req createUserV2Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	response, err := h.createUser.Execute(r.Context(), usecases.CreateUserRequest{
+		Email:    req.Email,
+		Name:     req.Name,
+		Password: req.Password,
+	})
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v2/users/"+formatUserUUID(response.ID))
+	writeJSON(w, http.StatusCreated, UserV2{
+		ID:        formatUserUUID(response.ID),
+		Email:     response.Email,
+		Name:      response.Name,
+		CreatedAt: &response.Created,
+	})
+}
+
+// Get GET /v2/users/{id}
+func (h *UserV2Handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUserUUID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	response, err := h.getUser.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+
+	etag := userETag(response.ID, response.Updated)
+	setUserCacheHeaders(w, etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toUserV2(response))
+}
+
+// checkIfMatch même précondition qu'en v1 (suppression) : l'ETag ne dépend pas de la version de l'API
+func (h *UserV2Handler) checkIfMatch(w http.ResponseWriter, r *http.Request, id int) bool {
+	match := r.Header.Get("If-Match")
+	if match == "" {
+		return true
+	}
+
+	current, err := h.getUser.Execute(r.Context(), id)
+	if err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return false
+	}
+	etag := userETag(current.ID, current.Updated)
+	if !etagMatches(match, etag) {
+		w.Header().Set("ETag", etag)
+		writeError(w, http.StatusPreconditionFailed, "user was modified since it was read")
+		return false
+	}
+	return true
+}
+
+// Update PUT /v2/users/{id}
+func (h *UserV2Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUserUUID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	var req updateUserV2Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	expected, ok := ifMatchUpdated(w, r, id)
+	if !ok {
+		return
+	}
+
+	response, err := h.updateUser.Execute(r.Context(), usecases.UpdateUserRequest{ID: id, Email: req.Email, Name: req.Name, ExpectedUpdated: expected})
+	if errors.Is(err, repositories.ErrUserModified) {
+		writeUserModified(w, r, h.getUser, id)
+		return
+	}
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	setUserCacheHeaders(w, userETag(response.ID, response.Updated))
+	writeJSON(w, http.StatusOK, UserV2{
+		ID:        formatUserUUID(response.ID),
+		Email:     response.Email,
+		Name:      response.Name,
+		UpdatedAt: &response.Updated,
+	})
+}
+
+// Delete DELETE /v2/users/{id}
+func (h *UserV2Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUserUUID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if !h.checkIfMatch(w, r, id) {
+		return
+	}
+
+	if _, err := h.deleteUser.Execute(r.Context(), id); err != nil {
+		writeUseCaseError(w, http.StatusNotFound, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List GET /v2/users?limit=&cursor=&status=&sort_by=&order=
+// Le curseur fige la taille de page : limit n'est lu que sur la première page
+// (status, sort_by et order doivent être répétés à chaque page)
+func (h *UserV2Handler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	req := usecases.ListUsersRequest{Page: 1, PageSize: 10}
+	b := bindRequest(r)
+	b.QueryInt("limit", &req.PageSize, 1, maxPageSize)
+	b.UserSort(&req.SortBy, &req.Order)
+	if !b.Valid(w) {
+		return
+	}
+	if raw := query.Get("cursor"); raw != "" {
+		page, pageSize, err := decodeV2Cursor(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		req.Page, req.PageSize = page, pageSize
+	}
+	req.Status = query.Get("status")
+
+	response, err := h.listUsers.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	list := UserListV2{Data: make([]UserV2, len(response.Users)), Total: response.Total}
+	for i, user := range response.Users {
+		list.Data[i] = toUserV2(user)
+	}
+	if response.Page < response.TotalPages {
+		list.NextCursor = encodeV2Cursor(response.Page+1, response.PageSize)
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func toUserV2(user *usecases.GetUserResponse) UserV2 {
+	return UserV2{
+		ID:        formatUserUUID(user.ID),
+		Email:     user.Email,
+		Name:      user.Name,
+		Status:    user.Status,
+		Handle:    user.Handle,
+		CreatedAt: &user.Created,
+		UpdatedAt: &user.Updated,
+	}
+}
+
+// =============================================================================
+// MAPPERS v2 : IDENTIFIANTS ET CURSEURS
+// =============================================================================
+
+// userUUIDPrefix préfixe fixe des UUID v2 : version 8 (RFC 9562, format propre à l'application)
+// et variante RFC ; les 12 derniers chiffres hexadécimaux portent l'ID interne.
+// Le client ne doit rien en déduire : une vraie colonne UUID pourra remplacer ce codage
+// sans changer le contrat, seuls ces deux mappers évolueront.
+const userUUIDPrefix = "00000000-0000-8000-8000-"
+
+func formatUserUUID(id int) string {
+	return fmt.Sprintf("%s%012x", userUUIDPrefix, id)
+}
+
+func parseUserUUID(raw string) (int, error) {
+	suffix, found := strings.CutPrefix(strings.ToLower(raw), userUUIDPrefix)
+	if !found || len(suffix) != 12 {
+		return 0, errors.New("identifiant utilisateur invalide")
+	}
+	if _, err := hex.DecodeString(suffix); err != nil {
+		return 0, errors.New("identifiant utilisateur invalide")
+	}
+
+	id, err := strconv.ParseInt(suffix, 16, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("identifiant utilisateur invalide")
+	}
+	return int(id), nil
+}
+
+// Le curseur v2 transporte page et taille : indépendant du feature flag de pagination de la v1
+func encodeV2Cursor(page, pageSize int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("p:" + strconv.Itoa(page) + ":" + strconv.Itoa(pageSize)))
+}
+
+func decodeV2Cursor(cursor string) (int, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, errors.New("curseur de pagination invalide")
+	}
+
+	value, found := strings.CutPrefix(string(raw), "p:")
+	if !found {
+		return 0, 0, errors.New("curseur de pagination invalide")
+	}
+	rawPage, rawSize, found := strings.Cut(value, ":")
+	if !found {
+		return 0, 0, errors.New("curseur de pagination invalide")
+	}
+
+	page, err := strconv.Atoi(rawPage)
+	if err != nil || page < 1 {
+		return 0, 0, errors.New("curseur de pagination invalide")
+	}
+	pageSize, err := strconv.Atoi(rawSize)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		return 0, 0, errors.New("curseur de pagination invalide")
+	}
+	return page, pageSize, nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+const maxWebhookBodySize = 1 << 20 // 1 MiB
+
+// WebhookHandler reçoit les événements poussés par les systèmes tiers
+// POST /webhooks/{source}
+type WebhookHandler struct {
+	useCase   usecases.UseCase[usecases.WebhookEvent, *usecases.HandleWebhookEventResponse]
+	verifiers map[string]*SignatureVerifier
+}
+
+func NewWebhookHandler(useCase usecases.UseCase[usecases.WebhookEvent, *usecases.HandleWebhookEventResponse], verifiers map[string]*SignatureVerifier) *WebhookHandler {
+	return &WebhookHandler{
+		useCase:   useCase,
+		verifiers: verifiers,
+	}
+}
+
+// webhookEnvelope enveloppe commune attendue pour tous les fournisseurs
+type webhookEnvelope struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Created int64           `json:"created"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	source := r.PathValue("source")
+	verifier, ok := h.verifiers[source]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown webhook source")
+		return
+	}
+
+	// 1. Lire le corps brut : la signature porte sur les octets exacts reçus
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "payload too large")
+		return
+	}
+
+	// 2. Vérifier la signature AVANT tout parsing ; Stripe envoie le même format dans son propre en-tête
+	signature := r.Header.Get("X-Webhook-Signature")
+	if signature == "" {
+		signature = r.Header.Get("Stripe-Signature")
+	}
+	// Motif du refus (absente, expirée, incorrecte) non exposé : il guiderait un attaquant
+	if err := verifier.Verify(signature, body); err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid webhook signature")
+		return
+	}
+
+	// 3. Parser l'enveloppe
+	var envelope webhookEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	if envelope.ID == "" || envelope.Type == "" {
+		writeError(w, http.StatusBadRequest, "id and type are required")
+		return
+	}
+
+	event := usecases.WebhookEvent{
+		ID:         envelope.ID,
+		Source:     source,
+		Type:       envelope.Type,
+		OccurredAt: time.Unix(envelope.Created, 0),
+		Payload:    envelope.Data,
+	}
+
+	// 4. Déléguer au use case (déduplication + traduction + exécution)
+	response, err := h.useCase.Execute(r.Context(), event)
+	if err != nil {
+		writeWebhookError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// writeWebhookError le statut dit au fournisseur si l'échec tient à nous : 5xx pour un échec
+// transitoire (stockage, délai, maintenance), 422 pour un événement refusé par le domaine (payload
+// invalide, utilisateur inconnu...). Le détail reste dans les logs, jamais dans la réponse
+func writeWebhookError(w http.ResponseWriter, r *http.Request, err error) {
+	var unavailable *usecases.UnavailableError
+	var technical *usecases.Error
+	switch {
+	case errors.Is(err, r.Context().Err()):
+		writeError(w, http.StatusServiceUnavailable, "request cancelled")
+	case errors.As(err, &unavailable):
+		setRetryAfter(w, unavailable)
+		writeJSON(w, http.StatusServiceUnavailable, ErrorResponse{Error: "service unavailable", Code: unavailable.Mode})
+	case errors.Is(err, usecases.ErrTimeout):
+		writeError(w, http.StatusGatewayTimeout, "webhook processing timed out")
+	case errors.As(err, &technical):
+		// Le fournisseur rejouera l'événement plus tard
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{
+			Error:     "webhook processing failed",
+			RequestID: usecases.RequestIDFromContext(r.Context()),
+		})
+	default:
+		writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: "webhook event rejected", Code: "event_rejected"})
+	}
+}
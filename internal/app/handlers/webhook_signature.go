@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errMissingSignature = errors.New("missing signature")
+	errInvalidSignature = errors.New("invalid signature")
+	errExpiredSignature = errors.New("signature timestamp outside tolerance")
+)
+
+// SignatureVerifier vérifie les signatures HMAC-SHA256 des webhooks entrants
+// En-tête attendu : "t=<unix timestamp>,v1=<hex hmac>" avec hmac = HMAC(secret, "<t>.<body>")
+// Le timestamp signé empêche le rejeu d'une requête capturée
+type SignatureVerifier struct {
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+func NewSignatureVerifier(secret string, tolerance time.Duration) *SignatureVerifier {
+	return &SignatureVerifier{
+		secret:    []byte(secret),
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+func (v *SignatureVerifier) Verify(header string, body []byte) error {
+	if header == "" {
+		return errMissingSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			// Deux horodatages : en-tête ambigu, refusé plutôt que d'en choisir un
+			if timestamp != "" {
+				return errInvalidSignature
+			}
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return errInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errInvalidSignature
+	}
+
+	age := v.now().Sub(time.Unix(unix, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return errExpiredSignature
+	}
+
+	expected := v.sign(timestamp, body)
+	// Plusieurs v1 possibles pendant une rotation de secret côté fournisseur
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+
+	return errInvalidSignature
+}
+
+func (v *SignatureVerifier) sign(timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
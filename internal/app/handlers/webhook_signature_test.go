@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	webhookTestSecret = "whsec_0123456789abcdef"
+	webhookTestBody   = `{"id":"evt_1","type":"invoice.paid","created":1772445600,"data":{}}`
+)
+
+var webhookTestNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+// webhookTestSignature en-tête signé indépendamment de SignatureVerifier.sign
+func webhookTestSignature(secret string, at time.Time, body string) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookTestVerifier() *SignatureVerifier {
+	verifier := NewSignatureVerifier(webhookTestSecret, 5*time.Minute)
+	verifier.now = func() time.Time { return webhookTestNow }
+	return verifier
+}
+
+func TestSignatureVerifierVerify(t *testing.T) {
+	valid := webhookTestSignature(webhookTestSecret, webhookTestNow.Add(-time.Minute), webhookTestBody)
+	_, signature, _ := strings.Cut(valid, ",v1=")
+	timestamp := strconv.FormatInt(webhookTestNow.Unix(), 10)
+
+	tests := []struct {
+		name    string
+		header  string
+		body    string
+		wantErr error
+	}{
+		{"signature valide", valid, webhookTestBody, nil},
+		{"espaces autour des champs", strings.ReplaceAll(valid, ",", " , "), webhookTestBody, nil},
+		{"rotation : une signature valide parmi d'autres", valid + ",v1=" + strings.Repeat("0", 64) + ",v1=zz", webhookTestBody, nil},
+		{"autre secret", webhookTestSignature("whsec_attacker-secret", webhookTestNow, webhookTestBody), webhookTestBody, errInvalidSignature},
+		{"corps modifié", valid, strings.Replace(webhookTestBody, "invoice.paid", "invoice.voided", 1), errInvalidSignature},
+		{"signature tronquée", valid[:len(valid)-2], webhookTestBody, errInvalidSignature},
+		{"signature vide", "t=" + timestamp + ",v1=", webhookTestBody, errInvalidSignature},
+		{"horodatage périmé", webhookTestSignature(webhookTestSecret, webhookTestNow.Add(-6*time.Minute), webhookTestBody), webhookTestBody, errExpiredSignature},
+		{"horodatage futur", webhookTestSignature(webhookTestSecret, webhookTestNow.Add(6*time.Minute), webhookTestBody), webhookTestBody, errExpiredSignature},
+		// Rejeu d'une requête capturée avec un horodatage rafraîchi : la signature ne le couvre pas
+		{"rejeu avec horodatage rafraîchi", "t=" + timestamp + ",v1=" + signature, webhookTestBody, errInvalidSignature},
+		{"deux horodatages", "t=" + timestamp + "," + valid, webhookTestBody, errInvalidSignature},
+		{"en-tête absent", "", webhookTestBody, errMissingSignature},
+		{"sans horodatage", "v1=" + signature, webhookTestBody, errInvalidSignature},
+		{"sans signature", "t=" + timestamp, webhookTestBody, errInvalidSignature},
+		{"horodatage non numérique", "t=now,v1=" + signature, webhookTestBody, errInvalidSignature},
+		{"champs sans séparateur", "garbage", webhookTestBody, errInvalidSignature},
+		{"v0 seulement", strings.Replace(valid, "v1=", "v0=", 1), webhookTestBody, errInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := newWebhookTestVerifier().Verify(tt.header, []byte(tt.body)); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erreur %v, attendu %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Une signature refusée répond 401 sans motif ni appel au use case
+func TestWebhookHandlerRejectsSignature(t *testing.T) {
+	called := false
+	useCase := usecases.UseCaseFunc[usecases.WebhookEvent, *usecases.HandleWebhookEventResponse](
+		func(context.Context, usecases.WebhookEvent) (*usecases.HandleWebhookEventResponse, error) {
+			called = true
+			return &usecases.HandleWebhookEventResponse{}, nil
+		})
+	handler := NewWebhookHandler(useCase, map[string]*SignatureVerifier{"payments": newWebhookTestVerifier()})
+
+	for _, header := range []string{
+		"",
+		webhookTestSignature(webhookTestSecret, webhookTestNow.Add(-time.Hour), webhookTestBody),
+		webhookTestSignature("whsec_attacker-secret", webhookTestNow, webhookTestBody),
+	} {
+		r := httptest.NewRequest("POST", "/webhooks/payments", strings.NewReader(webhookTestBody))
+		r.SetPathValue("source", "payments")
+		r.Header.Set("X-Webhook-Signature", header)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("%q : statut %d, attendu 401", header, w.Code)
+		}
+		if body := w.Body.String(); !strings.Contains(body, "invalid webhook signature") || strings.Contains(body, "tolerance") {
+			t.Fatalf("%q : réponse %s", header, body)
+		}
+	}
+	if called {
+		t.Fatal("use case appelé malgré une signature refusée")
+	}
+}
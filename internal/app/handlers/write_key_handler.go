@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// WriteKeyHandler gestion des clés d'écriture d'un tenant (ingestion publique, voir CollectHandler)
+type WriteKeyHandler struct {
+	create usecases.UseCase[usecases.CreateWriteKeyRequest, *usecases.WriteKeyResponse]
+	list   usecases.UseCase[usecases.ListWriteKeysRequest, *usecases.ListWriteKeysResponse]
+	rotate usecases.UseCase[usecases.WriteKeyRequest, *usecases.WriteKeyResponse]
+	revoke usecases.UseCase[usecases.WriteKeyRequest, struct{}]
+}
+
+func NewWriteKeyHandler(
+	create usecases.UseCase[usecases.CreateWriteKeyRequest, *usecases.WriteKeyResponse],
+	list usecases.UseCase[usecases.ListWriteKeysRequest, *usecases.ListWriteKeysResponse],
+	rotate usecases.UseCase[usecases.WriteKeyRequest, *usecases.WriteKeyResponse],
+	revoke usecases.UseCase[usecases.WriteKeyRequest, struct{}],
+) *WriteKeyHandler {
+	return &WriteKeyHandler{create: create, list: list, rotate: rotate, revoke: revoke}
+}
+
+// Create POST /tenants/{tenant}/write-keys {"name"} : la clé en clair n'est renvoyée qu'ici
+func (h *WriteKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req usecases.CreateWriteKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+	req.TenantID = r.PathValue("tenant")
+
+	response, err := h.create.Execute(r.Context(), req)
+	if err != nil {
+		writeUseCaseError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// List GET /tenants/{tenant}/write-keys
+func (h *WriteKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	response, err := h.list.Execute(r.Context(), usecases.ListWriteKeysRequest{TenantID: r.PathValue("tenant")})
+	if err != nil {
+		writeUseCaseError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Rotate POST /tenants/{tenant}/write-keys/{id}/rotate : nouvelle clé, l'ancienne expire après
+// le délai de grâce (WRITE_KEY_ROTATION_GRACE)
+func (h *WriteKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "write key id")
+	if !ok {
+		return
+	}
+
+	response, err := h.rotate.Execute(r.Context(), usecases.WriteKeyRequest{TenantID: r.PathValue("tenant"), ID: id})
+	if err != nil {
+		writeUseCaseError(w, writeKeyErrorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// Revoke DELETE /tenants/{tenant}/write-keys/{id} : refusée dès maintenant, toujours listée
+func (h *WriteKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, ok := bindPathID(w, r, "id", "write key id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.revoke.Execute(r.Context(), usecases.WriteKeyRequest{TenantID: r.PathValue("tenant"), ID: id}); err != nil {
+		writeUseCaseError(w, writeKeyErrorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeKeyErrorStatus(err error) int {
+	if errors.Is(err, repositories.ErrWriteKeyNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,313 @@
+package ws
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Topics auxquels un client peut s'abonner
+const (
+	TopicUser    = "user"              // événements concernant l'utilisateur connecté
+	TopicSignups = "analytics.signups" // compteur d'inscriptions en direct
+)
+
+const (
+	writeWait    = 10 * time.Second
+	pongWait     = 60 * time.Second
+	pingInterval = pongWait * 9 / 10 // ping avant l'expiration du délai de pong
+	sendBuffer   = 32                // messages en attente par client avant déconnexion
+)
+
+// TokenVerifier vérifie un jeton d'accès et retourne l'acteur authentifié
+type TokenVerifier interface {
+	Verify(token string) (usecases.Actor, error)
+}
+
+// Message format des messages échangés avec les clients
+type Message struct {
+	Type   string          `json:"type"`
+	Topics []string        `json:"topics,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// Hub maintient les connexions WebSocket et leur pousse les événements du domaine
+// GET /ws?access_token=... (les navigateurs ne peuvent pas poser d'en-tête Authorization)
+type Hub struct {
+	verifier TokenVerifier
+	logger   usecases.Logger
+
+	mutex   sync.RWMutex
+	clients map[*client]struct{}
+
+	signups atomic.Int64
+}
+
+func NewHub(verifier TokenVerifier, logger usecases.Logger) *Hub {
+	return &Hub{
+		verifier: verifier,
+		logger:   logger,
+		clients:  make(map[*client]struct{}),
+	}
+}
+
+// client connexion d'un acteur authentifié
+type client struct {
+	hub   *Hub
+	conn  *conn
+	actor usecases.Actor
+	send  chan []byte
+
+	topicsMutex sync.RWMutex
+	topics      map[string]bool
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	actor, err := h.verifier.Verify(bearerToken(r))
+	if err != nil {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	wsConn, err := upgrade(w, r)
+	if err != nil {
+		http.Error(w, `{"error":"websocket upgrade required"}`, http.StatusBadRequest)
+		return
+	}
+
+	c := &client{
+		hub:    h,
+		conn:   wsConn,
+		actor:  actor,
+		send:   make(chan []byte, sendBuffer),
+		topics: make(map[string]bool),
+		done:   make(chan struct{}),
+	}
+
+	h.mutex.Lock()
+	h.clients[c] = struct{}{}
+	h.mutex.Unlock()
+
+	h.logger.Info("WebSocket client connected", map[string]interface{}{"user_id": actor.UserID})
+
+	go c.writeLoop()
+	c.readLoop() // Bloque jusqu'à la déconnexion
+}
+
+// bearerToken lit le jeton depuis l'en-tête Authorization ou le paramètre access_token
+func bearerToken(r *http.Request) string {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		return token
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+// Handle est abonné au bus d'événements
+func (h *Hub) Handle(ctx context.Context, event events.Event) error {
+	if _, ok := event.(events.UserCreated); ok {
+		total := h.signups.Add(1)
+		h.broadcast(TopicSignups, 0, Message{
+			Type: TopicSignups,
+			Data: mustMarshal(map[string]interface{}{"total": total}),
+		})
+	}
+
+	userID := 0
+	switch e := event.(type) {
+	case events.UserCreated:
+		userID = e.UserID
+	case events.UserProfileUpdated:
+		userID = e.UserID
+	case events.UserDeleted:
+		userID = e.UserID
+	case events.DigestPreferenceChanged:
+		userID = e.UserID
+	case events.UserPhoneChanged:
+		userID = e.UserID
+	case events.UserStatusChanged:
+		userID = e.UserID
+	case events.UserHandleChanged:
+		userID = e.UserID
+	default:
+		return nil
+	}
+
+	// Seul le nom de l'événement est poussé : le client recharge la ressource via l'API
+	h.broadcast(TopicUser, userID, Message{
+		Type: event.EventName(),
+		Data: mustMarshal(map[string]interface{}{"user_id": userID, "occurred_at": event.OccurredAt()}),
+	})
+	return nil
+}
+
+// broadcast envoie un message aux clients abonnés au topic (userID 0 = tous les acteurs)
+func (h *Hub) broadcast(topic string, userID int, message Message) {
+	payload := mustMarshal(message)
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for c := range h.clients {
+		if userID != 0 && c.actor.UserID != userID {
+			continue
+		}
+		if !c.subscribed(topic) {
+			continue
+		}
+		c.enqueue(payload)
+	}
+}
+
+func (h *Hub) remove(c *client) {
+	h.mutex.Lock()
+	delete(h.clients, c)
+	h.mutex.Unlock()
+}
+
+// enqueue n'attend jamais : un client qui ne suit pas est déconnecté (backpressure)
+func (c *client) enqueue(payload []byte) {
+	select {
+	case c.send <- payload:
+	case <-c.done:
+	default:
+		c.hub.logger.Info("WebSocket client too slow, disconnecting", map[string]interface{}{"user_id": c.actor.UserID})
+		go c.shutdown(closeTryAgainLater, "client too slow")
+	}
+}
+
+func (c *client) subscribed(topic string) bool {
+	c.topicsMutex.RLock()
+	defer c.topicsMutex.RUnlock()
+	return c.topics[topic]
+}
+
+func (c *client) readLoop() {
+	defer c.shutdown(closeNormal, "")
+
+	var message []byte
+	for {
+		if err := c.conn.netConn.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+			return
+		}
+
+		fin, opcode, payload, err := c.conn.readFrame()
+		switch {
+		case errors.Is(err, errMessageTooBig):
+			c.shutdown(closeMessageTooBig, "message too big")
+			return
+		case errors.Is(err, errProtocol):
+			c.shutdown(closeProtocolError, "protocol error")
+			return
+		case err != nil:
+			return
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.conn.writeFrame(opPong, payload, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		case opPong:
+			// Le délai de lecture est repoussé à chaque frame reçue
+		case opClose:
+			return
+		case opText, opContinuation:
+			message = append(message, payload...)
+			if len(message) > maxMessageSize {
+				c.shutdown(closeMessageTooBig, "message too big")
+				return
+			}
+			if fin {
+				c.handleMessage(message)
+				message = nil
+			}
+		default:
+			c.shutdown(closePolicyViolation, "unsupported frame")
+			return
+		}
+	}
+}
+
+// handleMessage traite les abonnements envoyés par le client
+func (c *client) handleMessage(raw []byte) {
+	var message Message
+	if err := json.Unmarshal(raw, &message); err != nil {
+		c.enqueue(mustMarshal(Message{Type: "error", Data: mustMarshal("invalid message")}))
+		return
+	}
+
+	switch message.Type {
+	case "subscribe", "unsubscribe":
+		c.topicsMutex.Lock()
+		for _, topic := range message.Topics {
+			if topic != TopicUser && topic != TopicSignups {
+				continue
+			}
+			c.topics[topic] = message.Type == "subscribe"
+		}
+		c.topicsMutex.Unlock()
+
+		c.enqueue(mustMarshal(Message{Type: message.Type + "d", Topics: message.Topics}))
+
+		// Le compteur courant est envoyé dès l'abonnement
+		if message.Type == "subscribe" && c.subscribed(TopicSignups) {
+			c.enqueue(mustMarshal(Message{
+				Type: TopicSignups,
+				Data: mustMarshal(map[string]interface{}{"total": c.hub.signups.Load()}),
+			}))
+		}
+	default:
+		c.enqueue(mustMarshal(Message{Type: "error", Data: mustMarshal("unknown message type")}))
+	}
+}
+
+func (c *client) writeLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case payload := <-c.send:
+			if err := c.conn.writeFrame(opText, payload, time.Now().Add(writeWait)); err != nil {
+				c.shutdown(closeNormal, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.writeFrame(opPing, nil, time.Now().Add(writeWait)); err != nil {
+				c.shutdown(closeNormal, "")
+				return
+			}
+		}
+	}
+}
+
+// shutdown ferme la connexion une seule fois, quel que soit le côté qui la déclenche
+func (c *client) shutdown(code int, reason string) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.hub.remove(c)
+		_ = c.conn.writeClose(code, reason, time.Now().Add(writeWait))
+		_ = c.conn.close()
+		c.hub.logger.Info("WebSocket client disconnected", map[string]interface{}{"user_id": c.actor.UserID})
+	})
+}
+
+func mustMarshal(value interface{}) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err) // Uniquement des types sérialisables construits localement
+	}
+	return data
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// HTTPAlertWebhook implémente usecases.AlertWebhook : POST JSON de l'AlertNotice sur l'URL de la règle
+//
+//	POST {url}
+//	{"rule_id": 1, "name": "...", "status": "firing", "event": "user.created", "value": 12, "previous": 40, ...}
+//
+// Toute réponse 2xx est un succès ; l'envoi n'est pas réessayé (le prochain changement d'état le sera)
+type HTTPAlertWebhook struct {
+	client *http.Client
+}
+
+func NewHTTPAlertWebhook(clients *httpclient.Factory) *HTTPAlertWebhook {
+	return &HTTPAlertWebhook{client: clients.Client("alert_webhook", httpclient.ClientOptions{Timeout: 5 * time.Second})}
+}
+
+func (w *HTTPAlertWebhook) Send(ctx context.Context, url string, notice usecases.AlertNotice) error {
+	payload, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New("alert webhook returned " + resp.Status)
+	}
+	return nil
+}
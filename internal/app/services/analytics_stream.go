@@ -0,0 +1,148 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// analyticsHistorySize nombre d'agrégats conservés pour rejouer après une reconnexion
+	analyticsHistorySize = 300
+	// analyticsSubscriberBuffer agrégats en attente par client avant abandon
+	analyticsSubscriberBuffer = 16
+)
+
+// AnalyticsSnapshot agrégat publié à chaque intervalle où au moins un événement a eu lieu
+type AnalyticsSnapshot struct {
+	Seq    int64          `json:"seq"`
+	At     time.Time      `json:"at"`
+	Totals map[string]int `json:"totals"` // compteurs cumulés depuis le démarrage
+	Deltas map[string]int `json:"deltas"` // événements survenus depuis l'agrégat précédent
+}
+
+// AnalyticsStream agrège les événements du bus et diffuse les compteurs aux abonnés
+// Les événements ne sont diffusés qu'agrégés : un pic de trafic ne multiplie pas les messages
+type AnalyticsStream struct {
+	mutex   sync.Mutex
+	totals  map[string]int
+	pending map[string]int
+	seq     int64
+	history []AnalyticsSnapshot
+
+	subscribers map[chan AnalyticsSnapshot]struct{}
+	logger      usecases.Logger
+}
+
+func NewAnalyticsStream(logger usecases.Logger) *AnalyticsStream {
+	return &AnalyticsStream{
+		totals:      make(map[string]int),
+		pending:     make(map[string]int),
+		subscribers: make(map[chan AnalyticsSnapshot]struct{}),
+		logger:      logger,
+	}
+}
+
+// Handle est abonné au bus d'événements
+func (s *AnalyticsStream) Handle(ctx context.Context, event events.Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.pending[event.EventName()]++
+	return nil
+}
+
+// Start publie un agrégat toutes les interval jusqu'à l'annulation du context
+func (s *AnalyticsStream) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.flush(now)
+			}
+		}
+	}()
+}
+
+func (s *AnalyticsStream) flush(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.pending) == 0 {
+		return
+	}
+
+	for name, count := range s.pending {
+		s.totals[name] += count
+	}
+	s.seq++
+	snapshot := AnalyticsSnapshot{
+		Seq:    s.seq,
+		At:     now,
+		Totals: copyCounts(s.totals),
+		Deltas: s.pending,
+	}
+	s.pending = make(map[string]int)
+
+	s.history = append(s.history, snapshot)
+	if len(s.history) > analyticsHistorySize {
+		s.history = s.history[len(s.history)-analyticsHistorySize:]
+	}
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- snapshot:
+		default:
+			// Le client rattrapera les totaux avec l'agrégat suivant
+			s.logger.Info("Analytics snapshot dropped for slow client", map[string]interface{}{"seq": snapshot.Seq})
+		}
+	}
+}
+
+// Subscribe retourne les agrégats manqués depuis lastSeq puis le flux des suivants
+// Nouveau client (0), lastSeq trop ancien ou inconnu (redémarrage) : seul le dernier agrégat
+// est rejoué, ses totaux suffisent à resynchroniser le client
+func (s *AnalyticsStream) Subscribe(lastSeq int64) ([]AnalyticsSnapshot, <-chan AnalyticsSnapshot, func()) {
+	ch := make(chan AnalyticsSnapshot, analyticsSubscriberBuffer)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var replay []AnalyticsSnapshot
+	if len(s.history) > 0 {
+		oldest := s.history[0].Seq
+		if lastSeq > 0 && lastSeq >= oldest-1 && lastSeq <= s.seq {
+			replay = append(replay, s.history[lastSeq-oldest+1:]...)
+		} else {
+			replay = append(replay, s.history[len(s.history)-1])
+		}
+	}
+	s.subscribers[ch] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			delete(s.subscribers, ch)
+			close(ch)
+		})
+	}
+	return replay, ch, unsubscribe
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	result := make(map[string]int, len(counts))
+	for name, count := range counts {
+		result[name] = count
+	}
+	return result
+}
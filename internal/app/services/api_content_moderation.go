@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIContentModeration implémente usecases.ContentModeration avec un service de modération externe :
+//
+//	POST {url}  Authorization: Bearer {apiKey}
+//	{"kind": "name", "text": "..."}
+//	→ {"flagged": true, "categories": ["harassment"]}
+//
+// Service indisponible : le texte est évalué par fallback (liste de mots locale), l'incident est journalisé
+type APIContentModeration struct {
+	url      string
+	apiKey   string
+	client   *http.Client
+	fallback usecases.ContentModeration
+	logger   usecases.Logger
+}
+
+func NewAPIContentModeration(url, apiKey string, fallback usecases.ContentModeration, logger usecases.Logger, clients *httpclient.Factory) *APIContentModeration {
+	return &APIContentModeration{
+		url:      url,
+		apiKey:   apiKey,
+		client:   clients.Client("moderation", httpclient.ClientOptions{Timeout: 3 * time.Second}),
+		fallback: fallback,
+		logger:   logger,
+	}
+}
+
+func (m *APIContentModeration) Moderate(ctx context.Context, kind, text string) (*usecases.ModerationVerdict, error) {
+	verdict, err := m.call(ctx, kind, text)
+	if err != nil {
+		m.logger.Error("Moderation service unavailable, using word list", err, map[string]interface{}{"kind": kind})
+		return m.fallback.Moderate(ctx, kind, text)
+	}
+	return verdict, nil
+}
+
+func (m *APIContentModeration) call(ctx context.Context, kind, text string) (*usecases.ModerationVerdict, error) {
+	payload, err := json.Marshal(map[string]string{"kind": kind, "text": text})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("moderation service returned " + resp.Status)
+	}
+
+	var body struct {
+		Flagged    bool     `json:"flagged"`
+		Categories []string `json:"categories"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return &usecases.ModerationVerdict{Allowed: !body.Flagged, Reason: strings.Join(body.Categories, ",")}, nil
+}
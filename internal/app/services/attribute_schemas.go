@@ -0,0 +1,60 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// defaultAttributeTenant clé du schéma appliqué aux tenants sans schéma propre (et aux appels sans tenant)
+const defaultAttributeTenant = "*"
+
+// StaticAttributeSchemas implémente usecases.AttributeSchemaRegistry à partir d'un fichier JSON :
+//
+//	{"*": {"plan": "string", "seats": "number"}, "acme": {"plan": "string", "sso": "boolean"}}
+//
+// Le schéma d'un tenant remplace le schéma par défaut (il ne le complète pas)
+type StaticAttributeSchemas struct {
+	schemas map[string]*entities.AttributeSchema
+}
+
+// emptyAttributeSchema sans fichier ni entrée par défaut, aucun attribut n'est accepté
+var emptyAttributeSchema, _ = entities.NewAttributeSchema(nil)
+
+// LoadAttributeSchemas lit le fichier de schémas ; un chemin vide donne un registre sans attribut autorisé
+func LoadAttributeSchemas(path string) (*StaticAttributeSchemas, error) {
+	registry := &StaticAttributeSchemas{schemas: make(map[string]*entities.AttributeSchema)}
+	if path == "" {
+		return registry, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var definitions map[string]map[string]entities.AttributeType
+	if err := json.Unmarshal(raw, &definitions); err != nil {
+		return nil, fmt.Errorf("fichier de schémas d'attributs invalide : %w", err)
+	}
+
+	for tenant, types := range definitions {
+		schema, err := entities.NewAttributeSchema(types)
+		if err != nil {
+			return nil, fmt.Errorf("schéma d'attributs du tenant %q : %w", tenant, err)
+		}
+		registry.schemas[tenant] = schema
+	}
+	return registry, nil
+}
+
+func (r *StaticAttributeSchemas) SchemaFor(ctx context.Context, tenantID string) (*entities.AttributeSchema, error) {
+	if schema, ok := r.schemas[tenantID]; ok && tenantID != "" {
+		return schema, nil
+	}
+	if schema, ok := r.schemas[defaultAttributeTenant]; ok {
+		return schema, nil
+	}
+	return emptyAttributeSchema, nil
+}
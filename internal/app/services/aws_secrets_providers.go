@@ -0,0 +1,215 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/awssig"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AWSSecretsConfig accès à SSM Parameter Store ou à Secrets Manager
+type AWSSecretsConfig struct {
+	Region      string
+	Credentials awssig.Credentials
+	// Path SSM : préfixe des paramètres ("/clean-archi/prod" → /clean-archi/prod/JWT_SECRET) ;
+	// Secrets Manager : nom ou ARN du secret JSON regroupant les clés de l'API
+	Path string
+	// Clients fabrique des clients HTTP sortants (nil = client sans instrumentation)
+	Clients *httpclient.Factory
+}
+
+func (cfg AWSSecretsConfig) validate(service string) error {
+	if cfg.Region == "" {
+		return errors.New(service + ": région manquante")
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return errors.New(service + ": identifiants AWS manquants")
+	}
+	if strings.Trim(cfg.Path, "/") == "" {
+		return errors.New(service + ": chemin des secrets manquant")
+	}
+	return nil
+}
+
+// =============================================================================
+// SSM PARAMETER STORE
+// =============================================================================
+
+// SSMSecretsProvider implémente SecretsProvider avec un paramètre SecureString par secret :
+//
+//	POST https://ssm.{region}.amazonaws.com/  (X-Amz-Target: AmazonSSM.GetParameter)
+//	{"Name": "/clean-archi/prod/JWT_SECRET", "WithDecryption": true}
+//
+// Relu à chaque appel : une nouvelle version du paramètre est vue au rechargement suivant
+type SSMSecretsProvider struct {
+	api    awsJSONAPI
+	prefix string
+}
+
+func NewSSMSecretsProvider(cfg AWSSecretsConfig) (*SSMSecretsProvider, error) {
+	if err := cfg.validate("ssm"); err != nil {
+		return nil, err
+	}
+	return &SSMSecretsProvider{
+		api:    newAWSJSONAPI("ssm", "AmazonSSM", cfg),
+		prefix: "/" + strings.Trim(cfg.Path, "/") + "/",
+	}, nil
+}
+
+func (p *SSMSecretsProvider) Secret(ctx context.Context, name string) (string, error) {
+	var out struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+	err := p.api.call(ctx, "GetParameter", map[string]interface{}{"Name": p.prefix + name, "WithDecryption": true}, &out)
+	var failure *awsAPIError
+	if errors.As(err, &failure) && failure.Type == "ParameterNotFound" {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if out.Parameter.Value == "" {
+		return "", ErrSecretNotFound
+	}
+	return out.Parameter.Value, nil
+}
+
+// =============================================================================
+// SECRETS MANAGER
+// =============================================================================
+
+// SecretsManagerSecretsProvider implémente SecretsProvider avec un secret JSON dont chaque clé
+// est un secret de l'API (même organisation que VaultSecretsProvider) :
+//
+//	POST https://secretsmanager.{region}.amazonaws.com/  (X-Amz-Target: secretsmanager.GetSecretValue)
+//	{"SecretId": "clean-archi/prod"} → {"SecretString": "{\"JWT_SIGNING_KEYS\": \"...\", \"DATABASE_URL\": \"...\"}"}
+//
+// La version AWSCURRENT est lue à chaque appel : une rotation est vue au rechargement suivant
+type SecretsManagerSecretsProvider struct {
+	api      awsJSONAPI
+	secretID string
+}
+
+func NewSecretsManagerSecretsProvider(cfg AWSSecretsConfig) (*SecretsManagerSecretsProvider, error) {
+	if err := cfg.validate("secretsmanager"); err != nil {
+		return nil, err
+	}
+	return &SecretsManagerSecretsProvider{
+		api:      newAWSJSONAPI("secretsmanager", "secretsmanager", cfg),
+		secretID: strings.Trim(cfg.Path, "/"),
+	}, nil
+}
+
+func (p *SecretsManagerSecretsProvider) Secret(ctx context.Context, name string) (string, error) {
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	err := p.api.call(ctx, "GetSecretValue", map[string]string{"SecretId": p.secretID}, &out)
+	var failure *awsAPIError
+	if errors.As(err, &failure) && failure.Type == "ResourceNotFoundException" {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal([]byte(out.SecretString), &values); err != nil {
+		return "", errors.New("secretsmanager: le secret doit être un objet JSON")
+	}
+	value, ok := values[name]
+	if !ok || value == nil {
+		return "", ErrSecretNotFound
+	}
+	secret, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("secretsmanager: la clé %s n'est pas une chaîne", name)
+	}
+	if secret == "" {
+		return "", ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+// =============================================================================
+// API JSON AWS
+// =============================================================================
+
+// awsJSONAPI appels signés au protocole JSON 1.1 commun à SSM et Secrets Manager
+type awsJSONAPI struct {
+	service     string
+	target      string // préfixe de X-Amz-Target
+	endpoint    string
+	region      string
+	credentials awssig.Credentials
+	client      *http.Client
+}
+
+func newAWSJSONAPI(service, target string, cfg AWSSecretsConfig) awsJSONAPI {
+	return awsJSONAPI{
+		service:     service,
+		target:      target,
+		endpoint:    "https://" + service + "." + cfg.Region + ".amazonaws.com/",
+		region:      cfg.Region,
+		credentials: cfg.Credentials,
+		client:      cfg.Clients.Client(service, httpclient.ClientOptions{Timeout: 5 * time.Second}),
+	}
+}
+
+// awsAPIError réponse d'erreur ({"__type": "ParameterNotFound", "message": ...})
+type awsAPIError struct {
+	Service string
+	Status  int
+	Type    string
+	Message string
+}
+
+func (e *awsAPIError) Error() string {
+	return fmt.Sprintf("%s: statut %d : %s (%s)", e.Service, e.Status, e.Message, e.Type)
+}
+
+func (a awsJSONAPI) call(ctx context.Context, operation string, body, out interface{}) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", a.target+"."+operation)
+	awssig.Sign(req, encoded, a.service, a.region, a.credentials, time.Now())
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&failure)
+		errorType := failure.Type
+		if i := strings.LastIndex(errorType, "#"); i >= 0 {
+			errorType = errorType[i+1:]
+		}
+		return &awsAPIError{Service: a.service, Status: resp.StatusCode, Type: errorType, Message: failure.Message}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: réponse invalide : %w", a.service, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Points de vérification des fournisseurs : même protocole (POST secret, response, remoteip)
+const (
+	recaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	hcaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// SiteVerifyCaptcha implémente usecases.CaptchaVerifier avec l'API "siteverify" commune à
+// reCAPTCHA (v2 et v3), hCaptcha et Cloudflare Turnstile :
+//
+//	POST {endpoint}  secret=...&response=<jeton>&remoteip=...
+//	{"success": true, "score": 0.9, "error-codes": []}
+//
+// Le score n'est renvoyé que par reCAPTCHA v3 et hCaptcha Enterprise
+type SiteVerifyCaptcha struct {
+	name     string
+	endpoint string
+	secret   string
+	client   *http.Client
+}
+
+func NewRecaptchaVerifier(secret string, clients *httpclient.Factory) *SiteVerifyCaptcha {
+	return newSiteVerifyCaptcha("recaptcha", recaptchaVerifyURL, secret, clients)
+}
+
+func NewHCaptchaVerifier(secret string, clients *httpclient.Factory) *SiteVerifyCaptcha {
+	return newSiteVerifyCaptcha("hcaptcha", hcaptchaVerifyURL, secret, clients)
+}
+
+func NewTurnstileVerifier(secret string, clients *httpclient.Factory) *SiteVerifyCaptcha {
+	return newSiteVerifyCaptcha("turnstile", turnstileVerifyURL, secret, clients)
+}
+
+func newSiteVerifyCaptcha(name, endpoint, secret string, clients *httpclient.Factory) *SiteVerifyCaptcha {
+	return &SiteVerifyCaptcha{
+		name:     name,
+		endpoint: endpoint,
+		secret:   secret,
+		client:   clients.Client(name, httpclient.ClientOptions{Timeout: 5 * time.Second}),
+	}
+}
+
+func (c *SiteVerifyCaptcha) Verify(ctx context.Context, token, remoteIP string) (*usecases.CaptchaResult, error) {
+	form := url.Values{"secret": {c.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(c.name + ": siteverify returned " + resp.Status)
+	}
+
+	var body struct {
+		Success bool     `json:"success"`
+		Score   *float64 `json:"score"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	result := &usecases.CaptchaResult{Success: body.Success}
+	if body.Score != nil {
+		result.Score, result.HasScore = *body.Score, true
+	}
+	return result, nil
+}
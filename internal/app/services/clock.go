@@ -0,0 +1,57 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// HORLOGE SYSTÈME
+// =============================================================================
+
+// SystemClock implémente usecases.Clock avec l'horloge de la machine
+type SystemClock struct{}
+
+func NewSystemClock() SystemClock {
+	return SystemClock{}
+}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// =============================================================================
+// HORLOGE PILOTÉE (tests, démos, rejeu)
+// =============================================================================
+
+// FakeClock implémente usecases.Clock avec une heure qui ne bouge que sur demande : expirations
+// de jetons, fenêtres d'inactivité ou changements de mois se testent sans attendre
+type FakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// Set place l'horloge à un instant donné (éventuellement dans le passé)
+func (c *FakeClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = now
+}
+
+// Advance avance l'horloge de d et retourne la nouvelle heure
+func (c *FakeClock) Advance(d time.Duration) time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
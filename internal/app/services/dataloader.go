@@ -0,0 +1,163 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc charge un lot de clés en un appel ; les clés absentes du résultat sont "non trouvées"
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// DataLoader regroupe les chargements unitaires émis pendant une courte fenêtre en un seul appel
+// au BatchFunc, et mémorise les résultats : N résolutions imbriquées (ex: entrées d'audit → acteur)
+// ne coûtent qu'une requête. Un loader est créé par requête entrante (le cache n'expire pas).
+type DataLoader[K comparable, V any] struct {
+	batchFn  BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+	notFound error
+
+	mutex   sync.Mutex
+	cache   map[K]*loaderResult[V]
+	pending *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*loaderResult[V]
+	once    sync.Once // le minuteur et le seuil maxBatch peuvent tous deux déclencher l'envoi
+}
+
+func NewDataLoader[K comparable, V any](batchFn BatchFunc[K, V], wait time.Duration, maxBatch int, notFound error) *DataLoader[K, V] {
+	return &DataLoader[K, V]{
+		batchFn:  batchFn,
+		wait:     wait,
+		maxBatch: maxBatch,
+		notFound: notFound,
+		cache:    make(map[K]*loaderResult[V]),
+	}
+}
+
+// Load attend le résultat du lot contenant key ; le lot utilise le context du premier appelant
+func (l *DataLoader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	result := l.enqueue(ctx, key)
+
+	select {
+	case <-result.done:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// LoadMany charge plusieurs clés (dans le même lot si possible) ; les erreurs sont par clé
+func (l *DataLoader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, []error) {
+	results := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.enqueue(ctx, key)
+	}
+
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	for i, result := range results {
+		select {
+		case <-result.done:
+			values[i], errs[i] = result.value, result.err
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	return values, errs
+}
+
+func (l *DataLoader[K, V]) enqueue(ctx context.Context, key K) *loaderResult[V] {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if result, cached := l.cache[key]; cached {
+		return result
+	}
+
+	result := &loaderResult[V]{done: make(chan struct{})}
+	l.cache[key] = result
+
+	if l.pending == nil {
+		batch := &loaderBatch[K, V]{results: make(map[K]*loaderResult[V])}
+		l.pending = batch
+		time.AfterFunc(l.wait, func() { l.dispatch(ctx, batch) })
+	}
+	l.pending.keys = append(l.pending.keys, key)
+	l.pending.results[key] = result
+
+	if len(l.pending.keys) >= l.maxBatch {
+		batch := l.pending
+		l.pending = nil
+		go l.dispatch(ctx, batch)
+	}
+	return result
+}
+
+func (l *DataLoader[K, V]) dispatch(ctx context.Context, batch *loaderBatch[K, V]) {
+	l.mutex.Lock()
+	if l.pending == batch {
+		l.pending = nil
+	}
+	l.mutex.Unlock()
+
+	batch.once.Do(func() { l.run(ctx, batch) })
+}
+
+func (l *DataLoader[K, V]) run(ctx context.Context, batch *loaderBatch[K, V]) {
+	values, err := l.batchFn(ctx, batch.keys)
+	for key, result := range batch.results {
+		switch value, found := values[key]; {
+		case err != nil:
+			result.err = err
+		case !found:
+			result.err = l.notFound
+		default:
+			result.value = value
+		}
+		close(result.done)
+	}
+
+	// Une erreur technique n'est pas mémorisée : un Load ultérieur réessaiera
+	if err != nil {
+		l.mutex.Lock()
+		for key, result := range batch.results {
+			if l.cache[key] == result {
+				delete(l.cache, key)
+			}
+		}
+		l.mutex.Unlock()
+	}
+}
+
+// =============================================================================
+// LOADER DES UTILISATEURS (modèle de lecture)
+// =============================================================================
+
+// NewUserViewLoader retourne un loader d'utilisateurs par ID, adossé à UserReadRepository.GetByIds
+func NewUserViewLoader(readRepo repositories.UserReadRepository) *DataLoader[int, *repositories.UserView] {
+	return NewDataLoader(func(ctx context.Context, ids []int) (map[int]*repositories.UserView, error) {
+		views, err := readRepo.GetByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[int]*repositories.UserView, len(views))
+		for _, view := range views {
+			byID[view.ID] = view
+		}
+		return byID, nil
+	}, 2*time.Millisecond, 100, repositories.ErrUserNotFound)
+}
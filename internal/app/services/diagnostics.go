@@ -0,0 +1,147 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"runtime/pprof"
+	"slices"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// PROFILS SUR DISQUE : déclenchés (POST /debug/profiles/{kind}) ou automatiques
+// =============================================================================
+
+// maxStoredProfiles profils conservés dans le répertoire ; les plus anciens sont supprimés
+const maxStoredProfiles = 20
+
+// ProfileStore écrit les profils runtime/pprof dans un répertoire de l'instance, à récupérer
+// ensuite (kubectl cp, volume partagé) et à ouvrir avec go tool pprof
+type ProfileStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+func NewProfileStore(dir string) *ProfileStore {
+	return &ProfileStore{dir: dir}
+}
+
+// WriteProfile écrit le profil kind ("heap", "goroutine", "allocs"...) et retourne le chemin du fichier ;
+// les piles des goroutines sont écrites en texte, lisibles sans outil
+func (s *ProfileStore) WriteProfile(kind string) (string, error) {
+	profile := pprof.Lookup(kind)
+	if profile == nil {
+		return "", fmt.Errorf("profil inconnu : %q", kind)
+	}
+	debug, extension := 0, ".pb.gz"
+	if kind == "goroutine" {
+		debug, extension = 2, ".txt"
+	}
+	if kind == "heap" {
+		// Le profil de tas décrit l'état au dernier GC
+		runtime.GC()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.dir, kind+"-"+time.Now().UTC().Format("20060102T150405.000Z")+extension)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err := profile.WriteTo(file, debug); err != nil {
+		file.Close()
+		_ = os.Remove(path)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	s.prune()
+	return path, nil
+}
+
+// prune supprime les profils les plus anciens au-delà de maxStoredProfiles
+func (s *ProfileStore) prune() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	type storedProfile struct {
+		path    string
+		modTime time.Time
+	}
+	var stored []storedProfile
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		stored = append(stored, storedProfile{filepath.Join(s.dir, entry.Name()), info.ModTime()})
+	}
+	if len(stored) <= maxStoredProfiles {
+		return
+	}
+	slices.SortFunc(stored, func(a, b storedProfile) int { return a.modTime.Compare(b.modTime) })
+	for _, profile := range stored[:len(stored)-maxStoredProfiles] {
+		_ = os.Remove(profile.path)
+	}
+}
+
+// =============================================================================
+// CAPTURE AUTOMATIQUE DU TAS
+// =============================================================================
+
+// heapMetric octets occupés par les objets du tas (vivants ou pas encore balayés)
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// HeapWatcher capture un profil de tas quand le tas dépasse threshold octets, une fois par
+// franchissement : la capture est réarmée quand le tas repasse sous le seuil
+type HeapWatcher struct {
+	profiles  *ProfileStore
+	threshold uint64
+	logger    usecases.Logger
+
+	// captured vrai tant que le tas reste au-dessus du seuil après une capture (un seul job appelle Check)
+	captured bool
+}
+
+func NewHeapWatcher(profiles *ProfileStore, threshold uint64, logger usecases.Logger) *HeapWatcher {
+	return &HeapWatcher{profiles: profiles, threshold: threshold, logger: logger}
+}
+
+// Check mesure le tas et capture un profil au franchissement du seuil (appelé périodiquement)
+func (w *HeapWatcher) Check(ctx context.Context) {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	heap := sample[0].Value.Uint64()
+	if heap < w.threshold {
+		w.captured = false
+		return
+	}
+	if w.captured {
+		return
+	}
+	w.captured = true
+
+	path, err := w.profiles.WriteProfile("heap")
+	if err != nil {
+		w.logger.Error("Heap profile capture failed", err, map[string]interface{}{"heap_bytes": heap})
+		return
+	}
+	w.logger.Warn("Heap threshold crossed, profile captured", map[string]interface{}{
+		"heap_bytes": heap, "threshold_bytes": w.threshold, "file": path,
+	})
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// activityLabels libellés lisibles des types d'activité (les inconnus gardent leur nom technique)
+var activityLabels = map[string]string{
+	"user.created":                   "Inscription",
+	"user.profile_updated":           "Modifications du profil",
+	"user.digest_preference_changed": "Changements de préférences",
+	"user.logged_in":                 "Connexions",
+	"user.attributes_changed":        "Modifications des attributs",
+}
+
+var weeklyDigestTemplate = template.Must(template.New("weekly_digest").Parse(
+	`Bonjour {{.Name}},
+
+Voici votre activité du {{.Format.Date .PeriodStart}} au {{.Format.Date .PeriodEnd}} :
+{{range .Lines}}
+- {{.Label}} : {{$.Format.Integer .Count}}{{else}}
+Aucune activité cette semaine.{{end}}
+
+Vous recevez cet email car vous êtes abonné au résumé hebdomadaire.
+`))
+
+// TemplateDigestRenderer implémente usecases.DigestRenderer avec text/template
+type TemplateDigestRenderer struct{}
+
+func NewTemplateDigestRenderer() *TemplateDigestRenderer {
+	return &TemplateDigestRenderer{}
+}
+
+type digestLine struct {
+	Label string
+	Count int
+}
+
+func (r *TemplateDigestRenderer) RenderWeeklyDigest(digest usecases.WeeklyDigest) (string, string, error) {
+	lines := make([]digestLine, 0, len(digest.Activity))
+	for kind, count := range digest.Activity {
+		label, ok := activityLabels[kind]
+		if !ok {
+			label = kind
+		}
+		lines = append(lines, digestLine{Label: label, Count: count})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Label < lines[j].Label })
+
+	var body strings.Builder
+	err := weeklyDigestTemplate.Execute(&body, struct {
+		usecases.WeeklyDigest
+		Lines []digestLine
+	}{digest, lines})
+	if err != nil {
+		return "", "", err
+	}
+
+	return "Votre résumé d'activité de la semaine", body.String(), nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localeStyle conventions d'une locale : dispositions de date (time.Format) et séparateurs
+type localeStyle struct {
+	date     string
+	dateTime string
+	decimal  string
+	group    string
+}
+
+// isoStyle locales inconnues : ISO 8601, séparateurs anglo-saxons
+var isoStyle = localeStyle{"2006-01-02", "2006-01-02 15:04 MST", ".", ","}
+
+// localeStyles par langue, puis par locale régionale quand la région change les conventions
+// ("en-GB" : jour avant le mois). Les séparateurs suivent le CLDR (espace fine insécable en français)
+var localeStyles = map[string]localeStyle{
+	"fr":    {"02/01/2006", "02/01/2006 15:04 MST", ",", " "},
+	"fr-CH": {"02.01.2006", "02.01.2006 15:04 MST", ".", "’"},
+	"en":    {"01/02/2006", "01/02/2006 3:04 PM MST", ".", ","},
+	"en-GB": {"02/01/2006", "02/01/2006 15:04 MST", ".", ","},
+	"en-AU": {"02/01/2006", "02/01/2006 3:04 PM MST", ".", ","},
+	"en-IE": {"02/01/2006", "02/01/2006 15:04 MST", ".", ","},
+	"de":    {"02.01.2006", "02.01.2006 15:04 MST", ",", "."},
+	"de-CH": {"02.01.2006", "02.01.2006 15:04 MST", ".", "’"},
+	"es":    {"02/01/2006", "02/01/2006 15:04 MST", ",", "."},
+	"it":    {"02/01/2006", "02/01/2006 15:04 MST", ",", "."},
+	"pt":    {"02/01/2006", "02/01/2006 15:04 MST", ",", "."},
+	"nl":    {"02-01-2006", "02-01-2006 15:04 MST", ",", "."},
+	"pl":    {"02.01.2006", "02.01.2006 15:04 MST", ",", " "},
+	"sv":    {"2006-01-02", "2006-01-02 15:04 MST", ",", " "},
+	"ja":    {"2006/01/02", "2006/01/02 15:04 MST", ".", ","},
+	"zh":    {"2006/01/02", "2006/01/02 15:04 MST", ".", ","},
+}
+
+// LocaleDisplayFormatter implémente usecases.DisplayFormatter avec une table de conventions par
+// locale (chiffres latins, dates numériques) : pas de dépendance aux données CLDR complètes
+type LocaleDisplayFormatter struct{}
+
+func NewLocaleDisplayFormatter() *LocaleDisplayFormatter {
+	return &LocaleDisplayFormatter{}
+}
+
+func (f *LocaleDisplayFormatter) FormatDate(t time.Time, locale string) string {
+	return t.Format(styleFor(locale).date)
+}
+
+func (f *LocaleDisplayFormatter) FormatDateTime(t time.Time, locale string) string {
+	return t.Format(styleFor(locale).dateTime)
+}
+
+func (f *LocaleDisplayFormatter) FormatNumber(value float64, decimals int, locale string) string {
+	style := styleFor(locale)
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	digits := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+
+	var out strings.Builder
+	if value < 0 && strings.Trim(digits, "0.") != "" {
+		out.WriteByte('-')
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			out.WriteString(style.group)
+		}
+		out.WriteRune(digit)
+	}
+	if fraction != "" {
+		out.WriteString(style.decimal)
+		out.WriteString(fraction)
+	}
+	return out.String()
+}
+
+// styleFor locale régionale, puis sa langue, puis ISO
+func styleFor(locale string) localeStyle {
+	if style, ok := localeStyles[locale]; ok {
+		return style
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	if style, ok := localeStyles[language]; ok {
+		return style
+	}
+	return isoStyle
+}
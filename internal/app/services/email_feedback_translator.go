@@ -0,0 +1,48 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+)
+
+// EmailFeedbackWebhookTranslator traduit les retours du fournisseur d'email (source "email"),
+// relayés dans l'enveloppe commune des webhooks :
+//   - "email.bounced" : data {"email", "bounce_type"} ; seul un rebond "permanent" rejette l'adresse,
+//     un rebond temporaire (boîte pleine, serveur indisponible) est ignoré
+//   - "email.complained" : data {"email"} ; le destinataire a signalé l'email comme spam
+type EmailFeedbackWebhookTranslator struct{}
+
+func NewEmailFeedbackWebhookTranslator() *EmailFeedbackWebhookTranslator {
+	return &EmailFeedbackWebhookTranslator{}
+}
+
+type emailFeedbackPayload struct {
+	Email      string `json:"email"`
+	BounceType string `json:"bounce_type"`
+}
+
+func (t *EmailFeedbackWebhookTranslator) Translate(event usecases.WebhookEvent) (usecases.WebhookCommand, error) {
+	var reason string
+	switch event.Type {
+	case "email.bounced":
+		reason = entities.EmailBounced
+	case "email.complained":
+		reason = entities.EmailComplained
+	default:
+		return nil, usecases.ErrWebhookEventIgnored
+	}
+
+	var payload emailFeedbackPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, errors.New("payload de retour email invalide")
+	}
+	if payload.Email == "" {
+		return nil, errors.New("email manquant")
+	}
+	if reason == entities.EmailBounced && payload.BounceType != "permanent" {
+		return nil, usecases.ErrWebhookEventIgnored
+	}
+	return usecases.MarkEmailUndeliverableCommand{Email: payload.Email, Reason: reason}, nil
+}
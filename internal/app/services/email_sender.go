@@ -0,0 +1,79 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"fmt"
+)
+
+// MailEmailSender implémente usecases.EmailSender au-dessus d'un fournisseur (EMAIL_PROVIDER)
+// L'expéditeur par défaut et le modèle de bienvenue hébergé chez le fournisseur viennent de la configuration
+type MailEmailSender struct {
+	provider        usecases.MailProvider
+	from            string
+	welcomeTemplate string
+}
+
+// NewMailEmailSender welcomeTemplate vide : email de bienvenue textuel rédigé par l'application
+func NewMailEmailSender(provider usecases.MailProvider, from, welcomeTemplate string) *MailEmailSender {
+	return &MailEmailSender{provider: provider, from: from, welcomeTemplate: welcomeTemplate}
+}
+
+func (s *MailEmailSender) SendWelcomeEmail(ctx context.Context, email, name string) error {
+	mail := usecases.Mail{
+		To:         email,
+		Categories: []string{usecases.MailCategoryWelcome},
+	}
+	if s.welcomeTemplate != "" {
+		mail.Template = s.welcomeTemplate
+		mail.TemplateData = map[string]interface{}{"name": name, "email": email}
+	} else {
+		mail.Subject = "Bienvenue"
+		mail.Text = fmt.Sprintf("Bonjour %s,\n\nVotre compte a bien été créé.\n", name)
+	}
+	return s.Send(ctx, mail)
+}
+
+func (s *MailEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	return s.Send(ctx, usecases.Mail{
+		To:         to,
+		Subject:    subject,
+		Text:       body,
+		Categories: []string{usecases.MailCategoryTransactional},
+	})
+}
+
+func (s *MailEmailSender) Send(ctx context.Context, mail usecases.Mail) error {
+	if mail.From == "" {
+		mail.From = s.from
+	}
+	if err := mail.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", s.provider.Name(), err)
+	}
+	return s.provider.Send(ctx, mail)
+}
+
+// LogMailProvider implémente usecases.MailProvider en journalisant les emails
+// Pratique en développement : aucun compte chez un fournisseur n'est nécessaire
+type LogMailProvider struct {
+	logger usecases.Logger
+}
+
+func NewLogMailProvider(logger usecases.Logger) *LogMailProvider {
+	return &LogMailProvider{logger: logger}
+}
+
+func (p *LogMailProvider) Name() string { return "log" }
+
+func (p *LogMailProvider) Send(ctx context.Context, mail usecases.Mail) error {
+	p.logger.Info("Email sent", map[string]interface{}{
+		"to":          mail.To,
+		"subject":     mail.Subject,
+		"template":    mail.Template,
+		"categories":  mail.Categories,
+		"attachments": len(mail.Attachments),
+	})
+	return nil
+}
+
+func (p *LogMailProvider) HealthCheck(ctx context.Context) error { return nil }
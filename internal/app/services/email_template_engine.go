@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"slices"
+	"text/template"
+	"text/template/parse"
+)
+
+// maxRenderedEmailSize taille maximale d'une partie rendue : borne l'exécution d'un modèle
+const maxRenderedEmailSize = 512 << 10
+
+var errRenderedEmailTooLarge = errors.New("email rendu trop volumineux")
+
+// emailTemplateFuncs fonctions intégrées de text/template autorisées dans les modèles ;
+// call, index et slice (accès arbitraire aux valeurs) en sont exclues
+var emailTemplateFuncs = []string{
+	"and", "or", "not", "eq", "ne", "lt", "le", "gt", "ge", "len",
+	"print", "printf", "println", "html", "js", "urlquery",
+}
+
+// TextTemplateEmailEngine implémente usecases.EmailTemplateEngine avec text/template (sujet, texte)
+// et html/template (HTML, échappement contextuel). Les modèles sont vérifiés sur leur arbre
+// syntaxique : seules les variables autorisées ({{.name}}), les fonctions de emailTemplateFuncs,
+// les conditions et les variables locales sont acceptées
+type TextTemplateEmailEngine struct{}
+
+func NewTextTemplateEmailEngine() *TextTemplateEmailEngine {
+	return &TextTemplateEmailEngine{}
+}
+
+func (e *TextTemplateEmailEngine) Check(tpl entities.EmailTemplate, allowed []string) error {
+	for _, part := range []struct{ name, source string }{{"subject", tpl.Subject}, {"text", tpl.Text}, {"html", tpl.HTML}} {
+		if part.source == "" {
+			continue
+		}
+		trees, err := parse.Parse(part.name, part.source, "", "", builtinFuncs())
+		if err != nil {
+			return fmt.Errorf("%s : %w", part.name, err)
+		}
+		if len(trees) != 1 {
+			return fmt.Errorf("%s : define interdit", part.name)
+		}
+		if err := checkNode(trees[part.name].Root, allowed); err != nil {
+			return fmt.Errorf("%s : %w", part.name, err)
+		}
+	}
+	return nil
+}
+
+func (e *TextTemplateEmailEngine) Render(tpl entities.EmailTemplate, data map[string]string) (*usecases.RenderedEmail, error) {
+	rendered := &usecases.RenderedEmail{}
+	var err error
+	if rendered.Subject, err = executeText("subject", tpl.Subject, data); err != nil {
+		return nil, err
+	}
+	if rendered.Text, err = executeText("text", tpl.Text, data); err != nil {
+		return nil, err
+	}
+	if tpl.HTML != "" {
+		t, err := htmltemplate.New("html").Option("missingkey=error").Parse(tpl.HTML)
+		if err != nil {
+			return nil, err
+		}
+		out := &limitedBuffer{limit: maxRenderedEmailSize}
+		if err := t.Execute(out, data); err != nil {
+			return nil, err
+		}
+		rendered.HTML = out.String()
+	}
+	return rendered, nil
+}
+
+func executeText(name, source string, data map[string]string) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", err
+	}
+	out := &limitedBuffer{limit: maxRenderedEmailSize}
+	if err := t.Execute(out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// checkNode parcourt l'arbre : range et template sont refusés (aucune donnée itérable, pas d'inclusion)
+func checkNode(node parse.Node, allowed []string) error {
+	switch n := node.(type) {
+	case nil, *parse.TextNode, *parse.CommentNode:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkNode(child, allowed); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.ActionNode:
+		return checkPipe(n.Pipe, allowed)
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode, allowed)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode, allowed)
+	case *parse.RangeNode:
+		return errors.New("range interdit")
+	case *parse.TemplateNode:
+		return errors.New("template interdit")
+	default:
+		return fmt.Errorf("construction interdite : %s", node)
+	}
+}
+
+func checkBranch(branch *parse.BranchNode, allowed []string) error {
+	if err := checkPipe(branch.Pipe, allowed); err != nil {
+		return err
+	}
+	if err := checkNode(branch.List, allowed); err != nil {
+		return err
+	}
+	return checkNode(branch.ElseList, allowed)
+}
+
+func checkPipe(pipe *parse.PipeNode, allowed []string) error {
+	if pipe == nil {
+		return nil
+	}
+	for _, command := range pipe.Cmds {
+		for _, arg := range command.Args {
+			if err := checkArg(arg, allowed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkArg(arg parse.Node, allowed []string) error {
+	switch a := arg.(type) {
+	case *parse.FieldNode:
+		return checkField(a.Ident, allowed)
+	case *parse.VariableNode:
+		// $x variable locale, $.name donnée racine
+		if a.Ident[0] == "$" && len(a.Ident) > 1 {
+			return checkField(a.Ident[1:], allowed)
+		}
+		if len(a.Ident) > 1 {
+			return fmt.Errorf("accès interdit : %s", a)
+		}
+		return nil
+	case *parse.IdentifierNode:
+		if !slices.Contains(emailTemplateFuncs, a.Ident) {
+			return fmt.Errorf("fonction interdite : %s", a.Ident)
+		}
+		return nil
+	case *parse.PipeNode:
+		return checkPipe(a, allowed)
+	case *parse.StringNode, *parse.NumberNode, *parse.BoolNode, *parse.NilNode:
+		return nil
+	case *parse.DotNode:
+		return errors.New("{{.}} interdit : référencer une variable autorisée")
+	default:
+		return fmt.Errorf("construction interdite : %s", arg)
+	}
+}
+
+func checkField(ident []string, allowed []string) error {
+	if len(ident) != 1 {
+		return fmt.Errorf("accès interdit : .%s", ident[0])
+	}
+	if !slices.Contains(allowed, ident[0]) {
+		return fmt.Errorf("variable non autorisée : .%s (autorisées : %v)", ident[0], allowed)
+	}
+	return nil
+}
+
+// builtinFuncs parse.Parse exige la table des fonctions connues ; seules les clés comptent (valeurs non nil)
+func builtinFuncs() map[string]any {
+	funcs := make(map[string]any, len(emailTemplateFuncs))
+	for _, name := range emailTemplateFuncs {
+		funcs[name] = true
+	}
+	// Connues de text/template : refusées par checkArg plutôt que par le parseur, message plus clair
+	for _, name := range []string{"call", "index", "slice"} {
+		funcs[name] = true
+	}
+	return funcs
+}
+
+// limitedBuffer refuse d'écrire au-delà de limit : interrompt l'exécution d'un modèle trop prolixe
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errRenderedEmailTooLarge
+	}
+	return b.Buffer.Write(p)
+}
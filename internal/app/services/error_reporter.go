@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// REPORTER PAR DÉFAUT (journalisation)
+// =============================================================================
+
+// LogErrorReporter implémente usecases.ErrorReporter en journalisant l'erreur et sa stack
+// Utilisé quand aucun service de suivi d'erreurs n'est configuré
+type LogErrorReporter struct {
+	logger usecases.Logger
+}
+
+func NewLogErrorReporter(logger usecases.Logger) *LogErrorReporter {
+	return &LogErrorReporter{logger: logger}
+}
+
+func (r *LogErrorReporter) Report(ctx context.Context, err error, stack []byte, fields map[string]interface{}) {
+	logFields := reportFields(ctx, fields)
+	if stack != nil {
+		logFields["stack"] = string(stack)
+	}
+	r.logger.Error("Unexpected error", err, logFields)
+}
+
+// =============================================================================
+// REPORTER SENTRY (API "store", compatible Sentry et GlitchTip)
+// =============================================================================
+
+// SentryErrorReporter envoie chaque erreur comme un événement Sentry
+// En cas d'échec d'envoi, l'erreur est journalisée pour ne jamais être perdue
+type SentryErrorReporter struct {
+	endpoint    string
+	auth        string
+	environment string
+	client      *http.Client
+	fallback    *LogErrorReporter
+}
+
+// NewSentryErrorReporter lit un DSN de la forme https://<clé>@<hôte>/<projet>
+func NewSentryErrorReporter(dsn, environment string, logger usecases.Logger, clients *httpclient.Factory) (*SentryErrorReporter, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.User == nil || parsed.User.Username() == "" {
+		return nil, errors.New("DSN Sentry invalide")
+	}
+	project := strings.Trim(parsed.Path, "/")
+	if project == "" {
+		return nil, errors.New("DSN Sentry invalide : projet manquant")
+	}
+
+	return &SentryErrorReporter{
+		endpoint:    fmt.Sprintf("%s://%s/api/%s/store/", parsed.Scheme, parsed.Host, project),
+		auth:        "Sentry sentry_version=7, sentry_client=clean-archi-analytics/1.0, sentry_key=" + parsed.User.Username(),
+		environment: environment,
+		client:      clients.Client("sentry", httpclient.ClientOptions{Timeout: 3 * time.Second}),
+		fallback:    NewLogErrorReporter(logger),
+	}, nil
+}
+
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Environment string                 `json:"environment,omitempty"`
+	Message     string                 `json:"message"`
+	Exception   sentryExceptions       `json:"exception"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (r *SentryErrorReporter) Report(ctx context.Context, err error, stack []byte, fields map[string]interface{}) {
+	extra := reportFields(ctx, fields)
+	if stack != nil {
+		extra["stack"] = string(stack)
+	}
+
+	tags := make(map[string]string, 2)
+	for _, key := range []string{"use_case", "request_id"} {
+		if value, ok := extra[key].(string); ok && value != "" {
+			tags[key] = value
+		}
+	}
+
+	event := sentryEvent{
+		EventID:     randomHex(16),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Level:       "error",
+		Platform:    "go",
+		Environment: r.environment,
+		Message:     err.Error(),
+		Exception:   sentryExceptions{Values: []sentryException{{Type: fmt.Sprintf("%T", err), Value: err.Error()}}},
+		Tags:        tags,
+		Extra:       extra,
+	}
+
+	if sendErr := r.send(ctx, event); sendErr != nil {
+		extra["report_error"] = sendErr.Error()
+		r.fallback.Report(ctx, err, nil, extra)
+	}
+}
+
+func (r *SentryErrorReporter) send(ctx context.Context, event sentryEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Le signalement survit à l'annulation de la requête qui a échoué
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry: statut %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// reportFields copie les champs et y ajoute l'identifiant de requête
+func reportFields(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(fields)+2)
+	for key, value := range fields {
+		copied[key] = value
+	}
+	if requestID := usecases.RequestIDFromContext(ctx); requestID != "" {
+		copied["request_id"] = requestID
+	}
+	return copied
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"bufio"
+	"clean-archi-analytics/internal/domain/entities"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NDJSONEventFormat implémente usecases.EventArchiveFormat : un événement JSON par ligne
+//
+//	{"id":12,"name":"checkout.completed","user_id":4,"properties":{"plan":"pro"},"occurred_at":"...","received_at":"...","sample_rate":1}
+type NDJSONEventFormat struct{}
+
+func NewNDJSONEventFormat() NDJSONEventFormat {
+	return NDJSONEventFormat{}
+}
+
+func (NDJSONEventFormat) Name() string { return "ndjson" }
+
+// archivedEvent colonnes d'un événement archivé, partagées par tous les formats
+type archivedEvent struct {
+	ID         int64          `json:"id"`
+	Name       string         `json:"name"`
+	UserID     int            `json:"user_id,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	ReceivedAt time.Time      `json:"received_at"`
+	SampleRate float64        `json:"sample_rate"`
+}
+
+func (NDJSONEventFormat) Encode(w io.Writer, events []*entities.TrackedEvent) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	for _, event := range events {
+		err := encoder.Encode(archivedEvent{
+			ID:         event.ID,
+			Name:       event.Name,
+			UserID:     event.UserID,
+			Properties: event.Properties,
+			OccurredAt: event.OccurredAt,
+			ReceivedAt: event.ReceivedAt,
+			SampleRate: event.SampleRate,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+func (NDJSONEventFormat) Decode(r io.Reader) ([]*entities.TrackedEvent, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	var events []*entities.TrackedEvent
+	for line := 1; ; line++ {
+		var record archivedEvent
+		if err := decoder.Decode(&record); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("ndjson : événement %d : %w", line, err)
+		}
+		// ID d'origine non repris : le stockage en attribue un nouveau
+		events = append(events, &entities.TrackedEvent{
+			Name:       record.Name,
+			UserID:     record.UserID,
+			Properties: record.Properties,
+			OccurredAt: record.OccurredAt,
+			ReceivedAt: record.ReceivedAt,
+			SampleRate: record.SampleRate,
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"sync"
+)
+
+// EventHandler traite un événement du domaine
+type EventHandler func(ctx context.Context, event events.Event) error
+
+// AllEvents abonne un handler à tous les événements
+const AllEvents = "*"
+
+// InMemoryEventBus implémente usecases.EventPublisher avec une diffusion synchrone
+// Les handlers sont appelés dans l'ordre d'abonnement ; une erreur est journalisée
+// sans interrompre les autres abonnés
+type InMemoryEventBus struct {
+	mutex    sync.RWMutex
+	handlers map[string][]EventHandler
+	logger   usecases.Logger
+}
+
+func NewInMemoryEventBus(logger usecases.Logger) *InMemoryEventBus {
+	return &InMemoryEventBus{
+		handlers: make(map[string][]EventHandler),
+		logger:   logger,
+	}
+}
+
+// Subscribe abonne un handler à un nom d'événement (ou AllEvents)
+func (b *InMemoryEventBus) Subscribe(eventName string, handler EventHandler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.handlers[eventName] = append(b.handlers[eventName], handler)
+}
+
+func (b *InMemoryEventBus) Publish(ctx context.Context, evts ...events.Event) {
+	// La publication survit à l'annulation de la requête : l'écriture a déjà eu lieu
+	ctx = context.WithoutCancel(ctx)
+
+	for _, event := range evts {
+		b.mutex.RLock()
+		handlers := append(append([]EventHandler{}, b.handlers[event.EventName()]...), b.handlers[AllEvents]...)
+		b.mutex.RUnlock()
+
+		for _, handler := range handlers {
+			if err := handler(ctx, event); err != nil {
+				b.logger.Error("Event handler failed", err, map[string]interface{}{
+					"event": event.EventName(),
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// RÈGLES D'ÉVALUATION (communes à toutes les implémentations)
+// =============================================================================
+
+// FlagRule décrit l'activation d'un flag
+// Ordre d'évaluation : listes explicites (users, tenants), puis pourcentage, puis Enabled
+type FlagRule struct {
+	Enabled    bool     `json:"enabled"`
+	Percentage int      `json:"percentage,omitempty"` // 0-100, rollout progressif par utilisateur
+	Users      []int    `json:"users,omitempty"`
+	Tenants    []string `json:"tenants,omitempty"`
+}
+
+func (r FlagRule) evaluate(flag string, actor usecases.Actor, known bool) bool {
+	if known {
+		for _, id := range r.Users {
+			if id == actor.UserID {
+				return true
+			}
+		}
+		for _, tenant := range r.Tenants {
+			if tenant == actor.TenantID {
+				return true
+			}
+		}
+		if r.Percentage > 0 && actor.UserID != 0 {
+			return rolloutBucket(flag, actor.UserID) < r.Percentage
+		}
+	}
+	return r.Enabled
+}
+
+// rolloutBucket place un utilisateur dans [0, 100) de façon stable pour un flag donné
+// Le nom du flag entre dans le hash pour que les rollouts ne ciblent pas toujours les mêmes utilisateurs
+func rolloutBucket(flag string, userID int) int {
+	h := fnv.New32a()
+	h.Write([]byte(flag))
+	h.Write([]byte(":" + strconv.Itoa(userID)))
+	return int(h.Sum32() % 100)
+}
+
+// =============================================================================
+// STATIC FEATURE FLAGS (fichier JSON + surcharges par variables d'environnement)
+// =============================================================================
+
+// StaticFeatureFlags implémente usecases.FeatureFlags à partir de règles locales (remplacées par Reload)
+type StaticFeatureFlags struct {
+	mutex sync.RWMutex
+	rules map[string]FlagRule
+}
+
+func NewStaticFeatureFlags(rules map[string]FlagRule) *StaticFeatureFlags {
+	return &StaticFeatureFlags{rules: rules}
+}
+
+// LoadStaticFeatureFlags lit les règles depuis un fichier JSON optionnel ({"flag": {...}})
+// puis applique les variables FEATURE_FLAG_<NOM>=true|false|<pourcentage>
+// Exemple : FEATURE_FLAG_USERS_CURSOR_PAGINATION=25 pour users.cursor_pagination
+func LoadStaticFeatureFlags(path string, environ []string) (*StaticFeatureFlags, error) {
+	rules, err := loadFlagRules(path, environ)
+	if err != nil {
+		return nil, err
+	}
+	return NewStaticFeatureFlags(rules), nil
+}
+
+// Reload relit les règles comme LoadStaticFeatureFlags ; en cas d'erreur, les règles en place sont conservées
+func (f *StaticFeatureFlags) Reload(path string, environ []string) error {
+	rules, err := loadFlagRules(path, environ)
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	f.rules = rules
+	f.mutex.Unlock()
+	return nil
+}
+
+func loadFlagRules(path string, environ []string) (map[string]FlagRule, error) {
+	rules := make(map[string]FlagRule)
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &rules); err != nil {
+			return nil, errors.New("fichier de feature flags invalide")
+		}
+	}
+
+	for _, entry := range environ {
+		key, value, found := strings.Cut(entry, "=")
+		name, isFlag := strings.CutPrefix(key, "FEATURE_FLAG_")
+		if !found || !isFlag {
+			continue
+		}
+
+		flag := envNameToFlag(name)
+		rule := rules[flag]
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			rule.Enabled = enabled
+			rule.Percentage = 0
+		} else if percentage, err := strconv.Atoi(value); err == nil && percentage >= 0 && percentage <= 100 {
+			rule.Percentage = percentage
+		} else {
+			return nil, errors.New(key + ": valeur de feature flag invalide")
+		}
+		rules[flag] = rule
+	}
+
+	return rules, nil
+}
+
+// envNameToFlag convertit USERS_CURSOR_PAGINATION en users.cursor_pagination
+// (le premier segment devient le namespace)
+func envNameToFlag(name string) string {
+	name = strings.ToLower(name)
+	namespace, rest, found := strings.Cut(name, "_")
+	if !found {
+		return name
+	}
+	return namespace + "." + rest
+}
+
+func (f *StaticFeatureFlags) IsEnabled(ctx context.Context, flag string) bool {
+	f.mutex.RLock()
+	rule, ok := f.rules[flag]
+	f.mutex.RUnlock()
+	if !ok {
+		return false
+	}
+	actor, known := usecases.ActorFromContext(ctx)
+	return rule.evaluate(flag, actor, known)
+}
+
+// =============================================================================
+// REMOTE FEATURE FLAGS (service de flags externe, interrogé périodiquement)
+// =============================================================================
+
+// RemoteFeatureFlags implémente usecases.FeatureFlags en synchronisant les règles
+// depuis un service HTTP qui renvoie le même format JSON que le fichier
+// En cas d'indisponibilité, les dernières règles connues (ou le fallback) restent actives
+type RemoteFeatureFlags struct {
+	url      string
+	client   *http.Client
+	interval time.Duration
+	fallback usecases.FeatureFlags
+	logger   usecases.Logger
+
+	mutex sync.RWMutex
+	rules map[string]FlagRule
+}
+
+func NewRemoteFeatureFlags(url string, interval time.Duration, fallback usecases.FeatureFlags, logger usecases.Logger, clients *httpclient.Factory) *RemoteFeatureFlags {
+	return &RemoteFeatureFlags{
+		url:      url,
+		client:   clients.Client("feature_flags", httpclient.ClientOptions{Timeout: 5 * time.Second}),
+		interval: interval,
+		fallback: fallback,
+		logger:   logger,
+	}
+}
+
+// Start charge les règles puis les rafraîchit jusqu'à l'annulation du context
+func (f *RemoteFeatureFlags) Start(ctx context.Context) {
+	f.refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (f *RemoteFeatureFlags) refresh(ctx context.Context) {
+	rules, err := f.fetch(ctx)
+	if err != nil {
+		f.logger.Error("Failed to refresh feature flags", err, map[string]interface{}{
+			"url": f.url,
+		})
+		return
+	}
+
+	f.mutex.Lock()
+	f.rules = rules
+	f.mutex.Unlock()
+}
+
+func (f *RemoteFeatureFlags) fetch(ctx context.Context) (map[string]FlagRule, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("feature flag service returned " + resp.Status)
+	}
+
+	rules := make(map[string]FlagRule)
+	if err := json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (f *RemoteFeatureFlags) IsEnabled(ctx context.Context, flag string) bool {
+	f.mutex.RLock()
+	rule, ok := f.rules[flag]
+	f.mutex.RUnlock()
+
+	if !ok {
+		return f.fallback.IsEnabled(ctx, flag)
+	}
+	actor, known := usecases.ActorFromContext(ctx)
+	return rule.evaluate(flag, actor, known)
+}
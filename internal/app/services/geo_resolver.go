@@ -0,0 +1,104 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CIDRGeoResolver implémente usecases.GeoResolver à partir d'un fichier CSV de réseaux :
+//
+//	network,country,city,latitude,longitude
+//	81.250.0.0/16,FR,Paris,48.8566,2.3522
+//	2a01:cb00::/24,FR
+//
+// Ville et coordonnées sont facultatives ; sans coordonnées, la règle de voyage impossible
+// ne s'applique pas. Le réseau le plus précis l'emporte. Recherche linéaire : prévu pour quelques milliers de
+// réseaux (plages des bureaux, des partenaires, agrégats par pays), pas pour une base complète
+type CIDRGeoResolver struct {
+	networks []geoNetwork
+}
+
+type geoNetwork struct {
+	prefix   netip.Prefix
+	location entities.GeoLocation
+}
+
+// LoadCIDRGeoResolver lit le fichier ; la première ligne est ignorée si c'est l'en-tête
+func LoadCIDRGeoResolver(path string) (*CIDRGeoResolver, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	resolver := &CIDRGeoResolver{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("base de géolocalisation invalide : %w", err)
+		}
+		if line == 1 && strings.EqualFold(record[0], "network") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("base de géolocalisation invalide, ligne %d : network,country[,city[,latitude,longitude]] attendu", line)
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("base de géolocalisation invalide, ligne %d : %w", line, err)
+		}
+		network := geoNetwork{prefix: prefix.Masked(), location: entities.GeoLocation{Country: strings.ToUpper(strings.TrimSpace(record[1]))}}
+		if len(record) > 2 {
+			network.location.City = strings.TrimSpace(record[2])
+		}
+		if len(record) > 4 {
+			latitude, latErr := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+			longitude, lonErr := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+			if latErr != nil || lonErr != nil || math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+				return nil, fmt.Errorf("base de géolocalisation invalide, ligne %d : coordonnées", line)
+			}
+			network.location.Latitude, network.location.Longitude = latitude, longitude
+		}
+		resolver.networks = append(resolver.networks, network)
+	}
+
+	// Plus précis d'abord : le premier réseau qui contient l'adresse est le bon
+	slices.SortStableFunc(resolver.networks, func(a, b geoNetwork) int {
+		return b.prefix.Bits() - a.prefix.Bits()
+	})
+	return resolver, nil
+}
+
+func (r *CIDRGeoResolver) Resolve(ctx context.Context, ip string) (*entities.GeoLocation, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil, nil
+	}
+	addr = addr.Unmap()
+	if addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return nil, nil
+	}
+	for _, network := range r.networks {
+		if network.prefix.Contains(addr) {
+			location := network.location
+			return &location, nil
+		}
+	}
+	return nil, nil
+}
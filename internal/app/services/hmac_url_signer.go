@@ -0,0 +1,80 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Paramètres ajoutés aux liens signés
+const (
+	signedURLExpires   = "expires"
+	signedURLSignature = "signature"
+)
+
+// HMACURLSigner implémente usecases.URLSigner : signature = HMAC-SHA256(clé, chemin + "\n" +
+// paramètres triés, expiration comprise), en base64url. Modifier le chemin, un paramètre ou
+// l'expiration invalide le lien ; changer la clé invalide tous les liens en cours
+type HMACURLSigner struct {
+	key []byte
+}
+
+func NewHMACURLSigner(key string) (*HMACURLSigner, error) {
+	if len(key) < 32 {
+		return nil, fmt.Errorf("clé de signature des liens trop courte : %d octets, 32 minimum", len(key))
+	}
+	return &HMACURLSigner{key: []byte(key)}, nil
+}
+
+func (s *HMACURLSigner) Sign(path string, params url.Values, expires time.Time) url.Values {
+	signed := url.Values{}
+	for name, values := range params {
+		signed[name] = append([]string(nil), values...)
+	}
+	signed.Del(signedURLSignature)
+	signed.Set(signedURLExpires, strconv.FormatInt(expires.Unix(), 10))
+	signed.Set(signedURLSignature, s.signature(path, signed))
+	return signed
+}
+
+// Verify la signature est contrôlée avant l'expiration : une expiration falsifiée est invalide.
+// Une signature présente plusieurs fois est refusée, même si l'une d'elles est correcte
+func (s *HMACURLSigner) Verify(path string, params url.Values, now time.Time) error {
+	if len(params[signedURLSignature]) != 1 {
+		return usecases.ErrDownloadLinkInvalid
+	}
+	signature := params.Get(signedURLSignature)
+	if signature == "" {
+		return usecases.ErrDownloadLinkInvalid
+	}
+	unsigned := url.Values{}
+	for name, values := range params {
+		if name != signedURLSignature {
+			unsigned[name] = values
+		}
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signature(path, unsigned))) {
+		return usecases.ErrDownloadLinkInvalid
+	}
+
+	expires, err := strconv.ParseInt(params.Get(signedURLExpires), 10, 64)
+	if err != nil {
+		return usecases.ErrDownloadLinkInvalid
+	}
+	if !now.Before(time.Unix(expires, 0)) {
+		return usecases.ErrDownloadLinkExpired
+	}
+	return nil
+}
+
+// signature url.Values.Encode trie les paramètres : l'ordre de la requête reçue est indifférent
+func (s *HMACURLSigner) signature(path string, params url.Values) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path + "\n" + params.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	urlSignerTestKey  = "0123456789abcdef0123456789abcdef"
+	urlSignerTestPath = "/downloads/exports/42"
+)
+
+var urlSignerTestNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func newURLSignerTest(t *testing.T, key string) *HMACURLSigner {
+	t.Helper()
+	signer, err := NewHMACURLSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// parseSignedQuery requête reçue telle quelle, dans l'ordre écrit
+func parseSignedQuery(t *testing.T, query string) url.Values {
+	t.Helper()
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return params
+}
+
+func TestHMACURLSignerVerify(t *testing.T) {
+	signer := newURLSignerTest(t, urlSignerTestKey)
+	expires := urlSignerTestNow.Add(time.Hour)
+	signed := signer.Sign(urlSignerTestPath, url.Values{"format": {"csv"}, "tenant": {"acme"}}, expires)
+	query := signed.Encode()
+	signature := signed.Get(signedURLSignature)
+	expiresParam := signed.Get(signedURLExpires)
+	unsigned := strings.Replace(query, "&signature="+signature, "", 1)
+
+	other := newURLSignerTest(t, strings.Repeat("k", 32)).Sign(urlSignerTestPath, url.Values{"format": {"csv"}, "tenant": {"acme"}}, expires)
+
+	tests := []struct {
+		name    string
+		path    string
+		query   string
+		at      time.Time
+		wantErr error
+	}{
+		{"lien signé", urlSignerTestPath, query, urlSignerTestNow, nil},
+		{"paramètres réordonnés", urlSignerTestPath, "tenant=acme&signature=" + signature + "&expires=" + expiresParam + "&format=csv", urlSignerTestNow, nil},
+		{"dernière seconde", urlSignerTestPath, query, expires.Add(-time.Second), nil},
+		{"chemin modifié", "/downloads/exports/43", query, urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"chemin préfixé", "/downloads/exports/42/x", query, urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"autre route", "/downloads/audit", query, urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"paramètre modifié", urlSignerTestPath, strings.Replace(query, "tenant=acme", "tenant=globex", 1), urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"paramètre ajouté", urlSignerTestPath, query + "&user_id=1", urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"paramètre retiré", urlSignerTestPath, strings.Replace(query, "format=csv&", "", 1), urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"paramètre dupliqué", urlSignerTestPath, query + "&tenant=globex", urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"paramètre dupliqué en tête", urlSignerTestPath, "tenant=globex&" + query, urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"expiration repoussée", urlSignerTestPath, strings.Replace(query, "expires="+expiresParam, "expires=9999999999", 1), urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"expiration dupliquée", urlSignerTestPath, query + "&expires=9999999999", urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"expiration retirée", urlSignerTestPath, strings.Replace(query, "expires="+expiresParam+"&", "", 1), urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"lien expiré", urlSignerTestPath, query, expires, usecases.ErrDownloadLinkExpired},
+		{"lien expiré depuis longtemps", urlSignerTestPath, query, expires.Add(24 * time.Hour), usecases.ErrDownloadLinkExpired},
+		{"signature d'une autre clé", urlSignerTestPath, other.Encode(), urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"signature tronquée", urlSignerTestPath, strings.Replace(query, signature, signature[:len(signature)-1], 1), urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"signature avec padding", urlSignerTestPath, strings.Replace(query, signature, signature+"%3D", 1), urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"signature vide", urlSignerTestPath, unsigned + "&signature=", urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"signature absente", urlSignerTestPath, unsigned, urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"signature dupliquée", urlSignerTestPath, query + "&signature=x", urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+		{"signature répétée", urlSignerTestPath, query + "&signature=" + signature, urlSignerTestNow, usecases.ErrDownloadLinkInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := signer.Verify(tt.path, parseSignedQuery(t, tt.query), tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erreur %v, attendu %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Sign remplace une signature fournie dans params et ne modifie pas params
+func TestHMACURLSignerSign(t *testing.T) {
+	signer := newURLSignerTest(t, urlSignerTestKey)
+	params := url.Values{"format": {"csv"}, signedURLSignature: {"forged"}}
+	signed := signer.Sign(urlSignerTestPath, params, urlSignerTestNow.Add(time.Hour))
+
+	if len(signed[signedURLSignature]) != 1 || signed.Get(signedURLSignature) == "forged" {
+		t.Fatalf("signature %v", signed[signedURLSignature])
+	}
+	if params.Get(signedURLSignature) != "forged" || params.Has(signedURLExpires) {
+		t.Fatalf("paramètres modifiés : %v", params)
+	}
+	if err := signer.Verify(urlSignerTestPath, signed, urlSignerTestNow); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewHMACURLSignerRejectsShortKey(t *testing.T) {
+	if _, err := NewHMACURLSigner(urlSignerTestKey[:31]); err == nil {
+		t.Fatal("clé de 31 octets acceptée")
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hrMaxPages garde-fou contre un curseur de pagination qui ne se termine jamais
+const hrMaxPages = 1000
+
+// HRUserProvider implémente usecases.ExternalUserProvider avec l'API REST du SIRH :
+//
+//	GET {baseURL}/employees?page=N  (Authorization: Bearer <token>)
+//	{"data": [{"id": "E-042", "work_email": "...", "first_name": "...", ...}], "next_page": 2}
+type HRUserProvider struct {
+	baseURL string
+	token   string
+	client  *http.Client
+	logger  usecases.Logger
+}
+
+func NewHRUserProvider(baseURL, token string, logger usecases.Logger, clients *httpclient.Factory) *HRUserProvider {
+	return &HRUserProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  clients.Client("hr_sync", httpclient.ClientOptions{Timeout: 30 * time.Second}),
+		logger:  logger,
+	}
+}
+
+func (p *HRUserProvider) Source() string {
+	return "hr"
+}
+
+// hrEmployee format "employee" du SIRH ; il ne sort jamais de ce fichier
+type hrEmployee struct {
+	ID               string `json:"id"`
+	WorkEmail        string `json:"work_email"`
+	FirstName        string `json:"first_name"`
+	LastName         string `json:"last_name"`
+	PreferredName    string `json:"preferred_name"`
+	EmploymentStatus string `json:"employment_status"` // active, on_leave, terminated
+}
+
+type hrEmployeesPage struct {
+	Data     []hrEmployee `json:"data"`
+	NextPage *int         `json:"next_page"`
+}
+
+func (p *HRUserProvider) FetchUsers(ctx context.Context) ([]usecases.ExternalUser, error) {
+	var users []usecases.ExternalUser
+	page := 1
+	for range hrMaxPages {
+		result, err := p.fetchPage(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, employee := range result.Data {
+			user, err := translateHREmployee(employee)
+			if err != nil {
+				// Un enregistrement inexploitable ne bloque pas la synchronisation des autres
+				p.logger.Warn("HR employee ignored", map[string]interface{}{"employee_id": employee.ID, "error": err.Error()})
+				continue
+			}
+			users = append(users, user)
+		}
+
+		if result.NextPage == nil {
+			return users, nil
+		}
+		if *result.NextPage <= page {
+			return nil, errors.New("hr: pagination invalide")
+		}
+		page = *result.NextPage
+	}
+	return nil, fmt.Errorf("hr: plus de %d pages", hrMaxPages)
+}
+
+func (p *HRUserProvider) fetchPage(ctx context.Context, page int) (*hrEmployeesPage, error) {
+	endpoint := p.baseURL + "/employees?" + url.Values{"page": {strconv.Itoa(page)}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("hr: " + resp.Status)
+	}
+
+	var result hrEmployeesPage
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("hr: réponse invalide : %w", err)
+	}
+	return &result, nil
+}
+
+// translateHREmployee traduit un salarié en compte externe
+// - nom affiché : nom d'usage s'il est renseigné, sinon prénom + nom
+// - congé : le compte reste actif ; départ : il est désactivé
+func translateHREmployee(employee hrEmployee) (usecases.ExternalUser, error) {
+	if strings.TrimSpace(employee.ID) == "" {
+		return usecases.ExternalUser{}, errors.New("identifiant manquant")
+	}
+	if strings.TrimSpace(employee.WorkEmail) == "" {
+		return usecases.ExternalUser{}, errors.New("email professionnel manquant")
+	}
+
+	name := strings.TrimSpace(employee.PreferredName)
+	if name == "" {
+		name = strings.TrimSpace(strings.TrimSpace(employee.FirstName) + " " + strings.TrimSpace(employee.LastName))
+	}
+
+	var active bool
+	switch employee.EmploymentStatus {
+	case "active", "on_leave":
+		active = true
+	case "terminated":
+		active = false
+	default:
+		return usecases.ExternalUser{}, fmt.Errorf("statut d'emploi inconnu %q", employee.EmploymentStatus)
+	}
+
+	return usecases.ExternalUser{
+		ExternalID: strings.TrimSpace(employee.ID),
+		Email:      strings.ToLower(strings.TrimSpace(employee.WorkEmail)),
+		Name:       name,
+		Active:     active,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"bufio"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrIPListNotLoaded la liste n'a encore jamais pu être chargée
+var ErrIPListNotLoaded = errors.New("liste d'adresses non chargée")
+
+// SourceIPList implémente usecases.IPList à partir d'un fichier local ou d'une URL http(s)
+// (liste publique des nœuds de sortie TOR...) : une adresse ou un réseau CIDR par ligne,
+// lignes vides et commentaires (#) ignorés. Refresh recharge la source ; en cas d'échec,
+// la dernière liste connue reste active
+type SourceIPList struct {
+	source string
+	client *http.Client
+
+	mutex     sync.RWMutex
+	loaded    bool
+	addresses map[netip.Addr]struct{}
+	networks  []netip.Prefix
+}
+
+func NewSourceIPList(name, source string, clients *httpclient.Factory) *SourceIPList {
+	return &SourceIPList{
+		source: source,
+		client: clients.Client(name, httpclient.ClientOptions{Timeout: 30 * time.Second}),
+	}
+}
+
+// Refresh relit la source
+func (l *SourceIPList) Refresh(ctx context.Context) error {
+	body, err := l.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	addresses := make(map[netip.Addr]struct{})
+	var networks []netip.Prefix
+	scanner := bufio.NewScanner(body)
+	for line := 1; scanner.Scan(); line++ {
+		entry, _, _ := strings.Cut(scanner.Text(), "#")
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return fmt.Errorf("liste d'adresses invalide, ligne %d : %w", line, err)
+			}
+			networks = append(networks, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return fmt.Errorf("liste d'adresses invalide, ligne %d : %w", line, err)
+		}
+		addresses[addr.Unmap()] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	l.loaded, l.addresses, l.networks = true, addresses, networks
+	l.mutex.Unlock()
+	return nil
+}
+
+func (l *SourceIPList) open(ctx context.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(l.source, "http://") && !strings.HasPrefix(l.source, "https://") {
+		return os.Open(l.source)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.New("ip list source returned " + resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (l *SourceIPList) Contains(ctx context.Context, ip string) (bool, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false, nil
+	}
+	addr = addr.Unmap()
+
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if !l.loaded {
+		return false, ErrIPListNotLoaded
+	}
+	if _, ok := l.addresses[addr]; ok {
+		return true, nil
+	}
+	for _, network := range l.networks {
+		if network.Contains(addr) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bufio"
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// JSONLinesExportWriter implémente usecases.AnonymizedDataWriter : un enregistrement JSON par ligne,
+// discriminé par "type", prêt à être rejoué dans la base de staging
+//
+//	{"type":"user","user":{"id":1,"email":"...",...}}
+//	{"type":"activity","activity":{"user_id":1,"kind":"user.created","at":"..."}}
+//	{"type":"event","event":{"aggregate_id":1,"version":1,"name":"user.registered","data":{...}}}
+type JSONLinesExportWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func NewJSONLinesExportWriter(w io.Writer) *JSONLinesExportWriter {
+	buffered := bufio.NewWriter(w)
+	return &JSONLinesExportWriter{writer: buffered, encoder: json.NewEncoder(buffered)}
+}
+
+type exportRecord struct {
+	Type     string          `json:"type"`
+	User     *entities.User  `json:"user,omitempty"`
+	Activity *exportActivity `json:"activity,omitempty"`
+	Event    *exportEvent    `json:"event,omitempty"`
+}
+
+type exportActivity struct {
+	UserID int       `json:"user_id"`
+	Kind   string    `json:"kind"`
+	Fields []string  `json:"fields,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+type exportEvent struct {
+	AggregateID int       `json:"aggregate_id"`
+	Version     int       `json:"version"`
+	Name        string    `json:"name"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	Data        any       `json:"data"`
+}
+
+func (w *JSONLinesExportWriter) WriteUser(ctx context.Context, user *entities.User) error {
+	return w.encoder.Encode(exportRecord{Type: "user", User: user})
+}
+
+func (w *JSONLinesExportWriter) WriteActivity(ctx context.Context, entry repositories.ActivityEntry) error {
+	return w.encoder.Encode(exportRecord{Type: "activity", Activity: &exportActivity{
+		UserID: entry.UserID,
+		Kind:   entry.Kind,
+		Fields: entry.Fields,
+		At:     entry.At,
+	}})
+}
+
+func (w *JSONLinesExportWriter) WriteEvent(ctx context.Context, event repositories.StoredEvent) error {
+	return w.encoder.Encode(exportRecord{Type: "event", Event: &exportEvent{
+		AggregateID: event.AggregateID,
+		Version:     event.Version,
+		Name:        event.Event.EventName(),
+		RecordedAt:  event.RecordedAt,
+		Data:        event.Event,
+	}})
+}
+
+// Flush écrit les lignes encore en mémoire tampon ; à appeler après l'export
+func (w *JSONLinesExportWriter) Flush() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,656 @@
+package services
+
+import (
+	"bufio"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// ANNUAIRE LDAP / ACTIVE DIRECTORY (LDAPv3 : bind simple + recherche)
+// =============================================================================
+
+// LDAPDirectoryConfig paramètres de connexion à l'annuaire
+type LDAPDirectoryConfig struct {
+	// URL ldaps://host:636 (ou ldap://host:389, en clair : réseau de confiance uniquement)
+	URL string
+	// BindDN / BindPassword compte de service utilisé pour la recherche ; vide = recherche anonyme
+	BindDN       string
+	BindPassword string
+	// BaseDN racine de la recherche des utilisateurs
+	BaseDN string
+	// UserFilter filtre RFC 4515 ; %s reçoit l'identifiant saisi, échappé
+	// (ex: "(mail=%s)", "(sAMAccountName=%s)" pour Active Directory)
+	UserFilter string
+	Timeout    time.Duration
+}
+
+// Attributs lus sur l'entrée de l'utilisateur (communs à OpenLDAP et Active Directory)
+var ldapUserAttributes = []string{"mail", "displayName", "cn", "memberOf"}
+
+// Codes de résultat LDAP (RFC 4511 §4.1.9)
+const (
+	ldapResultSuccess            = 0
+	ldapResultInvalidCredentials = 49
+)
+
+// ldapMaxMessageSize garde-fou contre une réponse démesurée
+const ldapMaxMessageSize = 1 << 20
+
+// LDAPDirectory implémente usecases.Directory : le compte de service recherche l'entrée
+// de l'identifiant, puis un bind avec son DN et le mot de passe saisi le vérifie
+type LDAPDirectory struct {
+	config LDAPDirectoryConfig
+}
+
+func NewLDAPDirectory(config LDAPDirectoryConfig) (*LDAPDirectory, error) {
+	parsed, err := url.Parse(config.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "ldap" && parsed.Scheme != "ldaps") {
+		return nil, errors.New("ldap: URL invalide (ldap:// ou ldaps://)")
+	}
+	if strings.Count(config.UserFilter, "%s") != 1 {
+		return nil, errors.New("ldap: le filtre utilisateur doit contenir exactement un %s")
+	}
+	if _, err := compileLDAPFilter(fmt.Sprintf(config.UserFilter, "x")); err != nil {
+		return nil, fmt.Errorf("ldap: filtre utilisateur invalide : %w", err)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	return &LDAPDirectory{config: config}, nil
+}
+
+func (d *LDAPDirectory) Source() string {
+	return "ldap"
+}
+
+func (d *LDAPDirectory) Authenticate(ctx context.Context, login, password string) (*usecases.DirectoryEntry, error) {
+	// Un bind avec mot de passe vide est un bind anonyme (RFC 4513 §5.1.2) : il réussirait
+	if login == "" || password == "" {
+		return nil, usecases.ErrInvalidCredentials
+	}
+
+	conn, err := d.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	if d.config.BindDN != "" {
+		if err := conn.bind(d.config.BindDN, d.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("ldap: bind du compte de service : %w", err)
+		}
+	}
+
+	filter, err := compileLDAPFilter(fmt.Sprintf(d.config.UserFilter, escapeLDAPFilterValue(login)))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := conn.search(d.config.BaseDN, filter, ldapUserAttributes)
+	if err != nil {
+		return nil, err
+	}
+	switch len(entries) {
+	case 0:
+		return nil, usecases.ErrDirectoryUserNotFound
+	case 1:
+	default:
+		return nil, errors.New("ldap: plusieurs entrées correspondent à l'identifiant")
+	}
+
+	entry := entries[0]
+	if err := conn.bind(entry.dn, password); err != nil {
+		var result *ldapResultError
+		if errors.As(err, &result) && result.code == ldapResultInvalidCredentials {
+			return nil, usecases.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	name := entry.first("displayName")
+	if name == "" {
+		name = entry.first("cn")
+	}
+	return &usecases.DirectoryEntry{
+		DN:     entry.dn,
+		Email:  entry.first("mail"),
+		Name:   name,
+		Groups: entry.attributes["memberof"],
+	}, nil
+}
+
+// =============================================================================
+// CONNEXION
+// =============================================================================
+
+type ldapConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageID int
+	stop      func() bool
+}
+
+func (d *LDAPDirectory) dial(ctx context.Context) (*ldapConn, error) {
+	parsed, _ := url.Parse(d.config.URL)
+	host, port := parsed.Hostname(), parsed.Port()
+	if port == "" {
+		port = "389"
+		if parsed.Scheme == "ldaps" {
+			port = "636"
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: d.config.Timeout}
+	var conn net.Conn
+	var err error
+	if parsed.Scheme == "ldaps" {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ldap: %w", err)
+	}
+
+	deadline := time.Now().Add(d.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	return &ldapConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		// L'annulation de la requête interrompt les lectures en cours
+		stop: context.AfterFunc(ctx, func() { conn.Close() }),
+	}, nil
+}
+
+func (c *ldapConn) close() {
+	c.stop()
+	c.messageID++
+	c.conn.Write(berTLV(berSequence, berInt(berInteger, c.messageID), berTLV(ldapUnbindRequest)))
+	c.conn.Close()
+}
+
+// send écrit une requête et retourne son identifiant de message
+func (c *ldapConn) send(op []byte) (int, error) {
+	c.messageID++
+	if _, err := c.conn.Write(berTLV(berSequence, berInt(berInteger, c.messageID), op)); err != nil {
+		return 0, fmt.Errorf("ldap: %w", err)
+	}
+	return c.messageID, nil
+}
+
+// receive lit la prochaine réponse à la requête messageID
+func (c *ldapConn) receive(messageID int) (berElement, error) {
+	for {
+		message, err := readBER(c.reader)
+		if err != nil {
+			return berElement{}, fmt.Errorf("ldap: %w", err)
+		}
+		children, err := message.children()
+		if err != nil || len(children) < 2 {
+			return berElement{}, errors.New("ldap: message invalide")
+		}
+		id, err := children[0].int()
+		if err != nil {
+			return berElement{}, errors.New("ldap: message invalide")
+		}
+		// Message 0 : notification non sollicitée (Notice of Disconnection...)
+		if id == 0 {
+			return berElement{}, errors.New("ldap: connexion fermée par le serveur")
+		}
+		if id == messageID {
+			return children[1], nil
+		}
+	}
+}
+
+func (c *ldapConn) bind(dn, password string) error {
+	messageID, err := c.send(berTLV(ldapBindRequest,
+		berInt(berInteger, 3),
+		berTLV(berOctetString, []byte(dn)),
+		berTLV(ldapSimpleAuth, []byte(password)),
+	))
+	if err != nil {
+		return err
+	}
+
+	response, err := c.receive(messageID)
+	if err != nil {
+		return err
+	}
+	if response.tag != ldapBindResponse {
+		return errors.New("ldap: réponse de bind inattendue")
+	}
+	return parseLDAPResult(response)
+}
+
+type ldapEntry struct {
+	dn string
+	// attributes valeurs par nom d'attribut en minuscules (les noms LDAP sont insensibles à la casse)
+	attributes map[string][]string
+}
+
+func (e ldapEntry) first(attribute string) string {
+	if values := e.attributes[strings.ToLower(attribute)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c *ldapConn) search(baseDN string, filter []byte, attributes []string) ([]ldapEntry, error) {
+	requested := make([][]byte, len(attributes))
+	for i, attribute := range attributes {
+		requested[i] = berTLV(berOctetString, []byte(attribute))
+	}
+	messageID, err := c.send(berTLV(ldapSearchRequest,
+		berTLV(berOctetString, []byte(baseDN)),
+		berInt(berEnumerated, 2), // wholeSubtree
+		berInt(berEnumerated, 0), // neverDerefAliases
+		berInt(berInteger, 2),    // deux entrées suffisent à détecter une ambiguïté
+		berInt(berInteger, 0),
+		berTLV(berBoolean, []byte{0}),
+		filter,
+		berTLV(berSequence, requested...),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ldapEntry
+	for {
+		response, err := c.receive(messageID)
+		if err != nil {
+			return nil, err
+		}
+		switch response.tag {
+		case ldapSearchResultEntry:
+			entry, err := parseLDAPEntry(response)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case ldapSearchResultReference:
+			// Les referrals vers d'autres serveurs ne sont pas suivis
+		case ldapSearchResultDone:
+			if err := parseLDAPResult(response); err != nil {
+				return nil, err
+			}
+			return entries, nil
+		default:
+			return nil, errors.New("ldap: réponse de recherche inattendue")
+		}
+	}
+}
+
+func parseLDAPEntry(response berElement) (ldapEntry, error) {
+	children, err := response.children()
+	if err != nil || len(children) < 2 {
+		return ldapEntry{}, errors.New("ldap: entrée invalide")
+	}
+	entry := ldapEntry{dn: string(children[0].content), attributes: make(map[string][]string)}
+
+	attributes, err := children[1].children()
+	if err != nil {
+		return ldapEntry{}, errors.New("ldap: entrée invalide")
+	}
+	for _, attribute := range attributes {
+		parts, err := attribute.children()
+		if err != nil || len(parts) != 2 {
+			return ldapEntry{}, errors.New("ldap: attribut invalide")
+		}
+		values, err := parts[1].children()
+		if err != nil {
+			return ldapEntry{}, errors.New("ldap: attribut invalide")
+		}
+		name := strings.ToLower(string(parts[0].content))
+		for _, value := range values {
+			entry.attributes[name] = append(entry.attributes[name], string(value.content))
+		}
+	}
+	return entry, nil
+}
+
+// ldapResultError résultat LDAP en échec
+type ldapResultError struct {
+	code    int
+	message string
+}
+
+func (e *ldapResultError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("ldap: code de résultat %d", e.code)
+	}
+	return fmt.Sprintf("ldap: code de résultat %d (%s)", e.code, e.message)
+}
+
+// parseLDAPResult LDAPResult ::= SEQUENCE { resultCode, matchedDN, diagnosticMessage, ... }
+func parseLDAPResult(response berElement) error {
+	children, err := response.children()
+	if err != nil || len(children) < 3 {
+		return errors.New("ldap: résultat invalide")
+	}
+	code, err := children[0].int()
+	if err != nil {
+		return errors.New("ldap: résultat invalide")
+	}
+	if code != ldapResultSuccess {
+		return &ldapResultError{code: code, message: string(children[2].content)}
+	}
+	return nil
+}
+
+// =============================================================================
+// FILTRES (RFC 4515 : &, |, !, égalité, présence, sous-chaînes)
+// =============================================================================
+
+// escapeLDAPFilterValue échappe une valeur insérée dans un filtre (injection LDAP)
+func escapeLDAPFilterValue(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '*', '(', ')', 0:
+			fmt.Fprintf(&builder, "\\%02x", c)
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
+
+func compileLDAPFilter(filter string) ([]byte, error) {
+	encoded, rest, err := parseLDAPFilter(strings.TrimSpace(filter))
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.New("caractères après la fin du filtre")
+	}
+	return encoded, nil
+}
+
+func parseLDAPFilter(filter string) ([]byte, string, error) {
+	if len(filter) < 2 || filter[0] != '(' {
+		return nil, "", errors.New("parenthèse ouvrante attendue")
+	}
+	filter = filter[1:]
+
+	switch filter[0] {
+	case '&', '|', '!':
+		tag := byte(ldapFilterAnd)
+		switch filter[0] {
+		case '|':
+			tag = ldapFilterOr
+		case '!':
+			tag = ldapFilterNot
+		}
+		rest := filter[1:]
+		var children [][]byte
+		for strings.HasPrefix(rest, "(") {
+			child, next, err := parseLDAPFilter(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			children = append(children, child)
+			rest = next
+		}
+		if !strings.HasPrefix(rest, ")") {
+			return nil, "", errors.New("parenthèse fermante attendue")
+		}
+		if len(children) == 0 || (tag == ldapFilterNot && len(children) != 1) {
+			return nil, "", errors.New("opérateur sans opérande valide")
+		}
+		return berTLV(tag, children...), rest[1:], nil
+	}
+
+	end := strings.IndexByte(filter, ')')
+	if end < 0 {
+		return nil, "", errors.New("parenthèse fermante attendue")
+	}
+	item, err := parseLDAPFilterItem(filter[:end])
+	if err != nil {
+		return nil, "", err
+	}
+	return item, filter[end+1:], nil
+}
+
+func parseLDAPFilterItem(item string) ([]byte, error) {
+	attribute, value, found := strings.Cut(item, "=")
+	if !found || attribute == "" {
+		return nil, fmt.Errorf("comparaison invalide %q", item)
+	}
+	if strings.ContainsAny(attribute, "<>~:") {
+		return nil, fmt.Errorf("opérateur non pris en charge dans %q", item)
+	}
+
+	if value == "*" {
+		return berTLV(ldapFilterPresent, []byte(attribute)), nil
+	}
+	if !strings.Contains(value, "*") {
+		decoded, err := unescapeLDAPFilterValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return berTLV(ldapFilterEqual, berTLV(berOctetString, []byte(attribute)), berTLV(berOctetString, decoded)), nil
+	}
+
+	// Sous-chaînes : initial*any*...*final
+	parts := strings.Split(value, "*")
+	var substrings [][]byte
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		decoded, err := unescapeLDAPFilterValue(part)
+		if err != nil {
+			return nil, err
+		}
+		tag := byte(ldapSubstringAny)
+		switch i {
+		case 0:
+			tag = ldapSubstringInitial
+		case len(parts) - 1:
+			tag = ldapSubstringFinal
+		}
+		substrings = append(substrings, berTLV(tag, decoded))
+	}
+	if len(substrings) == 0 {
+		return nil, fmt.Errorf("sous-chaîne vide dans %q", item)
+	}
+	return berTLV(ldapFilterSubstrings, berTLV(berOctetString, []byte(attribute)), berTLV(berSequence, substrings...)), nil
+}
+
+// unescapeLDAPFilterValue décode les séquences \XX d'une valeur de filtre
+func unescapeLDAPFilterValue(value string) ([]byte, error) {
+	var decoded []byte
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			decoded = append(decoded, value[i])
+			continue
+		}
+		if i+2 >= len(value) {
+			return nil, errors.New("séquence d'échappement incomplète")
+		}
+		b, err := hex.DecodeString(value[i+1 : i+3])
+		if err != nil {
+			return nil, errors.New("séquence d'échappement invalide")
+		}
+		decoded = append(decoded, b[0])
+		i += 2
+	}
+	return decoded, nil
+}
+
+// =============================================================================
+// ENCODAGE BER (sous-ensemble utilisé par LDAP, longueurs définies uniquement)
+// =============================================================================
+
+const (
+	berBoolean     = 0x01
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berEnumerated  = 0x0a
+	berSequence    = 0x30
+
+	ldapBindRequest           = 0x60
+	ldapBindResponse          = 0x61
+	ldapUnbindRequest         = 0x42
+	ldapSearchRequest         = 0x63
+	ldapSearchResultEntry     = 0x64
+	ldapSearchResultDone      = 0x65
+	ldapSearchResultReference = 0x73
+	ldapSimpleAuth            = 0x80
+
+	ldapFilterAnd        = 0xa0
+	ldapFilterOr         = 0xa1
+	ldapFilterNot        = 0xa2
+	ldapFilterEqual      = 0xa3
+	ldapFilterSubstrings = 0xa4
+	ldapFilterPresent    = 0x87
+	ldapSubstringInitial = 0x80
+	ldapSubstringAny     = 0x81
+	ldapSubstringFinal   = 0x82
+)
+
+func berTLV(tag byte, contents ...[]byte) []byte {
+	length := 0
+	for _, content := range contents {
+		length += len(content)
+	}
+	encoded := append([]byte{tag}, berLength(length)...)
+	for _, content := range contents {
+		encoded = append(encoded, content...)
+	}
+	return encoded
+}
+
+func berLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+	var octets []byte
+	for ; length > 0; length >>= 8 {
+		octets = append([]byte{byte(length)}, octets...)
+	}
+	return append([]byte{0x80 | byte(len(octets))}, octets...)
+}
+
+// berInt entier positif, encodé sur le minimum d'octets
+func berInt(tag byte, value int) []byte {
+	var octets []byte
+	for {
+		octets = append([]byte{byte(value)}, octets...)
+		if value >>= 8; value == 0 {
+			break
+		}
+	}
+	if octets[0]&0x80 != 0 {
+		octets = append([]byte{0}, octets...)
+	}
+	return berTLV(tag, octets)
+}
+
+// berElement élément décodé (identifiant sur un octet : suffisant pour LDAP)
+type berElement struct {
+	tag     byte
+	content []byte
+}
+
+func (e berElement) int() (int, error) {
+	if len(e.content) == 0 || len(e.content) > 4 {
+		return 0, errors.New("entier BER invalide")
+	}
+	value := int(int8(e.content[0]))
+	for _, octet := range e.content[1:] {
+		value = value<<8 | int(octet)
+	}
+	return value, nil
+}
+
+func (e berElement) children() ([]berElement, error) {
+	var children []berElement
+	for data := e.content; len(data) > 0; {
+		if len(data) < 2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		length, header, err := decodeBERLength(data[1:])
+		if err != nil {
+			return nil, err
+		}
+		start := 1 + header
+		if length > len(data)-start {
+			return nil, io.ErrUnexpectedEOF
+		}
+		children = append(children, berElement{tag: data[0], content: data[start : start+length]})
+		data = data[start+length:]
+	}
+	return children, nil
+}
+
+// decodeBERLength retourne la longueur et le nombre d'octets qui l'encodent
+// (Active Directory encode souvent les longueurs sur 4 octets, même petites)
+func decodeBERLength(data []byte) (int, int, error) {
+	if len(data) == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if data[0] < 0x80 {
+		return int(data[0]), 1, nil
+	}
+	count := int(data[0] & 0x7f)
+	if count == 0 || count > 4 {
+		return 0, 0, errors.New("longueur BER non prise en charge")
+	}
+	if len(data) < 1+count {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	length := 0
+	for _, octet := range data[1 : 1+count] {
+		length = length<<8 | int(octet)
+	}
+	return length, 1 + count, nil
+}
+
+// readBER lit un élément complet depuis le flux
+func readBER(reader *bufio.Reader) (berElement, error) {
+	tag, err := reader.ReadByte()
+	if err != nil {
+		return berElement{}, err
+	}
+	first, err := reader.ReadByte()
+	if err != nil {
+		return berElement{}, err
+	}
+	lengthBytes := []byte{first}
+	if first >= 0x80 {
+		extra := make([]byte, int(first&0x7f))
+		if _, err := io.ReadFull(reader, extra); err != nil {
+			return berElement{}, err
+		}
+		lengthBytes = append(lengthBytes, extra...)
+	}
+	length, _, err := decodeBERLength(lengthBytes)
+	if err != nil {
+		return berElement{}, err
+	}
+	if length > ldapMaxMessageSize {
+		return berElement{}, errors.New("message LDAP trop volumineux")
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(reader, content); err != nil {
+		return berElement{}, err
+	}
+	return berElement{tag: tag, content: content}, nil
+}
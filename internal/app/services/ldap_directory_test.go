@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+// newLDAPTestConn connexion reliée à un faux serveur : serve reçoit chaque message décodé
+// et retourne les messages à renvoyer
+func newLDAPTestConn(t *testing.T, serve func(request berElement) [][]byte) *ldapConn {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	go func() {
+		reader := bufio.NewReader(server)
+		for {
+			request, err := readBER(reader)
+			if err != nil {
+				return
+			}
+			for _, response := range serve(request) {
+				if _, err := server.Write(response); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return &ldapConn{conn: client, reader: bufio.NewReader(client), stop: func() bool { return true }}
+}
+
+func ldapTestMessage(messageID int, op []byte) []byte {
+	return berTLV(berSequence, berInt(berInteger, messageID), op)
+}
+
+func ldapTestResult(tag byte, code int, message string) []byte {
+	return berTLV(tag, berInt(berEnumerated, code), berTLV(berOctetString), berTLV(berOctetString, []byte(message)))
+}
+
+func mustHex(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := hex.DecodeString(strings.ReplaceAll(value, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestBERIntRoundTrip(t *testing.T) {
+	tests := []struct {
+		value   int
+		encoded string
+	}{
+		{0, "02 01 00"},
+		{127, "02 01 7f"},
+		// Bit de poids fort à 1 : octet nul ajouté pour rester positif
+		{128, "02 02 00 80"},
+		{256, "02 02 01 00"},
+		{65535, "02 03 00 ff ff"},
+		{1 << 24, "02 04 01 00 00 00"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.value), func(t *testing.T) {
+			encoded := berInt(berInteger, tt.value)
+			if want := mustHex(t, tt.encoded); !bytes.Equal(encoded, want) {
+				t.Fatalf("encodage % x, attendu % x", encoded, want)
+			}
+			element, err := readBER(bufio.NewReader(bytes.NewReader(encoded)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value, err := element.int(); err != nil || value != tt.value {
+				t.Fatalf("décodage %d (%v), attendu %d", value, err, tt.value)
+			}
+		})
+	}
+}
+
+func TestBERLengthRoundTrip(t *testing.T) {
+	for _, length := range []int{0, 127, 128, 255, 256, 70_000} {
+		content := bytes.Repeat([]byte{'x'}, length)
+		element, err := readBER(bufio.NewReader(bytes.NewReader(berTLV(berOctetString, content))))
+		if err != nil {
+			t.Fatalf("longueur %d : %v", length, err)
+		}
+		if element.tag != berOctetString || !bytes.Equal(element.content, content) {
+			t.Fatalf("longueur %d : contenu de %d octets", length, len(element.content))
+		}
+	}
+
+	// Active Directory encode les longueurs sur 4 octets, même petites
+	element, err := readBER(bufio.NewReader(bytes.NewReader(mustHex(t, "04 84 00 00 00 02 6f 6b"))))
+	if err != nil || string(element.content) != "ok" {
+		t.Fatalf("longueur longue : %q (%v)", element.content, err)
+	}
+	// Au-delà de 4 octets de longueur : refusé plutôt que de déborder
+	if _, err := readBER(bufio.NewReader(bytes.NewReader(mustHex(t, "04 85 00 00 00 00 02 6f 6b")))); err == nil {
+		t.Fatal("longueur sur 5 octets acceptée")
+	}
+	if _, err := readBER(bufio.NewReader(bytes.NewReader(mustHex(t, "04 05 6f 6b")))); err == nil {
+		t.Fatal("élément tronqué accepté")
+	}
+}
+
+func TestLDAPConnBind(t *testing.T) {
+	// BindRequest RFC 4511 §4.2 : messageID 1, version 3, simple "secret"
+	want := mustHex(t, "30 1a 02 01 01 60 15 02 01 03 04 08"+hex.EncodeToString([]byte("cn=admin"))+"80 06"+hex.EncodeToString([]byte("secret")))
+
+	tests := []struct {
+		name     string
+		code     int
+		wantCode int
+	}{
+		{"succès", ldapResultSuccess, 0},
+		{"identifiants invalides", ldapResultInvalidCredentials, ldapResultInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []byte
+			conn := newLDAPTestConn(t, func(request berElement) [][]byte {
+				received = berTLV(request.tag, request.content)
+				return [][]byte{ldapTestMessage(1, ldapTestResult(ldapBindResponse, tt.code, "diagnostic"))}
+			})
+
+			err := conn.bind("cn=admin", "secret")
+			if !bytes.Equal(received, want) {
+				t.Fatalf("requête % x, attendu % x", received, want)
+			}
+			var result *ldapResultError
+			switch {
+			case tt.wantCode == 0 && err != nil:
+				t.Fatalf("bind refusé : %v", err)
+			case tt.wantCode != 0 && (!errors.As(err, &result) || result.code != tt.wantCode):
+				t.Fatalf("erreur %v, attendu le code %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestLDAPConnSearch(t *testing.T) {
+	filter, err := compileLDAPFilter("(mail=alice@example.com)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Assez de groupes pour que l'entrée dépasse 127 octets (longueur en forme longue)
+	groups := make([][]byte, 10)
+	for i := range groups {
+		groups[i] = berTLV(berOctetString, fmt.Appendf(nil, "cn=group-%d,ou=groups,dc=example,dc=com", i))
+	}
+	entry := berTLV(ldapSearchResultEntry,
+		berTLV(berOctetString, []byte("uid=alice,dc=example,dc=com")),
+		berTLV(berSequence,
+			berTLV(berSequence, berTLV(berOctetString, []byte("mail")), berTLV(0x31, berTLV(berOctetString, []byte("alice@example.com")))),
+			berTLV(berSequence, berTLV(berOctetString, []byte("memberOf")), berTLV(0x31, groups...)),
+		),
+	)
+
+	var request []berElement
+	conn := newLDAPTestConn(t, func(message berElement) [][]byte {
+		parts, _ := message.children()
+		request, _ = parts[1].children()
+		return [][]byte{
+			// Réponse à un autre message : ignorée
+			ldapTestMessage(7, ldapTestResult(ldapSearchResultDone, 0, "")),
+			ldapTestMessage(1, entry),
+			ldapTestMessage(1, berTLV(ldapSearchResultReference, berTLV(berOctetString, []byte("ldap://other/")))),
+			ldapTestMessage(1, ldapTestResult(ldapSearchResultDone, ldapResultSuccess, "")),
+		}
+	})
+
+	entries, err := conn.search("dc=example,dc=com", filter, ldapUserAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(request) != 8 || string(request[0].content) != "dc=example,dc=com" || !bytes.Equal(berTLV(request[6].tag, request[6].content), filter) {
+		t.Fatalf("requête de recherche inattendue : %+v", request)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("%d entrées, attendu 1", len(entries))
+	}
+	if entries[0].dn != "uid=alice,dc=example,dc=com" || entries[0].first("MAIL") != "alice@example.com" {
+		t.Fatalf("entrée inattendue : %+v", entries[0])
+	}
+	if groups := entries[0].attributes["memberof"]; len(groups) != 10 || groups[9] != "cn=group-9,ou=groups,dc=example,dc=com" {
+		t.Fatalf("groupes inattendus : %v", groups)
+	}
+}
+
+func TestEscapeLDAPFilterValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"alice@example.com", "alice@example.com"},
+		{"*", `\2a`},
+		{"*)(uid=*", `\2a\29\28uid=\2a`},
+		{"admin)(|(uid=*", `admin\29\28|\28uid=\2a`},
+		{`a\b`, `a\5cb`},
+		{"a\x00b", `a\00b`},
+		{"élodie", "élodie"},
+	}
+	for _, tt := range tests {
+		if got := escapeLDAPFilterValue(tt.value); got != tt.want {
+			t.Errorf("escapeLDAPFilterValue(%q) = %q, attendu %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// describeLDAPFilter forme lisible d'un filtre compilé, pour comparer des structures
+func describeLDAPFilter(t *testing.T, filter string) string {
+	t.Helper()
+	compiled, err := compileLDAPFilter(filter)
+	if err != nil {
+		t.Fatalf("%s : %v", filter, err)
+	}
+	element, err := readBER(bufio.NewReader(bytes.NewReader(compiled)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var describe func(element berElement) string
+	describe = func(element berElement) string {
+		children, _ := element.children()
+		switch element.tag {
+		case ldapFilterAnd, ldapFilterOr, ldapFilterNot:
+			parts := make([]string, len(children))
+			for i, child := range children {
+				parts[i] = describe(child)
+			}
+			return fmt.Sprintf("%x(%s)", element.tag, strings.Join(parts, ","))
+		case ldapFilterEqual:
+			return fmt.Sprintf("%s=%q", children[0].content, children[1].content)
+		case ldapFilterPresent:
+			return fmt.Sprintf("%s=*", element.content)
+		}
+		return fmt.Sprintf("%x", element.tag)
+	}
+	return describe(element)
+}
+
+// Une valeur échappée reste une seule comparaison d'égalité sur la valeur littérale,
+// quel que soit le filtre utilisateur qui l'entoure
+func TestCompileLDAPFilterInjection(t *testing.T) {
+	inputs := []string{"*)(uid=*", "*", "admin)(|(uid=*", "x))(&(objectClass=*", `\2a`}
+	for _, userFilter := range []string{"(mail=%s)", "(&(objectClass=person)(uid=%s))"} {
+		for _, input := range inputs {
+			got := describeLDAPFilter(t, fmt.Sprintf(userFilter, escapeLDAPFilterValue(input)))
+			want := strings.Replace(describeLDAPFilter(t, fmt.Sprintf(userFilter, "placeholder")), `"placeholder"`, fmt.Sprintf("%q", input), 1)
+			if got != want {
+				t.Errorf("%s avec %q : %s, attendu %s", userFilter, input, got, want)
+			}
+		}
+	}
+
+	// Vecteur écrit à la main : *)(uid=* échappé donne une égalité, pas une présence
+	compiled, err := compileLDAPFilter("(mail=" + escapeLDAPFilterValue("*)(uid=*") + ")")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := mustHex(t, "a3 10 04 04"+hex.EncodeToString([]byte("mail"))+"04 08"+hex.EncodeToString([]byte("*)(uid=*"))); !bytes.Equal(compiled, want) {
+		t.Fatalf("filtre % x, attendu % x", compiled, want)
+	}
+
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LocalFileStorage implémente usecases.FileStorage sur un répertoire local :
+//
+//	<dir>/chunks/<upload>/<index>  fragments en cours d'envoi
+//	<dir>/files/<key>              fichiers assemblés ou produits
+//
+// Chaque écriture passe par un fichier temporaire renommé une fois complet : un fragment ou un
+// fichier interrompu n'est jamais lu à moitié. Un seul nœud : les fragments d'un upload doivent
+// tous arriver sur la même instance (répertoire partagé sinon)
+type LocalFileStorage struct {
+	dir string
+}
+
+func NewLocalFileStorage(dir string) (*LocalFileStorage, error) {
+	for _, sub := range []string{"chunks", "files"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalFileStorage{dir: dir}, nil
+}
+
+func (s *LocalFileStorage) Write(ctx context.Context, key string, write func(w io.Writer) error) error {
+	path, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, write)
+}
+
+func (s *LocalFileStorage) PutChunk(ctx context.Context, uploadID string, index int, data []byte) error {
+	dir, err := s.chunkDir(uploadID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, strconv.Itoa(index)), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+func (s *LocalFileStorage) AssembleChunks(ctx context.Context, uploadID string, count int, key string) error {
+	dir, err := s.chunkDir(uploadID)
+	if err != nil {
+		return err
+	}
+	path, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomic(path, func(w io.Writer) error {
+		for index := 0; index < count; index++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := appendFile(w, filepath.Join(dir, strconv.Itoa(index))); err != nil {
+				return fmt.Errorf("fragment %d : %w", index, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (s *LocalFileStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *LocalFileStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalFileStorage) DeleteChunks(ctx context.Context, uploadID string) error {
+	dir, err := s.chunkDir(uploadID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (s *LocalFileStorage) chunkDir(uploadID string) (string, error) {
+	if err := validateStorageName(uploadID); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, "chunks", uploadID), nil
+}
+
+func (s *LocalFileStorage) filePath(key string) (string, error) {
+	if err := validateStorageName(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, "files", key), nil
+}
+
+// validateStorageName un seul segment de chemin : aucune clé ne sort du répertoire de stockage
+func validateStorageName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+		return fmt.Errorf("nom de fichier de stockage invalide : %q", name)
+	}
+	return nil
+}
+
+// writeFileAtomic écrit path via un fichier temporaire du même répertoire, renommé une fois synchronisé
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func appendFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// SlogLogger implémente usecases.Logger au-dessus de log/slog (sortie JSON)
+// Le niveau minimal se change à chaud (usecases.LogLevelController), "info" par défaut
+type SlogLogger struct {
+	logger *slog.Logger
+	level  *slog.LevelVar
+}
+
+func NewSlogLogger() *SlogLogger {
+	level := new(slog.LevelVar)
+	return &SlogLogger{
+		logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})),
+		level:  level,
+	}
+}
+
+func (l *SlogLogger) LogLevel() string {
+	return strings.ToLower(l.level.Level().String())
+}
+
+// SetLogLevel "debug", "info", "warn" ou "error"
+func (l *SlogLogger) SetLogLevel(level string) error {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("niveau de log inconnu : %q", level)
+	}
+	l.level.Set(parsed)
+	return nil
+}
+
+func (l *SlogLogger) Info(message string, fields map[string]interface{}) {
+	l.logger.Info(message, toAttrs(fields)...)
+}
+
+func (l *SlogLogger) Warn(message string, fields map[string]interface{}) {
+	l.logger.Warn(message, toAttrs(fields)...)
+}
+
+func (l *SlogLogger) Error(message string, err error, fields map[string]interface{}) {
+	attrs := toAttrs(fields)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.logger.Error(message, attrs...)
+}
+
+func toAttrs(fields map[string]interface{}) []any {
+	attrs := make([]any, 0, len(fields))
+	for key, value := range fields {
+		attrs = append(attrs, slog.Any(key, value))
+	}
+	return attrs
+}
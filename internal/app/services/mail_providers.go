@@ -0,0 +1,498 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/awssig"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// AMAZON SES (API v2)
+// =============================================================================
+
+// SESMailProvider implémente usecases.MailProvider avec SES v2, requêtes signées SigV4 :
+//
+//	POST https://email.{region}.amazonaws.com/v2/email/outbound-emails
+//
+// Contenu simple, modèle SES (TemplateName / TemplateData) ou, avec des pièces jointes, message MIME
+// brut ; chaque catégorie devient une étiquette (EmailTags) reprise par les destinations d'événements
+type SESMailProvider struct {
+	endpoint    string
+	region      string
+	credentials awssig.Credentials
+	client      *http.Client
+}
+
+func NewSESMailProvider(region string, credentials awssig.Credentials, clients *httpclient.Factory) *SESMailProvider {
+	return &SESMailProvider{
+		endpoint:    "https://email." + region + ".amazonaws.com",
+		region:      region,
+		credentials: credentials,
+		client:      clients.Client("ses", httpclient.ClientOptions{Timeout: 10 * time.Second}),
+	}
+}
+
+func (p *SESMailProvider) Name() string { return "ses" }
+
+func (p *SESMailProvider) Send(ctx context.Context, mail usecases.Mail) error {
+	content := map[string]interface{}{}
+	switch {
+	case len(mail.Attachments) > 0 && mail.Template != "":
+		return errors.New("ses: pièces jointes et modèle ne peuvent être combinés")
+	case len(mail.Attachments) > 0:
+		raw, err := rawMIMEMessage(mail)
+		if err != nil {
+			return err
+		}
+		content["Raw"] = map[string]interface{}{"Data": raw}
+	case mail.Template != "":
+		data, err := json.Marshal(templateData(mail.TemplateData))
+		if err != nil {
+			return err
+		}
+		content["Template"] = map[string]interface{}{"TemplateName": mail.Template, "TemplateData": string(data)}
+	default:
+		body := map[string]interface{}{}
+		if mail.Text != "" {
+			body["Text"] = map[string]string{"Data": mail.Text, "Charset": "UTF-8"}
+		}
+		if mail.HTML != "" {
+			body["Html"] = map[string]string{"Data": mail.HTML, "Charset": "UTF-8"}
+		}
+		content["Simple"] = map[string]interface{}{
+			"Subject": map[string]string{"Data": mail.Subject, "Charset": "UTF-8"},
+			"Body":    body,
+		}
+	}
+
+	request := map[string]interface{}{
+		"FromEmailAddress": mail.From,
+		"Destination":      map[string][]string{"ToAddresses": {mail.To}},
+		"Content":          content,
+	}
+	if mail.ReplyTo != "" {
+		request["ReplyToAddresses"] = []string{mail.ReplyTo}
+	}
+	if len(mail.Categories) > 0 {
+		tags := make([]map[string]string, 0, len(mail.Categories))
+		for _, category := range mail.Categories {
+			tags = append(tags, map[string]string{"Name": category, "Value": "true"})
+		}
+		request["EmailTags"] = tags
+	}
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return p.call(ctx, http.MethodPost, "/v2/email/outbound-emails", encoded, nil)
+}
+
+// HealthCheck GetAccount : identifiants valides et envoi non suspendu pour la région
+func (p *SESMailProvider) HealthCheck(ctx context.Context) error {
+	var account struct {
+		SendingEnabled bool `json:"SendingEnabled"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/v2/email/account", nil, &account); err != nil {
+		return err
+	}
+	if !account.SendingEnabled {
+		return errors.New("ses: envoi désactivé pour ce compte dans la région " + p.region)
+	}
+	return nil
+}
+
+func (p *SESMailProvider) call(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, p.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	awssig.Sign(req, body, "ses", p.region, p.credentials, time.Now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&failure)
+		return fmt.Errorf("ses: statut %d : %s (%s)", resp.StatusCode, failure.Message, resp.Header.Get("X-Amzn-ErrorType"))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("ses: réponse invalide : %w", err)
+	}
+	return nil
+}
+
+// rawMIMEMessage message multipart/mixed : corps (texte et/ou HTML en multipart/alternative) puis
+// pièces jointes encodées en base64
+func rawMIMEMessage(mail usecases.Mail) ([]byte, error) {
+	var buf bytes.Buffer
+	message := multipart.NewWriter(&buf)
+	header := []string{
+		"From: " + mail.From,
+		"To: " + mail.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", mail.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + message.Boundary(),
+	}
+	if mail.ReplyTo != "" {
+		header = append(header, "Reply-To: "+mail.ReplyTo)
+	}
+	for _, line := range header {
+		if strings.ContainsAny(line, "\r\n") {
+			return nil, errors.New("en-tête d'email invalide")
+		}
+		buf.WriteString(line + "\r\n")
+	}
+	buf.WriteString("\r\n")
+
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{{"text/plain", mail.Text}, {"text/html", mail.HTML}} {
+		if part.content == "" {
+			continue
+		}
+		w, err := alternative.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+	w, err := message.CreatePart(textproto.MIMEHeader{"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range mail.Attachments {
+		w, err := message.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachmentContentType(attachment), map[string]string{"name": attachment.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+		for len(encoded) > 76 {
+			io.WriteString(w, encoded[:76]+"\r\n")
+			encoded = encoded[76:]
+		}
+		io.WriteString(w, encoded+"\r\n")
+	}
+	if err := message.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// =============================================================================
+// SENDGRID (API v3)
+// =============================================================================
+
+// SendGridMailProvider implémente usecases.MailProvider avec POST /v3/mail/send ; un modèle est un
+// modèle dynamique (template_id, dynamic_template_data), les catégories sont celles de SendGrid
+type SendGridMailProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func NewSendGridMailProvider(apiKey string, clients *httpclient.Factory) *SendGridMailProvider {
+	return &SendGridMailProvider{
+		apiKey:  apiKey,
+		baseURL: "https://api.sendgrid.com",
+		client:  clients.Client("sendgrid", httpclient.ClientOptions{Timeout: 10 * time.Second}),
+	}
+}
+
+func (p *SendGridMailProvider) Name() string { return "sendgrid" }
+
+func (p *SendGridMailProvider) Send(ctx context.Context, mail usecases.Mail) error {
+	personalization := map[string]interface{}{"to": []map[string]string{{"email": mail.To}}}
+	request := map[string]interface{}{
+		"from":             map[string]string{"email": mail.From},
+		"personalizations": []map[string]interface{}{personalization},
+	}
+	if mail.ReplyTo != "" {
+		request["reply_to"] = map[string]string{"email": mail.ReplyTo}
+	}
+	if mail.Subject != "" {
+		request["subject"] = mail.Subject
+	}
+	if mail.Template != "" {
+		request["template_id"] = mail.Template
+		personalization["dynamic_template_data"] = templateData(mail.TemplateData)
+	}
+	// SendGrid impose text/plain avant text/html
+	var content []map[string]string
+	if mail.Text != "" {
+		content = append(content, map[string]string{"type": "text/plain", "value": mail.Text})
+	}
+	if mail.HTML != "" {
+		content = append(content, map[string]string{"type": "text/html", "value": mail.HTML})
+	}
+	if len(content) > 0 {
+		request["content"] = content
+	}
+	if len(mail.Attachments) > 0 {
+		attachments := make([]map[string]string, 0, len(mail.Attachments))
+		for _, attachment := range mail.Attachments {
+			attachments = append(attachments, map[string]string{
+				"content":     base64.StdEncoding.EncodeToString(attachment.Content),
+				"filename":    attachment.Filename,
+				"type":        attachmentContentType(attachment),
+				"disposition": "attachment",
+			})
+		}
+		request["attachments"] = attachments
+	}
+	if len(mail.Categories) > 0 {
+		request["categories"] = mail.Categories
+	}
+
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := p.do(ctx, http.MethodPost, "/v3/mail/send", encoded)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// HealthCheck la clé d'API existe et porte le droit mail.send
+func (p *SendGridMailProvider) HealthCheck(ctx context.Context) error {
+	resp, err := p.do(ctx, http.MethodGet, "/v3/scopes", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("sendgrid: réponse invalide : %w", err)
+	}
+	if !slices.Contains(result.Scopes, "mail.send") {
+		return errors.New("sendgrid: la clé d'API n'a pas le droit mail.send")
+	}
+	return nil
+}
+
+// do retourne la réponse d'un appel réussi (2xx), à fermer par l'appelant
+func (p *SendGridMailProvider) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var failure struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&failure)
+		messages := make([]string, 0, len(failure.Errors))
+		for _, e := range failure.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, errors.New("sendgrid: " + resp.Status + ": " + strings.Join(messages, "; "))
+	}
+	return resp, nil
+}
+
+// =============================================================================
+// MAILGUN
+// =============================================================================
+
+// MailgunMailProvider implémente usecases.MailProvider avec POST /v3/{domaine}/messages (formulaire
+// multipart) ; un modèle est un modèle Mailgun (template, t:variables), une catégorie un tag (o:tag)
+type MailgunMailProvider struct {
+	apiKey  string
+	domain  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewMailgunMailProvider baseURL https://api.mailgun.net, ou https://api.eu.mailgun.net pour un domaine UE
+func NewMailgunMailProvider(apiKey, domain, baseURL string, clients *httpclient.Factory) *MailgunMailProvider {
+	return &MailgunMailProvider{
+		apiKey:  apiKey,
+		domain:  domain,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  clients.Client("mailgun", httpclient.ClientOptions{Timeout: 10 * time.Second}),
+	}
+}
+
+func (p *MailgunMailProvider) Name() string { return "mailgun" }
+
+func (p *MailgunMailProvider) Send(ctx context.Context, mail usecases.Mail) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	fields := [][2]string{{"from", mail.From}, {"to", mail.To}}
+	if mail.ReplyTo != "" {
+		fields = append(fields, [2]string{"h:Reply-To", mail.ReplyTo})
+	}
+	if mail.Subject != "" {
+		fields = append(fields, [2]string{"subject", mail.Subject})
+	}
+	if mail.Text != "" {
+		fields = append(fields, [2]string{"text", mail.Text})
+	}
+	if mail.HTML != "" {
+		fields = append(fields, [2]string{"html", mail.HTML})
+	}
+	if mail.Template != "" {
+		variables, err := json.Marshal(templateData(mail.TemplateData))
+		if err != nil {
+			return err
+		}
+		fields = append(fields, [2]string{"template", mail.Template}, [2]string{"t:variables", string(variables)})
+	}
+	for _, category := range mail.Categories {
+		fields = append(fields, [2]string{"o:tag", category})
+	}
+	for _, field := range fields {
+		if err := form.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+	for _, attachment := range mail.Attachments {
+		w, err := form.CreatePart(textproto.MIMEHeader{
+			"Content-Disposition": {mime.FormatMediaType("form-data", map[string]string{"name": "attachment", "filename": attachment.Filename})},
+			"Content-Type":        {attachmentContentType(attachment)},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(attachment.Content); err != nil {
+			return err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	resp, err := p.do(ctx, http.MethodPost, "/v3/"+url.PathEscape(p.domain)+"/messages", form.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// HealthCheck le domaine d'envoi existe pour cette clé et il est vérifié (DNS en place)
+func (p *MailgunMailProvider) HealthCheck(ctx context.Context) error {
+	resp, err := p.do(ctx, http.MethodGet, "/v3/domains/"+url.PathEscape(p.domain), "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Domain struct {
+			State string `json:"state"`
+		} `json:"domain"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("mailgun: réponse invalide : %w", err)
+	}
+	if result.Domain.State != "active" {
+		return fmt.Errorf("mailgun: domaine %s à l'état %q (vérification DNS attendue)", p.domain, result.Domain.State)
+	}
+	return nil
+}
+
+// do retourne la réponse d'un appel réussi (2xx), à fermer par l'appelant
+func (p *MailgunMailProvider) do(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth("api", p.apiKey)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var failure struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&failure)
+		return nil, errors.New("mailgun: " + resp.Status + ": " + failure.Message)
+	}
+	return resp, nil
+}
+
+// =============================================================================
+// UTILITAIRES
+// =============================================================================
+
+// templateData variables d'un modèle ; jamais null, les fournisseurs attendent un objet
+func templateData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return map[string]interface{}{}
+	}
+	return data
+}
+
+func attachmentContentType(attachment usecases.MailAttachment) string {
+	if attachment.ContentType == "" {
+		return "application/octet-stream"
+	}
+	return attachment.ContentType
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"database/sql"
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ExpvarMetrics implémente usecases.Metrics en publiant des compteurs via expvar
+// Les valeurs sont visibles sur /debug/vars
+type ExpvarMetrics struct {
+	mutex    sync.Mutex
+	calls    *expvar.Map
+	errors   *expvar.Map
+	duration *expvar.Map // durée cumulée en microsecondes
+}
+
+var (
+	expvarOnce    sync.Once
+	expvarMetrics *ExpvarMetrics
+)
+
+// NewExpvarMetrics retourne l'instance partagée (expvar interdit de publier deux fois un même nom)
+func NewExpvarMetrics() *ExpvarMetrics {
+	expvarOnce.Do(func() {
+		expvarMetrics = &ExpvarMetrics{
+			calls:    expvar.NewMap("usecase_calls_total"),
+			errors:   expvar.NewMap("usecase_errors_total"),
+			duration: expvar.NewMap("usecase_duration_us_total"),
+		}
+	})
+	return expvarMetrics
+}
+
+func (m *ExpvarMetrics) ObserveUseCase(name string, duration time.Duration, err error) {
+	m.calls.Add(name, 1)
+	m.duration.Add(name, duration.Microseconds())
+	if err != nil {
+		m.errors.Add(name, 1)
+	}
+}
+
+// =============================================================================
+// HISTOGRAMMES DE LATENCE DES REPOSITORIES
+// =============================================================================
+
+// latencyBuckets bornes supérieures des buckets (cumulatifs, façon Prometheus)
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// latencyHistogram histogramme d'une méthode, exposé en JSON sur /debug/vars
+type latencyHistogram struct {
+	Count   int64            `json:"count"`
+	Errors  int64            `json:"errors"`
+	SumUs   int64            `json:"sum_us"`
+	Buckets map[string]int64 `json:"buckets"` // "le_<borne>" -> nombre d'appels <= borne ; "le_inf" = total
+}
+
+var (
+	queryMetricsOnce sync.Once
+	queryMetrics     *ExpvarQueryMetrics
+)
+
+// ExpvarQueryMetrics implémente database.QueryMetrics avec un histogramme par repository/méthode
+type ExpvarQueryMetrics struct {
+	mutex      sync.Mutex
+	histograms map[string]*latencyHistogram
+}
+
+// NewExpvarQueryMetrics retourne l'instance partagée publiée sous "repository_latency"
+func NewExpvarQueryMetrics() *ExpvarQueryMetrics {
+	queryMetricsOnce.Do(func() {
+		queryMetrics = &ExpvarQueryMetrics{histograms: make(map[string]*latencyHistogram)}
+		expvar.Publish("repository_latency", expvar.Func(queryMetrics.snapshot))
+	})
+	return queryMetrics
+}
+
+func (m *ExpvarQueryMetrics) ObserveQuery(repository, method string, duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := repository + "." + method
+	histogram, ok := m.histograms[key]
+	if !ok {
+		histogram = &latencyHistogram{Buckets: make(map[string]int64, len(latencyBuckets)+1)}
+		m.histograms[key] = histogram
+	}
+
+	histogram.Count++
+	histogram.SumUs += duration.Microseconds()
+	if err != nil {
+		histogram.Errors++
+	}
+	for _, bound := range latencyBuckets {
+		if duration <= bound {
+			histogram.Buckets["le_"+bound.String()]++
+		}
+	}
+	histogram.Buckets["le_inf"]++
+}
+
+func (m *ExpvarQueryMetrics) snapshot() interface{} {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result := make(map[string]latencyHistogram, len(m.histograms))
+	for key, histogram := range m.histograms {
+		histogramCopy := *histogram
+		histogramCopy.Buckets = make(map[string]int64, len(histogram.Buckets))
+		for bucket, count := range histogram.Buckets {
+			histogramCopy.Buckets[bucket] = count
+		}
+		result[key] = histogramCopy
+	}
+	return result
+}
+
+// =============================================================================
+// SANTÉ DU POOL SQL
+// =============================================================================
+
+// sqlPoolStats vue JSON de sql.DBStats
+type sqlPoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// PublishSQLPoolStats expose les statistiques du pool sous "sql_pool_<name>" sur /debug/vars
+// Un wait_count qui grimpe indique un pool sous-dimensionné (MaxOpenConns)
+func PublishSQLPoolStats(name string, stats func() sql.DBStats) {
+	expvar.Publish("sql_pool_"+name, expvar.Func(func() interface{} {
+		s := stats()
+		return sqlPoolStats{
+			MaxOpenConnections: s.MaxOpenConnections,
+			OpenConnections:    s.OpenConnections,
+			InUse:              s.InUse,
+			Idle:               s.Idle,
+			WaitCount:          s.WaitCount,
+			WaitDurationMs:     s.WaitDuration.Milliseconds(),
+			MaxIdleClosed:      s.MaxIdleClosed,
+			MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  s.MaxLifetimeClosed,
+		}
+	}))
+}
+
+// =============================================================================
+// INGESTION DES ÉVÉNEMENTS ANALYTICS
+// =============================================================================
+
+// maxDroppedEventLabels noms d'événements suivis dans analytics_events_dropped_total ;
+// au-delà, les écartés sont comptés sous "(other)" (le compteur ne doit pas exploser lui-même)
+const maxDroppedEventLabels = 200
+
+var (
+	trackingMetricsOnce sync.Once
+	trackingMetrics     *ExpvarTrackingMetrics
+)
+
+// ExpvarTrackingMetrics implémente usecases.TrackingMetrics :
+//   - analytics_events_total : événements reçus par issue (accepted, sampled, dropped, no_consent)
+//   - analytics_events_dropped_total : événements écartés par nom d'événement
+type ExpvarTrackingMetrics struct {
+	outcomes *expvar.Map
+	dropped  *expvar.Map
+
+	mutex  sync.Mutex
+	labels map[string]bool
+}
+
+// NewExpvarTrackingMetrics retourne l'instance partagée
+func NewExpvarTrackingMetrics() *ExpvarTrackingMetrics {
+	trackingMetricsOnce.Do(func() {
+		trackingMetrics = &ExpvarTrackingMetrics{
+			outcomes: expvar.NewMap("analytics_events_total"),
+			dropped:  expvar.NewMap("analytics_events_dropped_total"),
+			labels:   make(map[string]bool),
+		}
+	})
+	return trackingMetrics
+}
+
+func (m *ExpvarTrackingMetrics) ObserveTrackedEvents(event, outcome string, count int) {
+	m.outcomes.Add(outcome, int64(count))
+	if outcome != usecases.TrackOutcomeDropped {
+		return
+	}
+
+	m.mutex.Lock()
+	if !m.labels[event] {
+		if len(m.labels) < maxDroppedEventLabels {
+			m.labels[event] = true
+		} else {
+			event = "(other)"
+		}
+	}
+	m.mutex.Unlock()
+	m.dropped.Add(event, int64(count))
+}
+
+// =============================================================================
+// APPELS HTTP SORTANTS
+// =============================================================================
+
+var (
+	httpClientMetricsOnce sync.Once
+	httpClientMetrics     *ExpvarHTTPClientMetrics
+)
+
+// ExpvarHTTPClientMetrics implémente httpclient.Metrics :
+//   - http_client_latency : histogramme par client/méthode (mêmes buckets que les repositories)
+//   - http_client_responses_total : réponses par client et statut ("stripe.502" ; "vault.0" = aucune réponse)
+type ExpvarHTTPClientMetrics struct {
+	latency   *ExpvarQueryMetrics
+	responses *expvar.Map
+}
+
+// NewExpvarHTTPClientMetrics retourne l'instance partagée
+func NewExpvarHTTPClientMetrics() *ExpvarHTTPClientMetrics {
+	httpClientMetricsOnce.Do(func() {
+		httpClientMetrics = &ExpvarHTTPClientMetrics{
+			latency:   &ExpvarQueryMetrics{histograms: make(map[string]*latencyHistogram)},
+			responses: expvar.NewMap("http_client_responses_total"),
+		}
+		expvar.Publish("http_client_latency", expvar.Func(httpClientMetrics.latency.snapshot))
+	})
+	return httpClientMetrics
+}
+
+func (m *ExpvarHTTPClientMetrics) ObserveRequest(client, method string, status int, duration time.Duration, err error) {
+	m.latency.ObserveQuery(client, method, duration, err)
+	m.responses.Add(client+"."+strconv.Itoa(status), 1)
+}
+
+// =============================================================================
+// FILES DES ÉLÉMENTS ABANDONNÉS
+// =============================================================================
+
+var (
+	deadLetterMetricsOnce sync.Once
+	deadLetterMetrics     *ExpvarDeadLetterMetrics
+)
+
+// ExpvarDeadLetterMetrics implémente usecases.DeadLetterMetrics :
+//   - dead_letter_depth : éléments abandonnés par file, à la dernière mesure
+type ExpvarDeadLetterMetrics struct {
+	depth *expvar.Map
+}
+
+// NewExpvarDeadLetterMetrics retourne l'instance partagée
+func NewExpvarDeadLetterMetrics() *ExpvarDeadLetterMetrics {
+	deadLetterMetricsOnce.Do(func() {
+		deadLetterMetrics = &ExpvarDeadLetterMetrics{depth: expvar.NewMap("dead_letter_depth")}
+	})
+	return deadLetterMetrics
+}
+
+func (m *ExpvarDeadLetterMetrics) ObserveDeadLetterDepth(queue string, depth int) {
+	gauge := new(expvar.Int)
+	gauge.Set(int64(depth))
+	m.depth.Set(queue, gauge)
+}
+
+// =============================================================================
+// RETOURS DU FOURNISSEUR D'EMAIL
+// =============================================================================
+
+var (
+	emailFeedbackMetricsOnce sync.Once
+	emailFeedbackMetrics     *ExpvarEmailFeedbackMetrics
+)
+
+// ExpvarEmailFeedbackMetrics implémente usecases.EmailFeedbackMetrics :
+//   - email_feedback_total : rebonds définitifs et plaintes reçus, par motif
+//   - email_suppressed_total : envois supprimés vers des adresses rejetées
+type ExpvarEmailFeedbackMetrics struct {
+	feedback   *expvar.Map
+	suppressed *expvar.Int
+}
+
+// NewExpvarEmailFeedbackMetrics retourne l'instance partagée
+func NewExpvarEmailFeedbackMetrics() *ExpvarEmailFeedbackMetrics {
+	emailFeedbackMetricsOnce.Do(func() {
+		emailFeedbackMetrics = &ExpvarEmailFeedbackMetrics{
+			feedback:   expvar.NewMap("email_feedback_total"),
+			suppressed: expvar.NewInt("email_suppressed_total"),
+		}
+	})
+	return emailFeedbackMetrics
+}
+
+func (m *ExpvarEmailFeedbackMetrics) ObserveEmailFeedback(reason string) {
+	m.feedback.Add(reason, 1)
+}
+
+func (m *ExpvarEmailFeedbackMetrics) ObserveSuppressedEmail() {
+	m.suppressed.Add(1)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"sync"
+)
+
+// notificationBuffer nombre de notifications en attente par client avant abandon
+const notificationBuffer = 16
+
+// NotificationHub implémente usecases.NotificationPusher en diffusant aux clients connectés
+// Un client lent ne bloque jamais l'émetteur : ses notifications sont abandonnées
+// (il les retrouvera dans sa boîte de réception)
+type NotificationHub struct {
+	mutex       sync.RWMutex
+	subscribers map[int]map[chan entities.Notification]struct{}
+	logger      usecases.Logger
+}
+
+func NewNotificationHub(logger usecases.Logger) *NotificationHub {
+	return &NotificationHub{
+		subscribers: make(map[int]map[chan entities.Notification]struct{}),
+		logger:      logger,
+	}
+}
+
+// Subscribe ouvre un flux de notifications pour un utilisateur ; unsubscribe doit être appelé à la déconnexion
+func (h *NotificationHub) Subscribe(userID int) (<-chan entities.Notification, func()) {
+	ch := make(chan entities.Notification, notificationBuffer)
+
+	h.mutex.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan entities.Notification]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mutex.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mutex.Lock()
+			defer h.mutex.Unlock()
+
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+func (h *NotificationHub) Push(ctx context.Context, notification *entities.Notification) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for ch := range h.subscribers[notification.UserID] {
+		select {
+		case ch <- *notification:
+		default:
+			h.logger.Info("Notification dropped for slow client", map[string]interface{}{
+				"user_id":         notification.UserID,
+				"notification_id": notification.ID,
+			})
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+)
+
+// =============================================================================
+// EMAIL NOTIFIER
+// =============================================================================
+
+// EmailNotifier implémente usecases.Notifier au-dessus de l'EmailSender
+type EmailNotifier struct {
+	emailSender usecases.EmailSender
+}
+
+func NewEmailNotifier(emailSender usecases.EmailSender) *EmailNotifier {
+	return &EmailNotifier{emailSender: emailSender}
+}
+
+func (n *EmailNotifier) Channel() entities.NotificationChannel { return entities.ChannelEmail }
+
+func (n *EmailNotifier) Notify(ctx context.Context, message usecases.NotificationMessage) error {
+	return n.emailSender.SendEmail(ctx, message.Email, message.Title, message.Body)
+}
+
+// =============================================================================
+// SMS NOTIFIER
+// =============================================================================
+
+// SMSClient interface d'un fournisseur SMS (modèle Twilio : expéditeur, destinataire, texte)
+type SMSClient interface {
+	SendMessage(ctx context.Context, from, to, body string) (messageID string, err error)
+}
+
+// SMSNotifier implémente usecases.Notifier au-dessus d'un SMSClient
+type SMSNotifier struct {
+	client SMSClient
+	from   string
+}
+
+func NewSMSNotifier(client SMSClient, from string) *SMSNotifier {
+	return &SMSNotifier{client: client, from: from}
+}
+
+func (n *SMSNotifier) Channel() entities.NotificationChannel { return entities.ChannelSMS }
+
+func (n *SMSNotifier) Notify(ctx context.Context, message usecases.NotificationMessage) error {
+	_, err := n.client.SendMessage(ctx, n.from, message.Phone, message.Title+" : "+message.Body)
+	return err
+}
+
+// =============================================================================
+// IN-APP NOTIFIER
+// =============================================================================
+
+// InAppNotifier implémente usecases.Notifier en déposant la notification dans la boîte de réception
+// puis en la poussant aux clients connectés
+type InAppNotifier struct {
+	notificationRepo repositories.NotificationRepository
+	pusher           usecases.NotificationPusher
+	clock            usecases.Clock
+}
+
+func NewInAppNotifier(notificationRepo repositories.NotificationRepository, pusher usecases.NotificationPusher, clock usecases.Clock) *InAppNotifier {
+	return &InAppNotifier{notificationRepo: notificationRepo, pusher: pusher, clock: clock}
+}
+
+func (n *InAppNotifier) Channel() entities.NotificationChannel { return entities.ChannelInApp }
+
+func (n *InAppNotifier) Notify(ctx context.Context, message usecases.NotificationMessage) error {
+	notification, err := entities.NewNotification(message.UserID, message.EventType, message.Title, message.Body, n.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	created, err := n.notificationRepo.Create(ctx, notification)
+	if err != nil {
+		return err
+	}
+
+	n.pusher.Push(ctx, created)
+	return nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// OPAPolicyEngine implémente usecases.PolicyEngine en interrogeant Open Policy Agent (API Data) :
+//
+//	POST {url}  {"input": {"action": "update_user", "access": "user", "actor": {...}, "resource": {...}}}
+//	→ {"result": true}
+//
+// url désigne la règle de décision, ex: http://opa:8181/v1/data/clean_archi/allow
+// Une règle non définie pour cette entrée (pas de "result") vaut refus
+type OPAPolicyEngine struct {
+	url    string
+	client *http.Client
+}
+
+func NewOPAPolicyEngine(url string, clients *httpclient.Factory) *OPAPolicyEngine {
+	return &OPAPolicyEngine{
+		url: url,
+		// Évaluée à chaque use case : un OPA lent ne doit pas bloquer l'application
+		client: clients.Client("opa", httpclient.ClientOptions{Timeout: 2 * time.Second}),
+	}
+}
+
+type opaDecision struct {
+	Result *bool `json:"result"`
+}
+
+func (e *OPAPolicyEngine) Allow(ctx context.Context, input usecases.PolicyInput) (bool, error) {
+	body, err := json.Marshal(map[string]interface{}{"input": policyInputDocument(input)})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("opa: statut %d", resp.StatusCode)
+	}
+
+	var decision opaDecision
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return false, fmt.Errorf("opa: réponse invalide : %w", err)
+	}
+	return decision.Result != nil && *decision.Result, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/json"
+)
+
+// OutboxDispatcher relaie les messages de l'outbox vers les systèmes externes
+// Un message en échec reste en file jusqu'à maxAttempts tentatives, puis rejoint la file des
+// messages abandonnés (usecases.OutboxDeadLetters)
+type OutboxDispatcher struct {
+	outboxRepo  repositories.OutboxRepository
+	emailSender usecases.EmailSender
+	clock       usecases.Clock
+	logger      usecases.Logger
+	batchSize   int
+	maxAttempts int
+}
+
+func NewOutboxDispatcher(
+	outboxRepo repositories.OutboxRepository,
+	emailSender usecases.EmailSender,
+	clock usecases.Clock,
+	logger usecases.Logger,
+	maxAttempts int,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo:  outboxRepo,
+		emailSender: emailSender,
+		clock:       clock,
+		logger:      logger,
+		batchSize:   50,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// DispatchPending envoie un lot de messages en attente
+func (d *OutboxDispatcher) DispatchPending(ctx context.Context) {
+	messages, err := d.outboxRepo.ListPending(ctx, d.batchSize, d.maxAttempts)
+	if err != nil {
+		d.logger.Error("Failed to list pending outbox messages", err, nil)
+		return
+	}
+
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := d.send(ctx, message); err != nil {
+			d.logger.Error("Failed to dispatch outbox message", err, map[string]interface{}{
+				"message_id": message.ID,
+				"kind":       message.Kind,
+				"attempt":    message.Attempts + 1,
+			})
+			if err := d.outboxRepo.MarkFailed(ctx, message.ID, err.Error(), d.clock.Now()); err != nil {
+				d.logger.Error("Failed to mark outbox message as failed", err, map[string]interface{}{
+					"message_id": message.ID,
+				})
+			}
+			continue
+		}
+
+		if err := d.outboxRepo.MarkSent(ctx, message.ID, d.clock.Now()); err != nil {
+			d.logger.Error("Failed to mark outbox message as sent", err, map[string]interface{}{
+				"message_id": message.ID,
+			})
+		}
+	}
+}
+
+func (d *OutboxDispatcher) send(ctx context.Context, message *repositories.OutboxMessage) error {
+	switch message.Kind {
+	case usecases.OutboxKindEmail:
+		var payload usecases.EmailPayload
+		if err := json.Unmarshal(message.Payload, &payload); err != nil {
+			return err
+		}
+		return d.emailSender.SendEmail(ctx, payload.To, payload.Subject, payload.Body)
+	default:
+		d.logger.Error("Unknown outbox message kind", nil, map[string]interface{}{
+			"message_id": message.ID,
+			"kind":       message.Kind,
+		})
+		return nil // Rien à réessayer : on le marque comme traité
+	}
+}
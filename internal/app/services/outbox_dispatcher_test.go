@@ -0,0 +1,66 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/infra/database"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// outboxTestSender refuse les emails adressés à failing
+type outboxTestSender struct {
+	usecases.EmailSender
+	failing string
+}
+
+func (s outboxTestSender) SendEmail(_ context.Context, to, _, _ string) error {
+	if to == s.failing {
+		return errors.New("smtp: 451 try again later")
+	}
+	return nil
+}
+
+type outboxTestLogger struct{}
+
+func (outboxTestLogger) Info(string, map[string]interface{})         {}
+func (outboxTestLogger) Warn(string, map[string]interface{})         {}
+func (outboxTestLogger) Error(string, error, map[string]interface{}) {}
+
+// Les dates d'envoi et d'échec viennent de l'horloge injectée
+func TestOutboxDispatcherUsesClock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+	outbox := database.NewInMemoryOutboxRepository()
+	for _, to := range []string{"alice@example.com", "bob@example.com"} {
+		payload, _ := json.Marshal(usecases.EmailPayload{To: to, Subject: "Digest", Body: "..."})
+		if _, err := outbox.Enqueue(ctx, &repositories.OutboxMessage{Kind: usecases.OutboxKindEmail, DedupKey: to, Payload: payload}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dispatcher := NewOutboxDispatcher(outbox, outboxTestSender{failing: "bob@example.com"}, clock, outboxTestLogger{}, 3)
+
+	dispatcher.DispatchPending(ctx)
+	clock.Advance(time.Hour)
+	dispatcher.DispatchPending(ctx)
+
+	sent, err := outbox.Get(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.SentAt == nil || !sent.SentAt.Equal(now) {
+		t.Fatalf("envoyé à %v, attendu %v", sent.SentAt, now)
+	}
+	failed, err := outbox.Get(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed.SentAt != nil || len(failed.Failures) != 2 ||
+		!failed.Failures[0].At.Equal(now) || !failed.Failures[1].At.Equal(now.Add(time.Hour)) {
+		t.Fatalf("message en échec : %+v", failed)
+	}
+}
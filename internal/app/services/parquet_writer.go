@@ -0,0 +1,509 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+// =============================================================================
+// PARQUET : exports lisibles directement par Spark, DuckDB, pandas...
+// =============================================================================
+
+// Compressions des pages Parquet
+const (
+	ParquetSnappy       = "snappy"
+	ParquetGzip         = "gzip"
+	ParquetUncompressed = "none"
+)
+
+// ParquetCompressions compressions disponibles
+var ParquetCompressions = []string{ParquetSnappy, ParquetGzip, ParquetUncompressed}
+
+// ParquetExportEncoder implémente usecases.ExportEncoder (format "parquet")
+type ParquetExportEncoder struct {
+	compressions []string
+	rowGroupRows int
+}
+
+// NewParquetExportEncoder compression par défaut des exports (une de ParquetCompressions) ;
+// rowGroupRows lignes par groupe, gardées en mémoire le temps de les écrire
+func NewParquetExportEncoder(defaultCompression string, rowGroupRows int) *ParquetExportEncoder {
+	compressions := []string{defaultCompression}
+	for _, compression := range ParquetCompressions {
+		if compression != defaultCompression {
+			compressions = append(compressions, compression)
+		}
+	}
+	return &ParquetExportEncoder{compressions: compressions, rowGroupRows: rowGroupRows}
+}
+
+func (e *ParquetExportEncoder) Format() string         { return "parquet" }
+func (e *ParquetExportEncoder) ContentType() string    { return "application/vnd.apache.parquet" }
+func (e *ParquetExportEncoder) Compressions() []string { return e.compressions }
+
+func (e *ParquetExportEncoder) NewWriter(w io.Writer, columns []usecases.ExportColumn, compression string) (usecases.ExportRowWriter, error) {
+	return NewParquetWriter(w, columns, compression, e.rowGroupRows)
+}
+
+// Types physiques, types convertis et codecs du format (parquet.thrift)
+const (
+	parquetBoolean   int32 = 0
+	parquetInt32     int32 = 1
+	parquetInt64     int32 = 2
+	parquetDouble    int32 = 5
+	parquetByteArray int32 = 6
+
+	parquetConvertedUTF8            int32 = 0
+	parquetConvertedDate            int32 = 6
+	parquetConvertedTimestampMicros int32 = 10
+
+	parquetCodecUncompressed int32 = 0
+	parquetCodecSnappy       int32 = 1
+	parquetCodecGzip         int32 = 2
+
+	parquetEncodingPlain int32 = 0
+	parquetEncodingRLE   int32 = 3
+)
+
+var parquetMagic = []byte("PAR1")
+
+// ParquetWriter fichier Parquet à schéma plat : une colonne OPTIONAL par ExportColumn (nil =
+// valeur absente), valeurs PLAIN, une page par colonne et par groupe de lignes
+//
+//	string → BYTE_ARRAY (STRING)   int64 → INT64   double → DOUBLE   bool → BOOLEAN
+//	timestamp → INT64 (TIMESTAMP µs, UTC)   date → INT32 (DATE)
+type ParquetWriter struct {
+	out          *bufio.Writer
+	offset       int64
+	columns      []*parquetColumn
+	codec        int32
+	rowGroupRows int
+	rows         int
+	totalRows    int64
+	rowGroups    []parquetRowGroup
+}
+
+type parquetColumn struct {
+	usecases.ExportColumn
+	physical int32
+	// definitions niveau de définition de chaque ligne du groupe (1 = valeur présente)
+	definitions []byte
+	values      bytes.Buffer
+	bools       []bool
+}
+
+type parquetRowGroup struct {
+	rows   int64
+	size   int64
+	chunks []parquetChunk
+}
+
+type parquetChunk struct {
+	offset       int64
+	values       int64
+	uncompressed int64
+	compressed   int64
+}
+
+func NewParquetWriter(w io.Writer, columns []usecases.ExportColumn, compression string, rowGroupRows int) (*ParquetWriter, error) {
+	codecs := map[string]int32{ParquetSnappy: parquetCodecSnappy, ParquetGzip: parquetCodecGzip, ParquetUncompressed: parquetCodecUncompressed}
+	codec, ok := codecs[compression]
+	if !ok {
+		return nil, fmt.Errorf("parquet : compression %q inconnue", compression)
+	}
+	physicals := map[string]int32{
+		usecases.ExportString:    parquetByteArray,
+		usecases.ExportInt64:     parquetInt64,
+		usecases.ExportDouble:    parquetDouble,
+		usecases.ExportBool:      parquetBoolean,
+		usecases.ExportTimestamp: parquetInt64,
+		usecases.ExportDate:      parquetInt32,
+	}
+	p := &ParquetWriter{out: bufio.NewWriter(w), codec: codec, rowGroupRows: max(rowGroupRows, 1)}
+	for _, column := range columns {
+		physical, ok := physicals[column.Type]
+		if !ok {
+			return nil, fmt.Errorf("parquet : type %q de la colonne %s non pris en charge", column.Type, column.Name)
+		}
+		p.columns = append(p.columns, &parquetColumn{ExportColumn: column, physical: physical})
+	}
+	if err := p.write(parquetMagic); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *ParquetWriter) write(data []byte) error {
+	n, err := p.out.Write(data)
+	p.offset += int64(n)
+	return err
+}
+
+// Write ajoute une ligne, une valeur par colonne du type Go de la colonne (nil = absente)
+func (p *ParquetWriter) Write(row []any) error {
+	if len(row) != len(p.columns) {
+		return fmt.Errorf("parquet : %d valeurs pour %d colonnes", len(row), len(p.columns))
+	}
+	for i, column := range p.columns {
+		if err := column.append(row[i]); err != nil {
+			return err
+		}
+	}
+	p.rows++
+	if p.rows >= p.rowGroupRows {
+		return p.flushRowGroup()
+	}
+	return nil
+}
+
+func (c *parquetColumn) append(value any) error {
+	if value == nil {
+		c.definitions = append(c.definitions, 0)
+		return nil
+	}
+	var ok bool
+	switch c.Type {
+	case usecases.ExportString:
+		var text string
+		if text, ok = value.(string); ok {
+			c.values.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(text))))
+			c.values.WriteString(text)
+		}
+	case usecases.ExportInt64:
+		var number int64
+		if number, ok = value.(int64); ok {
+			c.values.Write(binary.LittleEndian.AppendUint64(nil, uint64(number)))
+		}
+	case usecases.ExportDouble:
+		var number float64
+		if number, ok = value.(float64); ok {
+			c.values.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(number)))
+		}
+	case usecases.ExportBool:
+		var flag bool
+		if flag, ok = value.(bool); ok {
+			c.bools = append(c.bools, flag)
+		}
+	case usecases.ExportTimestamp:
+		var at time.Time
+		if at, ok = value.(time.Time); ok {
+			c.values.Write(binary.LittleEndian.AppendUint64(nil, uint64(at.UnixMicro())))
+		}
+	case usecases.ExportDate:
+		var day time.Time
+		if day, ok = value.(time.Time); ok {
+			days := math.Floor(float64(day.Unix()) / 86400)
+			c.values.Write(binary.LittleEndian.AppendUint32(nil, uint32(int32(days))))
+		}
+	}
+	if !ok {
+		return fmt.Errorf("parquet : valeur %T pour la colonne %s (%s)", value, c.Name, c.Type)
+	}
+	c.definitions = append(c.definitions, 1)
+	return nil
+}
+
+// flushRowGroup écrit une page par colonne pour les lignes en attente
+func (p *ParquetWriter) flushRowGroup() error {
+	group := parquetRowGroup{rows: int64(p.rows)}
+	for _, column := range p.columns {
+		chunk, err := p.writePage(column)
+		if err != nil {
+			return err
+		}
+		group.size += chunk.uncompressed
+		group.chunks = append(group.chunks, chunk)
+		column.definitions, column.bools = column.definitions[:0], column.bools[:0]
+		column.values.Reset()
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.totalRows += int64(p.rows)
+	p.rows = 0
+	return nil
+}
+
+// writePage page de données v1 : niveaux de définition (RLE, précédés de leur longueur) puis valeurs
+func (p *ParquetWriter) writePage(column *parquetColumn) (parquetChunk, error) {
+	levels := parquetLevels(column.definitions)
+	body := binary.LittleEndian.AppendUint32(make([]byte, 0, 4+len(levels)+column.values.Len()), uint32(len(levels)))
+	body = append(body, levels...)
+	if column.physical == parquetBoolean {
+		body = append(body, parquetBitPack(column.bools)...)
+	} else {
+		body = append(body, column.values.Bytes()...)
+	}
+
+	compressed, err := p.compress(body)
+	if err != nil {
+		return parquetChunk{}, err
+	}
+	if len(compressed) > math.MaxInt32 || len(body) > math.MaxInt32 {
+		return parquetChunk{}, fmt.Errorf("parquet : page de la colonne %s trop grande, réduire la taille des groupes de lignes", column.Name)
+	}
+	var header thriftWriter
+	header.i32(1, 0) // DATA_PAGE
+	header.i32(2, int32(len(body)))
+	header.i32(3, int32(len(compressed)))
+	header.structBegin(5)
+	header.i32(1, int32(len(column.definitions)))
+	header.i32(2, parquetEncodingPlain)
+	header.i32(3, parquetEncodingRLE)
+	header.i32(4, parquetEncodingRLE)
+	header.structEnd()
+	header.stop()
+
+	chunk := parquetChunk{
+		offset:       p.offset,
+		values:       int64(len(column.definitions)),
+		uncompressed: int64(header.buf.Len() + len(body)),
+		compressed:   int64(header.buf.Len() + len(compressed)),
+	}
+	if err := p.write(header.buf.Bytes()); err != nil {
+		return parquetChunk{}, err
+	}
+	return chunk, p.write(compressed)
+}
+
+func (p *ParquetWriter) compress(body []byte) ([]byte, error) {
+	switch p.codec {
+	case parquetCodecSnappy:
+		return snappyEncode(body), nil
+	case parquetCodecGzip:
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(body); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		return compressed.Bytes(), nil
+	}
+	return body, nil
+}
+
+// parquetLevels niveaux 0/1 en séquences RLE (largeur d'un bit : une valeur par octet)
+func parquetLevels(levels []byte) []byte {
+	var encoded []byte
+	for start := 0; start < len(levels); {
+		end := start + 1
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		encoded = binary.AppendUvarint(encoded, uint64(end-start)<<1)
+		encoded = append(encoded, levels[start])
+		start = end
+	}
+	return encoded
+}
+
+// parquetBitPack booléens PLAIN : un bit par valeur, bit de poids faible en premier
+func parquetBitPack(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for i, value := range values {
+		if value {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+// Close écrit le dernier groupe de lignes et le pied de fichier (schéma, emplacement des pages)
+func (p *ParquetWriter) Close() error {
+	if p.rows > 0 {
+		if err := p.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	var footer thriftWriter
+	footer.i32(1, 1)
+	footer.listBegin(2, thriftStruct, len(p.columns)+1)
+	footer.elementBegin()
+	footer.binary(4, []byte("schema"))
+	footer.i32(5, int32(len(p.columns)))
+	footer.structEnd()
+	for _, column := range p.columns {
+		footer.elementBegin()
+		footer.i32(1, column.physical)
+		footer.i32(3, 1) // OPTIONAL
+		footer.binary(4, []byte(column.Name))
+		switch column.Type {
+		case usecases.ExportString:
+			footer.i32(6, parquetConvertedUTF8)
+			footer.structBegin(10)
+			footer.structBegin(1) // STRING
+			footer.structEnd()
+			footer.structEnd()
+		case usecases.ExportDate:
+			footer.i32(6, parquetConvertedDate)
+			footer.structBegin(10)
+			footer.structBegin(6) // DATE
+			footer.structEnd()
+			footer.structEnd()
+		case usecases.ExportTimestamp:
+			footer.i32(6, parquetConvertedTimestampMicros)
+			footer.structBegin(10)
+			footer.structBegin(8) // TIMESTAMP
+			footer.boolean(1, true)
+			footer.structBegin(2)
+			footer.structBegin(2) // MICROS
+			footer.structEnd()
+			footer.structEnd()
+			footer.structEnd()
+			footer.structEnd()
+		}
+		footer.structEnd()
+	}
+	footer.i64(3, p.totalRows)
+	footer.listBegin(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		footer.elementBegin()
+		footer.listBegin(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := p.columns[i]
+			footer.elementBegin()
+			footer.i64(2, chunk.offset)
+			footer.structBegin(3)
+			footer.i32(1, column.physical)
+			footer.listBegin(2, thriftI32, 2)
+			footer.listI32(parquetEncodingPlain)
+			footer.listI32(parquetEncodingRLE)
+			footer.listBegin(3, thriftBinary, 1)
+			footer.listBinary([]byte(column.Name))
+			footer.i32(4, p.codec)
+			footer.i64(5, chunk.values)
+			footer.i64(6, chunk.uncompressed)
+			footer.i64(7, chunk.compressed)
+			footer.i64(9, chunk.offset)
+			footer.structEnd()
+			footer.structEnd()
+		}
+		footer.i64(2, group.size)
+		footer.i64(3, group.rows)
+		footer.structEnd()
+	}
+	footer.binary(6, []byte("clean-archi-analytics"))
+	footer.stop()
+
+	if err := p.write(footer.buf.Bytes()); err != nil {
+		return err
+	}
+	if err := p.write(binary.LittleEndian.AppendUint32(nil, uint32(footer.buf.Len()))); err != nil {
+		return err
+	}
+	if err := p.write(parquetMagic); err != nil {
+		return err
+	}
+	return p.out.Flush()
+}
+
+// =============================================================================
+// THRIFT (protocole compact) : en-têtes de page et pied de fichier Parquet
+// =============================================================================
+
+// Types du protocole compact
+const (
+	thriftBoolTrue  byte = 1
+	thriftBoolFalse byte = 2
+	thriftI32       byte = 5
+	thriftI64       byte = 6
+	thriftBinary    byte = 8
+	thriftList      byte = 9
+	thriftStruct    byte = 12
+)
+
+// thriftWriter encodeur compact minimal : champs en ordre croissant d'identifiant dans chaque
+// structure, listes de structures, d'entiers ou de chaînes
+type thriftWriter struct {
+	buf    bytes.Buffer
+	lastID int16
+	stack  []int16
+}
+
+func (t *thriftWriter) field(id int16, kind byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | kind)
+	} else {
+		t.buf.WriteByte(kind)
+		t.varint(int64(id))
+	}
+	t.lastID = id
+}
+
+// varint entier signé en zigzag
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(v<<1)^uint64(v>>63)))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) boolean(id int16, v bool) {
+	if v {
+		t.field(id, thriftBoolTrue)
+	} else {
+		t.field(id, thriftBoolFalse)
+	}
+}
+
+func (t *thriftWriter) binary(id int16, v []byte) {
+	t.field(id, thriftBinary)
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(v))))
+	t.buf.Write(v)
+}
+
+func (t *thriftWriter) structBegin(id int16) {
+	t.field(id, thriftStruct)
+	t.elementBegin()
+}
+
+// elementBegin structure élément d'une liste (sans en-tête de champ)
+func (t *thriftWriter) elementBegin() {
+	t.stack = append(t.stack, t.lastID)
+	t.lastID = 0
+}
+
+func (t *thriftWriter) structEnd() {
+	t.stop()
+	t.lastID = t.stack[len(t.stack)-1]
+	t.stack = slices.Delete(t.stack, len(t.stack)-1, len(t.stack))
+}
+
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
+
+func (t *thriftWriter) listBegin(id int16, kind byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | kind)
+	} else {
+		t.buf.WriteByte(0xf0 | kind)
+		t.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+	}
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) listBinary(v []byte) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(v))))
+	t.buf.Write(v)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PBKDF2Hasher implémente usecases.PasswordHasher avec PBKDF2-SHA256 (stdlib uniquement)
+// Format stocké : pbkdf2-sha256$<iterations>$<salt base64>$<hash base64>
+type PBKDF2Hasher struct {
+	iterations int
+	saltLength int
+	keyLength  int
+}
+
+func NewPBKDF2Hasher(iterations int) *PBKDF2Hasher {
+	return &PBKDF2Hasher{
+		iterations: iterations,
+		saltLength: 16,
+		keyLength:  32,
+	}
+}
+
+func (h *PBKDF2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, h.iterations, h.keyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s",
+		h.iterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *PBKDF2Hasher) Verify(password, hash string) error {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return errors.New("invalid hash format")
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return errors.New("invalid hash format")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.New("invalid hash format")
+	}
+
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return errors.New("invalid hash format")
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(key, expected) != 1 {
+		return errors.New("password mismatch")
+	}
+	return nil
+}
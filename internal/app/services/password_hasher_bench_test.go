@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+// Coût de production (600 000 itérations) : c'est lui qui borne le débit des inscriptions
+const benchIterations = 600_000
+
+func BenchmarkPBKDF2HasherHash(b *testing.B) {
+	hasher := NewPBKDF2Hasher(benchIterations)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := hasher.Hash("Secret123!"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPBKDF2HasherVerify(b *testing.B) {
+	hasher := NewPBKDF2Hasher(benchIterations)
+	hash, err := hasher.Hash("Secret123!")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := hasher.Verify("Secret123!", hash); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
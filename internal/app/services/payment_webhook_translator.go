@@ -0,0 +1,63 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// PaymentWebhookTranslator traduit les événements du fournisseur de paiement
+// en commandes métier (couche anti-corruption : son format ne fuit pas dans le domaine)
+type PaymentWebhookTranslator struct{}
+
+func NewPaymentWebhookTranslator() *PaymentWebhookTranslator {
+	return &PaymentWebhookTranslator{}
+}
+
+// paymentCustomerPayload format "customer" du fournisseur de paiement
+// Notre ID utilisateur voyage dans les metadata rattachées au client
+type paymentCustomerPayload struct {
+	Object struct {
+		Email    string            `json:"email"`
+		Name     string            `json:"name"`
+		Metadata map[string]string `json:"metadata"`
+	} `json:"object"`
+}
+
+func (t *PaymentWebhookTranslator) Translate(event usecases.WebhookEvent) (usecases.WebhookCommand, error) {
+	switch event.Type {
+	case "customer.updated":
+		payload, userID, err := t.decodeCustomer(event.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return usecases.UpdateUserProfileCommand{
+			UserID: userID,
+			Email:  payload.Object.Email,
+			Name:   payload.Object.Name,
+		}, nil
+	case "customer.deleted":
+		_, userID, err := t.decodeCustomer(event.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return usecases.DeleteUserCommand{UserID: userID}, nil
+	default:
+		return nil, usecases.ErrWebhookEventIgnored
+	}
+}
+
+func (t *PaymentWebhookTranslator) decodeCustomer(raw json.RawMessage) (*paymentCustomerPayload, int, error) {
+	var payload paymentCustomerPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, 0, errors.New("payload client invalide")
+	}
+
+	userID, err := strconv.Atoi(payload.Object.Metadata["user_id"])
+	if err != nil || userID <= 0 {
+		return nil, 0, errors.New("metadata user_id manquante ou invalide")
+	}
+
+	return &payload, userID, nil
+}
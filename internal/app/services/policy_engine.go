@@ -0,0 +1,388 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Opérateurs des conditions de politique
+const (
+	policyOpEq       = "eq"
+	policyOpNe       = "ne"
+	policyOpIn       = "in"
+	policyOpContains = "contains"
+	policyOpPresent  = "present"
+)
+
+// PolicyCondition nœud d'une condition : combinaison (all, any, not) ou comparaison d'un attribut
+// ("action", "access", "actor.user_id", "actor.tenant_id", "actor.roles", "actor.authenticated",
+// "actor.system", "actor.impersonator_id", "resource.<clé>") à une valeur fixe (value) ou à un
+// autre attribut (value_from). Un attribut absent rend la comparaison fausse, quel que soit l'opérateur
+type PolicyCondition struct {
+	All       []PolicyCondition `json:"all,omitempty"`
+	Any       []PolicyCondition `json:"any,omitempty"`
+	Not       *PolicyCondition  `json:"not,omitempty"`
+	Attribute string            `json:"attribute,omitempty"`
+	Operator  string            `json:"operator,omitempty"`
+	Value     interface{}       `json:"value,omitempty"`
+	ValueFrom string            `json:"value_from,omitempty"`
+}
+
+// Policy règle déclarative : s'applique aux actions listées ("*" : toutes, "user_*" : préfixe)
+// quand sa condition est vraie (sans condition : toujours)
+type Policy struct {
+	ID          string           `json:"id"`
+	Description string           `json:"description,omitempty"`
+	Actions     []string         `json:"actions"`
+	Effect      string           `json:"effect"` // allow | deny
+	When        *PolicyCondition `json:"when,omitempty"`
+}
+
+type policyFile struct {
+	Default  string   `json:"default"` // allow | deny
+	Policies []Policy `json:"policies"`
+}
+
+// RulePolicyEngine implémente usecases.PolicyEngine avec des règles lues dans un fichier JSON :
+//
+//	{"default": "allow", "policies": [{
+//	  "id": "self-or-admin", "actions": ["update_user", "patch_user"], "effect": "allow",
+//	  "when": {"any": [
+//	    {"attribute": "actor.roles", "operator": "contains", "value": "admin"},
+//	    {"attribute": "resource.user_id", "operator": "eq", "value_from": "actor.user_id"}]}}]}
+//
+// Pour une action donnée : une politique "deny" vérifiée refuse ; sinon, si des politiques
+// "allow" la visent, l'une d'elles doit être vérifiée ; sans politique applicable, "default" décide
+type RulePolicyEngine struct {
+	allowByDefault bool
+	policies       []Policy
+}
+
+// LoadPolicyFile lit et valide le fichier de politiques
+func LoadPolicyFile(path string) (*RulePolicyEngine, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewRulePolicyEngine(raw)
+}
+
+func NewRulePolicyEngine(document []byte) (*RulePolicyEngine, error) {
+	var file policyFile
+	if err := json.Unmarshal(document, &file); err != nil {
+		return nil, fmt.Errorf("fichier de politiques invalide : %w", err)
+	}
+	if file.Default != "allow" && file.Default != "deny" {
+		return nil, fmt.Errorf("fichier de politiques : \"default\" doit valoir \"allow\" ou \"deny\" (reçu %q)", file.Default)
+	}
+
+	for i, policy := range file.Policies {
+		if policy.ID == "" {
+			return nil, fmt.Errorf("politique n°%d : \"id\" manquant", i+1)
+		}
+		if policy.Effect != "allow" && policy.Effect != "deny" {
+			return nil, fmt.Errorf("politique %q : \"effect\" doit valoir \"allow\" ou \"deny\"", policy.ID)
+		}
+		if len(policy.Actions) == 0 {
+			return nil, fmt.Errorf("politique %q : aucune action", policy.ID)
+		}
+		if policy.When != nil {
+			if err := validatePolicyCondition(*policy.When); err != nil {
+				return nil, fmt.Errorf("politique %q : %w", policy.ID, err)
+			}
+		}
+	}
+	return &RulePolicyEngine{allowByDefault: file.Default == "allow", policies: file.Policies}, nil
+}
+
+// NewDefaultPolicyEngine politique intégrée, appliquée sans fichier de politiques ni OPA : tout
+// est refusé sauf aux tâches internes, au rôle adminRole, aux use cases publics et, pour les
+// use cases d'un utilisateur, à cet utilisateur. Les rôles dédiés (support, impersonation) sont
+// vérifiés par usecases.AccessGuard, devant les politiques
+func NewDefaultPolicyEngine(adminRole string) *RulePolicyEngine {
+	policies := []Policy{
+		{
+			ID:      "system",
+			Actions: []string{"*"},
+			Effect:  "allow",
+			When:    &PolicyCondition{Attribute: "actor.system", Operator: policyOpEq, Value: true},
+		},
+		{
+			ID:      "public",
+			Actions: []string{"*"},
+			Effect:  "allow",
+			When:    &PolicyCondition{Attribute: "access", Operator: policyOpEq, Value: usecases.AccessPublic.String()},
+		},
+		{
+			ID:          "self",
+			Description: "un utilisateur n'agit que sur lui-même",
+			Actions:     []string{"*"},
+			Effect:      "allow",
+			When: &PolicyCondition{All: []PolicyCondition{
+				{Attribute: "access", Operator: policyOpEq, Value: usecases.AccessUser.String()},
+				{Attribute: "actor.authenticated", Operator: policyOpEq, Value: true},
+				{Any: []PolicyCondition{
+					{Not: &PolicyCondition{Attribute: "resource.user_id", Operator: policyOpPresent}},
+					{Attribute: "resource.user_id", Operator: policyOpEq, ValueFrom: "actor.user_id"},
+				}},
+			}},
+		},
+		{
+			ID:          "dedicated-role",
+			Description: "rôle vérifié par AccessGuard",
+			Actions:     []string{"*"},
+			Effect:      "allow",
+			When: &PolicyCondition{All: []PolicyCondition{
+				{Attribute: "access", Operator: policyOpIn, Value: []interface{}{usecases.AccessSupport.String(), usecases.AccessImpersonation.String()}},
+				{Attribute: "actor.authenticated", Operator: policyOpEq, Value: true},
+			}},
+		},
+	}
+	if adminRole != "" {
+		policies = append(policies, Policy{
+			ID:      "admin",
+			Actions: []string{"*"},
+			Effect:  "allow",
+			When:    &PolicyCondition{Attribute: "actor.roles", Operator: policyOpContains, Value: adminRole},
+		})
+	}
+	return &RulePolicyEngine{allowByDefault: false, policies: policies}
+}
+
+func (e *RulePolicyEngine) Allow(ctx context.Context, input usecases.PolicyInput) (bool, error) {
+	document := policyInputDocument(input)
+	applicable, allowed := false, false
+	for _, policy := range e.policies {
+		if !policyMatchesAction(policy.Actions, input.Action) {
+			continue
+		}
+		holds := policy.When == nil || evaluatePolicyCondition(*policy.When, document)
+		if policy.Effect == "deny" {
+			if holds {
+				return false, nil
+			}
+			continue
+		}
+		applicable = true
+		allowed = allowed || holds
+	}
+	if applicable {
+		return allowed, nil
+	}
+	return e.allowByDefault, nil
+}
+
+func policyMatchesAction(actions []string, action string) bool {
+	for _, pattern := range actions {
+		if pattern == "*" || pattern == action {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePolicyCondition(condition PolicyCondition) error {
+	kinds := 0
+	for _, set := range []bool{condition.All != nil, condition.Any != nil, condition.Not != nil, condition.Attribute != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errors.New("une condition porte exactement un de \"all\", \"any\", \"not\" ou \"attribute\"")
+	}
+
+	for _, child := range append(condition.All, condition.Any...) {
+		if err := validatePolicyCondition(child); err != nil {
+			return err
+		}
+	}
+	if condition.Not != nil {
+		return validatePolicyCondition(*condition.Not)
+	}
+	if condition.Attribute == "" {
+		return nil
+	}
+
+	if err := validatePolicyAttribute(condition.Attribute); err != nil {
+		return err
+	}
+	switch condition.Operator {
+	case policyOpPresent:
+		return nil
+	case policyOpEq, policyOpNe, policyOpIn, policyOpContains:
+	default:
+		return fmt.Errorf("opérateur inconnu %q pour %q", condition.Operator, condition.Attribute)
+	}
+	if condition.ValueFrom != "" {
+		return validatePolicyAttribute(condition.ValueFrom)
+	}
+	if condition.Value == nil {
+		return fmt.Errorf("%q : \"value\" ou \"value_from\" obligatoire avec l'opérateur %q", condition.Attribute, condition.Operator)
+	}
+	if _, isList := condition.Value.([]interface{}); condition.Operator == policyOpIn && !isList {
+		return fmt.Errorf("%q : l'opérateur \"in\" attend une liste", condition.Attribute)
+	}
+	return nil
+}
+
+func validatePolicyAttribute(attribute string) error {
+	if attribute == "action" || attribute == "access" || strings.HasPrefix(attribute, "actor.") || strings.HasPrefix(attribute, "resource.") {
+		return nil
+	}
+	return fmt.Errorf("attribut inconnu %q (action, access, actor.* ou resource.*)", attribute)
+}
+
+func evaluatePolicyCondition(condition PolicyCondition, document map[string]interface{}) bool {
+	switch {
+	case condition.All != nil:
+		for _, child := range condition.All {
+			if !evaluatePolicyCondition(child, document) {
+				return false
+			}
+		}
+		return true
+	case condition.Any != nil:
+		for _, child := range condition.Any {
+			if evaluatePolicyCondition(child, document) {
+				return true
+			}
+		}
+		return false
+	case condition.Not != nil:
+		return !evaluatePolicyCondition(*condition.Not, document)
+	}
+
+	actual, found := lookupPolicyAttribute(document, condition.Attribute)
+	if condition.Operator == policyOpPresent {
+		return found
+	}
+	expected := normalizePolicyValue(condition.Value)
+	if condition.ValueFrom != "" {
+		var ok bool
+		if expected, ok = lookupPolicyAttribute(document, condition.ValueFrom); !ok {
+			return false
+		}
+	}
+	if !found {
+		return false
+	}
+
+	switch condition.Operator {
+	case policyOpEq:
+		return reflect.DeepEqual(actual, expected)
+	case policyOpNe:
+		return !reflect.DeepEqual(actual, expected)
+	case policyOpIn:
+		return policyListContains(expected, actual)
+	case policyOpContains:
+		return policyListContains(actual, expected)
+	default:
+		return false
+	}
+}
+
+func policyListContains(list, value interface{}) bool {
+	items, ok := list.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupPolicyAttribute(document map[string]interface{}, attribute string) (interface{}, bool) {
+	if attribute == "action" || attribute == "access" {
+		value, found := document[attribute]
+		return value, found
+	}
+	scope, key, _ := strings.Cut(attribute, ".")
+	values, ok := document[scope].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, found := values[key]
+	if !found || value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// policyInputDocument entrée des politiques sous forme JSON (partagée avec l'adaptateur OPA) :
+//
+//	{"action": "update_user", "access": "user", "actor": {"user_id": 42, "tenant_id": "acme", "roles": ["admin"],
+//	 "authenticated": true, "system": false}, "resource": {"user_id": 42}}
+func policyInputDocument(input usecases.PolicyInput) map[string]interface{} {
+	roles := make([]interface{}, 0, len(input.Actor.Roles))
+	for _, role := range input.Actor.Roles {
+		roles = append(roles, role)
+	}
+	actor := map[string]interface{}{
+		"roles":         roles,
+		"authenticated": input.Authenticated,
+		"system":        input.Actor.System,
+	}
+	if input.Actor.UserID > 0 {
+		actor["user_id"] = float64(input.Actor.UserID)
+	}
+	if input.Actor.TenantID != "" {
+		actor["tenant_id"] = input.Actor.TenantID
+	}
+	if input.Actor.Impersonation != nil {
+		actor["impersonator_id"] = float64(input.Actor.Impersonation.AdminID)
+	}
+
+	resource := make(map[string]interface{}, len(input.Resource))
+	for key, value := range input.Resource {
+		resource[key] = normalizePolicyValue(value)
+	}
+	return map[string]interface{}{"action": input.Action, "access": input.Access.String(), "actor": actor, "resource": resource}
+}
+
+// normalizePolicyValue ramène les nombres en float64 et les listes en []interface{},
+// comme les valeurs décodées du fichier de politiques
+func normalizePolicyValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case int:
+		return float64(typed)
+	case int64:
+		return float64(typed)
+	case int32:
+		return float64(typed)
+	case float32:
+		return float64(typed)
+	case []string:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = item
+		}
+		return items
+	case []int:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = float64(item)
+		}
+		return items
+	case []interface{}:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = normalizePolicyValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
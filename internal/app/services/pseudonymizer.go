@@ -0,0 +1,96 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Noms fictifs des copies anonymisées ; le domaine est réservé (RFC 2606) : aucun email ne peut partir
+var (
+	pseudonymFirstNames = []string{
+		"Adèle", "Basile", "Céleste", "Dorian", "Elsa", "Félix", "Garance", "Hector", "Iris", "Joachim",
+		"Katia", "Lucien", "Margot", "Nestor", "Ophélie", "Pierre", "Romane", "Sacha", "Tess", "Victor",
+		"Wanda", "Xavier", "Yann", "Zélie", "Aurèle", "Bérénice", "Côme", "Daphné", "Émile", "Flore",
+	}
+	pseudonymLastNames = []string{
+		"Arnaud", "Barbier", "Carpentier", "Delmas", "Estève", "Fabre", "Girard", "Huet", "Imbert", "Jacob",
+		"Klein", "Lemaire", "Marchand", "Noël", "Olivier", "Perrin", "Quentin", "Renard", "Sauvage", "Tessier",
+		"Vidal", "Weber", "Lebrun", "Morel", "Brunet", "Chevalier", "Gauthier", "Mercier", "Blanc", "Guérin",
+	}
+)
+
+const pseudonymEmailDomain = "example.invalid"
+
+// HMACPseudonymizer implémente usecases.Pseudonymizer : chaque pseudonyme dérive de
+// HMAC-SHA256(clé, type + valeur normalisée). Sans la clé, impossible de remonter à l'original
+// ni même de tester une valeur candidate ; avec la même clé, deux exports donnent les mêmes
+// pseudonymes. La clé ne doit donc jamais quitter la production
+type HMACPseudonymizer struct {
+	key []byte
+}
+
+func NewHMACPseudonymizer(key string) (*HMACPseudonymizer, error) {
+	if len(key) < 32 {
+		return nil, fmt.Errorf("clé d'anonymisation trop courte : %d octets, 32 minimum", len(key))
+	}
+	return &HMACPseudonymizer{key: []byte(key)}, nil
+}
+
+// Email unique par email d'origine (préserve la contrainte d'unicité de la base cible)
+func (p *HMACPseudonymizer) Email(email string) string {
+	digest := p.digest("email", strings.ToLower(strings.TrimSpace(email)))
+	first, last := p.pickName(digest)
+	return fmt.Sprintf("%s.%s.%s@%s", emailLocalPart(first), emailLocalPart(last), hex.EncodeToString(digest[8:14]), pseudonymEmailDomain)
+}
+
+// Name prénom et nom fictifs : deux homonymes d'origine restent homonymes
+func (p *HMACPseudonymizer) Name(name string) string {
+	first, last := p.pickName(p.digest("name", strings.ToLower(strings.TrimSpace(name))))
+	return first + " " + last
+}
+
+// Phone numéro de la plage réservée à la fiction de l'ARCEP (+33 6 39 98 xx xx)
+func (p *HMACPseudonymizer) Phone(phone string) string {
+	digest := p.digest("phone", strings.TrimSpace(phone))
+	return fmt.Sprintf("+3363998%04d", binary.BigEndian.Uint32(digest)%10000)
+}
+
+// Handle "anon_" suivi de 12 caractères hexadécimaux : format valide, jamais réservé
+func (p *HMACPseudonymizer) Handle(handle string) string {
+	return "anon_" + hex.EncodeToString(p.digest("handle", strings.ToLower(handle))[:6])
+}
+
+func (p *HMACPseudonymizer) Text(text string) string {
+	return "[texte anonymisé " + hex.EncodeToString(p.digest("text", text)[:4]) + "]"
+}
+
+// Identifier implémente usecases.IdentifierHasher : 32 caractères hexadécimaux, sans forme lisible
+// (propriétés identifiantes des événements analytics)
+func (p *HMACPseudonymizer) Identifier(value string) string {
+	return hex.EncodeToString(p.digest("identifier", value)[:16])
+}
+
+func (p *HMACPseudonymizer) digest(kind, value string) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func (p *HMACPseudonymizer) pickName(digest []byte) (string, string) {
+	first := binary.BigEndian.Uint32(digest[0:4]) % uint32(len(pseudonymFirstNames))
+	last := binary.BigEndian.Uint32(digest[4:8]) % uint32(len(pseudonymLastNames))
+	return pseudonymFirstNames[first], pseudonymLastNames[last]
+}
+
+// emailLocalPart "Aurèle" → "aurele" (partie locale ASCII)
+func emailLocalPart(name string) string {
+	replacer := strings.NewReplacer("à", "a", "â", "a", "é", "e", "è", "e", "ê", "e", "ë", "e",
+		"î", "i", "ï", "i", "ô", "o", "ù", "u", "û", "u", "ç", "c", "'", "", " ", "")
+	return replacer.Replace(strings.ToLower(name))
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	mathrand "math/rand/v2"
+	"sync"
+)
+
+// =============================================================================
+// SOURCES D'ALÉA
+// =============================================================================
+
+// CryptoRandomSource implémente usecases.RandomSource avec le CSPRNG du système
+type CryptoRandomSource struct{}
+
+func NewCryptoRandomSource() CryptoRandomSource {
+	return CryptoRandomSource{}
+}
+
+func (CryptoRandomSource) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+// SeededRandomSource implémente usecases.RandomSource avec une suite ChaCha8 déterminée par la graine :
+// même graine, mêmes jetons (tests, fixtures reproductibles). Jamais en production
+type SeededRandomSource struct {
+	mutex  sync.Mutex
+	stream *mathrand.ChaCha8
+}
+
+func NewSeededRandomSource(seed uint64) *SeededRandomSource {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return &SeededRandomSource{stream: mathrand.NewChaCha8(key)}
+}
+
+func (s *SeededRandomSource) Read(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.stream.Read(p)
+}
+
+// =============================================================================
+// GÉNÉRATEUR DE JETONS
+// =============================================================================
+
+// RandomTokenGenerator implémente usecases.TokenGenerator au-dessus d'une RandomSource
+type RandomTokenGenerator struct {
+	source usecases.RandomSource
+}
+
+func NewRandomTokenGenerator(source usecases.RandomSource) *RandomTokenGenerator {
+	return &RandomTokenGenerator{source: source}
+}
+
+func (g *RandomTokenGenerator) Token(size int) (string, error) {
+	buffer, err := g.read(size)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+func (g *RandomTokenGenerator) HexToken(size int) (string, error) {
+	buffer, err := g.read(size)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
+
+func (g *RandomTokenGenerator) read(size int) ([]byte, error) {
+	buffer := make([]byte, size)
+	if _, err := g.source.Read(buffer); err != nil {
+		return nil, err
+	}
+	return buffer, nil
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"strings"
+	"text/template"
+)
+
+var reengagementTemplate = template.Must(template.New("reengagement").Parse(
+	`Bonjour {{.Name}},
+
+Nous ne vous avons pas vu depuis le {{.Format.Date .LastActivity}} ({{.Format.Integer .InactiveDays}} jours).
+Votre compte est toujours là : il suffit de vous reconnecter pour retrouver votre activité.
+
+Sans connexion de votre part, un compte inactif trop longtemps peut être supprimé.
+`))
+
+// TemplateReengagementRenderer implémente usecases.ReengagementRenderer avec text/template
+type TemplateReengagementRenderer struct{}
+
+func NewTemplateReengagementRenderer() *TemplateReengagementRenderer {
+	return &TemplateReengagementRenderer{}
+}
+
+func (r *TemplateReengagementRenderer) RenderReengagement(reminder usecases.ReengagementReminder) (string, string, error) {
+	var body strings.Builder
+	if err := reengagementTemplate.Execute(&body, reminder); err != nil {
+		return "", "", err
+	}
+	return "Votre compte vous attend", body.String(), nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"sync"
+	"time"
+)
+
+// InMemoryResultCache implémente usecases.ResultCache dans la mémoire de l'instance
+// Chaque instance a son cache : une invalidation ne traverse pas les instances, la TTL borne l'écart
+// Au-delà de maxEntries, les entrées expirées sont purgées, puis la plus proche de l'expiration
+type InMemoryResultCache struct {
+	mutex      sync.Mutex
+	entries    map[string]resultCacheEntry
+	tags       map[string]map[string]struct{}
+	maxEntries int
+	clock      usecases.Clock
+}
+
+type resultCacheEntry struct {
+	value   any
+	expires time.Time
+	tags    []string
+}
+
+func NewInMemoryResultCache(maxEntries int, clock usecases.Clock) *InMemoryResultCache {
+	return &InMemoryResultCache{
+		entries:    make(map[string]resultCacheEntry),
+		tags:       make(map[string]map[string]struct{}),
+		maxEntries: maxEntries,
+		clock:      clock,
+	}
+}
+
+func (c *InMemoryResultCache) Get(key string) (any, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	if !c.clock.Now().Before(entry.expires) {
+		c.remove(key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *InMemoryResultCache) Set(key string, value any, ttl time.Duration, tags []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+	if _, exists := c.entries[key]; exists {
+		c.remove(key)
+	} else if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+
+	c.entries[key] = resultCacheEntry{value: value, expires: now.Add(ttl), tags: tags}
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+}
+
+func (c *InMemoryResultCache) Invalidate(tags ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			c.remove(key)
+		}
+	}
+}
+
+// remove retire l'entrée et ses références dans l'index des étiquettes
+func (c *InMemoryResultCache) remove(key string) {
+	entry, found := c.entries[key]
+	if !found {
+		return
+	}
+	delete(c.entries, key)
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
+
+// evict libère au moins une place : les entrées expirées, sinon celle qui expire le plus tôt
+func (c *InMemoryResultCache) evict(now time.Time) {
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			c.remove(key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		c.remove(oldestKey)
+	}
+}
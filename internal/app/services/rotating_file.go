@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat horodatage des fichiers archivés : access-2026-01-31T23-59-59.000.log
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFileConfig politique de rotation, sur le modèle de lumberjack
+type RotatingFileConfig struct {
+	Path string
+	// MaxSize taille (octets) au-delà de laquelle le fichier est archivé (0 = jamais)
+	MaxSize int64
+	// MaxBackups archives conservées (0 = toutes)
+	MaxBackups int
+	// MaxAge archives plus anciennes supprimées (0 = aucune limite d'âge)
+	MaxAge time.Duration
+}
+
+// RotatingFile io.Writer sûr en concurrence qui archive le fichier courant quand il atteint MaxSize ;
+// une écriture n'est jamais coupée entre deux fichiers
+type RotatingFile struct {
+	config RotatingFileConfig
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+func NewRotatingFile(config RotatingFileConfig) (*RotatingFile, error) {
+	if config.Path == "" {
+		return nil, errors.New("chemin du fichier manquant")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0o755); err != nil {
+		return nil, err
+	}
+	f := &RotatingFile{config: config}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.config.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.config.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate archive le fichier courant sans attendre MaxSize (rotation quotidienne, SIGHUP...)
+func (f *RotatingFile) Rotate() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.rotate()
+}
+
+func (f *RotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+	extension := filepath.Ext(f.config.Path)
+	backup := strings.TrimSuffix(f.config.Path, extension) + "-" + time.Now().UTC().Format(backupTimeFormat) + extension
+	if err := os.Rename(f.config.Path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("rotation de %s : %w", f.config.Path, err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.prune()
+	return nil
+}
+
+// prune supprime les archives au-delà de MaxBackups ou plus anciennes que MaxAge
+func (f *RotatingFile) prune() {
+	if f.config.MaxBackups == 0 && f.config.MaxAge == 0 {
+		return
+	}
+	extension := filepath.Ext(f.config.Path)
+	prefix := filepath.Base(strings.TrimSuffix(f.config.Path, extension)) + "-"
+	entries, err := os.ReadDir(filepath.Dir(f.config.Path))
+	if err != nil {
+		return
+	}
+	type backupFile struct {
+		path    string
+		rotated time.Time
+	}
+	var backups []backupFile
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || !strings.HasSuffix(stamp, extension) {
+			continue
+		}
+		rotated, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, extension))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{filepath.Join(filepath.Dir(f.config.Path), entry.Name()), rotated})
+	}
+	// Plus récentes d'abord
+	slices.SortFunc(backups, func(a, b backupFile) int { return b.rotated.Compare(a.rotated) })
+	for i, backup := range backups {
+		tooMany := f.config.MaxBackups > 0 && i >= f.config.MaxBackups
+		tooOld := f.config.MaxAge > 0 && time.Since(backup.rotated) > f.config.MaxAge
+		if tooMany || tooOld {
+			_ = os.Remove(backup.path)
+		}
+	}
+}
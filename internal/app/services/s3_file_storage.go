@@ -0,0 +1,227 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/awssig"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3StorageConfig bucket S3 ou service compatible (MinIO, R2...) adressé en style chemin
+type S3StorageConfig struct {
+	Region string
+	// Endpoint vide : point d'accès régional d'AWS ; sinon le service compatible (http://localhost:9000)
+	Endpoint    string
+	Bucket      string
+	Prefix      string
+	Credentials awssig.Credentials
+	Clients     *httpclient.Factory
+}
+
+// S3FileStorage implémente usecases.FileStorage sur un bucket S3, mêmes clés que LocalFileStorage :
+//
+//	<prefix>chunks/<upload>/<index>  fragments en cours d'envoi
+//	<prefix>files/<key>              fichiers assemblés ou produits
+//
+// Un objet n'est visible qu'une fois entièrement envoyé ; chaque fichier est mis en mémoire avant
+// l'envoi (PutObject en une requête), à réserver aux fichiers de taille raisonnable (archives par lot)
+type S3FileStorage struct {
+	config S3StorageConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3FileStorage(config S3StorageConfig) *S3FileStorage {
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &S3FileStorage{
+		config: config,
+		client: config.Clients.Client("s3", httpclient.ClientOptions{Timeout: 5 * time.Minute}),
+		now:    time.Now,
+	}
+}
+
+func (s *S3FileStorage) Write(ctx context.Context, key string, write func(w io.Writer) error) error {
+	if err := validateStorageName(key); err != nil {
+		return err
+	}
+	var content bytes.Buffer
+	if err := write(&content); err != nil {
+		return err
+	}
+	return s.put(ctx, "files/"+key, content.Bytes())
+}
+
+func (s *S3FileStorage) PutChunk(ctx context.Context, uploadID string, index int, data []byte) error {
+	if err := validateStorageName(uploadID); err != nil {
+		return err
+	}
+	return s.put(ctx, "chunks/"+uploadID+"/"+strconv.Itoa(index), data)
+}
+
+func (s *S3FileStorage) AssembleChunks(ctx context.Context, uploadID string, count int, key string) error {
+	if err := validateStorageName(uploadID); err != nil {
+		return err
+	}
+	err := s.Write(ctx, key, func(w io.Writer) error {
+		for index := 0; index < count; index++ {
+			chunk, err := s.get(ctx, "chunks/"+uploadID+"/"+strconv.Itoa(index))
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(w, chunk)
+			chunk.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.DeleteChunks(ctx, uploadID)
+}
+
+func (s *S3FileStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateStorageName(key); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, "files/"+key)
+}
+
+func (s *S3FileStorage) Delete(ctx context.Context, key string) error {
+	if err := validateStorageName(key); err != nil {
+		return err
+	}
+	return s.delete(ctx, "files/"+key)
+}
+
+// DeleteChunks S3 ne supprime pas par préfixe : les fragments sont listés puis supprimés un à un
+func (s *S3FileStorage) DeleteChunks(ctx context.Context, uploadID string) error {
+	if err := validateStorageName(uploadID); err != nil {
+		return err
+	}
+	keys, err := s.list(ctx, "chunks/"+uploadID+"/")
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := s.delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *S3FileStorage) put(ctx context.Context, key string, body []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, nil, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// get fs.ErrNotExist si l'objet n'existe pas
+func (s *S3FileStorage) get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// delete sans effet si l'objet n'existe pas (S3 répond 204 dans les deux cas)
+func (s *S3FileStorage) delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// s3ListResult réponse de ListObjectsV2
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list clés relatives (sans le préfixe de la configuration) des objets sous prefix
+func (s *S3FileStorage) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	query := url.Values{"list-type": {"2"}, "prefix": {s.config.Prefix + prefix}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3: réponse invalide : %w", err)
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(object.Key, s.config.Prefix))
+		}
+		if !result.IsTruncated {
+			return keys, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// do requête signée sur l'objet key (le bucket si key est vide) ; le corps de la réponse est à
+// fermer par l'appelant, sauf en cas d'erreur
+func (s *S3FileStorage) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	target := s.config.Endpoint + "/" + url.PathEscape(s.config.Bucket) + "/"
+	if key != "" {
+		target += (&url.URL{Path: s.config.Prefix + key}).EscapedPath()
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(digest[:]))
+	awssig.Sign(req, body, "s3", s.config.Region, s.config.Credentials, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet && key != "" {
+		return nil, fmt.Errorf("s3: %s : %w", key, fs.ErrNotExist)
+	}
+	var failure struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&failure)
+	return nil, fmt.Errorf("s3: statut %d : %s (%s)", resp.StatusCode, failure.Message, failure.Code)
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"compress/flate"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// SERVICE PROVIDER SAML 2.0 (HTTP-Redirect vers l'IdP, HTTP-POST vers l'ACS)
+// =============================================================================
+
+const (
+	samlProtocolNS  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlAssertionNS = "urn:oasis:names:tc:SAML:2.0:assertion"
+
+	samlBindingPOST   = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlStatusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearer        = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	samlNameIDEmail   = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+
+	// samlClockSkew tolérance sur les horloges de l'IdP
+	samlClockSkew = 3 * time.Minute
+	// samlMaxResponseSize garde-fou sur la réponse décodée
+	samlMaxResponseSize = 512 << 10
+)
+
+// SAMLServiceProvider implémente usecases.SAMLServiceProvider ; chaque tenant est un SP distinct
+// (entityID {base}/saml/{tenant}/metadata, ACS {base}/saml/{tenant}/acs)
+type SAMLServiceProvider struct {
+	baseURL string
+}
+
+func NewSAMLServiceProvider(baseURL string) *SAMLServiceProvider {
+	return &SAMLServiceProvider{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (sp *SAMLServiceProvider) entityID(tenantID string) string {
+	return sp.baseURL + "/saml/" + url.PathEscape(tenantID) + "/metadata"
+}
+
+func (sp *SAMLServiceProvider) acsURL(tenantID string) string {
+	return sp.baseURL + "/saml/" + url.PathEscape(tenantID) + "/acs"
+}
+
+// =============================================================================
+// MÉTADONNÉES ET DEMANDE D'AUTHENTIFICATION
+// =============================================================================
+
+type samlEntityDescriptor struct {
+	XMLName  xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID string   `xml:"entityID,attr"`
+	SP       struct {
+		AuthnRequestsSigned        bool   `xml:"AuthnRequestsSigned,attr"`
+		WantAssertionsSigned       bool   `xml:"WantAssertionsSigned,attr"`
+		ProtocolSupportEnumeration string `xml:"protocolSupportEnumeration,attr"`
+		NameIDFormat               string `xml:"NameIDFormat"`
+		AssertionConsumerService   struct {
+			Binding   string `xml:"Binding,attr"`
+			Location  string `xml:"Location,attr"`
+			Index     int    `xml:"index,attr"`
+			IsDefault bool   `xml:"isDefault,attr"`
+		} `xml:"AssertionConsumerService"`
+	} `xml:"SPSSODescriptor"`
+}
+
+func (sp *SAMLServiceProvider) Metadata(tenantID string) ([]byte, error) {
+	descriptor := samlEntityDescriptor{EntityID: sp.entityID(tenantID)}
+	descriptor.SP.WantAssertionsSigned = true
+	descriptor.SP.ProtocolSupportEnumeration = samlProtocolNS
+	descriptor.SP.NameIDFormat = samlNameIDEmail
+	descriptor.SP.AssertionConsumerService.Binding = samlBindingPOST
+	descriptor.SP.AssertionConsumerService.Location = sp.acsURL(tenantID)
+	descriptor.SP.AssertionConsumerService.IsDefault = true
+
+	body, err := xml.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type samlAuthnRequest struct {
+	XMLName                     xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+	ID                          string   `xml:"ID,attr"`
+	Version                     string   `xml:"Version,attr"`
+	IssueInstant                string   `xml:"IssueInstant,attr"`
+	Destination                 string   `xml:"Destination,attr"`
+	AssertionConsumerServiceURL string   `xml:"AssertionConsumerServiceURL,attr"`
+	ProtocolBinding             string   `xml:"ProtocolBinding,attr"`
+	Issuer                      struct {
+		XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+		Value   string   `xml:",chardata"`
+	}
+	NameIDPolicy struct {
+		Format      string `xml:"Format,attr"`
+		AllowCreate bool   `xml:"AllowCreate,attr"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:protocol NameIDPolicy"`
+}
+
+func (sp *SAMLServiceProvider) AuthnRequestURL(idp *repositories.IdentityProvider, requestID, relayState string, now time.Time) (string, error) {
+	request := samlAuthnRequest{
+		ID:                          requestID,
+		Version:                     "2.0",
+		IssueInstant:                now.UTC().Format(time.RFC3339),
+		Destination:                 idp.SSOURL,
+		AssertionConsumerServiceURL: sp.acsURL(idp.TenantID),
+		ProtocolBinding:             samlBindingPOST,
+	}
+	request.Issuer.Value = sp.entityID(idp.TenantID)
+	request.NameIDPolicy.Format = samlNameIDEmail
+	request.NameIDPolicy.AllowCreate = true
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	// Binding HTTP-Redirect : DEFLATE brut puis base64 (SAML Bindings §3.4.4.1)
+	var compressed bytes.Buffer
+	writer, err := flate.NewWriter(&compressed, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	redirect, err := url.Parse(idp.SSOURL)
+	if err != nil {
+		return "", err
+	}
+	query := redirect.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	if relayState != "" {
+		query.Set("RelayState", relayState)
+	}
+	redirect.RawQuery = query.Encode()
+	return redirect.String(), nil
+}
+
+// =============================================================================
+// CERTIFICAT DE L'IDP
+// =============================================================================
+
+func (sp *SAMLServiceProvider) ValidateCertificate(certificate string) error {
+	if _, err := parseIdPCertificate(certificate); err != nil {
+		return fmt.Errorf("certificate invalide : %w", err)
+	}
+	return nil
+}
+
+// parseIdPCertificate accepte le PEM ou le base64 nu (tel que copié depuis les métadonnées de l'IdP)
+func parseIdPCertificate(certificate string) (*x509.Certificate, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(strings.TrimSpace(certificate))); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, errors.New("bloc PEM CERTIFICATE attendu")
+		}
+		der = block.Bytes
+	} else {
+		decoded, err := decodeXMLBase64(certificate)
+		if err != nil {
+			return nil, errors.New("ni PEM ni base64")
+		}
+		der = decoded
+	}
+
+	parsed, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := parsed.PublicKey.(*rsa.PublicKey); !ok {
+		return nil, errors.New("seules les clés RSA sont prises en charge")
+	}
+	return parsed, nil
+}
+
+// =============================================================================
+// RÉPONSE DE L'IDP (ASSERTION CONSUMER SERVICE)
+// =============================================================================
+
+func (sp *SAMLServiceProvider) ParseResponse(idp *repositories.IdentityProvider, samlResponse string, now time.Time) (*usecases.SAMLAssertion, error) {
+	assertion, err := sp.parseResponse(idp, samlResponse, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", usecases.ErrInvalidSAMLResponse, err)
+	}
+	return assertion, nil
+}
+
+func (sp *SAMLServiceProvider) parseResponse(idp *repositories.IdentityProvider, samlResponse string, now time.Time) (*usecases.SAMLAssertion, error) {
+	if len(samlResponse) > samlMaxResponseSize*4/3+4 {
+		return nil, errors.New("réponse trop volumineuse")
+	}
+	document, err := decodeXMLBase64(samlResponse)
+	if err != nil {
+		return nil, errors.New("base64 invalide")
+	}
+	response, err := parseXMLTree(document)
+	if err != nil {
+		return nil, err
+	}
+	if response.space != samlProtocolNS || response.local != "Response" {
+		return nil, errors.New("élément Response attendu")
+	}
+	if _, err := collectIDs(response); err != nil {
+		return nil, err
+	}
+
+	acsURL := sp.acsURL(idp.TenantID)
+	if destination := response.attr("Destination"); destination != "" && destination != acsURL {
+		return nil, errors.New("destination inattendue")
+	}
+	status := response.child(samlProtocolNS, "Status")
+	if code := status.child(samlProtocolNS, "StatusCode"); code == nil || code.attr("Value") != samlStatusSuccess {
+		return nil, errors.New("authentification refusée par l'IdP")
+	}
+
+	if len(response.childrenNamed(samlAssertionNS, "EncryptedAssertion")) > 0 {
+		return nil, errors.New("assertions chiffrées non prises en charge")
+	}
+	assertions := response.childrenNamed(samlAssertionNS, "Assertion")
+	if len(assertions) != 1 {
+		return nil, errors.New("une assertion exactement est attendue")
+	}
+	assertion := assertions[0]
+
+	// L'assertion doit être couverte par une signature : la sienne, ou celle de la réponse
+	// qui la contient. Tout ce qui suit n'est lu que dans l'élément ainsi vérifié
+	certificate, err := parseIdPCertificate(idp.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("certificat de l'IdP : %w", err)
+	}
+	switch {
+	case assertion.child(xmlDSigNS, "Signature") != nil:
+		err = verifyEnvelopedSignature(assertion, certificate)
+	case response.child(xmlDSigNS, "Signature") != nil:
+		err = verifyEnvelopedSignature(response, certificate)
+	default:
+		err = errors.New("assertion non signée")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if issuer := assertion.child(samlAssertionNS, "Issuer").textContent(); issuer != idp.EntityID {
+		return nil, errors.New("émetteur inattendu")
+	}
+	if err := sp.checkConditions(assertion, idp.TenantID, now); err != nil {
+		return nil, err
+	}
+
+	subject := assertion.child(samlAssertionNS, "Subject")
+	if subject == nil {
+		return nil, errors.New("Subject manquant")
+	}
+	inResponseTo, err := bearerConfirmation(subject, acsURL, now)
+	if err != nil {
+		return nil, err
+	}
+	if expected := response.attr("InResponseTo"); expected != "" && expected != inResponseTo {
+		return nil, errors.New("InResponseTo incohérent")
+	}
+
+	nameID := subject.child(samlAssertionNS, "NameID")
+	if nameID.textContent() == "" {
+		return nil, errors.New("NameID manquant")
+	}
+
+	return &usecases.SAMLAssertion{
+		InResponseTo: inResponseTo,
+		NameID:       nameID.textContent(),
+		NameIDFormat: nameID.attr("Format"),
+		Attributes:   samlAttributes(assertion),
+	}, nil
+}
+
+// checkConditions fenêtre de validité et audience (notre entityID)
+func (sp *SAMLServiceProvider) checkConditions(assertion *xmlNode, tenantID string, now time.Time) error {
+	conditions := assertion.child(samlAssertionNS, "Conditions")
+	if conditions == nil {
+		return errors.New("Conditions manquantes")
+	}
+	if err := checkValidityWindow(conditions, now); err != nil {
+		return err
+	}
+
+	restrictions := conditions.childrenNamed(samlAssertionNS, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return errors.New("audience manquante")
+	}
+	// Chaque restriction doit nous inclure (SAML Core §2.5.1.4)
+	entityID := sp.entityID(tenantID)
+	for _, restriction := range restrictions {
+		found := false
+		for _, audience := range restriction.childrenNamed(samlAssertionNS, "Audience") {
+			found = found || audience.textContent() == entityID
+		}
+		if !found {
+			return errors.New("audience inattendue")
+		}
+	}
+	return nil
+}
+
+// bearerConfirmation InResponseTo de la confirmation bearer destinée à notre ACS
+func bearerConfirmation(subject *xmlNode, acsURL string, now time.Time) (string, error) {
+	for _, confirmation := range subject.childrenNamed(samlAssertionNS, "SubjectConfirmation") {
+		if confirmation.attr("Method") != samlBearer {
+			continue
+		}
+		data := confirmation.child(samlAssertionNS, "SubjectConfirmationData")
+		if data == nil || data.attr("Recipient") != acsURL || data.attr("NotOnOrAfter") == "" {
+			continue
+		}
+		if checkValidityWindow(data, now) != nil {
+			continue
+		}
+		return data.attr("InResponseTo"), nil
+	}
+	return "", errors.New("aucune confirmation bearer valide pour cet ACS")
+}
+
+// checkValidityWindow attributs NotBefore / NotOnOrAfter, avec tolérance d'horloge
+func checkValidityWindow(node *xmlNode, now time.Time) error {
+	if value := node.attr("NotBefore"); value != "" {
+		notBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return errors.New("NotBefore invalide")
+		}
+		if now.Add(samlClockSkew).Before(notBefore) {
+			return errors.New("assertion pas encore valide")
+		}
+	}
+	if value := node.attr("NotOnOrAfter"); value != "" {
+		notOnOrAfter, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return errors.New("NotOnOrAfter invalide")
+		}
+		if !now.Add(-samlClockSkew).Before(notOnOrAfter) {
+			return errors.New("assertion expirée")
+		}
+	}
+	return nil
+}
+
+// samlAttributes valeurs des attributs, par Name
+func samlAttributes(assertion *xmlNode) map[string][]string {
+	attributes := make(map[string][]string)
+	for _, statement := range assertion.childrenNamed(samlAssertionNS, "AttributeStatement") {
+		for _, attribute := range statement.childrenNamed(samlAssertionNS, "Attribute") {
+			name := attribute.attr("Name")
+			if name == "" {
+				continue
+			}
+			for _, value := range attribute.childrenNamed(samlAssertionNS, "AttributeValue") {
+				attributes[name] = append(attributes[name], value.textContent())
+			}
+		}
+	}
+	return attributes
+}
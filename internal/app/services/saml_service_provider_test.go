@@ -0,0 +1,239 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/infra/database"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+var samlTestNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+const (
+	samlTestTenant    = "acme"
+	samlTestIssuer    = "https://idp.example.com/metadata"
+	samlTestRequest   = "_req-1"
+	samlTestNameID    = "alice@example.com"
+	samlTestNSDecl    = ` xmlns:ds="` + xmlDSigNS + `"`
+	samlTestRSASHA256 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	samlTestSHA256    = "http://www.w3.org/2001/04/xmlenc#sha256"
+)
+
+// samlTestIdP IdP de test : clé RSA et certificat auto-signé
+type samlTestIdP struct {
+	key         *rsa.PrivateKey
+	certificate string
+}
+
+func newSAMLTestIdP(t testing.TB) *samlTestIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    samlTestNow.Add(-time.Hour),
+		NotAfter:     samlTestNow.Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &samlTestIdP{
+		key:         key,
+		certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+func (idp *samlTestIdP) provider() *repositories.IdentityProvider {
+	return &repositories.IdentityProvider{
+		TenantID:    samlTestTenant,
+		EntityID:    samlTestIssuer,
+		Certificate: idp.certificate,
+		Enabled:     true,
+	}
+}
+
+// samlTestAssertion champs variables d'une assertion
+type samlTestAssertion struct {
+	ID           string
+	NameID       string
+	Recipient    string
+	Audience     string
+	NotOnOrAfter time.Time
+}
+
+func newSAMLTestAssertion(sp *SAMLServiceProvider) samlTestAssertion {
+	return samlTestAssertion{
+		ID:           "_a1",
+		NameID:       samlTestNameID,
+		Recipient:    sp.acsURL(samlTestTenant),
+		Audience:     sp.entityID(samlTestTenant),
+		NotOnOrAfter: samlTestNow.Add(5 * time.Minute),
+	}
+}
+
+// xml assertion déjà sous forme canonique (exc-c14n) ; inner est inséré après Issuer
+func (a samlTestAssertion) xml(inner string) string {
+	notOnOrAfter := a.NotOnOrAfter.Format(time.RFC3339)
+	return fmt.Sprintf(`<saml:Assertion xmlns:saml="%s" ID="%s" IssueInstant="%s" Version="2.0">`+
+		`<saml:Issuer>%s</saml:Issuer>%s`+
+		`<saml:Subject><saml:NameID Format="%s">%s</saml:NameID>`+
+		`<saml:SubjectConfirmation Method="%s"><saml:SubjectConfirmationData InResponseTo="%s" NotOnOrAfter="%s" Recipient="%s"></saml:SubjectConfirmationData></saml:SubjectConfirmation>`+
+		`</saml:Subject>`+
+		`<saml:Conditions NotBefore="%s" NotOnOrAfter="%s"><saml:AudienceRestriction><saml:Audience>%s</saml:Audience></saml:AudienceRestriction></saml:Conditions>`+
+		`</saml:Assertion>`,
+		samlAssertionNS, a.ID, samlTestNow.Add(-time.Minute).Format(time.RFC3339),
+		samlTestIssuer, inner,
+		samlNameIDEmail, a.NameID,
+		samlBearer, samlTestRequest, notOnOrAfter, a.Recipient,
+		samlTestNow.Add(-time.Minute).Format(time.RFC3339), notOnOrAfter, a.Audience)
+}
+
+// samlTestSignedInfo SignedInfo sous forme canonique (déclaration ds rendue sur l'élément)
+func samlTestSignedInfo(id string, digest []byte) string {
+	return `<ds:SignedInfo` + samlTestNSDecl + `>` +
+		`<ds:CanonicalizationMethod Algorithm="` + xmlExcC14N + `"></ds:CanonicalizationMethod>` +
+		`<ds:SignatureMethod Algorithm="` + samlTestRSASHA256 + `"></ds:SignatureMethod>` +
+		`<ds:Reference URI="#` + id + `"><ds:Transforms>` +
+		`<ds:Transform Algorithm="` + xmlEnveloped + `"></ds:Transform>` +
+		`<ds:Transform Algorithm="` + xmlExcC14N + `"></ds:Transform>` +
+		`</ds:Transforms><ds:DigestMethod Algorithm="` + samlTestSHA256 + `"></ds:DigestMethod>` +
+		`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest) + `</ds:DigestValue></ds:Reference>` +
+		`</ds:SignedInfo>`
+}
+
+// sign assertion avec signature enveloppée. Empreinte et signature portent sur les formes
+// canoniques écrites à la main : elles ne dépendent pas de canonicalize, qui est ainsi vérifiée
+func (idp *samlTestIdP) sign(t testing.TB, a samlTestAssertion) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(a.xml("")))
+	signedInfo := samlTestSignedInfo(a.ID, digest[:])
+	hashed := sha256.Sum256([]byte(signedInfo))
+	value, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Dans le document, SignedInfo hérite de la déclaration portée par Signature
+	signature := `<ds:Signature` + samlTestNSDecl + `>` +
+		strings.Replace(signedInfo, samlTestNSDecl, "", 1) +
+		`<ds:SignatureValue>` + base64.StdEncoding.EncodeToString(value) + `</ds:SignatureValue>` +
+		`</ds:Signature>`
+	return a.xml(signature)
+}
+
+// samlTestResponse réponse encodée pour l'ACS, body placé après Status
+func samlTestResponse(sp *SAMLServiceProvider, body string) string {
+	document := `<samlp:Response xmlns:samlp="` + samlProtocolNS + `" Destination="` + sp.acsURL(samlTestTenant) + `" ID="_r1" InResponseTo="` + samlTestRequest + `" Version="2.0">` +
+		`<samlp:Status><samlp:StatusCode Value="` + samlStatusSuccess + `"/></samlp:Status>` +
+		body +
+		`</samlp:Response>`
+	return base64.StdEncoding.EncodeToString([]byte(document))
+}
+
+// samlTestSignature élément Signature d'une assertion signée
+func samlTestSignature(signed string) string {
+	start := strings.Index(signed, "<ds:Signature")
+	end := strings.Index(signed, "</ds:Signature>") + len("</ds:Signature>")
+	return signed[start:end]
+}
+
+func TestSAMLServiceProviderParseResponse(t *testing.T) {
+	sp := NewSAMLServiceProvider("https://analytics.example.com/")
+	idp := newSAMLTestIdP(t)
+	attacker := newSAMLTestIdP(t)
+
+	valid := newSAMLTestAssertion(sp)
+	signed := idp.sign(t, valid)
+
+	forged := valid
+	forged.ID = "_evil"
+	forged.NameID = "admin@example.com"
+
+	otherAudience := valid
+	otherAudience.Audience = "https://other.example.com/saml/metadata"
+	otherRecipient := valid
+	otherRecipient.Recipient = "https://other.example.com/saml/acs"
+	expired := valid
+	expired.NotOnOrAfter = samlTestNow.Add(-samlClockSkew - time.Second)
+
+	tests := []struct {
+		name     string
+		response string
+		// wantErr fragment du motif de refus ; vide = réponse acceptée
+		wantErr string
+	}{
+		{"assertion signée valide", samlTestResponse(sp, signed), ""},
+		{"assertion modifiée après signature", samlTestResponse(sp, strings.Replace(signed, samlTestNameID, "admin@example.com", 1)), "empreinte du contenu signé incorrecte"},
+		{"assertion signée par une autre clé", samlTestResponse(sp, attacker.sign(t, valid)), "signature incorrecte"},
+		{"assertion non signée", samlTestResponse(sp, valid.xml("")), "assertion non signée"},
+		// Signature wrapping : l'assertion signée reste intacte, une assertion forgée est présentée à côté
+		{"wrapping : assertion forgée à côté de l'assertion signée", samlTestResponse(sp, forged.xml("")+signed), "une assertion exactement est attendue"},
+		{"wrapping : assertion signée cachée dans Extensions", samlTestResponse(sp, "<samlp:Extensions>"+signed+"</samlp:Extensions>"+forged.xml("")), "assertion non signée"},
+		{"wrapping : signature déplacée dans l'assertion forgée", samlTestResponse(sp, forged.xml(samlTestSignature(signed)+"<saml:Advice>"+signed+"</saml:Advice>")), "ne référence pas l'élément signé"},
+		{"wrapping : assertion forgée de même ID", samlTestResponse(sp, strings.Replace(forged.xml("<saml:Advice>"+signed+"</saml:Advice>"), `ID="_evil"`, `ID="_a1"`, 1)), "dupliqué"},
+		{"audience d'un autre SP", samlTestResponse(sp, idp.sign(t, otherAudience)), "audience inattendue"},
+		{"destinataire d'un autre ACS", samlTestResponse(sp, idp.sign(t, otherRecipient)), "aucune confirmation bearer valide"},
+		{"assertion expirée", samlTestResponse(sp, idp.sign(t, expired)), "assertion expirée"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertion, err := sp.ParseResponse(idp.provider(), tt.response, samlTestNow)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("réponse refusée : %v", err)
+				}
+				if assertion.NameID != samlTestNameID || assertion.InResponseTo != samlTestRequest {
+					t.Fatalf("assertion inattendue : %+v", assertion)
+				}
+				return
+			}
+			if !errors.Is(err, usecases.ErrInvalidSAMLResponse) {
+				t.Fatalf("erreur %v, attendu ErrInvalidSAMLResponse", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erreur %q, attendu %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Le SP est sans état : le rejeu d'une réponse (même assertion, même InResponseTo) est refusé
+// par la consommation de la demande, à usage unique (ConsumeSSOResponseUseCase)
+func TestSAMLResponseReplay(t *testing.T) {
+	sp := NewSAMLServiceProvider("https://analytics.example.com")
+	idp := newSAMLTestIdP(t)
+	requests := database.NewInMemorySSORequestRepository(NewFakeClock(samlTestNow))
+	ctx := context.Background()
+
+	if err := requests.Save(ctx, repositories.SSORequest{ID: samlTestRequest, TenantID: samlTestTenant, Expires: samlTestNow.Add(10 * time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	response := samlTestResponse(sp, idp.sign(t, newSAMLTestAssertion(sp)))
+
+	for attempt, wantErr := range []error{nil, repositories.ErrSSORequestNotFound} {
+		assertion, err := sp.ParseResponse(idp.provider(), response, samlTestNow)
+		if err != nil {
+			t.Fatalf("tentative %d : %v", attempt, err)
+		}
+		if _, err := requests.Consume(ctx, assertion.InResponseTo, samlTestNow); !errors.Is(err, wantErr) {
+			t.Fatalf("tentative %d : erreur %v, attendu %v", attempt, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Scheduler exécute des tâches périodiques jusqu'à l'annulation du context
+// Une tâche qui panique est signalée et la suivante s'exécute normalement
+type Scheduler struct {
+	logger   usecases.Logger
+	reporter usecases.ErrorReporter
+	wg       sync.WaitGroup
+}
+
+func NewScheduler(logger usecases.Logger, reporter usecases.ErrorReporter) *Scheduler {
+	return &Scheduler{logger: logger, reporter: reporter}
+}
+
+// Every planifie task toutes les interval ; une exécution n'en chevauche jamais une autre
+func (s *Scheduler) Every(ctx context.Context, name string, interval time.Duration, task func(ctx context.Context)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				start := time.Now()
+				s.run(ctx, name, task)
+				s.logger.Info("Scheduled task finished", map[string]interface{}{
+					"task":        name,
+					"duration_ms": time.Since(start).Milliseconds(),
+				})
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) run(ctx context.Context, name string, task func(ctx context.Context)) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.reporter.Report(ctx, fmt.Errorf("panic: %v", recovered), debug.Stack(), map[string]interface{}{"task": name})
+		}
+	}()
+	task(ctx)
+}
+
+// Wait attend la fin des tâches en cours après l'annulation du context
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrSecretNotFound le fournisseur ne connaît pas ce secret
+var ErrSecretNotFound = errors.New("secret introuvable")
+
+// SecretsProvider source des secrets (clés JWT, identifiants SQL...) : environnement,
+// fichiers montés par l'orchestrateur ou Vault. Chaque appel relit la source
+type SecretsProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// =============================================================================
+// ENVIRONNEMENT
+// =============================================================================
+
+// EnvSecretsProvider lit les secrets dans les variables d'environnement (comportement historique)
+type EnvSecretsProvider struct{}
+
+func NewEnvSecretsProvider() *EnvSecretsProvider {
+	return &EnvSecretsProvider{}
+}
+
+func (p *EnvSecretsProvider) Secret(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// =============================================================================
+// FICHIERS (secrets Docker / Kubernetes)
+// =============================================================================
+
+// FileSecretsProvider lit un fichier par secret dans dir (ex: /run/secrets/JWT_SECRET)
+// Relu à chaque appel : une rotation du volume monté est vue au rechargement suivant
+type FileSecretsProvider struct {
+	dir string
+}
+
+func NewFileSecretsProvider(dir string) *FileSecretsProvider {
+	return &FileSecretsProvider{dir: dir}
+}
+
+func (p *FileSecretsProvider) Secret(ctx context.Context, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", errors.New("nom de secret invalide : " + name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(p.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	// Les éditeurs et `kubectl create secret --from-file` laissent souvent un saut de ligne final
+	value := strings.TrimRight(string(content), "\r\n")
+	if value == "" {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// =============================================================================
+// MAGASIN DE SECRETS RECHARGEABLE
+// =============================================================================
+
+// SecretStore garde la dernière valeur connue des secrets suivis et les relit sur demande
+// Les abonnés sont prévenus de chaque secret modifié (rotation sans redémarrage)
+type SecretStore struct {
+	provider  SecretsProvider
+	logger    usecases.Logger
+	names     []string
+	mutex     sync.RWMutex
+	values    map[string]string
+	listeners []func(name string)
+}
+
+func NewSecretStore(provider SecretsProvider, logger usecases.Logger, names ...string) *SecretStore {
+	return &SecretStore{
+		provider: provider,
+		logger:   logger,
+		names:    names,
+		values:   make(map[string]string),
+	}
+}
+
+// Load lecture initiale : un secret absent vaut "", toute autre erreur empêche le démarrage
+func (s *SecretStore) Load(ctx context.Context) error {
+	values, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.values = values
+	s.mutex.Unlock()
+	return nil
+}
+
+// Get dernière valeur connue du secret
+func (s *SecretStore) Get(name string) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.values[name]
+}
+
+// OnChange enregistre fn, appelée après chaque rechargement pour chaque secret modifié
+func (s *SecretStore) OnChange(fn func(name string)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload relit tous les secrets suivis ; en cas d'échec (Vault injoignable...), les valeurs
+// précédentes restent en service
+func (s *SecretStore) Reload(ctx context.Context) {
+	values, err := s.fetch(ctx)
+	if err != nil {
+		s.logger.Error("Secrets reload failed, keeping previous values", err, nil)
+		return
+	}
+
+	s.mutex.Lock()
+	var changed []string
+	for _, name := range s.names {
+		if values[name] != s.values[name] {
+			changed = append(changed, name)
+		}
+	}
+	s.values = values
+	listeners := s.listeners
+	s.mutex.Unlock()
+
+	// Hors verrou : un abonné peut relire le magasin
+	for _, name := range changed {
+		s.logger.Info("Secret rotated", map[string]interface{}{"secret": name})
+		for _, listener := range listeners {
+			listener(name)
+		}
+	}
+}
+
+func (s *SecretStore) fetch(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string, len(s.names))
+	for _, name := range s.names {
+		value, err := s.provider.Secret(ctx, name)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("secret %s : %w", name, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/binary"
+)
+
+// =============================================================================
+// SNAPPY : compression des pages Parquet (format bloc brut, sans en-tête de trame)
+// =============================================================================
+
+// snappyMaxOffset distance maximale d'une copie encodée sur deux octets
+const snappyMaxOffset = 1<<16 - 1
+
+// snappyEncode compresse src au format bloc Snappy : longueur décompressée (varint) puis
+// littéraux et copies. Correspondances de 4 octets au moins, trouvées par table de hachage :
+// moins compact que l'encodeur de référence, lisible par tous les décodeurs
+func snappyEncode(src []byte) []byte {
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)/2+16), uint64(len(src)))
+	if len(src) < 16 {
+		return snappyLiteral(dst, src)
+	}
+
+	const tableBits = 14
+	var table [1 << tableBits]int32 // position + 1 ; 0 = aucune
+	hash := func(u uint32) uint32 { return (u * 0x1e35a7bd) >> (32 - tableBits) }
+
+	pending := 0
+	for i := 0; i+4 <= len(src); {
+		current := binary.LittleEndian.Uint32(src[i:])
+		h := hash(current)
+		candidate := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if candidate < 0 || i-candidate > snappyMaxOffset || binary.LittleEndian.Uint32(src[candidate:]) != current {
+			i++
+			continue
+		}
+		length := 4
+		for i+length < len(src) && src[candidate+length] == src[i+length] {
+			length++
+		}
+		dst = snappyLiteral(dst, src[pending:i])
+		dst = snappyCopy(dst, i-candidate, length)
+		i += length
+		pending = i
+	}
+	return snappyLiteral(dst, src[pending:])
+}
+
+func snappyLiteral(dst, literal []byte) []byte {
+	if len(literal) == 0 {
+		return dst
+	}
+	n := uint32(len(literal) - 1)
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n)<<2)
+	case n < 1<<8:
+		dst = append(dst, 60<<2, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, literal...)
+}
+
+// snappyCopy copies de 64 octets au plus, décalage sur deux octets
+func snappyCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		chunk := min(length, 64)
+		dst = append(dst, byte(chunk-1)<<2|0x02, byte(offset), byte(offset>>8))
+		length -= chunk
+	}
+	return dst
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StripeBilling implémente usecases.Billing avec l'API REST de Stripe (formulaires, Bearer)
+// L'usage est déclaré en événements de compteur (Billing Meters) : un compteur Stripe par
+// ressource, désigné par son event_name (STRIPE_METERS)
+type StripeBilling struct {
+	secretKey string
+	baseURL   string
+	// meters event_name du compteur Stripe par ressource ; ressource absente = non déclarée
+	meters map[string]string
+	client *http.Client
+}
+
+func NewStripeBilling(secretKey, baseURL string, meters map[string]string, clients *httpclient.Factory) *StripeBilling {
+	return &StripeBilling{
+		secretKey: secretKey,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		meters:    meters,
+		client:    clients.Client("stripe", httpclient.ClientOptions{Timeout: 10 * time.Second}),
+	}
+}
+
+func (b *StripeBilling) CreateCustomer(ctx context.Context, customer usecases.BillingCustomer) (string, error) {
+	form := url.Values{}
+	form.Set("email", customer.Email)
+	if customer.Name != "" {
+		form.Set("name", customer.Name)
+	}
+	form.Set("metadata[tenant_id]", customer.TenantID)
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	// Clé d'idempotence par tenant : un nouvel essai après une réponse perdue ne crée pas de doublon
+	if err := b.post(ctx, "/v1/customers", form, "customer:"+customer.TenantID, &result); err != nil {
+		return "", err
+	}
+	return result.ID, nil
+}
+
+func (b *StripeBilling) Subscribe(ctx context.Context, customerID, priceID, tenantID string) (*usecases.BillingSubscription, error) {
+	form := url.Values{}
+	form.Set("customer", customerID)
+	form.Set("items[0][price]", priceID)
+	form.Set("metadata[tenant_id]", tenantID)
+
+	var subscription stripeSubscription
+	if err := b.post(ctx, "/v1/subscriptions", form, "", &subscription); err != nil {
+		return nil, err
+	}
+	converted := subscription.toBilling()
+	return &converted, nil
+}
+
+func (b *StripeBilling) ReportUsage(ctx context.Context, report usecases.UsageReport) error {
+	meter, ok := b.meters[report.Resource]
+	if !ok {
+		return nil
+	}
+	form := url.Values{}
+	form.Set("event_name", meter)
+	form.Set("payload[stripe_customer_id]", report.CustomerID)
+	form.Set("payload[value]", strconv.Itoa(report.Quantity))
+	form.Set("timestamp", strconv.FormatInt(report.At.Unix(), 10))
+	// identifier : Stripe ignore un second événement de même identifiant sur le compteur
+	form.Set("identifier", report.IdempotencyKey)
+
+	return b.post(ctx, "/v1/billing/meter_events", form, report.IdempotencyKey, nil)
+}
+
+// stripeSubscription format "subscription" de Stripe ; la fin de période a migré de l'abonnement
+// vers ses items dans les versions récentes de l'API, les deux emplacements sont lus
+type stripeSubscription struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+func (s stripeSubscription) toBilling() usecases.BillingSubscription {
+	subscription := usecases.BillingSubscription{
+		ID:     s.ID,
+		Status: entities.SubscriptionStatus(s.Status),
+	}
+	periodEnd := s.CurrentPeriodEnd
+	if len(s.Items.Data) > 0 {
+		subscription.PriceID = s.Items.Data[0].Price.ID
+		if periodEnd == 0 {
+			periodEnd = s.Items.Data[0].CurrentPeriodEnd
+		}
+	}
+	if periodEnd > 0 {
+		subscription.CurrentPeriodEnd = time.Unix(periodEnd, 0).UTC()
+	}
+	return subscription
+}
+
+// ParseSubscriptionEvent traduit customer.subscription.created / updated / deleted ;
+// un abonnement supprimé arrive avec le statut canceled
+func (b *StripeBilling) ParseSubscriptionEvent(event usecases.WebhookEvent) (*usecases.SubscriptionEvent, error) {
+	switch event.Type {
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+	default:
+		return nil, usecases.ErrWebhookEventIgnored
+	}
+
+	var payload struct {
+		Object stripeSubscription `json:"object"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, errors.New("payload abonnement invalide")
+	}
+	if payload.Object.ID == "" || payload.Object.Customer == "" {
+		return nil, errors.New("abonnement sans identifiant ou sans client")
+	}
+	return &usecases.SubscriptionEvent{
+		CustomerID:   payload.Object.Customer,
+		TenantID:     payload.Object.Metadata["tenant_id"],
+		Subscription: payload.Object.toBilling(),
+	}, nil
+}
+
+// post envoie un formulaire et décode la réponse dans result (ignorée si nil)
+func (b *StripeBilling) post(ctx context.Context, path string, form url.Values, idempotencyKey string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		return fmt.Errorf("stripe: %s: %s", resp.Status, failure.Error.Message)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("stripe: réponse invalide : %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+// CATALOGUE DES OFFRES
+// =============================================================================
+
+// LoadBillingPlans lit le catalogue des offres depuis un fichier JSON :
+//
+//	{"pro": {"price_id": "price_123", "quotas": {"users": 5000}, "features": ["bulk_import"]}}
+//
+// Un chemin vide donne un catalogue vide (aucune offre ne peut être souscrite)
+func LoadBillingPlans(path string) (usecases.BillingPlans, error) {
+	plans := make(usecases.BillingPlans)
+	if path == "" {
+		return plans, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var definitions map[string]struct {
+		PriceID  string         `json:"price_id"`
+		Quotas   map[string]int `json:"quotas"`
+		Features []string       `json:"features"`
+	}
+	if err := json.Unmarshal(raw, &definitions); err != nil {
+		return nil, fmt.Errorf("fichier d'offres invalide : %w", err)
+	}
+
+	prices := make(map[string]string, len(definitions))
+	for name, definition := range definitions {
+		if definition.PriceID == "" {
+			return nil, fmt.Errorf("offre %q : price_id obligatoire", name)
+		}
+		if other, ok := prices[definition.PriceID]; ok {
+			return nil, fmt.Errorf("offres %q et %q : même price_id", other, name)
+		}
+		for resource, limit := range definition.Quotas {
+			if limit < 0 {
+				return nil, fmt.Errorf("offre %q : quota %s négatif", name, resource)
+			}
+		}
+		prices[definition.PriceID] = name
+		plans[name] = usecases.BillingPlan{
+			Name:     name,
+			PriceID:  definition.PriceID,
+			Quotas:   definition.Quotas,
+			Features: definition.Features,
+		}
+	}
+	return plans, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrTaskRunnerStopped est retournée par Go après le début de l'arrêt
+var ErrTaskRunnerStopped = errors.New("task runner stopped")
+
+// BoundedTaskRunner implémente usecases.TaskRunner
+// - au plus maxConcurrent tâches simultanées : Go attend une place (ou l'annulation de la requête)
+// - chaque tâche garde les valeurs du context appelant (request_id, acteur) sans son annulation
+// - Shutdown refuse les nouvelles tâches, attend les tâches en cours puis les annule à l'échéance
+type BoundedTaskRunner struct {
+	slots    chan struct{}
+	logger   usecases.Logger
+	reporter usecases.ErrorReporter
+
+	mutex   sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+
+	base   context.Context // annulé quand le délai de drainage est dépassé
+	cancel context.CancelFunc
+}
+
+func NewBoundedTaskRunner(maxConcurrent int, logger usecases.Logger, reporter usecases.ErrorReporter) *BoundedTaskRunner {
+	base, cancel := context.WithCancel(context.Background())
+	return &BoundedTaskRunner{
+		slots:    make(chan struct{}, max(maxConcurrent, 1)),
+		logger:   logger,
+		reporter: reporter,
+		base:     base,
+		cancel:   cancel,
+	}
+}
+
+func (r *BoundedTaskRunner) Go(ctx context.Context, name string, task func(ctx context.Context) error) error {
+	select {
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.base.Done():
+		return ErrTaskRunnerStopped
+	}
+
+	// Enregistrement sous verrou : Shutdown ne peut pas commencer son Wait entre le test et le Add
+	r.mutex.Lock()
+	if r.stopped {
+		r.mutex.Unlock()
+		<-r.slots
+		return ErrTaskRunnerStopped
+	}
+	r.wg.Add(1)
+	r.mutex.Unlock()
+
+	taskCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(r.base, cancel)
+
+	go func() {
+		defer r.wg.Done()
+		defer func() { <-r.slots }()
+		defer stop()
+		defer cancel()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				r.reporter.Report(taskCtx, fmt.Errorf("panic: %v", recovered), debug.Stack(), map[string]interface{}{"task": name})
+			}
+		}()
+
+		if err := task(taskCtx); err != nil {
+			r.logger.Error("Async task failed", err, map[string]interface{}{"task": name})
+		}
+	}()
+	return nil
+}
+
+// Shutdown attend la fin des tâches en cours ; à l'échéance de ctx, elles sont annulées
+// et Shutdown attend qu'elles rendent la main avant de retourner ctx.Err()
+func (r *BoundedTaskRunner) Shutdown(ctx context.Context) error {
+	r.mutex.Lock()
+	r.stopped = true
+	r.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("jeton invalide")
+	ErrExpiredToken = errors.New("jeton expiré")
+)
+
+// legacyTokenHeader en-tête des jetons signés par la clé sans identifiant (JWT_SECRET) :
+// identique à celui des versions précédentes, les jetons en cours restent valides
+var legacyTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// tokenHeaderFields en-tête JWT ; seul HS256 est accepté (pas de négociation d'algorithme)
+type tokenHeaderFields struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ"`
+}
+
+// tokenClaims claims JWT portés par les jetons d'accès
+// Une session d'impersonation porte en plus l'administrateur (act, RFC 8693), ses actions
+// permises (scope, séparées par des espaces) et le bandeau à afficher
+type tokenClaims struct {
+	Subject  string            `json:"sub"`
+	TenantID string            `json:"tenant,omitempty"`
+	Roles    []string          `json:"roles,omitempty"`
+	Actor    *tokenActorClaims `json:"act,omitempty"`
+	Scope    string            `json:"scope,omitempty"`
+	Banner   string            `json:"impersonation_banner,omitempty"`
+	IssuedAt int64             `json:"iat"`
+	Expires  int64             `json:"exp"`
+}
+
+type tokenActorClaims struct {
+	Subject string `json:"sub"`
+}
+
+// =============================================================================
+// JEUX DE CLÉS (ROTATION)
+// =============================================================================
+
+// JWTKeySet clés HMAC indexées par kid : la clé active signe, les autres ne font que vérifier
+// Rotation : ajouter la nouvelle clé en tête, puis retirer l'ancienne une fois ses jetons expirés
+type JWTKeySet struct {
+	activeKID string
+	keys      map[string][]byte
+}
+
+// ParseJWTKeySet lit signingKeys au format "kid1=secret1,kid2=secret2" (la première clé est
+// la clé active) ; legacySecret, s'il est fourni, vérifie les jetons sans kid et signe
+// à défaut d'autre clé
+func ParseJWTKeySet(signingKeys, legacySecret string) (*JWTKeySet, error) {
+	set := &JWTKeySet{keys: make(map[string][]byte)}
+	if legacySecret != "" {
+		set.keys[""] = []byte(legacySecret)
+	}
+
+	active := ""
+	for _, pair := range strings.Split(signingKeys, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		kid, secret, found := strings.Cut(pair, "=")
+		kid = strings.TrimSpace(kid)
+		if !found || kid == "" || secret == "" {
+			return nil, errors.New("JWT_SIGNING_KEYS: format attendu \"kid=secret,...\"")
+		}
+		if _, exists := set.keys[kid]; exists {
+			return nil, errors.New("JWT_SIGNING_KEYS: kid " + strconv.Quote(kid) + " en double")
+		}
+		set.keys[kid] = []byte(secret)
+		if active == "" {
+			active = kid
+		}
+	}
+	set.activeKID = active
+	return set, nil
+}
+
+// ActiveKID kid de la clé de signature ("" = clé historique JWT_SECRET)
+func (k *JWTKeySet) ActiveKID() string {
+	return k.activeKID
+}
+
+// Keys longueur de chaque clé par kid (contrôle de configuration, jamais les valeurs)
+func (k *JWTKeySet) Keys() map[string]int {
+	lengths := make(map[string]int, len(k.keys))
+	for kid, key := range k.keys {
+		lengths[kid] = len(key)
+	}
+	return lengths
+}
+
+func (k *JWTKeySet) empty() bool {
+	return k == nil || len(k.keys) == 0
+}
+
+// =============================================================================
+// SERVICE DE JETONS
+// =============================================================================
+
+// HS256TokenService émet et vérifie des jetons d'accès JWT signés en HMAC-SHA256
+// Le jeu de clés est remplaçable à chaud (SetKeys) lors d'une rotation
+type HS256TokenService struct {
+	keys  atomic.Pointer[JWTKeySet]
+	clock usecases.Clock
+}
+
+// NewHS256TokenService clock date l'émission et juge l'expiration (iat, exp)
+func NewHS256TokenService(keys *JWTKeySet, clock usecases.Clock) *HS256TokenService {
+	s := &HS256TokenService{clock: clock}
+	s.SetKeys(keys)
+	return s
+}
+
+// SetKeys remplace le jeu de clés ; les requêtes en cours terminent avec l'ancien
+func (s *HS256TokenService) SetKeys(keys *JWTKeySet) {
+	if keys == nil {
+		keys = &JWTKeySet{}
+	}
+	s.keys.Store(keys)
+}
+
+// Issue signe un jeton pour l'acteur, valable pendant ttl
+func (s *HS256TokenService) Issue(actor usecases.Actor, ttl time.Duration) (string, error) {
+	keys := s.keys.Load()
+	key, ok := keys.keys[keys.activeKID]
+	if !ok {
+		return "", errors.New("secret de signature non configuré")
+	}
+
+	now := s.clock.Now()
+	fields := tokenClaims{
+		Subject:  strconv.Itoa(actor.UserID),
+		TenantID: actor.TenantID,
+		Roles:    actor.Roles,
+		IssuedAt: now.Unix(),
+		Expires:  now.Add(ttl).Unix(),
+	}
+	if actor.Impersonation != nil {
+		fields.Actor = &tokenActorClaims{Subject: strconv.Itoa(actor.Impersonation.AdminID)}
+		fields.Scope = strings.Join(actor.Impersonation.Scope, " ")
+		fields.Banner = actor.Impersonation.Banner
+	}
+	claims, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+
+	header := legacyTokenHeader
+	if keys.activeKID != "" {
+		fields, err := json.Marshal(tokenHeaderFields{Algorithm: "HS256", KeyID: keys.activeKID, Type: "JWT"})
+		if err != nil {
+			return "", err
+		}
+		header = base64.RawURLEncoding.EncodeToString(fields)
+	}
+
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return signingInput + "." + signHS256(key, signingInput), nil
+}
+
+// Verify contrôle la signature avec la clé désignée par le kid, l'expiration, puis retourne
+// l'acteur du jeton. Un jeton sans kid n'est vérifié que par la clé historique
+func (s *HS256TokenService) Verify(token string) (usecases.Actor, error) {
+	keys := s.keys.Load()
+	if keys.empty() {
+		return usecases.Actor{}, ErrInvalidToken // Aucun secret : tout est refusé
+	}
+
+	header, rest, found := strings.Cut(token, ".")
+	if !found {
+		return usecases.Actor{}, ErrInvalidToken
+	}
+	payload, signature, found := strings.Cut(rest, ".")
+	if !found {
+		return usecases.Actor{}, ErrInvalidToken
+	}
+
+	kid, err := parseTokenHeader(header)
+	if err != nil {
+		return usecases.Actor{}, err
+	}
+	key, ok := keys.keys[kid]
+	if !ok {
+		return usecases.Actor{}, ErrInvalidToken // Clé retirée du jeu ou inconnue
+	}
+	if !hmac.Equal([]byte(signature), []byte(signHS256(key, header+"."+payload))) {
+		return usecases.Actor{}, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return usecases.Actor{}, ErrInvalidToken
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return usecases.Actor{}, ErrInvalidToken
+	}
+
+	if s.clock.Now().Unix() >= claims.Expires {
+		return usecases.Actor{}, ErrExpiredToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return usecases.Actor{}, ErrInvalidToken
+	}
+
+	actor := usecases.Actor{UserID: userID, TenantID: claims.TenantID, Roles: claims.Roles}
+	if claims.Actor != nil {
+		adminID, err := strconv.Atoi(claims.Actor.Subject)
+		if err != nil || adminID <= 0 {
+			return usecases.Actor{}, ErrInvalidToken
+		}
+		actor.Impersonation = &usecases.Impersonation{AdminID: adminID, Banner: claims.Banner, Scope: strings.Fields(claims.Scope)}
+	}
+	return actor, nil
+}
+
+// parseTokenHeader retourne le kid d'un en-tête HS256 ; tout autre champ est refusé
+func parseTokenHeader(header string) (string, error) {
+	if header == legacyTokenHeader {
+		return "", nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(header)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var fields tokenHeaderFields
+	if err := decoder.Decode(&fields); err != nil {
+		return "", ErrInvalidToken
+	}
+	if fields.Algorithm != "HS256" || (fields.Type != "" && fields.Type != "JWT") || fields.KeyID == "" {
+		return "", ErrInvalidToken
+	}
+	return fields.KeyID, nil
+}
+
+func signHS256(key []byte, signingInput string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var tokenTestNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func mustJWTKeySet(t *testing.T, signingKeys, legacySecret string) *JWTKeySet {
+	t.Helper()
+	keys, err := ParseJWTKeySet(signingKeys, legacySecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+// tokenTestSign jeton signé indépendamment de Issue : en-tête et claims écrits à la main
+func tokenTestSign(secret, header, claims string) string {
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseJWTKeySet(t *testing.T) {
+	tests := []struct {
+		name       string
+		keys       string
+		wantActive string
+		wantErr    bool
+	}{
+		{"première clé active", "k2=secret-2, k1=secret-1", "k2", false},
+		{"virgules superflues", ",k1=secret-1,,", "k1", false},
+		{"secret contenant =", "k1=a=b", "k1", false},
+		{"aucune clé", "", "", false},
+		{"sans =", "k1", "", true},
+		{"kid vide", "=secret", "", true},
+		{"kid blanc", " =secret", "", true},
+		{"secret vide", "k1=", "", true},
+		{"kid en double", "k1=secret-1,k1=secret-2", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseJWTKeySet(tt.keys, "")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("jeu de clés %q accepté", tt.keys)
+				}
+				// Le message d'erreur ne reprend jamais un secret
+				if strings.Contains(err.Error(), "secret-") {
+					t.Fatalf("secret dans l'erreur : %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if keys.ActiveKID() != tt.wantActive {
+				t.Fatalf("clé active %q, attendu %q", keys.ActiveKID(), tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestHS256TokenServiceVerify(t *testing.T) {
+	clock := NewFakeClock(tokenTestNow)
+	service := NewHS256TokenService(mustJWTKeySet(t, "k1=secret-1", "legacy-secret"), clock)
+	valid, err := service.Issue(usecases.Actor{UserID: 42, TenantID: "acme", Roles: []string{"member"}}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, rest, _ := strings.Cut(valid, ".")
+	payload, signature, _ := strings.Cut(rest, ".")
+
+	claims := `{"sub":"42","tenant":"acme","iat":1772445600,"exp":1772449200}`
+	adminClaims := `{"sub":"42","tenant":"acme","roles":["admin"],"iat":1772445600,"exp":1772449200}`
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"jeton émis", valid, nil},
+		{"jeton signé à la main", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, claims), nil},
+		{"jeton historique sans kid", tokenTestSign("legacy-secret", `{"alg":"HS256","typ":"JWT"}`, claims), nil},
+		{"kid inconnu", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k9","typ":"JWT"}`, claims), ErrInvalidToken},
+		{"kid vide", tokenTestSign("secret-1", `{"alg":"HS256","kid":"","typ":"JWT"}`, claims), ErrInvalidToken},
+		// Sans kid, seule la clé historique vérifie : la clé active ne signe pas d'en-tête historique
+		{"clé active sans kid", tokenTestSign("secret-1", `{"alg":"HS256","typ":"JWT"}`, claims), ErrInvalidToken},
+		{"alg none", tokenTestSign("", `{"alg":"none","kid":"k1","typ":"JWT"}`, claims), ErrInvalidToken},
+		{"alg none sans signature", base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + payload + ".", ErrInvalidToken},
+		{"alg HS512", tokenTestSign("secret-1", `{"alg":"HS512","kid":"k1","typ":"JWT"}`, claims), ErrInvalidToken},
+		{"alg RS256", tokenTestSign("secret-1", `{"alg":"RS256","kid":"k1","typ":"JWT"}`, claims), ErrInvalidToken},
+		{"alg en minuscules", tokenTestSign("secret-1", `{"alg":"hs256","kid":"k1","typ":"JWT"}`, claims), ErrInvalidToken},
+		{"champ d'en-tête inconnu", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT","jku":"https://evil.example.com"}`, claims), ErrInvalidToken},
+		{"typ inattendu", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWE"}`, claims), ErrInvalidToken},
+		{"autre secret", tokenTestSign("attacker", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, claims), ErrInvalidToken},
+		{"claims modifiées", header + "." + base64.RawURLEncoding.EncodeToString([]byte(adminClaims)) + "." + signature, ErrInvalidToken},
+		{"signature tronquée", valid[:len(valid)-1], ErrInvalidToken},
+		{"signature vide", header + "." + payload + ".", ErrInvalidToken},
+		{"signature absente", header + "." + payload, ErrInvalidToken},
+		{"segment en trop", valid + ".x", ErrInvalidToken},
+		{"en-tête seul", header, ErrInvalidToken},
+		{"en-tête tronqué", header[:len(header)-2] + "." + payload + "." + signature, ErrInvalidToken},
+		{"en-tête non base64", "!!." + payload + "." + signature, ErrInvalidToken},
+		{"claims non JSON", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, `not json`), ErrInvalidToken},
+		{"sujet non numérique", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, `{"sub":"admin","exp":1772449200}`), ErrInvalidToken},
+		{"sujet nul", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, `{"sub":"0","exp":1772449200}`), ErrInvalidToken},
+		{"administrateur impersonant invalide", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, `{"sub":"42","act":{"sub":"x"},"exp":1772449200}`), ErrInvalidToken},
+		{"sans expiration", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, `{"sub":"42"}`), ErrExpiredToken},
+		{"expiré", tokenTestSign("secret-1", `{"alg":"HS256","kid":"k1","typ":"JWT"}`, `{"sub":"42","exp":1772445600}`), ErrExpiredToken},
+		{"jeton vide", "", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor, err := service.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erreur %v, attendu %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (actor.UserID != 42 || actor.TenantID != "acme") {
+				t.Fatalf("acteur inattendu : %+v", actor)
+			}
+		})
+	}
+}
+
+// Rotation : l'ancienne clé vérifie tant qu'elle reste dans le jeu, puis ses jetons sont refusés
+func TestHS256TokenServiceKeyRotation(t *testing.T) {
+	clock := NewFakeClock(tokenTestNow)
+	service := NewHS256TokenService(mustJWTKeySet(t, "k1=secret-1", ""), clock)
+	old, err := service.Issue(usecases.Actor{UserID: 42}, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Délai de grâce : la nouvelle clé signe, l'ancienne vérifie encore
+	service.SetKeys(mustJWTKeySet(t, "k2=secret-2,k1=secret-1", ""))
+	current, err := service.Issue(usecases.Actor{UserID: 42}, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid, err := parseTokenHeader(strings.Split(current, ".")[0]); err != nil || kid != "k2" {
+		t.Fatalf("jeton signé par %q (%v), attendu k2", kid, err)
+	}
+	for _, token := range []string{old, current} {
+		if _, err := service.Verify(token); err != nil {
+			t.Fatalf("jeton refusé pendant le délai de grâce : %v", err)
+		}
+	}
+
+	// Ancienne clé retirée : ses jetons sont refusés, même non expirés
+	service.SetKeys(mustJWTKeySet(t, "k2=secret-2", ""))
+	if _, err := service.Verify(old); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("jeton de la clé retirée : erreur %v, attendu ErrInvalidToken", err)
+	}
+	if _, err := service.Verify(current); err != nil {
+		t.Fatalf("jeton de la clé active refusé : %v", err)
+	}
+
+	// Expiration jugée par l'horloge injectée
+	clock.Set(tokenTestNow.Add(24 * time.Hour))
+	if _, err := service.Verify(current); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("erreur %v, attendu ErrExpiredToken", err)
+	}
+
+	// Aucun secret : tout est refusé, rien n'est émis
+	service.SetKeys(nil)
+	if _, err := service.Verify(current); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("erreur %v, attendu ErrInvalidToken", err)
+	}
+	if _, err := service.Issue(usecases.Actor{UserID: 42}, time.Hour); err == nil {
+		t.Fatal("jeton émis sans secret")
+	}
+}
+
+// La session d'impersonation et son périmètre survivent à l'aller-retour
+func TestHS256TokenServiceImpersonationScope(t *testing.T) {
+	service := NewHS256TokenService(mustJWTKeySet(t, "k1=secret-1", ""), NewFakeClock(tokenTestNow))
+	token, err := service.Issue(usecases.Actor{
+		UserID:        42,
+		Impersonation: &usecases.Impersonation{AdminID: 1, Banner: "support", Scope: []string{"get_user", "list_sessions"}},
+	}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actor, err := service.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impersonation := actor.Impersonation
+	if impersonation == nil || impersonation.AdminID != 1 || impersonation.Banner != "support" || !slices.Equal(impersonation.Scope, []string{"get_user", "list_sessions"}) {
+		t.Fatalf("impersonation inattendue : %+v", impersonation)
+	}
+}
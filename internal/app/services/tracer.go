@@ -0,0 +1,76 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// LogTracer implémente usecases.Tracer en journalisant les spans
+// Il propage trace_id/span_id dans le context ; un exporteur OpenTelemetry
+// pourra le remplacer sans toucher aux use cases
+type LogTracer struct {
+	logger usecases.Logger
+}
+
+func NewLogTracer(logger usecases.Logger) *LogTracer {
+	return &LogTracer{logger: logger}
+}
+
+type traceContextKey struct{}
+
+type logSpan struct {
+	logger   usecases.Logger
+	name     string
+	traceID  string
+	spanID   string
+	parentID string
+	start    time.Time
+}
+
+func (t *LogTracer) StartSpan(ctx context.Context, name string) (context.Context, usecases.Span) {
+	span := &logSpan{
+		logger: t.logger,
+		name:   name,
+		spanID: randomHex(8),
+		start:  time.Now(),
+	}
+
+	if parent, ok := ctx.Value(traceContextKey{}).(*logSpan); ok {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		span.traceID = randomHex(16)
+	}
+
+	return context.WithValue(ctx, traceContextKey{}, span), span
+}
+
+func (s *logSpan) End(err error) {
+	fields := map[string]interface{}{
+		"span":        s.name,
+		"trace_id":    s.traceID,
+		"span_id":     s.spanID,
+		"duration_ms": time.Since(s.start).Milliseconds(),
+	}
+	if s.parentID != "" {
+		fields["parent_id"] = s.parentID
+	}
+	if err != nil {
+		fields["status"] = "error"
+	}
+	s.logger.Info("Span finished", fields)
+}
+
+// TraceParent en-tête W3C Trace Context des appels sortants faits sous ce span
+func (s *logSpan) TraceParent() string {
+	return "00-" + s.traceID + "-" + s.spanID + "-01"
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TwilioSMSClient implémente SMSClient avec l'API REST de Twilio
+type TwilioSMSClient struct {
+	accountSID string
+	authToken  string
+	baseURL    string
+	client     *http.Client
+}
+
+func NewTwilioSMSClient(accountSID, authToken string, clients *httpclient.Factory) *TwilioSMSClient {
+	return &TwilioSMSClient{
+		accountSID: accountSID,
+		authToken:  authToken,
+		baseURL:    "https://api.twilio.com/2010-04-01",
+		client:     clients.Client("twilio", httpclient.ClientOptions{Timeout: 10 * time.Second}),
+	}
+}
+
+func (c *TwilioSMSClient) SendMessage(ctx context.Context, from, to, body string) (string, error) {
+	form := url.Values{}
+	form.Set("From", from)
+	form.Set("To", to)
+	form.Set("Body", body)
+
+	endpoint := c.baseURL + "/Accounts/" + url.PathEscape(c.accountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(c.accountSID, c.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		SID     string `json:"sid"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode >= 300 {
+		return "", errors.New("twilio: " + resp.Status + ": " + result.Message)
+	}
+	return result.SID, nil
+}
+
+// LogSMSClient implémente SMSClient en journalisant les SMS (développement)
+type LogSMSClient struct {
+	logger usecases.Logger
+}
+
+func NewLogSMSClient(logger usecases.Logger) *LogSMSClient {
+	return &LogSMSClient{logger: logger}
+}
+
+func (c *LogSMSClient) SendMessage(ctx context.Context, from, to, body string) (string, error) {
+	c.logger.Info("SMS sent", map[string]interface{}{
+		"from": from,
+		"to":   to,
+	})
+	return "log", nil
+}
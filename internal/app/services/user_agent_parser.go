@@ -0,0 +1,76 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"strings"
+)
+
+// UserAgentParser implémente usecases.UserAgentParser par recherche de jetons connus dans
+// l'en-tête : suffisant pour reconnaître un appareil, sans base de signatures à maintenir
+type UserAgentParser struct{}
+
+func NewUserAgentParser() *UserAgentParser {
+	return &UserAgentParser{}
+}
+
+// agentToken jeton recherché (en minuscules) et nom retenu ; le premier trouvé l'emporte
+type agentToken struct {
+	token string
+	name  string
+}
+
+// Ordre significatif : Edge et Opera s'annoncent aussi Chrome, Chrome s'annonce aussi Safari,
+// iOS et Android s'annoncent aussi macOS / Linux
+var (
+	browserTokens = []agentToken{
+		{"edg/", "Edge"}, {"opr/", "Opera"}, {"opera", "Opera"}, {"samsungbrowser", "Samsung Internet"},
+		{"firefox/", "Firefox"}, {"fxios/", "Firefox"}, {"crios/", "Chrome"}, {"chrome/", "Chrome"},
+		{"safari/", "Safari"}, {"curl/", "curl"}, {"okhttp", "OkHttp"},
+	}
+	osTokens = []agentToken{
+		{"iphone", "iOS"}, {"ipad", "iPadOS"}, {"android", "Android"}, {"windows", "Windows"},
+		{"cros", "ChromeOS"}, {"mac os x", "macOS"}, {"macintosh", "macOS"}, {"linux", "Linux"},
+	}
+	botTokens = []string{"bot", "crawler", "spider", "headless"}
+)
+
+func (p *UserAgentParser) Parse(userAgent string) entities.DeviceInfo {
+	ua := strings.ToLower(userAgent)
+	if strings.TrimSpace(ua) == "" {
+		return entities.DeviceInfo{Type: entities.DeviceUnknown}
+	}
+
+	device := entities.DeviceInfo{OS: matchAgentToken(ua, osTokens), Browser: matchAgentToken(ua, browserTokens)}
+	switch {
+	case containsAny(ua, botTokens):
+		device.Type = entities.DeviceBot
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(device.OS == "Android" && !strings.Contains(ua, "mobile")):
+		device.Type = entities.DeviceTablet
+	case strings.Contains(ua, "mobile") || device.OS == "iOS":
+		device.Type = entities.DeviceMobile
+	case device.OS != "":
+		device.Type = entities.DeviceDesktop
+	default:
+		device.Type = entities.DeviceUnknown
+	}
+	return device
+}
+
+func matchAgentToken(ua string, tokens []agentToken) string {
+	for _, candidate := range tokens {
+		if strings.Contains(ua, candidate.token) {
+			return candidate.name
+		}
+	}
+	return ""
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
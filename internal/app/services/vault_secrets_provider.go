@@ -0,0 +1,112 @@
+package services
+
+import (
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VaultConfig accès au moteur KV v2 de HashiCorp Vault
+type VaultConfig struct {
+	Address   string // ex: https://vault.internal:8200
+	Token     string
+	Namespace string              // Vault Enterprise ; vide = namespace racine
+	Mount     string              // point de montage du moteur KV (défaut "secret")
+	Path      string              // chemin du secret regroupant les clés de l'API, ex: "clean-archi/api"
+	Clients   *httpclient.Factory // nil = client sans instrumentation
+}
+
+// VaultSecretsProvider implémente SecretsProvider avec un secret KV v2 dont chaque clé est
+// un secret de l'API :
+//
+//	GET {address}/v1/{mount}/data/{path}  (X-Vault-Token: <token>)
+//	{"data": {"data": {"JWT_SIGNING_KEYS": "...", "DATABASE_URL": "..."}, "metadata": {...}}}
+//
+// La dernière version est lue à chaque appel : une nouvelle version écrite dans Vault
+// est prise en compte au rechargement suivant
+type VaultSecretsProvider struct {
+	endpoint  string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+func NewVaultSecretsProvider(cfg VaultConfig) (*VaultSecretsProvider, error) {
+	address, err := url.Parse(cfg.Address)
+	if err != nil || address.Host == "" || (address.Scheme != "http" && address.Scheme != "https") {
+		return nil, errors.New("vault: adresse invalide")
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("vault: jeton manquant")
+	}
+	mount := strings.Trim(cfg.Mount, "/")
+	if mount == "" {
+		mount = "secret"
+	}
+	path := strings.Trim(cfg.Path, "/")
+	if path == "" {
+		return nil, errors.New("vault: chemin du secret manquant")
+	}
+
+	return &VaultSecretsProvider{
+		endpoint:  strings.TrimRight(cfg.Address, "/") + "/v1/" + mount + "/data/" + path,
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		client:    cfg.Clients.Client("vault", httpclient.ClientOptions{Timeout: 5 * time.Second}),
+	}, nil
+}
+
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+func (p *VaultSecretsProvider) Secret(ctx context.Context, name string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		// Chemin absent, ou dernière version supprimée
+		return "", ErrSecretNotFound
+	case resp.StatusCode != http.StatusOK:
+		return "", errors.New("vault: " + resp.Status)
+	}
+
+	var result vaultKVResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("vault: réponse invalide : %w", err)
+	}
+	value, ok := result.Data.Data[name]
+	if !ok || value == nil {
+		return "", ErrSecretNotFound
+	}
+	secret, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault: la clé %s n'est pas une chaîne", name)
+	}
+	if secret == "" {
+		return "", ErrSecretNotFound
+	}
+	return secret, nil
+}
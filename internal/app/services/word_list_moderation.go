@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bufio"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// moderationWords listes intégrées par langue (MODERATION_LANGUAGES), complétées par un fichier
+var moderationWords = map[string][]string{
+	"en": {"fuck*", "shit", "damn", "idiot", "stupid", "hitler", "cunt", "bitch", "bastard", "asshole", "whore", "slut", "retard", "nigger", "faggot", "nazi"},
+	"fr": {"merde", "putain", "connard", "connasse", "salope", "encule", "batard", "pute", "bite", "couille", "nique", "negro", "abruti"},
+	"es": {"mierda", "puta", "cabron", "pendejo", "gilipollas", "maricon", "cono", "joder"},
+	"de": {"scheisse", "arschloch", "hure", "fotze", "wichser", "schlampe", "hurensohn"},
+}
+
+// leetspeak substitutions courantes des chiffres et symboles
+var leetspeak = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l', '+': 't', '€': 'e',
+}
+
+// accents lettres accentuées ramenées à leur base (é → e, ß → ss...)
+var accents = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "ö", "o", "õ", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y", "ß", "ss",
+)
+
+// shortWordLength les mots de cette taille ou moins ne sont cherchés qu'en entier ("cunt" ne
+// refuse pas "Scunthorpe"), sauf s'ils se terminent par "*" dans la liste
+const shortWordLength = 4
+
+// WordListModeration implémente usecases.ContentModeration avec des listes de mots, sans appel
+// externe. Le texte est normalisé avant comparaison : minuscules, accents retirés, leetspeak
+// (sh1t, @ss), lettres répétées (fuuuck), séparateurs dans un mot (f.u.c.k, f_u_c_k) et lettres
+// isolées (f u c k). Les mots longs sont cherchés dans chaque mot du texte, les courts en entier
+type WordListModeration struct {
+	long  []string
+	short map[string]bool
+}
+
+// LoadWordListModeration listes intégrées des langues demandées, plus les mots de path
+// (un par ligne, # pour les commentaires, "mot*" pour chercher un mot court partout) s'il n'est pas vide
+func LoadWordListModeration(languages []string, path string) (*WordListModeration, error) {
+	var words []string
+	for _, language := range languages {
+		list, ok := moderationWords[language]
+		if !ok {
+			return nil, fmt.Errorf("liste de modération inconnue : %s", language)
+		}
+		words = append(words, list...)
+	}
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			word, _, _ := strings.Cut(scanner.Text(), "#")
+			if word = strings.TrimSpace(word); word != "" {
+				words = append(words, word)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return NewWordListModeration(words), nil
+}
+
+func NewWordListModeration(words []string) *WordListModeration {
+	m := &WordListModeration{short: make(map[string]bool)}
+	for _, word := range words {
+		word, anywhere := strings.CutSuffix(word, "*")
+		normalized := squeeze(normalizeModerated(word))
+		if normalized == "" {
+			continue
+		}
+		if len(normalized) <= shortWordLength && !anywhere {
+			m.short[normalized] = true
+		} else {
+			m.long = append(m.long, normalized)
+		}
+	}
+	return m
+}
+
+func (m *WordListModeration) Moderate(ctx context.Context, kind, text string) (*usecases.ModerationVerdict, error) {
+	for _, token := range moderationTokens(text) {
+		if m.short[token] {
+			return &usecases.ModerationVerdict{Allowed: false, Reason: "word_list"}, nil
+		}
+		for _, word := range m.long {
+			if strings.Contains(token, word) {
+				return &usecases.ModerationVerdict{Allowed: false, Reason: "word_list"}, nil
+			}
+		}
+	}
+	return &usecases.ModerationVerdict{Allowed: true}, nil
+}
+
+// moderationTokens mots normalisés du texte, sous deux formes (telle quelle et lettres répétées
+// réduites), plus la suite des lettres isolées recollées ("f u c k")
+func moderationTokens(text string) []string {
+	var tokens []string
+	var letters strings.Builder
+	flushLetters := func() {
+		if letters.Len() > 1 {
+			tokens = append(tokens, letters.String(), squeeze(letters.String()))
+		}
+		letters.Reset()
+	}
+	for _, field := range strings.FieldsFunc(text, unicode.IsSpace) {
+		token := normalizeModerated(field)
+		if token == "" {
+			continue
+		}
+		if len(token) == 1 {
+			letters.WriteString(token)
+			continue
+		}
+		flushLetters()
+		tokens = append(tokens, token, squeeze(token))
+	}
+	flushLetters()
+	return tokens
+}
+
+// normalizeModerated minuscules, accents et leetspeak ; tout ce qui n'est pas une lettre est retiré
+func normalizeModerated(text string) string {
+	text = accents.Replace(strings.ToLower(text))
+	var normalized strings.Builder
+	for _, r := range text {
+		if replacement, ok := leetspeak[r]; ok {
+			r = replacement
+		}
+		if unicode.IsLetter(r) {
+			normalized.WriteRune(r)
+		}
+	}
+	return normalized.String()
+}
+
+// squeeze réduit les lettres répétées : "fuuuck" → "fuck"
+func squeeze(text string) string {
+	var squeezed strings.Builder
+	var previous rune
+	for i, r := range text {
+		if i == 0 || r != previous {
+			squeezed.WriteRune(r)
+		}
+		previous = r
+	}
+	return squeezed.String()
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPWorkspaceProvisioner implémente usecases.WorkspaceProvisioner avec l'API de provisioning
+// de la plateforme analytics :
+//
+//	POST   {baseURL}/workspaces       {"owner_id": 42, "owner_email": "..."} → 201 {"id": "ws_..."}
+//	DELETE {baseURL}/workspaces/{id}  → 204 (404 accepté : espace déjà supprimé)
+type HTTPWorkspaceProvisioner struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewHTTPWorkspaceProvisioner(baseURL, token string, clients *httpclient.Factory) *HTTPWorkspaceProvisioner {
+	return &HTTPWorkspaceProvisioner{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  clients.Client("workspaces", httpclient.ClientOptions{Timeout: 15 * time.Second}),
+	}
+}
+
+func (p *HTTPWorkspaceProvisioner) ProvisionWorkspace(ctx context.Context, userID int, email string) (string, error) {
+	payload, err := json.Marshal(map[string]interface{}{"owner_id": userID, "owner_email": email})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/workspaces", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Un nouvel essai après une réponse perdue ne crée pas un second espace
+	req.Header.Set("Idempotency-Key", "owner-"+strconv.Itoa(userID))
+
+	resp, err := p.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", errors.New("workspaces: " + resp.Status)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("workspaces: réponse invalide : %w", err)
+	}
+	if result.ID == "" {
+		return "", errors.New("workspaces: identifiant manquant dans la réponse")
+	}
+	return result.ID, nil
+}
+
+func (p *HTTPWorkspaceProvisioner) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, p.baseURL+"/workspaces/"+url.PathEscape(workspaceID), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return errors.New("workspaces: " + resp.Status)
+	}
+	return nil
+}
+
+func (p *HTTPWorkspaceProvisioner) do(req *http.Request) (*http.Response, error) {
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	return p.client.Do(req)
+}
+
+// LocalWorkspaceProvisioner espaces tenus en mémoire, sans plateforme de provisioning
+// (développement, démonstrations)
+type LocalWorkspaceProvisioner struct {
+	tokens     usecases.TokenGenerator
+	mutex      sync.Mutex
+	workspaces map[string]int
+}
+
+func NewLocalWorkspaceProvisioner(tokens usecases.TokenGenerator) *LocalWorkspaceProvisioner {
+	return &LocalWorkspaceProvisioner{tokens: tokens, workspaces: make(map[string]int)}
+}
+
+func (p *LocalWorkspaceProvisioner) ProvisionWorkspace(ctx context.Context, userID int, email string) (string, error) {
+	suffix, err := p.tokens.HexToken(8)
+	if err != nil {
+		return "", err
+	}
+	id := "ws_" + suffix
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.workspaces[id] = userID
+	return id, nil
+}
+
+func (p *LocalWorkspaceProvisioner) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.workspaces, workspaceID)
+	return nil
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// =============================================================================
+// ARBRE XML (préfixes conservés : la canonicalisation en a besoin)
+// =============================================================================
+
+const (
+	xmlNamespace   = "http://www.w3.org/XML/1998/namespace"
+	xmlDSigNS      = "http://www.w3.org/2000/09/xmldsig#"
+	xmlExcC14N     = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	xmlEnveloped   = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	xmlMaxNodes    = 10_000
+	xmlMaxDepthLen = 64
+)
+
+// Algorithmes de signature et d'empreinte acceptés (SHA-1 est refusé)
+var (
+	xmlSignatureHashes = map[string]crypto.Hash{
+		"http://www.w3.org/2001/04/xmldsig-more#rsa-sha256": crypto.SHA256,
+		"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512": crypto.SHA512,
+	}
+	xmlDigestHashes = map[string]crypto.Hash{
+		"http://www.w3.org/2001/04/xmlenc#sha256": crypto.SHA256,
+		"http://www.w3.org/2001/04/xmlenc#sha512": crypto.SHA512,
+	}
+)
+
+// xmlNode élément XML ; text n'est renseigné que pour les nœuds texte (element == false)
+type xmlNode struct {
+	parent  *xmlNode
+	element bool
+	text    string
+
+	prefix string
+	local  string
+	// space URI de l'espace de noms de l'élément, résolue à l'analyse
+	space string
+	// namespaces déclarations portées par l'élément (préfixe "" = espace par défaut)
+	namespaces map[string]string
+	// attributes attributs hors déclarations xmlns, préfixe dans Name.Space
+	attributes []xml.Attr
+	children   []*xmlNode
+}
+
+// parseXMLTree construit l'arbre d'un document ; DTD refusée (entités, attaques d'expansion)
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root, current *xmlNode
+	nodes, depth := 0, 0
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if nodes++; nodes > xmlMaxNodes {
+			return nil, errors.New("document XML trop volumineux")
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			if current == nil && root != nil {
+				return nil, errors.New("plusieurs éléments racine")
+			}
+			if depth++; depth > xmlMaxDepthLen {
+				return nil, errors.New("document XML trop profond")
+			}
+			node := &xmlNode{parent: current, element: true, prefix: token.Name.Space, local: token.Name.Local, namespaces: map[string]string{}}
+			for _, attr := range token.Attr {
+				switch {
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					node.namespaces[""] = attr.Value
+				case attr.Name.Space == "xmlns":
+					node.namespaces[attr.Name.Local] = attr.Value
+				default:
+					node.attributes = append(node.attributes, attr)
+				}
+			}
+			space, ok := node.lookupNamespace(node.prefix)
+			if !ok {
+				return nil, fmt.Errorf("préfixe non déclaré %q", node.prefix)
+			}
+			node.space = space
+
+			if current == nil {
+				root = node
+			} else {
+				current.children = append(current.children, node)
+			}
+			current = node
+		case xml.EndElement:
+			if current == nil || token.Name.Space != current.prefix || token.Name.Local != current.local {
+				return nil, errors.New("balise fermante inattendue")
+			}
+			depth--
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, &xmlNode{parent: current, text: string(token)})
+			}
+		case xml.Directive:
+			return nil, errors.New("DTD non autorisée")
+		}
+		// Commentaires et instructions de traitement ignorés (canonicalisation sans commentaires)
+	}
+
+	if root == nil || current != nil {
+		return nil, errors.New("document XML incomplet")
+	}
+	return root, nil
+}
+
+// lookupNamespace URI liée au préfixe dans la portée de l'élément ("" : espace par défaut)
+func (n *xmlNode) lookupNamespace(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespace, true
+	}
+	for node := n; node != nil; node = node.parent {
+		if uri, ok := node.namespaces[prefix]; ok {
+			return uri, true
+		}
+	}
+	return "", prefix == ""
+}
+
+// attr / childrenNamed / child acceptent un nœud nil : les éléments optionnels s'enchaînent sans garde
+func (n *xmlNode) attr(local string) string {
+	if n == nil {
+		return ""
+	}
+	for _, attr := range n.attributes {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func (n *xmlNode) childrenNamed(space, local string) []*xmlNode {
+	if n == nil {
+		return nil
+	}
+	var found []*xmlNode
+	for _, child := range n.children {
+		if child.element && child.space == space && child.local == local {
+			found = append(found, child)
+		}
+	}
+	return found
+}
+
+// child premier enfant portant ce nom, nil sinon
+func (n *xmlNode) child(space, local string) *xmlNode {
+	if children := n.childrenNamed(space, local); len(children) > 0 {
+		return children[0]
+	}
+	return nil
+}
+
+// textContent texte direct de l'élément, espaces de bord retirés
+func (n *xmlNode) textContent() string {
+	if n == nil {
+		return ""
+	}
+	var builder strings.Builder
+	for _, child := range n.children {
+		if !child.element {
+			builder.WriteString(child.text)
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// collectIDs vérifie l'unicité des attributs ID : deux éléments de même ID permettraient
+// de faire vérifier l'un et exploiter l'autre (signature wrapping)
+func collectIDs(root *xmlNode) (map[string]*xmlNode, error) {
+	ids := make(map[string]*xmlNode)
+	var walk func(node *xmlNode) error
+	walk = func(node *xmlNode) error {
+		if id := node.attr("ID"); id != "" {
+			if _, exists := ids[id]; exists {
+				return fmt.Errorf("ID %q dupliqué", id)
+			}
+			ids[id] = node
+		}
+		for _, child := range node.children {
+			if child.element {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return ids, walk(root)
+}
+
+// =============================================================================
+// CANONICALISATION EXCLUSIVE (xml-exc-c14n, sans commentaires)
+// =============================================================================
+
+type excC14N struct {
+	// inclusive préfixes de InclusiveNamespaces PrefixList ("#default" = espace par défaut)
+	inclusive []string
+	// exclude élément retiré de la sortie (transformation enveloped-signature)
+	exclude *xmlNode
+	buffer  bytes.Buffer
+}
+
+func canonicalize(node *xmlNode, inclusive []string, exclude *xmlNode) ([]byte, error) {
+	c := &excC14N{inclusive: inclusive, exclude: exclude}
+	if err := c.element(node, map[string]string{}); err != nil {
+		return nil, err
+	}
+	return c.buffer.Bytes(), nil
+}
+
+func (c *excC14N) element(node *xmlNode, rendered map[string]string) error {
+	// Espaces de noms « visiblement utilisés » : celui de l'élément et ceux de ses attributs préfixés
+	used := []string{node.prefix}
+	for _, attr := range node.attributes {
+		if attr.Name.Space != "" && attr.Name.Space != "xml" {
+			used = append(used, attr.Name.Space)
+		}
+	}
+	for _, prefix := range c.inclusive {
+		if prefix == "#default" {
+			prefix = ""
+		}
+		if _, inScope := node.lookupNamespace(prefix); inScope {
+			used = append(used, prefix)
+		}
+	}
+	slices.Sort(used)
+	used = slices.Compact(used)
+
+	type declaration struct{ prefix, uri string }
+	var declarations []declaration
+	next := rendered
+	for _, prefix := range used {
+		uri, ok := node.lookupNamespace(prefix)
+		if !ok {
+			return fmt.Errorf("préfixe non déclaré %q", prefix)
+		}
+		current, seen := rendered[prefix]
+		if (seen && current == uri) || (!seen && prefix == "" && uri == "") {
+			continue
+		}
+		if len(declarations) == 0 {
+			next = make(map[string]string, len(rendered)+len(used))
+			for key, value := range rendered {
+				next[key] = value
+			}
+		}
+		declarations = append(declarations, declaration{prefix, uri})
+		next[prefix] = uri
+	}
+
+	type attribute struct{ space, qname, local, value string }
+	attributes := make([]attribute, 0, len(node.attributes))
+	for _, attr := range node.attributes {
+		qname, space := attr.Name.Local, ""
+		if attr.Name.Space != "" {
+			qname = attr.Name.Space + ":" + attr.Name.Local
+			space, _ = node.lookupNamespace(attr.Name.Space)
+		}
+		attributes = append(attributes, attribute{space, qname, attr.Name.Local, attr.Value})
+	}
+	slices.SortFunc(attributes, func(a, b attribute) int {
+		if order := strings.Compare(a.space, b.space); order != 0 {
+			return order
+		}
+		return strings.Compare(a.local, b.local)
+	})
+
+	qname := node.local
+	if node.prefix != "" {
+		qname = node.prefix + ":" + node.local
+	}
+	c.buffer.WriteString("<" + qname)
+	for _, declaration := range declarations {
+		if declaration.prefix == "" {
+			c.buffer.WriteString(` xmlns="`)
+		} else {
+			c.buffer.WriteString(" xmlns:" + declaration.prefix + `="`)
+		}
+		c.buffer.WriteString(escapeC14NAttribute(declaration.uri) + `"`)
+	}
+	for _, attr := range attributes {
+		c.buffer.WriteString(" " + attr.qname + `="` + escapeC14NAttribute(attr.value) + `"`)
+	}
+	c.buffer.WriteString(">")
+
+	for _, child := range node.children {
+		switch {
+		case child == c.exclude:
+		case child.element:
+			if err := c.element(child, next); err != nil {
+				return err
+			}
+		default:
+			c.buffer.WriteString(escapeC14NText(child.text))
+		}
+	}
+	c.buffer.WriteString("</" + qname + ">")
+	return nil
+}
+
+var (
+	c14nTextEscaper      = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	c14nAttributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeC14NText(text string) string {
+	return c14nTextEscaper.Replace(text)
+}
+
+func escapeC14NAttribute(value string) string {
+	return c14nAttributeEscaper.Replace(value)
+}
+
+// =============================================================================
+// VÉRIFICATION DE SIGNATURE ENVELOPPÉE (XML-DSig)
+// =============================================================================
+
+// verifyEnvelopedSignature vérifie la signature enfant direct de signed, qui doit référencer
+// signed lui-même (URI="#ID") : seul le contenu de signed est alors authentifié
+func verifyEnvelopedSignature(signed *xmlNode, certificate *x509.Certificate) error {
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("seules les clés RSA sont prises en charge")
+	}
+
+	signatures := signed.childrenNamed(xmlDSigNS, "Signature")
+	if len(signatures) != 1 {
+		return errors.New("une signature exactement est attendue")
+	}
+	signature := signatures[0]
+
+	signedInfo := signature.child(xmlDSigNS, "SignedInfo")
+	if signedInfo == nil {
+		return errors.New("SignedInfo manquant")
+	}
+	c14nMethod := signedInfo.child(xmlDSigNS, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.attr("Algorithm") != xmlExcC14N {
+		return errors.New("canonicalisation non prise en charge")
+	}
+	signatureMethod := signedInfo.child(xmlDSigNS, "SignatureMethod")
+	if signatureMethod == nil {
+		return errors.New("SignatureMethod manquant")
+	}
+	signatureHash, ok := xmlSignatureHashes[signatureMethod.attr("Algorithm")]
+	if !ok {
+		return errors.New("algorithme de signature non pris en charge")
+	}
+
+	references := signedInfo.childrenNamed(xmlDSigNS, "Reference")
+	if len(references) != 1 {
+		return errors.New("une référence exactement est attendue")
+	}
+	reference := references[0]
+	if id := signed.attr("ID"); id == "" || reference.attr("URI") != "#"+id {
+		return errors.New("la signature ne référence pas l'élément signé")
+	}
+
+	var inclusive []string
+	if transforms := reference.child(xmlDSigNS, "Transforms"); transforms != nil {
+		for _, transform := range transforms.childrenNamed(xmlDSigNS, "Transform") {
+			switch transform.attr("Algorithm") {
+			case xmlEnveloped:
+			case xmlExcC14N:
+				inclusive = inclusivePrefixes(transform)
+			default:
+				return errors.New("transformation non prise en charge")
+			}
+		}
+	}
+
+	digestMethod := reference.child(xmlDSigNS, "DigestMethod")
+	if digestMethod == nil {
+		return errors.New("DigestMethod manquant")
+	}
+	digestHash, ok := xmlDigestHashes[digestMethod.attr("Algorithm")]
+	if !ok {
+		return errors.New("algorithme d'empreinte non pris en charge")
+	}
+	expectedDigest, err := decodeXMLBase64(reference.child(xmlDSigNS, "DigestValue").textContent())
+	if err != nil {
+		return errors.New("DigestValue invalide")
+	}
+
+	canonical, err := canonicalize(signed, inclusive, signature)
+	if err != nil {
+		return err
+	}
+	digest := digestHash.New()
+	digest.Write(canonical)
+	if subtle.ConstantTimeCompare(digest.Sum(nil), expectedDigest) != 1 {
+		return errors.New("empreinte du contenu signé incorrecte")
+	}
+
+	signatureValue, err := decodeXMLBase64(signature.child(xmlDSigNS, "SignatureValue").textContent())
+	if err != nil {
+		return errors.New("SignatureValue invalide")
+	}
+	canonicalSignedInfo, err := canonicalize(signedInfo, inclusivePrefixes(c14nMethod), nil)
+	if err != nil {
+		return err
+	}
+	hashed := signatureHash.New()
+	hashed.Write(canonicalSignedInfo)
+	if err := rsa.VerifyPKCS1v15(publicKey, signatureHash, hashed.Sum(nil), signatureValue); err != nil {
+		return errors.New("signature incorrecte")
+	}
+	return nil
+}
+
+// inclusivePrefixes PrefixList de l'élément InclusiveNamespaces d'une transformation exc-c14n
+func inclusivePrefixes(transform *xmlNode) []string {
+	for _, child := range transform.children {
+		if child.element && child.space == xmlExcC14N && child.local == "InclusiveNamespaces" {
+			return strings.Fields(child.attr("PrefixList"))
+		}
+	}
+	return nil
+}
+
+// decodeXMLBase64 les valeurs base64 des signatures sont souvent coupées par des retours à la ligne
+func decodeXMLBase64(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalizeExclusive(t *testing.T) {
+	// Déclarations inutilisées de l'ancêtre omises, attributs triés (sans espace de noms d'abord),
+	// guillemets et échappements normalisés, éléments vides développés, élément exclu retiré
+	document := `<root xmlns="urn:default" xmlns:a="urn:a" xmlns:unused="urn:unused">` +
+		`<a:item z='2' a:y="1" b="&lt;&quot;&gt;"><empty/><a:skip>x</a:skip>1 &lt; 2 &gt; 0 &amp;</a:item></root>`
+	want := `<a:item xmlns:a="urn:a" b="&lt;&quot;>" z="2" a:y="1"><empty xmlns="urn:default"></empty>1 &lt; 2 &gt; 0 &amp;</a:item>`
+
+	root, err := parseXMLTree([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := root.child("urn:a", "item")
+	canonical, err := canonicalize(item, nil, item.child("urn:a", "skip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(canonical) != want {
+		t.Fatalf("canonicalisation\n got %s\nwant %s", canonical, want)
+	}
+}
+
+func TestVerifyEnvelopedSignature(t *testing.T) {
+	sp := NewSAMLServiceProvider("https://analytics.example.com")
+	idp := newSAMLTestIdP(t)
+	certificate, err := parseIdPCertificate(idp.certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := parseIdPCertificate(newSAMLTestIdP(t).certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := idp.sign(t, newSAMLTestAssertion(sp))
+	signature := samlTestSignature(signed)
+
+	tests := []struct {
+		name        string
+		document    string
+		certificate *x509.Certificate
+		wantErr     string
+	}{
+		{"signature valide", signed, certificate, ""},
+		{"contenu modifié", strings.Replace(signed, samlTestIssuer, "https://evil.example.com", 1), certificate, "empreinte du contenu signé incorrecte"},
+		{"SignedInfo modifié", strings.Replace(signed, `URI="#_a1"`, `URI="#_a1" Id="x"`, 1), certificate, "signature incorrecte"},
+		{"autre certificat", signed, other, "signature incorrecte"},
+		{"deux signatures", strings.Replace(signed, signature, signature+signature, 1), certificate, "une signature exactement"},
+		{"référence vers un autre élément", strings.Replace(signed, `ID="_a1"`, `ID="_a2"`, 1), certificate, "ne référence pas l'élément signé"},
+		{"RSA-SHA1 refusé", strings.Replace(signed, samlTestRSASHA256, "http://www.w3.org/2000/09/xmldsig#rsa-sha1", 1), certificate, "algorithme de signature non pris en charge"},
+		{"transformation XSLT refusée", strings.Replace(signed, xmlEnveloped, "http://www.w3.org/TR/1999/REC-xslt-19991116", 1), certificate, "transformation non prise en charge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parseXMLTree([]byte(tt.document))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = verifyEnvelopedSignature(root, tt.certificate)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("signature refusée : %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erreur %v, attendu %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// La canonicalisation de l'assertion signée (signature retirée) redonne exactement la forme
+// écrite à la main par les tests : c'est sur elle que porte l'empreinte
+func TestCanonicalizeSignedAssertion(t *testing.T) {
+	sp := NewSAMLServiceProvider("https://analytics.example.com")
+	assertion := newSAMLTestAssertion(sp)
+	root, err := parseXMLTree([]byte(newSAMLTestIdP(t).sign(t, assertion)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonical, err := canonicalize(root, nil, root.child(xmlDSigNS, "Signature"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(canonical) != assertion.xml("") {
+		t.Fatalf("canonicalisation\n got %s\nwant %s", canonical, assertion.xml(""))
+	}
+}
@@ -0,0 +1,264 @@
+// Package architecture vérifie les règles de dépendance entre couches : `go test ./...` échoue
+// dès qu'un import les enfreint
+package architecture
+
+import (
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const module = "clean-archi-analytics"
+
+// =============================================================================
+// RÈGLES
+// =============================================================================
+
+// rule contraint les imports des paquets sous scope (le paquet lui-même et ses sous-paquets)
+//   - allow non vide : seuls ces paquets du module sont autorisés (en plus du scope lui-même)
+//   - deny : paquets interdits, du module ou non (database/sql, drivers...)
+type rule struct {
+	scope  string
+	reason string
+	allow  []string
+	deny   []string
+}
+
+var rules = []rule{
+	{
+		scope:  "internal/domain/events",
+		reason: "les événements du domaine sont la couche la plus interne",
+		deny:   []string{"internal", "pkg", "cmd"},
+	},
+	{
+		scope:  "internal/domain/entities",
+		reason: "les entités ne dépendent que des événements qu'elles rejouent",
+		allow:  []string{"internal/domain/events"},
+	},
+	{
+		scope:  "internal/domain/repositories",
+		reason: "les contrats de stockage ne décrivent que des entités",
+		allow:  []string{"internal/domain/entities", "internal/domain/events"},
+	},
+	{
+		scope:  "internal/domain/usecases",
+		reason: "les use cases ne connaissent l'infrastructure qu'à travers leurs ports",
+		allow:  []string{"internal/domain"},
+		deny:   []string{"database/sql", "net/http"},
+	},
+	{
+		scope:  "internal/app/handlers",
+		reason: "la couche HTTP passe par les use cases, jamais par la persistance",
+		deny:   []string{"internal/infra", "database/sql"},
+	},
+	{
+		scope:  "internal/app/services",
+		reason: "les adaptateurs de services ne contournent pas les dépôts",
+		deny:   []string{"internal/infra", "internal/app/handlers"},
+	},
+	{
+		scope:  "internal/infra",
+		reason: "la persistance ne dépend ni des handlers ni des services applicatifs",
+		deny:   []string{"internal/app"},
+	},
+	{
+		scope:  "internal/chaos",
+		reason: "l'injection de pannes ne décore que des ports du domaine",
+		allow:  []string{"internal/domain"},
+	},
+	{
+		scope:  "internal/awssig",
+		reason: "la signature AWS, partagée par l'infrastructure et les services, ne dépend d'aucune couche",
+		deny:   []string{"internal", "pkg", "cmd"},
+	},
+	{
+		scope:  "internal/httpclient",
+		reason: "les clients sortants, partagés par l'infrastructure et les services, ne connaissent que les ports du domaine",
+		allow:  []string{"internal/domain"},
+	},
+	{
+		scope:  "pkg/client",
+		reason: "le SDK public ne peut pas exposer les paquets internes",
+		deny:   []string{"internal"},
+	},
+	{
+		scope:  "pkg/server",
+		reason: "le serveur embarqué réutilise l'assemblage de cmd/api, sans importer les adaptateurs",
+		allow:  []string{"internal/bootstrap", "internal/config", "internal/domain"},
+	},
+}
+
+// =============================================================================
+// TESTS
+// =============================================================================
+
+func TestDependencyRules(t *testing.T) {
+	imports, err := moduleImports(moduleRoot(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, violation := range checkRules(rules, imports) {
+		t.Error(violation)
+	}
+}
+
+// TestRulesMatchPackages garde-fou : une règle dont le scope ne correspond plus à aucun
+// paquet (dossier renommé) ne vérifierait plus rien
+func TestRulesMatchPackages(t *testing.T) {
+	imports, err := moduleImports(moduleRoot(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rules {
+		matched := false
+		for pkg := range imports {
+			matched = matched || within(pkg, module+"/"+r.scope)
+		}
+		if !matched {
+			t.Errorf("rule %q matches no package", r.scope)
+		}
+	}
+}
+
+func TestCheckRulesReportsViolations(t *testing.T) {
+	imports := map[string][]importRef{
+		module + "/internal/domain/usecases": {
+			{path: module + "/internal/domain/entities", pos: "usecases/a.go:5"},
+			{path: module + "/internal/infra/database", pos: "usecases/a.go:6"},
+			{path: "net/http", pos: "usecases/b.go:4"},
+		},
+		module + "/internal/app/handlers": {
+			{path: module + "/internal/domain/usecases", pos: "handlers/a.go:3"},
+		},
+	}
+	got := checkRules(rules, imports)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 violations, got %d: %v", len(got), got)
+	}
+	if !strings.Contains(got[0], "usecases/a.go:6") || !strings.Contains(got[1], "usecases/b.go:4") {
+		t.Fatalf("unexpected violations %v", got)
+	}
+}
+
+// =============================================================================
+// ANALYSE DES IMPORTS
+// =============================================================================
+
+type importRef struct {
+	path string
+	pos  string
+}
+
+// checkRules violations triées par fichier, une par import fautif
+func checkRules(rules []rule, imports map[string][]importRef) []string {
+	var violations []string
+	for pkg, refs := range imports {
+		for _, r := range rules {
+			scope := module + "/" + r.scope
+			if !within(pkg, scope) {
+				continue
+			}
+			for _, ref := range refs {
+				if reason, ok := permitted(r, scope, ref.path); !ok {
+					violations = append(violations, ref.pos+": "+pkg+" imports "+ref.path+" ("+reason+")")
+				}
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// permitted false (et la raison) si l'import enfreint la règle
+func permitted(r rule, scope, imported string) (string, bool) {
+	for _, denied := range r.deny {
+		if within(imported, denied) || within(imported, module+"/"+denied) {
+			return r.reason, false
+		}
+	}
+	if len(r.allow) == 0 || !within(imported, module) || within(imported, scope) {
+		return "", true
+	}
+	for _, allowed := range r.allow {
+		if within(imported, module+"/"+allowed) {
+			return "", true
+		}
+	}
+	return r.reason, false
+}
+
+// within pkg est base ou l'un de ses sous-paquets
+func within(pkg, base string) bool {
+	return pkg == base || strings.HasPrefix(pkg, base+"/")
+}
+
+// moduleImports imports des fichiers non-test de chaque paquet du module : les tests
+// externes (package x_test) peuvent assembler des adaptateurs concrets
+func moduleImports(root string) (map[string][]importRef, error) {
+	imports := make(map[string][]importRef)
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name := entry.Name(); file != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			return nil
+		}
+
+		parsed, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			return err
+		}
+		pkg := path.Join(module, filepath.ToSlash(rel))
+		for _, spec := range parsed.Imports {
+			imported, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return err
+			}
+			position := fset.Position(spec.Pos())
+			relFile, _ := filepath.Rel(root, position.Filename)
+			imports[pkg] = append(imports[pkg], importRef{
+				path: imported,
+				pos:  filepath.ToSlash(relFile) + ":" + strconv.Itoa(position.Line),
+			})
+		}
+		return nil
+	})
+	return imports, err
+}
+
+// moduleRoot dossier du go.mod, en remontant depuis le dossier du test
+func moduleRoot(t *testing.T) string {
+	t.Helper()
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			t.Fatal("go.mod not found")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,124 @@
+// Package awssig signe les appels aux API AWS (Signature Version 4) sans dépendance au SDK :
+// partagé par les adaptateurs de persistance (DynamoDB) et de services (SSM, Secrets Manager)
+package awssig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Credentials identifiants statiques (variables AWS_* ; fournies par le runtime sur Lambda)
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken identifiants temporaires (rôle IAM, Lambda) ; vide pour une clé d'accès longue durée
+	SessionToken string
+}
+
+const signingAlgorithm = "AWS4-HMAC-SHA256"
+
+// Sign signe req avec Signature Version 4 (en-têtes host, x-amz-* et content-type)
+// body doit être le corps exact envoyé : son empreinte fait partie de la signature
+// (S3 attend en plus X-Amz-Content-Sha256, à renseigner par l'appelant avant la signature)
+func Sign(req *http.Request, body []byte, service, region string, credentials Credentials, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := signingAlgorithm + "\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", signingAlgorithm+" Credential="+credentials.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// canonicalQuery paramètres triés par nom puis par valeur, une fois encodés selon RFC 3986
+func canonicalQuery(query url.Values) string {
+	type param struct{ name, value string }
+	params := make([]param, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, param{escape(name), escape(value)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].name != params[j].name {
+			return params[i].name < params[j].name
+		}
+		return params[i].value < params[j].value
+	})
+
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.name + "=" + p.value
+	}
+	return strings.Join(parts, "&")
+}
+
+// escaper seuls A-Z, a-z, 0-9, "-", "_", "." et "~" restent en clair (espace en %20)
+var escaper = strings.NewReplacer("+", "%20", "%7E", "~")
+
+func escape(value string) string {
+	return escaper.Replace(url.QueryEscape(value))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
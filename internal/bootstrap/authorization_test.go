@@ -0,0 +1,237 @@
+package bootstrap
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/config"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// publicUseCases seuls use cases ouverts aux appels anonymes
+var publicUseCases = []string{
+	"check_handle_availability", "confirm_onboarding", "consume_sso_response", "create_user",
+	"get_onboarding", "get_saml_metadata", "handle_webhook_event", "login",
+	"start_onboarding", "start_sso_login", "track_event", "verify_login",
+}
+
+// newTestApp application assemblée avec la configuration par défaut (dépôts en mémoire) ;
+// la facturation est activée pour enregistrer ses use cases, sur une adresse injoignable
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv("STRIPE_SECRET_KEY", "sk_test")
+	t.Setenv("STRIPE_API_URL", "http://127.0.0.1:1")
+	t.Setenv("JWT_SECRET", "bootstrap-test-secret-0123456789abcdef")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := Build(context.Background(), cfg, Ports{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = app.Shutdown(context.Background()) })
+	return app
+}
+
+func TestAnonymousAuthorization(t *testing.T) {
+	app := newTestApp(t)
+	registry := app.pipeline.Registry
+
+	if got := registry.Names(usecases.AccessPublic); !slices.Equal(got, publicUseCases) {
+		t.Fatalf("use cases publics %v, attendu %v", got, publicUseCases)
+	}
+	names := registry.Names()
+	for _, name := range []string{"bulk_delete_users", "sync_users", "subscribe_tenant", "list_users"} {
+		if !slices.Contains(names, name) {
+			t.Fatalf("%s non enregistré", name)
+		}
+	}
+
+	// Actions vérifiées hors pipeline : inconnues du registre, donc réservées à l'administration
+	for _, name := range append(names, "debug_diagnostics") {
+		err := app.pipeline.Authorizer.Authorize(context.Background(), name, nil)
+		switch {
+		case slices.Contains(publicUseCases, name) && err != nil:
+			t.Errorf("%s refusé à un appel anonyme : %v", name, err)
+		case !slices.Contains(publicUseCases, name) && !errors.Is(err, usecases.ErrAuthenticationRequired):
+			t.Errorf("%s : erreur %v, attendu ErrAuthenticationRequired", name, err)
+		}
+	}
+}
+
+func TestAuthorizationLevels(t *testing.T) {
+	app := newTestApp(t)
+	user := usecases.Actor{UserID: 42, Roles: []string{"member"}}
+	admin := usecases.Actor{UserID: 1, Roles: []string{app.Config.AdminRole}}
+	support := usecases.Actor{UserID: 2, Roles: []string{app.Config.SupportRole}}
+	impersonated := usecases.Actor{UserID: 42, Roles: []string{app.Config.AdminRole}, Impersonation: &usecases.Impersonation{AdminID: 1}}
+	tenant := usecases.Actor{TenantID: "acme"}
+
+	tests := []struct {
+		name    string
+		actor   usecases.Actor
+		useCase string
+		input   interface{}
+		wantErr error
+	}{
+		{"utilisateur sur lui-même", user, "get_user", 42, nil},
+		{"utilisateur sur un autre", user, "get_user", 7, usecases.ErrForbidden},
+		{"utilisateur sur ses propres ressources", user, "start_export", nil, nil},
+		{"utilisateur sur une action d'administration", user, "list_users", nil, usecases.ErrForbidden},
+		{"utilisateur sur une tâche interne", user, "send_weekly_digests", nil, usecases.ErrForbidden},
+		{"utilisateur sur la chronologie", user, "get_user_timeline", nil, usecases.ErrForbidden},
+		{"administration sur un autre utilisateur", admin, "get_user", 7, nil},
+		{"administration", admin, "bulk_delete_users", nil, nil},
+		{"administration sur une tâche interne", admin, "send_weekly_digests", nil, usecases.ErrForbidden},
+		{"administration impersonnée", impersonated, "list_users", nil, usecases.ErrImpersonationScope},
+		{"support sur la chronologie", support, "get_user_timeline", nil, nil},
+		{"support sur une action d'administration", support, "list_users", nil, usecases.ErrForbidden},
+		{"clé d'écriture sur l'ingestion", tenant, "track_event", nil, nil},
+		{"clé d'écriture hors ingestion", tenant, "list_users", nil, usecases.ErrAuthenticationRequired},
+		{"tâche interne", usecases.SystemActor, "send_weekly_digests", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := usecases.ContextWithActor(context.Background(), tt.actor)
+			if err := app.pipeline.Authorizer.Authorize(ctx, tt.useCase, tt.input); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erreur %v, attendu %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// bearer en-tête Authorization d'un jeton signé avec les clés de l'application
+func bearer(t *testing.T, app *App, actor usecases.Actor) string {
+	t.Helper()
+	keys, err := services.ParseJWTKeySet(app.Config.JWTSigningKeys, app.Config.JWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := services.NewHS256TokenService(keys, app.clock).Issue(actor, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+// Routes privilégiées : 401 sans jeton, 403 pour un utilisateur sans le rôle requis ; le rôle
+// d'administration passe l'autorisation (la réponse dépend ensuite de l'entrée)
+func TestPrivilegedRoutes(t *testing.T) {
+	app := newTestApp(t)
+	member := bearer(t, app, usecases.Actor{UserID: 42, Roles: []string{"member"}})
+	admin := bearer(t, app, usecases.Actor{UserID: 1, Roles: []string{app.Config.AdminRole}})
+
+	routes := []struct {
+		method, path, body string
+	}{
+		{"POST", "/users/bulk", `{"users":[{"email":"a@example.com","name":"Alice","password":"s3cret-Passw0rd"}]}`},
+		{"PUT", "/users/bulk", `{"users":[{"id":42,"name":"Alice"}]}`},
+		{"POST", "/users/bulk/delete", `{"ids":[42]}`},
+		{"POST", "/users/import", `{"upload_id":"u1"}`},
+		{"POST", "/tenants/acme/subscription", `{"plan":"pro","email":"billing@acme.example.com"}`},
+		{"GET", "/tenants/acme/subscription", ""},
+		{"POST", "/sync/users", ""},
+		{"POST", "/sync/users?dry_run=true", ""},
+	}
+	for _, route := range routes {
+		for _, tt := range []struct {
+			caller        string
+			authorization string
+			wantStatus    int
+		}{
+			{"anonyme", "", http.StatusUnauthorized},
+			{"utilisateur", member, http.StatusForbidden},
+			{"administration", admin, 0},
+		} {
+			t.Run(route.method+" "+route.path+" "+tt.caller, func(t *testing.T) {
+				r := httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+				r.Header.Set("Content-Type", "application/json")
+				if tt.authorization != "" {
+					r.Header.Set("Authorization", tt.authorization)
+				}
+				w := httptest.NewRecorder()
+				app.Handler.ServeHTTP(w, r)
+				switch {
+				case tt.wantStatus != 0 && w.Code != tt.wantStatus:
+					t.Fatalf("statut %d, attendu %d : %s", w.Code, tt.wantStatus, w.Body)
+				case tt.wantStatus == 0 && (w.Code == http.StatusUnauthorized || w.Code == http.StatusForbidden):
+					t.Fatalf("statut %d pour l'administration : %s", w.Code, w.Body)
+				}
+			})
+		}
+	}
+}
+
+// Le flux SSE des notifications n'est pas un use case : il passe par l'Authorizer du pipeline
+func TestNotificationStreamOwnership(t *testing.T) {
+	app := newTestApp(t)
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{"anonyme", "", http.StatusUnauthorized},
+		{"autre utilisateur", bearer(t, app, usecases.Actor{UserID: 7}), http.StatusForbidden},
+		{"utilisateur lui-même", bearer(t, app, usecases.Actor{UserID: 42}), http.StatusOK},
+		{"administration", bearer(t, app, usecases.Actor{UserID: 1, Roles: []string{app.Config.AdminRole}}), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			r := httptest.NewRequest("GET", "/users/42/notifications/stream", nil).WithContext(ctx)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			app.Handler.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("statut %d, attendu %d : %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if stream := strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream"); stream != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("Content-Type %q", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+// Une session d'impersonation ne lit que ce que porte son jeton (claim scope), dans la limite
+// de usecases.ImpersonationScope : les écritures sont refusées, même sur le compte impersonné
+func TestImpersonationScope(t *testing.T) {
+	app := newTestApp(t)
+	session := bearer(t, app, usecases.Actor{UserID: 42, Impersonation: &usecases.Impersonation{AdminID: 1, Scope: usecases.ImpersonationScope}})
+	unscoped := bearer(t, app, usecases.Actor{UserID: 42, Impersonation: &usecases.Impersonation{AdminID: 1}})
+	widened := bearer(t, app, usecases.Actor{UserID: 42, Impersonation: &usecases.Impersonation{AdminID: 1, Scope: []string{"get_user", "update_user"}}})
+
+	tests := []struct {
+		name, authorization, method, path, body string
+		wantScopeError                          bool
+	}{
+		{"lecture dans la portée", session, "GET", "/users/42/notifications", "", false},
+		{"modification du compte", session, "PUT", "/users/42", `{"name":"Mallory"}`, true},
+		{"modification partielle", session, "PATCH", "/users/42", `{"name":"Mallory"}`, true},
+		{"souscription", session, "POST", "/tenants/acme/subscription", `{"plan":"pro","email":"billing@acme.example.com"}`, true},
+		{"jeton sans portée", unscoped, "GET", "/users/42/notifications", "", true},
+		{"portée élargie hors liste", widened, "PUT", "/users/42", `{"name":"Mallory"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+			app.Handler.ServeHTTP(w, r)
+			scopeError := w.Code == http.StatusForbidden && strings.Contains(w.Body.String(), `"impersonation_scope"`)
+			if scopeError != tt.wantScopeError {
+				t.Fatalf("statut %d : %s", w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,1427 @@
+// Package bootstrap assemble l'application (composition root) : dépôts, services, use cases,
+// routeur et tâches planifiées. Utilisé par cmd/api et par le mode embarqué de pkg/server
+package bootstrap
+
+import (
+	"clean-archi-analytics/internal/app/handlers"
+	"clean-archi-analytics/internal/app/handlers/ws"
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/chaos"
+	"clean-archi-analytics/internal/config"
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"clean-archi-analytics/internal/infra/database"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+)
+
+// Ports implémentations fournies par l'application hôte ; nil = implémentation déduite de la configuration
+type Ports struct {
+	Logger          usecases.Logger
+	ErrorReporter   usecases.ErrorReporter
+	UserRepository  repositories.UserRepository
+	EventRepository repositories.EventRepository
+	EmailSender     usecases.EmailSender
+	Clock           usecases.Clock
+	// Hooks règles métier de l'hôte autour des use cases (usecases.BeforeUserCreate...)
+	Hooks *usecases.Hooks
+}
+
+// App application assemblée ; Handler sert l'API, StartJobs lance les traitements périodiques
+// et Shutdown libère les ressources une fois le trafic arrêté
+type App struct {
+	Config   *config.Config
+	Logger   usecases.Logger
+	Reporter usecases.ErrorReporter
+	// Router routeur authentifié, sans les middlewares transverses (CORS, CSRF, compression...)
+	Router http.Handler
+	// Handler Router enveloppé des middlewares transverses : ce que sert le serveur HTTP
+	Handler http.Handler
+	// EventBuffer écritures groupées de l'ingestion (nil si EVENT_FLUSH_INTERVAL=0)
+	EventBuffer *database.BufferedEventRepository
+	// SearchIndexer recopie vers Elasticsearch (nil sans ELASTICSEARCH_URL)
+	SearchIndexer *usecases.SearchIndexer
+
+	// Use cases des sous-commandes (seed)
+	BulkCreateUsers               usecases.UseCase[usecases.BulkCreateUsersRequest, *usecases.BulkCreateUsersResponse]
+	UpdateUser                    usecases.UseCase[usecases.UpdateUserRequest, *usecases.UpdateUserResponse]
+	UpdateDigestPreference        usecases.UseCase[usecases.UpdateDigestPreferenceRequest, *usecases.UpdateDigestPreferenceResponse]
+	UpdateNotificationPreferences usecases.UseCase[usecases.UpdateNotificationPreferencesRequest, *usecases.NotificationPreferencesResponse]
+	// Use cases de `api dlq` (files des éléments abandonnés)
+	ListDeadLetters    usecases.UseCase[usecases.ListDeadLettersRequest, *usecases.ListDeadLettersResponse]
+	GetDeadLetter      usecases.UseCase[usecases.GetDeadLetterRequest, *usecases.DeadLetter]
+	ReplayDeadLetters  usecases.UseCase[usecases.DeadLetterBatchRequest, *usecases.DeadLetterBatchResponse]
+	DiscardDeadLetters usecases.UseCase[usecases.DeadLetterBatchRequest, *usecases.DeadLetterBatchResponse]
+	// RestoreEvents use case de `api events-restore` (archives d'événements analytics)
+	RestoreEvents usecases.UseCase[usecases.RestoreEventsRequest, *usecases.RestoreEventsResponse]
+
+	ctx       context.Context
+	stop      context.CancelFunc
+	tasks     *services.BoundedTaskRunner
+	scheduler *services.Scheduler
+	settings  *settingsReloader
+	jobs      []job
+	closers   []func()
+	// debugHandler diagnostics servis par ServeDiagnostics (nil sans DEBUG_ADDR)
+	debugHandler http.Handler
+
+	pipeline       usecases.Pipeline
+	clock          usecases.Clock
+	rollupRepo     repositories.EventRollupRepository
+	eventHistory   repositories.EventHistory
+	userRepo       repositories.UserFacetedSearchRepository
+	activityRepo   repositories.ActivityRepository
+	userEventStore repositories.UserEventStore
+	userReadRepo   repositories.UserReadRepository
+	resultCache    usecases.ResultCache
+	passwordHasher usecases.PasswordHasher
+	tokenGenerator usecases.TokenGenerator
+}
+
+// job traitement périodique lancé par StartJobs
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context)
+}
+
+// Build assemble l'application ; ctx borne la durée de vie des tâches de fond, qui s'exécutent
+// au nom du système (Shutdown les arrête aussi). En cas d'erreur, les ressources déjà ouvertes sont libérées
+func Build(ctx context.Context, cfg *config.Config, ports Ports) (_ *App, err error) {
+	ctx, stop := context.WithCancel(usecases.ContextWithActor(ctx, usecases.SystemActor))
+	app := &App{Config: cfg, ctx: ctx, stop: stop}
+	defer func() {
+		if err != nil {
+			app.close()
+		}
+	}()
+
+	logger := ports.Logger
+	if logger == nil {
+		logger = services.NewSlogLogger()
+	}
+	// Niveau de log pilotable (LOG_LEVEL, PUT /admin/log-level) sauf logger de l'hôte qui ne le permet pas
+	logs, _ := logger.(usecases.LogLevelController)
+	if logs != nil {
+		if err := logs.SetLogLevel(cfg.LogLevel); err != nil {
+			return nil, fmt.Errorf("LOG_LEVEL: %w", err)
+		}
+	}
+	tracer := services.NewLogTracer(logger)
+	// Clients HTTP sortants : délais, nouveaux essais, spans et métriques communs à tous les adaptateurs
+	clients, err := newHTTPClients(cfg, tracer)
+	if err != nil {
+		return nil, fmt.Errorf("http clients: %w", err)
+	}
+	reporter := ports.ErrorReporter
+	if reporter == nil {
+		if reporter, err = newErrorReporter(cfg, logger, clients); err != nil {
+			return nil, fmt.Errorf("error reporter: %w", err)
+		}
+	}
+	app.Logger, app.Reporter = logger, reporter
+
+	flags, localFlags, err := newFeatureFlags(ctx, cfg, logger, clients)
+	if err != nil {
+		return nil, fmt.Errorf("feature flags: %w", err)
+	}
+
+	// Secrets (clés JWT, DSN) : environnement, fichiers montés, Vault, SSM ou Secrets Manager
+	secrets, err := LoadSecrets(ctx, cfg, logger, clients)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+
+	// Horloge partagée par les dépôts en mémoire, les services et les use cases
+	var clock usecases.Clock = services.NewSystemClock()
+	if ports.Clock != nil {
+		clock = ports.Clock
+	}
+
+	// Infrastructure
+	var userEventStore repositories.UserEventStore
+	if cfg.PersistenceMode == config.PersistenceEventSourced {
+		userEventStore = database.NewInMemoryUserEventStore(clock)
+	}
+	// Un dépôt fourni par l'application hôte remplace le mode de persistance (ni pool SQL, ni index embarqué)
+	baseUserRepo, sqlDB, closeUserRepo := ports.UserRepository, (*sql.DB)(nil), func() {}
+	if baseUserRepo == nil {
+		if baseUserRepo, sqlDB, closeUserRepo, err = newUserRepository(ctx, cfg, userEventStore, clock, secrets, clients); err != nil {
+			return nil, fmt.Errorf("user repository: %w", err)
+		}
+	}
+	app.closers = append(app.closers, closeUserRepo)
+	var searchClient *database.ElasticsearchClient
+	if cfg.ElasticsearchURL != "" {
+		searchClient = database.NewElasticsearchClient(database.ElasticsearchConfig{
+			URL:         cfg.ElasticsearchURL,
+			Username:    cfg.ElasticsearchUsername,
+			Password:    cfg.ElasticsearchPassword,
+			APIKey:      cfg.ElasticsearchAPIKey,
+			IndexPrefix: cfg.ElasticsearchIndex,
+			Clients:     clients,
+		})
+		setupCtx, cancelSetup := context.WithTimeout(ctx, 10*time.Second)
+		err := searchClient.EnsureIndexes(setupCtx)
+		cancelSetup()
+		if err != nil {
+			return nil, fmt.Errorf("elasticsearch: %w", err)
+		}
+		baseUserRepo = database.NewElasticsearchUserRepository(baseUserRepo, searchClient)
+	}
+	// Injection de pannes (CHAOS_*, refusée en production) devant les dépôts, l'email et le bus
+	var injector *chaos.Injector
+	if cfg.ChaosEnabled() {
+		injector = chaos.NewInjector(chaos.Config{
+			Latency:     cfg.ChaosLatency,
+			ErrorRate:   cfg.ChaosErrorRate,
+			TimeoutRate: cfg.ChaosTimeoutRate,
+			Targets:     cfg.ChaosTargets,
+		}, logger)
+		logger.Warn("Fault injection enabled", map[string]interface{}{
+			"latency": cfg.ChaosLatency.String(), "error_rate": cfg.ChaosErrorRate, "timeout_rate": cfg.ChaosTimeoutRate, "targets": cfg.ChaosTargets,
+		})
+	}
+	var userRepo repositories.UserFacetedSearchRepository = database.NewLoggingUserRepository(baseUserRepo, logger, services.NewExpvarQueryMetrics(), cfg.SlowQueryThreshold)
+	if injector != nil {
+		userRepo = chaos.NewUserRepository(userRepo, injector)
+	}
+	userReadRepo := database.NewInMemoryUserReadRepository()
+	webhookEventRepo := database.NewInMemoryWebhookEventRepository(cfg.WebhookRetention, clock)
+	failedWebhookRepo := database.NewInMemoryFailedWebhookRepository()
+	activityRepo := database.NewInMemoryActivityRepository()
+	timelineRepo := database.NewInMemoryTimelineRepository()
+	consentRepo := database.NewInMemoryConsentRepository()
+	auditRepo := database.NewInMemoryAuditRepository()
+	// Agrégats quotidiens : table event_rollups en mode "sql", mémoire sinon
+	var rollupRepo repositories.EventRollupRepository = database.NewInMemoryEventRollupRepository()
+	// Événements analytics des clients : table tracked_events en mode "sql", mémoire sinon
+	var eventRepo repositories.EventRepository = database.NewInMemoryEventRepository()
+	if sqlDB != nil {
+		rollupRepo = database.NewSQLEventRollupRepository(sqlDB)
+		eventRepo = database.NewSQLEventRepository(sqlDB)
+	}
+	if ports.EventRepository != nil {
+		eventRepo = ports.EventRepository
+	}
+	// Stockage lui-même, sans décorateurs : la rétention y supprime les événements expirés
+	eventStore := eventRepo
+	if injector != nil {
+		eventRepo = chaos.NewEventRepository(eventRepo, injector)
+	}
+	// Écritures groupées de l'ingestion ; EVENT_FLUSH_INTERVAL=0 écrit chaque événement immédiatement
+	if cfg.EventFlushInterval > 0 {
+		app.EventBuffer = database.NewBufferedEventRepository(eventRepo, logger, cfg.EventBufferSize, cfg.EventBatchSize, cfg.EventFlushInterval)
+		eventRepo = app.EventBuffer
+	}
+	// Chronologie utilisateur : événements analytics notables (TIMELINE_EVENTS) à l'ingestion
+	timelineRecorder := usecases.NewTimelineRecorder(timelineRepo, cfg.TimelineEvents, logger)
+	eventRepo = timelineRecorder.TrackedEvents(eventRepo)
+	// Nettoyage des données personnelles avant tout le reste (EVENT_SCRUB_*) ; sans EVENT_SCRUB_KEY,
+	// les propriétés identifiantes sont retirées faute de pouvoir les empreinter
+	var scrubHasher usecases.IdentifierHasher
+	if cfg.EventScrubKey != "" {
+		pseudonymizer, err := services.NewHMACPseudonymizer(cfg.EventScrubKey)
+		if err != nil {
+			return nil, fmt.Errorf("EVENT_SCRUB_KEY: %w", err)
+		}
+		scrubHasher = pseudonymizer
+	}
+	eventScrubber := usecases.NewEventScrubber(usecases.EventScrubPolicy{
+		IPv4Prefix:       cfg.EventScrubIPv4Prefix,
+		IPv6Prefix:       cfg.EventScrubIPv6Prefix,
+		HashedProperties: cfg.EventScrubHashProperties,
+		DeniedProperties: cfg.EventScrubDenyProperties,
+	}, scrubHasher)
+	eventRepo = eventScrubber.TrackedEvents(eventRepo)
+	// Compteurs d'usage par tenant : partagés entre instances en mode "sql"
+	var usageRepo repositories.UsageRepository = database.NewInMemoryUsageRepository()
+	if sqlDB != nil {
+		usageRepo = database.NewSQLUsageRepository(sqlDB)
+	}
+	// Comptes de facturation : offre en vigueur de chaque tenant
+	var billingAccountRepo repositories.BillingAccountRepository = database.NewInMemoryBillingAccountRepository()
+	if sqlDB != nil {
+		billingAccountRepo = database.NewSQLBillingAccountRepository(sqlDB)
+	}
+	outboxRepo := database.NewInMemoryOutboxRepository()
+	notificationPrefRepo := database.NewInMemoryNotificationPreferenceRepository()
+	notificationRepo := database.NewInMemoryNotificationRepository()
+	termsRepo := database.NewInMemoryTermsAcceptanceRepository()
+	externalLinkRepo := database.NewInMemoryExternalUserLinkRepository()
+	identityProviderRepo := database.NewInMemoryIdentityProviderRepository()
+	emailTemplateRepo := database.NewInMemoryEmailTemplateRepository()
+	ssoRequestRepo := database.NewInMemorySSORequestRepository(clock)
+
+	// Services
+	tokenGenerator := services.NewRandomTokenGenerator(services.NewCryptoRandomSource())
+	attributeSchemas, err := services.LoadAttributeSchemas(cfg.AttributeSchemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("attribute schemas: %w", err)
+	}
+	passwordHasher := services.NewPBKDF2Hasher(600_000)
+	var hrProvider usecases.ExternalUserProvider
+	if cfg.HRSyncURL != "" {
+		hrProvider = services.NewHRUserProvider(cfg.HRSyncURL, cfg.HRSyncToken, logger, clients)
+	}
+	syncPolicy, err := usecases.ParseSyncConflictPolicy(cfg.HRSyncConflictPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("hr sync: %w", err)
+	}
+	billingPlans, err := services.LoadBillingPlans(cfg.BillingPlansFile)
+	if err != nil {
+		return nil, fmt.Errorf("billing plans: %w", err)
+	}
+	// Sans STRIPE_SECRET_KEY, aucune souscription : quotas et flags restent ceux de la configuration
+	var billing usecases.Billing
+	if cfg.StripeSecretKey != "" {
+		billing = services.NewStripeBilling(cfg.StripeSecretKey, cfg.StripeAPIURL, cfg.StripeMeters, clients)
+	}
+	var emailSender usecases.EmailSender
+	if ports.EmailSender != nil {
+		emailSender = ports.EmailSender
+	} else {
+		// Fournisseur vérifié au démarrage : une clé révoquée ou un domaine non vérifié
+		// ferait échouer chaque envoi, jusqu'à l'abandon des messages de l'outbox
+		mailProvider := NewMailProvider(cfg, logger, clients)
+		checkCtx, cancelCheck := context.WithTimeout(ctx, 10*time.Second)
+		err := mailProvider.HealthCheck(checkCtx)
+		cancelCheck()
+		if err != nil {
+			return nil, fmt.Errorf("email: %w", err)
+		}
+		emailSender = services.NewMailEmailSender(mailProvider, cfg.EmailFrom, cfg.EmailWelcomeTemplate)
+	}
+	// Adresses rejetées par le fournisseur (POST /webhooks/email) : envois supprimés
+	emailFeedbackMetrics := services.NewExpvarEmailFeedbackMetrics()
+	emailSender = usecases.NewSuppressingEmailSender(emailSender, userRepo, emailFeedbackMetrics, logger)
+	// Modèles d'emails modifiés par les administrateurs (PUT /admin/email-templates/{name}), par tenant et locale
+	emailTemplateEngine := services.NewTextTemplateEmailEngine()
+	emailTemplates := usecases.NewEmailTemplates(emailTemplateRepo, emailTemplateEngine)
+	emailSender = usecases.NewTemplatedEmailSender(emailSender, emailTemplates, logger)
+	// Dates et nombres selon la locale et le fuseau du destinataire (emails, exports, ?display=true)
+	displayFormats, err := usecases.NewDisplayFormats(userRepo, services.NewLocaleDisplayFormatter(), cfg.DisplayLocale, cfg.DisplayTimeZone, logger)
+	if err != nil {
+		return nil, fmt.Errorf("display: %w", err)
+	}
+	// Liens de téléchargement signés (/downloads/...) : désactivés sans DOWNLOAD_SIGNING_KEY
+	var urlSigner usecases.URLSigner
+	if cfg.DownloadSigningKey != "" {
+		if urlSigner, err = services.NewHMACURLSigner(cfg.DownloadSigningKey); err != nil {
+			return nil, fmt.Errorf("download links: %w", err)
+		}
+	}
+	downloadLinks := usecases.NewDownloadLinks(urlSigner, cfg.DownloadBaseURL, cfg.DownloadLinkTTL, clock)
+	if injector != nil {
+		emailSender = chaos.NewEmailSender(emailSender, injector)
+	}
+	tasks := services.NewBoundedTaskRunner(cfg.AsyncTaskLimit, logger, reporter)
+	app.tasks = tasks
+
+	// Bus d'événements : le projecteur maintient le modèle de lecture (CQRS)
+	eventBus := services.NewInMemoryEventBus(logger)
+	// Les use cases publient via publisher : le bus, précédé de l'injection de pannes si elle est active
+	var publisher usecases.EventPublisher = eventBus
+	if injector != nil {
+		publisher = chaos.NewEventPublisher(eventBus, injector)
+	}
+	eventBus.Subscribe(services.AllEvents, usecases.NewUserProjector(userReadRepo).Handle)
+	eventBus.Subscribe(services.AllEvents, usecases.NewActivityRecorder(activityRepo).Handle)
+	eventBus.Subscribe(services.AllEvents, timelineRecorder.Handle)
+	eventBus.Subscribe(services.AllEvents, usecases.NewRollupProjector(rollupRepo).Handle)
+	// Cache des lectures : invalidé après les projecteurs, une lecture suivante relit le modèle à jour
+	resultCache := services.NewInMemoryResultCache(cfg.CacheMaxEntries, clock)
+	eventBus.Subscribe(services.AllEvents, usecases.NewCacheInvalidator(resultCache).Handle)
+	// L'offre souscrite fixe quotas et fonctionnalités du tenant ; TENANT_QUOTAS reste prioritaire
+	tenantPlans := usecases.NewTenantPlans(billingAccountRepo, billingPlans)
+	flags = usecases.NewPlanFeatureFlags(flags, tenantPlans)
+	usageMeter := usecases.NewUsageMeter(usageRepo, usecases.QuotaPolicy{Default: cfg.Quotas, Tenants: tenantQuotas(cfg.TenantQuotas)}, tenantPlans, clock)
+	eventBus.Subscribe(services.AllEvents, usageMeter.Handle)
+	// Moteur de recherche : utilisateurs et événements recopiés en arrière-plan
+	var eventSearchRepo repositories.EventSearchRepository
+	var eventHistory repositories.EventHistory
+	if searchClient != nil {
+		searchIndex := database.NewElasticsearchSearchIndex(searchClient)
+		app.SearchIndexer = usecases.NewSearchIndexer(userRepo, searchIndex, tasks)
+		eventBus.Subscribe(services.AllEvents, app.SearchIndexer.Handle)
+		eventSearchRepo = searchIndex
+		eventHistory = searchIndex
+	}
+
+	// Connexion : mots de passe locaux, ou annuaire LDAP (comptes créés à la première connexion)
+	var credentials usecases.CredentialVerifier = usecases.NewPasswordCredentialVerifier(userRepo, passwordHasher)
+	if cfg.LDAPURL != "" {
+		directory, err := services.NewLDAPDirectory(services.LDAPDirectoryConfig{
+			URL:          cfg.LDAPURL,
+			BindDN:       cfg.LDAPBindDN,
+			BindPassword: cfg.LDAPBindPassword,
+			BaseDN:       cfg.LDAPBaseDN,
+			UserFilter:   cfg.LDAPUserFilter,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ldap: %w", err)
+		}
+		var fallback usecases.CredentialVerifier
+		if cfg.LDAPLocalFallback {
+			fallback = credentials
+		}
+		credentials = usecases.NewDirectoryCredentialVerifier(directory, userRepo, externalLinkRepo, passwordHasher,
+			publisher, logger, cfg.LDAPGroupRoles, fallback, clock, tokenGenerator)
+	}
+
+	// Notifications multi-canal, filtrées par les préférences de chaque utilisateur
+	// Le hub pousse les notifications in-app aux clients connectés en SSE
+	notificationHub := services.NewNotificationHub(logger)
+	notifiers := []usecases.Notifier{
+		services.NewEmailNotifier(emailSender),
+		services.NewSMSNotifier(newSMSClient(cfg, logger, clients), cfg.TwilioFrom),
+		services.NewInAppNotifier(notificationRepo, notificationHub, clock),
+	}
+	notificationRouter := usecases.NewNotificationRouter(userRepo, notificationPrefRepo, notifiers...)
+	eventBus.Subscribe(services.AllEvents, notificationRouter.Handle)
+
+	// Temps réel : événements utilisateur et compteur d'inscriptions poussés en WebSocket
+	jwtKeys, err := services.ParseJWTKeySet(cfg.JWTSigningKeys, cfg.JWTSecret)
+	if err != nil {
+		return nil, fmt.Errorf("jwt keys: %w", err)
+	}
+	tokenService := services.NewHS256TokenService(jwtKeys, clock)
+	secrets.OnChange(rotateJWTKeys(secrets, tokenService, logger))
+	realtimeHub := ws.NewHub(tokenService, logger)
+	eventBus.Subscribe(services.AllEvents, realtimeHub.Handle)
+	analyticsStream := services.NewAnalyticsStream(logger)
+	eventBus.Subscribe(services.AllEvents, analyticsStream.Handle)
+	analyticsStream.Start(ctx, cfg.AnalyticsStreamInterval)
+
+	// Niveaux d'accès déclarés par chaque Wrap : vérifiés avant les politiques, quelles qu'elles soient
+	registry := usecases.NewUseCaseRegistry()
+	authorizer, err := newAuthorizer(cfg, clients, registry)
+	if err != nil {
+		return nil, fmt.Errorf("authorization policies: %w", err)
+	}
+	authorizer = usecases.NewAccessGuard(authorizer, registry, map[usecases.Access]string{
+		usecases.AccessAdmin:         cfg.AdminRole,
+		usecases.AccessSupport:       cfg.SupportRole,
+		usecases.AccessImpersonation: cfg.ImpersonationRole,
+	})
+
+	// Maintenance / lecture seule : le mode de la configuration, durci par les feature flags
+	availability, err := usecases.NewAvailability(cfg.AvailabilityMode, flags, cfg.MaintenanceRetryAfter, usecases.ReadOnlyUseCases)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pipeline transverse appliqué à chaque use case
+	auditTrail := usecases.NewAuditTrail(auditRepo, clock, logger, usecases.AuditedUseCases)
+	// Nœuds de sortie TOR : règle de risque des connexions, CAPTCHA dès le premier appel
+	var torExitList *services.SourceIPList
+	if cfg.TorExitList != "" {
+		torExitList = services.NewSourceIPList("tor_exit_list", cfg.TorExitList, clients)
+		if err := torExitList.Refresh(ctx); err != nil {
+			logger.Error("Failed to load TOR exit list", err, map[string]interface{}{"source": cfg.TorExitList})
+		}
+	}
+	var captcha *usecases.CaptchaGuard
+	if cfg.CaptchaProvider != "" {
+		policy := usecases.CaptchaPolicy{
+			Actions:        cfg.CaptchaActions,
+			MinScore:       cfg.CaptchaMinScore,
+			Threshold:      cfg.CaptchaThreshold,
+			Window:         cfg.CaptchaWindow,
+			BypassRoles:    cfg.CaptchaBypassRoles,
+			BypassNetworks: cfg.CaptchaBypassNetworks,
+		}
+		if torExitList != nil {
+			policy.Risky = torExitList
+		}
+		captcha = usecases.NewCaptchaGuard(NewCaptchaVerifier(cfg, clients), policy, clock, logger)
+	}
+	moderation, err := newContentModeration(cfg, logger, clients)
+	if err != nil {
+		return nil, fmt.Errorf("moderation: %w", err)
+	}
+	pipeline := usecases.Pipeline{
+		Logger:     logger,
+		Metrics:    services.NewExpvarMetrics(),
+		Tracer:     tracer,
+		Authorizer: usecases.NewImpersonationGuard(authorizer, usecases.ImpersonationScope),
+		Registry:   registry,
+		TxManager:  database.NewNoopTxManager(),
+		Reporter:   reporter,
+		Meter:      usageMeter,
+
+		DefaultTimeout: cfg.UseCaseTimeout,
+		Timeouts:       cfg.UseCaseTimeouts,
+
+		Cache:     resultCache,
+		CacheTTLs: cfg.CacheTTLs,
+
+		Hooks:        ports.Hooks,
+		Availability: availability,
+		Audit:        auditTrail,
+		Captcha:      captcha,
+		Moderation:   moderation,
+	}
+
+	// Use cases de commande (écritures)
+	createUserCommand := usecases.NewCreateUserUseCase(userRepo, passwordHasher, emailSender, publisher, tasks, logger, clock)
+	deleteUserCommand := usecases.Command(usecases.NewDeleteUserUseCase(userRepo, publisher, clock).Execute)
+	createUser := usecases.Wrap[usecases.CreateUserRequest, *usecases.CreateUserResponse](pipeline, "create_user", usecases.AccessPublic, createUserCommand)
+	updateUser := usecases.Wrap[usecases.UpdateUserRequest, *usecases.UpdateUserResponse](pipeline, "update_user", usecases.AccessUser,
+		usecases.NewUpdateUserUseCase(userRepo, attributeSchemas, publisher, clock))
+	patchUser := usecases.Wrap[usecases.PatchUserRequest, *usecases.UpdateUserResponse](pipeline, "patch_user", usecases.AccessUser,
+		usecases.NewPatchUserUseCase(userRepo, attributeSchemas, publisher, clock))
+	deleteUser := usecases.Wrap(pipeline, "delete_user", usecases.AccessUser, deleteUserCommand)
+	deactivateUser := usecases.Wrap[usecases.DeactivateUserRequest, *usecases.UserStatusResponse](pipeline, "deactivate_user", usecases.AccessUser,
+		usecases.NewDeactivateUserUseCase(userRepo, publisher, clock))
+	reactivateUser := usecases.Wrap[int, *usecases.UserStatusResponse](pipeline, "reactivate_user", usecases.AccessUser,
+		usecases.NewReactivateUserUseCase(userRepo, publisher, clock))
+	setUserHandle := usecases.Wrap[usecases.SetUserHandleRequest, *usecases.SetUserHandleResponse](pipeline, "set_user_handle", usecases.AccessUser,
+		usecases.NewSetUserHandleUseCase(userRepo, publisher, clock))
+	checkHandleAvailability := usecases.Wrap[string, *usecases.HandleAvailabilityResponse](pipeline, "check_handle_availability", usecases.AccessPublic,
+		usecases.NewCheckHandleAvailabilityUseCase(userRepo))
+	termsChecker := usecases.NewTermsChecker(termsRepo, cfg.TermsVersion)
+	// Historique des sessions : appareil (User-Agent) et localisation (GEOIP_DATABASE) du client
+	var geoResolver usecases.GeoResolver
+	if cfg.GeoIPDatabase != "" {
+		resolver, err := services.LoadCIDRGeoResolver(cfg.GeoIPDatabase)
+		if err != nil {
+			return nil, fmt.Errorf("geoip database: %w", err)
+		}
+		geoResolver = resolver
+	}
+	sessionRepo := database.NewInMemorySessionRepository(cfg.SessionHistory)
+	userAgents := services.NewUserAgentParser()
+	sessionRecorder := usecases.NewSessionRecorder(sessionRepo, userAgents, geoResolver, publisher, tokenGenerator, logger)
+	eventBus.Subscribe(services.AllEvents, sessionRecorder.Handle)
+	// Règles de risque (LOGIN_RISK_RULES) : blocage ou code envoyé par email avant l'émission du jeton
+	var loginRisk *usecases.LoginRiskGuard
+	if len(cfg.LoginRiskRules) > 0 {
+		var policies []usecases.LoginRiskPolicy
+		for _, name := range usecases.LoginRiskRules {
+			decision, ok := cfg.LoginRiskRules[name]
+			if !ok {
+				continue
+			}
+			var rule usecases.LoginRiskRule
+			switch name {
+			case "new_country":
+				rule = usecases.NewCountryRule{}
+			case "impossible_travel":
+				rule = usecases.NewImpossibleTravelRule(float64(cfg.LoginRiskMaxSpeed))
+			case "tor":
+				rule = usecases.NewIPListRule("tor", torExitList)
+			}
+			policies = append(policies, usecases.LoginRiskPolicy{Rule: rule, Decision: usecases.RiskDecision(decision)})
+		}
+		loginRisk = usecases.NewLoginRiskGuard(policies, database.NewInMemoryLoginChallengeRepository(), userRepo,
+			emailSender, auditTrail, tokenGenerator, clock, logger, cfg.LoginChallengeTTL)
+	}
+	sessions := usecases.NewSessionOpener(userRepo, tokenService, termsChecker, sessionRecorder, loginRisk, publisher, logger, cfg.AccessTokenTTL, clock)
+	listSessions := usecases.Wrap[usecases.ListSessionsRequest, *usecases.ListSessionsResponse](pipeline, "list_sessions", usecases.AccessUser,
+		usecases.NewListSessionsUseCase(sessionRepo))
+	login := usecases.Wrap[usecases.LoginRequest, *usecases.LoginResponse](pipeline, "login", usecases.AccessPublic,
+		usecases.NewLoginUseCase(credentials, sessions))
+	verifyLogin := usecases.Wrap[usecases.VerifyLoginRequest, *usecases.LoginResponse](pipeline, "verify_login", usecases.AccessPublic,
+		usecases.NewVerifyLoginUseCase(sessions))
+	impersonateUser := usecases.Wrap[usecases.ImpersonateUserRequest, *usecases.ImpersonateUserResponse](pipeline, "impersonate_user", usecases.AccessImpersonation,
+		usecases.NewImpersonateUserUseCase(userRepo, tokenService, publisher, logger, cfg.ImpersonationRole, cfg.ImpersonationTTL, clock))
+
+	// Inscription en libre-service : saga compte → vérification → confirmation → espace analytics → avis
+	var workspaces usecases.WorkspaceProvisioner = services.NewLocalWorkspaceProvisioner(tokenGenerator)
+	if cfg.WorkspaceURL != "" {
+		workspaces = services.NewHTTPWorkspaceProvisioner(cfg.WorkspaceURL, cfg.WorkspaceToken, clients)
+	}
+	onboardingRepo := database.NewInMemoryOnboardingRepository()
+	onboarding := usecases.NewOnboardingCoordinator(onboardingRepo, createUserCommand, deleteUserCommand, emailSender,
+		workspaces, tokenGenerator, clock, logger, cfg.OnboardingAdminEmails, cfg.OnboardingConfirmTTL)
+	startOnboarding := usecases.Wrap[usecases.StartOnboardingRequest, *usecases.OnboardingResponse](pipeline, "start_onboarding", usecases.AccessPublic,
+		usecases.NewStartOnboardingUseCase(onboarding))
+	confirmOnboarding := usecases.Wrap[usecases.ConfirmOnboardingRequest, *usecases.OnboardingResponse](pipeline, "confirm_onboarding", usecases.AccessPublic,
+		usecases.NewConfirmOnboardingUseCase(onboarding))
+	getOnboarding := usecases.Wrap[string, *usecases.OnboardingResponse](pipeline, "get_onboarding", usecases.AccessPublic,
+		usecases.NewGetOnboardingUseCase(onboardingRepo))
+	expireOnboardings := usecases.Wrap[usecases.ExpireOnboardingsRequest, *usecases.ExpireOnboardingsResponse](pipeline, "expire_onboardings", usecases.AccessSystem,
+		usecases.NewExpireOnboardingsUseCase(onboarding))
+
+	// SSO SAML par tenant : l'IdP est configuré via PUT /tenants/{tenant}/identity-provider
+	samlSP := services.NewSAMLServiceProvider(cfg.SAMLBaseURL)
+	configureIdentityProvider := usecases.Wrap[usecases.ConfigureIdentityProviderRequest, *usecases.IdentityProviderResponse](pipeline, "configure_identity_provider", usecases.AccessAdmin,
+		usecases.NewConfigureIdentityProviderUseCase(identityProviderRepo, samlSP, attributeSchemas, clock))
+	getIdentityProvider := usecases.Wrap[string, *usecases.IdentityProviderResponse](pipeline, "get_identity_provider", usecases.AccessAdmin,
+		usecases.NewGetIdentityProviderUseCase(identityProviderRepo))
+	getSAMLMetadata := usecases.Wrap[string, []byte](pipeline, "get_saml_metadata", usecases.AccessPublic,
+		usecases.NewGetSAMLMetadataUseCase(samlSP))
+	startSSOLogin := usecases.Wrap[usecases.StartSSOLoginRequest, *usecases.StartSSOLoginResponse](pipeline, "start_sso_login", usecases.AccessPublic,
+		usecases.NewStartSSOLoginUseCase(identityProviderRepo, ssoRequestRepo, samlSP, tokenGenerator, clock))
+	consumeSSOResponse := usecases.Wrap[usecases.ConsumeSSOResponseRequest, *usecases.ConsumeSSOResponseResponse](pipeline, "consume_sso_response", usecases.AccessPublic,
+		usecases.NewConsumeSSOResponseUseCase(identityProviderRepo, ssoRequestRepo, samlSP, userRepo, externalLinkRepo,
+			passwordHasher, attributeSchemas, publisher, logger, sessions, clock, tokenGenerator))
+
+	// Provisionnement SCIM 2.0 (Okta, Entra ID...) : l'externalId est conservé comme lien externe "scim"
+	listSCIMUsers := usecases.Wrap[usecases.ListSCIMUsersRequest, *usecases.ListSCIMUsersResponse](pipeline, "list_scim_users", usecases.AccessSystem,
+		usecases.NewListSCIMUsersUseCase(userRepo, externalLinkRepo))
+	createSCIMUser := usecases.Wrap[usecases.SCIMUserRequest, *usecases.SCIMUserResponse](pipeline, "create_scim_user", usecases.AccessSystem,
+		usecases.NewCreateSCIMUserUseCase(userRepo, externalLinkRepo, passwordHasher, publisher, clock, tokenGenerator))
+	getSCIMUser := usecases.Wrap[int, *usecases.SCIMUserResponse](pipeline, "get_scim_user", usecases.AccessSystem,
+		usecases.NewGetSCIMUserUseCase(userRepo, externalLinkRepo))
+	replaceSCIMUser := usecases.Wrap[usecases.ReplaceSCIMUserRequest, *usecases.SCIMUserResponse](pipeline, "replace_scim_user", usecases.AccessSystem,
+		usecases.NewReplaceSCIMUserUseCase(userRepo, externalLinkRepo, publisher, clock))
+	patchSCIMUser := usecases.Wrap[usecases.PatchSCIMUserRequest, *usecases.SCIMUserResponse](pipeline, "patch_scim_user", usecases.AccessSystem,
+		usecases.NewPatchSCIMUserUseCase(userRepo, externalLinkRepo, publisher, clock))
+	deleteSCIMUser := usecases.Wrap(pipeline, "delete_scim_user", usecases.AccessSystem,
+		usecases.Command(usecases.NewDeleteSCIMUserUseCase(userRepo, externalLinkRepo, publisher, clock).Execute))
+
+	acceptTerms := usecases.Wrap[usecases.AcceptTermsRequest, *usecases.TermsStatusResponse](pipeline, "accept_terms", usecases.AccessUser,
+		usecases.NewAcceptTermsUseCase(userRepo, termsRepo, termsChecker, publisher, clock))
+	bulkCreateUsers := usecases.Wrap[usecases.BulkCreateUsersRequest, *usecases.BulkCreateUsersResponse](pipeline, "bulk_create_users", usecases.AccessAdmin,
+		usecases.NewBulkCreateUsersUseCase(userRepo, passwordHasher, publisher, clock))
+	bulkUpdateUsers := usecases.Wrap[usecases.BulkUpdateUsersRequest, *usecases.BulkUpdateUsersResponse](pipeline, "bulk_update_users", usecases.AccessAdmin,
+		usecases.NewBulkUpdateUsersUseCase(userRepo, publisher, clock))
+	bulkDeleteUsers := usecases.Wrap[usecases.BulkDeleteUsersRequest, *usecases.BulkDeleteUsersResponse](pipeline, "bulk_delete_users", usecases.AccessAdmin,
+		usecases.NewBulkDeleteUsersUseCase(userRepo, publisher, clock))
+
+	// Upload fragmenté des fichiers volumineux (imports CSV), sur disque local
+	fileStorage, err := services.NewLocalFileStorage(cfg.UploadDir)
+	if err != nil {
+		return nil, fmt.Errorf("UPLOAD_DIR: %w", err)
+	}
+	uploads := usecases.NewUploads(database.NewInMemoryUploadSessionRepository(), fileStorage, tokenGenerator, clock, logger,
+		int64(cfg.UploadChunkSizeMB)<<20, int64(cfg.UploadMaxSizeMB)<<20, cfg.UploadTTL)
+	createUpload := usecases.Wrap[usecases.CreateUploadRequest, *usecases.UploadResponse](pipeline, "create_upload", usecases.AccessUser,
+		usecases.NewCreateUploadUseCase(uploads))
+	getUpload := usecases.Wrap[string, *usecases.UploadResponse](pipeline, "get_upload", usecases.AccessUser,
+		usecases.NewGetUploadUseCase(uploads))
+	uploadChunk := usecases.Wrap[usecases.UploadChunkRequest, *usecases.UploadResponse](pipeline, "upload_chunk", usecases.AccessUser,
+		usecases.NewUploadChunkUseCase(uploads))
+	completeUpload := usecases.Wrap[string, *usecases.UploadResponse](pipeline, "complete_upload", usecases.AccessUser,
+		usecases.NewCompleteUploadUseCase(uploads))
+	purgeExpiredUploads := usecases.Wrap[usecases.PurgeExpiredUploadsRequest, *usecases.PurgeExpiredUploadsResponse](pipeline, "purge_expired_uploads", usecases.AccessSystem,
+		usecases.NewPurgeExpiredUploadsUseCase(uploads))
+	importUsers := usecases.Wrap[usecases.ImportUsersRequest, *usecases.ImportUsersResponse](pipeline, "import_users", usecases.AccessAdmin,
+		usecases.NewImportUsersUseCase(uploads, bulkCreateUsers))
+	updateDigestPreference := usecases.Wrap[usecases.UpdateDigestPreferenceRequest, *usecases.UpdateDigestPreferenceResponse](pipeline, "update_digest_preference", usecases.AccessUser,
+		usecases.NewUpdateDigestPreferenceUseCase(userRepo, publisher, clock))
+	updateDisplayPreferences := usecases.Wrap[usecases.UpdateDisplayPreferencesRequest, *usecases.UpdateDisplayPreferencesResponse](pipeline, "update_display_preferences", usecases.AccessUser,
+		usecases.NewUpdateDisplayPreferencesUseCase(userRepo, publisher, clock))
+	getNotificationPreferences := usecases.Wrap[int, *usecases.NotificationPreferencesResponse](pipeline, "get_notification_preferences", usecases.AccessUser,
+		usecases.NewGetNotificationPreferencesUseCase(userRepo, notificationPrefRepo))
+	updateNotificationPreferences := usecases.Wrap[usecases.UpdateNotificationPreferencesRequest, *usecases.NotificationPreferencesResponse](pipeline, "update_notification_preferences", usecases.AccessUser,
+		usecases.NewUpdateNotificationPreferencesUseCase(userRepo, notificationPrefRepo, publisher, clock))
+	listNotifications := usecases.Wrap[usecases.ListNotificationsRequest, *usecases.ListNotificationsResponse](pipeline, "list_notifications", usecases.AccessUser,
+		usecases.NewListNotificationsUseCase(notificationRepo))
+	markNotificationAsRead := usecases.Wrap[usecases.MarkAsReadRequest, *usecases.MarkAsReadResponse](pipeline, "mark_notification_as_read", usecases.AccessUser,
+		usecases.NewMarkAsReadUseCase(notificationRepo, clock))
+	sendWeeklyDigests := usecases.Wrap[usecases.SendWeeklyDigestsRequest, *usecases.SendWeeklyDigestsResponse](pipeline, "send_weekly_digests", usecases.AccessSystem,
+		usecases.NewSendWeeklyDigestsUseCase(userRepo, activityRepo, outboxRepo, services.NewTemplateDigestRenderer(), displayFormats, clock))
+	processInactiveUsers := usecases.Wrap[usecases.ProcessInactiveUsersRequest, *usecases.ProcessInactiveUsersResponse](pipeline, "process_inactive_users", usecases.AccessSystem,
+		usecases.NewProcessInactiveUsersUseCase(userRepo, outboxRepo, services.NewTemplateReengagementRenderer(), publisher, usecases.InactivityPolicy{
+			RemindAfter:  cfg.ReengagementAfter,
+			CleanupAfter: cfg.CleanupFlagAfter,
+		}, displayFormats, clock))
+	// Facturation : souscription et déclaration d'usage uniquement avec un fournisseur configuré
+	getBillingAccount := usecases.Wrap[usecases.GetBillingAccountRequest, *usecases.BillingAccountResponse](pipeline, "get_billing_account", usecases.AccessAdmin,
+		usecases.NewGetBillingAccountUseCase(billingAccountRepo, billingPlans))
+	webhookTranslators := map[string]usecases.WebhookTranslator{
+		"payments": services.NewPaymentWebhookTranslator(),
+		// Rebonds et plaintes du fournisseur d'email : WEBHOOK_SECRETS="email=..."
+		"email": services.NewEmailFeedbackWebhookTranslator(),
+	}
+	markEmailUndeliverable := usecases.Wrap[usecases.MarkEmailUndeliverableRequest, *usecases.MarkEmailUndeliverableResponse](pipeline, "mark_email_undeliverable", usecases.AccessSystem,
+		usecases.NewMarkEmailUndeliverableUseCase(userRepo, publisher,
+			usecases.NewEmailFeedbackMonitor(cfg.BounceAlertThreshold, cfg.BounceAlertWindow, emailFeedbackMetrics, reporter, logger), clock))
+	var subscribeTenant usecases.UseCase[usecases.SubscribeTenantRequest, *usecases.BillingAccountResponse]
+	var syncSubscription usecases.UseCase[usecases.SyncSubscriptionRequest, *usecases.SyncSubscriptionResponse]
+	var reportBillingUsage usecases.UseCase[usecases.ReportBillingUsageRequest, *usecases.ReportBillingUsageResponse]
+	if billing != nil {
+		subscribeTenant = usecases.Wrap[usecases.SubscribeTenantRequest, *usecases.BillingAccountResponse](pipeline, "subscribe_tenant", usecases.AccessAdmin,
+			usecases.NewSubscribeTenantUseCase(billing, billingAccountRepo, billingPlans, clock))
+		syncSubscription = usecases.Wrap[usecases.SyncSubscriptionRequest, *usecases.SyncSubscriptionResponse](pipeline, "sync_subscription", usecases.AccessSystem,
+			usecases.NewSyncSubscriptionUseCase(billingAccountRepo, billingPlans, clock))
+		reportBillingUsage = usecases.Wrap[usecases.ReportBillingUsageRequest, *usecases.ReportBillingUsageResponse](pipeline, "report_billing_usage", usecases.AccessSystem,
+			usecases.NewReportBillingUsageUseCase(billing, billingAccountRepo, usageRepo))
+		// Secret de signature du endpoint Stripe : WEBHOOK_SECRETS="stripe=whsec_..."
+		webhookTranslators["stripe"] = usecases.NewBillingWebhookTranslator(billing)
+	}
+	webhookHandler := usecases.NewHandleWebhookEventUseCase(
+		webhookEventRepo,
+		failedWebhookRepo,
+		cfg.WebhookMaxFailures,
+		webhookTranslators,
+		updateUser,
+		deleteUser,
+		syncSubscription,
+		markEmailUndeliverable,
+		clock,
+		logger,
+	)
+	handleWebhook := usecases.Wrap[usecases.WebhookEvent, *usecases.HandleWebhookEventResponse](pipeline, "handle_webhook_event", usecases.AccessPublic, webhookHandler)
+
+	// Files des éléments abandonnés : `api dlq`, profondeur publiée sous dead_letter_depth
+	deadLetterQueues := map[string]usecases.DeadLetterQueue{
+		usecases.DeadLetterQueueOutbox:   usecases.NewOutboxDeadLetters(outboxRepo, cfg.OutboxMaxAttempts),
+		usecases.DeadLetterQueueWebhooks: usecases.NewWebhookDeadLetters(failedWebhookRepo, webhookHandler),
+	}
+	deadLetterMetrics := services.NewExpvarDeadLetterMetrics()
+	app.ListDeadLetters = usecases.Wrap[usecases.ListDeadLettersRequest, *usecases.ListDeadLettersResponse](pipeline, "list_dead_letters", usecases.AccessAdmin,
+		usecases.NewListDeadLettersUseCase(deadLetterQueues, deadLetterMetrics))
+	app.GetDeadLetter = usecases.Wrap[usecases.GetDeadLetterRequest, *usecases.DeadLetter](pipeline, "get_dead_letter", usecases.AccessAdmin,
+		usecases.NewGetDeadLetterUseCase(deadLetterQueues))
+	app.ReplayDeadLetters = usecases.Wrap[usecases.DeadLetterBatchRequest, *usecases.DeadLetterBatchResponse](pipeline, "replay_dead_letters", usecases.AccessAdmin,
+		usecases.NewReplayDeadLettersUseCase(deadLetterQueues, deadLetterMetrics))
+	app.DiscardDeadLetters = usecases.Wrap[usecases.DeadLetterBatchRequest, *usecases.DeadLetterBatchResponse](pipeline, "discard_dead_letters", usecases.AccessAdmin,
+		usecases.NewDiscardDeadLettersUseCase(deadLetterQueues, deadLetterMetrics))
+	measureDeadLetters := usecases.Wrap[usecases.MeasureDeadLettersRequest, *usecases.MeasureDeadLettersResponse](pipeline, "measure_dead_letters", usecases.AccessSystem,
+		usecases.NewMeasureDeadLettersUseCase(deadLetterQueues, deadLetterMetrics))
+
+	// Synchronisation SIRH : sans HR_SYNC_URL, POST /sync/users répond 503
+	syncUsers := usecases.Wrap[usecases.SyncUsersRequest, *usecases.SyncUsersResponse](pipeline, "sync_users", usecases.AccessAdmin,
+		usecases.NewSyncUsersUseCase(userRepo, externalLinkRepo, hrProvider, passwordHasher, publisher, syncPolicy, clock, tokenGenerator))
+
+	// Use cases de lecture (modèle de lecture uniquement)
+	// Lectures mises en cache (CACHE_TTLS) : étiquetées par l'utilisateur, invalidées par ses événements
+	getUser := usecases.WrapCached(pipeline, "get_user", usecases.AccessUser,
+		usecases.UseCaseFunc[int, *usecases.GetUserResponse](usecases.NewGetUserUseCase(userReadRepo).ExecuteByID),
+		func(_ int, output *usecases.GetUserResponse) []string {
+			return []string{usecases.UserCacheTag(output.ID)}
+		})
+	getUserByHandle := usecases.WrapCached(pipeline, "get_user_by_handle", usecases.AccessAdmin,
+		usecases.UseCaseFunc[string, *usecases.GetUserResponse](usecases.NewGetUserUseCase(userReadRepo).ExecuteByHandle),
+		func(_ string, output *usecases.GetUserResponse) []string {
+			return []string{usecases.UserCacheTag(output.ID)}
+		})
+	listInactiveUsers := usecases.Wrap[usecases.ListInactiveUsersRequest, *usecases.ListInactiveUsersResponse](pipeline, "list_inactive_users", usecases.AccessAdmin,
+		usecases.NewListInactiveUsersUseCase(userRepo, clock))
+	// Recherche multicritère : stockage d'écriture (attributs indexés en JSONB)
+	searchUsers := usecases.Wrap[usecases.SearchUsersRequest, *usecases.SearchUsersResponse](pipeline, "search_users", usecases.AccessAdmin,
+		usecases.NewSearchUsersUseCase(userRepo, attributeSchemas))
+	searchEvents := usecases.Wrap[usecases.SearchEventsRequest, *usecases.SearchEventsResponse](pipeline, "search_events", usecases.AccessAdmin,
+		usecases.NewSearchEventsUseCase(eventSearchRepo))
+	// Agrégats : aucune invalidation, la TTL borne le retard sur les événements récents
+	getEventRollups := usecases.WrapCached[usecases.GetEventRollupsRequest, *usecases.GetEventRollupsResponse](pipeline, "get_event_rollups", usecases.AccessAdmin,
+		usecases.NewGetEventRollupsUseCase(rollupRepo, clock), nil)
+	// Requêtes sur les événements bruts (expressions de filtre) et rapports enregistrés
+	queryEventsUseCase := usecases.NewQueryEventsUseCase(eventRepo, clock)
+	savedReportRepo := database.NewInMemorySavedReportRepository()
+	queryEvents := usecases.Wrap[usecases.QueryEventsRequest, *usecases.GetEventRollupsResponse](pipeline, "query_events", usecases.AccessAdmin,
+		queryEventsUseCase)
+	createSavedReport := usecases.Wrap[usecases.CreateSavedReportRequest, *entities.SavedReport](pipeline, "create_saved_report", usecases.AccessAdmin,
+		usecases.NewCreateSavedReportUseCase(savedReportRepo, clock))
+	listSavedReports := usecases.Wrap[usecases.ListSavedReportsRequest, *usecases.ListSavedReportsResponse](pipeline, "list_saved_reports", usecases.AccessAdmin,
+		usecases.NewListSavedReportsUseCase(savedReportRepo))
+	runSavedReport := usecases.Wrap[usecases.SavedReportRequest, *usecases.RunSavedReportResponse](pipeline, "run_saved_report", usecases.AccessAdmin,
+		usecases.NewRunSavedReportUseCase(savedReportRepo, queryEventsUseCase, clock))
+	deleteSavedReport := usecases.Wrap(pipeline, "delete_saved_report", usecases.AccessAdmin,
+		usecases.Command(usecases.NewDeleteSavedReportUseCase(savedReportRepo).Execute))
+	// Ingestion analytics : limites de cardinalité et réservoirs par fenêtre ANALYTICS_SAMPLE_WINDOW
+	eventSampler := usecases.NewEventSampler(usecases.TrackingLimits{
+		MaxEventNames:     cfg.AnalyticsMaxEvents,
+		MaxPropertyValues: cfg.AnalyticsMaxValues,
+		ReservoirSize:     cfg.AnalyticsReservoir,
+	})
+	trackingMetrics := services.NewExpvarTrackingMetrics()
+	// Consentement : ANALYTICS_CONSENT_DEFAULT tant que l'utilisateur ne s'est pas prononcé
+	defaultConsent, _ := entities.NewAnalyticsConsent(cfg.AnalyticsConsentDefault)
+	consentChecker := usecases.NewConsentChecker(consentRepo, defaultConsent, cfg.AnalyticsWithoutConsent)
+	trackEvent := usecases.Wrap[usecases.TrackEventRequest, *usecases.TrackEventResponse](pipeline, "track_event", usecases.AccessPublic,
+		usecases.NewTrackEventUseCase(eventRepo, eventSampler, consentChecker, userAgents, trackingMetrics, clock))
+	flushEventSamples := usecases.Wrap[usecases.FlushEventSamplesRequest, *usecases.FlushEventSamplesResponse](pipeline, "flush_event_samples", usecases.AccessSystem,
+		usecases.NewFlushEventSamplesUseCase(eventRepo, eventSampler, trackingMetrics))
+	// Clés d'écriture des tenants : ingestion publique (/collect/), sans jeton utilisateur
+	writeKeyRepo := database.NewInMemoryWriteKeyRepository()
+	writeKeys := usecases.NewWriteKeyAuthenticator(writeKeyRepo, clock)
+	createWriteKey := usecases.Wrap[usecases.CreateWriteKeyRequest, *usecases.WriteKeyResponse](pipeline, "create_write_key", usecases.AccessAdmin,
+		usecases.NewCreateWriteKeyUseCase(writeKeyRepo, tokenGenerator, clock))
+	listWriteKeys := usecases.Wrap[usecases.ListWriteKeysRequest, *usecases.ListWriteKeysResponse](pipeline, "list_write_keys", usecases.AccessAdmin,
+		usecases.NewListWriteKeysUseCase(writeKeyRepo))
+	rotateWriteKey := usecases.Wrap[usecases.WriteKeyRequest, *usecases.WriteKeyResponse](pipeline, "rotate_write_key", usecases.AccessAdmin,
+		usecases.NewRotateWriteKeyUseCase(writeKeyRepo, tokenGenerator, clock, cfg.WriteKeyRotationGrace))
+	revokeWriteKey := usecases.Wrap(pipeline, "revoke_write_key", usecases.AccessAdmin,
+		usecases.Command(usecases.NewRevokeWriteKeyUseCase(writeKeyRepo, clock).Execute))
+	getTenantUsage := usecases.Wrap[usecases.GetTenantUsageRequest, *usecases.TenantUsageResponse](pipeline, "get_tenant_usage", usecases.AccessAdmin,
+		usecases.NewGetTenantUsageUseCase(usageMeter))
+	getTermsStatus := usecases.Wrap[int, *usecases.TermsStatusResponse](pipeline, "get_terms_status", usecases.AccessUser,
+		usecases.NewGetTermsStatusUseCase(termsChecker))
+	getUserTimeline := usecases.Wrap[usecases.GetUserTimelineRequest, *usecases.GetUserTimelineResponse](pipeline, "get_user_timeline", usecases.AccessSupport,
+		usecases.NewGetUserTimelineUseCase(timelineRepo))
+	getAnalyticsConsent := usecases.Wrap[int, *usecases.AnalyticsConsentResponse](pipeline, "get_analytics_consent", usecases.AccessUser,
+		usecases.NewGetAnalyticsConsentUseCase(consentChecker))
+	recordAnalyticsConsent := usecases.Wrap[usecases.RecordAnalyticsConsentRequest, *usecases.AnalyticsConsentResponse](pipeline, "record_analytics_consent", usecases.AccessUser,
+		usecases.NewRecordAnalyticsConsentUseCase(userRepo, consentRepo, consentChecker, publisher, clock))
+	listConsentChanges := usecases.Wrap[usecases.ListConsentChangesRequest, *usecases.ListConsentChangesResponse](pipeline, "list_consent_changes", usecases.AccessAdmin,
+		usecases.NewListConsentChangesUseCase(consentRepo))
+	// LIST_TOTALS=estimated : totaux sans filtre tirés des statistiques du stockage
+	estimateTotals := cfg.ListTotals == config.ListTotalsEstimated
+	listUsers := usecases.Wrap[usecases.ListUsersRequest, *usecases.ListUsersResponse](pipeline, "list_users", usecases.AccessAdmin,
+		usecases.NewListUsersUseCase(userReadRepo, flags, estimateTotals))
+	countUsers := usecases.Wrap[usecases.CountUsersRequest, *usecases.CountUsersResponse](pipeline, "count_users", usecases.AccessAdmin,
+		usecases.NewCountUsersUseCase(userReadRepo, estimateTotals))
+	getUserStats := usecases.Wrap[usecases.GetUserStatsRequest, *usecases.GetUserStatsResponse](pipeline, "get_user_stats", usecases.AccessAdmin,
+		usecases.NewGetUserStatsUseCase(userReadRepo, onboardingRepo, clock))
+
+	// Delivery
+	verifiers := make(map[string]*handlers.SignatureVerifier)
+	for source, secret := range cfg.WebhookSecrets {
+		verifiers[source] = handlers.NewSignatureVerifier(secret, cfg.WebhookTolerance)
+	}
+
+	getAvailability := usecases.Wrap[struct{}, *usecases.AvailabilityResponse](pipeline, "get_availability", usecases.AccessAdmin,
+		usecases.NewGetAvailabilityUseCase(availability))
+	setAvailability := usecases.Wrap[usecases.SetAvailabilityRequest, *usecases.AvailabilityResponse](pipeline, "set_availability", usecases.AccessAdmin,
+		usecases.NewSetAvailabilityUseCase(availability, logger))
+	getLogLevel := usecases.Wrap[struct{}, *usecases.LogLevelResponse](pipeline, "get_log_level", usecases.AccessAdmin,
+		usecases.NewGetLogLevelUseCase(logs))
+	setLogLevel := usecases.Wrap[usecases.SetLogLevelRequest, *usecases.LogLevelResponse](pipeline, "set_log_level", usecases.AccessAdmin,
+		usecases.NewSetLogLevelUseCase(logs, logger))
+	saveEmailTemplate := usecases.Wrap[usecases.SaveEmailTemplateRequest, *usecases.EmailTemplateResponse](pipeline, "save_email_template", usecases.AccessAdmin,
+		usecases.NewSaveEmailTemplateUseCase(emailTemplateRepo, emailTemplateEngine, clock))
+	listEmailTemplateVersions := usecases.Wrap[usecases.ListEmailTemplateVersionsRequest, *usecases.ListEmailTemplateVersionsResponse](pipeline, "list_email_template_versions", usecases.AccessAdmin,
+		usecases.NewListEmailTemplateVersionsUseCase(emailTemplateRepo))
+	previewEmailTemplate := usecases.Wrap[usecases.PreviewEmailTemplateRequest, *usecases.PreviewEmailTemplateResponse](pipeline, "preview_email_template", usecases.AccessAdmin,
+		usecases.NewPreviewEmailTemplateUseCase(emailTemplates, emailTemplateRepo, emailTemplateEngine, clock))
+	listAuditEntries := usecases.Wrap[usecases.ListAuditEntriesRequest, *usecases.ListAuditEntriesResponse](pipeline, "list_audit_entries", usecases.AccessAdmin,
+		usecases.NewListAuditEntriesUseCase(auditRepo))
+	exportAuditEntries := usecases.Wrap[usecases.ExportAuditEntriesRequest, *usecases.ExportAuditEntriesResponse](pipeline, "export_audit_entries", usecases.AccessAdmin,
+		usecases.NewExportAuditEntriesUseCase(auditRepo))
+	createAuditExportLink := usecases.Wrap[usecases.AuditQuery, *usecases.DownloadLink](pipeline, "create_audit_export_link", usecases.AccessAdmin,
+		usecases.NewCreateAuditExportLinkUseCase(downloadLinks))
+
+	// Rétention par classe de données (RETENTION_TTLS) ; classes archivées dans le FileStorage
+	retentionTargets := map[string]usecases.RetentionTarget{
+		usecases.RetentionUsers:     usecases.NewUserRetention(userRepo, publisher, clock),
+		usecases.RetentionSessions:  usecases.NewSessionRetention(sessionRepo),
+		usecases.RetentionAuditLogs: usecases.NewAuditRetention(auditRepo),
+		usecases.RetentionRollups:   usecases.NewRollupRetention(rollupRepo),
+	}
+	if purger, ok := eventStore.(repositories.EventPurger); ok {
+		retentionTargets[usecases.RetentionRawEvents] = usecases.NewEventRetention(eventStore, purger)
+	} else if cfg.RetentionTTLs[usecases.RetentionRawEvents] > 0 {
+		logger.Warn("Event store cannot delete events, retention disabled", map[string]interface{}{"class": usecases.RetentionRawEvents})
+	}
+	retentionPolicies := make([]usecases.RetentionPolicy, 0, len(cfg.RetentionTTLs))
+	for _, class := range usecases.RetentionClasses {
+		if ttl := cfg.RetentionTTLs[class]; ttl > 0 {
+			retentionPolicies = append(retentionPolicies, usecases.RetentionPolicy{
+				Class: class, TTL: ttl, Archive: slices.Contains(cfg.RetentionArchive, class),
+			})
+		}
+	}
+	// Archivage à froid des événements analytics (EVENT_ARCHIVE_*) : bucket S3 ou UPLOAD_DIR
+	var archiveStorage usecases.FileStorage = fileStorage
+	if cfg.EventArchiveS3Bucket != "" {
+		archiveStorage = services.NewS3FileStorage(services.S3StorageConfig{
+			Region:      cfg.AWSRegion,
+			Endpoint:    cfg.EventArchiveS3Endpoint,
+			Bucket:      cfg.EventArchiveS3Bucket,
+			Prefix:      cfg.EventArchiveS3Prefix,
+			Credentials: awsCredentials(cfg),
+			Clients:     clients,
+		})
+	}
+	eventArchive := usecases.NewEventArchive(archiveStorage, services.NewNDJSONEventFormat())
+	var archiveEvents usecases.UseCase[usecases.ArchiveEventsRequest, *usecases.ArchiveEventsResponse]
+	if purger, ok := eventStore.(repositories.EventPurger); ok {
+		archiveEvents = usecases.Wrap[usecases.ArchiveEventsRequest, *usecases.ArchiveEventsResponse](pipeline, "archive_events", usecases.AccessSystem,
+			usecases.NewArchiveEventsUseCase(eventArchive, eventStore, purger,
+				cfg.EventArchiveAfter, cfg.EventArchiveBatchSize, cfg.EventArchiveMaxDays, clock, logger))
+	} else if cfg.EventArchiveAfter > 0 {
+		logger.Warn("Event store cannot delete events, archival disabled", nil)
+	}
+	app.RestoreEvents = usecases.Wrap[usecases.RestoreEventsRequest, *usecases.RestoreEventsResponse](pipeline, "restore_events", usecases.AccessAdmin,
+		usecases.NewRestoreEventsUseCase(eventArchive, eventStore, cfg.EventArchiveRestoreHold, clock, logger))
+
+	enforceRetention := usecases.Wrap[usecases.EnforceRetentionRequest, *usecases.EnforceRetentionResponse](pipeline, "enforce_retention", usecases.AccessSystem,
+		usecases.NewEnforceRetentionUseCase(retentionPolicies, retentionTargets, fileStorage,
+			cfg.RetentionBatchSize, cfg.RetentionMaxBatches, clock, logger))
+
+	// Exports asynchrones : fichier produit par le TaskRunner, servi sur lien signé ; CSV ou Parquet
+	exportSources := map[string]usecases.ExportSource{
+		"audit":   usecases.NewAuditExportSource(auditRepo),
+		"events":  usecases.NewEventExportSource(eventRepo, clock),
+		"rollups": usecases.NewRollupExportSource(rollupRepo, clock),
+	}
+	exports := usecases.NewExportJobs(database.NewInMemoryExportJobRepository(), fileStorage, exportSources,
+		pipeline.Authorizer, tasks, downloadLinks, tokenGenerator, clock, logger, cfg.ExportRetention,
+		services.NewParquetExportEncoder(cfg.ExportParquetCompression, cfg.ExportParquetRowGroupRows))
+	startExport := usecases.Wrap[usecases.StartExportRequest, *usecases.ExportJobResponse](pipeline, "start_export", usecases.AccessUser,
+		usecases.NewStartExportUseCase(exports))
+	getExportStatus := usecases.Wrap[string, *usecases.ExportJobResponse](pipeline, "get_export_status", usecases.AccessUser,
+		usecases.NewGetExportStatusUseCase(exports))
+	downloadExport := usecases.Wrap[string, *usecases.ExportFile](pipeline, "download_export", usecases.AccessSystem,
+		usecases.NewDownloadExportUseCase(exports))
+	purgeExpiredExports := usecases.Wrap[usecases.PurgeExpiredExportsRequest, *usecases.PurgeExpiredExportsResponse](pipeline, "purge_expired_exports", usecases.AccessSystem,
+		usecases.NewPurgeExpiredExportsUseCase(exports))
+
+	// Alertes sur les métriques : règles évaluées sur les agrégats quotidiens par le job alert_evaluation
+	alertRuleRepo := database.NewInMemoryAlertRuleRepository()
+	createAlertRule := usecases.Wrap[usecases.CreateAlertRuleRequest, *entities.AlertRule](pipeline, "create_alert_rule", usecases.AccessAdmin,
+		usecases.NewCreateAlertRuleUseCase(alertRuleRepo, clock))
+	listAlertRules := usecases.Wrap[usecases.ListAlertRulesRequest, *usecases.ListAlertRulesResponse](pipeline, "list_alert_rules", usecases.AccessAdmin,
+		usecases.NewListAlertRulesUseCase(alertRuleRepo))
+	deleteAlertRule := usecases.Wrap[usecases.AlertRuleRequest, struct{}](pipeline, "delete_alert_rule", usecases.AccessAdmin,
+		usecases.Command(usecases.NewDeleteAlertRuleUseCase(alertRuleRepo).Execute))
+	evaluateAlerts := usecases.Wrap[usecases.EvaluateAlertsRequest, *usecases.EvaluateAlertsResponse](pipeline, "evaluate_alerts", usecases.AccessSystem,
+		usecases.NewEvaluateAlertsUseCase(alertRuleRepo, rollupRepo, userRepo, notificationPrefRepo, notifiers,
+			services.NewHTTPAlertWebhook(clients), logger))
+
+	// Diagnostics : port interne sans authentification (DEBUG_ADDR), sinon /debug/ du routeur,
+	// réservé au rôle d'administration comme les endpoints /admin/*
+	profiles := services.NewProfileStore(cfg.DiagnosticsDir)
+	var diagnostics http.Handler
+	if cfg.DebugAddr != "" {
+		app.debugHandler = handlers.NewDiagnosticsHandler(profiles, nil)
+	} else {
+		diagnostics = handlers.NewDiagnosticsHandler(profiles, pipeline.Authorizer)
+	}
+
+	// Mode session (SESSION_COOKIE) : jeton posé à la connexion, protégé par CSRF dans newHTTPHandler
+	sessionCookie := handlers.SessionCookieOptions{
+		Name:   cfg.SessionCookieName,
+		Secure: cfg.Environment != config.EnvironmentDevelopment,
+	}
+	var router http.Handler = handlers.NewRouter(handlers.Handlers{
+		User:       handlers.NewUserHandler(createUser, getUser, updateUser, patchUser, deleteUser, listUsers, countUsers, displayFormats),
+		UserV2:     handlers.NewUserV2Handler(createUser, getUser, updateUser, deleteUser, listUsers),
+		UserStatus: handlers.NewUserStatusHandler(deactivateUser, reactivateUser),
+		UserHandle: handlers.NewUserHandleHandler(getUserByHandle, checkHandleAvailability, setUserHandle),
+		Inactivity: handlers.NewInactivityHandler(listInactiveUsers),
+		UserSearch: handlers.NewUserSearchHandler(searchUsers),
+		UserSync:   handlers.NewUserSyncHandler(syncUsers),
+		UserStats:  handlers.NewUserStatsHandler(getUserStats),
+		Terms:      handlers.NewTermsHandler(getTermsStatus, acceptTerms),
+		Timeline:   handlers.NewTimelineHandler(getUserTimeline),
+		Consent:    handlers.NewConsentHandler(getAnalyticsConsent, recordAnalyticsConsent, listConsentChanges),
+		Auth:       handlers.NewAuthHandler(login, verifyLogin, impersonateUser, sessionCookie),
+		Session:    handlers.NewSessionHandler(listSessions),
+		Onboarding: handlers.NewOnboardingHandler(startOnboarding, confirmOnboarding, getOnboarding),
+		SSO:        handlers.NewSSOHandler(configureIdentityProvider, getIdentityProvider, getSAMLMetadata, startSSOLogin, consumeSSOResponse),
+		SCIM: handlers.NewSCIMHandler(cfg.SCIMBearerToken, createSCIMUser, getSCIMUser, replaceSCIMUser,
+			patchSCIMUser, deleteSCIMUser, listSCIMUsers),
+		UserBulk:     handlers.NewUserBulkHandler(bulkCreateUsers, bulkUpdateUsers, bulkDeleteUsers, importUsers),
+		Upload:       handlers.NewUploadHandler(createUpload, getUpload, uploadChunk, completeUpload),
+		Preference:   handlers.NewPreferenceHandler(updateDigestPreference, getNotificationPreferences, updateNotificationPreferences, updateDisplayPreferences),
+		Notification: handlers.NewNotificationHandler(listNotifications, markNotificationAsRead, notificationHub, pipeline.Authorizer),
+		Webhook:      handlers.NewWebhookHandler(handleWebhook, verifiers),
+		Analytics:    handlers.NewAnalyticsHandler(analyticsStream, searchEvents, getEventRollups, trackEvent, displayFormats),
+		EventQuery:   handlers.NewEventQueryHandler(queryEvents, createSavedReport, listSavedReports, runSavedReport, deleteSavedReport),
+		Segment:      handlers.NewSegmentHandler(trackEvent, clock),
+		WriteKey:     handlers.NewWriteKeyHandler(createWriteKey, listWriteKeys, rotateWriteKey, revokeWriteKey),
+		Collect: handlers.NewCollectHandler(writeKeys, trackEvent, handlers.CollectLimits{
+			Rate:  float64(cfg.CollectRateLimit),
+			Burst: cfg.CollectRateBurst,
+		}, clock),
+		Usage:        handlers.NewUsageHandler(getTenantUsage),
+		Billing:      handlers.NewBillingHandler(subscribeTenant, getBillingAccount),
+		Availability: handlers.NewAvailabilityHandler(getAvailability, setAvailability),
+		LogLevel:     handlers.NewLogLevelHandler(getLogLevel, setLogLevel),
+		Audit:        handlers.NewAuditHandler(listAuditEntries, exportAuditEntries, createAuditExportLink, displayFormats),
+		Export:       handlers.NewExportHandler(startExport, getExportStatus, downloadExport),
+		MailTemplate: handlers.NewEmailTemplateHandler(saveEmailTemplate, listEmailTemplateVersions, previewEmailTemplate),
+		Alert:        handlers.NewAlertHandler(createAlertRule, listAlertRules, deleteAlertRule),
+		Downloads:    downloadLinks,
+		Diagnostics:  diagnostics,
+		Realtime:     realtimeHub,
+	})
+	if cfg.TermsVersion != "" {
+		// Au plus près du routeur : les autres middlewares (CORS, CSRF...) répondent avant
+		router = handlers.RequireTerms(router, getTermsStatus)
+	}
+	// Sous Authenticate : les clés d'idempotence sont propres à l'acteur
+	router = handlers.Idempotency(router, cfg.IdempotencyTTL)
+	router = handlers.Authenticate(router, tokenService, sessionCookie.Name)
+
+	// Tâches planifiées
+	app.scheduler = services.NewScheduler(logger, reporter)
+	// Les variables d'environnement ne changent pas en cours d'exécution : rien à relire
+	if cfg.SecretsProvider != config.SecretsFromEnv && cfg.SecretsReloadInterval > 0 {
+		app.scheduler.Every(ctx, "secrets_reload", cfg.SecretsReloadInterval, secrets.Reload)
+	}
+	// Réglages rechargeables : SIGHUP (App.ReloadSettings) ou modification des fichiers
+	app.settings = newSettingsReloader(cfg, logger, logs, usageMeter, localFlags)
+	if (cfg.SettingsFile != "" || cfg.FeatureFlagsFile != "") && cfg.SettingsWatchInterval > 0 {
+		app.scheduler.Every(ctx, "settings_watch", cfg.SettingsWatchInterval, app.settings.Watch)
+	}
+	outboxDispatcher := services.NewOutboxDispatcher(outboxRepo, emailSender, clock, logger, cfg.OutboxMaxAttempts)
+	app.jobs = []job{
+		{"outbox_dispatch", cfg.OutboxPollInterval, outboxDispatcher.DispatchPending},
+		{"weekly_digest", cfg.DigestInterval, func(ctx context.Context) {
+			// Les erreurs sont journalisées par le pipeline
+			_, _ = sendWeeklyDigests.Execute(ctx, usecases.SendWeeklyDigestsRequest{Now: clock.Now()})
+		}},
+		{"inactive_users", cfg.InactivityCheckInterval, func(ctx context.Context) {
+			_, _ = processInactiveUsers.Execute(ctx, usecases.ProcessInactiveUsersRequest{Now: clock.Now()})
+		}},
+		{"analytics_sample_flush", cfg.AnalyticsWindow, func(ctx context.Context) {
+			_, _ = flushEventSamples.Execute(ctx, usecases.FlushEventSamplesRequest{})
+		}},
+	}
+	if reportBillingUsage != nil {
+		app.jobs = append(app.jobs, job{"billing_usage_report", cfg.BillingUsageInterval, func(ctx context.Context) {
+			_, _ = reportBillingUsage.Execute(ctx, usecases.ReportBillingUsageRequest{Now: clock.Now()})
+		}})
+	}
+	if cfg.OnboardingCheckInterval > 0 {
+		app.jobs = append(app.jobs, job{"onboarding_expiry", cfg.OnboardingCheckInterval, func(ctx context.Context) {
+			_, _ = expireOnboardings.Execute(ctx, usecases.ExpireOnboardingsRequest{Now: clock.Now()})
+		}})
+	}
+	if cfg.DLQMetricsInterval > 0 {
+		app.jobs = append(app.jobs, job{"dead_letter_metrics", cfg.DLQMetricsInterval, func(ctx context.Context) {
+			_, _ = measureDeadLetters.Execute(ctx, usecases.MeasureDeadLettersRequest{})
+		}})
+	}
+	if cfg.AlertEvaluationInterval > 0 {
+		app.jobs = append(app.jobs, job{"alert_evaluation", cfg.AlertEvaluationInterval, func(ctx context.Context) {
+			_, _ = evaluateAlerts.Execute(ctx, usecases.EvaluateAlertsRequest{Now: clock.Now()})
+		}})
+	}
+	if len(retentionPolicies) > 0 && cfg.RetentionInterval > 0 {
+		app.jobs = append(app.jobs, job{"retention", cfg.RetentionInterval, func(ctx context.Context) {
+			_, _ = enforceRetention.Execute(ctx, usecases.EnforceRetentionRequest{Now: clock.Now()})
+		}})
+	}
+	if archiveEvents != nil && cfg.EventArchiveAfter > 0 && cfg.EventArchiveInterval > 0 {
+		app.jobs = append(app.jobs, job{"event_archive", cfg.EventArchiveInterval, func(ctx context.Context) {
+			_, _ = archiveEvents.Execute(ctx, usecases.ArchiveEventsRequest{Now: clock.Now()})
+		}})
+	}
+	app.jobs = append(app.jobs, job{"upload_purge", cfg.UploadPurgeInterval, func(ctx context.Context) {
+		_, _ = purgeExpiredUploads.Execute(ctx, usecases.PurgeExpiredUploadsRequest{Now: clock.Now()})
+	}})
+	app.jobs = append(app.jobs, job{"export_purge", cfg.ExportPurgeInterval, func(ctx context.Context) {
+		_, _ = purgeExpiredExports.Execute(ctx, usecases.PurgeExpiredExportsRequest{Now: clock.Now()})
+	}})
+	if cfg.HeapProfileThreshold > 0 {
+		heapWatcher := services.NewHeapWatcher(profiles, uint64(cfg.HeapProfileThreshold)<<20, logger)
+		app.jobs = append(app.jobs, job{"heap_watch", cfg.HeapCheckInterval, heapWatcher.Check})
+	}
+	if hrProvider != nil && cfg.HRSyncInterval > 0 {
+		app.jobs = append(app.jobs, job{"hr_user_sync", cfg.HRSyncInterval, func(ctx context.Context) {
+			_, _ = syncUsers.Execute(ctx, usecases.SyncUsersRequest{DryRun: cfg.HRSyncDryRun, Now: clock.Now()})
+		}})
+	}
+	if torExitList != nil {
+		app.jobs = append(app.jobs, job{"tor_exit_list_refresh", cfg.TorExitListRefresh, func(ctx context.Context) {
+			if err := torExitList.Refresh(ctx); err != nil {
+				logger.Error("Failed to refresh TOR exit list", err, map[string]interface{}{"source": cfg.TorExitList})
+			}
+		}})
+	}
+
+	// Sous-commandes : use cases construits à la demande
+	app.pipeline, app.clock = pipeline, clock
+	app.rollupRepo, app.eventHistory = rollupRepo, eventHistory
+	app.userRepo, app.activityRepo, app.userEventStore = userRepo, activityRepo, userEventStore
+	app.userReadRepo, app.resultCache = userReadRepo, resultCache
+	app.passwordHasher, app.tokenGenerator = passwordHasher, tokenGenerator
+	app.BulkCreateUsers = bulkCreateUsers
+	app.UpdateUser = updateUser
+	app.UpdateDigestPreference = updateDigestPreference
+	app.UpdateNotificationPreferences = updateNotificationPreferences
+
+	accessLog, err := newAccessLog(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := accessLog.(io.Closer); ok {
+		app.closers = append(app.closers, func() { _ = closer.Close() })
+	}
+
+	app.Router = router
+	app.Handler = newHTTPHandler(cfg, router, reporter, accessLog)
+	return app, nil
+}
+
+// StartJobs lance les traitements périodiques (outbox, digests, inactivité...) ; sans appel
+// (Lambda, instance multipliée), seul le rechargement des secrets tourne
+func (a *App) StartJobs() {
+	for _, job := range a.jobs {
+		a.scheduler.Every(a.ctx, job.name, job.interval, job.run)
+	}
+}
+
+// ServeDiagnostics lance le serveur de diagnostic sur DEBUG_ADDR (sans effet si vide) ; Shutdown l'arrête
+func (a *App) ServeDiagnostics() error {
+	if a.debugHandler == nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", a.Config.DebugAddr)
+	if err != nil {
+		return fmt.Errorf("DEBUG_ADDR: %w", err)
+	}
+	server := &http.Server{Handler: a.debugHandler, ReadHeaderTimeout: 5 * time.Second}
+	a.closers = append(a.closers, func() { _ = server.Close() })
+	a.Logger.Info("Diagnostics server listening", map[string]interface{}{"addr": listener.Addr().String()})
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.Logger.Error("Diagnostics server failed", err, nil)
+		}
+	}()
+	return nil
+}
+
+// ReloadSettings relit les réglages rechargeables (niveau de log, quotas, feature flags locaux) ;
+// en cas d'erreur, journalisée, les réglages en place sont conservés
+func (a *App) ReloadSettings() error {
+	return a.settings.Reload(a.ctx)
+}
+
+// Shutdown à appeler une fois le trafic arrêté : attend les tâches asynchrones, écrit les
+// événements en tampon, arrête les traitements périodiques puis ferme les dépôts
+func (a *App) Shutdown(ctx context.Context) error {
+	// Les requêtes sont terminées : plus aucune tâche asynchrone ne peut être lancée
+	var errs []error
+	if err := a.tasks.Shutdown(ctx); err != nil {
+		a.Logger.Error("Async tasks did not drain in time", err, nil)
+		errs = append(errs, err)
+	}
+	// Plus aucun événement ne peut arriver : les événements en attente sont écrits
+	if a.EventBuffer != nil {
+		if err := a.EventBuffer.Shutdown(ctx); err != nil {
+			a.Logger.Error("Event buffer did not drain in time", err, map[string]interface{}{"lost": a.EventBuffer.Pending()})
+			errs = append(errs, err)
+		}
+	}
+	a.close()
+	return errors.Join(errs...)
+}
+
+func (a *App) close() {
+	a.stop()
+	if a.scheduler != nil {
+		a.scheduler.Wait()
+	}
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+	a.closers = nil
+}
+
+// BackfillRollups use case de `api rollup-backfill` (historique Elasticsearch requis)
+func (a *App) BackfillRollups() usecases.UseCase[usecases.BackfillRollupsRequest, *usecases.BackfillRollupsResponse] {
+	return usecases.Wrap[usecases.BackfillRollupsRequest, *usecases.BackfillRollupsResponse](a.pipeline, "backfill_rollups", usecases.AccessSystem,
+		usecases.NewBackfillRollupsUseCase(a.eventHistory, a.rollupRepo, a.clock))
+}
+
+// RebuildProjections use case de `api rebuild-projections` (persistance event-sourcée requise) ;
+// le moteur de recherche n'est rejoué que s'il est configuré
+func (a *App) RebuildProjections() usecases.UseCase[usecases.RebuildProjectionsRequest, *usecases.RebuildProjectionsResponse] {
+	newProjections := func() []usecases.Projection {
+		projections := []usecases.Projection{
+			usecases.NewReadModelProjection(a.userReadRepo),
+			usecases.NewRollupProjection(a.rollupRepo),
+		}
+		if a.SearchIndexer != nil {
+			projections = append(projections, usecases.NewSearchProjection(a.SearchIndexer))
+		}
+		// Après les autres : une lecture qui suit l'invalidation voit les projections à jour
+		return append(projections, usecases.NewCacheProjection(a.resultCache))
+	}
+	return usecases.Wrap[usecases.RebuildProjectionsRequest, *usecases.RebuildProjectionsResponse](a.pipeline, "rebuild_projections", usecases.AccessSystem,
+		usecases.NewRebuildProjectionsUseCase(a.userEventStore, newProjections))
+}
+
+// AnonymizeData use case de `api anonymize` ; la clé n'est exigée que par cette sous-commande
+func (a *App) AnonymizeData() (usecases.UseCase[usecases.AnonymizeDataRequest, *usecases.AnonymizeDataResponse], error) {
+	pseudonymizer, err := services.NewHMACPseudonymizer(a.Config.AnonymizationKey)
+	if err != nil {
+		return nil, fmt.Errorf("ANONYMIZATION_KEY: %w", err)
+	}
+	return usecases.Wrap[usecases.AnonymizeDataRequest, *usecases.AnonymizeDataResponse](a.pipeline, "anonymize_data", usecases.AccessSystem,
+		usecases.NewAnonymizeDataUseCase(a.userRepo, a.activityRepo, a.userEventStore, pseudonymizer, a.passwordHasher, a.tokenGenerator)), nil
+}
+
+// newUserRepository choisit le mode de persistance des utilisateurs
+// La fonction retournée libère les ressources (statements, pool) à l'arrêt
+// En mode event-sourcé, eventStore porte les flux ; en mode SQL, le DSN du primaire suit
+// les rotations de DATABASE_URL et son pool est retourné pour les autres tables (nil sinon)
+func newUserRepository(ctx context.Context, cfg *config.Config, eventStore repositories.UserEventStore, clock usecases.Clock, secrets *services.SecretStore, clients *httpclient.Factory) (repositories.UserRepository, *sql.DB, func(), error) {
+	switch cfg.PersistenceMode {
+	case config.PersistenceEventSourced:
+		return withSearchIndex(cfg, database.NewEventSourcedUserRepository(
+			eventStore,
+			database.NewInMemoryUserRepository(), // table d'état courant
+			cfg.SnapshotEvery,
+			clock,
+		)), nil, func() {}, nil
+	case config.PersistenceSQL:
+		pool := database.SQLPoolConfig{
+			MaxOpenConns:    cfg.DBMaxOpenConns,
+			MaxIdleConns:    cfg.DBMaxIdleConns,
+			ConnMaxLifetime: cfg.DBConnMaxLifetime,
+			ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+			Lazy:            cfg.DBLazyConnect,
+		}
+		var closers []func()
+		closeAll := func() {
+			for i := len(closers) - 1; i >= 0; i-- {
+				closers[i]()
+			}
+		}
+		var primaryDB *sql.DB
+		open := func(name string, dsn func() string) (repositories.UserRepository, error) {
+			db, err := database.OpenRotatingSQL(ctx, cfg.DatabaseDriver, dsn, pool)
+			if err != nil {
+				return nil, err
+			}
+			if name == "users" {
+				primaryDB = db
+				// Les connexions inactives portent les anciens identifiants : elles sont recyclées
+				secrets.OnChange(func(secret string) {
+					if secret == secretDatabaseURL {
+						database.RecycleIdleConns(db, pool.MaxIdleConns)
+					}
+				})
+			}
+			services.PublishSQLPoolStats(name, db.Stats)
+
+			// SQL_ADAPTER=ent : même contrat, mêmes tables ; le pool reste partagé avec les autres dépôts
+			if cfg.SQLAdapter == config.SQLAdapterEnt {
+				closers = append(closers, func() { _ = db.Close() })
+				return database.NewEntUserRepository(db), nil
+			}
+			repo := database.NewSQLUserRepository(db)
+			closers = append(closers, func() {
+				_ = repo.Close()
+				_ = db.Close()
+			})
+			return repo, nil
+		}
+
+		// Un DSN retiré de la source ne coupe pas l'accès : celui du démarrage reste utilisé
+		primary, err := open("users", func() string {
+			if dsn := secrets.Get(secretDatabaseURL); dsn != "" {
+				return dsn
+			}
+			return cfg.DatabaseURL
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(cfg.DatabaseReplicaURLs) == 0 {
+			return primary, primaryDB, closeAll, nil
+		}
+
+		replicas := make([]repositories.UserRepository, len(cfg.DatabaseReplicaURLs))
+		for i, dsn := range cfg.DatabaseReplicaURLs {
+			if replicas[i], err = open(fmt.Sprintf("users_replica_%d", i+1), func() string { return dsn }); err != nil {
+				closeAll()
+				return nil, nil, nil, err
+			}
+		}
+		return database.NewReplicaRoutingUserRepository(primary, replicas...), primaryDB, closeAll, nil
+	case config.PersistenceDynamoDB:
+		// Pas d'index plein texte embarqué : chaque instance (Lambda) aurait le sien, jamais à jour
+		client := database.NewDynamoDBClient(database.DynamoDBConfig{
+			Region:      cfg.AWSRegion,
+			Endpoint:    cfg.DynamoDBEndpoint,
+			Table:       cfg.DynamoDBTable,
+			Credentials: awsCredentials(cfg),
+			Clients:     clients,
+		})
+		if cfg.DynamoDBCreateTable {
+			if err := client.EnsureTable(ctx); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		return database.NewDynamoDBUserRepository(client), nil, func() {}, nil
+	default:
+		return withSearchIndex(cfg, database.NewInMemoryUserRepository()), nil, func() {}, nil
+	}
+}
+
+// withSearchIndex ajoute l'index plein texte embarqué aux stockages en mémoire (SEARCH_INDEX=embedded)
+func withSearchIndex(cfg *config.Config, repo repositories.UserRepository) repositories.UserRepository {
+	if cfg.SearchIndex != config.SearchIndexEmbedded {
+		return repo
+	}
+	return database.NewTextIndexedUserRepository(repo)
+}
+
+// newHTTPHandler empile les middlewares HTTP, du plus externe au plus interne :
+// request ID → journal d'accès (ACCESS_LOG) → recovery → en-têtes de sécurité → CORS → CSRF (mode session) → compression
+// → négociation du format → session de lecture → routeur
+func newHTTPHandler(cfg *config.Config, router http.Handler, reporter usecases.ErrorReporter, accessLog io.Writer) http.Handler {
+	var handler http.Handler = withReadSession(router)
+	handler = handlers.Negotiate(handler)
+	handler = handlers.Compress(handler, cfg.CompressionMinSize, handlers.ZstdEncoding, handlers.GzipEncoding)
+	if cfg.SessionCookieName != "" {
+		handler = handlers.CSRF(handler, handlers.CSRFOptions{
+			SessionCookie: cfg.SessionCookieName,
+			Secret:        cfg.CSRFSecret,
+			Secure:        cfg.Environment != config.EnvironmentDevelopment,
+		})
+	}
+	handler = handlers.CORS(handler, handlers.CORSOptions{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowedMethods:   cfg.CORSAllowedMethods,
+		AllowedHeaders:   cfg.CORSAllowedHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	})
+	handler = handlers.SecurityHeaders(handler, handlers.SecurityHeadersOptions{
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		PathPolicies:          map[string]string{"/docs/": cfg.DocsSecurityPolicy},
+	})
+	handler = handlers.Recover(handler, reporter)
+	if accessLog != nil {
+		handler = handlers.AccessLog(handler, accessLog, handlers.AccessLogOptions{
+			Format:      cfg.AccessLogFormat,
+			ErrorBodies: cfg.AccessLogErrorBodies,
+		})
+	}
+	return handlers.WithRequestID(handlers.WithLocale(handlers.WithClient(handler)))
+}
+
+// newAccessLog destination du journal d'accès : nil si ACCESS_LOG est vide
+func newAccessLog(cfg *config.Config) (io.Writer, error) {
+	switch cfg.AccessLog {
+	case "":
+		return nil, nil
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+	file, err := services.NewRotatingFile(services.RotatingFileConfig{
+		Path:       cfg.AccessLog,
+		MaxSize:    int64(cfg.AccessLogMaxSize) << 20,
+		MaxBackups: cfg.AccessLogMaxBackups,
+		MaxAge:     cfg.AccessLogMaxAge,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ACCESS_LOG: %w", err)
+	}
+	return file, nil
+}
+
+// withReadSession ouvre une session de lecture par requête : après une écriture,
+// les lectures de la même requête sont servies par le primaire (pas de lecture obsolète)
+func withReadSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(database.WithReadSession(r.Context())))
+	})
+}
+
+// newHTTPClients fabrique des clients sortants ; les métriques sont publiées sur /debug/vars
+func newHTTPClients(cfg *config.Config, tracer usecases.Tracer) (*httpclient.Factory, error) {
+	return httpclient.NewFactory(httpclient.Config{
+		ProxyURL:   cfg.OutboundProxyURL,
+		MaxRetries: cfg.OutboundMaxRetries,
+		RetryDelay: cfg.OutboundRetryDelay,
+		Tracer:     tracer,
+		Metrics:    services.NewExpvarHTTPClientMetrics(),
+	})
+}
+
+// newErrorReporter signale les erreurs à Sentry si un DSN est fourni, sinon les journalise
+func newErrorReporter(cfg *config.Config, logger usecases.Logger, clients *httpclient.Factory) (usecases.ErrorReporter, error) {
+	if cfg.SentryDSN == "" {
+		return services.NewLogErrorReporter(logger), nil
+	}
+	return services.NewSentryErrorReporter(cfg.SentryDSN, cfg.SentryEnvironment, logger, clients)
+}
+
+// newSMSClient utilise Twilio si les identifiants sont fournis, sinon journalise les SMS
+func newSMSClient(cfg *config.Config, logger usecases.Logger, clients *httpclient.Factory) services.SMSClient {
+	if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" {
+		return services.NewLogSMSClient(logger)
+	}
+	return services.NewTwilioSMSClient(cfg.TwilioAccountSID, cfg.TwilioAuthToken, clients)
+}
+
+// NewCaptchaVerifier vérification des jetons CAPTCHA_PROVIDER (non vide)
+func NewCaptchaVerifier(cfg *config.Config, clients *httpclient.Factory) usecases.CaptchaVerifier {
+	switch cfg.CaptchaProvider {
+	case config.CaptchaViaHCaptcha:
+		return services.NewHCaptchaVerifier(cfg.CaptchaSecret, clients)
+	case config.CaptchaViaTurnstile:
+		return services.NewTurnstileVerifier(cfg.CaptchaSecret, clients)
+	default:
+		return services.NewRecaptchaVerifier(cfg.CaptchaSecret, clients)
+	}
+}
+
+// newContentModeration modération des saisies (MODERATION_PROVIDER) ; nil avec "none"
+func newContentModeration(cfg *config.Config, logger usecases.Logger, clients *httpclient.Factory) (usecases.ContentModeration, error) {
+	if cfg.ModerationProvider == config.ModerationDisabled {
+		return nil, nil
+	}
+	words, err := services.LoadWordListModeration(cfg.ModerationLanguages, cfg.ModerationWordsFile)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ModerationProvider == config.ModerationViaAPI {
+		return services.NewAPIContentModeration(cfg.ModerationAPIURL, cfg.ModerationAPIKey, words, logger, clients), nil
+	}
+	return words, nil
+}
+
+// NewMailProvider fournisseur d'envoi des emails (EMAIL_PROVIDER) ; "log" journalise uniquement
+func NewMailProvider(cfg *config.Config, logger usecases.Logger, clients *httpclient.Factory) usecases.MailProvider {
+	switch cfg.EmailProvider {
+	case config.EmailViaSES:
+		return services.NewSESMailProvider(cfg.AWSRegion, awsCredentials(cfg), clients)
+	case config.EmailViaSendGrid:
+		return services.NewSendGridMailProvider(cfg.SendGridAPIKey, clients)
+	case config.EmailViaMailgun:
+		return services.NewMailgunMailProvider(cfg.MailgunAPIKey, cfg.MailgunDomain, cfg.MailgunAPIURL, clients)
+	default:
+		return services.NewLogMailProvider(logger)
+	}
+}
+
+// tenantQuotas surcharges TENANT_QUOTAS au format du domaine
+func tenantQuotas(raw map[string]map[string]int) map[string]usecases.Quotas {
+	quotas := make(map[string]usecases.Quotas, len(raw))
+	for tenant, limits := range raw {
+		quotas[tenant] = limits
+	}
+	return quotas
+}
+
+// newAuthorizer évalue les politiques du fichier local ou d'OPA ; sans l'un ni l'autre, la politique
+// intégrée (chaque utilisateur sur lui-même, le rôle d'administration sur tous)
+func newAuthorizer(cfg *config.Config, clients *httpclient.Factory, registry *usecases.UseCaseRegistry) (usecases.Authorizer, error) {
+	switch {
+	case cfg.PolicyFile != "":
+		engine, err := services.LoadPolicyFile(cfg.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+		return usecases.NewPolicyAuthorizer(engine, registry), nil
+	case cfg.OPAURL != "":
+		return usecases.NewPolicyAuthorizer(services.NewOPAPolicyEngine(cfg.OPAURL, clients), registry), nil
+	default:
+		return usecases.NewPolicyAuthorizer(services.NewDefaultPolicyEngine(cfg.AdminRole), registry), nil
+	}
+}
+
+// newFeatureFlags choisit l'implémentation des feature flags selon la configuration
+// Les règles locales (fichier + env) servent de fallback au service distant
+// Les règles locales sont aussi retournées seules, pour être rechargées à chaud
+func newFeatureFlags(ctx context.Context, cfg *config.Config, logger usecases.Logger, clients *httpclient.Factory) (usecases.FeatureFlags, *services.StaticFeatureFlags, error) {
+	local, err := services.LoadStaticFeatureFlags(cfg.FeatureFlagsFile, os.Environ())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.FeatureFlagsURL == "" {
+		return local, local, nil
+	}
+
+	remote := services.NewRemoteFeatureFlags(cfg.FeatureFlagsURL, cfg.FeatureFlagsRefresh, local, logger, clients)
+	remote.Start(ctx)
+	return remote, local, nil
+}
@@ -0,0 +1,96 @@
+package bootstrap
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/awssig"
+	"clean-archi-analytics/internal/config"
+	"clean-archi-analytics/internal/domain/usecases"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Secrets lus auprès de SECRETS_PROVIDER plutôt que dans la configuration, puis relus
+// toutes les SECRETS_RELOAD_INTERVAL pour suivre les rotations
+const (
+	secretJWT            = "JWT_SECRET"
+	secretJWTSigningKeys = "JWT_SIGNING_KEYS"
+	secretDatabaseURL    = "DATABASE_URL"
+	secretAnonymization  = "ANONYMIZATION_KEY"
+)
+
+func newSecretsProvider(cfg *config.Config, clients *httpclient.Factory) (services.SecretsProvider, error) {
+	switch cfg.SecretsProvider {
+	case config.SecretsFromFile:
+		return services.NewFileSecretsProvider(cfg.SecretsDir), nil
+	case config.SecretsFromVault:
+		return services.NewVaultSecretsProvider(services.VaultConfig{
+			Address:   cfg.VaultAddr,
+			Token:     cfg.VaultToken,
+			Namespace: cfg.VaultNamespace,
+			Mount:     cfg.VaultKVMount,
+			Path:      cfg.VaultSecretPath,
+			Clients:   clients,
+		})
+	case config.SecretsFromSSM:
+		return services.NewSSMSecretsProvider(awsSecretsConfig(cfg, clients))
+	case config.SecretsFromSecretsManager:
+		return services.NewSecretsManagerSecretsProvider(awsSecretsConfig(cfg, clients))
+	default:
+		return services.NewEnvSecretsProvider(), nil
+	}
+}
+
+// LoadSecrets lit les secrets gérés et les reporte dans cfg, qui reste la configuration
+// effective au démarrage ; le magasin retourné porte les valeurs rechargées ensuite.
+// clients peut être nil (appels sans instrumentation)
+func LoadSecrets(ctx context.Context, cfg *config.Config, logger usecases.Logger, clients *httpclient.Factory) (*services.SecretStore, error) {
+	provider, err := newSecretsProvider(cfg, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := services.NewSecretStore(provider, logger, secretJWT, secretJWTSigningKeys, secretDatabaseURL, secretAnonymization)
+	if err := secrets.Load(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.SecretsProvider, err)
+	}
+
+	cfg.JWTSecret = secrets.Get(secretJWT)
+	cfg.JWTSigningKeys = secrets.Get(secretJWTSigningKeys)
+	cfg.DatabaseURL = secrets.Get(secretDatabaseURL)
+	cfg.AnonymizationKey = secrets.Get(secretAnonymization)
+	if cfg.PersistenceMode == config.PersistenceSQL && cfg.DatabaseURL == "" {
+		return nil, errors.New("DATABASE_URL: obligatoire en mode \"sql\"")
+	}
+	return secrets, nil
+}
+
+// rotateJWTKeys installe le jeu de clés rechargé ; un jeu invalide est ignoré (l'ancien reste actif)
+func rotateJWTKeys(secrets *services.SecretStore, tokenService *services.HS256TokenService, logger usecases.Logger) func(name string) {
+	return func(name string) {
+		if name != secretJWT && name != secretJWTSigningKeys {
+			return
+		}
+		keys, err := services.ParseJWTKeySet(secrets.Get(secretJWTSigningKeys), secrets.Get(secretJWT))
+		if err != nil {
+			logger.Error("Rotated JWT keys rejected, keeping previous keys", err, nil)
+			return
+		}
+		tokenService.SetKeys(keys)
+		logger.Info("JWT keys rotated", map[string]interface{}{"active_kid": keys.ActiveKID()})
+	}
+}
+
+func awsSecretsConfig(cfg *config.Config, clients *httpclient.Factory) services.AWSSecretsConfig {
+	return services.AWSSecretsConfig{Region: cfg.AWSRegion, Credentials: awsCredentials(cfg), Path: cfg.AWSSecretsPath, Clients: clients}
+}
+
+// awsCredentials identifiants des appels AWS ; sous Lambda, ceux du rôle d'exécution (jeton de session)
+func awsCredentials(cfg *config.Config) awssig.Credentials {
+	return awssig.Credentials{
+		AccessKeyID:     cfg.AWSAccessKeyID,
+		SecretAccessKey: cfg.AWSSecretAccessKey,
+		SessionToken:    cfg.AWSSessionToken,
+	}
+}
@@ -0,0 +1,102 @@
+package bootstrap
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/config"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"maps"
+	"os"
+	"sync"
+	"time"
+)
+
+// settingsReloader applique à chaud les réglages sans risque : niveau de log, quotas et
+// feature flags locaux. Le reste de la configuration ne change qu'au redémarrage
+type settingsReloader struct {
+	cfg    *config.Config
+	logger usecases.Logger
+	// logs nil si le logger de l'application hôte n'est pas pilotable
+	logs  usecases.LogLevelController
+	meter *usecases.UsageMeter
+	flags *services.StaticFeatureFlags
+
+	mutex    sync.Mutex
+	current  config.Settings
+	modTimes map[string]time.Time
+}
+
+func newSettingsReloader(cfg *config.Config, logger usecases.Logger, logs usecases.LogLevelController, meter *usecases.UsageMeter, flags *services.StaticFeatureFlags) *settingsReloader {
+	r := &settingsReloader{
+		cfg:     cfg,
+		logger:  logger,
+		logs:    logs,
+		meter:   meter,
+		flags:   flags,
+		current: config.Settings{LogLevel: cfg.LogLevel, Quotas: cfg.Quotas, TenantQuotas: cfg.TenantQuotas},
+	}
+	r.modTimes = r.stat()
+	return r
+}
+
+// Reload relit SETTINGS_FILE et FEATURE_FLAGS_FILE ; un fichier invalide laisse tous les réglages en place
+func (r *settingsReloader) Reload(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.modTimes = r.stat()
+
+	settings, err := config.LoadSettings(r.cfg.SettingsFile)
+	if err != nil {
+		r.logger.Error("Settings reload failed", err, map[string]interface{}{"file": r.cfg.SettingsFile})
+		return err
+	}
+	if err := r.flags.Reload(r.cfg.FeatureFlagsFile, os.Environ()); err != nil {
+		r.logger.Error("Settings reload failed", err, map[string]interface{}{"file": r.cfg.FeatureFlagsFile})
+		return err
+	}
+
+	applied := []string{"FEATURE_FLAGS_FILE"}
+	// Niveau réappliqué seulement s'il a changé dans le fichier : celui fixé par PUT /admin/log-level tient sinon
+	if r.logs != nil && settings.LogLevel != r.current.LogLevel {
+		if err := r.logs.SetLogLevel(settings.LogLevel); err != nil {
+			return err
+		}
+		applied = append(applied, "LOG_LEVEL")
+	}
+	sameTenants := maps.EqualFunc(settings.TenantQuotas, r.current.TenantQuotas, func(a, b map[string]int) bool {
+		return maps.Equal(a, b)
+	})
+	if !maps.Equal(settings.Quotas, r.current.Quotas) || !sameTenants {
+		r.meter.SetPolicy(usecases.QuotaPolicy{Default: settings.Quotas, Tenants: tenantQuotas(settings.TenantQuotas)})
+		applied = append(applied, "QUOTAS")
+	}
+	r.current = settings
+
+	r.logger.Info("Settings reloaded", map[string]interface{}{"reloaded": applied})
+	return nil
+}
+
+// Watch relit les réglages quand SETTINGS_FILE ou FEATURE_FLAGS_FILE a été modifié (appelé périodiquement)
+func (r *settingsReloader) Watch(ctx context.Context) {
+	r.mutex.Lock()
+	modified := !maps.Equal(r.stat(), r.modTimes)
+	r.mutex.Unlock()
+	if modified {
+		// Erreur journalisée par Reload
+		_ = r.Reload(ctx)
+	}
+}
+
+// stat dates de modification des fichiers présents (un fichier supprimé compte comme modifié)
+func (r *settingsReloader) stat() map[string]time.Time {
+	modTimes := make(map[string]time.Time, 2)
+	for _, path := range []string{r.cfg.SettingsFile, r.cfg.FeatureFlagsFile} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			modTimes[path] = info.ModTime()
+		}
+	}
+	return modTimes
+}
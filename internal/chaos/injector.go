@@ -0,0 +1,95 @@
+// Package chaos injecte des pannes (latence, erreurs, délais dépassés) devant les dépôts, l'email
+// et le bus d'événements, pour éprouver timeouts, reprises et dégradations hors production
+package chaos
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// Cibles des décorateurs, reprises par CHAOS_TARGETS
+const (
+	TargetUsers  = "users"
+	TargetEvents = "events"
+	TargetEmail  = "email"
+	TargetBus    = "bus"
+)
+
+// Targets toutes les cibles connues
+var Targets = []string{TargetUsers, TargetEvents, TargetEmail, TargetBus}
+
+var (
+	// ErrInjectedFault erreur retournée à la place de l'appel
+	ErrInjectedFault = errors.New("panne injectée")
+	// ErrInjectedTimeout appel bloqué sans échéance dans le contexte, relâché après maxHang
+	ErrInjectedTimeout = errors.New("délai dépassé (panne injectée)")
+)
+
+// maxHang durée d'un appel bloqué quand le contexte n'a pas d'échéance
+const maxHang = 30 * time.Second
+
+// Config pannes appliquées à chaque appel des cibles
+//   - Latency ajoutée avant l'appel (interrompue par l'annulation du contexte)
+//   - ErrorRate part des appels remplacés par ErrInjectedFault, entre 0 et 1
+//   - TimeoutRate part des appels bloqués jusqu'à l'échéance du contexte, entre 0 et 1
+//   - Targets cibles touchées ; vide = toutes
+type Config struct {
+	Latency     time.Duration
+	ErrorRate   float64
+	TimeoutRate float64
+	Targets     []string
+}
+
+// Injector tire les pannes ; chaque panne injectée est journalisée pour la distinguer d'une vraie
+type Injector struct {
+	config Config
+	logger usecases.Logger
+}
+
+func NewInjector(config Config, logger usecases.Logger) *Injector {
+	return &Injector{config: config, logger: logger}
+}
+
+// Inject à appeler avant l'opération décorée : nil si l'appel doit avoir lieu
+func (i *Injector) Inject(ctx context.Context, target, operation string) error {
+	if len(i.config.Targets) > 0 && !slices.Contains(i.config.Targets, target) {
+		return nil
+	}
+
+	if i.config.Latency > 0 {
+		timer := time.NewTimer(i.config.Latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	draw := rand.Float64()
+	switch {
+	case draw < i.config.ErrorRate:
+		i.report(target, operation, "error")
+		return fmt.Errorf("%w : %s.%s", ErrInjectedFault, target, operation)
+	case draw < i.config.ErrorRate+i.config.TimeoutRate:
+		i.report(target, operation, "timeout")
+		timer := time.NewTimer(maxHang)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return fmt.Errorf("%w : %s.%s", ErrInjectedTimeout, target, operation)
+		}
+	}
+	return nil
+}
+
+func (i *Injector) report(target, operation, fault string) {
+	i.logger.Warn("Fault injected", map[string]interface{}{"target": target, "operation": operation, "fault": fault})
+}
@@ -0,0 +1,204 @@
+package chaos
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"time"
+)
+
+// =============================================================================
+// DÉPÔT UTILISATEURS
+// =============================================================================
+
+// UserRepository décore un repositories.UserRepository : chaque appel passe d'abord par l'injecteur
+type UserRepository struct {
+	next     repositories.UserRepository
+	injector *Injector
+}
+
+func NewUserRepository(next repositories.UserRepository, injector *Injector) *UserRepository {
+	return &UserRepository{next: next, injector: injector}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "Create"); err != nil {
+		return nil, err
+	}
+	return r.next.Create(ctx, user)
+}
+
+func (r *UserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "GetById"); err != nil {
+		return nil, err
+	}
+	return r.next.GetById(ctx, id)
+}
+
+func (r *UserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "GetByIds"); err != nil {
+		return nil, err
+	}
+	return r.next.GetByIds(ctx, ids)
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "GetByEmail"); err != nil {
+		return nil, err
+	}
+	return r.next.GetByEmail(ctx, email)
+}
+
+func (r *UserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "IsEmailTaken"); err != nil {
+		return false, err
+	}
+	return r.next.IsEmailTaken(ctx, email)
+}
+
+func (r *UserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "GetByHandle"); err != nil {
+		return nil, err
+	}
+	return r.next.GetByHandle(ctx, handle)
+}
+
+func (r *UserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "IsHandleTaken"); err != nil {
+		return false, err
+	}
+	return r.next.IsHandleTaken(ctx, handle)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "Update"); err != nil {
+		return nil, err
+	}
+	return r.next.Update(ctx, user)
+}
+
+func (r *UserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "UpdateIfUnchanged"); err != nil {
+		return nil, err
+	}
+	return r.next.UpdateIfUnchanged(ctx, user, expectedUpdated)
+}
+
+func (r *UserRepository) DeleteById(ctx context.Context, id int) error {
+	if err := r.injector.Inject(ctx, TargetUsers, "DeleteById"); err != nil {
+		return err
+	}
+	return r.next.DeleteById(ctx, id)
+}
+
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "List"); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, limit, offset)
+}
+
+func (r *UserRepository) Count(ctx context.Context) (int, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "Count"); err != nil {
+		return 0, err
+	}
+	return r.next.Count(ctx)
+}
+
+func (r *UserRepository) EstimateCount(ctx context.Context) (int, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "EstimateCount"); err != nil {
+		return 0, err
+	}
+	return r.next.EstimateCount(ctx)
+}
+
+func (r *UserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "ListInactiveSince"); err != nil {
+		return nil, err
+	}
+	return r.next.ListInactiveSince(ctx, cutoff, limit, offset)
+}
+
+func (r *UserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	if err := r.injector.Inject(ctx, TargetUsers, "Each"); err != nil {
+		return err
+	}
+	return r.next.Each(ctx, filters, fn)
+}
+
+// Search n'est disponible que si le dépôt décoré implémente UserSearchRepository
+func (r *UserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	searcher, ok := r.next.(repositories.UserSearchRepository)
+	if !ok {
+		return nil, repositories.ErrSearchNotSupported
+	}
+	if err := r.injector.Inject(ctx, TargetUsers, "Search"); err != nil {
+		return nil, err
+	}
+	return searcher.Search(ctx, filters)
+}
+
+// SearchWithFacets se replie sur Search si le dépôt décoré n'est pas un moteur de recherche dédié
+func (r *UserRepository) SearchWithFacets(ctx context.Context, filters repositories.UserRepositoryFilters) (*repositories.UserSearchResult, error) {
+	faceted, ok := r.next.(repositories.UserFacetedSearchRepository)
+	if !ok {
+		users, err := r.Search(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		return &repositories.UserSearchResult{Users: users}, nil
+	}
+	if err := r.injector.Inject(ctx, TargetUsers, "SearchWithFacets"); err != nil {
+		return nil, err
+	}
+	return faceted.SearchWithFacets(ctx, filters)
+}
+
+func (r *UserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "CreateMany"); err != nil {
+		return nil, err
+	}
+	return r.next.CreateMany(ctx, users)
+}
+
+func (r *UserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if err := r.injector.Inject(ctx, TargetUsers, "UpdateMany"); err != nil {
+		return nil, err
+	}
+	return r.next.UpdateMany(ctx, users)
+}
+
+func (r *UserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	if err := r.injector.Inject(ctx, TargetUsers, "DeleteByIds"); err != nil {
+		return err
+	}
+	return r.next.DeleteByIds(ctx, ids)
+}
+
+// =============================================================================
+// DÉPÔT D'ÉVÉNEMENTS ANALYTICS
+// =============================================================================
+
+// EventRepository décore un repositories.EventRepository (ingestion et lecture des événements suivis)
+type EventRepository struct {
+	next     repositories.EventRepository
+	injector *Injector
+}
+
+func NewEventRepository(next repositories.EventRepository, injector *Injector) *EventRepository {
+	return &EventRepository{next: next, injector: injector}
+}
+
+func (r *EventRepository) Append(ctx context.Context, events []*entities.TrackedEvent) error {
+	if err := r.injector.Inject(ctx, TargetEvents, "Append"); err != nil {
+		return err
+	}
+	return r.next.Append(ctx, events)
+}
+
+func (r *EventRepository) List(ctx context.Context, filters repositories.EventFilters) ([]*entities.TrackedEvent, error) {
+	if err := r.injector.Inject(ctx, TargetEvents, "List"); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, filters)
+}
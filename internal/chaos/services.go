@@ -0,0 +1,65 @@
+package chaos
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+)
+
+// =============================================================================
+// EMAIL
+// =============================================================================
+
+// EmailSender décore un usecases.EmailSender : un envoi en panne retourne l'erreur à l'appelant
+// (tâche asynchrone de bienvenue, dispatcher de l'outbox et ses reprises)
+type EmailSender struct {
+	next     usecases.EmailSender
+	injector *Injector
+}
+
+func NewEmailSender(next usecases.EmailSender, injector *Injector) *EmailSender {
+	return &EmailSender{next: next, injector: injector}
+}
+
+func (s *EmailSender) SendWelcomeEmail(ctx context.Context, email, name string) error {
+	if err := s.injector.Inject(ctx, TargetEmail, "SendWelcomeEmail"); err != nil {
+		return err
+	}
+	return s.next.SendWelcomeEmail(ctx, email, name)
+}
+
+func (s *EmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	if err := s.injector.Inject(ctx, TargetEmail, "SendEmail"); err != nil {
+		return err
+	}
+	return s.next.SendEmail(ctx, to, subject, body)
+}
+
+func (s *EmailSender) Send(ctx context.Context, mail usecases.Mail) error {
+	if err := s.injector.Inject(ctx, TargetEmail, "Send"); err != nil {
+		return err
+	}
+	return s.next.Send(ctx, mail)
+}
+
+// =============================================================================
+// BUS D'ÉVÉNEMENTS
+// =============================================================================
+
+// EventPublisher décore un usecases.EventPublisher : Publish ne retourne pas d'erreur, une panne
+// perd donc les événements (projections et compteurs en retard), comme un bus indisponible
+type EventPublisher struct {
+	next     usecases.EventPublisher
+	injector *Injector
+}
+
+func NewEventPublisher(next usecases.EventPublisher, injector *Injector) *EventPublisher {
+	return &EventPublisher{next: next, injector: injector}
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, evts ...events.Event) {
+	if err := p.injector.Inject(ctx, TargetBus, "Publish"); err != nil {
+		return
+	}
+	p.next.Publish(ctx, evts...)
+}
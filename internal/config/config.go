@@ -0,0 +1,1564 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Modes de persistance des utilisateurs
+const (
+	PersistenceState        = "state"         // table d'état classique
+	PersistenceEventSourced = "event_sourced" // flux d'événements + snapshots
+	PersistenceSQL          = "sql"           // base SQL via database/sql
+	PersistenceDynamoDB     = "dynamodb"      // table DynamoDB unique (déploiements serverless)
+)
+
+// Adaptateurs du dépôt des utilisateurs en mode "sql" (mêmes tables, mêmes migrations)
+const (
+	SQLAdapterDatabaseSQL = "sql" // requêtes sqlc et database/sql
+	SQLAdapterEnt         = "ent" // client ent généré depuis internal/infra/database/ent/schema
+)
+
+// Index plein texte des utilisateurs hors mode "sql" (PostgreSQL indexe lui-même, voir migration 0006)
+const (
+	SearchIndexEmbedded = "embedded" // index inversé en mémoire, maintenu à chaque écriture
+	SearchIndexNone     = "none"     // recherche par critères uniquement
+)
+
+// Totaux des listes d'utilisateurs
+const (
+	ListTotalsExact     = "exact"     // COUNT(*) à chaque page
+	ListTotalsEstimated = "estimated" // statistiques du stockage (pg_class en SQL), total approché
+)
+
+// Sources des secrets (clés JWT, DSN SQL)
+const (
+	SecretsFromEnv   = "env"
+	SecretsFromFile  = "file"
+	SecretsFromVault = "vault"
+	// SecretsFromSSM un paramètre SecureString par secret sous AWSSecretsPath
+	SecretsFromSSM = "ssm"
+	// SecretsFromSecretsManager un secret JSON AWSSecretsPath regroupant les secrets
+	SecretsFromSecretsManager = "secretsmanager"
+)
+
+// Fournisseurs d'envoi des emails
+const (
+	EmailViaLog      = "log" // journalisés uniquement (développement)
+	EmailViaSES      = "ses" // Amazon SES v2, région et identifiants AWS_*
+	EmailViaSendGrid = "sendgrid"
+	EmailViaMailgun  = "mailgun"
+)
+
+// Fournisseurs de CAPTCHA
+const (
+	CaptchaViaRecaptcha = "recaptcha" // reCAPTCHA v2 ou v3 (score)
+	CaptchaViaHCaptcha  = "hcaptcha"
+	CaptchaViaTurnstile = "turnstile" // Cloudflare Turnstile
+)
+
+// Fournisseurs de modération des contenus
+const (
+	ModerationViaWordList = "wordlist" // listes de mots intégrées (MODERATION_LANGUAGES) et MODERATION_WORDS_FILE
+	ModerationViaAPI      = "api"      // service externe (MODERATION_API_URL), listes de mots en secours
+	ModerationDisabled    = "none"
+)
+
+// MinWebhookSecretLength longueur minimale d'un secret de WEBHOOK_SECRETS (HMAC-SHA256) :
+// "payments=" ou un secret trivial rendraient la signature devinable
+const MinWebhookSecretLength = 16
+
+// Formats des archives d'événements analytics
+const (
+	ArchiveFormatNDJSON = "ndjson" // un objet JSON par ligne
+)
+
+// Environnements d'exécution : ils fixent les valeurs par défaut sensibles (HSTS...)
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentStaging     = "staging"
+	EnvironmentProduction  = "production"
+)
+
+// chaosTargets cibles de l'injection de pannes (voir internal/chaos)
+var chaosTargets = []string{"users", "events", "email", "bus"}
+
+// Config regroupe la configuration de l'application, lue depuis l'environnement
+type Config struct {
+	HTTPAddr string
+
+	// Environment "development" (défaut), "staging" ou "production"
+	Environment string
+
+	// PersistenceMode "state" (défaut), "event_sourced", "sql" ou "dynamodb"
+	PersistenceMode string
+
+	// SQLAdapter "sql" (défaut) ou "ent" : implémentation du dépôt des utilisateurs (mode "sql")
+	SQLAdapter string
+	// DynamoDBTable table unique des utilisateurs (obligatoire en mode "dynamodb")
+	DynamoDBTable string
+	// DynamoDBEndpoint vide : point d'accès régional ; sinon DynamoDB Local (http://localhost:8000)
+	DynamoDBEndpoint string
+	// DynamoDBCreateTable crée la table et ses index au démarrage s'ils n'existent pas (développement)
+	DynamoDBCreateTable bool
+	// AWSRegion / AWSAccessKeyID / AWSSecretAccessKey / AWSSessionToken région et identifiants des appels
+	// AWS (DynamoDB, SSM, Secrets Manager) ; fournis par le runtime sur Lambda
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+	// DatabaseDriver nom du driver database/sql enregistré dans le binaire (mode "sql")
+	DatabaseDriver string
+	// DatabaseURL DSN de la base (obligatoire en mode "sql") ; géré par SecretsProvider
+	DatabaseURL string
+	// DatabaseReplicaURLs DSN des réplicas en lecture (mode "sql", liste séparée par des virgules)
+	DatabaseReplicaURLs []string
+	// DBMaxOpenConns / DBMaxIdleConns taille du pool de connexions (2 par défaut sous Lambda :
+	// une instance ne traite qu'une invocation à la fois)
+	DBMaxOpenConns int
+	DBMaxIdleConns int
+	// DBConnMaxLifetime / DBConnMaxIdleTime recyclage des connexions (failover, load balancers)
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+	// DBLazyConnect aucune connexion au démarrage, la première requête l'ouvre (défaut sous Lambda :
+	// le démarrage à froid n'attend pas la base)
+	DBLazyConnect bool
+	// LambdaRuntimeAPI point d'accès de l'API Runtime, posé par Lambda ; non vide = exécution sous Lambda
+	LambdaRuntimeAPI string
+	// SnapshotEvery nombre d'événements entre deux snapshots en mode event-sourcé
+	SnapshotEvery int
+	// SlowQueryThreshold durée au-delà de laquelle un appel au repository est signalé (0 = désactivé)
+	SlowQueryThreshold time.Duration
+	// SearchIndex "embedded" (défaut) ou "none" : recherche plein texte (?q=) des modes "state" et "event_sourced"
+	SearchIndex string
+	// ListTotals "exact" (défaut) ou "estimated" : total de GET /users et HEAD /users sans filtre de statut
+	ListTotals string
+	// ElasticsearchURL cluster Elasticsearch/OpenSearch recevant utilisateurs et événements ; vide = désactivé
+	// Avec un cluster, la recherche d'utilisateurs et GET /analytics/events l'interrogent
+	ElasticsearchURL string
+	// ElasticsearchUsername / ElasticsearchPassword authentification basique ; ElasticsearchAPIKey la remplace
+	ElasticsearchUsername string
+	ElasticsearchPassword string
+	ElasticsearchAPIKey   string
+	// ElasticsearchIndex préfixe des index "<prefix>-users" et "<prefix>-events"
+	ElasticsearchIndex string
+
+	// FeatureFlagsFile fichier JSON optionnel des règles de feature flags
+	FeatureFlagsFile string
+	// FeatureFlagsURL service de flags distant ; vide = règles locales uniquement
+	FeatureFlagsURL string
+	// FeatureFlagsRefresh intervalle de synchronisation avec le service distant
+	FeatureFlagsRefresh time.Duration
+
+	// LogLevel "debug", "info" (défaut), "warn" ou "error" ; modifiable à chaud (PUT /admin/log-level)
+	LogLevel string
+	// SettingsFile fichier KEY=valeur surchargeant les réglages rechargeables (voir LoadSettings),
+	// relu avec FEATURE_FLAGS_FILE sur SIGHUP ou quand l'un des deux change
+	SettingsFile string
+	// SettingsWatchInterval fréquence de vérification des deux fichiers (0 = SIGHUP uniquement)
+	SettingsWatchInterval time.Duration
+
+	// AccessLog journal d'accès HTTP, distinct des logs applicatifs : "stdout", "stderr" ou chemin
+	// d'un fichier archivé selon AccessLogMaxSize / AccessLogMaxBackups / AccessLogMaxAge ; vide = désactivé
+	AccessLog string
+	// AccessLogFormat "combined" (défaut, format Apache) ou "json"
+	AccessLogFormat string
+	// AccessLogMaxSize taille (Mio) déclenchant l'archivage du fichier (0 = jamais)
+	AccessLogMaxSize int
+	// AccessLogMaxBackups archives conservées (0 = toutes)
+	AccessLogMaxBackups int
+	// AccessLogMaxAge âge au-delà duquel les archives sont supprimées (0 = aucune limite)
+	AccessLogMaxAge time.Duration
+	// AccessLogErrorBodies ajoute le corps JSON (champs sensibles masqués) des requêtes en erreur 4xx/5xx
+	AccessLogErrorBodies bool
+
+	// DigestInterval période du job de résumé hebdomadaire
+	DigestInterval time.Duration
+	// OutboxPollInterval fréquence de relève de l'outbox
+	OutboxPollInterval time.Duration
+	// OutboxMaxAttempts nombre de tentatives avant abandon d'un message
+	OutboxMaxAttempts int
+	// DLQMetricsInterval fréquence de mesure de la profondeur des files d'abandonnés (0 = jamais)
+	DLQMetricsInterval time.Duration
+	// InactivityCheckInterval période du job de relance / signalement des comptes inactifs
+	InactivityCheckInterval time.Duration
+	// ReengagementAfter inactivité déclenchant un email de relance (0 = pas de relance)
+	ReengagementAfter time.Duration
+	// CleanupFlagAfter inactivité au-delà de laquelle le compte est signalé pour nettoyage (0 = jamais)
+	CleanupFlagAfter time.Duration
+
+	// HRSyncURL API du SIRH dont les comptes sont synchronisés ; vide = synchronisation désactivée
+	HRSyncURL   string
+	HRSyncToken string
+	// HRSyncInterval période de la synchronisation planifiée (0 = à la demande uniquement)
+	HRSyncInterval time.Duration
+	// HRSyncConflictPolicy "external_wins" (défaut) ou "local_wins"
+	HRSyncConflictPolicy string
+	// HRSyncDryRun les exécutions planifiées produisent le rapport sans rien écrire
+	HRSyncDryRun bool
+
+	// OnboardingConfirmTTL délai laissé pour confirmer une inscription avant son annulation
+	OnboardingConfirmTTL time.Duration
+	// OnboardingCheckInterval période du job qui annule les inscriptions expirées
+	OnboardingCheckInterval time.Duration
+	// OnboardingAdminEmails destinataires de l'avis de nouvelle inscription (liste séparée par des virgules)
+	OnboardingAdminEmails []string
+	// WorkspaceURL API de provisioning des espaces analytics ; vide = espaces tenus en mémoire
+	WorkspaceURL   string
+	WorkspaceToken string
+
+	// WebhookSecrets secret HMAC par source de webhook (ex: "payments")
+	WebhookSecrets map[string]string
+	// WebhookTolerance écart maximal accepté entre le timestamp signé et l'heure serveur
+	WebhookTolerance time.Duration
+	// WebhookRetention durée pendant laquelle les IDs d'événements sont gardés pour la déduplication
+	WebhookRetention time.Duration
+	// WebhookMaxFailures échecs de traitement d'un événement avant son abandon (file des abandonnés)
+	WebhookMaxFailures int
+
+	// AuditRetention durée de conservation du journal d'audit (0 = indéfinie) : classe audit_logs
+	// de la rétention, sauf si RETENTION_TTLS la fixe
+	AuditRetention time.Duration
+
+	// RetentionTTLs durée de conservation par classe de données, "sessions=2160h,raw_events=8760h" :
+	// users (comptes désactivés), sessions, audit_logs, raw_events, rollups ; classe absente = conservée
+	RetentionTTLs map[string]time.Duration
+	// RetentionArchive classes écrites dans le stockage de fichiers (retention-...) avant suppression
+	RetentionArchive []string
+	// RetentionInterval période du job de rétention (0 = désactivé) ; RetentionBatchSize éléments
+	// supprimés par lot, RetentionMaxBatches lots par classe et par exécution (le reste attend la suivante)
+	RetentionInterval   time.Duration
+	RetentionBatchSize  int
+	RetentionMaxBatches int
+
+	// EventArchiveAfter âge (horodatage client) au-delà duquel les événements analytics passent du
+	// stockage chaud aux archives, un fichier par lot et par jour UTC (0 = archivage désactivé) ;
+	// plus court que raw_events de RETENTION_TTLS, sans quoi la rétention supprime avant l'archivage
+	EventArchiveAfter time.Duration
+	// EventArchiveInterval période du job d'archivage ; EventArchiveBatchSize événements par fichier,
+	// EventArchiveMaxDays jours archivés par exécution (le reste attend la suivante)
+	EventArchiveInterval  time.Duration
+	EventArchiveBatchSize int
+	EventArchiveMaxDays   int
+	// EventArchiveFormat format des nouvelles archives ; la restauration lit tous les formats
+	EventArchiveFormat string
+	// EventArchiveRestoreHold délai pendant lequel un jour restauré n'est pas réarchivé
+	EventArchiveRestoreHold time.Duration
+	// EventArchiveS3Bucket archives dans un bucket S3 (identifiants AWS_*) plutôt que dans UPLOAD_DIR ;
+	// EventArchiveS3Endpoint service compatible S3 (MinIO, R2...) à la place d'AWS, EventArchiveS3Prefix
+	// préfixe des clés
+	EventArchiveS3Bucket   string
+	EventArchiveS3Endpoint string
+	EventArchiveS3Prefix   string
+
+	// Quotas limite par ressource (api_calls par mois, users, events) appliquée à chaque tenant,
+	// lue au format "api_calls=100000,users=1000" ; absente ou 0 = illimitée
+	Quotas map[string]int
+	// TenantQuotas surcharges par tenant, au format "acme:users=5000,events=0;beta:api_calls=500"
+	TenantQuotas map[string]map[string]int
+
+	// StripeSecretKey clé secrète de l'API Stripe ; vide = facturation désactivée
+	StripeSecretKey string
+	// StripeAPIURL surchargeable pour stripe-mock ou un proxy sortant
+	StripeAPIURL string
+	// StripeMeters event_name du compteur Stripe par ressource, au format "api_calls=api_calls"
+	StripeMeters map[string]string
+	// BillingPlansFile catalogue JSON des offres (prix Stripe, quotas, feature flags)
+	BillingPlansFile string
+	// BillingUsageInterval période de déclaration de l'usage des tenants abonnés à Stripe
+	BillingUsageInterval time.Duration
+
+	// AnalyticsStreamInterval période d'agrégation des compteurs diffusés sur /analytics/stream
+	AnalyticsStreamInterval time.Duration
+	// AnalyticsMaxEvents noms d'événements distincts acceptés par fenêtre (POST /analytics/track)
+	AnalyticsMaxEvents int
+	// AnalyticsMaxValues valeurs distinctes d'une propriété d'un événement par fenêtre ;
+	// au-delà, les événements passent par le réservoir d'échantillonnage
+	AnalyticsMaxValues int
+	// AnalyticsReservoir événements hors limite retenus par nom et par fenêtre (0 = tous écartés)
+	AnalyticsReservoir int
+	// AnalyticsWindow durée d'une fenêtre : les compteurs de cardinalité repartent de zéro
+	// et les réservoirs sont enregistrés
+	AnalyticsWindow time.Duration
+	// AnalyticsConsentDefault consentement des utilisateurs qui ne se sont pas prononcés :
+	// "granted" (opt-out, défaut) ou "denied" (opt-in)
+	AnalyticsConsentDefault string
+	// AnalyticsWithoutConsent événements des utilisateurs sans consentement : "anonymize"
+	// (défaut, enregistrés sans identifiant ni appareil) ou "drop"
+	AnalyticsWithoutConsent string
+	// EventScrubIPv4Prefix / EventScrubIPv6Prefix bits conservés des adresses IP trouvées dans les
+	// propriétés des événements (24 / 48) ; 32 / 128 = adresses intactes
+	EventScrubIPv4Prefix int
+	EventScrubIPv6Prefix int
+	// EventScrubHashProperties propriétés identifiantes remplacées par une empreinte HMAC (EventScrubKey)
+	EventScrubHashProperties []string
+	// EventScrubDenyProperties propriétés retirées des événements avant stockage
+	EventScrubDenyProperties []string
+	// EventScrubKey clé HMAC des empreintes (32 octets minimum) ; vide = propriétés identifiantes retirées
+	EventScrubKey string
+	// EventBufferSize événements acceptés en attente d'écriture ; au-delà, l'ingestion attend
+	// EventBatchSize taille des écritures groupées ; EventFlushInterval délai maximal avant
+	// écriture (0 = écriture immédiate, sans tampon)
+	EventBufferSize    int
+	EventBatchSize     int
+	EventFlushInterval time.Duration
+
+	// UseCaseTimeout durée maximale d'un use case ; UseCaseTimeouts surcharge par nom ("bulk_create_users=5m")
+	UseCaseTimeout  time.Duration
+	UseCaseTimeouts map[string]time.Duration
+
+	// CacheTTLs durée de vie des résultats par use case de lecture ("get_user=30s") ; 0 = pas de cache
+	// CacheMaxEntries nombre maximal de résultats gardés en mémoire par instance
+	CacheTTLs       map[string]time.Duration
+	CacheMaxEntries int
+
+	// CompressionMinSize taille de corps à partir de laquelle les réponses sont compressées (zstd ou gzip)
+	CompressionMinSize int
+	// IdempotencyTTL durée pendant laquelle la réponse d'un POST est rejouée pour la même
+	// clé Idempotency-Key ; 0 = en-tête ignoré
+	IdempotencyTTL time.Duration
+
+	// CORSAllowedOrigins origines autorisées (exactes, "https://*.example.com" ou "*") ; vide = CORS désactivé
+	CORSAllowedOrigins []string
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+	// CORSAllowCredentials autorise cookies et en-tête Authorization cross-origin (incompatible avec "*")
+	CORSAllowCredentials bool
+	// CORSMaxAge durée de mise en cache des pré-vols par le navigateur
+	CORSMaxAge time.Duration
+
+	// HSTSMaxAge durée de Strict-Transport-Security (0 = désactivé, défaut hors production/staging)
+	HSTSMaxAge time.Duration
+	// ContentSecurityPolicy CSP des réponses de l'API ; DocsSecurityPolicy CSP de /docs/ (Swagger UI)
+	ContentSecurityPolicy string
+	DocsSecurityPolicy    string
+
+	// SessionCookieName cookie HttpOnly posé à la connexion pour les clients navigateur, accepté à défaut
+	// d'en-tête Bearer et protégé par CSRF ; vide = pas d'authentification par cookie
+	SessionCookieName string
+	// CSRFSecret clé HMAC des jetons CSRF (obligatoire avec SESSION_COOKIE)
+	CSRFSecret string
+
+	// AsyncTaskLimit nombre maximal d'effets de bord asynchrones simultanés (emails de bienvenue...)
+	AsyncTaskLimit int
+
+	// OutboundProxyURL proxy des appels sortants (Stripe, Vault, SIRH...) ; vide = HTTPS_PROXY / NO_PROXY
+	OutboundProxyURL string
+	// OutboundMaxRetries nouveaux essais des appels sortants rejouables (GET, PUT, DELETE, clé d'idempotence)
+	OutboundMaxRetries int
+	// OutboundRetryDelay premier délai entre deux essais, doublé ensuite
+	OutboundRetryDelay time.Duration
+
+	// AvailabilityMode "available" (défaut), "read_only" (écritures refusées) ou "maintenance" (tout refusé) ;
+	// modifiable à chaud par PUT /admin/availability et les feature flags ops.*_mode
+	AvailabilityMode string
+	// MaintenanceRetryAfter délai annoncé (Retry-After) aux requêtes refusées
+	MaintenanceRetryAfter time.Duration
+
+	// DebugAddr port interne des diagnostics (pprof, expvar, profils), sans authentification : limité
+	// au loopback (127.0.0.1:6060, [::1]:6060, localhost:6060) sauf DebugAddrPublic.
+	// Vide = servis sous /debug/ du port principal, réservés à ADMIN_ROLE
+	DebugAddr string
+	// DebugAddrPublic accepte une adresse DEBUG_ADDR hors loopback (réseau privé filtré en amont)
+	DebugAddrPublic bool
+	// DiagnosticsDir répertoire des profils écrits sur disque (déclenchés ou capturés automatiquement)
+	DiagnosticsDir string
+	// HeapProfileThreshold taille du tas (Mio) au-delà de laquelle un profil est capturé (0 = désactivé)
+	HeapProfileThreshold int
+	// HeapCheckInterval fréquence de mesure du tas
+	HeapCheckInterval time.Duration
+
+	// SentryDSN projet Sentry recevant les erreurs inattendues ; vide = erreurs journalisées uniquement
+	SentryDSN         string
+	SentryEnvironment string
+
+	// JWTSecret secret HMAC des jetons d'accès ; vide = toute connexion authentifiée est refusée
+	JWTSecret string
+	// JWTSigningKeys clés HMAC identifiées par kid, "kid1=secret1,kid2=secret2" : la première signe,
+	// les suivantes (et JWTSecret) ne font que vérifier pendant une rotation
+	JWTSigningKeys string
+	// AccessTokenTTL durée de validité des jetons émis par POST /auth/login
+	AccessTokenTTL time.Duration
+	// ImpersonationRole rôle (claim "roles" du jeton) requis pour ouvrir une session au nom d'un utilisateur
+	ImpersonationRole string
+	// ImpersonationTTL durée de validité des jetons d'impersonation (non renouvelables)
+	ImpersonationTTL time.Duration
+	// AdminRole rôle requis par les endpoints d'administration de l'instance (/admin/*)
+	AdminRole string
+	// GeoIPDatabase fichier CSV "network,country,city" de localisation des sessions ; vide = désactivée
+	GeoIPDatabase string
+	// SessionHistory sessions conservées par utilisateur (liste des sessions, détection des nouveaux appareils)
+	SessionHistory int
+	// LoginRiskRules issue de chaque règle de risque évaluée à la connexion : "new_country=step_up,
+	// impossible_travel=block,tor=block" ; step_up exige un code envoyé par email. Vide = désactivé
+	LoginRiskRules map[string]string
+	// LoginRiskMaxSpeed vitesse (km/h) au-delà de laquelle un déplacement est jugé impossible
+	LoginRiskMaxSpeed int
+	// LoginChallengeTTL durée de validité du code de vérification d'une connexion à risque
+	LoginChallengeTTL time.Duration
+	// TorExitList fichier ou URL http(s) des nœuds de sortie TOR (une adresse ou un CIDR par ligne)
+	TorExitList string
+	// TorExitListRefresh intervalle de rechargement de TorExitList
+	TorExitListRefresh time.Duration
+	// CaptchaProvider fournisseur du CAPTCHA des use cases publics : "recaptcha", "hcaptcha" ou
+	// "turnstile" ; vide = désactivé. Le navigateur transmet son jeton dans l'en-tête X-Captcha-Token
+	CaptchaProvider string
+	CaptchaSecret   string
+	// CaptchaActions use cases protégés
+	CaptchaActions []string
+	// CaptchaMinScore score minimal (reCAPTCHA v3, hCaptcha Enterprise) ; 0 = ignoré
+	CaptchaMinScore float64
+	// CaptchaThreshold appels tolérés par adresse IP et par CaptchaWindow avant d'exiger le CAPTCHA ;
+	// 0 = toujours exigé. Les nœuds de sortie TOR (TOR_EXIT_LIST) y sont soumis dès le premier appel
+	CaptchaThreshold int
+	CaptchaWindow    time.Duration
+	// CaptchaBypassRoles / CaptchaBypassNetworks clients de confiance dispensés de CAPTCHA : rôle du
+	// jeton (intégrations serveur à serveur) ou réseau d'origine (CIDR)
+	CaptchaBypassRoles    []string
+	CaptchaBypassNetworks []netip.Prefix
+	// ModerationProvider modération des noms et handles saisis : "wordlist" (défaut), "api" ou "none"
+	ModerationProvider string
+	// ModerationLanguages listes de mots intégrées : en, fr, es, de
+	ModerationLanguages []string
+	// ModerationWordsFile mots refusés en plus des listes intégrées, un par ligne
+	ModerationWordsFile string
+	ModerationAPIURL    string
+	ModerationAPIKey    string
+	// SupportRole rôle requis pour consulter la chronologie d'un utilisateur (GET /users/{id}/timeline)
+	SupportRole string
+	// TimelineEvents événements analytics repris dans la chronologie des utilisateurs ("checkout.completed,plan.upgraded")
+	TimelineEvents []string
+
+	// SecretsProvider source de JWT_SECRET, JWT_SIGNING_KEYS, DATABASE_URL et ANONYMIZATION_KEY : "env" (défaut),
+	// "file" (un fichier par secret dans SecretsDir), "vault" (KV v2), "ssm" ou "secretsmanager"
+	SecretsProvider string
+	SecretsDir      string
+	// VaultAddr / VaultToken / VaultNamespace / VaultKVMount / VaultSecretPath secret KV v2
+	// regroupant les secrets de l'API (mode "vault")
+	VaultAddr       string
+	VaultToken      string
+	VaultNamespace  string
+	VaultKVMount    string
+	VaultSecretPath string
+	// AWSSecretsPath préfixe des paramètres (mode "ssm") ou nom du secret (mode "secretsmanager")
+	AWSSecretsPath string
+	// AnonymizationKey clé HMAC des pseudonymes de `api anonymize` (32 octets minimum) ; la même clé
+	// redonne les mêmes pseudonymes d'un export à l'autre, elle ne doit jamais quitter la production
+	AnonymizationKey string
+	// SecretsReloadInterval période de relecture des secrets (rotation sans redémarrage) ; 0 = désactivé
+	SecretsReloadInterval time.Duration
+
+	// LDAPURL annuaire vérifiant les connexions (ldaps://...) ; vide = mots de passe locaux uniquement
+	LDAPURL string
+	// LDAPBindDN / LDAPBindPassword compte de service de recherche ; vides = recherche anonyme
+	LDAPBindDN       string
+	LDAPBindPassword string
+	// LDAPBaseDN racine de la recherche des utilisateurs (obligatoire avec LDAP_URL)
+	LDAPBaseDN string
+	// LDAPUserFilter filtre de recherche, %s = identifiant saisi ("(sAMAccountName=%s)" pour AD)
+	LDAPUserFilter string
+	// LDAPGroupRoles rôle par DN de groupe (en minuscules), lu au format "role=dn_groupe;role2=dn_groupe2"
+	LDAPGroupRoles map[string]string
+	// LDAPLocalFallback les identifiants inconnus de l'annuaire sont vérifiés localement (comptes de secours)
+	LDAPLocalFallback bool
+
+	// SAMLBaseURL URL publique de l'API, préfixe de l'entityID et de l'ACS SAML de chaque tenant
+	SAMLBaseURL string
+
+	// SCIMBearerToken jeton des fournisseurs d'identité sur /scim/v2 ; vide = provisionnement SCIM désactivé
+	SCIMBearerToken string
+
+	// PolicyFile politiques d'autorisation JSON (règles intégrées) ; OPAURL règle de décision OPA
+	// Exclusifs ; tous deux vides = politique intégrée (chaque utilisateur sur lui-même, ADMIN_ROLE sur tous)
+	PolicyFile string
+	OPAURL     string
+
+	// AttributeSchemaFile schémas JSON des attributs personnalisés par tenant ; vide = aucun attribut
+	AttributeSchemaFile string
+	// TermsVersion version en vigueur des conditions d'utilisation ; vide = acceptation non exigée
+	TermsVersion string
+
+	// TwilioAccountSID / TwilioAuthToken identifiants Twilio ; vides = SMS journalisés uniquement
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	// TwilioFrom numéro expéditeur des SMS (format E.164)
+	TwilioFrom string
+
+	// EmailProvider fournisseur des emails : "log" (défaut), "ses", "sendgrid" ou "mailgun" ; vérifié au démarrage
+	EmailProvider string
+	// EmailFrom expéditeur par défaut (obligatoire hors "log")
+	EmailFrom string
+	// EmailWelcomeTemplate modèle de bienvenue hébergé chez le fournisseur ; vide = texte intégré
+	EmailWelcomeTemplate string
+	// SendGridAPIKey clé d'API SendGrid (droit mail.send)
+	SendGridAPIKey string
+	// MailgunAPIKey / MailgunDomain / MailgunAPIURL domaine d'envoi Mailgun ; API UE : https://api.eu.mailgun.net
+	MailgunAPIKey string
+	MailgunDomain string
+	MailgunAPIURL string
+	// BounceAlertThreshold / BounceAlertWindow alerte quand autant de rebonds définitifs (ou de plaintes)
+	// arrivent sur une même fenêtre (POST /webhooks/email) ; 0 = pas d'alerte
+	BounceAlertThreshold int
+	BounceAlertWindow    time.Duration
+	// DisplayLocale / DisplayTimeZone format des dates et des nombres (emails, exports, champs display
+	// de l'API) pour les utilisateurs sans préférence ; fuseau de la base IANA
+	DisplayLocale   string
+	DisplayTimeZone string
+	// DownloadSigningKey clé HMAC des liens de téléchargement signés (/downloads/..., 32 octets minimum) ;
+	// vide = liens désactivés. DownloadLinkTTL durée de validité d'un lien, DownloadBaseURL préfixe
+	// des liens émis (URL publique de l'API ; vide = chemins relatifs)
+	DownloadSigningKey string
+	DownloadLinkTTL    time.Duration
+	DownloadBaseURL    string
+	// UploadDir répertoire des fragments et des fichiers assemblés des uploads fragmentés (POST /uploads)
+	// et des fichiers produits par les exports asynchrones (POST /exports) ;
+	// UploadChunkSizeMB taille des fragments, UploadMaxSizeMB taille maximale d'un fichier (Mio),
+	// UploadTTL délai pour terminer l'envoi puis consommer le fichier, purgé toutes les UploadPurgeInterval
+	UploadDir           string
+	UploadChunkSizeMB   int
+	UploadMaxSizeMB     int
+	UploadTTL           time.Duration
+	UploadPurgeInterval time.Duration
+	// ExportRetention durée de mise à disposition du fichier d'un export terminé (délai pour aboutir
+	// avant) ; ExportPurgeInterval période de la purge des exports expirés
+	ExportRetention     time.Duration
+	ExportPurgeInterval time.Duration
+	// ExportParquetCompression compression par défaut des exports Parquet (snappy, gzip ou none),
+	// modifiable par export ; ExportParquetRowGroupRows lignes par groupe, gardées en mémoire
+	ExportParquetCompression  string
+	ExportParquetRowGroupRows int
+	// CollectRateLimit requêtes par seconde acceptées par clé d'écriture sur /collect/ (0 = illimité),
+	// CollectRateBurst rafale tolérée ; WriteKeyRotationGrace validité de l'ancienne clé après une rotation
+	CollectRateLimit      int
+	CollectRateBurst      int
+	WriteKeyRotationGrace time.Duration
+	// AlertEvaluationInterval période d'évaluation des règles d'alerte sur les métriques (0 = jamais) ;
+	// une règle n'est mesurée qu'une fois sa fenêtre écoulée
+	AlertEvaluationInterval time.Duration
+
+	// ChaosLatency / ChaosErrorRate / ChaosTimeoutRate injection de pannes sur les dépôts, l'email et le bus :
+	// latence ajoutée à chaque appel, part des appels en erreur (0 à 1), part des appels bloqués jusqu'au délai
+	// Refusée avec APP_ENV=production ; toutes à zéro = désactivée
+	ChaosLatency     time.Duration
+	ChaosErrorRate   float64
+	ChaosTimeoutRate float64
+	// ChaosTargets cibles touchées parmi users, events, email et bus ; vide = toutes
+	ChaosTargets []string
+}
+
+// ChaosEnabled au moins une panne configurée (CHAOS_*)
+func (c *Config) ChaosEnabled() bool {
+	return c.ChaosLatency > 0 || c.ChaosErrorRate > 0 || c.ChaosTimeoutRate > 0
+}
+
+// Load lit la configuration depuis les variables d'environnement
+func Load() (*Config, error) {
+	cfg := &Config{
+		HTTPAddr:                getEnv("HTTP_ADDR", ":8080"),
+		Environment:             getEnv("APP_ENV", EnvironmentDevelopment),
+		PersistenceMode:         getEnv("PERSISTENCE_MODE", PersistenceState),
+		SQLAdapter:              getEnv("SQL_ADAPTER", SQLAdapterDatabaseSQL),
+		DynamoDBTable:           os.Getenv("DYNAMODB_TABLE"),
+		DynamoDBEndpoint:        os.Getenv("DYNAMODB_ENDPOINT"),
+		AWSRegion:               getEnv("AWS_REGION", "eu-west-3"),
+		AWSAccessKeyID:          os.Getenv("AWS_ACCESS_KEY_ID"),
+		AWSSecretAccessKey:      os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		AWSSessionToken:         os.Getenv("AWS_SESSION_TOKEN"),
+		DatabaseDriver:          getEnv("DB_DRIVER", "pgx"),
+		DatabaseURL:             os.Getenv("DATABASE_URL"),
+		DatabaseReplicaURLs:     parseList(os.Getenv("DATABASE_REPLICA_URLS")),
+		DBMaxOpenConns:          25,
+		DBMaxIdleConns:          25,
+		DBConnMaxLifetime:       30 * time.Minute,
+		DBConnMaxIdleTime:       5 * time.Minute,
+		LambdaRuntimeAPI:        os.Getenv("AWS_LAMBDA_RUNTIME_API"),
+		SnapshotEvery:           50,
+		SearchIndex:             getEnv("SEARCH_INDEX", SearchIndexEmbedded),
+		ListTotals:              getEnv("LIST_TOTALS", ListTotalsExact),
+		ElasticsearchURL:        os.Getenv("ELASTICSEARCH_URL"),
+		ElasticsearchUsername:   os.Getenv("ELASTICSEARCH_USERNAME"),
+		ElasticsearchPassword:   os.Getenv("ELASTICSEARCH_PASSWORD"),
+		ElasticsearchAPIKey:     os.Getenv("ELASTICSEARCH_API_KEY"),
+		ElasticsearchIndex:      getEnv("ELASTICSEARCH_INDEX_PREFIX", "clean-archi"),
+		FeatureFlagsFile:        os.Getenv("FEATURE_FLAGS_FILE"),
+		FeatureFlagsURL:         os.Getenv("FEATURE_FLAGS_URL"),
+		FeatureFlagsRefresh:     30 * time.Second,
+		SettingsFile:            os.Getenv("SETTINGS_FILE"),
+		SettingsWatchInterval:   10 * time.Second,
+		AccessLog:               os.Getenv("ACCESS_LOG"),
+		AccessLogFormat:         getEnv("ACCESS_LOG_FORMAT", "combined"),
+		AccessLogMaxSize:        100,
+		AccessLogMaxBackups:     7,
+		DigestInterval:          7 * 24 * time.Hour,
+		OutboxPollInterval:      10 * time.Second,
+		OutboxMaxAttempts:       5,
+		DLQMetricsInterval:      time.Minute,
+		InactivityCheckInterval: 24 * time.Hour,
+		ReengagementAfter:       30 * 24 * time.Hour,
+		CleanupFlagAfter:        365 * 24 * time.Hour,
+		HRSyncURL:               os.Getenv("HR_SYNC_URL"),
+		HRSyncToken:             os.Getenv("HR_SYNC_TOKEN"),
+		OnboardingConfirmTTL:    48 * time.Hour,
+		OnboardingCheckInterval: 5 * time.Minute,
+		OnboardingAdminEmails:   parseList(os.Getenv("ONBOARDING_ADMIN_EMAILS")),
+		WorkspaceURL:            os.Getenv("WORKSPACE_PROVISIONER_URL"),
+		WorkspaceToken:          os.Getenv("WORKSPACE_PROVISIONER_TOKEN"),
+		HRSyncInterval:          time.Hour,
+		HRSyncConflictPolicy:    getEnv("HR_SYNC_CONFLICT_POLICY", "external_wins"),
+		WebhookSecrets:          parseKeyValues(os.Getenv("WEBHOOK_SECRETS")),
+		WebhookTolerance:        5 * time.Minute,
+		WebhookRetention:        72 * time.Hour,
+		WebhookMaxFailures:      5,
+		AuditRetention:          365 * 24 * time.Hour,
+		RetentionArchive:        parseList(os.Getenv("RETENTION_ARCHIVE")),
+		RetentionInterval:       time.Hour,
+		RetentionBatchSize:      1000,
+		RetentionMaxBatches:     100,
+		StripeSecretKey:         os.Getenv("STRIPE_SECRET_KEY"),
+		StripeAPIURL:            getEnv("STRIPE_API_URL", "https://api.stripe.com"),
+		StripeMeters:            parseKeyValues(getEnv("STRIPE_METERS", "api_calls=api_calls")),
+		BillingPlansFile:        os.Getenv("BILLING_PLANS_FILE"),
+		BillingUsageInterval:    time.Hour,
+		AnalyticsStreamInterval: time.Second,
+		AnalyticsMaxEvents:      500,
+		AnalyticsMaxValues:      1000,
+		AnalyticsReservoir:      100,
+		AnalyticsWindow:         time.Minute,
+		AnalyticsConsentDefault: getEnv("ANALYTICS_CONSENT_DEFAULT", "granted"),
+		AnalyticsWithoutConsent: getEnv("ANALYTICS_WITHOUT_CONSENT", "anonymize"),
+		EventBufferSize:         10_000,
+		EventBatchSize:          500,
+		EventFlushInterval:      time.Second,
+		UseCaseTimeout:          10 * time.Second,
+		UseCaseTimeouts:         defaultUseCaseTimeouts(),
+		CacheTTLs:               defaultCacheTTLs(),
+		CacheMaxEntries:         10_000,
+		CompressionMinSize:      1024,
+		IdempotencyTTL:          24 * time.Hour,
+		CORSAllowedOrigins:      parseList(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		CORSAllowedMethods:      parseList(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE")),
+		CORSAllowedHeaders:      parseList(getEnv("CORS_ALLOWED_HEADERS", "Authorization,Content-Type,If-Match,If-None-Match,X-Request-ID,X-CSRF-Token,Idempotency-Key,X-Captcha-Token")),
+		CORSMaxAge:              10 * time.Minute,
+		ContentSecurityPolicy:   getEnv("CSP", "default-src 'none'; frame-ancestors 'none'"),
+		DocsSecurityPolicy:      getEnv("CSP_DOCS", "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"),
+		SessionCookieName:       os.Getenv("SESSION_COOKIE"),
+		CSRFSecret:              os.Getenv("CSRF_SECRET"),
+		AsyncTaskLimit:          100,
+		OutboundProxyURL:        os.Getenv("OUTBOUND_PROXY_URL"),
+		OutboundMaxRetries:      2,
+		OutboundRetryDelay:      200 * time.Millisecond,
+		AvailabilityMode:        getEnv("AVAILABILITY_MODE", "available"),
+		MaintenanceRetryAfter:   5 * time.Minute,
+		DebugAddr:               os.Getenv("DEBUG_ADDR"),
+		DiagnosticsDir:          getEnv("DIAGNOSTICS_DIR", filepath.Join(os.TempDir(), "clean-archi-diagnostics")),
+		HeapCheckInterval:       30 * time.Second,
+		SentryDSN:               os.Getenv("SENTRY_DSN"),
+		SentryEnvironment:       os.Getenv("SENTRY_ENVIRONMENT"),
+		JWTSecret:               os.Getenv("JWT_SECRET"),
+		JWTSigningKeys:          os.Getenv("JWT_SIGNING_KEYS"),
+		AccessTokenTTL:          time.Hour,
+		ImpersonationRole:       getEnv("IMPERSONATION_ROLE", "admin"),
+		ImpersonationTTL:        15 * time.Minute,
+		AdminRole:               getEnv("ADMIN_ROLE", "admin"),
+		GeoIPDatabase:           os.Getenv("GEOIP_DATABASE"),
+		SessionHistory:          50,
+		LoginRiskRules:          parseKeyValues(os.Getenv("LOGIN_RISK_RULES")),
+		LoginRiskMaxSpeed:       900,
+		LoginChallengeTTL:       10 * time.Minute,
+		TorExitList:             os.Getenv("TOR_EXIT_LIST"),
+		TorExitListRefresh:      time.Hour,
+		CaptchaProvider:         os.Getenv("CAPTCHA_PROVIDER"),
+		CaptchaSecret:           os.Getenv("CAPTCHA_SECRET"),
+		CaptchaActions:          parseList(getEnv("CAPTCHA_ACTIONS", "create_user")),
+		CaptchaWindow:           time.Hour,
+		CaptchaBypassRoles:      parseList(os.Getenv("CAPTCHA_BYPASS_ROLES")),
+		ModerationProvider:      getEnv("MODERATION_PROVIDER", ModerationViaWordList),
+		ModerationLanguages:     parseList(getEnv("MODERATION_LANGUAGES", "en,fr")),
+		ModerationWordsFile:     os.Getenv("MODERATION_WORDS_FILE"),
+		ModerationAPIURL:        os.Getenv("MODERATION_API_URL"),
+		ModerationAPIKey:        os.Getenv("MODERATION_API_KEY"),
+		SupportRole:             getEnv("SUPPORT_ROLE", "support"),
+		TimelineEvents:          parseList(os.Getenv("TIMELINE_EVENTS")),
+		SecretsProvider:         getEnv("SECRETS_PROVIDER", SecretsFromEnv),
+		SecretsDir:              getEnv("SECRETS_DIR", "/run/secrets"),
+		VaultAddr:               os.Getenv("VAULT_ADDR"),
+		VaultToken:              os.Getenv("VAULT_TOKEN"),
+		VaultNamespace:          os.Getenv("VAULT_NAMESPACE"),
+		VaultKVMount:            getEnv("VAULT_KV_MOUNT", "secret"),
+		VaultSecretPath:         os.Getenv("VAULT_SECRET_PATH"),
+		AWSSecretsPath:          os.Getenv("AWS_SECRETS_PATH"),
+		AnonymizationKey:        os.Getenv("ANONYMIZATION_KEY"),
+		SecretsReloadInterval:   time.Minute,
+		LDAPURL:                 os.Getenv("LDAP_URL"),
+		LDAPBindDN:              os.Getenv("LDAP_BIND_DN"),
+		LDAPBindPassword:        os.Getenv("LDAP_BIND_PASSWORD"),
+		LDAPBaseDN:              os.Getenv("LDAP_BASE_DN"),
+		LDAPUserFilter:          getEnv("LDAP_USER_FILTER", "(mail=%s)"),
+		SAMLBaseURL:             getEnv("SAML_SP_BASE_URL", "http://localhost:8080"),
+		SCIMBearerToken:         os.Getenv("SCIM_BEARER_TOKEN"),
+		PolicyFile:              os.Getenv("POLICY_FILE"),
+		OPAURL:                  os.Getenv("OPA_URL"),
+		TermsVersion:            os.Getenv("TERMS_VERSION"),
+		AttributeSchemaFile:     os.Getenv("ATTRIBUTE_SCHEMA_FILE"),
+		TwilioAccountSID:        os.Getenv("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:         os.Getenv("TWILIO_AUTH_TOKEN"),
+		TwilioFrom:              os.Getenv("TWILIO_FROM"),
+		EmailProvider:           getEnv("EMAIL_PROVIDER", EmailViaLog),
+		EmailFrom:               os.Getenv("EMAIL_FROM"),
+		EmailWelcomeTemplate:    os.Getenv("EMAIL_WELCOME_TEMPLATE"),
+		SendGridAPIKey:          os.Getenv("SENDGRID_API_KEY"),
+		MailgunAPIKey:           os.Getenv("MAILGUN_API_KEY"),
+		MailgunDomain:           os.Getenv("MAILGUN_DOMAIN"),
+		MailgunAPIURL:           getEnv("MAILGUN_API_URL", "https://api.mailgun.net"),
+		BounceAlertThreshold:    50,
+		BounceAlertWindow:       time.Hour,
+		DisplayLocale:           getEnv("DISPLAY_LOCALE", "fr"),
+		DisplayTimeZone:         getEnv("DISPLAY_TIME_ZONE", "Europe/Paris"),
+		DownloadSigningKey:      os.Getenv("DOWNLOAD_SIGNING_KEY"),
+		DownloadLinkTTL:         15 * time.Minute,
+		DownloadBaseURL:         strings.TrimSuffix(os.Getenv("DOWNLOAD_BASE_URL"), "/"),
+		UploadDir:               getEnv("UPLOAD_DIR", filepath.Join(os.TempDir(), "clean-archi-uploads")),
+		UploadChunkSizeMB:       8,
+		UploadMaxSizeMB:         1024,
+		UploadTTL:               24 * time.Hour,
+		UploadPurgeInterval:     time.Hour,
+		ExportRetention:         24 * time.Hour,
+		ExportPurgeInterval:     time.Hour,
+		ChaosTargets:            parseList(os.Getenv("CHAOS_TARGETS")),
+	}
+
+	switch cfg.Environment {
+	case EnvironmentDevelopment:
+	case EnvironmentStaging, EnvironmentProduction:
+		cfg.HSTSMaxAge = 365 * 24 * time.Hour
+	default:
+		return nil, errors.New("APP_ENV: valeur attendue \"development\", \"staging\" ou \"production\"")
+	}
+	if cfg.SentryEnvironment == "" {
+		cfg.SentryEnvironment = cfg.Environment
+	}
+
+	switch cfg.PersistenceMode {
+	case PersistenceState, PersistenceEventSourced, PersistenceDynamoDB:
+		if len(cfg.DatabaseReplicaURLs) > 0 {
+			return nil, errors.New("DATABASE_REPLICA_URLS: réservé au mode \"sql\"")
+		}
+	case PersistenceSQL:
+		// Hors environnement, le DSN est lu au démarrage auprès du fournisseur de secrets
+		if cfg.DatabaseURL == "" && cfg.SecretsProvider == SecretsFromEnv {
+			return nil, errors.New("DATABASE_URL: obligatoire en mode \"sql\"")
+		}
+	default:
+		return nil, errors.New("PERSISTENCE_MODE: valeur attendue \"state\", \"event_sourced\", \"sql\" ou \"dynamodb\"")
+	}
+	if cfg.PersistenceMode == PersistenceDynamoDB {
+		if cfg.DynamoDBTable == "" {
+			return nil, errors.New("DYNAMODB_TABLE: obligatoire en mode \"dynamodb\"")
+		}
+		if cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "" {
+			return nil, errors.New("AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY: obligatoires en mode \"dynamodb\"")
+		}
+	}
+	if cfg.SQLAdapter != SQLAdapterDatabaseSQL && cfg.SQLAdapter != SQLAdapterEnt {
+		return nil, errors.New("SQL_ADAPTER: valeur attendue \"sql\" ou \"ent\"")
+	}
+
+	if cfg.SearchIndex != SearchIndexEmbedded && cfg.SearchIndex != SearchIndexNone {
+		return nil, errors.New("SEARCH_INDEX: valeur attendue \"embedded\" ou \"none\"")
+	}
+
+	if cfg.ListTotals != ListTotalsExact && cfg.ListTotals != ListTotalsEstimated {
+		return nil, errors.New("LIST_TOTALS: valeur attendue \"exact\" ou \"estimated\"")
+	}
+
+	if cfg.PolicyFile != "" && cfg.OPAURL != "" {
+		return nil, errors.New("POLICY_FILE, OPA_URL: une seule source de politiques")
+	}
+
+	if cfg.LambdaRuntimeAPI != "" {
+		cfg.DBMaxOpenConns, cfg.DBMaxIdleConns, cfg.DBLazyConnect = 2, 2, true
+	}
+	var err error
+	if cfg.SnapshotEvery, err = getInt("SNAPSHOT_EVERY", cfg.SnapshotEvery); err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxOpenConns, err = getInt("DB_MAX_OPEN_CONNS", cfg.DBMaxOpenConns); err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxIdleConns, err = getInt("DB_MAX_IDLE_CONNS", cfg.DBMaxIdleConns); err != nil {
+		return nil, err
+	}
+	if cfg.DynamoDBCreateTable, err = getBool("DYNAMODB_CREATE_TABLE", cfg.DynamoDBCreateTable); err != nil {
+		return nil, err
+	}
+	if cfg.DBConnMaxLifetime, err = getDuration("DB_CONN_MAX_LIFETIME", cfg.DBConnMaxLifetime); err != nil {
+		return nil, err
+	}
+	if cfg.DBConnMaxIdleTime, err = getDuration("DB_CONN_MAX_IDLE_TIME", cfg.DBConnMaxIdleTime); err != nil {
+		return nil, err
+	}
+	if cfg.DBLazyConnect, err = getBool("DB_LAZY_CONNECT", cfg.DBLazyConnect); err != nil {
+		return nil, err
+	}
+	if cfg.SlowQueryThreshold, err = getDuration("SLOW_QUERY_THRESHOLD", cfg.SlowQueryThreshold); err != nil {
+		return nil, err
+	}
+	if cfg.FeatureFlagsRefresh, err = getDuration("FEATURE_FLAGS_REFRESH", cfg.FeatureFlagsRefresh); err != nil {
+		return nil, err
+	}
+	if cfg.SettingsWatchInterval, err = getDuration("SETTINGS_WATCH_INTERVAL", cfg.SettingsWatchInterval); err != nil {
+		return nil, err
+	}
+	if cfg.AccessLogFormat != "combined" && cfg.AccessLogFormat != "json" {
+		return nil, errors.New("ACCESS_LOG_FORMAT: valeur attendue \"combined\" ou \"json\"")
+	}
+	if cfg.AccessLogMaxSize, err = getInt("ACCESS_LOG_MAX_SIZE_MB", cfg.AccessLogMaxSize); err != nil {
+		return nil, err
+	}
+	if cfg.AccessLogMaxBackups, err = getInt("ACCESS_LOG_MAX_BACKUPS", cfg.AccessLogMaxBackups); err != nil {
+		return nil, err
+	}
+	if cfg.AccessLogMaxSize < 0 || cfg.AccessLogMaxBackups < 0 {
+		return nil, errors.New("ACCESS_LOG_MAX_SIZE_MB / ACCESS_LOG_MAX_BACKUPS: ne peuvent pas être négatifs")
+	}
+	if cfg.AccessLogMaxAge, err = getDuration("ACCESS_LOG_MAX_AGE", cfg.AccessLogMaxAge); err != nil {
+		return nil, err
+	}
+	if cfg.AccessLogErrorBodies, err = getBool("ACCESS_LOG_ERROR_BODIES", cfg.AccessLogErrorBodies); err != nil {
+		return nil, err
+	}
+	if cfg.DigestInterval, err = getDuration("DIGEST_INTERVAL", cfg.DigestInterval); err != nil {
+		return nil, err
+	}
+	if cfg.OutboxPollInterval, err = getDuration("OUTBOX_POLL_INTERVAL", cfg.OutboxPollInterval); err != nil {
+		return nil, err
+	}
+	if cfg.OutboxMaxAttempts, err = getInt("OUTBOX_MAX_ATTEMPTS", cfg.OutboxMaxAttempts); err != nil {
+		return nil, err
+	}
+	if cfg.DLQMetricsInterval, err = getDuration("DLQ_METRICS_INTERVAL", cfg.DLQMetricsInterval); err != nil {
+		return nil, err
+	}
+	if cfg.InactivityCheckInterval, err = getDuration("INACTIVITY_CHECK_INTERVAL", cfg.InactivityCheckInterval); err != nil {
+		return nil, err
+	}
+	if cfg.ReengagementAfter, err = getDuration("REENGAGEMENT_AFTER", cfg.ReengagementAfter); err != nil {
+		return nil, err
+	}
+	if cfg.CleanupFlagAfter, err = getDuration("CLEANUP_FLAG_AFTER", cfg.CleanupFlagAfter); err != nil {
+		return nil, err
+	}
+	if cfg.ReengagementAfter > 0 && cfg.CleanupFlagAfter > 0 && cfg.CleanupFlagAfter <= cfg.ReengagementAfter {
+		return nil, errors.New("CLEANUP_FLAG_AFTER: doit dépasser REENGAGEMENT_AFTER")
+	}
+	if cfg.HRSyncInterval, err = getDuration("HR_SYNC_INTERVAL", cfg.HRSyncInterval); err != nil {
+		return nil, err
+	}
+	if cfg.HRSyncConflictPolicy != "external_wins" && cfg.HRSyncConflictPolicy != "local_wins" {
+		return nil, errors.New("HR_SYNC_CONFLICT_POLICY: valeur attendue \"external_wins\" ou \"local_wins\"")
+	}
+	if cfg.HRSyncDryRun, err = getBool("HR_SYNC_DRY_RUN", cfg.HRSyncDryRun); err != nil {
+		return nil, err
+	}
+	if cfg.OnboardingConfirmTTL, err = getDuration("ONBOARDING_CONFIRM_TTL", cfg.OnboardingConfirmTTL); err != nil {
+		return nil, err
+	}
+	if cfg.OnboardingConfirmTTL <= 0 {
+		return nil, errors.New("ONBOARDING_CONFIRM_TTL: doit être positif")
+	}
+	if cfg.OnboardingCheckInterval, err = getDuration("ONBOARDING_CHECK_INTERVAL", cfg.OnboardingCheckInterval); err != nil {
+		return nil, err
+	}
+	if cfg.AnalyticsStreamInterval, err = getDuration("ANALYTICS_STREAM_INTERVAL", cfg.AnalyticsStreamInterval); err != nil {
+		return nil, err
+	}
+	if cfg.AnalyticsMaxEvents, err = getInt("ANALYTICS_MAX_EVENTS", cfg.AnalyticsMaxEvents); err != nil {
+		return nil, err
+	}
+	if cfg.AnalyticsMaxValues, err = getInt("ANALYTICS_MAX_VALUES", cfg.AnalyticsMaxValues); err != nil {
+		return nil, err
+	}
+	if cfg.AnalyticsMaxEvents < 1 || cfg.AnalyticsMaxValues < 1 {
+		return nil, errors.New("ANALYTICS_MAX_EVENTS, ANALYTICS_MAX_VALUES: au moins 1")
+	}
+	if cfg.AnalyticsReservoir, err = getInt("ANALYTICS_RESERVOIR_SIZE", cfg.AnalyticsReservoir); err != nil {
+		return nil, err
+	}
+	if cfg.AnalyticsReservoir < 0 {
+		return nil, errors.New("ANALYTICS_RESERVOIR_SIZE: ne peut pas être négatif")
+	}
+	if cfg.AnalyticsWindow, err = getDuration("ANALYTICS_SAMPLE_WINDOW", cfg.AnalyticsWindow); err != nil {
+		return nil, err
+	}
+	if cfg.AnalyticsWindow < time.Second {
+		return nil, errors.New("ANALYTICS_SAMPLE_WINDOW: au moins 1s")
+	}
+	if cfg.AnalyticsConsentDefault != "granted" && cfg.AnalyticsConsentDefault != "denied" {
+		return nil, errors.New("ANALYTICS_CONSENT_DEFAULT: valeur invalide (granted ou denied)")
+	}
+	if cfg.AnalyticsWithoutConsent != "anonymize" && cfg.AnalyticsWithoutConsent != "drop" {
+		return nil, errors.New("ANALYTICS_WITHOUT_CONSENT: valeur invalide (anonymize ou drop)")
+	}
+	cfg.EventScrubHashProperties = parseList(getEnv("EVENT_SCRUB_HASH_PROPERTIES", "user_id,email,anonymous_id,device_id"))
+	cfg.EventScrubDenyProperties = parseList(getEnv("EVENT_SCRUB_DENY_PROPERTIES",
+		"password,token,secret,phone,address,credit_card,card_number,iban,ssn"))
+	cfg.EventScrubKey = os.Getenv("EVENT_SCRUB_KEY")
+	if cfg.EventScrubIPv4Prefix, err = getInt("EVENT_SCRUB_IPV4_PREFIX", 24); err != nil {
+		return nil, err
+	}
+	if cfg.EventScrubIPv4Prefix < 0 || cfg.EventScrubIPv4Prefix > 32 {
+		return nil, errors.New("EVENT_SCRUB_IPV4_PREFIX: doit être compris entre 0 et 32")
+	}
+	if cfg.EventScrubIPv6Prefix, err = getInt("EVENT_SCRUB_IPV6_PREFIX", 48); err != nil {
+		return nil, err
+	}
+	if cfg.EventScrubIPv6Prefix < 0 || cfg.EventScrubIPv6Prefix > 128 {
+		return nil, errors.New("EVENT_SCRUB_IPV6_PREFIX: doit être compris entre 0 et 128")
+	}
+	if cfg.EventScrubKey != "" && len(cfg.EventScrubKey) < 32 {
+		return nil, errors.New("EVENT_SCRUB_KEY: 32 octets minimum")
+	}
+	if cfg.EventBufferSize, err = getInt("EVENT_BUFFER_SIZE", cfg.EventBufferSize); err != nil {
+		return nil, err
+	}
+	if cfg.EventBatchSize, err = getInt("EVENT_BATCH_SIZE", cfg.EventBatchSize); err != nil {
+		return nil, err
+	}
+	if cfg.EventBatchSize < 1 || cfg.EventBufferSize < cfg.EventBatchSize {
+		return nil, errors.New("EVENT_BATCH_SIZE: au moins 1 et au plus EVENT_BUFFER_SIZE")
+	}
+	if cfg.EventFlushInterval, err = getDuration("EVENT_FLUSH_INTERVAL", cfg.EventFlushInterval); err != nil {
+		return nil, err
+	}
+	if cfg.EventFlushInterval < 0 {
+		return nil, errors.New("EVENT_FLUSH_INTERVAL: ne peut pas être négatif")
+	}
+	if cfg.CompressionMinSize, err = getInt("COMPRESSION_MIN_SIZE", cfg.CompressionMinSize); err != nil {
+		return nil, err
+	}
+	if cfg.IdempotencyTTL, err = getDuration("IDEMPOTENCY_TTL", cfg.IdempotencyTTL); err != nil {
+		return nil, err
+	}
+	if cfg.AsyncTaskLimit, err = getInt("ASYNC_TASK_LIMIT", cfg.AsyncTaskLimit); err != nil {
+		return nil, err
+	}
+	if cfg.OutboundMaxRetries, err = getInt("OUTBOUND_MAX_RETRIES", cfg.OutboundMaxRetries); err != nil {
+		return nil, err
+	}
+	if cfg.OutboundMaxRetries < 0 {
+		return nil, errors.New("OUTBOUND_MAX_RETRIES: ne peut pas être négatif")
+	}
+	if cfg.OutboundRetryDelay, err = getDuration("OUTBOUND_RETRY_DELAY", cfg.OutboundRetryDelay); err != nil {
+		return nil, err
+	}
+	switch cfg.AvailabilityMode {
+	case "available", "read_only", "maintenance":
+	default:
+		return nil, errors.New("AVAILABILITY_MODE: valeur attendue \"available\", \"read_only\" ou \"maintenance\"")
+	}
+	if cfg.MaintenanceRetryAfter, err = getDuration("MAINTENANCE_RETRY_AFTER", cfg.MaintenanceRetryAfter); err != nil {
+		return nil, err
+	}
+	if cfg.HeapProfileThreshold, err = getInt("HEAP_PROFILE_THRESHOLD_MB", cfg.HeapProfileThreshold); err != nil {
+		return nil, err
+	}
+	if cfg.HeapProfileThreshold < 0 {
+		return nil, errors.New("HEAP_PROFILE_THRESHOLD_MB: ne peut pas être négatif")
+	}
+	if cfg.HeapCheckInterval, err = getDuration("HEAP_CHECK_INTERVAL", cfg.HeapCheckInterval); err != nil {
+		return nil, err
+	}
+	if cfg.HeapProfileThreshold > 0 && cfg.HeapCheckInterval <= 0 {
+		return nil, errors.New("HEAP_CHECK_INTERVAL: doit être positif avec HEAP_PROFILE_THRESHOLD_MB")
+	}
+	if cfg.UseCaseTimeout, err = getDuration("USECASE_TIMEOUT", cfg.UseCaseTimeout); err != nil {
+		return nil, err
+	}
+	for name, raw := range parseKeyValues(os.Getenv("USECASE_TIMEOUTS")) {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, errors.New("USECASE_TIMEOUTS: durée invalide pour " + name)
+		}
+		cfg.UseCaseTimeouts[name] = timeout
+	}
+	for name, raw := range parseKeyValues(os.Getenv("CACHE_TTLS")) {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl < 0 {
+			return nil, errors.New("CACHE_TTLS: durée invalide pour " + name)
+		}
+		cfg.CacheTTLs[name] = ttl
+	}
+	if cfg.CacheMaxEntries, err = getInt("CACHE_MAX_ENTRIES", cfg.CacheMaxEntries); err != nil {
+		return nil, err
+	}
+	if cfg.CacheMaxEntries < 1 {
+		return nil, errors.New("CACHE_MAX_ENTRIES: au moins 1")
+	}
+	if cfg.CORSAllowCredentials, err = getBool("CORS_ALLOW_CREDENTIALS", cfg.CORSAllowCredentials); err != nil {
+		return nil, err
+	}
+	if cfg.CORSAllowCredentials && slices.Contains(cfg.CORSAllowedOrigins, "*") {
+		return nil, errors.New("CORS_ALLOWED_ORIGINS: \"*\" est interdit avec CORS_ALLOW_CREDENTIALS")
+	}
+	if cfg.SessionCookieName != "" && cfg.CSRFSecret == "" {
+		return nil, errors.New("CSRF_SECRET: obligatoire avec SESSION_COOKIE")
+	}
+	if cfg.DebugAddrPublic, err = getBool("DEBUG_ADDR_PUBLIC", cfg.DebugAddrPublic); err != nil {
+		return nil, err
+	}
+	if cfg.DebugAddr != "" && !cfg.DebugAddrPublic && !loopbackAddr(cfg.DebugAddr) {
+		return nil, fmt.Errorf("DEBUG_ADDR: %q n'est pas une adresse loopback (diagnostics sans authentification) ; DEBUG_ADDR_PUBLIC=true pour l'accepter", cfg.DebugAddr)
+	}
+	if cfg.CORSMaxAge, err = getDuration("CORS_MAX_AGE", cfg.CORSMaxAge); err != nil {
+		return nil, err
+	}
+	if cfg.HSTSMaxAge, err = getDuration("HSTS_MAX_AGE", cfg.HSTSMaxAge); err != nil {
+		return nil, err
+	}
+	if cfg.AccessTokenTTL, err = getDuration("ACCESS_TOKEN_TTL", cfg.AccessTokenTTL); err != nil {
+		return nil, err
+	}
+	if cfg.ImpersonationTTL, err = getDuration("IMPERSONATION_TTL", cfg.ImpersonationTTL); err != nil {
+		return nil, err
+	}
+	if cfg.ImpersonationTTL <= 0 || cfg.ImpersonationTTL > time.Hour {
+		return nil, errors.New("IMPERSONATION_TTL: attendu entre 1s et 1h")
+	}
+	switch cfg.SecretsProvider {
+	case SecretsFromEnv, SecretsFromFile:
+	case SecretsFromVault:
+		if cfg.VaultAddr == "" || cfg.VaultToken == "" || cfg.VaultSecretPath == "" {
+			return nil, errors.New("VAULT_ADDR, VAULT_TOKEN, VAULT_SECRET_PATH: obligatoires avec SECRETS_PROVIDER=vault")
+		}
+	case SecretsFromSSM, SecretsFromSecretsManager:
+		if cfg.AWSSecretsPath == "" || cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "" {
+			return nil, errors.New("AWS_SECRETS_PATH, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY: obligatoires avec SECRETS_PROVIDER=" + cfg.SecretsProvider)
+		}
+	default:
+		return nil, errors.New("SECRETS_PROVIDER: valeur attendue \"env\", \"file\", \"vault\", \"ssm\" ou \"secretsmanager\"")
+	}
+	if cfg.SecretsReloadInterval, err = getDuration("SECRETS_RELOAD_INTERVAL", cfg.SecretsReloadInterval); err != nil {
+		return nil, err
+	}
+	if cfg.LDAPURL != "" && cfg.LDAPBaseDN == "" {
+		return nil, errors.New("LDAP_BASE_DN: obligatoire avec LDAP_URL")
+	}
+	// Réglages rechargeables : environnement, surchargé par SETTINGS_FILE
+	settings, err := LoadSettings(cfg.SettingsFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg.LogLevel, cfg.Quotas, cfg.TenantQuotas = settings.LogLevel, settings.Quotas, settings.TenantQuotas
+	if cfg.BillingUsageInterval, err = getDuration("BILLING_USAGE_INTERVAL", cfg.BillingUsageInterval); err != nil {
+		return nil, err
+	}
+	if cfg.BillingUsageInterval < time.Minute {
+		return nil, errors.New("BILLING_USAGE_INTERVAL: au moins 1m")
+	}
+	if cfg.LDAPGroupRoles, err = parseGroupRoles(os.Getenv("LDAP_GROUP_ROLES")); err != nil {
+		return nil, err
+	}
+	if cfg.LDAPLocalFallback, err = getBool("LDAP_LOCAL_FALLBACK", cfg.LDAPLocalFallback); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookTolerance, err = getDuration("WEBHOOK_TOLERANCE", cfg.WebhookTolerance); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookRetention, err = getDuration("WEBHOOK_RETENTION", cfg.WebhookRetention); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookMaxFailures, err = getInt("WEBHOOK_MAX_FAILURES", cfg.WebhookMaxFailures); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookMaxFailures <= 0 {
+		return nil, errors.New("WEBHOOK_MAX_FAILURES: doit être positif")
+	}
+	for source, secret := range cfg.WebhookSecrets {
+		if len(secret) < MinWebhookSecretLength {
+			return nil, fmt.Errorf("WEBHOOK_SECRETS: secret de %q trop court (%d octets, %d minimum)", source, len(secret), MinWebhookSecretLength)
+		}
+	}
+	if cfg.AuditRetention, err = getDuration("AUDIT_RETENTION", cfg.AuditRetention); err != nil {
+		return nil, err
+	}
+	if cfg.RetentionTTLs, err = parseRetentionTTLs(os.Getenv("RETENTION_TTLS"), cfg.AuditRetention); err != nil {
+		return nil, err
+	}
+	for _, class := range cfg.RetentionArchive {
+		if !slices.Contains(retentionClasses, class) {
+			return nil, errors.New("RETENTION_ARCHIVE: classe inconnue " + class)
+		}
+	}
+	if cfg.RetentionInterval, err = getDuration("RETENTION_INTERVAL", cfg.RetentionInterval); err != nil {
+		return nil, err
+	}
+	if cfg.RetentionBatchSize, err = getInt("RETENTION_BATCH_SIZE", cfg.RetentionBatchSize); err != nil {
+		return nil, err
+	}
+	if cfg.RetentionMaxBatches, err = getInt("RETENTION_MAX_BATCHES", cfg.RetentionMaxBatches); err != nil {
+		return nil, err
+	}
+	if cfg.RetentionBatchSize < 1 || cfg.RetentionMaxBatches < 1 {
+		return nil, errors.New("RETENTION_BATCH_SIZE, RETENTION_MAX_BATCHES: au moins 1")
+	}
+	if cfg.EventArchiveAfter, err = getDuration("EVENT_ARCHIVE_AFTER", 0); err != nil {
+		return nil, err
+	}
+	if cfg.EventArchiveInterval, err = getDuration("EVENT_ARCHIVE_INTERVAL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.EventArchiveBatchSize, err = getInt("EVENT_ARCHIVE_BATCH_SIZE", 10000); err != nil {
+		return nil, err
+	}
+	if cfg.EventArchiveMaxDays, err = getInt("EVENT_ARCHIVE_MAX_DAYS", 31); err != nil {
+		return nil, err
+	}
+	if cfg.EventArchiveBatchSize < 1 || cfg.EventArchiveMaxDays < 1 {
+		return nil, errors.New("EVENT_ARCHIVE_BATCH_SIZE, EVENT_ARCHIVE_MAX_DAYS: au moins 1")
+	}
+	cfg.EventArchiveFormat = getEnv("EVENT_ARCHIVE_FORMAT", ArchiveFormatNDJSON)
+	switch cfg.EventArchiveFormat {
+	case ArchiveFormatNDJSON:
+	default:
+		return nil, errors.New("EVENT_ARCHIVE_FORMAT: valeur attendue \"ndjson\"")
+	}
+	if cfg.EventArchiveRestoreHold, err = getDuration("EVENT_ARCHIVE_RESTORE_HOLD", 7*24*time.Hour); err != nil {
+		return nil, err
+	}
+	cfg.EventArchiveS3Bucket = os.Getenv("EVENT_ARCHIVE_S3_BUCKET")
+	cfg.EventArchiveS3Endpoint = os.Getenv("EVENT_ARCHIVE_S3_ENDPOINT")
+	cfg.EventArchiveS3Prefix = os.Getenv("EVENT_ARCHIVE_S3_PREFIX")
+	if cfg.EventArchiveS3Bucket != "" && (cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "") {
+		return nil, errors.New("AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY: obligatoires avec EVENT_ARCHIVE_S3_BUCKET")
+	}
+	switch cfg.EmailProvider {
+	case EmailViaLog:
+	case EmailViaSES:
+		if cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "" {
+			return nil, errors.New("AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY: obligatoires avec EMAIL_PROVIDER=ses")
+		}
+	case EmailViaSendGrid:
+		if cfg.SendGridAPIKey == "" {
+			return nil, errors.New("SENDGRID_API_KEY: obligatoire avec EMAIL_PROVIDER=sendgrid")
+		}
+	case EmailViaMailgun:
+		if cfg.MailgunAPIKey == "" || cfg.MailgunDomain == "" {
+			return nil, errors.New("MAILGUN_API_KEY, MAILGUN_DOMAIN: obligatoires avec EMAIL_PROVIDER=mailgun")
+		}
+	default:
+		return nil, errors.New("EMAIL_PROVIDER: valeur attendue \"log\", \"ses\", \"sendgrid\" ou \"mailgun\"")
+	}
+	if cfg.EmailProvider != EmailViaLog && cfg.EmailFrom == "" {
+		return nil, errors.New("EMAIL_FROM: obligatoire avec EMAIL_PROVIDER=" + cfg.EmailProvider)
+	}
+	if cfg.BounceAlertThreshold, err = getInt("BOUNCE_ALERT_THRESHOLD", cfg.BounceAlertThreshold); err != nil {
+		return nil, err
+	}
+	if cfg.BounceAlertWindow, err = getDuration("BOUNCE_ALERT_WINDOW", cfg.BounceAlertWindow); err != nil {
+		return nil, err
+	}
+	if cfg.BounceAlertWindow <= 0 {
+		return nil, errors.New("BOUNCE_ALERT_WINDOW: doit être positive")
+	}
+	if _, err := time.LoadLocation(cfg.DisplayTimeZone); err != nil {
+		return nil, fmt.Errorf("DISPLAY_TIME_ZONE: fuseau inconnu %q", cfg.DisplayTimeZone)
+	}
+	if cfg.DownloadLinkTTL, err = getDuration("DOWNLOAD_LINK_TTL", cfg.DownloadLinkTTL); err != nil {
+		return nil, err
+	}
+	if cfg.DownloadLinkTTL <= 0 {
+		return nil, errors.New("DOWNLOAD_LINK_TTL: doit être positive")
+	}
+	if cfg.DownloadSigningKey != "" && len(cfg.DownloadSigningKey) < 32 {
+		return nil, errors.New("DOWNLOAD_SIGNING_KEY: 32 octets minimum")
+	}
+	if cfg.UploadChunkSizeMB, err = getInt("UPLOAD_CHUNK_SIZE_MB", cfg.UploadChunkSizeMB); err != nil {
+		return nil, err
+	}
+	// Un fragment est lu en mémoire pour être vérifié avant d'être écrit
+	if cfg.UploadChunkSizeMB < 1 || cfg.UploadChunkSizeMB > 64 {
+		return nil, errors.New("UPLOAD_CHUNK_SIZE_MB: entre 1 et 64")
+	}
+	if cfg.UploadMaxSizeMB, err = getInt("UPLOAD_MAX_SIZE_MB", cfg.UploadMaxSizeMB); err != nil {
+		return nil, err
+	}
+	if cfg.UploadMaxSizeMB < cfg.UploadChunkSizeMB {
+		return nil, errors.New("UPLOAD_MAX_SIZE_MB: au moins UPLOAD_CHUNK_SIZE_MB")
+	}
+	if cfg.UploadTTL, err = getDuration("UPLOAD_TTL", cfg.UploadTTL); err != nil {
+		return nil, err
+	}
+	if cfg.UploadTTL <= 0 {
+		return nil, errors.New("UPLOAD_TTL: doit être positive")
+	}
+	if cfg.UploadPurgeInterval, err = getDuration("UPLOAD_PURGE_INTERVAL", cfg.UploadPurgeInterval); err != nil {
+		return nil, err
+	}
+	if cfg.UploadPurgeInterval <= 0 {
+		return nil, errors.New("UPLOAD_PURGE_INTERVAL: doit être positive")
+	}
+	if cfg.ExportRetention, err = getDuration("EXPORT_RETENTION", cfg.ExportRetention); err != nil {
+		return nil, err
+	}
+	if cfg.ExportRetention <= 0 {
+		return nil, errors.New("EXPORT_RETENTION: doit être positive")
+	}
+	if cfg.ExportPurgeInterval, err = getDuration("EXPORT_PURGE_INTERVAL", cfg.ExportPurgeInterval); err != nil {
+		return nil, err
+	}
+	if cfg.ExportPurgeInterval <= 0 {
+		return nil, errors.New("EXPORT_PURGE_INTERVAL: doit être positive")
+	}
+	cfg.ExportParquetCompression = getEnv("EXPORT_PARQUET_COMPRESSION", "snappy")
+	switch cfg.ExportParquetCompression {
+	case "snappy", "gzip", "none":
+	default:
+		return nil, errors.New("EXPORT_PARQUET_COMPRESSION: valeur attendue snappy, gzip ou none")
+	}
+	if cfg.ExportParquetRowGroupRows, err = getInt("EXPORT_PARQUET_ROW_GROUP_ROWS", 100000); err != nil {
+		return nil, err
+	}
+	if cfg.ExportParquetRowGroupRows < 1 {
+		return nil, errors.New("EXPORT_PARQUET_ROW_GROUP_ROWS: au moins 1")
+	}
+	if cfg.CollectRateLimit, err = getInt("COLLECT_RATE_LIMIT", 50); err != nil {
+		return nil, err
+	}
+	if cfg.CollectRateBurst, err = getInt("COLLECT_RATE_BURST", 100); err != nil {
+		return nil, err
+	}
+	if cfg.CollectRateLimit < 0 {
+		return nil, errors.New("COLLECT_RATE_LIMIT: ne peut pas être négatif")
+	}
+	if cfg.CollectRateBurst < 1 {
+		return nil, errors.New("COLLECT_RATE_BURST: au moins 1")
+	}
+	if cfg.WriteKeyRotationGrace, err = getDuration("WRITE_KEY_ROTATION_GRACE", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.WriteKeyRotationGrace < 0 {
+		return nil, errors.New("WRITE_KEY_ROTATION_GRACE: ne peut pas être négatif")
+	}
+	if cfg.AlertEvaluationInterval, err = getDuration("ALERT_EVALUATION_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.AlertEvaluationInterval < 0 {
+		return nil, errors.New("ALERT_EVALUATION_INTERVAL: ne peut pas être négatif")
+	}
+	if cfg.SessionHistory, err = getInt("SESSION_HISTORY", cfg.SessionHistory); err != nil {
+		return nil, err
+	}
+	if cfg.SessionHistory < 1 {
+		return nil, errors.New("SESSION_HISTORY: au moins 1")
+	}
+	for rule, decision := range cfg.LoginRiskRules {
+		switch rule {
+		case "new_country", "impossible_travel":
+			if cfg.GeoIPDatabase == "" {
+				return nil, errors.New("LOGIN_RISK_RULES: " + rule + " requiert GEOIP_DATABASE")
+			}
+		case "tor":
+			if cfg.TorExitList == "" {
+				return nil, errors.New("LOGIN_RISK_RULES: tor requiert TOR_EXIT_LIST")
+			}
+		default:
+			return nil, errors.New("LOGIN_RISK_RULES: règle inconnue " + rule + " (new_country, impossible_travel, tor)")
+		}
+		if decision != "step_up" && decision != "block" {
+			return nil, errors.New("LOGIN_RISK_RULES: issue invalide pour " + rule + " (step_up ou block)")
+		}
+	}
+	if cfg.LoginRiskMaxSpeed, err = getInt("LOGIN_RISK_MAX_SPEED", cfg.LoginRiskMaxSpeed); err != nil {
+		return nil, err
+	}
+	if cfg.LoginRiskMaxSpeed <= 0 {
+		return nil, errors.New("LOGIN_RISK_MAX_SPEED: doit être positive")
+	}
+	if cfg.LoginChallengeTTL, err = getDuration("LOGIN_CHALLENGE_TTL", cfg.LoginChallengeTTL); err != nil {
+		return nil, err
+	}
+	if cfg.LoginChallengeTTL <= 0 {
+		return nil, errors.New("LOGIN_CHALLENGE_TTL: doit être positive")
+	}
+	if cfg.TorExitListRefresh, err = getDuration("TOR_EXIT_LIST_REFRESH", cfg.TorExitListRefresh); err != nil {
+		return nil, err
+	}
+	if cfg.TorExitListRefresh <= 0 {
+		return nil, errors.New("TOR_EXIT_LIST_REFRESH: doit être positive")
+	}
+	switch cfg.CaptchaProvider {
+	case "":
+	case CaptchaViaRecaptcha, CaptchaViaHCaptcha, CaptchaViaTurnstile:
+		if cfg.CaptchaSecret == "" {
+			return nil, errors.New("CAPTCHA_SECRET: obligatoire avec CAPTCHA_PROVIDER=" + cfg.CaptchaProvider)
+		}
+	default:
+		return nil, errors.New("CAPTCHA_PROVIDER: valeur invalide (recaptcha, hcaptcha ou turnstile)")
+	}
+	if cfg.CaptchaMinScore, err = getRate("CAPTCHA_MIN_SCORE"); err != nil {
+		return nil, err
+	}
+	if cfg.CaptchaThreshold, err = getInt("CAPTCHA_THRESHOLD", cfg.CaptchaThreshold); err != nil {
+		return nil, err
+	}
+	if cfg.CaptchaThreshold < 0 {
+		return nil, errors.New("CAPTCHA_THRESHOLD: ne peut pas être négatif")
+	}
+	if cfg.CaptchaWindow, err = getDuration("CAPTCHA_WINDOW", cfg.CaptchaWindow); err != nil {
+		return nil, err
+	}
+	if cfg.CaptchaWindow <= 0 {
+		return nil, errors.New("CAPTCHA_WINDOW: doit être positive")
+	}
+	switch cfg.ModerationProvider {
+	case ModerationViaWordList, ModerationDisabled:
+	case ModerationViaAPI:
+		if cfg.ModerationAPIURL == "" {
+			return nil, errors.New("MODERATION_API_URL: obligatoire avec MODERATION_PROVIDER=api")
+		}
+	default:
+		return nil, errors.New("MODERATION_PROVIDER: valeur invalide (wordlist, api ou none)")
+	}
+	for _, raw := range parseList(os.Getenv("CAPTCHA_BYPASS_NETWORKS")) {
+		network, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return nil, errors.New("CAPTCHA_BYPASS_NETWORKS: réseau CIDR invalide " + raw)
+		}
+		cfg.CaptchaBypassNetworks = append(cfg.CaptchaBypassNetworks, network.Masked())
+	}
+	if cfg.ChaosLatency, err = getDuration("CHAOS_LATENCY", cfg.ChaosLatency); err != nil {
+		return nil, err
+	}
+	if cfg.ChaosErrorRate, err = getRate("CHAOS_ERROR_RATE"); err != nil {
+		return nil, err
+	}
+	if cfg.ChaosTimeoutRate, err = getRate("CHAOS_TIMEOUT_RATE"); err != nil {
+		return nil, err
+	}
+	if cfg.ChaosErrorRate+cfg.ChaosTimeoutRate > 1 {
+		return nil, errors.New("CHAOS_ERROR_RATE, CHAOS_TIMEOUT_RATE: leur somme ne peut dépasser 1")
+	}
+	for _, target := range cfg.ChaosTargets {
+		if !slices.Contains(chaosTargets, target) {
+			return nil, errors.New("CHAOS_TARGETS: cible inconnue " + strconv.Quote(target) + " (users, events, email, bus)")
+		}
+	}
+	if cfg.ChaosEnabled() && cfg.Environment == EnvironmentProduction {
+		return nil, errors.New("CHAOS_*: injection de pannes interdite avec APP_ENV=production")
+	}
+
+	return cfg, nil
+}
+
+// defaultUseCaseTimeouts use cases longs par nature : hachage PBKDF2 par ligne importée
+// ou par compte provisionné depuis le SIRH, parcours de tous les utilisateurs pour les digests
+// et pour l'export anonymisé, rejeu de tout le magasin d'événements, réception d'un fragment
+// d'upload (débit du client) et assemblage du fichier complet
+func defaultUseCaseTimeouts() map[string]time.Duration {
+	return map[string]time.Duration{
+		"anonymize_data":      time.Hour,
+		"rebuild_projections": time.Hour,
+		"bulk_create_users":   10 * time.Minute,
+		"import_users":        time.Hour,
+		"send_weekly_digests": 10 * time.Minute,
+		"sync_users":          10 * time.Minute,
+		"upload_chunk":        5 * time.Minute,
+		"complete_upload":     10 * time.Minute,
+	}
+}
+
+// defaultCacheTTLs lectures les plus fréquentes : profils (invalidés par les événements de
+// l'utilisateur) et agrégats analytics (recalculés au plus toutes les minutes)
+func defaultCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		"get_user":           30 * time.Second,
+		"get_user_by_handle": 30 * time.Second,
+		"get_event_rollups":  time.Minute,
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.New(key + ": durée invalide")
+	}
+	return duration, nil
+}
+
+func getInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(key + ": entier invalide")
+	}
+	return number, nil
+}
+
+// getRate proportion entre 0 et 1 ; absente = 0
+func getRate(key string) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, errors.New(key + ": proportion attendue entre 0 et 1")
+	}
+	return rate, nil
+}
+
+// loopbackAddr vrai si addr ("hôte:port") n'écoute que sur le loopback ; un hôte vide écoute sur
+// toutes les interfaces, un nom autre que localhost peut résoudre vers n'importe laquelle
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && ip.IsLoopback()
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New(key + ": booléen invalide")
+	}
+	return enabled, nil
+}
+
+// parseKeyValues lit le format "cle1=valeur1,cle2=valeur2"
+// retentionClasses classes de données de RETENTION_TTLS et RETENTION_ARCHIVE
+var retentionClasses = []string{"users", "sessions", "audit_logs", "raw_events", "rollups"}
+
+// parseRetentionTTLs lit RETENTION_TTLS ; audit_logs reprend AUDIT_RETENTION quand elle n'y figure pas
+func parseRetentionTTLs(raw string, auditRetention time.Duration) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for class, value := range parseKeyValues(raw) {
+		if !slices.Contains(retentionClasses, class) {
+			return nil, errors.New("RETENTION_TTLS: classe inconnue " + class)
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < 0 {
+			return nil, errors.New("RETENTION_TTLS: durée invalide pour " + class)
+		}
+		ttls[class] = ttl
+	}
+	if _, ok := ttls["audit_logs"]; !ok && auditRetention > 0 {
+		ttls["audit_logs"] = auditRetention
+	}
+	return ttls, nil
+}
+
+func parseKeyValues(raw string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || key == "" {
+			continue
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// parseGroupRoles lit le format "role=dn_groupe;role2=dn_groupe2" : les DN contiennent des virgules
+// et des "=", seul le premier "=" sépare le rôle
+func parseGroupRoles(raw string) (map[string]string, error) {
+	roles := make(map[string]string)
+	for _, pair := range strings.Split(raw, ";") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		role, group, found := strings.Cut(pair, "=")
+		role, group = strings.TrimSpace(role), strings.ToLower(strings.TrimSpace(group))
+		if !found || role == "" || group == "" {
+			return nil, errors.New("LDAP_GROUP_ROLES: format attendu \"role=dn_groupe;...\"")
+		}
+		roles[group] = role
+	}
+	return roles, nil
+}
+
+// quotaResources ressources limitables (repositories.UsageResources)
+var quotaResources = []string{"api_calls", "users", "events"}
+
+// parseQuotas lit le format "ressource=limite,ressource2=limite2"
+func parseQuotas(key, raw string) (map[string]int, error) {
+	quotas := make(map[string]int)
+	for resource, value := range parseKeyValues(raw) {
+		resource = strings.TrimSpace(resource)
+		if !slices.Contains(quotaResources, resource) {
+			return nil, fmt.Errorf("%s: ressource %q inconnue (attendu %s)", key, resource, strings.Join(quotaResources, ", "))
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("%s: limite invalide pour %s", key, resource)
+		}
+		quotas[resource] = limit
+	}
+	return quotas, nil
+}
+
+// parseTenantQuotas lit le format "tenant:ressource=limite,...;tenant2:..."
+func parseTenantQuotas(raw string) (map[string]map[string]int, error) {
+	tenants := make(map[string]map[string]int)
+	for _, entry := range strings.Split(raw, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		tenant, quotas, found := strings.Cut(entry, ":")
+		if tenant = strings.TrimSpace(tenant); !found || tenant == "" {
+			return nil, errors.New("TENANT_QUOTAS: format attendu \"tenant:ressource=limite,...;...\"")
+		}
+		parsed, err := parseQuotas("TENANT_QUOTAS", quotas)
+		if err != nil {
+			return nil, err
+		}
+		tenants[tenant] = parsed
+	}
+	return tenants, nil
+}
+
+// parseList lit le format "valeur1,valeur2" (les éléments vides sont ignorés)
+func parseList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
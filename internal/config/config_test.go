@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadWebhookSecrets(t *testing.T) {
+	tests := []struct {
+		secrets string
+		wantErr string
+	}{
+		{"payments=whsec_0123456789abcdef", ""},
+		{"payments=", `secret de "payments" trop court`},
+		{"payments=whsec_0123456789abcdef,crm=short", `secret de "crm" trop court`},
+		{"payments=" + strings.Repeat("x", MinWebhookSecretLength-1), "15 octets, 16 minimum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.secrets, func(t *testing.T) {
+			t.Setenv("WEBHOOK_SECRETS", tt.secrets)
+			_, err := Load()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("configuration refusée : %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erreur %v, attendu %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Les diagnostics de DEBUG_ADDR ne sont pas authentifiés : loopback seulement, sauf opt-in explicite
+func TestLoadDebugAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public string
+		wantOK bool
+	}{
+		{"", "", true},
+		{"127.0.0.1:6060", "", true},
+		{"127.10.0.1:6060", "", true},
+		{"[::1]:6060", "", true},
+		{"localhost:6060", "", true},
+		{":6060", "", false},
+		{"0.0.0.0:6060", "", false},
+		{"[::]:6060", "", false},
+		{"10.0.0.5:6060", "", false},
+		{"debug.internal:6060", "", false},
+		{"localhost.example.com:6060", "", false},
+		{"127.0.0.1", "", false},
+		{"0.0.0.0:6060", "false", false},
+		{"0.0.0.0:6060", "true", true},
+		{":6060", "true", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr+"/"+tt.public, func(t *testing.T) {
+			t.Setenv("DEBUG_ADDR", tt.addr)
+			t.Setenv("DEBUG_ADDR_PUBLIC", tt.public)
+			cfg, err := Load()
+			if tt.wantOK {
+				if err != nil {
+					t.Fatalf("configuration refusée : %v", err)
+				}
+				if cfg.DebugAddr != tt.addr {
+					t.Fatalf("DebugAddr %q", cfg.DebugAddr)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "DEBUG_ADDR") {
+				t.Fatalf("erreur %v, attendu un refus de DEBUG_ADDR", err)
+			}
+		})
+	}
+}
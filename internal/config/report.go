@@ -0,0 +1,353 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Setting valeur effective d'une variable de configuration, prête à être affichée
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// Redacted retourne la configuration effective avec les secrets masqués :
+// seule leur présence et leur longueur sont affichées, jamais leur valeur
+func (c *Config) Redacted() []Setting {
+	settings := []Setting{
+		{"HTTP_ADDR", c.HTTPAddr},
+		{"APP_ENV", c.Environment},
+		{"PERSISTENCE_MODE", c.PersistenceMode},
+		{"SQL_ADAPTER", c.SQLAdapter},
+		{"DYNAMODB_TABLE", c.DynamoDBTable},
+		{"DYNAMODB_ENDPOINT", c.DynamoDBEndpoint},
+		{"DYNAMODB_CREATE_TABLE", fmt.Sprint(c.DynamoDBCreateTable)},
+		{"AWS_REGION", c.AWSRegion},
+		{"AWS_ACCESS_KEY_ID", c.AWSAccessKeyID},
+		{"AWS_SECRET_ACCESS_KEY", redactSecret(c.AWSSecretAccessKey)},
+		{"AWS_SESSION_TOKEN", redactSecret(c.AWSSessionToken)},
+		{"DB_DRIVER", c.DatabaseDriver},
+		{"DATABASE_URL", redactURL(c.DatabaseURL)},
+		{"DATABASE_REPLICA_URLS", redactURLs(c.DatabaseReplicaURLs)},
+		{"DB_MAX_OPEN_CONNS", fmt.Sprint(c.DBMaxOpenConns)},
+		{"DB_MAX_IDLE_CONNS", fmt.Sprint(c.DBMaxIdleConns)},
+		{"DB_CONN_MAX_LIFETIME", c.DBConnMaxLifetime.String()},
+		{"DB_CONN_MAX_IDLE_TIME", c.DBConnMaxIdleTime.String()},
+		{"DB_LAZY_CONNECT", fmt.Sprint(c.DBLazyConnect)},
+		{"AWS_LAMBDA_RUNTIME_API", c.LambdaRuntimeAPI},
+		{"SNAPSHOT_EVERY", fmt.Sprint(c.SnapshotEvery)},
+		{"SLOW_QUERY_THRESHOLD", c.SlowQueryThreshold.String()},
+		{"SEARCH_INDEX", c.SearchIndex},
+		{"LIST_TOTALS", c.ListTotals},
+		{"ELASTICSEARCH_URL", redactURL(c.ElasticsearchURL)},
+		{"ELASTICSEARCH_USERNAME", c.ElasticsearchUsername},
+		{"ELASTICSEARCH_PASSWORD", redactSecret(c.ElasticsearchPassword)},
+		{"ELASTICSEARCH_API_KEY", redactSecret(c.ElasticsearchAPIKey)},
+		{"ELASTICSEARCH_INDEX_PREFIX", c.ElasticsearchIndex},
+		{"FEATURE_FLAGS_FILE", c.FeatureFlagsFile},
+		{"FEATURE_FLAGS_URL", redactURL(c.FeatureFlagsURL)},
+		{"FEATURE_FLAGS_REFRESH", c.FeatureFlagsRefresh.String()},
+		{"LOG_LEVEL", c.LogLevel},
+		{"SETTINGS_FILE", c.SettingsFile},
+		{"SETTINGS_WATCH_INTERVAL", c.SettingsWatchInterval.String()},
+		{"ACCESS_LOG", c.AccessLog},
+		{"ACCESS_LOG_FORMAT", c.AccessLogFormat},
+		{"ACCESS_LOG_MAX_SIZE_MB", fmt.Sprint(c.AccessLogMaxSize)},
+		{"ACCESS_LOG_MAX_BACKUPS", fmt.Sprint(c.AccessLogMaxBackups)},
+		{"ACCESS_LOG_MAX_AGE", c.AccessLogMaxAge.String()},
+		{"ACCESS_LOG_ERROR_BODIES", fmt.Sprint(c.AccessLogErrorBodies)},
+		{"DIGEST_INTERVAL", c.DigestInterval.String()},
+		{"OUTBOX_POLL_INTERVAL", c.OutboxPollInterval.String()},
+		{"OUTBOX_MAX_ATTEMPTS", fmt.Sprint(c.OutboxMaxAttempts)},
+		{"DLQ_METRICS_INTERVAL", c.DLQMetricsInterval.String()},
+		{"INACTIVITY_CHECK_INTERVAL", c.InactivityCheckInterval.String()},
+		{"REENGAGEMENT_AFTER", c.ReengagementAfter.String()},
+		{"CLEANUP_FLAG_AFTER", c.CleanupFlagAfter.String()},
+		{"HR_SYNC_URL", redactURL(c.HRSyncURL)},
+		{"HR_SYNC_TOKEN", redactSecret(c.HRSyncToken)},
+		{"HR_SYNC_INTERVAL", c.HRSyncInterval.String()},
+		{"HR_SYNC_CONFLICT_POLICY", c.HRSyncConflictPolicy},
+		{"HR_SYNC_DRY_RUN", fmt.Sprint(c.HRSyncDryRun)},
+		{"ONBOARDING_CONFIRM_TTL", c.OnboardingConfirmTTL.String()},
+		{"ONBOARDING_CHECK_INTERVAL", c.OnboardingCheckInterval.String()},
+		{"ONBOARDING_ADMIN_EMAILS", strings.Join(c.OnboardingAdminEmails, ", ")},
+		{"WORKSPACE_PROVISIONER_URL", c.WorkspaceURL},
+		{"WORKSPACE_PROVISIONER_TOKEN", redactSecret(c.WorkspaceToken)},
+		{"WEBHOOK_SECRETS", redactSecrets(c.WebhookSecrets)},
+		{"WEBHOOK_TOLERANCE", c.WebhookTolerance.String()},
+		{"WEBHOOK_RETENTION", c.WebhookRetention.String()},
+		{"WEBHOOK_MAX_FAILURES", fmt.Sprint(c.WebhookMaxFailures)},
+		{"AUDIT_RETENTION", c.AuditRetention.String()},
+		{"RETENTION_TTLS", formatDurations(c.RetentionTTLs)},
+		{"RETENTION_ARCHIVE", strings.Join(c.RetentionArchive, ", ")},
+		{"RETENTION_INTERVAL", c.RetentionInterval.String()},
+		{"RETENTION_BATCH_SIZE", fmt.Sprint(c.RetentionBatchSize)},
+		{"RETENTION_MAX_BATCHES", fmt.Sprint(c.RetentionMaxBatches)},
+		{"EVENT_ARCHIVE_AFTER", c.EventArchiveAfter.String()},
+		{"EVENT_ARCHIVE_INTERVAL", c.EventArchiveInterval.String()},
+		{"EVENT_ARCHIVE_BATCH_SIZE", fmt.Sprint(c.EventArchiveBatchSize)},
+		{"EVENT_ARCHIVE_MAX_DAYS", fmt.Sprint(c.EventArchiveMaxDays)},
+		{"EVENT_ARCHIVE_FORMAT", c.EventArchiveFormat},
+		{"EVENT_ARCHIVE_RESTORE_HOLD", c.EventArchiveRestoreHold.String()},
+		{"EVENT_ARCHIVE_S3_BUCKET", c.EventArchiveS3Bucket},
+		{"EVENT_ARCHIVE_S3_ENDPOINT", c.EventArchiveS3Endpoint},
+		{"EVENT_ARCHIVE_S3_PREFIX", c.EventArchiveS3Prefix},
+		{"QUOTAS", formatQuotas(c.Quotas)},
+		{"TENANT_QUOTAS", formatTenantQuotas(c.TenantQuotas)},
+		{"STRIPE_SECRET_KEY", redactSecret(c.StripeSecretKey)},
+		{"STRIPE_API_URL", redactURL(c.StripeAPIURL)},
+		{"STRIPE_METERS", formatKeyValues(c.StripeMeters)},
+		{"BILLING_PLANS_FILE", c.BillingPlansFile},
+		{"BILLING_USAGE_INTERVAL", c.BillingUsageInterval.String()},
+		{"ANALYTICS_STREAM_INTERVAL", c.AnalyticsStreamInterval.String()},
+		{"ANALYTICS_MAX_EVENTS", fmt.Sprint(c.AnalyticsMaxEvents)},
+		{"ANALYTICS_MAX_VALUES", fmt.Sprint(c.AnalyticsMaxValues)},
+		{"ANALYTICS_RESERVOIR_SIZE", fmt.Sprint(c.AnalyticsReservoir)},
+		{"ANALYTICS_SAMPLE_WINDOW", c.AnalyticsWindow.String()},
+		{"ANALYTICS_CONSENT_DEFAULT", c.AnalyticsConsentDefault},
+		{"ANALYTICS_WITHOUT_CONSENT", c.AnalyticsWithoutConsent},
+		{"EVENT_SCRUB_IPV4_PREFIX", fmt.Sprint(c.EventScrubIPv4Prefix)},
+		{"EVENT_SCRUB_IPV6_PREFIX", fmt.Sprint(c.EventScrubIPv6Prefix)},
+		{"EVENT_SCRUB_HASH_PROPERTIES", strings.Join(c.EventScrubHashProperties, ", ")},
+		{"EVENT_SCRUB_DENY_PROPERTIES", strings.Join(c.EventScrubDenyProperties, ", ")},
+		{"EVENT_SCRUB_KEY", redactSecret(c.EventScrubKey)},
+		{"EVENT_BUFFER_SIZE", fmt.Sprint(c.EventBufferSize)},
+		{"EVENT_BATCH_SIZE", fmt.Sprint(c.EventBatchSize)},
+		{"EVENT_FLUSH_INTERVAL", c.EventFlushInterval.String()},
+		{"USECASE_TIMEOUT", c.UseCaseTimeout.String()},
+		{"USECASE_TIMEOUTS", formatDurations(c.UseCaseTimeouts)},
+		{"CACHE_TTLS", formatDurations(c.CacheTTLs)},
+		{"CACHE_MAX_ENTRIES", fmt.Sprint(c.CacheMaxEntries)},
+		{"COMPRESSION_MIN_SIZE", fmt.Sprint(c.CompressionMinSize)},
+		{"IDEMPOTENCY_TTL", c.IdempotencyTTL.String()},
+		{"CORS_ALLOWED_ORIGINS", strings.Join(c.CORSAllowedOrigins, ", ")},
+		{"CORS_ALLOWED_METHODS", strings.Join(c.CORSAllowedMethods, ", ")},
+		{"CORS_ALLOWED_HEADERS", strings.Join(c.CORSAllowedHeaders, ", ")},
+		{"CORS_ALLOW_CREDENTIALS", fmt.Sprint(c.CORSAllowCredentials)},
+		{"CORS_MAX_AGE", c.CORSMaxAge.String()},
+		{"HSTS_MAX_AGE", c.HSTSMaxAge.String()},
+		{"CSP", c.ContentSecurityPolicy},
+		{"CSP_DOCS", c.DocsSecurityPolicy},
+		{"SESSION_COOKIE", c.SessionCookieName},
+		{"CSRF_SECRET", redactSecret(c.CSRFSecret)},
+		{"ASYNC_TASK_LIMIT", fmt.Sprint(c.AsyncTaskLimit)},
+		{"OUTBOUND_PROXY_URL", redactURL(c.OutboundProxyURL)},
+		{"OUTBOUND_MAX_RETRIES", fmt.Sprint(c.OutboundMaxRetries)},
+		{"OUTBOUND_RETRY_DELAY", c.OutboundRetryDelay.String()},
+		{"AVAILABILITY_MODE", c.AvailabilityMode},
+		{"MAINTENANCE_RETRY_AFTER", c.MaintenanceRetryAfter.String()},
+		{"DEBUG_ADDR", c.DebugAddr},
+		{"DEBUG_ADDR_PUBLIC", fmt.Sprint(c.DebugAddrPublic)},
+		{"DIAGNOSTICS_DIR", c.DiagnosticsDir},
+		{"HEAP_PROFILE_THRESHOLD_MB", fmt.Sprint(c.HeapProfileThreshold)},
+		{"HEAP_CHECK_INTERVAL", c.HeapCheckInterval.String()},
+		{"SENTRY_DSN", redactURL(c.SentryDSN)},
+		{"SENTRY_ENVIRONMENT", c.SentryEnvironment},
+		{"JWT_SECRET", redactSecret(c.JWTSecret)},
+		{"JWT_SIGNING_KEYS", redactSigningKeys(c.JWTSigningKeys)},
+		{"ACCESS_TOKEN_TTL", c.AccessTokenTTL.String()},
+		{"IMPERSONATION_ROLE", c.ImpersonationRole},
+		{"IMPERSONATION_TTL", c.ImpersonationTTL.String()},
+		{"ADMIN_ROLE", c.AdminRole},
+		{"GEOIP_DATABASE", c.GeoIPDatabase},
+		{"SESSION_HISTORY", fmt.Sprint(c.SessionHistory)},
+		{"LOGIN_RISK_RULES", formatKeyValues(c.LoginRiskRules)},
+		{"LOGIN_RISK_MAX_SPEED", fmt.Sprint(c.LoginRiskMaxSpeed)},
+		{"LOGIN_CHALLENGE_TTL", c.LoginChallengeTTL.String()},
+		{"TOR_EXIT_LIST", c.TorExitList},
+		{"TOR_EXIT_LIST_REFRESH", c.TorExitListRefresh.String()},
+		{"CAPTCHA_PROVIDER", c.CaptchaProvider},
+		{"CAPTCHA_SECRET", redactSecret(c.CaptchaSecret)},
+		{"CAPTCHA_ACTIONS", strings.Join(c.CaptchaActions, ", ")},
+		{"CAPTCHA_MIN_SCORE", fmt.Sprint(c.CaptchaMinScore)},
+		{"CAPTCHA_THRESHOLD", fmt.Sprint(c.CaptchaThreshold)},
+		{"CAPTCHA_WINDOW", c.CaptchaWindow.String()},
+		{"CAPTCHA_BYPASS_ROLES", strings.Join(c.CaptchaBypassRoles, ", ")},
+		{"CAPTCHA_BYPASS_NETWORKS", formatNetworks(c.CaptchaBypassNetworks)},
+		{"MODERATION_PROVIDER", c.ModerationProvider},
+		{"MODERATION_LANGUAGES", strings.Join(c.ModerationLanguages, ", ")},
+		{"MODERATION_WORDS_FILE", c.ModerationWordsFile},
+		{"MODERATION_API_URL", c.ModerationAPIURL},
+		{"MODERATION_API_KEY", redactSecret(c.ModerationAPIKey)},
+		{"SUPPORT_ROLE", c.SupportRole},
+		{"TIMELINE_EVENTS", strings.Join(c.TimelineEvents, ", ")},
+		{"SECRETS_PROVIDER", c.SecretsProvider},
+		{"SECRETS_DIR", c.SecretsDir},
+		{"VAULT_ADDR", redactURL(c.VaultAddr)},
+		{"VAULT_TOKEN", redactSecret(c.VaultToken)},
+		{"VAULT_NAMESPACE", c.VaultNamespace},
+		{"VAULT_KV_MOUNT", c.VaultKVMount},
+		{"VAULT_SECRET_PATH", c.VaultSecretPath},
+		{"AWS_SECRETS_PATH", c.AWSSecretsPath},
+		{"ANONYMIZATION_KEY", redactSecret(c.AnonymizationKey)},
+		{"SECRETS_RELOAD_INTERVAL", c.SecretsReloadInterval.String()},
+		{"LDAP_URL", redactURL(c.LDAPURL)},
+		{"LDAP_BIND_DN", c.LDAPBindDN},
+		{"LDAP_BIND_PASSWORD", redactSecret(c.LDAPBindPassword)},
+		{"LDAP_BASE_DN", c.LDAPBaseDN},
+		{"LDAP_USER_FILTER", c.LDAPUserFilter},
+		{"LDAP_GROUP_ROLES", formatGroupRoles(c.LDAPGroupRoles)},
+		{"LDAP_LOCAL_FALLBACK", fmt.Sprint(c.LDAPLocalFallback)},
+		{"SAML_SP_BASE_URL", c.SAMLBaseURL},
+		{"SCIM_BEARER_TOKEN", redactSecret(c.SCIMBearerToken)},
+		{"POLICY_FILE", c.PolicyFile},
+		{"OPA_URL", redactURL(c.OPAURL)},
+		{"ATTRIBUTE_SCHEMA_FILE", c.AttributeSchemaFile},
+		{"TERMS_VERSION", c.TermsVersion},
+		{"TWILIO_ACCOUNT_SID", c.TwilioAccountSID},
+		{"TWILIO_AUTH_TOKEN", redactSecret(c.TwilioAuthToken)},
+		{"TWILIO_FROM", c.TwilioFrom},
+		{"EMAIL_PROVIDER", c.EmailProvider},
+		{"EMAIL_FROM", c.EmailFrom},
+		{"EMAIL_WELCOME_TEMPLATE", c.EmailWelcomeTemplate},
+		{"SENDGRID_API_KEY", redactSecret(c.SendGridAPIKey)},
+		{"MAILGUN_API_KEY", redactSecret(c.MailgunAPIKey)},
+		{"MAILGUN_DOMAIN", c.MailgunDomain},
+		{"MAILGUN_API_URL", c.MailgunAPIURL},
+		{"BOUNCE_ALERT_THRESHOLD", fmt.Sprint(c.BounceAlertThreshold)},
+		{"BOUNCE_ALERT_WINDOW", c.BounceAlertWindow.String()},
+		{"DISPLAY_LOCALE", c.DisplayLocale},
+		{"DISPLAY_TIME_ZONE", c.DisplayTimeZone},
+		{"DOWNLOAD_SIGNING_KEY", redactSecret(c.DownloadSigningKey)},
+		{"DOWNLOAD_LINK_TTL", c.DownloadLinkTTL.String()},
+		{"DOWNLOAD_BASE_URL", c.DownloadBaseURL},
+		{"UPLOAD_DIR", c.UploadDir},
+		{"UPLOAD_CHUNK_SIZE_MB", fmt.Sprint(c.UploadChunkSizeMB)},
+		{"UPLOAD_MAX_SIZE_MB", fmt.Sprint(c.UploadMaxSizeMB)},
+		{"UPLOAD_TTL", c.UploadTTL.String()},
+		{"UPLOAD_PURGE_INTERVAL", c.UploadPurgeInterval.String()},
+		{"EXPORT_RETENTION", c.ExportRetention.String()},
+		{"EXPORT_PURGE_INTERVAL", c.ExportPurgeInterval.String()},
+		{"EXPORT_PARQUET_COMPRESSION", c.ExportParquetCompression},
+		{"EXPORT_PARQUET_ROW_GROUP_ROWS", fmt.Sprint(c.ExportParquetRowGroupRows)},
+		{"COLLECT_RATE_LIMIT", fmt.Sprint(c.CollectRateLimit)},
+		{"COLLECT_RATE_BURST", fmt.Sprint(c.CollectRateBurst)},
+		{"WRITE_KEY_ROTATION_GRACE", c.WriteKeyRotationGrace.String()},
+		{"ALERT_EVALUATION_INTERVAL", c.AlertEvaluationInterval.String()},
+		{"CHAOS_LATENCY", c.ChaosLatency.String()},
+		{"CHAOS_ERROR_RATE", fmt.Sprint(c.ChaosErrorRate)},
+		{"CHAOS_TIMEOUT_RATE", fmt.Sprint(c.ChaosTimeoutRate)},
+		{"CHAOS_TARGETS", strings.Join(c.ChaosTargets, ",")},
+	}
+	for i := range settings {
+		if settings[i].Value == "" {
+			settings[i].Value = "(vide)"
+		}
+	}
+	return settings
+}
+
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return fmt.Sprintf("*** (%d octets)", len(secret))
+}
+
+func redactSecrets(secrets map[string]string) string {
+	parts := make([]string, 0, len(secrets))
+	for key, secret := range secrets {
+		parts = append(parts, key+"="+redactSecret(secret))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// redactSigningKeys affiche les kid dans l'ordre (le premier signe), jamais les clés
+func redactSigningKeys(raw string) string {
+	var parts []string
+	for _, pair := range strings.Split(raw, ",") {
+		if kid, secret, found := strings.Cut(strings.TrimSpace(pair), "="); found {
+			parts = append(parts, kid+"="+redactSecret(secret))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// redactURL masque le mot de passe et l'utilisateur d'un DSN (clé Sentry, identifiants SQL)
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" {
+		return "*** (DSN non URL)"
+	}
+	if parsed.User != nil {
+		parsed.User = url.User("***")
+	}
+	// Les paramètres peuvent porter des secrets (password=, sslkey=) : on ne garde que les noms
+	keys := make([]string, 0)
+	for key := range parsed.Query() {
+		keys = append(keys, key+"=***")
+	}
+	sort.Strings(keys)
+	parsed.RawQuery = strings.Join(keys, "&")
+	return parsed.String()
+}
+
+func redactURLs(raws []string) string {
+	redacted := make([]string, len(raws))
+	for i, raw := range raws {
+		redacted[i] = redactURL(raw)
+	}
+	return strings.Join(redacted, ", ")
+}
+
+func formatQuotas(quotas map[string]int) string {
+	parts := make([]string, 0, len(quotas))
+	for resource, limit := range quotas {
+		parts = append(parts, resource+"="+strconv.Itoa(limit))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func formatTenantQuotas(tenants map[string]map[string]int) string {
+	parts := make([]string, 0, len(tenants))
+	for tenant, quotas := range tenants {
+		parts = append(parts, tenant+": "+formatQuotas(quotas))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "; ")
+}
+
+func formatDurations(durations map[string]time.Duration) string {
+	parts := make([]string, 0, len(durations))
+	for key, duration := range durations {
+		parts = append(parts, key+"="+duration.String())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func formatNetworks(networks []netip.Prefix) string {
+	parts := make([]string, 0, len(networks))
+	for _, network := range networks {
+		parts = append(parts, network.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatKeyValues(values map[string]string) string {
+	parts := make([]string, 0, len(values))
+	for key, value := range values {
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func formatGroupRoles(roles map[string]string) string {
+	parts := make([]string, 0, len(roles))
+	for group, role := range roles {
+		parts = append(parts, role+"="+group)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Settings réglages modifiables sans redémarrage : relus sur SIGHUP ou quand SETTINGS_FILE change,
+// sans toucher au reste de la configuration
+type Settings struct {
+	LogLevel     string
+	Quotas       map[string]int
+	TenantQuotas map[string]map[string]int
+}
+
+// LogLevels niveaux acceptés par LOG_LEVEL et PUT /admin/log-level
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// reloadableKeys seules variables acceptées dans SETTINGS_FILE
+var reloadableKeys = []string{"LOG_LEVEL", "QUOTAS", "TENANT_QUOTAS"}
+
+// LoadSettings lit les réglages rechargeables dans l'environnement, surchargés par les lignes
+// KEY=valeur de path (vide = environnement seul, # pour les commentaires) ; une ligne retirée du
+// fichier rend la valeur de l'environnement
+func LoadSettings(path string) (Settings, error) {
+	values := make(map[string]string, len(reloadableKeys))
+	for _, key := range reloadableKeys {
+		values[key] = os.Getenv(key)
+	}
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return Settings{}, fmt.Errorf("SETTINGS_FILE: %w", err)
+		}
+		for i, line := range strings.Split(string(raw), "\n") {
+			if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, value, found := strings.Cut(line, "=")
+			if key = strings.TrimSpace(key); !found || !slices.Contains(reloadableKeys, key) {
+				return Settings{}, fmt.Errorf("SETTINGS_FILE: ligne %d : variable attendue parmi %s", i+1, strings.Join(reloadableKeys, ", "))
+			}
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+
+	settings := Settings{LogLevel: values["LOG_LEVEL"]}
+	if settings.LogLevel == "" {
+		settings.LogLevel = "info"
+	}
+	if !slices.Contains(LogLevels, settings.LogLevel) {
+		return Settings{}, fmt.Errorf("LOG_LEVEL: valeur attendue parmi %s", strings.Join(LogLevels, ", "))
+	}
+	var err error
+	if settings.Quotas, err = parseQuotas("QUOTAS", values["QUOTAS"]); err != nil {
+		return Settings{}, err
+	}
+	if settings.TenantQuotas, err = parseTenantQuotas(values["TENANT_QUOTAS"]); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
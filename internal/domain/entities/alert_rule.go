@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"time"
+)
+
+// Conditions d'une règle d'alerte
+const (
+	// AlertDrop baisse d'au moins Threshold % par rapport à la fenêtre précédente
+	AlertDrop = "drop"
+	// AlertSpike hausse d'au moins Threshold % par rapport à la fenêtre précédente
+	AlertSpike = "spike"
+	// AlertAbove valeur de la fenêtre supérieure à Threshold
+	AlertAbove = "above"
+	// AlertBelow valeur de la fenêtre inférieure à Threshold
+	AlertBelow = "below"
+)
+
+var AlertConditions = []string{AlertDrop, AlertSpike, AlertAbove, AlertBelow}
+
+// MetricAlertEventType type de notification des alertes (déclenchement et retour à la normale)
+const MetricAlertEventType = "metric.alert"
+
+// AlertRule surveillance d'une métrique des agrégats quotidiens, évaluée par fenêtres successives
+// de WindowMinutes. La métrique est le nombre d'événements Event sur la fenêtre ou, avec PerEvent,
+// le rapport Event / PerEvent (taux d'erreur : login.failed / login.succeeded)
+type AlertRule struct {
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	Event         string  `json:"event"`
+	PerEvent      string  `json:"per_event,omitempty"`
+	Condition     string  `json:"condition"`
+	Threshold     float64 `json:"threshold"`
+	WindowMinutes int     `json:"window_minutes"`
+	// MinCount événements Event en dessous desquels la fenêtre de référence (drop, spike) n'est pas
+	// significative : pas d'alerte sur un volume trop faible
+	MinCount int `json:"min_count,omitempty"`
+	// Recipients utilisateurs notifiés (préférences de notification respectées) ; WebhookURL reçoit
+	// aussi chaque déclenchement et chaque retour à la normale
+	Recipients []int     `json:"recipients,omitempty"`
+	WebhookURL string    `json:"webhook_url,omitempty"`
+	Created    time.Time `json:"created"`
+
+	State AlertState `json:"state"`
+}
+
+// AlertState état d'évaluation : dernière observation des compteurs et dernière fenêtre mesurée
+type AlertState struct {
+	// ObservedAt / ObservedDay / ObservedCount / ObservedPerCount compteurs du jour à la dernière
+	// évaluation : la fenêtre suivante est la différence avec les agrégats courants
+	ObservedAt       time.Time `json:"observed_at"`
+	ObservedDay      time.Time `json:"-"`
+	ObservedCount    int       `json:"-"`
+	ObservedPerCount int       `json:"-"`
+	// Measured vrai dès qu'une fenêtre a été mesurée : LastValue / LastCount servent de référence
+	Measured  bool       `json:"measured"`
+	LastValue float64    `json:"last_value"`
+	LastCount int        `json:"last_count"`
+	Firing    bool       `json:"firing"`
+	FiredAt   *time.Time `json:"fired_at,omitempty"`
+}
+
+func NewAlertRule(name, event, perEvent, condition string, threshold float64, windowMinutes, minCount int, recipients []int, webhookURL string, now time.Time) *AlertRule {
+	return &AlertRule{
+		Name:          name,
+		Event:         event,
+		PerEvent:      perEvent,
+		Condition:     condition,
+		Threshold:     threshold,
+		WindowMinutes: windowMinutes,
+		MinCount:      minCount,
+		Recipients:    recipients,
+		WebhookURL:    webhookURL,
+		Created:       now,
+	}
+}
+
+func (r *AlertRule) Window() time.Duration {
+	return time.Duration(r.WindowMinutes) * time.Minute
+}
+
+// Breached la valeur de la fenêtre remplit la condition ; drop et spike exigent une fenêtre de
+// référence d'au moins MinCount événements (et non nulle)
+func (r *AlertRule) Breached(value float64) bool {
+	switch r.Condition {
+	case AlertAbove:
+		return value > r.Threshold
+	case AlertBelow:
+		return value < r.Threshold
+	}
+	if !r.State.Measured || r.State.LastValue <= 0 || r.State.LastCount < r.MinCount {
+		return false
+	}
+	change := (value - r.State.LastValue) / r.State.LastValue * 100
+	if r.Condition == AlertDrop {
+		return -change >= r.Threshold
+	}
+	return change >= r.Threshold
+}
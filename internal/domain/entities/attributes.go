@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// =============================================================================
+// ATTRIBUTS PERSONNALISÉS (MÉTADONNÉES TYPÉES)
+// =============================================================================
+
+// AttributeType type autorisé pour la valeur d'un attribut personnalisé
+type AttributeType string
+
+const (
+	AttributeString  AttributeType = "string"
+	AttributeNumber  AttributeType = "number"
+	AttributeBoolean AttributeType = "boolean"
+)
+
+var (
+	ErrUnknownAttribute      = errors.New("attribut non déclaré dans le schéma")
+	ErrInvalidAttributeValue = errors.New("valeur d'attribut invalide")
+	ErrInvalidAttributeType  = errors.New("type d'attribut invalide")
+)
+
+// maxAttributeStringLength longueur maximale d'une valeur texte (les attributs ne sont pas un stockage de documents)
+const maxAttributeStringLength = 512
+
+var attributeKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// AttributeSchema clés d'attributs autorisées et type de chacune
+// Un schéma vide n'autorise aucun attribut
+type AttributeSchema struct {
+	types map[string]AttributeType
+}
+
+func NewAttributeSchema(types map[string]AttributeType) (*AttributeSchema, error) {
+	for key, attributeType := range types {
+		if !attributeKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("%w : clé %q", ErrInvalidAttributeType, key)
+		}
+		switch attributeType {
+		case AttributeString, AttributeNumber, AttributeBoolean:
+		default:
+			return nil, fmt.Errorf("%w : %q pour %s", ErrInvalidAttributeType, attributeType, key)
+		}
+	}
+	return &AttributeSchema{types: maps.Clone(types)}, nil
+}
+
+// TypeOf type déclaré d'une clé ; false si la clé n'est pas autorisée
+func (s *AttributeSchema) TypeOf(key string) (AttributeType, bool) {
+	attributeType, ok := s.types[key]
+	return attributeType, ok
+}
+
+// Normalize vérifie une valeur (décodée du JSON) et la ramène à sa forme stockée :
+// string, float64 ou bool
+func (s *AttributeSchema) Normalize(key string, value any) (any, error) {
+	attributeType, ok := s.types[key]
+	if !ok {
+		return nil, fmt.Errorf("%w : %s", ErrUnknownAttribute, key)
+	}
+
+	switch attributeType {
+	case AttributeString:
+		if text, ok := value.(string); ok && len(text) <= maxAttributeStringLength {
+			return text, nil
+		}
+	case AttributeNumber:
+		switch number := value.(type) {
+		case float64:
+			return number, nil
+		case int:
+			return float64(number), nil
+		case int64:
+			return float64(number), nil
+		}
+	case AttributeBoolean:
+		if flag, ok := value.(bool); ok {
+			return flag, nil
+		}
+	}
+	return nil, fmt.Errorf("%w : %s attend le type %s", ErrInvalidAttributeValue, key, attributeType)
+}
+
+// ParseValue convertit une valeur reçue sous forme de texte (paramètre d'URL) selon le type déclaré
+func (s *AttributeSchema) ParseValue(key, raw string) (any, error) {
+	attributeType, ok := s.types[key]
+	if !ok {
+		return nil, fmt.Errorf("%w : %s", ErrUnknownAttribute, key)
+	}
+
+	switch attributeType {
+	case AttributeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %s attend le type %s", ErrInvalidAttributeValue, key, attributeType)
+		}
+		return number, nil
+	case AttributeBoolean:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %s attend le type %s", ErrInvalidAttributeValue, key, attributeType)
+		}
+		return flag, nil
+	}
+	return s.Normalize(key, raw)
+}
+
+// PatchAttributes applique un patch : une valeur nil supprime la clé, les clés absentes du patch
+// sont conservées. Tout le patch est validé avant d'être appliqué.
+// La map est remplacée, jamais modifiée en place : les copies de l'utilisateur restent intactes
+// Retourne les attributs modifiés (champs "attributes.<clé>"), vide si le patch ne change rien
+func (u *User) PatchAttributes(patch map[string]any, schema *AttributeSchema, now time.Time) ([]FieldChange, error) {
+	next := maps.Clone(u.Attributes)
+	if next == nil {
+		next = make(map[string]any, len(patch))
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			// Supprimer une clé retirée du schéma reste possible
+			delete(next, key)
+			continue
+		}
+		normalized, err := schema.Normalize(key, value)
+		if err != nil {
+			return nil, err
+		}
+		next[key] = normalized
+	}
+
+	changes := DiffAttributes(u.Attributes, next)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if len(next) == 0 {
+		next = nil
+	}
+	u.Attributes = next
+	u.Updated = now
+	return changes, nil
+}
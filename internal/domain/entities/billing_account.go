@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+)
+
+// SubscriptionStatus état d'un abonnement chez le fournisseur de facturation
+type SubscriptionStatus string
+
+const (
+	SubscriptionIncomplete SubscriptionStatus = "incomplete"
+	SubscriptionTrialing   SubscriptionStatus = "trialing"
+	SubscriptionActive     SubscriptionStatus = "active"
+	SubscriptionPastDue    SubscriptionStatus = "past_due"
+	SubscriptionCanceled   SubscriptionStatus = "canceled"
+	SubscriptionUnpaid     SubscriptionStatus = "unpaid"
+)
+
+// BillingAccount rattachement d'un tenant à son client et à son abonnement chez le fournisseur
+type BillingAccount struct {
+	TenantID       string
+	CustomerID     string
+	SubscriptionID string
+	Plan           string // vide tant qu'aucun abonnement n'a été souscrit
+	Status         SubscriptionStatus
+	// CurrentPeriodEnd fin de la période facturée en cours
+	CurrentPeriodEnd time.Time
+	// ReportedPeriod / ReportedCalls appels déjà déclarés au fournisseur pour le mois ReportedPeriod
+	ReportedPeriod string
+	ReportedCalls  int
+	Updated        time.Time
+}
+
+// PlanActive l'offre s'applique (quotas, fonctionnalités) : abonnement actif, en essai, ou en
+// retard de paiement (délai de grâce, le fournisseur relance) ; pas après résiliation ou impayé
+func (a *BillingAccount) PlanActive() bool {
+	if a.Plan == "" {
+		return false
+	}
+	switch a.Status {
+	case SubscriptionActive, SubscriptionTrialing, SubscriptionPastDue:
+		return true
+	}
+	return false
+}
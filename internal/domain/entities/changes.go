@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"slices"
+	"strings"
+)
+
+// =============================================================================
+// SUIVI DES MODIFICATIONS CHAMP PAR CHAMP
+// =============================================================================
+
+// FieldChange modification d'un champ : From absent pour un ajout, To absent pour une suppression
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from,omitempty"`
+	To    any    `json:"to,omitempty"`
+}
+
+// attributeFieldPrefix préfixe des champs d'attributs personnalisés dans les diffs
+const attributeFieldPrefix = "attributes."
+
+// DiffProfile champs du profil (name, email) qui diffèrent, dans cet ordre
+func DiffProfile(name, email, nextName, nextEmail string) []FieldChange {
+	var changes []FieldChange
+	if name != nextName {
+		changes = append(changes, FieldChange{Field: "name", From: name, To: nextName})
+	}
+	if email != nextEmail {
+		changes = append(changes, FieldChange{Field: "email", From: email, To: nextEmail})
+	}
+	return changes
+}
+
+// DiffAttributes attributs ajoutés, modifiés ou supprimés, triés par clé
+func DiffAttributes(before, after map[string]any) []FieldChange {
+	var changes []FieldChange
+	for key, from := range before {
+		to, kept := after[key]
+		switch {
+		case !kept:
+			changes = append(changes, FieldChange{Field: attributeFieldPrefix + key, From: from})
+		case to != from:
+			changes = append(changes, FieldChange{Field: attributeFieldPrefix + key, From: from, To: to})
+		}
+	}
+	for key, to := range after {
+		if _, existed := before[key]; !existed {
+			changes = append(changes, FieldChange{Field: attributeFieldPrefix + key, To: to})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b FieldChange) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+	return changes
+}
+
+// ChangedFields noms des champs modifiés (les valeurs, parfois personnelles, ne sont pas reprises)
+func ChangedFields(changes []FieldChange) []string {
+	if len(changes) == 0 {
+		return nil
+	}
+	fields := make([]string, len(changes))
+	for i, change := range changes {
+		fields[i] = change.Field
+	}
+	return fields
+}
@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidConsentStatus = errors.New("consentement invalide : granted ou denied attendu")
+	ErrInvalidConsentSource = errors.New("origine du consentement invalide : banner, settings, support ou api attendue")
+)
+
+// Statuts du consentement à la mesure d'audience
+const (
+	ConsentGranted = "granted"
+	ConsentDenied  = "denied"
+)
+
+// Origines d'un changement de consentement
+const (
+	ConsentFromBanner   = "banner"   // bandeau de consentement
+	ConsentFromSettings = "settings" // préférences du compte
+	ConsentFromSupport  = "support"  // demande traitée par le support
+	ConsentFromAPI      = "api"      // intégration serveur à serveur
+)
+
+// AnalyticsConsent valeur du consentement d'un utilisateur à la mesure d'audience
+type AnalyticsConsent struct {
+	Status string `json:"status"`
+}
+
+func NewAnalyticsConsent(status string) (AnalyticsConsent, error) {
+	if status != ConsentGranted && status != ConsentDenied {
+		return AnalyticsConsent{}, ErrInvalidConsentStatus
+	}
+	return AnalyticsConsent{Status: status}, nil
+}
+
+// Granted les événements de l'utilisateur peuvent lui être rattachés
+func (c AnalyticsConsent) Granted() bool {
+	return c.Status == ConsentGranted
+}
+
+// ConsentChange changement de consentement, conservé comme preuve : qui, quand, depuis où et
+// depuis quel client. Les changements successifs ne sont jamais modifiés
+type ConsentChange struct {
+	ID      int64            `json:"id"`
+	UserID  int              `json:"user_id"`
+	Consent AnalyticsConsent `json:"consent"`
+	Source  string           `json:"source"`
+	// ActorID auteur du changement quand ce n'est pas l'utilisateur (support) ; 0 sinon
+	ActorID   int       `json:"actor_id,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func NewConsentChange(userID int, consent AnalyticsConsent, source string, at time.Time) (*ConsentChange, error) {
+	switch source {
+	case ConsentFromBanner, ConsentFromSettings, ConsentFromSupport, ConsentFromAPI:
+	default:
+		return nil, ErrInvalidConsentSource
+	}
+	return &ConsentChange{UserID: userID, Consent: consent, Source: source, ChangedAt: at}, nil
+}
@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownEmailTemplate = errors.New("modèle d'email inconnu")
+	ErrInvalidLocale        = errors.New("locale invalide (ex: fr, fr-FR)")
+)
+
+// Modèles d'emails modifiables par les administrateurs
+const (
+	EmailTemplateWelcome = "welcome"
+	EmailTemplateReset   = "reset"
+	EmailTemplateInvite  = "invite"
+)
+
+// emailTemplateVariables seules variables qu'un modèle peut référencer, et seules données
+// transmises à son exécution : un modèle ne peut rien lire d'autre
+var emailTemplateVariables = map[string][]string{
+	EmailTemplateWelcome: {"name", "email"},
+	EmailTemplateReset:   {"name", "email", "reset_url", "expires_at"},
+	EmailTemplateInvite:  {"email", "inviter", "tenant", "invite_url", "expires_at"},
+}
+
+// maxEmailTemplateSize taille maximale de chaque partie (sujet, texte, HTML)
+const maxEmailTemplateSize = 64 << 10
+
+// EmailTemplateVariables variables autorisées du modèle ; false si le nom est inconnu
+func EmailTemplateVariables(name string) ([]string, bool) {
+	variables, ok := emailTemplateVariables[name]
+	return variables, ok
+}
+
+// EmailTemplateKey identifie une lignée de versions. TenantID vide = modèle par défaut de l'instance,
+// Locale vide = modèle utilisé quand aucune locale ne correspond
+type EmailTemplateKey struct {
+	Name     string `json:"name"`
+	TenantID string `json:"tenant_id,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+}
+
+// EmailTemplate version d'un modèle d'email ; les versions ne sont jamais modifiées,
+// chaque enregistrement en ajoute une (Version attribuée par le dépôt)
+type EmailTemplate struct {
+	EmailTemplateKey
+	Version int       `json:"version"`
+	Subject string    `json:"subject"`
+	Text    string    `json:"text,omitempty"`
+	HTML    string    `json:"html,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func NewEmailTemplate(key EmailTemplateKey, subject, text, html string, now time.Time) (*EmailTemplate, error) {
+	if _, ok := emailTemplateVariables[key.Name]; !ok {
+		return nil, ErrUnknownEmailTemplate
+	}
+	locale, err := NormalizeLocale(key.Locale)
+	if err != nil {
+		return nil, err
+	}
+	key.Locale = locale
+	key.TenantID = strings.TrimSpace(key.TenantID)
+
+	if strings.TrimSpace(subject) == "" {
+		return nil, errors.New("sujet obligatoire")
+	}
+	if strings.ContainsAny(subject, "\r\n") {
+		return nil, errors.New("le sujet tient sur une ligne")
+	}
+	if strings.TrimSpace(text) == "" && strings.TrimSpace(html) == "" {
+		return nil, errors.New("contenu obligatoire : text et/ou html")
+	}
+	if len(subject) > maxEmailTemplateSize || len(text) > maxEmailTemplateSize || len(html) > maxEmailTemplateSize {
+		return nil, errors.New("modèle trop volumineux (64 Kio par partie)")
+	}
+
+	return &EmailTemplate{
+		EmailTemplateKey: key,
+		Subject:          subject,
+		Text:             text,
+		HTML:             html,
+		Created:          now,
+	}, nil
+}
+
+// NormalizeLocale étiquette de langue simplifiée : "fr" ou "fr-FR" (langue en minuscules, région en
+// majuscules, "_" accepté) ; vide reste vide
+func NormalizeLocale(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	language, region, hasRegion := strings.Cut(strings.ReplaceAll(raw, "_", "-"), "-")
+	if !isLetters(language, 2, 3) || (hasRegion && !isLetters(region, 2, 2)) {
+		return "", ErrInvalidLocale
+	}
+	if hasRegion {
+		return strings.ToLower(language) + "-" + strings.ToUpper(region), nil
+	}
+	return strings.ToLower(language), nil
+}
+
+// LocaleFallbacks locales essayées dans l'ordre : "fr-CA" → "fr-CA", "fr", ""
+func LocaleFallbacks(locale string) []string {
+	if locale == "" {
+		return []string{""}
+	}
+	if language, _, hasRegion := strings.Cut(locale, "-"); hasRegion {
+		return []string{locale, language, ""}
+	}
+	return []string{locale, ""}
+}
+
+func isLetters(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"time"
+)
+
+// ExportStatus étape d'un export asynchrone
+type ExportStatus string
+
+const (
+	ExportQueued    ExportStatus = "queued"
+	ExportRunning   ExportStatus = "running"
+	ExportCompleted ExportStatus = "completed"
+	ExportFailed    ExportStatus = "failed"
+)
+
+// ExportJob export volumineux produit en tâche de fond : le client reçoit l'identifiant du job,
+// interroge son statut puis télécharge le fichier une fois terminé, jusqu'à ExpiresAt
+type ExportJob struct {
+	ID      string `json:"id"`
+	OwnerID int    `json:"owner_id"`
+	// Kind source exportée ("audit", "events", "rollups") ; Filters ses critères, validés au démarrage
+	Kind    string            `json:"kind"`
+	Filters map[string]string `json:"filters,omitempty"`
+	Status  ExportStatus      `json:"status"`
+	Rows    int               `json:"rows"`
+	// Format format du fichier ("csv", "parquet") ; Compression propre au format
+	Format      string `json:"format"`
+	Compression string `json:"compression,omitempty"`
+	// Error cause de l'échec, présentable au client
+	Error string `json:"error,omitempty"`
+	// FileKey clé du fichier produit dans le stockage
+	FileKey  string     `json:"-"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	// ExpiresAt fichier supprimé à cette date (job terminé) ; job abandonné s'il n'a pas abouti
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewExportJob(id string, ownerID int, kind string, filters map[string]string, now, expiresAt time.Time) *ExportJob {
+	return &ExportJob{
+		ID:        id,
+		OwnerID:   ownerID,
+		Kind:      kind,
+		Filters:   filters,
+		Status:    ExportQueued,
+		Created:   now,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (j *ExportJob) Start(now time.Time) {
+	j.Status = ExportRunning
+	j.Started = &now
+}
+
+// Complete le fichier fileKey est disponible jusqu'à expiresAt
+func (j *ExportJob) Complete(fileKey string, rows int, now, expiresAt time.Time) {
+	j.Status = ExportCompleted
+	j.FileKey = fileKey
+	j.Rows = rows
+	j.Finished = &now
+	j.ExpiresAt = expiresAt
+}
+
+func (j *ExportJob) Fail(reason string, now time.Time) {
+	j.Status = ExportFailed
+	j.Error = reason
+	j.Finished = &now
+}
+
+func (j *ExportJob) Expired(now time.Time) bool {
+	return !now.Before(j.ExpiresAt)
+}
@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// NotificationChannel canal de diffusion d'une notification
+type NotificationChannel string
+
+const (
+	ChannelEmail NotificationChannel = "email"
+	ChannelSMS   NotificationChannel = "sms"
+	ChannelInApp NotificationChannel = "in_app"
+)
+
+// NotificationChannels liste des canaux supportés
+var NotificationChannels = []NotificationChannel{ChannelEmail, ChannelSMS, ChannelInApp}
+
+// NotifiableEventTypes événements du domaine pouvant déclencher une notification
+var NotifiableEventTypes = []string{"user.created", "user.profile_updated", "user.new_device_login", MetricAlertEventType}
+
+// NotificationPreference choix d'un utilisateur pour un couple (canal, type d'événement)
+type NotificationPreference struct {
+	UserID    int                 `json:"user_id"`
+	Channel   NotificationChannel `json:"channel"`
+	EventType string              `json:"event_type"`
+	Enabled   bool                `json:"enabled"`
+	Updated   time.Time           `json:"updated"`
+}
+
+func NewNotificationPreference(userID int, channel NotificationChannel, eventType string, enabled bool, now time.Time) (*NotificationPreference, error) {
+	if err := validateChannel(channel); err != nil {
+		return nil, err
+	}
+
+	if err := validateNotifiableEventType(eventType); err != nil {
+		return nil, err
+	}
+
+	return &NotificationPreference{
+		UserID:    userID,
+		Channel:   channel,
+		EventType: eventType,
+		Enabled:   enabled,
+		Updated:   now,
+	}, nil
+}
+
+// DefaultNotificationEnabled valeur appliquée tant que l'utilisateur n'a rien choisi
+// Le SMS est opt-in (coût, intrusivité) ; email et in-app sont actifs par défaut
+func DefaultNotificationEnabled(channel NotificationChannel) bool {
+	return channel != ChannelSMS
+}
+
+// Notification message livré dans la boîte de réception in-app d'un utilisateur
+type Notification struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	EventType string     `json:"event_type"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Created   time.Time  `json:"created"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+func NewNotification(userID int, eventType, title, body string, now time.Time) (*Notification, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, errors.New("titre de notification vide")
+	}
+
+	return &Notification{
+		UserID:    userID,
+		EventType: eventType,
+		Title:     strings.TrimSpace(title),
+		Body:      body,
+		Created:   now,
+	}, nil
+}
+
+// MarkAsRead marque la notification comme lue ; retourne false si elle l'était déjà
+func (n *Notification) MarkAsRead(at time.Time) bool {
+	if n.ReadAt != nil {
+		return false
+	}
+	n.ReadAt = &at
+	return true
+}
+
+func validateChannel(channel NotificationChannel) error {
+	for _, known := range NotificationChannels {
+		if channel == known {
+			return nil
+		}
+	}
+	return errors.New("canal de notification inconnu")
+}
+
+func validateNotifiableEventType(eventType string) error {
+	for _, known := range NotifiableEventTypes {
+		if eventType == known {
+			return nil
+		}
+	}
+	return errors.New("type d'événement non notifiable")
+}
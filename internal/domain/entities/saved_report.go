@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+)
+
+// SavedReport requête analytics enregistrée par un utilisateur : expression de filtre sur les
+// événements et fenêtre glissante des Days derniers jours, recalculée à chaque exécution
+type SavedReport struct {
+	ID      int    `json:"id"`
+	OwnerID int    `json:"owner_id"`
+	Name    string `json:"name"`
+	// Where expression de filtre, validée à l'enregistrement ; vide = tous les événements
+	Where    string    `json:"where,omitempty"`
+	Interval string    `json:"interval"`
+	Days     int       `json:"days"`
+	Created  time.Time `json:"created"`
+}
+
+func NewSavedReport(ownerID int, name, where, interval string, days int, now time.Time) *SavedReport {
+	return &SavedReport{
+		OwnerID:  ownerID,
+		Name:     name,
+		Where:    where,
+		Interval: interval,
+		Days:     days,
+		Created:  now,
+	}
+}
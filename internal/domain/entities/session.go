@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// Types d'appareil reconnus dans le User-Agent
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// DeviceInfo appareil déduit du User-Agent ; les versions sont ignorées : une mise à jour du
+// navigateur ne fait pas un nouvel appareil
+type DeviceInfo struct {
+	Type    string `json:"type"`
+	OS      string `json:"os,omitempty"`
+	Browser string `json:"browser,omitempty"`
+}
+
+// Fingerprint clé de comparaison des appareils d'un utilisateur
+func (d DeviceInfo) Fingerprint() string {
+	return d.Type + "|" + d.OS + "|" + d.Browser
+}
+
+// GeoLocation localisation approximative d'une adresse IP ; coordonnées à zéro = inconnues
+type GeoLocation struct {
+	Country   string  `json:"country,omitempty"` // code ISO 3166-1 alpha-2
+	City      string  `json:"city,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
+
+// HasCoordinates la base de géolocalisation fournit la position (voyage impossible)
+func (l *GeoLocation) HasCoordinates() bool {
+	return l != nil && (l.Latitude != 0 || l.Longitude != 0)
+}
+
+// DistanceKm distance à vol d'oiseau (formule de haversine)
+func (l *GeoLocation) DistanceKm(other *GeoLocation) float64 {
+	const earthRadiusKm = 6371
+	lat1, lat2 := l.Latitude*math.Pi/180, other.Latitude*math.Pi/180
+	dLat, dLon := lat2-lat1, (other.Longitude-l.Longitude)*math.Pi/180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Session connexion d'un utilisateur : appareil, adresse et localisation au moment du login.
+// Les jetons d'accès sont sans état : la session est un historique, pas un droit révocable
+type Session struct {
+	ID        string       `json:"id"`
+	UserID    int          `json:"user_id"`
+	Device    DeviceInfo   `json:"device"`
+	UserAgent string       `json:"user_agent,omitempty"`
+	IP        string       `json:"ip,omitempty"`
+	Location  *GeoLocation `json:"location,omitempty"`
+	// NewDevice premier login de l'utilisateur depuis cet appareil
+	NewDevice bool      `json:"new_device"`
+	Created   time.Time `json:"created"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTermsVersion = errors.New("version des conditions d'utilisation invalide")
+
+// TermsAcceptance acceptation d'une version des conditions d'utilisation par un utilisateur
+// Les acceptations successives sont conservées : elles prouvent ce qui a été accepté, et quand
+type TermsAcceptance struct {
+	UserID     int       `json:"user_id"`
+	Version    string    `json:"version"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+func NewTermsAcceptance(userID int, version string, at time.Time) (*TermsAcceptance, error) {
+	version = strings.TrimSpace(version)
+	if version == "" || len(version) > 64 {
+		return nil, ErrInvalidTermsVersion
+	}
+
+	return &TermsAcceptance{
+		UserID:     userID,
+		Version:    version,
+		AcceptedAt: at,
+	}, nil
+}
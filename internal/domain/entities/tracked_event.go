@@ -0,0 +1,207 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"time"
+)
+
+// =============================================================================
+// ÉVÉNEMENTS ANALYTICS ENVOYÉS PAR LES CLIENTS
+// =============================================================================
+
+var (
+	ErrInvalidEventName     = errors.New("nom d'événement invalide : minuscules, chiffres, _ et . (100 caractères max)")
+	ErrInvalidEventProperty = errors.New("propriété d'événement invalide")
+	ErrTooManyProperties    = errors.New("trop de propriétés sur l'événement")
+)
+
+const (
+	// MaxEventProperties propriétés acceptées par événement
+	MaxEventProperties = 50
+	// MaxEventPropertyLength longueur maximale d'une valeur texte
+	MaxEventPropertyLength = 255
+)
+
+var (
+	eventNameRegex        = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+	eventPropertyKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+)
+
+// TrackedEvent événement produit ("checkout.completed", "page_viewed") envoyé par un client :
+// distinct des événements du domaine, il ne déclenche aucune règle métier
+type TrackedEvent struct {
+	ID     int64
+	Name   string
+	UserID int // 0 : événement anonyme
+	// Properties valeurs scalaires uniquement : string, float64 ou bool
+	Properties map[string]any
+	OccurredAt time.Time
+	ReceivedAt time.Time
+	// SampleRate nombre d'événements réels que celui-ci représente : 1 hors échantillonnage,
+	// plus quand l'événement a été retenu dans un réservoir (les agrégats multiplient par ce poids)
+	SampleRate float64
+}
+
+// NewTrackedEvent valide le nom et normalise les propriétés ; occurredAt zéro = receivedAt
+func NewTrackedEvent(name string, userID int, properties map[string]any, occurredAt, receivedAt time.Time) (*TrackedEvent, error) {
+	event := &TrackedEvent{}
+	if err := event.Init(name, userID, properties, occurredAt, receivedAt); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Init renseigne un événement existant comme NewTrackedEvent, en réutilisant sa map de propriétés :
+// chemin d'ingestion avec sync.Pool, l'événement ne doit plus être référencé ailleurs
+func (e *TrackedEvent) Init(name string, userID int, properties map[string]any, occurredAt, receivedAt time.Time) error {
+	if err := ValidateEventName(name); err != nil {
+		return err
+	}
+	if len(properties) > MaxEventProperties {
+		return fmt.Errorf("%w (%d max)", ErrTooManyProperties, MaxEventProperties)
+	}
+
+	normalized := e.Properties
+	clear(normalized)
+	if normalized == nil && len(properties) > 0 {
+		normalized = make(map[string]any, len(properties))
+	}
+	for key, value := range properties {
+		stored, keep, err := normalizeEventProperty(key, value)
+		if err != nil {
+			return err
+		}
+		if keep {
+			normalized[key] = stored
+		}
+	}
+	if len(normalized) == 0 {
+		normalized = nil
+	}
+
+	receivedAt = receivedAt.UTC()
+	if occurredAt.IsZero() {
+		occurredAt = receivedAt
+	}
+	*e = TrackedEvent{
+		Name:       name,
+		UserID:     userID,
+		Properties: normalized,
+		OccurredAt: occurredAt.UTC(),
+		ReceivedAt: receivedAt,
+		SampleRate: 1,
+	}
+	return nil
+}
+
+// Propriétés déduites du User-Agent du client (AddDeviceProperties)
+const (
+	DevicePropertyType    = "ua_device"
+	DevicePropertyOS      = "ua_os"
+	DevicePropertyBrowser = "ua_browser"
+)
+
+// AddDeviceProperties complète les propriétés avec l'appareil du client, sans écraser celles
+// envoyées par le client ni dépasser MaxEventProperties
+func (e *TrackedEvent) AddDeviceProperties(device DeviceInfo) {
+	for _, property := range [...][2]string{
+		{DevicePropertyType, device.Type},
+		{DevicePropertyOS, device.OS},
+		{DevicePropertyBrowser, device.Browser},
+	} {
+		if property[1] == "" || len(e.Properties) >= MaxEventProperties {
+			continue
+		}
+		if _, exists := e.Properties[property[0]]; exists {
+			continue
+		}
+		if e.Properties == nil {
+			e.Properties = make(map[string]any, 3)
+		}
+		e.Properties[property[0]] = property[1]
+	}
+}
+
+// Reset remet l'événement à zéro avant son retour dans un sync.Pool ; la map de propriétés,
+// vidée, est gardée pour le prochain Init
+func (e *TrackedEvent) Reset() {
+	properties := e.Properties
+	clear(properties)
+	*e = TrackedEvent{Properties: properties}
+}
+
+func ValidateEventName(name string) error {
+	if len(name) > 100 || !eventNameRegex.MatchString(name) {
+		return ErrInvalidEventName
+	}
+	return nil
+}
+
+// NormalizeEventProperties vérifie des propriétés décodées du JSON et les ramène à leur forme
+// stockée (string, float64 ou bool) ; une valeur nil est ignorée, objets et tableaux sont refusés
+// La map d'origine n'est jamais modifiée ; nil si aucune propriété ne reste
+func NormalizeEventProperties(properties map[string]any) (map[string]any, error) {
+	if len(properties) > MaxEventProperties {
+		return nil, fmt.Errorf("%w (%d max)", ErrTooManyProperties, MaxEventProperties)
+	}
+
+	normalized := make(map[string]any, len(properties))
+	for key, value := range properties {
+		stored, keep, err := normalizeEventProperty(key, value)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			normalized[key] = stored
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// IsEventPropertyKey clé de propriété acceptée : minuscules, chiffres et _, 64 caractères max
+func IsEventPropertyKey(key string) bool {
+	return eventPropertyKeyRegex.MatchString(key)
+}
+
+// normalizeEventProperty forme stockée d'une propriété ; false pour une valeur nil (ignorée)
+func normalizeEventProperty(key string, value any) (any, bool, error) {
+	if !eventPropertyKeyRegex.MatchString(key) {
+		return nil, false, fmt.Errorf("%w : clé %q", ErrInvalidEventProperty, key)
+	}
+	switch typed := value.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		if len(typed) > MaxEventPropertyLength {
+			return nil, false, fmt.Errorf("%w : %s dépasse %d caractères", ErrInvalidEventProperty, key, MaxEventPropertyLength)
+		}
+		return typed, true, nil
+	case bool:
+		return typed, true, nil
+	case float64:
+		if math.IsNaN(typed) || math.IsInf(typed, 0) {
+			return nil, false, fmt.Errorf("%w : %s n'est pas un nombre fini", ErrInvalidEventProperty, key)
+		}
+		return typed, true, nil
+	case int:
+		return float64(typed), true, nil
+	case int64:
+		return float64(typed), true, nil
+	default:
+		return nil, false, fmt.Errorf("%w : %s doit être un texte, un nombre ou un booléen", ErrInvalidEventProperty, key)
+	}
+}
+
+// Clone copie indépendante (propriétés comprises) : les dépôts en mémoire ne partagent jamais leurs maps
+func (e *TrackedEvent) Clone() *TrackedEvent {
+	clone := *e
+	clone.Properties = maps.Clone(e.Properties)
+	return &clone
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+var benchEventProperties = map[string]any{"plan": "pro", "amount": 49.5, "trial": false, "seats": 3}
+
+// BenchmarkTrackedEvent new : NewTrackedEvent à chaque événement ; pooled : Init/Reset sur un
+// événement réutilisé, comme le chemin d'ingestion
+func BenchmarkTrackedEvent(b *testing.B) {
+	now := time.Now()
+
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := NewTrackedEvent("checkout.completed", 42, benchEventProperties, time.Time{}, now); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		pool := sync.Pool{New: func() any { return new(TrackedEvent) }}
+		b.ReportAllocs()
+		for b.Loop() {
+			event := pool.Get().(*TrackedEvent)
+			if err := event.Init("checkout.completed", 42, benchEventProperties, time.Time{}, now); err != nil {
+				b.Fatal(err)
+			}
+			event.Reset()
+			pool.Put(event)
+		}
+	})
+}
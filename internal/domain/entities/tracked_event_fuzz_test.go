@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// FuzzNormalizeEventProperties part du JSON tel que reçu par POST /analytics/track
+func FuzzNormalizeEventProperties(f *testing.F) {
+	for _, seed := range []string{
+		`{"path":"/pricing","plan":"pro","amount":49.9,"trial":true}`,
+		`{"ignored":null}`,
+		`{"nested":{"a":1}}`,
+		`{"list":[1,2]}`,
+		`{"Upper":"x"}`,
+		`{"big":1e308,"small":-1e-308}`,
+		`{}`,
+		`null`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var properties map[string]any
+		if err := json.Unmarshal(data, &properties); err != nil {
+			return
+		}
+		original := maps.Clone(properties)
+
+		normalized, err := NormalizeEventProperties(properties)
+		if !reflect.DeepEqual(properties, original) {
+			t.Fatal("input properties were modified")
+		}
+		if err != nil {
+			if normalized != nil {
+				t.Fatal("properties returned alongside an error")
+			}
+			return
+		}
+		if len(normalized) > MaxEventProperties {
+			t.Fatalf("%d properties accepted", len(normalized))
+		}
+		for key, value := range normalized {
+			switch value.(type) {
+			case string, float64, bool:
+			default:
+				t.Fatalf("property %q has unexpected type %T", key, value)
+			}
+		}
+
+		// Les propriétés stockées doivent survivre à un aller-retour JSON (colonne JSONB, index)
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			t.Fatalf("normalized properties not encodable: %v", err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if again, err := NormalizeEventProperties(decoded); err != nil || !reflect.DeepEqual(again, normalized) {
+			t.Fatalf("normalization not stable across a JSON round trip: %v", err)
+		}
+	})
+}
+
+func FuzzNewTrackedEvent(f *testing.F) {
+	f.Add("checkout.completed", `{"amount":12}`)
+	f.Add("page_viewed", `{}`)
+	f.Add("a..b", `{}`)
+	f.Add("Checkout", `{}`)
+
+	receivedAt := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, name, rawProperties string) {
+		var properties map[string]any
+		_ = json.Unmarshal([]byte(rawProperties), &properties)
+
+		event, err := NewTrackedEvent(name, 1, properties, time.Time{}, receivedAt)
+		if err != nil {
+			return
+		}
+		if ValidateEventName(event.Name) != nil || event.SampleRate != 1 || !event.OccurredAt.Equal(receivedAt) {
+			t.Fatalf("invalid tracked event %+v", event)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package entities
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUploadExpired          = errors.New("session d'upload expirée")
+	ErrUploadClosed           = errors.New("session d'upload terminée")
+	ErrUploadIncomplete       = errors.New("fragments manquants")
+	ErrInvalidUploadChunk     = errors.New("fragment invalide")
+	ErrUploadChecksumMismatch = errors.New("somme de contrôle différente de celle annoncée")
+)
+
+// UploadStatus étape d'une session d'upload
+type UploadStatus string
+
+const (
+	UploadPending   UploadStatus = "pending"
+	UploadCompleted UploadStatus = "completed"
+	// UploadFailed le fichier assemblé ne correspond pas à la somme de contrôle annoncée
+	UploadFailed UploadStatus = "failed"
+)
+
+// Usages d'un fichier envoyé : le consommateur ne relit que les uploads de son usage
+const (
+	UploadPurposeUserImport = "user_import"
+)
+
+var uploadPurposes = []string{UploadPurposeUserImport}
+
+// UploadSession envoi d'un fichier volumineux en fragments de ChunkSize octets (le dernier plus
+// court), dans n'importe quel ordre et en plusieurs fois : une coupure ne fait renvoyer que les
+// fragments manquants. Le fichier est assemblé une fois tous les fragments reçus, puis vérifié
+// contre la somme SHA-256 annoncée à l'ouverture
+type UploadSession struct {
+	ID        string `json:"id"`
+	OwnerID   int    `json:"owner_id"`
+	Purpose   string `json:"purpose"`
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	ChunkSize int64  `json:"chunk_size"`
+	// Checksum SHA-256 (hex) du fichier complet, annoncée par le client
+	Checksum string `json:"checksum"`
+	// Chunks SHA-256 (hex) de chaque fragment reçu, "" tant qu'il manque
+	Chunks []string     `json:"-"`
+	Status UploadStatus `json:"status"`
+	// FileKey clé du fichier assemblé dans le stockage, une fois la session terminée
+	FileKey   string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+func NewUploadSession(id string, ownerID int, purpose, filename string, size, chunkSize int64, checksum string, now, expiresAt time.Time) (*UploadSession, error) {
+	if !isUploadPurpose(purpose) {
+		return nil, fmt.Errorf("usage d'upload inconnu : %q (%s)", purpose, strings.Join(uploadPurposes, ", "))
+	}
+	filename = strings.TrimSpace(filename)
+	if filename == "" || len(filename) > 255 || strings.ContainsAny(filename, "/\\\x00") {
+		return nil, errors.New("nom de fichier invalide")
+	}
+	if size <= 0 {
+		return nil, errors.New("taille de fichier invalide")
+	}
+	if chunkSize <= 0 {
+		return nil, errors.New("taille de fragment invalide")
+	}
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+	if !IsSHA256Hex(checksum) {
+		return nil, errors.New("checksum : SHA-256 du fichier en hexadécimal attendu")
+	}
+
+	return &UploadSession{
+		ID:        id,
+		OwnerID:   ownerID,
+		Purpose:   purpose,
+		Filename:  filename,
+		Size:      size,
+		ChunkSize: chunkSize,
+		Checksum:  checksum,
+		Chunks:    make([]string, int((size+chunkSize-1)/chunkSize)),
+		Status:    UploadPending,
+		ExpiresAt: expiresAt,
+		Created:   now,
+		Updated:   now,
+	}, nil
+}
+
+// ChunkLength taille attendue du fragment index
+func (s *UploadSession) ChunkLength(index int) (int64, error) {
+	if index < 0 || index >= len(s.Chunks) {
+		return 0, fmt.Errorf("%w : index %d hors de [0, %d]", ErrInvalidUploadChunk, index, len(s.Chunks)-1)
+	}
+	if index == len(s.Chunks)-1 {
+		return s.Size - int64(index)*s.ChunkSize, nil
+	}
+	return s.ChunkSize, nil
+}
+
+// AcceptChunk enregistre la réception du fragment index ; un fragment renvoyé remplace le précédent
+func (s *UploadSession) AcceptChunk(index int, checksum string, now time.Time) error {
+	if err := s.checkWritable(now); err != nil {
+		return err
+	}
+	if _, err := s.ChunkLength(index); err != nil {
+		return err
+	}
+	s.Chunks[index] = checksum
+	s.Updated = now
+	return nil
+}
+
+// Missing index des fragments pas encore reçus, dans l'ordre
+func (s *UploadSession) Missing() []int {
+	missing := []int{}
+	for index, checksum := range s.Chunks {
+		if checksum == "" {
+			missing = append(missing, index)
+		}
+	}
+	return missing
+}
+
+// CanComplete tous les fragments sont reçus et la session est encore ouverte
+func (s *UploadSession) CanComplete(now time.Time) error {
+	if err := s.checkWritable(now); err != nil {
+		return err
+	}
+	if missing := s.Missing(); len(missing) > 0 {
+		return fmt.Errorf("%w : %d sur %d", ErrUploadIncomplete, len(missing), len(s.Chunks))
+	}
+	return nil
+}
+
+// Complete le fichier assemblé sous fileKey correspond à la somme annoncée
+func (s *UploadSession) Complete(fileKey string, now time.Time) {
+	s.Status = UploadCompleted
+	s.FileKey = fileKey
+	s.Updated = now
+}
+
+// Fail le fichier assemblé ne correspond pas : la session est close, le client en ouvre une autre
+func (s *UploadSession) Fail(now time.Time) {
+	s.Status = UploadFailed
+	s.Updated = now
+}
+
+func (s *UploadSession) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+func (s *UploadSession) checkWritable(now time.Time) error {
+	if s.Status != UploadPending {
+		return ErrUploadClosed
+	}
+	if s.Expired(now) {
+		return ErrUploadExpired
+	}
+	return nil
+}
+
+// IsSHA256Hex empreinte SHA-256 en hexadécimal minuscule
+func IsSHA256Hex(value string) bool {
+	decoded, err := hex.DecodeString(value)
+	return err == nil && len(decoded) == 32 && strings.ToLower(value) == value
+}
+
+func isUploadPurpose(purpose string) bool {
+	for _, known := range uploadPurposes {
+		if purpose == known {
+			return true
+		}
+	}
+	return false
+}
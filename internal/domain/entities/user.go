@@ -5,6 +5,22 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
+)
+
+// UserStatus état du cycle de vie d'un compte
+type UserStatus string
+
+const (
+	UserStatusActive      UserStatus = "active"
+	UserStatusDeactivated UserStatus = "deactivated" // à la demande de l'utilisateur ou d'un admin, réactivable
+	UserStatusBanned      UserStatus = "banned"      // décision de modération
+)
+
+var (
+	ErrUserAlreadyActive = errors.New("le compte est déjà actif")
+	ErrUserBannedStatus  = errors.New("un compte banni ne peut être que réactivé")
+	ErrInvalidTimeZone   = errors.New("fuseau horaire invalide (ex: Europe/Paris)")
 )
 
 type User struct {
@@ -14,9 +30,40 @@ type User struct {
 	Password string    `json:"password,omitempty"`
 	Created  time.Time `json:"created"`
 	Updated  time.Time `json:"updated"`
+
+	// WeeklyDigest préférence d'opt-in pour le résumé d'activité hebdomadaire
+	WeeklyDigest bool `json:"weekly_digest"`
+	// Phone numéro E.164 optionnel, utilisé pour les notifications SMS
+	Phone string `json:"phone,omitempty"`
+	// Status cycle de vie du compte ; vide (données antérieures) équivaut à actif
+	Status UserStatus `json:"status,omitempty"`
+	// Handle identifiant public optionnel et unique (stocké normalisé, sans "@")
+	Handle string `json:"handle,omitempty"`
+	// LastLoginAt dernière authentification réussie ; nil si le compte ne s'est jamais connecté
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	// CleanupFlaggedAt date à laquelle le compte a été signalé pour nettoyage (inactivité prolongée)
+	CleanupFlaggedAt *time.Time `json:"cleanup_flagged_at,omitempty"`
+	// Attributes métadonnées libres, typées par le schéma du tenant (voir PatchAttributes)
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// EmailUndeliverableAt date à laquelle le fournisseur d'email a rejeté l'adresse ; plus aucun
+	// email ne lui est envoyé jusqu'à ce qu'elle change. EmailUndeliverableReason "bounce" ou "complaint"
+	EmailUndeliverableAt     *time.Time `json:"email_undeliverable_at,omitempty"`
+	EmailUndeliverableReason string     `json:"email_undeliverable_reason,omitempty"`
+	// Locale / TimeZone préférences d'affichage des dates et des nombres (emails, exports, champs
+	// display de l'API) ; vides = valeurs par défaut de l'instance
+	Locale   string `json:"locale,omitempty"`
+	TimeZone string `json:"time_zone,omitempty"`
 }
 
-func NewUser(email, name, password string) (*User, error) {
+// Motifs de rejet d'une adresse par le fournisseur d'email
+const (
+	EmailBounced    = "bounce"    // rebond définitif : adresse inexistante ou refusée
+	EmailComplained = "complaint" // signalée comme spam par le destinataire
+)
+
+// NewUser now : horodatage de création (Clock du use case)
+func NewUser(email, name, password string, now time.Time) (*User, error) {
+	email = normalizeEmail(email)
 	if err := validateEmail(email); err != nil {
 		return nil, err
 	}
@@ -29,13 +76,13 @@ func NewUser(email, name, password string) (*User, error) {
 		return nil, err
 	}
 
-	now := time.Now()
 	return &User{
-		Email:    strings.ToLower(strings.TrimSpace(email)),
+		Email:    email,
 		Name:     strings.TrimSpace(name),
 		Password: password,
 		Created:  now,
 		Updated:  now,
+		Status:   UserStatusActive,
 	}, nil
 }
 
@@ -46,48 +93,262 @@ func : mot-clé pour déclarer une fonction
 u : nom de la variable (comme self en Python)
 *User : pointeur vers User (la fonction peut modifier l'objet)
 UpdateUserProfile : nom de la fonction
-(name string, email string) : paramètres
-error : type de retour
+(name string, email string, now time.Time) : paramètres
+([]FieldChange, error) : types de retour (les champs modifiés, vide si rien ne change)
 */
-func (u *User) UpdateUserProfile(name string, email string) error {
-	if err := validateName(name); err != nil {
-		return err
-	}
+func (u *User) UpdateUserProfile(name string, email string, now time.Time) ([]FieldChange, error) {
+	return u.PatchProfile(&name, &email, now)
+}
 
-	if err := validateEmail(email); err != nil {
-		return err
+// PatchProfile mise à jour partielle : seuls les champs fournis (non nil) sont validés et appliqués
+// Retourne les champs réellement modifiés ; sans modification, Updated n'est pas touché
+func (u *User) PatchProfile(name, email *string, now time.Time) ([]FieldChange, error) {
+	nextName, nextEmail := u.Name, u.Email
+	if name != nil {
+		if err := validateName(*name); err != nil {
+			return nil, err
+		}
+		nextName = strings.TrimSpace(*name)
+	}
+	if email != nil {
+		nextEmail = normalizeEmail(*email)
+		if err := validateEmail(nextEmail); err != nil {
+			return nil, err
+		}
 	}
 
-	u.Name = strings.TrimSpace(name)
-	u.Email = strings.ToLower(strings.TrimSpace(email))
-	u.Updated = time.Now()
+	changes := DiffProfile(u.Name, u.Email, nextName, nextEmail)
+	if len(changes) == 0 {
+		return nil, nil
+	}
 
-	return nil
+	if nextEmail != u.Email {
+		u.ClearEmailUndeliverable()
+	}
+	u.Name = nextName
+	u.Email = nextEmail
+	u.Updated = now
+	return changes, nil
 }
 
-func (u *User) ChangePassword(newPassword string) error {
+func (u *User) ChangePassword(newPassword string, now time.Time) error {
 	if err := validatePassword(newPassword); err != nil {
 		return err
 	}
 
 	u.Password = newPassword // À hasher dans le use case
-	u.Updated = time.Now()
+	u.Updated = now
+
+	return nil
+}
+
+// SetWeeklyDigest active ou désactive le résumé hebdomadaire
+// Retourne false si la préférence était déjà dans cet état (rien à enregistrer)
+func (u *User) SetWeeklyDigest(enabled bool, now time.Time) bool {
+	if u.WeeklyDigest == enabled {
+		return false
+	}
+
+	u.WeeklyDigest = enabled
+	u.Updated = now
+	return true
+}
+
+// SetPhone change le numéro de téléphone (vide pour le supprimer)
+// Retourne false si le numéro est inchangé
+func (u *User) SetPhone(phone string, now time.Time) (bool, error) {
+	phone = strings.TrimSpace(phone)
+	if phone != "" {
+		if err := validatePhone(phone); err != nil {
+			return false, err
+		}
+	}
+
+	if u.Phone == phone {
+		return false, nil
+	}
+
+	u.Phone = phone
+	u.Updated = now
+	return true, nil
+}
+
+// SetDisplayPreferences change la locale ("fr", "fr-FR") et le fuseau horaire IANA ("Europe/Paris")
+// d'affichage ; vide revient à la valeur par défaut. Retourne false si rien ne change
+func (u *User) SetDisplayPreferences(locale, timeZone string, now time.Time) (bool, error) {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return false, err
+	}
+	timeZone = strings.TrimSpace(timeZone)
+	if err := ValidateTimeZone(timeZone); err != nil {
+		return false, err
+	}
+
+	if u.Locale == locale && u.TimeZone == timeZone {
+		return false, nil
+	}
+
+	u.Locale = locale
+	u.TimeZone = timeZone
+	u.Updated = now
+	return true, nil
+}
+
+// ValidateTimeZone nom de fuseau de la base IANA ; vide accepté (fuseau par défaut)
+func ValidateTimeZone(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(name); err != nil || name == "Local" {
+		return ErrInvalidTimeZone
+	}
+	return nil
+}
+
+// CurrentStatus statut effectif (les comptes créés avant le cycle de vie sont actifs)
+func (u *User) CurrentStatus() UserStatus {
+	if u.Status == "" {
+		return UserStatusActive
+	}
+	return u.Status
+}
+
+func (u *User) IsActive() bool {
+	return u.CurrentStatus() == UserStatusActive
+}
+
+// Deactivate désactive le compte, ou le bannit si ban est vrai
+// Un bannissement peut aggraver une désactivation, mais un banni reste banni
+// Retourne false si le compte était déjà dans cet état
+func (u *User) Deactivate(ban bool, now time.Time) (bool, error) {
+	target := UserStatusDeactivated
+	if ban {
+		target = UserStatusBanned
+	}
+
+	switch current := u.CurrentStatus(); {
+	case current == target:
+		return false, nil
+	case current == UserStatusBanned:
+		return false, ErrUserBannedStatus
+	}
+
+	u.Status = target
+	u.Updated = now
+	return true, nil
+}
+
+// Reactivate rend le compte actif, qu'il ait été désactivé ou banni
+func (u *User) Reactivate(now time.Time) error {
+	if u.IsActive() {
+		return ErrUserAlreadyActive
+	}
 
+	u.Status = UserStatusActive
+	u.Updated = now
 	return nil
 }
 
+// SetHandle change le handle (vide pour le retirer) ; la réservation et l'unicité
+// sont vérifiées respectivement ici et par le dépôt
+// Retourne false si le handle est inchangé
+func (u *User) SetHandle(raw string, now time.Time) (bool, error) {
+	handle := ""
+	if strings.TrimSpace(raw) != "" {
+		normalized, err := NormalizeHandle(raw)
+		if err != nil {
+			return false, err
+		}
+		handle = normalized
+	}
+
+	if u.Handle == handle {
+		return false, nil
+	}
+
+	u.Handle = handle
+	u.Updated = now
+	return true, nil
+}
+
+// RecordLogin enregistre une authentification réussie et lève un éventuel signalement d'inactivité
+// Updated n'est pas modifié : une connexion ne change pas le profil (ni son ETag)
+func (u *User) RecordLogin(at time.Time) {
+	u.LastLoginAt = &at
+	u.CleanupFlaggedAt = nil
+}
+
+// LastActivity dernière connexion, ou date d'inscription pour un compte jamais connecté
+func (u *User) LastActivity() time.Time {
+	if u.LastLoginAt != nil {
+		return *u.LastLoginAt
+	}
+	return u.Created
+}
+
+// FlagForCleanup signale le compte pour nettoyage ; retourne false s'il l'était déjà
+func (u *User) FlagForCleanup(at time.Time) bool {
+	if u.CleanupFlaggedAt != nil {
+		return false
+	}
+	u.CleanupFlaggedAt = &at
+	return true
+}
+
+// MarkEmailUndeliverable enregistre le rejet de l'adresse (EmailBounced ou EmailComplained)
+// Updated n'est pas modifié : le profil ne change pas. Retourne false si l'adresse était déjà rejetée
+func (u *User) MarkEmailUndeliverable(reason string, at time.Time) bool {
+	if u.EmailUndeliverableAt != nil {
+		return false
+	}
+	u.EmailUndeliverableAt = &at
+	u.EmailUndeliverableReason = reason
+	return true
+}
+
+// ClearEmailUndeliverable lève le rejet (nouvelle adresse)
+func (u *User) ClearEmailUndeliverable() {
+	u.EmailUndeliverableAt = nil
+	u.EmailUndeliverableReason = ""
+}
+
+// CanReceiveEmail l'adresse n'a pas été rejetée par le fournisseur
+func (u *User) CanReceiveEmail() bool {
+	return u.EmailUndeliverableAt == nil
+}
+
+// ParseUserStatus valide un statut reçu de l'extérieur (filtres de liste)
+func ParseUserStatus(raw string) (UserStatus, error) {
+	switch status := UserStatus(raw); status {
+	case UserStatusActive, UserStatusDeactivated, UserStatusBanned:
+		return status, nil
+	}
+	return "", errors.New("statut invalide (active, deactivated ou banned)")
+}
+
 func (u *User) isValidUser() bool {
 	return validateEmail(u.Email) == nil &&
 		validateName(u.Name) == nil &&
 		validatePassword(u.Password) == nil
 }
 
+// normalizeEmail forme stockée ; la validation porte sur elle : la mise en minuscules peut
+// allonger l'adresse (caractères non ASCII)
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func validateEmail(email string) error {
 	email = strings.TrimSpace(email)
 	if email == "" {
 		return errors.New("email can't be empty")
 	}
 
+	if !utf8.ValidString(email) {
+		return errors.New("invalid email")
+	}
+
 	if !strings.Contains(email, "@") {
 		return errors.New("invalid email")
 	}
@@ -99,11 +360,19 @@ func validateEmail(email string) error {
 	return nil
 }
 
-var (
-	offensiveNameRegex = regexp.MustCompile(`(?i)(fuck|shit|damn|idiot|stupid|hitler|cunt)`)
-	validNameRegex     = regexp.MustCompile(`^[a-zA-ZÀ-ÿ\s\-'.]+$`)
-)
+var phoneRegex = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
 
+func validatePhone(phone string) error {
+	if !phoneRegex.MatchString(phone) {
+		return errors.New("numéro de téléphone invalide (format E.164 attendu, ex: +33612345678)")
+	}
+	return nil
+}
+
+var validNameRegex = regexp.MustCompile(`^[a-zA-ZÀ-ÿ\s\-'.]+$`)
+
+// validateName forme du nom uniquement ; le contenu (insultes...) relève de la modération
+// (usecases.ContentModeration), appliquée aux saisies des utilisateurs
 func validateName(name string) error {
 	trimmedName := strings.TrimSpace(name)
 	if trimmedName == "" {
@@ -118,10 +387,6 @@ func validateName(name string) error {
 		return errors.New("brother nobody has such a long name")
 	}
 
-	if offensiveNameRegex.MatchString(trimmedName) {
-		return errors.New("offensive name non approprié")
-	}
-
 	if !validNameRegex.MatchString(trimmedName) {
 		return errors.New("nom contient des caractères invalides")
 	}
@@ -143,3 +408,42 @@ func validatePassword(password string) error {
 
 	return nil
 }
+
+// =============================================================================
+// HANDLES (@pseudo)
+// =============================================================================
+
+var (
+	ErrInvalidHandle  = errors.New("handle invalide : 3 à 30 caractères parmi a-z, 0-9 et _, commençant par une lettre")
+	ErrReservedHandle = errors.New("ce handle est réservé")
+)
+
+var handleRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+// reservedHandles noms qui prêtent à confusion (routes, rôles, marque) ou à l'usurpation
+var reservedHandles = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true, "support": true,
+	"help": true, "security": true, "staff": true, "moderator": true, "official": true,
+	"api": true, "auth": true, "login": true, "logout": true, "signup": true, "register": true,
+	"users": true, "user": true, "me": true, "settings": true, "account": true, "webhooks": true,
+	"analytics": true, "health": true, "debug": true, "docs": true, "www": true, "mail": true,
+	"null": true, "undefined": true, "anonymous": true, "everyone": true, "here": true,
+}
+
+// NormalizeHandle met un handle sous sa forme canonique : sans "@" initial, en minuscules,
+// puis vérifie le format et la liste des noms réservés
+func NormalizeHandle(raw string) (string, error) {
+	handle := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+	if !handleRegex.MatchString(handle) {
+		return "", ErrInvalidHandle
+	}
+	if IsReservedHandle(handle) {
+		return "", ErrReservedHandle
+	}
+	return handle, nil
+}
+
+// IsReservedHandle vrai pour un nom réservé ; les variantes à underscores ("ad_min") le sont aussi
+func IsReservedHandle(handle string) bool {
+	return reservedHandles[handle] || reservedHandles[strings.ReplaceAll(handle, "_", "")]
+}
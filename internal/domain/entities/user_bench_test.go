@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func BenchmarkNewUser(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := NewUser("  Alice.Martin@Example.com ", "Alice Martin", "Secret123!", time.Now()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewUserInvalidEmail(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := NewUser("not-an-email", "Alice Martin", "Secret123!", time.Now()); err == nil {
+			b.Fatal("expected validation error")
+		}
+	}
+}
+
+func BenchmarkUpdateUserProfile(b *testing.B) {
+	now := time.Now()
+	user, err := NewUser("alice@example.com", "Alice Martin", "Secret123!", now)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := user.UpdateUserProfile("Alice Dubois", "alice.dubois@example.com", now); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
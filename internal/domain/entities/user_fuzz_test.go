@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// Les corpus de départ reprennent les cas limites connus ; `go test -fuzz=FuzzNewUser` explore
+// au-delà, les entrées qui font échouer un test sont gardées dans testdata/fuzz
+
+func FuzzNewUser(f *testing.F) {
+	f.Add("alice.martin@example.com", "Alice Martin", "Secret123!")
+	f.Add("  Alice.Martin@Example.COM ", " Élodie D'Artagnan-Lévêque ", "123456")
+	f.Add("@", "ab", "      ")
+	f.Add("a@b", "Jean-Pierre", strings.Repeat("x", 128))
+	f.Add(strings.Repeat("a", 250)+"@b.fr", strings.Repeat("a", 100), "secret")
+	f.Add("İ@example.com", "Ana", "secret")
+
+	f.Fuzz(func(t *testing.T, email, name, password string) {
+		user, err := NewUser(email, name, password, time.Now())
+		if err != nil {
+			if user != nil {
+				t.Fatal("user returned alongside an error")
+			}
+			return
+		}
+		// Un utilisateur construit doit rester valide une fois normalisé (rechargement, mise à jour)
+		if !user.isValidUser() {
+			t.Fatalf("NewUser(%q, %q) produced an invalid user: %q / %q", email, name, user.Email, user.Name)
+		}
+		if user.Email != strings.ToLower(user.Email) || user.Name != strings.TrimSpace(user.Name) {
+			t.Fatalf("fields not normalized: %q / %q", user.Email, user.Name)
+		}
+	})
+}
+
+func FuzzValidateName(f *testing.F) {
+	for _, seed := range []string{"Alice", "a", "  ", "Zoë O'Brien", "Jean--Pierre", "Idiot", "名前", " Al ", strings.Repeat("é", 60)} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, name string) {
+		if err := validateName(name); err != nil {
+			return
+		}
+		trimmed := strings.TrimSpace(name)
+		if !utf8.ValidString(trimmed) {
+			t.Fatalf("accepted invalid UTF-8 name %q", name)
+		}
+		if len(trimmed) < 2 || len(trimmed) > 100 {
+			t.Fatalf("accepted name outside length bounds: %q", name)
+		}
+	})
+}
+
+func FuzzNormalizeHandle(f *testing.F) {
+	for _, seed := range []string{"@Alice_M", "ad_min", "a", "x1_", "  @@bob ", "K_elvin", "ſcott"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		handle, err := NormalizeHandle(raw)
+		if err != nil {
+			return
+		}
+		// Forme canonique : la renormaliser ne doit rien changer
+		again, err := NormalizeHandle(handle)
+		if err != nil || again != handle {
+			t.Fatalf("NormalizeHandle not idempotent: %q -> %q -> %q (%v)", raw, handle, again, err)
+		}
+		if IsReservedHandle(handle) {
+			t.Fatalf("reserved handle accepted: %q", handle)
+		}
+	})
+}
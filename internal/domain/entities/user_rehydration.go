@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"errors"
+)
+
+// ErrUserDeleted est retournée quand le flux d'événements se termine par une suppression
+var ErrUserDeleted = errors.New("user deleted")
+
+// RehydrateUser reconstruit l'état d'un utilisateur à partir d'un snapshot (optionnel)
+// et des événements survenus depuis. Aucune validation n'est rejouée : les événements
+// sont des faits déjà acceptés
+func RehydrateUser(snapshot *User, history []events.Event) (*User, error) {
+	user := &User{}
+	if snapshot != nil {
+		*user = *snapshot
+	}
+
+	deleted := false
+	for _, event := range history {
+		if _, ok := event.(events.UserDeleted); ok {
+			deleted = true
+			continue
+		}
+		user.Apply(event)
+	}
+
+	if deleted {
+		return nil, ErrUserDeleted
+	}
+	return user, nil
+}
+
+// Apply fait évoluer l'état de l'utilisateur selon un événement du flux
+func (u *User) Apply(event events.Event) {
+	switch e := event.(type) {
+	case events.UserRegistered:
+		u.ID = e.UserID
+		u.Email = e.Email
+		u.Name = e.Name
+		u.Password = e.PasswordHash
+		u.Created = e.Registered
+		u.Updated = e.Registered
+		u.Status = UserStatusActive
+	case events.UserProfileUpdated:
+		if e.Email != u.Email {
+			u.ClearEmailUndeliverable()
+		}
+		u.Email = e.Email
+		u.Name = e.Name
+		u.Updated = e.Updated
+	case events.PasswordChanged:
+		u.Password = e.PasswordHash
+		u.Updated = e.Changed
+	case events.DigestPreferenceChanged:
+		u.WeeklyDigest = e.Enabled
+		u.Updated = e.Changed
+	case events.UserPhoneChanged:
+		u.Phone = e.Phone
+		u.Updated = e.Changed
+	case events.UserLocaleChanged:
+		u.Locale = e.Locale
+		u.TimeZone = e.TimeZone
+		u.Updated = e.Changed
+	case events.UserStatusChanged:
+		u.Status = UserStatus(e.Status)
+		u.Updated = e.Changed
+	case events.UserHandleChanged:
+		u.Handle = e.Handle
+		u.Updated = e.Changed
+	case events.UserLoggedIn:
+		u.RecordLogin(e.At)
+	case events.UserFlaggedForCleanup:
+		u.FlagForCleanup(e.Flagged)
+	case events.UserAttributesChanged:
+		u.Attributes = e.Attributes
+		u.Updated = e.Changed
+	case events.UserEmailUndeliverable:
+		u.MarkEmailUndeliverable(e.Reason, e.Marked)
+	}
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+)
+
+// WriteKey clé d'écriture d'un tenant pour l'ingestion publique (POST /collect/...) : publiée dans
+// le code des sites et applications, elle n'ouvre que l'envoi d'événements, jamais l'API utilisateurs
+// Seule l'empreinte de la clé est conservée ; Prefix permet de la reconnaître dans les listes
+type WriteKey struct {
+	ID       int       `json:"id"`
+	TenantID string    `json:"tenant_id"`
+	Name     string    `json:"name"`
+	Prefix   string    `json:"prefix"`
+	Hash     string    `json:"-"`
+	Created  time.Time `json:"created"`
+	// Expires fin de validité : fixée par une rotation (délai de grâce) ou une révocation ; nil = sans fin
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+func NewWriteKey(tenantID, name, prefix, hash string, now time.Time) *WriteKey {
+	return &WriteKey{
+		TenantID: tenantID,
+		Name:     name,
+		Prefix:   prefix,
+		Hash:     hash,
+		Created:  now,
+	}
+}
+
+// Active vraie tant que la clé n'a pas expiré
+func (k *WriteKey) Active(now time.Time) bool {
+	return k.Expires == nil || now.Before(*k.Expires)
+}
+
+// ExpireAt avance la fin de validité à at, sans jamais la repousser
+func (k *WriteKey) ExpireAt(at time.Time) {
+	if k.Expires == nil || at.Before(*k.Expires) {
+		k.Expires = &at
+	}
+}
@@ -0,0 +1,242 @@
+package events
+
+import (
+	"time"
+)
+
+// Event représente un fait métier qui s'est produit
+// Les événements sont immuables : on ne les modifie jamais après publication
+type Event interface {
+	EventName() string
+	OccurredAt() time.Time
+}
+
+// Noms des événements utilisateur
+const (
+	UserCreatedEvent        = "user.created"
+	UserProfileUpdatedEvent = "user.profile_updated"
+	UserDeletedEvent        = "user.deleted"
+
+	DigestPreferenceChangedEvent = "user.digest_preference_changed"
+	UserPhoneChangedEvent        = "user.phone_changed"
+	UserStatusChangedEvent       = "user.status_changed"
+	UserHandleChangedEvent       = "user.handle_changed"
+	UserLoggedInEvent            = "user.logged_in"
+	UserFlaggedForCleanupEvent   = "user.flagged_for_cleanup"
+	TermsAcceptedEvent           = "user.terms_accepted"
+	UserAttributesChangedEvent   = "user.attributes_changed"
+	UserImpersonatedEvent        = "user.impersonated"
+	UserEmailUndeliverableEvent  = "user.email_undeliverable"
+	UserLocaleChangedEvent       = "user.locale_changed"
+	NewDeviceLoginEvent          = "user.new_device_login"
+	AnalyticsConsentChangedEvent = "user.analytics_consent_changed"
+
+	// Événements internes au flux event-sourcé (ils portent le hash du mot de passe
+	// et ne doivent jamais être diffusés hors du stockage)
+	UserRegisteredEvent  = "user.registered"
+	PasswordChangedEvent = "user.password_changed"
+)
+
+// UserCreated est publié après l'inscription d'un utilisateur
+type UserCreated struct {
+	UserID  int
+	Email   string
+	Name    string
+	Created time.Time
+}
+
+func (e UserCreated) EventName() string     { return UserCreatedEvent }
+func (e UserCreated) OccurredAt() time.Time { return e.Created }
+
+// UserProfileUpdated est publié après la modification du nom ou de l'email
+// ChangedFields noms des champs modifiés ("name", "email"), sans les valeurs
+type UserProfileUpdated struct {
+	UserID        int
+	Email         string
+	Name          string
+	ChangedFields []string
+	Updated       time.Time
+}
+
+func (e UserProfileUpdated) EventName() string     { return UserProfileUpdatedEvent }
+func (e UserProfileUpdated) OccurredAt() time.Time { return e.Updated }
+
+// UserDeleted est publié après la suppression d'un utilisateur
+type UserDeleted struct {
+	UserID  int
+	Deleted time.Time
+}
+
+func (e UserDeleted) EventName() string     { return UserDeletedEvent }
+func (e UserDeleted) OccurredAt() time.Time { return e.Deleted }
+
+// UserRegistered est le premier événement du flux event-sourcé d'un utilisateur
+type UserRegistered struct {
+	UserID       int
+	Email        string
+	Name         string
+	PasswordHash string
+	Registered   time.Time
+}
+
+func (e UserRegistered) EventName() string     { return UserRegisteredEvent }
+func (e UserRegistered) OccurredAt() time.Time { return e.Registered }
+
+// PasswordChanged enregistre le nouveau hash du mot de passe
+type PasswordChanged struct {
+	UserID       int
+	PasswordHash string
+	Changed      time.Time
+}
+
+func (e PasswordChanged) EventName() string     { return PasswordChangedEvent }
+func (e PasswordChanged) OccurredAt() time.Time { return e.Changed }
+
+// DigestPreferenceChanged est publié quand l'utilisateur change son opt-in au résumé hebdomadaire
+type DigestPreferenceChanged struct {
+	UserID  int
+	Enabled bool
+	Changed time.Time
+}
+
+func (e DigestPreferenceChanged) EventName() string     { return DigestPreferenceChangedEvent }
+func (e DigestPreferenceChanged) OccurredAt() time.Time { return e.Changed }
+
+// UserPhoneChanged est publié quand le numéro de téléphone change (vide = supprimé)
+type UserPhoneChanged struct {
+	UserID  int
+	Phone   string
+	Changed time.Time
+}
+
+func (e UserPhoneChanged) EventName() string     { return UserPhoneChangedEvent }
+func (e UserPhoneChanged) OccurredAt() time.Time { return e.Changed }
+
+// UserStatusChanged est publié quand un compte est désactivé, banni ou réactivé
+type UserStatusChanged struct {
+	UserID int
+	Status string
+	// Reason motif saisi par l'administrateur (vide à la réactivation)
+	Reason  string
+	Changed time.Time
+}
+
+func (e UserStatusChanged) EventName() string     { return UserStatusChangedEvent }
+func (e UserStatusChanged) OccurredAt() time.Time { return e.Changed }
+
+// UserHandleChanged est publié quand l'utilisateur choisit, change ou retire son handle (vide)
+type UserHandleChanged struct {
+	UserID  int
+	Handle  string
+	Changed time.Time
+}
+
+func (e UserHandleChanged) EventName() string     { return UserHandleChangedEvent }
+func (e UserHandleChanged) OccurredAt() time.Time { return e.Changed }
+
+// UserLoggedIn est publié après une authentification réussie
+type UserLoggedIn struct {
+	UserID int
+	At     time.Time
+}
+
+func (e UserLoggedIn) EventName() string     { return UserLoggedInEvent }
+func (e UserLoggedIn) OccurredAt() time.Time { return e.At }
+
+// UserFlaggedForCleanup est publié quand un compte inactif depuis trop longtemps est signalé
+// pour nettoyage ; la décision de suppression reste humaine
+type UserFlaggedForCleanup struct {
+	UserID        int
+	InactiveSince time.Time
+	Flagged       time.Time
+}
+
+func (e UserFlaggedForCleanup) EventName() string     { return UserFlaggedForCleanupEvent }
+func (e UserFlaggedForCleanup) OccurredAt() time.Time { return e.Flagged }
+
+// UserEmailUndeliverable est publié quand le fournisseur d'email rejette définitivement l'adresse
+// (rebond ou plainte, entities.EmailBounced / EmailComplained) : les envois suivants sont supprimés
+type UserEmailUndeliverable struct {
+	UserID int
+	Email  string
+	Reason string
+	Marked time.Time
+}
+
+func (e UserEmailUndeliverable) EventName() string     { return UserEmailUndeliverableEvent }
+func (e UserEmailUndeliverable) OccurredAt() time.Time { return e.Marked }
+
+// UserLocaleChanged est publié quand la locale ou le fuseau horaire d'affichage change (vides = défaut)
+type UserLocaleChanged struct {
+	UserID   int
+	Locale   string
+	TimeZone string
+	Changed  time.Time
+}
+
+func (e UserLocaleChanged) EventName() string     { return UserLocaleChangedEvent }
+func (e UserLocaleChanged) OccurredAt() time.Time { return e.Changed }
+
+// TermsAccepted est publié quand un utilisateur accepte une version des conditions d'utilisation
+type TermsAccepted struct {
+	UserID   int
+	Version  string
+	Accepted time.Time
+}
+
+func (e TermsAccepted) EventName() string     { return TermsAcceptedEvent }
+func (e TermsAccepted) OccurredAt() time.Time { return e.Accepted }
+
+// UserAttributesChanged est publié quand les attributs personnalisés changent
+// Attributes porte l'état complet après modification (nil : plus aucun attribut),
+// ChangedFields les attributs touchés ("attributes.<clé>")
+type UserAttributesChanged struct {
+	UserID        int
+	Attributes    map[string]any
+	ChangedFields []string
+	Changed       time.Time
+}
+
+func (e UserAttributesChanged) EventName() string     { return UserAttributesChangedEvent }
+func (e UserAttributesChanged) OccurredAt() time.Time { return e.Changed }
+
+// UserImpersonated est publié quand un administrateur ouvre une session au nom de l'utilisateur
+// Reason motif saisi par l'administrateur (ticket de support...), conservé pour l'audit
+type UserImpersonated struct {
+	UserID    int
+	AdminID   int
+	Reason    string
+	ExpiresAt time.Time
+	At        time.Time
+}
+
+func (e UserImpersonated) EventName() string     { return UserImpersonatedEvent }
+func (e UserImpersonated) OccurredAt() time.Time { return e.At }
+
+// NewDeviceLogin est publié quand un utilisateur se connecte depuis un appareil jamais vu sur son
+// compte (hors tout premier login) ; Country / City vides si la localisation est inconnue
+type NewDeviceLogin struct {
+	UserID    int
+	SessionID string
+	Device    string
+	OS        string
+	Browser   string
+	Country   string
+	City      string
+	At        time.Time
+}
+
+func (e NewDeviceLogin) EventName() string     { return NewDeviceLoginEvent }
+func (e NewDeviceLogin) OccurredAt() time.Time { return e.At }
+
+// AnalyticsConsentChanged est publié quand un utilisateur accorde ou retire son consentement à
+// la mesure d'audience (Status "granted" ou "denied")
+type AnalyticsConsentChanged struct {
+	UserID int
+	Status string
+	Source string
+	At     time.Time
+}
+
+func (e AnalyticsConsentChanged) EventName() string     { return AnalyticsConsentChangedEvent }
+func (e AnalyticsConsentChanged) OccurredAt() time.Time { return e.At }
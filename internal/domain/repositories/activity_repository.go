@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// ActivityEntry trace une action notable d'un utilisateur (inscription, modification de profil...)
+type ActivityEntry struct {
+	UserID int
+	Kind   string // nom de l'événement du domaine à l'origine de l'entrée
+	// Fields champs modifiés pour les événements de mise à jour (noms seulement, jamais les valeurs)
+	Fields []string
+	At     time.Time
+}
+
+// ActivityRepository définit le contrat du journal d'activité par utilisateur
+type ActivityRepository interface {
+	Record(ctx context.Context, entry ActivityEntry) error
+	ListByUser(ctx context.Context, userID int, since time.Time) ([]ActivityEntry, error)
+}
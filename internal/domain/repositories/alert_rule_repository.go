@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+// ErrAlertRuleNotFound aucune règle d'alerte avec cet identifiant
+var ErrAlertRuleNotFound = errors.New("règle d'alerte introuvable")
+
+// AlertRuleRepository définit le contrat de persistance des règles d'alerte et de leur état
+type AlertRuleRepository interface {
+	// Create renseigne rule.ID
+	Create(ctx context.Context, rule *entities.AlertRule) error
+	Get(ctx context.Context, id int) (*entities.AlertRule, error)
+	// List règles, de la plus ancienne à la plus récente
+	List(ctx context.Context) ([]*entities.AlertRule, error)
+	// Save enregistre l'état d'évaluation ; ErrAlertRuleNotFound si la règle a été supprimée
+	Save(ctx context.Context, rule *entities.AlertRule) error
+	// Delete ErrAlertRuleNotFound si la règle n'existe pas
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEntry action sensible tentée par un acteur (modification d'un compte, connexion,
+// impersonation, administration de l'instance...), réussie ou non
+type AuditEntry struct {
+	// ID attribué par Append, croissant : clé de la pagination
+	ID int64
+	At time.Time
+	// ActorID utilisateur à l'origine de l'action (0 = anonyme ou tâche interne, voir System)
+	ActorID int
+	System  bool
+	// ImpersonatorID administrateur derrière une session d'impersonation (0 sinon)
+	ImpersonatorID int
+	TenantID       string
+	// TargetUserID utilisateur visé par l'action (0 si aucun ou inconnu)
+	TargetUserID int
+	Action       string // nom du use case
+	Outcome      string // "succeeded", "failed", "denied" ou "challenged"
+	RequestID    string
+	// Details précisions sur l'issue (règles de risque déclenchées...) ; jamais de donnée personnelle
+	Details string
+}
+
+// AuditFilter critères de ListAuditEntries ; les champs à zéro ne filtrent pas
+type AuditFilter struct {
+	// ActorID acteur ou administrateur d'une session d'impersonation
+	ActorID      int
+	TargetUserID int
+	Action       string
+	// From / To bornes de At (début inclus, fin exclue)
+	From *time.Time
+	To   *time.Time
+	// BeforeID pagination par clé : entrées d'ID strictement inférieur (0 = première page)
+	BeforeID int64
+	Limit    int
+}
+
+// AuditRepository définit le contrat du journal d'audit : ajout seul, lecture des plus récentes
+// d'abord, purge par lots selon la durée de rétention
+type AuditRepository interface {
+	Append(ctx context.Context, entry AuditEntry) error
+	List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+	// DeleteByIDs supprime les entrées et retourne leur nombre ; les ID inconnus sont ignorés
+	DeleteByIDs(ctx context.Context, ids []int64) (int, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+var ErrBillingAccountNotFound = errors.New("compte de facturation introuvable")
+
+// BillingAccountRepository définit le contrat de stockage des comptes de facturation (un par tenant)
+type BillingAccountRepository interface {
+	// Save crée ou remplace le compte du tenant
+	Save(ctx context.Context, account *entities.BillingAccount) error
+	GetByTenant(ctx context.Context, tenantID string) (*entities.BillingAccount, error)
+	GetByCustomer(ctx context.Context, customerID string) (*entities.BillingAccount, error)
+	List(ctx context.Context) ([]*entities.BillingAccount, error)
+}
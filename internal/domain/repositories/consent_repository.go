@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrConsentNotFound l'utilisateur ne s'est encore jamais prononcé
+var ErrConsentNotFound = errors.New("consent not found")
+
+// ConsentFilter critères de ListConsentChanges ; les champs à zéro ne filtrent pas
+type ConsentFilter struct {
+	UserID int
+	Status string
+	// From / To bornes de ChangedAt (début inclus, fin exclue)
+	From *time.Time
+	To   *time.Time
+	// BeforeID pagination par clé : changements d'ID strictement inférieur (0 = première page)
+	BeforeID int64
+	Limit    int
+}
+
+// ConsentRepository historique des consentements à la mesure d'audience : ajout seul, preuve
+// des choix de chaque utilisateur
+type ConsentRepository interface {
+	// Save attribue l'ID, croissant
+	Save(ctx context.Context, change *entities.ConsentChange) error
+	// GetLatest ErrConsentNotFound si l'utilisateur n'a aucun changement
+	GetLatest(ctx context.Context, userID int) (*entities.ConsentChange, error)
+	// List les plus récents d'abord
+	List(ctx context.Context, filter ConsentFilter) ([]entities.ConsentChange, error)
+}
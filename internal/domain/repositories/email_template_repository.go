@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+// ErrEmailTemplateNotFound aucune version pour cette clé (ou cette version n'existe pas)
+var ErrEmailTemplateNotFound = errors.New("email template not found")
+
+// EmailTemplateRepository modèles d'emails versionnés, par tenant et par locale
+// Les versions ne sont jamais modifiées : chaque enregistrement en ajoute une
+type EmailTemplateRepository interface {
+	// Save ajoute une version à la lignée du modèle et renseigne tpl.Version (dernière + 1)
+	Save(ctx context.Context, tpl *entities.EmailTemplate) error
+	GetLatest(ctx context.Context, key entities.EmailTemplateKey) (*entities.EmailTemplate, error)
+	GetVersion(ctx context.Context, key entities.EmailTemplateKey, version int) (*entities.EmailTemplate, error)
+	// ListVersions historique de la lignée, de la plus ancienne à la plus récente ; vide si inconnue
+	ListVersions(ctx context.Context, key entities.EmailTemplateKey) ([]*entities.EmailTemplate, error)
+}
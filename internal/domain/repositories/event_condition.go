@@ -0,0 +1,56 @@
+package repositories
+
+// EventCondition expression de filtre sur les événements, compilée par usecases.ParseEventFilter
+// et traduite par chaque stockage (prédicat SQL, évaluation en mémoire) : EventAnd, EventOr,
+// EventNot ou EventComparison
+type EventCondition interface {
+	eventCondition()
+}
+
+type EventAnd struct {
+	Left, Right EventCondition
+}
+
+type EventOr struct {
+	Left, Right EventCondition
+}
+
+type EventNot struct {
+	Operand EventCondition
+}
+
+// Champs comparables d'un événement, en plus de ses propriétés
+const (
+	EventFieldName       = "event"
+	EventFieldUserID     = "user_id"
+	EventFieldOccurredAt = "occurred_at"
+	EventFieldReceivedAt = "received_at"
+)
+
+// Opérateurs d'EventComparison ; != et NOT IN sont des EventNot
+const (
+	EventOpEq     = "="
+	EventOpLt     = "<"
+	EventOpLe     = "<="
+	EventOpGt     = ">"
+	EventOpGe     = ">="
+	EventOpIn     = "IN"
+	EventOpExists = "EXISTS"
+)
+
+// EventComparison compare un champ (Field) ou une propriété (Property, Field vide)
+//   - Values : une valeur, plusieurs pour IN, aucune pour EXISTS ; string (event, propriétés),
+//     int (user_id), float64 ou bool (propriétés), time.Time (occurred_at, received_at)
+//   - fausse si la valeur est absente (événement anonyme, propriété manquante) ou d'un autre type
+//     que la valeur comparée : NOT la rend vraie, jamais indéterminée comme un NULL SQL
+type EventComparison struct {
+	Field    string
+	Property string
+	Op       string
+	Values   []any
+}
+
+func (EventAnd) eventCondition()        {}
+func (EventOr) eventCondition()         {}
+func (EventNot) eventCondition()        {}
+func (EventComparison) eventCondition() {}
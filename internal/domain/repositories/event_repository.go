@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"time"
+)
+
+// EventFilters critères de lecture des événements analytics ; zéro = pas de filtre
+type EventFilters struct {
+	Names  []string
+	UserID int
+	// From / To bornes sur OccurredAt (début inclus, fin exclue)
+	From  time.Time
+	To    time.Time
+	Limit int
+	// Where expression de filtre (usecases.ParseEventFilter) ; nil = aucune
+	Where EventCondition
+}
+
+// EventRepository définit le contrat de stockage des événements analytics (entities.TrackedEvent)
+//   - Append écrit un lot en une fois et renseigne les ID ; un lot vide ne fait rien. Aucune
+//     référence aux événements n'est gardée après le retour : l'ingestion les réutilise
+//   - List les plus anciens d'abord (OccurredAt puis ID)
+type EventRepository interface {
+	Append(ctx context.Context, events []*entities.TrackedEvent) error
+	List(ctx context.Context, filters EventFilters) ([]*entities.TrackedEvent, error)
+}
+
+// EventPurger suppression d'événements stockés (rétention) : capacité facultative des stockages,
+// absente des décorateurs de l'ingestion et des implémentations fournies par l'hôte
+type EventPurger interface {
+	// DeleteByIDs supprime les événements et retourne leur nombre ; les ID inconnus sont ignorés
+	DeleteByIDs(ctx context.Context, ids []int64) (int, error)
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoEventHistory aucune source d'événements passés n'est configurée (backfill impossible)
+var ErrNoEventHistory = errors.New("aucun historique d'événements configuré")
+
+// EventRollup nombre d'événements d'un type sur une journée UTC
+type EventRollup struct {
+	Day   time.Time // minuit UTC
+	Event string
+	Count int
+}
+
+// EventRollupFilters jours [From, To) ; Names vide = tous les événements
+type EventRollupFilters struct {
+	Names []string
+	From  time.Time
+	To    time.Time
+}
+
+// EventRollupRepository agrégats quotidiens par événement : une ligne par (jour, événement),
+// lue par les requêtes analytiques à la place des événements bruts
+type EventRollupRepository interface {
+	// Increment ajoute delta au compteur du jour, créé au besoin
+	Increment(ctx context.Context, day time.Time, event string, delta int) error
+	// ReplaceDays remplace tous les compteurs des jours [from, to) par rollups, en une fois :
+	// un backfill rejoué sur la même période donne le même résultat
+	ReplaceDays(ctx context.Context, from, to time.Time, rollups []EventRollup) error
+	// Query compteurs triés par jour puis par nom d'événement
+	Query(ctx context.Context, filters EventRollupFilters) ([]EventRollup, error)
+}
+
+// EventHistory source des événements passés, agrégés par jour, pour le backfill des rollups
+type EventHistory interface {
+	DailyEventCounts(ctx context.Context, from, to time.Time) ([]EventRollup, error)
+}
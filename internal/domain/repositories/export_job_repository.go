@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrExportJobNotFound aucun export avec cet identifiant
+var ErrExportJobNotFound = errors.New("export introuvable")
+
+// ExportJobRepository définit le contrat de persistance des exports asynchrones
+type ExportJobRepository interface {
+	// Save crée ou remplace le job
+	Save(ctx context.Context, job *entities.ExportJob) error
+	Get(ctx context.Context, id string) (*entities.ExportJob, error)
+	Delete(ctx context.Context, id string) error
+	// ListExpired jobs expirés à now, quel que soit leur statut (purge des fichiers)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.ExportJob, error)
+}
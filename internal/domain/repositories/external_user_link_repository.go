@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrExternalUserLinkNotFound aucun utilisateur local n'est encore rattaché à cet identifiant externe
+var ErrExternalUserLinkNotFound = errors.New("external user link not found")
+
+// ExternalUserLink rattache un compte d'un système externe (SIRH...) à un utilisateur local
+// Email et Name sont les valeurs externes appliquées lors de la dernière synchronisation :
+// elles permettent de savoir de quel côté un champ a changé depuis
+type ExternalUserLink struct {
+	Source     string
+	ExternalID string
+	UserID     int
+	Email      string
+	Name       string
+	Active     bool
+	SyncedAt   time.Time
+}
+
+// ExternalUserLinkRepository table de correspondance des comptes synchronisés
+type ExternalUserLinkRepository interface {
+	GetByExternalID(ctx context.Context, source, externalID string) (*ExternalUserLink, error)
+	// GetByUserID lien d'un utilisateur local avec la source donnée
+	GetByUserID(ctx context.Context, source string, userID int) (*ExternalUserLink, error)
+	// Save crée ou remplace le lien (source, externalID)
+	Save(ctx context.Context, link *ExternalUserLink) error
+	// Delete retire le lien (source, externalID) ; sans effet s'il n'existe pas
+	Delete(ctx context.Context, source, externalID string) error
+}
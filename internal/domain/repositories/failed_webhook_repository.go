@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFailedWebhookNotFound aucun échec enregistré pour cet événement
+var ErrFailedWebhookNotFound = errors.New("failed webhook event not found")
+
+// MaxDeliveryFailures échecs conservés par message ou événement : les plus anciens sont oubliés
+const MaxDeliveryFailures = 20
+
+// DeliveryFailure tentative en échec (envoi d'un message de l'outbox, traitement d'un webhook)
+type DeliveryFailure struct {
+	At    time.Time
+	Error string
+}
+
+// FailedWebhookEvent événement entrant dont le traitement a échoué, avec de quoi le rejouer
+// DeadLettered : le seuil d'échecs est atteint, les renvois du fournisseur ne sont plus traités
+// (seul un rejeu explicite l'est)
+type FailedWebhookEvent struct {
+	Source       string
+	EventID      string
+	Type         string
+	OccurredAt   time.Time
+	Payload      []byte
+	Attempts     int
+	Failures     []DeliveryFailure
+	DeadLettered bool
+	Created      time.Time
+	Updated      time.Time
+}
+
+// FailedWebhookRepository échecs de traitement des webhooks et file des événements abandonnés
+type FailedWebhookRepository interface {
+	// RecordFailure ajoute un échec à l'événement (enregistré au premier échec) ; il passe en
+	// DeadLettered à deadLetterAfter tentatives. Retourne l'état après l'échec
+	RecordFailure(ctx context.Context, event FailedWebhookEvent, failure DeliveryFailure, deadLetterAfter int) (*FailedWebhookEvent, error)
+	Get(ctx context.Context, source, eventID string) (*FailedWebhookEvent, error)
+	// Delete oublie l'événement (traité avec succès ou abandonné) ; sans erreur s'il est absent
+	Delete(ctx context.Context, source, eventID string) error
+	// ListDeadLettered les plus anciens d'abord
+	ListDeadLettered(ctx context.Context, limit, offset int) ([]*FailedWebhookEvent, error)
+	CountDeadLettered(ctx context.Context) (int, error)
+}
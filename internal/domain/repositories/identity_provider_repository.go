@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrIdentityProviderNotFound le tenant n'a pas configuré de fournisseur d'identité
+	ErrIdentityProviderNotFound = errors.New("identity provider not found")
+	// ErrSSORequestNotFound demande d'authentification inconnue, expirée ou déjà consommée
+	ErrSSORequestNotFound = errors.New("sso request not found")
+)
+
+// SAMLAttributeMapping noms des attributs SAML lus pour chaque champ de l'utilisateur
+type SAMLAttributeMapping struct {
+	// Email attribut portant l'email ; vide = NameID (format emailAddress)
+	Email string `json:"email,omitempty"`
+	// Name attribut du nom affiché ; vide = FirstName + " " + LastName s'ils sont renseignés
+	Name      string `json:"name,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	// Attributes clé d'attribut personnalisé → attribut SAML (valeurs validées par le schéma du tenant)
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// IdentityProvider fournisseur d'identité SAML 2.0 d'un tenant
+type IdentityProvider struct {
+	TenantID string
+	// EntityID identifiant de l'IdP, attendu comme Issuer des réponses
+	EntityID string
+	// SSOURL point d'entrée SSO de l'IdP (binding HTTP-Redirect)
+	SSOURL string
+	// Certificate certificat PEM de signature des assertions
+	Certificate string
+	Mapping     SAMLAttributeMapping
+	Enabled     bool
+	Updated     time.Time
+}
+
+// IdentityProviderRepository configuration SSO par tenant
+type IdentityProviderRepository interface {
+	GetByTenant(ctx context.Context, tenantID string) (*IdentityProvider, error)
+	// Save crée ou remplace la configuration du tenant
+	Save(ctx context.Context, idp *IdentityProvider) error
+}
+
+// SSORequest demande d'authentification émise vers l'IdP, en attente de sa réponse
+type SSORequest struct {
+	ID       string
+	TenantID string
+	// UserID utilisateur authentifié qui a lancé la connexion (rattachement explicite) ; 0 = anonyme
+	UserID  int
+	Expires time.Time
+}
+
+// SSORequestRepository demandes en attente : chaque réponse de l'IdP doit répondre
+// à une demande émise par nous (InResponseTo), et une seule fois (rejeu)
+type SSORequestRepository interface {
+	Save(ctx context.Context, request SSORequest) error
+	// Consume retire et retourne la demande ; ErrSSORequestNotFound si inconnue ou expirée
+	Consume(ctx context.Context, id string, now time.Time) (*SSORequest, error)
+}
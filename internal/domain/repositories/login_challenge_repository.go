@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLoginChallengeNotFound aucune vérification en cours avec cet identifiant (ou expirée)
+var ErrLoginChallengeNotFound = errors.New("vérification de connexion introuvable")
+
+// LoginChallenge vérification supplémentaire exigée avant d'émettre le jeton d'une connexion
+// à risque : le code envoyé par email n'est conservé que haché
+type LoginChallenge struct {
+	ID     string
+	UserID int
+	// TenantID / Roles portés par le jeton émis une fois la vérification réussie
+	TenantID  string
+	Roles     []string
+	CodeHash  string
+	Attempts  int
+	ExpiresAt time.Time
+}
+
+// LoginChallengeRepository définit le contrat de stockage des vérifications de connexion
+type LoginChallengeRepository interface {
+	// Save crée ou remplace la vérification
+	Save(ctx context.Context, challenge LoginChallenge) error
+	// Get ErrLoginChallengeNotFound si elle n'existe pas ou a expiré à now
+	Get(ctx context.Context, id string, now time.Time) (*LoginChallenge, error)
+	Delete(ctx context.Context, id string) error
+}
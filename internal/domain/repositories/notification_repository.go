@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationPreferenceRepository définit le contrat de persistance des préférences de notification
+// Seuls les choix explicites sont stockés : l'absence de ligne signifie "valeur par défaut"
+type NotificationPreferenceRepository interface {
+	ListByUser(ctx context.Context, userID int) ([]*entities.NotificationPreference, error)
+	Save(ctx context.Context, preference *entities.NotificationPreference) error
+}
+
+// NotificationRepository définit le contrat de la boîte de réception in-app
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *entities.Notification) (*entities.Notification, error)
+	GetById(ctx context.Context, id int) (*entities.Notification, error)
+	Update(ctx context.Context, notification *entities.Notification) error
+	// ListByUser retourne les notifications de la plus récente à la plus ancienne
+	ListByUser(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]*entities.Notification, error)
+	// CountByUser retourne le nombre total de notifications et le nombre de non lues
+	CountByUser(ctx context.Context, userID int) (total, unread int, err error)
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrOnboardingNotFound aucune inscription avec cet identifiant
+var ErrOnboardingNotFound = errors.New("inscription introuvable")
+
+// Onboarding état persisté d'une saga d'inscription, relu à chaque reprise (confirmation, expiration)
+type Onboarding struct {
+	ID     string
+	Status string
+	// NextStep index de la prochaine étape à exécuter
+	NextStep int
+	UserID   int
+	Email    string
+	Name     string
+	// VerificationHash empreinte SHA-256 du jeton envoyé par email (le jeton n'est jamais stocké)
+	VerificationHash string
+	Confirmed        bool
+	ExpiresAt        time.Time
+	WorkspaceID      string
+	// FailedStep / Error étape en échec et cause technique, quand la saga a été compensée
+	FailedStep string
+	Error      string
+	Created    time.Time
+	Updated    time.Time
+}
+
+// OnboardingRepository définit le contrat de persistance des sagas d'inscription
+type OnboardingRepository interface {
+	Save(ctx context.Context, onboarding Onboarding) error
+	Get(ctx context.Context, id string) (*Onboarding, error)
+	// ListByStatus inscriptions dans ce statut (reprise des sagas en attente)
+	ListByStatus(ctx context.Context, status string) ([]Onboarding, error)
+	CountByStatus(ctx context.Context, status string) (int, error)
+}
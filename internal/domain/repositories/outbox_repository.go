@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrOutboxMessageNotFound aucun message avec cet ID
+var ErrOutboxMessageNotFound = errors.New("outbox message not found")
+
+// OutboxMessage message en attente d'envoi vers un système externe (email, webhook...)
+// Le use case écrit dans l'outbox ; un dispatcher l'envoie ensuite avec des réessais
+type OutboxMessage struct {
+	ID        int
+	Kind      string // ex: "email"
+	DedupKey  string // unique : empêche de mettre deux fois le même message en file
+	Payload   []byte // JSON propre au Kind
+	Attempts  int
+	LastError string
+	Created   time.Time
+	SentAt    *time.Time
+	// Failures dernières tentatives en échec (au plus MaxDeliveryFailures), la plus ancienne d'abord
+	Failures []DeliveryFailure
+}
+
+// OutboxRepository définit le contrat de la file d'envoi transactionnelle
+type OutboxRepository interface {
+	// Enqueue ajoute un message ; retourne false si DedupKey existe déjà
+	Enqueue(ctx context.Context, message *OutboxMessage) (bool, error)
+	// ListPending retourne les messages non envoyés ayant moins de maxAttempts tentatives
+	ListPending(ctx context.Context, limit, maxAttempts int) ([]*OutboxMessage, error)
+	MarkSent(ctx context.Context, id int, sentAt time.Time) error
+	MarkFailed(ctx context.Context, id int, reason string, failedAt time.Time) error
+
+	// File des messages abandonnés : non envoyés après maxAttempts tentatives ou plus
+	ListDead(ctx context.Context, maxAttempts, limit, offset int) ([]*OutboxMessage, error)
+	CountDead(ctx context.Context, maxAttempts int) (int, error)
+	Get(ctx context.Context, id int) (*OutboxMessage, error)
+	// Requeue remet les tentatives à zéro (l'historique des échecs est conservé)
+	Requeue(ctx context.Context, id int) error
+	// Delete supprime le message ; sa DedupKey reste réservée, il ne sera pas remis en file
+	Delete(ctx context.Context, id int) error
+}
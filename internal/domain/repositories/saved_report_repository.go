@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+// ErrSavedReportNotFound aucun rapport avec cet identifiant
+var ErrSavedReportNotFound = errors.New("rapport introuvable")
+
+// SavedReportRepository définit le contrat de persistance des rapports analytics enregistrés
+type SavedReportRepository interface {
+	// Create renseigne report.ID
+	Create(ctx context.Context, report *entities.SavedReport) error
+	Get(ctx context.Context, id int) (*entities.SavedReport, error)
+	// ListByOwner rapports de l'utilisateur, du plus ancien au plus récent
+	ListByOwner(ctx context.Context, ownerID int) ([]*entities.SavedReport, error)
+	// Delete ErrSavedReportNotFound si le rapport n'existe pas
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"time"
+)
+
+// IndexedEvent événement du domaine tel qu'il est recopié dans le moteur de recherche :
+// nom, utilisateur concerné et date, jamais le contenu (emails, hash...)
+type IndexedEvent struct {
+	Name       string
+	UserID     int // 0 : événement sans utilisateur identifié
+	OccurredAt time.Time
+}
+
+// SearchIndexWriter alimente un moteur de recherche externe à partir des événements du domaine
+// Les écritures sont idempotentes : un même utilisateur peut être réindexé autant de fois que nécessaire
+type SearchIndexWriter interface {
+	IndexUser(ctx context.Context, user *entities.User) error
+	// DeleteUser ne signale pas d'erreur pour un document déjà absent
+	DeleteUser(ctx context.Context, id int) error
+	IndexEvent(ctx context.Context, event IndexedEvent) error
+}
+
+// EventSearchFilters critères combinés par ET ; Interval pas de l'histogramme (hour, day, week, month)
+type EventSearchFilters struct {
+	Names    []string // vide = tous les événements
+	UserID   int      // 0 = tous les utilisateurs
+	From     *time.Time
+	To       *time.Time // exclu
+	Interval string
+	Limit    int
+	Offset   int
+}
+
+// EventHistogramBucket nombre d'événements d'un intervalle de l'histogramme
+type EventHistogramBucket struct {
+	Start time.Time
+	Count int
+}
+
+// EventSearchResult événements les plus récents d'abord, agrégats sur l'ensemble des résultats
+type EventSearchResult struct {
+	Events    []IndexedEvent
+	Total     int
+	ByName    map[string]int
+	Histogram []EventHistogramBucket
+}
+
+// EventSearchRepository recherche et agrégation des événements recopiés par SearchIndexWriter
+type EventSearchRepository interface {
+	SearchEvents(ctx context.Context, filters EventSearchFilters) (*EventSearchResult, error)
+}
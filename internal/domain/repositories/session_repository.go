@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"time"
+)
+
+// SessionRepository définit le contrat de l'historique des sessions par utilisateur
+type SessionRepository interface {
+	Save(ctx context.Context, session *entities.Session) error
+	// ListByUser les plus récentes d'abord ; limit 0 = toutes celles conservées
+	ListByUser(ctx context.Context, userID int, limit int) ([]entities.Session, error)
+	DeleteByUser(ctx context.Context, userID int) error
+	// ListCreatedBefore sessions ouvertes avant cutoff, tous utilisateurs, les plus anciennes d'abord (rétention)
+	ListCreatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]entities.Session, error)
+	// DeleteByIDs supprime les sessions et retourne leur nombre ; les ID inconnus sont ignorés
+	DeleteByIDs(ctx context.Context, ids []string) (int, error)
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+// ErrTermsAcceptanceNotFound l'utilisateur n'a encore accepté aucune version
+var ErrTermsAcceptanceNotFound = errors.New("terms acceptance not found")
+
+// TermsAcceptanceRepository historique des acceptations des conditions d'utilisation
+// Les enregistrements ne sont jamais modifiés : chaque acceptation ajoute une ligne
+type TermsAcceptanceRepository interface {
+	Save(ctx context.Context, acceptance *entities.TermsAcceptance) error
+	// GetLatest retourne l'acceptation la plus récente de l'utilisateur
+	GetLatest(ctx context.Context, userID int) (*entities.TermsAcceptance, error)
+	// ListByUser retourne l'historique, de la plus ancienne à la plus récente
+	ListByUser(ctx context.Context, userID int) ([]*entities.TermsAcceptance, error)
+}
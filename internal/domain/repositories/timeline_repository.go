@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// Catégories des entrées de la chronologie utilisateur
+const (
+	TimelineSignup    = "signup"
+	TimelineLogin     = "login"
+	TimelineProfile   = "profile"
+	TimelineAccount   = "account"
+	TimelineAnalytics = "analytics"
+)
+
+// TimelineEntry fait marquant de la vie d'un compte, présenté au support : jamais de valeur
+// personnelle (email, téléphone, propriétés analytics), seulement les noms de champs modifiés
+// et quelques détails non sensibles (statut, version des conditions...)
+type TimelineEntry struct {
+	ID       int64             `json:"id"`
+	UserID   int               `json:"user_id"`
+	Category string            `json:"category"`
+	Event    string            `json:"event"` // événement du domaine ou nom de l'événement analytics
+	Fields   []string          `json:"fields,omitempty"`
+	Details  map[string]string `json:"details,omitempty"`
+	At       time.Time         `json:"at"`
+}
+
+// TimelinePosition position d'une entrée dans la chronologie, du plus récent au plus ancien
+type TimelinePosition struct {
+	At time.Time
+	ID int64
+}
+
+// TimelineFilter critères de lecture ; Before nil = depuis l'entrée la plus récente
+type TimelineFilter struct {
+	UserID     int
+	Categories []string
+	Before     *TimelinePosition
+	Limit      int
+}
+
+// TimelineRepository définit le contrat du modèle de lecture de la chronologie utilisateur
+//   - Record renseigne l'ID de l'entrée
+//   - List les plus récentes d'abord (At puis ID) : les événements analytics arrivent avec
+//     l'horodatage client, l'ordre d'enregistrement n'est pas l'ordre chronologique
+type TimelineRepository interface {
+	Record(ctx context.Context, entry *TimelineEntry) error
+	List(ctx context.Context, filter TimelineFilter) ([]TimelineEntry, error)
+	DeleteByUser(ctx context.Context, userID int) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUploadSessionNotFound aucune session d'upload avec cet identifiant
+var ErrUploadSessionNotFound = errors.New("session d'upload introuvable")
+
+// UploadSessionRepository définit le contrat de persistance des sessions d'upload fragmenté
+type UploadSessionRepository interface {
+	// Save crée ou remplace la session
+	Save(ctx context.Context, session *entities.UploadSession) error
+	Get(ctx context.Context, id string) (*entities.UploadSession, error)
+	Delete(ctx context.Context, id string) error
+	// ListExpired sessions expirées à now, quel que soit leur statut (purge des fichiers)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.UploadSession, error)
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+)
+
+// Ressources mesurées par tenant
+const (
+	UsageAPICalls = "api_calls" // appels de use cases, par mois calendaire UTC
+	UsageUsers    = "users"     // utilisateurs stockés
+	UsageEvents   = "events"    // événements analytics stockés
+)
+
+// UsageResources ressources mesurées, dans l'ordre d'affichage
+var UsageResources = []string{UsageAPICalls, UsageUsers, UsageEvents}
+
+// UsageLifetime période des compteurs qui ne repartent jamais de zéro (ressources stockées)
+const UsageLifetime = ""
+
+// UsageRepository définit le contrat des compteurs d'usage par tenant
+// period : mois "AAAA-MM" pour les appels, UsageLifetime pour les ressources stockées
+type UsageRepository interface {
+	// Add ajoute delta (négatif à la suppression) et retourne la nouvelle valeur du compteur
+	Add(ctx context.Context, tenant, period, resource string, delta int) (int, error)
+	// Get compteurs (ressource -> valeur) d'un tenant sur une période ; un compteur absent vaut 0
+	Get(ctx context.Context, tenant, period string) (map[string]int, error)
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrConcurrencyConflict le flux a été modifié depuis sa lecture (version attendue dépassée)
+	ErrConcurrencyConflict = errors.New("event stream version conflict")
+	// ErrSnapshotNotFound aucun snapshot n'existe encore pour cet agrégat
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+)
+
+// StoredEvent événement tel qu'enregistré dans le flux d'un agrégat
+type StoredEvent struct {
+	AggregateID int
+	Version     int // 1 pour le premier événement du flux
+	Event       events.Event
+	RecordedAt  time.Time
+	// Position rang dans l'ensemble du magasin, tous flux confondus (1 pour le premier événement) :
+	// checkpoint des rejeux
+	Position int64
+}
+
+// UserSnapshot état de l'agrégat à une version donnée, pour éviter de rejouer tout le flux
+type UserSnapshot struct {
+	User    entities.User
+	Version int
+}
+
+// UserEventStore définit le contrat du stockage event-sourcé des utilisateurs
+type UserEventStore interface {
+	// NextID réserve l'identifiant d'un nouvel agrégat
+	NextID(ctx context.Context) (int, error)
+	// Append ajoute des événements si la version courante du flux vaut expectedVersion
+	Append(ctx context.Context, aggregateID, expectedVersion int, evts []events.Event) ([]StoredEvent, error)
+	// Load retourne les événements de version strictement supérieure à afterVersion
+	Load(ctx context.Context, aggregateID, afterVersion int) ([]StoredEvent, error)
+	SaveSnapshot(ctx context.Context, snapshot UserSnapshot) error
+	LoadSnapshot(ctx context.Context, aggregateID int) (*UserSnapshot, error)
+	// ReadAll au plus limit événements de position strictement supérieure à afterPosition,
+	// tous flux confondus, par position croissante
+	ReadAll(ctx context.Context, afterPosition int64, limit int) ([]StoredEvent, error)
+	// HeadPosition position du dernier événement enregistré (0 : magasin vide)
+	HeadPosition(ctx context.Context) (int64, error)
+}
+
+// UserStateRepository table d'état courant maintenue par projection du flux
+// Elle sert aux lectures qui ne peuvent pas se faire par rejeu (email, handle, lots, listing, comptage, inactivité)
+type UserStateRepository interface {
+	Upsert(ctx context.Context, user *entities.User) error
+	DeleteById(ctx context.Context, id int) error
+	GetByIds(ctx context.Context, ids []int) ([]*entities.User, error)
+	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+	IsEmailTaken(ctx context.Context, email string) (bool, error)
+	GetByHandle(ctx context.Context, handle string) (*entities.User, error)
+	IsHandleTaken(ctx context.Context, handle string) (bool, error)
+	List(ctx context.Context, limit, offset int) ([]*entities.User, error)
+	Count(ctx context.Context) (int, error)
+	ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error)
+	Each(ctx context.Context, filters UserRepositoryFilters, fn func(*entities.User) error) error
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// UserView modèle de lecture dénormalisé d'un utilisateur (jamais de mot de passe)
+type UserView struct {
+	ID      int
+	Email   string
+	Name    string
+	Created time.Time
+	Updated time.Time
+	// Status "active", "deactivated" ou "banned"
+	Status string
+	Handle string
+	// LastLoginAt dernière connexion réussie (nil : jamais connecté)
+	LastLoginAt *time.Time
+	Attributes  map[string]any
+}
+
+// UserSortField critère de tri des listes d'utilisateurs
+type UserSortField string
+
+const (
+	UserSortCreated UserSortField = "created"
+	UserSortName    UserSortField = "name"
+	UserSortEmail   UserSortField = "email"
+)
+
+// UserSortFields critères acceptés, dans l'ordre de la documentation de l'API
+var UserSortFields = []UserSortField{UserSortCreated, UserSortName, UserSortEmail}
+
+// UserSort ordre d'une liste ; à critère égal, les vues sont départagées par ID (même sens)
+// pour que la pagination par offset reste stable
+type UserSort struct {
+	Field      UserSortField
+	Descending bool
+}
+
+// DefaultUserSort ordre historique des listes : par date d'inscription croissante
+var DefaultUserSort = UserSort{Field: UserSortCreated}
+
+// UserReadRepository définit le contrat du modèle de lecture (côté query du CQRS)
+// Il est alimenté par les événements du domaine, jamais par les use cases de commande,
+// si bien que les lectures ne se disputent pas les verrous/transactions d'écriture
+type UserReadRepository interface {
+	GetById(ctx context.Context, id int) (*UserView, error)
+	// GetByIds charge plusieurs vues en une requête ; les IDs inconnus sont absents du résultat
+	GetByIds(ctx context.Context, ids []int) ([]*UserView, error)
+	GetByEmail(ctx context.Context, email string) (*UserView, error)
+	GetByHandle(ctx context.Context, handle string) (*UserView, error)
+	List(ctx context.Context, sort UserSort, limit, offset int) ([]*UserView, error)
+	Count(ctx context.Context) (int, error)
+	// EstimateCount approximation bon marché de Count (compte exact si Count ne coûte rien)
+	EstimateCount(ctx context.Context) (int, error)
+	// ListByStatus / CountByStatus mêmes lectures restreintes à un statut de compte
+	ListByStatus(ctx context.Context, status string, sort UserSort, limit, offset int) ([]*UserView, error)
+	CountByStatus(ctx context.Context, status string) (int, error)
+	// CountCreatedBetween comptes créés dans [from, to) (index sur la date de création)
+	CountCreatedBetween(ctx context.Context, from, to time.Time) (int, error)
+
+	// Save, DeleteById et Clear sont réservés au projecteur
+	Save(ctx context.Context, view *UserView) error
+	DeleteById(ctx context.Context, id int) error
+	// Clear supprime toutes les vues (reconstruction complète par rejeu des événements)
+	Clear(ctx context.Context) error
+}
@@ -3,6 +3,23 @@ package repositories
 import (
 	"clean-archi-analytics/internal/domain/entities"
 	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrUserNotFound est retournée par les implémentations quand l'utilisateur n'existe pas
+	ErrUserNotFound = errors.New("user not found")
+	// ErrHandleTaken le handle appartient déjà à un autre utilisateur (contrôle final d'unicité)
+	ErrHandleTaken = errors.New("handle déjà utilisé")
+	// ErrUserModified l'utilisateur a été modifié depuis sa lecture (écriture conditionnelle refusée)
+	ErrUserModified = errors.New("utilisateur modifié depuis sa lecture")
+	// ErrSearchNotSupported le dépôt sous-jacent n'implémente pas UserSearchRepository
+	ErrSearchNotSupported = errors.New("recherche non supportée par ce dépôt")
+	// ErrFullTextNotSupported le dépôt ne sait pas traiter UserRepositoryFilters.Query (aucun index plein texte)
+	ErrFullTextNotSupported = errors.New("recherche plein texte non supportée par ce dépôt")
+	// ErrStopIteration retourné par le callback de Each : arrête le parcours sans erreur
+	ErrStopIteration = errors.New("stop iteration")
 )
 
 // UserRepository définit le contrat pour la persistance des utilisateurs
@@ -11,26 +28,84 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) (*entities.User, error)
 	GetById(ctx context.Context, id int) (*entities.User, error)
+	// GetByIds charge plusieurs utilisateurs en une requête ; les IDs inconnus sont absents du résultat
+	GetByIds(ctx context.Context, ids []int) ([]*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
-	isEmailTaken(ctx context.Context, email string) (bool, error)
+	IsEmailTaken(ctx context.Context, email string) (bool, error)
+	// GetByHandle / IsHandleTaken attendent un handle normalisé (entities.NormalizeHandle)
+	GetByHandle(ctx context.Context, handle string) (*entities.User, error)
+	IsHandleTaken(ctx context.Context, handle string) (bool, error)
 	Update(ctx context.Context, user *entities.User) (*entities.User, error)
+	// UpdateIfUnchanged Update conditionnel (verrouillage optimiste) : l'écriture n'a lieu que si
+	// l'utilisateur stocké porte encore la date de modification expectedUpdated, sinon ErrUserModified
+	UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error)
 	DeleteById(ctx context.Context, id int) error
 	List(ctx context.Context, limit, offset int) ([]*entities.User, error)
 	Count(ctx context.Context) (int, error)
+	// EstimateCount approximation bon marché de Count (statistiques du planificateur en SQL) ;
+	// les stockages où Count est déjà immédiat retournent le compte exact
+	EstimateCount(ctx context.Context) (int, error)
+	// ListInactiveSince comptes actifs dont la dernière activité (connexion, à défaut inscription)
+	// est antérieure à cutoff, triés par ID
+	ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error)
+	// Each appelle fn pour chaque utilisateur correspondant aux filtres, par ID croissant, en lisant
+	// le stockage par lots : le résultat n'est jamais chargé en entier (exports, résumés, réindexations)
+	//   - Query n'est pas supporté (ErrFullTextNotSupported), Limit et Offset sont ignorés
+	//   - le parcours avance par ID (keyset) : des écritures concurrentes ne décalent ni ne dupliquent rien,
+	//     un utilisateur créé pendant le parcours est vu s'il arrive après la position courante
+	//   - fn est appelé hors de toute transaction ou verrou : il peut écrire dans le dépôt
+	//   - une erreur de fn arrête le parcours et est retournée telle quelle, sauf ErrStopIteration
+	Each(ctx context.Context, filters UserRepositoryFilters, fn func(*entities.User) error) error
+
+	// Opérations en lot (imports) : tout ou rien, un seul aller-retour vers le stockage quand c'est possible
+	CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error)
+	UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error)
+	DeleteByIds(ctx context.Context, ids []int) error
 }
 
+// UserRepositoryFilters critères combinés par ET ; Email et Name sont des sous-chaînes insensibles à la casse
 type UserRepositoryFilters struct {
+	// Query texte libre sur le nom et l'email : chaque mot doit préfixer un mot indexé (ET),
+	// les correspondances sur le nom pèsent plus lourd que celles sur l'email
+	Query     string
 	Email     string
 	Name      string
+	Status    string // vide = tous les statuts
 	CreatedAt struct {
-		From *string
-		To   *string
+		From *string // RFC 3339, inclus
+		To   *string // RFC 3339, exclu
 	}
-	Limit  int
-	Offset int
+	// Attributes égalité stricte sur chaque clé, valeurs déjà normalisées (entities.AttributeSchema)
+	Attributes map[string]any
+	Limit      int
+	Offset     int
 }
 
+// UserSearchRepository résultats triés par ID, ou par pertinence décroissante (puis ID) avec Query
 type UserSearchRepository interface {
 	UserRepository
 	Search(ctx context.Context, filters UserRepositoryFilters) ([]*entities.User, error)
 }
+
+// Facettes calculées par les moteurs de recherche externes (UserFacetedSearchRepository)
+const (
+	UserFacetStatus      = "status"
+	UserFacetEmailDomain = "email_domain"
+)
+
+// UserSearchResult recherche enrichie : facettes sur l'ensemble des résultats (pas seulement la page)
+// et extraits surlignés des champs correspondant à Query
+type UserSearchResult struct {
+	Users []*entities.User
+	// Facets nombre de résultats par valeur ("status" → {"active": 12, "deactivated": 1})
+	Facets map[string]map[string]int
+	// Highlights extraits par utilisateur puis par champ ("name" → ["<em>Jean</em> Dupont"])
+	Highlights map[int]map[string][]string
+}
+
+// UserFacetedSearchRepository moteur de recherche dédié (Elasticsearch...) : mêmes critères que
+// UserSearchRepository, correspondance approchée sur Query (fautes de frappe), facettes et extraits
+type UserFacetedSearchRepository interface {
+	UserSearchRepository
+	SearchWithFacets(ctx context.Context, filters UserRepositoryFilters) (*UserSearchResult, error)
+}
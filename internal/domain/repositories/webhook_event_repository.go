@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+)
+
+// WebhookEventRepository garde la trace des événements entrants déjà traités
+// Elle permet la déduplication : les fournisseurs rejouent souvent un même événement
+type WebhookEventRepository interface {
+	// Reserve marque l'événement comme pris en charge.
+	// Retourne false si l'événement (source + ID) a déjà été réservé.
+	Reserve(ctx context.Context, source, eventID string) (bool, error)
+	// Release libère une réservation pour qu'un nouvel envoi puisse être traité
+	// (utilisé quand le traitement échoue).
+	Release(ctx context.Context, source, eventID string) error
+}
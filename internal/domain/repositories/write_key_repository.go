@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"errors"
+)
+
+// ErrWriteKeyNotFound aucune clé d'écriture avec cet identifiant ou cette empreinte
+var ErrWriteKeyNotFound = errors.New("clé d'écriture introuvable")
+
+// WriteKeyRepository définit le contrat de persistance des clés d'écriture des tenants
+type WriteKeyRepository interface {
+	// Create renseigne key.ID
+	Create(ctx context.Context, key *entities.WriteKey) error
+	Get(ctx context.Context, id int) (*entities.WriteKey, error)
+	// GetByHash clé dont l'empreinte est hash, expirée ou non
+	GetByHash(ctx context.Context, hash string) (*entities.WriteKey, error)
+	// ListByTenant clés du tenant, de la plus ancienne à la plus récente
+	ListByTenant(ctx context.Context, tenantID string) ([]*entities.WriteKey, error)
+	// Save met à jour une clé existante ; ErrWriteKeyNotFound sinon
+	Save(ctx context.Context, key *entities.WriteKey) error
+}
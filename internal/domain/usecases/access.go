@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// =============================================================================
+// NIVEAUX D'ACCÈS : déclarés à l'enregistrement des use cases (Wrap)
+// =============================================================================
+
+// Access niveau d'accès d'un use case, déclaré par Wrap. La valeur zéro est AccessAdmin : un
+// use case enregistré sans niveau réfléchi reste réservé à l'administration
+type Access int
+
+const (
+	// AccessAdmin rôle d'administration (ADMIN_ROLE) exigé
+	AccessAdmin Access = iota
+	// AccessSupport rôle de support (SUPPORT_ROLE) exigé : chronologie d'un utilisateur
+	AccessSupport
+	// AccessImpersonation rôle d'impersonation (IMPERSONATION_ROLE) exigé
+	AccessImpersonation
+	// AccessUser utilisateur authentifié, sur lui-même : l'utilisateur visé par l'entrée
+	// (resource.user_id) est l'acteur, ou le use case se limite aux ressources de l'acteur
+	AccessUser
+	// AccessPublic appel anonyme permis : inscription, connexion, ingestion, webhooks signés
+	AccessPublic
+	// AccessSystem tâches internes seulement (SystemActor) : jobs, sous-commandes, SCIM, liens signés
+	AccessSystem
+)
+
+var accessNames = map[Access]string{
+	AccessAdmin:         "admin",
+	AccessSupport:       "support",
+	AccessImpersonation: "impersonation",
+	AccessUser:          "user",
+	AccessPublic:        "public",
+	AccessSystem:        "system",
+}
+
+// String nom exposé aux politiques (attribut "access")
+func (a Access) String() string {
+	if name, ok := accessNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("access(%d)", int(a))
+}
+
+// UseCaseRegistry niveaux d'accès des use cases enregistrés par Wrap. Un nom inconnu (action
+// vérifiée hors pipeline, comme debug_diagnostics) relève de l'administration
+type UseCaseRegistry struct {
+	mu     sync.RWMutex
+	access map[string]Access
+}
+
+func NewUseCaseRegistry() *UseCaseRegistry {
+	return &UseCaseRegistry{access: make(map[string]Access)}
+}
+
+// Register déclare le niveau d'accès de name ; le même nom déclaré avec deux niveaux est une
+// erreur de composition, signalée au démarrage
+func (r *UseCaseRegistry) Register(name string, access Access) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if declared, ok := r.access[name]; ok && declared != access {
+		panic(fmt.Sprintf("use case %q déclaré %s puis %s", name, declared, access))
+	}
+	r.access[name] = access
+}
+
+// Access niveau déclaré de name (AccessAdmin pour un nom inconnu ou un registre nil)
+func (r *UseCaseRegistry) Access(name string) Access {
+	if r == nil {
+		return AccessAdmin
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.access[name]
+}
+
+// Names use cases enregistrés, triés ; levels limite la liste à ces niveaux (aucun = tous)
+func (r *UseCaseRegistry) Names(levels ...Access) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.access))
+	for name, access := range r.access {
+		if len(levels) == 0 || slices.Contains(levels, access) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AccessGuard Authorizer qui applique le niveau déclaré de chaque use case avant de déléguer
+// aux politiques : les tâches internes passent partout, un appel anonyme n'atteint que les use
+// cases publics, et les niveaux à rôle exigent ce rôle, jamais dans une session d'impersonation
+type AccessGuard struct {
+	next     Authorizer
+	registry *UseCaseRegistry
+	roles    map[Access]string
+}
+
+// NewAccessGuard roles rôle exigé par niveau (AccessAdmin, AccessSupport, AccessImpersonation) ;
+// un rôle vide ferme le niveau à tous les utilisateurs
+func NewAccessGuard(next Authorizer, registry *UseCaseRegistry, roles map[Access]string) *AccessGuard {
+	return &AccessGuard{next: next, registry: registry, roles: roles}
+}
+
+func (g *AccessGuard) Authorize(ctx context.Context, useCase string, input interface{}) error {
+	access := g.registry.Access(useCase)
+	actor, _ := ActorFromContext(ctx)
+	switch {
+	case actor.System, access == AccessPublic:
+	case actor.UserID <= 0:
+		return ErrAuthenticationRequired
+	case access == AccessSystem:
+		return ErrForbidden
+	case access == AccessUser:
+	case actor.Impersonation != nil || g.roles[access] == "" || !slices.Contains(actor.Roles, g.roles[access]):
+		return ErrForbidden
+	}
+	return g.next.Authorize(ctx, useCase, input)
+}
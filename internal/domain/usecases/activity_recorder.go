@@ -0,0 +1,49 @@
+// internal/domain/usecases/activity_recorder.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+)
+
+// =============================================================================
+// ACTIVITY RECORDER : journal d'activité par utilisateur, alimenté par les événements
+// =============================================================================
+
+type ActivityRecorder struct {
+	activityRepo repositories.ActivityRepository
+}
+
+func NewActivityRecorder(activityRepo repositories.ActivityRepository) *ActivityRecorder {
+	return &ActivityRecorder{activityRepo: activityRepo}
+}
+
+// Handle enregistre les événements qui concernent un utilisateur identifié
+func (r *ActivityRecorder) Handle(ctx context.Context, event events.Event) error {
+	userID := 0
+	var fields []string
+	switch e := event.(type) {
+	case events.UserCreated:
+		userID = e.UserID
+	case events.UserProfileUpdated:
+		userID, fields = e.UserID, e.ChangedFields
+	case events.UserAttributesChanged:
+		userID, fields = e.UserID, e.ChangedFields
+	case events.DigestPreferenceChanged:
+		userID = e.UserID
+	case events.UserLoggedIn:
+		userID = e.UserID
+	case events.UserImpersonated:
+		userID = e.UserID
+	default:
+		return nil
+	}
+
+	return r.activityRepo.Record(ctx, repositories.ActivityEntry{
+		UserID: userID,
+		Kind:   event.EventName(),
+		Fields: fields,
+		At:     event.OccurredAt(),
+	})
+}
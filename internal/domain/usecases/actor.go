@@ -0,0 +1,92 @@
+// internal/domain/usecases/actor.go
+package usecases
+
+import (
+	"context"
+)
+
+// Actor identifie qui exécute un use case (utilisateur connecté, tenant)
+// Il est posé dans le context par la couche delivery et lu par les use cases et leurs ports
+type Actor struct {
+	UserID   int
+	TenantID string
+	// Roles rôles accordés à la connexion (groupes de l'annuaire) ; vide pour un compte local
+	Roles []string
+	// System exécution interne (tâches planifiées, sous-commandes) : aucun utilisateur derrière
+	System bool
+	// Impersonation session ouverte par un administrateur au nom de UserID ; nil sinon
+	Impersonation *Impersonation
+}
+
+// Impersonation administrateur qui agit réellement derrière l'acteur d'une session d'impersonation
+type Impersonation struct {
+	AdminID int
+	// Banner texte que les clients affichent en permanence pendant la session
+	Banner string
+	// Scope use cases permis à la session (ImpersonationScope à l'ouverture) ; vide = aucun
+	Scope []string
+}
+
+// SystemActor acteur des exécutions internes, posé sur le context des tâches de fond
+var SystemActor = Actor{System: true}
+
+type actorContextKey struct{}
+
+// ContextWithActor retourne un context portant l'acteur courant
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext retourne l'acteur courant, ou un Actor vide (anonyme)
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(Actor)
+	return actor, ok
+}
+
+type requestIDContextKey struct{}
+
+// ContextWithRequestID retourne un context portant l'identifiant de la requête entrante
+// Il relie les logs, les rapports d'erreur et la réponse HTTP (en-tête X-Request-ID)
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext retourne l'identifiant de la requête, ou "" hors requête HTTP
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+type localeContextKey struct{}
+
+// ContextWithLocale retourne un context portant la locale préférée de l'appelant (entities.NormalizeLocale)
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext retourne la locale de l'appelant, ou "" si elle est inconnue
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	return locale
+}
+
+// ClientInfo client à l'origine de la requête : adresse IP, User-Agent et jeton CAPTCHA
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	// CaptchaToken jeton obtenu par le navigateur auprès du fournisseur (en-tête X-Captcha-Token)
+	CaptchaToken string
+}
+
+type clientContextKey struct{}
+
+// ContextWithClient retourne un context portant l'adresse et le User-Agent de l'appelant
+func ContextWithClient(ctx context.Context, client ClientInfo) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// ClientFromContext retourne le client de la requête, vide hors requête HTTP
+func ClientFromContext(ctx context.Context) ClientInfo {
+	client, _ := ctx.Value(clientContextKey{}).(ClientInfo)
+	return client
+}
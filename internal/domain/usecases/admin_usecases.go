@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// =============================================================================
+// ADMINISTRATION DE L'INSTANCE : niveau de log (rôle requis : AccessAdmin)
+// =============================================================================
+
+// LogLevelController logger dont le niveau minimal change à chaud (services.SlogLogger)
+type LogLevelController interface {
+	LogLevel() string
+	SetLogLevel(level string) error
+}
+
+// logLevels niveaux reconnus, du plus bavard au plus discret
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// errLogLevelUnsupported logger fourni par l'application hôte, sans LogLevelController
+var errLogLevelUnsupported = errors.New("le logger de l'application ne permet pas de changer de niveau")
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// GetLogLevelUseCase niveau courant ; logs nil si le logger de l'application hôte n'est pas pilotable
+type GetLogLevelUseCase struct {
+	logs LogLevelController
+}
+
+func NewGetLogLevelUseCase(logs LogLevelController) *GetLogLevelUseCase {
+	return &GetLogLevelUseCase{logs: logs}
+}
+
+func (uc *GetLogLevelUseCase) Execute(ctx context.Context, _ struct{}) (*LogLevelResponse, error) {
+	if uc.logs == nil {
+		return nil, errLogLevelUnsupported
+	}
+	return &LogLevelResponse{Level: uc.logs.LogLevel()}, nil
+}
+
+type SetLogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+func (req SetLogLevelRequest) Validate() error {
+	if !slices.Contains(logLevels, req.Level) {
+		return errors.New("niveau attendu : \"debug\", \"info\", \"warn\" ou \"error\"")
+	}
+	return nil
+}
+
+func (req SetLogLevelRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"level": req.Level}
+}
+
+// SetLogLevelUseCase change le niveau de l'instance qui reçoit l'appel, jusqu'au prochain
+// changement de LOG_LEVEL dans SETTINGS_FILE ou au redémarrage
+type SetLogLevelUseCase struct {
+	logs   LogLevelController
+	logger Logger
+}
+
+func NewSetLogLevelUseCase(logs LogLevelController, logger Logger) *SetLogLevelUseCase {
+	return &SetLogLevelUseCase{logs: logs, logger: logger}
+}
+
+func (uc *SetLogLevelUseCase) Execute(ctx context.Context, req SetLogLevelRequest) (*LogLevelResponse, error) {
+	if uc.logs == nil {
+		return nil, errLogLevelUnsupported
+	}
+	previous := uc.logs.LogLevel()
+	if err := uc.logs.SetLogLevel(req.Level); err != nil {
+		return nil, err
+	}
+	// Warn : reste visible quel que soit le nouveau niveau
+	uc.logger.Warn("Log level changed", map[string]interface{}{"level": req.Level, "previous": previous})
+	return &LogLevelResponse{Level: uc.logs.LogLevel()}, nil
+}
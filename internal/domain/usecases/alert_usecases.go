@@ -0,0 +1,424 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// ALERTES SUR LES MÉTRIQUES : règles évaluées périodiquement sur les agrégats quotidiens
+// =============================================================================
+
+const (
+	// maxAlertRuleName longueur maximale du nom d'une règle
+	maxAlertRuleName = 100
+	// maxAlertWindowMinutes fenêtre d'évaluation la plus longue (7 jours)
+	maxAlertWindowMinutes = 7 * 24 * 60
+	// maxAlertRecipients destinataires notifiés par règle
+	maxAlertRecipients = 20
+)
+
+// Statuts d'une alerte envoyée
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+// AlertNotice déclenchement ou retour à la normale d'une règle, envoyé à son webhook
+// Previous valeur de la fenêtre précédente (référence de drop et spike)
+type AlertNotice struct {
+	RuleID      int       `json:"rule_id"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Event       string    `json:"event"`
+	PerEvent    string    `json:"per_event,omitempty"`
+	Condition   string    `json:"condition"`
+	Threshold   float64   `json:"threshold"`
+	Value       float64   `json:"value"`
+	Previous    float64   `json:"previous"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+}
+
+// AlertWebhook port de livraison des alertes à l'URL d'une règle (Slack, PagerDuty, outil interne)
+type AlertWebhook interface {
+	Send(ctx context.Context, url string, notice AlertNotice) error
+}
+
+// =============================================================================
+// CREATE ALERT RULE USE CASE
+// =============================================================================
+
+type CreateAlertRuleUseCase struct {
+	repo  repositories.AlertRuleRepository
+	clock Clock
+}
+
+func NewCreateAlertRuleUseCase(repo repositories.AlertRuleRepository, clock Clock) *CreateAlertRuleUseCase {
+	return &CreateAlertRuleUseCase{repo: repo, clock: clock}
+}
+
+// CreateAlertRuleRequest Threshold pourcentage de variation (drop, spike) ou valeur absolue (above,
+// below), rapport entre 0 et 1 avec PerEvent ; WindowMinutes défaut 60 (comparaison heure par heure)
+type CreateAlertRuleRequest struct {
+	Name          string  `json:"name"`
+	Event         string  `json:"event"`
+	PerEvent      string  `json:"per_event,omitempty"`
+	Condition     string  `json:"condition"`
+	Threshold     float64 `json:"threshold"`
+	WindowMinutes int     `json:"window_minutes,omitempty"`
+	MinCount      int     `json:"min_count,omitempty"`
+	Recipients    []int   `json:"recipients,omitempty"`
+	WebhookURL    string  `json:"webhook_url,omitempty"`
+}
+
+func (req CreateAlertRuleRequest) Validate() error {
+	if name := strings.TrimSpace(req.Name); name == "" || len(name) > maxAlertRuleName {
+		return errors.New("le nom est obligatoire (100 caractères max)")
+	}
+	if entities.ValidateEventName(req.Event) != nil {
+		return errors.New("event doit être un nom d'événement valide")
+	}
+	if req.PerEvent != "" && (entities.ValidateEventName(req.PerEvent) != nil || req.PerEvent == req.Event) {
+		return errors.New("per_event doit être un nom d'événement valide, distinct de event")
+	}
+	if !slices.Contains(entities.AlertConditions, req.Condition) {
+		return errors.New("condition doit valoir drop, spike, above ou below")
+	}
+	if req.Threshold < 0 || (req.Condition == entities.AlertDrop && req.Threshold > 100) {
+		return errors.New("threshold doit être positif (100 % au plus pour drop)")
+	}
+	if req.WindowMinutes < 0 || req.WindowMinutes > maxAlertWindowMinutes {
+		return errors.New("window_minutes doit être compris entre 1 et 10080")
+	}
+	if req.MinCount < 0 {
+		return errors.New("min_count ne peut pas être négatif")
+	}
+	if len(req.Recipients) == 0 && req.WebhookURL == "" {
+		return errors.New("au moins un destinataire ou un webhook_url")
+	}
+	if len(req.Recipients) > maxAlertRecipients {
+		return errors.New("20 destinataires au plus")
+	}
+	if req.WebhookURL != "" {
+		parsed, err := url.Parse(req.WebhookURL)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+			return errors.New("webhook_url doit être une URL absolue")
+		}
+	}
+	return nil
+}
+
+func (req CreateAlertRuleRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"name":           req.Name,
+		"event":          req.Event,
+		"per_event":      req.PerEvent,
+		"condition":      req.Condition,
+		"threshold":      req.Threshold,
+		"window_minutes": req.WindowMinutes,
+		"recipients":     len(req.Recipients),
+	}
+}
+
+func (uc *CreateAlertRuleUseCase) Execute(ctx context.Context, req CreateAlertRuleRequest) (*entities.AlertRule, error) {
+	if req.WindowMinutes == 0 {
+		req.WindowMinutes = 60
+	}
+	recipients := slices.Compact(slices.Sorted(slices.Values(req.Recipients)))
+
+	rule := entities.NewAlertRule(strings.TrimSpace(req.Name), req.Event, req.PerEvent, req.Condition, req.Threshold,
+		req.WindowMinutes, req.MinCount, recipients, req.WebhookURL, uc.clock.Now())
+	if err := uc.repo.Create(ctx, rule); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de la règle d'alerte", err)
+	}
+	return rule, nil
+}
+
+// =============================================================================
+// LIST ALERT RULES USE CASE
+// =============================================================================
+
+type ListAlertRulesUseCase struct {
+	repo repositories.AlertRuleRepository
+}
+
+func NewListAlertRulesUseCase(repo repositories.AlertRuleRepository) *ListAlertRulesUseCase {
+	return &ListAlertRulesUseCase{repo: repo}
+}
+
+// ListAlertRulesRequest règles, avec leur état (déclenchée ou non, dernière valeur mesurée)
+type ListAlertRulesRequest struct{}
+
+type ListAlertRulesResponse struct {
+	Rules []*entities.AlertRule `json:"rules"`
+}
+
+func (uc *ListAlertRulesUseCase) Execute(ctx context.Context, _ ListAlertRulesRequest) (*ListAlertRulesResponse, error) {
+	rules, err := uc.repo.List(ctx)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des règles d'alerte", err)
+	}
+	return &ListAlertRulesResponse{Rules: rules}, nil
+}
+
+// =============================================================================
+// DELETE ALERT RULE USE CASE
+// =============================================================================
+
+type DeleteAlertRuleUseCase struct {
+	repo repositories.AlertRuleRepository
+}
+
+func NewDeleteAlertRuleUseCase(repo repositories.AlertRuleRepository) *DeleteAlertRuleUseCase {
+	return &DeleteAlertRuleUseCase{repo: repo}
+}
+
+type AlertRuleRequest struct {
+	ID int `json:"-"`
+}
+
+func (req AlertRuleRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"alert_rule_id": req.ID}
+}
+
+func (uc *DeleteAlertRuleUseCase) Execute(ctx context.Context, req AlertRuleRequest) error {
+	err := uc.repo.Delete(ctx, req.ID)
+	if err != nil && !errors.Is(err, repositories.ErrAlertRuleNotFound) {
+		return newError("erreur lors de la suppression de la règle d'alerte", err)
+	}
+	return err
+}
+
+// =============================================================================
+// EVALUATE ALERTS USE CASE
+// =============================================================================
+
+// EvaluateAlertsUseCase job périodique : chaque règle dont la fenêtre est écoulée est mesurée sur
+// les agrégats, par différence avec les compteurs relevés à l'évaluation précédente (les agrégats
+// sont quotidiens ; la fenêtre peut chevaucher minuit). Une fenêtre plus longue que prévu (job en
+// retard, redémarrage) est ramenée à la durée de la règle, pour rester comparable à la précédente.
+// Seuls les changements d'état sont notifiés : déclenchement, puis retour à la normale
+type EvaluateAlertsUseCase struct {
+	repo       repositories.AlertRuleRepository
+	rollupRepo repositories.EventRollupRepository
+	userRepo   repositories.UserRepository
+	prefRepo   repositories.NotificationPreferenceRepository
+	notifiers  []Notifier
+	webhook    AlertWebhook
+	logger     Logger
+}
+
+func NewEvaluateAlertsUseCase(
+	repo repositories.AlertRuleRepository,
+	rollupRepo repositories.EventRollupRepository,
+	userRepo repositories.UserRepository,
+	prefRepo repositories.NotificationPreferenceRepository,
+	notifiers []Notifier,
+	webhook AlertWebhook,
+	logger Logger,
+) *EvaluateAlertsUseCase {
+	return &EvaluateAlertsUseCase{
+		repo:       repo,
+		rollupRepo: rollupRepo,
+		userRepo:   userRepo,
+		prefRepo:   prefRepo,
+		notifiers:  notifiers,
+		webhook:    webhook,
+		logger:     logger,
+	}
+}
+
+type EvaluateAlertsRequest struct {
+	Now time.Time
+}
+
+func (req EvaluateAlertsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"now": req.Now}
+}
+
+// EvaluateAlertsResponse Evaluated règles dont une fenêtre a été mesurée
+type EvaluateAlertsResponse struct {
+	Evaluated int `json:"evaluated"`
+	Fired     int `json:"fired"`
+	Resolved  int `json:"resolved"`
+}
+
+func (uc *EvaluateAlertsUseCase) Execute(ctx context.Context, req EvaluateAlertsRequest) (*EvaluateAlertsResponse, error) {
+	rules, err := uc.repo.List(ctx)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des règles d'alerte", err)
+	}
+
+	response := &EvaluateAlertsResponse{}
+	for _, rule := range rules {
+		notice, evaluated, err := uc.evaluate(ctx, rule, req.Now)
+		if err != nil {
+			return response, err
+		}
+		if evaluated {
+			response.Evaluated++
+		}
+		if notice == nil {
+			continue
+		}
+		if notice.Status == AlertFiring {
+			response.Fired++
+		} else {
+			response.Resolved++
+		}
+		uc.notify(ctx, rule, *notice)
+	}
+	return response, nil
+}
+
+// evaluate mesure la fenêtre écoulée et enregistre le nouvel état ; notice non nil si l'état change
+func (uc *EvaluateAlertsUseCase) evaluate(ctx context.Context, rule *entities.AlertRule, now time.Time) (*AlertNotice, bool, error) {
+	state := rule.State
+	today := rollupDay(now)
+	if !state.ObservedAt.IsZero() && now.Sub(state.ObservedAt) < rule.Window() {
+		return nil, false, nil
+	}
+
+	from := today
+	if !state.ObservedAt.IsZero() {
+		from = state.ObservedDay
+	}
+	rollups, err := uc.rollupRepo.Query(ctx, repositories.EventRollupFilters{
+		Names: slices.DeleteFunc([]string{rule.Event, rule.PerEvent}, func(name string) bool { return name == "" }),
+		From:  from,
+		To:    today.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, false, newError("erreur lors de la lecture des agrégats", err)
+	}
+	var total, perTotal, todayCount, todayPerCount int
+	for _, rollup := range rollups {
+		count, perCount := 0, 0
+		if rollup.Event == rule.Event {
+			count = rollup.Count
+		} else {
+			perCount = rollup.Count
+		}
+		total, perTotal = total+count, perTotal+perCount
+		if rollup.Day.Equal(today) {
+			todayCount, todayPerCount = todayCount+count, todayPerCount+perCount
+		}
+	}
+
+	var notice *AlertNotice
+	evaluated := false
+	if !state.ObservedAt.IsZero() {
+		// Agrégats recalculés entre-temps (backfill) : la différence peut être négative
+		count := max(total-state.ObservedCount, 0)
+		perCount := max(perTotal-state.ObservedPerCount, 0)
+		elapsed := now.Sub(state.ObservedAt)
+
+		value, measurable := float64(count)*float64(rule.Window())/float64(elapsed), true
+		if rule.PerEvent != "" {
+			value, measurable = float64(count)/float64(perCount), perCount > 0
+		}
+		if measurable {
+			evaluated = true
+			breached := rule.Breached(value)
+			if breached != state.Firing {
+				notice = &AlertNotice{
+					RuleID:      rule.ID,
+					Name:        rule.Name,
+					Status:      AlertResolved,
+					Event:       rule.Event,
+					PerEvent:    rule.PerEvent,
+					Condition:   rule.Condition,
+					Threshold:   rule.Threshold,
+					Value:       value,
+					Previous:    state.LastValue,
+					WindowStart: state.ObservedAt,
+					WindowEnd:   now,
+				}
+				if breached {
+					notice.Status = AlertFiring
+					firedAt := now
+					state.FiredAt = &firedAt
+				}
+				state.Firing = breached
+			}
+			state.Measured, state.LastValue, state.LastCount = true, value, count
+		}
+	}
+
+	state.ObservedAt, state.ObservedDay = now, today
+	state.ObservedCount, state.ObservedPerCount = todayCount, todayPerCount
+	rule.State = state
+	if err := uc.repo.Save(ctx, rule); err != nil {
+		if errors.Is(err, repositories.ErrAlertRuleNotFound) {
+			// Règle supprimée pendant l'évaluation
+			return nil, false, nil
+		}
+		return nil, false, newError("erreur lors de l'enregistrement de l'état de l'alerte", err)
+	}
+	return notice, evaluated, nil
+}
+
+// notify destinataires et webhook de la règle ; un échec est journalisé sans bloquer l'évaluation
+// des autres règles (le changement d'état n'est pas renotifié)
+func (uc *EvaluateAlertsUseCase) notify(ctx context.Context, rule *entities.AlertRule, notice AlertNotice) {
+	fields := map[string]interface{}{"alert_rule_id": rule.ID, "status": notice.Status, "value": notice.Value}
+	uc.logger.Warn("Metric alert "+notice.Status, fields)
+
+	title, body := describeAlert(rule, notice)
+	users, err := uc.userRepo.GetByIds(ctx, rule.Recipients)
+	if err != nil {
+		uc.logger.Error("Failed to load alert recipients", err, fields)
+	}
+	for _, user := range users {
+		preferences, err := loadPreferenceMatrix(ctx, uc.prefRepo, user.ID)
+		if err == nil {
+			err = deliverNotification(ctx, uc.notifiers, preferences, NotificationMessage{
+				UserID:    user.ID,
+				Email:     user.Email,
+				Phone:     user.Phone,
+				Name:      user.Name,
+				EventType: entities.MetricAlertEventType,
+				Title:     title,
+				Body:      body,
+			})
+		}
+		if err != nil {
+			uc.logger.Error("Failed to notify alert recipient", err, map[string]interface{}{"alert_rule_id": rule.ID, "user_id": user.ID})
+		}
+	}
+
+	if rule.WebhookURL != "" && uc.webhook != nil {
+		if err := uc.webhook.Send(ctx, rule.WebhookURL, notice); err != nil {
+			uc.logger.Error("Failed to send alert webhook", err, fields)
+		}
+	}
+}
+
+// describeAlert "Alerte : Inscriptions en baisse" / "user.created : 12 sur 60 min (précédente : 40)"
+func describeAlert(rule *entities.AlertRule, notice AlertNotice) (title, body string) {
+	title = "Alerte : " + rule.Name
+	if notice.Status == AlertResolved {
+		title = "Retour à la normale : " + rule.Name
+	}
+
+	metric := rule.Event
+	if rule.PerEvent != "" {
+		metric += " / " + rule.PerEvent
+	}
+	body = fmt.Sprintf("%s : %.4g sur %d min", metric, notice.Value, rule.WindowMinutes)
+	switch rule.Condition {
+	case entities.AlertDrop, entities.AlertSpike:
+		body += fmt.Sprintf(" (fenêtre précédente : %.4g ; seuil : %s de %.4g %%)", notice.Previous, rule.Condition, rule.Threshold)
+	default:
+		body += fmt.Sprintf(" (seuil : %s %.4g)", rule.Condition, rule.Threshold)
+	}
+	return title, body + ", jusqu'au " + notice.WindowEnd.UTC().Format("02/01/2006 à 15:04 UTC") + "."
+}
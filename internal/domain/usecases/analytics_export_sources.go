@@ -0,0 +1,326 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// EXPORTS ANALYTICS : événements bruts et agrégats quotidiens (ExportJobs)
+// =============================================================================
+
+const (
+	// maxEventExportDays période maximale d'un export d'événements
+	maxEventExportDays = 366
+	// maxExportProperties colonnes de propriétés déduites au plus ; au-delà, filters.properties
+	// doit choisir les colonnes
+	maxExportProperties = 200
+)
+
+// eventExportColumns colonnes fixes des exports d'événements, suivies d'une colonne par propriété
+var eventExportColumns = []ExportColumn{
+	{"id", ExportInt64}, {"name", ExportString}, {"user_id", ExportInt64},
+	{"occurred_at", ExportTimestamp}, {"received_at", ExportTimestamp}, {"sample_rate", ExportDouble},
+}
+
+// eventPropertyPrefix préfixe des colonnes de propriétés : aucune collision avec les colonnes fixes
+const eventPropertyPrefix = "prop_"
+
+// EventExportSource événements analytics bruts (kind "events"). Critères :
+//
+//	from, to      bornes RFC 3339 sur l'horodatage client (to exclu) ; défaut : les 30 derniers jours
+//	events        noms séparés par des virgules ; vide = tous
+//	user_id       un seul utilisateur
+//	where         expression de filtre (ParseEventFilter)
+//	properties    colonnes de propriétés "plan,amount:double,trial:bool" (string par défaut ; types
+//	              string, int64, double, bool, timestamp) ; vide = déduites des événements exportés,
+//	              double, bool ou string selon les valeurs rencontrées
+//
+// Une valeur qui ne se convertit pas au type de sa colonne est exportée absente
+type EventExportSource struct {
+	eventRepo repositories.EventRepository
+	clock     Clock
+}
+
+func NewEventExportSource(eventRepo repositories.EventRepository, clock Clock) *EventExportSource {
+	return &EventExportSource{eventRepo: eventRepo, clock: clock}
+}
+
+func (s *EventExportSource) Action() string {
+	return "search_events"
+}
+
+// eventExportQuery critères analysés d'un export d'événements
+type eventExportQuery struct {
+	filters repositories.EventFilters
+	// properties nil : colonnes déduites
+	properties []ExportColumn
+}
+
+func (s *EventExportSource) parse(filters map[string]string) (*eventExportQuery, error) {
+	query := &eventExportQuery{}
+	query.filters.To = s.clock.Now()
+	if raw := filters["to"]; raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, errors.New("to doit être une date RFC 3339")
+		}
+		query.filters.To = to
+	}
+	query.filters.From = query.filters.To.AddDate(0, 0, -30)
+	if raw := filters["from"]; raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, errors.New("from doit être une date RFC 3339")
+		}
+		query.filters.From = from
+	}
+	if !query.filters.From.Before(query.filters.To) {
+		return nil, errors.New("la date de fin doit suivre la date de début")
+	}
+	if query.filters.To.Sub(query.filters.From) > maxEventExportDays*24*time.Hour {
+		return nil, fmt.Errorf("%d jours au plus par export", maxEventExportDays)
+	}
+	for _, name := range strings.Split(filters["events"], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			query.filters.Names = append(query.filters.Names, name)
+		}
+	}
+	if raw := filters["user_id"]; raw != "" {
+		userID, err := strconv.Atoi(raw)
+		if err != nil || userID <= 0 {
+			return nil, errors.New("user_id doit être un identifiant utilisateur")
+		}
+		query.filters.UserID = userID
+	}
+	where, err := ParseEventFilter(filters["where"])
+	if err != nil {
+		return nil, err
+	}
+	query.filters.Where = where
+	if raw := strings.TrimSpace(filters["properties"]); raw != "" {
+		properties, err := parsePropertyColumns(raw)
+		if err != nil {
+			return nil, err
+		}
+		query.properties = properties
+	}
+	return query, nil
+}
+
+// parsePropertyColumns "plan,amount:double" ; Name : la clé de la propriété, sans préfixe
+func parsePropertyColumns(raw string) ([]ExportColumn, error) {
+	var columns []ExportColumn
+	for _, spec := range strings.Split(raw, ",") {
+		key, kind, _ := strings.Cut(strings.TrimSpace(spec), ":")
+		if kind == "" {
+			kind = ExportString
+		}
+		if key == "" {
+			return nil, errors.New("properties : nom de propriété vide")
+		}
+		if !slices.Contains([]string{ExportString, ExportInt64, ExportDouble, ExportBool, ExportTimestamp}, kind) {
+			return nil, fmt.Errorf("properties : type %q invalide pour %s (string, int64, double, bool ou timestamp)", kind, key)
+		}
+		if slices.ContainsFunc(columns, func(column ExportColumn) bool { return column.Name == key }) {
+			return nil, fmt.Errorf("properties : %s en double", key)
+		}
+		columns = append(columns, ExportColumn{Name: key, Type: kind})
+	}
+	if len(columns) > maxExportProperties {
+		return nil, fmt.Errorf("properties : %d colonnes au plus", maxExportProperties)
+	}
+	return columns, nil
+}
+
+func (s *EventExportSource) Validate(filters map[string]string) error {
+	_, err := s.parse(filters)
+	return err
+}
+
+func (s *EventExportSource) Columns(ctx context.Context, filters map[string]string) ([]ExportColumn, error) {
+	query, err := s.parse(filters)
+	if err != nil {
+		return nil, err
+	}
+	properties := query.properties
+	if properties == nil {
+		if properties, err = s.inferProperties(ctx, query.filters); err != nil {
+			return nil, err
+		}
+	}
+	columns := slices.Clone(eventExportColumns)
+	for _, property := range properties {
+		columns = append(columns, ExportColumn{Name: eventPropertyPrefix + property.Name, Type: property.Type})
+	}
+	return columns, nil
+}
+
+// inferProperties premier passage sur les événements exportés : une colonne par clé, double ou
+// bool si toutes ses valeurs le sont, string sinon ; clés triées
+func (s *EventExportSource) inferProperties(ctx context.Context, filters repositories.EventFilters) ([]ExportColumn, error) {
+	kinds := map[string]string{}
+	_, err := scanEvents(ctx, s.eventRepo, filters, func(event *entities.TrackedEvent) error {
+		for key, value := range event.Properties {
+			kind := ExportString
+			switch value.(type) {
+			case float64:
+				kind = ExportDouble
+			case bool:
+				kind = ExportBool
+			}
+			if previous, seen := kinds[key]; seen && previous != kind {
+				kind = ExportString
+			}
+			kinds[key] = kind
+		}
+		if len(kinds) > maxExportProperties {
+			return fmt.Errorf("plus de %d propriétés distinctes : choisir les colonnes avec filters.properties", maxExportProperties)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]ExportColumn, 0, len(kinds))
+	for key, kind := range kinds {
+		columns = append(columns, ExportColumn{Name: key, Type: kind})
+	}
+	slices.SortFunc(columns, func(a, b ExportColumn) int { return strings.Compare(a.Name, b.Name) })
+	return columns, nil
+}
+
+func (s *EventExportSource) Export(ctx context.Context, filters map[string]string, emit func(row []any) error) (int, error) {
+	columns, err := s.Columns(ctx, filters)
+	if err != nil {
+		return 0, err
+	}
+	query, err := s.parse(filters)
+	if err != nil {
+		return 0, err
+	}
+	properties := columns[len(eventExportColumns):]
+	row := make([]any, len(columns))
+	return scanEvents(ctx, s.eventRepo, query.filters, func(event *entities.TrackedEvent) error {
+		row[0], row[1], row[2] = event.ID, event.Name, optionalExportID(event.UserID)
+		row[3], row[4], row[5] = event.OccurredAt, event.ReceivedAt, event.SampleRate
+		for i, column := range properties {
+			row[len(eventExportColumns)+i] = exportValue(event.Properties[strings.TrimPrefix(column.Name, eventPropertyPrefix)], column.Type)
+		}
+		return emit(row)
+	})
+}
+
+// exportValue valeur d'une propriété convertie au type de sa colonne ; nil si elle est absente ou
+// ne s'y convertit pas
+func exportValue(value any, kind string) any {
+	switch kind {
+	case ExportString:
+		switch typed := value.(type) {
+		case string:
+			return typed
+		case float64:
+			return strconv.FormatFloat(typed, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(typed)
+		}
+	case ExportDouble:
+		switch typed := value.(type) {
+		case float64:
+			return typed
+		case string:
+			if parsed, err := strconv.ParseFloat(typed, 64); err == nil {
+				return parsed
+			}
+		}
+	case ExportInt64:
+		switch typed := value.(type) {
+		case float64:
+			if typed == math.Trunc(typed) && math.Abs(typed) < 1<<63 {
+				return int64(typed)
+			}
+		case string:
+			if parsed, err := strconv.ParseInt(typed, 10, 64); err == nil {
+				return parsed
+			}
+		}
+	case ExportBool:
+		switch typed := value.(type) {
+		case bool:
+			return typed
+		case string:
+			if parsed, err := strconv.ParseBool(typed); err == nil {
+				return parsed
+			}
+		}
+	case ExportTimestamp:
+		if typed, ok := value.(string); ok {
+			if parsed, err := time.Parse(time.RFC3339Nano, typed); err == nil {
+				return parsed
+			}
+		}
+	}
+	return nil
+}
+
+// RollupExportSource agrégats quotidiens (kind "rollups"), une ligne par jour et par événement.
+// Critères de GET /analytics/rollups : events, from, to (jours UTC entiers ; défaut : 30 jours)
+type RollupExportSource struct {
+	rollupRepo repositories.EventRollupRepository
+	clock      Clock
+}
+
+func NewRollupExportSource(rollupRepo repositories.EventRollupRepository, clock Clock) *RollupExportSource {
+	return &RollupExportSource{rollupRepo: rollupRepo, clock: clock}
+}
+
+// RollupExportColumns colonnes des exports d'agrégats
+var RollupExportColumns = []ExportColumn{{"day", ExportDate}, {"event", ExportString}, {"count", ExportInt64}}
+
+func (s *RollupExportSource) Action() string {
+	return "get_event_rollups"
+}
+
+func rollupExportRequest(filters map[string]string) GetEventRollupsRequest {
+	return GetEventRollupsRequest{Events: filters["events"], From: filters["from"], To: filters["to"]}
+}
+
+func (s *RollupExportSource) Validate(filters map[string]string) error {
+	return rollupExportRequest(filters).Validate()
+}
+
+func (s *RollupExportSource) Columns(ctx context.Context, filters map[string]string) ([]ExportColumn, error) {
+	return RollupExportColumns, nil
+}
+
+func (s *RollupExportSource) Export(ctx context.Context, filters map[string]string, emit func(row []any) error) (int, error) {
+	req := rollupExportRequest(filters)
+	from, to, err := req.period(s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	query := repositories.EventRollupFilters{From: from, To: to}
+	for _, name := range strings.Split(req.Events, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			query.Names = append(query.Names, name)
+		}
+	}
+	rollups, err := s.rollupRepo.Query(ctx, query)
+	if err != nil {
+		return 0, newError("erreur lors de la lecture des agrégats", err)
+	}
+	for i, rollup := range rollups {
+		if err := emit([]any{rollup.Day, rollup.Event, int64(rollup.Count)}); err != nil {
+			return i, err
+		}
+	}
+	return len(rollups), nil
+}
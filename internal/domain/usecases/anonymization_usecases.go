@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// ANONYMISATION : COPIES PSEUDONYMISÉES POUR LES ENVIRONNEMENTS HORS PRODUCTION
+// =============================================================================
+
+// Pseudonymizer remplace une donnée personnelle par une valeur fictive, irréversible et stable :
+// une même valeur d'origine donne toujours le même pseudonyme (jointures et doublons préservés)
+type Pseudonymizer interface {
+	Email(email string) string
+	Name(name string) string
+	Phone(phone string) string
+	Handle(handle string) string
+	// Text texte libre (motif de bannissement...) : son contenu n'est pas conservé
+	Text(text string) string
+}
+
+// AnonymizedDataWriter destination de la copie (fichier d'import de la base de staging...)
+type AnonymizedDataWriter interface {
+	WriteUser(ctx context.Context, user *entities.User) error
+	WriteActivity(ctx context.Context, entry repositories.ActivityEntry) error
+	WriteEvent(ctx context.Context, event repositories.StoredEvent) error
+}
+
+type AnonymizeDataRequest struct {
+	Writer AnonymizedDataWriter
+	// Password mot de passe commun des comptes copiés (connexion en staging) ;
+	// vide = mot de passe aléatoire, aucun compte copié n'est utilisable par mot de passe
+	Password string
+}
+
+func (req AnonymizeDataRequest) Validate() error {
+	if req.Writer == nil {
+		return errors.New("destination de la copie manquante")
+	}
+	return nil
+}
+
+func (req AnonymizeDataRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"shared_password": req.Password != ""}
+}
+
+// AnonymizeDataResponse volumes copiés ; SkippedEvents événements d'un type inconnu de
+// l'anonymiseur, jamais copiés (ils pourraient porter des données personnelles)
+type AnonymizeDataResponse struct {
+	Users         int
+	Activities    int
+	Events        int
+	SkippedEvents int
+}
+
+// AnonymizeDataUseCase copie les utilisateurs, leur journal d'activité et, en persistance
+// event-sourcée, leur flux d'événements, en pseudonymisant chaque donnée personnelle :
+//   - email, nom, téléphone, handle et textes libres : pseudonymes du Pseudonymizer
+//   - mot de passe : un même hash pour tous les comptes, jamais celui d'origine
+//   - attributs personnalisés : non copiés (leur contenu dépend du tenant)
+//
+// Les identifiants et les dates sont conservés : la copie reste cohérente entre tables
+type AnonymizeDataUseCase struct {
+	userRepo       repositories.UserRepository
+	activityRepo   repositories.ActivityRepository
+	eventStore     repositories.UserEventStore // nil hors persistance event-sourcée
+	pseudonymizer  Pseudonymizer
+	hasher         PasswordHasher
+	tokenGenerator TokenGenerator
+}
+
+func NewAnonymizeDataUseCase(
+	userRepo repositories.UserRepository,
+	activityRepo repositories.ActivityRepository,
+	eventStore repositories.UserEventStore,
+	pseudonymizer Pseudonymizer,
+	hasher PasswordHasher,
+	tokenGenerator TokenGenerator,
+) *AnonymizeDataUseCase {
+	return &AnonymizeDataUseCase{
+		userRepo:       userRepo,
+		activityRepo:   activityRepo,
+		eventStore:     eventStore,
+		pseudonymizer:  pseudonymizer,
+		hasher:         hasher,
+		tokenGenerator: tokenGenerator,
+	}
+}
+
+func (uc *AnonymizeDataUseCase) Execute(ctx context.Context, req AnonymizeDataRequest) (*AnonymizeDataResponse, error) {
+	password := req.Password
+	if password == "" {
+		var err error
+		if password, err = randomProvisioningPassword(uc.tokenGenerator); err != nil {
+			return nil, newError("erreur lors de la génération du mot de passe", err)
+		}
+	}
+	// Un seul hachage (PBKDF2 est volontairement lent) partagé par tous les comptes
+	passwordHash, err := uc.hasher.Hash(password)
+	if err != nil {
+		return nil, newError("erreur lors du hachage du mot de passe", err)
+	}
+
+	response := &AnonymizeDataResponse{}
+	err = uc.userRepo.Each(ctx, repositories.UserRepositoryFilters{}, func(user *entities.User) error {
+		if err := req.Writer.WriteUser(ctx, uc.anonymizeUser(user, passwordHash)); err != nil {
+			return newError("erreur lors de l'écriture de la copie", err)
+		}
+		response.Users++
+
+		if err := uc.copyActivity(ctx, req.Writer, user.ID, response); err != nil {
+			return err
+		}
+		return uc.copyEvents(ctx, req.Writer, user.ID, passwordHash, response)
+	})
+	if err != nil {
+		return nil, eachError("erreur lors de la lecture des utilisateurs", err)
+	}
+	return response, nil
+}
+
+func (uc *AnonymizeDataUseCase) anonymizeUser(user *entities.User, passwordHash string) *entities.User {
+	copied := *user
+	copied.Email = uc.pseudonymizer.Email(user.Email)
+	copied.Name = uc.pseudonymizer.Name(user.Name)
+	copied.Password = passwordHash
+	if user.Phone != "" {
+		copied.Phone = uc.pseudonymizer.Phone(user.Phone)
+	}
+	if user.Handle != "" {
+		copied.Handle = uc.pseudonymizer.Handle(user.Handle)
+	}
+	copied.Attributes = nil
+	return &copied
+}
+
+// copyActivity le journal ne contient que des noms d'événements et de champs : copié tel quel
+func (uc *AnonymizeDataUseCase) copyActivity(ctx context.Context, writer AnonymizedDataWriter, userID int, response *AnonymizeDataResponse) error {
+	entries, err := uc.activityRepo.ListByUser(ctx, userID, time.Time{})
+	if err != nil {
+		return newError("erreur lors de la lecture de l'activité", err)
+	}
+	for _, entry := range entries {
+		if err := writer.WriteActivity(ctx, entry); err != nil {
+			return newError("erreur lors de l'écriture de la copie", err)
+		}
+		response.Activities++
+	}
+	return nil
+}
+
+func (uc *AnonymizeDataUseCase) copyEvents(ctx context.Context, writer AnonymizedDataWriter, userID int, passwordHash string, response *AnonymizeDataResponse) error {
+	if uc.eventStore == nil {
+		return nil
+	}
+
+	stream, err := uc.eventStore.Load(ctx, userID, 0)
+	if err != nil {
+		return newError("erreur lors de la lecture du flux d'événements", err)
+	}
+	for _, stored := range stream {
+		event, ok := uc.anonymizeEvent(stored.Event, passwordHash)
+		if !ok {
+			response.SkippedEvents++
+			continue
+		}
+		stored.Event = event
+		if err := writer.WriteEvent(ctx, stored); err != nil {
+			return newError("erreur lors de l'écriture de la copie", err)
+		}
+		response.Events++
+	}
+	return nil
+}
+
+// anonymizeEvent liste blanche : un nouveau type d'événement n'est copié qu'une fois ajouté ici
+func (uc *AnonymizeDataUseCase) anonymizeEvent(event events.Event, passwordHash string) (events.Event, bool) {
+	p := uc.pseudonymizer
+	switch e := event.(type) {
+	case events.UserRegistered:
+		e.Email, e.Name, e.PasswordHash = p.Email(e.Email), p.Name(e.Name), passwordHash
+		return e, true
+	case events.UserCreated:
+		e.Email, e.Name = p.Email(e.Email), p.Name(e.Name)
+		return e, true
+	case events.UserProfileUpdated:
+		e.Email, e.Name = p.Email(e.Email), p.Name(e.Name)
+		return e, true
+	case events.PasswordChanged:
+		e.PasswordHash = passwordHash
+		return e, true
+	case events.UserPhoneChanged:
+		if e.Phone != "" {
+			e.Phone = p.Phone(e.Phone)
+		}
+		return e, true
+	case events.UserHandleChanged:
+		if e.Handle != "" {
+			e.Handle = p.Handle(e.Handle)
+		}
+		return e, true
+	case events.UserStatusChanged:
+		if e.Reason != "" {
+			e.Reason = p.Text(e.Reason)
+		}
+		return e, true
+	case events.UserAttributesChanged:
+		e.Attributes = nil
+		return e, true
+	case events.UserEmailUndeliverable:
+		e.Email = p.Email(e.Email)
+		return e, true
+	case events.DigestPreferenceChanged, events.UserLocaleChanged, events.UserLoggedIn,
+		events.UserFlaggedForCleanup, events.TermsAccepted, events.UserDeleted:
+		return e, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,495 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// =============================================================================
+// JOURNAL D'AUDIT : alimenté par le pipeline, consulté par les administrateurs
+// =============================================================================
+
+// AuditedUseCases actions tracées dans le journal d'audit : écritures sur les comptes,
+// connexions, impersonation, administration de l'instance, consultations du support et accès
+// au journal lui-même
+var AuditedUseCases = []string{
+	"create_user", "update_user", "patch_user", "delete_user", "deactivate_user", "reactivate_user",
+	"set_user_handle", "bulk_create_users", "bulk_update_users", "bulk_delete_users", "import_users", "sync_users",
+	"create_scim_user", "replace_scim_user", "patch_scim_user", "delete_scim_user",
+	"login", "verify_login", "start_onboarding", "confirm_onboarding", "impersonate_user", "configure_identity_provider", "subscribe_tenant", "anonymize_data",
+	"set_availability", "set_log_level", "export_audit_entries", "create_audit_export_link", "rebuild_projections",
+	"replay_dead_letters", "discard_dead_letters", "save_email_template", "start_export", "get_user_timeline",
+	"record_analytics_consent", "list_consent_changes", "restore_events",
+	"create_write_key", "rotate_write_key", "revoke_write_key",
+	"create_alert_rule", "delete_alert_rule",
+}
+
+// Résultats d'une action tracée
+const (
+	AuditSucceeded = "succeeded"
+	AuditFailed    = "failed"
+	// AuditDenied refusée par les politiques (authentification ou droits insuffisants)
+	AuditDenied = "denied"
+	// AuditChallenged suspendue à une vérification supplémentaire (connexion à risque)
+	AuditChallenged = "challenged"
+)
+
+// AuditSubject sortie désignant l'utilisateur visé quand l'entrée ne le porte pas (création, connexion)
+type AuditSubject interface {
+	AuditUserID() int
+}
+
+// AuditTrail enregistre les actions tracées ; l'utilisateur visé est lu dans l'entrée
+// (PolicyResource, identifiant entier) ou à défaut dans la sortie (AuditSubject)
+type AuditTrail struct {
+	auditRepo repositories.AuditRepository
+	clock     Clock
+	logger    Logger
+	actions   map[string]bool
+}
+
+func NewAuditTrail(auditRepo repositories.AuditRepository, clock Clock, logger Logger, actions []string) *AuditTrail {
+	audited := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		audited[action] = true
+	}
+	return &AuditTrail{auditRepo: auditRepo, clock: clock, logger: logger, actions: audited}
+}
+
+// record une entrée perdue est journalisée mais ne fait pas échouer l'action
+func (t *AuditTrail) record(ctx context.Context, name string, input, output any, err error) {
+	outcome := AuditSucceeded
+	switch {
+	case errors.Is(err, ErrForbidden) || errors.Is(err, ErrAuthenticationRequired):
+		outcome = AuditDenied
+	case err != nil:
+		outcome = AuditFailed
+	}
+	t.Record(ctx, name, auditTarget(input, output, err), outcome, "")
+}
+
+// Record trace une décision prise au sein d'un use case (évaluation du risque d'une connexion),
+// hors de la liste des actions auditées
+func (t *AuditTrail) Record(ctx context.Context, action string, targetUserID int, outcome, details string) {
+	actor, _ := ActorFromContext(ctx)
+	entry := repositories.AuditEntry{
+		At:           t.clock.Now(),
+		ActorID:      actor.UserID,
+		System:       actor.System,
+		TenantID:     actor.TenantID,
+		TargetUserID: targetUserID,
+		Action:       action,
+		Outcome:      outcome,
+		RequestID:    RequestIDFromContext(ctx),
+		Details:      details,
+	}
+	if actor.Impersonation != nil {
+		entry.ImpersonatorID = actor.Impersonation.AdminID
+	}
+
+	// L'action a eu lieu : l'entrée est écrite même si le client a abandonné la requête
+	if recordErr := t.auditRepo.Append(context.WithoutCancel(ctx), entry); recordErr != nil {
+		t.logger.Error("Audit entry lost", recordErr, map[string]interface{}{
+			"use_case": action, "actor_id": entry.ActorID, "outcome": entry.Outcome,
+		})
+	}
+}
+
+func auditTarget(input, output any, err error) int {
+	if userID, ok := policyResource(input)["user_id"].(int); ok && userID > 0 {
+		return userID
+	}
+	if subject, ok := output.(AuditSubject); ok && err == nil {
+		return subject.AuditUserID()
+	}
+	return 0
+}
+
+// WithAudit trace les exécutions des use cases audités, refus des politiques compris (nil = aucun audit)
+func WithAudit[I, O any](name string, trail *AuditTrail) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if trail == nil || !trail.actions[name] {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			output, err := next.Execute(ctx, input)
+			trail.record(ctx, name, input, output, err)
+			return output, err
+		})
+	}
+}
+
+func (r *CreateUserResponse) AuditUserID() int {
+	if r == nil {
+		return 0
+	}
+	return r.ID
+}
+
+func (r *LoginResponse) AuditUserID() int {
+	if r == nil {
+		return 0
+	}
+	return r.UserID
+}
+
+// =============================================================================
+// CONSULTATION
+// =============================================================================
+
+// AuditQuery critères communs à la liste et à l'export ; les champs vides ne filtrent pas
+type AuditQuery struct {
+	// ActorID acteur, ou administrateur d'une session d'impersonation
+	ActorID      int    `json:"actor_id,omitempty"`
+	TargetUserID int    `json:"target_user_id,omitempty"`
+	Action       string `json:"action,omitempty"`
+	// From / To bornes RFC 3339 (début inclus, fin exclue)
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+func (q AuditQuery) Validate() error {
+	if q.ActorID < 0 || q.TargetUserID < 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if q.From != "" {
+		if _, err := time.Parse(time.RFC3339, q.From); err != nil {
+			return errors.New("from doit être une date RFC 3339")
+		}
+	}
+	if q.To != "" {
+		if _, err := time.Parse(time.RFC3339, q.To); err != nil {
+			return errors.New("to doit être une date RFC 3339")
+		}
+	}
+	return nil
+}
+
+func (q AuditQuery) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"actor_id":       q.ActorID,
+		"target_user_id": q.TargetUserID,
+		"action":         q.Action,
+		"from":           q.From,
+		"to":             q.To,
+	}
+}
+
+// Params critères en paramètres de requête (liens de téléchargement signés), sans les vides
+func (q AuditQuery) Params() url.Values {
+	params := url.Values{}
+	if q.ActorID > 0 {
+		params.Set("actor_id", strconv.Itoa(q.ActorID))
+	}
+	if q.TargetUserID > 0 {
+		params.Set("target_user_id", strconv.Itoa(q.TargetUserID))
+	}
+	for name, value := range map[string]string{"action": q.Action, "from": q.From, "to": q.To} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	return params
+}
+
+// ParseAuditQuery critères d'un export asynchrone (mêmes noms que Params)
+func ParseAuditQuery(filters map[string]string) (AuditQuery, error) {
+	query := AuditQuery{Action: filters["action"], From: filters["from"], To: filters["to"]}
+	for name, target := range map[string]*int{"actor_id": &query.ActorID, "target_user_id": &query.TargetUserID} {
+		if raw := filters[name]; raw != "" {
+			id, err := strconv.Atoi(raw)
+			if err != nil {
+				return query, fmt.Errorf("%s doit être un entier", name)
+			}
+			*target = id
+		}
+	}
+	for name := range filters {
+		if !slices.Contains([]string{"actor_id", "target_user_id", "action", "from", "to"}, name) {
+			return query, fmt.Errorf("critère inconnu : %q", name)
+		}
+	}
+	return query, query.Validate()
+}
+
+// filter From et To ont été validés
+func (q AuditQuery) filter() repositories.AuditFilter {
+	filter := repositories.AuditFilter{ActorID: q.ActorID, TargetUserID: q.TargetUserID, Action: q.Action}
+	if q.From != "" {
+		from, _ := time.Parse(time.RFC3339, q.From)
+		filter.From = &from
+	}
+	if q.To != "" {
+		to, _ := time.Parse(time.RFC3339, q.To)
+		filter.To = &to
+	}
+	return filter
+}
+
+type AuditEntryResponse struct {
+	ID             int64     `json:"id"`
+	At             time.Time `json:"at"`
+	ActorID        int       `json:"actor_id,omitempty"`
+	System         bool      `json:"system,omitempty"`
+	ImpersonatorID int       `json:"impersonator_id,omitempty"`
+	TenantID       string    `json:"tenant_id,omitempty"`
+	TargetUserID   int       `json:"target_user_id,omitempty"`
+	Action         string    `json:"action"`
+	Outcome        string    `json:"outcome"`
+	RequestID      string    `json:"request_id,omitempty"`
+	Details        string    `json:"details,omitempty"`
+}
+
+// AuditCSVHeader colonnes des exports CSV, dans l'ordre de AuditEntryResponse.CSVRecord
+var AuditCSVHeader = []string{
+	"id", "at", "actor_id", "system", "impersonator_id", "tenant_id", "target_user_id", "action", "outcome", "request_id", "details",
+}
+
+func (e AuditEntryResponse) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(e.ID, 10),
+		e.At.UTC().Format(time.RFC3339Nano),
+		optionalID(e.ActorID),
+		strconv.FormatBool(e.System),
+		optionalID(e.ImpersonatorID),
+		e.TenantID,
+		optionalID(e.TargetUserID),
+		e.Action,
+		e.Outcome,
+		e.RequestID,
+		e.Details,
+	}
+}
+
+// AuditExportColumns colonnes des exports asynchrones, dans l'ordre de AuditEntryResponse.ExportRow
+// (mêmes noms que AuditCSVHeader)
+var AuditExportColumns = []ExportColumn{
+	{"id", ExportInt64}, {"at", ExportTimestamp}, {"actor_id", ExportInt64}, {"system", ExportBool},
+	{"impersonator_id", ExportInt64}, {"tenant_id", ExportString}, {"target_user_id", ExportInt64},
+	{"action", ExportString}, {"outcome", ExportString}, {"request_id", ExportString}, {"details", ExportString},
+}
+
+// ExportRow valeurs typées de l'entrée ; identifiants et textes absents à nil
+func (e AuditEntryResponse) ExportRow() []any {
+	return []any{
+		e.ID, e.At, optionalExportID(e.ActorID), e.System, optionalExportID(e.ImpersonatorID),
+		optionalExportString(e.TenantID), optionalExportID(e.TargetUserID), e.Action, e.Outcome,
+		optionalExportString(e.RequestID), optionalExportString(e.Details),
+	}
+}
+
+func optionalExportID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return int64(id)
+}
+
+func optionalExportString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// optionalID cellule vide pour un identifiant absent (0)
+func optionalID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+func toAuditEntryResponse(entry repositories.AuditEntry) AuditEntryResponse {
+	return AuditEntryResponse{
+		ID:             entry.ID,
+		At:             entry.At,
+		ActorID:        entry.ActorID,
+		System:         entry.System,
+		ImpersonatorID: entry.ImpersonatorID,
+		TenantID:       entry.TenantID,
+		TargetUserID:   entry.TargetUserID,
+		Action:         entry.Action,
+		Outcome:        entry.Outcome,
+		RequestID:      entry.RequestID,
+		Details:        entry.Details,
+	}
+}
+
+// ListAuditEntriesUseCase entrées les plus récentes d'abord, paginées par curseur : une page reste
+// stable quand de nouvelles entrées arrivent pendant le parcours
+type ListAuditEntriesUseCase struct {
+	auditRepo repositories.AuditRepository
+}
+
+func NewListAuditEntriesUseCase(auditRepo repositories.AuditRepository) *ListAuditEntriesUseCase {
+	return &ListAuditEntriesUseCase{auditRepo: auditRepo}
+}
+
+type ListAuditEntriesRequest struct {
+	AuditQuery
+	// Cursor next_cursor de la page précédente ; vide pour la première page
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit"` // défaut : 50
+}
+
+func (req ListAuditEntriesRequest) Validate() error {
+	if err := req.AuditQuery.Validate(); err != nil {
+		return err
+	}
+	if req.Limit < 0 || req.Limit > 500 {
+		return errors.New("limit doit être compris entre 1 et 500")
+	}
+	if _, err := decodeAuditCursor(req.Cursor); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (req ListAuditEntriesRequest) LogFields() map[string]interface{} {
+	fields := req.AuditQuery.LogFields()
+	fields["limit"] = req.Limit
+	return fields
+}
+
+type ListAuditEntriesResponse struct {
+	Entries []AuditEntryResponse `json:"entries"`
+	// NextCursor absent sur la dernière page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (uc *ListAuditEntriesUseCase) Execute(ctx context.Context, req ListAuditEntriesRequest) (*ListAuditEntriesResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+	filter := req.filter()
+	filter.BeforeID, _ = decodeAuditCursor(req.Cursor)
+	// Une entrée de plus : indique s'il reste une page
+	filter.Limit = req.Limit + 1
+
+	entries, err := uc.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du journal d'audit", err)
+	}
+	response := &ListAuditEntriesResponse{Entries: make([]AuditEntryResponse, 0, min(len(entries), req.Limit))}
+	if len(entries) > req.Limit {
+		entries = entries[:req.Limit]
+		response.NextCursor = encodeAuditCursor(entries[len(entries)-1].ID)
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, toAuditEntryResponse(entry))
+	}
+	return response, nil
+}
+
+// errAuditCursor curseur altéré ou issu d'une autre liste
+var errAuditCursor = errors.New("curseur invalide")
+
+func encodeAuditCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeAuditCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errAuditCursor
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 1 {
+		return 0, errAuditCursor
+	}
+	return id, nil
+}
+
+// =============================================================================
+// EXPORT
+// =============================================================================
+
+// auditExportBatch entrées lues par appel au dépôt pendant un export
+const auditExportBatch = 500
+
+// ExportAuditEntriesUseCase parcourt toutes les entrées correspondant aux critères, les plus récentes
+// d'abord, et les passe une à une à Emit (ligne CSV écrite au fil de l'eau par le handler)
+type ExportAuditEntriesUseCase struct {
+	auditRepo repositories.AuditRepository
+}
+
+func NewExportAuditEntriesUseCase(auditRepo repositories.AuditRepository) *ExportAuditEntriesUseCase {
+	return &ExportAuditEntriesUseCase{auditRepo: auditRepo}
+}
+
+type ExportAuditEntriesRequest struct {
+	AuditQuery
+	// Emit reçoit chaque entrée ; une erreur (client parti) interrompt l'export
+	Emit func(entry AuditEntryResponse) error `json:"-"`
+}
+
+type ExportAuditEntriesResponse struct {
+	Exported int `json:"exported"`
+}
+
+func (uc *ExportAuditEntriesUseCase) Execute(ctx context.Context, req ExportAuditEntriesRequest) (*ExportAuditEntriesResponse, error) {
+	filter := req.filter()
+	filter.Limit = auditExportBatch
+	response := &ExportAuditEntriesResponse{}
+	for {
+		entries, err := uc.auditRepo.List(ctx, filter)
+		if err != nil {
+			return response, newError("erreur lors de la lecture du journal d'audit", err)
+		}
+		for _, entry := range entries {
+			if err := req.Emit(toAuditEntryResponse(entry)); err != nil {
+				return response, newError("export du journal d'audit interrompu", err)
+			}
+			response.Exported++
+		}
+		if len(entries) < auditExportBatch {
+			return response, nil
+		}
+		filter.BeforeID = entries[len(entries)-1].ID
+	}
+}
+
+// AuditExportSource journal d'audit en export asynchrone (ExportJobs, kind "audit")
+type AuditExportSource struct {
+	export *ExportAuditEntriesUseCase
+}
+
+func NewAuditExportSource(auditRepo repositories.AuditRepository) *AuditExportSource {
+	return &AuditExportSource{export: NewExportAuditEntriesUseCase(auditRepo)}
+}
+
+func (s *AuditExportSource) Action() string {
+	return "export_audit_entries"
+}
+
+func (s *AuditExportSource) Validate(filters map[string]string) error {
+	_, err := ParseAuditQuery(filters)
+	return err
+}
+
+func (s *AuditExportSource) Columns(ctx context.Context, filters map[string]string) ([]ExportColumn, error) {
+	return AuditExportColumns, nil
+}
+
+func (s *AuditExportSource) Export(ctx context.Context, filters map[string]string, emit func(row []any) error) (int, error) {
+	query, err := ParseAuditQuery(filters)
+	if err != nil {
+		return 0, err
+	}
+	response, err := s.export.Execute(ctx, ExportAuditEntriesRequest{
+		AuditQuery: query,
+		Emit:       func(entry AuditEntryResponse) error { return emit(entry.ExportRow()) },
+	})
+	return response.Exported, err
+}
@@ -0,0 +1,247 @@
+// internal/domain/usecases/auth_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// ERREURS D'AUTHENTIFICATION (codes stables exposés aux clients)
+// =============================================================================
+
+var (
+	// ErrInvalidCredentials email inconnu ou mot de passe faux : les deux cas sont indiscernables
+	ErrInvalidCredentials = errors.New("identifiants invalides")
+	// ErrAccountDeactivated le compte est désactivé (réactivable)
+	ErrAccountDeactivated = errors.New("compte désactivé")
+	// ErrAccountBanned le compte est banni
+	ErrAccountBanned = errors.New("compte banni")
+)
+
+// TokenIssuer émet les jetons d'accès des utilisateurs authentifiés
+type TokenIssuer interface {
+	Issue(actor Actor, ttl time.Duration) (string, error)
+}
+
+// VerifiedCredentials compte local correspondant à des identifiants valides
+type VerifiedCredentials struct {
+	User *entities.User
+	// TenantID / Roles portés par le jeton émis
+	TenantID string
+	Roles    []string
+}
+
+// CredentialVerifier vérifie un couple identifiant / mot de passe et retourne le compte local
+// Identifiant inconnu ou mot de passe faux : ErrInvalidCredentials, sans distinguer les deux cas
+type CredentialVerifier interface {
+	VerifyCredentials(ctx context.Context, login, password string) (*VerifiedCredentials, error)
+}
+
+// =============================================================================
+// VÉRIFICATION LOCALE (EMAIL + MOT DE PASSE HACHÉ)
+// =============================================================================
+
+type PasswordCredentialVerifier struct {
+	userRepo     repositories.UserRepository
+	passwordHash PasswordHasher
+}
+
+func NewPasswordCredentialVerifier(userRepo repositories.UserRepository, passwordHash PasswordHasher) *PasswordCredentialVerifier {
+	return &PasswordCredentialVerifier{
+		userRepo:     userRepo,
+		passwordHash: passwordHash,
+	}
+}
+
+func (v *PasswordCredentialVerifier) VerifyCredentials(ctx context.Context, login, password string) (*VerifiedCredentials, error) {
+	user, err := v.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(login)))
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la recherche de l'utilisateur", err)
+	}
+
+	if err := v.passwordHash.Verify(password, user.Password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return &VerifiedCredentials{User: user}, nil
+}
+
+// =============================================================================
+// OUVERTURE DE SESSION (commune à toutes les méthodes de connexion)
+// =============================================================================
+
+// SessionOpener émet le jeton d'accès d'un compte dont l'identité vient d'être vérifiée
+// (mot de passe, annuaire, SSO) : contrôle du statut, évaluation du risque, suivi des connexions
+// et des appareils, conditions d'utilisation
+type SessionOpener struct {
+	userRepo  repositories.UserRepository
+	tokens    TokenIssuer
+	terms     *TermsChecker
+	sessions  *SessionRecorder
+	risk      *LoginRiskGuard
+	publisher EventPublisher
+	logger    Logger
+	tokenTTL  time.Duration
+	clock     Clock
+}
+
+// NewSessionOpener risk nil = aucune règle de risque
+func NewSessionOpener(
+	userRepo repositories.UserRepository,
+	tokens TokenIssuer,
+	terms *TermsChecker,
+	sessions *SessionRecorder,
+	risk *LoginRiskGuard,
+	publisher EventPublisher,
+	logger Logger,
+	tokenTTL time.Duration,
+	clock Clock,
+) *SessionOpener {
+	return &SessionOpener{
+		userRepo:  userRepo,
+		tokens:    tokens,
+		terms:     terms,
+		sessions:  sessions,
+		risk:      risk,
+		publisher: publisher,
+		logger:    logger,
+		tokenTTL:  tokenTTL,
+		clock:     clock,
+	}
+}
+
+type LoginResponse struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	UserID      int       `json:"user_id"`
+	// TermsRequired version des conditions à accepter avant d'utiliser l'API (absente si à jour) :
+	// le jeton n'ouvre sinon que l'acceptation des conditions
+	TermsRequired string `json:"terms_required,omitempty"`
+}
+
+func (s *SessionOpener) Open(ctx context.Context, verified *VerifiedCredentials) (*LoginResponse, error) {
+	if err := checkLoginStatus(verified.User); err != nil {
+		return nil, err
+	}
+	attempt := s.prepare(ctx, verified.User.ID)
+	if err := s.risk.Assess(ctx, verified, attempt); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, verified, attempt)
+}
+
+// OpenVerified termine une connexion à risque avec le code reçu par email ; les règles ne sont
+// pas réévaluées
+func (s *SessionOpener) OpenVerified(ctx context.Context, challengeID, code string) (*LoginResponse, error) {
+	verified, err := s.risk.Verify(ctx, challengeID, code)
+	if err != nil {
+		return nil, err
+	}
+	// Le compte a pu être désactivé entre la connexion et la vérification
+	if err := checkLoginStatus(verified.User); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, verified, s.prepare(ctx, verified.User.ID))
+}
+
+// checkLoginStatus le statut n'est révélé qu'après vérification de l'identité : pas d'énumération
+// des comptes
+func checkLoginStatus(user *entities.User) error {
+	switch user.CurrentStatus() {
+	case entities.UserStatusDeactivated:
+		return ErrAccountDeactivated
+	case entities.UserStatusBanned:
+		return ErrAccountBanned
+	}
+	return nil
+}
+
+func (s *SessionOpener) prepare(ctx context.Context, userID int) *LoginAttempt {
+	// Sans historique lisible, les règles évaluent la connexion comme une première connexion
+	attempt, err := s.sessions.Prepare(ctx, userID, s.clock.Now())
+	if err != nil {
+		s.logger.Error("Failed to read previous sessions", err, map[string]interface{}{"user_id": userID})
+	}
+	return attempt
+}
+
+func (s *SessionOpener) issue(ctx context.Context, verified *VerifiedCredentials, attempt *LoginAttempt) (*LoginResponse, error) {
+	user := verified.User
+	now := attempt.At
+	expiresAt := now.Add(s.tokenTTL)
+	token, err := s.tokens.Issue(Actor{UserID: user.ID, TenantID: verified.TenantID, Roles: verified.Roles}, s.tokenTTL)
+	if err != nil {
+		return nil, newError("erreur lors de l'émission du jeton", err)
+	}
+
+	// Le suivi des connexions ne doit jamais empêcher de se connecter : l'échec est seulement journalisé
+	user.RecordLogin(now)
+	if _, err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Error("Failed to record last login", err, map[string]interface{}{"user_id": user.ID})
+	} else {
+		s.publisher.Publish(ctx, events.UserLoggedIn{UserID: user.ID, At: now})
+	}
+	if err := s.sessions.Record(ctx, attempt, expiresAt); err != nil {
+		s.logger.Error("Failed to record session", err, map[string]interface{}{"user_id": user.ID})
+	}
+
+	response := &LoginResponse{AccessToken: token, TokenType: "Bearer", ExpiresAt: expiresAt, UserID: user.ID}
+	_, upToDate, err := s.terms.Latest(ctx, user.ID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des acceptations", err)
+	}
+	if !upToDate {
+		response.TermsRequired = s.terms.CurrentVersion()
+	}
+	return response, nil
+}
+
+// =============================================================================
+// LOGIN USE CASE
+// =============================================================================
+
+type LoginUseCase struct {
+	credentials CredentialVerifier
+	sessions    *SessionOpener
+}
+
+func NewLoginUseCase(credentials CredentialVerifier, sessions *SessionOpener) *LoginUseCase {
+	return &LoginUseCase{
+		credentials: credentials,
+		sessions:    sessions,
+	}
+}
+
+type LoginRequest struct {
+	// Email identifiant de connexion : l'email, ou l'identifiant d'annuaire avec LDAP (voir LDAP_USER_FILTER)
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (req LoginRequest) Validate() error {
+	if strings.TrimSpace(req.Email) == "" || req.Password == "" {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+func (req LoginRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"email": req.Email}
+}
+
+func (uc *LoginUseCase) Execute(ctx context.Context, req LoginRequest) (*LoginResponse, error) {
+	verified, err := uc.credentials.VerifyCredentials(ctx, req.Email, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	return uc.sessions.Open(ctx, verified)
+}
@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+)
+
+// =============================================================================
+// AUTORISATION PAR POLITIQUES (ABAC)
+// =============================================================================
+
+var (
+	// ErrForbidden les politiques refusent l'action à l'acteur authentifié
+	ErrForbidden = errors.New("action non autorisée")
+	// ErrAuthenticationRequired les politiques refusent l'action à un appel anonyme
+	ErrAuthenticationRequired = errors.New("authentification requise")
+)
+
+// PolicyResource entrée de use case exposant aux politiques les attributs de la ressource visée
+// (ex: {"user_id": 42}) ; les entrées entières sont des identifiants d'utilisateur
+type PolicyResource interface {
+	PolicyAttributes() map[string]interface{}
+}
+
+// PolicyInput ce que voit une politique : l'action (nom du use case), son niveau d'accès
+// déclaré, l'acteur et la ressource
+type PolicyInput struct {
+	Action        string
+	Access        Access
+	Actor         Actor
+	Authenticated bool
+	Resource      map[string]interface{}
+}
+
+// PolicyEngine évalue les politiques déclaratives (règles intégrées, OPA...)
+type PolicyEngine interface {
+	Allow(ctx context.Context, input PolicyInput) (bool, error)
+}
+
+// PolicyAuthorizer implémente Authorizer en déléguant la décision au moteur de politiques
+// Une erreur du moteur refuse l'exécution (fermé par défaut)
+type PolicyAuthorizer struct {
+	engine   PolicyEngine
+	registry *UseCaseRegistry
+}
+
+// NewPolicyAuthorizer registry fournit le niveau d'accès de chaque action (celui du pipeline)
+func NewPolicyAuthorizer(engine PolicyEngine, registry *UseCaseRegistry) *PolicyAuthorizer {
+	return &PolicyAuthorizer{engine: engine, registry: registry}
+}
+
+func (a *PolicyAuthorizer) Authorize(ctx context.Context, useCase string, input interface{}) error {
+	actor, _ := ActorFromContext(ctx)
+	authenticated := actor.UserID > 0
+
+	allowed, err := a.engine.Allow(ctx, PolicyInput{
+		Action:        useCase,
+		Access:        a.registry.Access(useCase),
+		Actor:         actor,
+		Authenticated: authenticated,
+		Resource:      policyResource(input),
+	})
+	if err != nil {
+		return newError("erreur lors de l'évaluation des politiques d'autorisation", err)
+	}
+	if allowed {
+		return nil
+	}
+	if !authenticated && !actor.System {
+		return ErrAuthenticationRequired
+	}
+	return ErrForbidden
+}
+
+func policyResource(input interface{}) map[string]interface{} {
+	switch typed := input.(type) {
+	case PolicyResource:
+		return typed.PolicyAttributes()
+	case int:
+		return map[string]interface{}{"user_id": typed}
+	default:
+		return map[string]interface{}{}
+	}
+}
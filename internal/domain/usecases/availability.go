@@ -0,0 +1,213 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// MODES DE DISPONIBILITÉ : maintenance et lecture seule
+// =============================================================================
+
+// Modes de disponibilité, du plus ouvert au plus restrictif
+const (
+	AvailabilityAvailable   = "available"
+	AvailabilityReadOnly    = "read_only"   // lectures servies, écritures refusées
+	AvailabilityMaintenance = "maintenance" // tout est refusé, sauf le pilotage du mode
+)
+
+// Feature flags imposant un mode sans redéploiement ; le mode le plus restrictif l'emporte
+const (
+	FlagMaintenanceMode = "ops.maintenance_mode"
+	FlagReadOnlyMode    = "ops.read_only_mode"
+)
+
+// ReadOnlyUseCases use cases servis en lecture seule : lectures, et connexion (la mise à jour
+// de la dernière connexion est tolérée pour ne pas déconnecter tout le monde pendant une migration)
+var ReadOnlyUseCases = []string{
+	"get_user", "get_user_by_handle", "list_users", "count_users", "search_users", "list_inactive_users",
+	"check_handle_availability", "get_terms_status", "get_notification_preferences", "list_notifications",
+	"get_identity_provider", "get_saml_metadata", "get_tenant_usage", "get_billing_account",
+	"search_events", "get_event_rollups", "get_scim_user", "list_scim_users",
+	"login", "verify_login", "start_sso_login", "consume_sso_response",
+	"get_availability", "list_audit_entries", "export_audit_entries", "get_onboarding",
+	"list_dead_letters", "get_dead_letter", "measure_dead_letters", "get_user_timeline", "list_sessions",
+	"get_analytics_consent", "list_consent_changes", "query_events", "list_saved_reports", "run_saved_report",
+	"list_write_keys", "list_alert_rules", "get_user_stats",
+}
+
+// availabilityControl use cases toujours servis : sans eux, la maintenance ne pourrait pas être levée
+// (ni les logs détaillés pendant une intervention)
+var availabilityControl = map[string]bool{
+	"get_availability": true, "set_availability": true,
+	"get_log_level": true, "set_log_level": true,
+}
+
+// ErrUnavailable cause des refus liés au mode de disponibilité (HTTP 503)
+var ErrUnavailable = errors.New("service temporairement indisponible")
+
+// UnavailableError use case refusé par le mode courant ; RetryAfter est annoncé au client
+type UnavailableError struct {
+	Mode       string
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Mode == AvailabilityReadOnly {
+		return "service en lecture seule, les modifications sont temporairement refusées"
+	}
+	return "service en maintenance"
+}
+
+func (e *UnavailableError) Unwrap() error { return ErrUnavailable }
+
+// Availability mode courant : celui fixé par SetMode (configuration au démarrage, endpoint
+// d'administration), durci le cas échéant par les feature flags
+type Availability struct {
+	flags      FeatureFlags
+	retryAfter time.Duration
+	reads      map[string]bool
+
+	mutex sync.RWMutex
+	mode  string
+}
+
+// NewAvailability flags peut être nil (mode piloté par la configuration et l'endpoint seulement)
+func NewAvailability(mode string, flags FeatureFlags, retryAfter time.Duration, readUseCases []string) (*Availability, error) {
+	reads := make(map[string]bool, len(readUseCases))
+	for _, name := range readUseCases {
+		reads[name] = true
+	}
+	availability := &Availability{flags: flags, retryAfter: retryAfter, reads: reads}
+	if err := availability.SetMode(mode); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+func (a *Availability) SetMode(mode string) error {
+	switch mode {
+	case AvailabilityAvailable, AvailabilityReadOnly, AvailabilityMaintenance:
+	default:
+		return fmt.Errorf("mode de disponibilité inconnu : %q", mode)
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.mode = mode
+	return nil
+}
+
+// Mode mode effectif pour ctx (les flags peuvent cibler un tenant)
+func (a *Availability) Mode(ctx context.Context) string {
+	a.mutex.RLock()
+	mode := a.mode
+	a.mutex.RUnlock()
+	if a.flags == nil || mode == AvailabilityMaintenance {
+		return mode
+	}
+	if a.flags.IsEnabled(ctx, FlagMaintenanceMode) {
+		return AvailabilityMaintenance
+	}
+	if a.flags.IsEnabled(ctx, FlagReadOnlyMode) {
+		return AvailabilityReadOnly
+	}
+	return mode
+}
+
+// Check nil si le use case name peut s'exécuter dans le mode courant
+func (a *Availability) Check(ctx context.Context, name string) error {
+	if availabilityControl[name] {
+		return nil
+	}
+	switch mode := a.Mode(ctx); {
+	case mode == AvailabilityMaintenance, mode == AvailabilityReadOnly && !a.reads[name]:
+		return &UnavailableError{Mode: mode, RetryAfter: a.retryAfter}
+	}
+	return nil
+}
+
+// WithAvailability refuse le use case en maintenance, ou en lecture seule s'il écrit (nil = toujours disponible)
+// Appliqué dans le pipeline : HTTP, SCIM, WebSocket et tâches planifiées sont concernés de la même façon
+func WithAvailability[I, O any](name string, availability *Availability) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if availability == nil {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			if err := availability.Check(ctx, name); err != nil {
+				var zero O
+				return zero, err
+			}
+			return next.Execute(ctx, input)
+		})
+	}
+}
+
+// =============================================================================
+// PILOTAGE DU MODE
+// =============================================================================
+
+type AvailabilityResponse struct {
+	// Mode mode effectif (flags compris) ; Configured celui fixé par la configuration ou l'endpoint
+	Mode       string `json:"mode"`
+	Configured string `json:"configured"`
+	// RetryAfterSeconds délai annoncé aux clients refusés
+	RetryAfterSeconds int `json:"retry_after_seconds"`
+}
+
+func (a *Availability) response(ctx context.Context) *AvailabilityResponse {
+	a.mutex.RLock()
+	configured := a.mode
+	a.mutex.RUnlock()
+	return &AvailabilityResponse{Mode: a.Mode(ctx), Configured: configured, RetryAfterSeconds: int(a.retryAfter.Seconds())}
+}
+
+type GetAvailabilityUseCase struct {
+	availability *Availability
+}
+
+func NewGetAvailabilityUseCase(availability *Availability) *GetAvailabilityUseCase {
+	return &GetAvailabilityUseCase{availability: availability}
+}
+
+func (uc *GetAvailabilityUseCase) Execute(ctx context.Context, _ struct{}) (*AvailabilityResponse, error) {
+	return uc.availability.response(ctx), nil
+}
+
+type SetAvailabilityRequest struct {
+	Mode string `json:"mode"`
+}
+
+func (req SetAvailabilityRequest) Validate() error {
+	switch req.Mode {
+	case AvailabilityAvailable, AvailabilityReadOnly, AvailabilityMaintenance:
+		return nil
+	}
+	return errors.New("mode attendu : \"available\", \"read_only\" ou \"maintenance\"")
+}
+
+func (req SetAvailabilityRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"mode": req.Mode}
+}
+
+// SetAvailabilityUseCase change le mode de l'instance qui reçoit l'appel (non partagé entre
+// instances : les feature flags servent à basculer toute la flotte)
+type SetAvailabilityUseCase struct {
+	availability *Availability
+	logger       Logger
+}
+
+func NewSetAvailabilityUseCase(availability *Availability, logger Logger) *SetAvailabilityUseCase {
+	return &SetAvailabilityUseCase{availability: availability, logger: logger}
+}
+
+func (uc *SetAvailabilityUseCase) Execute(ctx context.Context, req SetAvailabilityRequest) (*AvailabilityResponse, error) {
+	if err := uc.availability.SetMode(req.Mode); err != nil {
+		return nil, newError(err.Error(), err)
+	}
+	uc.logger.Warn("Availability mode changed", map[string]interface{}{"mode": req.Mode})
+	return uc.availability.response(ctx), nil
+}
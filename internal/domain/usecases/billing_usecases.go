@@ -0,0 +1,497 @@
+// internal/domain/usecases/billing_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownPlan offre absente du catalogue (BILLING_PLANS_FILE)
+	ErrUnknownPlan = errors.New("offre inconnue")
+	// ErrAlreadySubscribed le tenant a déjà un abonnement en cours : le changement d'offre passe
+	// par le portail du fournisseur, puis revient par webhook
+	ErrAlreadySubscribed = errors.New("le tenant a déjà un abonnement en cours")
+)
+
+// =============================================================================
+// PORT DE FACTURATION
+// =============================================================================
+
+// Billing port du fournisseur de facturation (services.StripeBilling)
+type Billing interface {
+	// CreateCustomer crée le client du tenant et retourne son identifiant chez le fournisseur
+	CreateCustomer(ctx context.Context, customer BillingCustomer) (string, error)
+	Subscribe(ctx context.Context, customerID, priceID, tenantID string) (*BillingSubscription, error)
+	// ReportUsage déclare une consommation ; IdempotencyKey évite un double comptage au rejeu
+	ReportUsage(ctx context.Context, report UsageReport) error
+	// ParseSubscriptionEvent traduit un webhook du fournisseur (signature déjà vérifiée) ;
+	// ErrWebhookEventIgnored pour les événements sans rapport avec un abonnement
+	ParseSubscriptionEvent(event WebhookEvent) (*SubscriptionEvent, error)
+}
+
+type BillingCustomer struct {
+	TenantID string
+	Email    string
+	Name     string
+}
+
+type BillingSubscription struct {
+	ID               string
+	PriceID          string
+	Status           entities.SubscriptionStatus
+	CurrentPeriodEnd time.Time
+}
+
+type UsageReport struct {
+	CustomerID     string
+	Resource       string // repositories.UsageAPICalls
+	Quantity       int
+	At             time.Time
+	IdempotencyKey string
+}
+
+// SubscriptionEvent changement d'abonnement notifié par le fournisseur
+type SubscriptionEvent struct {
+	CustomerID string
+	// TenantID metadata posée à la souscription ; vide pour un abonnement créé hors de l'API
+	TenantID     string
+	Subscription BillingSubscription
+}
+
+// =============================================================================
+// CATALOGUE DES OFFRES
+// =============================================================================
+
+// BillingPlan offre commerciale : prix chez le fournisseur, quotas et fonctionnalités incluses
+type BillingPlan struct {
+	Name    string
+	PriceID string
+	// Quotas remplacent les quotas par défaut, ressource par ressource (TENANT_QUOTAS reste prioritaire)
+	Quotas Quotas
+	// Features feature flags activés pour les utilisateurs du tenant
+	Features []string
+}
+
+// BillingPlans catalogue des offres par nom
+type BillingPlans map[string]BillingPlan
+
+func (p BillingPlans) byPrice(priceID string) (BillingPlan, bool) {
+	for _, plan := range p {
+		if plan.PriceID == priceID {
+			return plan, true
+		}
+	}
+	return BillingPlan{}, false
+}
+
+// TenantPlans offre en vigueur de chaque tenant, lue dans son compte de facturation :
+// les webhooks d'abonnement mettent le compte à jour, quotas et flags suivent sans redémarrage
+type TenantPlans struct {
+	accountRepo repositories.BillingAccountRepository
+	plans       BillingPlans
+}
+
+func NewTenantPlans(accountRepo repositories.BillingAccountRepository, plans BillingPlans) *TenantPlans {
+	return &TenantPlans{accountRepo: accountRepo, plans: plans}
+}
+
+// planFor offre active du tenant ; false sans compte, sans abonnement en vigueur ou offre retirée du catalogue
+func (t *TenantPlans) planFor(ctx context.Context, tenant string) (BillingPlan, bool, error) {
+	account, err := t.accountRepo.GetByTenant(ctx, tenant)
+	if errors.Is(err, repositories.ErrBillingAccountNotFound) {
+		return BillingPlan{}, false, nil
+	}
+	if err != nil {
+		return BillingPlan{}, false, err
+	}
+	if !account.PlanActive() {
+		return BillingPlan{}, false, nil
+	}
+	plan, ok := t.plans[account.Plan]
+	return plan, ok, nil
+}
+
+// TenantQuotas implémente TenantQuotaSource
+func (t *TenantPlans) TenantQuotas(ctx context.Context, tenant string) (Quotas, error) {
+	plan, ok, err := t.planFor(ctx, tenant)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return plan.Quotas, nil
+}
+
+// PlanFeatureFlags décore un FeatureFlags : un flag inclus dans l'offre du tenant est actif,
+// les autres sont évalués par les règles habituelles
+type PlanFeatureFlags struct {
+	next  FeatureFlags
+	plans *TenantPlans
+}
+
+func NewPlanFeatureFlags(next FeatureFlags, plans *TenantPlans) *PlanFeatureFlags {
+	return &PlanFeatureFlags{next: next, plans: plans}
+}
+
+func (f *PlanFeatureFlags) IsEnabled(ctx context.Context, flag string) bool {
+	if tenant := meteredTenant(ctx); tenant != "" {
+		// Compte illisible : les règles habituelles décident, l'appel n'échoue pas pour un flag
+		if plan, ok, err := f.plans.planFor(ctx, tenant); err == nil && ok && slices.Contains(plan.Features, flag) {
+			return true
+		}
+	}
+	return f.next.IsEnabled(ctx, flag)
+}
+
+// =============================================================================
+// WEBHOOKS D'ABONNEMENT
+// =============================================================================
+
+// SyncSubscriptionCommand applique un changement d'abonnement notifié par le fournisseur
+type SyncSubscriptionCommand struct {
+	Event SubscriptionEvent
+}
+
+func (SyncSubscriptionCommand) CommandName() string { return "sync_subscription" }
+
+// BillingWebhookTranslator implémente WebhookTranslator en déléguant le décodage au port Billing
+type BillingWebhookTranslator struct {
+	billing Billing
+}
+
+func NewBillingWebhookTranslator(billing Billing) *BillingWebhookTranslator {
+	return &BillingWebhookTranslator{billing: billing}
+}
+
+func (t *BillingWebhookTranslator) Translate(event WebhookEvent) (WebhookCommand, error) {
+	subscription, err := t.billing.ParseSubscriptionEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return SyncSubscriptionCommand{Event: *subscription}, nil
+}
+
+// =============================================================================
+// DTO COMMUN
+// =============================================================================
+
+type BillingAccountResponse struct {
+	TenantID         string     `json:"tenant_id"`
+	CustomerID       string     `json:"customer_id"`
+	Plan             string     `json:"plan,omitempty"`
+	Status           string     `json:"status,omitempty"`
+	Active           bool       `json:"active"`
+	CurrentPeriodEnd *time.Time `json:"current_period_end,omitempty"`
+	// Features fonctionnalités incluses dans l'offre active
+	Features []string `json:"features,omitempty"`
+}
+
+func newBillingAccountResponse(account *entities.BillingAccount, plans BillingPlans) *BillingAccountResponse {
+	response := &BillingAccountResponse{
+		TenantID:   account.TenantID,
+		CustomerID: account.CustomerID,
+		Plan:       account.Plan,
+		Status:     string(account.Status),
+		Active:     account.PlanActive(),
+	}
+	if !account.CurrentPeriodEnd.IsZero() {
+		periodEnd := account.CurrentPeriodEnd
+		response.CurrentPeriodEnd = &periodEnd
+	}
+	if response.Active {
+		response.Features = plans[account.Plan].Features
+	}
+	return response
+}
+
+// applySubscription recopie l'état de l'abonnement sur le compte ; un prix absent du catalogue
+// ne donne aucune offre (quotas et flags par défaut)
+func applySubscription(account *entities.BillingAccount, subscription BillingSubscription, plans BillingPlans, now time.Time) {
+	account.SubscriptionID = subscription.ID
+	account.Status = subscription.Status
+	account.CurrentPeriodEnd = subscription.CurrentPeriodEnd
+	account.Plan = ""
+	if plan, ok := plans.byPrice(subscription.PriceID); ok {
+		account.Plan = plan.Name
+	}
+	account.Updated = now
+}
+
+// =============================================================================
+// SUBSCRIBE TENANT USE CASE
+// =============================================================================
+
+// SubscribeTenantUseCase souscrit une offre pour un tenant ; le client est créé chez le
+// fournisseur à la première souscription. Réservé à l'administration (AccessAdmin) : les
+// jetons ne portent pas d'appartenance vérifiée au tenant
+type SubscribeTenantUseCase struct {
+	billing     Billing
+	accountRepo repositories.BillingAccountRepository
+	plans       BillingPlans
+	clock       Clock
+}
+
+func NewSubscribeTenantUseCase(billing Billing, accountRepo repositories.BillingAccountRepository, plans BillingPlans, clock Clock) *SubscribeTenantUseCase {
+	return &SubscribeTenantUseCase{billing: billing, accountRepo: accountRepo, plans: plans, clock: clock}
+}
+
+type SubscribeTenantRequest struct {
+	TenantID string `json:"-"`
+	Plan     string `json:"plan"`
+	// Email / Name contact de facturation, utilisés à la création du client
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+func (req SubscribeTenantRequest) Validate() error {
+	if req.TenantID == "" {
+		return errors.New("tenant obligatoire")
+	}
+	if req.Plan == "" {
+		return errors.New("offre obligatoire")
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		return errors.New("email de facturation invalide")
+	}
+	return nil
+}
+
+func (req SubscribeTenantRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant": req.TenantID, "plan": req.Plan}
+}
+
+func (req SubscribeTenantRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (uc *SubscribeTenantUseCase) Execute(ctx context.Context, req SubscribeTenantRequest) (*BillingAccountResponse, error) {
+	plan, ok := uc.plans[req.Plan]
+	if !ok {
+		return nil, fmt.Errorf("%w : %s", ErrUnknownPlan, req.Plan)
+	}
+
+	account, err := uc.accountRepo.GetByTenant(ctx, req.TenantID)
+	switch {
+	case errors.Is(err, repositories.ErrBillingAccountNotFound):
+		customerID, err := uc.billing.CreateCustomer(ctx, BillingCustomer{
+			TenantID: req.TenantID,
+			Email:    strings.TrimSpace(req.Email),
+			Name:     req.Name,
+		})
+		if err != nil {
+			return nil, newError("erreur lors de la création du client de facturation", err)
+		}
+		account = &entities.BillingAccount{TenantID: req.TenantID, CustomerID: customerID, Updated: uc.clock.Now()}
+		// Enregistré avant la souscription : un échec de celle-ci ne recrée pas de client au nouvel essai
+		if err := uc.accountRepo.Save(ctx, account); err != nil {
+			return nil, newError("erreur lors de l'enregistrement du compte de facturation", err)
+		}
+	case err != nil:
+		return nil, newError("erreur lors de la lecture du compte de facturation", err)
+	case account.SubscriptionID != "" && account.Status != entities.SubscriptionCanceled:
+		return nil, ErrAlreadySubscribed
+	}
+
+	subscription, err := uc.billing.Subscribe(ctx, account.CustomerID, plan.PriceID, req.TenantID)
+	if err != nil {
+		return nil, newError("erreur lors de la souscription de l'offre", err)
+	}
+	applySubscription(account, *subscription, uc.plans, uc.clock.Now())
+	if err := uc.accountRepo.Save(ctx, account); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du compte de facturation", err)
+	}
+	return newBillingAccountResponse(account, uc.plans), nil
+}
+
+// =============================================================================
+// GET BILLING ACCOUNT USE CASE
+// =============================================================================
+
+// GetBillingAccountUseCase compte de facturation d'un tenant, réservé à l'administration comme la souscription
+type GetBillingAccountUseCase struct {
+	accountRepo repositories.BillingAccountRepository
+	plans       BillingPlans
+}
+
+func NewGetBillingAccountUseCase(accountRepo repositories.BillingAccountRepository, plans BillingPlans) *GetBillingAccountUseCase {
+	return &GetBillingAccountUseCase{accountRepo: accountRepo, plans: plans}
+}
+
+type GetBillingAccountRequest struct {
+	TenantID string
+}
+
+func (req GetBillingAccountRequest) Validate() error {
+	if req.TenantID == "" {
+		return errors.New("tenant obligatoire")
+	}
+	return nil
+}
+
+func (req GetBillingAccountRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant": req.TenantID}
+}
+
+func (req GetBillingAccountRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (uc *GetBillingAccountUseCase) Execute(ctx context.Context, req GetBillingAccountRequest) (*BillingAccountResponse, error) {
+	account, err := uc.accountRepo.GetByTenant(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	return newBillingAccountResponse(account, uc.plans), nil
+}
+
+// =============================================================================
+// SYNC SUBSCRIPTION USE CASE (webhooks du fournisseur)
+// =============================================================================
+
+// SyncSubscriptionUseCase recopie l'abonnement notifié sur le compte du tenant : l'offre,
+// donc les quotas et les fonctionnalités, change dès l'appel suivant
+// Les webhooks sont appliqués dans leur ordre d'arrivée (chacun porte l'état complet de l'abonnement)
+type SyncSubscriptionUseCase struct {
+	accountRepo repositories.BillingAccountRepository
+	plans       BillingPlans
+	clock       Clock
+}
+
+func NewSyncSubscriptionUseCase(accountRepo repositories.BillingAccountRepository, plans BillingPlans, clock Clock) *SyncSubscriptionUseCase {
+	return &SyncSubscriptionUseCase{accountRepo: accountRepo, plans: plans, clock: clock}
+}
+
+type SyncSubscriptionRequest struct {
+	Event SubscriptionEvent
+}
+
+func (req SyncSubscriptionRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"customer_id":     req.Event.CustomerID,
+		"subscription_id": req.Event.Subscription.ID,
+		"status":          req.Event.Subscription.Status,
+	}
+}
+
+// SyncSubscriptionResponse Account nil : abonnement d'un client inconnu, sans tenant en metadata
+type SyncSubscriptionResponse struct {
+	Account *BillingAccountResponse `json:"account,omitempty"`
+}
+
+func (uc *SyncSubscriptionUseCase) Execute(ctx context.Context, req SyncSubscriptionRequest) (*SyncSubscriptionResponse, error) {
+	account, err := uc.accountRepo.GetByCustomer(ctx, req.Event.CustomerID)
+	if errors.Is(err, repositories.ErrBillingAccountNotFound) {
+		if req.Event.TenantID == "" {
+			return &SyncSubscriptionResponse{}, nil
+		}
+		// Webhook arrivé avant l'enregistrement de la souscription, ou client créé hors de l'API
+		account = &entities.BillingAccount{TenantID: req.Event.TenantID, CustomerID: req.Event.CustomerID}
+	} else if err != nil {
+		return nil, newError("erreur lors de la lecture du compte de facturation", err)
+	}
+
+	applySubscription(account, req.Event.Subscription, uc.plans, uc.clock.Now())
+	if err := uc.accountRepo.Save(ctx, account); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du compte de facturation", err)
+	}
+	return &SyncSubscriptionResponse{Account: newBillingAccountResponse(account, uc.plans)}, nil
+}
+
+// =============================================================================
+// REPORT BILLING USAGE USE CASE (tâche planifiée)
+// =============================================================================
+
+// ReportBillingUsageUseCase déclare au fournisseur les appels de chaque tenant abonné depuis la
+// déclaration précédente (facturation à l'usage) ; le reliquat d'un mois terminé est déclaré
+// au premier passage du mois suivant, daté de sa dernière seconde
+type ReportBillingUsageUseCase struct {
+	billing     Billing
+	accountRepo repositories.BillingAccountRepository
+	usageRepo   repositories.UsageRepository
+}
+
+func NewReportBillingUsageUseCase(billing Billing, accountRepo repositories.BillingAccountRepository, usageRepo repositories.UsageRepository) *ReportBillingUsageUseCase {
+	return &ReportBillingUsageUseCase{billing: billing, accountRepo: accountRepo, usageRepo: usageRepo}
+}
+
+type ReportBillingUsageRequest struct {
+	Now time.Time
+}
+
+func (req ReportBillingUsageRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"now": req.Now}
+}
+
+type ReportBillingUsageResponse struct {
+	Accounts int `json:"accounts"`
+	Calls    int `json:"calls"`
+	// Failed comptes à redéclarer au prochain passage (fournisseur indisponible...)
+	Failed int `json:"failed"`
+}
+
+func (uc *ReportBillingUsageUseCase) Execute(ctx context.Context, req ReportBillingUsageRequest) (*ReportBillingUsageResponse, error) {
+	accounts, err := uc.accountRepo.List(ctx)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des comptes de facturation", err)
+	}
+
+	response := &ReportBillingUsageResponse{}
+	period := usagePeriod(req.Now)
+	for _, account := range accounts {
+		if !account.PlanActive() || account.SubscriptionID == "" {
+			continue
+		}
+		response.Accounts++
+
+		if account.ReportedPeriod != "" && account.ReportedPeriod != period {
+			end, err := time.Parse("2006-01", account.ReportedPeriod)
+			if err == nil {
+				if err := uc.report(ctx, account, account.ReportedPeriod, end.AddDate(0, 1, 0).Add(-time.Second), response); err != nil {
+					response.Failed++
+					continue
+				}
+			}
+			account.ReportedPeriod, account.ReportedCalls = period, 0
+		}
+		if account.ReportedPeriod == "" {
+			account.ReportedPeriod = period
+		}
+		if err := uc.report(ctx, account, period, req.Now, response); err != nil {
+			response.Failed++
+		}
+	}
+	return response, nil
+}
+
+// report déclare les appels de period non encore déclarés, puis enregistre le nouveau cumul
+func (uc *ReportBillingUsageUseCase) report(ctx context.Context, account *entities.BillingAccount, period string, at time.Time, response *ReportBillingUsageResponse) error {
+	usage, err := uc.usageRepo.Get(ctx, account.TenantID, period)
+	if err != nil {
+		return err
+	}
+	calls := usage[repositories.UsageAPICalls]
+	if calls <= account.ReportedCalls {
+		return nil
+	}
+
+	err = uc.billing.ReportUsage(ctx, UsageReport{
+		CustomerID: account.CustomerID,
+		Resource:   repositories.UsageAPICalls,
+		Quantity:   calls - account.ReportedCalls,
+		At:         at,
+		// Même clé tant que le cumul n'a pas bougé : un rejeu après un Save raté ne compte pas deux fois
+		IdempotencyKey: fmt.Sprintf("%s:%s:%d", account.TenantID, period, calls),
+	})
+	if err != nil {
+		return err
+	}
+	response.Calls += calls - account.ReportedCalls
+	account.ReportedPeriod, account.ReportedCalls = period, calls
+	return uc.accountRepo.Save(ctx, account)
+}
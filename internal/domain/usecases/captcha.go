@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// CAPTCHA : protection des use cases publics contre les robots
+// =============================================================================
+
+var (
+	// ErrCaptchaRequired aucun jeton CAPTCHA (en-tête X-Captcha-Token) alors qu'il est exigé
+	ErrCaptchaRequired = errors.New("vérification CAPTCHA requise")
+	// ErrCaptchaFailed jeton refusé par le fournisseur, expiré, déjà utilisé ou score trop bas
+	ErrCaptchaFailed = errors.New("vérification CAPTCHA échouée")
+)
+
+// CaptchaResult réponse du fournisseur pour un jeton
+type CaptchaResult struct {
+	Success bool
+	// Score de 0 (robot) à 1 (humain), fourni par reCAPTCHA v3 et hCaptcha Enterprise seulement
+	Score    float64
+	HasScore bool
+}
+
+// CaptchaVerifier vérifie auprès du fournisseur (reCAPTCHA, hCaptcha, Turnstile) le jeton
+// obtenu par le navigateur ; remoteIP peut être vide
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (*CaptchaResult, error)
+}
+
+// CaptchaPolicy quand exiger le CAPTCHA, et qui en est dispensé
+type CaptchaPolicy struct {
+	// Actions use cases protégés (nom passé à Wrap)
+	Actions []string
+	// MinScore score minimal quand le fournisseur en donne un (0 = ignoré)
+	MinScore float64
+	// Threshold appels tolérés par adresse IP et par Window avant d'exiger le CAPTCHA ; 0 = toujours exigé
+	Threshold int
+	Window    time.Duration
+	// Risky adresses soumises au CAPTCHA dès le premier appel (nœuds de sortie TOR...) ; nil = aucune
+	Risky IPList
+	// BypassRoles rôles des clients de confiance (intégrations serveur à serveur) dispensés de CAPTCHA
+	BypassRoles []string
+	// BypassNetworks réseaux dispensés de CAPTCHA (back-offices, partenaires)
+	BypassNetworks []netip.Prefix
+}
+
+// CaptchaGuard applique CaptchaPolicy ; le jeton est celui du client de la requête (ClientFromContext)
+type CaptchaGuard struct {
+	verifier CaptchaVerifier
+	policy   CaptchaPolicy
+	actions  map[string]bool
+	clock    Clock
+	logger   Logger
+
+	mutex     sync.Mutex
+	attempts  map[string][]time.Time
+	lastSweep time.Time
+}
+
+func NewCaptchaGuard(verifier CaptchaVerifier, policy CaptchaPolicy, clock Clock, logger Logger) *CaptchaGuard {
+	actions := make(map[string]bool, len(policy.Actions))
+	for _, action := range policy.Actions {
+		actions[action] = true
+	}
+	return &CaptchaGuard{
+		verifier: verifier,
+		policy:   policy,
+		actions:  actions,
+		clock:    clock,
+		logger:   logger,
+		attempts: make(map[string][]time.Time),
+	}
+}
+
+func (g *CaptchaGuard) check(ctx context.Context, name string) error {
+	client := ClientFromContext(ctx)
+	if g.trusted(ctx, client.IP) || !g.required(ctx, name, client.IP) {
+		return nil
+	}
+	if client.CaptchaToken == "" {
+		return ErrCaptchaRequired
+	}
+
+	result, err := g.verifier.Verify(ctx, client.CaptchaToken, client.IP)
+	if err != nil {
+		return newError("erreur lors de la vérification CAPTCHA", err)
+	}
+	if !result.Success || (result.HasScore && result.Score < g.policy.MinScore) {
+		g.logger.Warn("CAPTCHA rejected", map[string]interface{}{
+			"use_case": name, "success": result.Success, "score": result.Score,
+		})
+		return ErrCaptchaFailed
+	}
+	return nil
+}
+
+func (g *CaptchaGuard) trusted(ctx context.Context, ip string) bool {
+	if actor, ok := ActorFromContext(ctx); ok {
+		for _, role := range actor.Roles {
+			if slices.Contains(g.policy.BypassRoles, role) {
+				return true
+			}
+		}
+	}
+	if addr, err := netip.ParseAddr(ip); err == nil {
+		for _, network := range g.policy.BypassNetworks {
+			if network.Contains(addr.Unmap()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// required compte l'appel et indique si l'adresse a dépassé le seuil ; une liste d'adresses
+// à risque indisponible n'impose rien
+func (g *CaptchaGuard) required(ctx context.Context, name, ip string) bool {
+	if g.policy.Threshold == 0 || ip == "" {
+		return true
+	}
+	if g.policy.Risky != nil {
+		risky, err := g.policy.Risky.Contains(ctx, ip)
+		if err != nil {
+			g.logger.Error("Failed to check risky address", err, map[string]interface{}{"use_case": name})
+		}
+		if risky {
+			return true
+		}
+	}
+
+	now := g.clock.Now()
+	since := now.Add(-g.policy.Window)
+	key := name + "|" + ip
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if now.Sub(g.lastSweep) >= g.policy.Window {
+		for existing, times := range g.attempts {
+			if !times[len(times)-1].After(since) {
+				delete(g.attempts, existing)
+			}
+		}
+		g.lastSweep = now
+	}
+	recent := slices.DeleteFunc(g.attempts[key], func(at time.Time) bool { return !at.After(since) })
+	g.attempts[key] = append(recent, now)
+	return len(recent) >= g.policy.Threshold
+}
+
+// WithCaptcha exige un CAPTCHA valide pour les use cases protégés (nil = aucun CAPTCHA)
+func WithCaptcha[I, O any](name string, guard *CaptchaGuard) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if guard == nil || !guard.actions[name] {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			if err := guard.check(ctx, name); err != nil {
+				var zero O
+				return zero, err
+			}
+			return next.Execute(ctx, input)
+		})
+	}
+}
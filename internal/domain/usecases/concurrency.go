@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+)
+
+// =============================================================================
+// LECTURES CONCURRENTES
+// =============================================================================
+
+// runConcurrently exécute les tâches en parallèle et attend qu'elles soient toutes terminées
+//   - à la première erreur, le context des autres tâches est annulé ; c'est elle qui est retournée
+//   - un panic dans une tâche est relancé dans l'appelant, où WithRecovery le rapporte
+//   - les tâches ne partagent ni état modifiable ni transaction : réservé aux lectures indépendantes
+func runConcurrently(ctx context.Context, tasks ...func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		firstErr  error
+		recovered any
+	)
+	for _, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					mutex.Lock()
+					if recovered == nil {
+						recovered = r
+					}
+					mutex.Unlock()
+					cancel()
+				}
+			}()
+			if err := task(ctx); err != nil {
+				mutex.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mutex.Unlock()
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if recovered != nil {
+		panic(recovered)
+	}
+	return firstErr
+}
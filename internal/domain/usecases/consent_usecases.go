@@ -0,0 +1,273 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// CONSENTEMENT À LA MESURE D'AUDIENCE
+// =============================================================================
+
+// Traitement des événements analytics d'un utilisateur sans consentement
+const (
+	WithoutConsentDrop      = "drop"      // écartés
+	WithoutConsentAnonymize = "anonymize" // enregistrés sans rattachement à l'utilisateur ni appareil
+)
+
+// ConsentChecker consentement en vigueur d'un utilisateur : son dernier choix, ou la valeur par
+// défaut tant qu'il ne s'est pas prononcé (denied pour un recueil opt-in, granted pour un opt-out)
+type ConsentChecker struct {
+	consentRepo    repositories.ConsentRepository
+	defaultConsent entities.AnalyticsConsent
+	withoutConsent string
+}
+
+func NewConsentChecker(consentRepo repositories.ConsentRepository, defaultConsent entities.AnalyticsConsent, withoutConsent string) *ConsentChecker {
+	return &ConsentChecker{consentRepo: consentRepo, defaultConsent: defaultConsent, withoutConsent: withoutConsent}
+}
+
+// Current consentement en vigueur et dernier changement (nil si l'utilisateur ne s'est jamais prononcé)
+func (c *ConsentChecker) Current(ctx context.Context, userID int) (entities.AnalyticsConsent, *entities.ConsentChange, error) {
+	latest, err := c.consentRepo.GetLatest(ctx, userID)
+	switch {
+	case errors.Is(err, repositories.ErrConsentNotFound):
+		return c.defaultConsent, nil, nil
+	case err != nil:
+		return entities.AnalyticsConsent{}, nil, err
+	}
+	return latest.Consent, latest, nil
+}
+
+// =============================================================================
+// GET ANALYTICS CONSENT USE CASE
+// =============================================================================
+
+type GetAnalyticsConsentUseCase struct {
+	checker *ConsentChecker
+}
+
+func NewGetAnalyticsConsentUseCase(checker *ConsentChecker) *GetAnalyticsConsentUseCase {
+	return &GetAnalyticsConsentUseCase{checker: checker}
+}
+
+type AnalyticsConsentResponse struct {
+	UserID int    `json:"user_id"`
+	Status string `json:"status"`
+	// Default aucun choix exprimé : Status est la valeur par défaut de l'instance
+	Default   bool       `json:"default"`
+	Source    string     `json:"source,omitempty"`
+	ChangedAt *time.Time `json:"changed_at,omitempty"`
+}
+
+func (uc *GetAnalyticsConsentUseCase) Execute(ctx context.Context, userID int) (*AnalyticsConsentResponse, error) {
+	consent, latest, err := uc.checker.Current(ctx, userID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du consentement", err)
+	}
+	return toAnalyticsConsentResponse(userID, consent, latest), nil
+}
+
+func toAnalyticsConsentResponse(userID int, consent entities.AnalyticsConsent, latest *entities.ConsentChange) *AnalyticsConsentResponse {
+	response := &AnalyticsConsentResponse{UserID: userID, Status: consent.Status, Default: latest == nil}
+	if latest != nil {
+		response.Source = latest.Source
+		response.ChangedAt = &latest.ChangedAt
+	}
+	return response
+}
+
+// =============================================================================
+// RECORD ANALYTICS CONSENT USE CASE
+// =============================================================================
+
+// RecordAnalyticsConsentUseCase enregistre le choix d'un utilisateur avec l'adresse et le
+// User-Agent du client (ClientFromContext), preuve exigée en cas de contrôle
+type RecordAnalyticsConsentUseCase struct {
+	userRepo    repositories.UserRepository
+	consentRepo repositories.ConsentRepository
+	checker     *ConsentChecker
+	publisher   EventPublisher
+	clock       Clock
+}
+
+func NewRecordAnalyticsConsentUseCase(
+	userRepo repositories.UserRepository,
+	consentRepo repositories.ConsentRepository,
+	checker *ConsentChecker,
+	publisher EventPublisher,
+	clock Clock,
+) *RecordAnalyticsConsentUseCase {
+	return &RecordAnalyticsConsentUseCase{
+		userRepo:    userRepo,
+		consentRepo: consentRepo,
+		checker:     checker,
+		publisher:   publisher,
+		clock:       clock,
+	}
+}
+
+type RecordAnalyticsConsentRequest struct {
+	UserID int    `json:"-"`
+	Status string `json:"status"`
+	// Source banner, settings, support ou api ; défaut : settings
+	Source string `json:"source,omitempty"`
+}
+
+func (req RecordAnalyticsConsentRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+func (req RecordAnalyticsConsentRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if _, err := entities.NewAnalyticsConsent(req.Status); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (req RecordAnalyticsConsentRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "status": req.Status, "source": req.Source}
+}
+
+func (uc *RecordAnalyticsConsentUseCase) Execute(ctx context.Context, req RecordAnalyticsConsentRequest) (*AnalyticsConsentResponse, error) {
+	if _, err := uc.userRepo.GetById(ctx, req.UserID); err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+	consent, err := entities.NewAnalyticsConsent(req.Status)
+	if err != nil {
+		return nil, err
+	}
+	if req.Source == "" {
+		req.Source = entities.ConsentFromSettings
+	}
+
+	// Idempotent : confirmer le choix en vigueur n'ajoute pas de ligne
+	_, latest, err := uc.checker.Current(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du consentement", err)
+	}
+	if latest != nil && latest.Consent == consent {
+		return toAnalyticsConsentResponse(req.UserID, consent, latest), nil
+	}
+
+	change, err := entities.NewConsentChange(req.UserID, consent, req.Source, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if actor, ok := ActorFromContext(ctx); ok && actor.UserID != req.UserID {
+		change.ActorID = actor.UserID
+	}
+	client := ClientFromContext(ctx)
+	change.IP, change.UserAgent = client.IP, client.UserAgent
+	if err := uc.consentRepo.Save(ctx, change); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du consentement", err)
+	}
+
+	uc.publisher.Publish(ctx, events.AnalyticsConsentChanged{
+		UserID: change.UserID,
+		Status: change.Consent.Status,
+		Source: change.Source,
+		At:     change.ChangedAt,
+	})
+	return toAnalyticsConsentResponse(req.UserID, consent, change), nil
+}
+
+// =============================================================================
+// LIST CONSENT CHANGES USE CASE (conformité)
+// =============================================================================
+
+// ListConsentChangesUseCase historique des changements, les plus récents d'abord, paginé par
+// curseur : preuve des choix d'un utilisateur, ou de tous sur une période
+type ListConsentChangesUseCase struct {
+	consentRepo repositories.ConsentRepository
+}
+
+func NewListConsentChangesUseCase(consentRepo repositories.ConsentRepository) *ListConsentChangesUseCase {
+	return &ListConsentChangesUseCase{consentRepo: consentRepo}
+}
+
+type ListConsentChangesRequest struct {
+	UserID int    `json:"user_id,omitempty"`
+	Status string `json:"status,omitempty"`
+	// From / To bornes RFC 3339 de ChangedAt (début inclus, fin exclue)
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Cursor next_cursor de la page précédente ; vide pour la première page
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit"` // défaut : 50
+}
+
+func (req ListConsentChangesRequest) Validate() error {
+	if req.UserID < 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if req.Status != "" {
+		if _, err := entities.NewAnalyticsConsent(req.Status); err != nil {
+			return err
+		}
+	}
+	if req.From != "" {
+		if _, err := time.Parse(time.RFC3339, req.From); err != nil {
+			return errors.New("from doit être une date RFC 3339")
+		}
+	}
+	if req.To != "" {
+		if _, err := time.Parse(time.RFC3339, req.To); err != nil {
+			return errors.New("to doit être une date RFC 3339")
+		}
+	}
+	if req.Limit < 0 || req.Limit > 500 {
+		return errors.New("limit doit être compris entre 1 et 500")
+	}
+	if _, err := decodeAuditCursor(req.Cursor); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (req ListConsentChangesRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "status": req.Status, "limit": req.Limit}
+}
+
+type ListConsentChangesResponse struct {
+	Changes []entities.ConsentChange `json:"changes"`
+	// NextCursor absent sur la dernière page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (uc *ListConsentChangesUseCase) Execute(ctx context.Context, req ListConsentChangesRequest) (*ListConsentChangesResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+	filter := repositories.ConsentFilter{UserID: req.UserID, Status: req.Status}
+	if from, err := time.Parse(time.RFC3339, req.From); err == nil {
+		filter.From = &from
+	}
+	if to, err := time.Parse(time.RFC3339, req.To); err == nil {
+		filter.To = &to
+	}
+	filter.BeforeID, _ = decodeAuditCursor(req.Cursor)
+	// Un changement de plus : indique s'il reste une page
+	filter.Limit = req.Limit + 1
+
+	changes, err := uc.consentRepo.List(ctx, filter)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des consentements", err)
+	}
+	response := &ListConsentChangesResponse{Changes: changes}
+	if len(changes) > req.Limit {
+		response.Changes = changes[:req.Limit]
+		response.NextCursor = encodeAuditCursor(response.Changes[req.Limit-1].ID)
+	}
+	if response.Changes == nil {
+		response.Changes = []entities.ConsentChange{}
+	}
+	return response, nil
+}
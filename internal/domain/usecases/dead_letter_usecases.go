@@ -0,0 +1,525 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// FILES DES ÉLÉMENTS ABANDONNÉS : messages de l'outbox, webhooks entrants
+// =============================================================================
+
+// Files d'éléments abandonnés
+const (
+	DeadLetterQueueOutbox   = "outbox"
+	DeadLetterQueueWebhooks = "webhooks"
+)
+
+// ErrDeadLetterNotFound élément absent de la file (rejoué, abandonné ou jamais en échec)
+var ErrDeadLetterNotFound = errors.New("élément introuvable dans la file des abandonnés")
+
+var errDeadLetterQueue = errors.New("file inconnue (outbox, webhooks)")
+
+// DeadLetter élément abandonné après trop d'échecs ; Payload n'est renseigné que par Get
+type DeadLetter struct {
+	ID        string              `json:"id"`
+	Kind      string              `json:"kind"`
+	Attempts  int                 `json:"attempts"`
+	LastError string              `json:"last_error"`
+	Failures  []DeadLetterFailure `json:"failures,omitempty"`
+	Payload   json.RawMessage     `json:"payload,omitempty"`
+	Created   time.Time           `json:"created"`
+}
+
+// DeadLetterFailure tentative en échec, la plus ancienne d'abord
+type DeadLetterFailure struct {
+	At    time.Time `json:"at"`
+	Error string    `json:"error"`
+}
+
+func deadLetterFailures(failures []repositories.DeliveryFailure) []DeadLetterFailure {
+	result := make([]DeadLetterFailure, 0, len(failures))
+	for _, failure := range failures {
+		result = append(result, DeadLetterFailure{At: failure.At, Error: failure.Error})
+	}
+	return result
+}
+
+// DeadLetterQueue file d'éléments abandonnés : Replay les soumet à nouveau, Discard les oublie
+type DeadLetterQueue interface {
+	List(ctx context.Context, limit, offset int) ([]DeadLetter, error)
+	Get(ctx context.Context, id string) (*DeadLetter, error)
+	Replay(ctx context.Context, id string) error
+	Discard(ctx context.Context, id string) error
+	Depth(ctx context.Context) (int, error)
+}
+
+// DeadLetterMetrics publie la profondeur des files (jauge par file)
+type DeadLetterMetrics interface {
+	ObserveDeadLetterDepth(queue string, depth int)
+}
+
+// OutboxDeadLetters messages non envoyés après maxAttempts tentatives ; Replay les remet en file,
+// le dispatcher les renvoie à sa prochaine relève
+type OutboxDeadLetters struct {
+	outboxRepo  repositories.OutboxRepository
+	maxAttempts int
+}
+
+func NewOutboxDeadLetters(outboxRepo repositories.OutboxRepository, maxAttempts int) *OutboxDeadLetters {
+	return &OutboxDeadLetters{outboxRepo: outboxRepo, maxAttempts: maxAttempts}
+}
+
+func (q *OutboxDeadLetters) List(ctx context.Context, limit, offset int) ([]DeadLetter, error) {
+	messages, err := q.outboxRepo.ListDead(ctx, q.maxAttempts, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]DeadLetter, 0, len(messages))
+	for _, message := range messages {
+		letters = append(letters, outboxDeadLetter(message))
+	}
+	return letters, nil
+}
+
+func (q *OutboxDeadLetters) Get(ctx context.Context, id string) (*DeadLetter, error) {
+	message, err := q.dead(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	letter := outboxDeadLetter(message)
+	if json.Valid(message.Payload) {
+		letter.Payload = message.Payload
+	}
+	return &letter, nil
+}
+
+func (q *OutboxDeadLetters) Replay(ctx context.Context, id string) error {
+	message, err := q.dead(ctx, id)
+	if err != nil {
+		return err
+	}
+	return q.outboxRepo.Requeue(ctx, message.ID)
+}
+
+func (q *OutboxDeadLetters) Discard(ctx context.Context, id string) error {
+	message, err := q.dead(ctx, id)
+	if err != nil {
+		return err
+	}
+	return q.outboxRepo.Delete(ctx, message.ID)
+}
+
+func (q *OutboxDeadLetters) Depth(ctx context.Context) (int, error) {
+	return q.outboxRepo.CountDead(ctx, q.maxAttempts)
+}
+
+// dead message abandonné id ; un message envoyé ou encore en cours de réessais n'en fait pas partie
+func (q *OutboxDeadLetters) dead(ctx context.Context, id string) (*repositories.OutboxMessage, error) {
+	messageID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, ErrDeadLetterNotFound
+	}
+	message, err := q.outboxRepo.Get(ctx, messageID)
+	if errors.Is(err, repositories.ErrOutboxMessageNotFound) {
+		return nil, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if message.SentAt != nil || message.Attempts < q.maxAttempts {
+		return nil, ErrDeadLetterNotFound
+	}
+	return message, nil
+}
+
+func outboxDeadLetter(message *repositories.OutboxMessage) DeadLetter {
+	return DeadLetter{
+		ID:        strconv.Itoa(message.ID),
+		Kind:      message.Kind,
+		Attempts:  message.Attempts,
+		LastError: message.LastError,
+		Failures:  deadLetterFailures(message.Failures),
+		Created:   message.Created,
+	}
+}
+
+// WebhookDeadLetters webhooks entrants abandonnés, identifiés par "source/id_événement" ;
+// Replay les retraite immédiatement (HandleWebhookEventUseCase.Replay)
+type WebhookDeadLetters struct {
+	failures repositories.FailedWebhookRepository
+	handler  *HandleWebhookEventUseCase
+}
+
+func NewWebhookDeadLetters(failures repositories.FailedWebhookRepository, handler *HandleWebhookEventUseCase) *WebhookDeadLetters {
+	return &WebhookDeadLetters{failures: failures, handler: handler}
+}
+
+func (q *WebhookDeadLetters) List(ctx context.Context, limit, offset int) ([]DeadLetter, error) {
+	events, err := q.failures.ListDeadLettered(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]DeadLetter, 0, len(events))
+	for _, event := range events {
+		letters = append(letters, webhookDeadLetter(event))
+	}
+	return letters, nil
+}
+
+func (q *WebhookDeadLetters) Get(ctx context.Context, id string) (*DeadLetter, error) {
+	event, err := q.dead(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	letter := webhookDeadLetter(event)
+	if json.Valid(event.Payload) {
+		letter.Payload = event.Payload
+	}
+	return &letter, nil
+}
+
+func (q *WebhookDeadLetters) Replay(ctx context.Context, id string) error {
+	event, err := q.dead(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = q.handler.Replay(ctx, WebhookEvent{
+		ID:         event.EventID,
+		Source:     event.Source,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Payload:    event.Payload,
+	})
+	return err
+}
+
+func (q *WebhookDeadLetters) Discard(ctx context.Context, id string) error {
+	event, err := q.dead(ctx, id)
+	if err != nil {
+		return err
+	}
+	// La réservation de l'événement est conservée : un renvoi du fournisseur reste ignoré
+	return q.failures.Delete(ctx, event.Source, event.EventID)
+}
+
+func (q *WebhookDeadLetters) Depth(ctx context.Context) (int, error) {
+	return q.failures.CountDeadLettered(ctx)
+}
+
+func (q *WebhookDeadLetters) dead(ctx context.Context, id string) (*repositories.FailedWebhookEvent, error) {
+	source, eventID, ok := strings.Cut(id, "/")
+	if !ok || source == "" || eventID == "" {
+		return nil, ErrDeadLetterNotFound
+	}
+	event, err := q.failures.Get(ctx, source, eventID)
+	if errors.Is(err, repositories.ErrFailedWebhookNotFound) {
+		return nil, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !event.DeadLettered {
+		return nil, ErrDeadLetterNotFound
+	}
+	return event, nil
+}
+
+func webhookDeadLetter(event *repositories.FailedWebhookEvent) DeadLetter {
+	letter := DeadLetter{
+		ID:       event.Source + "/" + event.EventID,
+		Kind:     event.Type,
+		Attempts: event.Attempts,
+		Failures: deadLetterFailures(event.Failures),
+		Created:  event.Created,
+	}
+	if len(event.Failures) > 0 {
+		letter.LastError = event.Failures[len(event.Failures)-1].Error
+	}
+	return letter
+}
+
+// deadLetterQueue file nommée de queues
+func deadLetterQueue(queues map[string]DeadLetterQueue, name string) (DeadLetterQueue, error) {
+	queue, ok := queues[name]
+	if !ok {
+		return nil, errDeadLetterQueue
+	}
+	return queue, nil
+}
+
+// =============================================================================
+// LIST DEAD LETTERS USE CASE
+// =============================================================================
+
+type ListDeadLettersUseCase struct {
+	queues  map[string]DeadLetterQueue
+	metrics DeadLetterMetrics
+}
+
+func NewListDeadLettersUseCase(queues map[string]DeadLetterQueue, metrics DeadLetterMetrics) *ListDeadLettersUseCase {
+	return &ListDeadLettersUseCase{queues: queues, metrics: metrics}
+}
+
+type ListDeadLettersRequest struct {
+	Queue  string `json:"queue"`
+	Limit  int    `json:"limit"` // défaut : 50
+	Offset int    `json:"offset"`
+}
+
+func (req ListDeadLettersRequest) Validate() error {
+	if req.Queue == "" {
+		return errors.New("file obligatoire")
+	}
+	if req.Limit < 0 || req.Limit > 500 {
+		return errors.New("limit doit être compris entre 1 et 500")
+	}
+	if req.Offset < 0 {
+		return errors.New("offset doit être positif")
+	}
+	return nil
+}
+
+func (req ListDeadLettersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"queue": req.Queue, "limit": req.Limit, "offset": req.Offset}
+}
+
+type ListDeadLettersResponse struct {
+	Queue string       `json:"queue"`
+	Depth int          `json:"depth"`
+	Items []DeadLetter `json:"items"`
+}
+
+func (uc *ListDeadLettersUseCase) Execute(ctx context.Context, req ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	queue, err := deadLetterQueue(uc.queues, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	depth, err := queue.Depth(ctx)
+	if err != nil {
+		return nil, newError("erreur lors du comptage des éléments abandonnés", err)
+	}
+	uc.metrics.ObserveDeadLetterDepth(req.Queue, depth)
+	items, err := queue.List(ctx, limit, req.Offset)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des éléments abandonnés", err)
+	}
+	return &ListDeadLettersResponse{Queue: req.Queue, Depth: depth, Items: items}, nil
+}
+
+// =============================================================================
+// GET DEAD LETTER USE CASE
+// =============================================================================
+
+// GetDeadLetterUseCase élément avec son historique d'échecs et son contenu
+type GetDeadLetterUseCase struct {
+	queues map[string]DeadLetterQueue
+}
+
+func NewGetDeadLetterUseCase(queues map[string]DeadLetterQueue) *GetDeadLetterUseCase {
+	return &GetDeadLetterUseCase{queues: queues}
+}
+
+type GetDeadLetterRequest struct {
+	Queue string `json:"queue"`
+	ID    string `json:"id"`
+}
+
+func (req GetDeadLetterRequest) Validate() error {
+	if req.Queue == "" || req.ID == "" {
+		return errors.New("file et identifiant obligatoires")
+	}
+	return nil
+}
+
+func (req GetDeadLetterRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"queue": req.Queue, "id": req.ID}
+}
+
+func (uc *GetDeadLetterUseCase) Execute(ctx context.Context, req GetDeadLetterRequest) (*DeadLetter, error) {
+	queue, err := deadLetterQueue(uc.queues, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+	letter, err := queue.Get(ctx, req.ID)
+	if errors.Is(err, ErrDeadLetterNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'élément abandonné", err)
+	}
+	return letter, nil
+}
+
+// =============================================================================
+// REPLAY / DISCARD DEAD LETTERS USE CASES
+// =============================================================================
+
+// Résultats par élément d'un rejeu ou d'un abandon
+const (
+	DeadLetterReplayed  = "replayed"
+	DeadLetterDiscarded = "discarded"
+	DeadLetterFailed    = "failed"
+	DeadLetterNotFound  = "not_found"
+)
+
+// DeadLetterBatchRequest éléments IDs de Queue, ou tous ses éléments avec All
+type DeadLetterBatchRequest struct {
+	Queue string   `json:"queue"`
+	IDs   []string `json:"ids"`
+	All   bool     `json:"all"`
+}
+
+func (req DeadLetterBatchRequest) Validate() error {
+	if req.Queue == "" {
+		return errors.New("file obligatoire")
+	}
+	if len(req.IDs) == 0 && !req.All {
+		return errors.New("identifiants obligatoires (ou tous les éléments)")
+	}
+	if len(req.IDs) > 0 && req.All {
+		return errors.New("identifiants et tous les éléments sont exclusifs")
+	}
+	return nil
+}
+
+func (req DeadLetterBatchRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"queue": req.Queue, "ids": len(req.IDs), "all": req.All}
+}
+
+// DeadLetterResult issue pour un élément ; Error détail d'un échec
+type DeadLetterResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type DeadLetterBatchResponse struct {
+	Queue   string             `json:"queue"`
+	Results []DeadLetterResult `json:"results"`
+	// Depth éléments restant dans la file
+	Depth int `json:"depth"`
+}
+
+// deadLetterBatch applique apply à chaque élément ; un échec n'interrompt pas le lot
+type deadLetterBatch struct {
+	queues  map[string]DeadLetterQueue
+	metrics DeadLetterMetrics
+	status  string
+	apply   func(queue DeadLetterQueue, ctx context.Context, id string) error
+}
+
+// deadLetterPageSize éléments lus par page pour All
+const deadLetterPageSize = 100
+
+func (b deadLetterBatch) Execute(ctx context.Context, req DeadLetterBatchRequest) (*DeadLetterBatchResponse, error) {
+	queue, err := deadLetterQueue(b.queues, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := req.IDs
+	if req.All {
+		// Liste figée avant de commencer : un élément rejoué sans succès reste dans la file
+		for offset := 0; ; offset += deadLetterPageSize {
+			page, err := queue.List(ctx, deadLetterPageSize, offset)
+			if err != nil {
+				return nil, newError("erreur lors de la lecture des éléments abandonnés", err)
+			}
+			for _, letter := range page {
+				ids = append(ids, letter.ID)
+			}
+			if len(page) < deadLetterPageSize {
+				break
+			}
+		}
+	}
+
+	response := &DeadLetterBatchResponse{Queue: req.Queue, Results: make([]DeadLetterResult, 0, len(ids))}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := DeadLetterResult{ID: id, Status: b.status}
+		switch err := b.apply(queue, ctx, id); {
+		case errors.Is(err, ErrDeadLetterNotFound):
+			result.Status = DeadLetterNotFound
+		case err != nil:
+			result.Status, result.Error = DeadLetterFailed, err.Error()
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if response.Depth, err = queue.Depth(ctx); err != nil {
+		return nil, newError("erreur lors du comptage des éléments abandonnés", err)
+	}
+	b.metrics.ObserveDeadLetterDepth(req.Queue, response.Depth)
+	return response, nil
+}
+
+// ReplayDeadLettersUseCase resoumet les éléments choisis (outbox : remise en file ; webhooks :
+// retraitement immédiat)
+type ReplayDeadLettersUseCase struct{ deadLetterBatch }
+
+func NewReplayDeadLettersUseCase(queues map[string]DeadLetterQueue, metrics DeadLetterMetrics) *ReplayDeadLettersUseCase {
+	return &ReplayDeadLettersUseCase{deadLetterBatch{queues: queues, metrics: metrics, status: DeadLetterReplayed, apply: DeadLetterQueue.Replay}}
+}
+
+// DiscardDeadLettersUseCase retire définitivement les éléments choisis
+type DiscardDeadLettersUseCase struct{ deadLetterBatch }
+
+func NewDiscardDeadLettersUseCase(queues map[string]DeadLetterQueue, metrics DeadLetterMetrics) *DiscardDeadLettersUseCase {
+	return &DiscardDeadLettersUseCase{deadLetterBatch{queues: queues, metrics: metrics, status: DeadLetterDiscarded, apply: DeadLetterQueue.Discard}}
+}
+
+// =============================================================================
+// MEASURE DEAD LETTERS USE CASE
+// =============================================================================
+
+// MeasureDeadLettersUseCase publie la profondeur de chaque file (tâche périodique)
+type MeasureDeadLettersUseCase struct {
+	queues  map[string]DeadLetterQueue
+	metrics DeadLetterMetrics
+}
+
+func NewMeasureDeadLettersUseCase(queues map[string]DeadLetterQueue, metrics DeadLetterMetrics) *MeasureDeadLettersUseCase {
+	return &MeasureDeadLettersUseCase{queues: queues, metrics: metrics}
+}
+
+type MeasureDeadLettersRequest struct{}
+
+// MeasureDeadLettersResponse profondeur par file
+type MeasureDeadLettersResponse struct {
+	Depths map[string]int `json:"depths"`
+}
+
+func (uc *MeasureDeadLettersUseCase) Execute(ctx context.Context, req MeasureDeadLettersRequest) (*MeasureDeadLettersResponse, error) {
+	names := make([]string, 0, len(uc.queues))
+	for name := range uc.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response := &MeasureDeadLettersResponse{Depths: make(map[string]int, len(names))}
+	for _, name := range names {
+		depth, err := uc.queues[name].Depth(ctx)
+		if err != nil {
+			return nil, newError(fmt.Sprintf("erreur lors du comptage de la file %s", name), err)
+		}
+		uc.metrics.ObserveDeadLetterDepth(name, depth)
+		response.Depths[name] = depth
+	}
+	return response, nil
+}
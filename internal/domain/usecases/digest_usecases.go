@@ -0,0 +1,222 @@
+// internal/domain/usecases/digest_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// =============================================================================
+// PORTS ET DTOs DU RÉSUMÉ HEBDOMADAIRE
+// =============================================================================
+
+// OutboxKindEmail type des messages d'outbox contenant un EmailPayload
+const OutboxKindEmail = "email"
+
+// EmailPayload contenu d'un message d'outbox de type email
+type EmailPayload struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// WeeklyDigest données passées au template du résumé
+type WeeklyDigest struct {
+	Name        string
+	Email       string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	// Activity nombre d'occurrences par type d'activité sur la période
+	Activity map[string]int
+	// Format locale et fuseau du destinataire pour les dates et les nombres
+	Format DisplayFormat
+}
+
+// DigestRenderer interface pour produire le sujet et le corps de l'email de résumé
+type DigestRenderer interface {
+	RenderWeeklyDigest(digest WeeklyDigest) (subject, body string, err error)
+}
+
+// =============================================================================
+// SEND WEEKLY DIGESTS USE CASE (planifié)
+// =============================================================================
+
+type SendWeeklyDigestsUseCase struct {
+	userRepo     repositories.UserRepository
+	activityRepo repositories.ActivityRepository
+	outboxRepo   repositories.OutboxRepository
+	renderer     DigestRenderer
+	formats      *DisplayFormats
+	clock        Clock
+}
+
+func NewSendWeeklyDigestsUseCase(
+	userRepo repositories.UserRepository,
+	activityRepo repositories.ActivityRepository,
+	outboxRepo repositories.OutboxRepository,
+	renderer DigestRenderer,
+	formats *DisplayFormats,
+	clock Clock,
+) *SendWeeklyDigestsUseCase {
+	return &SendWeeklyDigestsUseCase{
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+		outboxRepo:   outboxRepo,
+		renderer:     renderer,
+		formats:      formats,
+		clock:        clock,
+	}
+}
+
+type SendWeeklyDigestsRequest struct {
+	// Now fin de la période couverte (les 7 jours précédents) ; zéro = l'horloge du use case
+	Now time.Time
+}
+
+type SendWeeklyDigestsResponse struct {
+	UsersScanned int `json:"users_scanned"`
+	Queued       int `json:"queued"`
+	AlreadySent  int `json:"already_sent"`
+}
+
+func (uc *SendWeeklyDigestsUseCase) Execute(ctx context.Context, req SendWeeklyDigestsRequest) (*SendWeeklyDigestsResponse, error) {
+	if req.Now.IsZero() {
+		req.Now = uc.clock.Now()
+	}
+	periodStart := req.Now.AddDate(0, 0, -7)
+	year, week := req.Now.ISOWeek()
+
+	response := &SendWeeklyDigestsResponse{}
+
+	// Parcours par lots (UserRepository.Each) pour ne pas charger tous les utilisateurs en mémoire
+	err := uc.userRepo.Each(ctx, repositories.UserRepositoryFilters{}, func(user *entities.User) error {
+		response.UsersScanned++
+		// Un compte désactivé ou banni ne reçoit plus rien
+		if !user.WeeklyDigest || !user.IsActive() {
+			return nil
+		}
+
+		entries, err := uc.activityRepo.ListByUser(ctx, user.ID, periodStart)
+		if err != nil {
+			return newError("erreur lors de la lecture de l'activité", err)
+		}
+
+		digest := WeeklyDigest{
+			Name:        user.Name,
+			Email:       user.Email,
+			PeriodStart: periodStart,
+			PeriodEnd:   req.Now,
+			Activity:    make(map[string]int),
+			Format:      uc.formats.ForUser(user),
+		}
+		for _, entry := range entries {
+			digest.Activity[entry.Kind]++
+		}
+
+		subject, body, err := uc.renderer.RenderWeeklyDigest(digest)
+		if err != nil {
+			return newError("erreur lors du rendu du résumé", err)
+		}
+
+		payload, err := json.Marshal(EmailPayload{To: user.Email, Subject: subject, Body: body})
+		if err != nil {
+			return newError("erreur lors du rendu du résumé", err)
+		}
+
+		// La clé de déduplication rend le job rejouable dans la même semaine
+		queued, err := uc.outboxRepo.Enqueue(ctx, &repositories.OutboxMessage{
+			Kind:     OutboxKindEmail,
+			DedupKey: fmt.Sprintf("weekly_digest:%d:%d-W%02d", user.ID, year, week),
+			Payload:  payload,
+			Created:  req.Now,
+		})
+		if err != nil {
+			return newError("erreur lors de la mise en file du résumé", err)
+		}
+
+		if queued {
+			response.Queued++
+		} else {
+			response.AlreadySent++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, eachError("erreur lors de la récupération des utilisateurs", err)
+	}
+
+	return response, nil
+}
+
+// =============================================================================
+// UPDATE DIGEST PREFERENCE USE CASE
+// =============================================================================
+
+type UpdateDigestPreferenceUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewUpdateDigestPreferenceUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *UpdateDigestPreferenceUseCase {
+	return &UpdateDigestPreferenceUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+type UpdateDigestPreferenceRequest struct {
+	UserID  int  `json:"-"`
+	Enabled bool `json:"enabled"`
+}
+
+func (req UpdateDigestPreferenceRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type UpdateDigestPreferenceResponse struct {
+	UserID       int  `json:"user_id"`
+	WeeklyDigest bool `json:"weekly_digest"`
+}
+
+func (req UpdateDigestPreferenceRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	return nil
+}
+
+func (req UpdateDigestPreferenceRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "enabled": req.Enabled}
+}
+
+func (uc *UpdateDigestPreferenceUseCase) Execute(ctx context.Context, req UpdateDigestPreferenceRequest) (*UpdateDigestPreferenceResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	if user.SetWeeklyDigest(req.Enabled, uc.clock.Now()) {
+		if _, err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, newError("erreur lors de la mise à jour", err)
+		}
+
+		uc.publisher.Publish(ctx, events.DigestPreferenceChanged{
+			UserID:  user.ID,
+			Enabled: user.WeeklyDigest,
+			Changed: user.Updated,
+		})
+	}
+
+	return &UpdateDigestPreferenceResponse{
+		UserID:       user.ID,
+		WeeklyDigest: user.WeeklyDigest,
+	}, nil
+}
@@ -0,0 +1,137 @@
+// internal/domain/usecases/directory_authenticator.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// =============================================================================
+// PORT ANNUAIRE D'ENTREPRISE (LDAP / ACTIVE DIRECTORY)
+// =============================================================================
+
+// ErrDirectoryUserNotFound l'identifiant ne correspond à aucune entrée de l'annuaire
+var ErrDirectoryUserNotFound = errors.New("utilisateur absent de l'annuaire")
+
+// DirectoryEntry entrée d'annuaire d'un utilisateur authentifié
+type DirectoryEntry struct {
+	// DN identifiant de l'entrée, clé du lien avec le compte local
+	DN    string
+	Email string
+	Name  string
+	// Groups DN des groupes dont l'utilisateur est membre
+	Groups []string
+}
+
+// Directory interface pour authentifier un utilisateur auprès de l'annuaire
+// Mot de passe refusé : ErrInvalidCredentials ; identifiant inconnu : ErrDirectoryUserNotFound
+type Directory interface {
+	Source() string
+	Authenticate(ctx context.Context, login, password string) (*DirectoryEntry, error)
+}
+
+// =============================================================================
+// VÉRIFICATION PAR L'ANNUAIRE (COMPTES PROVISIONNÉS À LA PREMIÈRE CONNEXION)
+// =============================================================================
+
+// DirectoryCredentialVerifier implémente CredentialVerifier avec l'annuaire :
+// le compte local est rattaché ou créé à la première connexion (voir externalAccounts),
+// les groupes de l'utilisateur sont traduits en rôles par groupRoles (DN du groupe en minuscules → rôle)
+type DirectoryCredentialVerifier struct {
+	directory  Directory
+	linkRepo   repositories.ExternalUserLinkRepository
+	accounts   *externalAccounts
+	groupRoles map[string]string
+	// fallback vérification locale des identifiants absents de l'annuaire (comptes de secours) ; nil = refus
+	fallback CredentialVerifier
+}
+
+func NewDirectoryCredentialVerifier(
+	directory Directory,
+	userRepo repositories.UserRepository,
+	linkRepo repositories.ExternalUserLinkRepository,
+	hasher PasswordHasher,
+	publisher EventPublisher,
+	logger Logger,
+	groupRoles map[string]string,
+	fallback CredentialVerifier,
+	clock Clock,
+	tokenGenerator TokenGenerator,
+) *DirectoryCredentialVerifier {
+	return &DirectoryCredentialVerifier{
+		directory: directory,
+		linkRepo:  linkRepo,
+		accounts: &externalAccounts{
+			linkByEmail:    true,
+			userRepo:       userRepo,
+			linkRepo:       linkRepo,
+			hasher:         hasher,
+			publisher:      publisher,
+			logger:         logger,
+			clock:          clock,
+			tokenGenerator: tokenGenerator,
+		},
+		groupRoles: groupRoles,
+		fallback:   fallback,
+	}
+}
+
+func (v *DirectoryCredentialVerifier) VerifyCredentials(ctx context.Context, login, password string) (*VerifiedCredentials, error) {
+	login = strings.TrimSpace(login)
+	entry, err := v.directory.Authenticate(ctx, login, password)
+	switch {
+	case errors.Is(err, ErrDirectoryUserNotFound):
+		return v.verifyLocally(ctx, login, password)
+	case errors.Is(err, ErrInvalidCredentials):
+		return nil, ErrInvalidCredentials
+	case err != nil:
+		return nil, newError("erreur lors de l'interrogation de l'annuaire", err)
+	}
+
+	user, err := v.accounts.resolve(ctx, ExternalIdentity{
+		Source:     v.directory.Source(),
+		ExternalID: entry.DN,
+		Email:      entry.Email,
+		Name:       entry.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &VerifiedCredentials{User: user, Roles: v.rolesFor(entry.Groups)}, nil
+}
+
+// verifyLocally un compte rattaché à l'annuaire n'a jamais accès au repli local :
+// retiré de l'annuaire, il ne doit plus pouvoir se connecter
+func (v *DirectoryCredentialVerifier) verifyLocally(ctx context.Context, login, password string) (*VerifiedCredentials, error) {
+	if v.fallback == nil {
+		return nil, ErrInvalidCredentials
+	}
+	verified, err := v.fallback.VerifyCredentials(ctx, login, password)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = v.linkRepo.GetByUserID(ctx, v.directory.Source(), verified.User.ID)
+	switch {
+	case errors.Is(err, repositories.ErrExternalUserLinkNotFound):
+		return verified, nil
+	case err != nil:
+		return nil, newError("erreur lors de la lecture du lien d'annuaire", err)
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// rolesFor rôles triés et dédupliqués des groupes reconnus
+func (v *DirectoryCredentialVerifier) rolesFor(groups []string) []string {
+	var roles []string
+	for _, group := range groups {
+		if role, ok := v.groupRoles[strings.ToLower(group)]; ok {
+			roles = append(roles, role)
+		}
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
@@ -0,0 +1,308 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// PORTS ET FORMATS D'AFFICHAGE
+// =============================================================================
+
+// DisplayFormatter rend dates et nombres selon une locale ("fr", "en-US") ; les dates reçues
+// sont déjà converties dans le fuseau du destinataire
+type DisplayFormatter interface {
+	FormatDate(t time.Time, locale string) string
+	FormatDateTime(t time.Time, locale string) string
+	FormatNumber(value float64, decimals int, locale string) string
+}
+
+// DisplayFormat locale et fuseau résolus pour un destinataire, utilisable dans les templates
+// ({{.Format.Date .PeriodStart}}). La valeur zéro formate en ISO 8601 (UTC)
+type DisplayFormat struct {
+	Locale   string `json:"locale"`
+	TimeZone string `json:"time_zone"`
+
+	location  *time.Location
+	formatter DisplayFormatter
+}
+
+// Date jour, dans le fuseau du destinataire
+func (f DisplayFormat) Date(t time.Time) string {
+	if f.formatter == nil {
+		return t.UTC().Format(time.DateOnly)
+	}
+	return f.formatter.FormatDate(t.In(f.location), f.Locale)
+}
+
+// DateTime date et heure, dans le fuseau du destinataire
+func (f DisplayFormat) DateTime(t time.Time) string {
+	if f.formatter == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return f.formatter.FormatDateTime(t.In(f.location), f.Locale)
+}
+
+// CalendarDate jour calendaire sans conversion de fuseau : intervalles d'agrégats, alignés sur
+// les jours UTC, qu'une conversion décalerait d'un jour à l'ouest de Greenwich
+func (f DisplayFormat) CalendarDate(t time.Time) string {
+	if f.formatter == nil {
+		return t.UTC().Format(time.DateOnly)
+	}
+	return f.formatter.FormatDate(t.UTC(), f.Locale)
+}
+
+// Integer nombre entier avec séparateur de milliers
+func (f DisplayFormat) Integer(n int) string {
+	if f.formatter == nil {
+		return strconv.Itoa(n)
+	}
+	return f.formatter.FormatNumber(float64(n), 0, f.Locale)
+}
+
+// Decimal nombre arrondi à decimals chiffres après la virgule
+func (f DisplayFormat) Decimal(value float64, decimals int) string {
+	if f.formatter == nil {
+		return strconv.FormatFloat(value, 'f', decimals, 64)
+	}
+	return f.formatter.FormatNumber(value, decimals, f.Locale)
+}
+
+// =============================================================================
+// RÉSOLUTION DES PRÉFÉRENCES D'AFFICHAGE
+// =============================================================================
+
+// DisplayFormats résout le format d'un destinataire : ses préférences (User.Locale, User.TimeZone),
+// sinon la locale de la requête (Accept-Language), sinon les valeurs par défaut de l'instance
+type DisplayFormats struct {
+	userRepo  repositories.UserRepository
+	formatter DisplayFormatter
+	locale    string
+	timeZone  string
+	logger    Logger
+
+	// locations fuseaux déjà chargés (time.LoadLocation relit la base à chaque appel)
+	locations sync.Map
+}
+
+func NewDisplayFormats(userRepo repositories.UserRepository, formatter DisplayFormatter, locale, timeZone string, logger Logger) (*DisplayFormats, error) {
+	locale, err := entities.NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := entities.ValidateTimeZone(timeZone); err != nil {
+		return nil, err
+	}
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	return &DisplayFormats{
+		userRepo:  userRepo,
+		formatter: formatter,
+		locale:    locale,
+		timeZone:  timeZone,
+		logger:    logger,
+	}, nil
+}
+
+// Default format de l'instance (destinataire inconnu)
+func (d *DisplayFormats) Default() DisplayFormat {
+	return d.resolve("", "")
+}
+
+// ForUser format d'un destinataire connu (emails, tâches planifiées)
+func (d *DisplayFormats) ForUser(user *entities.User) DisplayFormat {
+	return d.resolve(user.Locale, user.TimeZone)
+}
+
+// ForActor format de l'appelant (champs display de l'API, exports) ; une erreur de lecture
+// retombe sur la locale de la requête plutôt que d'échouer l'appel
+func (d *DisplayFormats) ForActor(ctx context.Context) DisplayFormat {
+	locale := LocaleFromContext(ctx)
+	actor, _ := ActorFromContext(ctx)
+	if actor.UserID == 0 || actor.System {
+		return d.resolve(locale, "")
+	}
+	user, err := d.userRepo.GetById(ctx, actor.UserID)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrUserNotFound) {
+			d.logger.Error("Failed to load display preferences", err, map[string]interface{}{"user_id": actor.UserID})
+		}
+		return d.resolve(locale, "")
+	}
+	if user.Locale != "" {
+		locale = user.Locale
+	}
+	return d.resolve(locale, user.TimeZone)
+}
+
+func (d *DisplayFormats) resolve(locale, timeZone string) DisplayFormat {
+	if locale == "" {
+		locale = d.locale
+	}
+	if timeZone == "" {
+		timeZone = d.timeZone
+	}
+	return DisplayFormat{
+		Locale:    locale,
+		TimeZone:  timeZone,
+		location:  d.location(timeZone),
+		formatter: d.formatter,
+	}
+}
+
+// location un fuseau enregistré mais devenu inconnu (base IANA mise à jour) retombe sur UTC
+func (d *DisplayFormats) location(name string) *time.Location {
+	if cached, ok := d.locations.Load(name); ok {
+		return cached.(*time.Location)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		location = time.UTC
+	}
+	d.locations.Store(name, location)
+	return location
+}
+
+// =============================================================================
+// CHAMPS D'AFFICHAGE DE L'API (?display=true)
+// =============================================================================
+
+// UserDisplay dates d'un utilisateur formatées pour l'appelant
+type UserDisplay struct {
+	DisplayFormat
+	Created     string `json:"created"`
+	Updated     string `json:"updated"`
+	LastLoginAt string `json:"last_login_at,omitempty"`
+}
+
+func NewUserDisplay(user *GetUserResponse, format DisplayFormat) UserDisplay {
+	display := UserDisplay{
+		DisplayFormat: format,
+		Created:       format.DateTime(user.Created),
+		Updated:       format.DateTime(user.Updated),
+	}
+	if user.LastLoginAt != nil {
+		display.LastLoginAt = format.DateTime(*user.LastLoginAt)
+	}
+	return display
+}
+
+// EventRollupsDisplay période et compteurs des agrégats formatés pour l'appelant
+type EventRollupsDisplay struct {
+	DisplayFormat
+	From    string                     `json:"from"`
+	To      string                     `json:"to"`
+	Buckets []EventRollupBucketDisplay `json:"buckets"`
+	Totals  map[string]string          `json:"totals"`
+	Total   string                     `json:"total"`
+}
+
+type EventRollupBucketDisplay struct {
+	Start string `json:"start"`
+	Total string `json:"total"`
+}
+
+// NewEventRollupsDisplay la réponse (éventuellement partagée par le cache) n'est pas modifiée
+func NewEventRollupsDisplay(rollups *GetEventRollupsResponse, format DisplayFormat) EventRollupsDisplay {
+	display := EventRollupsDisplay{
+		DisplayFormat: format,
+		From:          format.CalendarDate(rollups.From),
+		To:            format.CalendarDate(rollups.To),
+		Buckets:       make([]EventRollupBucketDisplay, 0, len(rollups.Buckets)),
+		Totals:        make(map[string]string, len(rollups.Totals)),
+		Total:         format.Integer(rollups.Total),
+	}
+	for _, bucket := range rollups.Buckets {
+		display.Buckets = append(display.Buckets, EventRollupBucketDisplay{
+			Start: format.CalendarDate(bucket.Start),
+			Total: format.Integer(bucket.Total),
+		})
+	}
+	for name, count := range rollups.Totals {
+		display.Totals[name] = format.Integer(count)
+	}
+	return display
+}
+
+// =============================================================================
+// UPDATE DISPLAY PREFERENCES USE CASE
+// =============================================================================
+
+type UpdateDisplayPreferencesUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewUpdateDisplayPreferencesUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *UpdateDisplayPreferencesUseCase {
+	return &UpdateDisplayPreferencesUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+// UpdateDisplayPreferencesRequest valeurs vides = défaut de l'instance
+type UpdateDisplayPreferencesRequest struct {
+	UserID   int    `json:"-"`
+	Locale   string `json:"locale"`
+	TimeZone string `json:"time_zone"`
+}
+
+func (req UpdateDisplayPreferencesRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+func (req UpdateDisplayPreferencesRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	return nil
+}
+
+func (req UpdateDisplayPreferencesRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "locale": req.Locale, "time_zone": req.TimeZone}
+}
+
+type UpdateDisplayPreferencesResponse struct {
+	UserID   int    `json:"user_id"`
+	Locale   string `json:"locale"`
+	TimeZone string `json:"time_zone"`
+}
+
+func (uc *UpdateDisplayPreferencesUseCase) Execute(ctx context.Context, req UpdateDisplayPreferencesRequest) (*UpdateDisplayPreferencesResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	changed, err := user.SetDisplayPreferences(req.Locale, req.TimeZone, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if _, err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, newError("erreur lors de la mise à jour", err)
+		}
+
+		uc.publisher.Publish(ctx, events.UserLocaleChanged{
+			UserID:   user.ID,
+			Locale:   user.Locale,
+			TimeZone: user.TimeZone,
+			Changed:  user.Updated,
+		})
+	}
+
+	return &UpdateDisplayPreferencesResponse{
+		UserID:   user.ID,
+		Locale:   user.Locale,
+		TimeZone: user.TimeZone,
+	}, nil
+}
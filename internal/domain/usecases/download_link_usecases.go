@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// =============================================================================
+// LIENS DE TÉLÉCHARGEMENT SIGNÉS
+// =============================================================================
+
+var (
+	ErrDownloadLinkInvalid   = errors.New("lien de téléchargement invalide")
+	ErrDownloadLinkExpired   = errors.New("lien de téléchargement expiré")
+	ErrDownloadLinksDisabled = errors.New("liens de téléchargement désactivés (DOWNLOAD_SIGNING_KEY)")
+)
+
+// URLSigner signe un chemin et ses paramètres jusqu'à une date d'expiration ; Sign retourne les
+// paramètres complétés (expiration, signature), Verify les contrôle sur la requête reçue
+type URLSigner interface {
+	Sign(path string, params url.Values, expires time.Time) url.Values
+	Verify(path string, params url.Values, now time.Time) error
+}
+
+// DownloadLink lien retourné au client : le fichier se télécharge sans jeton Bearer (navigateur,
+// curl), la signature tenant lieu d'autorisation jusqu'à ExpiresAt
+type DownloadLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DownloadLinks émet et vérifie les liens signés des téléchargements volumineux (exports) : ils
+// sont servis hors des handlers JSON authentifiés, par des routes /downloads/ en flux. L'appelant
+// est autorisé à l'émission du lien ; le téléchargement s'exécute ensuite en tâche interne
+// (SystemActor). signer nil : liens désactivés
+type DownloadLinks struct {
+	signer  URLSigner
+	baseURL string
+	ttl     time.Duration
+	clock   Clock
+}
+
+// NewDownloadLinks baseURL préfixe des liens ("https://api.example.com" ; vide = chemin relatif)
+func NewDownloadLinks(signer URLSigner, baseURL string, ttl time.Duration, clock Clock) *DownloadLinks {
+	return &DownloadLinks{signer: signer, baseURL: baseURL, ttl: ttl, clock: clock}
+}
+
+// Link lien vers path, valable ttl ; params fait partie de la signature
+func (l *DownloadLinks) Link(path string, params url.Values) (*DownloadLink, error) {
+	if l.signer == nil {
+		return nil, ErrDownloadLinksDisabled
+	}
+	expires := l.clock.Now().Add(l.ttl).Truncate(time.Second)
+	signed := l.signer.Sign(path, params, expires)
+	return &DownloadLink{URL: l.baseURL + path + "?" + signed.Encode(), ExpiresAt: expires}, nil
+}
+
+// Verify contrôle la signature et l'expiration d'une requête de téléchargement
+func (l *DownloadLinks) Verify(path string, params url.Values) error {
+	if l.signer == nil {
+		return ErrDownloadLinksDisabled
+	}
+	return l.signer.Verify(path, params, l.clock.Now())
+}
+
+// =============================================================================
+// CREATE AUDIT EXPORT LINK USE CASE
+// =============================================================================
+
+// AuditExportDownloadPath route servant l'export CSV du journal d'audit sur lien signé
+const AuditExportDownloadPath = "/downloads/audit"
+
+// CreateAuditExportLinkUseCase lien signé vers l'export du journal d'audit (mêmes critères que
+// GET /admin/audit/export), pour un téléchargement hors de l'application (navigateur, script)
+type CreateAuditExportLinkUseCase struct {
+	links *DownloadLinks
+}
+
+func NewCreateAuditExportLinkUseCase(links *DownloadLinks) *CreateAuditExportLinkUseCase {
+	return &CreateAuditExportLinkUseCase{links: links}
+}
+
+func (uc *CreateAuditExportLinkUseCase) Execute(ctx context.Context, query AuditQuery) (*DownloadLink, error) {
+	return uc.links.Link(AuditExportDownloadPath, query.Params())
+}
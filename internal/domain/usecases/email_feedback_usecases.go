@@ -0,0 +1,227 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// REBONDS ET PLAINTES : retours du fournisseur d'email (POST /webhooks/email)
+// =============================================================================
+
+// MarkEmailUndeliverableCommand commande issue d'un rebond définitif ou d'une plainte
+type MarkEmailUndeliverableCommand struct {
+	Email  string
+	Reason string // entities.EmailBounced ou entities.EmailComplained
+}
+
+func (MarkEmailUndeliverableCommand) CommandName() string { return "mark_email_undeliverable" }
+
+// EmailFeedbackMetrics compte les retours du fournisseur et les envois supprimés
+type EmailFeedbackMetrics interface {
+	ObserveEmailFeedback(reason string)
+	ObserveSuppressedEmail()
+}
+
+// =============================================================================
+// MARK EMAIL UNDELIVERABLE USE CASE
+// =============================================================================
+
+// MarkEmailUndeliverableUseCase les envois suivants vers l'adresse sont supprimés (SuppressingEmailSender)
+// Une adresse inconnue n'est pas une erreur : le fournisseur signale aussi les emails de comptes supprimés
+type MarkEmailUndeliverableUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	monitor   *EmailFeedbackMonitor
+	clock     Clock
+}
+
+func NewMarkEmailUndeliverableUseCase(userRepo repositories.UserRepository, publisher EventPublisher, monitor *EmailFeedbackMonitor, clock Clock) *MarkEmailUndeliverableUseCase {
+	return &MarkEmailUndeliverableUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		monitor:   monitor,
+		clock:     clock,
+	}
+}
+
+type MarkEmailUndeliverableRequest struct {
+	Email  string `json:"email"`
+	Reason string `json:"reason"`
+}
+
+func (req MarkEmailUndeliverableRequest) Validate() error {
+	if strings.TrimSpace(req.Email) == "" {
+		return errors.New("email manquant")
+	}
+	if req.Reason != entities.EmailBounced && req.Reason != entities.EmailComplained {
+		return errors.New("motif attendu : bounce ou complaint")
+	}
+	return nil
+}
+
+func (req MarkEmailUndeliverableRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"reason": req.Reason}
+}
+
+// MarkEmailUndeliverableResponse Status : marked | already_marked | unknown_email
+type MarkEmailUndeliverableResponse struct {
+	UserID int    `json:"user_id,omitempty"`
+	Status string `json:"status"`
+}
+
+func (uc *MarkEmailUndeliverableUseCase) Execute(ctx context.Context, req MarkEmailUndeliverableRequest) (*MarkEmailUndeliverableResponse, error) {
+	now := uc.clock.Now()
+	// Chaque retour compte pour l'alerte, même pour une adresse inconnue ou déjà rejetée
+	uc.monitor.Observe(ctx, req.Reason, now)
+
+	user, err := uc.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return &MarkEmailUndeliverableResponse{Status: "unknown_email"}, nil
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la recherche de l'utilisateur", err)
+	}
+
+	if !user.MarkEmailUndeliverable(req.Reason, now) {
+		return &MarkEmailUndeliverableResponse{UserID: user.ID, Status: "already_marked"}, nil
+	}
+	if _, err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, newError("erreur lors du rejet de l'adresse", err)
+	}
+
+	uc.publisher.Publish(ctx, events.UserEmailUndeliverable{
+		UserID: user.ID,
+		Email:  user.Email,
+		Reason: req.Reason,
+		Marked: now,
+	})
+
+	return &MarkEmailUndeliverableResponse{UserID: user.ID, Status: "marked"}, nil
+}
+
+// =============================================================================
+// ALERTE SUR LES PICS DE REBONDS ET DE PLAINTES
+// =============================================================================
+
+// EmailFeedbackMonitor compte les retours par motif sur des fenêtres fixes ; le premier retour qui
+// atteint threshold dans une fenêtre déclenche une alerte (journal et ErrorReporter), une seule fois
+// par fenêtre et par motif. Un pic signale une liste d'adresses dégradée ou un envoi jugé abusif,
+// qui menacent la réputation du domaine d'envoi. threshold <= 0 désactive l'alerte
+type EmailFeedbackMonitor struct {
+	threshold int
+	window    time.Duration
+	metrics   EmailFeedbackMetrics
+	reporter  ErrorReporter
+	logger    Logger
+
+	mu      sync.Mutex
+	started time.Time
+	counts  map[string]int
+}
+
+func NewEmailFeedbackMonitor(threshold int, window time.Duration, metrics EmailFeedbackMetrics, reporter ErrorReporter, logger Logger) *EmailFeedbackMonitor {
+	return &EmailFeedbackMonitor{
+		threshold: threshold,
+		window:    window,
+		metrics:   metrics,
+		reporter:  reporter,
+		logger:    logger,
+		counts:    make(map[string]int),
+	}
+}
+
+func (m *EmailFeedbackMonitor) Observe(ctx context.Context, reason string, at time.Time) {
+	m.metrics.ObserveEmailFeedback(reason)
+	if m.threshold <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	if m.started.IsZero() || !at.Before(m.started.Add(m.window)) {
+		m.started = at.Truncate(m.window)
+		m.counts = make(map[string]int)
+	}
+	m.counts[reason]++
+	spike := m.counts[reason] == m.threshold
+	started := m.started
+	m.mu.Unlock()
+
+	if !spike {
+		return
+	}
+	fields := map[string]interface{}{
+		"reason":       reason,
+		"count":        m.threshold,
+		"window_start": started,
+		"window":       m.window.String(),
+	}
+	m.logger.Warn("Email feedback spike", fields)
+	m.reporter.Report(ctx, fmt.Errorf("pic de retours email : %d %s depuis %s", m.threshold, reason, started.Format(time.RFC3339)), nil, fields)
+}
+
+// =============================================================================
+// SUPPRESSION DES ENVOIS
+// =============================================================================
+
+// SuppressingEmailSender décore un EmailSender : un email vers une adresse rejetée par le fournisseur
+// n'est pas envoyé et l'appel réussit (l'outbox ne le renverra pas). Les adresses inconnues
+// (administrateurs, invités) sont envoyées normalement
+type SuppressingEmailSender struct {
+	next     EmailSender
+	userRepo repositories.UserRepository
+	metrics  EmailFeedbackMetrics
+	logger   Logger
+}
+
+func NewSuppressingEmailSender(next EmailSender, userRepo repositories.UserRepository, metrics EmailFeedbackMetrics, logger Logger) *SuppressingEmailSender {
+	return &SuppressingEmailSender{next: next, userRepo: userRepo, metrics: metrics, logger: logger}
+}
+
+func (s *SuppressingEmailSender) SendWelcomeEmail(ctx context.Context, email, name string) error {
+	if s.suppressed(ctx, email) {
+		return nil
+	}
+	return s.next.SendWelcomeEmail(ctx, email, name)
+}
+
+func (s *SuppressingEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	if s.suppressed(ctx, to) {
+		return nil
+	}
+	return s.next.SendEmail(ctx, to, subject, body)
+}
+
+func (s *SuppressingEmailSender) Send(ctx context.Context, mail Mail) error {
+	if s.suppressed(ctx, mail.To) {
+		return nil
+	}
+	return s.next.Send(ctx, mail)
+}
+
+// suppressed une erreur de lecture laisse passer l'envoi : mieux vaut un rebond de plus qu'un email perdu
+func (s *SuppressingEmailSender) suppressed(ctx context.Context, to string) bool {
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(to)))
+	if err != nil {
+		if !errors.Is(err, repositories.ErrUserNotFound) {
+			s.logger.Error("Failed to check email suppression", err, nil)
+		}
+		return false
+	}
+	if user.CanReceiveEmail() {
+		return false
+	}
+	s.metrics.ObserveSuppressedEmail()
+	s.logger.Info("Email suppressed", map[string]interface{}{
+		"user_id": user.ID,
+		"reason":  user.EmailUndeliverableReason,
+	})
+	return true
+}
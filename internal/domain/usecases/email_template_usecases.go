@@ -0,0 +1,323 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// =============================================================================
+// MODÈLES D'EMAILS : versions par tenant et par locale, exécution restreinte
+// =============================================================================
+
+// EmailTemplateEngine compile et exécute les modèles
+//   - Check refuse un modèle qui référence autre chose que les variables autorisées
+//     (champs imbriqués, appels de méthodes ou de call, inclusions, boucles)
+//   - Render ne reçoit que les variables autorisées ; le HTML est échappé selon son contexte
+type EmailTemplateEngine interface {
+	Check(tpl entities.EmailTemplate, allowed []string) error
+	Render(tpl entities.EmailTemplate, data map[string]string) (*RenderedEmail, error)
+}
+
+// RenderedEmail modèle exécuté, prêt à être envoyé
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html,omitempty"`
+}
+
+// EmailTemplates résout le modèle applicable et l'exécute. Ordre de résolution, à la première
+// lignée qui a une version : tenant puis défaut de l'instance, et pour chacun la locale exacte,
+// sa langue ("fr-CA" → "fr"), puis le modèle sans locale
+type EmailTemplates struct {
+	repo   repositories.EmailTemplateRepository
+	engine EmailTemplateEngine
+}
+
+func NewEmailTemplates(repo repositories.EmailTemplateRepository, engine EmailTemplateEngine) *EmailTemplates {
+	return &EmailTemplates{repo: repo, engine: engine}
+}
+
+// Resolve ErrEmailTemplateNotFound si aucune lignée ne correspond
+func (t *EmailTemplates) Resolve(ctx context.Context, name, tenantID, locale string) (*entities.EmailTemplate, error) {
+	tenants := []string{tenantID}
+	if tenantID != "" {
+		tenants = append(tenants, "")
+	}
+	for _, tenant := range tenants {
+		for _, candidate := range entities.LocaleFallbacks(locale) {
+			tpl, err := t.repo.GetLatest(ctx, entities.EmailTemplateKey{Name: name, TenantID: tenant, Locale: candidate})
+			if errors.Is(err, repositories.ErrEmailTemplateNotFound) {
+				continue
+			}
+			return tpl, err
+		}
+	}
+	return nil, repositories.ErrEmailTemplateNotFound
+}
+
+// Render les données hors des variables autorisées du modèle sont écartées, les absentes valent ""
+func (t *EmailTemplates) Render(tpl entities.EmailTemplate, data map[string]string) (*RenderedEmail, error) {
+	allowed, ok := entities.EmailTemplateVariables(tpl.Name)
+	if !ok {
+		return nil, entities.ErrUnknownEmailTemplate
+	}
+	values := make(map[string]string, len(allowed))
+	for _, variable := range allowed {
+		values[variable] = data[variable]
+	}
+	rendered, err := t.engine.Render(tpl, values)
+	if err != nil {
+		return nil, fmt.Errorf("modèle %s v%d : %w", tpl.Name, tpl.Version, err)
+	}
+	return rendered, nil
+}
+
+// EmailTemplateResponse DTO d'une version
+type EmailTemplateResponse struct {
+	entities.EmailTemplate
+	Variables []string `json:"variables"`
+}
+
+func newEmailTemplateResponse(tpl *entities.EmailTemplate) *EmailTemplateResponse {
+	variables, _ := entities.EmailTemplateVariables(tpl.Name)
+	return &EmailTemplateResponse{EmailTemplate: *tpl, Variables: variables}
+}
+
+// =============================================================================
+// SAVE EMAIL TEMPLATE USE CASE
+// =============================================================================
+
+type SaveEmailTemplateUseCase struct {
+	repo   repositories.EmailTemplateRepository
+	engine EmailTemplateEngine
+	clock  Clock
+}
+
+func NewSaveEmailTemplateUseCase(repo repositories.EmailTemplateRepository, engine EmailTemplateEngine, clock Clock) *SaveEmailTemplateUseCase {
+	return &SaveEmailTemplateUseCase{repo: repo, engine: engine, clock: clock}
+}
+
+// SaveEmailTemplateRequest TenantID vide = modèle par défaut de l'instance, Locale vide = toutes locales
+type SaveEmailTemplateRequest struct {
+	Name     string `json:"-"`
+	TenantID string `json:"tenant_id"`
+	Locale   string `json:"locale"`
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	HTML     string `json:"html"`
+}
+
+func (req SaveEmailTemplateRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (req SaveEmailTemplateRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"template": req.Name, "tenant_id": req.TenantID, "locale": req.Locale}
+}
+
+func (uc *SaveEmailTemplateUseCase) Execute(ctx context.Context, req SaveEmailTemplateRequest) (*EmailTemplateResponse, error) {
+	tpl, err := entities.NewEmailTemplate(entities.EmailTemplateKey{Name: req.Name, TenantID: req.TenantID, Locale: req.Locale},
+		req.Subject, req.Text, req.HTML, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Un modèle invalide est refusé à l'enregistrement, pas à l'envoi
+	allowed, _ := entities.EmailTemplateVariables(tpl.Name)
+	if err := uc.engine.Check(*tpl, allowed); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Save(ctx, tpl); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du modèle", err)
+	}
+	return newEmailTemplateResponse(tpl), nil
+}
+
+// =============================================================================
+// LIST EMAIL TEMPLATE VERSIONS USE CASE
+// =============================================================================
+
+type ListEmailTemplateVersionsUseCase struct {
+	repo repositories.EmailTemplateRepository
+}
+
+func NewListEmailTemplateVersionsUseCase(repo repositories.EmailTemplateRepository) *ListEmailTemplateVersionsUseCase {
+	return &ListEmailTemplateVersionsUseCase{repo: repo}
+}
+
+type ListEmailTemplateVersionsRequest struct {
+	entities.EmailTemplateKey
+}
+
+func (req ListEmailTemplateVersionsRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+// ListEmailTemplateVersionsResponse versions de la plus récente à la plus ancienne
+type ListEmailTemplateVersionsResponse struct {
+	Versions []*EmailTemplateResponse `json:"versions"`
+}
+
+func (uc *ListEmailTemplateVersionsUseCase) Execute(ctx context.Context, req ListEmailTemplateVersionsRequest) (*ListEmailTemplateVersionsResponse, error) {
+	if _, ok := entities.EmailTemplateVariables(req.Name); !ok {
+		return nil, entities.ErrUnknownEmailTemplate
+	}
+	locale, err := entities.NormalizeLocale(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	req.Locale = locale
+
+	versions, err := uc.repo.ListVersions(ctx, req.EmailTemplateKey)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des versions", err)
+	}
+	response := &ListEmailTemplateVersionsResponse{Versions: make([]*EmailTemplateResponse, 0, len(versions))}
+	for i := len(versions) - 1; i >= 0; i-- {
+		response.Versions = append(response.Versions, newEmailTemplateResponse(versions[i]))
+	}
+	return response, nil
+}
+
+// =============================================================================
+// PREVIEW EMAIL TEMPLATE USE CASE
+// =============================================================================
+
+// PreviewEmailTemplateUseCase rend un modèle avec des données d'exemple, sans rien envoyer :
+// un brouillon (vérifié comme à l'enregistrement), une version précise, ou le modèle que
+// recevrait un destinataire du tenant dans cette locale
+type PreviewEmailTemplateUseCase struct {
+	templates *EmailTemplates
+	repo      repositories.EmailTemplateRepository
+	engine    EmailTemplateEngine
+	clock     Clock
+}
+
+func NewPreviewEmailTemplateUseCase(templates *EmailTemplates, repo repositories.EmailTemplateRepository, engine EmailTemplateEngine, clock Clock) *PreviewEmailTemplateUseCase {
+	return &PreviewEmailTemplateUseCase{templates: templates, repo: repo, engine: engine, clock: clock}
+}
+
+type PreviewEmailTemplateRequest struct {
+	Name     string `json:"-"`
+	TenantID string `json:"tenant_id"`
+	Locale   string `json:"locale"`
+	// Version version précise de la lignée (TenantID, Locale) ; 0 = résolution comme à l'envoi
+	Version int `json:"version"`
+	// Draft contenu non enregistré ; prioritaire sur Version
+	Draft *RenderedEmail    `json:"draft"`
+	Data  map[string]string `json:"data"`
+}
+
+func (req PreviewEmailTemplateRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (req PreviewEmailTemplateRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"template": req.Name, "tenant_id": req.TenantID, "locale": req.Locale, "version": req.Version}
+}
+
+// PreviewEmailTemplateResponse Source lignée et version rendues (Version 0 pour un brouillon)
+type PreviewEmailTemplateResponse struct {
+	Source   entities.EmailTemplateKey `json:"source"`
+	Version  int                       `json:"version"`
+	Rendered *RenderedEmail            `json:"rendered"`
+}
+
+func (uc *PreviewEmailTemplateUseCase) Execute(ctx context.Context, req PreviewEmailTemplateRequest) (*PreviewEmailTemplateResponse, error) {
+	if _, ok := entities.EmailTemplateVariables(req.Name); !ok {
+		return nil, entities.ErrUnknownEmailTemplate
+	}
+	locale, err := entities.NormalizeLocale(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	key := entities.EmailTemplateKey{Name: req.Name, TenantID: req.TenantID, Locale: locale}
+
+	tpl, err := uc.load(ctx, key, req)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := uc.templates.Render(*tpl, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &PreviewEmailTemplateResponse{Source: tpl.EmailTemplateKey, Version: tpl.Version, Rendered: rendered}, nil
+}
+
+func (uc *PreviewEmailTemplateUseCase) load(ctx context.Context, key entities.EmailTemplateKey, req PreviewEmailTemplateRequest) (*entities.EmailTemplate, error) {
+	switch {
+	case req.Draft != nil:
+		draft, err := entities.NewEmailTemplate(key, req.Draft.Subject, req.Draft.Text, req.Draft.HTML, uc.clock.Now())
+		if err != nil {
+			return nil, err
+		}
+		allowed, _ := entities.EmailTemplateVariables(key.Name)
+		if err := uc.engine.Check(*draft, allowed); err != nil {
+			return nil, err
+		}
+		return draft, nil
+	case req.Version > 0:
+		return uc.repo.GetVersion(ctx, key, req.Version)
+	default:
+		return uc.templates.Resolve(ctx, key.Name, key.TenantID, key.Locale)
+	}
+}
+
+// =============================================================================
+// ENVOI
+// =============================================================================
+
+// TemplatedEmailSender décore un EmailSender : l'email de bienvenue utilise le modèle "welcome"
+// du tenant de l'acteur et de la locale de la requête (LocaleFromContext) quand il en existe un ;
+// sinon, et si le modèle échoue à l'exécution, l'email par défaut de next est envoyé
+type TemplatedEmailSender struct {
+	next      EmailSender
+	templates *EmailTemplates
+	logger    Logger
+}
+
+func NewTemplatedEmailSender(next EmailSender, templates *EmailTemplates, logger Logger) *TemplatedEmailSender {
+	return &TemplatedEmailSender{next: next, templates: templates, logger: logger}
+}
+
+func (s *TemplatedEmailSender) SendWelcomeEmail(ctx context.Context, email, name string) error {
+	actor, _ := ActorFromContext(ctx)
+	tpl, err := s.templates.Resolve(ctx, entities.EmailTemplateWelcome, actor.TenantID, LocaleFromContext(ctx))
+	if err != nil {
+		if !errors.Is(err, repositories.ErrEmailTemplateNotFound) {
+			s.logger.Error("Failed to resolve email template", err, map[string]interface{}{"template": entities.EmailTemplateWelcome})
+		}
+		return s.next.SendWelcomeEmail(ctx, email, name)
+	}
+
+	rendered, err := s.templates.Render(*tpl, map[string]string{"name": name, "email": email})
+	if err != nil {
+		s.logger.Error("Failed to render email template", err, map[string]interface{}{
+			"template":  tpl.Name,
+			"tenant_id": tpl.TenantID,
+			"locale":    tpl.Locale,
+			"version":   tpl.Version,
+		})
+		return s.next.SendWelcomeEmail(ctx, email, name)
+	}
+
+	return s.next.Send(ctx, Mail{
+		To:         email,
+		Subject:    rendered.Subject,
+		Text:       rendered.Text,
+		HTML:       rendered.HTML,
+		Categories: []string{MailCategoryWelcome},
+	})
+}
+
+func (s *TemplatedEmailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	return s.next.SendEmail(ctx, to, subject, body)
+}
+
+func (s *TemplatedEmailSender) Send(ctx context.Context, mail Mail) error {
+	return s.next.Send(ctx, mail)
+}
@@ -0,0 +1,357 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// =============================================================================
+// ARCHIVAGE À FROID DES ÉVÉNEMENTS ANALYTICS
+// =============================================================================
+
+// EventArchiveFormat encodage d'un fichier d'archive (services.NDJSONEventFormat)
+type EventArchiveFormat interface {
+	// Name nom du format (EVENT_ARCHIVE_FORMAT) et extension des fichiers
+	Name() string
+	Encode(w io.Writer, events []*entities.TrackedEvent) error
+	Decode(r io.Reader) ([]*entities.TrackedEvent, error)
+}
+
+// maxRestoreDays jours restaurables en une commande
+const maxRestoreDays = 366
+
+// EventArchive emplacement des archives dans le FileStorage, sans index à part : les clés se
+// déduisent du jour, events-AAAA-MM-JJ-<n>.<format> (n à partir de 1, un fichier par lot, les
+// événements arrivés en retard pour un jour déjà archivé forment de nouveaux fichiers). Un jour
+// restauré porte un marqueur events-AAAA-MM-JJ.hold jusqu'auquel il n'est pas réarchivé
+// Le FileStorage doit signaler un fichier absent par fs.ErrNotExist (LocalFileStorage, S3FileStorage)
+type EventArchive struct {
+	storage FileStorage
+	// formats le premier écrit les nouvelles archives, tous sont lus à la restauration
+	formats []EventArchiveFormat
+}
+
+func NewEventArchive(storage FileStorage, format EventArchiveFormat, others ...EventArchiveFormat) *EventArchive {
+	return &EventArchive{storage: storage, formats: append([]EventArchiveFormat{format}, others...)}
+}
+
+func archiveDayPrefix(day time.Time) string {
+	return "events-" + day.UTC().Format(time.DateOnly)
+}
+
+func archivePartKey(day time.Time, part int, format EventArchiveFormat) string {
+	return fmt.Sprintf("%s-%d.%s", archiveDayPrefix(day), part, format.Name())
+}
+
+func archiveHoldKey(day time.Time) string {
+	return archiveDayPrefix(day) + ".hold"
+}
+
+// exists false si le fichier key est absent
+func (a *EventArchive) exists(ctx context.Context, key string) (bool, error) {
+	file, err := a.storage.Open(ctx, key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, file.Close()
+}
+
+// parts fichiers existants du jour, dans l'ordre, avec leur format
+func (a *EventArchive) parts(ctx context.Context, day time.Time) ([]string, []EventArchiveFormat, error) {
+	var keys []string
+	var formats []EventArchiveFormat
+	for part := 1; ; part++ {
+		found := false
+		for _, format := range a.formats {
+			key := archivePartKey(day, part, format)
+			ok, err := a.exists(ctx, key)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ok {
+				keys, formats, found = append(keys, key), append(formats, format), true
+				break
+			}
+		}
+		if !found {
+			return keys, formats, nil
+		}
+	}
+}
+
+// heldUntil fin du maintien en stockage chaud d'un jour restauré (zéro sans marqueur)
+func (a *EventArchive) heldUntil(ctx context.Context, day time.Time) (time.Time, error) {
+	file, err := a.storage.Open(ctx, archiveHoldKey(day))
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, 64))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, string(content))
+}
+
+func (a *EventArchive) hold(ctx context.Context, day, until time.Time) error {
+	return a.storage.Write(ctx, archiveHoldKey(day), func(w io.Writer) error {
+		_, err := io.WriteString(w, until.UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// =============================================================================
+// ARCHIVE EVENTS USE CASE
+// =============================================================================
+
+// ArchiveEventsUseCase déplace les événements plus anciens que after (jours UTC entiers) du
+// stockage chaud vers les archives : chaque lot est écrit puis supprimé du stockage, un lot
+// interrompu entre les deux est réécrit à l'exécution suivante (doublon possible, jamais de perte)
+// Les agrégats quotidiens ne sont pas touchés : ils restent interrogeables sur toute la période
+type ArchiveEventsUseCase struct {
+	archive   *EventArchive
+	eventRepo repositories.EventRepository
+	purger    repositories.EventPurger
+	after     time.Duration
+	batchSize int
+	maxDays   int
+	clock     Clock
+	logger    Logger
+}
+
+// NewArchiveEventsUseCase eventRepo et purger : le stockage lui-même, sans tampon ni décorateurs
+func NewArchiveEventsUseCase(
+	archive *EventArchive,
+	eventRepo repositories.EventRepository,
+	purger repositories.EventPurger,
+	after time.Duration,
+	batchSize int,
+	maxDays int,
+	clock Clock,
+	logger Logger,
+) *ArchiveEventsUseCase {
+	return &ArchiveEventsUseCase{
+		archive:   archive,
+		eventRepo: eventRepo,
+		purger:    purger,
+		after:     after,
+		batchSize: batchSize,
+		maxDays:   maxDays,
+		clock:     clock,
+		logger:    logger,
+	}
+}
+
+// ArchiveEventsRequest Now zéro = l'horloge du use case
+type ArchiveEventsRequest struct {
+	Now time.Time
+}
+
+// ArchiveEventsResponse Complete : plus aucun jour à archiver à la fin de l'exécution (hors jours
+// maintenus après une restauration, comptés dans Held)
+type ArchiveEventsResponse struct {
+	Before   time.Time `json:"before"`
+	Days     int       `json:"days"`
+	Files    int       `json:"files"`
+	Events   int       `json:"events"`
+	Held     int       `json:"held"`
+	Complete bool      `json:"complete"`
+}
+
+func (uc *ArchiveEventsUseCase) Execute(ctx context.Context, req ArchiveEventsRequest) (*ArchiveEventsResponse, error) {
+	if req.Now.IsZero() {
+		req.Now = uc.clock.Now()
+	}
+	response := &ArchiveEventsResponse{Before: req.Now.Add(-uc.after).UTC().Truncate(24 * time.Hour)}
+	var from time.Time
+	for response.Days < uc.maxDays {
+		oldest, err := uc.eventRepo.List(ctx, repositories.EventFilters{From: from, To: response.Before, Limit: 1})
+		if err != nil {
+			return response, newError("erreur lors de la lecture des événements", err)
+		}
+		if len(oldest) == 0 {
+			response.Complete = true
+			break
+		}
+		day := oldest[0].OccurredAt.UTC().Truncate(24 * time.Hour)
+		from = day.AddDate(0, 0, 1)
+
+		until, err := uc.archive.heldUntil(ctx, day)
+		if err != nil {
+			return response, newError("erreur lors de la lecture des archives", err)
+		}
+		if until.After(req.Now) {
+			response.Held++
+			continue
+		}
+		files, archived, err := uc.archiveDay(ctx, day)
+		response.Files += files
+		response.Events += archived
+		if err != nil {
+			return response, newError("erreur lors de l'archivage du "+day.Format(time.DateOnly), err)
+		}
+		if !until.IsZero() {
+			if err := uc.archive.storage.Delete(ctx, archiveHoldKey(day)); err != nil {
+				return response, newError("erreur lors de l'archivage du "+day.Format(time.DateOnly), err)
+			}
+		}
+		response.Days++
+	}
+
+	if response.Events > 0 {
+		uc.logger.Info("Events archived", map[string]interface{}{
+			"before": response.Before, "days": response.Days, "files": response.Files,
+			"events": response.Events, "held": response.Held, "complete": response.Complete,
+		})
+	}
+	return response, nil
+}
+
+// archiveDay un fichier par lot, à la suite des fichiers déjà présents pour ce jour
+func (uc *ArchiveEventsUseCase) archiveDay(ctx context.Context, day time.Time) (int, int, error) {
+	existing, _, err := uc.archive.parts(ctx, day)
+	if err != nil {
+		return 0, 0, err
+	}
+	format := uc.archive.formats[0]
+	files, archived := 0, 0
+	for part := len(existing) + 1; ; part++ {
+		batch, err := uc.eventRepo.List(ctx, repositories.EventFilters{From: day, To: day.AddDate(0, 0, 1), Limit: uc.batchSize})
+		if err != nil || len(batch) == 0 {
+			return files, archived, err
+		}
+		err = uc.archive.storage.Write(ctx, archivePartKey(day, part, format), func(w io.Writer) error {
+			return format.Encode(w, batch)
+		})
+		if err != nil {
+			return files, archived, err
+		}
+		files++
+		ids := make([]int64, len(batch))
+		for i, event := range batch {
+			ids[i] = event.ID
+		}
+		deleted, err := uc.purger.DeleteByIDs(ctx, ids)
+		archived += deleted
+		if err != nil {
+			return files, archived, err
+		}
+		// Lecture et suppression hors du même stockage : le lot serait réarchivé indéfiniment
+		if deleted == 0 {
+			return files, archived, errors.New("aucun événement archivé supprimé du stockage")
+		}
+	}
+}
+
+// =============================================================================
+// RESTORE EVENTS USE CASE
+// =============================================================================
+
+// RestoreEventsUseCase recharge dans le stockage chaud les archives des jours [From, To) puis les
+// supprime ; chaque jour restauré reste en stockage chaud pendant hold (EVENT_ARCHIVE_RESTORE_HOLD)
+// avant d'être réarchivé. Les événements reçoivent de nouveaux identifiants
+type RestoreEventsUseCase struct {
+	archive   *EventArchive
+	eventRepo repositories.EventRepository
+	hold      time.Duration
+	clock     Clock
+	logger    Logger
+}
+
+// NewRestoreEventsUseCase eventRepo : le stockage lui-même, les événements archivés ont déjà été
+// nettoyés et comptés dans les agrégats
+func NewRestoreEventsUseCase(archive *EventArchive, eventRepo repositories.EventRepository, hold time.Duration, clock Clock, logger Logger) *RestoreEventsUseCase {
+	return &RestoreEventsUseCase{archive: archive, eventRepo: eventRepo, hold: hold, clock: clock, logger: logger}
+}
+
+// RestoreEventsRequest jours UTC, To exclu
+type RestoreEventsRequest struct {
+	From time.Time
+	To   time.Time
+}
+
+func (req RestoreEventsRequest) Validate() error {
+	if req.From.IsZero() || req.To.IsZero() {
+		return errors.New("dates de début et de fin obligatoires")
+	}
+	if !req.From.Before(req.To) {
+		return errors.New("la date de fin doit suivre la date de début")
+	}
+	if req.To.Sub(req.From) > maxRestoreDays*24*time.Hour {
+		return fmt.Errorf("%d jours au plus par restauration", maxRestoreDays)
+	}
+	return nil
+}
+
+func (req RestoreEventsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"from": req.From, "to": req.To}
+}
+
+type RestoreEventsResponse struct {
+	Days      int       `json:"days"`
+	Files     int       `json:"files"`
+	Events    int       `json:"events"`
+	HeldUntil time.Time `json:"held_until"`
+}
+
+func (uc *RestoreEventsUseCase) Execute(ctx context.Context, req RestoreEventsRequest) (*RestoreEventsResponse, error) {
+	response := &RestoreEventsResponse{HeldUntil: uc.clock.Now().Add(uc.hold).UTC()}
+	for day := req.From.UTC().Truncate(24 * time.Hour); day.Before(req.To); day = day.AddDate(0, 0, 1) {
+		keys, formats, err := uc.archive.parts(ctx, day)
+		if err != nil {
+			return response, newError("erreur lors de la lecture des archives", err)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		// Marqueur posé avant le rechargement : l'archivage ne reprend pas le jour en cours de route
+		if err := uc.archive.hold(ctx, day, response.HeldUntil); err != nil {
+			return response, newError("erreur lors de la restauration du "+day.Format(time.DateOnly), err)
+		}
+		for i, key := range keys {
+			restored, err := uc.restore(ctx, key, formats[i])
+			response.Events += restored
+			if err != nil {
+				return response, newError("erreur lors de la restauration de "+key, err)
+			}
+			response.Files++
+		}
+		response.Days++
+	}
+
+	uc.logger.Info("Events restored", map[string]interface{}{
+		"from": req.From, "to": req.To, "days": response.Days, "files": response.Files,
+		"events": response.Events, "held_until": response.HeldUntil,
+	})
+	return response, nil
+}
+
+// restore recharge un fichier puis le supprime : rejouée après une interruption entre les deux,
+// la restauration recharge le fichier une seconde fois
+func (uc *RestoreEventsUseCase) restore(ctx context.Context, key string, format EventArchiveFormat) (int, error) {
+	file, err := uc.archive.storage.Open(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	batch, err := format.Decode(file)
+	_ = file.Close()
+	if err != nil {
+		return 0, err
+	}
+	if err := uc.eventRepo.Append(ctx, batch); err != nil {
+		return 0, err
+	}
+	return len(batch), uc.archive.storage.Delete(ctx, key)
+}
@@ -0,0 +1,387 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// FILTRES D'ÉVÉNEMENTS : expressions compilées en repositories.EventCondition
+// =============================================================================
+
+// ErrInvalidEventFilter expression mal formée, ou comparaison non applicable au champ
+var ErrInvalidEventFilter = errors.New("filtre d'événements invalide")
+
+const (
+	eventPropertyField = "props."
+	// Garde-fous : une expression est fournie par le client
+	eventFilterMaxLength = 2000
+	eventFilterMaxDepth  = 16
+	eventFilterMaxValues = 100
+)
+
+// eventFilterOperators opérateurs de comparaison acceptés ; le découpage en produit d'autres (==)
+var eventFilterOperators = []string{
+	repositories.EventOpEq, "!=", repositories.EventOpLt, repositories.EventOpLe, repositories.EventOpGt, repositories.EventOpGe,
+}
+
+// ParseEventFilter compile une expression ; vide = nil (aucun filtre). Les erreurs enveloppent
+// ErrInvalidEventFilter
+//
+//	event = "signup" AND props.plan IN ("pro", "team")
+//	NOT (user_id = 42) OR props.amount >= 100 AND props.trial = true
+//	props.coupon EXISTS AND occurred_at >= "2026-01-01T00:00:00Z"
+//
+// Champs : event, user_id, occurred_at, received_at, props.<clé>. Opérateurs : = != < <= > >=,
+// IN (...), NOT IN (...), EXISTS ; AND prioritaire sur OR, NOT, parenthèses. Mots-clés insensibles
+// à la casse, chaînes entre guillemets (échappements JSON). != et NOT IN retiennent aussi les
+// événements sans la valeur (anonymes, propriété absente)
+func ParseEventFilter(raw string) (repositories.EventCondition, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	if len(raw) > eventFilterMaxLength {
+		return nil, fmt.Errorf("%w : expression trop longue (%d caractères max)", ErrInvalidEventFilter, eventFilterMaxLength)
+	}
+	tokens, err := tokenizeEventFilter(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidEventFilter, err)
+	}
+	parser := &eventFilterParser{tokens: tokens}
+	condition, err := parser.parseOr(0)
+	if err == nil && parser.pos < len(parser.tokens) {
+		err = fmt.Errorf("élément inattendu %q", parser.tokens[parser.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidEventFilter, err)
+	}
+	return condition, nil
+}
+
+// =============================================================================
+// ANALYSE
+// =============================================================================
+
+type eventFilterTokenKind int
+
+const (
+	eventFilterWord eventFilterTokenKind = iota
+	eventFilterString
+	eventFilterOperator
+	eventFilterPunct
+)
+
+type eventFilterToken struct {
+	kind eventFilterTokenKind
+	text string
+}
+
+func tokenizeEventFilter(raw string) ([]eventFilterToken, error) {
+	var tokens []eventFilterToken
+	for i := 0; i < len(raw); {
+		switch c := raw[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, eventFilterToken{eventFilterPunct, string(c)})
+			i++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			end := i + 1
+			if end < len(raw) && raw[end] == '=' {
+				end++
+			}
+			if raw[i:end] == "!" {
+				return nil, errors.New("opérateur ! inconnu (!= attendu)")
+			}
+			tokens = append(tokens, eventFilterToken{eventFilterOperator, raw[i:end]})
+			i = end
+		case c == '"':
+			// Même lecture que les filtres SCIM : fin de chaîne repérée, décodage par json
+			end := i + 1
+			for end < len(raw) && raw[end] != '"' {
+				if raw[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(raw) {
+				return nil, errors.New("chaîne non terminée")
+			}
+			var text string
+			if err := json.Unmarshal([]byte(raw[i:end+1]), &text); err != nil {
+				return nil, errors.New("chaîne invalide")
+			}
+			tokens = append(tokens, eventFilterToken{eventFilterString, text})
+			i = end + 1
+		default:
+			end := i
+			for end < len(raw) && !strings.ContainsRune(" \t\n\r(),=!<>\"", rune(raw[end])) {
+				end++
+			}
+			tokens = append(tokens, eventFilterToken{eventFilterWord, raw[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type eventFilterParser struct {
+	tokens []eventFilterToken
+	pos    int
+}
+
+func (p *eventFilterParser) peek() (eventFilterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return eventFilterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *eventFilterParser) peekKeyword(keyword string) bool {
+	token, ok := p.peek()
+	return ok && token.kind == eventFilterWord && strings.EqualFold(token.text, keyword)
+}
+
+func (p *eventFilterParser) peekPunct(punct string) bool {
+	token, ok := p.peek()
+	return ok && token.kind == eventFilterPunct && token.text == punct
+}
+
+func (p *eventFilterParser) expect(punct string) error {
+	if !p.peekPunct(punct) {
+		return fmt.Errorf("%q attendu", punct)
+	}
+	p.pos++
+	return nil
+}
+
+// parseOr AND est prioritaire sur OR
+func (p *eventFilterParser) parseOr(depth int) (repositories.EventCondition, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = repositories.EventOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *eventFilterParser) parseAnd(depth int) (repositories.EventCondition, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = repositories.EventAnd{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *eventFilterParser) parseUnary(depth int) (repositories.EventCondition, error) {
+	if depth > eventFilterMaxDepth {
+		return nil, errors.New("expression trop imbriquée")
+	}
+	token, ok := p.peek()
+	if !ok {
+		return nil, errors.New("expression attendue")
+	}
+	if p.peekKeyword("not") {
+		p.pos++
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return repositories.EventNot{Operand: operand}, nil
+	}
+	if p.peekPunct("(") {
+		p.pos++
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+	if token.kind != eventFilterWord {
+		return nil, fmt.Errorf("champ attendu, %q trouvé", token.text)
+	}
+	p.pos++
+	return p.parseComparison(token.text)
+}
+
+// parseComparison champ déjà lu : opérateur puis valeur(s), vérifiées selon le champ
+func (p *eventFilterParser) parseComparison(field string) (repositories.EventCondition, error) {
+	comparison := repositories.EventComparison{Field: field}
+	if key, ok := strings.CutPrefix(field, eventPropertyField); ok {
+		if !entities.IsEventPropertyKey(key) {
+			return nil, fmt.Errorf("propriété %q invalide", key)
+		}
+		comparison.Field, comparison.Property = "", key
+	} else {
+		switch field {
+		case repositories.EventFieldName, repositories.EventFieldUserID,
+			repositories.EventFieldOccurredAt, repositories.EventFieldReceivedAt:
+		default:
+			return nil, fmt.Errorf("champ %q inconnu (event, user_id, occurred_at, received_at ou props.<clé>)", field)
+		}
+	}
+
+	negate := false
+	token, ok := p.peek()
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("opérateur attendu après %s", field)
+	case token.kind == eventFilterOperator:
+		if !slices.Contains(eventFilterOperators, token.text) {
+			return nil, fmt.Errorf("opérateur %q inconnu", token.text)
+		}
+		p.pos++
+		comparison.Op, negate = token.text, token.text == "!="
+		if negate {
+			comparison.Op = repositories.EventOpEq
+		}
+	case p.peekKeyword("exists"):
+		p.pos++
+		comparison.Op = repositories.EventOpExists
+	case p.peekKeyword("not"):
+		p.pos++
+		if !p.peekKeyword("in") {
+			return nil, fmt.Errorf("IN attendu après %s NOT", field)
+		}
+		p.pos++
+		comparison.Op, negate = repositories.EventOpIn, true
+	case p.peekKeyword("in"):
+		p.pos++
+		comparison.Op = repositories.EventOpIn
+	default:
+		return nil, fmt.Errorf("opérateur attendu après %s, %q trouvé", field, token.text)
+	}
+
+	switch comparison.Op {
+	case repositories.EventOpExists:
+	case repositories.EventOpIn:
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			value, err := p.parseValue(comparison)
+			if err != nil {
+				return nil, err
+			}
+			comparison.Values = append(comparison.Values, value)
+			if len(comparison.Values) > eventFilterMaxValues {
+				return nil, fmt.Errorf("IN : %d valeurs au plus", eventFilterMaxValues)
+			}
+			if !p.peekPunct(",") {
+				break
+			}
+			p.pos++
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	default:
+		value, err := p.parseValue(comparison)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = []any{value}
+	}
+	if err := checkEventComparison(comparison, field); err != nil {
+		return nil, err
+	}
+	if negate {
+		return repositories.EventNot{Operand: comparison}, nil
+	}
+	return comparison, nil
+}
+
+// parseValue littéral converti au type du champ comparé
+func (p *eventFilterParser) parseValue(comparison repositories.EventComparison) (any, error) {
+	token, ok := p.peek()
+	if !ok || (token.kind != eventFilterString && token.kind != eventFilterWord) {
+		return nil, errors.New("valeur attendue")
+	}
+	p.pos++
+
+	switch comparison.Field {
+	case repositories.EventFieldName:
+		if token.kind != eventFilterString {
+			return nil, errors.New("event attend une chaîne entre guillemets")
+		}
+		return token.text, nil
+	case repositories.EventFieldUserID:
+		userID, err := strconv.Atoi(token.text)
+		if token.kind != eventFilterWord || err != nil || userID <= 0 {
+			return nil, errors.New("user_id attend un identifiant utilisateur")
+		}
+		return userID, nil
+	case repositories.EventFieldOccurredAt, repositories.EventFieldReceivedAt:
+		at, err := time.Parse(time.RFC3339, token.text)
+		if token.kind != eventFilterString || err != nil {
+			return nil, fmt.Errorf("%s attend une date RFC 3339 entre guillemets", comparison.Field)
+		}
+		return at, nil
+	}
+
+	if token.kind == eventFilterString {
+		return token.text, nil
+	}
+	switch strings.ToLower(token.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	number, err := strconv.ParseFloat(token.text, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return nil, fmt.Errorf("valeur %q invalide : chaîne entre guillemets, nombre, true ou false", token.text)
+	}
+	return number, nil
+}
+
+// checkEventComparison opérateurs applicables au champ ou au type de la valeur
+func checkEventComparison(comparison repositories.EventComparison, field string) error {
+	ordering := comparison.Op != repositories.EventOpEq && comparison.Op != repositories.EventOpIn &&
+		comparison.Op != repositories.EventOpExists
+	switch comparison.Field {
+	case repositories.EventFieldName:
+		if ordering || comparison.Op == repositories.EventOpExists {
+			return errors.New("event se compare avec =, !=, IN ou NOT IN")
+		}
+	case repositories.EventFieldUserID:
+		if ordering {
+			return errors.New("user_id se compare avec =, !=, IN, NOT IN ou EXISTS")
+		}
+	case repositories.EventFieldOccurredAt, repositories.EventFieldReceivedAt:
+		if comparison.Op == repositories.EventOpIn || comparison.Op == repositories.EventOpExists {
+			return fmt.Errorf("%s se compare avec =, !=, <, <=, > ou >=", comparison.Field)
+		}
+	default:
+		if !ordering {
+			return nil
+		}
+		if _, ok := comparison.Values[0].(bool); ok {
+			return fmt.Errorf("%s : un booléen se compare avec = ou !=", field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describeEventCondition forme canonique d'une condition, entièrement parenthésée : elle fixe
+// la structure (précédence, NOT) et les types des valeurs, et reste une expression valide
+func describeEventCondition(condition repositories.EventCondition) string {
+	switch typed := condition.(type) {
+	case repositories.EventAnd:
+		return "(" + describeEventCondition(typed.Left) + " AND " + describeEventCondition(typed.Right) + ")"
+	case repositories.EventOr:
+		return "(" + describeEventCondition(typed.Left) + " OR " + describeEventCondition(typed.Right) + ")"
+	case repositories.EventNot:
+		return "NOT (" + describeEventCondition(typed.Operand) + ")"
+	case repositories.EventComparison:
+		field := typed.Field
+		if field == "" {
+			field = eventPropertyField + typed.Property
+		}
+		values := make([]string, len(typed.Values))
+		for i, value := range typed.Values {
+			switch v := value.(type) {
+			case string:
+				quoted, _ := json.Marshal(v)
+				values[i] = string(quoted)
+			case int:
+				values[i] = strconv.Itoa(v)
+			case float64:
+				values[i] = strconv.FormatFloat(v, 'g', -1, 64)
+			case bool:
+				values[i] = strconv.FormatBool(v)
+			case time.Time:
+				values[i] = `"` + v.UTC().Format(time.RFC3339Nano) + `"`
+			default:
+				values[i] = fmt.Sprintf("?%T", v)
+			}
+		}
+		switch typed.Op {
+		case repositories.EventOpExists:
+			return field + " EXISTS"
+		case repositories.EventOpIn:
+			return field + " IN (" + strings.Join(values, ", ") + ")"
+		}
+		return field + " " + typed.Op + " " + strings.Join(values, ", ")
+	}
+	return fmt.Sprintf("?%T", condition)
+}
+
+func TestParseEventFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   string
+	}{
+		{"égalité", `event = "signup"`, `event = "signup"`},
+		{"AND prioritaire sur OR", `event = "a" OR user_id = 1 AND props.plan = "pro"`, `(event = "a" OR (user_id = 1 AND props.plan = "pro"))`},
+		{"AND puis OR", `event = "a" AND user_id = 1 OR props.plan = "pro"`, `((event = "a" AND user_id = 1) OR props.plan = "pro")`},
+		{"parenthèses", `(event = "a" OR user_id = 1) AND props.plan = "pro"`, `((event = "a" OR user_id = 1) AND props.plan = "pro")`},
+		{"NOT porte sur l'opérande suivant", `NOT user_id = 42 AND props.trial = true`, `(NOT (user_id = 42) AND props.trial = true)`},
+		{"NOT d'un groupe", `NOT (user_id = 42 OR user_id = 43)`, `NOT ((user_id = 42 OR user_id = 43))`},
+		{"!=", `props.plan != "free"`, `NOT (props.plan = "free")`},
+		{"IN et NOT IN", `event IN ("a", "b") AND user_id NOT IN (1, 2)`, `(event IN ("a", "b") AND NOT (user_id IN (1, 2)))`},
+		{"EXISTS", `props.coupon EXISTS`, `props.coupon EXISTS`},
+		{"mots-clés en minuscules", `not props.a exists and props.b in (1) or props.c not in (true)`, `((NOT (props.a EXISTS) AND props.b IN (1)) OR NOT (props.c IN (true)))`},
+		{"nombres", `props.amount >= 100 AND props.ratio < -1.5e-3`, `(props.amount >= 100 AND props.ratio < -0.0015)`},
+		{"date", `occurred_at >= "2026-01-01T01:00:00+01:00"`, `occurred_at >= "2026-01-01T00:00:00Z"`},
+		{"opérateurs sans espaces", `props.a>=1 AND props.b<=2`, `(props.a >= 1 AND props.b <= 2)`},
+		{"chaîne échappée", `props.note = "a \"b\" \\ é"`, `props.note = "a \"b\" \\ é"`},
+		// Mots-clés et guillemets SQL dans une chaîne : une seule valeur, rien d'autre
+		{"mots-clés dans une chaîne", `event = "x\" OR user_id = 1 OR \"y"`, `event = "x\" OR user_id = 1 OR \"y"`},
+		{"SQL dans une chaîne", `props.plan = "'; DROP TABLE tracked_events; --"`, `props.plan = "'; DROP TABLE tracked_events; --"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := ParseEventFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("expression refusée : %v", err)
+			}
+			if got := describeEventCondition(condition); got != tt.want {
+				t.Fatalf("structure %s, attendu %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, blank := range []string{"", "  \t\n"} {
+		if condition, err := ParseEventFilter(blank); condition != nil || err != nil {
+			t.Fatalf("%q : %v, %v, attendu aucun filtre", blank, condition, err)
+		}
+	}
+}
+
+func TestParseEventFilterRejects(t *testing.T) {
+	values := make([]string, eventFilterMaxValues+1)
+	for i := range values {
+		values[i] = strconv.Itoa(i + 1)
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+	}{
+		{"champ inconnu", `name = "signup"`},
+		{"colonne SQL", `properties = "x"`},
+		{"champ injecté", `user_id;DROP = 1`},
+		{"propriété sans clé", `props. = "x"`},
+		{"propriété en majuscules", `props.Plan = "pro"`},
+		{"propriété avec tiret", `props.a-b = "x"`},
+		{"propriété avec guillemet", `props.a'b = "x"`},
+		{"propriété imbriquée", `props.a.b = "x"`},
+		{"opérateur ==", `props.plan == "pro"`},
+		{"opérateur == sur une date", `occurred_at == "2026-01-01T00:00:00Z"`},
+		{"opérateur =>", `props.amount => 1`},
+		{"opérateur <>", `props.plan <> "pro"`},
+		{"opérateur !", `props.plan ! "pro"`},
+		{"opérateur LIKE", `event LIKE "sign%"`},
+		{"opérateur ~", `event ~ "x"`},
+		{"opérateur absent", `event "signup"`},
+		{"NOT sans IN", `event NOT "signup"`},
+		{"valeur absente", `event =`},
+		{"champ seul", `event`},
+		{"NOT seul", `NOT`},
+		{"OR sans opérande", `event = "a" OR`},
+		{"AND en tête", `AND event = "a"`},
+		{"deux comparaisons sans opérateur", `event = "a" user_id = 1`},
+		{"valeur collée à AND", `props.a >= 1AND props.b <= 2`},
+		{"chaîne non terminée", `event = "signup`},
+		{"échappement en fin de chaîne", `event = "signup\`},
+		{"échappement invalide", `event = "\x41"`},
+		{"IN vide", `event IN ()`},
+		{"IN sans parenthèses", `event IN "a"`},
+		{"IN virgule finale", `event IN ("a",)`},
+		{"IN non fermé", `event IN ("a"`},
+		{"IN trop long", `user_id IN (` + strings.Join(values, ", ") + `)`},
+		{"parenthèse non fermée", `(event = "a"`},
+		{"parenthèse en trop", `event = "a")`},
+		{"event non quoté", `event = signup`},
+		{"event ordonné", `event > "a"`},
+		{"event EXISTS", `event EXISTS`},
+		{"user_id quoté", `user_id = "42"`},
+		{"user_id nul", `user_id = 0`},
+		{"user_id ordonné", `user_id > 1`},
+		{"date invalide", `occurred_at >= "hier"`},
+		{"date non quotée", `occurred_at >= 2026`},
+		{"date IN", `occurred_at IN ("2026-01-01T00:00:00Z")`},
+		{"booléen ordonné", `props.trial > true`},
+		{"valeur non quotée", `props.plan = pro`},
+		{"NaN", `props.amount = NaN`},
+		{"infini", `props.amount < +Inf`},
+		{"trop long", `props.note = "` + strings.Repeat("a", eventFilterMaxLength) + `"`},
+		{"parenthèses trop imbriquées", strings.Repeat("(", eventFilterMaxDepth+1) + `event = "a"` + strings.Repeat(")", eventFilterMaxDepth+1)},
+		{"NOT trop imbriqués", strings.Repeat("NOT ", eventFilterMaxDepth+1) + `event = "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := ParseEventFilter(tt.filter)
+			if !errors.Is(err, ErrInvalidEventFilter) || condition != nil {
+				t.Fatalf("condition %v, erreur %v, attendu ErrInvalidEventFilter", condition, err)
+			}
+		})
+	}
+
+	// Limite d'imbrication atteinte sans être dépassée
+	nested := strings.Repeat("(", eventFilterMaxDepth) + `event = "a"` + strings.Repeat(")", eventFilterMaxDepth)
+	if _, err := ParseEventFilter(nested); err != nil {
+		t.Fatalf("%d niveaux refusés : %v", eventFilterMaxDepth, err)
+	}
+}
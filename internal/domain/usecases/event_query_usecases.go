@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// QUERY EVENTS USE CASE : compteurs calculés sur les événements bruts, filtrés par expression
+// =============================================================================
+
+const (
+	// eventScanBatch événements lus par page
+	eventScanBatch = 1000
+	// maxEventQueryDays période maximale d'une requête : les événements sont relus un à un,
+	// contrairement aux agrégats quotidiens
+	maxEventQueryDays = 92
+)
+
+// QueryEventsUseCase mêmes séries que GetEventRollupsUseCase, calculées sur les événements
+// stockés qui satisfont une expression de filtre (propriétés, utilisateur...)
+type QueryEventsUseCase struct {
+	eventRepo repositories.EventRepository
+	clock     Clock
+}
+
+func NewQueryEventsUseCase(eventRepo repositories.EventRepository, clock Clock) *QueryEventsUseCase {
+	return &QueryEventsUseCase{eventRepo: eventRepo, clock: clock}
+}
+
+type QueryEventsRequest struct {
+	// Where expression de filtre (ParseEventFilter) ; vide = tous les événements
+	Where string `json:"where,omitempty"`
+	// From / To bornes RFC 3339 étendues aux jours UTC entiers (To exclu) ; défaut : les 30 derniers jours
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Interval string `json:"interval,omitempty"` // day, week ou month ; défaut : day
+}
+
+// rollups période et intervalle, validés comme ceux des agrégats
+func (req QueryEventsRequest) rollups() GetEventRollupsRequest {
+	return GetEventRollupsRequest{From: req.From, To: req.To, Interval: req.Interval}
+}
+
+func (req QueryEventsRequest) Validate() error {
+	if err := req.rollups().Validate(); err != nil {
+		return err
+	}
+	if req.To != "" {
+		if _, _, err := req.period(time.Time{}); err != nil {
+			return err
+		}
+	}
+	_, err := ParseEventFilter(req.Where)
+	return err
+}
+
+// period période des agrégats, limitée à maxEventQueryDays (lecture des événements bruts)
+func (req QueryEventsRequest) period(now time.Time) (time.Time, time.Time, error) {
+	from, to, err := req.rollups().period(now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to.Sub(from) > maxEventQueryDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("période limitée à 92 jours")
+	}
+	return from, to, nil
+}
+
+func (req QueryEventsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"where": req.Where, "from": req.From, "to": req.To, "interval": req.Interval}
+}
+
+func (uc *QueryEventsUseCase) Execute(ctx context.Context, req QueryEventsRequest) (*GetEventRollupsResponse, error) {
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+	from, to, err := req.period(uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	where, err := ParseEventFilter(req.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	series := newEventSeries(from, to, req.Interval)
+	_, err = scanEvents(ctx, uc.eventRepo, repositories.EventFilters{From: from, To: to, Where: where}, func(event *entities.TrackedEvent) error {
+		series.add(rollupDay(event.OccurredAt), event.Name, 1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return series.response, nil
+}
+
+// scanEvents parcourt les événements page par page, dans l'ordre du dépôt (horodatage puis ID) : la
+// page suivante reprend au dernier horodatage vu, en sautant les événements déjà vus à cet instant
+func scanEvents(ctx context.Context, eventRepo repositories.EventRepository, filters repositories.EventFilters, fn func(event *entities.TrackedEvent) error) (int, error) {
+	count, seenAtLast := 0, 0
+	for {
+		filters.Limit = eventScanBatch + seenAtLast
+		page, err := eventRepo.List(ctx, filters)
+		if err != nil {
+			return count, newError("erreur lors de la lecture des événements", err)
+		}
+		if len(page) <= seenAtLast {
+			return count, nil
+		}
+		for _, event := range page[seenAtLast:] {
+			if err := fn(event); err != nil {
+				return count, err
+			}
+			count++
+		}
+		last := page[len(page)-1].OccurredAt
+		if !last.Equal(filters.From) {
+			seenAtLast = 0
+		}
+		for _, event := range page[seenAtLast:] {
+			if event.OccurredAt.Equal(last) {
+				seenAtLast++
+			}
+		}
+		filters.From = last
+		if len(page) < filters.Limit {
+			return count, nil
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// EVENT SCRUBBER : données personnelles retirées des événements avant stockage
+// =============================================================================
+
+// IdentifierHasher empreinte stable et irréversible d'un identifiant (services.HMACPseudonymizer)
+type IdentifierHasher interface {
+	Identifier(value string) string
+}
+
+// EventScrubPolicy traitement des propriétés des événements analytics avant leur écriture
+type EventScrubPolicy struct {
+	// IPv4Prefix / IPv6Prefix bits conservés des adresses IP trouvées dans les valeurs
+	// (24 et 48 : dernier octet / 80 derniers bits à zéro) ; 32 / 128 = adresses intactes
+	IPv4Prefix int
+	IPv6Prefix int
+	// HashedProperties propriétés identifiantes (email, user_id...) remplacées par leur empreinte :
+	// les comptages d'utilisateurs distincts restent possibles, pas la ré-identification
+	HashedProperties []string
+	// DeniedProperties propriétés retirées (password, phone...)
+	DeniedProperties []string
+}
+
+// EventScrubber applique l'EventScrubPolicy à chaque lot écrit par le dépôt d'ingestion
+// (TrackedEvents) : événements acceptés, réservoirs d'échantillonnage et imports passent tous par là
+// Sans IdentifierHasher, les propriétés identifiantes sont retirées plutôt qu'empreintées
+// L'identifiant interne (UserID) est conservé : il relève du consentement (ConsentChecker)
+type EventScrubber struct {
+	ipv4   int
+	ipv6   int
+	hashed map[string]bool
+	denied map[string]bool
+	hasher IdentifierHasher
+}
+
+func NewEventScrubber(policy EventScrubPolicy, hasher IdentifierHasher) *EventScrubber {
+	s := &EventScrubber{
+		ipv4:   policy.IPv4Prefix,
+		ipv6:   policy.IPv6Prefix,
+		hashed: make(map[string]bool, len(policy.HashedProperties)),
+		denied: make(map[string]bool, len(policy.DeniedProperties)),
+		hasher: hasher,
+	}
+	for _, key := range policy.HashedProperties {
+		s.hashed[key] = true
+	}
+	for _, key := range policy.DeniedProperties {
+		s.denied[key] = true
+	}
+	return s
+}
+
+// Scrub nettoie les propriétés de l'événement sur place
+func (s *EventScrubber) Scrub(event *entities.TrackedEvent) {
+	for key, value := range event.Properties {
+		switch {
+		case s.denied[key]:
+			delete(event.Properties, key)
+		case s.hashed[key]:
+			if identifier, ok := identifierValue(value); ok && s.hasher != nil {
+				event.Properties[key] = s.hasher.Identifier(identifier)
+			} else {
+				delete(event.Properties, key)
+			}
+		default:
+			if text, ok := value.(string); ok {
+				if truncated, ok := s.truncateIP(text); ok {
+					event.Properties[key] = truncated
+				}
+			}
+		}
+	}
+	if len(event.Properties) == 0 {
+		event.Properties = nil
+	}
+}
+
+// truncateIP adresse tronquée au préfixe de sa famille ; false si text n'est pas une adresse IP
+func (s *EventScrubber) truncateIP(text string) (string, bool) {
+	// Test rapide avant l'analyse : la plupart des valeurs ne sont pas des adresses
+	if len(text) < 2 || len(text) > 45 || !strings.ContainsAny(text, ".:") {
+		return "", false
+	}
+	addr, err := netip.ParseAddr(text)
+	if err != nil {
+		return "", false
+	}
+	bits := s.ipv6
+	if addr.Is4() || addr.Is4In6() {
+		addr, bits = addr.Unmap(), s.ipv4
+	}
+	if bits >= addr.BitLen() {
+		return text, false
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return "", false
+	}
+	return prefix.Addr().String(), true
+}
+
+// identifierValue texte d'un identifiant ; un booléen n'identifie personne
+func identifierValue(value any) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return strings.ToLower(strings.TrimSpace(typed)), typed != ""
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// TrackedEvents dépôt d'ingestion qui nettoie chaque lot avant de le transmettre à next :
+// décorateur le plus externe, aucune donnée brute n'atteint le tampon ni le stockage
+// Les événements du lot sont modifiés sur place (l'appelant n'en garde pas l'usage)
+func (s *EventScrubber) TrackedEvents(next repositories.EventRepository) repositories.EventRepository {
+	return &scrubbedEventRepository{EventRepository: next, scrubber: s}
+}
+
+type scrubbedEventRepository struct {
+	repositories.EventRepository
+	scrubber *EventScrubber
+}
+
+func (d *scrubbedEventRepository) Append(ctx context.Context, batch []*entities.TrackedEvent) error {
+	for _, event := range batch {
+		d.scrubber.Scrub(event)
+	}
+	return d.EventRepository.Append(ctx, batch)
+}
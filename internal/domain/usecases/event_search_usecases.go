@@ -0,0 +1,160 @@
+// internal/domain/usecases/event_search_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// SEARCH EVENTS USE CASE
+// =============================================================================
+
+// EventHistogramIntervals pas acceptés pour l'histogramme des événements
+var EventHistogramIntervals = []string{"hour", "day", "week", "month"}
+
+// SearchEventsUseCase recherche dans les événements recopiés par SearchIndexer, avec leur
+// répartition par nom et dans le temps ; sans moteur de recherche configuré (eventRepo nil),
+// retourne repositories.ErrSearchNotSupported
+type SearchEventsUseCase struct {
+	eventRepo repositories.EventSearchRepository
+}
+
+func NewSearchEventsUseCase(eventRepo repositories.EventSearchRepository) *SearchEventsUseCase {
+	return &SearchEventsUseCase{eventRepo: eventRepo}
+}
+
+type SearchEventsRequest struct {
+	// Events noms d'événements séparés par des virgules ("user.created,user.deleted") ; vide = tous
+	Events string `json:"events,omitempty"`
+	UserID int    `json:"user_id,omitempty"`
+	// From / To bornes RFC 3339 (début inclus, fin exclue)
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Interval string `json:"interval,omitempty"` // défaut : day
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+func (req SearchEventsRequest) Validate() error {
+	if req.UserID < 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if req.Page < 0 {
+		return errors.New("page invalide")
+	}
+	if req.PageSize < 0 || req.PageSize > 100 {
+		return errors.New("page_size doit être compris entre 1 et 100")
+	}
+	if req.Interval != "" && !slices.Contains(EventHistogramIntervals, req.Interval) {
+		return errors.New("interval doit valoir hour, day, week ou month")
+	}
+	if req.From != "" {
+		if _, err := time.Parse(time.RFC3339, req.From); err != nil {
+			return errors.New("from doit être une date RFC 3339")
+		}
+	}
+	if req.To != "" {
+		if _, err := time.Parse(time.RFC3339, req.To); err != nil {
+			return errors.New("to doit être une date RFC 3339")
+		}
+	}
+	return nil
+}
+
+func (req SearchEventsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"events":    req.Events,
+		"user_id":   req.UserID,
+		"interval":  req.Interval,
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	}
+}
+
+func (req SearchEventsRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type EventResponse struct {
+	Event      string    `json:"event"`
+	UserID     int       `json:"user_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type EventHistogramBucketResponse struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+type SearchEventsResponse struct {
+	Events   []EventResponse `json:"events"`
+	Total    int             `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+	// ByName / Histogram portent sur tous les résultats, pas seulement la page
+	ByName    map[string]int                 `json:"by_name"`
+	Interval  string                         `json:"interval"`
+	Histogram []EventHistogramBucketResponse `json:"histogram"`
+}
+
+func (uc *SearchEventsUseCase) Execute(ctx context.Context, req SearchEventsRequest) (*SearchEventsResponse, error) {
+	if uc.eventRepo == nil {
+		return nil, repositories.ErrSearchNotSupported
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+
+	filters := repositories.EventSearchFilters{
+		UserID:   req.UserID,
+		Interval: req.Interval,
+		Limit:    req.PageSize,
+		Offset:   (req.Page - 1) * req.PageSize,
+	}
+	for _, name := range strings.Split(req.Events, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filters.Names = append(filters.Names, name)
+		}
+	}
+	if req.From != "" {
+		from, _ := time.Parse(time.RFC3339, req.From)
+		filters.From = &from
+	}
+	if req.To != "" {
+		to, _ := time.Parse(time.RFC3339, req.To)
+		filters.To = &to
+	}
+
+	result, err := uc.eventRepo.SearchEvents(ctx, filters)
+	if err != nil {
+		return nil, newError("erreur lors de la recherche des événements", err)
+	}
+
+	response := &SearchEventsResponse{
+		Events:    make([]EventResponse, len(result.Events)),
+		Total:     result.Total,
+		Page:      req.Page,
+		PageSize:  req.PageSize,
+		ByName:    result.ByName,
+		Interval:  req.Interval,
+		Histogram: make([]EventHistogramBucketResponse, len(result.Histogram)),
+	}
+	for i, event := range result.Events {
+		response.Events[i] = EventResponse{Event: event.Name, UserID: event.UserID, OccurredAt: event.OccurredAt}
+	}
+	for i, bucket := range result.Histogram {
+		response.Histogram[i] = EventHistogramBucketResponse{Start: bucket.Start, Count: bucket.Count}
+	}
+	return response, nil
+}
@@ -0,0 +1,466 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// EXPORTS ASYNCHRONES
+// =============================================================================
+
+// Types des colonnes d'un export (ExportColumn.Type)
+const (
+	ExportString    = "string"
+	ExportInt64     = "int64"
+	ExportDouble    = "double"
+	ExportBool      = "bool"
+	ExportTimestamp = "timestamp" // time.Time, à la microseconde
+	ExportDate      = "date"      // time.Time, jour UTC
+)
+
+// ExportColumn colonne typée d'un export
+type ExportColumn struct {
+	Name string
+	Type string
+}
+
+// ExportSource données exportables en tâche de fond, une ligne par enregistrement
+type ExportSource interface {
+	// Action use case de lecture équivalent, soumis à l'Authorizer au démarrage : un export
+	// asynchrone exige les mêmes droits que l'export direct
+	Action() string
+	// Validate contrôle les critères avant la mise en file
+	Validate(filters map[string]string) error
+	// Columns schéma de l'export pour ces critères, établi avant la première ligne
+	Columns(ctx context.Context, filters map[string]string) ([]ExportColumn, error)
+	// Export émet les lignes et retourne leur nombre : une valeur par colonne, du type Go de la
+	// colonne (string, int64, float64, bool, time.Time) ou nil si elle est absente. Une erreur
+	// d'emit interrompt l'export
+	Export(ctx context.Context, filters map[string]string, emit func(row []any) error) (int, error)
+}
+
+// ExportEncoder format de fichier des exports (CSV intégré, services.ParquetExportEncoder)
+type ExportEncoder interface {
+	// Format nom du format (StartExportRequest.Format) et extension des fichiers
+	Format() string
+	ContentType() string
+	// Compressions valeurs acceptées pour StartExportRequest.Compression, la première par défaut
+	Compressions() []string
+	NewWriter(w io.Writer, columns []ExportColumn, compression string) (ExportRowWriter, error)
+}
+
+// ExportRowWriter écrit les lignes d'un fichier d'export ; Close le termine (tampons, pied de fichier)
+type ExportRowWriter interface {
+	Write(row []any) error
+	Close() error
+}
+
+// ExportCSV format par défaut des exports
+const ExportCSV = "csv"
+
+// csvExportEncoder une ligne d'en-tête puis une ligne par enregistrement, valeurs absentes vides
+type csvExportEncoder struct{}
+
+func (csvExportEncoder) Format() string         { return ExportCSV }
+func (csvExportEncoder) ContentType() string    { return "text/csv; charset=utf-8" }
+func (csvExportEncoder) Compressions() []string { return []string{"none"} }
+
+func (csvExportEncoder) NewWriter(w io.Writer, columns []ExportColumn, compression string) (ExportRowWriter, error) {
+	out := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	if err := out.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvRowWriter{out: out, columns: columns, record: make([]string, len(columns))}, nil
+}
+
+type csvRowWriter struct {
+	out     *csv.Writer
+	columns []ExportColumn
+	record  []string
+}
+
+func (w *csvRowWriter) Write(row []any) error {
+	for i, value := range row {
+		w.record[i] = csvCell(value, w.columns[i].Type)
+	}
+	if err := w.out.Write(w.record); err != nil {
+		return err
+	}
+	return w.out.Error()
+}
+
+func (w *csvRowWriter) Close() error {
+	w.out.Flush()
+	return w.out.Error()
+}
+
+func csvCell(value any, kind string) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case int64:
+		return strconv.FormatInt(typed, 10)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	case time.Time:
+		if kind == ExportDate {
+			return typed.UTC().Format(time.DateOnly)
+		}
+		return typed.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+var (
+	ErrUnknownExportKind   = errors.New("type d'export inconnu")
+	ErrUnknownExportFormat = errors.New("format d'export inconnu")
+	// ErrExportNotReady l'export n'est pas (ou plus) téléchargeable : en cours, échoué ou expiré
+	ErrExportNotReady = errors.New("export non disponible")
+)
+
+// ExportJobs exports volumineux hors requête HTTP : le job est mis en file sur le TaskRunner,
+// le fichier (CSV ou format d'un ExportEncoder) écrit dans le FileStorage puis servi sur lien signé (DownloadLinks) ou au
+// propriétaire du job, jusqu'à l'expiration de retention. Un job interrompu par un arrêt de
+// l'instance reste "running" puis est purgé à son expiration
+type ExportJobs struct {
+	jobRepo    repositories.ExportJobRepository
+	storage    FileStorage
+	sources    map[string]ExportSource
+	encoders   map[string]ExportEncoder
+	authorizer Authorizer
+	tasks      TaskRunner
+	links      *DownloadLinks
+	tokens     TokenGenerator
+	clock      Clock
+	logger     Logger
+	retention  time.Duration
+}
+
+func NewExportJobs(
+	jobRepo repositories.ExportJobRepository,
+	storage FileStorage,
+	sources map[string]ExportSource,
+	authorizer Authorizer,
+	tasks TaskRunner,
+	links *DownloadLinks,
+	tokens TokenGenerator,
+	clock Clock,
+	logger Logger,
+	retention time.Duration,
+	encoders ...ExportEncoder,
+) *ExportJobs {
+	byFormat := map[string]ExportEncoder{ExportCSV: csvExportEncoder{}}
+	for _, encoder := range encoders {
+		byFormat[encoder.Format()] = encoder
+	}
+	return &ExportJobs{
+		jobRepo:    jobRepo,
+		storage:    storage,
+		sources:    sources,
+		encoders:   byFormat,
+		authorizer: authorizer,
+		tasks:      tasks,
+		links:      links,
+		tokens:     tokens,
+		clock:      clock,
+		logger:     logger,
+		retention:  retention,
+	}
+}
+
+// ExportDownloadPath route servant le fichier d'un export terminé sur lien signé
+func ExportDownloadPath(id string) string {
+	return "/downloads/exports/" + id
+}
+
+// run produit le fichier du job ; le context est celui du TaskRunner (acteur conservé)
+func (e *ExportJobs) run(ctx context.Context, job *entities.ExportJob, source ExportSource) error {
+	job.Start(e.clock.Now())
+	if err := e.jobRepo.Save(ctx, job); err != nil {
+		return err
+	}
+
+	encoder := e.encoders[job.Format]
+	key := "export-" + job.ID + "." + job.Format
+	rows := 0
+	err := e.storage.Write(ctx, key, func(w io.Writer) error {
+		columns, err := source.Columns(ctx, job.Filters)
+		if err != nil {
+			return err
+		}
+		out, err := encoder.NewWriter(w, columns, job.Compression)
+		if err != nil {
+			return err
+		}
+		if rows, err = source.Export(ctx, job.Filters, out.Write); err != nil {
+			return err
+		}
+		return out.Close()
+	})
+
+	now := e.clock.Now()
+	if err != nil {
+		job.Fail(errorMessage(err), now)
+		e.logger.Error("Export failed", err, map[string]interface{}{"export_id": job.ID, "kind": job.Kind})
+	} else {
+		job.Complete(key, rows, now, now.Add(e.retention))
+	}
+	// Enregistré même si l'instance s'arrête : le statut final n'est pas perdu
+	return e.jobRepo.Save(context.WithoutCancel(ctx), job)
+}
+
+// owned job de l'appelant ; celui d'un autre utilisateur est introuvable. Les tâches internes
+// (téléchargement sur lien signé) voient tous les jobs
+func (e *ExportJobs) owned(ctx context.Context, id string) (*entities.ExportJob, error) {
+	job, err := e.jobRepo.Get(ctx, id)
+	if errors.Is(err, repositories.ErrExportJobNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'export", err)
+	}
+	actor, _ := ActorFromContext(ctx)
+	if !actor.System && actor.UserID != job.OwnerID {
+		return nil, repositories.ErrExportJobNotFound
+	}
+	return job, nil
+}
+
+// errorMessage message client d'une erreur de use case, cause technique masquée sinon
+func errorMessage(err error) string {
+	var ucErr *Error
+	if errors.As(err, &ucErr) {
+		return ucErr.Message
+	}
+	return "erreur lors de la production de l'export"
+}
+
+// ExportJobResponse statut du job ; Download lien signé vers le fichier une fois terminé
+// (absent si les liens sont désactivés : GET /exports/{id}/download reste disponible)
+type ExportJobResponse struct {
+	*entities.ExportJob
+	Download *DownloadLink `json:"download,omitempty"`
+}
+
+// =============================================================================
+// START EXPORT USE CASE
+// =============================================================================
+
+type StartExportUseCase struct {
+	exports *ExportJobs
+}
+
+func NewStartExportUseCase(exports *ExportJobs) *StartExportUseCase {
+	return &StartExportUseCase{exports: exports}
+}
+
+// StartExportRequest Format "csv" par défaut ; Compression propre au format, vide = celle par défaut
+type StartExportRequest struct {
+	Kind        string            `json:"kind"`
+	Filters     map[string]string `json:"filters"`
+	Format      string            `json:"format,omitempty"`
+	Compression string            `json:"compression,omitempty"`
+}
+
+func (req StartExportRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"kind": req.Kind, "filters": req.Filters, "format": req.Format, "compression": req.Compression}
+}
+
+// Execute met le job en file et retourne aussitôt son identifiant (statut queued)
+func (uc *StartExportUseCase) Execute(ctx context.Context, req StartExportRequest) (*ExportJobResponse, error) {
+	e := uc.exports
+	source, ok := e.sources[req.Kind]
+	if !ok {
+		kinds := make([]string, 0, len(e.sources))
+		for kind := range e.sources {
+			kinds = append(kinds, kind)
+		}
+		slices.Sort(kinds)
+		return nil, fmt.Errorf("%w : %q (%s)", ErrUnknownExportKind, req.Kind, strings.Join(kinds, ", "))
+	}
+	if req.Format == "" {
+		req.Format = ExportCSV
+	}
+	encoder, ok := e.encoders[req.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w : %q (%s)", ErrUnknownExportFormat, req.Format, strings.Join(slices.Sorted(maps.Keys(e.encoders)), ", "))
+	}
+	if req.Compression == "" {
+		req.Compression = encoder.Compressions()[0]
+	}
+	if !slices.Contains(encoder.Compressions(), req.Compression) {
+		return nil, fmt.Errorf("compression %q non disponible en %s (%s)", req.Compression, req.Format, strings.Join(encoder.Compressions(), ", "))
+	}
+	if err := source.Validate(req.Filters); err != nil {
+		return nil, err
+	}
+	if err := e.authorizer.Authorize(ctx, source.Action(), req.Filters); err != nil {
+		return nil, err
+	}
+
+	id, err := e.tokens.HexToken(16)
+	if err != nil {
+		return nil, newError("erreur lors de la création de l'export", err)
+	}
+	actor, _ := ActorFromContext(ctx)
+	now := e.clock.Now()
+	job := entities.NewExportJob(id, actor.UserID, req.Kind, req.Filters, now, now.Add(e.retention))
+	job.Format, job.Compression = req.Format, req.Compression
+	if err := e.jobRepo.Save(ctx, job); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de l'export", err)
+	}
+
+	queued := *job
+	if err := e.tasks.Go(ctx, "export_"+req.Kind, func(ctx context.Context) error {
+		return e.run(ctx, &queued, source)
+	}); err != nil {
+		job.Fail("export non démarré", e.clock.Now())
+		_ = e.jobRepo.Save(context.WithoutCancel(ctx), job)
+		return nil, newError("export non démarré", err)
+	}
+	return &ExportJobResponse{ExportJob: job}, nil
+}
+
+// =============================================================================
+// GET EXPORT STATUS USE CASE
+// =============================================================================
+
+// GetExportStatusUseCase statut d'un job, interrogé par le client jusqu'à completed ou failed
+type GetExportStatusUseCase struct {
+	exports *ExportJobs
+}
+
+func NewGetExportStatusUseCase(exports *ExportJobs) *GetExportStatusUseCase {
+	return &GetExportStatusUseCase{exports: exports}
+}
+
+func (uc *GetExportStatusUseCase) Execute(ctx context.Context, id string) (*ExportJobResponse, error) {
+	e := uc.exports
+	job, err := e.owned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := &ExportJobResponse{ExportJob: job}
+	if job.Status == entities.ExportCompleted && !job.Expired(e.clock.Now()) {
+		link, err := e.links.Link(ExportDownloadPath(job.ID), nil)
+		if err != nil && !errors.Is(err, ErrDownloadLinksDisabled) {
+			return nil, newError("erreur lors de la création du lien de téléchargement", err)
+		}
+		if link != nil && link.ExpiresAt.After(job.ExpiresAt) {
+			link.ExpiresAt = job.ExpiresAt
+		}
+		response.Download = link
+	}
+	return response, nil
+}
+
+// =============================================================================
+// DOWNLOAD EXPORT USE CASE
+// =============================================================================
+
+// DownloadExportUseCase fichier d'un export terminé : au propriétaire, ou sur lien signé
+type DownloadExportUseCase struct {
+	exports *ExportJobs
+}
+
+func NewDownloadExportUseCase(exports *ExportJobs) *DownloadExportUseCase {
+	return &DownloadExportUseCase{exports: exports}
+}
+
+// ExportFile Content à fermer par l'appelant
+type ExportFile struct {
+	Job         *entities.ExportJob
+	ContentType string
+	Content     io.ReadCloser
+}
+
+func (uc *DownloadExportUseCase) Execute(ctx context.Context, id string) (*ExportFile, error) {
+	e := uc.exports
+	job, err := e.owned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != entities.ExportCompleted || job.Expired(e.clock.Now()) {
+		return nil, ErrExportNotReady
+	}
+	content, err := e.storage.Open(ctx, job.FileKey)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'export", err)
+	}
+	contentType := "application/octet-stream"
+	if encoder, ok := e.encoders[job.Format]; ok {
+		contentType = encoder.ContentType()
+	}
+	return &ExportFile{Job: job, ContentType: contentType, Content: content}, nil
+}
+
+// =============================================================================
+// PURGE EXPIRED EXPORTS USE CASE
+// =============================================================================
+
+// purgeExportsBatch jobs traités par passage de la tâche planifiée
+const purgeExportsBatch = 500
+
+// PurgeExpiredExportsUseCase supprime les jobs expirés et leur fichier (tâche planifiée) ; un job
+// qui n'a pas abouti avant son expiration est considéré abandonné
+type PurgeExpiredExportsUseCase struct {
+	exports *ExportJobs
+}
+
+func NewPurgeExpiredExportsUseCase(exports *ExportJobs) *PurgeExpiredExportsUseCase {
+	return &PurgeExpiredExportsUseCase{exports: exports}
+}
+
+type PurgeExpiredExportsRequest struct {
+	Now time.Time
+}
+
+type PurgeExpiredExportsResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+func (uc *PurgeExpiredExportsUseCase) Execute(ctx context.Context, req PurgeExpiredExportsRequest) (*PurgeExpiredExportsResponse, error) {
+	e := uc.exports
+	if req.Now.IsZero() {
+		req.Now = e.clock.Now()
+	}
+	expired, err := e.jobRepo.ListExpired(ctx, req.Now, purgeExportsBatch)
+	if err != nil {
+		return nil, newError("erreur lors de la recherche des exports expirés", err)
+	}
+
+	response := &PurgeExpiredExportsResponse{}
+	for _, job := range expired {
+		if job.FileKey != "" {
+			if err := e.storage.Delete(ctx, job.FileKey); err != nil {
+				e.logger.Error("Failed to delete export file", err, map[string]interface{}{"export_id": job.ID})
+				continue
+			}
+		}
+		if err := e.jobRepo.Delete(ctx, job.ID); err != nil {
+			e.logger.Error("Failed to delete export", err, map[string]interface{}{"export_id": job.ID})
+			continue
+		}
+		response.Deleted++
+	}
+	return response, nil
+}
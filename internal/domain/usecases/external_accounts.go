@@ -0,0 +1,199 @@
+// internal/domain/usecases/external_accounts.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"strings"
+)
+
+// =============================================================================
+// COMPTES FÉDÉRÉS (ANNUAIRE, SSO) : RATTACHEMENT ET PROVISIONNEMENT À LA CONNEXION
+// =============================================================================
+
+// ExternalIdentity identité vérifiée par un fournisseur externe
+type ExternalIdentity struct {
+	// Source / ExternalID clé du lien avec le compte local ("ldap" + DN, "saml:<tenant>" + NameID)
+	Source     string
+	ExternalID string
+	Email      string
+	Name       string
+	// LinkUserID compte local de l'utilisateur authentifié qui a lancé la connexion : rattachement
+	// explicite à la première connexion par ce fournisseur (0 = aucun)
+	LinkUserID int
+}
+
+// ErrExternalAccountNotLinked un compte local utilise déjà l'email de l'identité externe, mais le
+// fournisseur n'est pas habilité à s'y rattacher seul : l'utilisateur doit lancer la connexion
+// depuis sa session locale (rattachement explicite)
+var ErrExternalAccountNotLinked = errors.New("un compte local utilise déjà cet email : connectez-vous pour le rattacher")
+
+// externalAccounts retrouve le compte local d'une identité externe :
+//   - première connexion : le compte désigné par LinkUserID est rattaché ; à défaut, le compte de
+//     même email si linkByEmail, sinon un nouveau compte est créé
+//   - connexions suivantes : nom et email suivent le fournisseur
+type externalAccounts struct {
+	// linkByEmail le fournisseur fait foi pour les emails de l'instance (annuaire configuré par
+	// l'exploitant) ; jamais pour un IdP déclaré par un tenant, qui pourrait affirmer n'importe quel email
+	linkByEmail    bool
+	userRepo       repositories.UserRepository
+	linkRepo       repositories.ExternalUserLinkRepository
+	hasher         PasswordHasher
+	publisher      EventPublisher
+	logger         Logger
+	clock          Clock
+	tokenGenerator TokenGenerator
+}
+
+// resolve compte local de l'identité, rattaché ou créé au besoin
+func (a *externalAccounts) resolve(ctx context.Context, identity ExternalIdentity) (*entities.User, error) {
+	email := strings.ToLower(strings.TrimSpace(identity.Email))
+	name := strings.TrimSpace(identity.Name)
+	if email == "" {
+		// Sans email, aucun compte local ne peut être rattaché ni créé
+		a.logger.Warn("External identity without email", map[string]interface{}{"source": identity.Source, "external_id": identity.ExternalID})
+		return nil, ErrInvalidCredentials
+	}
+
+	link, err := a.linkRepo.GetByExternalID(ctx, identity.Source, identity.ExternalID)
+	switch {
+	case errors.Is(err, repositories.ErrExternalUserLinkNotFound):
+		return a.linkOrProvision(ctx, identity, email, name)
+	case err != nil:
+		return nil, newError("erreur lors de la lecture du lien externe", err)
+	}
+
+	user, err := a.userRepo.GetById(ctx, link.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		// Supprimé localement : on ne le recrée pas à la connexion
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la recherche de l'utilisateur", err)
+	}
+
+	if err := a.refreshProfile(ctx, user, email, name); err != nil {
+		return nil, err
+	}
+	link.Email, link.Name, link.Active, link.SyncedAt = user.Email, user.Name, true, a.clock.Now()
+	if err := a.linkRepo.Save(ctx, link); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du lien externe", err)
+	}
+	return user, nil
+}
+
+// linkOrProvision première connexion par ce fournisseur
+func (a *externalAccounts) linkOrProvision(ctx context.Context, identity ExternalIdentity, email, name string) (*entities.User, error) {
+	var user *entities.User
+	var err error
+	if identity.LinkUserID > 0 {
+		user, err = a.userRepo.GetById(ctx, identity.LinkUserID)
+	} else {
+		user, err = a.userRepo.GetByEmail(ctx, email)
+	}
+	switch {
+	case errors.Is(err, repositories.ErrUserNotFound) && identity.LinkUserID > 0:
+		return nil, ErrInvalidCredentials
+	case errors.Is(err, repositories.ErrUserNotFound):
+		if user, err = a.provision(ctx, identity.Source, email, name); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, newError("erreur lors de la recherche de l'utilisateur", err)
+	case identity.LinkUserID == 0 && !a.linkByEmail:
+		a.logger.Warn("External identity matches an unlinked local account", map[string]interface{}{
+			"source": identity.Source, "user_id": user.ID,
+		})
+		return nil, ErrExternalAccountNotLinked
+	default:
+		if err := a.refreshProfile(ctx, user, email, name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.linkRepo.Save(ctx, &repositories.ExternalUserLink{
+		Source:     identity.Source,
+		ExternalID: identity.ExternalID,
+		UserID:     user.ID,
+		Email:      user.Email,
+		Name:       user.Name,
+		Active:     true,
+		SyncedAt:   a.clock.Now(),
+	}); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du lien externe", err)
+	}
+	return user, nil
+}
+
+// provision crée le compte local ; son mot de passe aléatoire ne sert jamais (le fournisseur fait foi)
+func (a *externalAccounts) provision(ctx context.Context, source, email, name string) (*entities.User, error) {
+	password, err := randomProvisioningPassword(a.tokenGenerator)
+	if err != nil {
+		return nil, newError("erreur lors de la génération du mot de passe", err)
+	}
+	user, err := entities.NewUser(email, name, password, a.clock.Now())
+	if err != nil {
+		a.logger.Warn("External identity cannot be provisioned", map[string]interface{}{"source": source, "error": err.Error()})
+		return nil, ErrInvalidCredentials
+	}
+	if user.Password, err = a.hasher.Hash(user.Password); err != nil {
+		return nil, newError("erreur lors du hachage du mot de passe", err)
+	}
+
+	created, err := a.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, newError("erreur lors de la création de l'utilisateur", err)
+	}
+	a.publisher.Publish(ctx, events.UserCreated{
+		UserID:  created.ID,
+		Email:   created.Email,
+		Name:    created.Name,
+		Created: created.Created,
+	})
+	a.logger.Info("User provisioned from external identity", map[string]interface{}{"source": source, "user_id": created.ID})
+	return created, nil
+}
+
+// refreshProfile aligne nom et email sur le fournisseur ; un échec est journalisé sans bloquer la connexion
+func (a *externalAccounts) refreshProfile(ctx context.Context, user *entities.User, email, name string) error {
+	if name == "" {
+		name = user.Name
+	}
+	if email != user.Email {
+		taken, err := a.userRepo.IsEmailTaken(ctx, email)
+		if err != nil {
+			return newError("erreur lors de la vérification de l'email", err)
+		}
+		if taken {
+			a.logger.Warn("External email already used locally", map[string]interface{}{"user_id": user.ID})
+			email = user.Email
+		}
+	}
+
+	candidate := *user
+	changes, err := candidate.UpdateUserProfile(name, email, a.clock.Now())
+	if err != nil {
+		a.logger.Warn("External profile ignored", map[string]interface{}{"user_id": user.ID, "error": err.Error()})
+		return nil
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	updated, err := a.userRepo.Update(ctx, &candidate)
+	if err != nil {
+		return newError("erreur lors de la mise à jour de l'utilisateur", err)
+	}
+	*user = *updated
+	a.publisher.Publish(ctx, events.UserProfileUpdated{
+		UserID:        user.ID,
+		Email:         user.Email,
+		Name:          user.Name,
+		ChangedFields: entities.ChangedFields(changes),
+		Updated:       user.Updated,
+	})
+	return nil
+}
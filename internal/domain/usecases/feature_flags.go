@@ -0,0 +1,19 @@
+// internal/domain/usecases/feature_flags.go
+package usecases
+
+import (
+	"context"
+)
+
+// Noms des feature flags connus du domaine
+const (
+	// FlagCursorPagination active la pagination par curseur opaque dans ListUsers
+	FlagCursorPagination = "users.cursor_pagination"
+)
+
+// FeatureFlags interface pour évaluer les feature flags
+// L'évaluation dépend du context : l'implémentation lit l'Actor (utilisateur, tenant)
+// pour les activations ciblées ou progressives
+type FeatureFlags interface {
+	IsEnabled(ctx context.Context, flag string) bool
+}
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// =============================================================================
+// HOOKS : règles métier injectées par les projets qui embarquent l'API
+// =============================================================================
+
+// ErrRejectedByHook cause des erreurs retournées par un hook Before (HTTP 422)
+var ErrRejectedByHook = errors.New("refusé par une règle métier")
+
+// Hooks fonctions appelées avant et après les use cases, par nom (celui passé à Wrap) :
+//   - Before reçoit l'entrée validée et autorisée ; une erreur annule l'appel, sans consommer de quota
+//   - After reçoit l'entrée, la sortie et l'erreur du use case, une fois ses écritures faites ;
+//     il observe (synchronisation, audit...) sans modifier le résultat
+//
+// Les hooks d'un même use case s'exécutent dans l'ordre d'enregistrement. Enregistrement et
+// exécution sont sûrs en concurrence : des hooks peuvent être ajoutés après le démarrage
+type Hooks struct {
+	mutex  sync.RWMutex
+	before map[string][]func(ctx context.Context, input any) error
+	after  map[string][]func(ctx context.Context, input, output any, err error)
+}
+
+func NewHooks() *Hooks {
+	return &Hooks{
+		before: make(map[string][]func(ctx context.Context, input any) error),
+		after:  make(map[string][]func(ctx context.Context, input, output any, err error)),
+	}
+}
+
+// Before enregistre fn avant le use case name ; I doit être le type d'entrée du use case,
+// sans quoi chaque appel échoue (erreur de câblage visible dès le premier appel)
+func Before[I any](h *Hooks, name string, fn func(ctx context.Context, input I) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.before[name] = append(h.before[name], func(ctx context.Context, input any) error {
+		typed, ok := input.(I)
+		if !ok {
+			return fmt.Errorf("hook %s: entrée %T, %T attendue", name, input, typed)
+		}
+		return fn(ctx, typed)
+	})
+}
+
+// After enregistre fn après le use case name ; un hook dont I ou O ne correspond pas au use case est ignoré
+func After[I, O any](h *Hooks, name string, fn func(ctx context.Context, input I, output O, err error)) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.after[name] = append(h.after[name], func(ctx context.Context, input, output any, err error) {
+		typedInput, inputOK := input.(I)
+		typedOutput, outputOK := output.(O)
+		if inputOK && outputOK {
+			fn(ctx, typedInput, typedOutput, err)
+		}
+	})
+}
+
+func (h *Hooks) runBefore(ctx context.Context, name string, input any) error {
+	h.mutex.RLock()
+	hooks := h.before[name]
+	h.mutex.RUnlock()
+	for _, hook := range hooks {
+		if err := hook(ctx, input); err != nil {
+			var useCaseErr *Error
+			if errors.As(err, &useCaseErr) {
+				return err
+			}
+			return newError(err.Error(), fmt.Errorf("%w: %w", ErrRejectedByHook, err))
+		}
+	}
+	return nil
+}
+
+func (h *Hooks) runAfter(ctx context.Context, name string, input, output any, err error) {
+	h.mutex.RLock()
+	hooks := h.after[name]
+	h.mutex.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, input, output, err)
+	}
+}
+
+// WithHooks exécute les hooks enregistrés pour name autour du use case (nil = aucun hook)
+func WithHooks[I, O any](name string, hooks *Hooks) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if hooks == nil {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			if err := hooks.runBefore(ctx, name, input); err != nil {
+				var zero O
+				return zero, err
+			}
+			output, err := next.Execute(ctx, input)
+			hooks.runAfter(ctx, name, input, output, err)
+			return output, err
+		})
+	}
+}
+
+// =============================================================================
+// HOOKS DES UTILISATEURS
+// =============================================================================
+
+func BeforeUserCreate(h *Hooks, fn func(ctx context.Context, input CreateUserRequest) error) {
+	Before(h, "create_user", fn)
+}
+
+func AfterUserCreate(h *Hooks, fn func(ctx context.Context, input CreateUserRequest, output *CreateUserResponse, err error)) {
+	After(h, "create_user", fn)
+}
+
+func BeforeUserUpdate(h *Hooks, fn func(ctx context.Context, input UpdateUserRequest) error) {
+	Before(h, "update_user", fn)
+}
+
+func AfterUserUpdate(h *Hooks, fn func(ctx context.Context, input UpdateUserRequest, output *UpdateUserResponse, err error)) {
+	After(h, "update_user", fn)
+}
+
+// BeforeUserDelete / AfterUserDelete reçoivent l'ID de l'utilisateur supprimé
+func BeforeUserDelete(h *Hooks, fn func(ctx context.Context, id int) error) {
+	Before(h, "delete_user", fn)
+}
+
+func AfterUserDelete(h *Hooks, fn func(ctx context.Context, id int, err error)) {
+	After(h, "delete_user", func(ctx context.Context, id int, _ struct{}, err error) {
+		fn(ctx, id, err)
+	})
+}
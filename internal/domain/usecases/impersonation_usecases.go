@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// IMPERSONATION : SESSIONS D'ASSISTANCE OUVERTES PAR UN ADMINISTRATEUR
+// =============================================================================
+
+var (
+	// ErrImpersonationNotAllowed l'acteur n'a pas le rôle requis, ou est lui-même en impersonation
+	ErrImpersonationNotAllowed = errors.New("impersonation non autorisée")
+	// ErrImpersonationScope l'action est interdite à une session d'impersonation
+	ErrImpersonationScope = errors.New("action interdite pendant une session d'impersonation")
+)
+
+// ImpersonationScope use cases permis aux sessions d'impersonation, inscrits dans le jeton
+// (claim scope) : les lectures du compte, pour voir ce que voit l'utilisateur. Tout le reste,
+// écritures comprises, est refusé ; un use case ajouté n'est jamais ouvert sans y figurer
+var ImpersonationScope = []string{
+	"get_user",
+	"get_terms_status",
+	"get_analytics_consent",
+	"get_notification_preferences",
+	"list_notifications",
+	"list_sessions",
+	"get_upload",
+	"get_export_status",
+}
+
+// ImpersonationGuard Authorizer qui limite les sessions d'impersonation aux actions à la fois
+// permises (allowed) et portées par le jeton, avant de déléguer à l'Authorizer suivant
+type ImpersonationGuard struct {
+	next    Authorizer
+	allowed map[string]bool
+}
+
+func NewImpersonationGuard(next Authorizer, allowedActions []string) *ImpersonationGuard {
+	allowed := make(map[string]bool, len(allowedActions))
+	for _, action := range allowedActions {
+		allowed[action] = true
+	}
+	return &ImpersonationGuard{next: next, allowed: allowed}
+}
+
+func (g *ImpersonationGuard) Authorize(ctx context.Context, useCase string, input interface{}) error {
+	if actor, _ := ActorFromContext(ctx); actor.Impersonation != nil {
+		if !g.allowed[useCase] || !slices.Contains(actor.Impersonation.Scope, useCase) {
+			return ErrImpersonationScope
+		}
+	}
+	return g.next.Authorize(ctx, useCase, input)
+}
+
+// =============================================================================
+// IMPERSONATE USER USE CASE
+// =============================================================================
+
+type ImpersonateUserRequest struct {
+	UserID int `json:"-"`
+	// Reason motif de la session (ticket de support...) : obligatoire, conservé pour l'audit
+	Reason string `json:"reason"`
+}
+
+func (req ImpersonateUserRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+func (req ImpersonateUserRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return errors.New("le motif de la session est obligatoire")
+	}
+	if len(reason) > 500 {
+		return errors.New("le motif ne doit pas dépasser 500 caractères")
+	}
+	return nil
+}
+
+func (req ImpersonateUserRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "reason": req.Reason}
+}
+
+type ImpersonateUserResponse struct {
+	AccessToken    string    `json:"access_token"`
+	TokenType      string    `json:"token_type"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	UserID         int       `json:"user_id"`
+	ImpersonatorID int       `json:"impersonator_id"`
+	// Banner texte à afficher pendant toute la session (aussi porté par le jeton)
+	Banner string `json:"banner"`
+}
+
+// ImpersonateUserUseCase émet, pour un administrateur, un jeton agissant au nom d'un utilisateur :
+//   - réservé aux acteurs portant adminRole, jamais depuis une session d'impersonation
+//   - durée courte (ttl), sans rôle ni renouvellement ; portée limitée aux lectures de
+//     ImpersonationScope, inscrite dans le jeton et vérifiée par ImpersonationGuard
+//   - chaque ouverture est journalisée (niveau Warn), inscrite dans l'activité de l'utilisateur
+//     et lui est notifiée ; chaque action de la session est journalisée avec impersonator_id
+type ImpersonateUserUseCase struct {
+	userRepo  repositories.UserRepository
+	tokens    TokenIssuer
+	publisher EventPublisher
+	logger    Logger
+	adminRole string
+	ttl       time.Duration
+	clock     Clock
+}
+
+func NewImpersonateUserUseCase(
+	userRepo repositories.UserRepository,
+	tokens TokenIssuer,
+	publisher EventPublisher,
+	logger Logger,
+	adminRole string,
+	ttl time.Duration,
+	clock Clock,
+) *ImpersonateUserUseCase {
+	return &ImpersonateUserUseCase{
+		userRepo:  userRepo,
+		tokens:    tokens,
+		publisher: publisher,
+		logger:    logger,
+		adminRole: adminRole,
+		ttl:       ttl,
+		clock:     clock,
+	}
+}
+
+func (uc *ImpersonateUserUseCase) Execute(ctx context.Context, req ImpersonateUserRequest) (*ImpersonateUserResponse, error) {
+	actor, _ := ActorFromContext(ctx)
+	if actor.UserID <= 0 {
+		return nil, ErrAuthenticationRequired
+	}
+	if actor.Impersonation != nil || uc.adminRole == "" || !slices.Contains(actor.Roles, uc.adminRole) {
+		return nil, ErrImpersonationNotAllowed
+	}
+	if req.UserID == actor.UserID {
+		return nil, errors.New("impossible d'ouvrir une session d'impersonation sur son propre compte")
+	}
+
+	admin, err := uc.userRepo.GetById(ctx, actor.UserID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'administrateur", err)
+	}
+	user, err := uc.userRepo.GetById(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'utilisateur", err)
+	}
+	switch user.CurrentStatus() {
+	case entities.UserStatusDeactivated:
+		return nil, ErrAccountDeactivated
+	case entities.UserStatusBanned:
+		return nil, ErrAccountBanned
+	}
+
+	now := uc.clock.Now()
+	expiresAt := now.Add(uc.ttl)
+	impersonation := &Impersonation{
+		AdminID: admin.ID,
+		Banner:  fmt.Sprintf("Session d'assistance : %s (%s) agit au nom de %s", admin.Name, admin.Email, user.Name),
+		Scope:   slices.Clone(ImpersonationScope),
+	}
+	token, err := uc.tokens.Issue(Actor{UserID: user.ID, TenantID: actor.TenantID, Impersonation: impersonation}, uc.ttl)
+	if err != nil {
+		return nil, newError("erreur lors de l'émission du jeton", err)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	uc.logger.Warn("AUDIT impersonation session opened", map[string]interface{}{
+		"admin_id":   admin.ID,
+		"user_id":    user.ID,
+		"reason":     reason,
+		"expires_at": expiresAt,
+		"request_id": RequestIDFromContext(ctx),
+	})
+	uc.publisher.Publish(ctx, events.UserImpersonated{
+		UserID:    user.ID,
+		AdminID:   admin.ID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		At:        now,
+	})
+
+	return &ImpersonateUserResponse{
+		AccessToken:    token,
+		TokenType:      "Bearer",
+		ExpiresAt:      expiresAt,
+		UserID:         user.ID,
+		ImpersonatorID: admin.ID,
+		Banner:         impersonation.Banner,
+	}, nil
+}
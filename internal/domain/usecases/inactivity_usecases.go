@@ -0,0 +1,272 @@
+// internal/domain/usecases/inactivity_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// =============================================================================
+// PORTS ET DTOs DE L'INACTIVITÉ
+// =============================================================================
+
+// ReengagementReminder données passées au template de relance
+type ReengagementReminder struct {
+	Name         string
+	Email        string
+	LastActivity time.Time
+	InactiveDays int
+	// Format locale et fuseau du destinataire pour les dates et les nombres
+	Format DisplayFormat
+}
+
+// ReengagementRenderer interface pour produire le sujet et le corps de l'email de relance
+type ReengagementRenderer interface {
+	RenderReengagement(reminder ReengagementReminder) (subject, body string, err error)
+}
+
+// InactivityPolicy seuils d'inactivité ; 0 désactive l'étape correspondante
+type InactivityPolicy struct {
+	// RemindAfter relance par email au-delà de cette inactivité
+	RemindAfter time.Duration
+	// CleanupAfter signalement pour nettoyage au-delà de cette inactivité (plus de relance ensuite)
+	CleanupAfter time.Duration
+}
+
+// inactiveDays nombre de jours entiers écoulés depuis la dernière activité
+func inactiveDays(since, now time.Time) int {
+	return int(now.Sub(since).Hours() / 24)
+}
+
+// =============================================================================
+// PROCESS INACTIVE USERS USE CASE (planifié)
+// =============================================================================
+
+type ProcessInactiveUsersUseCase struct {
+	userRepo   repositories.UserRepository
+	outboxRepo repositories.OutboxRepository
+	renderer   ReengagementRenderer
+	publisher  EventPublisher
+	policy     InactivityPolicy
+	formats    *DisplayFormats
+	clock      Clock
+}
+
+func NewProcessInactiveUsersUseCase(
+	userRepo repositories.UserRepository,
+	outboxRepo repositories.OutboxRepository,
+	renderer ReengagementRenderer,
+	publisher EventPublisher,
+	policy InactivityPolicy,
+	formats *DisplayFormats,
+	clock Clock,
+) *ProcessInactiveUsersUseCase {
+	return &ProcessInactiveUsersUseCase{
+		userRepo:   userRepo,
+		outboxRepo: outboxRepo,
+		renderer:   renderer,
+		publisher:  publisher,
+		policy:     policy,
+		formats:    formats,
+		clock:      clock,
+	}
+}
+
+// ProcessInactiveUsersRequest Now zéro = l'horloge du use case
+type ProcessInactiveUsersRequest struct {
+	Now time.Time
+}
+
+type ProcessInactiveUsersResponse struct {
+	UsersScanned      int `json:"users_scanned"`
+	Reminded          int `json:"reminded"`
+	AlreadyReminded   int `json:"already_reminded"`
+	FlaggedForCleanup int `json:"flagged_for_cleanup"`
+}
+
+const inactivityBatchSize = 100
+
+func (uc *ProcessInactiveUsersUseCase) Execute(ctx context.Context, req ProcessInactiveUsersRequest) (*ProcessInactiveUsersResponse, error) {
+	if req.Now.IsZero() {
+		req.Now = uc.clock.Now()
+	}
+
+	// Le seuil le plus court délimite les comptes à examiner
+	threshold := uc.policy.RemindAfter
+	if threshold <= 0 {
+		threshold = uc.policy.CleanupAfter
+	}
+	if threshold <= 0 {
+		return &ProcessInactiveUsersResponse{}, nil
+	}
+	cutoff := req.Now.Add(-threshold)
+
+	response := &ProcessInactiveUsersResponse{}
+
+	// Le signalement ne change pas la dernière activité : la pagination par offset reste stable
+	for offset := 0; ; offset += inactivityBatchSize {
+		users, err := uc.userRepo.ListInactiveSince(ctx, cutoff, inactivityBatchSize, offset)
+		if err != nil {
+			return nil, newError("erreur lors de la recherche des comptes inactifs", err)
+		}
+
+		for _, user := range users {
+			response.UsersScanned++
+			lastActivity := user.LastActivity()
+
+			if uc.policy.CleanupAfter > 0 && lastActivity.Before(req.Now.Add(-uc.policy.CleanupAfter)) {
+				if !user.FlagForCleanup(req.Now) {
+					continue
+				}
+				if _, err := uc.userRepo.Update(ctx, user); err != nil {
+					return nil, newError("erreur lors du signalement du compte", err)
+				}
+				uc.publisher.Publish(ctx, events.UserFlaggedForCleanup{
+					UserID:        user.ID,
+					InactiveSince: lastActivity,
+					Flagged:       req.Now,
+				})
+				response.FlaggedForCleanup++
+				continue
+			}
+
+			if uc.policy.RemindAfter <= 0 {
+				continue
+			}
+
+			subject, body, err := uc.renderer.RenderReengagement(ReengagementReminder{
+				Name:         user.Name,
+				Email:        user.Email,
+				LastActivity: lastActivity,
+				InactiveDays: inactiveDays(lastActivity, req.Now),
+				Format:       uc.formats.ForUser(user),
+			})
+			if err != nil {
+				return nil, newError("erreur lors du rendu de la relance", err)
+			}
+
+			payload, err := json.Marshal(EmailPayload{To: user.Email, Subject: subject, Body: body})
+			if err != nil {
+				return nil, newError("erreur lors du rendu de la relance", err)
+			}
+
+			// Une seule relance par période d'inactivité : une nouvelle connexion change la clé
+			queued, err := uc.outboxRepo.Enqueue(ctx, &repositories.OutboxMessage{
+				Kind:     OutboxKindEmail,
+				DedupKey: fmt.Sprintf("reengagement:%d:%d", user.ID, lastActivity.Unix()),
+				Payload:  payload,
+				Created:  req.Now,
+			})
+			if err != nil {
+				return nil, newError("erreur lors de la mise en file de la relance", err)
+			}
+
+			if queued {
+				response.Reminded++
+			} else {
+				response.AlreadyReminded++
+			}
+		}
+
+		if len(users) < inactivityBatchSize {
+			break
+		}
+	}
+
+	return response, nil
+}
+
+// =============================================================================
+// LIST INACTIVE USERS USE CASE (rapport)
+// =============================================================================
+
+type ListInactiveUsersUseCase struct {
+	userRepo repositories.UserRepository
+	clock    Clock
+}
+
+func NewListInactiveUsersUseCase(userRepo repositories.UserRepository, clock Clock) *ListInactiveUsersUseCase {
+	return &ListInactiveUsersUseCase{userRepo: userRepo, clock: clock}
+}
+
+type ListInactiveUsersRequest struct {
+	// Days inactivité minimale en jours
+	Days     int `json:"days"`
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+type InactiveUserResponse struct {
+	ID               int        `json:"id"`
+	Email            string     `json:"email"`
+	Name             string     `json:"name"`
+	LastLoginAt      *time.Time `json:"last_login_at,omitempty"`
+	InactiveDays     int        `json:"inactive_days"`
+	CleanupFlaggedAt *time.Time `json:"cleanup_flagged_at,omitempty"`
+}
+
+type ListInactiveUsersResponse struct {
+	Users    []*InactiveUserResponse `json:"users"`
+	Days     int                     `json:"days"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"page_size"`
+	HasMore  bool                    `json:"has_more"`
+}
+
+func (req ListInactiveUsersRequest) Validate() error {
+	if req.Days < 1 {
+		return errors.New("days doit être au moins 1")
+	}
+	if req.Page < 0 {
+		return errors.New("page invalide")
+	}
+	if req.PageSize < 0 || req.PageSize > 100 {
+		return errors.New("page_size doit être compris entre 1 et 100")
+	}
+	return nil
+}
+
+func (req ListInactiveUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"days": req.Days, "page": req.Page, "page_size": req.PageSize}
+}
+
+func (uc *ListInactiveUsersUseCase) Execute(ctx context.Context, req ListInactiveUsersRequest) (*ListInactiveUsersResponse, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	now := uc.clock.Now()
+	// Une ligne de plus que la page : indique s'il reste des résultats sans COUNT
+	users, err := uc.userRepo.ListInactiveSince(ctx, now.AddDate(0, 0, -req.Days), req.PageSize+1, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, newError("erreur lors de la recherche des comptes inactifs", err)
+	}
+
+	response := &ListInactiveUsersResponse{Days: req.Days, Page: req.Page, PageSize: req.PageSize}
+	if len(users) > req.PageSize {
+		users = users[:req.PageSize]
+		response.HasMore = true
+	}
+
+	response.Users = make([]*InactiveUserResponse, len(users))
+	for i, user := range users {
+		response.Users[i] = &InactiveUserResponse{
+			ID:               user.ID,
+			Email:            user.Email,
+			Name:             user.Name,
+			LastLoginAt:      user.LastLoginAt,
+			InactiveDays:     inactiveDays(user.LastActivity(), now),
+			CleanupFlaggedAt: user.CleanupFlaggedAt,
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,358 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// ÉVALUATION DU RISQUE DES CONNEXIONS
+// =============================================================================
+
+var (
+	// ErrLoginBlocked connexion refusée par une règle de risque, identifiants pourtant valides
+	ErrLoginBlocked = errors.New("connexion bloquée : activité inhabituelle sur le compte")
+	// ErrStepUpRequired un code envoyé par email doit confirmer la connexion (StepUpRequiredError)
+	ErrStepUpRequired = errors.New("vérification supplémentaire requise")
+	// ErrInvalidLoginChallenge code faux, vérification inconnue, expirée ou épuisée
+	ErrInvalidLoginChallenge = errors.New("code de vérification invalide ou expiré")
+)
+
+// RiskDecision issue de l'évaluation d'une connexion
+type RiskDecision string
+
+const (
+	RiskAllow  RiskDecision = "allow"
+	RiskStepUp RiskDecision = "step_up"
+	RiskBlock  RiskDecision = "block"
+)
+
+// LoginRiskRules règles configurables (LOGIN_RISK_RULES)
+var LoginRiskRules = []string{"new_country", "impossible_travel", "tor"}
+
+// StepUpRequiredError vérification ouverte pour la connexion : le client soumet le code reçu
+// par email à POST /auth/login/verify
+type StepUpRequiredError struct {
+	ChallengeID string
+	Method      string
+	ExpiresAt   time.Time
+}
+
+func (e *StepUpRequiredError) Error() string { return ErrStepUpRequired.Error() }
+func (e *StepUpRequiredError) Unwrap() error { return ErrStepUpRequired }
+
+// LoginRiskRule signal de risque sur une connexion ; Matches ne décide pas de l'issue,
+// fixée par la configuration de la règle (LoginRiskPolicy)
+type LoginRiskRule interface {
+	Name() string
+	Matches(ctx context.Context, attempt *LoginAttempt) (bool, error)
+}
+
+// IPList liste d'adresses et de réseaux (nœuds de sortie TOR...) tenue à jour par un fournisseur
+type IPList interface {
+	Contains(ctx context.Context, ip string) (bool, error)
+}
+
+// NewCountryRule pays jamais vu parmi les connexions localisées de l'utilisateur ; sans
+// historique localisé, rien ne permet de comparer
+type NewCountryRule struct{}
+
+func (NewCountryRule) Name() string { return "new_country" }
+
+func (NewCountryRule) Matches(ctx context.Context, attempt *LoginAttempt) (bool, error) {
+	if attempt.Location == nil || attempt.Location.Country == "" {
+		return false, nil
+	}
+	located := false
+	for _, session := range attempt.Previous {
+		if session.Location == nil || session.Location.Country == "" {
+			continue
+		}
+		if session.Location.Country == attempt.Location.Country {
+			return false, nil
+		}
+		located = true
+	}
+	return located, nil
+}
+
+// impossibleTravelMinKm en deçà, l'imprécision de la géolocalisation domine
+const impossibleTravelMinKm = 300
+
+// ImpossibleTravelRule déplacement depuis la dernière connexion localisée plus rapide que
+// maxSpeedKmh (un vol commercial : ~900 km/h)
+type ImpossibleTravelRule struct {
+	maxSpeedKmh float64
+}
+
+func NewImpossibleTravelRule(maxSpeedKmh float64) *ImpossibleTravelRule {
+	return &ImpossibleTravelRule{maxSpeedKmh: maxSpeedKmh}
+}
+
+func (r *ImpossibleTravelRule) Name() string { return "impossible_travel" }
+
+func (r *ImpossibleTravelRule) Matches(ctx context.Context, attempt *LoginAttempt) (bool, error) {
+	if !attempt.Location.HasCoordinates() {
+		return false, nil
+	}
+	for _, session := range attempt.Previous {
+		if !session.Location.HasCoordinates() {
+			continue
+		}
+		distance := attempt.Location.DistanceKm(session.Location)
+		if distance < impossibleTravelMinKm {
+			return false, nil
+		}
+		hours := attempt.At.Sub(session.Created).Hours()
+		return hours <= 0 || distance/hours > r.maxSpeedKmh, nil
+	}
+	return false, nil
+}
+
+// IPListRule adresse du client présente dans une liste (nœuds de sortie TOR pour "tor")
+type IPListRule struct {
+	name string
+	list IPList
+}
+
+func NewIPListRule(name string, list IPList) *IPListRule {
+	return &IPListRule{name: name, list: list}
+}
+
+func (r *IPListRule) Name() string { return r.name }
+
+func (r *IPListRule) Matches(ctx context.Context, attempt *LoginAttempt) (bool, error) {
+	if attempt.IP == "" {
+		return false, nil
+	}
+	return r.list.Contains(ctx, attempt.IP)
+}
+
+// LoginRiskPolicy règle et issue appliquée quand elle se déclenche
+type LoginRiskPolicy struct {
+	Rule     LoginRiskRule
+	Decision RiskDecision
+}
+
+// riskSeverity ordre des issues : la plus sévère des règles déclenchées l'emporte
+var riskSeverity = map[RiskDecision]int{RiskAllow: 0, RiskStepUp: 1, RiskBlock: 2}
+
+// LoginRiskGuard évalue les connexions avant l'émission du jeton : blocage, ou vérification par
+// un code envoyé à l'adresse du compte. Chaque connexion qui déclenche une règle est tracée dans
+// le journal d'audit (action login_risk). Une règle en erreur (liste indisponible) est ignorée :
+// la connexion ne dépend pas d'un fournisseur externe
+type LoginRiskGuard struct {
+	policies      []LoginRiskPolicy
+	challengeRepo repositories.LoginChallengeRepository
+	userRepo      repositories.UserRepository
+	emailSender   EmailSender
+	audit         *AuditTrail
+	tokens        TokenGenerator
+	clock         Clock
+	logger        Logger
+	challengeTTL  time.Duration
+}
+
+func NewLoginRiskGuard(
+	policies []LoginRiskPolicy,
+	challengeRepo repositories.LoginChallengeRepository,
+	userRepo repositories.UserRepository,
+	emailSender EmailSender,
+	audit *AuditTrail,
+	tokens TokenGenerator,
+	clock Clock,
+	logger Logger,
+	challengeTTL time.Duration,
+) *LoginRiskGuard {
+	return &LoginRiskGuard{
+		policies:      policies,
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		emailSender:   emailSender,
+		audit:         audit,
+		tokens:        tokens,
+		clock:         clock,
+		logger:        logger,
+		challengeTTL:  challengeTTL,
+	}
+}
+
+// maxChallengeAttempts codes faux tolérés avant l'annulation de la vérification
+const maxChallengeAttempts = 5
+
+// Assess nil si la connexion est acceptée, ErrLoginBlocked, ou *StepUpRequiredError une fois
+// le code envoyé ; un guard nil accepte toutes les connexions
+func (g *LoginRiskGuard) Assess(ctx context.Context, verified *VerifiedCredentials, attempt *LoginAttempt) error {
+	if g == nil || len(g.policies) == 0 {
+		return nil
+	}
+	decision := RiskAllow
+	var triggered []string
+	for _, policy := range g.policies {
+		matched, err := policy.Rule.Matches(ctx, attempt)
+		if err != nil {
+			g.logger.Error("Login risk rule failed", err, map[string]interface{}{"rule": policy.Rule.Name(), "user_id": attempt.UserID})
+			continue
+		}
+		if !matched {
+			continue
+		}
+		triggered = append(triggered, policy.Rule.Name())
+		if riskSeverity[policy.Decision] > riskSeverity[decision] {
+			decision = policy.Decision
+		}
+	}
+	if len(triggered) == 0 {
+		return nil
+	}
+
+	details := "rules=" + strings.Join(triggered, ",") + " decision=" + string(decision)
+	switch decision {
+	case RiskBlock:
+		g.record(ctx, attempt.UserID, AuditDenied, details)
+		return ErrLoginBlocked
+	case RiskStepUp:
+		g.record(ctx, attempt.UserID, AuditChallenged, details)
+		return g.challenge(ctx, verified)
+	}
+	g.record(ctx, attempt.UserID, AuditSucceeded, details)
+	return nil
+}
+
+func (g *LoginRiskGuard) record(ctx context.Context, userID int, outcome, details string) {
+	if g.audit != nil {
+		g.audit.Record(ctx, "login_risk", userID, outcome, details)
+	}
+}
+
+// challenge ouvre la vérification et envoie le code à l'adresse du compte
+func (g *LoginRiskGuard) challenge(ctx context.Context, verified *VerifiedCredentials) error {
+	id, err := g.tokens.HexToken(16)
+	if err != nil {
+		return newError("erreur lors de la création de la vérification", err)
+	}
+	code, err := g.code()
+	if err != nil {
+		return newError("erreur lors de la création de la vérification", err)
+	}
+	expiresAt := g.clock.Now().Add(g.challengeTTL)
+	if err := g.challengeRepo.Save(ctx, repositories.LoginChallenge{
+		ID:        id,
+		UserID:    verified.User.ID,
+		TenantID:  verified.TenantID,
+		Roles:     verified.Roles,
+		CodeHash:  hashChallengeCode(id, code),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return newError("erreur lors de l'enregistrement de la vérification", err)
+	}
+
+	body := fmt.Sprintf("Bonjour %s,\n\nUne connexion inhabituelle à votre compte doit être confirmée. "+
+		"Code de vérification : %s (valable jusqu'à %s).\n\nSi vous n'êtes pas à l'origine de cette connexion, "+
+		"ne communiquez ce code à personne et changez votre mot de passe.\n",
+		verified.User.Name, code, expiresAt.UTC().Format("15:04 UTC"))
+	if err := g.emailSender.SendEmail(ctx, verified.User.Email, "Code de vérification de connexion", body); err != nil {
+		return newError("erreur lors de l'envoi du code de vérification", err)
+	}
+	return &StepUpRequiredError{ChallengeID: id, Method: "email", ExpiresAt: expiresAt}
+}
+
+// code six chiffres ; le biais du modulo sur 32 bits est négligeable
+func (g *LoginRiskGuard) code() (string, error) {
+	raw, err := g.tokens.HexToken(4)
+	if err != nil {
+		return "", err
+	}
+	value, err := strconv.ParseUint(raw, 16, 32)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", value%1_000_000), nil
+}
+
+// Verify identifiants de la connexion dont le code est correct ; la vérification est consommée
+func (g *LoginRiskGuard) Verify(ctx context.Context, challengeID, code string) (*VerifiedCredentials, error) {
+	if g == nil {
+		return nil, ErrInvalidLoginChallenge
+	}
+	challenge, err := g.challengeRepo.Get(ctx, challengeID, g.clock.Now())
+	if errors.Is(err, repositories.ErrLoginChallengeNotFound) {
+		return nil, ErrInvalidLoginChallenge
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de la vérification", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashChallengeCode(challenge.ID, code)), []byte(challenge.CodeHash)) != 1 {
+		challenge.Attempts++
+		if challenge.Attempts >= maxChallengeAttempts {
+			err = g.challengeRepo.Delete(ctx, challenge.ID)
+		} else {
+			err = g.challengeRepo.Save(ctx, *challenge)
+		}
+		if err != nil {
+			g.logger.Error("Failed to record challenge attempt", err, map[string]interface{}{"user_id": challenge.UserID})
+		}
+		return nil, ErrInvalidLoginChallenge
+	}
+	if err := g.challengeRepo.Delete(ctx, challenge.ID); err != nil {
+		return nil, newError("erreur lors de la clôture de la vérification", err)
+	}
+
+	user, err := g.userRepo.GetById(ctx, challenge.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrInvalidLoginChallenge
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la recherche de l'utilisateur", err)
+	}
+	return &VerifiedCredentials{User: user, TenantID: challenge.TenantID, Roles: challenge.Roles}, nil
+}
+
+// hashChallengeCode l'identifiant sale le hash : un code à six chiffres ne se devine pas
+// d'une vérification à l'autre
+func hashChallengeCode(challengeID, code string) string {
+	digest := sha256.Sum256([]byte(challengeID + ":" + code))
+	return hex.EncodeToString(digest[:])
+}
+
+// =============================================================================
+// VERIFY LOGIN USE CASE
+// =============================================================================
+
+// VerifyLoginUseCase termine une connexion à risque avec le code reçu par email
+type VerifyLoginUseCase struct {
+	sessions *SessionOpener
+}
+
+func NewVerifyLoginUseCase(sessions *SessionOpener) *VerifyLoginUseCase {
+	return &VerifyLoginUseCase{sessions: sessions}
+}
+
+type VerifyLoginRequest struct {
+	ChallengeID string `json:"challenge_id"`
+	Code        string `json:"code"`
+}
+
+func (req VerifyLoginRequest) Validate() error {
+	if req.ChallengeID == "" || req.Code == "" {
+		return ErrInvalidLoginChallenge
+	}
+	return nil
+}
+
+func (req VerifyLoginRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"challenge_id": req.ChallengeID}
+}
+
+func (uc *VerifyLoginUseCase) Execute(ctx context.Context, req VerifyLoginRequest) (*LoginResponse, error) {
+	return uc.sessions.OpenVerified(ctx, req.ChallengeID, req.Code)
+}
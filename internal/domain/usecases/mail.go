@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// =============================================================================
+// EMAILS TRANSACTIONNELS : port indépendant du fournisseur (SES, SendGrid, Mailgun)
+// =============================================================================
+
+// Mail email transactionnel. Le contenu est soit rédigé par l'application (Text et/ou HTML), soit
+// un modèle hébergé chez le fournisseur (Template, alimenté par TemplateData) ; un modèle porte
+// alors son propre sujet
+type Mail struct {
+	To      string
+	From    string // vide = expéditeur par défaut (EMAIL_FROM)
+	ReplyTo string
+	Subject string
+	Text    string
+	HTML    string
+
+	Template     string
+	TemplateData map[string]interface{}
+
+	Attachments []MailAttachment
+	// Categories étiquettes du fournisseur (statistiques, suivi des rebonds par type d'email)
+	Categories []string
+}
+
+// MailAttachment pièce jointe ; ContentType vide = application/octet-stream
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Catégories des emails émis par l'application
+const (
+	MailCategoryWelcome       = "welcome"
+	MailCategoryTransactional = "transactional"
+)
+
+func (m Mail) Validate() error {
+	if strings.TrimSpace(m.To) == "" {
+		return errors.New("destinataire manquant")
+	}
+	if m.Template == "" {
+		if m.Subject == "" {
+			return errors.New("sujet manquant")
+		}
+		if m.Text == "" && m.HTML == "" {
+			return errors.New("contenu manquant : Text, HTML ou Template")
+		}
+	}
+	for _, attachment := range m.Attachments {
+		if attachment.Filename == "" || strings.ContainsAny(attachment.Filename, "\"\r\n/\\") {
+			return errors.New("pièce jointe : nom de fichier invalide")
+		}
+	}
+	for _, category := range m.Categories {
+		if category == "" || strings.ContainsAny(category, " \r\n") {
+			return errors.New("catégorie invalide")
+		}
+	}
+	return nil
+}
+
+// MailProvider fournisseur d'envoi (EMAIL_PROVIDER). Send reçoit un email validé, expéditeur renseigné
+type MailProvider interface {
+	Name() string
+	Send(ctx context.Context, mail Mail) error
+	// HealthCheck identifiants acceptés et envoi autorisé ; appelé au démarrage
+	HealthCheck(ctx context.Context) error
+}
@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// =============================================================================
+// MODÉRATION DES CONTENUS SAISIS PAR LES UTILISATEURS
+// =============================================================================
+
+// ErrContentRejected cause des refus de modération (HTTP 422)
+var ErrContentRejected = errors.New("contenu non approprié")
+
+// Types de contenu modérés : les fournisseurs peuvent les traiter différemment
+const (
+	ModerateName   = "name"
+	ModerateHandle = "handle"
+	ModerateBio    = "bio"
+)
+
+// ModerationVerdict Reason catégorie du refus (insulte, haine...), pour les logs
+type ModerationVerdict struct {
+	Allowed bool
+	Reason  string
+}
+
+// ContentModeration évalue un texte saisi par un utilisateur (services.WordListModeration,
+// services.APIContentModeration)
+type ContentModeration interface {
+	Moderate(ctx context.Context, kind, text string) (*ModerationVerdict, error)
+}
+
+// ModeratedText champ d'une requête soumis à la modération
+type ModeratedText struct {
+	Field string
+	Kind  string
+	Text  string
+}
+
+// Moderated permet à un DTO d'exposer les textes publics à modérer avant l'exécution
+// Les comptes provisionnés par un annuaire (SCIM, RH, LDAP) ne passent pas par là : source de confiance
+type Moderated interface {
+	ModeratedContent() []ModeratedText
+}
+
+// WithModeration refuse les requêtes Moderated dont un texte est rejeté (nil = aucune modération)
+func WithModeration[I, O any](name string, moderation ContentModeration, logger Logger) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if moderation == nil {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			if moderated, ok := any(input).(Moderated); ok {
+				for _, text := range moderated.ModeratedContent() {
+					if text.Text == "" {
+						continue
+					}
+					verdict, err := moderation.Moderate(ctx, text.Kind, text.Text)
+					if err != nil {
+						var zero O
+						return zero, newError("erreur lors de la modération du contenu", err)
+					}
+					if !verdict.Allowed {
+						logger.Warn("Content rejected by moderation", map[string]interface{}{
+							"use_case": name, "field": text.Field, "reason": verdict.Reason,
+						})
+						var zero O
+						return zero, fmt.Errorf("%s : %w", text.Field, ErrContentRejected)
+					}
+				}
+			}
+			return next.Execute(ctx, input)
+		})
+	}
+}
+
+// =============================================================================
+// CONTENUS MODÉRÉS DES REQUÊTES
+// =============================================================================
+
+func (req CreateUserRequest) ModeratedContent() []ModeratedText {
+	return []ModeratedText{{Field: "name", Kind: ModerateName, Text: req.Name}}
+}
+
+func (req UpdateUserRequest) ModeratedContent() []ModeratedText {
+	return []ModeratedText{{Field: "name", Kind: ModerateName, Text: req.Name}}
+}
+
+func (req PatchUserRequest) ModeratedContent() []ModeratedText {
+	if req.Name == nil {
+		return nil
+	}
+	return []ModeratedText{{Field: "name", Kind: ModerateName, Text: *req.Name}}
+}
+
+func (req SetUserHandleRequest) ModeratedContent() []ModeratedText {
+	return []ModeratedText{{Field: "handle", Kind: ModerateHandle, Text: req.Handle}}
+}
+
+func (req StartOnboardingRequest) ModeratedContent() []ModeratedText {
+	return []ModeratedText{{Field: "name", Kind: ModerateName, Text: req.Name}}
+}
+
+func (req BulkCreateUsersRequest) ModeratedContent() []ModeratedText {
+	texts := make([]ModeratedText, 0, len(req.Users))
+	for i, user := range req.Users {
+		texts = append(texts, ModeratedText{Field: "users[" + strconv.Itoa(i) + "].name", Kind: ModerateName, Text: user.Name})
+	}
+	return texts
+}
+
+func (req BulkUpdateUsersRequest) ModeratedContent() []ModeratedText {
+	texts := make([]ModeratedText, 0, len(req.Users))
+	for i, user := range req.Users {
+		texts = append(texts, ModeratedText{Field: "users[" + strconv.Itoa(i) + "].name", Kind: ModerateName, Text: user.Name})
+	}
+	return texts
+}
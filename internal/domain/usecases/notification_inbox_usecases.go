@@ -0,0 +1,165 @@
+// internal/domain/usecases/notification_inbox_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+)
+
+// NotificationPusher interface pour pousser en temps réel une notification aux clients connectés
+type NotificationPusher interface {
+	Push(ctx context.Context, notification *entities.Notification)
+}
+
+// =============================================================================
+// LIST NOTIFICATIONS USE CASE
+// =============================================================================
+
+type ListNotificationsUseCase struct {
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewListNotificationsUseCase(notificationRepo repositories.NotificationRepository) *ListNotificationsUseCase {
+	return &ListNotificationsUseCase{notificationRepo: notificationRepo}
+}
+
+type ListNotificationsRequest struct {
+	UserID     int  `json:"-"`
+	UnreadOnly bool `json:"unread_only"`
+	Page       int  `json:"page"`
+	PageSize   int  `json:"page_size"`
+}
+
+func (req ListNotificationsRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type ListNotificationsResponse struct {
+	Notifications []*entities.Notification `json:"notifications"`
+	Total         int                      `json:"total"`
+	UnreadCount   int                      `json:"unread_count"`
+	Page          int                      `json:"page"`
+	PageSize      int                      `json:"page_size"`
+}
+
+func (req ListNotificationsRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if req.Page < 0 {
+		return errors.New("page invalide")
+	}
+	if req.PageSize < 0 || req.PageSize > 100 {
+		return errors.New("page_size doit être compris entre 1 et 100")
+	}
+	return nil
+}
+
+func (req ListNotificationsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "unread_only": req.UnreadOnly, "page": req.Page}
+}
+
+func (uc *ListNotificationsUseCase) Execute(ctx context.Context, req ListNotificationsRequest) (*ListNotificationsResponse, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	// Page et compteurs lus en parallèle
+	var (
+		notifications []*entities.Notification
+		total, unread int
+	)
+	err := runConcurrently(ctx,
+		func(ctx context.Context) error {
+			var err error
+			notifications, err = uc.notificationRepo.ListByUser(ctx, req.UserID, req.UnreadOnly, req.PageSize, (req.Page-1)*req.PageSize)
+			if err != nil {
+				return newError("erreur lors de la récupération des notifications", err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			var err error
+			total, unread, err = uc.notificationRepo.CountByUser(ctx, req.UserID)
+			if err != nil {
+				return newError("erreur lors du comptage des notifications", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if req.UnreadOnly {
+		total = unread
+	}
+
+	return &ListNotificationsResponse{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+	}, nil
+}
+
+// =============================================================================
+// MARK AS READ USE CASE
+// =============================================================================
+
+type MarkAsReadUseCase struct {
+	notificationRepo repositories.NotificationRepository
+	clock            Clock
+}
+
+func NewMarkAsReadUseCase(notificationRepo repositories.NotificationRepository, clock Clock) *MarkAsReadUseCase {
+	return &MarkAsReadUseCase{notificationRepo: notificationRepo, clock: clock}
+}
+
+type MarkAsReadRequest struct {
+	UserID         int
+	NotificationID int
+}
+
+func (req MarkAsReadRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type MarkAsReadResponse struct {
+	Notification *entities.Notification `json:"notification"`
+	UnreadCount  int                    `json:"unread_count"`
+}
+
+func (req MarkAsReadRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "notification_id": req.NotificationID}
+}
+
+func (uc *MarkAsReadUseCase) Execute(ctx context.Context, req MarkAsReadRequest) (*MarkAsReadResponse, error) {
+	notification, err := uc.notificationRepo.GetById(ctx, req.NotificationID)
+	// Une notification d'un autre utilisateur est traitée comme inexistante
+	if err == nil && notification.UserID != req.UserID {
+		err = repositories.ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, newError("notification non trouvée", err)
+	}
+
+	// Idempotent : relire une notification déjà lue ne change pas sa date de lecture
+	if notification.MarkAsRead(uc.clock.Now()) {
+		if err := uc.notificationRepo.Update(ctx, notification); err != nil {
+			return nil, newError("erreur lors de la mise à jour de la notification", err)
+		}
+	}
+
+	_, unread, err := uc.notificationRepo.CountByUser(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("erreur lors du comptage des notifications", err)
+	}
+
+	return &MarkAsReadResponse{Notification: notification, UnreadCount: unread}, nil
+}
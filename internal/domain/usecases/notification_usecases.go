@@ -0,0 +1,326 @@
+// internal/domain/usecases/notification_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// =============================================================================
+// PORT NOTIFIER (un adaptateur par canal)
+// =============================================================================
+
+// NotificationMessage notification prête à être envoyée, avec les coordonnées du destinataire
+type NotificationMessage struct {
+	UserID    int
+	Email     string
+	Phone     string
+	Name      string
+	EventType string
+	Title     string
+	Body      string
+}
+
+// Notifier interface pour livrer une notification sur un canal donné
+type Notifier interface {
+	Channel() entities.NotificationChannel
+	Notify(ctx context.Context, message NotificationMessage) error
+}
+
+// =============================================================================
+// NOTIFICATION ROUTER : événements du domaine → notifications filtrées par préférences
+// =============================================================================
+
+type NotificationRouter struct {
+	userRepo  repositories.UserRepository
+	prefRepo  repositories.NotificationPreferenceRepository
+	notifiers []Notifier
+}
+
+func NewNotificationRouter(
+	userRepo repositories.UserRepository,
+	prefRepo repositories.NotificationPreferenceRepository,
+	notifiers ...Notifier,
+) *NotificationRouter {
+	return &NotificationRouter{
+		userRepo:  userRepo,
+		prefRepo:  prefRepo,
+		notifiers: notifiers,
+	}
+}
+
+// Handle est abonné au bus d'événements
+func (r *NotificationRouter) Handle(ctx context.Context, event events.Event) error {
+	userID, title, body, ok := describeNotifiableEvent(event)
+	if !ok {
+		return nil
+	}
+
+	user, err := r.userRepo.GetById(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := loadPreferenceMatrix(ctx, r.prefRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	message := NotificationMessage{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Name:      user.Name,
+		EventType: event.EventName(),
+		Title:     title,
+		Body:      body,
+	}
+
+	return deliverNotification(ctx, r.notifiers, preferences, message)
+}
+
+// deliverNotification envoie message sur chaque canal activé par les préférences du destinataire
+func deliverNotification(ctx context.Context, notifiers []Notifier, preferences preferenceMatrix, message NotificationMessage) error {
+	var failures []error
+	for _, notifier := range notifiers {
+		channel := notifier.Channel()
+		if !preferences.enabled(channel, message.EventType) {
+			continue
+		}
+		if channel == entities.ChannelSMS && message.Phone == "" {
+			continue
+		}
+
+		if err := notifier.Notify(ctx, message); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", channel, err))
+		}
+	}
+
+	return errors.Join(failures...)
+}
+
+// describeNotifiableEvent construit le contenu de la notification d'un événement
+func describeNotifiableEvent(event events.Event) (userID int, title, body string, ok bool) {
+	switch e := event.(type) {
+	case events.UserCreated:
+		return e.UserID, "Bienvenue " + e.Name, "Votre compte a bien été créé.", true
+	case events.UserProfileUpdated:
+		return e.UserID, "Profil modifié", "Votre nom ou votre email a été modifié. Si ce n'est pas vous, contactez le support.", true
+	case events.UserImpersonated:
+		return e.UserID, "Session d'assistance ouverte", "Un administrateur accède à votre compte pour vous assister, jusqu'à " +
+			e.ExpiresAt.UTC().Format("15:04 UTC") + ". Chacune de ses actions est journalisée.", true
+	case events.NewDeviceLogin:
+		return e.UserID, "Connexion depuis un nouvel appareil", "Nouvelle connexion à votre compte : " + describeDevice(e) +
+			" le " + e.At.UTC().Format("02/01/2006 à 15:04 UTC") + ". Si ce n'est pas vous, changez votre mot de passe et contactez le support.", true
+	default:
+		return 0, "", "", false
+	}
+}
+
+// describeDevice "Chrome sur Windows (desktop), Lyon, FR" ; les éléments inconnus sont omis
+func describeDevice(e events.NewDeviceLogin) string {
+	device := e.Browser
+	if device == "" {
+		device = "navigateur inconnu"
+	}
+	if e.OS != "" {
+		device += " sur " + e.OS
+	}
+	device += " (" + e.Device + ")"
+	if e.City != "" {
+		device += ", " + e.City
+	}
+	if e.Country != "" {
+		device += ", " + e.Country
+	}
+	return device
+}
+
+// preferenceMatrix préférences explicites indexées par canal puis type d'événement
+type preferenceMatrix map[entities.NotificationChannel]map[string]bool
+
+func loadPreferenceMatrix(ctx context.Context, prefRepo repositories.NotificationPreferenceRepository, userID int) (preferenceMatrix, error) {
+	stored, err := prefRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := make(preferenceMatrix)
+	for _, preference := range stored {
+		if matrix[preference.Channel] == nil {
+			matrix[preference.Channel] = make(map[string]bool)
+		}
+		matrix[preference.Channel][preference.EventType] = preference.Enabled
+	}
+	return matrix, nil
+}
+
+func (m preferenceMatrix) enabled(channel entities.NotificationChannel, eventType string) bool {
+	if enabled, explicit := m[channel][eventType]; explicit {
+		return enabled
+	}
+	return entities.DefaultNotificationEnabled(channel)
+}
+
+// =============================================================================
+// GET NOTIFICATION PREFERENCES USE CASE
+// =============================================================================
+
+type NotificationPreferenceDTO struct {
+	Channel   entities.NotificationChannel `json:"channel"`
+	EventType string                       `json:"event_type"`
+	Enabled   bool                         `json:"enabled"`
+	IsDefault bool                         `json:"is_default,omitempty"`
+}
+
+type NotificationPreferencesResponse struct {
+	UserID      int                         `json:"user_id"`
+	Phone       string                      `json:"phone,omitempty"`
+	Preferences []NotificationPreferenceDTO `json:"preferences"`
+}
+
+type GetNotificationPreferencesUseCase struct {
+	userRepo repositories.UserRepository
+	prefRepo repositories.NotificationPreferenceRepository
+}
+
+func NewGetNotificationPreferencesUseCase(
+	userRepo repositories.UserRepository,
+	prefRepo repositories.NotificationPreferenceRepository,
+) *GetNotificationPreferencesUseCase {
+	return &GetNotificationPreferencesUseCase{
+		userRepo: userRepo,
+		prefRepo: prefRepo,
+	}
+}
+
+func (uc *GetNotificationPreferencesUseCase) Execute(ctx context.Context, userID int) (*NotificationPreferencesResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, userID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	return buildPreferencesResponse(ctx, uc.prefRepo, user)
+}
+
+// buildPreferencesResponse retourne la matrice complète canal × événement (valeurs par défaut incluses)
+func buildPreferencesResponse(ctx context.Context, prefRepo repositories.NotificationPreferenceRepository, user *entities.User) (*NotificationPreferencesResponse, error) {
+	matrix, err := loadPreferenceMatrix(ctx, prefRepo, user.ID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des préférences", err)
+	}
+
+	response := &NotificationPreferencesResponse{UserID: user.ID, Phone: user.Phone}
+	for _, channel := range entities.NotificationChannels {
+		for _, eventType := range entities.NotifiableEventTypes {
+			_, explicit := matrix[channel][eventType]
+			response.Preferences = append(response.Preferences, NotificationPreferenceDTO{
+				Channel:   channel,
+				EventType: eventType,
+				Enabled:   matrix.enabled(channel, eventType),
+				IsDefault: !explicit,
+			})
+		}
+	}
+	return response, nil
+}
+
+// =============================================================================
+// UPDATE NOTIFICATION PREFERENCES USE CASE
+// =============================================================================
+
+type UpdateNotificationPreferencesUseCase struct {
+	userRepo  repositories.UserRepository
+	prefRepo  repositories.NotificationPreferenceRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewUpdateNotificationPreferencesUseCase(
+	userRepo repositories.UserRepository,
+	prefRepo repositories.NotificationPreferenceRepository,
+	publisher EventPublisher,
+	clock Clock,
+) *UpdateNotificationPreferencesUseCase {
+	return &UpdateNotificationPreferencesUseCase{
+		userRepo:  userRepo,
+		prefRepo:  prefRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	UserID int `json:"-"`
+	// Phone nil = inchangé, "" = supprimé
+	Phone       *string `json:"phone,omitempty"`
+	Preferences []struct {
+		Channel   entities.NotificationChannel `json:"channel"`
+		EventType string                       `json:"event_type"`
+		Enabled   bool                         `json:"enabled"`
+	} `json:"preferences"`
+}
+
+func (req UpdateNotificationPreferencesRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+func (req UpdateNotificationPreferencesRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	return nil
+}
+
+func (req UpdateNotificationPreferencesRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "preferences": len(req.Preferences)}
+}
+
+func (uc *UpdateNotificationPreferencesUseCase) Execute(ctx context.Context, req UpdateNotificationPreferencesRequest) (*NotificationPreferencesResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	// 1. Valider toutes les préférences avant d'écrire quoi que ce soit
+	now := uc.clock.Now()
+	preferences := make([]*entities.NotificationPreference, 0, len(req.Preferences))
+	for _, input := range req.Preferences {
+		preference, err := entities.NewNotificationPreference(user.ID, input.Channel, input.EventType, input.Enabled, now)
+		if err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, preference)
+	}
+
+	// 2. Mettre à jour le numéro de téléphone si demandé
+	if req.Phone != nil {
+		changed, err := user.SetPhone(*req.Phone, now)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			if _, err := uc.userRepo.Update(ctx, user); err != nil {
+				return nil, newError("erreur lors de la mise à jour", err)
+			}
+			uc.publisher.Publish(ctx, events.UserPhoneChanged{
+				UserID:  user.ID,
+				Phone:   user.Phone,
+				Changed: user.Updated,
+			})
+		}
+	}
+
+	// 3. Enregistrer les préférences
+	for _, preference := range preferences {
+		if err := uc.prefRepo.Save(ctx, preference); err != nil {
+			return nil, newError("erreur lors de l'enregistrement des préférences", err)
+		}
+	}
+
+	return buildPreferencesResponse(ctx, uc.prefRepo, user)
+}
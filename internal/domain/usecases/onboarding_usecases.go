@@ -0,0 +1,416 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// INSCRIPTION EN PLUSIEURS ÉTAPES (saga)
+// =============================================================================
+
+// Statuts d'une inscription
+const (
+	OnboardingAwaitingConfirmation = "awaiting_confirmation"
+	OnboardingCompleted            = "completed"
+	// OnboardingCompensated une étape a échoué (ou la confirmation a expiré) : tout a été annulé
+	OnboardingCompensated = "compensated"
+	// OnboardingCompensationFailed une annulation n'a pas abouti : intervention manuelle requise
+	OnboardingCompensationFailed = "compensation_failed"
+)
+
+var (
+	errOnboardingExpired = errors.New("délai de confirmation expiré")
+	errOnboardingToken   = errors.New("jeton de confirmation invalide")
+	errOnboardingSettled = errors.New("inscription déjà traitée")
+)
+
+// WorkspaceProvisioner crée l'espace analytics d'un nouvel utilisateur ; DeleteWorkspace est
+// l'action compensatoire (sans effet sur un espace déjà supprimé)
+type WorkspaceProvisioner interface {
+	ProvisionWorkspace(ctx context.Context, userID int, email string) (string, error)
+	DeleteWorkspace(ctx context.Context, workspaceID string) error
+}
+
+// onboardingState état de la saga : l'inscription persistée et, au démarrage seulement,
+// les secrets qui ne sont jamais stockés
+type onboardingState struct {
+	record   repositories.Onboarding
+	password string
+}
+
+// OnboardingCoordinator déroule l'inscription :
+// création du compte → email de vérification → attente de la confirmation → espace analytics
+// → notification des administrateurs. Une étape en échec, ou une confirmation qui n'arrive pas
+// avant ttl, annule les précédentes (suppression de l'espace puis du compte)
+type OnboardingCoordinator struct {
+	onboardingRepo repositories.OnboardingRepository
+	// createUser / deleteUser use cases nus : l'inscription est autorisée et tracée en tant que telle
+	createUser  UseCase[CreateUserRequest, *CreateUserResponse]
+	deleteUser  UseCase[int, struct{}]
+	emailSender EmailSender
+	workspaces  WorkspaceProvisioner
+	tokens      TokenGenerator
+	clock       Clock
+	logger      Logger
+	adminEmails []string
+	ttl         time.Duration
+
+	saga *Saga[onboardingState]
+	// mutex une seule reprise à la fois : confirmation et expiration ne se croisent pas
+	mutex sync.Mutex
+}
+
+func NewOnboardingCoordinator(
+	onboardingRepo repositories.OnboardingRepository,
+	createUser UseCase[CreateUserRequest, *CreateUserResponse],
+	deleteUser UseCase[int, struct{}],
+	emailSender EmailSender,
+	workspaces WorkspaceProvisioner,
+	tokens TokenGenerator,
+	clock Clock,
+	logger Logger,
+	adminEmails []string,
+	ttl time.Duration,
+) *OnboardingCoordinator {
+	c := &OnboardingCoordinator{
+		onboardingRepo: onboardingRepo,
+		createUser:     createUser,
+		deleteUser:     deleteUser,
+		emailSender:    emailSender,
+		workspaces:     workspaces,
+		tokens:         tokens,
+		clock:          clock,
+		logger:         logger,
+		adminEmails:    adminEmails,
+		ttl:            ttl,
+	}
+	c.saga = NewSaga("onboarding", logger,
+		SagaStep[onboardingState]{Name: "create_user", Run: c.createAccount, Compensate: c.deleteAccount},
+		SagaStep[onboardingState]{Name: "send_verification", Run: c.sendVerification},
+		SagaStep[onboardingState]{Name: "await_confirmation", Run: c.awaitConfirmation},
+		SagaStep[onboardingState]{Name: "provision_workspace", Run: c.provisionWorkspace, Compensate: c.deleteWorkspace},
+		SagaStep[onboardingState]{Name: "notify_admins", Run: c.notifyAdmins},
+	)
+	return c
+}
+
+func (c *OnboardingCoordinator) createAccount(ctx context.Context, state *onboardingState) error {
+	created, err := c.createUser.Execute(ctx, CreateUserRequest{
+		Email: state.record.Email, Name: state.record.Name, Password: state.password,
+	})
+	if err != nil {
+		return err
+	}
+	state.record.UserID = created.ID
+	return nil
+}
+
+func (c *OnboardingCoordinator) deleteAccount(ctx context.Context, state *onboardingState) error {
+	_, err := c.deleteUser.Execute(ctx, state.record.UserID)
+	return err
+}
+
+func (c *OnboardingCoordinator) sendVerification(ctx context.Context, state *onboardingState) error {
+	token, err := c.tokens.Token(24)
+	if err != nil {
+		return err
+	}
+	state.record.VerificationHash = hashOnboardingToken(token)
+	state.record.ExpiresAt = c.clock.Now().Add(c.ttl)
+	body := fmt.Sprintf("Bonjour %s,\n\nPour activer votre compte, confirmez votre adresse avant le %s :\n\n"+
+		"POST /onboarding/%s/confirm {\"token\": \"%s\"}\n\nSans confirmation, l'inscription sera annulée.\n",
+		state.record.Name, state.record.ExpiresAt.Format("02/01/2006 15:04 MST"), state.record.ID, token)
+	return c.emailSender.SendEmail(ctx, state.record.Email, "Confirmez votre adresse email", body)
+}
+
+func (c *OnboardingCoordinator) awaitConfirmation(ctx context.Context, state *onboardingState) error {
+	if state.record.Confirmed {
+		return nil
+	}
+	if c.clock.Now().After(state.record.ExpiresAt) {
+		return errOnboardingExpired
+	}
+	return ErrSagaSuspended
+}
+
+func (c *OnboardingCoordinator) provisionWorkspace(ctx context.Context, state *onboardingState) error {
+	// Étape rejouée après un échec de sauvegarde : l'espace existe déjà
+	if state.record.WorkspaceID != "" {
+		return nil
+	}
+	workspaceID, err := c.workspaces.ProvisionWorkspace(ctx, state.record.UserID, state.record.Email)
+	if err != nil {
+		return err
+	}
+	state.record.WorkspaceID = workspaceID
+	return nil
+}
+
+func (c *OnboardingCoordinator) deleteWorkspace(ctx context.Context, state *onboardingState) error {
+	if state.record.WorkspaceID == "" {
+		return nil
+	}
+	return c.workspaces.DeleteWorkspace(ctx, state.record.WorkspaceID)
+}
+
+func (c *OnboardingCoordinator) notifyAdmins(ctx context.Context, state *onboardingState) error {
+	body := fmt.Sprintf("Nouvel utilisateur inscrit : %s <%s> (ID %d), espace analytics %s.\n",
+		state.record.Name, state.record.Email, state.record.UserID, state.record.WorkspaceID)
+	for _, admin := range c.adminEmails {
+		if err := c.emailSender.SendEmail(ctx, admin, "Nouvelle inscription", body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// advance exécute la saga depuis la prochaine étape de l'inscription et enregistre le résultat
+func (c *OnboardingCoordinator) advance(ctx context.Context, state *onboardingState) error {
+	next, err := c.saga.Run(ctx, state, state.record.NextStep)
+	state.record.NextStep = next
+	return c.save(ctx, state, err)
+}
+
+// save enregistre l'issue d'une exécution de la saga ; une saga compensée retourne son erreur
+func (c *OnboardingCoordinator) save(ctx context.Context, state *onboardingState, runErr error) error {
+	var sagaErr *SagaError
+	switch {
+	case runErr == nil:
+		state.record.Status = OnboardingCompleted
+	case errors.Is(runErr, ErrSagaSuspended):
+		state.record.Status = OnboardingAwaitingConfirmation
+	case errors.As(runErr, &sagaErr):
+		state.record.Status = OnboardingCompensated
+		if len(sagaErr.Compensations) > 0 {
+			state.record.Status = OnboardingCompensationFailed
+		}
+		state.record.FailedStep, state.record.Error = sagaErr.Step, sagaErr.Error()
+	default:
+		return runErr
+	}
+	state.record.Updated = c.clock.Now()
+	if err := c.onboardingRepo.Save(context.WithoutCancel(ctx), state.record); err != nil {
+		return newError("erreur lors de l'enregistrement de l'inscription", err)
+	}
+	if sagaErr != nil {
+		return newError("l'inscription n'a pas pu aboutir et a été annulée", sagaErr)
+	}
+	return nil
+}
+
+func hashOnboardingToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+type OnboardingResponse struct {
+	ID          string    `json:"id"`
+	Status      string    `json:"status"`
+	UserID      int       `json:"user_id,omitempty"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+	FailedStep  string    `json:"failed_step,omitempty"`
+}
+
+func (r *OnboardingResponse) AuditUserID() int {
+	if r == nil {
+		return 0
+	}
+	return r.UserID
+}
+
+func toOnboardingResponse(record repositories.Onboarding) *OnboardingResponse {
+	response := &OnboardingResponse{
+		ID:          record.ID,
+		Status:      record.Status,
+		UserID:      record.UserID,
+		WorkspaceID: record.WorkspaceID,
+		FailedStep:  record.FailedStep,
+	}
+	if record.Status == OnboardingAwaitingConfirmation {
+		response.ExpiresAt = record.ExpiresAt
+	}
+	return response
+}
+
+// =============================================================================
+// USE CASES
+// =============================================================================
+
+type StartOnboardingUseCase struct {
+	coordinator *OnboardingCoordinator
+}
+
+func NewStartOnboardingUseCase(coordinator *OnboardingCoordinator) *StartOnboardingUseCase {
+	return &StartOnboardingUseCase{coordinator: coordinator}
+}
+
+type StartOnboardingRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (req StartOnboardingRequest) QuotaUsage() map[string]int {
+	return map[string]int{repositories.UsageUsers: 1}
+}
+
+func (req StartOnboardingRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"email": req.Email, "name": req.Name}
+}
+
+// Execute crée le compte et envoie l'email de vérification ; la saga attend ensuite la confirmation
+func (uc *StartOnboardingUseCase) Execute(ctx context.Context, req StartOnboardingRequest) (*OnboardingResponse, error) {
+	c := uc.coordinator
+	id, err := c.tokens.HexToken(16)
+	if err != nil {
+		return nil, newError("erreur lors de la création de l'inscription", err)
+	}
+	now := c.clock.Now()
+	state := &onboardingState{
+		record:   repositories.Onboarding{ID: id, Email: req.Email, Name: req.Name, Created: now, Updated: now},
+		password: req.Password,
+	}
+	next, runErr := c.saga.Run(ctx, state, 0)
+	state.record.NextStep = next
+	// Compte refusé (email pris, mot de passe trop court...) : rien n'a été fait, rien n'est conservé
+	var sagaErr *SagaError
+	if errors.As(runErr, &sagaErr) && next == 0 {
+		return nil, sagaErr.Err
+	}
+	if err := c.save(ctx, state, runErr); err != nil {
+		return nil, err
+	}
+	return toOnboardingResponse(state.record), nil
+}
+
+type ConfirmOnboardingUseCase struct {
+	coordinator *OnboardingCoordinator
+}
+
+func NewConfirmOnboardingUseCase(coordinator *OnboardingCoordinator) *ConfirmOnboardingUseCase {
+	return &ConfirmOnboardingUseCase{coordinator: coordinator}
+}
+
+type ConfirmOnboardingRequest struct {
+	ID    string `json:"-"`
+	Token string `json:"token"`
+}
+
+func (req ConfirmOnboardingRequest) Validate() error {
+	if req.ID == "" || req.Token == "" {
+		return errOnboardingToken
+	}
+	return nil
+}
+
+func (req ConfirmOnboardingRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"onboarding_id": req.ID}
+}
+
+// Execute valide le jeton puis reprend la saga : espace analytics, notification des administrateurs
+func (uc *ConfirmOnboardingUseCase) Execute(ctx context.Context, req ConfirmOnboardingRequest) (*OnboardingResponse, error) {
+	c := uc.coordinator
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	record, err := c.onboardingRepo.Get(ctx, req.ID)
+	if errors.Is(err, repositories.ErrOnboardingNotFound) {
+		return nil, errOnboardingToken
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'inscription", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashOnboardingToken(req.Token)), []byte(record.VerificationHash)) != 1 {
+		return nil, errOnboardingToken
+	}
+	if record.Status != OnboardingAwaitingConfirmation {
+		return nil, errOnboardingSettled
+	}
+
+	state := &onboardingState{record: *record}
+	state.record.Confirmed = !c.clock.Now().After(record.ExpiresAt)
+	if err := c.advance(ctx, state); err != nil {
+		return nil, err
+	}
+	return toOnboardingResponse(state.record), nil
+}
+
+// GetOnboardingUseCase statut d'une inscription (l'identifiant, aléatoire, vaut autorisation de lecture)
+type GetOnboardingUseCase struct {
+	onboardingRepo repositories.OnboardingRepository
+}
+
+func NewGetOnboardingUseCase(onboardingRepo repositories.OnboardingRepository) *GetOnboardingUseCase {
+	return &GetOnboardingUseCase{onboardingRepo: onboardingRepo}
+}
+
+func (uc *GetOnboardingUseCase) Execute(ctx context.Context, id string) (*OnboardingResponse, error) {
+	record, err := uc.onboardingRepo.Get(ctx, id)
+	if errors.Is(err, repositories.ErrOnboardingNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'inscription", err)
+	}
+	return toOnboardingResponse(*record), nil
+}
+
+// ExpireOnboardingsUseCase annule les inscriptions non confirmées à temps (tâche planifiée)
+type ExpireOnboardingsUseCase struct {
+	coordinator *OnboardingCoordinator
+}
+
+func NewExpireOnboardingsUseCase(coordinator *OnboardingCoordinator) *ExpireOnboardingsUseCase {
+	return &ExpireOnboardingsUseCase{coordinator: coordinator}
+}
+
+type ExpireOnboardingsRequest struct {
+	Now time.Time
+}
+
+type ExpireOnboardingsResponse struct {
+	Expired int `json:"expired"`
+	// CompensationFailed inscriptions dont l'annulation n'a pas abouti (voir les logs)
+	CompensationFailed int `json:"compensation_failed"`
+}
+
+func (uc *ExpireOnboardingsUseCase) Execute(ctx context.Context, req ExpireOnboardingsRequest) (*ExpireOnboardingsResponse, error) {
+	c := uc.coordinator
+	if req.Now.IsZero() {
+		req.Now = c.clock.Now()
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	waiting, err := c.onboardingRepo.ListByStatus(ctx, OnboardingAwaitingConfirmation)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des inscriptions", err)
+	}
+	response := &ExpireOnboardingsResponse{}
+	for _, record := range waiting {
+		if !req.Now.After(record.ExpiresAt) {
+			continue
+		}
+		state := &onboardingState{record: record}
+		abortErr := c.saga.Abort(ctx, state, record.NextStep, errOnboardingExpired)
+		// Une saga compensée n'est pas une erreur du job ; un échec d'enregistrement l'arrête
+		var sagaErr *SagaError
+		if err := c.save(ctx, state, abortErr); err != nil && !errors.As(err, &sagaErr) {
+			return response, err
+		}
+		if state.record.Status == OnboardingCompensationFailed {
+			response.CompensationFailed++
+		}
+		response.Expired++
+	}
+	return response, nil
+}
@@ -0,0 +1,387 @@
+// internal/domain/usecases/pipeline.go
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// =============================================================================
+// CONTRAT GÉNÉRIQUE DES USE CASES
+// =============================================================================
+
+// UseCase contrat commun à tous les use cases : une entrée, une sortie, une erreur
+// Il permet d'empiler des décorateurs transverses sans toucher au code métier
+type UseCase[I, O any] interface {
+	Execute(ctx context.Context, input I) (O, error)
+}
+
+// UseCaseFunc adapte une fonction en UseCase (ex: GetUserUseCase.ExecuteByID)
+type UseCaseFunc[I, O any] func(ctx context.Context, input I) (O, error)
+
+func (f UseCaseFunc[I, O]) Execute(ctx context.Context, input I) (O, error) {
+	return f(ctx, input)
+}
+
+// Command adapte un use case sans sortie (ex: DeleteUserUseCase) en UseCase
+func Command[I any](fn func(ctx context.Context, input I) error) UseCase[I, struct{}] {
+	return UseCaseFunc[I, struct{}](func(ctx context.Context, input I) (struct{}, error) {
+		return struct{}{}, fn(ctx, input)
+	})
+}
+
+// Decorator enveloppe un use case pour lui ajouter un comportement transverse
+type Decorator[I, O any] func(next UseCase[I, O]) UseCase[I, O]
+
+// Decorate applique les décorateurs dans l'ordre : le premier est le plus externe
+func Decorate[I, O any](useCase UseCase[I, O], decorators ...Decorator[I, O]) UseCase[I, O] {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		useCase = decorators[i](useCase)
+	}
+	return useCase
+}
+
+// =============================================================================
+// ERREURS DES USE CASES
+// =============================================================================
+
+// Error erreur renvoyée par un use case : Message est destiné au client,
+// Cause conserve l'erreur technique pour les logs (elle n'est jamais exposée)
+type Error struct {
+	Message string
+	Cause   error
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+// ErrTimeout cause des erreurs de use case interrompus par WithTimeout (HTTP 504)
+var ErrTimeout = errors.New("use case timed out")
+
+func newError(message string, cause error) error {
+	return &Error{Message: message, Cause: cause}
+}
+
+// eachError erreur d'un parcours UserRepository.Each : celles du callback sont déjà des erreurs
+// de use case et remontent telles quelles, seules celles du stockage prennent message
+func eachError(message string, err error) error {
+	var useCaseErr *Error
+	if errors.As(err, &useCaseErr) {
+		return err
+	}
+	return newError(message, err)
+}
+
+// =============================================================================
+// PORTS UTILISÉS PAR LES DÉCORATEURS
+// =============================================================================
+
+// Loggable permet à un DTO d'exposer des champs de log sûrs (jamais de mot de passe)
+type Loggable interface {
+	LogFields() map[string]interface{}
+}
+
+// Validatable est implémenté par les DTOs qui valident leur propre format
+type Validatable interface {
+	Validate() error
+}
+
+// Metrics interface pour mesurer les exécutions de use cases
+type Metrics interface {
+	ObserveUseCase(name string, duration time.Duration, err error)
+}
+
+// Span représente une opération tracée
+type Span interface {
+	End(err error)
+}
+
+// Tracer interface pour le tracing distribué
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Authorizer décide si l'acteur courant peut exécuter un use case avec cette entrée
+type Authorizer interface {
+	Authorize(ctx context.Context, useCase string, input interface{}) error
+}
+
+// ErrorReporter remonte les erreurs inattendues (panics, dépassements de délai) à un service
+// de suivi d'erreurs ; stack peut être nil. Report ne doit ni bloquer ni paniquer
+type ErrorReporter interface {
+	Report(ctx context.Context, err error, stack []byte, fields map[string]interface{})
+}
+
+// TxManager exécute une fonction dans une transaction portée par le context
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Clock source de l'heure courante : les use cases la lisent et passent l'instant aux entités
+// (Created, Updated, expirations), que les tests figent ou avancent à volonté
+type Clock interface {
+	Now() time.Time
+}
+
+// RandomSource octets aléatoires (même contrat qu'io.Reader) : CSPRNG en production,
+// suite reproductible à partir d'une graine dans les tests et les jeux de données
+type RandomSource interface {
+	Read(p []byte) (int, error)
+}
+
+// TokenGenerator secrets opaques tirés d'une RandomSource : mots de passe de provisioning,
+// identifiants de requête SSO, et demain jetons de réinitialisation, codes d'invitation, clés d'API
+type TokenGenerator interface {
+	// Token size octets d'aléa encodés en base64url sans padding
+	Token(size int) (string, error)
+	// HexToken size octets d'aléa en hexadécimal (identifiants contraints à [0-9a-f])
+	HexToken(size int) (string, error)
+}
+
+// =============================================================================
+// DÉCORATEURS
+// =============================================================================
+
+// WithLogging journalise chaque exécution (durée, champs Loggable, cause des erreurs)
+func WithLogging[I, O any](name string, logger Logger) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			fields := map[string]interface{}{"use_case": name}
+			if requestID := RequestIDFromContext(ctx); requestID != "" {
+				fields["request_id"] = requestID
+			}
+			// Toute action d'une session d'impersonation désigne l'administrateur qui l'a faite
+			if actor, _ := ActorFromContext(ctx); actor.Impersonation != nil {
+				fields["impersonator_id"] = actor.Impersonation.AdminID
+			}
+			if loggable, ok := any(input).(Loggable); ok {
+				for key, value := range loggable.LogFields() {
+					fields[key] = value
+				}
+			}
+
+			start := time.Now()
+			output, err := next.Execute(ctx, input)
+			fields["duration_ms"] = time.Since(start).Milliseconds()
+
+			if err != nil {
+				var ucErr *Error
+				if errors.As(err, &ucErr) && ucErr.Cause != nil {
+					fields["cause"] = ucErr.Cause.Error()
+				}
+				logger.Error("Use case failed", err, fields)
+				return output, err
+			}
+
+			logger.Info("Use case succeeded", fields)
+			return output, nil
+		})
+	}
+}
+
+// WithMetrics mesure la durée et le résultat de chaque exécution
+func WithMetrics[I, O any](name string, metrics Metrics) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			start := time.Now()
+			output, err := next.Execute(ctx, input)
+			metrics.ObserveUseCase(name, time.Since(start), err)
+			return output, err
+		})
+	}
+}
+
+// WithTracing ouvre un span autour de l'exécution
+func WithTracing[I, O any](name string, tracer Tracer) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			ctx, span := tracer.StartSpan(ctx, "usecase."+name)
+			output, err := next.Execute(ctx, input)
+			span.End(err)
+			return output, err
+		})
+	}
+}
+
+// WithValidation rejette les entrées Validatable invalides avant d'exécuter le use case
+func WithValidation[I, O any]() Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			if validatable, ok := any(input).(Validatable); ok {
+				if err := validatable.Validate(); err != nil {
+					var zero O
+					return zero, err
+				}
+			}
+			return next.Execute(ctx, input)
+		})
+	}
+}
+
+// WithAuthorization vérifie les droits de l'acteur courant avant l'exécution
+func WithAuthorization[I, O any](name string, authorizer Authorizer) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			if err := authorizer.Authorize(ctx, name, input); err != nil {
+				var zero O
+				return zero, err
+			}
+			return next.Execute(ctx, input)
+		})
+	}
+}
+
+// WithRecovery convertit une panique du use case en erreur interne et la signale avec sa stack
+// Les dépassements de délai sont aussi signalés : ils révèlent une dépendance lente
+func WithRecovery[I, O any](name string, reporter ErrorReporter) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (output O, err error) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					cause := fmt.Errorf("panic: %v", recovered)
+					reporter.Report(ctx, cause, debug.Stack(), map[string]interface{}{"use_case": name})
+
+					var zero O
+					output, err = zero, newError("erreur interne", cause)
+				}
+			}()
+
+			output, err = next.Execute(ctx, input)
+			if errors.Is(err, ErrTimeout) {
+				// On signale la cause technique, pas le message destiné au client
+				var ucErr *Error
+				if errors.As(err, &ucErr) && ucErr.Cause != nil {
+					reporter.Report(ctx, ucErr.Cause, nil, map[string]interface{}{"use_case": name})
+				} else {
+					reporter.Report(ctx, err, nil, map[string]interface{}{"use_case": name})
+				}
+			}
+			return output, err
+		})
+	}
+}
+
+// WithTimeout borne la durée d'exécution : le context est annulé à l'échéance et les adapters
+// (repositories, clients HTTP) abandonnent leur appel. Le use case s'exécute dans la goroutine
+// appelante : on attend son retour plutôt que de l'abandonner, pour ne pas accumuler de goroutines
+func WithTimeout[I, O any](name string, timeout time.Duration) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if timeout <= 0 {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			output, err := next.Execute(ctx, input)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				var zero O
+				return zero, newError("délai de traitement dépassé", fmt.Errorf("%w: %s après %s: %v", ErrTimeout, name, timeout, err))
+			}
+			return output, err
+		})
+	}
+}
+
+// WithTransaction exécute le use case dans une transaction (rollback si erreur)
+func WithTransaction[I, O any](txManager TxManager) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			var output O
+			err := txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+				var err error
+				output, err = next.Execute(txCtx, input)
+				return err
+			})
+			return output, err
+		})
+	}
+}
+
+// =============================================================================
+// PIPELINE STANDARD (utilisé par la composition root)
+// =============================================================================
+
+// Pipeline regroupe les ports transverses appliqués à tous les use cases
+type Pipeline struct {
+	Logger     Logger
+	Metrics    Metrics
+	Tracer     Tracer
+	Authorizer Authorizer
+	TxManager  TxManager
+	Reporter   ErrorReporter
+	// Registry niveaux d'accès déclarés par Wrap, lus par AccessGuard et les politiques (nil = aucun relevé)
+	Registry *UseCaseRegistry
+	// Meter usage et quotas par tenant (nil = ni mesure ni quota)
+	Meter *UsageMeter
+
+	// DefaultTimeout durée maximale d'un use case (0 = pas de limite)
+	DefaultTimeout time.Duration
+	// Timeouts surcharge DefaultTimeout par nom de use case (ex: imports, digests)
+	Timeouts map[string]time.Duration
+
+	// Cache résultats des use cases enveloppés par WrapCached (nil = aucun cache)
+	Cache ResultCache
+	// CacheTTLs durée de vie des résultats par nom de use case ; absent = pas de cache
+	CacheTTLs map[string]time.Duration
+
+	// Hooks règles ajoutées par l'application hôte autour des use cases (nil = aucune)
+	Hooks *Hooks
+	// Availability modes maintenance et lecture seule (nil = toujours disponible)
+	Availability *Availability
+	// Audit journal d'audit des use cases sensibles (nil = aucun audit)
+	Audit *AuditTrail
+	// Captcha CAPTCHA exigé sur les use cases publics (nil = aucun CAPTCHA)
+	Captcha *CaptchaGuard
+	// Moderation textes publics (noms, handles) des requêtes Moderated (nil = aucune modération)
+	Moderation ContentModeration
+}
+
+func (p Pipeline) timeout(name string) time.Duration {
+	if timeout, ok := p.Timeouts[name]; ok {
+		return timeout
+	}
+	return p.DefaultTimeout
+}
+
+// Wrap applique la chaîne standard :
+// tracing → metrics → logging → recovery → timeout → availability → validation → captcha → moderation → audit → authorization → hooks → quotas → transaction → use case
+// Les refus de maintenance passent avant tout le reste : aucune dépendance n'est sollicitée
+// La validation passe avant l'autorisation pour ne jamais évaluer une politique sur une entrée mal formée
+// Le CAPTCHA passe aussi après la validation : le client corrige sa saisie sans perdre son jeton,
+// et avant la modération : un robot ne sollicite pas le fournisseur de modération
+// L'audit enveloppe l'autorisation : les tentatives refusées sont tracées comme les autres
+// Les quotas passent après : un appel refusé par les politiques ou par un hook ne consomme rien
+// Recovery et timeout sont sous le logging pour que paniques et dépassements soient journalisés et mesurés
+// access niveau d'accès du use case, relevé dans p.Registry pour l'autorisation
+func Wrap[I, O any](p Pipeline, name string, access Access, useCase UseCase[I, O]) UseCase[I, O] {
+	if p.Registry != nil {
+		p.Registry.Register(name, access)
+	}
+	return Decorate(useCase,
+		WithTracing[I, O](name, p.Tracer),
+		WithMetrics[I, O](name, p.Metrics),
+		WithLogging[I, O](name, p.Logger),
+		WithRecovery[I, O](name, p.Reporter),
+		WithTimeout[I, O](name, p.timeout(name)),
+		WithAvailability[I, O](name, p.Availability),
+		WithValidation[I, O](),
+		WithCaptcha[I, O](name, p.Captcha),
+		WithModeration[I, O](name, p.Moderation, p.Logger),
+		WithAudit[I, O](name, p.Audit),
+		WithAuthorization[I, O](name, p.Authorizer),
+		WithHooks[I, O](name, p.Hooks),
+		WithQuotas[I, O](p.Meter),
+		WithTransaction[I, O](p.TxManager),
+	)
+}
+
+// WrapCached Wrap avec le cache de résultats au plus près du use case : un résultat servi depuis
+// le cache a passé validation, autorisation et quotas comme un autre. tags peut être nil
+func WrapCached[I, O any](p Pipeline, name string, access Access, useCase UseCase[I, O], tags CacheTags[I, O]) UseCase[I, O] {
+	return Wrap(p, name, access, WithCache(name, p.Cache, p.CacheTTLs[name], tags)(useCase))
+}
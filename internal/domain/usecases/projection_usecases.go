@@ -0,0 +1,333 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// =============================================================================
+// PROJECTIONS : modèles de lecture reconstruits par rejeu du magasin d'événements
+// =============================================================================
+
+// ErrNoEventStore la persistance n'est pas event-sourcée : aucun flux à rejouer
+var ErrNoEventStore = errors.New("magasin d'événements non configuré (PERSISTENCE_MODE=event_sourced requis)")
+
+// Projection modèle de lecture alimenté par les événements, reconstructible par rejeu. Une
+// instance sert à un seul rejeu : elle peut accumuler entre Handle et Flush
+type Projection interface {
+	Name() string
+	// Reset vide la projection avant un rejeu complet (checkpoint 0) ; jamais appelé en reprise
+	Reset(ctx context.Context) error
+	Handle(ctx context.Context, event events.Event) error
+	// Flush écrit ce que Handle a accumulé ; appelé après chaque lot, avant d'avancer le checkpoint
+	Flush(ctx context.Context) error
+}
+
+// replayedEvent événement tel que le bus l'a publié à l'origine : le flux enregistre UserRegistered
+// là où la commande a publié UserCreated ; les autres événements internes ne sont pas publiés
+func replayedEvent(event events.Event) (events.Event, bool) {
+	if registered, ok := event.(events.UserRegistered); ok {
+		return events.UserCreated{
+			UserID:  registered.UserID,
+			Email:   registered.Email,
+			Name:    registered.Name,
+			Created: registered.Registered,
+		}, true
+	}
+	return event, !isInternalEvent(event.EventName())
+}
+
+// ReadModelProjection modèle de lecture CQRS (UserProjector)
+type ReadModelProjection struct {
+	readRepo  repositories.UserReadRepository
+	projector *UserProjector
+}
+
+func NewReadModelProjection(readRepo repositories.UserReadRepository) *ReadModelProjection {
+	return &ReadModelProjection{readRepo: readRepo, projector: NewUserProjector(readRepo)}
+}
+
+func (p *ReadModelProjection) Name() string { return "read_model" }
+
+func (p *ReadModelProjection) Reset(ctx context.Context) error { return p.readRepo.Clear(ctx) }
+
+func (p *ReadModelProjection) Handle(ctx context.Context, event events.Event) error {
+	return p.projector.Handle(ctx, event)
+}
+
+func (p *ReadModelProjection) Flush(ctx context.Context) error { return nil }
+
+// RollupProjection agrégats quotidiens. En reprise, chaque événement est compté comme par
+// RollupProjector ; en rejeu complet, les compteurs sont recalculés puis remplacent ceux des
+// jours couverts, sauf pour les événements absents du magasin (publiés sans être enregistrés
+// dans un flux), conservés tels quels
+type RollupProjection struct {
+	rollupRepo repositories.EventRollupRepository
+	full       bool
+	counts     map[rollupKey]int
+	from, to   time.Time
+}
+
+type rollupKey struct {
+	day   time.Time
+	event string
+}
+
+func NewRollupProjection(rollupRepo repositories.EventRollupRepository) *RollupProjection {
+	return &RollupProjection{rollupRepo: rollupRepo, counts: make(map[rollupKey]int)}
+}
+
+func (p *RollupProjection) Name() string { return "rollups" }
+
+func (p *RollupProjection) Reset(ctx context.Context) error {
+	p.full = true
+	return nil
+}
+
+func (p *RollupProjection) Handle(ctx context.Context, event events.Event) error {
+	day := rollupDay(event.OccurredAt())
+	if !p.full {
+		return p.rollupRepo.Increment(ctx, day, event.EventName(), 1)
+	}
+	p.counts[rollupKey{day: day, event: event.EventName()}]++
+	if p.from.IsZero() || day.Before(p.from) {
+		p.from = day
+	}
+	if end := day.AddDate(0, 0, 1); end.After(p.to) {
+		p.to = end
+	}
+	return nil
+}
+
+// Flush en rejeu complet : les compteurs cumulés depuis le début remplacent la période [from, to)
+// à chaque lot ; le dernier remplacement est le résultat final
+func (p *RollupProjection) Flush(ctx context.Context) error {
+	if !p.full || len(p.counts) == 0 {
+		return nil
+	}
+	replayed := make(map[string]bool)
+	rollups := make([]repositories.EventRollup, 0, len(p.counts))
+	for key, count := range p.counts {
+		replayed[key.event] = true
+		rollups = append(rollups, repositories.EventRollup{Day: key.day, Event: key.event, Count: count})
+	}
+	existing, err := p.rollupRepo.Query(ctx, repositories.EventRollupFilters{From: p.from, To: p.to})
+	if err != nil {
+		return err
+	}
+	for _, rollup := range existing {
+		if !replayed[rollup.Event] {
+			rollups = append(rollups, rollup)
+		}
+	}
+	return p.rollupRepo.ReplaceDays(ctx, p.from, p.to, rollups)
+}
+
+// SearchProjection documents utilisateurs du moteur de recherche, réindexés depuis le stockage une
+// fois par lot pour chaque utilisateur concerné. L'index des événements n'est pas rejoué : ses
+// documents n'ont pas d'identifiant stable, un rejeu les dupliquerait
+type SearchProjection struct {
+	indexer *SearchIndexer
+	users   map[int]bool
+}
+
+func NewSearchProjection(indexer *SearchIndexer) *SearchProjection {
+	return &SearchProjection{indexer: indexer, users: make(map[int]bool)}
+}
+
+func (p *SearchProjection) Name() string { return "search" }
+
+// Reset écritures idempotentes : les documents sont remplacés, ceux des utilisateurs supprimés retirés
+func (p *SearchProjection) Reset(ctx context.Context) error { return nil }
+
+func (p *SearchProjection) Handle(ctx context.Context, event events.Event) error {
+	if userID := eventUserID(event); userID > 0 {
+		p.users[userID] = true
+	}
+	return nil
+}
+
+func (p *SearchProjection) Flush(ctx context.Context) error {
+	for userID := range p.users {
+		if err := p.indexer.reindexUser(ctx, userID); err != nil {
+			return err
+		}
+		delete(p.users, userID)
+	}
+	return nil
+}
+
+// CacheProjection invalide les résultats en cache des utilisateurs rejoués (CacheInvalidator) ;
+// à placer après les autres projections
+type CacheProjection struct {
+	invalidator *CacheInvalidator
+}
+
+func NewCacheProjection(cache ResultCache) *CacheProjection {
+	return &CacheProjection{invalidator: NewCacheInvalidator(cache)}
+}
+
+func (p *CacheProjection) Name() string { return "cache" }
+
+func (p *CacheProjection) Reset(ctx context.Context) error { return nil }
+
+func (p *CacheProjection) Handle(ctx context.Context, event events.Event) error {
+	return p.invalidator.Handle(ctx, event)
+}
+
+func (p *CacheProjection) Flush(ctx context.Context) error { return nil }
+
+// =============================================================================
+// REBUILD PROJECTIONS USE CASE
+// =============================================================================
+
+const (
+	defaultRebuildBatchSize = 500
+	maxRebuildBatchSize     = 10_000
+)
+
+// RebuildProjectionsUseCase rejoue le magasin d'événements à partir d'un checkpoint dans les
+// projections : modèles de lecture ayant dérivé, index vide, agrégats faussés par une panne
+type RebuildProjectionsUseCase struct {
+	store          repositories.UserEventStore
+	newProjections func() []Projection
+}
+
+// NewRebuildProjectionsUseCase store nil : l'exécution retourne ErrNoEventStore ; newProjections
+// construit des projections neuves à chaque exécution, dans l'ordre d'application
+func NewRebuildProjectionsUseCase(store repositories.UserEventStore, newProjections func() []Projection) *RebuildProjectionsUseCase {
+	return &RebuildProjectionsUseCase{store: store, newProjections: newProjections}
+}
+
+// RebuildProgress avancement après un lot ; Position est le checkpoint de reprise
+type RebuildProgress struct {
+	Position int64
+	Head     int64
+	Events   int
+}
+
+// RebuildProjectionsRequest From checkpoint (0 : rejeu complet, projections vidées au préalable) ;
+// Projections vide = toutes ; Progress appelé après chaque lot écrit (nil : rien)
+type RebuildProjectionsRequest struct {
+	From        int64
+	Projections []string
+	BatchSize   int
+	Progress    func(RebuildProgress) error
+}
+
+func (req RebuildProjectionsRequest) Validate() error {
+	if req.From < 0 {
+		return errors.New("le checkpoint doit être positif ou nul")
+	}
+	if req.BatchSize < 0 || req.BatchSize > maxRebuildBatchSize {
+		return fmt.Errorf("taille de lot entre 1 et %d", maxRebuildBatchSize)
+	}
+	return nil
+}
+
+func (req RebuildProjectionsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"from": req.From, "projections": req.Projections, "batch_size": req.BatchSize}
+}
+
+type RebuildProjectionsResponse struct {
+	Projections []string `json:"projections"`
+	From        int64    `json:"from"`
+	Position    int64    `json:"position"`
+	Events      int      `json:"events"`
+	Skipped     int      `json:"skipped"`
+}
+
+func (uc *RebuildProjectionsUseCase) Execute(ctx context.Context, req RebuildProjectionsRequest) (*RebuildProjectionsResponse, error) {
+	if uc.store == nil {
+		return nil, ErrNoEventStore
+	}
+	projections, err := selectProjections(uc.newProjections(), req.Projections)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultRebuildBatchSize
+	}
+
+	response := &RebuildProjectionsResponse{From: req.From, Position: req.From}
+	for _, projection := range projections {
+		response.Projections = append(response.Projections, projection.Name())
+	}
+	if req.From == 0 {
+		for _, projection := range projections {
+			if err := projection.Reset(ctx); err != nil {
+				return nil, newError(fmt.Sprintf("erreur lors de la remise à zéro de la projection %s", projection.Name()), err)
+			}
+		}
+	}
+	head, err := uc.store.HeadPosition(ctx)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du magasin d'événements", err)
+	}
+
+	for response.Position < head {
+		batch, err := uc.store.ReadAll(ctx, response.Position, batchSize)
+		if err != nil {
+			return response, newError("erreur lors de la lecture du magasin d'événements", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		for _, stored := range batch {
+			event, published := replayedEvent(stored.Event)
+			if !published {
+				response.Skipped++
+				continue
+			}
+			for _, projection := range projections {
+				if err := projection.Handle(ctx, event); err != nil {
+					return response, newError(fmt.Sprintf("projection %s : échec à la position %d", projection.Name(), stored.Position), err)
+				}
+			}
+			response.Events++
+		}
+		for _, projection := range projections {
+			if err := projection.Flush(ctx); err != nil {
+				return response, newError(fmt.Sprintf("projection %s : échec de l'écriture du lot", projection.Name()), err)
+			}
+		}
+		// Le checkpoint n'avance qu'une fois le lot écrit dans toutes les projections
+		response.Position = batch[len(batch)-1].Position
+
+		if req.Progress != nil {
+			if err := req.Progress(RebuildProgress{Position: response.Position, Head: head, Events: response.Events}); err != nil {
+				return response, err
+			}
+		}
+	}
+	return response, nil
+}
+
+// selectProjections projections nommées, dans l'ordre d'application de all
+func selectProjections(all []Projection, names []string) ([]Projection, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	known := make([]string, 0, len(all))
+	for _, projection := range all {
+		known = append(known, projection.Name())
+	}
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("projection inconnue %q (disponibles : %v)", name, known)
+		}
+	}
+	selected := make([]Projection, 0, len(names))
+	for _, projection := range all {
+		if slices.Contains(names, projection.Name()) {
+			selected = append(selected, projection)
+		}
+	}
+	return selected, nil
+}
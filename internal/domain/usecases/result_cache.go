@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// =============================================================================
+// CACHE DES RÉSULTATS DE LECTURE
+// =============================================================================
+
+// ResultCache stockage des résultats de use cases de lecture ; chaque entrée porte des étiquettes
+// et disparaît dès que l'une d'elles est invalidée. Les sorties sont partagées entre appelants :
+// elles ne doivent pas être modifiées
+type ResultCache interface {
+	Get(key string) (any, bool)
+	Set(key string, value any, ttl time.Duration, tags []string)
+	Invalidate(tags ...string)
+}
+
+// CacheTags étiquettes d'un résultat, calculées après exécution : un utilisateur lu par son handle
+// est étiqueté par son ID, que portent les événements
+type CacheTags[I, O any] func(input I, output O) []string
+
+// UserCacheTag étiquette des résultats qui dépendent de l'utilisateur id
+func UserCacheTag(id int) string {
+	return "user:" + strconv.Itoa(id)
+}
+
+// WithCache sert le résultat précédent tant qu'il n'a ni expiré ni été invalidé
+//   - clé : nom du use case, tenant de l'acteur et empreinte SHA-256 de l'entrée encodée en JSON ;
+//     le résultat ne doit donc dépendre que du tenant et de l'entrée (pas de l'utilisateur appelant)
+//   - les erreurs ne sont jamais mises en cache
+//   - cache nil ou ttl <= 0 : décorateur transparent
+func WithCache[I, O any](name string, cache ResultCache, ttl time.Duration, tags CacheTags[I, O]) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if cache == nil || ttl <= 0 {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			key, ok := cacheKey(ctx, name, input)
+			if !ok {
+				return next.Execute(ctx, input)
+			}
+			if cached, found := cache.Get(key); found {
+				if output, ok := cached.(O); ok {
+					return output, nil
+				}
+			}
+
+			output, err := next.Execute(ctx, input)
+			if err != nil {
+				return output, err
+			}
+			entryTags := []string{name}
+			if tags != nil {
+				entryTags = append(entryTags, tags(input, output)...)
+			}
+			cache.Set(key, output, ttl, entryTags)
+			return output, nil
+		})
+	}
+}
+
+// cacheKey false si l'entrée ne s'encode pas en JSON (elle n'est alors pas mise en cache)
+func cacheKey(ctx context.Context, name string, input any) (string, bool) {
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return "", false
+	}
+	actor, _ := ActorFromContext(ctx)
+	digest := sha256.New()
+	// Séparateurs nuls : "a"+"bc" et "ab"+"c" ne produisent pas la même clé
+	digest.Write([]byte(actor.TenantID))
+	digest.Write([]byte{0})
+	digest.Write(encoded)
+	return name + ":" + hex.EncodeToString(digest.Sum(nil)), true
+}
+
+// =============================================================================
+// INVALIDATION PAR ÉVÉNEMENTS
+// =============================================================================
+
+// CacheInvalidator abonné du bus : tout événement qui concerne un utilisateur (profil, statut,
+// handle, connexion...) invalide ses résultats. À abonner après les projecteurs, pour qu'une
+// lecture qui suit l'invalidation voie le modèle de lecture à jour
+type CacheInvalidator struct {
+	cache ResultCache
+}
+
+func NewCacheInvalidator(cache ResultCache) *CacheInvalidator {
+	return &CacheInvalidator{cache: cache}
+}
+
+func (i *CacheInvalidator) Handle(ctx context.Context, event events.Event) error {
+	if userID := eventUserID(event); userID > 0 {
+		i.cache.Invalidate(UserCacheTag(userID))
+	}
+	return nil
+}
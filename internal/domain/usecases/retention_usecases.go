@@ -0,0 +1,337 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// =============================================================================
+// RÉTENTION DES DONNÉES PAR CLASSE
+// =============================================================================
+
+// Classes de données soumises à rétention (RETENTION_TTLS)
+const (
+	RetentionUsers     = "users"      // comptes désactivés, depuis leur dernière modification
+	RetentionSessions  = "sessions"   // historique des connexions, depuis leur ouverture
+	RetentionAuditLogs = "audit_logs" // journal d'audit
+	RetentionRawEvents = "raw_events" // événements analytics, selon leur horodatage client
+	RetentionRollups   = "rollups"    // agrégats quotidiens, selon leur jour
+)
+
+// RetentionClasses ordre d'application : comptes d'abord, leurs sessions suivent par UserDeleted
+var RetentionClasses = []string{RetentionUsers, RetentionSessions, RetentionAuditLogs, RetentionRawEvents, RetentionRollups}
+
+// RetentionPolicy durée de conservation d'une classe ; Archive : chaque lot est écrit dans le
+// FileStorage (retention-<classe>-...) avant d'être supprimé
+type RetentionPolicy struct {
+	Class   string
+	TTL     time.Duration
+	Archive bool
+}
+
+// RetentionBatch lot d'éléments expirés d'une classe : Records pour l'archive, Remove pour les supprimer
+type RetentionBatch struct {
+	Records []any
+	Remove  func(ctx context.Context) (int, error)
+}
+
+// RetentionTarget accès d'une classe à ses éléments expirés
+type RetentionTarget interface {
+	// Expired au plus limit éléments antérieurs à cutoff ; lot vide = plus rien d'expiré
+	Expired(ctx context.Context, cutoff time.Time, limit int) (*RetentionBatch, error)
+}
+
+// =============================================================================
+// CLASSES : adaptateurs des dépôts
+// =============================================================================
+
+// userRetention comptes désactivés depuis plus longtemps que la durée de conservation ; leur
+// suppression publie UserDeleted comme DeleteUserUseCase (sessions, chronologie et index suivent)
+type userRetention struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewUserRetention(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) RetentionTarget {
+	return &userRetention{userRepo: userRepo, publisher: publisher, clock: clock}
+}
+
+func (t *userRetention) Expired(ctx context.Context, cutoff time.Time, limit int) (*RetentionBatch, error) {
+	var ids []int
+	var records []any
+	filters := repositories.UserRepositoryFilters{Status: string(entities.UserStatusDeactivated)}
+	err := t.userRepo.Each(ctx, filters, func(user *entities.User) error {
+		if !user.Updated.Before(cutoff) {
+			return nil
+		}
+		archived := *user
+		archived.Password = "" // jamais de hash dans une archive
+		ids, records = append(ids, user.ID), append(records, archived)
+		if len(ids) == limit {
+			return repositories.ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, repositories.ErrStopIteration) {
+		return nil, err
+	}
+	return &RetentionBatch{Records: records, Remove: func(ctx context.Context) (int, error) {
+		if err := t.userRepo.DeleteByIds(ctx, ids); err != nil {
+			return 0, err
+		}
+		for _, id := range ids {
+			t.publisher.Publish(ctx, events.UserDeleted{UserID: id, Deleted: t.clock.Now()})
+		}
+		return len(ids), nil
+	}}, nil
+}
+
+type sessionRetention struct {
+	sessionRepo repositories.SessionRepository
+}
+
+func NewSessionRetention(sessionRepo repositories.SessionRepository) RetentionTarget {
+	return &sessionRetention{sessionRepo: sessionRepo}
+}
+
+func (t *sessionRetention) Expired(ctx context.Context, cutoff time.Time, limit int) (*RetentionBatch, error) {
+	sessions, err := t.sessionRepo.ListCreatedBefore(ctx, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(sessions))
+	records := make([]any, len(sessions))
+	for i, session := range sessions {
+		ids[i], records[i] = session.ID, session
+	}
+	return &RetentionBatch{Records: records, Remove: func(ctx context.Context) (int, error) {
+		return t.sessionRepo.DeleteByIDs(ctx, ids)
+	}}, nil
+}
+
+type auditRetention struct {
+	auditRepo repositories.AuditRepository
+}
+
+func NewAuditRetention(auditRepo repositories.AuditRepository) RetentionTarget {
+	return &auditRetention{auditRepo: auditRepo}
+}
+
+func (t *auditRetention) Expired(ctx context.Context, cutoff time.Time, limit int) (*RetentionBatch, error) {
+	entries, err := t.auditRepo.List(ctx, repositories.AuditFilter{To: &cutoff, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(entries))
+	records := make([]any, len(entries))
+	for i, entry := range entries {
+		ids[i], records[i] = entry.ID, toAuditEntryResponse(entry)
+	}
+	return &RetentionBatch{Records: records, Remove: func(ctx context.Context) (int, error) {
+		return t.auditRepo.DeleteByIDs(ctx, ids)
+	}}, nil
+}
+
+// eventRetention lecture par le dépôt d'ingestion, suppression par le stockage lui-même
+type eventRetention struct {
+	eventRepo repositories.EventRepository
+	purger    repositories.EventPurger
+}
+
+func NewEventRetention(eventRepo repositories.EventRepository, purger repositories.EventPurger) RetentionTarget {
+	return &eventRetention{eventRepo: eventRepo, purger: purger}
+}
+
+func (t *eventRetention) Expired(ctx context.Context, cutoff time.Time, limit int) (*RetentionBatch, error) {
+	tracked, err := t.eventRepo.List(ctx, repositories.EventFilters{To: cutoff, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(tracked))
+	records := make([]any, len(tracked))
+	for i, event := range tracked {
+		ids[i], records[i] = event.ID, event
+	}
+	return &RetentionBatch{Records: records, Remove: func(ctx context.Context) (int, error) {
+		return t.purger.DeleteByIDs(ctx, ids)
+	}}, nil
+}
+
+// rollupRetention lots de jours entiers (au moins un, même s'il dépasse limit)
+type rollupRetention struct {
+	rollupRepo repositories.EventRollupRepository
+}
+
+func NewRollupRetention(rollupRepo repositories.EventRollupRepository) RetentionTarget {
+	return &rollupRetention{rollupRepo: rollupRepo}
+}
+
+func (t *rollupRetention) Expired(ctx context.Context, cutoff time.Time, limit int) (*RetentionBatch, error) {
+	// Un jour n'expire qu'une fois entièrement passé sous cutoff
+	cutoffDay := cutoff.UTC().Truncate(24 * time.Hour)
+	rollups, err := t.rollupRepo.Query(ctx, repositories.EventRollupFilters{To: cutoffDay})
+	if err != nil {
+		return nil, err
+	}
+	var records []any
+	var first, last time.Time
+	for i, rollup := range rollups {
+		if i > 0 && !rollup.Day.Equal(rollups[i-1].Day) && len(records) >= limit {
+			break
+		}
+		if i == 0 {
+			first = rollup.Day
+		}
+		last = rollup.Day
+		records = append(records, rollup)
+	}
+	return &RetentionBatch{Records: records, Remove: func(ctx context.Context) (int, error) {
+		if err := t.rollupRepo.ReplaceDays(ctx, first, last.AddDate(0, 0, 1), nil); err != nil {
+			return 0, err
+		}
+		return len(records), nil
+	}}, nil
+}
+
+// =============================================================================
+// ENFORCE RETENTION USE CASE
+// =============================================================================
+
+// EnforceRetentionUseCase supprime (ou archive puis supprime) les données expirées de chaque
+// classe, par lots de batchSize, au plus maxBatches lots par classe et par exécution : une
+// classe très en retard est rattrapée sur plusieurs exécutions sans monopoliser le stockage
+// Une classe en échec n'arrête pas les suivantes : son erreur figure dans le rapport
+type EnforceRetentionUseCase struct {
+	policies   []RetentionPolicy
+	targets    map[string]RetentionTarget
+	storage    FileStorage
+	batchSize  int
+	maxBatches int
+	clock      Clock
+	logger     Logger
+}
+
+// NewEnforceRetentionUseCase policies sans durée (0) ou sans target : classe conservée indéfiniment
+func NewEnforceRetentionUseCase(
+	policies []RetentionPolicy,
+	targets map[string]RetentionTarget,
+	storage FileStorage,
+	batchSize int,
+	maxBatches int,
+	clock Clock,
+	logger Logger,
+) *EnforceRetentionUseCase {
+	enforced := make([]RetentionPolicy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.TTL > 0 && targets[policy.Class] != nil {
+			enforced = append(enforced, policy)
+		}
+	}
+	return &EnforceRetentionUseCase{
+		policies:   enforced,
+		targets:    targets,
+		storage:    storage,
+		batchSize:  batchSize,
+		maxBatches: maxBatches,
+		clock:      clock,
+		logger:     logger,
+	}
+}
+
+// EnforceRetentionRequest Now zéro = l'horloge du use case
+type EnforceRetentionRequest struct {
+	Now time.Time
+}
+
+// RetentionReport bilan d'une classe : Complete plus rien d'expiré à la fin de l'exécution
+type RetentionReport struct {
+	Class    string    `json:"class"`
+	Cutoff   time.Time `json:"cutoff"`
+	Removed  int       `json:"removed"`
+	Archived int       `json:"archived"`
+	Batches  int       `json:"batches"`
+	Complete bool      `json:"complete"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type EnforceRetentionResponse struct {
+	Classes []RetentionReport `json:"classes"`
+}
+
+func (uc *EnforceRetentionUseCase) Execute(ctx context.Context, req EnforceRetentionRequest) (*EnforceRetentionResponse, error) {
+	if req.Now.IsZero() {
+		req.Now = uc.clock.Now()
+	}
+	response := &EnforceRetentionResponse{Classes: make([]RetentionReport, 0, len(uc.policies))}
+	for _, policy := range uc.policies {
+		report := uc.enforce(ctx, policy, req.Now)
+		fields := map[string]interface{}{
+			"class": report.Class, "cutoff": report.Cutoff, "removed": report.Removed,
+			"archived": report.Archived, "batches": report.Batches, "complete": report.Complete,
+		}
+		if report.Error != "" {
+			uc.logger.Error("Retention enforcement failed", errors.New(report.Error), fields)
+		} else if report.Removed > 0 {
+			uc.logger.Info("Retention enforced", fields)
+		}
+		response.Classes = append(response.Classes, report)
+	}
+	return response, nil
+}
+
+func (uc *EnforceRetentionUseCase) enforce(ctx context.Context, policy RetentionPolicy, now time.Time) RetentionReport {
+	report := RetentionReport{Class: policy.Class, Cutoff: now.Add(-policy.TTL).UTC()}
+	target := uc.targets[policy.Class]
+	for report.Batches < uc.maxBatches {
+		batch, err := target.Expired(ctx, report.Cutoff, uc.batchSize)
+		if err != nil {
+			report.Error = err.Error()
+			return report
+		}
+		if len(batch.Records) == 0 {
+			report.Complete = true
+			return report
+		}
+		report.Batches++
+		if policy.Archive {
+			if err := uc.archive(ctx, policy.Class, now, report.Batches, batch.Records); err != nil {
+				report.Error = "archive : " + err.Error()
+				return report
+			}
+			report.Archived += len(batch.Records)
+		}
+		removed, err := batch.Remove(ctx)
+		report.Removed += removed
+		if err != nil {
+			report.Error = err.Error()
+			return report
+		}
+	}
+	return report
+}
+
+// archive un fichier NDJSON par lot, retention-<classe>-<exécution>-<lot>.ndjson ; un lot dont
+// l'archive échoue n'est pas supprimé
+func (uc *EnforceRetentionUseCase) archive(ctx context.Context, class string, now time.Time, batch int, records []any) error {
+	if uc.storage == nil {
+		return errors.New("aucun stockage de fichiers configuré")
+	}
+	key := fmt.Sprintf("retention-%s-%s-%04d.ndjson", class, now.UTC().Format("20060102T150405Z"), batch)
+	return uc.storage.Write(ctx, key, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
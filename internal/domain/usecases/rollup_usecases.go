@@ -0,0 +1,317 @@
+// internal/domain/usecases/rollup_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// rollupDay minuit UTC du jour de t : les agrégats sont découpés en jours UTC
+func rollupDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// isInternalEvent événements propres au flux event-sourcé, jamais comptés ni recopiés
+func isInternalEvent(name string) bool {
+	return name == events.UserRegisteredEvent || name == events.PasswordChangedEvent
+}
+
+// =============================================================================
+// ROLLUP PROJECTOR : agrégats quotidiens maintenus au fil des événements
+// =============================================================================
+
+// RollupProjector est abonné au bus : chaque événement incrémente le compteur de son jour
+type RollupProjector struct {
+	rollupRepo repositories.EventRollupRepository
+}
+
+func NewRollupProjector(rollupRepo repositories.EventRollupRepository) *RollupProjector {
+	return &RollupProjector{rollupRepo: rollupRepo}
+}
+
+func (p *RollupProjector) Handle(ctx context.Context, event events.Event) error {
+	if isInternalEvent(event.EventName()) {
+		return nil
+	}
+	return p.rollupRepo.Increment(ctx, rollupDay(event.OccurredAt()), event.EventName(), 1)
+}
+
+// =============================================================================
+// BACKFILL ROLLUPS USE CASE
+// =============================================================================
+
+// rollupBackfillChunkDays jours recalculés par requête à l'historique et par remplacement
+const rollupBackfillChunkDays = 31
+
+// BackfillRollupsUseCase recalcule les agrégats d'une période depuis l'historique des événements
+// (index Elasticsearch des événements) : premier déploiement, ou rattrapage après une panne du projecteur
+type BackfillRollupsUseCase struct {
+	history    repositories.EventHistory
+	rollupRepo repositories.EventRollupRepository
+	clock      Clock
+}
+
+// NewBackfillRollupsUseCase history nil : l'exécution retourne repositories.ErrNoEventHistory
+func NewBackfillRollupsUseCase(history repositories.EventHistory, rollupRepo repositories.EventRollupRepository, clock Clock) *BackfillRollupsUseCase {
+	return &BackfillRollupsUseCase{history: history, rollupRepo: rollupRepo, clock: clock}
+}
+
+// BackfillRollupsRequest jours [From, To) ; To zéro = jusqu'à hier inclus
+// Le jour courant est laissé au projecteur : l'indexation asynchrone de l'historique a du retard
+type BackfillRollupsRequest struct {
+	From time.Time
+	To   time.Time
+}
+
+func (req BackfillRollupsRequest) Validate() error {
+	if req.From.IsZero() {
+		return errors.New("date de début obligatoire")
+	}
+	if !req.To.IsZero() && !req.From.Before(req.To) {
+		return errors.New("la date de fin doit suivre la date de début")
+	}
+	return nil
+}
+
+func (req BackfillRollupsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"from": req.From, "to": req.To}
+}
+
+type BackfillRollupsResponse struct {
+	Days    int `json:"days"`
+	Rollups int `json:"rollups"`
+	Events  int `json:"events"`
+}
+
+func (uc *BackfillRollupsUseCase) Execute(ctx context.Context, req BackfillRollupsRequest) (*BackfillRollupsResponse, error) {
+	if uc.history == nil {
+		return nil, repositories.ErrNoEventHistory
+	}
+	from := rollupDay(req.From)
+	to := rollupDay(uc.clock.Now())
+	if !req.To.IsZero() {
+		to = rollupDay(req.To)
+	}
+
+	response := &BackfillRollupsResponse{}
+	for start := from; start.Before(to); {
+		end := start.AddDate(0, 0, rollupBackfillChunkDays)
+		if end.After(to) {
+			end = to
+		}
+
+		counts, err := uc.history.DailyEventCounts(ctx, start, end)
+		if err != nil {
+			return nil, newError("erreur lors de la lecture de l'historique des événements", err)
+		}
+		rollups := make([]repositories.EventRollup, 0, len(counts))
+		for _, rollup := range counts {
+			if isInternalEvent(rollup.Event) {
+				continue
+			}
+			rollup.Day = rollupDay(rollup.Day)
+			rollups = append(rollups, rollup)
+			response.Events += rollup.Count
+		}
+		if err := uc.rollupRepo.ReplaceDays(ctx, start, end, rollups); err != nil {
+			return nil, newError("erreur lors de l'écriture des agrégats", err)
+		}
+
+		response.Days += int(end.Sub(start) / (24 * time.Hour))
+		response.Rollups += len(rollups)
+		start = end
+	}
+	return response, nil
+}
+
+// =============================================================================
+// GET EVENT ROLLUPS USE CASE
+// =============================================================================
+
+// EventRollupIntervals regroupements acceptés des agrégats quotidiens
+var EventRollupIntervals = []string{"day", "week", "month"}
+
+// maxRollupRangeDays période maximale d'une lecture (environ trois ans)
+const maxRollupRangeDays = 1100
+
+// GetEventRollupsUseCase nombre d'événements par jour, semaine ou mois, lu dans les agrégats
+// quotidiens : le coût ne dépend que de la période, jamais du volume d'événements
+type GetEventRollupsUseCase struct {
+	rollupRepo repositories.EventRollupRepository
+	clock      Clock
+}
+
+func NewGetEventRollupsUseCase(rollupRepo repositories.EventRollupRepository, clock Clock) *GetEventRollupsUseCase {
+	return &GetEventRollupsUseCase{rollupRepo: rollupRepo, clock: clock}
+}
+
+type GetEventRollupsRequest struct {
+	// Events noms d'événements séparés par des virgules ; vide = tous
+	Events string `json:"events,omitempty"`
+	// From / To bornes RFC 3339 étendues aux jours UTC entiers (To exclu) ; défaut : les 30 derniers jours
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Interval string `json:"interval,omitempty"` // défaut : day
+}
+
+// Validate sans To, la fin de période dépend de l'horloge : ordre et étendue sont alors vérifiés
+// à l'exécution (period)
+func (req GetEventRollupsRequest) Validate() error {
+	if req.Interval != "" && !slices.Contains(EventRollupIntervals, req.Interval) {
+		return errors.New("interval doit valoir day, week ou month")
+	}
+	if req.To == "" {
+		_, _, err := req.bounds(time.Time{})
+		return err
+	}
+	_, _, err := req.period(time.Time{})
+	return err
+}
+
+func (req GetEventRollupsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"events": req.Events, "from": req.From, "to": req.To, "interval": req.Interval}
+}
+
+// period jours [from, to) couverts par la requête, ordonnés et limités à maxRollupRangeDays
+func (req GetEventRollupsRequest) period(now time.Time) (time.Time, time.Time, error) {
+	from, to, err := req.bounds(now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("la date de fin doit suivre la date de début")
+	}
+	if to.Sub(from) > maxRollupRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("période limitée à 1100 jours")
+	}
+	return from, to, nil
+}
+
+// bounds bornes lues dans la requête ; sans To, la période se termine avec le jour de now (inclus)
+func (req GetEventRollupsRequest) bounds(now time.Time) (time.Time, time.Time, error) {
+	to := rollupDay(now).AddDate(0, 0, 1)
+	if req.To != "" {
+		parsed, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to doit être une date RFC 3339")
+		}
+		// Un jour entamé est compris en entier
+		if to = rollupDay(parsed); !to.Equal(parsed) {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	from := to.AddDate(0, 0, -30)
+	if req.From != "" {
+		parsed, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from doit être une date RFC 3339")
+		}
+		from = rollupDay(parsed)
+	}
+	return from, to, nil
+}
+
+type EventRollupBucketResponse struct {
+	Start  time.Time      `json:"start"`
+	Total  int            `json:"total"`
+	Counts map[string]int `json:"counts"`
+}
+
+type GetEventRollupsResponse struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Interval string    `json:"interval"`
+	// Buckets un intervalle par pas de la période, vides compris (séries continues pour les graphiques)
+	Buckets []EventRollupBucketResponse `json:"buckets"`
+	Totals  map[string]int              `json:"totals"`
+	Total   int                         `json:"total"`
+}
+
+func (uc *GetEventRollupsUseCase) Execute(ctx context.Context, req GetEventRollupsRequest) (*GetEventRollupsResponse, error) {
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+	from, to, err := req.period(uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	filters := repositories.EventRollupFilters{From: from, To: to}
+	for _, name := range strings.Split(req.Events, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filters.Names = append(filters.Names, name)
+		}
+	}
+	rollups, err := uc.rollupRepo.Query(ctx, filters)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des agrégats", err)
+	}
+
+	series := newEventSeries(from, to, req.Interval)
+	for _, rollup := range rollups {
+		series.add(rollup.Day, rollup.Event, rollup.Count)
+	}
+	return series.response, nil
+}
+
+// eventSeries compteurs par intervalle de la période [from, to) en jours UTC entiers, vides compris
+type eventSeries struct {
+	response  *GetEventRollupsResponse
+	positions map[time.Time]int
+}
+
+func newEventSeries(from, to time.Time, interval string) *eventSeries {
+	series := &eventSeries{
+		response: &GetEventRollupsResponse{
+			From:     from,
+			To:       to,
+			Interval: interval,
+			Buckets:  []EventRollupBucketResponse{},
+			Totals:   make(map[string]int),
+		},
+		positions: make(map[time.Time]int),
+	}
+	for start := rollupBucketStart(from, interval); start.Before(to); start = rollupBucketNext(start, interval) {
+		series.positions[start] = len(series.response.Buckets)
+		series.response.Buckets = append(series.response.Buckets, EventRollupBucketResponse{Start: start, Counts: make(map[string]int)})
+	}
+	return series
+}
+
+// add day : minuit UTC d'un jour de la période
+func (s *eventSeries) add(day time.Time, event string, count int) {
+	bucket := &s.response.Buckets[s.positions[rollupBucketStart(day, s.response.Interval)]]
+	bucket.Counts[event] += count
+	bucket.Total += count
+	s.response.Totals[event] += count
+	s.response.Total += count
+}
+
+// rollupBucketStart début de l'intervalle contenant day : le jour, le lundi (semaine ISO) ou le 1er du mois
+func rollupBucketStart(day time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "month":
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func rollupBucketNext(start time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
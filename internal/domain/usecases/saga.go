@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// =============================================================================
+// SAGA : enchaînement d'étapes avec actions compensatoires
+// =============================================================================
+
+// ErrSagaSuspended retournée par une étape qui attend un événement extérieur (confirmation...) :
+// la saga s'arrête sans compenser et reprendra à cette même étape
+var ErrSagaSuspended = errors.New("saga en attente")
+
+// SagaStep étape d'une saga sur l'état S ; Compensate annule les effets d'un Run réussi
+// (nil = rien à annuler). Les deux doivent supporter d'être rejoués après un redémarrage
+type SagaStep[S any] struct {
+	Name       string
+	Run        func(ctx context.Context, state *S) error
+	Compensate func(ctx context.Context, state *S) error
+}
+
+// SagaError étape en échec ; Compensations erreurs des annulations qui n'ont pas abouti
+// (vide = toutes les étapes précédentes ont été annulées)
+type SagaError struct {
+	Step          string
+	Err           error
+	Compensations []error
+}
+
+func (e *SagaError) Error() string {
+	if len(e.Compensations) > 0 {
+		return fmt.Sprintf("étape %s : %v (%d compensation(s) en échec)", e.Step, e.Err, len(e.Compensations))
+	}
+	return fmt.Sprintf("étape %s : %v", e.Step, e.Err)
+}
+
+func (e *SagaError) Unwrap() error { return e.Err }
+
+// Saga coordinateur en mémoire : l'appelant persiste l'état et l'index retourné entre deux reprises
+type Saga[S any] struct {
+	name   string
+	steps  []SagaStep[S]
+	logger Logger
+}
+
+func NewSaga[S any](name string, logger Logger, steps ...SagaStep[S]) *Saga[S] {
+	return &Saga[S]{name: name, steps: steps, logger: logger}
+}
+
+// Run exécute les étapes à partir de from et retourne l'index de la prochaine étape (Len si la
+// saga est terminée). Une étape suspendue retourne ErrSagaSuspended ; une étape en échec déclenche
+// la compensation des étapes précédentes, dans l'ordre inverse, et retourne un *SagaError
+func (s *Saga[S]) Run(ctx context.Context, state *S, from int) (int, error) {
+	for i := from; i < len(s.steps); i++ {
+		err := s.steps[i].Run(ctx, state)
+		if errors.Is(err, ErrSagaSuspended) {
+			return i, err
+		}
+		if err != nil {
+			return i, s.compensate(ctx, state, i, err)
+		}
+	}
+	return len(s.steps), nil
+}
+
+// Abort compense les étapes précédant failed, comme si elle avait échoué avec cause (expiration...)
+func (s *Saga[S]) Abort(ctx context.Context, state *S, failed int, cause error) error {
+	return s.compensate(ctx, state, failed, cause)
+}
+
+// Len nombre d'étapes
+func (s *Saga[S]) Len() int {
+	return len(s.steps)
+}
+
+func (s *Saga[S]) compensate(ctx context.Context, state *S, failed int, cause error) error {
+	sagaErr := &SagaError{Step: s.steps[failed].Name, Err: cause}
+	// L'annulation va au bout même si la requête qui a déclenché l'échec est abandonnée
+	ctx = context.WithoutCancel(ctx)
+	for i := failed - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+		if err := step.Compensate(ctx, state); err != nil {
+			s.logger.Error("Saga compensation failed", err, map[string]interface{}{"saga": s.name, "step": step.Name})
+			sagaErr.Compensations = append(sagaErr.Compensations, fmt.Errorf("%s: %w", step.Name, err))
+		}
+	}
+	s.logger.Warn("Saga compensated", map[string]interface{}{
+		"saga": s.name, "failed_step": sagaErr.Step, "error": cause.Error(), "compensation_errors": len(sagaErr.Compensations),
+	})
+	return sagaErr
+}
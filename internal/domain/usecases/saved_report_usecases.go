@@ -0,0 +1,192 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// RAPPORTS ENREGISTRÉS : requêtes QueryEvents nommées, propres à chaque utilisateur
+// =============================================================================
+
+// maxSavedReportName longueur maximale du nom d'un rapport
+const maxSavedReportName = 100
+
+// ownedSavedReport rapport de l'appelant ; celui d'un autre utilisateur est introuvable
+func ownedSavedReport(ctx context.Context, repo repositories.SavedReportRepository, id int) (*entities.SavedReport, error) {
+	report, err := repo.Get(ctx, id)
+	if errors.Is(err, repositories.ErrSavedReportNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du rapport", err)
+	}
+	actor, _ := ActorFromContext(ctx)
+	if !actor.System && actor.UserID != report.OwnerID {
+		return nil, repositories.ErrSavedReportNotFound
+	}
+	return report, nil
+}
+
+// =============================================================================
+// CREATE SAVED REPORT USE CASE
+// =============================================================================
+
+type CreateSavedReportUseCase struct {
+	repo  repositories.SavedReportRepository
+	clock Clock
+}
+
+func NewCreateSavedReportUseCase(repo repositories.SavedReportRepository, clock Clock) *CreateSavedReportUseCase {
+	return &CreateSavedReportUseCase{repo: repo, clock: clock}
+}
+
+// CreateSavedReportRequest Where expression de filtre (ParseEventFilter) ; Days fenêtre glissante
+// (défaut : 30 jours) ; Interval day, week ou month (défaut : day)
+type CreateSavedReportRequest struct {
+	Name     string `json:"name"`
+	Where    string `json:"where,omitempty"`
+	Interval string `json:"interval,omitempty"`
+	Days     int    `json:"days,omitempty"`
+}
+
+func (req CreateSavedReportRequest) Validate() error {
+	if name := strings.TrimSpace(req.Name); name == "" || len(name) > maxSavedReportName {
+		return errors.New("le nom est obligatoire (100 caractères max)")
+	}
+	if req.Interval != "" && !slices.Contains(EventRollupIntervals, req.Interval) {
+		return errors.New("interval doit valoir day, week ou month")
+	}
+	if req.Days < 0 || req.Days > maxEventQueryDays {
+		return errors.New("days doit être compris entre 1 et 92")
+	}
+	_, err := ParseEventFilter(req.Where)
+	return err
+}
+
+func (req CreateSavedReportRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"name": req.Name, "where": req.Where, "interval": req.Interval, "days": req.Days}
+}
+
+func (uc *CreateSavedReportUseCase) Execute(ctx context.Context, req CreateSavedReportRequest) (*entities.SavedReport, error) {
+	actor, _ := ActorFromContext(ctx)
+	if actor.UserID <= 0 {
+		return nil, ErrAuthenticationRequired
+	}
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+	if req.Days == 0 {
+		req.Days = 30
+	}
+
+	report := entities.NewSavedReport(actor.UserID, strings.TrimSpace(req.Name), strings.TrimSpace(req.Where), req.Interval, req.Days, uc.clock.Now())
+	if err := uc.repo.Create(ctx, report); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du rapport", err)
+	}
+	return report, nil
+}
+
+// =============================================================================
+// LIST SAVED REPORTS USE CASE
+// =============================================================================
+
+type ListSavedReportsUseCase struct {
+	repo repositories.SavedReportRepository
+}
+
+func NewListSavedReportsUseCase(repo repositories.SavedReportRepository) *ListSavedReportsUseCase {
+	return &ListSavedReportsUseCase{repo: repo}
+}
+
+// ListSavedReportsRequest rapports de l'appelant
+type ListSavedReportsRequest struct{}
+
+type ListSavedReportsResponse struct {
+	Reports []*entities.SavedReport `json:"reports"`
+}
+
+func (uc *ListSavedReportsUseCase) Execute(ctx context.Context, _ ListSavedReportsRequest) (*ListSavedReportsResponse, error) {
+	actor, _ := ActorFromContext(ctx)
+	if actor.UserID <= 0 {
+		return nil, ErrAuthenticationRequired
+	}
+	reports, err := uc.repo.ListByOwner(ctx, actor.UserID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des rapports", err)
+	}
+	return &ListSavedReportsResponse{Reports: reports}, nil
+}
+
+// =============================================================================
+// RUN SAVED REPORT USE CASE
+// =============================================================================
+
+// RunSavedReportUseCase exécute la requête du rapport sur ses Days derniers jours, jour courant compris
+type RunSavedReportUseCase struct {
+	repo  repositories.SavedReportRepository
+	query *QueryEventsUseCase
+	clock Clock
+}
+
+func NewRunSavedReportUseCase(repo repositories.SavedReportRepository, query *QueryEventsUseCase, clock Clock) *RunSavedReportUseCase {
+	return &RunSavedReportUseCase{repo: repo, query: query, clock: clock}
+}
+
+type SavedReportRequest struct {
+	ID int `json:"-"`
+}
+
+func (req SavedReportRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"report_id": req.ID}
+}
+
+type RunSavedReportResponse struct {
+	Report *entities.SavedReport `json:"report"`
+	*GetEventRollupsResponse
+}
+
+func (uc *RunSavedReportUseCase) Execute(ctx context.Context, req SavedReportRequest) (*RunSavedReportResponse, error) {
+	report, err := ownedSavedReport(ctx, uc.repo, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	to := rollupDay(uc.clock.Now()).AddDate(0, 0, 1)
+	result, err := uc.query.Execute(ctx, QueryEventsRequest{
+		Where:    report.Where,
+		From:     to.AddDate(0, 0, -report.Days).Format(time.RFC3339),
+		To:       to.Format(time.RFC3339),
+		Interval: report.Interval,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RunSavedReportResponse{Report: report, GetEventRollupsResponse: result}, nil
+}
+
+// =============================================================================
+// DELETE SAVED REPORT USE CASE
+// =============================================================================
+
+type DeleteSavedReportUseCase struct {
+	repo repositories.SavedReportRepository
+}
+
+func NewDeleteSavedReportUseCase(repo repositories.SavedReportRepository) *DeleteSavedReportUseCase {
+	return &DeleteSavedReportUseCase{repo: repo}
+}
+
+func (uc *DeleteSavedReportUseCase) Execute(ctx context.Context, req SavedReportRequest) error {
+	if _, err := ownedSavedReport(ctx, uc.repo, req.ID); err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, req.ID); err != nil && !errors.Is(err, repositories.ErrSavedReportNotFound) {
+		return newError("erreur lors de la suppression du rapport", err)
+	}
+	return nil
+}
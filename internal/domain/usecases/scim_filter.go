@@ -0,0 +1,535 @@
+// internal/domain/usecases/scim_filter.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// FILTRES SCIM 2.0 (RFC 7644 §3.4.2.2)
+// =============================================================================
+
+// ErrInvalidSCIMFilter filtre mal formé, ou portant sur un attribut non pris en charge
+var ErrInvalidSCIMFilter = errors.New("filtre SCIM invalide")
+
+const (
+	scimUserSchema = "urn:ietf:params:scim:schemas:core:2.0:user:"
+	// Garde-fous : un filtre est fourni par le client
+	scimFilterMaxLength = 1000
+	scimFilterMaxDepth  = 16
+)
+
+type scimKind int
+
+const (
+	scimString scimKind = iota
+	scimCaseExactString
+	scimBoolean
+	scimDateTime
+)
+
+// scimScope attributs filtrables d'une ressource ; complex = attributs multi-valués
+// filtrables par valuePath (emails[type eq "work"])
+type scimScope struct {
+	kinds   map[string]scimKind
+	complex map[string]*scimScope
+}
+
+var (
+	scimEmailScope = &scimScope{kinds: map[string]scimKind{
+		"value":   scimString,
+		"type":    scimString,
+		"primary": scimBoolean,
+	}}
+	scimUserScope = &scimScope{
+		kinds: map[string]scimKind{
+			"id":                scimCaseExactString,
+			"username":          scimString,
+			"externalid":        scimCaseExactString,
+			"displayname":       scimString,
+			"name.formatted":    scimString,
+			"active":            scimBoolean,
+			"emails":            scimString,
+			"emails.value":      scimString,
+			"emails.type":       scimString,
+			"emails.primary":    scimBoolean,
+			"meta.created":      scimDateTime,
+			"meta.lastmodified": scimDateTime,
+		},
+		complex: map[string]*scimScope{"emails": scimEmailScope},
+	}
+)
+
+// scimValue valeur d'un attribut d'une ressource
+type scimValue struct {
+	present bool
+	text    string
+	boolean bool
+	time    time.Time
+}
+
+// scimRecord ressource évaluée par un filtre
+type scimRecord interface {
+	value(attr string) scimValue
+	elements(attr string) []scimRecord
+}
+
+type scimExpr interface {
+	matches(record scimRecord) bool
+}
+
+// SCIMFilter filtre compilé : les attributs et les types des valeurs sont vérifiés à la compilation
+type SCIMFilter struct {
+	root scimExpr
+	// usesExternalID l'évaluation a besoin de l'externalId (lien à charger pour chaque candidat)
+	usesExternalID bool
+}
+
+// ParseSCIMFilter compile un filtre ; les erreurs enveloppent ErrInvalidSCIMFilter
+func ParseSCIMFilter(raw string) (*SCIMFilter, error) {
+	if len(raw) > scimFilterMaxLength {
+		return nil, fmt.Errorf("%w : filtre trop long", ErrInvalidSCIMFilter)
+	}
+	tokens, err := tokenizeSCIMFilter(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidSCIMFilter, err)
+	}
+	parser := &scimFilterParser{tokens: tokens}
+	root, err := parser.parseOr(scimUserScope, 0)
+	if err == nil && parser.pos < len(parser.tokens) {
+		err = fmt.Errorf("élément inattendu %q", parser.tokens[parser.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidSCIMFilter, err)
+	}
+	return &SCIMFilter{root: root, usesExternalID: parser.usesExternalID}, nil
+}
+
+func (f *SCIMFilter) matches(record scimRecord) bool {
+	return f == nil || f.root.matches(record)
+}
+
+// equality valeur recherchée si le filtre se résume à attr eq "valeur" (accès direct sans parcours)
+func (f *SCIMFilter) equality(attr string) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+	compare, ok := f.root.(*scimCompare)
+	if !ok || compare.attr != attr || compare.op != "eq" || compare.null {
+		return "", false
+	}
+	return compare.operand.text, true
+}
+
+// =============================================================================
+// EXPRESSIONS
+// =============================================================================
+
+type scimLogical struct {
+	and         bool
+	left, right scimExpr
+}
+
+func (e *scimLogical) matches(record scimRecord) bool {
+	if e.and {
+		return e.left.matches(record) && e.right.matches(record)
+	}
+	return e.left.matches(record) || e.right.matches(record)
+}
+
+type scimNot struct {
+	inner scimExpr
+}
+
+func (e *scimNot) matches(record scimRecord) bool {
+	return !e.inner.matches(record)
+}
+
+type scimPresent struct {
+	attr string
+}
+
+func (e *scimPresent) matches(record scimRecord) bool {
+	return record.value(e.attr).present
+}
+
+// scimValuePath au moins un élément de l'attribut multi-valué satisfait le filtre
+type scimValuePath struct {
+	attr  string
+	inner scimExpr
+}
+
+func (e *scimValuePath) matches(record scimRecord) bool {
+	for _, element := range record.elements(e.attr) {
+		if e.inner.matches(element) {
+			return true
+		}
+	}
+	return false
+}
+
+type scimCompare struct {
+	attr    string
+	op      string
+	kind    scimKind
+	operand scimValue
+	// null comparaison à null : eq = absent, ne = présent
+	null bool
+}
+
+func (e *scimCompare) matches(record scimRecord) bool {
+	value := record.value(e.attr)
+	if e.null || !value.present {
+		return (e.op == "eq") == !value.present
+	}
+
+	switch e.kind {
+	case scimBoolean:
+		return (value.boolean == e.operand.boolean) == (e.op == "eq")
+	case scimDateTime:
+		return compareSCIMOrder(e.op, value.time.Compare(e.operand.time))
+	}
+
+	actual, expected := value.text, e.operand.text
+	if e.kind == scimString {
+		actual, expected = strings.ToLower(actual), strings.ToLower(expected)
+	}
+	switch e.op {
+	case "co":
+		return strings.Contains(actual, expected)
+	case "sw":
+		return strings.HasPrefix(actual, expected)
+	case "ew":
+		return strings.HasSuffix(actual, expected)
+	}
+	return compareSCIMOrder(e.op, strings.Compare(actual, expected))
+}
+
+func compareSCIMOrder(op string, order int) bool {
+	switch op {
+	case "eq":
+		return order == 0
+	case "ne":
+		return order != 0
+	case "gt":
+		return order > 0
+	case "ge":
+		return order >= 0
+	case "lt":
+		return order < 0
+	case "le":
+		return order <= 0
+	}
+	return false
+}
+
+// =============================================================================
+// ANALYSE
+// =============================================================================
+
+type scimTokenKind int
+
+const (
+	scimTokenWord scimTokenKind = iota
+	scimTokenString
+	scimTokenPunct
+)
+
+type scimToken struct {
+	kind scimTokenKind
+	text string
+}
+
+func tokenizeSCIMFilter(raw string) ([]scimToken, error) {
+	var tokens []scimToken
+	for i := 0; i < len(raw); {
+		switch c := raw[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, scimToken{scimTokenPunct, string(c)})
+			i++
+		case c == '"':
+			// Chaîne JSON : on repère la fin (guillemet non échappé) puis on laisse json décoder
+			end := i + 1
+			for end < len(raw) && raw[end] != '"' {
+				if raw[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(raw) {
+				return nil, errors.New("chaîne non terminée")
+			}
+			var text string
+			if err := json.Unmarshal([]byte(raw[i:end+1]), &text); err != nil {
+				return nil, errors.New("chaîne invalide")
+			}
+			tokens = append(tokens, scimToken{scimTokenString, text})
+			i = end + 1
+		default:
+			end := i
+			for end < len(raw) && !strings.ContainsRune(" \t\n\r()[]\"", rune(raw[end])) {
+				end++
+			}
+			tokens = append(tokens, scimToken{scimTokenWord, raw[i:end]})
+			i = end
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("filtre vide")
+	}
+	return tokens, nil
+}
+
+type scimFilterParser struct {
+	tokens         []scimToken
+	pos            int
+	usesExternalID bool
+}
+
+func (p *scimFilterParser) peek() (scimToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return scimToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *scimFilterParser) peekKeyword(keyword string) bool {
+	token, ok := p.peek()
+	return ok && token.kind == scimTokenWord && strings.EqualFold(token.text, keyword)
+}
+
+func (p *scimFilterParser) expect(punct string) error {
+	token, ok := p.peek()
+	if !ok || token.kind != scimTokenPunct || token.text != punct {
+		return fmt.Errorf("%q attendu", punct)
+	}
+	p.pos++
+	return nil
+}
+
+// parseOr "and" est prioritaire sur "or"
+func (p *scimFilterParser) parseOr(scope *scimScope, depth int) (scimExpr, error) {
+	left, err := p.parseAnd(scope, depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd(scope, depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &scimLogical{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseAnd(scope *scimScope, depth int) (scimExpr, error) {
+	left, err := p.parseUnary(scope, depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseUnary(scope, depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &scimLogical{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseUnary(scope *scimScope, depth int) (scimExpr, error) {
+	if depth > scimFilterMaxDepth {
+		return nil, errors.New("filtre trop imbriqué")
+	}
+	token, ok := p.peek()
+	if !ok {
+		return nil, errors.New("expression attendue")
+	}
+
+	if p.peekKeyword("not") {
+		p.pos++
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr(scope, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &scimNot{inner: inner}, p.expect(")")
+	}
+	if token.kind == scimTokenPunct && token.text == "(" {
+		p.pos++
+		inner, err := p.parseOr(scope, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+	if token.kind != scimTokenWord {
+		return nil, fmt.Errorf("attribut attendu, %q trouvé", token.text)
+	}
+	p.pos++
+	return p.parseAttributeExpression(scope, normalizeSCIMAttribute(token.text), depth)
+}
+
+func (p *scimFilterParser) parseAttributeExpression(scope *scimScope, attr string, depth int) (scimExpr, error) {
+	if attr == "externalid" {
+		p.usesExternalID = true
+	}
+
+	// valuePath : emails[type eq "work" and value co "@example.com"]
+	if token, ok := p.peek(); ok && token.kind == scimTokenPunct && token.text == "[" {
+		subScope, ok := scope.complex[attr]
+		if !ok {
+			return nil, fmt.Errorf("attribut %q non filtrable par valeurs", attr)
+		}
+		p.pos++
+		inner, err := p.parseOr(subScope, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &scimValuePath{attr: attr, inner: inner}, p.expect("]")
+	}
+
+	kind, ok := scope.kinds[attr]
+	if !ok {
+		return nil, fmt.Errorf("attribut %q non pris en charge", attr)
+	}
+	token, ok := p.peek()
+	if !ok || token.kind != scimTokenWord {
+		return nil, fmt.Errorf("opérateur attendu après %q", attr)
+	}
+	op := strings.ToLower(token.text)
+	p.pos++
+	if op == "pr" {
+		return &scimPresent{attr: attr}, nil
+	}
+	switch op {
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, fmt.Errorf("opérateur %q inconnu", token.text)
+	}
+
+	operandToken, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("valeur attendue après %q", op)
+	}
+	p.pos++
+	compare := &scimCompare{attr: attr, op: op, kind: kind}
+	if operandToken.kind == scimTokenWord && operandToken.text == "null" {
+		if op != "eq" && op != "ne" {
+			return nil, errors.New("null n'est comparable qu'avec eq ou ne")
+		}
+		compare.null = true
+		return compare, nil
+	}
+
+	switch kind {
+	case scimBoolean:
+		value := strings.EqualFold(operandToken.text, "true")
+		if operandToken.kind != scimTokenWord || (!value && !strings.EqualFold(operandToken.text, "false")) {
+			return nil, fmt.Errorf("%q attend true ou false", attr)
+		}
+		if op != "eq" && op != "ne" {
+			return nil, fmt.Errorf("opérateur %q non applicable à un booléen", op)
+		}
+		compare.operand = scimValue{present: true, boolean: value}
+	case scimDateTime:
+		if operandToken.kind != scimTokenString {
+			return nil, fmt.Errorf("%q attend une date", attr)
+		}
+		value, err := time.Parse(time.RFC3339, operandToken.text)
+		if err != nil {
+			return nil, fmt.Errorf("%q attend une date RFC 3339", attr)
+		}
+		if op == "co" || op == "sw" || op == "ew" {
+			return nil, fmt.Errorf("opérateur %q non applicable à une date", op)
+		}
+		compare.operand = scimValue{present: true, time: value}
+	default:
+		if operandToken.kind != scimTokenString {
+			return nil, fmt.Errorf("%q attend une chaîne entre guillemets", attr)
+		}
+		compare.operand = scimValue{present: true, text: operandToken.text}
+	}
+	return compare, nil
+}
+
+// normalizeSCIMAttribute noms insensibles à la casse, préfixe de schéma facultatif
+func normalizeSCIMAttribute(raw string) string {
+	attr := strings.ToLower(raw)
+	return strings.TrimPrefix(attr, scimUserSchema)
+}
+
+// =============================================================================
+// RESSOURCE USER ÉVALUÉE
+// =============================================================================
+
+// scimUserRecord un seul email par utilisateur (professionnel, principal) : emails.value = userName
+type scimUserRecord struct {
+	user       *entities.User
+	externalID string
+}
+
+func (r scimUserRecord) value(attr string) scimValue {
+	text := func(value string) scimValue { return scimValue{present: value != "", text: value} }
+	switch attr {
+	case "id":
+		return text(strconv.Itoa(r.user.ID))
+	case "username", "emails", "emails.value":
+		return text(r.user.Email)
+	case "externalid":
+		return text(r.externalID)
+	case "displayname", "name.formatted":
+		return text(r.user.Name)
+	case "emails.type":
+		return text("work")
+	case "emails.primary":
+		return scimValue{present: true, boolean: true}
+	case "active":
+		return scimValue{present: true, boolean: r.user.IsActive()}
+	case "meta.created":
+		return scimValue{present: true, time: r.user.Created}
+	case "meta.lastmodified":
+		return scimValue{present: true, time: r.user.Updated}
+	}
+	return scimValue{}
+}
+
+func (r scimUserRecord) elements(attr string) []scimRecord {
+	if attr == "emails" {
+		return []scimRecord{scimEmailRecord{email: r.user.Email}}
+	}
+	return nil
+}
+
+type scimEmailRecord struct {
+	email string
+}
+
+func (r scimEmailRecord) value(attr string) scimValue {
+	switch attr {
+	case "value":
+		return scimValue{present: r.email != "", text: r.email}
+	case "type":
+		return scimValue{present: true, text: "work"}
+	case "primary":
+		return scimValue{present: true, boolean: true}
+	}
+	return scimValue{}
+}
+
+func (r scimEmailRecord) elements(string) []scimRecord {
+	return nil
+}
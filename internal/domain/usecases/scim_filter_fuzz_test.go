@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// `go test -fuzz=FuzzParseSCIMFilter ./internal/domain/usecases` : le filtre vient du fournisseur
+// d'identité, toute entrée doit être compilée ou refusée proprement
+
+func FuzzParseSCIMFilter(f *testing.F) {
+	for _, seed := range []string{
+		`userName eq "alice@example.com"`,
+		`active eq true or userName sw "a" and not (displayName co "b")`,
+		`emails[type eq "work" and value ew "@example.com"]`,
+		`meta.lastModified gt "2026-03-02T10:00:00Z"`,
+		`externalId eq null`,
+		`displayName eq "a\" or userName pr or \"b\\"`,
+		`userName eq "é😀"`,
+		`((((userName pr))))`,
+		`emails[emails[value pr]]`,
+		`not (`,
+		`"`,
+		`userName eq "\`,
+	} {
+		f.Add(seed)
+	}
+	record := scimUserRecord{user: &entities.User{ID: 1, Email: "alice@example.com", Name: "Alice", Created: time.Unix(0, 0)}}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		filter, err := ParseSCIMFilter(raw)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidSCIMFilter) || filter != nil {
+				t.Fatalf("ParseSCIMFilter(%q) = %v, %v", raw, filter, err)
+			}
+			return
+		}
+		filter.matches(record)
+
+		// La forme canonique se recompile à l'identique, sauf si ses parenthèses dépassent les limites
+		canonical := describeSCIMFilter(filter.root)
+		again, err := ParseSCIMFilter(canonical)
+		if err != nil {
+			if len(canonical) > scimFilterMaxLength || strings.Contains(err.Error(), "imbriqué") {
+				return
+			}
+			t.Fatalf("forme canonique %q de %q refusée : %v", canonical, raw, err)
+		}
+		if got := describeSCIMFilter(again.root); got != canonical {
+			t.Fatalf("%q : %q puis %q", raw, canonical, got)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describeSCIMFilter forme canonique d'un filtre compilé, entièrement parenthésée : elle fixe
+// la structure (précédence, groupes) et reste un filtre valide
+func describeSCIMFilter(expr scimExpr) string {
+	switch e := expr.(type) {
+	case *scimLogical:
+		op := " or "
+		if e.and {
+			op = " and "
+		}
+		return "(" + describeSCIMFilter(e.left) + op + describeSCIMFilter(e.right) + ")"
+	case *scimNot:
+		return "not (" + describeSCIMFilter(e.inner) + ")"
+	case *scimPresent:
+		return e.attr + " pr"
+	case *scimValuePath:
+		return e.attr + "[" + describeSCIMFilter(e.inner) + "]"
+	case *scimCompare:
+		operand := "null"
+		switch {
+		case e.null:
+		case e.kind == scimBoolean:
+			operand = "false"
+			if e.operand.boolean {
+				operand = "true"
+			}
+		case e.kind == scimDateTime:
+			operand = `"` + e.operand.time.UTC().Format(time.RFC3339Nano) + `"`
+		default:
+			quoted, _ := json.Marshal(e.operand.text)
+			operand = string(quoted)
+		}
+		return e.attr + " " + e.op + " " + operand
+	}
+	return "?"
+}
+
+func TestParseSCIMFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   string
+	}{
+		{"égalité", `userName eq "alice@example.com"`, `username eq "alice@example.com"`},
+		{"schéma et casse", `urn:ietf:params:scim:schemas:core:2.0:User:USERNAME EQ "a"`, `username eq "a"`},
+		{"and prioritaire sur or", `active eq true or userName sw "a" and displayName co "b"`, `(active eq true or (username sw "a" and displayname co "b"))`},
+		{"and puis or", `userName sw "a" and displayName co "b" or active eq false`, `((username sw "a" and displayname co "b") or active eq false)`},
+		{"or associatif à gauche", `id eq "1" or id eq "2" or id eq "3"`, `((id eq "1" or id eq "2") or id eq "3")`},
+		{"groupement", `(active eq true or userName sw "a") and displayName co "b"`, `((active eq true or username sw "a") and displayname co "b")`},
+		{"not", `not (active eq true) and userName pr`, `(not (active eq true) and username pr)`},
+		{"not imbriqué", `not (not (userName pr))`, `not (not (username pr))`},
+		{"valuePath", `emails[type eq "work" and value ew "@example.com"]`, `emails[(type eq "work" and value ew "@example.com")]`},
+		{"null", `externalId eq null`, `externalid eq null`},
+		{"date", `meta.lastModified gt "2026-03-02T10:00:00+01:00"`, `meta.lastmodified gt "2026-03-02T09:00:00Z"`},
+		{"guillemet échappé", `displayName eq "Alice \"Al\" Martin"`, `displayname eq "Alice \"Al\" Martin"`},
+		{"barre oblique inverse échappée", `displayName eq "a\\"`, `displayname eq "a\\"`},
+		{"séquence unicode", `displayName eq "\u00e9lodie"`, `displayname eq "élodie"`},
+		// Un mot-clé dans une chaîne reste une valeur, jamais un opérateur
+		{"mots-clés dans une chaîne", `displayName eq "a\" or userName pr or \"b"`, `displayname eq "a\" or userName pr or \"b"`},
+		{"parenthèses dans une chaîne", `displayName co ")(" and active eq true`, `(displayname co ")(" and active eq true)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseSCIMFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("filtre refusé : %v", err)
+			}
+			if got := describeSCIMFilter(filter.root); got != tt.want {
+				t.Fatalf("structure %s, attendu %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSCIMFilterRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+	}{
+		{"vide", ""},
+		{"blancs", "  \t "},
+		{"attribut inconnu", `password eq "secret"`},
+		{"attribut d'un autre schéma", `urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:employeeNumber eq "1"`},
+		{"sous-attribut inconnu", `emails[password eq "x"]`},
+		{"valuePath sur un attribut simple", `userName[value eq "x"]`},
+		{"valuePath imbriqué", `emails[emails[value eq "x"]]`},
+		{"opérateur inconnu", `userName like "a%"`},
+		{"opérateur absent", `userName "a"`},
+		{"valeur absente", `userName eq`},
+		{"valeur non quotée", `userName eq alice`},
+		{"chaîne non terminée", `userName eq "alice`},
+		{"échappement en fin de chaîne", `userName eq "alice\`},
+		{"échappement invalide", `userName eq "\x41"`},
+		{"booléen quoté", `active eq "true"`},
+		{"booléen invalide", `active eq yes`},
+		{"ordre sur un booléen", `active gt true`},
+		{"date invalide", `meta.created gt "hier"`},
+		{"co sur une date", `meta.created co "2026"`},
+		{"null avec co", `userName co null`},
+		{"parenthèse non fermée", `(userName pr`},
+		{"parenthèse en trop", `userName pr)`},
+		{"crochet non fermé", `emails[value pr`},
+		{"not sans parenthèses", `not userName pr`},
+		{"and en tête", `and userName pr`},
+		{"and sans opérande", `userName pr and`},
+		{"deux expressions sans opérateur", `userName pr active pr`},
+		{"guillemet en attribut", `"userName" eq "a"`},
+		{"trop long", `displayName eq "` + strings.Repeat("a", scimFilterMaxLength) + `"`},
+		{"trop imbriqué", strings.Repeat("(", scimFilterMaxDepth+1) + "userName pr" + strings.Repeat(")", scimFilterMaxDepth+1)},
+		{"not trop imbriqués", strings.Repeat("not (", scimFilterMaxDepth+1) + "userName pr" + strings.Repeat(")", scimFilterMaxDepth+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseSCIMFilter(tt.filter)
+			if !errors.Is(err, ErrInvalidSCIMFilter) || filter != nil {
+				t.Fatalf("filtre %v, erreur %v, attendu ErrInvalidSCIMFilter", filter, err)
+			}
+		})
+	}
+
+	// Limite d'imbrication atteinte sans être dépassée
+	nested := strings.Repeat("(", scimFilterMaxDepth) + "userName pr" + strings.Repeat(")", scimFilterMaxDepth)
+	if _, err := ParseSCIMFilter(nested); err != nil {
+		t.Fatalf("%d niveaux refusés : %v", scimFilterMaxDepth, err)
+	}
+}
+
+func TestSCIMFilterMatches(t *testing.T) {
+	created := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	record := scimUserRecord{
+		user:       &entities.User{ID: 7, Email: "alice@example.com", Name: `Alice "Al" Martin`, Created: created, Updated: created},
+		externalID: "ext-7",
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`userName eq "ALICE@example.com"`, true},
+		{`externalId eq "EXT-7"`, false},
+		{`externalId eq "ext-7"`, true},
+		{`id eq "7"`, true},
+		{`displayName eq "Alice \"Al\" Martin"`, true},
+		{`active eq true or userName eq "bob@example.com" and active eq false`, true},
+		{`(active eq true or userName eq "bob@example.com") and active eq false`, false},
+		{`not (userName sw "alice")`, false},
+		{`emails[type eq "work" and value ew "@example.com"]`, true},
+		{`emails[type eq "home"]`, false},
+		{`meta.created ge "2026-03-02T10:00:00Z" and meta.created lt "2026-03-02T10:00:01Z"`, true},
+		{`externalId eq null`, false},
+		{`name.formatted ne null`, true},
+	}
+	for _, tt := range tests {
+		filter, err := ParseSCIMFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("%s : %v", tt.filter, err)
+		}
+		if got := filter.matches(record); got != tt.want {
+			t.Errorf("%s : %v, attendu %v", tt.filter, got, tt.want)
+		}
+	}
+}
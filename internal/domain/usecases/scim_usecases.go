@@ -0,0 +1,740 @@
+// internal/domain/usecases/scim_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// PROVISIONNEMENT SCIM 2.0 (OKTA, ENTRA ID...)
+// =============================================================================
+
+var (
+	// ErrSCIMUserNameTaken userName déjà porté par un autre compte (SCIM : 409 uniqueness)
+	ErrSCIMUserNameTaken = errors.New("userName déjà utilisé")
+	// ErrSCIMExternalIDTaken externalId déjà rattaché à un autre compte
+	ErrSCIMExternalIDTaken = errors.New("externalId déjà rattaché à un autre utilisateur")
+	// ErrInvalidSCIMPatch opération PATCH mal formée
+	ErrInvalidSCIMPatch = errors.New("opération PATCH SCIM invalide")
+)
+
+const (
+	// scimLinkSource source des liens externalId ↔ utilisateur local
+	scimLinkSource = "scim"
+	// SCIMMaxResults taille de page maximale d'une liste (annoncée dans ServiceProviderConfig)
+	SCIMMaxResults = 200
+	// scimDefaultResults taille de page sans paramètre count
+	scimDefaultResults = 100
+)
+
+// SCIMUserResponse utilisateur tel qu'exposé au client SCIM ; userName est l'email du compte
+type SCIMUserResponse struct {
+	ID          int
+	ExternalID  string
+	UserName    string
+	DisplayName string
+	Active      bool
+	Created     time.Time
+	Updated     time.Time
+}
+
+// SCIMUserRequest représentation complète d'un utilisateur (POST, PUT)
+type SCIMUserRequest struct {
+	UserName   string
+	ExternalID string
+	// DisplayName vide = partie locale de userName (le nom est obligatoire côté entité)
+	DisplayName string
+	Active      bool
+	// Password facultatif : sans lui, le compte reçoit un mot de passe aléatoire (connexion SSO)
+	Password string
+}
+
+func (req SCIMUserRequest) Validate() error {
+	if strings.TrimSpace(req.UserName) == "" {
+		return errors.New("userName obligatoire")
+	}
+	return nil
+}
+
+func (req SCIMUserRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_name": req.UserName, "external_id": req.ExternalID, "active": req.Active}
+}
+
+// scimUserChanges attributs à modifier ; nil = inchangé, ExternalID vide = lien retiré
+type scimUserChanges struct {
+	UserName    *string
+	DisplayName *string
+	Active      *bool
+	ExternalID  *string
+}
+
+// scimUsers opérations communes aux use cases SCIM
+type scimUsers struct {
+	userRepo  repositories.UserRepository
+	linkRepo  repositories.ExternalUserLinkRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func newSCIMUsers(userRepo repositories.UserRepository, linkRepo repositories.ExternalUserLinkRepository, publisher EventPublisher, clock Clock) *scimUsers {
+	return &scimUsers{userRepo: userRepo, linkRepo: linkRepo, publisher: publisher, clock: clock}
+}
+
+// load ErrUserNotFound tel quel : le handler répond 404
+func (s *scimUsers) load(ctx context.Context, id int) (*entities.User, error) {
+	user, err := s.userRepo.GetById(ctx, id)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, repositories.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la recherche de l'utilisateur", err)
+	}
+	return user, nil
+}
+
+func (s *scimUsers) externalID(ctx context.Context, userID int) (string, error) {
+	link, err := s.linkRepo.GetByUserID(ctx, scimLinkSource, userID)
+	if errors.Is(err, repositories.ErrExternalUserLinkNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", newError("erreur lors de la lecture du lien SCIM", err)
+	}
+	return link.ExternalID, nil
+}
+
+func (s *scimUsers) response(ctx context.Context, user *entities.User) (*SCIMUserResponse, error) {
+	externalID, err := s.externalID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newSCIMUserResponse(user, externalID), nil
+}
+
+func newSCIMUserResponse(user *entities.User, externalID string) *SCIMUserResponse {
+	return &SCIMUserResponse{
+		ID:          user.ID,
+		ExternalID:  externalID,
+		UserName:    user.Email,
+		DisplayName: user.Name,
+		Active:      user.IsActive(),
+		Created:     user.Created,
+		Updated:     user.Updated,
+	}
+}
+
+// apply enregistre les modifications en une écriture et publie leurs événements
+// Un compte banni relève de la modération : le provisionnement ne touche pas à son statut
+func (s *scimUsers) apply(ctx context.Context, user *entities.User, changes scimUserChanges) error {
+	if changes.UserName != nil {
+		email := strings.ToLower(strings.TrimSpace(*changes.UserName))
+		changes.UserName = &email
+		if email != user.Email {
+			taken, err := s.userRepo.IsEmailTaken(ctx, email)
+			if err != nil {
+				return newError("erreur lors de la vérification de l'email", err)
+			}
+			if taken {
+				return ErrSCIMUserNameTaken
+			}
+		}
+	}
+	if changes.ExternalID != nil {
+		if err := s.checkExternalID(ctx, user.ID, *changes.ExternalID); err != nil {
+			return err
+		}
+	}
+
+	now := s.clock.Now()
+	profileChanges, err := user.PatchProfile(changes.DisplayName, changes.UserName, now)
+	if err != nil {
+		return err
+	}
+	statusChanged := false
+	if changes.Active != nil && *changes.Active != user.IsActive() && user.CurrentStatus() != entities.UserStatusBanned {
+		if *changes.Active {
+			err = user.Reactivate(now)
+		} else {
+			_, err = user.Deactivate(false, now)
+		}
+		if err != nil {
+			return err
+		}
+		statusChanged = true
+	}
+
+	if len(profileChanges) > 0 || statusChanged {
+		if _, err := s.userRepo.Update(ctx, user); err != nil {
+			return newError("erreur lors de la mise à jour", err)
+		}
+		if len(profileChanges) > 0 {
+			s.publisher.Publish(ctx, events.UserProfileUpdated{
+				UserID:        user.ID,
+				Email:         user.Email,
+				Name:          user.Name,
+				ChangedFields: entities.ChangedFields(profileChanges),
+				Updated:       user.Updated,
+			})
+		}
+		if statusChanged {
+			s.publisher.Publish(ctx, events.UserStatusChanged{
+				UserID:  user.ID,
+				Status:  string(user.CurrentStatus()),
+				Reason:  "provisionnement SCIM",
+				Changed: user.Updated,
+			})
+		}
+	}
+
+	if changes.ExternalID != nil {
+		return s.linkExternalID(ctx, user, *changes.ExternalID)
+	}
+	return nil
+}
+
+func (s *scimUsers) checkExternalID(ctx context.Context, userID int, externalID string) error {
+	if externalID == "" {
+		return nil
+	}
+	link, err := s.linkRepo.GetByExternalID(ctx, scimLinkSource, externalID)
+	switch {
+	case errors.Is(err, repositories.ErrExternalUserLinkNotFound):
+		return nil
+	case err != nil:
+		return newError("erreur lors de la lecture du lien SCIM", err)
+	case link.UserID != userID:
+		// Un lien vers un compte supprimé depuis ne bloque pas
+		if _, err := s.userRepo.GetById(ctx, link.UserID); errors.Is(err, repositories.ErrUserNotFound) {
+			return nil
+		}
+		return ErrSCIMExternalIDTaken
+	}
+	return nil
+}
+
+// linkExternalID remplace le lien SCIM du compte (un seul externalId par utilisateur)
+func (s *scimUsers) linkExternalID(ctx context.Context, user *entities.User, externalID string) error {
+	current, err := s.externalID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if current == externalID {
+		return nil
+	}
+	if current != "" {
+		if err := s.linkRepo.Delete(ctx, scimLinkSource, current); err != nil {
+			return newError("erreur lors de la suppression du lien SCIM", err)
+		}
+	}
+	if externalID == "" {
+		return nil
+	}
+	if err := s.linkRepo.Save(ctx, &repositories.ExternalUserLink{
+		Source:     scimLinkSource,
+		ExternalID: externalID,
+		UserID:     user.ID,
+		Email:      user.Email,
+		Name:       user.Name,
+		Active:     user.IsActive(),
+		SyncedAt:   s.clock.Now(),
+	}); err != nil {
+		return newError("erreur lors de l'enregistrement du lien SCIM", err)
+	}
+	return nil
+}
+
+// scimDisplayName nom du compte à défaut de displayName
+func scimDisplayName(displayName, userName string) string {
+	if name := strings.TrimSpace(displayName); name != "" {
+		return name
+	}
+	local, _, _ := strings.Cut(strings.TrimSpace(userName), "@")
+	return local
+}
+
+// =============================================================================
+// CREATE SCIM USER USE CASE
+// =============================================================================
+
+type CreateSCIMUserUseCase struct {
+	users          *scimUsers
+	hasher         PasswordHasher
+	tokenGenerator TokenGenerator
+}
+
+func NewCreateSCIMUserUseCase(
+	userRepo repositories.UserRepository,
+	linkRepo repositories.ExternalUserLinkRepository,
+	hasher PasswordHasher,
+	publisher EventPublisher,
+	clock Clock,
+	tokenGenerator TokenGenerator,
+) *CreateSCIMUserUseCase {
+	return &CreateSCIMUserUseCase{
+		users:          newSCIMUsers(userRepo, linkRepo, publisher, clock),
+		hasher:         hasher,
+		tokenGenerator: tokenGenerator,
+	}
+}
+
+func (uc *CreateSCIMUserUseCase) Execute(ctx context.Context, req SCIMUserRequest) (*SCIMUserResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.UserName))
+	taken, err := uc.users.userRepo.IsEmailTaken(ctx, email)
+	if err != nil {
+		return nil, newError("erreur lors de la vérification de l'email", err)
+	}
+	if taken {
+		return nil, ErrSCIMUserNameTaken
+	}
+	if err := uc.users.checkExternalID(ctx, 0, req.ExternalID); err != nil {
+		return nil, err
+	}
+
+	password := req.Password
+	if password == "" {
+		if password, err = randomProvisioningPassword(uc.tokenGenerator); err != nil {
+			return nil, newError("erreur lors de la génération du mot de passe", err)
+		}
+	}
+	user, err := entities.NewUser(email, scimDisplayName(req.DisplayName, email), password, uc.users.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if user.Password, err = uc.hasher.Hash(user.Password); err != nil {
+		return nil, newError("erreur lors du hachage du mot de passe", err)
+	}
+
+	created, err := uc.users.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, newError("erreur lors de la création de l'utilisateur", err)
+	}
+	uc.users.publisher.Publish(ctx, events.UserCreated{
+		UserID:  created.ID,
+		Email:   created.Email,
+		Name:    created.Name,
+		Created: created.Created,
+	})
+
+	// Compte créé inactif (pré-provisionnement) : désactivé aussitôt, événement compris
+	if err := uc.users.apply(ctx, created, scimUserChanges{Active: &req.Active, ExternalID: &req.ExternalID}); err != nil {
+		return nil, err
+	}
+	return newSCIMUserResponse(created, req.ExternalID), nil
+}
+
+// =============================================================================
+// GET SCIM USER USE CASE
+// =============================================================================
+
+type GetSCIMUserUseCase struct {
+	users *scimUsers
+}
+
+func NewGetSCIMUserUseCase(userRepo repositories.UserRepository, linkRepo repositories.ExternalUserLinkRepository) *GetSCIMUserUseCase {
+	return &GetSCIMUserUseCase{users: newSCIMUsers(userRepo, linkRepo, nil, nil)}
+}
+
+func (uc *GetSCIMUserUseCase) Execute(ctx context.Context, id int) (*SCIMUserResponse, error) {
+	user, err := uc.users.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.users.response(ctx, user)
+}
+
+// =============================================================================
+// REPLACE SCIM USER USE CASE (PUT)
+// =============================================================================
+
+type ReplaceSCIMUserUseCase struct {
+	users *scimUsers
+}
+
+func NewReplaceSCIMUserUseCase(
+	userRepo repositories.UserRepository,
+	linkRepo repositories.ExternalUserLinkRepository,
+	publisher EventPublisher,
+	clock Clock,
+) *ReplaceSCIMUserUseCase {
+	return &ReplaceSCIMUserUseCase{users: newSCIMUsers(userRepo, linkRepo, publisher, clock)}
+}
+
+// ReplaceSCIMUserRequest le mot de passe n'est pas modifiable par PUT (changement réservé à l'utilisateur)
+type ReplaceSCIMUserRequest struct {
+	ID int
+	SCIMUserRequest
+}
+
+func (req ReplaceSCIMUserRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.ID}
+}
+
+func (req ReplaceSCIMUserRequest) LogFields() map[string]interface{} {
+	fields := req.SCIMUserRequest.LogFields()
+	fields["user_id"] = req.ID
+	return fields
+}
+
+func (uc *ReplaceSCIMUserUseCase) Execute(ctx context.Context, req ReplaceSCIMUserRequest) (*SCIMUserResponse, error) {
+	user, err := uc.users.load(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := scimDisplayName(req.DisplayName, req.UserName)
+	if err := uc.users.apply(ctx, user, scimUserChanges{
+		UserName:    &req.UserName,
+		DisplayName: &displayName,
+		Active:      &req.Active,
+		ExternalID:  &req.ExternalID,
+	}); err != nil {
+		return nil, err
+	}
+	return newSCIMUserResponse(user, req.ExternalID), nil
+}
+
+// =============================================================================
+// PATCH SCIM USER USE CASE (RFC 7644 §3.5.2)
+// =============================================================================
+
+type PatchSCIMUserUseCase struct {
+	users *scimUsers
+}
+
+func NewPatchSCIMUserUseCase(
+	userRepo repositories.UserRepository,
+	linkRepo repositories.ExternalUserLinkRepository,
+	publisher EventPublisher,
+	clock Clock,
+) *PatchSCIMUserUseCase {
+	return &PatchSCIMUserUseCase{users: newSCIMUsers(userRepo, linkRepo, publisher, clock)}
+}
+
+// SCIMPatchOperation Value est la valeur JSON décodée (chaîne, booléen, objet...)
+type SCIMPatchOperation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+type PatchSCIMUserRequest struct {
+	ID         int
+	Operations []SCIMPatchOperation
+}
+
+func (req PatchSCIMUserRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.ID}
+}
+
+func (req PatchSCIMUserRequest) Validate() error {
+	if len(req.Operations) == 0 {
+		return fmt.Errorf("%w : aucune opération", ErrInvalidSCIMPatch)
+	}
+	_, err := compileSCIMPatch(req.Operations)
+	return err
+}
+
+func (req PatchSCIMUserRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.ID, "operations": len(req.Operations)}
+}
+
+func (uc *PatchSCIMUserUseCase) Execute(ctx context.Context, req PatchSCIMUserRequest) (*SCIMUserResponse, error) {
+	changes, err := compileSCIMPatch(req.Operations)
+	if err != nil {
+		return nil, err
+	}
+	user, err := uc.users.load(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.users.apply(ctx, user, changes); err != nil {
+		return nil, err
+	}
+	return uc.users.response(ctx, user)
+}
+
+// compileSCIMPatch traduit les opérations en modifications du compte
+// Les attributs que le compte ne stocke pas (title, addresses, name.givenName seul...) sont
+// ignorés plutôt que refusés : les IdP les envoient tous, un refus bloquerait le provisionnement
+func compileSCIMPatch(operations []SCIMPatchOperation) (scimUserChanges, error) {
+	var changes scimUserChanges
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		switch op {
+		case "add", "replace":
+			if operation.Path == "" {
+				// Sans path, la valeur est un objet d'attributs (Okta : {"active": false})
+				values, ok := operation.Value.(map[string]any)
+				if !ok {
+					return changes, fmt.Errorf("%w : objet attendu sans path", ErrInvalidSCIMPatch)
+				}
+				for attr, value := range values {
+					if err := changes.set(attr, value); err != nil {
+						return changes, err
+					}
+				}
+				continue
+			}
+			if err := changes.set(operation.Path, operation.Value); err != nil {
+				return changes, err
+			}
+		case "remove":
+			if err := changes.remove(operation.Path); err != nil {
+				return changes, err
+			}
+		default:
+			return changes, fmt.Errorf("%w : op %q inconnue", ErrInvalidSCIMPatch, operation.Op)
+		}
+	}
+	return changes, nil
+}
+
+func (c *scimUserChanges) set(path string, value any) error {
+	switch attr := normalizeSCIMAttribute(path); attr {
+	case "username":
+		text, err := scimPatchString(attr, value)
+		if err != nil {
+			return err
+		}
+		c.UserName = &text
+	case "displayname", "name.formatted":
+		text, err := scimPatchString(attr, value)
+		if err != nil {
+			return err
+		}
+		c.DisplayName = &text
+	case "externalid":
+		text, err := scimPatchString(attr, value)
+		if err != nil {
+			return err
+		}
+		c.ExternalID = &text
+	case "active":
+		active, err := scimPatchBool(value)
+		if err != nil {
+			return err
+		}
+		c.Active = &active
+	case "name":
+		// displayName, s'il est aussi fourni, reste prioritaire quel que soit l'ordre
+		name, ok := value.(map[string]any)
+		if !ok || c.DisplayName != nil {
+			return nil
+		}
+		formatted, _ := name["formatted"].(string)
+		if formatted == "" {
+			given, _ := name["givenName"].(string)
+			family, _ := name["familyName"].(string)
+			formatted = strings.TrimSpace(given + " " + family)
+		}
+		if formatted != "" {
+			c.DisplayName = &formatted
+		}
+	}
+	return nil
+}
+
+func (c *scimUserChanges) remove(path string) error {
+	switch attr := normalizeSCIMAttribute(path); attr {
+	case "":
+		return fmt.Errorf("%w : path obligatoire pour remove", ErrInvalidSCIMPatch)
+	case "externalid":
+		empty := ""
+		c.ExternalID = &empty
+	case "username", "displayname", "name.formatted", "active":
+		return fmt.Errorf("%w : %s ne peut pas être retiré", ErrInvalidSCIMPatch, attr)
+	}
+	return nil
+}
+
+func scimPatchString(attr string, value any) (string, error) {
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w : %s attend une chaîne", ErrInvalidSCIMPatch, attr)
+	}
+	return text, nil
+}
+
+// scimPatchBool Entra ID envoie les booléens sous forme de chaîne ("False")
+func scimPatchBool(value any) (bool, error) {
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		if parsed, err := strconv.ParseBool(strings.ToLower(value)); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, fmt.Errorf("%w : active attend un booléen", ErrInvalidSCIMPatch)
+}
+
+// =============================================================================
+// DELETE SCIM USER USE CASE (déprovisionnement)
+// =============================================================================
+
+type DeleteSCIMUserUseCase struct {
+	users *scimUsers
+}
+
+func NewDeleteSCIMUserUseCase(
+	userRepo repositories.UserRepository,
+	linkRepo repositories.ExternalUserLinkRepository,
+	publisher EventPublisher,
+	clock Clock,
+) *DeleteSCIMUserUseCase {
+	return &DeleteSCIMUserUseCase{users: newSCIMUsers(userRepo, linkRepo, publisher, clock)}
+}
+
+func (uc *DeleteSCIMUserUseCase) Execute(ctx context.Context, id int) error {
+	if _, err := uc.users.load(ctx, id); err != nil {
+		return err
+	}
+	externalID, err := uc.users.externalID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.users.userRepo.DeleteById(ctx, id); err != nil {
+		return newError("erreur lors de la suppression", err)
+	}
+	if externalID != "" {
+		if err := uc.users.linkRepo.Delete(ctx, scimLinkSource, externalID); err != nil {
+			return newError("erreur lors de la suppression du lien SCIM", err)
+		}
+	}
+
+	uc.users.publisher.Publish(ctx, events.UserDeleted{UserID: id, Deleted: uc.users.clock.Now()})
+	return nil
+}
+
+// =============================================================================
+// LIST SCIM USERS USE CASE (filtre et pagination)
+// =============================================================================
+
+type ListSCIMUsersUseCase struct {
+	users *scimUsers
+}
+
+func NewListSCIMUsersUseCase(userRepo repositories.UserRepository, linkRepo repositories.ExternalUserLinkRepository) *ListSCIMUsersUseCase {
+	return &ListSCIMUsersUseCase{users: newSCIMUsers(userRepo, linkRepo, nil, nil)}
+}
+
+// ListSCIMUsersRequest StartIndex commence à 1 ; Count nil = taille par défaut,
+// 0 = totalResults seul (RFC 7644 §3.4.2.4)
+type ListSCIMUsersRequest struct {
+	Filter     string
+	StartIndex int
+	Count      *int
+}
+
+type ListSCIMUsersResponse struct {
+	TotalResults int
+	StartIndex   int
+	Resources    []*SCIMUserResponse
+}
+
+func (req ListSCIMUsersRequest) Validate() error {
+	if req.Filter == "" {
+		return nil
+	}
+	_, err := ParseSCIMFilter(req.Filter)
+	return err
+}
+
+func (req ListSCIMUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"filter": req.Filter, "start_index": req.StartIndex}
+}
+
+func (uc *ListSCIMUsersUseCase) Execute(ctx context.Context, req ListSCIMUsersRequest) (*ListSCIMUsersResponse, error) {
+	var filter *SCIMFilter
+	if req.Filter != "" {
+		var err error
+		if filter, err = ParseSCIMFilter(req.Filter); err != nil {
+			return nil, err
+		}
+	}
+	startIndex, count := max(req.StartIndex, 1), scimDefaultResults
+	if req.Count != nil {
+		count = min(max(*req.Count, 0), SCIMMaxResults)
+	}
+
+	response := &ListSCIMUsersResponse{StartIndex: startIndex, Resources: make([]*SCIMUserResponse, 0, count)}
+	collect := func(user *entities.User) error {
+		externalID := ""
+		if filter != nil && filter.usesExternalID {
+			var err error
+			if externalID, err = uc.users.externalID(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+		if !filter.matches(scimUserRecord{user: user, externalID: externalID}) {
+			return nil
+		}
+		response.TotalResults++
+		if response.TotalResults < startIndex || len(response.Resources) >= count {
+			return nil
+		}
+		resource, err := uc.users.response(ctx, user)
+		if err != nil {
+			return err
+		}
+		response.Resources = append(response.Resources, resource)
+		return nil
+	}
+
+	// Recherche d'un compte précis (la requête des IdP avant chaque création) : accès direct
+	if candidates, ok, err := uc.directCandidates(ctx, filter); ok {
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range candidates {
+			if err := collect(user); err != nil {
+				return nil, err
+			}
+		}
+		return response, nil
+	}
+
+	// Filtre évalué sur chaque compte : parcours complet du dépôt
+	if err := uc.users.userRepo.Each(ctx, repositories.UserRepositoryFilters{}, collect); err != nil {
+		return nil, eachError("erreur lors de la lecture des utilisateurs", err)
+	}
+	return response, nil
+}
+
+// directCandidates ok = false quand le filtre impose un parcours complet
+func (uc *ListSCIMUsersUseCase) directCandidates(ctx context.Context, filter *SCIMFilter) ([]*entities.User, bool, error) {
+	var (
+		user *entities.User
+		err  error
+	)
+	if userName, ok := filter.equality("username"); ok {
+		user, err = uc.users.userRepo.GetByEmail(ctx, strings.ToLower(userName))
+	} else if externalID, ok := filter.equality("externalid"); ok {
+		var link *repositories.ExternalUserLink
+		if link, err = uc.users.linkRepo.GetByExternalID(ctx, scimLinkSource, externalID); err == nil {
+			user, err = uc.users.userRepo.GetById(ctx, link.UserID)
+		} else if errors.Is(err, repositories.ErrExternalUserLinkNotFound) {
+			return nil, true, nil
+		}
+	} else {
+		return nil, false, nil
+	}
+
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, true, newError("erreur lors de la recherche de l'utilisateur", err)
+	}
+	return []*entities.User{user}, true, nil
+}
@@ -0,0 +1,116 @@
+// internal/domain/usecases/search_indexer.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+)
+
+// =============================================================================
+// SEARCH INDEXER : recopie utilisateurs et événements dans le moteur de recherche
+// =============================================================================
+
+// SearchIndexer est abonné au bus : chaque événement est recopié dans l'index des événements
+// et, s'il concerne un utilisateur, le document de cet utilisateur est réindexé depuis le
+// stockage (les événements ne portent pas l'utilisateur complet)
+// L'indexation passe par le TaskRunner : un moteur lent ou indisponible ne ralentit pas les écritures.
+// Les erreurs sont journalisées ; le document à jour est réécrit au prochain événement de l'utilisateur
+type SearchIndexer struct {
+	userRepo repositories.UserRepository
+	index    repositories.SearchIndexWriter
+	tasks    TaskRunner
+}
+
+func NewSearchIndexer(userRepo repositories.UserRepository, index repositories.SearchIndexWriter, tasks TaskRunner) *SearchIndexer {
+	return &SearchIndexer{userRepo: userRepo, index: index, tasks: tasks}
+}
+
+func (i *SearchIndexer) Handle(ctx context.Context, event events.Event) error {
+	// Internes au flux event-sourcé : jamais recopiés hors du stockage
+	if isInternalEvent(event.EventName()) {
+		return nil
+	}
+
+	userID := eventUserID(event)
+	indexed := repositories.IndexedEvent{Name: event.EventName(), UserID: userID, OccurredAt: event.OccurredAt()}
+	return i.tasks.Go(ctx, "search_index", func(ctx context.Context) error {
+		if err := i.index.IndexEvent(ctx, indexed); err != nil {
+			return err
+		}
+		if userID == 0 {
+			return nil
+		}
+		if _, deleted := event.(events.UserDeleted); deleted {
+			return i.index.DeleteUser(ctx, userID)
+		}
+		return i.reindexUser(ctx, userID)
+	})
+}
+
+// Reindex recopie tous les utilisateurs du stockage (index vide, rattrapage après une panne)
+func (i *SearchIndexer) Reindex(ctx context.Context) (int, error) {
+	indexed := 0
+	err := i.userRepo.Each(ctx, repositories.UserRepositoryFilters{}, func(user *entities.User) error {
+		if err := i.index.IndexUser(ctx, user); err != nil {
+			return err
+		}
+		indexed++
+		return nil
+	})
+	return indexed, err
+}
+
+func (i *SearchIndexer) reindexUser(ctx context.Context, userID int) error {
+	user, err := i.userRepo.GetById(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		// Supprimé entre-temps : UserDeleted retire le document
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return i.index.IndexUser(ctx, user)
+}
+
+// eventUserID utilisateur concerné par un événement publié (0 : aucun)
+func eventUserID(event events.Event) int {
+	switch e := event.(type) {
+	case events.UserCreated:
+		return e.UserID
+	case events.UserProfileUpdated:
+		return e.UserID
+	case events.UserDeleted:
+		return e.UserID
+	case events.DigestPreferenceChanged:
+		return e.UserID
+	case events.UserPhoneChanged:
+		return e.UserID
+	case events.UserStatusChanged:
+		return e.UserID
+	case events.UserHandleChanged:
+		return e.UserID
+	case events.UserLoggedIn:
+		return e.UserID
+	case events.UserFlaggedForCleanup:
+		return e.UserID
+	case events.TermsAccepted:
+		return e.UserID
+	case events.UserAttributesChanged:
+		return e.UserID
+	case events.UserImpersonated:
+		return e.UserID
+	case events.UserEmailUndeliverable:
+		return e.UserID
+	case events.UserLocaleChanged:
+		return e.UserID
+	case events.NewDeviceLogin:
+		return e.UserID
+	case events.AnalyticsConsentChanged:
+		return e.UserID
+	default:
+		return 0
+	}
+}
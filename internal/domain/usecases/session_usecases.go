@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// PORTS : APPAREIL ET LOCALISATION DU CLIENT
+// =============================================================================
+
+// UserAgentParser déduit l'appareil d'un en-tête User-Agent (services.UserAgentParser)
+type UserAgentParser interface {
+	Parse(userAgent string) entities.DeviceInfo
+}
+
+// GeoResolver localise une adresse IP ; nil, nil si elle est inconnue (adresse privée, hors base)
+type GeoResolver interface {
+	Resolve(ctx context.Context, ip string) (*entities.GeoLocation, error)
+}
+
+// =============================================================================
+// SESSION RECORDER : historique des connexions, alerte sur nouvel appareil
+// =============================================================================
+
+// SessionRecorder enregistre chaque connexion avec l'appareil et la localisation du client
+// (ClientFromContext) et publie NewDeviceLogin quand l'appareil n'a jamais été vu sur le compte
+type SessionRecorder struct {
+	sessionRepo repositories.SessionRepository
+	agents      UserAgentParser
+	geo         GeoResolver
+	publisher   EventPublisher
+	tokens      TokenGenerator
+	logger      Logger
+}
+
+// NewSessionRecorder geo nil = localisation désactivée
+func NewSessionRecorder(
+	sessionRepo repositories.SessionRepository,
+	agents UserAgentParser,
+	geo GeoResolver,
+	publisher EventPublisher,
+	tokens TokenGenerator,
+	logger Logger,
+) *SessionRecorder {
+	return &SessionRecorder{
+		sessionRepo: sessionRepo,
+		agents:      agents,
+		geo:         geo,
+		publisher:   publisher,
+		tokens:      tokens,
+		logger:      logger,
+	}
+}
+
+// LoginAttempt connexion en cours : client, appareil, localisation et sessions précédentes
+// de l'utilisateur (les plus récentes d'abord), évaluée avant l'émission du jeton
+type LoginAttempt struct {
+	UserID    int
+	IP        string
+	UserAgent string
+	Device    entities.DeviceInfo
+	Location  *entities.GeoLocation
+	At        time.Time
+	Previous  []entities.Session
+}
+
+// Prepare décrit la connexion de userID à now depuis le client de la requête (ClientFromContext)
+func (r *SessionRecorder) Prepare(ctx context.Context, userID int, now time.Time) (*LoginAttempt, error) {
+	client := ClientFromContext(ctx)
+	attempt := &LoginAttempt{
+		UserID:    userID,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Device:    r.agents.Parse(client.UserAgent),
+		At:        now,
+	}
+	if r.geo != nil && client.IP != "" {
+		// Localisation facultative : une base indisponible n'empêche pas la connexion
+		location, err := r.geo.Resolve(ctx, client.IP)
+		if err != nil {
+			r.logger.Error("Failed to resolve client location", err, map[string]interface{}{"user_id": userID})
+		}
+		attempt.Location = location
+	}
+
+	previous, err := r.sessionRepo.ListByUser(ctx, userID, 0)
+	if err != nil {
+		return attempt, err
+	}
+	attempt.Previous = previous
+	return attempt, nil
+}
+
+// Record enregistre la session de la connexion acceptée ; l'appelant journalise l'erreur
+// sans refuser la connexion
+func (r *SessionRecorder) Record(ctx context.Context, attempt *LoginAttempt, expiresAt time.Time) error {
+	id, err := r.tokens.HexToken(16)
+	if err != nil {
+		return err
+	}
+	session := &entities.Session{
+		ID:        id,
+		UserID:    attempt.UserID,
+		Device:    attempt.Device,
+		UserAgent: attempt.UserAgent,
+		IP:        attempt.IP,
+		Location:  attempt.Location,
+		Created:   attempt.At,
+		ExpiresAt: expiresAt,
+	}
+	session.NewDevice = len(attempt.Previous) > 0
+	for _, known := range attempt.Previous {
+		if known.Device.Fingerprint() == session.Device.Fingerprint() {
+			session.NewDevice = false
+			break
+		}
+	}
+	if err := r.sessionRepo.Save(ctx, session); err != nil {
+		return err
+	}
+
+	if session.NewDevice {
+		event := events.NewDeviceLogin{
+			UserID:    attempt.UserID,
+			SessionID: session.ID,
+			Device:    session.Device.Type,
+			OS:        session.Device.OS,
+			Browser:   session.Device.Browser,
+			At:        attempt.At,
+		}
+		if session.Location != nil {
+			event.Country, event.City = session.Location.Country, session.Location.City
+		}
+		r.publisher.Publish(ctx, event)
+	}
+	return nil
+}
+
+// Handle efface l'historique des sessions d'un utilisateur supprimé
+func (r *SessionRecorder) Handle(ctx context.Context, event events.Event) error {
+	if deleted, ok := event.(events.UserDeleted); ok {
+		return r.sessionRepo.DeleteByUser(ctx, deleted.UserID)
+	}
+	return nil
+}
+
+// =============================================================================
+// LIST SESSIONS USE CASE
+// =============================================================================
+
+// ListSessionsUseCase connexions récentes d'un utilisateur, les plus récentes d'abord
+type ListSessionsUseCase struct {
+	sessionRepo repositories.SessionRepository
+}
+
+func NewListSessionsUseCase(sessionRepo repositories.SessionRepository) *ListSessionsUseCase {
+	return &ListSessionsUseCase{sessionRepo: sessionRepo}
+}
+
+type ListSessionsRequest struct {
+	UserID int `json:"-"`
+	Limit  int `json:"limit"` // défaut : 20
+}
+
+func (req ListSessionsRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if req.Limit < 0 || req.Limit > 100 {
+		return errors.New("limit doit être compris entre 1 et 100")
+	}
+	return nil
+}
+
+func (req ListSessionsRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type ListSessionsResponse struct {
+	Sessions []entities.Session `json:"sessions"`
+}
+
+func (uc *ListSessionsUseCase) Execute(ctx context.Context, req ListSessionsRequest) (*ListSessionsResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	sessions, err := uc.sessionRepo.ListByUser(ctx, req.UserID, req.Limit)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des sessions", err)
+	}
+	return &ListSessionsResponse{Sessions: sessions}, nil
+}
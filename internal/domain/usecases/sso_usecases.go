@@ -0,0 +1,478 @@
+// internal/domain/usecases/sso_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// PORT SAML 2.0 (SSO INITIÉ PAR LE SERVICE PROVIDER)
+// =============================================================================
+
+var (
+	// ErrSSONotConfigured le tenant n'a pas de fournisseur d'identité actif
+	ErrSSONotConfigured = errors.New("SSO non configuré pour ce tenant")
+	// ErrInvalidSAMLResponse réponse de l'IdP refusée (signature, émetteur, audience, validité...)
+	ErrInvalidSAMLResponse = errors.New("réponse SAML invalide")
+)
+
+// ssoRequestTTL délai laissé à l'utilisateur pour s'authentifier auprès de l'IdP
+const ssoRequestTTL = 10 * time.Minute
+
+// SAMLAssertion assertion dont la signature et les conditions ont été vérifiées
+type SAMLAssertion struct {
+	InResponseTo string
+	NameID       string
+	NameIDFormat string
+	// Attributes valeurs par nom d'attribut SAML
+	Attributes map[string][]string
+}
+
+// SAMLServiceProvider protocole SAML côté SP : le XML et les signatures restent dans l'adaptateur
+type SAMLServiceProvider interface {
+	// Metadata métadonnées SP du tenant (entityID, ACS) à fournir à l'IdP
+	Metadata(tenantID string) ([]byte, error)
+	// ValidateCertificate vérifie le certificat PEM de signature d'un IdP
+	ValidateCertificate(certificate string) error
+	// AuthnRequestURL URL de l'IdP portant la demande requestID (binding HTTP-Redirect)
+	AuthnRequestURL(idp *repositories.IdentityProvider, requestID, relayState string, now time.Time) (string, error)
+	// ParseResponse vérifie la réponse (SAMLResponse du binding HTTP-POST) et retourne son assertion
+	// Toute réponse refusée retourne une erreur enveloppant ErrInvalidSAMLResponse
+	ParseResponse(idp *repositories.IdentityProvider, samlResponse string, now time.Time) (*SAMLAssertion, error)
+}
+
+// IdentityProviderResponse configuration SSO d'un tenant (le certificat n'est pas renvoyé)
+type IdentityProviderResponse struct {
+	TenantID string                            `json:"tenant_id"`
+	EntityID string                            `json:"entity_id"`
+	SSOURL   string                            `json:"sso_url"`
+	Mapping  repositories.SAMLAttributeMapping `json:"mapping"`
+	Enabled  bool                              `json:"enabled"`
+	Updated  time.Time                         `json:"updated"`
+}
+
+func newIdentityProviderResponse(idp *repositories.IdentityProvider) *IdentityProviderResponse {
+	return &IdentityProviderResponse{
+		TenantID: idp.TenantID,
+		EntityID: idp.EntityID,
+		SSOURL:   idp.SSOURL,
+		Mapping:  idp.Mapping,
+		Enabled:  idp.Enabled,
+		Updated:  idp.Updated,
+	}
+}
+
+// =============================================================================
+// CONFIGURE IDENTITY PROVIDER USE CASE
+// =============================================================================
+
+// ConfigureIdentityProviderUseCase réservé au rôle d'administration (AccessAdmin) : l'IdP d'un
+// tenant ouvre des sessions sur les comptes qu'il provisionne ou que leurs titulaires lui rattachent
+type ConfigureIdentityProviderUseCase struct {
+	idpRepo repositories.IdentityProviderRepository
+	sp      SAMLServiceProvider
+	schemas AttributeSchemaRegistry
+	clock   Clock
+}
+
+func NewConfigureIdentityProviderUseCase(
+	idpRepo repositories.IdentityProviderRepository,
+	sp SAMLServiceProvider,
+	schemas AttributeSchemaRegistry,
+	clock Clock,
+) *ConfigureIdentityProviderUseCase {
+	return &ConfigureIdentityProviderUseCase{
+		idpRepo: idpRepo,
+		sp:      sp,
+		schemas: schemas,
+		clock:   clock,
+	}
+}
+
+type ConfigureIdentityProviderRequest struct {
+	TenantID    string                            `json:"-"`
+	EntityID    string                            `json:"entity_id"`
+	SSOURL      string                            `json:"sso_url"`
+	Certificate string                            `json:"certificate"`
+	Mapping     repositories.SAMLAttributeMapping `json:"mapping"`
+	Enabled     bool                              `json:"enabled"`
+}
+
+func (req ConfigureIdentityProviderRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (req ConfigureIdentityProviderRequest) Validate() error {
+	if strings.TrimSpace(req.TenantID) == "" {
+		return errors.New("tenant obligatoire")
+	}
+	if strings.TrimSpace(req.EntityID) == "" {
+		return errors.New("entity_id obligatoire")
+	}
+	parsed, err := url.Parse(req.SSOURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return errors.New("sso_url doit être une URL absolue")
+	}
+	if strings.TrimSpace(req.Certificate) == "" {
+		return errors.New("certificate obligatoire")
+	}
+	return nil
+}
+
+func (req ConfigureIdentityProviderRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID, "entity_id": req.EntityID, "enabled": req.Enabled}
+}
+
+func (uc *ConfigureIdentityProviderUseCase) Execute(ctx context.Context, req ConfigureIdentityProviderRequest) (*IdentityProviderResponse, error) {
+	if err := uc.sp.ValidateCertificate(req.Certificate); err != nil {
+		return nil, err
+	}
+
+	// Les attributs personnalisés alimentés par l'IdP doivent exister dans le schéma du tenant
+	if len(req.Mapping.Attributes) > 0 {
+		schema, err := uc.schemas.SchemaFor(ctx, req.TenantID)
+		if err != nil {
+			return nil, newError("erreur lors du chargement du schéma d'attributs", err)
+		}
+		for _, key := range slices.Sorted(maps.Keys(req.Mapping.Attributes)) {
+			if _, ok := schema.TypeOf(key); !ok {
+				return nil, errors.New("mapping : attribut non déclaré dans le schéma : " + key)
+			}
+		}
+	}
+
+	idp := &repositories.IdentityProvider{
+		TenantID:    req.TenantID,
+		EntityID:    strings.TrimSpace(req.EntityID),
+		SSOURL:      req.SSOURL,
+		Certificate: req.Certificate,
+		Mapping:     req.Mapping,
+		Enabled:     req.Enabled,
+		Updated:     uc.clock.Now(),
+	}
+	if err := uc.idpRepo.Save(ctx, idp); err != nil {
+		return nil, newError("erreur lors de l'enregistrement du fournisseur d'identité", err)
+	}
+	return newIdentityProviderResponse(idp), nil
+}
+
+// =============================================================================
+// GET IDENTITY PROVIDER USE CASE
+// =============================================================================
+
+type GetIdentityProviderUseCase struct {
+	idpRepo repositories.IdentityProviderRepository
+}
+
+func NewGetIdentityProviderUseCase(idpRepo repositories.IdentityProviderRepository) *GetIdentityProviderUseCase {
+	return &GetIdentityProviderUseCase{idpRepo: idpRepo}
+}
+
+func (uc *GetIdentityProviderUseCase) Execute(ctx context.Context, tenantID string) (*IdentityProviderResponse, error) {
+	idp, err := uc.idpRepo.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return newIdentityProviderResponse(idp), nil
+}
+
+// =============================================================================
+// SERVICE PROVIDER METADATA USE CASE
+// =============================================================================
+
+// GetSAMLMetadataUseCase les métadonnées ne dépendent pas de l'IdP : elles sont fournies
+// à l'administrateur de l'IdP avant même la configuration du tenant
+type GetSAMLMetadataUseCase struct {
+	sp SAMLServiceProvider
+}
+
+func NewGetSAMLMetadataUseCase(sp SAMLServiceProvider) *GetSAMLMetadataUseCase {
+	return &GetSAMLMetadataUseCase{sp: sp}
+}
+
+func (uc *GetSAMLMetadataUseCase) Execute(ctx context.Context, tenantID string) ([]byte, error) {
+	return uc.sp.Metadata(tenantID)
+}
+
+// =============================================================================
+// START SSO LOGIN USE CASE
+// =============================================================================
+
+// StartSSOLoginUseCase lancée depuis une session locale, la demande rattachera l'identité de l'IdP
+// à ce compte (seul moyen de relier un compte existant : l'IdP ne s'y rattache pas par email)
+type StartSSOLoginUseCase struct {
+	idpRepo        repositories.IdentityProviderRepository
+	requestRepo    repositories.SSORequestRepository
+	sp             SAMLServiceProvider
+	tokenGenerator TokenGenerator
+	clock          Clock
+}
+
+func NewStartSSOLoginUseCase(
+	idpRepo repositories.IdentityProviderRepository,
+	requestRepo repositories.SSORequestRepository,
+	sp SAMLServiceProvider,
+	tokenGenerator TokenGenerator,
+	clock Clock,
+) *StartSSOLoginUseCase {
+	return &StartSSOLoginUseCase{
+		idpRepo:        idpRepo,
+		requestRepo:    requestRepo,
+		sp:             sp,
+		tokenGenerator: tokenGenerator,
+		clock:          clock,
+	}
+}
+
+type StartSSOLoginRequest struct {
+	TenantID string `json:"tenant_id"`
+	// RelayState valeur opaque renvoyée telle quelle par l'IdP (page de retour du front)
+	RelayState string `json:"relay_state,omitempty"`
+}
+
+type StartSSOLoginResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+func (req StartSSOLoginRequest) Validate() error {
+	// La spécification limite RelayState à 80 octets (SAML Bindings §3.4.3)
+	if len(req.RelayState) > 80 {
+		return errors.New("relay_state ne doit pas dépasser 80 octets")
+	}
+	return nil
+}
+
+func (uc *StartSSOLoginUseCase) Execute(ctx context.Context, req StartSSOLoginRequest) (*StartSSOLoginResponse, error) {
+	idp, err := enabledIdentityProvider(ctx, uc.idpRepo, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	requestID, err := newSSORequestID(uc.tokenGenerator)
+	if err != nil {
+		return nil, newError("erreur lors de la génération de la demande", err)
+	}
+	now := uc.clock.Now()
+	redirectURL, err := uc.sp.AuthnRequestURL(idp, requestID, req.RelayState, now)
+	if err != nil {
+		return nil, newError("erreur lors de la construction de la demande", err)
+	}
+
+	pending := repositories.SSORequest{ID: requestID, TenantID: idp.TenantID, Expires: now.Add(ssoRequestTTL)}
+	// Une session d'impersonation ne rattache jamais d'identité au compte visité
+	if actor, ok := ActorFromContext(ctx); ok && actor.UserID > 0 && actor.Impersonation == nil {
+		pending.UserID = actor.UserID
+	}
+	if err := uc.requestRepo.Save(ctx, pending); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de la demande", err)
+	}
+	return &StartSSOLoginResponse{RedirectURL: redirectURL}, nil
+}
+
+// =============================================================================
+// CONSUME SSO RESPONSE USE CASE (ASSERTION CONSUMER SERVICE)
+// =============================================================================
+
+// ConsumeSSOResponseUseCase ouvre une session à partir de la réponse de l'IdP : le compte local est
+// retrouvé par NameID ; à la première connexion, il est rattaché au compte qui a lancé la demande,
+// sinon créé (ErrExternalAccountNotLinked si un compte local non rattaché utilise déjà l'email)
+type ConsumeSSOResponseUseCase struct {
+	idpRepo     repositories.IdentityProviderRepository
+	requestRepo repositories.SSORequestRepository
+	sp          SAMLServiceProvider
+	accounts    *externalAccounts
+	schemas     AttributeSchemaRegistry
+	sessions    *SessionOpener
+	clock       Clock
+}
+
+func NewConsumeSSOResponseUseCase(
+	idpRepo repositories.IdentityProviderRepository,
+	requestRepo repositories.SSORequestRepository,
+	sp SAMLServiceProvider,
+	userRepo repositories.UserRepository,
+	linkRepo repositories.ExternalUserLinkRepository,
+	hasher PasswordHasher,
+	schemas AttributeSchemaRegistry,
+	publisher EventPublisher,
+	logger Logger,
+	sessions *SessionOpener,
+	clock Clock,
+	tokenGenerator TokenGenerator,
+) *ConsumeSSOResponseUseCase {
+	return &ConsumeSSOResponseUseCase{
+		idpRepo:     idpRepo,
+		requestRepo: requestRepo,
+		sp:          sp,
+		accounts: &externalAccounts{
+			userRepo:       userRepo,
+			linkRepo:       linkRepo,
+			hasher:         hasher,
+			publisher:      publisher,
+			logger:         logger,
+			clock:          clock,
+			tokenGenerator: tokenGenerator,
+		},
+		schemas:  schemas,
+		sessions: sessions,
+		clock:    clock,
+	}
+}
+
+type ConsumeSSOResponseRequest struct {
+	TenantID     string `json:"tenant_id"`
+	SAMLResponse string `json:"-"`
+	RelayState   string `json:"relay_state,omitempty"`
+}
+
+func (req ConsumeSSOResponseRequest) Validate() error {
+	if req.SAMLResponse == "" {
+		return ErrInvalidSAMLResponse
+	}
+	return nil
+}
+
+func (req ConsumeSSOResponseRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+type ConsumeSSOResponseResponse struct {
+	*LoginResponse
+	RelayState string `json:"relay_state,omitempty"`
+}
+
+func (uc *ConsumeSSOResponseUseCase) Execute(ctx context.Context, req ConsumeSSOResponseRequest) (*ConsumeSSOResponseResponse, error) {
+	idp, err := enabledIdentityProvider(ctx, uc.idpRepo, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	assertion, err := uc.sp.ParseResponse(idp, req.SAMLResponse, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Seules les réponses à nos propres demandes sont acceptées (pas d'SSO initié par l'IdP),
+	// et chacune une seule fois : la demande est consommée
+	if assertion.InResponseTo == "" {
+		return nil, fmt.Errorf("%w : réponse non sollicitée", ErrInvalidSAMLResponse)
+	}
+	pending, err := uc.requestRepo.Consume(ctx, assertion.InResponseTo, now)
+	if errors.Is(err, repositories.ErrSSORequestNotFound) || (err == nil && pending.TenantID != idp.TenantID) {
+		return nil, fmt.Errorf("%w : demande inconnue, expirée ou déjà utilisée", ErrInvalidSAMLResponse)
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de la demande", err)
+	}
+
+	identity := mapSAMLIdentity(idp, assertion)
+	identity.LinkUserID = pending.UserID
+	user, err := uc.accounts.resolve(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.applyAttributes(ctx, idp, assertion, user); err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessions.Open(ctx, &VerifiedCredentials{User: user, TenantID: idp.TenantID})
+	if err != nil {
+		return nil, err
+	}
+	return &ConsumeSSOResponseResponse{LoginResponse: session, RelayState: req.RelayState}, nil
+}
+
+// applyAttributes copie les attributs personnalisés fournis par l'IdP ; une valeur refusée par
+// le schéma est ignorée (journalisée) pour ne pas bloquer la connexion
+func (uc *ConsumeSSOResponseUseCase) applyAttributes(ctx context.Context, idp *repositories.IdentityProvider, assertion *SAMLAssertion, user *entities.User) error {
+	if len(idp.Mapping.Attributes) == 0 {
+		return nil
+	}
+	schema, err := uc.schemas.SchemaFor(ctx, idp.TenantID)
+	if err != nil {
+		return newError("erreur lors du chargement du schéma d'attributs", err)
+	}
+
+	patch := make(map[string]any, len(idp.Mapping.Attributes))
+	for key, samlName := range idp.Mapping.Attributes {
+		values := assertion.Attributes[samlName]
+		if len(values) == 0 {
+			continue
+		}
+		value, err := schema.ParseValue(key, values[0])
+		if err != nil {
+			uc.accounts.logger.Warn("SAML attribute ignored", map[string]interface{}{"user_id": user.ID, "attribute": key, "error": err.Error()})
+			continue
+		}
+		patch[key] = value
+	}
+
+	changes, err := user.PatchAttributes(patch, schema, uc.clock.Now())
+	if err != nil {
+		return err
+	}
+	return saveUserChanges(ctx, uc.accounts.userRepo, uc.accounts.publisher, user, nil, nil, changes)
+}
+
+// mapSAMLIdentity traduit l'assertion selon le mapping du tenant
+func mapSAMLIdentity(idp *repositories.IdentityProvider, assertion *SAMLAssertion) ExternalIdentity {
+	first := func(name string) string {
+		if values := assertion.Attributes[name]; name != "" && len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+
+	email := first(idp.Mapping.Email)
+	if idp.Mapping.Email == "" {
+		email = assertion.NameID
+	}
+	name := first(idp.Mapping.Name)
+	if name == "" {
+		name = strings.TrimSpace(first(idp.Mapping.FirstName) + " " + first(idp.Mapping.LastName))
+	}
+	if name == "" {
+		// Nom obligatoire côté entité : à défaut, la partie locale de l'email
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	return ExternalIdentity{
+		Source:     "saml:" + idp.TenantID,
+		ExternalID: assertion.NameID,
+		Email:      email,
+		Name:       name,
+	}
+}
+
+func enabledIdentityProvider(ctx context.Context, idpRepo repositories.IdentityProviderRepository, tenantID string) (*repositories.IdentityProvider, error) {
+	idp, err := idpRepo.GetByTenant(ctx, tenantID)
+	if errors.Is(err, repositories.ErrIdentityProviderNotFound) {
+		return nil, ErrSSONotConfigured
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du fournisseur d'identité", err)
+	}
+	if !idp.Enabled {
+		return nil, ErrSSONotConfigured
+	}
+	return idp, nil
+}
+
+// newSSORequestID identifiant xs:ID (ne commence pas par un chiffre) imprévisible
+func newSSORequestID(tokenGenerator TokenGenerator) (string, error) {
+	id, err := tokenGenerator.HexToken(20)
+	if err != nil {
+		return "", err
+	}
+	return "_" + id, nil
+}
@@ -0,0 +1,190 @@
+// internal/domain/usecases/terms_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// CONDITIONS D'UTILISATION : VERSION EN VIGUEUR
+// =============================================================================
+
+var (
+	// ErrTermsNotAccepted la dernière version des conditions n'a pas été acceptée
+	ErrTermsNotAccepted = errors.New("conditions d'utilisation à accepter")
+	// ErrTermsVersionMismatch la version acceptée n'est pas celle en vigueur (page affichée obsolète)
+	ErrTermsVersionMismatch = errors.New("cette version des conditions d'utilisation n'est plus en vigueur")
+)
+
+// TermsChecker compare la dernière acceptation d'un utilisateur à la version en vigueur
+// Sans version configurée, aucune acceptation n'est exigée
+type TermsChecker struct {
+	termsRepo      repositories.TermsAcceptanceRepository
+	currentVersion string
+}
+
+func NewTermsChecker(termsRepo repositories.TermsAcceptanceRepository, currentVersion string) *TermsChecker {
+	return &TermsChecker{
+		termsRepo:      termsRepo,
+		currentVersion: currentVersion,
+	}
+}
+
+// CurrentVersion version en vigueur ("" : conditions non exigées)
+func (c *TermsChecker) CurrentVersion() string {
+	return c.currentVersion
+}
+
+// Latest dernière acceptation de l'utilisateur (nil s'il n'a jamais rien accepté) et conformité
+func (c *TermsChecker) Latest(ctx context.Context, userID int) (*entities.TermsAcceptance, bool, error) {
+	latest, err := c.termsRepo.GetLatest(ctx, userID)
+	switch {
+	case errors.Is(err, repositories.ErrTermsAcceptanceNotFound):
+		return nil, c.currentVersion == "", nil
+	case err != nil:
+		return nil, false, err
+	}
+	return latest, c.currentVersion == "" || latest.Version == c.currentVersion, nil
+}
+
+// =============================================================================
+// GET TERMS STATUS USE CASE
+// =============================================================================
+
+type GetTermsStatusUseCase struct {
+	checker *TermsChecker
+}
+
+func NewGetTermsStatusUseCase(checker *TermsChecker) *GetTermsStatusUseCase {
+	return &GetTermsStatusUseCase{checker: checker}
+}
+
+type TermsStatusResponse struct {
+	UserID          int        `json:"user_id"`
+	CurrentVersion  string     `json:"current_version,omitempty"`
+	AcceptedVersion string     `json:"accepted_version,omitempty"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+	UpToDate        bool       `json:"up_to_date"`
+}
+
+func (uc *GetTermsStatusUseCase) Execute(ctx context.Context, userID int) (*TermsStatusResponse, error) {
+	latest, upToDate, err := uc.checker.Latest(ctx, userID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des acceptations", err)
+	}
+
+	response := &TermsStatusResponse{
+		UserID:         userID,
+		CurrentVersion: uc.checker.CurrentVersion(),
+		UpToDate:       upToDate,
+	}
+	if latest != nil {
+		response.AcceptedVersion = latest.Version
+		response.AcceptedAt = &latest.AcceptedAt
+	}
+	return response, nil
+}
+
+// =============================================================================
+// ACCEPT TERMS USE CASE
+// =============================================================================
+
+type AcceptTermsUseCase struct {
+	userRepo  repositories.UserRepository
+	termsRepo repositories.TermsAcceptanceRepository
+	checker   *TermsChecker
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewAcceptTermsUseCase(
+	userRepo repositories.UserRepository,
+	termsRepo repositories.TermsAcceptanceRepository,
+	checker *TermsChecker,
+	publisher EventPublisher,
+	clock Clock,
+) *AcceptTermsUseCase {
+	return &AcceptTermsUseCase{
+		userRepo:  userRepo,
+		termsRepo: termsRepo,
+		checker:   checker,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+// AcceptTermsRequest Version est celle affichée à l'utilisateur : elle doit être la version en vigueur
+type AcceptTermsRequest struct {
+	UserID  int    `json:"-"`
+	Version string `json:"version"`
+}
+
+func (req AcceptTermsRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+func (req AcceptTermsRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if req.Version == "" {
+		return entities.ErrInvalidTermsVersion
+	}
+	return nil
+}
+
+func (req AcceptTermsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "version": req.Version}
+}
+
+func (uc *AcceptTermsUseCase) Execute(ctx context.Context, req AcceptTermsRequest) (*TermsStatusResponse, error) {
+	if req.Version != uc.checker.CurrentVersion() {
+		return nil, ErrTermsVersionMismatch
+	}
+
+	if _, err := uc.userRepo.GetById(ctx, req.UserID); err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	latest, upToDate, err := uc.checker.Latest(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des acceptations", err)
+	}
+	// Idempotent : accepter de nouveau la même version n'ajoute pas de ligne
+	if upToDate && latest != nil {
+		return &TermsStatusResponse{
+			UserID:          req.UserID,
+			CurrentVersion:  latest.Version,
+			AcceptedVersion: latest.Version,
+			AcceptedAt:      &latest.AcceptedAt,
+			UpToDate:        true,
+		}, nil
+	}
+
+	acceptance, err := entities.NewTermsAcceptance(req.UserID, req.Version, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.termsRepo.Save(ctx, acceptance); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de l'acceptation", err)
+	}
+
+	uc.publisher.Publish(ctx, events.TermsAccepted{
+		UserID:   acceptance.UserID,
+		Version:  acceptance.Version,
+		Accepted: acceptance.AcceptedAt,
+	})
+
+	return &TermsStatusResponse{
+		UserID:          acceptance.UserID,
+		CurrentVersion:  acceptance.Version,
+		AcceptedVersion: acceptance.Version,
+		AcceptedAt:      &acceptance.AcceptedAt,
+		UpToDate:        true,
+	}, nil
+}
@@ -0,0 +1,257 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// TIMELINE RECORDER : chronologie par utilisateur, alimentée par les événements
+// =============================================================================
+
+// TimelineRecorder alimente le modèle de lecture de la chronologie : événements du domaine via
+// le bus (Handle), événements analytics notables via le dépôt d'ingestion (TrackedEvents)
+type TimelineRecorder struct {
+	timelineRepo repositories.TimelineRepository
+	notable      map[string]bool
+	logger       Logger
+}
+
+// NewTimelineRecorder notable noms des événements analytics repris dans la chronologie
+func NewTimelineRecorder(timelineRepo repositories.TimelineRepository, notable []string, logger Logger) *TimelineRecorder {
+	names := make(map[string]bool, len(notable))
+	for _, name := range notable {
+		names[name] = true
+	}
+	return &TimelineRecorder{timelineRepo: timelineRepo, notable: names, logger: logger}
+}
+
+// Handle enregistre les événements du domaine qui concernent un utilisateur ; la suppression
+// du compte efface sa chronologie
+func (r *TimelineRecorder) Handle(ctx context.Context, event events.Event) error {
+	if deleted, ok := event.(events.UserDeleted); ok {
+		return r.timelineRepo.DeleteByUser(ctx, deleted.UserID)
+	}
+	entry := timelineEntry(event)
+	if entry == nil {
+		return nil
+	}
+	return r.timelineRepo.Record(ctx, entry)
+}
+
+// timelineEntry entrée d'un événement du domaine, nil s'il n'a pas sa place dans la chronologie
+// (événements internes du flux event-sourcé : ils doublonnent les événements publics)
+func timelineEntry(event events.Event) *repositories.TimelineEntry {
+	entry := &repositories.TimelineEntry{Event: event.EventName(), At: event.OccurredAt()}
+	switch e := event.(type) {
+	case events.UserCreated:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineSignup
+	case events.UserLoggedIn:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineLogin
+	case events.NewDeviceLogin:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineLogin
+		entry.Details = map[string]string{"device": e.Device, "os": e.OS, "browser": e.Browser, "country": e.Country}
+	case events.UserProfileUpdated:
+		entry.UserID, entry.Category, entry.Fields = e.UserID, repositories.TimelineProfile, e.ChangedFields
+	case events.UserAttributesChanged:
+		entry.UserID, entry.Category, entry.Fields = e.UserID, repositories.TimelineProfile, e.ChangedFields
+	case events.UserPhoneChanged:
+		entry.UserID, entry.Category, entry.Fields = e.UserID, repositories.TimelineProfile, []string{"phone"}
+	case events.UserHandleChanged:
+		entry.UserID, entry.Category, entry.Fields = e.UserID, repositories.TimelineProfile, []string{"handle"}
+	case events.UserLocaleChanged:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineProfile
+		entry.Details = map[string]string{"locale": e.Locale, "time_zone": e.TimeZone}
+	case events.DigestPreferenceChanged:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineProfile
+		entry.Details = map[string]string{"enabled": strconv.FormatBool(e.Enabled)}
+	case events.UserStatusChanged:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineAccount
+		entry.Details = map[string]string{"status": e.Status}
+	case events.TermsAccepted:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineAccount
+		entry.Details = map[string]string{"version": e.Version}
+	case events.AnalyticsConsentChanged:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineAccount
+		entry.Details = map[string]string{"status": e.Status, "source": e.Source}
+	case events.UserImpersonated:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineAccount
+		entry.Details = map[string]string{"admin_id": strconv.Itoa(e.AdminID)}
+	case events.UserEmailUndeliverable:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineAccount
+		entry.Details = map[string]string{"reason": e.Reason}
+	case events.UserFlaggedForCleanup:
+		entry.UserID, entry.Category = e.UserID, repositories.TimelineAccount
+	default:
+		return nil
+	}
+	return entry
+}
+
+// TrackedEvents dépôt d'ingestion qui reporte dans la chronologie les événements analytics
+// notables d'un utilisateur identifié, une fois le lot accepté par next. Les propriétés ne sont
+// pas reprises : envoyées par le client, elles peuvent contenir des données personnelles
+func (r *TimelineRecorder) TrackedEvents(next repositories.EventRepository) repositories.EventRepository {
+	if len(r.notable) == 0 {
+		return next
+	}
+	return &timelineEventRepository{EventRepository: next, recorder: r}
+}
+
+type timelineEventRepository struct {
+	repositories.EventRepository
+	recorder *TimelineRecorder
+}
+
+// Append une erreur de la chronologie est journalisée sans faire échouer l'ingestion
+func (d *timelineEventRepository) Append(ctx context.Context, batch []*entities.TrackedEvent) error {
+	if err := d.EventRepository.Append(ctx, batch); err != nil {
+		return err
+	}
+	for _, event := range batch {
+		if event.UserID <= 0 || !d.recorder.notable[event.Name] {
+			continue
+		}
+		entry := &repositories.TimelineEntry{
+			UserID:   event.UserID,
+			Category: repositories.TimelineAnalytics,
+			Event:    event.Name,
+			At:       event.OccurredAt,
+		}
+		if err := d.recorder.timelineRepo.Record(ctx, entry); err != nil {
+			d.recorder.logger.Error("Failed to record timeline entry", err, map[string]interface{}{"event": event.Name, "user_id": event.UserID})
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// GET USER TIMELINE USE CASE
+// =============================================================================
+
+// timelineCategories catégories acceptées par le filtre category
+var timelineCategories = []string{
+	repositories.TimelineSignup, repositories.TimelineLogin, repositories.TimelineProfile,
+	repositories.TimelineAccount, repositories.TimelineAnalytics,
+}
+
+// GetUserTimelineUseCase chronologie d'un utilisateur pour le support, les faits les plus
+// récents d'abord, paginée par curseur
+type GetUserTimelineUseCase struct {
+	timelineRepo repositories.TimelineRepository
+}
+
+func NewGetUserTimelineUseCase(timelineRepo repositories.TimelineRepository) *GetUserTimelineUseCase {
+	return &GetUserTimelineUseCase{timelineRepo: timelineRepo}
+}
+
+type GetUserTimelineRequest struct {
+	UserID int `json:"-"`
+	// Categories filtre optionnel (signup, login, profile, account, analytics)
+	Categories []string `json:"categories,omitempty"`
+	// Cursor next_cursor de la page précédente ; vide pour la première page
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit"` // défaut : 50
+}
+
+func (req GetUserTimelineRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	for _, category := range req.Categories {
+		if !slices.Contains(timelineCategories, category) {
+			return fmt.Errorf("catégorie inconnue : %q (%s)", category, strings.Join(timelineCategories, ", "))
+		}
+	}
+	if req.Limit < 0 || req.Limit > 200 {
+		return errors.New("limit doit être compris entre 1 et 200")
+	}
+	if _, err := decodeTimelineCursor(req.Cursor); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (req GetUserTimelineRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "categories": req.Categories, "limit": req.Limit}
+}
+
+func (req GetUserTimelineRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type GetUserTimelineResponse struct {
+	UserID  int                          `json:"user_id"`
+	Entries []repositories.TimelineEntry `json:"entries"`
+	// NextCursor absent sur la dernière page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (uc *GetUserTimelineUseCase) Execute(ctx context.Context, req GetUserTimelineRequest) (*GetUserTimelineResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+	before, _ := decodeTimelineCursor(req.Cursor)
+	// Une entrée de plus : indique s'il reste une page
+	entries, err := uc.timelineRepo.List(ctx, repositories.TimelineFilter{
+		UserID:     req.UserID,
+		Categories: req.Categories,
+		Before:     before,
+		Limit:      req.Limit + 1,
+	})
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de la chronologie", err)
+	}
+
+	response := &GetUserTimelineResponse{UserID: req.UserID, Entries: entries}
+	if len(entries) > req.Limit {
+		response.Entries = entries[:req.Limit]
+		last := response.Entries[req.Limit-1]
+		response.NextCursor = encodeTimelineCursor(repositories.TimelinePosition{At: last.At, ID: last.ID})
+	}
+	if response.Entries == nil {
+		response.Entries = []repositories.TimelineEntry{}
+	}
+	return response, nil
+}
+
+// errTimelineCursor curseur altéré ou issu d'une autre liste
+var errTimelineCursor = errors.New("curseur invalide")
+
+// encodeTimelineCursor "<unix nano>.<id>" de la dernière entrée de la page
+func encodeTimelineCursor(position repositories.TimelinePosition) string {
+	raw := strconv.FormatInt(position.At.UnixNano(), 10) + "." + strconv.FormatInt(position.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTimelineCursor(cursor string) (*repositories.TimelinePosition, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errTimelineCursor
+	}
+	nanos, rawID, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return nil, errTimelineCursor
+	}
+	at, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errTimelineCursor
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id < 1 {
+		return nil, errTimelineCursor
+	}
+	return &repositories.TimelinePosition{At: time.Unix(0, at).UTC(), ID: id}, nil
+}
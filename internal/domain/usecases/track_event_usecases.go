@@ -0,0 +1,405 @@
+// internal/domain/usecases/track_event_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Issues d'un événement analytics reçu, reprises dans la réponse et les compteurs
+const (
+	TrackOutcomeAccepted = "accepted" // enregistré tel quel
+	TrackOutcomeSampled  = "sampled"  // hors limite, retenu dans le réservoir (enregistré en fin de fenêtre)
+	TrackOutcomeDropped  = "dropped"  // hors limite, écarté
+	// TrackOutcomeNoConsent écarté faute de consentement (ANALYTICS_WITHOUT_CONSENT=drop) ;
+	// compteurs seulement, le client reçoit dropped
+	TrackOutcomeNoConsent = "no_consent"
+)
+
+// TrackingMetrics port des compteurs d'ingestion analytics (services.ExpvarTrackingMetrics)
+type TrackingMetrics interface {
+	ObserveTrackedEvents(event, outcome string, count int)
+}
+
+// maxTrackClockSkew avance maximale acceptée sur l'horodatage client
+const maxTrackClockSkew = time.Hour
+
+// ErrTrackTimestampInFuture horodatage client en avance de plus de maxTrackClockSkew sur l'horloge
+// du serveur ; vérifié à l'exécution, Validate ne connaissant pas l'horloge
+var ErrTrackTimestampInFuture = errors.New("timestamp dans le futur")
+
+// =============================================================================
+// EVENT SAMPLER : protection contre l'explosion de cardinalité
+// =============================================================================
+
+// TrackingLimits limites de cardinalité appliquées par fenêtre d'échantillonnage
+type TrackingLimits struct {
+	// MaxEventNames noms d'événements distincts ; au-delà, les nouveaux noms sont écartés
+	MaxEventNames int
+	// MaxPropertyValues valeurs distinctes d'une propriété d'un même événement
+	MaxPropertyValues int
+	// ReservoirSize événements hors limite retenus par nom d'événement ; 0 = tous écartés
+	ReservoirSize int
+}
+
+// maxTrackedPropertyKeys clés de propriétés distinctes par nom d'événement et par fenêtre
+const maxTrackedPropertyKeys = 4 * entities.MaxEventProperties
+
+// otherEventsLabel compteur des événements écartés parce que leur nom dépasse MaxEventNames :
+// les compteurs par nom ne doivent pas exploser à leur tour
+const otherEventsLabel = "(other)"
+
+// EventSampler suit, sur la fenêtre courante, les valeurs distinctes vues pour chaque propriété
+// de chaque événement. Un événement qui ferait dépasser une limite (client qui envoie un
+// identifiant unique par événement, par exemple) n'est pas enregistré tel quel : il entre dans
+// un réservoir de taille fixe par nom (algorithme R), vidé par FlushEventSamplesUseCase en fin de fenêtre
+// Les valeurs sont gardées sous forme d'empreinte : la mémoire reste bornée par les limites
+type EventSampler struct {
+	limits TrackingLimits
+
+	mutex  sync.Mutex
+	events map[string]*sampledEventStats
+	// otherDropped événements écartés sur la limite de noms, dans la fenêtre
+	otherDropped int
+}
+
+type sampledEventStats struct {
+	values    map[string]map[uint64]struct{}
+	reservoir []*entities.TrackedEvent
+	// overflow événements hors limite vus dans la fenêtre (retenus ou non)
+	overflow int
+}
+
+func NewEventSampler(limits TrackingLimits) *EventSampler {
+	return &EventSampler{limits: limits, events: make(map[string]*sampledEventStats)}
+}
+
+// admit décide du sort de l'événement ; seules les valeurs des événements acceptés comptent
+// dans la cardinalité (un événement hors limite ne consomme pas le budget des suivants)
+func (s *EventSampler) admit(event *entities.TrackedEvent) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats, ok := s.events[event.Name]
+	if !ok {
+		if len(s.events) >= s.limits.MaxEventNames {
+			s.otherDropped++
+			return TrackOutcomeDropped
+		}
+		stats = &sampledEventStats{values: make(map[string]map[uint64]struct{})}
+		s.events[event.Name] = stats
+	}
+
+	fingerprints := make(map[string]uint64, len(event.Properties))
+	newKeys := 0
+	withinLimits := true
+	for key, value := range event.Properties {
+		fingerprint := propertyFingerprint(value)
+		seen, known := stats.values[key]
+		if !known {
+			newKeys++
+			if len(stats.values)+newKeys > maxTrackedPropertyKeys {
+				withinLimits = false
+				break
+			}
+		} else if _, exists := seen[fingerprint]; !exists && len(seen) >= s.limits.MaxPropertyValues {
+			withinLimits = false
+			break
+		}
+		fingerprints[key] = fingerprint
+	}
+
+	if withinLimits {
+		for key, fingerprint := range fingerprints {
+			seen, known := stats.values[key]
+			if !known {
+				seen = make(map[uint64]struct{})
+				stats.values[key] = seen
+			}
+			seen[fingerprint] = struct{}{}
+		}
+		return TrackOutcomeAccepted
+	}
+
+	stats.overflow++
+	if s.limits.ReservoirSize <= 0 {
+		return TrackOutcomeDropped
+	}
+	if len(stats.reservoir) < s.limits.ReservoirSize {
+		stats.reservoir = append(stats.reservoir, event)
+		return TrackOutcomeSampled
+	}
+	// Algorithme R : le n-ième événement remplace un retenu avec une probabilité taille/n
+	if slot := rand.IntN(stats.overflow); slot < s.limits.ReservoirSize {
+		stats.reservoir[slot] = event
+		return TrackOutcomeSampled
+	}
+	return TrackOutcomeDropped
+}
+
+// sampledWindow contenu d'une fenêtre terminée
+type sampledWindow struct {
+	events []*entities.TrackedEvent
+	// sampled / dropped par nom d'événement
+	sampled map[string]int
+	dropped map[string]int
+}
+
+// drain clôt la fenêtre : compteurs de cardinalité remis à zéro, réservoirs retournés avec
+// leur poids (chaque événement retenu représente overflow/retenus événements)
+func (s *EventSampler) drain() sampledWindow {
+	s.mutex.Lock()
+	events, otherDropped := s.events, s.otherDropped
+	s.events = make(map[string]*sampledEventStats, len(events))
+	s.otherDropped = 0
+	s.mutex.Unlock()
+
+	window := sampledWindow{sampled: make(map[string]int), dropped: make(map[string]int)}
+	if otherDropped > 0 {
+		window.dropped[otherEventsLabel] = otherDropped
+	}
+	for name, stats := range events {
+		if stats.overflow == 0 {
+			continue
+		}
+		if dropped := stats.overflow - len(stats.reservoir); dropped > 0 {
+			window.dropped[name] = dropped
+		}
+		if len(stats.reservoir) == 0 {
+			continue
+		}
+		rate := float64(stats.overflow) / float64(len(stats.reservoir))
+		for _, event := range stats.reservoir {
+			event.SampleRate = rate
+			window.events = append(window.events, event)
+		}
+		window.sampled[name] = len(stats.reservoir)
+	}
+	return window
+}
+
+// propertyFingerprint empreinte typée d'une valeur normalisée ("1" et 1 restent distincts)
+func propertyFingerprint(value any) uint64 {
+	hash := fnv.New64a()
+	switch typed := value.(type) {
+	case string:
+		hash.Write([]byte("s:" + typed))
+	case float64:
+		hash.Write([]byte("n:" + strconv.FormatFloat(typed, 'g', -1, 64)))
+	case bool:
+		hash.Write([]byte("b:" + strconv.FormatBool(typed)))
+	}
+	return hash.Sum64()
+}
+
+// =============================================================================
+// TRACK EVENT USE CASE
+// =============================================================================
+
+// TrackEventUseCase enregistre un événement analytics envoyé par un client, pour l'utilisateur
+// connecté (anonyme sans session), sous la protection de l'EventSampler
+// Un utilisateur connecté sans consentement voit ses événements écartés ou anonymisés
+// (ConsentChecker) ; avec consentement, l'appareil déduit du User-Agent est ajouté aux propriétés
+type TrackEventUseCase struct {
+	eventRepo repositories.EventRepository
+	sampler   *EventSampler
+	consent   *ConsentChecker
+	agents    UserAgentParser
+	metrics   TrackingMetrics
+	clock     Clock
+}
+
+// trackedEventPool événements de l'ingestion : un événement enregistré ou écarté est rendu au pool
+// (Append n'en garde pas de référence), un événement retenu dans un réservoir ne l'est jamais
+var trackedEventPool = sync.Pool{New: func() any { return new(entities.TrackedEvent) }}
+
+func releaseTrackedEvent(event *entities.TrackedEvent) {
+	event.Reset()
+	trackedEventPool.Put(event)
+}
+
+// NewTrackEventUseCase consent nil : aucun contrôle du consentement ; agents nil : pas de propriétés d'appareil
+func NewTrackEventUseCase(
+	eventRepo repositories.EventRepository,
+	sampler *EventSampler,
+	consent *ConsentChecker,
+	agents UserAgentParser,
+	metrics TrackingMetrics,
+	clock Clock,
+) *TrackEventUseCase {
+	return &TrackEventUseCase{eventRepo: eventRepo, sampler: sampler, consent: consent, agents: agents, metrics: metrics, clock: clock}
+}
+
+type TrackEventRequest struct {
+	Event      string         `json:"event"`
+	Properties map[string]any `json:"properties,omitempty"`
+	// Timestamp horodatage client RFC 3339 ; vide = heure de réception
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+func (req TrackEventRequest) Validate() error {
+	if req.Event == "" {
+		return errors.New("nom d'événement obligatoire")
+	}
+	if err := entities.ValidateEventName(req.Event); err != nil {
+		return err
+	}
+	if req.Timestamp != "" {
+		if _, err := time.Parse(time.RFC3339, req.Timestamp); err != nil {
+			return errors.New("timestamp doit être une date RFC 3339")
+		}
+	}
+	return nil
+}
+
+// LogFields jamais les valeurs des propriétés : elles viennent du client et peuvent être personnelles
+func (req TrackEventRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"event": req.Event, "properties": len(req.Properties)}
+}
+
+func (req TrackEventRequest) QuotaUsage() map[string]int {
+	return map[string]int{repositories.UsageEvents: 1}
+}
+
+type TrackEventResponse struct {
+	// Status accepted, sampled (peut encore être écarté par le réservoir) ou dropped
+	Status string `json:"status"`
+}
+
+// trackResponses une réponse par issue, partagée entre les requêtes : elles ne sont jamais modifiées
+var trackResponses = map[string]*TrackEventResponse{
+	TrackOutcomeAccepted: {Status: TrackOutcomeAccepted},
+	TrackOutcomeSampled:  {Status: TrackOutcomeSampled},
+	TrackOutcomeDropped:  {Status: TrackOutcomeDropped},
+}
+
+// ConsumedQuota seuls les événements acceptés comptent : les échantillonnés sont enregistrés
+// en fin de fenêtre, pour le compte de plusieurs
+func (resp *TrackEventResponse) ConsumedQuota() map[string]int {
+	if resp.Status != TrackOutcomeAccepted {
+		return nil
+	}
+	return map[string]int{repositories.UsageEvents: 1}
+}
+
+func (uc *TrackEventUseCase) Execute(ctx context.Context, req TrackEventRequest) (*TrackEventResponse, error) {
+	now := uc.clock.Now()
+	var occurredAt time.Time
+	if req.Timestamp != "" {
+		occurredAt, _ = time.Parse(time.RFC3339, req.Timestamp)
+		if occurredAt.After(now.Add(maxTrackClockSkew)) {
+			return nil, ErrTrackTimestampInFuture
+		}
+	}
+	actor, _ := ActorFromContext(ctx)
+	userID, withDevice, err := uc.consentedUser(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if userID < 0 {
+		uc.metrics.ObserveTrackedEvents(req.Event, TrackOutcomeNoConsent, 1)
+		return trackResponses[TrackOutcomeDropped], nil
+	}
+
+	event := trackedEventPool.Get().(*entities.TrackedEvent)
+	if err := event.Init(req.Event, userID, req.Properties, occurredAt, now); err != nil {
+		releaseTrackedEvent(event)
+		return nil, err
+	}
+	if withDevice {
+		event.AddDeviceProperties(uc.agents.Parse(ClientFromContext(ctx).UserAgent))
+	}
+
+	// Échantillonnés et écartés sont comptés à la clôture de la fenêtre (FlushEventSamplesUseCase) :
+	// un événement retenu peut encore être remplacé dans le réservoir
+	outcome := uc.sampler.admit(event)
+	switch outcome {
+	case TrackOutcomeAccepted:
+		batch := [1]*entities.TrackedEvent{event}
+		err := uc.eventRepo.Append(ctx, batch[:])
+		if err == nil {
+			uc.metrics.ObserveTrackedEvents(event.Name, TrackOutcomeAccepted, 1)
+		}
+		releaseTrackedEvent(event)
+		if err != nil {
+			return nil, newError("erreur lors de l'enregistrement de l'événement", err)
+		}
+	case TrackOutcomeDropped:
+		releaseTrackedEvent(event)
+	}
+	return trackResponses[outcome], nil
+}
+
+// consentedUser utilisateur auquel rattacher l'événement : lui-même s'il a consenti, 0 (anonyme)
+// sans consentement en mode anonymize, -1 en mode drop. Les événements anonymes n'identifient
+// personne : ni contrôle, ni propriétés d'appareil
+func (uc *TrackEventUseCase) consentedUser(ctx context.Context, userID int) (int, bool, error) {
+	if userID == 0 {
+		return 0, false, nil
+	}
+	if uc.consent != nil {
+		consent, _, err := uc.consent.Current(ctx, userID)
+		if err != nil {
+			return 0, false, newError("erreur lors de la lecture du consentement", err)
+		}
+		if !consent.Granted() {
+			if uc.consent.withoutConsent == WithoutConsentDrop {
+				return -1, false, nil
+			}
+			return 0, false, nil
+		}
+	}
+	return userID, uc.agents != nil, nil
+}
+
+// =============================================================================
+// FLUSH EVENT SAMPLES USE CASE
+// =============================================================================
+
+// FlushEventSamplesUseCase clôt la fenêtre d'échantillonnage (tâche planifiée) : les événements
+// retenus sont enregistrés avec leur poids, les autres comptés comme écartés
+// Un arrêt du processus perd le réservoir de la fenêtre en cours
+type FlushEventSamplesUseCase struct {
+	eventRepo repositories.EventRepository
+	sampler   *EventSampler
+	metrics   TrackingMetrics
+}
+
+func NewFlushEventSamplesUseCase(eventRepo repositories.EventRepository, sampler *EventSampler, metrics TrackingMetrics) *FlushEventSamplesUseCase {
+	return &FlushEventSamplesUseCase{eventRepo: eventRepo, sampler: sampler, metrics: metrics}
+}
+
+type FlushEventSamplesRequest struct{}
+
+type FlushEventSamplesResponse struct {
+	Sampled int `json:"sampled"`
+	Dropped int `json:"dropped"`
+}
+
+func (uc *FlushEventSamplesUseCase) Execute(ctx context.Context, _ FlushEventSamplesRequest) (*FlushEventSamplesResponse, error) {
+	window := uc.sampler.drain()
+
+	response := &FlushEventSamplesResponse{}
+	for name, count := range window.dropped {
+		uc.metrics.ObserveTrackedEvents(name, TrackOutcomeDropped, count)
+		response.Dropped += count
+	}
+	if err := uc.eventRepo.Append(ctx, window.events); err != nil {
+		for name, count := range window.sampled {
+			uc.metrics.ObserveTrackedEvents(name, TrackOutcomeDropped, count)
+		}
+		return nil, newError("erreur lors de l'enregistrement des événements échantillonnés", err)
+	}
+	for name, count := range window.sampled {
+		uc.metrics.ObserveTrackedEvents(name, TrackOutcomeSampled, count)
+		response.Sampled += count
+	}
+	return response, nil
+}
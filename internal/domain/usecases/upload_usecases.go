@@ -0,0 +1,409 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// UPLOADS FRAGMENTÉS ET REPRENABLES
+// =============================================================================
+
+// FileStorage stockage des fichiers volumineux : fragments d'une session d'upload puis fichier
+// assemblé, fichiers produits par les exports
+type FileStorage interface {
+	// Write crée le fichier key avec le contenu écrit par write ; visible seulement une fois complet
+	Write(ctx context.Context, key string, write func(w io.Writer) error) error
+	// PutChunk écrit le fragment index de l'upload ; un fragment renvoyé remplace le précédent
+	PutChunk(ctx context.Context, uploadID string, index int, data []byte) error
+	// AssembleChunks concatène les fragments 0..count-1 dans le fichier key, puis les supprime
+	AssembleChunks(ctx context.Context, uploadID string, count int, key string) error
+	// Open erreur enveloppant fs.ErrNotExist si le fichier key n'existe pas
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete supprime le fichier key ; sans effet s'il n'existe pas
+	Delete(ctx context.Context, key string) error
+	// DeleteChunks supprime les fragments de l'upload ; sans effet s'il n'y en a pas
+	DeleteChunks(ctx context.Context, uploadID string) error
+}
+
+// ErrChunkChecksumMismatch le fragment reçu ne correspond pas à l'empreinte envoyée avec lui
+var ErrChunkChecksumMismatch = errors.New("empreinte du fragment différente de celle annoncée")
+
+// Uploads protocole d'upload fragmenté : ouverture d'une session (taille et SHA-256 annoncés),
+// envoi des fragments dans n'importe quel ordre (chacun vérifié s'il porte son empreinte),
+// reprise sur les fragments manquants, puis assemblage et vérification du fichier complet.
+// Seul le propriétaire d'une session la voit ; les sessions expirées sont purgées avec leurs fichiers
+type Uploads struct {
+	uploadRepo repositories.UploadSessionRepository
+	storage    FileStorage
+	tokens     TokenGenerator
+	clock      Clock
+	logger     Logger
+	chunkSize  int64
+	maxSize    int64
+	ttl        time.Duration
+
+	// locks un verrou par session : fragments et assemblage d'une même session ne se croisent pas
+	locks sync.Map
+}
+
+func NewUploads(
+	uploadRepo repositories.UploadSessionRepository,
+	storage FileStorage,
+	tokens TokenGenerator,
+	clock Clock,
+	logger Logger,
+	chunkSize, maxSize int64,
+	ttl time.Duration,
+) *Uploads {
+	return &Uploads{
+		uploadRepo: uploadRepo,
+		storage:    storage,
+		tokens:     tokens,
+		clock:      clock,
+		logger:     logger,
+		chunkSize:  chunkSize,
+		maxSize:    maxSize,
+		ttl:        ttl,
+	}
+}
+
+// open fichier assemblé d'une session terminée de l'appelant, pour le consommateur de son usage
+func (u *Uploads) open(ctx context.Context, id, purpose string) (io.ReadCloser, *entities.UploadSession, error) {
+	session, err := u.owned(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session.Purpose != purpose || session.Status != entities.UploadCompleted {
+		return nil, nil, repositories.ErrUploadSessionNotFound
+	}
+	file, err := u.storage.Open(ctx, session.FileKey)
+	if err != nil {
+		return nil, nil, newError("erreur lors de la lecture du fichier", err)
+	}
+	return file, session, nil
+}
+
+// owned session de l'appelant ; celle d'un autre utilisateur est introuvable
+func (u *Uploads) owned(ctx context.Context, id string) (*entities.UploadSession, error) {
+	session, err := u.uploadRepo.Get(ctx, id)
+	if errors.Is(err, repositories.ErrUploadSessionNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de la session d'upload", err)
+	}
+	actor, _ := ActorFromContext(ctx)
+	if !actor.System && actor.UserID != session.OwnerID {
+		return nil, repositories.ErrUploadSessionNotFound
+	}
+	return session, nil
+}
+
+// discard supprime la session, ses fragments et son fichier assemblé (consommé ou expiré)
+func (u *Uploads) discard(ctx context.Context, session *entities.UploadSession) error {
+	unlock := u.lock(session.ID)
+	defer unlock()
+
+	if err := u.storage.DeleteChunks(ctx, session.ID); err != nil {
+		return err
+	}
+	if session.FileKey != "" {
+		if err := u.storage.Delete(ctx, session.FileKey); err != nil {
+			return err
+		}
+	}
+	if err := u.uploadRepo.Delete(ctx, session.ID); err != nil {
+		return err
+	}
+	u.locks.Delete(session.ID)
+	return nil
+}
+
+func (u *Uploads) lock(id string) func() {
+	value, _ := u.locks.LoadOrStore(id, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// UploadResponse session et fragments restant à envoyer
+type UploadResponse struct {
+	*entities.UploadSession
+	ChunkCount    int   `json:"chunk_count"`
+	MissingChunks []int `json:"missing_chunks"`
+}
+
+func toUploadResponse(session *entities.UploadSession) *UploadResponse {
+	return &UploadResponse{
+		UploadSession: session,
+		ChunkCount:    len(session.Chunks),
+		MissingChunks: session.Missing(),
+	}
+}
+
+// =============================================================================
+// CREATE UPLOAD USE CASE
+// =============================================================================
+
+type CreateUploadUseCase struct {
+	uploads *Uploads
+}
+
+func NewCreateUploadUseCase(uploads *Uploads) *CreateUploadUseCase {
+	return &CreateUploadUseCase{uploads: uploads}
+}
+
+type CreateUploadRequest struct {
+	Purpose  string `json:"purpose"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	// Checksum SHA-256 (hex) du fichier complet
+	Checksum string `json:"checksum"`
+}
+
+func (req CreateUploadRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"purpose": req.Purpose, "filename": req.Filename, "size": req.Size}
+}
+
+func (uc *CreateUploadUseCase) Execute(ctx context.Context, req CreateUploadRequest) (*UploadResponse, error) {
+	u := uc.uploads
+	if req.Size > u.maxSize {
+		return nil, fmt.Errorf("fichier trop volumineux : %d octets maximum", u.maxSize)
+	}
+	id, err := u.tokens.HexToken(16)
+	if err != nil {
+		return nil, newError("erreur lors de la création de la session d'upload", err)
+	}
+	actor, _ := ActorFromContext(ctx)
+	now := u.clock.Now()
+	session, err := entities.NewUploadSession(id, actor.UserID, req.Purpose, req.Filename, req.Size, u.chunkSize, req.Checksum, now, now.Add(u.ttl))
+	if err != nil {
+		return nil, err
+	}
+	if err := u.uploadRepo.Save(ctx, session); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de la session d'upload", err)
+	}
+	return toUploadResponse(session), nil
+}
+
+// =============================================================================
+// GET UPLOAD USE CASE
+// =============================================================================
+
+// GetUploadUseCase état d'une session : le client reprend l'envoi sur MissingChunks
+type GetUploadUseCase struct {
+	uploads *Uploads
+}
+
+func NewGetUploadUseCase(uploads *Uploads) *GetUploadUseCase {
+	return &GetUploadUseCase{uploads: uploads}
+}
+
+func (uc *GetUploadUseCase) Execute(ctx context.Context, id string) (*UploadResponse, error) {
+	session, err := uc.uploads.owned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toUploadResponse(session), nil
+}
+
+// =============================================================================
+// UPLOAD CHUNK USE CASE
+// =============================================================================
+
+type UploadChunkUseCase struct {
+	uploads *Uploads
+}
+
+func NewUploadChunkUseCase(uploads *Uploads) *UploadChunkUseCase {
+	return &UploadChunkUseCase{uploads: uploads}
+}
+
+type UploadChunkRequest struct {
+	UploadID string
+	Index    int
+	// Checksum SHA-256 (hex) du fragment ; vide = fragment vérifié seulement avec le fichier complet
+	Checksum string
+	Body     io.Reader
+}
+
+func (req UploadChunkRequest) Validate() error {
+	if req.Checksum != "" && !entities.IsSHA256Hex(req.Checksum) {
+		return errors.New("empreinte du fragment : SHA-256 en hexadécimal attendu")
+	}
+	return nil
+}
+
+func (req UploadChunkRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"upload_id": req.UploadID, "index": req.Index}
+}
+
+// Execute le fragment est lu en entier puis vérifié (taille, empreinte) avant d'être écrit :
+// un envoi interrompu ou altéré ne remplace jamais un fragment déjà reçu
+func (uc *UploadChunkUseCase) Execute(ctx context.Context, req UploadChunkRequest) (*UploadResponse, error) {
+	u := uc.uploads
+	session, err := u.owned(ctx, req.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	length, err := session.ChunkLength(req.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, length+1))
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du fragment", err)
+	}
+	if int64(len(data)) != length {
+		return nil, fmt.Errorf("%w : %d octets attendus pour le fragment %d", entities.ErrInvalidUploadChunk, length, req.Index)
+	}
+	digest := sha256.Sum256(data)
+	checksum := hex.EncodeToString(digest[:])
+	if req.Checksum != "" && req.Checksum != checksum {
+		return nil, ErrChunkChecksumMismatch
+	}
+
+	unlock := u.lock(session.ID)
+	defer unlock()
+
+	// Relue sous verrou : un autre fragment ou l'assemblage a pu la modifier entre-temps
+	session, err = u.owned(ctx, req.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	now := u.clock.Now()
+	if err := session.AcceptChunk(req.Index, checksum, now); err != nil {
+		return nil, err
+	}
+	if err := u.storage.PutChunk(ctx, session.ID, req.Index, data); err != nil {
+		return nil, newError("erreur lors de l'écriture du fragment", err)
+	}
+	if err := u.uploadRepo.Save(ctx, session); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de la session d'upload", err)
+	}
+	return toUploadResponse(session), nil
+}
+
+// =============================================================================
+// COMPLETE UPLOAD USE CASE
+// =============================================================================
+
+type CompleteUploadUseCase struct {
+	uploads *Uploads
+}
+
+func NewCompleteUploadUseCase(uploads *Uploads) *CompleteUploadUseCase {
+	return &CompleteUploadUseCase{uploads: uploads}
+}
+
+// Execute assemble les fragments puis vérifie le fichier contre la somme annoncée à l'ouverture ;
+// un fichier différent est supprimé et la session close (status failed)
+func (uc *CompleteUploadUseCase) Execute(ctx context.Context, id string) (*UploadResponse, error) {
+	u := uc.uploads
+	unlock := u.lock(id)
+	defer unlock()
+
+	session, err := u.owned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := u.clock.Now()
+	if err := session.CanComplete(now); err != nil {
+		return nil, err
+	}
+
+	key := "upload-" + session.ID
+	if err := u.storage.AssembleChunks(ctx, session.ID, len(session.Chunks), key); err != nil {
+		return nil, newError("erreur lors de l'assemblage du fichier", err)
+	}
+	checksum, err := uc.checksum(ctx, key)
+	if err != nil {
+		return nil, newError("erreur lors de la vérification du fichier", err)
+	}
+
+	if checksum != session.Checksum {
+		session.Fail(now)
+		if err := u.storage.Delete(ctx, key); err != nil {
+			u.logger.Error("Failed to delete rejected upload", err, map[string]interface{}{"upload_id": session.ID})
+		}
+	} else {
+		session.Complete(key, now)
+	}
+	if err := u.uploadRepo.Save(ctx, session); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de la session d'upload", err)
+	}
+	if session.Status == entities.UploadFailed {
+		return nil, entities.ErrUploadChecksumMismatch
+	}
+	return toUploadResponse(session), nil
+}
+
+func (uc *CompleteUploadUseCase) checksum(ctx context.Context, key string) (string, error) {
+	file, err := uc.uploads.storage.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// =============================================================================
+// PURGE EXPIRED UPLOADS USE CASE
+// =============================================================================
+
+// purgeUploadsBatch sessions traitées par passage de la tâche planifiée
+const purgeUploadsBatch = 500
+
+// PurgeExpiredUploadsUseCase supprime les sessions expirées, leurs fragments et leur fichier
+// assemblé (tâche planifiée) : un fichier non consommé avant l'expiration est perdu
+type PurgeExpiredUploadsUseCase struct {
+	uploads *Uploads
+}
+
+func NewPurgeExpiredUploadsUseCase(uploads *Uploads) *PurgeExpiredUploadsUseCase {
+	return &PurgeExpiredUploadsUseCase{uploads: uploads}
+}
+
+type PurgeExpiredUploadsRequest struct {
+	Now time.Time
+}
+
+type PurgeExpiredUploadsResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+func (uc *PurgeExpiredUploadsUseCase) Execute(ctx context.Context, req PurgeExpiredUploadsRequest) (*PurgeExpiredUploadsResponse, error) {
+	u := uc.uploads
+	if req.Now.IsZero() {
+		req.Now = u.clock.Now()
+	}
+	expired, err := u.uploadRepo.ListExpired(ctx, req.Now, purgeUploadsBatch)
+	if err != nil {
+		return nil, newError("erreur lors de la recherche des uploads expirés", err)
+	}
+
+	response := &PurgeExpiredUploadsResponse{}
+	for _, session := range expired {
+		if err := u.discard(ctx, session); err != nil {
+			u.logger.Error("Failed to purge upload", err, map[string]interface{}{"upload_id": session.ID})
+			continue
+		}
+		response.Deleted++
+	}
+	return response, nil
+}
@@ -0,0 +1,299 @@
+// internal/domain/usecases/usage_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded cause des erreurs de use case refusés par WithQuotas (HTTP 429)
+var ErrQuotaExceeded = errors.New("quota dépassé")
+
+// QuotaConsumer entrée de use case déclarant les ressources stockées que son exécution va créer
+// (ex: {"users": 1}) : le quota est vérifié avant l'exécution, pas une fois la ressource créée
+type QuotaConsumer interface {
+	QuotaUsage() map[string]int
+}
+
+// QuotaReporter sortie de use case déclarant les ressources réellement stockées, quand aucun
+// événement du domaine ne permet au UsageMeter de les compter (événements analytics)
+type QuotaReporter interface {
+	ConsumedQuota() map[string]int
+}
+
+// Quotas limite par ressource (repositories.UsageResources) ; absente ou 0 = illimitée
+type Quotas map[string]int
+
+// QuotaPolicy quotas par défaut et surcharges par tenant (ressource par ressource)
+type QuotaPolicy struct {
+	Default Quotas
+	Tenants map[string]Quotas
+}
+
+// For quotas effectifs d'un tenant
+func (p QuotaPolicy) For(tenant string) Quotas {
+	quotas := maps.Clone(p.Default)
+	if quotas == nil {
+		quotas = make(Quotas)
+	}
+	for resource, limit := range p.Tenants[tenant] {
+		quotas[resource] = limit
+	}
+	return quotas
+}
+
+// TenantQuotaSource quotas propres au tenant, fixés hors configuration (offre souscrite : TenantPlans)
+type TenantQuotaSource interface {
+	TenantQuotas(ctx context.Context, tenant string) (Quotas, error)
+}
+
+// usagePeriod mois calendaire UTC des compteurs d'appels
+func usagePeriod(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// =============================================================================
+// USAGE METER : compteurs d'usage par tenant
+// =============================================================================
+
+// UsageMeter mesure l'usage de chaque tenant (celui de l'acteur courant) :
+//   - appels de use cases, comptés par WithQuotas
+//   - utilisateurs stockés, suivis par Handle sur le bus (user.created / user.deleted)
+//   - événements analytics stockés, déclarés par les sorties QuotaReporter
+//
+// Les appels sans tenant (comptes sans tenant, tâches internes) ne sont ni mesurés ni limités
+type UsageMeter struct {
+	usageRepo repositories.UsageRepository
+	// plans quotas de l'offre du tenant, entre les quotas par défaut et ses surcharges ; nil = aucun
+	plans TenantQuotaSource
+	clock Clock
+
+	mutex  sync.RWMutex
+	policy QuotaPolicy
+}
+
+func NewUsageMeter(usageRepo repositories.UsageRepository, policy QuotaPolicy, plans TenantQuotaSource, clock Clock) *UsageMeter {
+	return &UsageMeter{usageRepo: usageRepo, policy: policy, plans: plans, clock: clock}
+}
+
+// SetPolicy remplace les quotas de la configuration (rechargement à chaud) ; les compteurs sont conservés
+func (m *UsageMeter) SetPolicy(policy QuotaPolicy) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.policy = policy
+}
+
+// quotasFor quotas effectifs : défaut, puis offre souscrite, puis surcharges de TENANT_QUOTAS
+func (m *UsageMeter) quotasFor(ctx context.Context, tenant string) (Quotas, error) {
+	m.mutex.RLock()
+	policy := m.policy
+	m.mutex.RUnlock()
+	quotas := policy.For(tenant)
+	if m.plans == nil {
+		return quotas, nil
+	}
+	planQuotas, err := m.plans.TenantQuotas(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	for resource, limit := range planQuotas {
+		if _, overridden := policy.Tenants[tenant][resource]; !overridden {
+			quotas[resource] = limit
+		}
+	}
+	return quotas, nil
+}
+
+// meteredTenant tenant de l'acteur courant ; "" si l'appel n'est pas mesuré
+func meteredTenant(ctx context.Context) string {
+	actor, _ := ActorFromContext(ctx)
+	if actor.System {
+		return ""
+	}
+	return actor.TenantID
+}
+
+// Handle suit les utilisateurs stockés ; abonné au bus, le context porte l'acteur de la commande
+func (m *UsageMeter) Handle(ctx context.Context, event events.Event) error {
+	tenant := meteredTenant(ctx)
+	if tenant == "" {
+		return nil
+	}
+	switch event.EventName() {
+	case events.UserCreatedEvent:
+		_, err := m.usageRepo.Add(ctx, tenant, repositories.UsageLifetime, repositories.UsageUsers, 1)
+		return err
+	case events.UserDeletedEvent:
+		_, err := m.usageRepo.Add(ctx, tenant, repositories.UsageLifetime, repositories.UsageUsers, -1)
+		return err
+	}
+	return nil
+}
+
+// usage compteurs du mois courant et ressources stockées, fusionnés
+func (m *UsageMeter) usage(ctx context.Context, tenant string, now time.Time) (map[string]int, error) {
+	usage, err := m.usageRepo.Get(ctx, tenant, repositories.UsageLifetime)
+	if err != nil {
+		return nil, err
+	}
+	calls, err := m.usageRepo.Get(ctx, tenant, usagePeriod(now))
+	if err != nil {
+		return nil, err
+	}
+	usage[repositories.UsageAPICalls] = calls[repositories.UsageAPICalls]
+	return usage, nil
+}
+
+// check refuse l'appel si lui-même ou les ressources déclarées dépassent un quota
+// Vérification puis écriture : sous forte concurrence, un quota peut être dépassé de quelques unités
+func (m *UsageMeter) check(ctx context.Context, tenant string, consumption map[string]int, now time.Time) error {
+	quotas, err := m.quotasFor(ctx, tenant)
+	if err != nil {
+		return newError("erreur lors de la lecture des quotas du tenant", err)
+	}
+	if len(quotas) == 0 {
+		return nil
+	}
+	usage, err := m.usage(ctx, tenant, now)
+	if err != nil {
+		return newError("erreur lors de la lecture de l'usage du tenant", err)
+	}
+
+	requested := maps.Clone(consumption)
+	if requested == nil {
+		requested = make(map[string]int, 1)
+	}
+	requested[repositories.UsageAPICalls]++
+	for resource, amount := range requested {
+		limit := quotas[resource]
+		if limit > 0 && amount > 0 && usage[resource]+amount > limit {
+			return newError(fmt.Sprintf("quota %s atteint pour le tenant %s (%d/%d)", resource, tenant, usage[resource], limit),
+				ErrQuotaExceeded)
+		}
+	}
+	return nil
+}
+
+// WithQuotas mesure les appels du tenant de l'acteur et refuse ceux qui dépasseraient un quota
+// Les appels refusés ne sont pas comptés ; un appel en erreur l'est (il a consommé du service)
+func WithQuotas[I, O any](meter *UsageMeter) Decorator[I, O] {
+	return func(next UseCase[I, O]) UseCase[I, O] {
+		if meter == nil {
+			return next
+		}
+		return UseCaseFunc[I, O](func(ctx context.Context, input I) (O, error) {
+			tenant := meteredTenant(ctx)
+			if tenant == "" {
+				return next.Execute(ctx, input)
+			}
+
+			now := meter.clock.Now()
+			var consumption map[string]int
+			if consumer, ok := any(input).(QuotaConsumer); ok {
+				consumption = consumer.QuotaUsage()
+			}
+			if err := meter.check(ctx, tenant, consumption, now); err != nil {
+				var zero O
+				return zero, err
+			}
+			if _, err := meter.usageRepo.Add(ctx, tenant, usagePeriod(now), repositories.UsageAPICalls, 1); err != nil {
+				var zero O
+				return zero, newError("erreur lors de la mesure de l'usage du tenant", err)
+			}
+
+			output, err := next.Execute(ctx, input)
+			if err != nil {
+				return output, err
+			}
+			if reporter, ok := any(output).(QuotaReporter); ok {
+				for resource, amount := range reporter.ConsumedQuota() {
+					if amount == 0 {
+						continue
+					}
+					if _, err := meter.usageRepo.Add(ctx, tenant, repositories.UsageLifetime, resource, amount); err != nil {
+						var zero O
+						return zero, newError("erreur lors de la mesure de l'usage du tenant", err)
+					}
+				}
+			}
+			return output, nil
+		})
+	}
+}
+
+// =============================================================================
+// GET TENANT USAGE USE CASE
+// =============================================================================
+
+type GetTenantUsageUseCase struct {
+	meter *UsageMeter
+}
+
+func NewGetTenantUsageUseCase(meter *UsageMeter) *GetTenantUsageUseCase {
+	return &GetTenantUsageUseCase{meter: meter}
+}
+
+type GetTenantUsageRequest struct {
+	TenantID string
+}
+
+func (req GetTenantUsageRequest) Validate() error {
+	if req.TenantID == "" {
+		return errors.New("tenant obligatoire")
+	}
+	return nil
+}
+
+func (req GetTenantUsageRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant": req.TenantID}
+}
+
+func (req GetTenantUsageRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+type ResourceUsageResponse struct {
+	Resource string `json:"resource"`
+	Used     int    `json:"used"`
+	// Limit quota de la ressource ; absent = illimité
+	Limit int `json:"limit,omitempty"`
+}
+
+type TenantUsageResponse struct {
+	TenantID string `json:"tenant_id"`
+	// Period mois des compteurs d'appels ("AAAA-MM", UTC)
+	Period    string                  `json:"period"`
+	Resources []ResourceUsageResponse `json:"resources"`
+}
+
+func (uc *GetTenantUsageUseCase) Execute(ctx context.Context, req GetTenantUsageRequest) (*TenantUsageResponse, error) {
+	now := uc.meter.clock.Now()
+	usage, err := uc.meter.usage(ctx, req.TenantID, now)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de l'usage du tenant", err)
+	}
+
+	quotas, err := uc.meter.quotasFor(ctx, req.TenantID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des quotas du tenant", err)
+	}
+	response := &TenantUsageResponse{
+		TenantID:  req.TenantID,
+		Period:    usagePeriod(now),
+		Resources: make([]ResourceUsageResponse, 0, len(repositories.UsageResources)),
+	}
+	for _, resource := range repositories.UsageResources {
+		response.Resources = append(response.Resources, ResourceUsageResponse{
+			Resource: resource,
+			Used:     usage[resource],
+			Limit:    quotas[resource],
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,430 @@
+// internal/domain/usecases/user_bulk_commands.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxBulkSize nombre maximal de lignes par opération en lot
+const MaxBulkSize = 1000
+
+// Les opérations en lot sont destinées aux outils d'import : tout ou rien, un aller-retour
+// vers le stockage par lot, et pas d'email de bienvenue (l'import n'est pas une inscription)
+
+// =============================================================================
+// BULK CREATE USERS USE CASE
+// =============================================================================
+
+type BulkCreateUsersUseCase struct {
+	userRepo     repositories.UserRepository
+	passwordHash PasswordHasher
+	publisher    EventPublisher
+	clock        Clock
+}
+
+func NewBulkCreateUsersUseCase(
+	userRepo repositories.UserRepository,
+	passwordHash PasswordHasher,
+	publisher EventPublisher,
+	clock Clock,
+) *BulkCreateUsersUseCase {
+	return &BulkCreateUsersUseCase{
+		userRepo:     userRepo,
+		passwordHash: passwordHash,
+		publisher:    publisher,
+		clock:        clock,
+	}
+}
+
+type BulkCreateUsersRequest struct {
+	Users []CreateUserRequest `json:"users"`
+}
+
+func (req BulkCreateUsersRequest) QuotaUsage() map[string]int {
+	return map[string]int{repositories.UsageUsers: len(req.Users)}
+}
+
+type BulkCreateUsersResponse struct {
+	Users []*CreateUserResponse `json:"users"`
+}
+
+func (req BulkCreateUsersRequest) Validate() error {
+	return validateBulkSize(len(req.Users))
+}
+
+func (req BulkCreateUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"count": len(req.Users)}
+}
+
+func (uc *BulkCreateUsersUseCase) Execute(ctx context.Context, req BulkCreateUsersRequest) (*BulkCreateUsersResponse, error) {
+	// 1. Valider toutes les lignes avant d'écrire quoi que ce soit
+	now := uc.clock.Now()
+	users := make([]*entities.User, len(req.Users))
+	seen := make(map[string]int, len(req.Users))
+	for i, input := range req.Users {
+		user, err := entities.NewUser(input.Email, input.Name, input.Password, now)
+		if err != nil {
+			return nil, lineError(i, err)
+		}
+		if first, duplicate := seen[user.Email]; duplicate {
+			return nil, lineError(i, fmt.Errorf("email en double (ligne %d)", first+1))
+		}
+		seen[user.Email] = i
+		users[i] = user
+	}
+
+	// 2. Hasher les mots de passe (coûteux : on s'arrête dès que le context est annulé)
+	for i, user := range users {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hashedPassword, err := uc.passwordHash.Hash(user.Password)
+		if err != nil {
+			return nil, newError("erreur lors du traitement du mot de passe", err)
+		}
+		users[i].Password = hashedPassword
+	}
+
+	// 3. Sauvegarder le lot
+	created, err := uc.userRepo.CreateMany(ctx, users)
+	if err != nil {
+		return nil, newError("erreur lors de la création des utilisateurs", err)
+	}
+
+	response := &BulkCreateUsersResponse{Users: make([]*CreateUserResponse, len(created))}
+	publishedEvents := make([]events.Event, len(created))
+	for i, user := range created {
+		publishedEvents[i] = events.UserCreated{
+			UserID:  user.ID,
+			Email:   user.Email,
+			Name:    user.Name,
+			Created: user.Created,
+		}
+		response.Users[i] = &CreateUserResponse{
+			ID:      user.ID,
+			Email:   user.Email,
+			Name:    user.Name,
+			Created: user.Created,
+		}
+	}
+	uc.publisher.Publish(ctx, publishedEvents...)
+
+	return response, nil
+}
+
+// =============================================================================
+// BULK UPDATE USERS USE CASE
+// =============================================================================
+
+type BulkUpdateUsersUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewBulkUpdateUsersUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *BulkUpdateUsersUseCase {
+	return &BulkUpdateUsersUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+type BulkUpdateUsersRequest struct {
+	Users []UpdateUserRequest `json:"users"`
+}
+
+// PolicyAttributes utilisateurs modifiés (resource.user_ids), pour les politiques qui bornent les lots
+func (req BulkUpdateUsersRequest) PolicyAttributes() map[string]interface{} {
+	ids := make([]int, len(req.Users))
+	for i, user := range req.Users {
+		ids[i] = user.ID
+	}
+	return map[string]interface{}{"user_ids": ids}
+}
+
+type BulkUpdateUsersResponse struct {
+	Users []*UpdateUserResponse `json:"users"`
+}
+
+func (req BulkUpdateUsersRequest) Validate() error {
+	if err := validateBulkSize(len(req.Users)); err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(req.Users))
+	for i, input := range req.Users {
+		if err := input.Validate(); err != nil {
+			return lineError(i, err)
+		}
+		if seen[input.ID] {
+			return lineError(i, errors.New("utilisateur en double dans le lot"))
+		}
+		seen[input.ID] = true
+	}
+	return nil
+}
+
+func (req BulkUpdateUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"count": len(req.Users)}
+}
+
+func (uc *BulkUpdateUsersUseCase) Execute(ctx context.Context, req BulkUpdateUsersRequest) (*BulkUpdateUsersResponse, error) {
+	// 1. Charger les utilisateurs en une requête et appliquer les règles métier de l'entité
+	ids := make([]int, len(req.Users))
+	for i, input := range req.Users {
+		ids[i] = input.ID
+	}
+	loaded, err := uc.userRepo.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, newError("erreur lors de la récupération des utilisateurs", err)
+	}
+	byID := make(map[int]*entities.User, len(loaded))
+	for _, user := range loaded {
+		byID[user.ID] = user
+	}
+
+	now := uc.clock.Now()
+	users := make([]*entities.User, len(req.Users))
+	changes := make([][]entities.FieldChange, len(req.Users))
+	var changed []*entities.User
+	for i, input := range req.Users {
+		user, found := byID[input.ID]
+		if !found {
+			return nil, lineError(i, newError("utilisateur non trouvé", repositories.ErrUserNotFound))
+		}
+		if changes[i], err = user.UpdateUserProfile(input.Name, input.Email, now); err != nil {
+			return nil, lineError(i, err)
+		}
+		users[i] = user
+		if len(changes[i]) > 0 {
+			changed = append(changed, user)
+		}
+	}
+
+	// 2. Sauvegarder les seuls utilisateurs modifiés (l'unicité des emails est vérifiée par le repository)
+	if len(changed) > 0 {
+		if _, err := uc.userRepo.UpdateMany(ctx, changed); err != nil {
+			return nil, newError("erreur lors de la mise à jour des utilisateurs", err)
+		}
+	}
+
+	response := &BulkUpdateUsersResponse{Users: make([]*UpdateUserResponse, len(users))}
+	publishedEvents := make([]events.Event, 0, len(changed))
+	for i, user := range users {
+		if len(changes[i]) > 0 {
+			publishedEvents = append(publishedEvents, events.UserProfileUpdated{
+				UserID:        user.ID,
+				Email:         user.Email,
+				Name:          user.Name,
+				ChangedFields: entities.ChangedFields(changes[i]),
+				Updated:       user.Updated,
+			})
+		}
+		response.Users[i] = newUpdateUserResponse(user, changes[i])
+	}
+	uc.publisher.Publish(ctx, publishedEvents...)
+
+	return response, nil
+}
+
+// =============================================================================
+// BULK DELETE USERS USE CASE
+// =============================================================================
+
+type BulkDeleteUsersUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewBulkDeleteUsersUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *BulkDeleteUsersUseCase {
+	return &BulkDeleteUsersUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+type BulkDeleteUsersRequest struct {
+	IDs []int `json:"ids"`
+}
+
+type BulkDeleteUsersResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+func (req BulkDeleteUsersRequest) Validate() error {
+	if err := validateBulkSize(len(req.IDs)); err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(req.IDs))
+	for i, id := range req.IDs {
+		if id <= 0 {
+			return lineError(i, errors.New("identifiant utilisateur invalide"))
+		}
+		if seen[id] {
+			return lineError(i, errors.New("utilisateur en double dans le lot"))
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// PolicyAttributes utilisateurs supprimés (resource.user_ids)
+func (req BulkDeleteUsersRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_ids": req.IDs}
+}
+
+func (req BulkDeleteUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"count": len(req.IDs)}
+}
+
+func (uc *BulkDeleteUsersUseCase) Execute(ctx context.Context, req BulkDeleteUsersRequest) (*BulkDeleteUsersResponse, error) {
+	if err := uc.userRepo.DeleteByIds(ctx, req.IDs); err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, newError("au moins un utilisateur est introuvable", err)
+		}
+		return nil, newError("erreur lors de la suppression des utilisateurs", err)
+	}
+
+	deleted := uc.clock.Now()
+	publishedEvents := make([]events.Event, len(req.IDs))
+	for i, id := range req.IDs {
+		publishedEvents[i] = events.UserDeleted{UserID: id, Deleted: deleted}
+	}
+	uc.publisher.Publish(ctx, publishedEvents...)
+
+	return &BulkDeleteUsersResponse{Deleted: len(req.IDs)}, nil
+}
+
+// =============================================================================
+// IMPORT USERS USE CASE
+// =============================================================================
+
+// ImportUsersUseCase crée les comptes d'un fichier CSV envoyé en upload fragmenté (usage
+// user_import), colonnes email, name et password dans n'importe quel ordre. Le fichier est
+// importé par lots de MaxBulkSize lignes, chacun tout ou rien : une erreur arrête l'import, les
+// lots précédents restent créés. Le fichier entièrement importé est supprimé
+type ImportUsersUseCase struct {
+	uploads     *Uploads
+	createUsers UseCase[BulkCreateUsersRequest, *BulkCreateUsersResponse]
+}
+
+func NewImportUsersUseCase(uploads *Uploads, createUsers UseCase[BulkCreateUsersRequest, *BulkCreateUsersResponse]) *ImportUsersUseCase {
+	return &ImportUsersUseCase{uploads: uploads, createUsers: createUsers}
+}
+
+type ImportUsersRequest struct {
+	UploadID string `json:"upload_id"`
+}
+
+func (req ImportUsersRequest) Validate() error {
+	if req.UploadID == "" {
+		return errors.New("upload_id obligatoire")
+	}
+	return nil
+}
+
+func (req ImportUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"upload_id": req.UploadID}
+}
+
+type ImportUsersResponse struct {
+	Imported int `json:"imported"`
+}
+
+func (uc *ImportUsersUseCase) Execute(ctx context.Context, req ImportUsersRequest) (*ImportUsersResponse, error) {
+	file, session, err := uc.uploads.open(ctx, req.UploadID, entities.UploadPurposeUserImport)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("en-tête CSV illisible : %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))] = i
+	}
+	for _, required := range []string{"email", "name", "password"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("colonne %q absente de l'en-tête CSV", required)
+		}
+	}
+
+	response := &ImportUsersResponse{}
+	batch := make([]CreateUserRequest, 0, MaxBulkSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := uc.createUsers.Execute(ctx, BulkCreateUsersRequest{Users: batch}); err != nil {
+			// Lignes du lot numérotées à partir de 1 : la ligne 1 du fichier est l'en-tête
+			return fmt.Errorf("lot débutant ligne %d du fichier (%d comptes déjà importés) : %w", response.Imported+2, response.Imported, err)
+		}
+		response.Imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV invalide (%d comptes déjà importés) : %w", response.Imported, err)
+		}
+		batch = append(batch, CreateUserRequest{
+			Email:    record[columns["email"]],
+			Name:     record[columns["name"]],
+			Password: record[columns["password"]],
+		})
+		if len(batch) == MaxBulkSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.uploads.discard(context.WithoutCancel(ctx), session); err != nil {
+		uc.uploads.logger.Error("Failed to delete imported upload", err, map[string]interface{}{"upload_id": session.ID})
+	}
+	return response, nil
+}
+
+func validateBulkSize(size int) error {
+	if size == 0 {
+		return errors.New("le lot est vide")
+	}
+	if size > MaxBulkSize {
+		return fmt.Errorf("le lot dépasse %d lignes", MaxBulkSize)
+	}
+	return nil
+}
+
+// lineError préfixe l'erreur par le numéro de ligne (1-based) pour que l'import soit corrigeable
+// Le message client d'une erreur de use case est préfixé, sa cause est conservée pour les logs
+func lineError(index int, err error) error {
+	var ucErr *Error
+	if errors.As(err, &ucErr) {
+		return &Error{Message: fmt.Sprintf("ligne %d : %s", index+1, ucErr.Message), Cause: ucErr.Cause}
+	}
+	return fmt.Errorf("ligne %d : %w", index+1, err)
+}
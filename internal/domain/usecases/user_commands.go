@@ -0,0 +1,742 @@
+// internal/domain/usecases/user_commands.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// INTERFACES POUR LES SERVICES EXTERNES (Dependency Inversion)
+// =============================================================================
+
+// PasswordHasher interface pour hasher les mots de passe
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) error
+}
+
+// EmailSender interface pour envoyer des emails ; SendWelcomeEmail et SendEmail sont des raccourcis
+// de Send pour les emails textuels de l'application
+type EmailSender interface {
+	SendWelcomeEmail(ctx context.Context, email, name string) error
+	SendEmail(ctx context.Context, to, subject, body string) error
+	Send(ctx context.Context, mail Mail) error
+}
+
+// EventPublisher interface pour publier les événements du domaine
+// La publication a lieu après l'écriture : les abonnés (projecteurs, notifications)
+// gèrent eux-mêmes leurs erreurs, une commande réussie ne doit pas échouer à cause d'eux
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...events.Event)
+}
+
+// Logger interface pour les logs
+type Logger interface {
+	Info(message string, fields map[string]interface{})
+	Warn(message string, fields map[string]interface{})
+	Error(message string, err error, fields map[string]interface{})
+}
+
+// TaskRunner exécute les effets de bord asynchrones (emails...) de façon suivie :
+// la tâche reçoit un context détaché de la requête, le nombre de tâches simultanées est borné
+// et l'arrêt du serveur attend leur fin. Go échoue si le runner est arrêté
+type TaskRunner interface {
+	Go(ctx context.Context, name string, task func(ctx context.Context) error) error
+}
+
+// AttributeSchemaRegistry fournit le schéma des attributs personnalisés d'un tenant
+// (tenant vide : schéma par défaut)
+type AttributeSchemaRegistry interface {
+	SchemaFor(ctx context.Context, tenantID string) (*entities.AttributeSchema, error)
+}
+
+// =============================================================================
+// CREATE USER USE CASE
+// =============================================================================
+
+type CreateUserUseCase struct {
+	userRepo     repositories.UserRepository
+	passwordHash PasswordHasher
+	emailSender  EmailSender
+	publisher    EventPublisher
+	tasks        TaskRunner
+	logger       Logger
+	clock        Clock
+}
+
+func NewCreateUserUseCase(
+	userRepo repositories.UserRepository,
+	passwordHash PasswordHasher,
+	emailSender EmailSender,
+	publisher EventPublisher,
+	tasks TaskRunner,
+	logger Logger,
+	clock Clock,
+) *CreateUserUseCase {
+	return &CreateUserUseCase{
+		userRepo:     userRepo,
+		passwordHash: passwordHash,
+		emailSender:  emailSender,
+		publisher:    publisher,
+		tasks:        tasks,
+		logger:       logger,
+		clock:        clock,
+	}
+}
+
+// CreateUserRequest DTO pour l'input
+type CreateUserRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+func (req CreateUserRequest) QuotaUsage() map[string]int {
+	return map[string]int{repositories.UsageUsers: 1}
+}
+
+// CreateUserResponse DTO pour l'output
+type CreateUserResponse struct {
+	ID      int       `json:"id"`
+	Email   string    `json:"email"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+func (req CreateUserRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"email": req.Email, "name": req.Name}
+}
+
+func (uc *CreateUserUseCase) Execute(ctx context.Context, req CreateUserRequest) (*CreateUserResponse, error) {
+	// 1. Vérifier que l'email n'existe pas déjà
+	exists, err := uc.userRepo.IsEmailTaken(ctx, req.Email)
+	if err != nil {
+		return nil, newError("erreur lors de la vérification de l'email", err)
+	}
+
+	if exists {
+		return nil, errors.New("un utilisateur avec cet email existe déjà")
+	}
+
+	// 2. Créer l'entité User avec validation métier
+	user, err := entities.NewUser(req.Email, req.Name, req.Password, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Hasher le mot de passe
+	hashedPassword, err := uc.passwordHash.Hash(user.Password)
+	if err != nil {
+		return nil, newError("erreur lors du traitement du mot de passe", err)
+	}
+	user.Password = hashedPassword
+
+	// 4. Sauvegarder en base
+	createdUser, err := uc.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, newError("erreur lors de la création de l'utilisateur", err)
+	}
+
+	uc.publisher.Publish(ctx, events.UserCreated{
+		UserID:  createdUser.ID,
+		Email:   createdUser.Email,
+		Name:    createdUser.Name,
+		Created: createdUser.Created,
+	})
+
+	// 5. Envoyer email de bienvenue (asynchrone, ne doit pas faire échouer la création)
+	fields := map[string]interface{}{
+		"user_id": createdUser.ID,
+		"email":   createdUser.Email,
+	}
+	err = uc.tasks.Go(ctx, "welcome_email", func(ctx context.Context) error {
+		if err := uc.emailSender.SendWelcomeEmail(ctx, createdUser.Email, createdUser.Name); err != nil {
+			uc.logger.Error("Failed to send welcome email", err, fields)
+		}
+		return nil
+	})
+	if err != nil {
+		uc.logger.Error("Failed to schedule welcome email", err, fields)
+	}
+
+	// 6. Retourner la réponse (sans le mot de passe)
+	return &CreateUserResponse{
+		ID:      createdUser.ID,
+		Email:   createdUser.Email,
+		Name:    createdUser.Name,
+		Created: createdUser.Created,
+	}, nil
+}
+
+// =============================================================================
+// UPDATE USER USE CASE
+// =============================================================================
+
+type UpdateUserUseCase struct {
+	userRepo  repositories.UserRepository
+	schemas   AttributeSchemaRegistry
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewUpdateUserUseCase(userRepo repositories.UserRepository, schemas AttributeSchemaRegistry, publisher EventPublisher, clock Clock) *UpdateUserUseCase {
+	return &UpdateUserUseCase{
+		userRepo:  userRepo,
+		schemas:   schemas,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+type UpdateUserRequest struct {
+	ID    int    `json:"id" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+	Name  string `json:"name" validate:"required,min=2,max=100"`
+	// Attributes patch des attributs personnalisés : null supprime la clé, les clés absentes sont conservées
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// ExpectedUpdated précondition If-Match : l'écriture est conditionnée à cette date de
+	// modification (repositories.ErrUserModified sinon) ; nil = inconditionnelle
+	ExpectedUpdated *time.Time `json:"-"`
+}
+
+func (req UpdateUserRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.ID}
+}
+
+type UpdateUserResponse struct {
+	ID         int            `json:"id"`
+	Email      string         `json:"email"`
+	Name       string         `json:"name"`
+	Updated    time.Time      `json:"updated"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// Changes champs réellement modifiés ; vide si la requête ne changeait rien (aucune écriture)
+	Changes []entities.FieldChange `json:"changes"`
+}
+
+func (req UpdateUserRequest) Validate() error {
+	if req.ID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	return nil
+}
+
+// LogFields ne journalise que les clés des attributs : leurs valeurs peuvent être sensibles
+func (req UpdateUserRequest) LogFields() map[string]interface{} {
+	fields := map[string]interface{}{"user_id": req.ID, "email": req.Email, "name": req.Name}
+	if len(req.Attributes) > 0 {
+		fields["attributes"] = slices.Sorted(maps.Keys(req.Attributes))
+	}
+	return fields
+}
+
+func (uc *UpdateUserUseCase) Execute(ctx context.Context, req UpdateUserRequest) (*UpdateUserResponse, error) {
+	// 1. Récupérer l'utilisateur existant
+	user, err := uc.userRepo.GetById(ctx, req.ID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+	if err := checkExpectedUpdated(user, req.ExpectedUpdated); err != nil {
+		return nil, err
+	}
+
+	// 2. Si l'email change, vérifier qu'il n'est pas pris
+	if user.Email != req.Email {
+		exists, err := uc.userRepo.IsEmailTaken(ctx, req.Email)
+		if err != nil {
+			return nil, newError("erreur lors de la vérification de l'email", err)
+		}
+
+		if exists {
+			return nil, errors.New("cet email est déjà utilisé")
+		}
+	}
+
+	// 3. Utiliser la méthode métier de l'entité pour la mise à jour
+	now := uc.clock.Now()
+	profileChanges, err := user.UpdateUserProfile(req.Name, req.Email, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Appliquer le patch d'attributs, validé par le schéma du tenant de l'appelant
+	attributeChanges, err := patchUserAttributes(ctx, uc.schemas, user, req.Attributes, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Sauvegarder et publier uniquement ce qui a changé
+	if err := saveUserChanges(ctx, uc.userRepo, uc.publisher, user, req.ExpectedUpdated, profileChanges, attributeChanges); err != nil {
+		return nil, err
+	}
+
+	return newUpdateUserResponse(user, append(profileChanges, attributeChanges...)), nil
+}
+
+// patchUserAttributes applique un patch d'attributs avec le schéma du tenant de l'appelant
+func patchUserAttributes(ctx context.Context, schemas AttributeSchemaRegistry, user *entities.User, patch map[string]any, now time.Time) ([]entities.FieldChange, error) {
+	if len(patch) == 0 {
+		return nil, nil
+	}
+
+	actor, _ := ActorFromContext(ctx)
+	schema, err := schemas.SchemaFor(ctx, actor.TenantID)
+	if err != nil {
+		return nil, newError("erreur lors du chargement du schéma d'attributs", err)
+	}
+	return user.PatchAttributes(patch, schema, now)
+}
+
+// checkExpectedUpdated refus immédiat d'une précondition déjà périmée à la lecture ; l'écriture
+// conditionnelle (saveUserChanges) couvre une modification concurrente survenue ensuite
+func checkExpectedUpdated(user *entities.User, expectedUpdated *time.Time) error {
+	if expectedUpdated != nil && !user.Updated.Equal(*expectedUpdated) {
+		return repositories.ErrUserModified
+	}
+	return nil
+}
+
+// saveUserChanges enregistre l'utilisateur et publie un événement par groupe de champs modifiés
+// Sans aucune modification, le repository n'est pas appelé (ni Updated ni événement). Avec
+// expectedUpdated, l'écriture est conditionnelle (UpdateIfUnchanged)
+func saveUserChanges(
+	ctx context.Context,
+	userRepo repositories.UserRepository,
+	publisher EventPublisher,
+	user *entities.User,
+	expectedUpdated *time.Time,
+	profileChanges, attributeChanges []entities.FieldChange,
+) error {
+	if len(profileChanges) == 0 && len(attributeChanges) == 0 {
+		return nil
+	}
+
+	var err error
+	if expectedUpdated != nil {
+		_, err = userRepo.UpdateIfUnchanged(ctx, user, *expectedUpdated)
+	} else {
+		_, err = userRepo.Update(ctx, user)
+	}
+	if errors.Is(err, repositories.ErrUserModified) {
+		return err
+	}
+	if err != nil {
+		return newError("erreur lors de la mise à jour", err)
+	}
+
+	if len(profileChanges) > 0 {
+		publisher.Publish(ctx, events.UserProfileUpdated{
+			UserID:        user.ID,
+			Email:         user.Email,
+			Name:          user.Name,
+			ChangedFields: entities.ChangedFields(profileChanges),
+			Updated:       user.Updated,
+		})
+	}
+	if len(attributeChanges) > 0 {
+		publisher.Publish(ctx, events.UserAttributesChanged{
+			UserID:        user.ID,
+			Attributes:    user.Attributes,
+			ChangedFields: entities.ChangedFields(attributeChanges),
+			Changed:       user.Updated,
+		})
+	}
+	return nil
+}
+
+func newUpdateUserResponse(user *entities.User, changes []entities.FieldChange) *UpdateUserResponse {
+	if changes == nil {
+		changes = []entities.FieldChange{}
+	}
+	return &UpdateUserResponse{
+		ID:         user.ID,
+		Email:      user.Email,
+		Name:       user.Name,
+		Updated:    user.Updated,
+		Attributes: user.Attributes,
+		Changes:    changes,
+	}
+}
+
+// =============================================================================
+// PATCH USER USE CASE (mise à jour partielle)
+// =============================================================================
+
+// PatchUserUseCase applique un JSON Merge Patch (RFC 7396) au profil :
+// seuls les champs présents sont validés et enregistrés
+type PatchUserUseCase struct {
+	userRepo  repositories.UserRepository
+	schemas   AttributeSchemaRegistry
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewPatchUserUseCase(userRepo repositories.UserRepository, schemas AttributeSchemaRegistry, publisher EventPublisher, clock Clock) *PatchUserUseCase {
+	return &PatchUserUseCase{
+		userRepo:  userRepo,
+		schemas:   schemas,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+// PatchUserRequest un champ nil est laissé inchangé
+type PatchUserRequest struct {
+	ID    int     `json:"-"`
+	Email *string `json:"email,omitempty"`
+	Name  *string `json:"name,omitempty"`
+	// Attributes mêmes règles que pour UpdateUser : null supprime la clé
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// ExpectedUpdated précondition If-Match, comme pour UpdateUser
+	ExpectedUpdated *time.Time `json:"-"`
+}
+
+func (req PatchUserRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.ID}
+}
+
+func (req PatchUserRequest) Validate() error {
+	if req.ID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if req.Email == nil && req.Name == nil && len(req.Attributes) == 0 {
+		return errors.New("aucun champ à modifier")
+	}
+	return nil
+}
+
+// LogFields seuls les champs fournis sont journalisés (clés uniquement pour les attributs)
+func (req PatchUserRequest) LogFields() map[string]interface{} {
+	fields := map[string]interface{}{"user_id": req.ID}
+	if req.Email != nil {
+		fields["email"] = *req.Email
+	}
+	if req.Name != nil {
+		fields["name"] = *req.Name
+	}
+	if len(req.Attributes) > 0 {
+		fields["attributes"] = slices.Sorted(maps.Keys(req.Attributes))
+	}
+	return fields
+}
+
+func (uc *PatchUserUseCase) Execute(ctx context.Context, req PatchUserRequest) (*UpdateUserResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, req.ID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+	if err := checkExpectedUpdated(user, req.ExpectedUpdated); err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil && !strings.EqualFold(strings.TrimSpace(*req.Email), user.Email) {
+		exists, err := uc.userRepo.IsEmailTaken(ctx, strings.ToLower(strings.TrimSpace(*req.Email)))
+		if err != nil {
+			return nil, newError("erreur lors de la vérification de l'email", err)
+		}
+		if exists {
+			return nil, errors.New("cet email est déjà utilisé")
+		}
+	}
+
+	now := uc.clock.Now()
+	profileChanges, err := user.PatchProfile(req.Name, req.Email, now)
+	if err != nil {
+		return nil, err
+	}
+	attributeChanges, err := patchUserAttributes(ctx, uc.schemas, user, req.Attributes, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveUserChanges(ctx, uc.userRepo, uc.publisher, user, req.ExpectedUpdated, profileChanges, attributeChanges); err != nil {
+		return nil, err
+	}
+
+	return newUpdateUserResponse(user, append(profileChanges, attributeChanges...)), nil
+}
+
+// =============================================================================
+// DELETE USER USE CASE
+// =============================================================================
+
+type DeleteUserUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewDeleteUserUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *DeleteUserUseCase {
+	return &DeleteUserUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, id int) error {
+	// 1. Vérifier que l'utilisateur existe
+	_, err := uc.userRepo.GetById(ctx, id)
+	if err != nil {
+		return newError("utilisateur non trouvé", err)
+	}
+
+	// 2. Supprimer l'utilisateur
+	if err := uc.userRepo.DeleteById(ctx, id); err != nil {
+		return newError("erreur lors de la suppression", err)
+	}
+
+	uc.publisher.Publish(ctx, events.UserDeleted{UserID: id, Deleted: uc.clock.Now()})
+
+	return nil
+}
+
+// =============================================================================
+// DEACTIVATE / REACTIVATE USER USE CASES (cycle de vie du compte)
+// =============================================================================
+
+type DeactivateUserUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewDeactivateUserUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *DeactivateUserUseCase {
+	return &DeactivateUserUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+// DeactivateUserRequest Ban bannit le compte au lieu de le désactiver (modération)
+type DeactivateUserRequest struct {
+	UserID int    `json:"user_id"`
+	Reason string `json:"reason"`
+	Ban    bool   `json:"ban"`
+}
+
+func (req DeactivateUserRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type UserStatusResponse struct {
+	UserID  int       `json:"user_id"`
+	Status  string    `json:"status"`
+	Updated time.Time `json:"updated"`
+}
+
+func (req DeactivateUserRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	if len(req.Reason) > 500 {
+		return errors.New("motif trop long (max 500 caractères)")
+	}
+	return nil
+}
+
+func (req DeactivateUserRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "ban": req.Ban}
+}
+
+func (uc *DeactivateUserUseCase) Execute(ctx context.Context, req DeactivateUserRequest) (*UserStatusResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	changed, err := user.Deactivate(req.Ban, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Déjà dans l'état demandé : idempotent, aucun événement
+	if changed {
+		if _, err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, newError("erreur lors de la mise à jour du statut", err)
+		}
+
+		uc.publisher.Publish(ctx, events.UserStatusChanged{
+			UserID:  user.ID,
+			Status:  string(user.CurrentStatus()),
+			Reason:  req.Reason,
+			Changed: user.Updated,
+		})
+	}
+
+	return &UserStatusResponse{UserID: user.ID, Status: string(user.CurrentStatus()), Updated: user.Updated}, nil
+}
+
+type ReactivateUserUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewReactivateUserUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *ReactivateUserUseCase {
+	return &ReactivateUserUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+func (uc *ReactivateUserUseCase) Execute(ctx context.Context, userID int) (*UserStatusResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, userID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	if err := user.Reactivate(uc.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, newError("erreur lors de la mise à jour du statut", err)
+	}
+
+	uc.publisher.Publish(ctx, events.UserStatusChanged{
+		UserID:  user.ID,
+		Status:  string(user.CurrentStatus()),
+		Changed: user.Updated,
+	})
+
+	return &UserStatusResponse{UserID: user.ID, Status: string(user.CurrentStatus()), Updated: user.Updated}, nil
+}
+
+// =============================================================================
+// HANDLES : DISPONIBILITÉ ET CHOIX
+// =============================================================================
+
+type CheckHandleAvailabilityUseCase struct {
+	userRepo repositories.UserRepository
+}
+
+func NewCheckHandleAvailabilityUseCase(userRepo repositories.UserRepository) *CheckHandleAvailabilityUseCase {
+	return &CheckHandleAvailabilityUseCase{userRepo: userRepo}
+}
+
+// Motifs d'indisponibilité d'un handle
+const (
+	HandleUnavailableInvalid  = "invalid"
+	HandleUnavailableReserved = "reserved"
+	HandleUnavailableTaken    = "taken"
+)
+
+// HandleAvailabilityResponse Handle est la forme normalisée (vide si le format est invalide)
+type HandleAvailabilityResponse struct {
+	Handle    string `json:"handle"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Execute est indicatif : le handle peut être pris entre la vérification et le choix,
+// SetUserHandleUseCase refait le contrôle
+func (uc *CheckHandleAvailabilityUseCase) Execute(ctx context.Context, raw string) (*HandleAvailabilityResponse, error) {
+	handle, err := entities.NormalizeHandle(raw)
+	switch {
+	case errors.Is(err, entities.ErrReservedHandle):
+		return &HandleAvailabilityResponse{Reason: HandleUnavailableReserved}, nil
+	case err != nil:
+		return &HandleAvailabilityResponse{Reason: HandleUnavailableInvalid}, nil
+	}
+
+	taken, err := uc.userRepo.IsHandleTaken(ctx, handle)
+	if err != nil {
+		return nil, newError("erreur lors de la vérification du handle", err)
+	}
+	if taken {
+		return &HandleAvailabilityResponse{Handle: handle, Reason: HandleUnavailableTaken}, nil
+	}
+	return &HandleAvailabilityResponse{Handle: handle, Available: true}, nil
+}
+
+type SetUserHandleUseCase struct {
+	userRepo  repositories.UserRepository
+	publisher EventPublisher
+	clock     Clock
+}
+
+func NewSetUserHandleUseCase(userRepo repositories.UserRepository, publisher EventPublisher, clock Clock) *SetUserHandleUseCase {
+	return &SetUserHandleUseCase{
+		userRepo:  userRepo,
+		publisher: publisher,
+		clock:     clock,
+	}
+}
+
+// SetUserHandleRequest Handle vide retire le handle de l'utilisateur
+type SetUserHandleRequest struct {
+	UserID int    `json:"user_id"`
+	Handle string `json:"handle"`
+}
+
+func (req SetUserHandleRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID}
+}
+
+type SetUserHandleResponse struct {
+	UserID  int       `json:"user_id"`
+	Handle  string    `json:"handle"`
+	Updated time.Time `json:"updated"`
+}
+
+func (req SetUserHandleRequest) Validate() error {
+	if req.UserID <= 0 {
+		return errors.New("identifiant utilisateur invalide")
+	}
+	return nil
+}
+
+func (req SetUserHandleRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"user_id": req.UserID, "handle": req.Handle}
+}
+
+func (uc *SetUserHandleUseCase) Execute(ctx context.Context, req SetUserHandleRequest) (*SetUserHandleResponse, error) {
+	user, err := uc.userRepo.GetById(ctx, req.UserID)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	changed, err := user.SetHandle(req.Handle, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return &SetUserHandleResponse{UserID: user.ID, Handle: user.Handle, Updated: user.Updated}, nil
+	}
+
+	if user.Handle != "" {
+		taken, err := uc.userRepo.IsHandleTaken(ctx, user.Handle)
+		if err != nil {
+			return nil, newError("erreur lors de la vérification du handle", err)
+		}
+		if taken {
+			return nil, repositories.ErrHandleTaken
+		}
+	}
+
+	// Le dépôt refait le contrôle d'unicité au moment de l'écriture (course entre deux choix)
+	if _, err := uc.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, repositories.ErrHandleTaken) {
+			return nil, err
+		}
+		return nil, newError("erreur lors de la mise à jour du handle", err)
+	}
+
+	uc.publisher.Publish(ctx, events.UserHandleChanged{
+		UserID:  user.ID,
+		Handle:  user.Handle,
+		Changed: user.Updated,
+	})
+
+	return &SetUserHandleResponse{UserID: user.ID, Handle: user.Handle, Updated: user.Updated}, nil
+}
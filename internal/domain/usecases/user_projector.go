@@ -0,0 +1,87 @@
+// internal/domain/usecases/user_projector.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+)
+
+// =============================================================================
+// USER PROJECTOR : alimente le modèle de lecture à partir des événements
+// =============================================================================
+
+type UserProjector struct {
+	readRepo repositories.UserReadRepository
+}
+
+func NewUserProjector(readRepo repositories.UserReadRepository) *UserProjector {
+	return &UserProjector{readRepo: readRepo}
+}
+
+// Handle applique un événement au modèle de lecture
+// Les événements inconnus sont ignorés : un projecteur ne s'intéresse qu'à ce qu'il projette
+func (p *UserProjector) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.UserCreated:
+		return p.readRepo.Save(ctx, &repositories.UserView{
+			ID:      e.UserID,
+			Email:   e.Email,
+			Name:    e.Name,
+			Created: e.Created,
+			Updated: e.Created,
+			Status:  string(entities.UserStatusActive),
+		})
+	case events.UserProfileUpdated:
+		view, err := p.readRepo.GetById(ctx, e.UserID)
+		if err != nil {
+			return err
+		}
+		view.Email = e.Email
+		view.Name = e.Name
+		view.Updated = e.Updated
+		return p.readRepo.Save(ctx, view)
+	case events.UserStatusChanged:
+		view, err := p.readRepo.GetById(ctx, e.UserID)
+		if err != nil {
+			return err
+		}
+		view.Status = e.Status
+		view.Updated = e.Changed
+		return p.readRepo.Save(ctx, view)
+	case events.UserHandleChanged:
+		view, err := p.readRepo.GetById(ctx, e.UserID)
+		if err != nil {
+			return err
+		}
+		view.Handle = e.Handle
+		view.Updated = e.Changed
+		return p.readRepo.Save(ctx, view)
+	case events.UserLoggedIn:
+		view, err := p.readRepo.GetById(ctx, e.UserID)
+		if err != nil {
+			return err
+		}
+		at := e.At
+		view.LastLoginAt = &at
+		return p.readRepo.Save(ctx, view)
+	case events.UserAttributesChanged:
+		view, err := p.readRepo.GetById(ctx, e.UserID)
+		if err != nil {
+			return err
+		}
+		view.Attributes = e.Attributes
+		view.Updated = e.Changed
+		return p.readRepo.Save(ctx, view)
+	case events.UserDeleted:
+		err := p.readRepo.DeleteById(ctx, e.UserID)
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil // Idempotent : la vue est déjà à jour
+		}
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,479 @@
+// internal/domain/usecases/user_queries.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Les use cases de lecture (côté query du CQRS) ne lisent que le modèle de lecture
+// alimenté par UserProjector : ils ne touchent jamais au stockage d'écriture
+
+// =============================================================================
+// GET USER USE CASE
+// =============================================================================
+
+type GetUserUseCase struct {
+	readRepo repositories.UserReadRepository
+}
+
+func NewGetUserUseCase(readRepo repositories.UserReadRepository) *GetUserUseCase {
+	return &GetUserUseCase{
+		readRepo: readRepo,
+	}
+}
+
+type GetUserResponse struct {
+	ID      int       `json:"id"`
+	Email   string    `json:"email"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	Status  string    `json:"status"`
+	Handle  string    `json:"handle,omitempty"`
+	// LastLoginAt absent si le compte ne s'est jamais connecté
+	LastLoginAt *time.Time     `json:"last_login_at,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+// newGetUserResponse DTO commun à toutes les lectures d'un utilisateur
+func newGetUserResponse(user *repositories.UserView) *GetUserResponse {
+	return &GetUserResponse{
+		ID:          user.ID,
+		Email:       user.Email,
+		Name:        user.Name,
+		Created:     user.Created,
+		Updated:     user.Updated,
+		Status:      user.Status,
+		Handle:      user.Handle,
+		LastLoginAt: user.LastLoginAt,
+		Attributes:  user.Attributes,
+	}
+}
+
+func (uc *GetUserUseCase) ExecuteByID(ctx context.Context, id int) (*GetUserResponse, error) {
+	user, err := uc.readRepo.GetById(ctx, id)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	return newGetUserResponse(user), nil
+}
+
+func (uc *GetUserUseCase) ExecuteByEmail(ctx context.Context, email string) (*GetUserResponse, error) {
+	user, err := uc.readRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	return newGetUserResponse(user), nil
+}
+
+// ExecuteByHandle accepte le handle avec ou sans "@", quelle que soit la casse
+func (uc *GetUserUseCase) ExecuteByHandle(ctx context.Context, raw string) (*GetUserResponse, error) {
+	handle, err := entities.NormalizeHandle(raw)
+	if err != nil {
+		// Un handle invalide ou réservé ne peut appartenir à personne
+		return nil, newError("utilisateur non trouvé", repositories.ErrUserNotFound)
+	}
+
+	user, err := uc.readRepo.GetByHandle(ctx, handle)
+	if err != nil {
+		return nil, newError("utilisateur non trouvé", err)
+	}
+
+	return newGetUserResponse(user), nil
+}
+
+// =============================================================================
+// LIST USERS USE CASE (avec pagination)
+// =============================================================================
+
+type ListUsersUseCase struct {
+	readRepo repositories.UserReadRepository
+	flags    FeatureFlags
+	// estimateTotals total sans filtre lu par EstimateCount : sur une grande table, COUNT(*)
+	// coûte plus cher que la page elle-même
+	estimateTotals bool
+}
+
+func NewListUsersUseCase(readRepo repositories.UserReadRepository, flags FeatureFlags, estimateTotals bool) *ListUsersUseCase {
+	return &ListUsersUseCase{
+		readRepo:       readRepo,
+		flags:          flags,
+		estimateTotals: estimateTotals,
+	}
+}
+
+type ListUsersRequest struct {
+	Page     int `json:"page" validate:"min=1"`
+	PageSize int `json:"page_size" validate:"min=1,max=100"`
+	// Cursor remplace Page quand FlagCursorPagination est actif
+	Cursor string `json:"cursor,omitempty"`
+	// Status restreint la liste à un statut de compte ; vide = tous
+	Status string `json:"status,omitempty"`
+	// SortBy critère de tri parmi repositories.UserSortFields (défaut "created") ; Order "asc"
+	// (défaut) ou "desc". Comme Status, à répéter à chaque page d'un parcours par curseur
+	SortBy string `json:"sort_by,omitempty"`
+	Order  string `json:"order,omitempty"`
+}
+
+// Sens de tri des listes
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+type ListUsersResponse struct {
+	Users      []*GetUserResponse `json:"users"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalPages int                `json:"total_pages"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	// SortBy / Order tri appliqué (valeurs par défaut comprises)
+	SortBy string `json:"sort_by"`
+	Order  string `json:"order"`
+	// TotalEstimated Total (et donc TotalPages) est une estimation du stockage
+	TotalEstimated bool `json:"total_estimated,omitempty"`
+}
+
+func (req ListUsersRequest) Validate() error {
+	if req.Page < 0 {
+		return errors.New("page invalide")
+	}
+	if req.PageSize < 0 || req.PageSize > 100 {
+		return errors.New("page_size doit être compris entre 1 et 100")
+	}
+	if req.Status != "" {
+		if _, err := entities.ParseUserStatus(req.Status); err != nil {
+			return err
+		}
+	}
+	if _, err := req.sort(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (req ListUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"page": req.Page, "page_size": req.PageSize, "status": req.Status,
+		"sort_by": req.SortBy, "order": req.Order}
+}
+
+// sort tri demandé, validé contre la liste des critères indexés par le modèle de lecture
+func (req ListUsersRequest) sort() (repositories.UserSort, error) {
+	order := repositories.DefaultUserSort
+	if req.SortBy != "" {
+		order.Field = repositories.UserSortField(req.SortBy)
+		if !slices.Contains(repositories.UserSortFields, order.Field) {
+			return order, errors.New("sort_by doit valoir created, name ou email")
+		}
+	}
+	switch req.Order {
+	case "", SortAscending:
+	case SortDescending:
+		order.Descending = true
+	default:
+		return order, errors.New("order doit valoir asc ou desc")
+	}
+	return order, nil
+}
+
+func (uc *ListUsersUseCase) Execute(ctx context.Context, req ListUsersRequest) (*ListUsersResponse, error) {
+	// Valeurs par défaut
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	// Calculer offset
+	offset := (req.Page - 1) * req.PageSize
+
+	// Nouveau mode de pagination, activé progressivement par feature flag
+	cursorMode := uc.flags.IsEnabled(ctx, FlagCursorPagination)
+	if cursorMode && req.Cursor != "" {
+		cursorOffset, err := decodeListCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		offset = cursorOffset
+		req.Page = offset/req.PageSize + 1
+	}
+
+	order, err := req.sort()
+	if err != nil {
+		return nil, err
+	}
+
+	// Page et total sont lus en parallèle : la latence est celle de la plus lente des deux requêtes
+	var (
+		users     []*repositories.UserView
+		total     int
+		estimated bool
+	)
+	err = runConcurrently(ctx,
+		func(ctx context.Context) error {
+			var err error
+			if req.Status != "" {
+				users, err = uc.readRepo.ListByStatus(ctx, req.Status, order, req.PageSize, offset)
+			} else {
+				users, err = uc.readRepo.List(ctx, order, req.PageSize, offset)
+			}
+			if err != nil {
+				return newError("erreur lors de la récupération des utilisateurs", err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			var err error
+			total, estimated, err = countUsers(ctx, uc.readRepo, req.Status, uc.estimateTotals)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convertir en DTO
+	userResponses := make([]*GetUserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = newGetUserResponse(user)
+	}
+
+	// Calculer le nombre de pages
+	totalPages := (total + req.PageSize - 1) / req.PageSize
+
+	response := &ListUsersResponse{
+		Users:      userResponses,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: totalPages,
+		SortBy:     string(order.Field),
+		Order:      SortAscending,
+	}
+	response.TotalEstimated = estimated
+	if order.Descending {
+		response.Order = SortDescending
+	}
+
+	// Avec un total approché, une page pleine laisse supposer une suite
+	if cursorMode && (offset+len(users) < total || estimated && len(users) == req.PageSize) {
+		response.NextCursor = encodeListCursor(offset + len(users))
+	}
+
+	return response, nil
+}
+
+// Le curseur est opaque pour le client : l'offset encodé pourra évoluer (keyset) sans casser l'API
+func encodeListCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+func decodeListCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("curseur de pagination invalide")
+	}
+
+	value, found := strings.CutPrefix(string(raw), "o:")
+	if !found {
+		return 0, errors.New("curseur de pagination invalide")
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, errors.New("curseur de pagination invalide")
+	}
+	return offset, nil
+}
+
+// countUsers total des utilisateurs, restreint à un statut si demandé ; estimate n'est suivi que
+// sans statut (les statistiques du stockage ne portent que sur la table entière)
+func countUsers(ctx context.Context, readRepo repositories.UserReadRepository, status string, estimate bool) (int, bool, error) {
+	var total int
+	var err error
+	switch {
+	case status != "":
+		total, err = readRepo.CountByStatus(ctx, status)
+		estimate = false
+	case estimate:
+		total, err = readRepo.EstimateCount(ctx)
+	default:
+		total, err = readRepo.Count(ctx)
+	}
+	if err != nil {
+		return 0, false, newError("erreur lors du comptage des utilisateurs", err)
+	}
+	return total, estimate, nil
+}
+
+// =============================================================================
+// COUNT USERS USE CASE (HEAD /users)
+// =============================================================================
+
+// CountUsersUseCase total seul, sans lire de page : sert HEAD /users (X-Total-Count)
+type CountUsersUseCase struct {
+	readRepo       repositories.UserReadRepository
+	estimateTotals bool
+}
+
+func NewCountUsersUseCase(readRepo repositories.UserReadRepository, estimateTotals bool) *CountUsersUseCase {
+	return &CountUsersUseCase{
+		readRepo:       readRepo,
+		estimateTotals: estimateTotals,
+	}
+}
+
+type CountUsersRequest struct {
+	// Status restreint le compte à un statut de compte ; vide = tous
+	Status string `json:"status,omitempty"`
+}
+
+type CountUsersResponse struct {
+	Total     int  `json:"total"`
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+func (req CountUsersRequest) Validate() error {
+	if req.Status != "" {
+		if _, err := entities.ParseUserStatus(req.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (req CountUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"status": req.Status}
+}
+
+func (uc *CountUsersUseCase) Execute(ctx context.Context, req CountUsersRequest) (*CountUsersResponse, error) {
+	total, estimated, err := countUsers(ctx, uc.readRepo, req.Status, uc.estimateTotals)
+	if err != nil {
+		return nil, err
+	}
+	return &CountUsersResponse{Total: total, Estimated: estimated}, nil
+}
+
+// =============================================================================
+// GET USER STATS USE CASE (GET /admin/stats)
+// =============================================================================
+
+// GetUserStatsUseCase tableau de bord d'administration : chaque chiffre est un comptage du modèle
+// de lecture (par statut, par tranche de dates de création), jamais un parcours des utilisateurs
+// Aucune répartition par rôle : les rôles ne sont pas portés par les comptes, ils sont accordés à
+// chaque connexion par les groupes de l'annuaire
+type GetUserStatsUseCase struct {
+	readRepo       repositories.UserReadRepository
+	onboardingRepo repositories.OnboardingRepository
+	clock          Clock
+}
+
+func NewGetUserStatsUseCase(readRepo repositories.UserReadRepository, onboardingRepo repositories.OnboardingRepository, clock Clock) *GetUserStatsUseCase {
+	return &GetUserStatsUseCase{readRepo: readRepo, onboardingRepo: onboardingRepo, clock: clock}
+}
+
+// GetUserStatsRequest période des nouveaux comptes, comme celle des agrégats d'événements :
+// From / To RFC 3339 étendues aux jours UTC entiers (défaut : les 30 derniers jours), Interval
+// day, week ou month (défaut : day)
+type GetUserStatsRequest struct {
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Interval string `json:"interval,omitempty"`
+}
+
+// rollups période et intervalle, validés comme ceux des agrégats
+func (req GetUserStatsRequest) rollups() GetEventRollupsRequest {
+	return GetEventRollupsRequest{From: req.From, To: req.To, Interval: req.Interval}
+}
+
+func (req GetUserStatsRequest) Validate() error {
+	return req.rollups().Validate()
+}
+
+func (req GetUserStatsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"from": req.From, "to": req.To, "interval": req.Interval}
+}
+
+type NewUsersBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// NewUsersSeries comptes créés par intervalle, vides compris
+type NewUsersSeries struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Interval string           `json:"interval"`
+	Buckets  []NewUsersBucket `json:"buckets"`
+	Total    int              `json:"total"`
+}
+
+// GetUserStatsResponse Unverified comptes dont l'inscription attend la confirmation de l'adresse
+// email ; les comptes créés par un administrateur, un import, SCIM ou l'annuaire sont réputés vérifiés
+type GetUserStatsResponse struct {
+	Total      int            `json:"total"`
+	ByStatus   map[string]int `json:"by_status"`
+	Verified   int            `json:"verified"`
+	Unverified int            `json:"unverified"`
+	NewUsers   NewUsersSeries `json:"new_users"`
+}
+
+func (uc *GetUserStatsUseCase) Execute(ctx context.Context, req GetUserStatsRequest) (*GetUserStatsResponse, error) {
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+	from, to, err := req.rollups().period(uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetUserStatsResponse{ByStatus: make(map[string]int)}
+	if response.Total, err = uc.readRepo.Count(ctx); err != nil {
+		return nil, newError("erreur lors du comptage des utilisateurs", err)
+	}
+	for _, status := range []entities.UserStatus{entities.UserStatusActive, entities.UserStatusDeactivated, entities.UserStatusBanned} {
+		count, err := uc.readRepo.CountByStatus(ctx, string(status))
+		if err != nil {
+			return nil, newError("erreur lors du comptage des utilisateurs", err)
+		}
+		response.ByStatus[string(status)] = count
+	}
+
+	pending, err := uc.onboardingRepo.CountByStatus(ctx, OnboardingAwaitingConfirmation)
+	if err != nil {
+		return nil, newError("erreur lors du comptage des inscriptions", err)
+	}
+	response.Unverified = min(pending, response.Total)
+	response.Verified = response.Total - response.Unverified
+
+	response.NewUsers = NewUsersSeries{From: from, To: to, Interval: req.Interval, Buckets: []NewUsersBucket{}}
+	for start := rollupBucketStart(from, req.Interval); start.Before(to); start = rollupBucketNext(start, req.Interval) {
+		// Le premier et le dernier intervalle sont bornés par la période
+		bucketFrom, bucketTo := start, rollupBucketNext(start, req.Interval)
+		if bucketFrom.Before(from) {
+			bucketFrom = from
+		}
+		if bucketTo.After(to) {
+			bucketTo = to
+		}
+		count, err := uc.readRepo.CountCreatedBetween(ctx, bucketFrom, bucketTo)
+		if err != nil {
+			return nil, newError("erreur lors du comptage des nouveaux utilisateurs", err)
+		}
+		response.NewUsers.Buckets = append(response.NewUsers.Buckets, NewUsersBucket{Start: start, Count: count})
+		response.NewUsers.Total += count
+	}
+	return response, nil
+}
@@ -0,0 +1,185 @@
+// internal/domain/usecases/user_search_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+)
+
+// =============================================================================
+// SEARCH USERS USE CASE
+// =============================================================================
+
+// SearchUsersUseCase recherche multicritère, attributs personnalisés compris, et plein texte
+// classée par pertinence sur le nom et l'email (Query)
+// Contrairement aux autres lectures, elle interroge le stockage d'écriture : le modèle de lecture
+// n'indexe ni les sous-chaînes, ni les mots, ni les attributs (index GIN tsvector et JSONB en SQL,
+// index inversé en mémoire sinon)
+type SearchUsersUseCase struct {
+	searchRepo repositories.UserSearchRepository
+	schemas    AttributeSchemaRegistry
+}
+
+func NewSearchUsersUseCase(searchRepo repositories.UserSearchRepository, schemas AttributeSchemaRegistry) *SearchUsersUseCase {
+	return &SearchUsersUseCase{
+		searchRepo: searchRepo,
+		schemas:    schemas,
+	}
+}
+
+// maxSearchQueryLength longueur maximale du texte libre d'une recherche
+const maxSearchQueryLength = 200
+
+type SearchUsersRequest struct {
+	// Query texte libre ("jean dup") : chaque mot préfixe un mot du nom ou de l'email ; les
+	// résultats sont alors triés par pertinence au lieu de l'ID
+	Query  string `json:"query,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+	// CreatedFrom / CreatedTo bornes RFC 3339 (début inclus, fin exclue)
+	CreatedFrom string `json:"created_from,omitempty"`
+	CreatedTo   string `json:"created_to,omitempty"`
+	// Attributes valeurs brutes (paramètres d'URL), converties selon le schéma du tenant
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+}
+
+type SearchUsersResponse struct {
+	Users    []*GetUserResponse `json:"users"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+	HasMore  bool               `json:"has_more"`
+	// Facets / Highlights fournis par un moteur de recherche dédié uniquement (ELASTICSEARCH_URL) :
+	// nombre de résultats par statut et domaine d'email, extraits surlignés par ID d'utilisateur
+	Facets     map[string]map[string]int   `json:"facets,omitempty"`
+	Highlights map[int]map[string][]string `json:"highlights,omitempty"`
+}
+
+func (req SearchUsersRequest) Validate() error {
+	if req.Page < 0 {
+		return errors.New("page invalide")
+	}
+	if req.PageSize < 0 || req.PageSize > 100 {
+		return errors.New("page_size doit être compris entre 1 et 100")
+	}
+	if len(req.Query) > maxSearchQueryLength {
+		return errors.New("la recherche ne doit pas dépasser 200 caractères")
+	}
+	if req.Status != "" {
+		if _, err := entities.ParseUserStatus(req.Status); err != nil {
+			return err
+		}
+	}
+	if req.CreatedFrom != "" {
+		if _, err := time.Parse(time.RFC3339, req.CreatedFrom); err != nil {
+			return errors.New("created_from doit être une date RFC 3339")
+		}
+	}
+	if req.CreatedTo != "" {
+		if _, err := time.Parse(time.RFC3339, req.CreatedTo); err != nil {
+			return errors.New("created_to doit être une date RFC 3339")
+		}
+	}
+	return nil
+}
+
+// LogFields les critères texte et les valeurs d'attributs peuvent être personnels : seules les clés sont journalisées
+func (req SearchUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{
+		"full_text":  req.Query != "",
+		"status":     req.Status,
+		"attributes": slices.Sorted(maps.Keys(req.Attributes)),
+		"page":       req.Page,
+		"page_size":  req.PageSize,
+	}
+}
+
+func (uc *SearchUsersUseCase) Execute(ctx context.Context, req SearchUsersRequest) (*SearchUsersResponse, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	filters := repositories.UserRepositoryFilters{
+		Query:  req.Query,
+		Email:  req.Email,
+		Name:   req.Name,
+		Status: req.Status,
+		// Une ligne de plus que la page : indique s'il reste des résultats sans COUNT
+		Limit:  req.PageSize + 1,
+		Offset: (req.Page - 1) * req.PageSize,
+	}
+	if req.CreatedFrom != "" {
+		filters.CreatedAt.From = &req.CreatedFrom
+	}
+	if req.CreatedTo != "" {
+		filters.CreatedAt.To = &req.CreatedTo
+	}
+
+	if len(req.Attributes) > 0 {
+		actor, _ := ActorFromContext(ctx)
+		schema, err := uc.schemas.SchemaFor(ctx, actor.TenantID)
+		if err != nil {
+			return nil, newError("erreur lors du chargement du schéma d'attributs", err)
+		}
+		filters.Attributes = make(map[string]any, len(req.Attributes))
+		for key, raw := range req.Attributes {
+			value, err := schema.ParseValue(key, raw)
+			if err != nil {
+				return nil, err
+			}
+			filters.Attributes[key] = value
+		}
+	}
+
+	var result *repositories.UserSearchResult
+	var err error
+	if faceted, ok := uc.searchRepo.(repositories.UserFacetedSearchRepository); ok {
+		result, err = faceted.SearchWithFacets(ctx, filters)
+	} else {
+		result = &repositories.UserSearchResult{}
+		result.Users, err = uc.searchRepo.Search(ctx, filters)
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la recherche des utilisateurs", err)
+	}
+
+	users := result.Users
+	response := &SearchUsersResponse{Page: req.Page, PageSize: req.PageSize, Facets: result.Facets}
+	if len(users) > req.PageSize {
+		users = users[:req.PageSize]
+		response.HasMore = true
+	}
+
+	response.Users = make([]*GetUserResponse, len(users))
+	for i, user := range users {
+		response.Users[i] = &GetUserResponse{
+			ID:          user.ID,
+			Email:       user.Email,
+			Name:        user.Name,
+			Created:     user.Created,
+			Updated:     user.Updated,
+			Status:      string(user.CurrentStatus()),
+			Handle:      user.Handle,
+			LastLoginAt: user.LastLoginAt,
+			Attributes:  user.Attributes,
+		}
+		if highlight, ok := result.Highlights[user.ID]; ok {
+			if response.Highlights == nil {
+				response.Highlights = make(map[int]map[string][]string)
+			}
+			response.Highlights[user.ID] = highlight
+		}
+	}
+
+	return response, nil
+}
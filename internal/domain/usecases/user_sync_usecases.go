@@ -0,0 +1,414 @@
+// internal/domain/usecases/user_sync_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// PORTS DE LA SYNCHRONISATION AVEC UN SYSTÈME EXTERNE (SIRH)
+// =============================================================================
+
+// ErrUserSyncDisabled aucun fournisseur externe n'est configuré
+var ErrUserSyncDisabled = errors.New("synchronisation des utilisateurs non configurée")
+
+// ExternalUser compte externe déjà traduit dans notre vocabulaire :
+// le format du fournisseur reste dans son adaptateur (couche anti-corruption)
+type ExternalUser struct {
+	ExternalID string
+	Email      string
+	Name       string
+	// Active false pour un départ : le compte local est désactivé, jamais supprimé
+	Active bool
+}
+
+// ExternalUserProvider interface pour lire les comptes d'un système externe
+type ExternalUserProvider interface {
+	// Source identifiant stable du système, préfixe des liens enregistrés
+	Source() string
+	FetchUsers(ctx context.Context) ([]ExternalUser, error)
+}
+
+// SyncConflictPolicy arbitrage quand un champ a été modifié localement depuis la dernière synchronisation
+type SyncConflictPolicy string
+
+const (
+	// SyncExternalWins le système externe fait foi : les modifications locales sont écrasées
+	SyncExternalWins SyncConflictPolicy = "external_wins"
+	// SyncLocalWins les modifications locales sont conservées ; les autres champs suivent le système externe
+	SyncLocalWins SyncConflictPolicy = "local_wins"
+)
+
+func ParseSyncConflictPolicy(raw string) (SyncConflictPolicy, error) {
+	switch policy := SyncConflictPolicy(raw); policy {
+	case SyncExternalWins, SyncLocalWins:
+		return policy, nil
+	}
+	return "", errors.New("politique de conflit invalide (external_wins ou local_wins)")
+}
+
+// Actions rapportées pour chaque compte externe
+const (
+	SyncActionCreated   = "created"
+	SyncActionLinked    = "linked" // compte local existant rattaché par son email
+	SyncActionUpdated   = "updated"
+	SyncActionUnchanged = "unchanged"
+	SyncActionSkipped   = "skipped"
+	SyncActionFailed    = "failed"
+)
+
+// =============================================================================
+// SYNC USERS USE CASE (planifié ou déclenché à la demande)
+// =============================================================================
+
+// SyncUsersUseCase crée, modifie et désactive des comptes : déclenché par le job planifié
+// (SystemActor) ou, à la demande, par l'administration (AccessAdmin)
+type SyncUsersUseCase struct {
+	userRepo       repositories.UserRepository
+	linkRepo       repositories.ExternalUserLinkRepository
+	provider       ExternalUserProvider
+	hasher         PasswordHasher
+	publisher      EventPublisher
+	policy         SyncConflictPolicy
+	clock          Clock
+	tokenGenerator TokenGenerator
+}
+
+// NewSyncUsersUseCase provider nil : la synchronisation répond ErrUserSyncDisabled
+func NewSyncUsersUseCase(
+	userRepo repositories.UserRepository,
+	linkRepo repositories.ExternalUserLinkRepository,
+	provider ExternalUserProvider,
+	hasher PasswordHasher,
+	publisher EventPublisher,
+	policy SyncConflictPolicy,
+	clock Clock,
+	tokenGenerator TokenGenerator,
+) *SyncUsersUseCase {
+	return &SyncUsersUseCase{
+		userRepo:       userRepo,
+		linkRepo:       linkRepo,
+		provider:       provider,
+		hasher:         hasher,
+		publisher:      publisher,
+		policy:         policy,
+		clock:          clock,
+		tokenGenerator: tokenGenerator,
+	}
+}
+
+// SyncUsersRequest DryRun calcule le rapport sans rien écrire (ni utilisateur, ni lien, ni événement)
+type SyncUsersRequest struct {
+	DryRun bool      `json:"dry_run"`
+	Now    time.Time `json:"-"`
+}
+
+func (req SyncUsersRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"dry_run": req.DryRun}
+}
+
+// SyncConflict champ modifié des deux côtés (ou localement, quand le système externe fait foi)
+type SyncConflict struct {
+	Field    string `json:"field"`
+	Local    any    `json:"local"`
+	External any    `json:"external"`
+	Kept     string `json:"kept"` // "local" ou "external"
+}
+
+type SyncUserResult struct {
+	ExternalID string                 `json:"external_id"`
+	UserID     int                    `json:"user_id,omitempty"`
+	Action     string                 `json:"action"`
+	Changes    []entities.FieldChange `json:"changes,omitempty"`
+	Conflicts  []SyncConflict         `json:"conflicts,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// SyncUsersResponse Results omet les comptes inchangés sans conflit
+type SyncUsersResponse struct {
+	Source    string           `json:"source"`
+	Policy    string           `json:"policy"`
+	DryRun    bool             `json:"dry_run"`
+	Fetched   int              `json:"fetched"`
+	Created   int              `json:"created"`
+	Linked    int              `json:"linked"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Skipped   int              `json:"skipped"`
+	Failed    int              `json:"failed"`
+	Conflicts int              `json:"conflicts"`
+	Results   []SyncUserResult `json:"results"`
+}
+
+func (uc *SyncUsersUseCase) Execute(ctx context.Context, req SyncUsersRequest) (*SyncUsersResponse, error) {
+	if uc.provider == nil {
+		return nil, ErrUserSyncDisabled
+	}
+	if req.Now.IsZero() {
+		req.Now = uc.clock.Now()
+	}
+
+	externalUsers, err := uc.provider.FetchUsers(ctx)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture du système externe", err)
+	}
+
+	response := &SyncUsersResponse{
+		Source:  uc.provider.Source(),
+		Policy:  string(uc.policy),
+		DryRun:  req.DryRun,
+		Fetched: len(externalUsers),
+		Results: []SyncUserResult{},
+	}
+
+	seen := make(map[string]bool, len(externalUsers))
+	for _, external := range externalUsers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var result SyncUserResult
+		if seen[external.ExternalID] {
+			result = SyncUserResult{ExternalID: external.ExternalID, Action: SyncActionSkipped, Error: "identifiant externe en double"}
+		} else {
+			seen[external.ExternalID] = true
+			result = uc.syncOne(ctx, external, req)
+		}
+
+		response.Conflicts += len(result.Conflicts)
+		switch result.Action {
+		case SyncActionCreated:
+			response.Created++
+		case SyncActionLinked:
+			response.Linked++
+		case SyncActionUpdated:
+			response.Updated++
+		case SyncActionUnchanged:
+			response.Unchanged++
+			if len(result.Conflicts) == 0 {
+				continue
+			}
+		case SyncActionSkipped:
+			response.Skipped++
+		case SyncActionFailed:
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// syncOne rattache, crée ou met à jour le compte local d'un compte externe
+// Une erreur n'interrompt pas la synchronisation : elle est rapportée pour ce compte
+func (uc *SyncUsersUseCase) syncOne(ctx context.Context, external ExternalUser, req SyncUsersRequest) SyncUserResult {
+	result := SyncUserResult{ExternalID: external.ExternalID}
+	fail := func(err error) SyncUserResult {
+		result.Action, result.Error = SyncActionFailed, err.Error()
+		return result
+	}
+	external.Email = strings.ToLower(strings.TrimSpace(external.Email))
+	external.Name = strings.TrimSpace(external.Name)
+
+	var user *entities.User
+	link, err := uc.linkRepo.GetByExternalID(ctx, uc.provider.Source(), external.ExternalID)
+	switch {
+	case errors.Is(err, repositories.ErrExternalUserLinkNotFound):
+		user, err = uc.userRepo.GetByEmail(ctx, external.Email)
+		switch {
+		case errors.Is(err, repositories.ErrUserNotFound):
+			return uc.provision(ctx, external, req)
+		case err != nil:
+			return fail(err)
+		}
+		// Compte local existant : sans historique commun, les valeurs locales servent de référence
+		// (aucun conflit possible, les valeurs externes s'appliquent)
+		link = &repositories.ExternalUserLink{
+			Source:     uc.provider.Source(),
+			ExternalID: external.ExternalID,
+			UserID:     user.ID,
+			Email:      user.Email,
+			Name:       user.Name,
+			Active:     user.IsActive(),
+		}
+		result.Action = SyncActionLinked
+	case err != nil:
+		return fail(err)
+	default:
+		user, err = uc.userRepo.GetById(ctx, link.UserID)
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			// Supprimé localement : on ne le recrée pas dans son dos
+			result.UserID, result.Action, result.Error = link.UserID, SyncActionSkipped, "utilisateur local supprimé"
+			return result
+		}
+		if err != nil {
+			return fail(err)
+		}
+	}
+	result.UserID = user.ID
+
+	name := resolveSyncField(uc.policy, "name", user.Name, link.Name, external.Name, &result)
+	email := resolveSyncField(uc.policy, "email", user.Email, link.Email, external.Email, &result)
+
+	if email != user.Email {
+		taken, err := uc.userRepo.IsEmailTaken(ctx, email)
+		if err != nil {
+			return fail(err)
+		}
+		if taken {
+			return fail(errors.New("cet email est déjà utilisé"))
+		}
+	}
+	profileChanges, err := user.UpdateUserProfile(name, email, req.Now)
+	if err != nil {
+		return fail(err)
+	}
+	result.Changes = profileChanges
+
+	// Un compte banni relève de la modération : la synchronisation ne touche pas à son statut
+	statusChanged := false
+	if user.CurrentStatus() != entities.UserStatusBanned {
+		before := user.CurrentStatus()
+		if active := resolveSyncField(uc.policy, "active", user.IsActive(), link.Active, external.Active, &result); active != user.IsActive() {
+			if active {
+				err = user.Reactivate(req.Now)
+			} else {
+				_, err = user.Deactivate(false, req.Now)
+			}
+			if err != nil {
+				return fail(err)
+			}
+			statusChanged = true
+			result.Changes = append(result.Changes, entities.FieldChange{Field: "status", From: string(before), To: string(user.CurrentStatus())})
+		}
+	}
+
+	if result.Action == "" {
+		result.Action = SyncActionUnchanged
+		if len(result.Changes) > 0 {
+			result.Action = SyncActionUpdated
+		}
+	}
+	if req.DryRun {
+		return result
+	}
+
+	if len(result.Changes) > 0 {
+		if _, err := uc.userRepo.Update(ctx, user); err != nil {
+			return fail(err)
+		}
+		if len(profileChanges) > 0 {
+			uc.publisher.Publish(ctx, events.UserProfileUpdated{
+				UserID:        user.ID,
+				Email:         user.Email,
+				Name:          user.Name,
+				ChangedFields: entities.ChangedFields(profileChanges),
+				Updated:       user.Updated,
+			})
+		}
+		if statusChanged {
+			uc.publisher.Publish(ctx, events.UserStatusChanged{
+				UserID:  user.ID,
+				Status:  string(user.CurrentStatus()),
+				Reason:  "synchronisation " + uc.provider.Source(),
+				Changed: user.Updated,
+			})
+		}
+	}
+
+	// Les valeurs externes deviennent la référence de la prochaine synchronisation
+	link.Email, link.Name, link.Active = external.Email, external.Name, external.Active
+	link.SyncedAt = req.Now
+	if err := uc.linkRepo.Save(ctx, link); err != nil {
+		return fail(err)
+	}
+	return result
+}
+
+// provision crée le compte local d'un nouvel arrivant
+// Le mot de passe aléatoire n'est communiqué à personne : l'utilisateur devra en définir un
+func (uc *SyncUsersUseCase) provision(ctx context.Context, external ExternalUser, req SyncUsersRequest) SyncUserResult {
+	result := SyncUserResult{ExternalID: external.ExternalID, Action: SyncActionCreated}
+	if !external.Active {
+		result.Action, result.Error = SyncActionSkipped, "compte externe inactif"
+		return result
+	}
+
+	password, err := randomProvisioningPassword(uc.tokenGenerator)
+	if err != nil {
+		result.Action, result.Error = SyncActionFailed, err.Error()
+		return result
+	}
+	user, err := entities.NewUser(external.Email, external.Name, password, req.Now)
+	if err != nil {
+		result.Action, result.Error = SyncActionFailed, err.Error()
+		return result
+	}
+	result.Changes = entities.DiffProfile("", "", user.Name, user.Email)
+	if req.DryRun {
+		return result
+	}
+
+	if user.Password, err = uc.hasher.Hash(user.Password); err != nil {
+		result.Action, result.Error = SyncActionFailed, err.Error()
+		return result
+	}
+	created, err := uc.userRepo.Create(ctx, user)
+	if err != nil {
+		result.Action, result.Error = SyncActionFailed, err.Error()
+		return result
+	}
+	result.UserID = created.ID
+
+	uc.publisher.Publish(ctx, events.UserCreated{
+		UserID:  created.ID,
+		Email:   created.Email,
+		Name:    created.Name,
+		Created: created.Created,
+	})
+
+	if err := uc.linkRepo.Save(ctx, &repositories.ExternalUserLink{
+		Source:     uc.provider.Source(),
+		ExternalID: external.ExternalID,
+		UserID:     created.ID,
+		Email:      created.Email,
+		Name:       created.Name,
+		Active:     true,
+		SyncedAt:   req.Now,
+	}); err != nil {
+		result.Action, result.Error = SyncActionFailed, err.Error()
+	}
+	return result
+}
+
+// resolveSyncField valeur à retenir pour un champ, d'après sa valeur locale, sa valeur externe
+// et la valeur externe appliquée lors de la dernière synchronisation (synced)
+//   - modifié seulement côté externe : la valeur externe s'applique
+//   - modifié localement : conflit, arbitré par la politique (en local_wins, une modification
+//     locale face à une valeur externe inchangée est simplement conservée)
+func resolveSyncField[T comparable](policy SyncConflictPolicy, field string, local, synced, external T, result *SyncUserResult) T {
+	if local == external || local == synced {
+		return external
+	}
+
+	externalChanged := external != synced
+	switch {
+	case policy == SyncExternalWins:
+		result.Conflicts = append(result.Conflicts, SyncConflict{Field: field, Local: local, External: external, Kept: "external"})
+		return external
+	case externalChanged:
+		result.Conflicts = append(result.Conflicts, SyncConflict{Field: field, Local: local, External: external, Kept: "local"})
+	}
+	return local
+}
+
+// randomProvisioningPassword mot de passe d'un compte provisionné, que personne ne connaît
+func randomProvisioningPassword(tokenGenerator TokenGenerator) (string, error) {
+	return tokenGenerator.Token(24)
+}
@@ -0,0 +1,250 @@
+// internal/domain/usecases/webhook_usecases.go
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// ÉVÉNEMENTS ENTRANTS ET COMMANDES MÉTIER
+// =============================================================================
+
+// ErrWebhookEventIgnored indique qu'un événement est valide mais sans action métier associée
+var ErrWebhookEventIgnored = errors.New("webhook event ignored")
+
+// WebhookEvent représente un événement brut poussé par un système tiers
+// Le payload reste opaque : seule la couche de traduction connaît son format
+type WebhookEvent struct {
+	ID         string
+	Source     string
+	Type       string
+	OccurredAt time.Time
+	Payload    json.RawMessage
+}
+
+func (e WebhookEvent) LogFields() map[string]interface{} {
+	return map[string]interface{}{"source": e.Source, "event_id": e.ID, "event_type": e.Type}
+}
+
+// WebhookCommand est une commande métier issue de la traduction d'un événement
+type WebhookCommand interface {
+	CommandName() string
+}
+
+// UpdateUserProfileCommand demande la mise à jour du profil d'un utilisateur
+type UpdateUserProfileCommand struct {
+	UserID int
+	Email  string
+	Name   string
+}
+
+func (UpdateUserProfileCommand) CommandName() string { return "update_user_profile" }
+
+// DeleteUserCommand demande la suppression d'un utilisateur
+type DeleteUserCommand struct {
+	UserID int
+}
+
+func (DeleteUserCommand) CommandName() string { return "delete_user" }
+
+// WebhookTranslator interface pour la couche anti-corruption
+// Chaque fournisseur a son propre traducteur vers nos commandes métier
+type WebhookTranslator interface {
+	Translate(event WebhookEvent) (WebhookCommand, error)
+}
+
+// =============================================================================
+// HANDLE WEBHOOK EVENT USE CASE
+// =============================================================================
+
+// HandleWebhookEventUseCase un événement en échec est libéré pour le renvoi du fournisseur ;
+// après maxFailures échecs, il est gardé dans la file des événements abandonnés (WebhookDeadLetters)
+// et les renvois sont ignorés comme des doublons jusqu'à un rejeu explicite
+type HandleWebhookEventUseCase struct {
+	eventRepo   repositories.WebhookEventRepository
+	failures    repositories.FailedWebhookRepository
+	maxFailures int
+	translators map[string]WebhookTranslator
+	updateUser  UseCase[UpdateUserRequest, *UpdateUserResponse]
+	deleteUser  UseCase[int, struct{}]
+	// syncSubscription nil sans fournisseur de facturation configuré
+	syncSubscription  UseCase[SyncSubscriptionRequest, *SyncSubscriptionResponse]
+	markUndeliverable UseCase[MarkEmailUndeliverableRequest, *MarkEmailUndeliverableResponse]
+	clock             Clock
+	logger            Logger
+}
+
+func NewHandleWebhookEventUseCase(
+	eventRepo repositories.WebhookEventRepository,
+	failures repositories.FailedWebhookRepository,
+	maxFailures int,
+	translators map[string]WebhookTranslator,
+	updateUser UseCase[UpdateUserRequest, *UpdateUserResponse],
+	deleteUser UseCase[int, struct{}],
+	syncSubscription UseCase[SyncSubscriptionRequest, *SyncSubscriptionResponse],
+	markUndeliverable UseCase[MarkEmailUndeliverableRequest, *MarkEmailUndeliverableResponse],
+	clock Clock,
+	logger Logger,
+) *HandleWebhookEventUseCase {
+	return &HandleWebhookEventUseCase{
+		eventRepo:         eventRepo,
+		failures:          failures,
+		maxFailures:       maxFailures,
+		translators:       translators,
+		updateUser:        updateUser,
+		deleteUser:        deleteUser,
+		syncSubscription:  syncSubscription,
+		markUndeliverable: markUndeliverable,
+		clock:             clock,
+		logger:            logger,
+	}
+}
+
+// HandleWebhookEventResponse DTO pour l'output
+type HandleWebhookEventResponse struct {
+	EventID string `json:"event_id"`
+	Status  string `json:"status"` // processed | duplicate | ignored
+	Command string `json:"command,omitempty"`
+}
+
+func (uc *HandleWebhookEventUseCase) Execute(ctx context.Context, event WebhookEvent) (*HandleWebhookEventResponse, error) {
+	translator, ok := uc.translators[event.Source]
+	if !ok {
+		return nil, errors.New("source de webhook inconnue")
+	}
+
+	// 1. Dédupliquer par (source, ID) : les fournisseurs garantissent "at least once"
+	reserved, err := uc.eventRepo.Reserve(ctx, event.Source, event.ID)
+	if err != nil {
+		return nil, newError("erreur lors de l'enregistrement de l'événement", err)
+	}
+
+	if !reserved {
+		return &HandleWebhookEventResponse{EventID: event.ID, Status: "duplicate"}, nil
+	}
+
+	response, err := uc.process(ctx, translator, event)
+	if err != nil {
+		// Libérer la réservation pour que le rejeu du fournisseur soit retraité, sauf pour un
+		// événement qui vient de rejoindre la file des abandonnés
+		if !uc.recordFailure(ctx, event, err) {
+			uc.release(ctx, event)
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+// Replay retraite un événement abandonné, hors réservation ; un échec s'ajoute à son historique
+func (uc *HandleWebhookEventUseCase) Replay(ctx context.Context, event WebhookEvent) (*HandleWebhookEventResponse, error) {
+	translator, ok := uc.translators[event.Source]
+	if !ok {
+		return nil, errors.New("source de webhook inconnue")
+	}
+	response, err := uc.process(ctx, translator, event)
+	if err != nil {
+		uc.recordFailure(ctx, event, err)
+		return nil, err
+	}
+	return response, nil
+}
+
+// process étapes 2 et 3 ; un succès efface les échecs précédents de l'événement
+func (uc *HandleWebhookEventUseCase) process(ctx context.Context, translator WebhookTranslator, event WebhookEvent) (*HandleWebhookEventResponse, error) {
+	// 2. Traduire le payload en commande métier
+	command, err := translator.Translate(event)
+	if errors.Is(err, ErrWebhookEventIgnored) {
+		uc.forgetFailures(ctx, event)
+		return &HandleWebhookEventResponse{EventID: event.ID, Status: "ignored"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Exécuter la commande via les use cases existants, au nom du système : l'appel est
+	// authentifié par la signature du fournisseur, pas par un utilisateur
+	if err := uc.dispatch(ContextWithActor(ctx, SystemActor), command); err != nil {
+		return nil, err
+	}
+
+	uc.forgetFailures(ctx, event)
+	return &HandleWebhookEventResponse{
+		EventID: event.ID,
+		Status:  "processed",
+		Command: command.CommandName(),
+	}, nil
+}
+
+func (uc *HandleWebhookEventUseCase) dispatch(ctx context.Context, command WebhookCommand) error {
+	switch cmd := command.(type) {
+	case UpdateUserProfileCommand:
+		_, err := uc.updateUser.Execute(ctx, UpdateUserRequest{
+			ID:    cmd.UserID,
+			Email: cmd.Email,
+			Name:  cmd.Name,
+		})
+		return err
+	case DeleteUserCommand:
+		_, err := uc.deleteUser.Execute(ctx, cmd.UserID)
+		return err
+	case SyncSubscriptionCommand:
+		if uc.syncSubscription == nil {
+			return errors.New("facturation non configurée")
+		}
+		_, err := uc.syncSubscription.Execute(ctx, SyncSubscriptionRequest{Event: cmd.Event})
+		return err
+	case MarkEmailUndeliverableCommand:
+		_, err := uc.markUndeliverable.Execute(ctx, MarkEmailUndeliverableRequest{Email: cmd.Email, Reason: cmd.Reason})
+		return err
+	default:
+		return errors.New("commande de webhook non supportée")
+	}
+}
+
+// recordFailure ajoute l'échec à l'historique de l'événement ; true s'il est (désormais) abandonné
+func (uc *HandleWebhookEventUseCase) recordFailure(ctx context.Context, event WebhookEvent, cause error) bool {
+	failed, err := uc.failures.RecordFailure(ctx, repositories.FailedWebhookEvent{
+		Source:     event.Source,
+		EventID:    event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Payload:    event.Payload,
+	}, repositories.DeliveryFailure{At: uc.clock.Now(), Error: cause.Error()}, uc.maxFailures)
+	if err != nil {
+		uc.logger.Error("Failed to record webhook failure", err, map[string]interface{}{
+			"source":   event.Source,
+			"event_id": event.ID,
+		})
+		return false
+	}
+	if failed.DeadLettered && failed.Attempts == uc.maxFailures {
+		uc.logger.Warn("Webhook event dead-lettered", map[string]interface{}{
+			"source":   event.Source,
+			"event_id": event.ID,
+			"attempts": failed.Attempts,
+		})
+	}
+	return failed.DeadLettered
+}
+
+func (uc *HandleWebhookEventUseCase) forgetFailures(ctx context.Context, event WebhookEvent) {
+	if err := uc.failures.Delete(ctx, event.Source, event.ID); err != nil {
+		uc.logger.Error("Failed to clear webhook failures", err, map[string]interface{}{
+			"source":   event.Source,
+			"event_id": event.ID,
+		})
+	}
+}
+
+func (uc *HandleWebhookEventUseCase) release(ctx context.Context, event WebhookEvent) {
+	if err := uc.eventRepo.Release(ctx, event.Source, event.ID); err != nil {
+		uc.logger.Error("Failed to release webhook event", err, map[string]interface{}{
+			"source":   event.Source,
+			"event_id": event.ID,
+		})
+	}
+}
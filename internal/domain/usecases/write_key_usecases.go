@@ -0,0 +1,266 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// CLÉS D'ÉCRITURE : authentification de l'ingestion publique, distincte des jetons utilisateurs
+// =============================================================================
+
+// ErrInvalidWriteKey clé inconnue, révoquée ou expirée (HTTP 401)
+var ErrInvalidWriteKey = errors.New("clé d'écriture invalide")
+
+const (
+	// writeKeyPrefix préfixe des clés, pour les reconnaître (scanners de secrets, journaux)
+	writeKeyPrefix = "wk_"
+	// writeKeyBytes octets d'aléa d'une clé
+	writeKeyBytes = 16
+	// writeKeyDisplayLength caractères de la clé conservés en clair (WriteKey.Prefix)
+	writeKeyDisplayLength = len(writeKeyPrefix) + 8
+	// maxWriteKeyName longueur maximale du nom d'une clé
+	maxWriteKeyName = 100
+)
+
+func hashWriteKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+// newWriteKey tire une nouvelle clé ; la valeur en clair n'est retournée qu'une fois, à sa création
+func newWriteKey(tokens TokenGenerator, tenantID, name string, now time.Time) (*entities.WriteKey, string, error) {
+	token, err := tokens.HexToken(writeKeyBytes)
+	if err != nil {
+		return nil, "", newError("erreur lors de la génération de la clé d'écriture", err)
+	}
+	secret := writeKeyPrefix + token
+	return entities.NewWriteKey(tenantID, name, secret[:writeKeyDisplayLength], hashWriteKey(secret), now), secret, nil
+}
+
+// tenantWriteKey clé du tenant ; celle d'un autre tenant est introuvable
+func tenantWriteKey(ctx context.Context, repo repositories.WriteKeyRepository, tenantID string, id int) (*entities.WriteKey, error) {
+	key, err := repo.Get(ctx, id)
+	if errors.Is(err, repositories.ErrWriteKeyNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de la clé d'écriture", err)
+	}
+	if key.TenantID != tenantID {
+		return nil, repositories.ErrWriteKeyNotFound
+	}
+	return key, nil
+}
+
+// WriteKeyResponse clé créée ; Key, la valeur à publier dans le client, n'est plus jamais renvoyée
+type WriteKeyResponse struct {
+	*entities.WriteKey
+	Key string `json:"key"`
+}
+
+// =============================================================================
+// WRITE KEY AUTHENTICATOR
+// =============================================================================
+
+// WriteKeyAuthenticator vérifie les clés présentées à l'ingestion publique
+type WriteKeyAuthenticator struct {
+	repo  repositories.WriteKeyRepository
+	clock Clock
+}
+
+func NewWriteKeyAuthenticator(repo repositories.WriteKeyRepository, clock Clock) *WriteKeyAuthenticator {
+	return &WriteKeyAuthenticator{repo: repo, clock: clock}
+}
+
+// Authenticate clé active correspondant à secret ; ErrInvalidWriteKey sinon
+// Les événements envoyés avec la clé sont ceux du tenant, sans utilisateur (anonymes)
+func (a *WriteKeyAuthenticator) Authenticate(ctx context.Context, secret string) (*entities.WriteKey, error) {
+	if !strings.HasPrefix(secret, writeKeyPrefix) {
+		return nil, ErrInvalidWriteKey
+	}
+	key, err := a.repo.GetByHash(ctx, hashWriteKey(secret))
+	if errors.Is(err, repositories.ErrWriteKeyNotFound) {
+		return nil, ErrInvalidWriteKey
+	}
+	if err != nil {
+		return nil, newError("erreur lors de la lecture de la clé d'écriture", err)
+	}
+	if !key.Active(a.clock.Now()) {
+		return nil, ErrInvalidWriteKey
+	}
+	return key, nil
+}
+
+// =============================================================================
+// CREATE WRITE KEY USE CASE
+// =============================================================================
+
+type CreateWriteKeyUseCase struct {
+	repo   repositories.WriteKeyRepository
+	tokens TokenGenerator
+	clock  Clock
+}
+
+func NewCreateWriteKeyUseCase(repo repositories.WriteKeyRepository, tokens TokenGenerator, clock Clock) *CreateWriteKeyUseCase {
+	return &CreateWriteKeyUseCase{repo: repo, tokens: tokens, clock: clock}
+}
+
+type CreateWriteKeyRequest struct {
+	TenantID string `json:"-"`
+	// Name usage de la clé (site, application mobile...)
+	Name string `json:"name"`
+}
+
+func (req CreateWriteKeyRequest) Validate() error {
+	if strings.TrimSpace(req.TenantID) == "" {
+		return errors.New("tenant obligatoire")
+	}
+	if name := strings.TrimSpace(req.Name); name == "" || len(name) > maxWriteKeyName {
+		return errors.New("le nom est obligatoire (100 caractères max)")
+	}
+	return nil
+}
+
+func (req CreateWriteKeyRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID, "name": req.Name}
+}
+
+func (req CreateWriteKeyRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (uc *CreateWriteKeyUseCase) Execute(ctx context.Context, req CreateWriteKeyRequest) (*WriteKeyResponse, error) {
+	key, secret, err := newWriteKey(uc.tokens, req.TenantID, strings.TrimSpace(req.Name), uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Create(ctx, key); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de la clé d'écriture", err)
+	}
+	return &WriteKeyResponse{WriteKey: key, Key: secret}, nil
+}
+
+// =============================================================================
+// LIST WRITE KEYS USE CASE
+// =============================================================================
+
+type ListWriteKeysUseCase struct {
+	repo repositories.WriteKeyRepository
+}
+
+func NewListWriteKeysUseCase(repo repositories.WriteKeyRepository) *ListWriteKeysUseCase {
+	return &ListWriteKeysUseCase{repo: repo}
+}
+
+// ListWriteKeysRequest clés du tenant, expirées comprises (Expires renseigné)
+type ListWriteKeysRequest struct {
+	TenantID string `json:"-"`
+}
+
+func (req ListWriteKeysRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (req ListWriteKeysRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+type ListWriteKeysResponse struct {
+	Keys []*entities.WriteKey `json:"keys"`
+}
+
+func (uc *ListWriteKeysUseCase) Execute(ctx context.Context, req ListWriteKeysRequest) (*ListWriteKeysResponse, error) {
+	keys, err := uc.repo.ListByTenant(ctx, req.TenantID)
+	if err != nil {
+		return nil, newError("erreur lors de la lecture des clés d'écriture", err)
+	}
+	return &ListWriteKeysResponse{Keys: keys}, nil
+}
+
+// =============================================================================
+// ROTATE WRITE KEY USE CASE
+// =============================================================================
+
+// RotateWriteKeyUseCase remplace une clé par une nouvelle, de même nom ; l'ancienne reste
+// acceptée pendant le délai de grâce, le temps de déployer la nouvelle dans les clients
+type RotateWriteKeyUseCase struct {
+	repo   repositories.WriteKeyRepository
+	tokens TokenGenerator
+	clock  Clock
+	grace  time.Duration
+}
+
+func NewRotateWriteKeyUseCase(repo repositories.WriteKeyRepository, tokens TokenGenerator, clock Clock, grace time.Duration) *RotateWriteKeyUseCase {
+	return &RotateWriteKeyUseCase{repo: repo, tokens: tokens, clock: clock, grace: grace}
+}
+
+type WriteKeyRequest struct {
+	TenantID string `json:"-"`
+	ID       int    `json:"-"`
+}
+
+func (req WriteKeyRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID, "write_key_id": req.ID}
+}
+
+func (req WriteKeyRequest) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{"tenant_id": req.TenantID}
+}
+
+func (uc *RotateWriteKeyUseCase) Execute(ctx context.Context, req WriteKeyRequest) (*WriteKeyResponse, error) {
+	current, err := tenantWriteKey(ctx, uc.repo, req.TenantID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := uc.clock.Now()
+	if !current.Active(now) {
+		return nil, errors.New("clé d'écriture révoquée ou expirée")
+	}
+
+	// La nouvelle clé d'abord : un échec laisse l'ancienne active, sans fin de validité
+	key, secret, err := newWriteKey(uc.tokens, current.TenantID, current.Name, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Create(ctx, key); err != nil {
+		return nil, newError("erreur lors de l'enregistrement de la clé d'écriture", err)
+	}
+	current.ExpireAt(now.Add(uc.grace))
+	if err := uc.repo.Save(ctx, current); err != nil {
+		return nil, newError("erreur lors de l'expiration de l'ancienne clé d'écriture", err)
+	}
+	return &WriteKeyResponse{WriteKey: key, Key: secret}, nil
+}
+
+// =============================================================================
+// REVOKE WRITE KEY USE CASE
+// =============================================================================
+
+// RevokeWriteKeyUseCase expiration immédiate (clé divulguée) ; la clé reste listée
+type RevokeWriteKeyUseCase struct {
+	repo  repositories.WriteKeyRepository
+	clock Clock
+}
+
+func NewRevokeWriteKeyUseCase(repo repositories.WriteKeyRepository, clock Clock) *RevokeWriteKeyUseCase {
+	return &RevokeWriteKeyUseCase{repo: repo, clock: clock}
+}
+
+func (uc *RevokeWriteKeyUseCase) Execute(ctx context.Context, req WriteKeyRequest) error {
+	key, err := tenantWriteKey(ctx, uc.repo, req.TenantID, req.ID)
+	if err != nil {
+		return err
+	}
+	key.ExpireAt(uc.clock.Now())
+	if err := uc.repo.Save(ctx, key); err != nil {
+		return newError("erreur lors de la révocation de la clé d'écriture", err)
+	}
+	return nil
+}
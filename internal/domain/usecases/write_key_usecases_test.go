@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var writeKeyTestNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+// writeKeyTestClock horloge réglable (services.FakeClock n'est pas accessible depuis le domaine)
+type writeKeyTestClock struct{ now time.Time }
+
+func (c *writeKeyTestClock) Now() time.Time { return c.now }
+
+// writeKeyTestTokens jetons distincts et prévisibles
+type writeKeyTestTokens struct{ next int }
+
+func (g *writeKeyTestTokens) HexToken(size int) (string, error) {
+	g.next++
+	return fmt.Sprintf("%0*x", 2*size, g.next), nil
+}
+
+func (g *writeKeyTestTokens) Token(size int) (string, error) { return g.HexToken(size) }
+
+// writeKeyTestRepository dépôt minimal en mémoire (database importe ce package)
+type writeKeyTestRepository struct {
+	keys []entities.WriteKey
+	err  error
+}
+
+func (r *writeKeyTestRepository) Create(_ context.Context, key *entities.WriteKey) error {
+	key.ID = len(r.keys) + 1
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *writeKeyTestRepository) Get(_ context.Context, id int) (*entities.WriteKey, error) {
+	if id < 1 || id > len(r.keys) {
+		return nil, repositories.ErrWriteKeyNotFound
+	}
+	key := r.keys[id-1]
+	return &key, nil
+}
+
+func (r *writeKeyTestRepository) GetByHash(_ context.Context, hash string) (*entities.WriteKey, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, repositories.ErrWriteKeyNotFound
+}
+
+func (r *writeKeyTestRepository) ListByTenant(_ context.Context, tenantID string) ([]*entities.WriteKey, error) {
+	var keys []*entities.WriteKey
+	for _, key := range r.keys {
+		if key.TenantID == tenantID {
+			keys = append(keys, &key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *writeKeyTestRepository) Save(_ context.Context, key *entities.WriteKey) error {
+	if key.ID < 1 || key.ID > len(r.keys) {
+		return repositories.ErrWriteKeyNotFound
+	}
+	r.keys[key.ID-1] = *key
+	return nil
+}
+
+func TestWriteKeyAuthenticatorAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	repo := &writeKeyTestRepository{}
+	clock := &writeKeyTestClock{now: writeKeyTestNow}
+	tokens := &writeKeyTestTokens{}
+	create := NewCreateWriteKeyUseCase(repo, tokens, clock)
+	grace := time.Hour
+
+	newKey := func(tenantID string) *WriteKeyResponse {
+		t.Helper()
+		key, err := create.Execute(ctx, CreateWriteKeyRequest{TenantID: tenantID, Name: "site"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	acme := newKey("acme")
+	globex := newKey("globex")
+	revoked := newKey("acme")
+	if err := NewRevokeWriteKeyUseCase(repo, clock).Execute(ctx, WriteKeyRequest{TenantID: "acme", ID: revoked.ID}); err != nil {
+		t.Fatal(err)
+	}
+	rotated := newKey("acme")
+	replacement, err := NewRotateWriteKeyUseCase(repo, tokens, clock, grace).Execute(ctx, WriteKeyRequest{TenantID: "acme", ID: rotated.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		// at instant de la vérification (zéro = création des clés)
+		at         time.Time
+		wantTenant string
+		wantErr    error
+	}{
+		{"clé active", acme.Key, time.Time{}, "acme", nil},
+		// La clé d'un autre tenant reste la sienne : jamais celle du tenant visé par l'appelant
+		{"clé d'un autre tenant", globex.Key, time.Time{}, "globex", nil},
+		{"clé inconnue", "wk_" + strings.Repeat("f", 32), time.Time{}, "", ErrInvalidWriteKey},
+		{"clé modifiée", acme.Key[:len(acme.Key)-1] + "x", time.Time{}, "", ErrInvalidWriteKey},
+		{"clé tronquée", acme.Key[:len(acme.Key)-1], time.Time{}, "", ErrInvalidWriteKey},
+		{"préfixe seul", acme.Prefix, time.Time{}, "", ErrInvalidWriteKey},
+		{"sans préfixe wk_", strings.TrimPrefix(acme.Key, "wk_"), time.Time{}, "", ErrInvalidWriteKey},
+		{"empreinte à la place de la clé", hashWriteKey(acme.Key), time.Time{}, "", ErrInvalidWriteKey},
+		{"clé vide", "", time.Time{}, "", ErrInvalidWriteKey},
+		{"clé révoquée", revoked.Key, time.Time{}, "", ErrInvalidWriteKey},
+		{"clé remplacée, pendant le délai de grâce", rotated.Key, writeKeyTestNow.Add(grace - time.Second), "acme", nil},
+		{"clé remplacée, après le délai de grâce", rotated.Key, writeKeyTestNow.Add(grace), "", ErrInvalidWriteKey},
+		{"nouvelle clé après le délai de grâce", replacement.Key, writeKeyTestNow.Add(grace), "acme", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.now = writeKeyTestNow
+			if !tt.at.IsZero() {
+				clock.now = tt.at
+			}
+			key, err := NewWriteKeyAuthenticator(repo, clock).Authenticate(ctx, tt.secret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("erreur %v, attendu %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && key.TenantID != tt.wantTenant {
+				t.Fatalf("tenant %q, attendu %q", key.TenantID, tt.wantTenant)
+			}
+		})
+	}
+}
+
+// Une panne du dépôt n'est pas confondue avec une clé invalide : erreur technique (5xx), pas 401
+func TestWriteKeyAuthenticatorRepositoryError(t *testing.T) {
+	repo := &writeKeyTestRepository{err: errors.New("connexion perdue")}
+	_, err := NewWriteKeyAuthenticator(repo, &writeKeyTestClock{now: writeKeyTestNow}).Authenticate(context.Background(), "wk_0123456789abcdef")
+
+	var technical *Error
+	if errors.Is(err, ErrInvalidWriteKey) || !errors.As(err, &technical) {
+		t.Fatalf("erreur %v, attendu une erreur technique", err)
+	}
+}
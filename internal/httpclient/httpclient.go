@@ -0,0 +1,213 @@
+// Package httpclient clients HTTP sortants partagés par les adaptateurs (services, persistance) :
+// délai, nouveaux essais, tracing, métriques et proxy configurés une seule fois
+package httpclient
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxRetryDelay plafond d'attente entre deux essais, Retry-After compris
+const maxRetryDelay = 10 * time.Second
+
+// Metrics mesure les appels sortants par client (nom passé à Factory.Client) ;
+// status vaut 0 quand aucune réponse n'a été reçue
+type Metrics interface {
+	ObserveRequest(client, method string, status int, duration time.Duration, err error)
+}
+
+// Config réglages communs à tous les clients sortants
+type Config struct {
+	// ProxyURL proxy sortant ; vide = HTTPS_PROXY / HTTP_PROXY / NO_PROXY de l'environnement
+	ProxyURL string
+	// MaxRetries nouveaux essais des requêtes rejouables (0 = aucun)
+	MaxRetries int
+	// RetryDelay premier délai entre deux essais, doublé ensuite
+	RetryDelay time.Duration
+	// Tracer span par appel et en-tête traceparent (nil = pas de tracing)
+	Tracer usecases.Tracer
+	// Metrics nil = pas de mesure
+	Metrics Metrics
+}
+
+// Factory fabrique les clients sortants ; ils partagent un même pool de connexions.
+// Une Factory nil donne des clients sans instrumentation ni nouvel essai (outils, tests)
+type Factory struct {
+	config    Config
+	transport *http.Transport
+}
+
+func NewFactory(config Config) (*Factory, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("proxy invalide : %q", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = 200 * time.Millisecond
+	}
+	return &Factory{config: config, transport: transport}, nil
+}
+
+// ClientOptions réglages propres à un client
+type ClientOptions struct {
+	// Timeout durée maximale d'un appel, nouveaux essais compris (0 = aucune)
+	Timeout time.Duration
+	// NoRetry l'adaptateur rejoue lui-même ses appels (erreurs métier de l'API distante)
+	NoRetry bool
+}
+
+// Client client nommé (métriques, spans "http.<name>")
+func (f *Factory) Client(name string, options ClientOptions) *http.Client {
+	if f == nil {
+		return &http.Client{Timeout: options.Timeout}
+	}
+	maxRetries := f.config.MaxRetries
+	if options.NoRetry {
+		maxRetries = 0
+	}
+	return &http.Client{
+		Timeout: options.Timeout,
+		Transport: &instrumentedTransport{
+			name:       name,
+			next:       f.transport,
+			maxRetries: maxRetries,
+			retryDelay: f.config.RetryDelay,
+			tracer:     f.config.Tracer,
+			metrics:    f.config.Metrics,
+		},
+	}
+}
+
+// =============================================================================
+// TRANSPORT INSTRUMENTÉ
+// =============================================================================
+
+type instrumentedTransport struct {
+	name       string
+	next       http.RoundTripper
+	maxRetries int
+	retryDelay time.Duration
+	tracer     usecases.Tracer
+	metrics    Metrics
+}
+
+// traceParent implémenté par les spans qui se propagent (W3C Trace Context)
+type traceParent interface {
+	TraceParent() string
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	ctx := req.Context()
+	var span usecases.Span
+	if t.tracer != nil {
+		ctx, span = t.tracer.StartSpan(ctx, "http."+t.name)
+		req = req.Clone(ctx)
+		if propagated, ok := span.(traceParent); ok {
+			req.Header.Set("Traceparent", propagated.TraceParent())
+		}
+	}
+
+	resp, err := t.roundTripWithRetries(ctx, req)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	// Les 5xx comptent comme des échecs de la dépendance, les 4xx restent des réponses
+	observed := err
+	if observed == nil && status >= http.StatusInternalServerError {
+		observed = errors.New(resp.Status)
+	}
+	if t.metrics != nil {
+		t.metrics.ObserveRequest(t.name, req.Method, status, time.Since(start), observed)
+	}
+	if span != nil {
+		span.End(observed)
+	}
+	return resp, err
+}
+
+func (t *instrumentedTransport) roundTripWithRetries(ctx context.Context, req *http.Request) (*http.Response, error) {
+	replayable := t.maxRetries > 0 && idempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+		resp, err := t.next.RoundTrip(req)
+
+		retry, delay := t.shouldRetry(ctx, resp, err, attempt)
+		if !replayable || !retry {
+			return resp, err
+		}
+		if resp != nil {
+			// Connexion réutilisable : le corps est lu avant d'être fermé
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// idempotent un POST n'est rejoué qu'avec une clé d'idempotence (Stripe...)
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// shouldRetry rejoue les erreurs de transport, 429 et 502/503/504, dans la limite de maxRetries ;
+// même politique que le SDK (pkg/client) : délai doublé avec une part aléatoire, Retry-After respecté
+func (t *instrumentedTransport) shouldRetry(ctx context.Context, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= t.maxRetries || ctx.Err() != nil {
+		return false, 0
+	}
+	if err != nil {
+		return true, t.backoff(attempt)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return false, 0
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		// Une attente plus longue que le plafond dépasserait le délai du client : la réponse est rendue
+		if delay := time.Duration(seconds) * time.Second; delay <= maxRetryDelay {
+			return true, delay
+		}
+		return false, 0
+	}
+	return true, t.backoff(attempt)
+}
+
+func (t *instrumentedTransport) backoff(attempt int) time.Duration {
+	delay := min(t.retryDelay<<attempt, maxRetryDelay)
+	// Part aléatoire : des instances coupées en même temps ne reviennent pas ensemble
+	return delay/2 + mathrand.N(delay/2+1)
+}
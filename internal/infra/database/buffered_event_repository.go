@@ -0,0 +1,273 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrEventBufferFull le tampon est resté plein jusqu'à l'échéance du context de l'appelant
+	ErrEventBufferFull = errors.New("event buffer full")
+	// ErrEventBufferClosed Append après le début de Shutdown
+	ErrEventBufferClosed = errors.New("event buffer closed")
+)
+
+// bufferFlushTimeout durée maximale d'une écriture lancée par la boucle de fond : un stockage
+// bloqué ne fige ni la boucle ni Shutdown, qui l'attend
+const bufferFlushTimeout = 30 * time.Second
+
+// BufferedEventRepository décore un repositories.EventRepository pour l'ingestion analytics :
+//   - Append garde les événements en mémoire ; ils sont écrits par lots de batchSize, dès qu'un lot
+//     est complet ou au plus tard toutes les flushInterval
+//   - au-delà de maxBuffered événements en attente, Append attend qu'un lot soit écrit (contre-pression)
+//     et retourne ErrEventBufferFull à l'échéance de son context
+//   - un lot en échec reste en tête du tampon et sera réessayé : rien n'est perdu tant que l'instance tourne
+//   - List lit le stockage et y ajoute les événements en attente qui correspondent aux filtres, sans
+//     attendre ni déclencher d'écriture (voir List)
+//   - Shutdown refuse les nouveaux événements et écrit ceux qui restent
+//
+// Les ID ne sont renseignés qu'à l'écriture effective, sur des copies : l'appelant ne les voit pas
+type BufferedEventRepository struct {
+	next          repositories.EventRepository
+	logger        usecases.Logger
+	maxBuffered   int
+	batchSize     int
+	flushInterval time.Duration
+
+	mutex   sync.Mutex
+	pending []*entities.TrackedEvent
+	// writing événements en tête de pending en cours d'écriture ; written total des événements écrits
+	// puis retirés de pending : pending[i] est le written+i-ième depuis le démarrage
+	writing int
+	written int64
+	closed  bool
+	// spaceFreed fermé (puis remplacé) à chaque lot écrit : réveille les Append en attente
+	spaceFreed chan struct{}
+
+	// flushMutex un seul lot en cours d'écriture : les premiers éléments de pending restent ceux du lot
+	flushMutex sync.Mutex
+	flushNow   chan struct{}
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+func NewBufferedEventRepository(
+	next repositories.EventRepository,
+	logger usecases.Logger,
+	maxBuffered int,
+	batchSize int,
+	flushInterval time.Duration,
+) *BufferedEventRepository {
+	r := &BufferedEventRepository{
+		next:          next,
+		logger:        logger,
+		maxBuffered:   max(maxBuffered, 1),
+		batchSize:     max(batchSize, 1),
+		flushInterval: flushInterval,
+		spaceFreed:    make(chan struct{}),
+		flushNow:      make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *BufferedEventRepository) Append(ctx context.Context, events []*entities.TrackedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	// Lot plus grand que le tampon (réservoirs d'échantillonnage) : écrit directement
+	if len(events) > r.maxBuffered {
+		return r.next.Append(ctx, events)
+	}
+
+	copies := make([]*entities.TrackedEvent, len(events))
+	for i, event := range events {
+		copies[i] = event.Clone()
+	}
+	for {
+		r.mutex.Lock()
+		if r.closed {
+			r.mutex.Unlock()
+			return ErrEventBufferClosed
+		}
+		if len(r.pending)+len(copies) <= r.maxBuffered {
+			r.pending = append(r.pending, copies...)
+			full := len(r.pending) >= r.batchSize
+			r.mutex.Unlock()
+			if full {
+				r.signalFlush()
+			}
+			return nil
+		}
+		spaceFreed := r.spaceFreed
+		r.mutex.Unlock()
+
+		r.signalFlush()
+		select {
+		case <-spaceFreed:
+		case <-ctx.Done():
+			return ErrEventBufferFull
+		}
+	}
+}
+
+// List lit le stockage puis y fusionne les événements en attente qui correspondent aux filtres
+// (lecture de ses propres écritures), sans attendre le lot en cours d'écriture ni remonter ses erreurs.
+// Les événements écrits pendant la lecture ne sont comptés qu'une fois, par leur ID ; un lot encore
+// en cours d'écriture à la fin de la lecture n'apparaît que si le stockage le rend déjà.
+// À horodatage égal, les événements en attente suivent ceux du stockage (ils recevront des ID plus grands)
+func (r *BufferedEventRepository) List(ctx context.Context, filters repositories.EventFilters) ([]*entities.TrackedEvent, error) {
+	r.mutex.Lock()
+	written := r.written
+	before := append([]*entities.TrackedEvent(nil), r.pending...)
+	r.mutex.Unlock()
+
+	stored, err := r.next.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []*entities.TrackedEvent
+	r.mutex.Lock()
+	// Écrits depuis le début de la lecture : le stockage les a peut-être déjà rendus
+	if flushed := min(int(r.written-written), len(before)); flushed > 0 {
+		seen := make(map[int64]bool, len(stored))
+		for _, event := range stored {
+			seen[event.ID] = true
+		}
+		for _, event := range before[:flushed] {
+			if !seen[event.ID] && matchEventFilters(filters, event) {
+				merged = append(merged, event.Clone())
+			}
+		}
+	}
+	for _, event := range r.pending[r.writing:] {
+		if matchEventFilters(filters, event) {
+			merged = append(merged, event.Clone())
+		}
+	}
+	r.mutex.Unlock()
+	if len(merged) == 0 {
+		return stored, nil
+	}
+
+	merged = append(stored, merged...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		if !merged[i].OccurredAt.Equal(merged[j].OccurredAt) {
+			return merged[i].OccurredAt.Before(merged[j].OccurredAt)
+		}
+		return merged[i].ID != 0 && (merged[j].ID == 0 || merged[i].ID < merged[j].ID)
+	})
+	if filters.Limit > 0 && len(merged) > filters.Limit {
+		merged = merged[:filters.Limit]
+	}
+	return merged, nil
+}
+
+// Flush écrit tous les événements en attente, lot par lot ; s'arrête au premier lot en échec
+func (r *BufferedEventRepository) Flush(ctx context.Context) error {
+	r.flushMutex.Lock()
+	defer r.flushMutex.Unlock()
+
+	for {
+		r.mutex.Lock()
+		batch := append([]*entities.TrackedEvent(nil), r.pending[:min(r.batchSize, len(r.pending))]...)
+		r.writing = len(batch)
+		r.mutex.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		// Append renseigne les ID du lot : List ne lit ces événements qu'une fois l'écriture terminée
+		err := r.next.Append(ctx, batch)
+		r.mutex.Lock()
+		r.writing = 0
+		if err != nil {
+			r.mutex.Unlock()
+			return err
+		}
+		r.pending = r.pending[len(batch):]
+		r.written += int64(len(batch))
+		if len(r.pending) == 0 {
+			r.pending = nil // libère le tableau sous-jacent
+		}
+		close(r.spaceFreed)
+		r.spaceFreed = make(chan struct{})
+		r.mutex.Unlock()
+	}
+}
+
+// Pending nombre d'événements en attente d'écriture
+func (r *BufferedEventRepository) Pending() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.pending)
+}
+
+// Shutdown refuse les nouveaux événements puis écrit ceux qui restent avant l'échéance de ctx
+// (ctx.Err() si la boucle de fond n'a pas rendu la main à temps) ; en cas d'échec, Pending donne
+// le nombre d'événements perdus
+func (r *BufferedEventRepository) Shutdown(ctx context.Context) error {
+	r.mutex.Lock()
+	alreadyClosed := r.closed
+	r.closed = true
+	// Réveille les Append en attente : ils constatent la fermeture
+	close(r.spaceFreed)
+	r.spaceFreed = make(chan struct{})
+	r.mutex.Unlock()
+
+	if !alreadyClosed {
+		close(r.stop)
+	}
+	select {
+	case <-r.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return r.Flush(ctx)
+}
+
+func (r *BufferedEventRepository) signalFlush() {
+	select {
+	case r.flushNow <- struct{}{}:
+	default: // une écriture est déjà demandée
+	}
+}
+
+// run écrit un lot dès qu'il est complet, et le reste à chaque période
+func (r *BufferedEventRepository) run() {
+	defer close(r.stopped)
+
+	var tick <-chan time.Time
+	if r.flushInterval > 0 {
+		ticker := time.NewTicker(r.flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-tick:
+		case <-r.flushNow:
+		}
+		r.flushWithTimeout()
+	}
+}
+
+func (r *BufferedEventRepository) flushWithTimeout() {
+	ctx, cancel := context.WithTimeout(context.Background(), bufferFlushTimeout)
+	defer cancel()
+	if err := r.Flush(ctx); err != nil {
+		r.logger.Error("Event buffer flush failed", err, map[string]interface{}{"pending": r.Pending()})
+	}
+}
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+var bufferTestNow = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+// bufferTestStore stockage en mémoire dont les écritures peuvent bloquer (appending reçoit un signal,
+// puis l'écriture attend resume) ou échouer, et dont la lecture peut déclencher une écriture concurrente
+type bufferTestStore struct {
+	*InMemoryEventRepository
+	appendErr  error
+	appending  chan struct{}
+	resume     chan struct{}
+	beforeList func()
+	afterList  func()
+}
+
+func (s *bufferTestStore) Append(ctx context.Context, events []*entities.TrackedEvent) error {
+	if s.resume != nil {
+		s.appending <- struct{}{}
+		<-s.resume
+	}
+	if s.appendErr != nil {
+		return s.appendErr
+	}
+	return s.InMemoryEventRepository.Append(ctx, events)
+}
+
+func (s *bufferTestStore) List(ctx context.Context, filters repositories.EventFilters) ([]*entities.TrackedEvent, error) {
+	if s.beforeList != nil {
+		s.beforeList()
+	}
+	events, err := s.InMemoryEventRepository.List(ctx, filters)
+	if s.afterList != nil {
+		s.afterList()
+	}
+	return events, err
+}
+
+type bufferTestLogger struct{}
+
+func (bufferTestLogger) Info(string, map[string]interface{})         {}
+func (bufferTestLogger) Warn(string, map[string]interface{})         {}
+func (bufferTestLogger) Error(string, error, map[string]interface{}) {}
+
+func newBufferTest(t *testing.T, store *bufferTestStore) *BufferedEventRepository {
+	t.Helper()
+	// Ni période ni lot complet : seul un Flush explicite écrit
+	buffer := NewBufferedEventRepository(store, bufferTestLogger{}, 100, 100, 0)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = buffer.Shutdown(ctx)
+	})
+	return buffer
+}
+
+func bufferTestEvent(name string, offset time.Duration) *entities.TrackedEvent {
+	return &entities.TrackedEvent{Name: name, UserID: 1, OccurredAt: bufferTestNow.Add(offset), SampleRate: 1}
+}
+
+func bufferTestNames(events []*entities.TrackedEvent) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.Name
+	}
+	return names
+}
+
+func checkBufferTestNames(t *testing.T, events []*entities.TrackedEvent, want ...string) {
+	t.Helper()
+	if got := bufferTestNames(events); !slices.Equal(got, want) {
+		t.Fatalf("événements %v, attendu %v", got, want)
+	}
+}
+
+// List rend le stockage et les événements en attente, triés, sans attendre une écriture bloquée
+func TestBufferedEventRepositoryListMergesPending(t *testing.T) {
+	ctx := context.Background()
+	store := &bufferTestStore{InMemoryEventRepository: NewInMemoryEventRepository()}
+	if err := store.Append(ctx, []*entities.TrackedEvent{bufferTestEvent("stored_1", 0), bufferTestEvent("stored_3", 2*time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	buffer := newBufferTest(t, store)
+	if err := buffer.Append(ctx, []*entities.TrackedEvent{
+		bufferTestEvent("pending_2", time.Minute),
+		bufferTestEvent("pending_3", 2*time.Minute),
+		bufferTestEvent("other", time.Minute),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	store.appending, store.resume = make(chan struct{}), make(chan struct{})
+	flushed := make(chan error, 1)
+	go func() { flushed <- buffer.Flush(context.Background()) }()
+	<-store.appending
+
+	listed := make(chan []*entities.TrackedEvent, 1)
+	go func() {
+		events, err := buffer.List(ctx, repositories.EventFilters{Names: []string{"stored_1", "stored_3", "pending_2", "pending_3"}})
+		if err != nil {
+			t.Error(err)
+		}
+		listed <- events
+	}()
+	select {
+	case events := <-listed:
+		// Lot en cours d'écriture : absent tant que le stockage ne le rend pas
+		checkBufferTestNames(t, events, "stored_1", "stored_3")
+	case <-time.After(time.Second):
+		t.Fatal("List attend l'écriture en cours")
+	}
+
+	close(store.resume)
+	if err := <-flushed; err != nil {
+		t.Fatal(err)
+	}
+	store.resume = nil
+	if err := buffer.Append(ctx, []*entities.TrackedEvent{bufferTestEvent("pending_4", time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := buffer.List(ctx, repositories.EventFilters{Names: []string{"stored_1", "stored_3", "pending_2", "pending_3", "pending_4"}, Limit: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// À horodatage égal, l'événement en attente suit ceux du stockage
+	checkBufferTestNames(t, events, "stored_1", "pending_2", "pending_4", "stored_3")
+}
+
+// Une écriture en échec (événements d'autres appelants) n'empêche pas la lecture
+func TestBufferedEventRepositoryListIgnoresWriteErrors(t *testing.T) {
+	ctx := context.Background()
+	store := &bufferTestStore{InMemoryEventRepository: NewInMemoryEventRepository(), appendErr: errors.New("connection refused")}
+	buffer := newBufferTest(t, store)
+	if err := buffer.Append(ctx, []*entities.TrackedEvent{bufferTestEvent("pending", 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := buffer.Flush(ctx); err == nil {
+		t.Fatal("écriture en échec non signalée")
+	}
+
+	events, err := buffer.List(ctx, repositories.EventFilters{})
+	if err != nil {
+		t.Fatalf("erreur d'écriture remontée par List : %v", err)
+	}
+	checkBufferTestNames(t, events, "pending")
+	if events[0].ID != 0 {
+		t.Fatalf("ID %d avant écriture", events[0].ID)
+	}
+}
+
+// Un lot écrit pendant la lecture est rendu une seule fois, que le stockage le voie déjà ou non
+func TestBufferedEventRepositoryListDuringFlush(t *testing.T) {
+	for _, visible := range []bool{true, false} {
+		ctx := context.Background()
+		store := &bufferTestStore{InMemoryEventRepository: NewInMemoryEventRepository()}
+		buffer := newBufferTest(t, store)
+		if err := buffer.Append(ctx, []*entities.TrackedEvent{bufferTestEvent("a", 0), bufferTestEvent("b", time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+
+		flush := func() {
+			if err := buffer.Flush(ctx); err != nil {
+				t.Error(err)
+			}
+		}
+		if visible {
+			// Écrit avant que le stockage ne soit lu
+			store.beforeList = flush
+		} else {
+			// Écrit juste après la lecture du stockage
+			store.afterList = flush
+		}
+
+		events, err := buffer.List(ctx, repositories.EventFilters{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkBufferTestNames(t, events, "a", "b")
+		if events[0].ID == 0 || events[1].ID == 0 {
+			t.Fatalf("ID non renseignés : %+v", events)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package database
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/awssig"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DynamoDBConfig accès à la table (API JSON DynamoDB_20120810)
+type DynamoDBConfig struct {
+	Region string
+	// Endpoint vide : point d'accès régional ; sinon DynamoDB Local ou un proxy (http://localhost:8000)
+	Endpoint    string
+	Table       string
+	Credentials awssig.Credentials
+	// Clients sans nouvel essai : do rejoue lui-même les refus de débit propres à DynamoDB
+	Clients *httpclient.Factory
+}
+
+// DynamoDBClient appels à l'API JSON de DynamoDB, sans dépendance au SDK AWS :
+// seules les opérations sur les éléments, les requêtes et la création de table sont utilisées
+type DynamoDBClient struct {
+	config DynamoDBConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewDynamoDBClient(config DynamoDBConfig) *DynamoDBClient {
+	if config.Endpoint == "" {
+		config.Endpoint = "https://dynamodb." + config.Region + ".amazonaws.com"
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &DynamoDBClient{
+		config: config,
+		client: config.Clients.Client("dynamodb", httpclient.ClientOptions{Timeout: 5 * time.Second, NoRetry: true}),
+		now:    time.Now,
+	}
+}
+
+// Table nom de la table unique de l'application
+func (c *DynamoDBClient) Table() string { return c.config.Table }
+
+// dynamoDBError réponse d'erreur de l'API ({"__type": "...#ConditionalCheckFailedException", "message": ...})
+type dynamoDBError struct {
+	Status  int
+	Type    string // sans le préfixe d'espace de noms
+	Message string
+	// CancellationReasons une raison par élément de TransactWriteItems ("None" s'il n'est pas en cause)
+	CancellationReasons []dynamoDBCancellationReason
+}
+
+type dynamoDBCancellationReason struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+func (e *dynamoDBError) Error() string {
+	return fmt.Sprintf("dynamodb: statut %d : %s (%s)", e.Status, e.Message, e.Type)
+}
+
+// retryable limitation de débit ou erreur du service : la même requête peut être renvoyée
+func (e *dynamoDBError) retryable() bool {
+	switch e.Type {
+	case "ProvisionedThroughputExceededException", "ThrottlingException", "RequestLimitExceeded", "InternalServerError":
+		return true
+	}
+	return e.Status >= 500
+}
+
+// dynamoDBMaxAttempts essais par requête limitée en débit (attente de 50 ms, puis 100 ms...)
+const dynamoDBMaxAttempts = 4
+
+// do appelle l'opération (PutItem, Query...) et décode la réponse dans out (nil : réponse ignorée)
+func (c *DynamoDBClient) do(ctx context.Context, operation string, body, out interface{}) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	backoff := 50 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := c.send(ctx, operation, encoded, out)
+		var failure *dynamoDBError
+		if err == nil || !errors.As(err, &failure) || !failure.retryable() || attempt == dynamoDBMaxAttempts {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *DynamoDBClient) send(ctx context.Context, operation string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "DynamoDB_20120810."+operation)
+	awssig.Sign(req, body, "dynamodb", c.config.Region, c.config.Credentials, c.now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Type                string                       `json:"__type"`
+			Message             string                       `json:"message"`
+			MessageUpper        string                       `json:"Message"`
+			CancellationReasons []dynamoDBCancellationReason `json:"CancellationReasons"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&failure)
+		message := failure.Message
+		if message == "" {
+			message = failure.MessageUpper
+		}
+		errorType := failure.Type
+		if i := strings.LastIndex(errorType, "#"); i >= 0 {
+			errorType = errorType[i+1:]
+		}
+		return &dynamoDBError{Status: resp.StatusCode, Type: errorType, Message: message, CancellationReasons: failure.CancellationReasons}
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("dynamodb: réponse invalide : %w", err)
+		}
+	}
+	return nil
+}
+
+// EnsureTable crée la table et ses index secondaires si elle n'existe pas (DynamoDB Local, environnements
+// éphémères) ; en production la table est provisionnée avec l'infrastructure
+func (c *DynamoDBClient) EnsureTable(ctx context.Context) error {
+	stringKey := func(name string) map[string]string {
+		return map[string]string{"AttributeName": name, "AttributeType": "S"}
+	}
+	index := func(name, partitionKey, sortKey string) map[string]interface{} {
+		return map[string]interface{}{
+			"IndexName": name,
+			"KeySchema": []map[string]string{
+				{"AttributeName": partitionKey, "KeyType": "HASH"},
+				{"AttributeName": sortKey, "KeyType": "RANGE"},
+			},
+			"Projection": map[string]string{"ProjectionType": "ALL"},
+		}
+	}
+
+	err := c.do(ctx, "CreateTable", map[string]interface{}{
+		"TableName":   c.config.Table,
+		"BillingMode": "PAY_PER_REQUEST",
+		"AttributeDefinitions": []map[string]string{
+			stringKey(dynamoPartitionKey), stringKey(dynamoSortKey),
+			stringKey(dynamoEmailIndexKey), stringKey(dynamoEmailIndexSort),
+			stringKey(dynamoUsersIndexKey),
+			{"AttributeName": dynamoUsersIndexSort, "AttributeType": "N"},
+		},
+		"KeySchema": []map[string]string{
+			{"AttributeName": dynamoPartitionKey, "KeyType": "HASH"},
+			{"AttributeName": dynamoSortKey, "KeyType": "RANGE"},
+		},
+		"GlobalSecondaryIndexes": []map[string]interface{}{
+			index(dynamoEmailIndex, dynamoEmailIndexKey, dynamoEmailIndexSort),
+			index(dynamoUsersIndex, dynamoUsersIndexKey, dynamoUsersIndexSort),
+		},
+	}, nil)
+	var failure *dynamoDBError
+	if errors.As(err, &failure) && failure.Type == "ResourceInUseException" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("table %s : %w", c.config.Table, err)
+	}
+	return nil
+}
+
+// =============================================================================
+// VALEURS TYPÉES
+// =============================================================================
+
+// dynamoValue AttributeValue de l'API JSON : un seul champ renseigné
+type dynamoValue struct {
+	S    *string                `json:"S,omitempty"`
+	N    *string                `json:"N,omitempty"`
+	BOOL *bool                  `json:"BOOL,omitempty"`
+	NULL *bool                  `json:"NULL,omitempty"`
+	M    map[string]dynamoValue `json:"M,omitempty"`
+	L    []dynamoValue          `json:"L,omitempty"`
+}
+
+// MarshalJSON un objet ou un tableau vide reste {"M": {}} / {"L": []} (omitempty le supprimerait)
+func (v dynamoValue) MarshalJSON() ([]byte, error) {
+	switch {
+	case v.S != nil:
+		return json.Marshal(map[string]string{"S": *v.S})
+	case v.N != nil:
+		return json.Marshal(map[string]string{"N": *v.N})
+	case v.BOOL != nil:
+		return json.Marshal(map[string]bool{"BOOL": *v.BOOL})
+	case v.M != nil:
+		return json.Marshal(map[string]map[string]dynamoValue{"M": v.M})
+	case v.L != nil:
+		return json.Marshal(map[string][]dynamoValue{"L": v.L})
+	default:
+		return []byte(`{"NULL":true}`), nil
+	}
+}
+
+type dynamoItem map[string]dynamoValue
+
+func dynamoString(value string) dynamoValue { return dynamoValue{S: &value} }
+
+func dynamoNumber(value int) dynamoValue {
+	n := fmt.Sprint(value)
+	return dynamoValue{N: &n}
+}
+
+func dynamoBool(value bool) dynamoValue { return dynamoValue{BOOL: &value} }
+
+// dynamoFromAny valeurs JSON (attributs libres) : chaînes, nombres, booléens, null, objets et tableaux
+func dynamoFromAny(value any) (dynamoValue, error) {
+	switch v := value.(type) {
+	case nil:
+		null := true
+		return dynamoValue{NULL: &null}, nil
+	case string:
+		return dynamoString(v), nil
+	case bool:
+		return dynamoBool(v), nil
+	case float64, float32, int, int64, json.Number:
+		n := fmt.Sprint(v)
+		return dynamoValue{N: &n}, nil
+	case map[string]any:
+		m := make(map[string]dynamoValue, len(v))
+		for key, item := range v {
+			converted, err := dynamoFromAny(item)
+			if err != nil {
+				return dynamoValue{}, err
+			}
+			m[key] = converted
+		}
+		return dynamoValue{M: m}, nil
+	case []any:
+		l := make([]dynamoValue, len(v))
+		for i, item := range v {
+			converted, err := dynamoFromAny(item)
+			if err != nil {
+				return dynamoValue{}, err
+			}
+			l[i] = converted
+		}
+		return dynamoValue{L: l}, nil
+	default:
+		return dynamoValue{}, fmt.Errorf("dynamodb: type d'attribut non supporté %T", value)
+	}
+}
+
+// toAny inverse de dynamoFromAny ; les nombres redeviennent des float64, comme après un décodage JSON
+func (v dynamoValue) toAny() (any, error) {
+	switch {
+	case v.S != nil:
+		return *v.S, nil
+	case v.N != nil:
+		var n float64
+		if err := json.Unmarshal([]byte(*v.N), &n); err != nil {
+			return nil, fmt.Errorf("dynamodb: nombre invalide %q", *v.N)
+		}
+		return n, nil
+	case v.BOOL != nil:
+		return *v.BOOL, nil
+	case v.M != nil:
+		m := make(map[string]any, len(v.M))
+		for key, item := range v.M {
+			converted, err := item.toAny()
+			if err != nil {
+				return nil, err
+			}
+			m[key] = converted
+		}
+		return m, nil
+	case v.L != nil:
+		l := make([]any, len(v.L))
+		for i, item := range v.L {
+			converted, err := item.toAny()
+			if err != nil {
+				return nil, err
+			}
+			l[i] = converted
+		}
+		return l, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (item dynamoItem) str(name string) string {
+	if v, ok := item[name]; ok && v.S != nil {
+		return *v.S
+	}
+	return ""
+}
+
+func (item dynamoItem) number(name string) (int, error) {
+	v, ok := item[name]
+	if !ok || v.N == nil {
+		return 0, nil
+	}
+	var n int
+	if _, err := fmt.Sscan(*v.N, &n); err != nil {
+		return 0, fmt.Errorf("dynamodb: %s : nombre invalide %q", name, *v.N)
+	}
+	return n, nil
+}
+
+func (item dynamoItem) boolean(name string) bool {
+	v, ok := item[name]
+	return ok && v.BOOL != nil && *v.BOOL
+}
+
+// time absent : zéro
+func (item dynamoItem) time(name string) (time.Time, error) {
+	raw := item.str(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, raw)
+}
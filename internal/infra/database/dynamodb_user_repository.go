@@ -0,0 +1,979 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Clés et index de la table unique (voir DynamoDBClient.EnsureTable)
+const (
+	dynamoPartitionKey = "PK"
+	dynamoSortKey      = "SK"
+	// dynamoEmailIndex GSI des recherches par email : GSI1PK = EMAIL#<email>
+	dynamoEmailIndex     = "GSI1"
+	dynamoEmailIndexKey  = "GSI1PK"
+	dynamoEmailIndexSort = "GSI1SK"
+	// dynamoUsersIndex GSI des parcours par ID croissant : une seule partition GSI2PK = USER
+	dynamoUsersIndex     = "GSI2"
+	dynamoUsersIndexKey  = "GSI2PK"
+	dynamoUsersIndexSort = "ID"
+)
+
+const (
+	dynamoProfileSort  = "PROFILE"
+	dynamoUniqueSort   = "UNIQUE"
+	dynamoEmailPrefix  = "EMAIL#"
+	dynamoHandlePrefix = "HANDLE#"
+	// dynamoMaxTransactItems éléments par TransactWriteItems (limite de l'API)
+	dynamoMaxTransactItems = 100
+	// dynamoMaxBatchGet clés par BatchGetItem (limite de l'API)
+	dynamoMaxBatchGet = 100
+	// dynamoWriteAttempts essais d'une écriture unitaire en conflit avec une écriture concurrente
+	dynamoWriteAttempts = 3
+)
+
+var (
+	errDynamoEmailTaken = errors.New("email déjà utilisé")
+	// errDynamoConflict l'élément a changé entre sa lecture et l'écriture conditionnelle
+	errDynamoConflict = errors.New("dynamodb: utilisateur modifié pendant l'écriture")
+	errDynamoCursor   = errors.New("curseur invalide")
+)
+
+// DynamoDBUserRepository implémente repositories.UserRepository sur une table DynamoDB unique
+// (déploiements serverless) :
+//   - profil : PK = USER#<id>, SK = PROFILE, avec GSI1PK = EMAIL#<email> (index des emails) et
+//     GSI2PK = USER / ID (parcours par ID croissant)
+//   - unicité : un élément EMAIL#<email> / HANDLE#<handle> (SK = UNIQUE) par valeur prise, écrit dans
+//     la même transaction que le profil avec attribute_not_exists : deux inscriptions concurrentes
+//     ne peuvent pas réserver le même email
+//   - compteur : PK = COUNTER, SK = USERS porte le prochain ID (séquentiel, comme en SQL) et le total
+//
+// Les lectures par clé sont fortement cohérentes ; les index secondaires sont à cohérence éventuelle :
+// GetByEmail retombe sur l'élément d'unicité quand l'index n'est pas encore à jour
+// Les filtres (Each, ListInactiveSince) sont appliqués après lecture : un FilterExpression consommerait
+// la même capacité, et newUserFilterMatcher garde la sémantique du dépôt en mémoire
+type DynamoDBUserRepository struct {
+	client *DynamoDBClient
+}
+
+func NewDynamoDBUserRepository(client *DynamoDBClient) *DynamoDBUserRepository {
+	return &DynamoDBUserRepository{client: client}
+}
+
+// dynamoUser utilisateur lu avec la version de son élément (écritures conditionnelles)
+type dynamoUser struct {
+	user    *entities.User
+	version int
+}
+
+func (r *DynamoDBUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	// Vérification préalable pour un message clair ; la condition de la transaction reste la garantie finale
+	taken, err := r.IsEmailTaken(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errDynamoEmailTaken
+	}
+
+	first, err := r.allocateIDs(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	created := *user
+	created.ID = first
+	if err := r.transact(ctx, []userChange{{after: &created}}); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *DynamoDBUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	stored, err := r.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return stored.user, nil
+}
+
+func (r *DynamoDBUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	keys := make([]dynamoItem, len(ids))
+	for i, id := range ids {
+		keys[i] = dynamoProfileKey(id)
+	}
+	items, err := r.batchGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*entities.User, 0, len(items))
+	for _, item := range items {
+		user, _, err := userFromDynamoItem(item)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// GetByEmail lit l'index des emails ; un profil absent de l'index (inscription ou changement d'email
+// très récent) est retrouvé par l'élément d'unicité, lu de façon cohérente
+func (r *DynamoDBUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	var out struct {
+		Items []dynamoItem `json:"Items"`
+	}
+	err := r.client.do(ctx, "Query", map[string]interface{}{
+		"TableName":                 r.client.Table(),
+		"IndexName":                 dynamoEmailIndex,
+		"KeyConditionExpression":    "#email = :email",
+		"ExpressionAttributeNames":  map[string]string{"#email": dynamoEmailIndexKey},
+		"ExpressionAttributeValues": dynamoItem{":email": dynamoString(dynamoEmailPrefix + email)},
+		"Limit":                     1,
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Items) > 0 {
+		user, _, err := userFromDynamoItem(out.Items[0])
+		if err != nil {
+			return nil, err
+		}
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return r.getByUnique(ctx, dynamoEmailPrefix+email)
+}
+
+func (r *DynamoDBUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	item, err := r.getItem(ctx, dynamoUniqueKey(dynamoEmailPrefix+email))
+	return item != nil, err
+}
+
+func (r *DynamoDBUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	return r.getByUnique(ctx, dynamoHandlePrefix+handle)
+}
+
+func (r *DynamoDBUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	item, err := r.getItem(ctx, dynamoUniqueKey(dynamoHandlePrefix+handle))
+	return item != nil, err
+}
+
+// Update relit la version courante puis écrit sous condition ; rejoue en cas d'écriture concurrente
+func (r *DynamoDBUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	updated := *user
+	err := r.retryOnConflict(ctx, user.ID, func(current *dynamoUser) []userChange {
+		return []userChange{{before: current, after: &updated}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// UpdateIfUnchanged sans nouvelle tentative : la transaction est conditionnée à la version lue,
+// un conflit signifie justement que l'utilisateur a changé
+func (r *DynamoDBUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	current, err := r.load(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !current.user.Updated.Equal(expectedUpdated) {
+		return nil, repositories.ErrUserModified
+	}
+
+	updated := *user
+	err = r.transact(ctx, []userChange{{before: current, after: &updated}})
+	if errors.Is(err, errDynamoConflict) {
+		return nil, repositories.ErrUserModified
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *DynamoDBUserRepository) DeleteById(ctx context.Context, id int) error {
+	return r.retryOnConflict(ctx, id, func(current *dynamoUser) []userChange {
+		return []userChange{{before: current}}
+	})
+}
+
+func (r *DynamoDBUserRepository) retryOnConflict(ctx context.Context, id int, changes func(current *dynamoUser) []userChange) error {
+	for attempt := 1; ; attempt++ {
+		current, err := r.load(ctx, id)
+		if err != nil {
+			return err
+		}
+		err = r.transact(ctx, changes(current))
+		if !errors.Is(err, errDynamoConflict) || attempt == dynamoWriteAttempts {
+			return err
+		}
+	}
+}
+
+// List l'offset est parcouru sur l'index : le coût croît avec la page ; ListPage suit un curseur
+func (r *DynamoDBUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	return r.collect(ctx, limit, offset, func(*entities.User) bool { return true })
+}
+
+// ListPage page de limit utilisateurs par ID croissant ; cursor vide pour la première page,
+// puis le curseur retourné (LastEvaluatedKey) tant qu'il n'est pas vide
+func (r *DynamoDBUserRepository) ListPage(ctx context.Context, limit int, cursor string) ([]*entities.User, string, error) {
+	startKey, err := decodeDynamoCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	items, lastKey, err := r.queryUsersIndex(ctx, startKey, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	users := make([]*entities.User, 0, len(items))
+	for _, item := range items {
+		user, _, err := userFromDynamoItem(item)
+		if err != nil {
+			return nil, "", err
+		}
+		users = append(users, user)
+	}
+	next, err := encodeDynamoCursor(lastKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return users, next, nil
+}
+
+func (r *DynamoDBUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	return r.collect(ctx, limit, offset, func(user *entities.User) bool {
+		return user.IsActive() && user.LastActivity().Before(cutoff)
+	})
+}
+
+// Each parcourt l'index par ID croissant, page par page (LastEvaluatedKey)
+func (r *DynamoDBUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	if filters.Query != "" {
+		return repositories.ErrFullTextNotSupported
+	}
+	matches, err := newUserFilterMatcher(filters)
+	if err != nil {
+		return err
+	}
+	err = r.walk(ctx, userIterationBatchSize, func(user *entities.User) error {
+		if !matches(user) {
+			return nil
+		}
+		return fn(user)
+	})
+	if errors.Is(err, repositories.ErrStopIteration) {
+		return nil
+	}
+	return err
+}
+
+// Count total tenu à jour dans la transaction de chaque création ou suppression
+func (r *DynamoDBUserRepository) Count(ctx context.Context) (int, error) {
+	item, err := r.getItem(ctx, dynamoCounterKey())
+	if err != nil || item == nil {
+		return 0, err
+	}
+	return item.number("Count")
+}
+
+// EstimateCount Count est déjà une lecture d'un seul élément
+func (r *DynamoDBUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	return r.Count(ctx)
+}
+
+// CreateMany écrit par transactions d'au plus dynamoMaxTransactItems éléments ; si une transaction
+// échoue, les utilisateurs des transactions précédentes sont supprimés (tout ou rien)
+func (r *DynamoDBUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if len(users) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	keys := make([]dynamoItem, len(users))
+	for i, user := range users {
+		keys[i] = dynamoUniqueKey(dynamoEmailPrefix + user.Email)
+	}
+	taken, err := r.batchGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(taken) > 0 {
+		return nil, errDynamoEmailTaken
+	}
+
+	first, err := r.allocateIDs(ctx, len(users))
+	if err != nil {
+		return nil, err
+	}
+	created := make([]*entities.User, len(users))
+	changes := make([]userChange, len(users))
+	for i, user := range users {
+		userCopy := *user
+		userCopy.ID = first + i
+		created[i] = &userCopy
+		changes[i] = userChange{after: &userCopy}
+	}
+	if err := r.transactAll(ctx, changes); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateMany même découpage et même compensation que CreateMany ; un ID inconnu annule tout avant écriture
+func (r *DynamoDBUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if len(users) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	ids := make([]int, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	current, err := r.loadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]*entities.User, len(users))
+	changes := make([]userChange, len(users))
+	for i, user := range users {
+		userCopy := *user
+		updated[i] = &userCopy
+		changes[i] = userChange{before: current[user.ID], after: &userCopy}
+	}
+	if err := r.transactAll(ctx, changes); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteByIds un ID inconnu annule tout avant écriture
+func (r *DynamoDBUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	current, err := r.loadMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+	changes := make([]userChange, len(ids))
+	for i, id := range ids {
+		changes[i] = userChange{before: current[id]}
+	}
+	return r.transactAll(ctx, changes)
+}
+
+// =============================================================================
+// LECTURES
+// =============================================================================
+
+func (r *DynamoDBUserRepository) getItem(ctx context.Context, key dynamoItem) (dynamoItem, error) {
+	var out struct {
+		Item dynamoItem `json:"Item"`
+	}
+	err := r.client.do(ctx, "GetItem", map[string]interface{}{
+		"TableName":      r.client.Table(),
+		"Key":            key,
+		"ConsistentRead": true,
+	}, &out)
+	return out.Item, err
+}
+
+func (r *DynamoDBUserRepository) load(ctx context.Context, id int) (*dynamoUser, error) {
+	item, err := r.getItem(ctx, dynamoProfileKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, repositories.ErrUserNotFound
+	}
+	user, version, err := userFromDynamoItem(item)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamoUser{user: user, version: version}, nil
+}
+
+// loadMany ErrUserNotFound si l'un des IDs est inconnu
+func (r *DynamoDBUserRepository) loadMany(ctx context.Context, ids []int) (map[int]*dynamoUser, error) {
+	keys := make([]dynamoItem, len(ids))
+	for i, id := range ids {
+		keys[i] = dynamoProfileKey(id)
+	}
+	items, err := r.batchGet(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[int]*dynamoUser, len(items))
+	for _, item := range items {
+		user, version, err := userFromDynamoItem(item)
+		if err != nil {
+			return nil, err
+		}
+		users[user.ID] = &dynamoUser{user: user, version: version}
+	}
+	for _, id := range ids {
+		if users[id] == nil {
+			return nil, repositories.ErrUserNotFound
+		}
+	}
+	return users, nil
+}
+
+// getByUnique profil du propriétaire d'un élément d'unicité (EMAIL#..., HANDLE#...)
+func (r *DynamoDBUserRepository) getByUnique(ctx context.Context, value string) (*entities.User, error) {
+	item, err := r.getItem(ctx, dynamoUniqueKey(value))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, repositories.ErrUserNotFound
+	}
+	id, err := item.number("UserID")
+	if err != nil {
+		return nil, err
+	}
+	return r.GetById(ctx, id)
+}
+
+// batchGet lectures cohérentes par BatchGetItem ; les clés non traitées (limitation de débit) sont
+// redemandées ; les éléments absents sont ignorés
+func (r *DynamoDBUserRepository) batchGet(ctx context.Context, keys []dynamoItem) ([]dynamoItem, error) {
+	table := r.client.Table()
+	var items []dynamoItem
+	for start := 0; start < len(keys); start += dynamoMaxBatchGet {
+		pending := keys[start:min(start+dynamoMaxBatchGet, len(keys))]
+		backoff := 50 * time.Millisecond
+		for len(pending) > 0 {
+			var out struct {
+				Responses       map[string][]dynamoItem `json:"Responses"`
+				UnprocessedKeys map[string]struct {
+					Keys []dynamoItem `json:"Keys"`
+				} `json:"UnprocessedKeys"`
+			}
+			err := r.client.do(ctx, "BatchGetItem", map[string]interface{}{
+				"RequestItems": map[string]interface{}{
+					table: map[string]interface{}{"Keys": pending, "ConsistentRead": true},
+				},
+			}, &out)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, out.Responses[table]...)
+
+			pending = out.UnprocessedKeys[table].Keys
+			if len(pending) > 0 {
+				select {
+				case <-time.After(backoff):
+					backoff *= 2
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+		}
+	}
+	return items, nil
+}
+
+// queryUsersIndex une page de l'index par ID, à partir de startKey (nil : depuis le début)
+func (r *DynamoDBUserRepository) queryUsersIndex(ctx context.Context, startKey dynamoItem, limit int) ([]dynamoItem, dynamoItem, error) {
+	body := map[string]interface{}{
+		"TableName":                 r.client.Table(),
+		"IndexName":                 dynamoUsersIndex,
+		"KeyConditionExpression":    "#users = :users",
+		"ExpressionAttributeNames":  map[string]string{"#users": dynamoUsersIndexKey},
+		"ExpressionAttributeValues": dynamoItem{":users": dynamoString("USER")},
+		"ScanIndexForward":          true,
+	}
+	if limit > 0 {
+		body["Limit"] = limit
+	}
+	if startKey != nil {
+		body["ExclusiveStartKey"] = startKey
+	}
+
+	var out struct {
+		Items            []dynamoItem `json:"Items"`
+		LastEvaluatedKey dynamoItem   `json:"LastEvaluatedKey"`
+	}
+	if err := r.client.do(ctx, "Query", body, &out); err != nil {
+		return nil, nil, err
+	}
+	return out.Items, out.LastEvaluatedKey, nil
+}
+
+// walk appelle fn pour chaque utilisateur par ID croissant ; une erreur de fn arrête le parcours
+func (r *DynamoDBUserRepository) walk(ctx context.Context, pageSize int, fn func(*entities.User) error) error {
+	var startKey dynamoItem
+	for {
+		items, lastKey, err := r.queryUsersIndex(ctx, startKey, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			user, _, err := userFromDynamoItem(item)
+			if err != nil {
+				return err
+			}
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		if lastKey == nil {
+			return nil
+		}
+		startKey = lastKey
+	}
+}
+
+// collect page [offset, offset+limit) des utilisateurs retenus par keep
+func (r *DynamoDBUserRepository) collect(ctx context.Context, limit, offset int, keep func(*entities.User) bool) ([]*entities.User, error) {
+	users := make([]*entities.User, 0, limit)
+	if limit <= 0 {
+		return users, nil
+	}
+	skipped := 0
+	err := r.walk(ctx, min(offset+limit, userIterationBatchSize), func(user *entities.User) error {
+		if !keep(user) {
+			return nil
+		}
+		if skipped < offset {
+			skipped++
+			return nil
+		}
+		users = append(users, user)
+		if len(users) == limit {
+			return repositories.ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, repositories.ErrStopIteration) {
+		return nil, err
+	}
+	return users, nil
+}
+
+// =============================================================================
+// ÉCRITURES
+// =============================================================================
+
+// userChange before nil : création ; after nil : suppression
+type userChange struct {
+	before *dynamoUser
+	after  *entities.User
+}
+
+// inverse change qui annule c une fois appliqué (compensation)
+func (c userChange) inverse() userChange {
+	var inverse userChange
+	if c.after != nil {
+		inverse.before = &dynamoUser{user: c.after, version: c.version()}
+	}
+	if c.before != nil {
+		inverse.after = c.before.user
+	}
+	return inverse
+}
+
+// version de l'élément après application
+func (c userChange) version() int {
+	if c.before == nil {
+		return 1
+	}
+	return c.before.version + 1
+}
+
+// transactItem élément de TransactWriteItems et erreur retournée si sa condition échoue
+type transactItem struct {
+	request map[string]interface{}
+	failure error
+}
+
+// allocateIDs réserve count IDs consécutifs et retourne le premier
+func (r *DynamoDBUserRepository) allocateIDs(ctx context.Context, count int) (int, error) {
+	var out struct {
+		Attributes dynamoItem `json:"Attributes"`
+	}
+	err := r.client.do(ctx, "UpdateItem", map[string]interface{}{
+		"TableName":                 r.client.Table(),
+		"Key":                       dynamoCounterKey(),
+		"UpdateExpression":          "ADD NextID :count",
+		"ExpressionAttributeValues": dynamoItem{":count": dynamoNumber(count)},
+		"ReturnValues":              "UPDATED_NEW",
+	}, &out)
+	if err != nil {
+		return 0, err
+	}
+	last, err := out.Attributes.number("NextID")
+	if err != nil {
+		return 0, err
+	}
+	return last - count + 1, nil
+}
+
+// transact applique les changements dans une seule transaction
+func (r *DynamoDBUserRepository) transact(ctx context.Context, changes []userChange) error {
+	items, err := r.transactItems(changes)
+	if err != nil {
+		return err
+	}
+	requests := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		requests[i] = item.request
+	}
+
+	err = r.client.do(ctx, "TransactWriteItems", map[string]interface{}{"TransactItems": requests}, nil)
+	var failure *dynamoDBError
+	if !errors.As(err, &failure) || failure.Type != "TransactionCanceledException" {
+		return err
+	}
+	for i, reason := range failure.CancellationReasons {
+		switch {
+		case reason.Code == "ConditionalCheckFailed" && i < len(items):
+			return items[i].failure
+		case reason.Code == "TransactionConflict":
+			return errDynamoConflict
+		}
+	}
+	return err
+}
+
+// transactAll découpe les changements en transactions ; si l'une échoue, les précédentes sont annulées
+// par les changements inverses (au mieux : une écriture concurrente entre-temps l'emporte)
+func (r *DynamoDBUserRepository) transactAll(ctx context.Context, changes []userChange) error {
+	var batches [][]userChange
+	var batch []userChange
+	size := 1 // compteur
+	for _, change := range changes {
+		items := change.itemCount()
+		if len(batch) > 0 && size+items > dynamoMaxTransactItems {
+			batches = append(batches, batch)
+			batch, size = nil, 1
+		}
+		batch = append(batch, change)
+		size += items
+	}
+	batches = append(batches, batch)
+
+	for i, batch := range batches {
+		if err := r.transact(ctx, batch); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				inverse := make([]userChange, len(batches[j]))
+				for k, change := range batches[j] {
+					inverse[k] = change.inverse()
+				}
+				_ = r.transact(context.WithoutCancel(ctx), inverse)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// itemCount éléments de TransactWriteItems pour ce changement (hors compteur)
+func (c userChange) itemCount() int {
+	count := 1
+	for _, changed := range c.uniqueChanges() {
+		if changed.released != "" {
+			count++
+		}
+		if changed.claimed != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// uniqueChange élément d'unicité libéré et/ou réservé (vides : inchangé)
+type uniqueChange struct {
+	released string
+	claimed  string
+	failure  error
+}
+
+func (c userChange) uniqueChanges() []uniqueChange {
+	var before, after *entities.User
+	if c.before != nil {
+		before = c.before.user
+	}
+	after = c.after
+
+	value := func(user *entities.User, field func(*entities.User) string, prefix string) string {
+		if user == nil || field(user) == "" {
+			return ""
+		}
+		return prefix + field(user)
+	}
+	email := func(user *entities.User) string { return user.Email }
+	handle := func(user *entities.User) string { return user.Handle }
+
+	var changes []uniqueChange
+	for _, unique := range []struct {
+		field   func(*entities.User) string
+		prefix  string
+		failure error
+	}{
+		{email, dynamoEmailPrefix, errDynamoEmailTaken},
+		{handle, dynamoHandlePrefix, repositories.ErrHandleTaken},
+	} {
+		released, claimed := value(before, unique.field, unique.prefix), value(after, unique.field, unique.prefix)
+		if released == claimed {
+			continue
+		}
+		changes = append(changes, uniqueChange{released: released, claimed: claimed, failure: unique.failure})
+	}
+	return changes
+}
+
+func (r *DynamoDBUserRepository) transactItems(changes []userChange) ([]transactItem, error) {
+	table := r.client.Table()
+	var items []transactItem
+	delta := 0
+	for _, change := range changes {
+		id := 0
+		if change.after != nil {
+			id = change.after.ID
+		} else {
+			id = change.before.user.ID
+		}
+
+		switch {
+		case change.after == nil:
+			delta--
+			items = append(items, transactItem{
+				request: map[string]interface{}{"Delete": map[string]interface{}{
+					"TableName":                 table,
+					"Key":                       dynamoProfileKey(id),
+					"ConditionExpression":       "#version = :version",
+					"ExpressionAttributeNames":  map[string]string{"#version": "Version"},
+					"ExpressionAttributeValues": dynamoItem{":version": dynamoNumber(change.before.version)},
+				}},
+				failure: errDynamoConflict,
+			})
+		default:
+			item, err := userToDynamoItem(change.after, change.version())
+			if err != nil {
+				return nil, err
+			}
+			put := map[string]interface{}{"TableName": table, "Item": item}
+			if change.before == nil {
+				delta++
+				put["ConditionExpression"] = "attribute_not_exists(PK)"
+			} else {
+				put["ConditionExpression"] = "#version = :version"
+				put["ExpressionAttributeNames"] = map[string]string{"#version": "Version"}
+				put["ExpressionAttributeValues"] = dynamoItem{":version": dynamoNumber(change.before.version)}
+			}
+			items = append(items, transactItem{request: map[string]interface{}{"Put": put}, failure: errDynamoConflict})
+		}
+
+		for _, unique := range change.uniqueChanges() {
+			if unique.released != "" {
+				items = append(items, transactItem{
+					request: map[string]interface{}{"Delete": map[string]interface{}{
+						"TableName":                 table,
+						"Key":                       dynamoUniqueKey(unique.released),
+						"ConditionExpression":       "attribute_not_exists(PK) OR UserID = :id",
+						"ExpressionAttributeValues": dynamoItem{":id": dynamoNumber(id)},
+					}},
+					failure: errDynamoConflict,
+				})
+			}
+			if unique.claimed != "" {
+				item := dynamoUniqueKey(unique.claimed)
+				item["UserID"] = dynamoNumber(id)
+				items = append(items, transactItem{
+					request: map[string]interface{}{"Put": map[string]interface{}{
+						"TableName":           table,
+						"Item":                item,
+						"ConditionExpression": "attribute_not_exists(PK)",
+					}},
+					failure: unique.failure,
+				})
+			}
+		}
+	}
+
+	if delta != 0 {
+		items = append(items, transactItem{request: map[string]interface{}{"Update": map[string]interface{}{
+			"TableName":                 table,
+			"Key":                       dynamoCounterKey(),
+			"UpdateExpression":          "ADD #count :delta",
+			"ExpressionAttributeNames":  map[string]string{"#count": "Count"},
+			"ExpressionAttributeValues": dynamoItem{":delta": dynamoNumber(delta)},
+		}}})
+	}
+	return items, nil
+}
+
+// =============================================================================
+// ÉLÉMENTS
+// =============================================================================
+
+func dynamoProfileKey(id int) dynamoItem {
+	return dynamoItem{
+		dynamoPartitionKey: dynamoString("USER#" + strconv.Itoa(id)),
+		dynamoSortKey:      dynamoString(dynamoProfileSort),
+	}
+}
+
+func dynamoUniqueKey(value string) dynamoItem {
+	return dynamoItem{
+		dynamoPartitionKey: dynamoString(value),
+		dynamoSortKey:      dynamoString(dynamoUniqueSort),
+	}
+}
+
+func dynamoCounterKey() dynamoItem {
+	return dynamoItem{
+		dynamoPartitionKey: dynamoString("COUNTER"),
+		dynamoSortKey:      dynamoString("USERS"),
+	}
+}
+
+// userToDynamoItem profil et clés d'index ; handle, dates et attributs absents ne sont pas écrits
+func userToDynamoItem(user *entities.User, version int) (dynamoItem, error) {
+	item := dynamoProfileKey(user.ID)
+	item[dynamoEmailIndexKey] = dynamoString(dynamoEmailPrefix + user.Email)
+	item[dynamoEmailIndexSort] = dynamoString("USER#" + strconv.Itoa(user.ID))
+	item[dynamoUsersIndexKey] = dynamoString("USER")
+	item[dynamoUsersIndexSort] = dynamoNumber(user.ID)
+	item["Version"] = dynamoNumber(version)
+
+	item["Email"] = dynamoString(user.Email)
+	item["Name"] = dynamoString(user.Name)
+	item["Password"] = dynamoString(user.Password)
+	item["Created"] = dynamoString(user.Created.UTC().Format(time.RFC3339Nano))
+	item["Updated"] = dynamoString(user.Updated.UTC().Format(time.RFC3339Nano))
+	item["WeeklyDigest"] = dynamoBool(user.WeeklyDigest)
+	item["Phone"] = dynamoString(user.Phone)
+	item["Status"] = dynamoString(string(user.CurrentStatus()))
+	if user.Handle != "" {
+		item["Handle"] = dynamoString(user.Handle)
+	}
+	if user.LastLoginAt != nil {
+		item["LastLoginAt"] = dynamoString(user.LastLoginAt.UTC().Format(time.RFC3339Nano))
+	}
+	if user.CleanupFlaggedAt != nil {
+		item["CleanupFlaggedAt"] = dynamoString(user.CleanupFlaggedAt.UTC().Format(time.RFC3339Nano))
+	}
+	if user.EmailUndeliverableAt != nil {
+		item["EmailUndeliverableAt"] = dynamoString(user.EmailUndeliverableAt.UTC().Format(time.RFC3339Nano))
+		item["EmailUndeliverableReason"] = dynamoString(user.EmailUndeliverableReason)
+	}
+	if user.Locale != "" {
+		item["Locale"] = dynamoString(user.Locale)
+	}
+	if user.TimeZone != "" {
+		item["TimeZone"] = dynamoString(user.TimeZone)
+	}
+	if len(user.Attributes) > 0 {
+		attributes, err := dynamoFromAny(map[string]any(user.Attributes))
+		if err != nil {
+			return nil, err
+		}
+		item["Attributes"] = attributes
+	}
+	return item, nil
+}
+
+func userFromDynamoItem(item dynamoItem) (*entities.User, int, error) {
+	id, err := item.number(dynamoUsersIndexSort)
+	if err != nil {
+		return nil, 0, err
+	}
+	version, err := item.number("Version")
+	if err != nil {
+		return nil, 0, err
+	}
+	user := &entities.User{
+		ID:           id,
+		Email:        item.str("Email"),
+		Name:         item.str("Name"),
+		Password:     item.str("Password"),
+		WeeklyDigest: item.boolean("WeeklyDigest"),
+		Phone:        item.str("Phone"),
+		Status:       entities.UserStatus(item.str("Status")),
+		Handle:       item.str("Handle"),
+		Locale:       item.str("Locale"),
+		TimeZone:     item.str("TimeZone"),
+
+		EmailUndeliverableReason: item.str("EmailUndeliverableReason"),
+	}
+	if user.Created, err = item.time("Created"); err != nil {
+		return nil, 0, err
+	}
+	if user.Updated, err = item.time("Updated"); err != nil {
+		return nil, 0, err
+	}
+	for name, target := range map[string]**time.Time{
+		"LastLoginAt":          &user.LastLoginAt,
+		"CleanupFlaggedAt":     &user.CleanupFlaggedAt,
+		"EmailUndeliverableAt": &user.EmailUndeliverableAt,
+	} {
+		value, err := item.time(name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !value.IsZero() {
+			*target = &value
+		}
+	}
+	if attributes, ok := item["Attributes"]; ok {
+		decoded, err := attributes.toAny()
+		if err != nil {
+			return nil, 0, err
+		}
+		if m, ok := decoded.(map[string]any); ok && len(m) > 0 {
+			user.Attributes = m
+		}
+	}
+	return user, version, nil
+}
+
+// encodeDynamoCursor LastEvaluatedKey opaque pour le client (vide : dernière page)
+func encodeDynamoCursor(key dynamoItem) (string, error) {
+	if key == nil {
+		return "", nil
+	}
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeDynamoCursor seules les clés de l'index par ID sont acceptées
+func decodeDynamoCursor(cursor string) (dynamoItem, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errDynamoCursor
+	}
+	var key dynamoItem
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return nil, errDynamoCursor
+	}
+	for name := range key {
+		switch name {
+		case dynamoPartitionKey, dynamoSortKey, dynamoUsersIndexKey, dynamoUsersIndexSort:
+		default:
+			return nil, errDynamoCursor
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,222 @@
+package database
+
+import (
+	"bytes"
+	"clean-archi-analytics/internal/httpclient"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ElasticsearchConfig accès au cluster (Elasticsearch 8 ou OpenSearch 2, API REST commune)
+type ElasticsearchConfig struct {
+	URL string
+	// Username / Password authentification basique ; APIKey (Elasticsearch) la remplace si renseignée
+	Username string
+	Password string
+	APIKey   string
+	// IndexPrefix préfixe des index : "<prefix>-users" et "<prefix>-events"
+	IndexPrefix string
+	// Clients fabrique des clients HTTP sortants (nil = client sans instrumentation)
+	Clients *httpclient.Factory
+}
+
+// ElasticsearchClient appels REST au cluster, sans dépendance au client officiel :
+// seules les API document, recherche et création d'index sont utilisées
+type ElasticsearchClient struct {
+	config ElasticsearchConfig
+	client *http.Client
+}
+
+func NewElasticsearchClient(config ElasticsearchConfig) *ElasticsearchClient {
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	return &ElasticsearchClient{
+		config: config,
+		// Les recherches sont servies en direct : un cluster lent ne doit pas bloquer l'API
+		client: config.Clients.Client("elasticsearch", httpclient.ClientOptions{Timeout: 5 * time.Second}),
+	}
+}
+
+// UsersIndex / EventsIndex noms des index de l'application
+func (c *ElasticsearchClient) UsersIndex() string  { return c.config.IndexPrefix + "-users" }
+func (c *ElasticsearchClient) EventsIndex() string { return c.config.IndexPrefix + "-events" }
+
+// elasticsearchError réponse d'erreur du cluster ({"error": {"type": ..., "reason": ...}})
+type elasticsearchError struct {
+	Status int
+	Type   string
+	Reason string
+}
+
+func (e *elasticsearchError) Error() string {
+	return fmt.Sprintf("elasticsearch: statut %d : %s (%s)", e.Status, e.Reason, e.Type)
+}
+
+// do envoie body en JSON et décode la réponse dans out (nil : réponse ignorée)
+// Les statuts de acceptedStatuses ne sont pas des erreurs (404 d'une suppression, 409 d'une version périmée)
+func (c *ElasticsearchClient) do(ctx context.Context, method, path string, body, out interface{}, acceptedStatuses ...int) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.URL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.config.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.config.APIKey)
+	case c.config.Username != "":
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		for _, accepted := range acceptedStatuses {
+			if resp.StatusCode == accepted {
+				return resp.StatusCode, nil
+			}
+		}
+		var failure struct {
+			Error struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&failure)
+		return resp.StatusCode, &elasticsearchError{Status: resp.StatusCode, Type: failure.Error.Type, Reason: failure.Error.Reason}
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("elasticsearch: réponse invalide : %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// EnsureIndexes crée les index et leurs mappings s'ils n'existent pas encore
+// Un mapping existant n'est jamais modifié : un changement de mapping demande un nouvel index
+func (c *ElasticsearchClient) EnsureIndexes(ctx context.Context) error {
+	for index, definition := range map[string]interface{}{
+		c.UsersIndex():  usersIndexDefinition,
+		c.EventsIndex(): eventsIndexDefinition,
+	} {
+		_, err := c.do(ctx, http.MethodPut, "/"+index, definition, nil)
+		var failure *elasticsearchError
+		if errors.As(err, &failure) && failure.Type == "resource_already_exists_exception" {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("index %s : %w", index, err)
+		}
+	}
+	return nil
+}
+
+// usersIndexDefinition mots découpés comme searchTerms (lettres et chiffres), accents ignorés ;
+// sous-champs "raw" en minuscules pour les filtres par sous-chaîne, attributs texte en keyword
+var usersIndexDefinition = map[string]interface{}{
+	"settings": map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"tokenizer": map[string]interface{}{
+				"words": map[string]interface{}{"type": "pattern", "pattern": `[^\p{L}\p{N}]+`},
+			},
+			"analyzer": map[string]interface{}{
+				"words": map[string]interface{}{"type": "custom", "tokenizer": "words", "filter": []string{"lowercase", "asciifolding"}},
+			},
+			"normalizer": map[string]interface{}{
+				"lowercase": map[string]interface{}{"type": "custom", "filter": []string{"lowercase"}},
+			},
+		},
+	},
+	"mappings": map[string]interface{}{
+		"dynamic_templates": []interface{}{
+			map[string]interface{}{"attribute_strings": map[string]interface{}{
+				"path_match":         "attributes.*",
+				"match_mapping_type": "string",
+				"mapping":            map[string]interface{}{"type": "keyword"},
+			}},
+		},
+		"properties": map[string]interface{}{
+			"id":            map[string]interface{}{"type": "long"},
+			"name":          searchableTextMapping,
+			"email":         searchableTextMapping,
+			"email_domain":  map[string]interface{}{"type": "keyword"},
+			"status":        map[string]interface{}{"type": "keyword"},
+			"handle":        map[string]interface{}{"type": "keyword"},
+			"created":       map[string]interface{}{"type": "date"},
+			"updated":       map[string]interface{}{"type": "date"},
+			"last_login_at": map[string]interface{}{"type": "date"},
+			"attributes":    map[string]interface{}{"type": "object", "dynamic": true},
+		},
+	},
+}
+
+var searchableTextMapping = map[string]interface{}{
+	"type":     "text",
+	"analyzer": "words",
+	"fields": map[string]interface{}{
+		"raw": map[string]interface{}{"type": "keyword", "normalizer": "lowercase"},
+	},
+}
+
+var eventsIndexDefinition = map[string]interface{}{
+	"mappings": map[string]interface{}{
+		"dynamic": "strict",
+		"properties": map[string]interface{}{
+			"name":        map[string]interface{}{"type": "keyword"},
+			"user_id":     map[string]interface{}{"type": "long"},
+			"occurred_at": map[string]interface{}{"type": "date"},
+		},
+	},
+}
+
+// elasticsearchSearchResponse partie de la réponse de _search utilisée par les adaptateurs
+type elasticsearchSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID        string              `json:"_id"`
+			Source    json.RawMessage     `json:"_source"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations map[string]struct {
+		Buckets []elasticsearchBucket `json:"buckets"`
+	} `json:"aggregations"`
+}
+
+// elasticsearchBucket terms : Key est la valeur ; date_histogram : Key en millisecondes epoch
+type elasticsearchBucket struct {
+	Key      interface{} `json:"key"`
+	DocCount int         `json:"doc_count"`
+}
+
+func (c *ElasticsearchClient) search(ctx context.Context, index string, query map[string]interface{}) (*elasticsearchSearchResponse, error) {
+	var response elasticsearchSearchResponse
+	if _, err := c.do(ctx, http.MethodPost, "/"+index+"/_search", query, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// elasticsearchWildcardEscaper neutralise les jokers saisis par le client, comme escapeLike en SQL
+var elasticsearchWildcardEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
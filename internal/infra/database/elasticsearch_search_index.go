@@ -0,0 +1,219 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ElasticsearchSearchIndex implémente repositories.SearchIndexWriter (alimenté par usecases.SearchIndexer),
+// repositories.EventSearchRepository et repositories.EventHistory sur les index "<prefix>-users" et "<prefix>-events"
+type ElasticsearchSearchIndex struct {
+	client *ElasticsearchClient
+}
+
+func NewElasticsearchSearchIndex(client *ElasticsearchClient) *ElasticsearchSearchIndex {
+	return &ElasticsearchSearchIndex{client: client}
+}
+
+// elasticsearchUserDocument document indexé : jamais de hash de mot de passe ni de téléphone
+type elasticsearchUserDocument struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Email       string         `json:"email"`
+	EmailDomain string         `json:"email_domain"`
+	Status      string         `json:"status"`
+	Handle      string         `json:"handle,omitempty"`
+	Created     time.Time      `json:"created"`
+	Updated     time.Time      `json:"updated"`
+	LastLoginAt *time.Time     `json:"last_login_at,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+// IndexUser versionne le document par la date de mise à jour (version_type=external_gte) :
+// une indexation asynchrone arrivée en retard ne remplace pas un document plus récent
+func (i *ElasticsearchSearchIndex) IndexUser(ctx context.Context, user *entities.User) error {
+	_, domain, _ := strings.Cut(user.Email, "@")
+	document := elasticsearchUserDocument{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		EmailDomain: strings.ToLower(domain),
+		Status:      string(user.CurrentStatus()),
+		Handle:      user.Handle,
+		Created:     user.Created,
+		Updated:     user.Updated,
+		LastLoginAt: user.LastLoginAt,
+		Attributes:  user.Attributes,
+	}
+	version := int64(1)
+	if !user.Updated.IsZero() {
+		version = user.Updated.UnixNano()
+	}
+	path := fmt.Sprintf("/%s/_doc/%d?version=%d&version_type=external_gte", i.client.UsersIndex(), user.ID, version)
+	_, err := i.client.do(ctx, http.MethodPut, path, document, nil, http.StatusConflict)
+	return err
+}
+
+func (i *ElasticsearchSearchIndex) DeleteUser(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/%s/_doc/%d", i.client.UsersIndex(), id)
+	_, err := i.client.do(ctx, http.MethodDelete, path, nil, nil, http.StatusNotFound)
+	return err
+}
+
+type elasticsearchEventDocument struct {
+	Name       string    `json:"name"`
+	UserID     int       `json:"user_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (i *ElasticsearchSearchIndex) IndexEvent(ctx context.Context, event repositories.IndexedEvent) error {
+	document := elasticsearchEventDocument{Name: event.Name, UserID: event.UserID, OccurredAt: event.OccurredAt}
+	_, err := i.client.do(ctx, http.MethodPost, "/"+i.client.EventsIndex()+"/_doc", document, nil)
+	return err
+}
+
+// SearchEvents événements les plus récents d'abord, avec le nombre d'événements par nom et
+// l'histogramme sur Interval calculés sur tous les résultats
+func (i *ElasticsearchSearchIndex) SearchEvents(ctx context.Context, filters repositories.EventSearchFilters) (*repositories.EventSearchResult, error) {
+	conditions := []interface{}{}
+	if len(filters.Names) > 0 {
+		conditions = append(conditions, map[string]interface{}{"terms": map[string]interface{}{"name": filters.Names}})
+	}
+	if filters.UserID > 0 {
+		conditions = append(conditions, map[string]interface{}{"term": map[string]interface{}{"user_id": filters.UserID}})
+	}
+	if occurred := elasticsearchRange(filters.From, filters.To); occurred != nil {
+		conditions = append(conditions, map[string]interface{}{"range": map[string]interface{}{"occurred_at": occurred}})
+	}
+
+	query := map[string]interface{}{
+		"from":             filters.Offset,
+		"size":             filters.Limit,
+		"track_total_hits": true,
+		"query":            map[string]interface{}{"bool": map[string]interface{}{"filter": conditions}},
+		"sort":             []interface{}{map[string]interface{}{"occurred_at": "desc"}},
+		"aggs": map[string]interface{}{
+			"by_name": map[string]interface{}{"terms": map[string]interface{}{"field": "name", "size": 100}},
+			"histogram": map[string]interface{}{"date_histogram": map[string]interface{}{
+				"field":             "occurred_at",
+				"calendar_interval": filters.Interval,
+			}},
+		},
+	}
+	response, err := i.client.search(ctx, i.client.EventsIndex(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &repositories.EventSearchResult{
+		Events: make([]repositories.IndexedEvent, 0, len(response.Hits.Hits)),
+		Total:  response.Hits.Total.Value,
+		ByName: termsBuckets(response.Aggregations["by_name"].Buckets),
+	}
+	for _, hit := range response.Hits.Hits {
+		var document elasticsearchEventDocument
+		if err := json.Unmarshal(hit.Source, &document); err != nil {
+			return nil, fmt.Errorf("elasticsearch: événement %s illisible : %w", hit.ID, err)
+		}
+		result.Events = append(result.Events, repositories.IndexedEvent{
+			Name:       document.Name,
+			UserID:     document.UserID,
+			OccurredAt: document.OccurredAt,
+		})
+	}
+	for _, bucket := range response.Aggregations["histogram"].Buckets {
+		millis, ok := bucket.Key.(float64)
+		if !ok {
+			continue
+		}
+		result.Histogram = append(result.Histogram, repositories.EventHistogramBucket{
+			Start: time.UnixMilli(int64(millis)).UTC(),
+			Count: bucket.DocCount,
+		})
+	}
+	return result, nil
+}
+
+// DailyEventCounts implémente repositories.EventHistory : une seule agrégation (jour UTC puis nom)
+// sur les événements indexés, sans lire les documents
+func (i *ElasticsearchSearchIndex) DailyEventCounts(ctx context.Context, from, to time.Time) ([]repositories.EventRollup, error) {
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": []interface{}{
+			map[string]interface{}{"range": map[string]interface{}{"occurred_at": elasticsearchRange(&from, &to)}},
+		}}},
+		"aggs": map[string]interface{}{
+			"days": map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":             "occurred_at",
+					"calendar_interval": "day",
+					"time_zone":         "UTC",
+					"min_doc_count":     1,
+				},
+				"aggs": map[string]interface{}{
+					"by_name": map[string]interface{}{"terms": map[string]interface{}{"field": "name", "size": 100}},
+				},
+			},
+		},
+	}
+	var response struct {
+		Aggregations struct {
+			Days struct {
+				Buckets []struct {
+					Key    float64 `json:"key"`
+					ByName struct {
+						Buckets []elasticsearchBucket `json:"buckets"`
+					} `json:"by_name"`
+				} `json:"buckets"`
+			} `json:"days"`
+		} `json:"aggregations"`
+	}
+	if _, err := i.client.do(ctx, http.MethodPost, "/"+i.client.EventsIndex()+"/_search", query, &response); err != nil {
+		return nil, err
+	}
+
+	var rollups []repositories.EventRollup
+	for _, day := range response.Aggregations.Days.Buckets {
+		start := time.UnixMilli(int64(day.Key)).UTC()
+		for name, count := range termsBuckets(day.ByName.Buckets) {
+			rollups = append(rollups, repositories.EventRollup{Day: start, Event: name, Count: count})
+		}
+	}
+	return rollups, nil
+}
+
+// elasticsearchRange bornes d'une requête range (début inclus, fin exclue) ; nil sans borne
+func elasticsearchRange(from, to *time.Time) map[string]interface{} {
+	if from == nil && to == nil {
+		return nil
+	}
+	bounds := make(map[string]interface{}, 2)
+	if from != nil {
+		bounds["gte"] = from.Format(time.RFC3339Nano)
+	}
+	if to != nil {
+		bounds["lt"] = to.Format(time.RFC3339Nano)
+	}
+	return bounds
+}
+
+// termsBuckets agrégation terms → nombre de documents par valeur
+func termsBuckets(buckets []elasticsearchBucket) map[string]int {
+	counts := make(map[string]int, len(buckets))
+	for _, bucket := range buckets {
+		switch key := bucket.Key.(type) {
+		case string:
+			counts[key] = bucket.DocCount
+		case float64:
+			counts[strconv.FormatFloat(key, 'f', -1, 64)] = bucket.DocCount
+		}
+	}
+	return counts
+}
@@ -0,0 +1,224 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ElasticsearchUserRepository décore un repositories.UserRepository : les écritures et lectures
+// unitaires restent sur le stockage, Search et SearchWithFacets interrogent "<prefix>-users"
+//   - Query : correspondance approchée (fuzziness AUTO, le dernier mot est un préfixe), nom prioritaire
+//   - facettes status et email_domain, extraits surlignés du nom et de l'email
+//
+// Les utilisateurs trouvés sont relus dans le stockage, dans l'ordre du moteur : l'index, alimenté
+// de façon asynchrone par usecases.SearchIndexer, ne sert qu'à trouver et classer
+type ElasticsearchUserRepository struct {
+	next   repositories.UserRepository
+	client *ElasticsearchClient
+}
+
+func NewElasticsearchUserRepository(next repositories.UserRepository, client *ElasticsearchClient) *ElasticsearchUserRepository {
+	return &ElasticsearchUserRepository{next: next, client: client}
+}
+
+func (r *ElasticsearchUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	result, err := r.search(ctx, filters, false)
+	if err != nil {
+		return nil, err
+	}
+	return result.Users, nil
+}
+
+func (r *ElasticsearchUserRepository) SearchWithFacets(ctx context.Context, filters repositories.UserRepositoryFilters) (*repositories.UserSearchResult, error) {
+	return r.search(ctx, filters, true)
+}
+
+func (r *ElasticsearchUserRepository) search(ctx context.Context, filters repositories.UserRepositoryFilters, facets bool) (*repositories.UserSearchResult, error) {
+	from, to, err := parseCreatedRange(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []interface{}{}
+	substring := func(field, value string) {
+		pattern := "*" + elasticsearchWildcardEscaper.Replace(strings.ToLower(value)) + "*"
+		conditions = append(conditions, map[string]interface{}{"wildcard": map[string]interface{}{
+			field: map[string]interface{}{"value": pattern, "case_insensitive": true},
+		}})
+	}
+	if filters.Email != "" {
+		substring("email.raw", filters.Email)
+	}
+	if filters.Name != "" {
+		substring("name.raw", filters.Name)
+	}
+	if filters.Status != "" {
+		conditions = append(conditions, map[string]interface{}{"term": map[string]interface{}{"status": filters.Status}})
+	}
+	var fromBound, toBound *time.Time
+	if !from.IsZero() {
+		fromBound = &from
+	}
+	if !to.IsZero() {
+		toBound = &to
+	}
+	if created := elasticsearchRange(fromBound, toBound); created != nil {
+		conditions = append(conditions, map[string]interface{}{"range": map[string]interface{}{"created": created}})
+	}
+	for key, value := range filters.Attributes {
+		conditions = append(conditions, map[string]interface{}{"term": map[string]interface{}{"attributes." + key: value}})
+	}
+
+	boolQuery := map[string]interface{}{"filter": conditions}
+	sort := []interface{}{map[string]interface{}{"id": "asc"}}
+	query := map[string]interface{}{
+		"from":    filters.Offset,
+		"_source": false,
+	}
+	if filters.Limit > 0 {
+		query["size"] = filters.Limit
+	}
+	if filters.Query != "" {
+		boolQuery["must"] = []interface{}{map[string]interface{}{"multi_match": map[string]interface{}{
+			"query":     filters.Query,
+			"type":      "bool_prefix",
+			"fields":    []string{"name^2", "email"},
+			"operator":  "and",
+			"fuzziness": "AUTO",
+		}}}
+		sort = append([]interface{}{"_score"}, sort...)
+		query["highlight"] = map[string]interface{}{"fields": map[string]interface{}{"name": map[string]interface{}{}, "email": map[string]interface{}{}}}
+	}
+	query["query"] = map[string]interface{}{"bool": boolQuery}
+	query["sort"] = sort
+	if facets {
+		query["aggs"] = map[string]interface{}{
+			repositories.UserFacetStatus:      map[string]interface{}{"terms": map[string]interface{}{"field": "status"}},
+			repositories.UserFacetEmailDomain: map[string]interface{}{"terms": map[string]interface{}{"field": "email_domain", "size": 20}},
+		}
+	}
+
+	response, err := r.client.search(ctx, r.client.UsersIndex(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(response.Hits.Hits))
+	highlights := make(map[int]map[string][]string)
+	for _, hit := range response.Hits.Hits {
+		id, err := strconv.Atoi(hit.ID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+		if len(hit.Highlight) > 0 {
+			highlights[id] = hit.Highlight
+		}
+	}
+
+	stored, err := r.next.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*entities.User, len(stored))
+	for _, user := range stored {
+		byID[user.ID] = user
+	}
+	result := &repositories.UserSearchResult{Users: make([]*entities.User, 0, len(ids))}
+	for _, id := range ids {
+		// Absent du stockage : supprimé, l'index n'a pas encore été mis à jour
+		if user, ok := byID[id]; ok {
+			result.Users = append(result.Users, user)
+		}
+	}
+	if len(highlights) > 0 {
+		result.Highlights = highlights
+	}
+	if facets {
+		result.Facets = map[string]map[string]int{
+			repositories.UserFacetStatus:      termsBuckets(response.Aggregations[repositories.UserFacetStatus].Buckets),
+			repositories.UserFacetEmailDomain: termsBuckets(response.Aggregations[repositories.UserFacetEmailDomain].Buckets),
+		}
+	}
+	return result, nil
+}
+
+// =============================================================================
+// AUTRES OPÉRATIONS : DÉLÉGUÉES AU STOCKAGE
+// =============================================================================
+
+func (r *ElasticsearchUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	return r.next.Create(ctx, user)
+}
+
+func (r *ElasticsearchUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	return r.next.GetById(ctx, id)
+}
+
+func (r *ElasticsearchUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	return r.next.GetByIds(ctx, ids)
+}
+
+func (r *ElasticsearchUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.next.GetByEmail(ctx, email)
+}
+
+func (r *ElasticsearchUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	return r.next.IsEmailTaken(ctx, email)
+}
+
+func (r *ElasticsearchUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	return r.next.GetByHandle(ctx, handle)
+}
+
+func (r *ElasticsearchUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	return r.next.IsHandleTaken(ctx, handle)
+}
+
+func (r *ElasticsearchUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	return r.next.Update(ctx, user)
+}
+
+func (r *ElasticsearchUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	return r.next.UpdateIfUnchanged(ctx, user, expectedUpdated)
+}
+
+func (r *ElasticsearchUserRepository) DeleteById(ctx context.Context, id int) error {
+	return r.next.DeleteById(ctx, id)
+}
+
+func (r *ElasticsearchUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	return r.next.List(ctx, limit, offset)
+}
+
+func (r *ElasticsearchUserRepository) Count(ctx context.Context) (int, error) {
+	return r.next.Count(ctx)
+}
+
+func (r *ElasticsearchUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	return r.next.EstimateCount(ctx)
+}
+
+func (r *ElasticsearchUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	return r.next.ListInactiveSince(ctx, cutoff, limit, offset)
+}
+
+func (r *ElasticsearchUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	return r.next.Each(ctx, filters, fn)
+}
+
+func (r *ElasticsearchUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	return r.next.CreateMany(ctx, users)
+}
+
+func (r *ElasticsearchUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	return r.next.UpdateMany(ctx, users)
+}
+
+func (r *ElasticsearchUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	return r.next.DeleteByIds(ctx, ids)
+}
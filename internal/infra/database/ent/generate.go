@@ -0,0 +1,4 @@
+package ent
+
+// Client ent régénéré depuis schema/ ; les migrations SQL restent la référence du schéma
+//go:generate go run -mod=mod entgo.io/ent/cmd/ent generate ./schema
@@ -0,0 +1,77 @@
+// Code generated by ent, DO NOT EDIT.
+
+package migrate
+
+import (
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/schema/field"
+)
+
+var (
+	// TrackedEventsColumns holds the columns for the "tracked_events" table.
+	TrackedEventsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt64, Increment: true},
+		{Name: "name", Type: field.TypeString},
+		{Name: "properties", Type: field.TypeJSON, SchemaType: map[string]string{"postgres": "jsonb"}},
+		{Name: "sample_rate", Type: field.TypeFloat64, Default: 1},
+		{Name: "occurred_at", Type: field.TypeTime},
+		{Name: "received_at", Type: field.TypeTime},
+		{Name: "user_id", Type: field.TypeInt, Nullable: true},
+	}
+	// TrackedEventsTable holds the schema information for the "tracked_events" table.
+	TrackedEventsTable = &schema.Table{
+		Name:       "tracked_events",
+		Columns:    TrackedEventsColumns,
+		PrimaryKey: []*schema.Column{TrackedEventsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "tracked_events_users_events",
+				Columns:    []*schema.Column{TrackedEventsColumns[6]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.SetNull,
+			},
+		},
+	}
+	// UsersColumns holds the columns for the "users" table.
+	UsersColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "email", Type: field.TypeString, Unique: true},
+		{Name: "name", Type: field.TypeString},
+		{Name: "password", Type: field.TypeString},
+		{Name: "created", Type: field.TypeTime},
+		{Name: "updated", Type: field.TypeTime},
+		{Name: "weekly_digest", Type: field.TypeBool, Default: false},
+		{Name: "phone", Type: field.TypeString, Default: ""},
+		{Name: "status", Type: field.TypeString, Default: "active"},
+		{Name: "handle", Type: field.TypeString, Unique: true, Nullable: true},
+		{Name: "last_login_at", Type: field.TypeTime, Nullable: true},
+		{Name: "cleanup_flagged_at", Type: field.TypeTime, Nullable: true},
+		{Name: "attributes", Type: field.TypeJSON, SchemaType: map[string]string{"postgres": "jsonb"}},
+		{Name: "email_undeliverable_at", Type: field.TypeTime, Nullable: true},
+		{Name: "email_undeliverable_reason", Type: field.TypeString, Default: ""},
+		{Name: "locale", Type: field.TypeString, Default: ""},
+		{Name: "time_zone", Type: field.TypeString, Default: ""},
+	}
+	// UsersTable holds the schema information for the "users" table.
+	UsersTable = &schema.Table{
+		Name:       "users",
+		Columns:    UsersColumns,
+		PrimaryKey: []*schema.Column{UsersColumns[0]},
+	}
+	// Tables holds all the tables in the schema.
+	Tables = []*schema.Table{
+		TrackedEventsTable,
+		UsersTable,
+	}
+)
+
+func init() {
+	TrackedEventsTable.ForeignKeys[0].RefTable = UsersTable
+	TrackedEventsTable.Annotation = &entsql.Annotation{
+		Table: "tracked_events",
+	}
+	UsersTable.Annotation = &entsql.Annotation{
+		Table: "users",
+	}
+}
@@ -0,0 +1,13 @@
+// Code generated by ent, DO NOT EDIT.
+
+package predicate
+
+import (
+	"entgo.io/ent/dialect/sql"
+)
+
+// TrackedEvent is the predicate function for trackedevent builders.
+type TrackedEvent func(*sql.Selector)
+
+// User is the predicate function for user builders.
+type User func(*sql.Selector)
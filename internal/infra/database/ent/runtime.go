@@ -0,0 +1,47 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"clean-archi-analytics/internal/infra/database/ent/schema"
+	"clean-archi-analytics/internal/infra/database/ent/trackedevent"
+	"clean-archi-analytics/internal/infra/database/ent/user"
+)
+
+// The init function reads all schema descriptors with runtime code
+// (default values, validators, hooks and policies) and stitches it
+// to their package variables.
+func init() {
+	trackedeventFields := schema.TrackedEvent{}.Fields()
+	_ = trackedeventFields
+	// trackedeventDescSampleRate is the schema descriptor for sample_rate field.
+	trackedeventDescSampleRate := trackedeventFields[4].Descriptor()
+	// trackedevent.DefaultSampleRate holds the default value on creation for the sample_rate field.
+	trackedevent.DefaultSampleRate = trackedeventDescSampleRate.Default.(float64)
+	userFields := schema.User{}.Fields()
+	_ = userFields
+	// userDescWeeklyDigest is the schema descriptor for weekly_digest field.
+	userDescWeeklyDigest := userFields[5].Descriptor()
+	// user.DefaultWeeklyDigest holds the default value on creation for the weekly_digest field.
+	user.DefaultWeeklyDigest = userDescWeeklyDigest.Default.(bool)
+	// userDescPhone is the schema descriptor for phone field.
+	userDescPhone := userFields[6].Descriptor()
+	// user.DefaultPhone holds the default value on creation for the phone field.
+	user.DefaultPhone = userDescPhone.Default.(string)
+	// userDescStatus is the schema descriptor for status field.
+	userDescStatus := userFields[7].Descriptor()
+	// user.DefaultStatus holds the default value on creation for the status field.
+	user.DefaultStatus = userDescStatus.Default.(string)
+	// userDescEmailUndeliverableReason is the schema descriptor for email_undeliverable_reason field.
+	userDescEmailUndeliverableReason := userFields[13].Descriptor()
+	// user.DefaultEmailUndeliverableReason holds the default value on creation for the email_undeliverable_reason field.
+	user.DefaultEmailUndeliverableReason = userDescEmailUndeliverableReason.Default.(string)
+	// userDescLocale is the schema descriptor for locale field.
+	userDescLocale := userFields[14].Descriptor()
+	// user.DefaultLocale holds the default value on creation for the locale field.
+	user.DefaultLocale = userDescLocale.Default.(string)
+	// userDescTimeZone is the schema descriptor for time_zone field.
+	userDescTimeZone := userFields[15].Descriptor()
+	// user.DefaultTimeZone holds the default value on creation for the time_zone field.
+	user.DefaultTimeZone = userDescTimeZone.Default.(string)
+}
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// TrackedEvent décrit la table tracked_events existante (migrations/0008_create_tracked_events.sql) ;
+// user_id est NULL pour les événements anonymes
+type TrackedEvent struct {
+	ent.Schema
+}
+
+func (TrackedEvent) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: "tracked_events"}}
+}
+
+func (TrackedEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("id"),
+		field.String("name"),
+		field.Int("user_id").Optional().Nillable(),
+		field.JSON("properties", map[string]any{}).
+			SchemaType(map[string]string{dialect.Postgres: "jsonb"}),
+		field.Float("sample_rate").Default(1),
+		field.Time("occurred_at"),
+		field.Time("received_at"),
+	}
+}
+
+func (TrackedEvent) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).Ref("events").Field("user_id").Unique(),
+	}
+}
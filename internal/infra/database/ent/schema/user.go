@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// User décrit la table users existante (migrations/0001 à 0006 et 0011) : ent ne la crée ni ne la modifie
+// La colonne search_vector, alimentée par la base, n'est pas exposée
+type User struct {
+	ent.Schema
+}
+
+func (User) Annotations() []schema.Annotation {
+	return []schema.Annotation{entsql.Annotation{Table: "users"}}
+}
+
+func (User) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("email").Unique(),
+		field.String("name"),
+		field.String("password").Sensitive(),
+		field.Time("created").Immutable(),
+		field.Time("updated"),
+		field.Bool("weekly_digest").Default(false),
+		field.String("phone").Default(""),
+		field.String("status").Default("active"),
+		// handle NULL tant que l'utilisateur n'en a pas choisi (index UNIQUE users_handle_key)
+		field.String("handle").Optional().Nillable().Unique(),
+		field.Time("last_login_at").Optional().Nillable(),
+		field.Time("cleanup_flagged_at").Optional().Nillable(),
+		field.JSON("attributes", map[string]any{}).
+			SchemaType(map[string]string{dialect.Postgres: "jsonb"}),
+		field.Time("email_undeliverable_at").Optional().Nillable(),
+		field.String("email_undeliverable_reason").Default(""),
+		field.String("locale").Default(""),
+		field.String("time_zone").Default(""),
+	}
+}
+
+// Edges events : chargement anticipé des événements analytics (client.User.Query().WithEvents())
+func (User) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("events", TrackedEvent.Type),
+	}
+}
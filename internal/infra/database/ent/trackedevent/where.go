@@ -0,0 +1,334 @@
+// Code generated by ent, DO NOT EDIT.
+
+package trackedevent
+
+import (
+	"clean-archi-analytics/internal/infra/database/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLTE(FieldID, id))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldName, v))
+}
+
+// UserID applies equality check predicate on the "user_id" field. It's identical to UserIDEQ.
+func UserID(v int) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldUserID, v))
+}
+
+// SampleRate applies equality check predicate on the "sample_rate" field. It's identical to SampleRateEQ.
+func SampleRate(v float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldSampleRate, v))
+}
+
+// OccurredAt applies equality check predicate on the "occurred_at" field. It's identical to OccurredAtEQ.
+func OccurredAt(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldOccurredAt, v))
+}
+
+// ReceivedAt applies equality check predicate on the "received_at" field. It's identical to ReceivedAtEQ.
+func ReceivedAt(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldReceivedAt, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldContainsFold(FieldName, v))
+}
+
+// UserIDEQ applies the EQ predicate on the "user_id" field.
+func UserIDEQ(v int) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldUserID, v))
+}
+
+// UserIDNEQ applies the NEQ predicate on the "user_id" field.
+func UserIDNEQ(v int) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNEQ(FieldUserID, v))
+}
+
+// UserIDIn applies the In predicate on the "user_id" field.
+func UserIDIn(vs ...int) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldIn(FieldUserID, vs...))
+}
+
+// UserIDNotIn applies the NotIn predicate on the "user_id" field.
+func UserIDNotIn(vs ...int) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNotIn(FieldUserID, vs...))
+}
+
+// UserIDIsNil applies the IsNil predicate on the "user_id" field.
+func UserIDIsNil() predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldIsNull(FieldUserID))
+}
+
+// UserIDNotNil applies the NotNil predicate on the "user_id" field.
+func UserIDNotNil() predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNotNull(FieldUserID))
+}
+
+// SampleRateEQ applies the EQ predicate on the "sample_rate" field.
+func SampleRateEQ(v float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldSampleRate, v))
+}
+
+// SampleRateNEQ applies the NEQ predicate on the "sample_rate" field.
+func SampleRateNEQ(v float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNEQ(FieldSampleRate, v))
+}
+
+// SampleRateIn applies the In predicate on the "sample_rate" field.
+func SampleRateIn(vs ...float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldIn(FieldSampleRate, vs...))
+}
+
+// SampleRateNotIn applies the NotIn predicate on the "sample_rate" field.
+func SampleRateNotIn(vs ...float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNotIn(FieldSampleRate, vs...))
+}
+
+// SampleRateGT applies the GT predicate on the "sample_rate" field.
+func SampleRateGT(v float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGT(FieldSampleRate, v))
+}
+
+// SampleRateGTE applies the GTE predicate on the "sample_rate" field.
+func SampleRateGTE(v float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGTE(FieldSampleRate, v))
+}
+
+// SampleRateLT applies the LT predicate on the "sample_rate" field.
+func SampleRateLT(v float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLT(FieldSampleRate, v))
+}
+
+// SampleRateLTE applies the LTE predicate on the "sample_rate" field.
+func SampleRateLTE(v float64) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLTE(FieldSampleRate, v))
+}
+
+// OccurredAtEQ applies the EQ predicate on the "occurred_at" field.
+func OccurredAtEQ(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldOccurredAt, v))
+}
+
+// OccurredAtNEQ applies the NEQ predicate on the "occurred_at" field.
+func OccurredAtNEQ(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNEQ(FieldOccurredAt, v))
+}
+
+// OccurredAtIn applies the In predicate on the "occurred_at" field.
+func OccurredAtIn(vs ...time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldIn(FieldOccurredAt, vs...))
+}
+
+// OccurredAtNotIn applies the NotIn predicate on the "occurred_at" field.
+func OccurredAtNotIn(vs ...time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNotIn(FieldOccurredAt, vs...))
+}
+
+// OccurredAtGT applies the GT predicate on the "occurred_at" field.
+func OccurredAtGT(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGT(FieldOccurredAt, v))
+}
+
+// OccurredAtGTE applies the GTE predicate on the "occurred_at" field.
+func OccurredAtGTE(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGTE(FieldOccurredAt, v))
+}
+
+// OccurredAtLT applies the LT predicate on the "occurred_at" field.
+func OccurredAtLT(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLT(FieldOccurredAt, v))
+}
+
+// OccurredAtLTE applies the LTE predicate on the "occurred_at" field.
+func OccurredAtLTE(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLTE(FieldOccurredAt, v))
+}
+
+// ReceivedAtEQ applies the EQ predicate on the "received_at" field.
+func ReceivedAtEQ(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldEQ(FieldReceivedAt, v))
+}
+
+// ReceivedAtNEQ applies the NEQ predicate on the "received_at" field.
+func ReceivedAtNEQ(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNEQ(FieldReceivedAt, v))
+}
+
+// ReceivedAtIn applies the In predicate on the "received_at" field.
+func ReceivedAtIn(vs ...time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldIn(FieldReceivedAt, vs...))
+}
+
+// ReceivedAtNotIn applies the NotIn predicate on the "received_at" field.
+func ReceivedAtNotIn(vs ...time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldNotIn(FieldReceivedAt, vs...))
+}
+
+// ReceivedAtGT applies the GT predicate on the "received_at" field.
+func ReceivedAtGT(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGT(FieldReceivedAt, v))
+}
+
+// ReceivedAtGTE applies the GTE predicate on the "received_at" field.
+func ReceivedAtGTE(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldGTE(FieldReceivedAt, v))
+}
+
+// ReceivedAtLT applies the LT predicate on the "received_at" field.
+func ReceivedAtLT(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLT(FieldReceivedAt, v))
+}
+
+// ReceivedAtLTE applies the LTE predicate on the "received_at" field.
+func ReceivedAtLTE(v time.Time) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.FieldLTE(FieldReceivedAt, v))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.TrackedEvent {
+	return predicate.TrackedEvent(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.TrackedEvent {
+	return predicate.TrackedEvent(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.TrackedEvent) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.TrackedEvent) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.TrackedEvent) predicate.TrackedEvent {
+	return predicate.TrackedEvent(sql.NotPredicates(p))
+}
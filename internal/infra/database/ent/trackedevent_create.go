@@ -0,0 +1,303 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"clean-archi-analytics/internal/infra/database/ent/trackedevent"
+	"clean-archi-analytics/internal/infra/database/ent/user"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// TrackedEventCreate is the builder for creating a TrackedEvent entity.
+type TrackedEventCreate struct {
+	config
+	mutation *TrackedEventMutation
+	hooks    []Hook
+}
+
+// SetName sets the "name" field.
+func (_c *TrackedEventCreate) SetName(v string) *TrackedEventCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *TrackedEventCreate) SetUserID(v int) *TrackedEventCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_c *TrackedEventCreate) SetNillableUserID(v *int) *TrackedEventCreate {
+	if v != nil {
+		_c.SetUserID(*v)
+	}
+	return _c
+}
+
+// SetProperties sets the "properties" field.
+func (_c *TrackedEventCreate) SetProperties(v map[string]interface{}) *TrackedEventCreate {
+	_c.mutation.SetProperties(v)
+	return _c
+}
+
+// SetSampleRate sets the "sample_rate" field.
+func (_c *TrackedEventCreate) SetSampleRate(v float64) *TrackedEventCreate {
+	_c.mutation.SetSampleRate(v)
+	return _c
+}
+
+// SetNillableSampleRate sets the "sample_rate" field if the given value is not nil.
+func (_c *TrackedEventCreate) SetNillableSampleRate(v *float64) *TrackedEventCreate {
+	if v != nil {
+		_c.SetSampleRate(*v)
+	}
+	return _c
+}
+
+// SetOccurredAt sets the "occurred_at" field.
+func (_c *TrackedEventCreate) SetOccurredAt(v time.Time) *TrackedEventCreate {
+	_c.mutation.SetOccurredAt(v)
+	return _c
+}
+
+// SetReceivedAt sets the "received_at" field.
+func (_c *TrackedEventCreate) SetReceivedAt(v time.Time) *TrackedEventCreate {
+	_c.mutation.SetReceivedAt(v)
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *TrackedEventCreate) SetID(v int64) *TrackedEventCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *TrackedEventCreate) SetUser(v *User) *TrackedEventCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// Mutation returns the TrackedEventMutation object of the builder.
+func (_c *TrackedEventCreate) Mutation() *TrackedEventMutation {
+	return _c.mutation
+}
+
+// Save creates the TrackedEvent in the database.
+func (_c *TrackedEventCreate) Save(ctx context.Context) (*TrackedEvent, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *TrackedEventCreate) SaveX(ctx context.Context) *TrackedEvent {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *TrackedEventCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *TrackedEventCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *TrackedEventCreate) defaults() {
+	if _, ok := _c.mutation.SampleRate(); !ok {
+		v := trackedevent.DefaultSampleRate
+		_c.mutation.SetSampleRate(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *TrackedEventCreate) check() error {
+	if _, ok := _c.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "TrackedEvent.name"`)}
+	}
+	if _, ok := _c.mutation.Properties(); !ok {
+		return &ValidationError{Name: "properties", err: errors.New(`ent: missing required field "TrackedEvent.properties"`)}
+	}
+	if _, ok := _c.mutation.SampleRate(); !ok {
+		return &ValidationError{Name: "sample_rate", err: errors.New(`ent: missing required field "TrackedEvent.sample_rate"`)}
+	}
+	if _, ok := _c.mutation.OccurredAt(); !ok {
+		return &ValidationError{Name: "occurred_at", err: errors.New(`ent: missing required field "TrackedEvent.occurred_at"`)}
+	}
+	if _, ok := _c.mutation.ReceivedAt(); !ok {
+		return &ValidationError{Name: "received_at", err: errors.New(`ent: missing required field "TrackedEvent.received_at"`)}
+	}
+	return nil
+}
+
+func (_c *TrackedEventCreate) sqlSave(ctx context.Context) (*TrackedEvent, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != _node.ID {
+		id := _spec.ID.Value.(int64)
+		_node.ID = int64(id)
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *TrackedEventCreate) createSpec() (*TrackedEvent, *sqlgraph.CreateSpec) {
+	var (
+		_node = &TrackedEvent{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(trackedevent.Table, sqlgraph.NewFieldSpec(trackedevent.FieldID, field.TypeInt64))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(trackedevent.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.Properties(); ok {
+		_spec.SetField(trackedevent.FieldProperties, field.TypeJSON, value)
+		_node.Properties = value
+	}
+	if value, ok := _c.mutation.SampleRate(); ok {
+		_spec.SetField(trackedevent.FieldSampleRate, field.TypeFloat64, value)
+		_node.SampleRate = value
+	}
+	if value, ok := _c.mutation.OccurredAt(); ok {
+		_spec.SetField(trackedevent.FieldOccurredAt, field.TypeTime, value)
+		_node.OccurredAt = value
+	}
+	if value, ok := _c.mutation.ReceivedAt(); ok {
+		_spec.SetField(trackedevent.FieldReceivedAt, field.TypeTime, value)
+		_node.ReceivedAt = value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   trackedevent.UserTable,
+			Columns: []string{trackedevent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// TrackedEventCreateBulk is the builder for creating many TrackedEvent entities in bulk.
+type TrackedEventCreateBulk struct {
+	config
+	err      error
+	builders []*TrackedEventCreate
+}
+
+// Save creates the TrackedEvent entities in the database.
+func (_c *TrackedEventCreateBulk) Save(ctx context.Context) ([]*TrackedEvent, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*TrackedEvent, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*TrackedEventMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil && nodes[i].ID == 0 {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int64(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *TrackedEventCreateBulk) SaveX(ctx context.Context) []*TrackedEvent {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *TrackedEventCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *TrackedEventCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
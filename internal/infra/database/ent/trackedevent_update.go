@@ -0,0 +1,471 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"clean-archi-analytics/internal/infra/database/ent/predicate"
+	"clean-archi-analytics/internal/infra/database/ent/trackedevent"
+	"clean-archi-analytics/internal/infra/database/ent/user"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// TrackedEventUpdate is the builder for updating TrackedEvent entities.
+type TrackedEventUpdate struct {
+	config
+	hooks    []Hook
+	mutation *TrackedEventMutation
+}
+
+// Where appends a list predicates to the TrackedEventUpdate builder.
+func (_u *TrackedEventUpdate) Where(ps ...predicate.TrackedEvent) *TrackedEventUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetName sets the "name" field.
+func (_u *TrackedEventUpdate) SetName(v string) *TrackedEventUpdate {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *TrackedEventUpdate) SetNillableName(v *string) *TrackedEventUpdate {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *TrackedEventUpdate) SetUserID(v int) *TrackedEventUpdate {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *TrackedEventUpdate) SetNillableUserID(v *int) *TrackedEventUpdate {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *TrackedEventUpdate) ClearUserID() *TrackedEventUpdate {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetProperties sets the "properties" field.
+func (_u *TrackedEventUpdate) SetProperties(v map[string]interface{}) *TrackedEventUpdate {
+	_u.mutation.SetProperties(v)
+	return _u
+}
+
+// SetSampleRate sets the "sample_rate" field.
+func (_u *TrackedEventUpdate) SetSampleRate(v float64) *TrackedEventUpdate {
+	_u.mutation.ResetSampleRate()
+	_u.mutation.SetSampleRate(v)
+	return _u
+}
+
+// SetNillableSampleRate sets the "sample_rate" field if the given value is not nil.
+func (_u *TrackedEventUpdate) SetNillableSampleRate(v *float64) *TrackedEventUpdate {
+	if v != nil {
+		_u.SetSampleRate(*v)
+	}
+	return _u
+}
+
+// AddSampleRate adds value to the "sample_rate" field.
+func (_u *TrackedEventUpdate) AddSampleRate(v float64) *TrackedEventUpdate {
+	_u.mutation.AddSampleRate(v)
+	return _u
+}
+
+// SetOccurredAt sets the "occurred_at" field.
+func (_u *TrackedEventUpdate) SetOccurredAt(v time.Time) *TrackedEventUpdate {
+	_u.mutation.SetOccurredAt(v)
+	return _u
+}
+
+// SetNillableOccurredAt sets the "occurred_at" field if the given value is not nil.
+func (_u *TrackedEventUpdate) SetNillableOccurredAt(v *time.Time) *TrackedEventUpdate {
+	if v != nil {
+		_u.SetOccurredAt(*v)
+	}
+	return _u
+}
+
+// SetReceivedAt sets the "received_at" field.
+func (_u *TrackedEventUpdate) SetReceivedAt(v time.Time) *TrackedEventUpdate {
+	_u.mutation.SetReceivedAt(v)
+	return _u
+}
+
+// SetNillableReceivedAt sets the "received_at" field if the given value is not nil.
+func (_u *TrackedEventUpdate) SetNillableReceivedAt(v *time.Time) *TrackedEventUpdate {
+	if v != nil {
+		_u.SetReceivedAt(*v)
+	}
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *TrackedEventUpdate) SetUser(v *User) *TrackedEventUpdate {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the TrackedEventMutation object of the builder.
+func (_u *TrackedEventUpdate) Mutation() *TrackedEventMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *TrackedEventUpdate) ClearUser() *TrackedEventUpdate {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *TrackedEventUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *TrackedEventUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *TrackedEventUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *TrackedEventUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *TrackedEventUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(trackedevent.Table, trackedevent.Columns, sqlgraph.NewFieldSpec(trackedevent.FieldID, field.TypeInt64))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(trackedevent.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Properties(); ok {
+		_spec.SetField(trackedevent.FieldProperties, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.SampleRate(); ok {
+		_spec.SetField(trackedevent.FieldSampleRate, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedSampleRate(); ok {
+		_spec.AddField(trackedevent.FieldSampleRate, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.OccurredAt(); ok {
+		_spec.SetField(trackedevent.FieldOccurredAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.ReceivedAt(); ok {
+		_spec.SetField(trackedevent.FieldReceivedAt, field.TypeTime, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   trackedevent.UserTable,
+			Columns: []string{trackedevent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   trackedevent.UserTable,
+			Columns: []string{trackedevent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{trackedevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// TrackedEventUpdateOne is the builder for updating a single TrackedEvent entity.
+type TrackedEventUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *TrackedEventMutation
+}
+
+// SetName sets the "name" field.
+func (_u *TrackedEventUpdateOne) SetName(v string) *TrackedEventUpdateOne {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *TrackedEventUpdateOne) SetNillableName(v *string) *TrackedEventUpdateOne {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *TrackedEventUpdateOne) SetUserID(v int) *TrackedEventUpdateOne {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *TrackedEventUpdateOne) SetNillableUserID(v *int) *TrackedEventUpdateOne {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *TrackedEventUpdateOne) ClearUserID() *TrackedEventUpdateOne {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetProperties sets the "properties" field.
+func (_u *TrackedEventUpdateOne) SetProperties(v map[string]interface{}) *TrackedEventUpdateOne {
+	_u.mutation.SetProperties(v)
+	return _u
+}
+
+// SetSampleRate sets the "sample_rate" field.
+func (_u *TrackedEventUpdateOne) SetSampleRate(v float64) *TrackedEventUpdateOne {
+	_u.mutation.ResetSampleRate()
+	_u.mutation.SetSampleRate(v)
+	return _u
+}
+
+// SetNillableSampleRate sets the "sample_rate" field if the given value is not nil.
+func (_u *TrackedEventUpdateOne) SetNillableSampleRate(v *float64) *TrackedEventUpdateOne {
+	if v != nil {
+		_u.SetSampleRate(*v)
+	}
+	return _u
+}
+
+// AddSampleRate adds value to the "sample_rate" field.
+func (_u *TrackedEventUpdateOne) AddSampleRate(v float64) *TrackedEventUpdateOne {
+	_u.mutation.AddSampleRate(v)
+	return _u
+}
+
+// SetOccurredAt sets the "occurred_at" field.
+func (_u *TrackedEventUpdateOne) SetOccurredAt(v time.Time) *TrackedEventUpdateOne {
+	_u.mutation.SetOccurredAt(v)
+	return _u
+}
+
+// SetNillableOccurredAt sets the "occurred_at" field if the given value is not nil.
+func (_u *TrackedEventUpdateOne) SetNillableOccurredAt(v *time.Time) *TrackedEventUpdateOne {
+	if v != nil {
+		_u.SetOccurredAt(*v)
+	}
+	return _u
+}
+
+// SetReceivedAt sets the "received_at" field.
+func (_u *TrackedEventUpdateOne) SetReceivedAt(v time.Time) *TrackedEventUpdateOne {
+	_u.mutation.SetReceivedAt(v)
+	return _u
+}
+
+// SetNillableReceivedAt sets the "received_at" field if the given value is not nil.
+func (_u *TrackedEventUpdateOne) SetNillableReceivedAt(v *time.Time) *TrackedEventUpdateOne {
+	if v != nil {
+		_u.SetReceivedAt(*v)
+	}
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *TrackedEventUpdateOne) SetUser(v *User) *TrackedEventUpdateOne {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the TrackedEventMutation object of the builder.
+func (_u *TrackedEventUpdateOne) Mutation() *TrackedEventMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *TrackedEventUpdateOne) ClearUser() *TrackedEventUpdateOne {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Where appends a list predicates to the TrackedEventUpdate builder.
+func (_u *TrackedEventUpdateOne) Where(ps ...predicate.TrackedEvent) *TrackedEventUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *TrackedEventUpdateOne) Select(field string, fields ...string) *TrackedEventUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated TrackedEvent entity.
+func (_u *TrackedEventUpdateOne) Save(ctx context.Context) (*TrackedEvent, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *TrackedEventUpdateOne) SaveX(ctx context.Context) *TrackedEvent {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *TrackedEventUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *TrackedEventUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (_u *TrackedEventUpdateOne) sqlSave(ctx context.Context) (_node *TrackedEvent, err error) {
+	_spec := sqlgraph.NewUpdateSpec(trackedevent.Table, trackedevent.Columns, sqlgraph.NewFieldSpec(trackedevent.FieldID, field.TypeInt64))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "TrackedEvent.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, trackedevent.FieldID)
+		for _, f := range fields {
+			if !trackedevent.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != trackedevent.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(trackedevent.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Properties(); ok {
+		_spec.SetField(trackedevent.FieldProperties, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.SampleRate(); ok {
+		_spec.SetField(trackedevent.FieldSampleRate, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedSampleRate(); ok {
+		_spec.AddField(trackedevent.FieldSampleRate, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.OccurredAt(); ok {
+		_spec.SetField(trackedevent.FieldOccurredAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.ReceivedAt(); ok {
+		_spec.SetField(trackedevent.FieldReceivedAt, field.TypeTime, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   trackedevent.UserTable,
+			Columns: []string{trackedevent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   trackedevent.UserTable,
+			Columns: []string{trackedevent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &TrackedEvent{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{trackedevent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
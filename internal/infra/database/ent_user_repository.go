@@ -0,0 +1,567 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/infra/database/ent"
+	"clean-archi-analytics/internal/infra/database/ent/predicate"
+	entuser "clean-archi-analytics/internal/infra/database/ent/user"
+	"clean-archi-analytics/internal/infra/database/sqlcdb"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+)
+
+// EntUserRepository implémente repositories.UserSearchRepository avec ent (dialecte PostgreSQL),
+// sur les mêmes tables que SQLUserRepository : les deux adaptateurs sont interchangeables (SQL_ADAPTER)
+// Le schéma est décrit en Go (ent/schema) mais les migrations SQL restent la référence ;
+// Client donne accès aux requêtes ent avec chargement anticipé (WithEvents)
+type EntUserRepository struct {
+	client  *ent.Client
+	queries *sqlcdb.Queries
+}
+
+// NewEntUserRepository le pool reste géré par l'appelant
+func NewEntUserRepository(db *sql.DB) *EntUserRepository {
+	return &EntUserRepository{
+		client:  ent.NewClient(ent.Driver(entsql.OpenDB(dialect.Postgres, db))),
+		queries: sqlcdb.New(db),
+	}
+}
+
+// Client client ent généré, pour les lectures hors du contrat UserRepository
+func (r *EntUserRepository) Client() *ent.Client {
+	return r.client
+}
+
+func (r *EntUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	// Vérification préalable pour un message clair ; la contrainte UNIQUE reste la garantie finale
+	taken, err := r.IsEmailTaken(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errors.New("email déjà utilisé")
+	}
+
+	saved, err := r.createBuilder(r.client.User, user).Save(ctx)
+	if err != nil {
+		return nil, handleConstraintError(err)
+	}
+	created := *user
+	created.ID = saved.ID
+	return &created, nil
+}
+
+func (r *EntUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	return getEntUser(r.client.User.Get(ctx, id))
+}
+
+func (r *EntUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	users := make([]*entities.User, 0, len(ids))
+	for start := 0; start < len(ids); start += sqlBatchSize {
+		batch, err := r.client.User.Query().
+			Where(entuser.IDIn(ids[start:min(start+sqlBatchSize, len(ids))]...)).
+			All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		users = appendEntUsers(users, batch)
+	}
+	return users, nil
+}
+
+func (r *EntUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return getEntUser(r.client.User.Query().Where(entuser.EmailEQ(email)).Only(ctx))
+}
+
+func (r *EntUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	return r.client.User.Query().Where(entuser.EmailEQ(email)).Exist(ctx)
+}
+
+func (r *EntUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	return getEntUser(r.client.User.Query().Where(entuser.HandleEQ(handle)).Only(ctx))
+}
+
+func (r *EntUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	return r.client.User.Query().Where(entuser.HandleEQ(handle)).Exist(ctx)
+}
+
+func (r *EntUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	if err := r.checkUpdateUniqueness(ctx, user); err != nil {
+		return nil, err
+	}
+
+	affected, err := r.updateBuilder(r.client.User, user).Save(ctx)
+	if err != nil {
+		return nil, handleConstraintError(err)
+	}
+	if affected == 0 {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	updated := *user
+	return &updated, nil
+}
+
+// UpdateIfUnchanged UPDATE ... WHERE id = ? AND updated = ? : aucune ligne touchée et un
+// utilisateur toujours présent signifient qu'il a été modifié entre-temps
+func (r *EntUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	if err := r.checkUpdateUniqueness(ctx, user); err != nil {
+		return nil, err
+	}
+
+	affected, err := r.updateBuilder(r.client.User, user).Where(entuser.UpdatedEQ(expectedUpdated)).Save(ctx)
+	if err != nil {
+		return nil, handleConstraintError(err)
+	}
+	if affected == 0 {
+		exists, err := r.client.User.Query().Where(entuser.IDEQ(user.ID)).Exist(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, repositories.ErrUserModified
+		}
+		return nil, repositories.ErrUserNotFound
+	}
+
+	updated := *user
+	return &updated, nil
+}
+
+// checkUpdateUniqueness email et handle libres ou déjà à l'utilisateur (message clair avant la contrainte)
+func (r *EntUserRepository) checkUpdateUniqueness(ctx context.Context, user *entities.User) error {
+	taken, err := r.client.User.Query().Where(entuser.EmailEQ(user.Email), entuser.IDNEQ(user.ID)).Exist(ctx)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("email déjà utilisé")
+	}
+	if user.Handle != "" {
+		taken, err := r.client.User.Query().Where(entuser.HandleEQ(user.Handle), entuser.IDNEQ(user.ID)).Exist(ctx)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return repositories.ErrHandleTaken
+		}
+	}
+	return nil
+}
+
+func (r *EntUserRepository) DeleteById(ctx context.Context, id int) error {
+	err := r.client.User.DeleteOneID(id).Exec(ctx)
+	if ent.IsNotFound(err) {
+		return repositories.ErrUserNotFound
+	}
+	return err
+}
+
+func (r *EntUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	users, err := r.client.User.Query().
+		Order(entuser.ByID()).
+		Limit(limit).
+		Offset(offset).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return appendEntUsers(make([]*entities.User, 0, len(users)), users), nil
+}
+
+func (r *EntUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	// COALESCE(last_login_at, created) < cutoff, sous la forme servie par users_last_activity_idx
+	users, err := r.client.User.Query().
+		Where(
+			entuser.StatusEQ(string(entities.UserStatusActive)),
+			predicate.User(func(s *entsql.Selector) {
+				s.Where(entsql.P(func(b *entsql.Builder) {
+					b.WriteString("COALESCE(").Ident(s.C(entuser.FieldLastLoginAt)).Comma().
+						Ident(s.C(entuser.FieldCreated)).WriteString(") < ").Arg(cutoff)
+				}))
+			}),
+		).
+		Order(entuser.ByID()).
+		Limit(limit).
+		Offset(offset).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return appendEntUsers(make([]*entities.User, 0, len(users)), users), nil
+}
+
+// Search mêmes critères et même classement que SQLUserRepository.Search (index users_search_vector_idx)
+func (r *EntUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	predicates, err := entUserPredicates(filters)
+	if err != nil {
+		return nil, err
+	}
+	query := r.client.User.Query().Where(predicates...)
+
+	if filters.Query != "" {
+		terms := searchTerms(filters.Query)
+		if len(terms) == 0 {
+			return []*entities.User{}, nil
+		}
+		for i, term := range terms {
+			terms[i] = term + ":*"
+		}
+		tsquery := strings.Join(terms, " & ")
+		query = query.
+			Where(predicate.User(func(s *entsql.Selector) {
+				s.Where(entsql.P(func(b *entsql.Builder) {
+					b.Ident(s.C("search_vector")).WriteString(" @@ to_tsquery('simple', ").Arg(tsquery).WriteString(")")
+				}))
+			})).
+			Order(entuser.OrderOption(func(s *entsql.Selector) {
+				// ExprFunc (et non OrderExprFunc) : le paramètre est numéroté après ceux du WHERE
+				s.OrderExpr(entsql.ExprFunc(func(b *entsql.Builder) {
+					b.WriteString("ts_rank(").Ident(s.C("search_vector")).WriteString(", to_tsquery('simple', ").
+						Arg(tsquery).WriteString(")) DESC")
+				}))
+			}))
+	}
+	query = query.Order(entuser.ByID())
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	users, err := query.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return appendEntUsers(make([]*entities.User, 0, len(users)), users), nil
+}
+
+// Each lots lus par keyset (id > dernier ID vu), comme SQLUserRepository.Each
+func (r *EntUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	if filters.Query != "" {
+		return repositories.ErrFullTextNotSupported
+	}
+	predicates, err := entUserPredicates(filters)
+	if err != nil {
+		return err
+	}
+
+	lastID := 0
+	for {
+		batch, err := r.client.User.Query().
+			Where(predicates...).
+			Where(entuser.IDGT(lastID)).
+			Order(entuser.ByID()).
+			Limit(userIterationBatchSize).
+			All(ctx)
+		if err != nil {
+			return err
+		}
+		for _, saved := range batch {
+			if err := fn(userFromEnt(saved)); err != nil {
+				if errors.Is(err, repositories.ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		if len(batch) < userIterationBatchSize {
+			return nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+func (r *EntUserRepository) Count(ctx context.Context) (int, error) {
+	return r.client.User.Query().Count(ctx)
+}
+
+// EstimateCount statistiques du planificateur, hors du schéma ent : même requête que SQLUserRepository
+func (r *EntUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	estimate, err := r.queries.EstimateUserCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if estimate <= 0 {
+		return r.Count(ctx)
+	}
+	return int(estimate), nil
+}
+
+// CreateMany insère les utilisateurs par INSERT multi-lignes (CreateBulk) dans une seule transaction
+func (r *EntUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if len(users) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	created := make([]*entities.User, 0, len(users))
+	err := r.withTx(ctx, func(tx *ent.Tx) error {
+		emails := make([]string, len(users))
+		for i, user := range users {
+			emails[i] = user.Email
+		}
+		taken, err := tx.User.Query().Where(entuser.EmailIn(emails...)).Exist(ctx)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errors.New("email déjà utilisé")
+		}
+
+		for start := 0; start < len(users); start += sqlBatchSize {
+			batch := users[start:min(start+sqlBatchSize, len(users))]
+			builders := make([]*ent.UserCreate, len(batch))
+			for i, user := range batch {
+				builders[i] = r.createBuilder(tx.User, user)
+			}
+			saved, err := tx.User.CreateBulk(builders...).Save(ctx)
+			if err != nil {
+				return handleConstraintError(err)
+			}
+			for i, user := range batch {
+				userCopy := *user
+				userCopy.ID = saved[i].ID
+				created = append(created, &userCopy)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateMany un UPDATE par utilisateur, dans une seule transaction
+func (r *EntUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if len(users) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	err := r.withTx(ctx, func(tx *ent.Tx) error {
+		for _, user := range users {
+			affected, err := r.updateBuilder(tx.User, user).Save(ctx)
+			if err != nil {
+				// Violation de la contrainte UNIQUE sur email : le lot entier est annulé
+				return handleConstraintError(err)
+			}
+			if affected == 0 {
+				return repositories.ErrUserNotFound
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]*entities.User, len(users))
+	for i, user := range users {
+		userCopy := *user
+		updated[i] = &userCopy
+	}
+	return updated, nil
+}
+
+// DeleteByIds annule tout si un ID est inconnu
+func (r *EntUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return r.withTx(ctx, func(tx *ent.Tx) error {
+		for start := 0; start < len(ids); start += sqlBatchSize {
+			batch := ids[start:min(start+sqlBatchSize, len(ids))]
+			affected, err := tx.User.Delete().Where(entuser.IDIn(batch...)).Exec(ctx)
+			if err != nil {
+				return err
+			}
+			if affected != len(batch) {
+				return repositories.ErrUserNotFound
+			}
+		}
+		return nil
+	})
+}
+
+// withTx valide si fn réussit, annule sinon
+func (r *EntUserRepository) withTx(ctx context.Context, fn func(tx *ent.Tx) error) error {
+	tx, err := r.client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// createBuilder colonnes écrites à l'inscription ; handle vide et dates absentes restent NULL
+func (r *EntUserRepository) createBuilder(client *ent.UserClient, user *entities.User) *ent.UserCreate {
+	create := client.Create().
+		SetEmail(user.Email).
+		SetName(user.Name).
+		SetPassword(user.Password).
+		SetCreated(user.Created).
+		SetUpdated(user.Updated).
+		SetWeeklyDigest(user.WeeklyDigest).
+		SetPhone(user.Phone).
+		SetStatus(string(user.CurrentStatus())).
+		SetNillableLastLoginAt(user.LastLoginAt).
+		SetNillableCleanupFlaggedAt(user.CleanupFlaggedAt).
+		SetNillableEmailUndeliverableAt(user.EmailUndeliverableAt).
+		SetEmailUndeliverableReason(user.EmailUndeliverableReason).
+		SetLocale(user.Locale).
+		SetTimeZone(user.TimeZone).
+		SetAttributes(entAttributes(user.Attributes))
+	if user.Handle != "" {
+		create.SetHandle(user.Handle)
+	}
+	return create
+}
+
+// updateBuilder toutes les colonnes modifiables ; les champs vides ou nil remettent la colonne à NULL
+func (r *EntUserRepository) updateBuilder(client *ent.UserClient, user *entities.User) *ent.UserUpdate {
+	update := client.Update().
+		Where(entuser.IDEQ(user.ID)).
+		SetEmail(user.Email).
+		SetName(user.Name).
+		SetPassword(user.Password).
+		SetUpdated(user.Updated).
+		SetWeeklyDigest(user.WeeklyDigest).
+		SetPhone(user.Phone).
+		SetStatus(string(user.CurrentStatus())).
+		SetEmailUndeliverableReason(user.EmailUndeliverableReason).
+		SetLocale(user.Locale).
+		SetTimeZone(user.TimeZone).
+		SetAttributes(entAttributes(user.Attributes))
+	if user.Handle != "" {
+		update.SetHandle(user.Handle)
+	} else {
+		update.ClearHandle()
+	}
+	if user.LastLoginAt != nil {
+		update.SetLastLoginAt(*user.LastLoginAt)
+	} else {
+		update.ClearLastLoginAt()
+	}
+	if user.CleanupFlaggedAt != nil {
+		update.SetCleanupFlaggedAt(*user.CleanupFlaggedAt)
+	} else {
+		update.ClearCleanupFlaggedAt()
+	}
+	if user.EmailUndeliverableAt != nil {
+		update.SetEmailUndeliverableAt(*user.EmailUndeliverableAt)
+	} else {
+		update.ClearEmailUndeliverableAt()
+	}
+	return update
+}
+
+// entUserPredicates critères de filters hors Query, Limit et Offset (même périmètre que newUserFilterMatcher)
+func entUserPredicates(filters repositories.UserRepositoryFilters) ([]predicate.User, error) {
+	from, to, err := parseCreatedRange(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var predicates []predicate.User
+	if filters.Email != "" {
+		predicates = append(predicates, entuser.EmailContainsFold(filters.Email))
+	}
+	if filters.Name != "" {
+		predicates = append(predicates, entuser.NameContainsFold(filters.Name))
+	}
+	if filters.Status != "" {
+		predicates = append(predicates, entuser.StatusEQ(filters.Status))
+	}
+	if !from.IsZero() {
+		predicates = append(predicates, entuser.CreatedGTE(from))
+	}
+	if !to.IsZero() {
+		predicates = append(predicates, entuser.CreatedLT(to))
+	}
+	if len(filters.Attributes) > 0 {
+		// Containment JSONB : servi par l'index GIN users_attributes_idx
+		attributes, err := encodeAttributes(filters.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate.User(func(s *entsql.Selector) {
+			s.Where(entsql.P(func(b *entsql.Builder) {
+				b.Ident(s.C(entuser.FieldAttributes)).WriteString(" @> ").Arg(attributes).WriteString("::jsonb")
+			}))
+		}))
+	}
+	return predicates, nil
+}
+
+func getEntUser(saved *ent.User, err error) (*entities.User, error) {
+	if ent.IsNotFound(err) {
+		return nil, repositories.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return userFromEnt(saved), nil
+}
+
+// userFromEnt handle NULL → "", attributs '{}' → nil (comme scanUser)
+func userFromEnt(saved *ent.User) *entities.User {
+	user := &entities.User{
+		ID:                       saved.ID,
+		Email:                    saved.Email,
+		Name:                     saved.Name,
+		Password:                 saved.Password,
+		Created:                  saved.Created,
+		Updated:                  saved.Updated,
+		WeeklyDigest:             saved.WeeklyDigest,
+		Phone:                    saved.Phone,
+		Status:                   entities.UserStatus(saved.Status),
+		LastLoginAt:              saved.LastLoginAt,
+		CleanupFlaggedAt:         saved.CleanupFlaggedAt,
+		EmailUndeliverableAt:     saved.EmailUndeliverableAt,
+		EmailUndeliverableReason: saved.EmailUndeliverableReason,
+		Locale:                   saved.Locale,
+		TimeZone:                 saved.TimeZone,
+	}
+	if saved.Handle != nil {
+		user.Handle = *saved.Handle
+	}
+	if len(saved.Attributes) > 0 {
+		user.Attributes = saved.Attributes
+	}
+	return user
+}
+
+func appendEntUsers(users []*entities.User, saved []*ent.User) []*entities.User {
+	for _, s := range saved {
+		users = append(users, userFromEnt(s))
+	}
+	return users
+}
+
+// entAttributes la colonne est NOT NULL : une map nil serait encodée null
+func entAttributes(attributes map[string]any) map[string]any {
+	if attributes == nil {
+		return map[string]any{}
+	}
+	return attributes
+}
+
+// handleConstraintError l'index UNIQUE users_handle_key a tranché une course entre deux écritures
+func handleConstraintError(err error) error {
+	if ent.IsConstraintError(err) && strings.Contains(err.Error(), "users_handle_key") {
+		return repositories.ErrHandleTaken
+	}
+	return err
+}
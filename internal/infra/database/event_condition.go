@@ -0,0 +1,256 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// FILTRES D'ÉVÉNEMENTS (repositories.EventCondition) : prédicats SQL et évaluation en mémoire
+// =============================================================================
+
+// eventConditionDialect traduction des comparaisons sur les propriétés, stockées en JSON ; param
+// ajoute un paramètre et retourne son placeholder. Chaque prédicat produit vaut vrai ou faux,
+// jamais NULL : NOT garde le sens qu'il a en mémoire
+type eventConditionDialect struct {
+	exists  func(param func(any) string, key string) string
+	compare func(param func(any) string, key, op string, value any) string
+}
+
+// postgresEventDialect colonne JSONB properties (migrations/0008 et 0013)
+var postgresEventDialect = eventConditionDialect{
+	exists: func(param func(any) string, key string) string {
+		return "(properties -> " + param(key) + "::text) IS NOT NULL"
+	},
+	compare: func(param func(any) string, key, op string, value any) string {
+		if op == repositories.EventOpEq {
+			// Containment : égalité typée ("1" ≠ 1), servie par l'index GIN tracked_events_properties_idx
+			document, _ := json.Marshal(map[string]any{key: value})
+			return "properties @> " + param(string(document)) + "::jsonb"
+		}
+		if _, ok := value.(float64); ok {
+			// CASE : le cast ne doit porter que sur les nombres (AND n'est pas court-circuité en SQL)
+			return "CASE WHEN jsonb_typeof(properties -> " + param(key) + "::text) = 'number' THEN (properties ->> " +
+				param(key) + "::text)::double precision " + op + " " + param(value) + " ELSE FALSE END"
+		}
+		// COLLATE "C" : ordre des octets, celui de la comparaison en mémoire
+		return "COALESCE(jsonb_typeof(properties -> " + param(key) + "::text) = 'string' AND (properties ->> " +
+			param(key) + "::text) COLLATE \"C\" " + op + " " + param(value) + ", FALSE)"
+	},
+}
+
+// clickHouseEventDialect colonne properties en JSON texte
+var clickHouseEventDialect = eventConditionDialect{
+	exists: func(param func(any) string, key string) string {
+		return "JSONHas(properties, " + param(key) + ")"
+	},
+	compare: func(param func(any) string, key, op string, value any) string {
+		types, extract := "'String'", "JSONExtractString"
+		switch value.(type) {
+		case float64:
+			types, extract = "'Int64', 'UInt64', 'Double'", "JSONExtractFloat"
+		case bool:
+			types, extract = "'Bool'", "JSONExtractBool"
+		}
+		return "(JSONType(properties, " + param(key) + ") IN (" + types + ") AND " +
+			extract + "(properties, " + param(key) + ") " + op + " " + param(value) + ")"
+	},
+}
+
+// eventConditionColumns colonnes de tracked_events par champ
+var eventConditionColumns = map[string]string{
+	repositories.EventFieldName:       "name",
+	repositories.EventFieldUserID:     "user_id",
+	repositories.EventFieldOccurredAt: "occurred_at",
+	repositories.EventFieldReceivedAt: "received_at",
+}
+
+// eventConditionOperators opérateurs de comparaison repris tels quels dans le SQL
+var eventConditionOperators = []string{
+	repositories.EventOpEq, repositories.EventOpLt, repositories.EventOpLe, repositories.EventOpGt, repositories.EventOpGe,
+}
+
+func compileEventCondition(condition repositories.EventCondition, dialect eventConditionDialect, param func(any) string) (string, error) {
+	switch typed := condition.(type) {
+	case repositories.EventAnd:
+		return compileEventOperands(typed.Left, typed.Right, " AND ", dialect, param)
+	case repositories.EventOr:
+		return compileEventOperands(typed.Left, typed.Right, " OR ", dialect, param)
+	case repositories.EventNot:
+		operand, err := compileEventCondition(typed.Operand, dialect, param)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + operand + ")", nil
+	case repositories.EventComparison:
+		return compileEventComparison(typed, dialect, param)
+	}
+	return "", fmt.Errorf("filtre d'événements : condition %T non prise en charge", condition)
+}
+
+func compileEventOperands(left, right repositories.EventCondition, operator string, dialect eventConditionDialect, param func(any) string) (string, error) {
+	compiledLeft, err := compileEventCondition(left, dialect, param)
+	if err != nil {
+		return "", err
+	}
+	compiledRight, err := compileEventCondition(right, dialect, param)
+	if err != nil {
+		return "", err
+	}
+	return "(" + compiledLeft + operator + compiledRight + ")", nil
+}
+
+func compileEventComparison(comparison repositories.EventComparison, dialect eventConditionDialect, param func(any) string) (string, error) {
+	if comparison.Op != repositories.EventOpExists && len(comparison.Values) == 0 {
+		return "", fmt.Errorf("filtre d'événements : aucune valeur pour %s", comparison.Op)
+	}
+	if comparison.Op != repositories.EventOpExists && comparison.Op != repositories.EventOpIn &&
+		!slices.Contains(eventConditionOperators, comparison.Op) {
+		return "", fmt.Errorf("filtre d'événements : opérateur %q inconnu", comparison.Op)
+	}
+
+	if comparison.Field == "" {
+		switch comparison.Op {
+		case repositories.EventOpExists:
+			return dialect.exists(param, comparison.Property), nil
+		case repositories.EventOpIn:
+			alternatives := make([]string, len(comparison.Values))
+			for i, value := range comparison.Values {
+				alternatives[i] = dialect.compare(param, comparison.Property, repositories.EventOpEq, value)
+			}
+			return "(" + strings.Join(alternatives, " OR ") + ")", nil
+		}
+		return dialect.compare(param, comparison.Property, comparison.Op, comparison.Values[0]), nil
+	}
+
+	column, ok := eventConditionColumns[comparison.Field]
+	if !ok {
+		return "", fmt.Errorf("filtre d'événements : champ %q inconnu", comparison.Field)
+	}
+	switch comparison.Op {
+	case repositories.EventOpExists:
+		return column + " IS NOT NULL", nil
+	case repositories.EventOpIn:
+		values := make([]string, len(comparison.Values))
+		for i, value := range comparison.Values {
+			values[i] = param(value)
+		}
+		return "COALESCE(" + column + " IN (" + strings.Join(values, ", ") + "), FALSE)", nil
+	}
+	// COALESCE : user_id est NULL pour un événement anonyme
+	return "COALESCE(" + column + " " + comparison.Op + " " + param(comparison.Values[0]) + ", FALSE)", nil
+}
+
+// ClickHouseEventPredicate prédicat ClickHouse (paramètres ?) de condition, pour un stockage
+// d'événements fourni par l'hôte : colonnes de tracked_events, properties en JSON texte
+func ClickHouseEventPredicate(condition repositories.EventCondition) (string, []any, error) {
+	var args []any
+	predicate, err := compileEventCondition(condition, clickHouseEventDialect, func(value any) string {
+		args = append(args, value)
+		return "?"
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return predicate, args, nil
+}
+
+// matchEventCondition évaluation en mémoire, mêmes règles que les prédicats SQL ; nil = vraie
+func matchEventCondition(condition repositories.EventCondition, event *entities.TrackedEvent) bool {
+	switch typed := condition.(type) {
+	case nil:
+		return true
+	case repositories.EventAnd:
+		return matchEventCondition(typed.Left, event) && matchEventCondition(typed.Right, event)
+	case repositories.EventOr:
+		return matchEventCondition(typed.Left, event) || matchEventCondition(typed.Right, event)
+	case repositories.EventNot:
+		return !matchEventCondition(typed.Operand, event)
+	case repositories.EventComparison:
+		return matchEventComparison(typed, event)
+	}
+	return false
+}
+
+func matchEventComparison(comparison repositories.EventComparison, event *entities.TrackedEvent) bool {
+	var actual any
+	switch comparison.Field {
+	case "":
+		actual = event.Properties[comparison.Property]
+	case repositories.EventFieldName:
+		actual = event.Name
+	case repositories.EventFieldUserID:
+		if event.UserID > 0 {
+			actual = event.UserID
+		}
+	case repositories.EventFieldOccurredAt:
+		actual = event.OccurredAt
+	case repositories.EventFieldReceivedAt:
+		actual = event.ReceivedAt
+	}
+	if actual == nil {
+		return false
+	}
+
+	switch comparison.Op {
+	case repositories.EventOpExists:
+		return true
+	case repositories.EventOpIn:
+		return slices.ContainsFunc(comparison.Values, func(value any) bool {
+			order, ok := compareEventValues(actual, value)
+			return ok && order == 0
+		})
+	}
+	if len(comparison.Values) == 0 {
+		return false
+	}
+	order, ok := compareEventValues(actual, comparison.Values[0])
+	if !ok {
+		return false
+	}
+	switch comparison.Op {
+	case repositories.EventOpEq:
+		return order == 0
+	case repositories.EventOpLt:
+		return order < 0
+	case repositories.EventOpLe:
+		return order <= 0
+	case repositories.EventOpGt:
+		return order > 0
+	case repositories.EventOpGe:
+		return order >= 0
+	}
+	return false
+}
+
+// compareEventValues false si les deux valeurs ne sont pas du même type ; les booléens ne sont
+// qu'égaux (0) ou différents (1)
+func compareEventValues(actual, expected any) (int, bool) {
+	switch typed := actual.(type) {
+	case string:
+		other, ok := expected.(string)
+		return strings.Compare(typed, other), ok
+	case float64:
+		other, ok := expected.(float64)
+		return cmp.Compare(typed, other), ok
+	case int:
+		other, ok := expected.(int)
+		return cmp.Compare(typed, other), ok
+	case bool:
+		other, ok := expected.(bool)
+		if typed == other {
+			return 0, ok
+		}
+		return 1, ok
+	case time.Time:
+		other, ok := expected.(time.Time)
+		return typed.Compare(other), ok
+	}
+	return 0, false
+}
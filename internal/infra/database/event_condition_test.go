@@ -0,0 +1,200 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	postgresPlaceholder = regexp.MustCompile(`\$[0-9]+`)
+	// sqlLiteralFragments seuls littéraux écrits par les dialectes ; tout autre guillemet viendrait
+	// de l'expression du client
+	sqlLiteralFragments = strings.NewReplacer(
+		`'number'`, "", `'string'`, "", `COLLATE "C"`, "",
+		`'String'`, "", `'Int64'`, "", `'UInt64'`, "", `'Double'`, "", `'Bool'`, "",
+	)
+)
+
+// compilePostgresEventCondition prédicat et paramètres, comme SQLEventRepository.List
+func compilePostgresEventCondition(condition repositories.EventCondition) (string, []any, error) {
+	where := sqlConditions{}
+	predicate, err := compileEventCondition(condition, postgresEventDialect, where.param)
+	return predicate, where.args, err
+}
+
+// checkEventPredicate le prédicat ne contient que des placeholders, numérotés dans l'ordre des
+// paramètres, et aucun littéral venu de l'expression
+func checkEventPredicate(t *testing.T, dialect, predicate string, args []any) {
+	t.Helper()
+	placeholders := strings.Count(predicate, "?")
+	if dialect == "postgres" {
+		found := postgresPlaceholder.FindAllString(predicate, -1)
+		placeholders = len(found)
+		for i, placeholder := range found {
+			if placeholder != "$"+strconv.Itoa(i+1) {
+				t.Fatalf("%s : placeholder %s en position %d : %s", dialect, placeholder, i+1, predicate)
+			}
+		}
+	}
+	if placeholders != len(args) {
+		t.Fatalf("%s : %d placeholders pour %d paramètres : %s", dialect, placeholders, len(args), predicate)
+	}
+	if stripped := sqlLiteralFragments.Replace(predicate); strings.ContainsAny(stripped, `'";`) || strings.Contains(stripped, "--") || strings.Contains(stripped, "/*") {
+		t.Fatalf("%s : littéral dans le prédicat : %s", dialect, predicate)
+	}
+}
+
+func TestCompileEventConditionBindsValues(t *testing.T) {
+	payloads := []string{
+		`'; DROP TABLE tracked_events; --`,
+		`" OR 1=1 --`,
+		`x') OR ('1'='1`,
+		`/* */`,
+	}
+	for _, payload := range payloads {
+		for _, filter := range []string{
+			`event = %s`,
+			`event IN ("a", %s)`,
+			`props.plan = %s`,
+			`props.plan != %s`,
+			`props.plan >= %s`,
+			`props.plan NOT IN (%s, 1, true)`,
+		} {
+			quoted, _ := json.Marshal(payload)
+			expression := strings.Replace(filter, "%s", string(quoted), 1)
+			condition, err := usecases.ParseEventFilter(expression)
+			if err != nil {
+				t.Fatalf("%s : %v", expression, err)
+			}
+
+			predicate, args, err := compilePostgresEventCondition(condition)
+			if err != nil {
+				t.Fatalf("%s : %v", expression, err)
+			}
+			checkEventPredicate(t, "postgres", predicate, args)
+			if strings.Contains(predicate, payload) {
+				t.Fatalf("%s : valeur dans le SQL : %s", expression, predicate)
+			}
+			// Égalité sur une propriété : la valeur voyage dans le document JSON paramétré
+			if !slices.ContainsFunc(args, func(arg any) bool {
+				text, _ := arg.(string)
+				return text == payload || strings.Contains(text, string(quoted))
+			}) {
+				t.Fatalf("%s : valeur absente des paramètres %v", expression, args)
+			}
+
+			predicate, args, err = ClickHouseEventPredicate(condition)
+			if err != nil {
+				t.Fatalf("%s : %v", expression, err)
+			}
+			checkEventPredicate(t, "clickhouse", predicate, args)
+			if strings.Contains(predicate, payload) || !slices.Contains(args, any(payload)) {
+				t.Fatalf("%s : valeur non paramétrée : %s %v", expression, predicate, args)
+			}
+		}
+	}
+}
+
+func TestCompileEventConditionPredicates(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		filter string
+		want   string
+		args   []any
+	}{
+		{`user_id = 42`, `COALESCE(user_id = $1, FALSE)`, []any{42}},
+		{`NOT user_id IN (1, 2)`, `NOT (COALESCE(user_id IN ($1, $2), FALSE))`, []any{1, 2}},
+		{`user_id EXISTS OR occurred_at < "2026-01-01T00:00:00Z"`, `(user_id IS NOT NULL OR COALESCE(occurred_at < $1, FALSE))`, []any{at}},
+		{`props.coupon EXISTS`, `(properties -> $1::text) IS NOT NULL`, []any{"coupon"}},
+		{`props.plan = "pro"`, `properties @> $1::jsonb`, []any{`{"plan":"pro"}`}},
+		{`props.amount > 10`, `CASE WHEN jsonb_typeof(properties -> $1::text) = 'number' THEN (properties ->> $2::text)::double precision > $3 ELSE FALSE END`, []any{"amount", "amount", 10.0}},
+	}
+	for _, tt := range tests {
+		condition, err := usecases.ParseEventFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("%s : %v", tt.filter, err)
+		}
+		predicate, args, err := compilePostgresEventCondition(condition)
+		if err != nil {
+			t.Fatalf("%s : %v", tt.filter, err)
+		}
+		if predicate != tt.want || !slices.Equal(args, tt.args) {
+			t.Errorf("%s : %s %v, attendu %s %v", tt.filter, predicate, args, tt.want, tt.args)
+		}
+	}
+}
+
+// Une condition construite hors de ParseEventFilter n'atteint jamais le SQL si elle n'est pas
+// prise en charge : champ ou opérateur repris tels quels sinon
+func TestCompileEventConditionRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition repositories.EventCondition
+	}{
+		{"champ inconnu", repositories.EventComparison{Field: "name; DROP TABLE tracked_events", Op: repositories.EventOpEq, Values: []any{"x"}}},
+		{"colonne hors filtre", repositories.EventComparison{Field: "properties", Op: repositories.EventOpExists}},
+		{"opérateur inconnu", repositories.EventComparison{Field: repositories.EventFieldName, Op: "LIKE", Values: []any{"x"}}},
+		{"opérateur ==", repositories.EventComparison{Property: "plan", Op: "==", Values: []any{"x"}}},
+		{"opérateur injecté", repositories.EventComparison{Property: "plan", Op: "= 1 OR 1 =", Values: []any{"x"}}},
+		{"sans valeur", repositories.EventComparison{Field: repositories.EventFieldUserID, Op: repositories.EventOpEq}},
+		{"IN vide", repositories.EventComparison{Field: repositories.EventFieldUserID, Op: repositories.EventOpIn}},
+		{"dans un NOT", repositories.EventNot{Operand: repositories.EventComparison{Field: "id", Op: repositories.EventOpEq, Values: []any{1}}}},
+		{"à droite d'un OR", repositories.EventOr{
+			Left:  repositories.EventComparison{Field: repositories.EventFieldUserID, Op: repositories.EventOpExists},
+			Right: repositories.EventComparison{Property: "plan", Op: "~", Values: []any{"x"}},
+		}},
+		{"condition nil", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if predicate, _, err := compilePostgresEventCondition(tt.condition); err == nil {
+				t.Fatalf("postgres : prédicat %s", predicate)
+			}
+			if predicate, _, err := ClickHouseEventPredicate(tt.condition); err == nil {
+				t.Fatalf("clickhouse : prédicat %s", predicate)
+			}
+		})
+	}
+}
+
+// `go test -fuzz=FuzzCompileEventCondition ./internal/infra/database` : toute expression acceptée
+// par ParseEventFilter se compile, sans rien du client dans le texte SQL
+func FuzzCompileEventCondition(f *testing.F) {
+	for _, seed := range []string{
+		`event = "signup" AND props.plan IN ("pro", "team")`,
+		`NOT (user_id = 42) OR props.amount >= 100 AND props.trial = true`,
+		`props.coupon EXISTS AND occurred_at >= "2026-01-01T00:00:00Z"`,
+		`props.plan != "'; DROP TABLE tracked_events; --"`,
+		`props.plan == "pro"`,
+		`occurred_at == "2026-01-01T00:00:00Z"`,
+		`NOT NOT NOT (event = "a")`,
+		`props.a >= 1AND props.b <= 2`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		condition, err := usecases.ParseEventFilter(raw)
+		if err != nil || condition == nil {
+			return
+		}
+		predicate, args, err := compilePostgresEventCondition(condition)
+		if err != nil {
+			t.Fatalf("%q accepté par ParseEventFilter, refusé par postgres : %v", raw, err)
+		}
+		checkEventPredicate(t, "postgres", predicate, args)
+
+		predicate, args, err = ClickHouseEventPredicate(condition)
+		if err != nil {
+			t.Fatalf("%q accepté par ParseEventFilter, refusé par clickhouse : %v", raw, err)
+		}
+		checkEventPredicate(t, "clickhouse", predicate, args)
+	})
+}
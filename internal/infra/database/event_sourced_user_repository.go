@@ -0,0 +1,489 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// EventSourcedUserRepository implémente repositories.UserRepository en mode event-sourcé
+// - les écritures ajoutent des événements au flux de l'utilisateur (source de vérité)
+// - GetById rejoue le flux depuis le dernier snapshot
+// - les autres lectures passent par la table d'état courant, tenue à jour par le projecteur
+type EventSourcedUserRepository struct {
+	store         repositories.UserEventStore
+	state         repositories.UserStateRepository
+	projector     *UserStateProjector
+	snapshotEvery int
+	clock         usecases.Clock
+
+	// Sérialise les écritures pour garantir l'unicité des emails entre vérification et ajout
+	writeMutex sync.Mutex
+}
+
+func NewEventSourcedUserRepository(
+	store repositories.UserEventStore,
+	state repositories.UserStateRepository,
+	snapshotEvery int,
+	clock usecases.Clock,
+) *EventSourcedUserRepository {
+	return &EventSourcedUserRepository{
+		store:         store,
+		state:         state,
+		projector:     NewUserStateProjector(state),
+		snapshotEvery: snapshotEvery,
+		clock:         clock,
+	}
+}
+
+func (r *EventSourcedUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	return r.create(ctx, user)
+}
+
+func (r *EventSourcedUserRepository) create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	taken, err := r.state.IsEmailTaken(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errors.New("email déjà utilisé")
+	}
+
+	id, err := r.store.NextID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := events.UserRegistered{
+		UserID:       id,
+		Email:        user.Email,
+		Name:         user.Name,
+		PasswordHash: user.Password,
+		Registered:   user.Created,
+	}
+
+	if err := r.append(ctx, id, 0, nil, []events.Event{registered}); err != nil {
+		return nil, err
+	}
+
+	return entities.RehydrateUser(nil, []events.Event{registered})
+}
+
+func (r *EventSourcedUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	user, _, err := r.load(ctx, id)
+	return user, err
+}
+
+// GetByIds lit la table d'état courant : rejouer N flux reviendrait à N chargements
+func (r *EventSourcedUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	return r.state.GetByIds(ctx, ids)
+}
+
+func (r *EventSourcedUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.state.GetByEmail(ctx, email)
+}
+
+func (r *EventSourcedUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	return r.state.IsEmailTaken(ctx, email)
+}
+
+func (r *EventSourcedUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	return r.state.GetByHandle(ctx, handle)
+}
+
+func (r *EventSourcedUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	return r.state.IsHandleTaken(ctx, handle)
+}
+
+// Update compare l'état rejoué à l'utilisateur reçu et n'enregistre que les faits réels
+func (r *EventSourcedUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	return r.update(ctx, user)
+}
+
+// UpdateIfUnchanged la comparaison et l'écriture se font sous writeMutex ; l'ajout au flux reste
+// de toute façon conditionné à sa version
+func (r *EventSourcedUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	current, _, err := r.load(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !current.Updated.Equal(expectedUpdated) {
+		return nil, repositories.ErrUserModified
+	}
+	return r.update(ctx, user)
+}
+
+func (r *EventSourcedUserRepository) update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	current, version, err := r.load(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []events.Event
+	if current.Email != user.Email || current.Name != user.Name {
+		if current.Email != user.Email {
+			taken, err := r.state.IsEmailTaken(ctx, user.Email)
+			if err != nil {
+				return nil, err
+			}
+			if taken {
+				return nil, errors.New("email déjà utilisé")
+			}
+		}
+		changes = append(changes, events.UserProfileUpdated{
+			UserID:        user.ID,
+			Email:         user.Email,
+			Name:          user.Name,
+			ChangedFields: entities.ChangedFields(entities.DiffProfile(current.Name, current.Email, user.Name, user.Email)),
+			Updated:       user.Updated,
+		})
+	}
+	if current.Password != user.Password {
+		changes = append(changes, events.PasswordChanged{
+			UserID:       user.ID,
+			PasswordHash: user.Password,
+			Changed:      user.Updated,
+		})
+	}
+	if current.WeeklyDigest != user.WeeklyDigest {
+		changes = append(changes, events.DigestPreferenceChanged{
+			UserID:  user.ID,
+			Enabled: user.WeeklyDigest,
+			Changed: user.Updated,
+		})
+	}
+	if current.Phone != user.Phone {
+		changes = append(changes, events.UserPhoneChanged{
+			UserID:  user.ID,
+			Phone:   user.Phone,
+			Changed: user.Updated,
+		})
+	}
+	if current.Locale != user.Locale || current.TimeZone != user.TimeZone {
+		changes = append(changes, events.UserLocaleChanged{
+			UserID:   user.ID,
+			Locale:   user.Locale,
+			TimeZone: user.TimeZone,
+			Changed:  user.Updated,
+		})
+	}
+	if current.Handle != user.Handle {
+		if user.Handle != "" {
+			// Sous writeMutex : aucune autre écriture ne peut prendre le handle entre-temps
+			owner, err := r.state.GetByHandle(ctx, user.Handle)
+			switch {
+			case err == nil && owner.ID != user.ID:
+				return nil, repositories.ErrHandleTaken
+			case err != nil && !errors.Is(err, repositories.ErrUserNotFound):
+				return nil, err
+			}
+		}
+		changes = append(changes, events.UserHandleChanged{
+			UserID:  user.ID,
+			Handle:  user.Handle,
+			Changed: user.Updated,
+		})
+	}
+	if current.CurrentStatus() != user.CurrentStatus() {
+		// Le motif n'est pas un état : il voyage dans l'événement publié par le use case
+		changes = append(changes, events.UserStatusChanged{
+			UserID:  user.ID,
+			Status:  string(user.CurrentStatus()),
+			Changed: user.Updated,
+		})
+	}
+
+	if attributeChanges := entities.DiffAttributes(current.Attributes, user.Attributes); len(attributeChanges) > 0 {
+		changes = append(changes, events.UserAttributesChanged{
+			UserID:        user.ID,
+			Attributes:    maps.Clone(user.Attributes),
+			ChangedFields: entities.ChangedFields(attributeChanges),
+			Changed:       user.Updated,
+		})
+	}
+	if user.LastLoginAt != nil && !sameTime(current.LastLoginAt, user.LastLoginAt) {
+		// Lève aussi le signalement d'inactivité au rejeu
+		changes = append(changes, events.UserLoggedIn{UserID: user.ID, At: *user.LastLoginAt})
+	}
+	if user.CleanupFlaggedAt != nil && !sameTime(current.CleanupFlaggedAt, user.CleanupFlaggedAt) {
+		changes = append(changes, events.UserFlaggedForCleanup{
+			UserID:        user.ID,
+			InactiveSince: user.LastActivity(),
+			Flagged:       *user.CleanupFlaggedAt,
+		})
+	}
+
+	// Un changement d'adresse lève le rejet au rejeu de UserProfileUpdated
+	if user.EmailUndeliverableAt != nil && !sameTime(current.EmailUndeliverableAt, user.EmailUndeliverableAt) {
+		changes = append(changes, events.UserEmailUndeliverable{
+			UserID: user.ID,
+			Email:  user.Email,
+			Reason: user.EmailUndeliverableReason,
+			Marked: *user.EmailUndeliverableAt,
+		})
+	}
+
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	if err := r.append(ctx, user.ID, version, current, changes); err != nil {
+		return nil, err
+	}
+
+	return entities.RehydrateUser(current, changes)
+}
+
+func (r *EventSourcedUserRepository) DeleteById(ctx context.Context, id int) error {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	current, version, err := r.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return r.append(ctx, id, version, current, []events.Event{
+		events.UserDeleted{UserID: id, Deleted: r.clock.Now()},
+	})
+}
+
+// Les flux étant par agrégat, les lots ne sont pas atomiques au niveau du store :
+// toutes les vérifications (existence, unicité des emails) sont faites avant la première écriture
+
+func (r *EventSourcedUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	batchEmails := make(map[string]bool, len(users))
+	for _, user := range users {
+		taken, err := r.state.IsEmailTaken(ctx, user.Email)
+		if err != nil {
+			return nil, err
+		}
+		if taken || batchEmails[user.Email] {
+			return nil, errors.New("email déjà utilisé")
+		}
+		batchEmails[user.Email] = true
+	}
+
+	created := make([]*entities.User, 0, len(users))
+	for _, user := range users {
+		createdUser, err := r.create(ctx, user)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, createdUser)
+	}
+	return created, nil
+}
+
+func (r *EventSourcedUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	batchEmails := make(map[string]int, len(users))
+	for _, user := range users {
+		current, _, err := r.load(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if ownerID, seen := batchEmails[user.Email]; seen && ownerID != user.ID {
+			return nil, errors.New("email déjà utilisé")
+		}
+		batchEmails[user.Email] = user.ID
+
+		if current.Email != user.Email {
+			owner, err := r.state.GetByEmail(ctx, user.Email)
+			switch {
+			case err == nil && owner.ID != user.ID:
+				return nil, errors.New("email déjà utilisé")
+			case err != nil && !errors.Is(err, repositories.ErrUserNotFound):
+				return nil, err
+			}
+		}
+	}
+
+	updated := make([]*entities.User, 0, len(users))
+	for _, user := range users {
+		updatedUser, err := r.update(ctx, user)
+		if err != nil {
+			return updated, err
+		}
+		updated = append(updated, updatedUser)
+	}
+	return updated, nil
+}
+
+func (r *EventSourcedUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	type loaded struct {
+		user    *entities.User
+		version int
+	}
+	aggregates := make(map[int]loaded, len(ids))
+	for _, id := range ids {
+		user, version, err := r.load(ctx, id)
+		if err != nil {
+			return err
+		}
+		aggregates[id] = loaded{user: user, version: version}
+	}
+
+	deleted := r.clock.Now()
+	for id, aggregate := range aggregates {
+		if err := r.append(ctx, id, aggregate.version, aggregate.user, []events.Event{
+			events.UserDeleted{UserID: id, Deleted: deleted},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *EventSourcedUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	return r.state.List(ctx, limit, offset)
+}
+
+func (r *EventSourcedUserRepository) Count(ctx context.Context) (int, error) {
+	return r.state.Count(ctx)
+}
+
+// EstimateCount l'état courant est une projection locale : le compte exact est immédiat
+func (r *EventSourcedUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	return r.state.Count(ctx)
+}
+
+func (r *EventSourcedUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	return r.state.ListInactiveSince(ctx, cutoff, limit, offset)
+}
+
+func (r *EventSourcedUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	return r.state.Each(ctx, filters, fn)
+}
+
+// Search lit l'état courant, comme les autres requêtes
+func (r *EventSourcedUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	searcher, ok := r.state.(repositories.UserSearchRepository)
+	if !ok {
+		return nil, repositories.ErrSearchNotSupported
+	}
+	return searcher.Search(ctx, filters)
+}
+
+// sameTime compare deux dates optionnelles
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// load rejoue l'agrégat : dernier snapshot + événements postérieurs
+func (r *EventSourcedUserRepository) load(ctx context.Context, id int) (*entities.User, int, error) {
+	var base *entities.User
+	version := 0
+
+	snapshot, err := r.store.LoadSnapshot(ctx, id)
+	switch {
+	case err == nil:
+		base = &snapshot.User
+		version = snapshot.Version
+	case !errors.Is(err, repositories.ErrSnapshotNotFound):
+		return nil, 0, err
+	}
+
+	stored, err := r.store.Load(ctx, id, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	if base == nil && len(stored) == 0 {
+		return nil, 0, repositories.ErrUserNotFound
+	}
+
+	history := make([]events.Event, len(stored))
+	for i, event := range stored {
+		history[i] = event.Event
+	}
+	if len(stored) > 0 {
+		version = stored[len(stored)-1].Version
+	}
+
+	user, err := entities.RehydrateUser(base, history)
+	if errors.Is(err, entities.ErrUserDeleted) {
+		return nil, 0, repositories.ErrUserNotFound
+	}
+	return user, version, err
+}
+
+// append ajoute les événements, projette l'état courant et prend un snapshot si besoin
+func (r *EventSourcedUserRepository) append(ctx context.Context, id, expectedVersion int, current *entities.User, changes []events.Event) error {
+	stored, err := r.store.Append(ctx, id, expectedVersion, changes)
+	if err != nil {
+		return err
+	}
+
+	if err := r.projector.Project(ctx, current, stored); err != nil {
+		return err
+	}
+
+	newVersion := stored[len(stored)-1].Version
+	if r.snapshotEvery > 0 && newVersion/r.snapshotEvery > expectedVersion/r.snapshotEvery {
+		user, err := entities.RehydrateUser(current, changes)
+		if errors.Is(err, entities.ErrUserDeleted) {
+			return nil // Pas de snapshot pour un agrégat supprimé
+		}
+		if err != nil {
+			return err
+		}
+		return r.store.SaveSnapshot(ctx, repositories.UserSnapshot{User: *user, Version: newVersion})
+	}
+	return nil
+}
+
+// =============================================================================
+// PROJECTEUR DE L'ÉTAT COURANT
+// =============================================================================
+
+// UserStateProjector maintient la table d'état courant à partir des événements ajoutés
+type UserStateProjector struct {
+	state repositories.UserStateRepository
+}
+
+func NewUserStateProjector(state repositories.UserStateRepository) *UserStateProjector {
+	return &UserStateProjector{state: state}
+}
+
+// Project applique les événements à l'état précédent (nil pour un nouvel agrégat)
+func (p *UserStateProjector) Project(ctx context.Context, previous *entities.User, stored []repositories.StoredEvent) error {
+	history := make([]events.Event, len(stored))
+	for i, event := range stored {
+		history[i] = event.Event
+	}
+
+	user, err := entities.RehydrateUser(previous, history)
+	if errors.Is(err, entities.ErrUserDeleted) {
+		return p.state.DeleteById(ctx, stored[0].AggregateID)
+	}
+	if err != nil {
+		return err
+	}
+	return p.state.Upsert(ctx, user)
+}
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// QueryMetrics interface pour mesurer la latence des appels aux repositories
+type QueryMetrics interface {
+	ObserveQuery(repository, method string, duration time.Duration, err error)
+}
+
+// LoggingUserRepository décore un repositories.UserRepository :
+// - journalise chaque appel avec sa durée et des arguments assainis (emails masqués, jamais de hash)
+// - signale les appels plus lents que slowThreshold
+// - alimente les histogrammes de latence par méthode
+type LoggingUserRepository struct {
+	next          repositories.UserRepository
+	logger        usecases.Logger
+	metrics       QueryMetrics
+	slowThreshold time.Duration
+}
+
+func NewLoggingUserRepository(
+	next repositories.UserRepository,
+	logger usecases.Logger,
+	metrics QueryMetrics,
+	slowThreshold time.Duration,
+) *LoggingUserRepository {
+	return &LoggingUserRepository{
+		next:          next,
+		logger:        logger,
+		metrics:       metrics,
+		slowThreshold: slowThreshold,
+	}
+}
+
+func (r *LoggingUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	start := time.Now()
+	created, err := r.next.Create(ctx, user)
+	r.observe("Create", start, err, map[string]interface{}{"email": maskEmail(user.Email)})
+	return created, err
+}
+
+func (r *LoggingUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	start := time.Now()
+	user, err := r.next.GetById(ctx, id)
+	r.observe("GetById", start, err, map[string]interface{}{"id": id})
+	return user, err
+}
+
+func (r *LoggingUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	start := time.Now()
+	users, err := r.next.GetByIds(ctx, ids)
+	r.observe("GetByIds", start, err, map[string]interface{}{"count": len(ids)})
+	return users, err
+}
+
+func (r *LoggingUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	start := time.Now()
+	user, err := r.next.GetByEmail(ctx, email)
+	r.observe("GetByEmail", start, err, map[string]interface{}{"email": maskEmail(email)})
+	return user, err
+}
+
+func (r *LoggingUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	start := time.Now()
+	taken, err := r.next.IsEmailTaken(ctx, email)
+	r.observe("IsEmailTaken", start, err, map[string]interface{}{"email": maskEmail(email)})
+	return taken, err
+}
+
+func (r *LoggingUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	start := time.Now()
+	user, err := r.next.GetByHandle(ctx, handle)
+	r.observe("GetByHandle", start, err, map[string]interface{}{"handle": handle})
+	return user, err
+}
+
+func (r *LoggingUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	start := time.Now()
+	taken, err := r.next.IsHandleTaken(ctx, handle)
+	r.observe("IsHandleTaken", start, err, map[string]interface{}{"handle": handle})
+	return taken, err
+}
+
+func (r *LoggingUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	start := time.Now()
+	updated, err := r.next.Update(ctx, user)
+	r.observe("Update", start, err, map[string]interface{}{"id": user.ID})
+	return updated, err
+}
+
+func (r *LoggingUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	start := time.Now()
+	updated, err := r.next.UpdateIfUnchanged(ctx, user, expectedUpdated)
+	r.observe("UpdateIfUnchanged", start, err, map[string]interface{}{"id": user.ID})
+	return updated, err
+}
+
+func (r *LoggingUserRepository) DeleteById(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteById(ctx, id)
+	r.observe("DeleteById", start, err, map[string]interface{}{"id": id})
+	return err
+}
+
+func (r *LoggingUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	start := time.Now()
+	users, err := r.next.List(ctx, limit, offset)
+	r.observe("List", start, err, map[string]interface{}{"limit": limit, "offset": offset})
+	return users, err
+}
+
+func (r *LoggingUserRepository) Count(ctx context.Context) (int, error) {
+	start := time.Now()
+	count, err := r.next.Count(ctx)
+	r.observe("Count", start, err, nil)
+	return count, err
+}
+
+func (r *LoggingUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	start := time.Now()
+	count, err := r.next.EstimateCount(ctx)
+	r.observe("EstimateCount", start, err, nil)
+	return count, err
+}
+
+func (r *LoggingUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	start := time.Now()
+	users, err := r.next.ListInactiveSince(ctx, cutoff, limit, offset)
+	r.observe("ListInactiveSince", start, err, map[string]interface{}{"limit": limit, "offset": offset})
+	return users, err
+}
+
+// Each le temps passé dans fn est exclu de la durée observée : seul le stockage est mesuré
+func (r *LoggingUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	start := time.Now()
+	var inCallback time.Duration
+	users := 0
+	err := r.next.Each(ctx, filters, func(user *entities.User) error {
+		callbackStart := time.Now()
+		defer func() { inCallback += time.Since(callbackStart) }()
+		users++
+		return fn(user)
+	})
+	r.observe("Each", start.Add(inCallback), err, map[string]interface{}{"users": users})
+	return err
+}
+
+// Search n'est disponible que si le dépôt décoré implémente UserSearchRepository
+func (r *LoggingUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	searcher, ok := r.next.(repositories.UserSearchRepository)
+	if !ok {
+		return nil, repositories.ErrSearchNotSupported
+	}
+
+	start := time.Now()
+	users, err := searcher.Search(ctx, filters)
+	r.observe("Search", start, err, map[string]interface{}{"limit": filters.Limit, "offset": filters.Offset})
+	return users, err
+}
+
+// SearchWithFacets se replie sur Search (sans facettes ni extraits) si le dépôt décoré n'est pas
+// un moteur de recherche dédié
+func (r *LoggingUserRepository) SearchWithFacets(ctx context.Context, filters repositories.UserRepositoryFilters) (*repositories.UserSearchResult, error) {
+	faceted, ok := r.next.(repositories.UserFacetedSearchRepository)
+	if !ok {
+		users, err := r.Search(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		return &repositories.UserSearchResult{Users: users}, nil
+	}
+
+	start := time.Now()
+	result, err := faceted.SearchWithFacets(ctx, filters)
+	r.observe("SearchWithFacets", start, err, map[string]interface{}{"limit": filters.Limit, "offset": filters.Offset})
+	return result, err
+}
+
+func (r *LoggingUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	start := time.Now()
+	created, err := r.next.CreateMany(ctx, users)
+	r.observe("CreateMany", start, err, map[string]interface{}{"count": len(users)})
+	return created, err
+}
+
+func (r *LoggingUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	start := time.Now()
+	updated, err := r.next.UpdateMany(ctx, users)
+	r.observe("UpdateMany", start, err, map[string]interface{}{"count": len(users)})
+	return updated, err
+}
+
+func (r *LoggingUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	start := time.Now()
+	err := r.next.DeleteByIds(ctx, ids)
+	r.observe("DeleteByIds", start, err, map[string]interface{}{"count": len(ids)})
+	return err
+}
+
+func (r *LoggingUserRepository) observe(method string, start time.Time, err error, fields map[string]interface{}) {
+	duration := time.Since(start)
+	// "Non trouvé" est un résultat métier normal, pas une erreur du stockage
+	failed := err != nil && !errors.Is(err, repositories.ErrUserNotFound)
+	var observed error
+	if failed {
+		observed = err
+	}
+	r.metrics.ObserveQuery("user", method, duration, observed)
+
+	if fields == nil {
+		fields = make(map[string]interface{}, 3)
+	}
+	fields["repository"] = "user"
+	fields["method"] = method
+	fields["duration_us"] = duration.Microseconds()
+
+	switch {
+	case failed:
+		r.logger.Error("Repository call failed", err, fields)
+	case r.slowThreshold > 0 && duration >= r.slowThreshold:
+		fields["threshold_ms"] = r.slowThreshold.Milliseconds()
+		r.logger.Warn("Slow repository call", fields)
+	default:
+		r.logger.Info("Repository call", fields)
+	}
+}
+
+// maskEmail ne garde que la première lettre et le domaine : "alice@example.com" -> "a***@example.com"
+func maskEmail(email string) string {
+	local, domain, found := strings.Cut(email, "@")
+	if !found || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// InMemoryActivityRepository implémente repositories.ActivityRepository en mémoire
+type InMemoryActivityRepository struct {
+	mutex   sync.RWMutex
+	entries map[int][]repositories.ActivityEntry
+}
+
+func NewInMemoryActivityRepository() *InMemoryActivityRepository {
+	return &InMemoryActivityRepository{
+		entries: make(map[int][]repositories.ActivityEntry),
+	}
+}
+
+func (r *InMemoryActivityRepository) Record(ctx context.Context, entry repositories.ActivityEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry.Fields = slices.Clone(entry.Fields)
+	r.entries[entry.UserID] = append(r.entries[entry.UserID], entry)
+	return nil
+}
+
+func (r *InMemoryActivityRepository) ListByUser(ctx context.Context, userID int, since time.Time) ([]repositories.ActivityEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entries := r.entries[userID]
+	result := make([]repositories.ActivityEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.At.Before(since) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// InMemoryAlertRuleRepository implémente repositories.AlertRuleRepository en mémoire
+type InMemoryAlertRuleRepository struct {
+	mutex  sync.RWMutex
+	rules  map[int]entities.AlertRule
+	nextID int
+}
+
+func NewInMemoryAlertRuleRepository() *InMemoryAlertRuleRepository {
+	return &InMemoryAlertRuleRepository{rules: make(map[int]entities.AlertRule), nextID: 1}
+}
+
+// copyAlertRule copie indépendante : Recipients et FiredAt ne sont pas partagés avec le dépôt
+func copyAlertRule(rule entities.AlertRule) *entities.AlertRule {
+	rule.Recipients = slices.Clone(rule.Recipients)
+	if rule.State.FiredAt != nil {
+		firedAt := *rule.State.FiredAt
+		rule.State.FiredAt = &firedAt
+	}
+	return &rule
+}
+
+func (r *InMemoryAlertRuleRepository) Create(ctx context.Context, rule *entities.AlertRule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	rule.ID = r.nextID
+	r.nextID++
+	r.rules[rule.ID] = *copyAlertRule(*rule)
+	return nil
+}
+
+func (r *InMemoryAlertRuleRepository) Get(ctx context.Context, id int) (*entities.AlertRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, repositories.ErrAlertRuleNotFound
+	}
+	return copyAlertRule(rule), nil
+}
+
+func (r *InMemoryAlertRuleRepository) List(ctx context.Context) ([]*entities.AlertRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rules := make([]*entities.AlertRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, copyAlertRule(rule))
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules, nil
+}
+
+func (r *InMemoryAlertRuleRepository) Save(ctx context.Context, rule *entities.AlertRule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.rules[rule.ID]; !ok {
+		return repositories.ErrAlertRuleNotFound
+	}
+	r.rules[rule.ID] = *copyAlertRule(*rule)
+	return nil
+}
+
+func (r *InMemoryAlertRuleRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.rules[id]; !ok {
+		return repositories.ErrAlertRuleNotFound
+	}
+	delete(r.rules, id)
+	return nil
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+)
+
+// InMemoryAuditRepository implémente repositories.AuditRepository en mémoire ; les entrées sont
+// conservées dans l'ordre d'ajout, donc par ID croissant
+type InMemoryAuditRepository struct {
+	mutex   sync.RWMutex
+	entries []repositories.AuditEntry
+	nextID  int64
+}
+
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{nextID: 1}
+}
+
+func (r *InMemoryAuditRepository) Append(ctx context.Context, entry repositories.AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry.ID = r.nextID
+	r.nextID++
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *InMemoryAuditRepository) List(ctx context.Context, filter repositories.AuditFilter) ([]repositories.AuditEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var result []repositories.AuditEntry
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+		entry := r.entries[i]
+		if filter.BeforeID > 0 && entry.ID >= filter.BeforeID {
+			continue
+		}
+		if auditEntryMatches(entry, filter) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryAuditRepository) DeleteByIDs(ctx context.Context, ids []int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	deleted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kept := r.entries[:0]
+	for _, entry := range r.entries {
+		if !deleted[entry.ID] {
+			kept = append(kept, entry)
+		}
+	}
+	count := len(r.entries) - len(kept)
+	clear(r.entries[len(kept):])
+	r.entries = kept
+	return count, nil
+}
+
+func auditEntryMatches(entry repositories.AuditEntry, filter repositories.AuditFilter) bool {
+	if filter.ActorID > 0 && entry.ActorID != filter.ActorID && entry.ImpersonatorID != filter.ActorID {
+		return false
+	}
+	if filter.TargetUserID > 0 && entry.TargetUserID != filter.TargetUserID {
+		return false
+	}
+	if filter.Action != "" && entry.Action != filter.Action {
+		return false
+	}
+	if filter.From != nil && entry.At.Before(*filter.From) {
+		return false
+	}
+	if filter.To != nil && !entry.At.Before(*filter.To) {
+		return false
+	}
+	return true
+}
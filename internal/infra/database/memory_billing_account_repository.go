@@ -0,0 +1,80 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sort"
+	"sync"
+)
+
+// InMemoryBillingAccountRepository implémente repositories.BillingAccountRepository en mémoire
+type InMemoryBillingAccountRepository struct {
+	mutex    sync.RWMutex
+	accounts map[string]entities.BillingAccount
+}
+
+func NewInMemoryBillingAccountRepository() *InMemoryBillingAccountRepository {
+	return &InMemoryBillingAccountRepository{
+		accounts: make(map[string]entities.BillingAccount),
+	}
+}
+
+func (r *InMemoryBillingAccountRepository) Save(ctx context.Context, account *entities.BillingAccount) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.accounts[account.TenantID] = *account
+	return nil
+}
+
+func (r *InMemoryBillingAccountRepository) GetByTenant(ctx context.Context, tenantID string) (*entities.BillingAccount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	account, ok := r.accounts[tenantID]
+	if !ok {
+		return nil, repositories.ErrBillingAccountNotFound
+	}
+	return &account, nil
+}
+
+func (r *InMemoryBillingAccountRepository) GetByCustomer(ctx context.Context, customerID string) (*entities.BillingAccount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, account := range r.accounts {
+		if account.CustomerID == customerID {
+			return &account, nil
+		}
+	}
+	return nil, repositories.ErrBillingAccountNotFound
+}
+
+func (r *InMemoryBillingAccountRepository) List(ctx context.Context) ([]*entities.BillingAccount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	accounts := make([]*entities.BillingAccount, 0, len(r.accounts))
+	for _, account := range r.accounts {
+		accounts = append(accounts, &account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].TenantID < accounts[j].TenantID })
+	return accounts, nil
+}
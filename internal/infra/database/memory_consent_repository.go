@@ -0,0 +1,92 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+)
+
+// InMemoryConsentRepository implémente repositories.ConsentRepository en mémoire ; les changements
+// sont conservés dans l'ordre d'ajout, donc par ID croissant
+type InMemoryConsentRepository struct {
+	mutex   sync.RWMutex
+	changes []entities.ConsentChange
+	latest  map[int]int // userID -> index du dernier changement
+	nextID  int64
+}
+
+func NewInMemoryConsentRepository() *InMemoryConsentRepository {
+	return &InMemoryConsentRepository{latest: make(map[int]int), nextID: 1}
+}
+
+func (r *InMemoryConsentRepository) Save(ctx context.Context, change *entities.ConsentChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	change.ID = r.nextID
+	r.nextID++
+	r.latest[change.UserID] = len(r.changes)
+	r.changes = append(r.changes, *change)
+	return nil
+}
+
+func (r *InMemoryConsentRepository) GetLatest(ctx context.Context, userID int) (*entities.ConsentChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index, ok := r.latest[userID]
+	if !ok {
+		return nil, repositories.ErrConsentNotFound
+	}
+	change := r.changes[index]
+	return &change, nil
+}
+
+func (r *InMemoryConsentRepository) List(ctx context.Context, filter repositories.ConsentFilter) ([]entities.ConsentChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var result []entities.ConsentChange
+	for i := len(r.changes) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+		change := r.changes[i]
+		if filter.BeforeID > 0 && change.ID >= filter.BeforeID {
+			continue
+		}
+		if consentChangeMatches(change, filter) {
+			result = append(result, change)
+		}
+	}
+	return result, nil
+}
+
+func consentChangeMatches(change entities.ConsentChange, filter repositories.ConsentFilter) bool {
+	if filter.UserID != 0 && change.UserID != filter.UserID {
+		return false
+	}
+	if filter.Status != "" && change.Consent.Status != filter.Status {
+		return false
+	}
+	if filter.From != nil && change.ChangedAt.Before(*filter.From) {
+		return false
+	}
+	if filter.To != nil && !change.ChangedAt.Before(*filter.To) {
+		return false
+	}
+	return true
+}
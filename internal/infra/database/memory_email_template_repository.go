@@ -0,0 +1,80 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+)
+
+// InMemoryEmailTemplateRepository implémente repositories.EmailTemplateRepository en mémoire
+type InMemoryEmailTemplateRepository struct {
+	mutex    sync.RWMutex
+	versions map[entities.EmailTemplateKey][]entities.EmailTemplate
+}
+
+func NewInMemoryEmailTemplateRepository() *InMemoryEmailTemplateRepository {
+	return &InMemoryEmailTemplateRepository{
+		versions: make(map[entities.EmailTemplateKey][]entities.EmailTemplate),
+	}
+}
+
+func (r *InMemoryEmailTemplateRepository) Save(ctx context.Context, tpl *entities.EmailTemplate) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	tpl.Version = len(r.versions[tpl.EmailTemplateKey]) + 1
+	r.versions[tpl.EmailTemplateKey] = append(r.versions[tpl.EmailTemplateKey], *tpl)
+	return nil
+}
+
+func (r *InMemoryEmailTemplateRepository) GetLatest(ctx context.Context, key entities.EmailTemplateKey) (*entities.EmailTemplate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	versions := r.versions[key]
+	if len(versions) == 0 {
+		return nil, repositories.ErrEmailTemplateNotFound
+	}
+	tpl := versions[len(versions)-1]
+	return &tpl, nil
+}
+
+func (r *InMemoryEmailTemplateRepository) GetVersion(ctx context.Context, key entities.EmailTemplateKey, version int) (*entities.EmailTemplate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	versions := r.versions[key]
+	if version < 1 || version > len(versions) {
+		return nil, repositories.ErrEmailTemplateNotFound
+	}
+	tpl := versions[version-1]
+	return &tpl, nil
+}
+
+func (r *InMemoryEmailTemplateRepository) ListVersions(ctx context.Context, key entities.EmailTemplateKey) ([]*entities.EmailTemplate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	versions := make([]*entities.EmailTemplate, 0, len(r.versions[key]))
+	for _, tpl := range r.versions[key] {
+		versions = append(versions, &tpl)
+	}
+	return versions, nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// InMemoryEventRepository implémente repositories.EventRepository en mémoire
+type InMemoryEventRepository struct {
+	mutex  sync.RWMutex
+	events []*entities.TrackedEvent
+	nextID int64
+}
+
+func NewInMemoryEventRepository() *InMemoryEventRepository {
+	return &InMemoryEventRepository{nextID: 1}
+}
+
+func (r *InMemoryEventRepository) Append(ctx context.Context, events []*entities.TrackedEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, event := range events {
+		event.ID = r.nextID
+		r.nextID++
+		r.events = append(r.events, event.Clone())
+	}
+	return nil
+}
+
+func (r *InMemoryEventRepository) List(ctx context.Context, filters repositories.EventFilters) ([]*entities.TrackedEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*entities.TrackedEvent, 0)
+	for _, event := range r.events {
+		if matchEventFilters(filters, event) {
+			result = append(result, event.Clone())
+		}
+	}
+
+	// Ajoutés dans l'ordre de réception : un horodatage client peut être antérieur au précédent
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].OccurredAt.Equal(result[j].OccurredAt) {
+			return result[i].OccurredAt.Before(result[j].OccurredAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	if filters.Limit > 0 && len(result) > filters.Limit {
+		result = result[:filters.Limit]
+	}
+	return result, nil
+}
+
+// matchEventFilters vrai si l'événement passe tous les filtres (hors Limit)
+func matchEventFilters(filters repositories.EventFilters, event *entities.TrackedEvent) bool {
+	switch {
+	case len(filters.Names) > 0 && !slices.Contains(filters.Names, event.Name):
+		return false
+	case filters.UserID > 0 && event.UserID != filters.UserID:
+		return false
+	case !filters.From.IsZero() && event.OccurredAt.Before(filters.From):
+		return false
+	case !filters.To.IsZero() && !event.OccurredAt.Before(filters.To):
+		return false
+	}
+	return matchEventCondition(filters.Where, event)
+}
+
+func (r *InMemoryEventRepository) DeleteByIDs(ctx context.Context, ids []int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	deleted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if !deleted[event.ID] {
+			kept = append(kept, event)
+		}
+	}
+	count := len(r.events) - len(kept)
+	clear(r.events[len(kept):])
+	r.events = kept
+	return count, nil
+}
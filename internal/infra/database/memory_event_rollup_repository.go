@@ -0,0 +1,85 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+type eventRollupKey struct {
+	day   time.Time
+	event string
+}
+
+// InMemoryEventRollupRepository implémente repositories.EventRollupRepository en mémoire
+type InMemoryEventRollupRepository struct {
+	mutex  sync.RWMutex
+	counts map[eventRollupKey]int
+}
+
+func NewInMemoryEventRollupRepository() *InMemoryEventRollupRepository {
+	return &InMemoryEventRollupRepository{
+		counts: make(map[eventRollupKey]int),
+	}
+}
+
+func (r *InMemoryEventRollupRepository) Increment(ctx context.Context, day time.Time, event string, delta int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.counts[eventRollupKey{day: day.UTC(), event: event}] += delta
+	return nil
+}
+
+func (r *InMemoryEventRollupRepository) ReplaceDays(ctx context.Context, from, to time.Time, rollups []repositories.EventRollup) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for key := range r.counts {
+		if !key.day.Before(from) && key.day.Before(to) {
+			delete(r.counts, key)
+		}
+	}
+	for _, rollup := range rollups {
+		r.counts[eventRollupKey{day: rollup.Day.UTC(), event: rollup.Event}] += rollup.Count
+	}
+	return nil
+}
+
+func (r *InMemoryEventRollupRepository) Query(ctx context.Context, filters repositories.EventRollupFilters) ([]repositories.EventRollup, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rollups := make([]repositories.EventRollup, 0)
+	for key, count := range r.counts {
+		if key.day.Before(filters.From) || !key.day.Before(filters.To) {
+			continue
+		}
+		if len(filters.Names) > 0 && !slices.Contains(filters.Names, key.event) {
+			continue
+		}
+		rollups = append(rollups, repositories.EventRollup{Day: key.day, Event: key.event, Count: count})
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		if !rollups[i].Day.Equal(rollups[j].Day) {
+			return rollups[i].Day.Before(rollups[j].Day)
+		}
+		return rollups[i].Event < rollups[j].Event
+	})
+	return rollups, nil
+}
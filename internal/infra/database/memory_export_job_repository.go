@@ -0,0 +1,78 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryExportJobRepository implémente repositories.ExportJobRepository en mémoire
+type InMemoryExportJobRepository struct {
+	mutex sync.RWMutex
+	jobs  map[string]entities.ExportJob
+}
+
+func NewInMemoryExportJobRepository() *InMemoryExportJobRepository {
+	return &InMemoryExportJobRepository{jobs: make(map[string]entities.ExportJob)}
+}
+
+func (r *InMemoryExportJobRepository) Save(ctx context.Context, job *entities.ExportJob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *InMemoryExportJobRepository) Get(ctx context.Context, id string) (*entities.ExportJob, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, repositories.ErrExportJobNotFound
+	}
+	return &job, nil
+}
+
+func (r *InMemoryExportJobRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.jobs, id)
+	return nil
+}
+
+func (r *InMemoryExportJobRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.ExportJob, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var expired []*entities.ExportJob
+	for _, job := range r.jobs {
+		if len(expired) == limit {
+			break
+		}
+		if job.Expired(now) {
+			expired = append(expired, &job)
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+)
+
+// InMemoryExternalUserLinkRepository implémente repositories.ExternalUserLinkRepository en mémoire
+type InMemoryExternalUserLinkRepository struct {
+	mutex sync.RWMutex
+	links map[string]repositories.ExternalUserLink // clé "source\x00externalID"
+}
+
+func NewInMemoryExternalUserLinkRepository() *InMemoryExternalUserLinkRepository {
+	return &InMemoryExternalUserLinkRepository{
+		links: make(map[string]repositories.ExternalUserLink),
+	}
+}
+
+func externalLinkKey(source, externalID string) string {
+	return source + "\x00" + externalID
+}
+
+func (r *InMemoryExternalUserLinkRepository) GetByExternalID(ctx context.Context, source, externalID string) (*repositories.ExternalUserLink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	link, ok := r.links[externalLinkKey(source, externalID)]
+	if !ok {
+		return nil, repositories.ErrExternalUserLinkNotFound
+	}
+	return &link, nil
+}
+
+func (r *InMemoryExternalUserLinkRepository) GetByUserID(ctx context.Context, source string, userID int) (*repositories.ExternalUserLink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, link := range r.links {
+		if link.Source == source && link.UserID == userID {
+			return &link, nil
+		}
+	}
+	return nil, repositories.ErrExternalUserLinkNotFound
+}
+
+func (r *InMemoryExternalUserLinkRepository) Save(ctx context.Context, link *repositories.ExternalUserLink) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.links[externalLinkKey(link.Source, link.ExternalID)] = *link
+	return nil
+}
+
+func (r *InMemoryExternalUserLinkRepository) Delete(ctx context.Context, source, externalID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.links, externalLinkKey(source, externalID))
+	return nil
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sort"
+	"sync"
+)
+
+// InMemoryFailedWebhookRepository implémente repositories.FailedWebhookRepository en mémoire
+type InMemoryFailedWebhookRepository struct {
+	mutex  sync.Mutex
+	events map[string]*repositories.FailedWebhookEvent
+}
+
+func NewInMemoryFailedWebhookRepository() *InMemoryFailedWebhookRepository {
+	return &InMemoryFailedWebhookRepository{events: make(map[string]*repositories.FailedWebhookEvent)}
+}
+
+func (r *InMemoryFailedWebhookRepository) RecordFailure(ctx context.Context, event repositories.FailedWebhookEvent, failure repositories.DeliveryFailure, deadLetterAfter int) (*repositories.FailedWebhookEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := event.Source + ":" + event.EventID
+	stored, exists := r.events[key]
+	if !exists {
+		stored = &event
+		stored.Payload = append([]byte(nil), event.Payload...)
+		stored.Attempts, stored.Failures, stored.Created = 0, nil, failure.At
+		r.events[key] = stored
+	}
+	stored.Attempts++
+	stored.Failures = appendDeliveryFailure(stored.Failures, failure)
+	stored.Updated = failure.At
+	if stored.Attempts >= deadLetterAfter {
+		stored.DeadLettered = true
+	}
+	return copyFailedWebhookEvent(stored), nil
+}
+
+func (r *InMemoryFailedWebhookRepository) Get(ctx context.Context, source, eventID string) (*repositories.FailedWebhookEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	event, exists := r.events[source+":"+eventID]
+	if !exists {
+		return nil, repositories.ErrFailedWebhookNotFound
+	}
+	return copyFailedWebhookEvent(event), nil
+}
+
+func (r *InMemoryFailedWebhookRepository) Delete(ctx context.Context, source, eventID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.events, source+":"+eventID)
+	return nil
+}
+
+func (r *InMemoryFailedWebhookRepository) ListDeadLettered(ctx context.Context, limit, offset int) ([]*repositories.FailedWebhookEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dead := r.deadLettered()
+	if offset >= len(dead) {
+		return []*repositories.FailedWebhookEvent{}, nil
+	}
+	dead = dead[offset:]
+	if limit > 0 && len(dead) > limit {
+		dead = dead[:limit]
+	}
+	result := make([]*repositories.FailedWebhookEvent, 0, len(dead))
+	for _, event := range dead {
+		result = append(result, copyFailedWebhookEvent(event))
+	}
+	return result, nil
+}
+
+func (r *InMemoryFailedWebhookRepository) CountDeadLettered(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.deadLettered()), nil
+}
+
+// deadLettered les plus anciens d'abord (premier échec) ; mutex détenu par l'appelant
+func (r *InMemoryFailedWebhookRepository) deadLettered() []*repositories.FailedWebhookEvent {
+	var dead []*repositories.FailedWebhookEvent
+	for _, event := range r.events {
+		if event.DeadLettered {
+			dead = append(dead, event)
+		}
+	}
+	sort.Slice(dead, func(i, j int) bool {
+		if !dead[i].Created.Equal(dead[j].Created) {
+			return dead[i].Created.Before(dead[j].Created)
+		}
+		return dead[i].Source+":"+dead[i].EventID < dead[j].Source+":"+dead[j].EventID
+	})
+	return dead
+}
+
+func copyFailedWebhookEvent(event *repositories.FailedWebhookEvent) *repositories.FailedWebhookEvent {
+	eventCopy := *event
+	eventCopy.Payload = append([]byte(nil), event.Payload...)
+	eventCopy.Failures = append([]repositories.DeliveryFailure(nil), event.Failures...)
+	return &eventCopy
+}
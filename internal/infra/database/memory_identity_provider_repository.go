@@ -0,0 +1,50 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"maps"
+	"sync"
+)
+
+// InMemoryIdentityProviderRepository implémente repositories.IdentityProviderRepository en mémoire
+type InMemoryIdentityProviderRepository struct {
+	mutex     sync.RWMutex
+	providers map[string]repositories.IdentityProvider
+}
+
+func NewInMemoryIdentityProviderRepository() *InMemoryIdentityProviderRepository {
+	return &InMemoryIdentityProviderRepository{
+		providers: make(map[string]repositories.IdentityProvider),
+	}
+}
+
+func (r *InMemoryIdentityProviderRepository) GetByTenant(ctx context.Context, tenantID string) (*repositories.IdentityProvider, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	idp, ok := r.providers[tenantID]
+	if !ok {
+		return nil, repositories.ErrIdentityProviderNotFound
+	}
+	idp.Mapping.Attributes = maps.Clone(idp.Mapping.Attributes)
+	return &idp, nil
+}
+
+func (r *InMemoryIdentityProviderRepository) Save(ctx context.Context, idp *repositories.IdentityProvider) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored := *idp
+	stored.Mapping.Attributes = maps.Clone(idp.Mapping.Attributes)
+	r.providers[idp.TenantID] = stored
+	return nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// InMemoryLoginChallengeRepository implémente repositories.LoginChallengeRepository en mémoire ;
+// les vérifications expirées sont retirées à chaque lecture
+type InMemoryLoginChallengeRepository struct {
+	mutex      sync.Mutex
+	challenges map[string]repositories.LoginChallenge
+}
+
+func NewInMemoryLoginChallengeRepository() *InMemoryLoginChallengeRepository {
+	return &InMemoryLoginChallengeRepository{challenges: make(map[string]repositories.LoginChallenge)}
+}
+
+func (r *InMemoryLoginChallengeRepository) Save(ctx context.Context, challenge repositories.LoginChallenge) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	challenge.Roles = slices.Clone(challenge.Roles)
+	r.challenges[challenge.ID] = challenge
+	return nil
+}
+
+func (r *InMemoryLoginChallengeRepository) Get(ctx context.Context, id string, now time.Time) (*repositories.LoginChallenge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for existingID, existing := range r.challenges {
+		if !now.Before(existing.ExpiresAt) {
+			delete(r.challenges, existingID)
+		}
+	}
+	challenge, ok := r.challenges[id]
+	if !ok {
+		return nil, repositories.ErrLoginChallengeNotFound
+	}
+	challenge.Roles = slices.Clone(challenge.Roles)
+	return &challenge, nil
+}
+
+func (r *InMemoryLoginChallengeRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.challenges, id)
+	return nil
+}
@@ -0,0 +1,175 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sort"
+	"sync"
+)
+
+// InMemoryNotificationPreferenceRepository implémente repositories.NotificationPreferenceRepository en mémoire
+type InMemoryNotificationPreferenceRepository struct {
+	mutex       sync.RWMutex
+	preferences map[int]map[string]*entities.NotificationPreference // userID -> "canal:événement" -> préférence
+}
+
+func NewInMemoryNotificationPreferenceRepository() *InMemoryNotificationPreferenceRepository {
+	return &InMemoryNotificationPreferenceRepository{
+		preferences: make(map[int]map[string]*entities.NotificationPreference),
+	}
+}
+
+func (r *InMemoryNotificationPreferenceRepository) ListByUser(ctx context.Context, userID int) ([]*entities.NotificationPreference, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*entities.NotificationPreference, 0, len(r.preferences[userID]))
+	for _, preference := range r.preferences[userID] {
+		preferenceCopy := *preference
+		result = append(result, &preferenceCopy)
+	}
+	return result, nil
+}
+
+func (r *InMemoryNotificationPreferenceRepository) Save(ctx context.Context, preference *entities.NotificationPreference) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.preferences[preference.UserID] == nil {
+		r.preferences[preference.UserID] = make(map[string]*entities.NotificationPreference)
+	}
+
+	preferenceCopy := *preference
+	r.preferences[preference.UserID][string(preference.Channel)+":"+preference.EventType] = &preferenceCopy
+	return nil
+}
+
+// InMemoryNotificationRepository implémente repositories.NotificationRepository en mémoire
+type InMemoryNotificationRepository struct {
+	mutex         sync.RWMutex
+	notifications map[int]*entities.Notification
+	nextID        int
+}
+
+func NewInMemoryNotificationRepository() *InMemoryNotificationRepository {
+	return &InMemoryNotificationRepository{
+		notifications: make(map[int]*entities.Notification),
+		nextID:        1,
+	}
+}
+
+func (r *InMemoryNotificationRepository) Create(ctx context.Context, notification *entities.Notification) (*entities.Notification, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	notificationCopy := *notification
+	notificationCopy.ID = r.nextID
+	r.nextID++
+	r.notifications[notificationCopy.ID] = &notificationCopy
+
+	result := notificationCopy
+	return &result, nil
+}
+
+func (r *InMemoryNotificationRepository) GetById(ctx context.Context, id int) (*entities.Notification, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	notification, exists := r.notifications[id]
+	if !exists {
+		return nil, repositories.ErrNotificationNotFound
+	}
+
+	notificationCopy := *notification
+	return &notificationCopy, nil
+}
+
+func (r *InMemoryNotificationRepository) Update(ctx context.Context, notification *entities.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.notifications[notification.ID]
+	if !exists {
+		return repositories.ErrNotificationNotFound
+	}
+
+	*existing = *notification
+	return nil
+}
+
+func (r *InMemoryNotificationRepository) ListByUser(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]*entities.Notification, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	matching := make([]*entities.Notification, 0)
+	for _, notification := range r.notifications {
+		if notification.UserID != userID || (unreadOnly && notification.ReadAt != nil) {
+			continue
+		}
+		matching = append(matching, notification)
+	}
+
+	// Les IDs sont croissants : tri décroissant = plus récentes d'abord
+	sort.Slice(matching, func(i, j int) bool { return matching[i].ID > matching[j].ID })
+
+	if offset >= len(matching) {
+		return []*entities.Notification{}, nil
+	}
+	end := offset + limit
+	if end > len(matching) {
+		end = len(matching)
+	}
+
+	result := make([]*entities.Notification, 0, end-offset)
+	for _, notification := range matching[offset:end] {
+		notificationCopy := *notification
+		result = append(result, &notificationCopy)
+	}
+	return result, nil
+}
+
+func (r *InMemoryNotificationRepository) CountByUser(ctx context.Context, userID int) (int, int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	total, unread := 0, 0
+	for _, notification := range r.notifications {
+		if notification.UserID != userID {
+			continue
+		}
+		total++
+		if notification.ReadAt == nil {
+			unread++
+		}
+	}
+	return total, unread, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+)
+
+// InMemoryOnboardingRepository implémente repositories.OnboardingRepository en mémoire
+type InMemoryOnboardingRepository struct {
+	mutex       sync.RWMutex
+	onboardings map[string]repositories.Onboarding
+}
+
+func NewInMemoryOnboardingRepository() *InMemoryOnboardingRepository {
+	return &InMemoryOnboardingRepository{onboardings: make(map[string]repositories.Onboarding)}
+}
+
+func (r *InMemoryOnboardingRepository) Save(ctx context.Context, onboarding repositories.Onboarding) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.onboardings[onboarding.ID] = onboarding
+	return nil
+}
+
+func (r *InMemoryOnboardingRepository) Get(ctx context.Context, id string) (*repositories.Onboarding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	onboarding, ok := r.onboardings[id]
+	if !ok {
+		return nil, repositories.ErrOnboardingNotFound
+	}
+	return &onboarding, nil
+}
+
+func (r *InMemoryOnboardingRepository) ListByStatus(ctx context.Context, status string) ([]repositories.Onboarding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var result []repositories.Onboarding
+	for _, onboarding := range r.onboardings {
+		if onboarding.Status == status {
+			result = append(result, onboarding)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryOnboardingRepository) CountByStatus(ctx context.Context, status string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, onboarding := range r.onboardings {
+		if onboarding.Status == status {
+			count++
+		}
+	}
+	return count, nil
+}
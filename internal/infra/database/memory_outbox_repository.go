@@ -0,0 +1,218 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryOutboxRepository implémente repositories.OutboxRepository en mémoire
+type InMemoryOutboxRepository struct {
+	mutex    sync.Mutex
+	messages map[int]*repositories.OutboxMessage
+	dedup    map[string]int
+	nextID   int
+}
+
+func NewInMemoryOutboxRepository() *InMemoryOutboxRepository {
+	return &InMemoryOutboxRepository{
+		messages: make(map[int]*repositories.OutboxMessage),
+		dedup:    make(map[string]int),
+		nextID:   1,
+	}
+}
+
+func (r *InMemoryOutboxRepository) Enqueue(ctx context.Context, message *repositories.OutboxMessage) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if message.DedupKey != "" {
+		if _, exists := r.dedup[message.DedupKey]; exists {
+			return false, nil
+		}
+	}
+
+	messageCopy := *message
+	messageCopy.ID = r.nextID
+	r.nextID++
+
+	r.messages[messageCopy.ID] = &messageCopy
+	if messageCopy.DedupKey != "" {
+		r.dedup[messageCopy.DedupKey] = messageCopy.ID
+	}
+	message.ID = messageCopy.ID
+	return true, nil
+}
+
+func (r *InMemoryOutboxRepository) ListPending(ctx context.Context, limit, maxAttempts int) ([]*repositories.OutboxMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var pending []*repositories.OutboxMessage
+	for _, message := range r.messages {
+		if message.SentAt == nil && message.Attempts < maxAttempts {
+			pending = append(pending, copyOutboxMessage(message))
+		}
+	}
+
+	// Ordre d'insertion : les messages les plus anciens partent en premier
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+func (r *InMemoryOutboxRepository) MarkSent(ctx context.Context, id int, sentAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	message, exists := r.messages[id]
+	if !exists {
+		return repositories.ErrOutboxMessageNotFound
+	}
+	message.Attempts++
+	message.SentAt = &sentAt
+	message.LastError = ""
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) MarkFailed(ctx context.Context, id int, reason string, failedAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	message, exists := r.messages[id]
+	if !exists {
+		return repositories.ErrOutboxMessageNotFound
+	}
+	message.Attempts++
+	message.LastError = reason
+	message.Failures = appendDeliveryFailure(message.Failures, repositories.DeliveryFailure{At: failedAt, Error: reason})
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) ListDead(ctx context.Context, maxAttempts, limit, offset int) ([]*repositories.OutboxMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dead := r.dead(maxAttempts)
+	if offset >= len(dead) {
+		return []*repositories.OutboxMessage{}, nil
+	}
+	dead = dead[offset:]
+	if limit > 0 && len(dead) > limit {
+		dead = dead[:limit]
+	}
+	result := make([]*repositories.OutboxMessage, 0, len(dead))
+	for _, message := range dead {
+		result = append(result, copyOutboxMessage(message))
+	}
+	return result, nil
+}
+
+func (r *InMemoryOutboxRepository) CountDead(ctx context.Context, maxAttempts int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.dead(maxAttempts)), nil
+}
+
+func (r *InMemoryOutboxRepository) Get(ctx context.Context, id int) (*repositories.OutboxMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	message, exists := r.messages[id]
+	if !exists {
+		return nil, repositories.ErrOutboxMessageNotFound
+	}
+	return copyOutboxMessage(message), nil
+}
+
+func (r *InMemoryOutboxRepository) Requeue(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	message, exists := r.messages[id]
+	if !exists {
+		return repositories.ErrOutboxMessageNotFound
+	}
+	message.Attempts = 0
+	return nil
+}
+
+func (r *InMemoryOutboxRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.messages[id]; !exists {
+		return repositories.ErrOutboxMessageNotFound
+	}
+	// La DedupKey reste dans r.dedup : le message abandonné n'est pas remis en file
+	delete(r.messages, id)
+	return nil
+}
+
+// dead messages abandonnés par ordre d'insertion ; mutex détenu par l'appelant
+func (r *InMemoryOutboxRepository) dead(maxAttempts int) []*repositories.OutboxMessage {
+	var dead []*repositories.OutboxMessage
+	for _, message := range r.messages {
+		if message.SentAt == nil && message.Attempts >= maxAttempts {
+			dead = append(dead, message)
+		}
+	}
+	sort.Slice(dead, func(i, j int) bool { return dead[i].ID < dead[j].ID })
+	return dead
+}
+
+func copyOutboxMessage(message *repositories.OutboxMessage) *repositories.OutboxMessage {
+	messageCopy := *message
+	messageCopy.Failures = append([]repositories.DeliveryFailure(nil), message.Failures...)
+	return &messageCopy
+}
+
+// appendDeliveryFailure ajoute failure en ne gardant que les MaxDeliveryFailures plus récents
+func appendDeliveryFailure(failures []repositories.DeliveryFailure, failure repositories.DeliveryFailure) []repositories.DeliveryFailure {
+	failures = append(failures, failure)
+	if len(failures) > repositories.MaxDeliveryFailures {
+		failures = append([]repositories.DeliveryFailure(nil), failures[len(failures)-repositories.MaxDeliveryFailures:]...)
+	}
+	return failures
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sort"
+	"sync"
+)
+
+// InMemorySavedReportRepository implémente repositories.SavedReportRepository en mémoire
+type InMemorySavedReportRepository struct {
+	mutex   sync.RWMutex
+	reports map[int]entities.SavedReport
+	nextID  int
+}
+
+func NewInMemorySavedReportRepository() *InMemorySavedReportRepository {
+	return &InMemorySavedReportRepository{reports: make(map[int]entities.SavedReport), nextID: 1}
+}
+
+func (r *InMemorySavedReportRepository) Create(ctx context.Context, report *entities.SavedReport) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	report.ID = r.nextID
+	r.nextID++
+	r.reports[report.ID] = *report
+	return nil
+}
+
+func (r *InMemorySavedReportRepository) Get(ctx context.Context, id int) (*entities.SavedReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	report, ok := r.reports[id]
+	if !ok {
+		return nil, repositories.ErrSavedReportNotFound
+	}
+	return &report, nil
+}
+
+func (r *InMemorySavedReportRepository) ListByOwner(ctx context.Context, ownerID int) ([]*entities.SavedReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	reports := make([]*entities.SavedReport, 0)
+	for _, report := range r.reports {
+		if report.OwnerID == ownerID {
+			reports = append(reports, &report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+	return reports, nil
+}
+
+func (r *InMemorySavedReportRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.reports[id]; !ok {
+		return repositories.ErrSavedReportNotFound
+	}
+	delete(r.reports, id)
+	return nil
+}
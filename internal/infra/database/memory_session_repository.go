@@ -0,0 +1,130 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemorySessionRepository implémente repositories.SessionRepository en mémoire ; seules les
+// maxSessions dernières sessions de chaque utilisateur sont conservées
+type InMemorySessionRepository struct {
+	mutex       sync.RWMutex
+	maxSessions int
+	sessions    map[int][]entities.Session
+}
+
+func NewInMemorySessionRepository(maxSessions int) *InMemorySessionRepository {
+	return &InMemorySessionRepository{maxSessions: maxSessions, sessions: make(map[int][]entities.Session)}
+}
+
+func (r *InMemorySessionRepository) Save(ctx context.Context, session *entities.Session) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored := *session
+	if session.Location != nil {
+		location := *session.Location
+		stored.Location = &location
+	}
+	sessions := append(r.sessions[session.UserID], stored)
+	if len(sessions) > r.maxSessions {
+		sessions = sessions[len(sessions)-r.maxSessions:]
+	}
+	r.sessions[session.UserID] = sessions
+	return nil
+}
+
+func (r *InMemorySessionRepository) ListByUser(ctx context.Context, userID int, limit int) ([]entities.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	sessions := r.sessions[userID]
+	result := make([]entities.Session, 0, len(sessions))
+	for i := len(sessions) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		session := sessions[i]
+		if session.Location != nil {
+			location := *session.Location
+			session.Location = &location
+		}
+		result = append(result, session)
+	}
+	return result, nil
+}
+
+func (r *InMemorySessionRepository) DeleteByUser(ctx context.Context, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.sessions, userID)
+	return nil
+}
+
+func (r *InMemorySessionRepository) ListCreatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]entities.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var result []entities.Session
+	for _, sessions := range r.sessions {
+		for _, session := range sessions {
+			if session.Created.Before(cutoff) {
+				result = append(result, session)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Created.Before(result[j].Created) })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *InMemorySessionRepository) DeleteByIDs(ctx context.Context, ids []string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	count := 0
+	for userID, sessions := range r.sessions {
+		kept := sessions[:0]
+		for _, session := range sessions {
+			if deleted[session.ID] {
+				count++
+				continue
+			}
+			kept = append(kept, session)
+		}
+		if len(kept) == 0 {
+			delete(r.sessions, userID)
+			continue
+		}
+		clear(sessions[len(kept):])
+		r.sessions[userID] = kept
+	}
+	return count, nil
+}
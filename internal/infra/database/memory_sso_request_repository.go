@@ -0,0 +1,61 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemorySSORequestRepository implémente repositories.SSORequestRepository en mémoire
+// Les demandes expirées sont purgées à chaque écriture
+type InMemorySSORequestRepository struct {
+	mutex    sync.Mutex
+	requests map[string]repositories.SSORequest
+	clock    usecases.Clock
+}
+
+func NewInMemorySSORequestRepository(clock usecases.Clock) *InMemorySSORequestRepository {
+	return &InMemorySSORequestRepository{
+		requests: make(map[string]repositories.SSORequest),
+		clock:    clock,
+	}
+}
+
+func (r *InMemorySSORequestRepository) Save(ctx context.Context, request repositories.SSORequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.clock.Now()
+	for id, pending := range r.requests {
+		if !now.Before(pending.Expires) {
+			delete(r.requests, id)
+		}
+	}
+	r.requests[request.ID] = request
+	return nil
+}
+
+func (r *InMemorySSORequestRepository) Consume(ctx context.Context, id string, now time.Time) (*repositories.SSORequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, repositories.ErrSSORequestNotFound
+	}
+	delete(r.requests, id)
+	if !now.Before(request.Expires) {
+		return nil, repositories.ErrSSORequestNotFound
+	}
+	return &request, nil
+}
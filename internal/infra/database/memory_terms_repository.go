@@ -0,0 +1,65 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+)
+
+// InMemoryTermsAcceptanceRepository implémente repositories.TermsAcceptanceRepository en mémoire
+type InMemoryTermsAcceptanceRepository struct {
+	mutex       sync.RWMutex
+	acceptances map[int][]entities.TermsAcceptance // userID -> historique dans l'ordre d'enregistrement
+}
+
+func NewInMemoryTermsAcceptanceRepository() *InMemoryTermsAcceptanceRepository {
+	return &InMemoryTermsAcceptanceRepository{
+		acceptances: make(map[int][]entities.TermsAcceptance),
+	}
+}
+
+func (r *InMemoryTermsAcceptanceRepository) Save(ctx context.Context, acceptance *entities.TermsAcceptance) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.acceptances[acceptance.UserID] = append(r.acceptances[acceptance.UserID], *acceptance)
+	return nil
+}
+
+func (r *InMemoryTermsAcceptanceRepository) GetLatest(ctx context.Context, userID int) (*entities.TermsAcceptance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	history := r.acceptances[userID]
+	if len(history) == 0 {
+		return nil, repositories.ErrTermsAcceptanceNotFound
+	}
+	latest := history[len(history)-1]
+	return &latest, nil
+}
+
+func (r *InMemoryTermsAcceptanceRepository) ListByUser(ctx context.Context, userID int) ([]*entities.TermsAcceptance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	history := r.acceptances[userID]
+	result := make([]*entities.TermsAcceptance, len(history))
+	for i := range history {
+		acceptanceCopy := history[i]
+		result[i] = &acceptanceCopy
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// InMemoryTimelineRepository implémente repositories.TimelineRepository en mémoire ; les entrées
+// de chaque utilisateur sont gardées triées (At puis ID croissants)
+type InMemoryTimelineRepository struct {
+	mutex   sync.RWMutex
+	nextID  int64
+	entries map[int][]repositories.TimelineEntry
+}
+
+func NewInMemoryTimelineRepository() *InMemoryTimelineRepository {
+	return &InMemoryTimelineRepository{entries: make(map[int][]repositories.TimelineEntry)}
+}
+
+func (r *InMemoryTimelineRepository) Record(ctx context.Context, entry *repositories.TimelineEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.nextID++
+	entry.ID = r.nextID
+	stored := *entry
+	stored.Fields = slices.Clone(entry.Fields)
+	stored.Details = maps.Clone(entry.Details)
+
+	entries := r.entries[entry.UserID]
+	at, _ := slices.BinarySearchFunc(entries, stored, compareTimelineEntries)
+	r.entries[entry.UserID] = slices.Insert(entries, at, stored)
+	return nil
+}
+
+func (r *InMemoryTimelineRepository) List(ctx context.Context, filter repositories.TimelineFilter) ([]repositories.TimelineEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entries := r.entries[filter.UserID]
+	end := len(entries)
+	if filter.Before != nil {
+		end, _ = slices.BinarySearchFunc(entries, repositories.TimelineEntry{At: filter.Before.At, ID: filter.Before.ID}, compareTimelineEntries)
+	}
+
+	var result []repositories.TimelineEntry
+	for i := end - 1; i >= 0 && (filter.Limit <= 0 || len(result) < filter.Limit); i-- {
+		entry := entries[i]
+		if len(filter.Categories) > 0 && !slices.Contains(filter.Categories, entry.Category) {
+			continue
+		}
+		entry.Fields = slices.Clone(entry.Fields)
+		entry.Details = maps.Clone(entry.Details)
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+func (r *InMemoryTimelineRepository) DeleteByUser(ctx context.Context, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.entries, userID)
+	return nil
+}
+
+func compareTimelineEntries(a, b repositories.TimelineEntry) int {
+	if c := a.At.Compare(b.At); c != 0 {
+		return c
+	}
+	switch {
+	case a.ID < b.ID:
+		return -1
+	case a.ID > b.ID:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryUploadSessionRepository implémente repositories.UploadSessionRepository en mémoire
+type InMemoryUploadSessionRepository struct {
+	mutex    sync.RWMutex
+	sessions map[string]entities.UploadSession
+}
+
+func NewInMemoryUploadSessionRepository() *InMemoryUploadSessionRepository {
+	return &InMemoryUploadSessionRepository{sessions: make(map[string]entities.UploadSession)}
+}
+
+func (r *InMemoryUploadSessionRepository) Save(ctx context.Context, session *entities.UploadSession) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored := *session
+	stored.Chunks = append([]string(nil), session.Chunks...)
+	r.sessions[session.ID] = stored
+	return nil
+}
+
+func (r *InMemoryUploadSessionRepository) Get(ctx context.Context, id string) (*entities.UploadSession, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, repositories.ErrUploadSessionNotFound
+	}
+	session.Chunks = append([]string(nil), session.Chunks...)
+	return &session, nil
+}
+
+func (r *InMemoryUploadSessionRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.sessions, id)
+	return nil
+}
+
+func (r *InMemoryUploadSessionRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entities.UploadSession, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var expired []*entities.UploadSession
+	for _, session := range r.sessions {
+		if len(expired) == limit {
+			break
+		}
+		if session.Expired(now) {
+			session.Chunks = append([]string(nil), session.Chunks...)
+			expired = append(expired, &session)
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"sync"
+)
+
+type usageKey struct {
+	tenant   string
+	period   string
+	resource string
+}
+
+// InMemoryUsageRepository implémente repositories.UsageRepository en mémoire
+type InMemoryUsageRepository struct {
+	mutex    sync.RWMutex
+	counters map[usageKey]int
+}
+
+func NewInMemoryUsageRepository() *InMemoryUsageRepository {
+	return &InMemoryUsageRepository{
+		counters: make(map[usageKey]int),
+	}
+}
+
+func (r *InMemoryUsageRepository) Add(ctx context.Context, tenant, period, resource string, delta int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := usageKey{tenant: tenant, period: period, resource: resource}
+	r.counters[key] += delta
+	return r.counters[key], nil
+}
+
+func (r *InMemoryUsageRepository) Get(ctx context.Context, tenant, period string) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	usage := make(map[string]int)
+	for key, value := range r.counters {
+		if key.tenant == tenant && key.period == period {
+			usage[key.resource] = value
+		}
+	}
+	return usage, nil
+}
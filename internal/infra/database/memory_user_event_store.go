@@ -0,0 +1,147 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/events"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"sync"
+)
+
+// InMemoryUserEventStore implémente repositories.UserEventStore en mémoire
+type InMemoryUserEventStore struct {
+	mutex     sync.RWMutex
+	streams   map[int][]repositories.StoredEvent
+	all       []repositories.StoredEvent // tous flux confondus, Position = rang + 1
+	snapshots map[int]repositories.UserSnapshot
+	nextID    int
+	clock     usecases.Clock
+}
+
+func NewInMemoryUserEventStore(clock usecases.Clock) *InMemoryUserEventStore {
+	return &InMemoryUserEventStore{
+		streams:   make(map[int][]repositories.StoredEvent),
+		snapshots: make(map[int]repositories.UserSnapshot),
+		nextID:    1,
+		clock:     clock,
+	}
+}
+
+func (s *InMemoryUserEventStore) NextID(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	return id, nil
+}
+
+func (s *InMemoryUserEventStore) Append(ctx context.Context, aggregateID, expectedVersion int, evts []events.Event) ([]repositories.StoredEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stream := s.streams[aggregateID]
+	if len(stream) != expectedVersion {
+		return nil, repositories.ErrConcurrencyConflict
+	}
+
+	now := s.clock.Now()
+	appended := make([]repositories.StoredEvent, 0, len(evts))
+	for i, event := range evts {
+		appended = append(appended, repositories.StoredEvent{
+			AggregateID: aggregateID,
+			Version:     expectedVersion + i + 1,
+			Event:       event,
+			RecordedAt:  now,
+			Position:    int64(len(s.all) + i + 1),
+		})
+	}
+
+	s.streams[aggregateID] = append(stream, appended...)
+	s.all = append(s.all, appended...)
+	return appended, nil
+}
+
+func (s *InMemoryUserEventStore) Load(ctx context.Context, aggregateID, afterVersion int) ([]repositories.StoredEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stream := s.streams[aggregateID]
+	if afterVersion >= len(stream) {
+		return []repositories.StoredEvent{}, nil
+	}
+
+	// Copie du slice : l'appelant ne doit pas pouvoir modifier le flux
+	return append([]repositories.StoredEvent{}, stream[afterVersion:]...), nil
+}
+
+func (s *InMemoryUserEventStore) SaveSnapshot(ctx context.Context, snapshot repositories.UserSnapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.snapshots[snapshot.User.ID] = snapshot
+	return nil
+}
+
+func (s *InMemoryUserEventStore) LoadSnapshot(ctx context.Context, aggregateID int) (*repositories.UserSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	snapshot, exists := s.snapshots[aggregateID]
+	if !exists {
+		return nil, repositories.ErrSnapshotNotFound
+	}
+	return &snapshot, nil
+}
+
+func (s *InMemoryUserEventStore) ReadAll(ctx context.Context, afterPosition int64, limit int) ([]repositories.StoredEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if afterPosition < 0 {
+		afterPosition = 0
+	}
+	if afterPosition >= int64(len(s.all)) {
+		return []repositories.StoredEvent{}, nil
+	}
+	end := int64(len(s.all))
+	if limit > 0 && afterPosition+int64(limit) < end {
+		end = afterPosition + int64(limit)
+	}
+	return append([]repositories.StoredEvent{}, s.all[afterPosition:end]...), nil
+}
+
+func (s *InMemoryUserEventStore) HeadPosition(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return int64(len(s.all)), nil
+}
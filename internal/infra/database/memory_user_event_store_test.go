@@ -0,0 +1,32 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"clean-archi-analytics/internal/domain/events"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryUserEventStoreRecordedAt(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clock := services.NewFakeClock(now)
+	store := NewInMemoryUserEventStore(clock)
+
+	if _, err := store.Append(ctx, 1, 0, []events.Event{events.UserCreated{UserID: 1, Email: "alice@example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+	if _, err := store.Append(ctx, 1, 1, []events.Event{events.UserDeleted{UserID: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := store.Load(ctx, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stream) != 2 || !stream[0].RecordedAt.Equal(now) || !stream[1].RecordedAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("flux %+v", stream)
+	}
+}
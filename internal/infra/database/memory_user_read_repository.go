@@ -0,0 +1,337 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InMemoryUserReadRepository implémente repositories.UserReadRepository en mémoire
+// Un index par critère de tri (IDs triés par clé puis par ID) est maintenu à l'écriture pour
+// que List soit une simple découpe, sans tri à chaque requête (c'est le rôle d'un modèle de
+// lecture) ; l'ordre décroissant parcourt le même index à l'envers
+type InMemoryUserReadRepository struct {
+	mutex    sync.RWMutex
+	views    map[int]*repositories.UserView
+	emails   map[string]int
+	handles  map[string]int
+	indexes  map[repositories.UserSortField][]int
+	byStatus map[string]int // nombre de vues par statut (CountByStatus sans parcours)
+}
+
+func NewInMemoryUserReadRepository() *InMemoryUserReadRepository {
+	indexes := make(map[repositories.UserSortField][]int, len(repositories.UserSortFields))
+	for _, field := range repositories.UserSortFields {
+		indexes[field] = nil
+	}
+	return &InMemoryUserReadRepository{
+		views:    make(map[int]*repositories.UserView),
+		emails:   make(map[string]int),
+		handles:  make(map[string]int),
+		indexes:  indexes,
+		byStatus: make(map[string]int),
+	}
+}
+
+func (r *InMemoryUserReadRepository) GetById(ctx context.Context, id int) (*repositories.UserView, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	view, exists := r.views[id]
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	viewCopy := *view
+	return &viewCopy, nil
+}
+
+func (r *InMemoryUserReadRepository) GetByIds(ctx context.Context, ids []int) ([]*repositories.UserView, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	views := make([]*repositories.UserView, 0, len(ids))
+	for _, id := range ids {
+		if view, exists := r.views[id]; exists {
+			viewCopy := *view
+			views = append(views, &viewCopy)
+		}
+	}
+	return views, nil
+}
+
+func (r *InMemoryUserReadRepository) GetByEmail(ctx context.Context, email string) (*repositories.UserView, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, exists := r.emails[email]
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	viewCopy := *r.views[id]
+	return &viewCopy, nil
+}
+
+func (r *InMemoryUserReadRepository) GetByHandle(ctx context.Context, handle string) (*repositories.UserView, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, exists := r.handles[handle]
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	viewCopy := *r.views[id]
+	return &viewCopy, nil
+}
+
+func (r *InMemoryUserReadRepository) List(ctx context.Context, order repositories.UserSort, limit, offset int) ([]*repositories.UserView, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index, err := r.index(order)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= len(index) {
+		return []*repositories.UserView{}, nil
+	}
+	end := offset + limit
+	if end > len(index) {
+		end = len(index)
+	}
+
+	views := make([]*repositories.UserView, 0, end-offset)
+	for position := offset; position < end; position++ {
+		viewCopy := *r.views[indexAt(index, position, order.Descending)]
+		views = append(views, &viewCopy)
+	}
+	return views, nil
+}
+
+func (r *InMemoryUserReadRepository) Count(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.views), nil
+}
+
+// EstimateCount le compte exact ne coûte rien en mémoire
+func (r *InMemoryUserReadRepository) EstimateCount(ctx context.Context) (int, error) {
+	return r.Count(ctx)
+}
+
+// ListByStatus parcourt l'index du tri en sautant les autres statuts : linéaire en mémoire,
+// un index (status, clé de tri, id) en base
+func (r *InMemoryUserReadRepository) ListByStatus(ctx context.Context, status string, order repositories.UserSort, limit, offset int) ([]*repositories.UserView, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index, err := r.index(order)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*repositories.UserView, 0, limit)
+	for position := range index {
+		if len(views) == limit {
+			break
+		}
+		view := r.views[indexAt(index, position, order.Descending)]
+		if view.Status != status {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		viewCopy := *view
+		views = append(views, &viewCopy)
+	}
+	return views, nil
+}
+
+func (r *InMemoryUserReadRepository) CountByStatus(ctx context.Context, status string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.byStatus[status], nil
+}
+
+// CountCreatedBetween deux recherches dichotomiques dans l'index par date de création
+func (r *InMemoryUserReadRepository) CountCreatedBetween(ctx context.Context, from, to time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index := r.indexes[repositories.UserSortCreated]
+	start := sort.Search(len(index), func(i int) bool { return !r.views[index[i]].Created.Before(from) })
+	end := sort.Search(len(index), func(i int) bool { return !r.views[index[i]].Created.Before(to) })
+	return max(end-start, 0), nil
+}
+
+func (r *InMemoryUserReadRepository) Save(ctx context.Context, view *repositories.UserView) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	viewCopy := *view
+	if existing, exists := r.views[view.ID]; exists {
+		delete(r.emails, existing.Email)
+		delete(r.handles, existing.Handle)
+		r.byStatus[existing.Status]--
+		r.unindex(existing)
+	}
+	r.reindex(&viewCopy)
+
+	r.views[view.ID] = &viewCopy
+	r.emails[view.Email] = view.ID
+	if view.Handle != "" {
+		r.handles[view.Handle] = view.ID
+	}
+	r.byStatus[view.Status]++
+	return nil
+}
+
+func (r *InMemoryUserReadRepository) DeleteById(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	view, exists := r.views[id]
+	if !exists {
+		return repositories.ErrUserNotFound
+	}
+
+	r.unindex(view)
+	delete(r.emails, view.Email)
+	delete(r.handles, view.Handle)
+	delete(r.views, id)
+	r.byStatus[view.Status]--
+	return nil
+}
+
+func (r *InMemoryUserReadRepository) Clear(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.views = make(map[int]*repositories.UserView)
+	r.emails = make(map[string]int)
+	r.handles = make(map[string]int)
+	for field := range r.indexes {
+		r.indexes[field] = nil
+	}
+	r.byStatus = make(map[string]int)
+	return nil
+}
+
+// index IDs dans l'ordre croissant du critère
+func (r *InMemoryUserReadRepository) index(order repositories.UserSort) ([]int, error) {
+	index, ok := r.indexes[order.Field]
+	if !ok {
+		return nil, fmt.Errorf("critère de tri non indexé : %q", order.Field)
+	}
+	return index, nil
+}
+
+// reindex insère la vue à sa place dans chaque index (recherche dichotomique)
+func (r *InMemoryUserReadRepository) reindex(view *repositories.UserView) {
+	for field, index := range r.indexes {
+		position := sort.Search(len(index), func(i int) bool {
+			return !userViewLess(field, r.views[index[i]], view)
+		})
+		index = append(index, 0)
+		copy(index[position+1:], index[position:])
+		index[position] = view.ID
+		r.indexes[field] = index
+	}
+}
+
+// unindex retire la vue telle qu'indexée (ses anciennes clés) de chaque index
+func (r *InMemoryUserReadRepository) unindex(view *repositories.UserView) {
+	for field, index := range r.indexes {
+		position := sort.Search(len(index), func(i int) bool {
+			return !userViewLess(field, r.views[index[i]], view)
+		})
+		if position < len(index) && index[position] == view.ID {
+			r.indexes[field] = append(index[:position], index[position+1:]...)
+		}
+	}
+}
+
+// userViewLess ordre croissant d'un index : clé du critère (sans casse pour le nom et l'email,
+// ordre des octets), puis ID
+func userViewLess(field repositories.UserSortField, a, b *repositories.UserView) bool {
+	switch field {
+	case repositories.UserSortName:
+		if left, right := strings.ToLower(a.Name), strings.ToLower(b.Name); left != right {
+			return left < right
+		}
+	case repositories.UserSortEmail:
+		if left, right := strings.ToLower(a.Email), strings.ToLower(b.Email); left != right {
+			return left < right
+		}
+	default:
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+	}
+	return a.ID < b.ID
+}
+
+func indexAt(index []int, position int, descending bool) int {
+	if descending {
+		return index[len(index)-1-position]
+	}
+	return index[position]
+}
@@ -0,0 +1,578 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InMemoryUserRepository implémente repositories.UserRepository en mémoire
+// Utile pour le développement local et les démos : aucune base à installer
+// Toutes les lectures retournent des COPIES pour éviter les modifications externes
+type InMemoryUserRepository struct {
+	mutex   sync.RWMutex
+	users   map[int]*entities.User
+	emails  map[string]int // Index email -> ID pour les recherches rapides
+	handles map[string]int // Index handle -> ID (utilisateurs qui en ont un)
+	nextID  int
+}
+
+// userIterationBatchSize utilisateurs lus par lot dans Each (mémoire et SQL)
+const userIterationBatchSize = 500
+
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		users:   make(map[int]*entities.User),
+		emails:  make(map[string]int),
+		handles: make(map[string]int),
+		nextID:  1,
+	}
+}
+
+func (r *InMemoryUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.emails[user.Email]; exists {
+		return nil, errors.New("email déjà utilisé")
+	}
+	if _, taken := r.handles[user.Handle]; taken && user.Handle != "" {
+		return nil, repositories.ErrHandleTaken
+	}
+
+	userCopy := *user
+	userCopy.ID = r.nextID
+	r.nextID++
+
+	r.users[userCopy.ID] = &userCopy
+	r.emails[userCopy.Email] = userCopy.ID
+	r.indexHandle("", &userCopy)
+
+	result := userCopy
+	return &result, nil
+}
+
+func (r *InMemoryUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	user, exists := r.users[id]
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	userCopy := *user
+	return &userCopy, nil
+}
+
+func (r *InMemoryUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	users := make([]*entities.User, 0, len(ids))
+	for _, id := range ids {
+		if user, exists := r.users[id]; exists {
+			userCopy := *user
+			users = append(users, &userCopy)
+		}
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, exists := r.emails[email]
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	userCopy := *r.users[id]
+	return &userCopy, nil
+}
+
+func (r *InMemoryUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, exists := r.emails[email]
+	return exists, nil
+}
+
+func (r *InMemoryUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, exists := r.handles[handle]
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	userCopy := *r.users[id]
+	return &userCopy, nil
+}
+
+func (r *InMemoryUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, exists := r.handles[handle]
+	return exists, nil
+}
+
+// indexHandle remplace previous par le handle de user dans l'index (appelant verrouillé)
+func (r *InMemoryUserRepository) indexHandle(previous string, user *entities.User) {
+	if previous != "" && r.handles[previous] == user.ID {
+		delete(r.handles, previous)
+	}
+	if user.Handle != "" {
+		r.handles[user.Handle] = user.ID
+	}
+}
+
+func (r *InMemoryUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.update(user)
+}
+
+func (r *InMemoryUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, exists := r.users[user.ID]; exists && !existing.Updated.Equal(expectedUpdated) {
+		return nil, repositories.ErrUserModified
+	}
+	return r.update(user)
+}
+
+// update appelé sous r.mutex
+func (r *InMemoryUserRepository) update(user *entities.User) (*entities.User, error) {
+	existing, exists := r.users[user.ID]
+	if !exists {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	if existing.Email != user.Email {
+		if ownerID, taken := r.emails[user.Email]; taken && ownerID != user.ID {
+			return nil, errors.New("email déjà utilisé")
+		}
+		delete(r.emails, existing.Email)
+		r.emails[user.Email] = user.ID
+	}
+	if ownerID, taken := r.handles[user.Handle]; taken && user.Handle != "" && ownerID != user.ID {
+		return nil, repositories.ErrHandleTaken
+	}
+	r.indexHandle(existing.Handle, user)
+
+	// Modification en place : l'objet stocké garde la même adresse
+	*existing = *user
+
+	userCopy := *existing
+	return &userCopy, nil
+}
+
+// Upsert enregistre l'utilisateur tel quel (ID fourni) : utilisé comme table d'état
+// courant par le mode event-sourcé (repositories.UserStateRepository)
+func (r *InMemoryUserRepository) Upsert(ctx context.Context, user *entities.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previousHandle := ""
+	if existing, exists := r.users[user.ID]; exists {
+		delete(r.emails, existing.Email)
+		previousHandle = existing.Handle
+	}
+
+	userCopy := *user
+	r.users[user.ID] = &userCopy
+	r.emails[user.Email] = user.ID
+	r.indexHandle(previousHandle, &userCopy)
+	if user.ID >= r.nextID {
+		r.nextID = user.ID + 1
+	}
+	return nil
+}
+
+func (r *InMemoryUserRepository) DeleteById(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	user, exists := r.users[id]
+	if !exists {
+		return repositories.ErrUserNotFound
+	}
+
+	delete(r.emails, user.Email)
+	delete(r.handles, user.Handle)
+	delete(r.users, id)
+	return nil
+}
+
+func (r *InMemoryUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Ordre stable par ID, sinon la pagination sur une map n'a pas de sens
+	ids := make([]int, 0, len(r.users))
+	for id := range r.users {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	if offset >= len(ids) {
+		return []*entities.User{}, nil
+	}
+	end := offset + limit
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	users := make([]*entities.User, 0, end-offset)
+	for _, id := range ids[offset:end] {
+		userCopy := *r.users[id]
+		users = append(users, &userCopy)
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepository) Count(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.users), nil
+}
+
+// EstimateCount le compte exact ne coûte rien en mémoire
+func (r *InMemoryUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	return r.Count(ctx)
+}
+
+func (r *InMemoryUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ids := make([]int, 0)
+	for id, user := range r.users {
+		if user.IsActive() && user.LastActivity().Before(cutoff) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	if offset >= len(ids) {
+		return []*entities.User{}, nil
+	}
+	end := min(offset+limit, len(ids))
+
+	users := make([]*entities.User, 0, end-offset)
+	for _, id := range ids[offset:end] {
+		userCopy := *r.users[id]
+		users = append(users, &userCopy)
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Le texte libre demande un index inversé : voir TextIndexedUserRepository
+	if filters.Query != "" {
+		return nil, repositories.ErrFullTextNotSupported
+	}
+	matches, err := newUserFilterMatcher(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ids := make([]int, 0)
+	for id, user := range r.users {
+		if matches(user) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	if filters.Offset >= len(ids) {
+		return []*entities.User{}, nil
+	}
+	end := len(ids)
+	if filters.Limit > 0 {
+		end = min(filters.Offset+filters.Limit, len(ids))
+	}
+
+	users := make([]*entities.User, 0, end-filters.Offset)
+	for _, id := range ids[filters.Offset:end] {
+		userCopy := *r.users[id]
+		users = append(users, &userCopy)
+	}
+	return users, nil
+}
+
+// Each lit les utilisateurs par lots sous verrou partagé, puis appelle fn verrou relâché
+func (r *InMemoryUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	if filters.Query != "" {
+		return repositories.ErrFullTextNotSupported
+	}
+	matches, err := newUserFilterMatcher(filters)
+	if err != nil {
+		return err
+	}
+
+	after := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch := r.batchAfter(after, matches)
+		for _, user := range batch {
+			if err := fn(user); err != nil {
+				if errors.Is(err, repositories.ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		if len(batch) < userIterationBatchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].ID
+	}
+}
+
+// batchAfter copies des userIterationBatchSize premiers utilisateurs d'ID supérieur à after
+func (r *InMemoryUserRepository) batchAfter(after int, matches func(*entities.User) bool) []*entities.User {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ids := make([]int, 0)
+	for id, user := range r.users {
+		if id > after && matches(user) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	users := make([]*entities.User, 0, min(len(ids), userIterationBatchSize))
+	for _, id := range ids[:min(len(ids), userIterationBatchSize)] {
+		userCopy := *r.users[id]
+		users = append(users, &userCopy)
+	}
+	return users
+}
+
+// newUserFilterMatcher critères de filters hors Query, Limit et Offset
+func newUserFilterMatcher(filters repositories.UserRepositoryFilters) (func(*entities.User) bool, error) {
+	from, to, err := parseCreatedRange(filters)
+	if err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(filters.Email)
+	name := strings.ToLower(filters.Name)
+
+	return func(user *entities.User) bool {
+		switch {
+		case email != "" && !strings.Contains(strings.ToLower(user.Email), email),
+			name != "" && !strings.Contains(strings.ToLower(user.Name), name),
+			filters.Status != "" && string(user.CurrentStatus()) != filters.Status,
+			!from.IsZero() && user.Created.Before(from),
+			!to.IsZero() && !user.Created.Before(to):
+			return false
+		}
+		return matchesAttributes(user.Attributes, filters.Attributes)
+	}, nil
+}
+
+// parseCreatedRange bornes de création des filtres (zéro : pas de borne)
+func parseCreatedRange(filters repositories.UserRepositoryFilters) (from, to time.Time, err error) {
+	if filters.CreatedAt.From != nil {
+		if from, err = time.Parse(time.RFC3339, *filters.CreatedAt.From); err != nil {
+			return time.Time{}, time.Time{}, errors.New("date de début invalide")
+		}
+	}
+	if filters.CreatedAt.To != nil {
+		if to, err = time.Parse(time.RFC3339, *filters.CreatedAt.To); err != nil {
+			return time.Time{}, time.Time{}, errors.New("date de fin invalide")
+		}
+	}
+	return from, to, nil
+}
+
+// matchesAttributes même sémantique que attributes @> filtre en JSONB (valeurs scalaires)
+func matchesAttributes(attributes, filter map[string]any) bool {
+	for key, expected := range filter {
+		value, ok := attributes[key]
+		if !ok || value != expected {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateMany crée tous les utilisateurs ou aucun (emails vérifiés avant toute écriture)
+func (r *InMemoryUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	batchEmails := make(map[string]bool, len(users))
+	for _, user := range users {
+		if _, exists := r.emails[user.Email]; exists || batchEmails[user.Email] {
+			return nil, errors.New("email déjà utilisé")
+		}
+		batchEmails[user.Email] = true
+	}
+
+	created := make([]*entities.User, 0, len(users))
+	for _, user := range users {
+		userCopy := *user
+		userCopy.ID = r.nextID
+		r.nextID++
+
+		r.users[userCopy.ID] = &userCopy
+		r.emails[userCopy.Email] = userCopy.ID
+		r.indexHandle("", &userCopy)
+
+		result := userCopy
+		created = append(created, &result)
+	}
+	return created, nil
+}
+
+// UpdateMany met à jour tous les utilisateurs ou aucun
+func (r *InMemoryUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Simuler l'index des emails après le lot pour détecter les conflits (y compris entre lignes du lot)
+	emails := make(map[string]int, len(r.emails))
+	for email, id := range r.emails {
+		emails[email] = id
+	}
+	for _, user := range users {
+		existing, exists := r.users[user.ID]
+		if !exists {
+			return nil, repositories.ErrUserNotFound
+		}
+		if emails[existing.Email] == user.ID {
+			delete(emails, existing.Email)
+		}
+	}
+	for _, user := range users {
+		if ownerID, taken := emails[user.Email]; taken && ownerID != user.ID {
+			return nil, errors.New("email déjà utilisé")
+		}
+		emails[user.Email] = user.ID
+	}
+
+	updated := make([]*entities.User, 0, len(users))
+	for _, user := range users {
+		// Les lots ne modifient pas les handles : l'index est seulement tenu à jour
+		r.indexHandle(r.users[user.ID].Handle, user)
+		*r.users[user.ID] = *user
+		userCopy := *user
+		updated = append(updated, &userCopy)
+	}
+	r.emails = emails
+	return updated, nil
+}
+
+// DeleteByIds supprime tous les utilisateurs ou aucun (ErrUserNotFound si un ID est inconnu)
+func (r *InMemoryUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, id := range ids {
+		if _, exists := r.users[id]; !exists {
+			return repositories.ErrUserNotFound
+		}
+	}
+
+	for _, id := range ids {
+		if user, exists := r.users[id]; exists {
+			delete(r.emails, user.Email)
+			delete(r.handles, user.Handle)
+			delete(r.users, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Tailles représentatives : démo, petite instance, instance chargée
+var benchSizes = []int{100, 10_000, 100_000}
+
+func seedUserRepository(b *testing.B, size int) *InMemoryUserRepository {
+	b.Helper()
+	repo := NewInMemoryUserRepository()
+	ctx := context.Background()
+	for i := 0; i < size; i++ {
+		user := &entities.User{Email: fmt.Sprintf("user%d@example.com", i), Name: fmt.Sprintf("User %d", i)}
+		if _, err := repo.Create(ctx, user); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func seedUserReadRepository(b *testing.B, size int) *InMemoryUserReadRepository {
+	b.Helper()
+	repo := NewInMemoryUserReadRepository()
+	ctx := context.Background()
+	now := time.Now()
+	for i := 1; i <= size; i++ {
+		view := &repositories.UserView{ID: i, Email: fmt.Sprintf("user%d@example.com", i), Name: fmt.Sprintf("User %d", i), Created: now, Updated: now}
+		if err := repo.Save(ctx, view); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func BenchmarkInMemoryUserRepositoryList(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("users=%d", size), func(b *testing.B) {
+			repo := seedUserRepository(b, size)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			for b.Loop() {
+				// Dernière page : le pire cas pour une pagination par offset
+				if _, err := repo.List(ctx, 20, size-20); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkInMemoryUserReadRepositoryList(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("users=%d", size), func(b *testing.B) {
+			repo := seedUserReadRepository(b, size)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := repo.List(ctx, repositories.DefaultUserSort, 20, size-20); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Recherche par email : le chemin de la connexion et de la vérification d'unicité
+// (aucun dépôt en mémoire n'implémente encore UserSearchRepository)
+func BenchmarkInMemoryUserReadRepositoryGetByEmail(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("users=%d", size), func(b *testing.B) {
+			repo := seedUserReadRepository(b, size)
+			ctx := context.Background()
+			email := fmt.Sprintf("user%d@example.com", size/2)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := repo.GetByEmail(ctx, email); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkInMemoryUserRepositoryGetByIds(b *testing.B) {
+	repo := seedUserRepository(b, 10_000)
+	ctx := context.Background()
+	ids := make([]int, 100)
+	for i := range ids {
+		ids[i] = i*97 + 1
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := repo.GetByIds(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
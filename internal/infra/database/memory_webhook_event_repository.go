@@ -0,0 +1,66 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryWebhookEventRepository implémente repositories.WebhookEventRepository en mémoire
+// Les réservations expirent après retention pour que la map ne grossisse pas indéfiniment
+type InMemoryWebhookEventRepository struct {
+	mutex     sync.Mutex
+	seen      map[string]time.Time
+	retention time.Duration
+	clock     usecases.Clock
+}
+
+func NewInMemoryWebhookEventRepository(retention time.Duration, clock usecases.Clock) *InMemoryWebhookEventRepository {
+	return &InMemoryWebhookEventRepository{
+		seen:      make(map[string]time.Time),
+		retention: retention,
+		clock:     clock,
+	}
+}
+
+func (r *InMemoryWebhookEventRepository) Reserve(ctx context.Context, source, eventID string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.clock.Now()
+	r.purgeExpired(now)
+
+	key := source + ":" + eventID
+	if _, exists := r.seen[key]; exists {
+		return false, nil
+	}
+
+	r.seen[key] = now
+	return true, nil
+}
+
+func (r *InMemoryWebhookEventRepository) Release(ctx context.Context, source, eventID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.seen, source+":"+eventID)
+	return nil
+}
+
+// purgeExpired doit être appelée avec le mutex verrouillé
+func (r *InMemoryWebhookEventRepository) purgeExpired(now time.Time) {
+	for key, reservedAt := range r.seen {
+		if now.Sub(reservedAt) > r.retention {
+			delete(r.seen, key)
+		}
+	}
+}
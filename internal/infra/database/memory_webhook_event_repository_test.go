@@ -0,0 +1,38 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/app/services"
+	"context"
+	"testing"
+	"time"
+)
+
+// Une réservation bloque les doublons pendant retention, puis est purgée selon l'horloge injectée
+func TestInMemoryWebhookEventRepositoryRetention(t *testing.T) {
+	ctx := context.Background()
+	clock := services.NewFakeClock(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC))
+	repo := NewInMemoryWebhookEventRepository(time.Hour, clock)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		source  string
+		want    bool
+	}{
+		{"première réception", 0, "stripe", true},
+		{"doublon", time.Minute, "stripe", false},
+		{"même identifiant, autre source", 0, "github", true},
+		{"doublon à la limite de retention", time.Hour - time.Minute, "stripe", false},
+		{"après retention", time.Second, "stripe", true},
+	}
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		reserved, err := repo.Reserve(ctx, step.source, "evt_1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reserved != step.want {
+			t.Fatalf("%s : réservé %v, attendu %v", step.name, reserved, step.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"sort"
+	"sync"
+)
+
+// InMemoryWriteKeyRepository implémente repositories.WriteKeyRepository en mémoire
+type InMemoryWriteKeyRepository struct {
+	mutex  sync.RWMutex
+	keys   map[int]entities.WriteKey
+	byHash map[string]int
+	nextID int
+}
+
+func NewInMemoryWriteKeyRepository() *InMemoryWriteKeyRepository {
+	return &InMemoryWriteKeyRepository{keys: make(map[int]entities.WriteKey), byHash: make(map[string]int), nextID: 1}
+}
+
+func (r *InMemoryWriteKeyRepository) Create(ctx context.Context, key *entities.WriteKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key.ID = r.nextID
+	r.nextID++
+	r.keys[key.ID] = *key
+	r.byHash[key.Hash] = key.ID
+	return nil
+}
+
+func (r *InMemoryWriteKeyRepository) Get(ctx context.Context, id int) (*entities.WriteKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, repositories.ErrWriteKeyNotFound
+	}
+	return &key, nil
+}
+
+func (r *InMemoryWriteKeyRepository) GetByHash(ctx context.Context, hash string) (*entities.WriteKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key, ok := r.keys[r.byHash[hash]]
+	if !ok {
+		return nil, repositories.ErrWriteKeyNotFound
+	}
+	return &key, nil
+}
+
+func (r *InMemoryWriteKeyRepository) ListByTenant(ctx context.Context, tenantID string) ([]*entities.WriteKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := make([]*entities.WriteKey, 0)
+	for _, key := range r.keys {
+		if key.TenantID == tenantID {
+			keys = append(keys, &key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func (r *InMemoryWriteKeyRepository) Save(ctx context.Context, key *entities.WriteKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.keys[key.ID]; !ok {
+		return repositories.ErrWriteKeyNotFound
+	}
+	r.keys[key.ID] = *key
+	return nil
+}
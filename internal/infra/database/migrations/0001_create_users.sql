@@ -0,0 +1,11 @@
+-- Table des utilisateurs (mode PERSISTENCE_MODE=sql)
+CREATE TABLE IF NOT EXISTS users (
+    id            SERIAL PRIMARY KEY,
+    email         TEXT        NOT NULL UNIQUE,
+    name          TEXT        NOT NULL,
+    password      TEXT        NOT NULL,
+    created       TIMESTAMPTZ NOT NULL,
+    updated       TIMESTAMPTZ NOT NULL,
+    weekly_digest BOOLEAN     NOT NULL DEFAULT FALSE,
+    phone         TEXT        NOT NULL DEFAULT ''
+);
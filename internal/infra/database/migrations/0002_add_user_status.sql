@@ -0,0 +1,5 @@
+-- Cycle de vie des comptes : active, deactivated, banned
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'deactivated', 'banned'));
+
+CREATE INDEX IF NOT EXISTS users_status_idx ON users (status);
@@ -0,0 +1,5 @@
+-- Handle public optionnel (@pseudo) : NULL tant que l'utilisateur n'en a pas choisi
+-- L'index UNIQUE ignore les NULL et garantit l'unicité en dernier ressort
+ALTER TABLE users ADD COLUMN IF NOT EXISTS handle TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_handle_key ON users (handle);
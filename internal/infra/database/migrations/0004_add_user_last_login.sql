@@ -0,0 +1,8 @@
+-- Suivi des connexions et de l'inactivité : NULL tant que le compte ne s'est jamais connecté
+-- (ou n'a pas été signalé pour nettoyage)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS cleanup_flagged_at TIMESTAMPTZ;
+
+-- Recherche des comptes inactifs (ListInactiveSince) : même expression que la requête
+CREATE INDEX IF NOT EXISTS users_last_activity_idx ON users ((COALESCE(last_login_at, created)))
+    WHERE status = 'active';
@@ -0,0 +1,5 @@
+-- Attributs personnalisés (clés et types restreints par le schéma du tenant, validés côté application)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Filtres de recherche par attributs (attributes @> '{"plan": "pro"}')
+CREATE INDEX IF NOT EXISTS users_attributes_idx ON users USING GIN (attributes jsonb_path_ops);
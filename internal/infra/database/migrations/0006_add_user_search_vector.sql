@@ -0,0 +1,10 @@
+-- Recherche plein texte sur le nom (poids A) et l'email (poids B, ponctuation remplacée par des
+-- espaces pour que "jean.dupont@example.fr" donne les mots jean, dupont, example, fr)
+-- Configuration 'simple' : ni racinisation ni mots vides, les noms propres restent intacts
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', regexp_replace(email, '[^[:alnum:]]+', ' ', 'g')), 'B')
+) STORED;
+
+-- Requêtes search_vector @@ to_tsquery('simple', 'jean:* & dup:*')
+CREATE INDEX IF NOT EXISTS users_search_vector_idx ON users USING GIN (search_vector);
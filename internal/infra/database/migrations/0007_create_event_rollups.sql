@@ -0,0 +1,10 @@
+-- Agrégats quotidiens des événements du domaine (RollupProjector, sous-commande rollup-backfill)
+CREATE TABLE IF NOT EXISTS event_rollups (
+    day   DATE    NOT NULL,
+    event TEXT    NOT NULL,
+    count BIGINT  NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, event)
+);
+
+-- Requêtes filtrées par événement sur une période (la clé primaire sert les périodes tous événements confondus)
+CREATE INDEX IF NOT EXISTS event_rollups_event_day_idx ON event_rollups (event, day);
@@ -0,0 +1,14 @@
+-- Événements analytics envoyés par les clients (POST /analytics/track)
+-- sample_rate > 1 : l'événement a été retenu par échantillonnage et en représente plusieurs
+CREATE TABLE IF NOT EXISTS tracked_events (
+    id          BIGSERIAL        PRIMARY KEY,
+    name        TEXT             NOT NULL,
+    user_id     INTEGER,
+    properties  JSONB            NOT NULL DEFAULT '{}'::jsonb,
+    sample_rate DOUBLE PRECISION NOT NULL DEFAULT 1,
+    occurred_at TIMESTAMPTZ      NOT NULL,
+    received_at TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tracked_events_name_occurred_idx ON tracked_events (name, occurred_at);
+CREATE INDEX IF NOT EXISTS tracked_events_user_occurred_idx ON tracked_events (user_id, occurred_at) WHERE user_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Compteurs d'usage par tenant (usecases.UsageMeter)
+-- period : mois "AAAA-MM" pour les appels, '' pour les ressources stockées (utilisateurs, événements)
+CREATE TABLE IF NOT EXISTS tenant_usage (
+    tenant   TEXT   NOT NULL,
+    period   TEXT   NOT NULL,
+    resource TEXT   NOT NULL,
+    value    BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant, period, resource)
+);
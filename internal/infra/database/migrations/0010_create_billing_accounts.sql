@@ -0,0 +1,12 @@
+-- Comptes de facturation : un par tenant, rattaché au client et à l'abonnement du fournisseur
+CREATE TABLE IF NOT EXISTS billing_accounts (
+    tenant_id          TEXT        PRIMARY KEY,
+    customer_id        TEXT        NOT NULL UNIQUE,
+    subscription_id    TEXT        NOT NULL DEFAULT '',
+    plan               TEXT        NOT NULL DEFAULT '',
+    status             TEXT        NOT NULL DEFAULT '',
+    current_period_end TIMESTAMPTZ,
+    reported_period    TEXT        NOT NULL DEFAULT '',
+    reported_calls     BIGINT      NOT NULL DEFAULT 0,
+    updated            TIMESTAMPTZ NOT NULL
+);
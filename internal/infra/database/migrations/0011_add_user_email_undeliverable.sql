@@ -0,0 +1,4 @@
+-- Adresse rejetée par le fournisseur d'email (rebond définitif ou plainte) : NULL tant qu'elle
+-- est joignable ; les envois vers elle sont supprimés jusqu'à un changement d'adresse
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_undeliverable_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_undeliverable_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Préférences d'affichage des dates et des nombres : chaînes vides = valeurs par défaut de l'instance
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '';
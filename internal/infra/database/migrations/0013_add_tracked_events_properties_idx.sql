@@ -0,0 +1,2 @@
+-- Filtres d'événements (props.plan = "pro", IN) : égalités traduites en containment JSONB
+CREATE INDEX IF NOT EXISTS tracked_events_properties_idx ON tracked_events USING GIN (properties jsonb_path_ops);
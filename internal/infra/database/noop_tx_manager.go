@@ -0,0 +1,17 @@
+package database
+
+import (
+	"context"
+)
+
+// NoopTxManager implémente usecases.TxManager pour les repositories en mémoire
+// Chaque opération y est déjà atomique : il n'y a rien à committer
+type NoopTxManager struct{}
+
+func NewNoopTxManager() *NoopTxManager {
+	return &NoopTxManager{}
+}
+
+func (m *NoopTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,26 @@
+-- name: UpsertBillingAccount :exec
+INSERT INTO billing_accounts (tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+                              reported_period, reported_calls, updated)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (tenant_id) DO UPDATE SET customer_id = EXCLUDED.customer_id,
+    subscription_id = EXCLUDED.subscription_id, plan = EXCLUDED.plan, status = EXCLUDED.status,
+    current_period_end = EXCLUDED.current_period_end, reported_period = EXCLUDED.reported_period,
+    reported_calls = EXCLUDED.reported_calls, updated = EXCLUDED.updated;
+
+-- name: GetBillingAccountByTenant :one
+SELECT tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+       reported_period, reported_calls, updated
+FROM billing_accounts
+WHERE tenant_id = $1;
+
+-- name: GetBillingAccountByCustomer :one
+SELECT tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+       reported_period, reported_calls, updated
+FROM billing_accounts
+WHERE customer_id = $1;
+
+-- name: ListBillingAccounts :many
+SELECT tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+       reported_period, reported_calls, updated
+FROM billing_accounts
+ORDER BY tenant_id;
@@ -0,0 +1,16 @@
+-- name: IncrementEventRollup :exec
+INSERT INTO event_rollups (day, event, count)
+VALUES ($1, $2, $3)
+ON CONFLICT (day, event) DO UPDATE SET count = event_rollups.count + EXCLUDED.count;
+
+-- name: DeleteEventRollups :exec
+DELETE FROM event_rollups WHERE day >= sqlc.arg(from_day) AND day < sqlc.arg(to_day);
+
+-- names : tableau JSON des événements retenus, [] pour tous
+-- name: ListEventRollups :many
+SELECT day, event, count
+FROM event_rollups
+WHERE day >= sqlc.arg(from_day) AND day < sqlc.arg(to_day)
+  AND (sqlc.arg(names)::jsonb = '[]'::jsonb
+       OR event IN (SELECT jsonb_array_elements_text(sqlc.arg(names)::jsonb)))
+ORDER BY day, event;
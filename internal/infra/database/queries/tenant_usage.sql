@@ -0,0 +1,9 @@
+-- Un seul aller-retour : l'upsert retourne la valeur après incrément
+-- name: AddTenantUsage :one
+INSERT INTO tenant_usage (tenant, period, resource, value)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant, period, resource) DO UPDATE SET value = tenant_usage.value + EXCLUDED.value
+RETURNING value;
+
+-- name: GetTenantUsage :many
+SELECT resource, value FROM tenant_usage WHERE tenant = $1 AND period = $2;
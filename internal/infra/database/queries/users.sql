@@ -0,0 +1,80 @@
+-- Requêtes statiques du SQLUserRepository ; les recherches à filtres variables (Search, Each)
+-- et les écritures par lots restent construites dans sql_user_repository.go
+
+-- name: GetUserByID :one
+SELECT id, email, name, password, created, updated, weekly_digest, phone, status, handle,
+       last_login_at, cleanup_flagged_at, attributes, email_undeliverable_at, email_undeliverable_reason,
+       locale, time_zone
+FROM users
+WHERE id = $1;
+
+-- name: GetUserByEmail :one
+SELECT id, email, name, password, created, updated, weekly_digest, phone, status, handle,
+       last_login_at, cleanup_flagged_at, attributes, email_undeliverable_at, email_undeliverable_reason,
+       locale, time_zone
+FROM users
+WHERE email = $1;
+
+-- Un handle absent est NULL en base : il ne correspond jamais
+-- name: GetUserByHandle :one
+SELECT id, email, name, password, created, updated, weekly_digest, phone, status, handle,
+       last_login_at, cleanup_flagged_at, attributes, email_undeliverable_at, email_undeliverable_reason,
+       locale, time_zone
+FROM users
+WHERE handle = $1;
+
+-- name: IsEmailTaken :one
+SELECT EXISTS (SELECT 1 FROM users WHERE email = $1);
+
+-- name: IsHandleTaken :one
+SELECT EXISTS (SELECT 1 FROM users WHERE handle = $1);
+
+-- name: GetUserIDByEmail :one
+SELECT id FROM users WHERE email = $1;
+
+-- name: GetUserIDByHandle :one
+SELECT id FROM users WHERE handle = $1;
+
+-- name: CreateUser :one
+INSERT INTO users (email, name, password, created, updated, weekly_digest, phone, status, handle,
+                   last_login_at, cleanup_flagged_at, attributes, email_undeliverable_at, email_undeliverable_reason,
+                   locale, time_zone)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+RETURNING id;
+
+-- expected_updated NULL = inconditionnel ; renseigné, verrouillage optimiste (UpdateIfUnchanged)
+-- name: UpdateUser :execrows
+UPDATE users
+SET email = $1, name = $2, password = $3, updated = $4, weekly_digest = $5, phone = $6, status = $7,
+    handle = $8, last_login_at = $9, cleanup_flagged_at = $10, attributes = $11,
+    email_undeliverable_at = $12, email_undeliverable_reason = $13, locale = $14, time_zone = $15
+WHERE id = $16 AND (sqlc.narg(expected_updated)::timestamptz IS NULL OR updated = sqlc.narg(expected_updated));
+
+-- name: DeleteUser :execrows
+DELETE FROM users WHERE id = $1;
+
+-- name: ListUsers :many
+SELECT id, email, name, password, created, updated, weekly_digest, phone, status, handle,
+       last_login_at, cleanup_flagged_at, attributes, email_undeliverable_at, email_undeliverable_reason,
+       locale, time_zone
+FROM users
+ORDER BY id
+LIMIT $1 OFFSET $2;
+
+-- Jamais connectés depuis l'inscription, ou dernière connexion trop ancienne
+-- (index d'expression users_last_activity_idx)
+-- name: ListInactiveUsersSince :many
+SELECT id, email, name, password, created, updated, weekly_digest, phone, status, handle,
+       last_login_at, cleanup_flagged_at, attributes, email_undeliverable_at, email_undeliverable_reason,
+       locale, time_zone
+FROM users
+WHERE status = 'active' AND COALESCE(last_login_at, created) < sqlc.arg(cutoff)::timestamptz
+ORDER BY id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users;
+
+-- reltuples est mis à jour par VACUUM / ANALYZE et l'autovacuum
+-- name: EstimateUserCount :one
+SELECT reltuples FROM pg_class WHERE oid = 'users'::regclass;
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// STICKINESS APRÈS ÉCRITURE
+// =============================================================================
+
+// readSession marque une requête entrante ; après une écriture, ses lectures restent sur le primaire
+type readSession struct {
+	mutex sync.Mutex
+	wrote bool
+}
+
+type readSessionKey struct{}
+
+// WithReadSession attache une session de lecture au context (une par requête HTTP)
+// Sans session, chaque lecture peut partir sur un réplica même juste après une écriture
+func WithReadSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readSessionKey{}, &readSession{})
+}
+
+func markWrite(ctx context.Context) {
+	if session, ok := ctx.Value(readSessionKey{}).(*readSession); ok {
+		session.mutex.Lock()
+		session.wrote = true
+		session.mutex.Unlock()
+	}
+}
+
+func pinnedToPrimary(ctx context.Context) bool {
+	session, ok := ctx.Value(readSessionKey{}).(*readSession)
+	if !ok {
+		return false
+	}
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	return session.wrote
+}
+
+// =============================================================================
+// ROUTAGE PRIMAIRE / RÉPLICAS
+// =============================================================================
+
+// ReplicaRoutingUserRepository envoie les écritures au primaire et répartit les lectures
+// (GetById, GetByIds, GetByEmail, GetByHandle, List, ListInactiveSince, Search, Count) entre les réplicas en round-robin
+// - après une écriture dans la même requête (WithReadSession), les lectures restent sur le primaire
+// - IsEmailTaken et IsHandleTaken servent de contrôle avant écriture : ils lisent toujours le primaire
+// - une erreur technique d'un réplica est rejouée sur le primaire
+type ReplicaRoutingUserRepository struct {
+	primary  repositories.UserRepository
+	replicas []repositories.UserRepository
+	next     atomic.Uint64
+}
+
+func NewReplicaRoutingUserRepository(primary repositories.UserRepository, replicas ...repositories.UserRepository) *ReplicaRoutingUserRepository {
+	return &ReplicaRoutingUserRepository{primary: primary, replicas: replicas}
+}
+
+// reader choisit le dépôt de lecture pour cet appel
+func (r *ReplicaRoutingUserRepository) reader(ctx context.Context) repositories.UserRepository {
+	if len(r.replicas) == 0 || pinnedToPrimary(ctx) {
+		return r.primary
+	}
+	return r.replicas[r.next.Add(1)%uint64(len(r.replicas))]
+}
+
+// read exécute la lecture sur un réplica, puis sur le primaire si le réplica est en panne
+func read[T any](ctx context.Context, r *ReplicaRoutingUserRepository, fn func(repositories.UserRepository) (T, error)) (T, error) {
+	repo := r.reader(ctx)
+	result, err := fn(repo)
+	if err == nil || repo == r.primary || errors.Is(err, repositories.ErrUserNotFound) || ctx.Err() != nil {
+		return result, err
+	}
+	return fn(r.primary)
+}
+
+func (r *ReplicaRoutingUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	markWrite(ctx)
+	return r.primary.Create(ctx, user)
+}
+
+func (r *ReplicaRoutingUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.User, error) {
+		return repo.GetById(ctx, id)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) ([]*entities.User, error) {
+		return repo.GetByIds(ctx, ids)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.User, error) {
+		return repo.GetByEmail(ctx, email)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	return r.primary.IsEmailTaken(ctx, email)
+}
+
+func (r *ReplicaRoutingUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (*entities.User, error) {
+		return repo.GetByHandle(ctx, handle)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	return r.primary.IsHandleTaken(ctx, handle)
+}
+
+func (r *ReplicaRoutingUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	markWrite(ctx)
+	return r.primary.Update(ctx, user)
+}
+
+func (r *ReplicaRoutingUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	markWrite(ctx)
+	return r.primary.UpdateIfUnchanged(ctx, user, expectedUpdated)
+}
+
+func (r *ReplicaRoutingUserRepository) DeleteById(ctx context.Context, id int) error {
+	markWrite(ctx)
+	return r.primary.DeleteById(ctx, id)
+}
+
+func (r *ReplicaRoutingUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) ([]*entities.User, error) {
+		return repo.List(ctx, limit, offset)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) Count(ctx context.Context) (int, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (int, error) {
+		return repo.Count(ctx)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) (int, error) {
+		return repo.EstimateCount(ctx)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) ([]*entities.User, error) {
+		return repo.ListInactiveSince(ctx, cutoff, limit, offset)
+	})
+}
+
+// Each la bascule sur le primaire n'a lieu que si le réplica échoue avant le premier utilisateur :
+// au-delà, fn aurait vu deux fois les mêmes utilisateurs
+func (r *ReplicaRoutingUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	repo := r.reader(ctx)
+	delivered := false
+	err := repo.Each(ctx, filters, func(user *entities.User) error {
+		delivered = true
+		return fn(user)
+	})
+	if err == nil || repo == r.primary || delivered || ctx.Err() != nil {
+		return err
+	}
+	return r.primary.Each(ctx, filters, fn)
+}
+
+// Search est routé comme les autres lectures si les dépôts implémentent UserSearchRepository
+func (r *ReplicaRoutingUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	return read(ctx, r, func(repo repositories.UserRepository) ([]*entities.User, error) {
+		searcher, ok := repo.(repositories.UserSearchRepository)
+		if !ok {
+			return nil, repositories.ErrSearchNotSupported
+		}
+		return searcher.Search(ctx, filters)
+	})
+}
+
+func (r *ReplicaRoutingUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	markWrite(ctx)
+	return r.primary.CreateMany(ctx, users)
+}
+
+func (r *ReplicaRoutingUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	markWrite(ctx)
+	return r.primary.UpdateMany(ctx, users)
+}
+
+func (r *ReplicaRoutingUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	markWrite(ctx)
+	return r.primary.DeleteByIds(ctx, ids)
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/infra/database/sqlcdb"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// SQLBillingAccountRepository implémente repositories.BillingAccountRepository (migrations/0010_create_billing_accounts.sql)
+// Requêtes : queries/billing_accounts.sql
+type SQLBillingAccountRepository struct {
+	queries *sqlcdb.Queries
+}
+
+func NewSQLBillingAccountRepository(db *sql.DB) *SQLBillingAccountRepository {
+	return &SQLBillingAccountRepository{queries: sqlcdb.New(db)}
+}
+
+func (r *SQLBillingAccountRepository) Save(ctx context.Context, account *entities.BillingAccount) error {
+	var periodEnd sql.NullTime
+	if !account.CurrentPeriodEnd.IsZero() {
+		periodEnd = sql.NullTime{Time: account.CurrentPeriodEnd, Valid: true}
+	}
+	return r.queries.UpsertBillingAccount(ctx, sqlcdb.UpsertBillingAccountParams{
+		TenantID:         account.TenantID,
+		CustomerID:       account.CustomerID,
+		SubscriptionID:   account.SubscriptionID,
+		Plan:             account.Plan,
+		Status:           string(account.Status),
+		CurrentPeriodEnd: periodEnd,
+		ReportedPeriod:   account.ReportedPeriod,
+		ReportedCalls:    int64(account.ReportedCalls),
+		Updated:          account.Updated,
+	})
+}
+
+func (r *SQLBillingAccountRepository) GetByTenant(ctx context.Context, tenantID string) (*entities.BillingAccount, error) {
+	return getBillingAccount(r.queries.GetBillingAccountByTenant(ctx, tenantID))
+}
+
+func (r *SQLBillingAccountRepository) GetByCustomer(ctx context.Context, customerID string) (*entities.BillingAccount, error) {
+	return getBillingAccount(r.queries.GetBillingAccountByCustomer(ctx, customerID))
+}
+
+func (r *SQLBillingAccountRepository) List(ctx context.Context) ([]*entities.BillingAccount, error) {
+	rows, err := r.queries.ListBillingAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*entities.BillingAccount, 0, len(rows))
+	for _, row := range rows {
+		accounts = append(accounts, billingAccountFromRow(row))
+	}
+	return accounts, nil
+}
+
+func getBillingAccount(row sqlcdb.BillingAccount, err error) (*entities.BillingAccount, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repositories.ErrBillingAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return billingAccountFromRow(row), nil
+}
+
+func billingAccountFromRow(row sqlcdb.BillingAccount) *entities.BillingAccount {
+	account := &entities.BillingAccount{
+		TenantID:       row.TenantID,
+		CustomerID:     row.CustomerID,
+		SubscriptionID: row.SubscriptionID,
+		Plan:           row.Plan,
+		Status:         entities.SubscriptionStatus(row.Status),
+		ReportedPeriod: row.ReportedPeriod,
+		ReportedCalls:  int(row.ReportedCalls),
+		Updated:        row.Updated,
+	}
+	if row.CurrentPeriodEnd.Valid {
+		account.CurrentPeriodEnd = row.CurrentPeriodEnd.Time.UTC()
+	}
+	return account
+}
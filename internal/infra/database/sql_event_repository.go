@@ -0,0 +1,159 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// SQLEventRepository implémente repositories.EventRepository (migrations/0008_create_tracked_events.sql)
+type SQLEventRepository struct {
+	db *sql.DB
+}
+
+func NewSQLEventRepository(db *sql.DB) *SQLEventRepository {
+	return &SQLEventRepository{db: db}
+}
+
+// Append INSERT multi-lignes par lots de sqlBatchSize, dans une seule transaction
+func (r *SQLEventRepository) Append(ctx context.Context, events []*entities.TrackedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // Sans effet après Commit
+
+	// 6 paramètres par ligne, bien sous la limite de 65535
+	for start := 0; start < len(events); start += sqlBatchSize {
+		batch := events[start:min(start+sqlBatchSize, len(events))]
+		var query strings.Builder
+		query.WriteString(`INSERT INTO tracked_events (name, user_id, properties, sample_rate, occurred_at, received_at) VALUES `)
+		args := make([]any, 0, len(batch)*6)
+		for i, event := range batch {
+			properties, err := encodeAttributes(event.Properties)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			n := len(args)
+			query.WriteString("(" + placeholders(n+1, 2) + ", " + placeholders(n+3, 1) + "::jsonb, " + placeholders(n+4, 3) + ")")
+			args = append(args, event.Name, sql.NullInt64{Int64: int64(event.UserID), Valid: event.UserID > 0},
+				properties, event.SampleRate, event.OccurredAt, event.ReceivedAt)
+		}
+		query.WriteString(` RETURNING id`)
+
+		rows, err := tx.QueryContext(ctx, query.String(), args...)
+		if err != nil {
+			return err
+		}
+		// RETURNING suit l'ordre de VALUES pour un INSERT multi-lignes
+		i := 0
+		for rows.Next() {
+			if err := rows.Scan(&batch[i].ID); err != nil {
+				rows.Close()
+				return err
+			}
+			i++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLEventRepository) List(ctx context.Context, filters repositories.EventFilters) ([]*entities.TrackedEvent, error) {
+	where := sqlConditions{}
+	if len(filters.Names) > 0 {
+		names := make([]string, len(filters.Names))
+		for i, name := range filters.Names {
+			names[i] = where.param(name)
+		}
+		where.conditions = append(where.conditions, "name IN ("+strings.Join(names, ", ")+")")
+	}
+	if filters.UserID > 0 {
+		where.add("user_id = $?", filters.UserID)
+	}
+	if !filters.From.IsZero() {
+		where.add("occurred_at >= $?", filters.From)
+	}
+	if !filters.To.IsZero() {
+		where.add("occurred_at < $?", filters.To)
+	}
+	if filters.Where != nil {
+		predicate, err := compileEventCondition(filters.Where, postgresEventDialect, where.param)
+		if err != nil {
+			return nil, err
+		}
+		where.conditions = append(where.conditions, predicate)
+	}
+	query := `SELECT id, name, user_id, properties, sample_rate, occurred_at, received_at FROM tracked_events` +
+		where.clause() + ` ORDER BY occurred_at, id`
+	if filters.Limit > 0 {
+		query += " LIMIT " + where.param(filters.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*entities.TrackedEvent, 0)
+	for rows.Next() {
+		var event entities.TrackedEvent
+		var userID sql.NullInt64
+		var properties []byte
+		if err := rows.Scan(&event.ID, &event.Name, &userID, &properties, &event.SampleRate, &event.OccurredAt, &event.ReceivedAt); err != nil {
+			return nil, err
+		}
+		event.UserID = int(userID.Int64)
+		if event.Properties, err = decodeAttributes(properties); err != nil {
+			return nil, err
+		}
+		event.OccurredAt = event.OccurredAt.UTC()
+		event.ReceivedAt = event.ReceivedAt.UTC()
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// DeleteByIDs DELETE par lots de sqlBatchSize, dans une seule transaction
+func (r *SQLEventRepository) DeleteByIDs(ctx context.Context, ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() // Sans effet après Commit
+
+	deleted := 0
+	for start := 0; start < len(ids); start += sqlBatchSize {
+		batch := ids[start:min(start+sqlBatchSize, len(ids))]
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		result, err := tx.ExecContext(ctx, `DELETE FROM tracked_events WHERE id IN (`+placeholders(1, len(args))+`)`, args...)
+		if err != nil {
+			return 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		deleted += int(affected)
+	}
+	return deleted, tx.Commit()
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/infra/database/sqlcdb"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// SQLEventRollupRepository implémente repositories.EventRollupRepository (migrations/0007_create_event_rollups.sql)
+// Les jours sont des DATE : les bornes sont passées à minuit UTC
+// Requêtes : queries/event_rollups.sql ; le backfill multi-lignes de ReplaceDays reste construit ici
+type SQLEventRollupRepository struct {
+	db      *sql.DB
+	queries *sqlcdb.Queries
+}
+
+func NewSQLEventRollupRepository(db *sql.DB) *SQLEventRollupRepository {
+	return &SQLEventRollupRepository{db: db, queries: sqlcdb.New(db)}
+}
+
+// Increment un seul aller-retour par événement : l'upsert incrémente la ligne sans la relire
+func (r *SQLEventRollupRepository) Increment(ctx context.Context, day time.Time, event string, delta int) error {
+	return r.queries.IncrementEventRollup(ctx, sqlcdb.IncrementEventRollupParams{
+		Day:   utcDay(day),
+		Event: event,
+		Count: int64(delta),
+	})
+}
+
+// ReplaceDays supprime puis réinsère dans une transaction : une lecture concurrente voit l'ancien
+// ou le nouvel état de la période, jamais une période vide
+func (r *SQLEventRollupRepository) ReplaceDays(ctx context.Context, from, to time.Time, rollups []repositories.EventRollup) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // Sans effet après Commit
+
+	if err := r.queries.WithTx(tx).DeleteEventRollups(ctx, sqlcdb.DeleteEventRollupsParams{
+		FromDay: utcDay(from),
+		ToDay:   utcDay(to),
+	}); err != nil {
+		return err
+	}
+
+	// 3 paramètres par ligne, bien sous la limite de 65535
+	for start := 0; start < len(rollups); start += sqlBatchSize {
+		batch := rollups[start:min(start+sqlBatchSize, len(rollups))]
+		var query strings.Builder
+		query.WriteString(`INSERT INTO event_rollups (day, event, count) VALUES `)
+		args := make([]any, 0, len(batch)*3)
+		for i, rollup := range batch {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(" + placeholders(len(args)+1, 3) + ")")
+			args = append(args, rollup.Day.UTC().Format(time.DateOnly), rollup.Event, rollup.Count)
+		}
+		// Un Increment concurrent a pu recréer la ligne depuis le DELETE : il s'ajoute au backfill
+		query.WriteString(` ON CONFLICT (day, event) DO UPDATE SET count = event_rollups.count + EXCLUDED.count`)
+		if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLEventRollupRepository) Query(ctx context.Context, filters repositories.EventRollupFilters) ([]repositories.EventRollup, error) {
+	names, err := json.Marshal(filters.Names)
+	if err != nil {
+		return nil, err
+	}
+	if len(filters.Names) == 0 {
+		names = []byte("[]") // nil est encodé null
+	}
+
+	rows, err := r.queries.ListEventRollups(ctx, sqlcdb.ListEventRollupsParams{
+		FromDay: utcDay(filters.From),
+		ToDay:   utcDay(filters.To),
+		Names:   names,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rollups := make([]repositories.EventRollup, 0, len(rows))
+	for _, row := range rows {
+		rollups = append(rollups, repositories.EventRollup{Day: row.Day.UTC(), Event: row.Event, Count: int(row.Count)})
+	}
+	return rollups, nil
+}
+
+// utcDay minuit UTC du jour de t, tel qu'il est comparé à une colonne DATE
+func utcDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
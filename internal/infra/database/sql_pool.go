@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"time"
+)
+
+// SQLPoolConfig réglages du pool de connexions database/sql
+type SQLPoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// Lazy aucune vérification à l'ouverture : la première requête ouvre la connexion (Lambda)
+	Lazy bool
+}
+
+// OpenSQL ouvre le pool, applique les réglages et vérifie la connexion (sauf pool.Lazy)
+// Le driver (ex: "pgx", "postgres") doit être enregistré dans le binaire par un import anonyme
+func OpenSQL(ctx context.Context, driver, dsn string, pool SQLPoolConfig) (*sql.DB, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return configurePool(ctx, db, pool)
+}
+
+// OpenRotatingSQL comme OpenSQL, mais le DSN est relu à chaque nouvelle connexion :
+// après une rotation des identifiants, les connexions ouvertes ensuite utilisent les
+// nouveaux (voir RecycleIdleConns pour ne pas attendre ConnMaxLifetime)
+func OpenRotatingSQL(ctx context.Context, driverName string, dsn func() string, pool SQLPoolConfig) (*sql.DB, error) {
+	// sql.Open n'ouvre aucune connexion : il sert uniquement à retrouver le driver enregistré
+	probe, err := sql.Open(driverName, dsn())
+	if err != nil {
+		return nil, err
+	}
+	drv := probe.Driver()
+	probe.Close()
+
+	return configurePool(ctx, sql.OpenDB(&rotatingConnector{driver: drv, dsn: dsn}), pool)
+}
+
+// RecycleIdleConns ferme les connexions inactives du pool (ouvertes avec d'anciens identifiants) ;
+// celles en cours d'utilisation sont fermées à leur libération
+func RecycleIdleConns(db *sql.DB, maxIdle int) {
+	db.SetMaxIdleConns(0)
+	db.SetMaxIdleConns(maxIdle)
+}
+
+func configurePool(ctx context.Context, db *sql.DB, pool SQLPoolConfig) (*sql.DB, error) {
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	if pool.Lazy {
+		return db, nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// rotatingConnector implémente driver.Connector avec un DSN lu à chaque connexion
+// Le connecteur du driver (DSN analysé) est conservé tant que le DSN ne change pas
+type rotatingConnector struct {
+	driver driver.Driver
+	dsn    func() string
+
+	mutex     sync.Mutex
+	lastDSN   string
+	connector driver.Connector
+}
+
+func (c *rotatingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dsn := c.dsn()
+	driverCtx, ok := c.driver.(driver.DriverContext)
+	if !ok {
+		return c.driver.Open(dsn)
+	}
+
+	c.mutex.Lock()
+	if c.connector == nil || dsn != c.lastDSN {
+		connector, err := driverCtx.OpenConnector(dsn)
+		if err != nil {
+			c.mutex.Unlock()
+			return nil, err
+		}
+		c.connector, c.lastDSN = connector, dsn
+	}
+	connector := c.connector
+	c.mutex.Unlock()
+
+	return connector.Connect(ctx)
+}
+
+func (c *rotatingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// =============================================================================
+// CACHE DE REQUÊTES PRÉPARÉES
+// =============================================================================
+
+// statementCache prépare une requête à sa première utilisation puis la réutilise
+// database/sql re-prépare le statement de façon transparente sur chaque connexion du pool
+type statementCache struct {
+	db    *sql.DB
+	mutex sync.RWMutex
+	stmts map[string]*sql.Stmt
+}
+
+func newStatementCache(db *sql.DB) *statementCache {
+	return &statementCache{db: db, stmts: make(map[string]*sql.Stmt)}
+}
+
+func (c *statementCache) get(ctx context.Context, query string) (*sql.Stmt, error) {
+	c.mutex.RLock()
+	stmt, ok := c.stmts[query]
+	c.mutex.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if stmt, ok := c.stmts[query]; ok {
+		return stmt, nil // Préparé par une autre goroutine entre-temps
+	}
+
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.stmts[query] = stmt
+	return stmt, nil
+}
+
+func (c *statementCache) close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var firstErr error
+	for query, stmt := range c.stmts {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.stmts, query)
+	}
+	return firstErr
+}
+
+// Code de sqlcdb régénéré depuis queries/ et migrations/ (sqlc.yaml à la racine du module)
+//go:generate sqlc generate -f ../../../sqlc.yaml
+
+// cachedDBTX implémente sqlcdb.DBTX : les requêtes générées par sqlc passent par le cache de statements
+type cachedDBTX struct {
+	stmts *statementCache
+}
+
+func (c cachedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := c.stmts.get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (c cachedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.stmts.db.PrepareContext(ctx, query)
+}
+
+func (c cachedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := c.stmts.get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// QueryRowContext *sql.Row ne peut pas porter l'erreur de préparation : la requête est alors
+// exécutée sans statement et l'erreur remonte au Scan
+func (c cachedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	stmt, err := c.stmts.get(ctx, query)
+	if err != nil {
+		return c.stmts.db.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
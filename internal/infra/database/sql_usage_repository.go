@@ -0,0 +1,42 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/infra/database/sqlcdb"
+	"context"
+	"database/sql"
+)
+
+// SQLUsageRepository implémente repositories.UsageRepository (migrations/0009_create_tenant_usage.sql)
+// Les compteurs sont partagés par toutes les instances de l'API : les quotas valent pour l'ensemble
+// Requêtes : queries/tenant_usage.sql
+type SQLUsageRepository struct {
+	queries *sqlcdb.Queries
+}
+
+func NewSQLUsageRepository(db *sql.DB) *SQLUsageRepository {
+	return &SQLUsageRepository{queries: sqlcdb.New(db)}
+}
+
+// Add un seul aller-retour : l'upsert retourne la valeur après incrément
+func (r *SQLUsageRepository) Add(ctx context.Context, tenant, period, resource string, delta int) (int, error) {
+	value, err := r.queries.AddTenantUsage(ctx, sqlcdb.AddTenantUsageParams{
+		Tenant:   tenant,
+		Period:   period,
+		Resource: resource,
+		Value:    int64(delta),
+	})
+	return int(value), err
+}
+
+func (r *SQLUsageRepository) Get(ctx context.Context, tenant, period string) (map[string]int, error) {
+	rows, err := r.queries.GetTenantUsage(ctx, sqlcdb.GetTenantUsageParams{Tenant: tenant, Period: period})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]int, len(rows))
+	for _, row := range rows {
+		usage[row.Resource] = int(row.Value)
+	}
+	return usage, nil
+}
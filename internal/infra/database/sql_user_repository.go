@@ -0,0 +1,730 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/infra/database/sqlcdb"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userSelectColumns requêtes à filtres variables (Search, Each, GetByIds), construites ici ;
+// les requêtes statiques sont dans queries/users.sql (code généré : sqlcdb)
+const userSelectColumns = `SELECT id, email, name, password, created, updated, weekly_digest, phone, status, COALESCE(handle, ''),
+	last_login_at, cleanup_flagged_at, attributes, email_undeliverable_at, email_undeliverable_reason, locale, time_zone FROM users`
+
+// SQLUserRepository implémente repositories.UserRepository avec database/sql (dialecte PostgreSQL)
+// Schéma : migrations/0001_create_users.sql, 0002_add_user_status.sql, 0003_add_user_handle.sql,
+// 0004_add_user_last_login.sql, 0005_add_user_attributes.sql, 0006_add_user_search_vector.sql,
+// 0011_add_user_email_undeliverable.sql, 0012_add_user_locale.sql
+type SQLUserRepository struct {
+	db      *sql.DB
+	stmts   *statementCache
+	queries *sqlcdb.Queries
+}
+
+func NewSQLUserRepository(db *sql.DB) *SQLUserRepository {
+	stmts := newStatementCache(db)
+	return &SQLUserRepository{db: db, stmts: stmts, queries: sqlcdb.New(cachedDBTX{stmts: stmts})}
+}
+
+// Close libère les statements préparés (le pool reste géré par l'appelant)
+func (r *SQLUserRepository) Close() error {
+	return r.stmts.close()
+}
+
+func (r *SQLUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	// Vérification préalable pour un message clair ; la contrainte UNIQUE reste la garantie finale
+	taken, err := r.IsEmailTaken(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errors.New("email déjà utilisé")
+	}
+
+	attributes, err := encodeAttributes(user.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := r.queries.CreateUser(ctx, sqlcdb.CreateUserParams{
+		Email:                    user.Email,
+		Name:                     user.Name,
+		Password:                 user.Password,
+		Created:                  user.Created,
+		Updated:                  user.Updated,
+		WeeklyDigest:             user.WeeklyDigest,
+		Phone:                    user.Phone,
+		Status:                   string(user.CurrentStatus()),
+		Handle:                   nullString(user.Handle),
+		LastLoginAt:              nullTime(user.LastLoginAt),
+		CleanupFlaggedAt:         nullTime(user.CleanupFlaggedAt),
+		Attributes:               json.RawMessage(attributes),
+		EmailUndeliverableAt:     nullTime(user.EmailUndeliverableAt),
+		EmailUndeliverableReason: user.EmailUndeliverableReason,
+		Locale:                   user.Locale,
+		TimeZone:                 user.TimeZone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	created := *user
+	created.ID = int(id)
+	return &created, nil
+}
+
+func (r *SQLUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	row, err := r.queries.GetUserByID(ctx, int32(id))
+	return userFromRow(userRow(row), err)
+}
+
+func (r *SQLUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	users := make([]*entities.User, 0, len(ids))
+	for start := 0; start < len(ids); start += sqlBatchSize {
+		batch := ids[start:min(start+sqlBatchSize, len(ids))]
+
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+
+		rows, err := r.db.QueryContext(ctx, userSelectColumns+` WHERE id IN (`+placeholders(1, len(args))+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			users = append(users, user)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+func (r *SQLUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	row, err := r.queries.GetUserByEmail(ctx, email)
+	return userFromRow(userRow(row), err)
+}
+
+func (r *SQLUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	return r.queries.IsEmailTaken(ctx, email)
+}
+
+func (r *SQLUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	row, err := r.queries.GetUserByHandle(ctx, nullString(handle))
+	return userFromRow(userRow(row), err)
+}
+
+func (r *SQLUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	return r.queries.IsHandleTaken(ctx, nullString(handle))
+}
+
+func (r *SQLUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	return r.update(ctx, user, sql.NullTime{})
+}
+
+// UpdateIfUnchanged UPDATE ... WHERE id = $16 AND updated = $17 : aucune ligne touchée et un
+// utilisateur toujours présent signifient qu'il a été modifié entre-temps
+func (r *SQLUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	updated, err := r.update(ctx, user, sql.NullTime{Time: expectedUpdated, Valid: true})
+	if !errors.Is(err, repositories.ErrUserNotFound) {
+		return updated, err
+	}
+	if _, err := r.GetById(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return nil, repositories.ErrUserModified
+}
+
+func (r *SQLUserRepository) update(ctx context.Context, user *entities.User, expectedUpdated sql.NullTime) (*entities.User, error) {
+	ownerID, err := r.queries.GetUserIDByEmail(ctx, user.Email)
+	switch {
+	case err == nil && int(ownerID) != user.ID:
+		return nil, errors.New("email déjà utilisé")
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+	if user.Handle != "" {
+		ownerID, err := r.queries.GetUserIDByHandle(ctx, nullString(user.Handle))
+		switch {
+		case err == nil && int(ownerID) != user.ID:
+			return nil, repositories.ErrHandleTaken
+		case err != nil && !errors.Is(err, sql.ErrNoRows):
+			return nil, err
+		}
+	}
+
+	attributes, err := encodeAttributes(user.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := r.queries.UpdateUser(ctx, sqlcdb.UpdateUserParams{
+		Email:                    user.Email,
+		Name:                     user.Name,
+		Password:                 user.Password,
+		Updated:                  user.Updated,
+		WeeklyDigest:             user.WeeklyDigest,
+		Phone:                    user.Phone,
+		Status:                   string(user.CurrentStatus()),
+		Handle:                   nullString(user.Handle),
+		LastLoginAt:              nullTime(user.LastLoginAt),
+		CleanupFlaggedAt:         nullTime(user.CleanupFlaggedAt),
+		Attributes:               json.RawMessage(attributes),
+		EmailUndeliverableAt:     nullTime(user.EmailUndeliverableAt),
+		EmailUndeliverableReason: user.EmailUndeliverableReason,
+		Locale:                   user.Locale,
+		TimeZone:                 user.TimeZone,
+		ID:                       int32(user.ID),
+		ExpectedUpdated:          expectedUpdated,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	updated := *user
+	return &updated, nil
+}
+
+func (r *SQLUserRepository) DeleteById(ctx context.Context, id int) error {
+	affected, err := r.queries.DeleteUser(ctx, int32(id))
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repositories.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *SQLUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	rows, err := r.queries.ListUsers(ctx, sqlcdb.ListUsersParams{Limit: int32(limit), Offset: int32(offset)})
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*entities.User, 0, len(rows))
+	for _, row := range rows {
+		user, err := userFromRow(userRow(row), nil)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// ListInactiveSince comptes jamais connectés depuis l'inscription, ou dont la dernière connexion
+// est antérieure à cutoff
+func (r *SQLUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	rows, err := r.queries.ListInactiveUsersSince(ctx, sqlcdb.ListInactiveUsersSinceParams{
+		Cutoff:    cutoff,
+		RowLimit:  int32(limit),
+		RowOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*entities.User, 0, len(rows))
+	for _, row := range rows {
+		user, err := userFromRow(userRow(row), nil)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// Search construit la clause WHERE à partir des filtres renseignés (paramètres positionnels uniquement)
+func (r *SQLUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	var where sqlConditions
+
+	rank := ""
+	if filters.Query != "" {
+		// Chaque mot devient un préfixe obligatoire : "jean dup" → 'jean:* & dup:*' (index users_search_vector_idx)
+		terms := searchTerms(filters.Query)
+		if len(terms) == 0 {
+			return []*entities.User{}, nil
+		}
+		for i, term := range terms {
+			terms[i] = term + ":*"
+		}
+		where.add(`search_vector @@ to_tsquery('simple', $?)`, strings.Join(terms, " & "))
+		rank = `ts_rank(search_vector, to_tsquery('simple', $` + strconv.Itoa(len(where.args)) + `)) DESC, `
+	}
+	if err := where.addFilters(filters); err != nil {
+		return nil, err
+	}
+
+	query := userSelectColumns + where.clause() + ` ORDER BY ` + rank + `id`
+	if filters.Limit > 0 {
+		query += ` LIMIT ` + where.param(filters.Limit)
+	}
+	if filters.Offset > 0 {
+		query += ` OFFSET ` + where.param(filters.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*entities.User, 0, filters.Limit)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// Each lots lus par keyset (id > dernier ID vu, index primaire) : chaque lot est lu en entier et la
+// connexion rendue au pool avant d'appeler fn
+func (r *SQLUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	if filters.Query != "" {
+		return repositories.ErrFullTextNotSupported
+	}
+
+	// $1 : position du keyset, mise à jour à chaque lot
+	var where sqlConditions
+	where.add(`id > $?`, 0)
+	if err := where.addFilters(filters); err != nil {
+		return err
+	}
+	query := userSelectColumns + where.clause() + ` ORDER BY id LIMIT ` + where.param(userIterationBatchSize)
+
+	for {
+		batch, err := r.queryUsers(ctx, query, where.args...)
+		if err != nil {
+			return err
+		}
+		for _, user := range batch {
+			if err := fn(user); err != nil {
+				if errors.Is(err, repositories.ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		if len(batch) < userIterationBatchSize {
+			return nil
+		}
+		where.args[0] = batch[len(batch)-1].ID
+	}
+}
+
+func (r *SQLUserRepository) queryUsers(ctx context.Context, query string, args ...any) ([]*entities.User, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// sqlConditions clause WHERE à paramètres positionnels : "$?" est numéroté à l'ajout
+type sqlConditions struct {
+	conditions []string
+	args       []any
+}
+
+func (c *sqlConditions) add(condition string, value any) {
+	c.args = append(c.args, value)
+	c.conditions = append(c.conditions, strings.ReplaceAll(condition, "$?", "$"+strconv.Itoa(len(c.args))))
+}
+
+// param ajoute un paramètre hors condition (LIMIT, OFFSET) et retourne son placeholder
+func (c *sqlConditions) param(value any) string {
+	c.args = append(c.args, value)
+	return "$" + strconv.Itoa(len(c.args))
+}
+
+func (c *sqlConditions) clause() string {
+	if len(c.conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(c.conditions, " AND ")
+}
+
+// addFilters critères de filters hors Query, Limit et Offset (même périmètre que newUserFilterMatcher)
+func (c *sqlConditions) addFilters(filters repositories.UserRepositoryFilters) error {
+	if filters.Email != "" {
+		c.add(`email ILIKE $?`, "%"+escapeLike(filters.Email)+"%")
+	}
+	if filters.Name != "" {
+		c.add(`name ILIKE $?`, "%"+escapeLike(filters.Name)+"%")
+	}
+	if filters.Status != "" {
+		c.add(`status = $?`, filters.Status)
+	}
+	if filters.CreatedAt.From != nil {
+		c.add(`created >= $?::timestamptz`, *filters.CreatedAt.From)
+	}
+	if filters.CreatedAt.To != nil {
+		c.add(`created < $?::timestamptz`, *filters.CreatedAt.To)
+	}
+	if len(filters.Attributes) > 0 {
+		// Containment JSONB : servi par l'index GIN users_attributes_idx
+		attributes, err := encodeAttributes(filters.Attributes)
+		if err != nil {
+			return err
+		}
+		c.add(`attributes @> $?::jsonb`, attributes)
+	}
+	return nil
+}
+
+// likeEscaper neutralise les jokers LIKE saisis par le client (\ est l'échappement par défaut de PostgreSQL)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+func (r *SQLUserRepository) Count(ctx context.Context) (int, error) {
+	count, err := r.queries.CountUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// EstimateCount lit reltuples (mis à jour par VACUUM / ANALYZE et l'autovacuum) au lieu de parcourir
+// la table ; une table jamais analysée (-1, ou 0 avant PostgreSQL 14) retombe sur COUNT(*)
+func (r *SQLUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	estimate, err := r.queries.EstimateUserCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if estimate <= 0 {
+		return r.Count(ctx)
+	}
+	return int(estimate), nil
+}
+
+// rowScanner couvre *sql.Row et *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (*entities.User, error) {
+	var user entities.User
+	var attributes []byte
+	err := row.Scan(&user.ID, &user.Email, &user.Name, &user.Password, &user.Created, &user.Updated, &user.WeeklyDigest, &user.Phone, &user.Status, &user.Handle,
+		&user.LastLoginAt, &user.CleanupFlaggedAt, &attributes, &user.EmailUndeliverableAt, &user.EmailUndeliverableReason,
+		&user.Locale, &user.TimeZone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repositories.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Attributes, err = decodeAttributes(attributes); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// userRow colonnes des requêtes générées ; chaque requête sqlc a son propre type de ligne,
+// de mêmes champs : la conversion userRow(row) est vérifiée à la compilation
+type userRow struct {
+	ID                       int32
+	Email                    string
+	Name                     string
+	Password                 string
+	Created                  time.Time
+	Updated                  time.Time
+	WeeklyDigest             bool
+	Phone                    string
+	Status                   string
+	Handle                   sql.NullString
+	LastLoginAt              sql.NullTime
+	CleanupFlaggedAt         sql.NullTime
+	Attributes               json.RawMessage
+	EmailUndeliverableAt     sql.NullTime
+	EmailUndeliverableReason string
+	Locale                   string
+	TimeZone                 string
+}
+
+// userFromRow handle NULL → "", dates NULL → nil, attributs '{}' → nil (comme scanUser)
+func userFromRow(row userRow, err error) (*entities.User, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repositories.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	user := &entities.User{
+		ID:                       int(row.ID),
+		Email:                    row.Email,
+		Name:                     row.Name,
+		Password:                 row.Password,
+		Created:                  row.Created,
+		Updated:                  row.Updated,
+		WeeklyDigest:             row.WeeklyDigest,
+		Phone:                    row.Phone,
+		Status:                   entities.UserStatus(row.Status),
+		Handle:                   row.Handle.String,
+		LastLoginAt:              timePtr(row.LastLoginAt),
+		CleanupFlaggedAt:         timePtr(row.CleanupFlaggedAt),
+		EmailUndeliverableAt:     timePtr(row.EmailUndeliverableAt),
+		EmailUndeliverableReason: row.EmailUndeliverableReason,
+		Locale:                   row.Locale,
+		TimeZone:                 row.TimeZone,
+	}
+	if user.Attributes, err = decodeAttributes(row.Attributes); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// nullString une chaîne vide est stockée NULL (colonnes optionnelles et uniques comme handle)
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *value, Valid: true}
+}
+
+func timePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+// encodeAttributes sérialise les attributs pour la colonne JSONB ('{}' quand il n'y en a pas)
+func encodeAttributes(attributes map[string]any) (string, error) {
+	if len(attributes) == 0 {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(attributes)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// decodeAttributes un objet vide redonne nil, comme pour un utilisateur en mémoire
+func decodeAttributes(raw []byte) (map[string]any, error) {
+	if len(raw) == 0 || string(raw) == "{}" {
+		return nil, nil
+	}
+	var attributes map[string]any
+	if err := json.Unmarshal(raw, &attributes); err != nil {
+		return nil, err
+	}
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	return attributes, nil
+}
+
+// sqlBatchSize lignes par requête multi-lignes (8 paramètres par ligne, bien sous la limite de 65535)
+const sqlBatchSize = 500
+
+// CreateMany insère les utilisateurs par INSERT multi-lignes dans une seule transaction
+func (r *SQLUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if len(users) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // Sans effet après Commit
+
+	emails := make([]any, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	var taken int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE email IN (`+placeholders(1, len(emails))+`)`, emails...).Scan(&taken); err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		return nil, errors.New("email déjà utilisé")
+	}
+
+	created := make([]*entities.User, 0, len(users))
+	for start := 0; start < len(users); start += sqlBatchSize {
+		batch := users[start:min(start+sqlBatchSize, len(users))]
+
+		var query strings.Builder
+		query.WriteString(`INSERT INTO users (email, name, password, created, updated, weekly_digest, phone, status) VALUES `)
+		args := make([]any, 0, len(batch)*8)
+		for i, user := range batch {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(" + placeholders(len(args)+1, 8) + ")")
+			args = append(args, user.Email, user.Name, user.Password, user.Created, user.Updated, user.WeeklyDigest, user.Phone, string(user.CurrentStatus()))
+		}
+		// RETURNING respecte l'ordre des VALUES pour un INSERT multi-lignes PostgreSQL
+		query.WriteString(` RETURNING id`)
+
+		rows, err := tx.QueryContext(ctx, query.String(), args...)
+		if err != nil {
+			return nil, err
+		}
+		i := 0
+		for rows.Next() {
+			user := *batch[i]
+			if err := rows.Scan(&user.ID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			created = append(created, &user)
+			i++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateMany applique les modifications par UPDATE ... FROM (VALUES ...) dans une seule transaction
+func (r *SQLUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	if len(users) == 0 {
+		return []*entities.User{}, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(users); start += sqlBatchSize {
+		batch := users[start:min(start+sqlBatchSize, len(users))]
+
+		var values strings.Builder
+		args := make([]any, 0, len(batch)*8)
+		for i, user := range batch {
+			if i > 0 {
+				values.WriteString(", ")
+			}
+			values.WriteString("(" + placeholders(len(args)+1, 8) + ")")
+			args = append(args, user.ID, user.Email, user.Name, user.Password, user.Updated, user.WeeklyDigest, user.Phone, string(user.CurrentStatus()))
+		}
+
+		// Casts explicites : les types des colonnes VALUES ne peuvent pas être inférés
+		result, err := tx.ExecContext(ctx, `UPDATE users AS u SET
+			email = v.email::text, name = v.name::text, password = v.password::text,
+			updated = v.updated::timestamptz, weekly_digest = v.weekly_digest::boolean, phone = v.phone::text,
+			status = v.status::text
+			FROM (VALUES `+values.String()+`) AS v(id, email, name, password, updated, weekly_digest, phone, status)
+			WHERE u.id = v.id::integer`, args...)
+		if err != nil {
+			// Violation de la contrainte UNIQUE sur email : le lot entier est annulé
+			return nil, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected != int64(len(batch)) {
+			return nil, repositories.ErrUserNotFound
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	updated := make([]*entities.User, len(users))
+	for i, user := range users {
+		userCopy := *user
+		updated[i] = &userCopy
+	}
+	return updated, nil
+}
+
+// DeleteByIds supprime par DELETE ... WHERE id IN (...) ; annule tout si un ID est inconnu
+func (r *SQLUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(ids); start += sqlBatchSize {
+		batch := ids[start:min(start+sqlBatchSize, len(ids))]
+
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id IN (`+placeholders(1, len(args))+`)`, args...)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected != int64(len(batch)) {
+			return repositories.ErrUserNotFound
+		}
+	}
+
+	return tx.Commit()
+}
+
+// placeholders retourne "$from, $from+1, ..." pour count paramètres
+func placeholders(from, count int) string {
+	var b strings.Builder
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("$" + strconv.Itoa(from+i))
+	}
+	return b.String()
+}
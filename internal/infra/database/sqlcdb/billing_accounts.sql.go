@@ -0,0 +1,127 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: billing_accounts.sql
+
+package sqlcdb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const upsertBillingAccount = `-- name: UpsertBillingAccount :exec
+INSERT INTO billing_accounts (tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+                              reported_period, reported_calls, updated)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (tenant_id) DO UPDATE SET customer_id = EXCLUDED.customer_id,
+    subscription_id = EXCLUDED.subscription_id, plan = EXCLUDED.plan, status = EXCLUDED.status,
+    current_period_end = EXCLUDED.current_period_end, reported_period = EXCLUDED.reported_period,
+    reported_calls = EXCLUDED.reported_calls, updated = EXCLUDED.updated
+`
+
+type UpsertBillingAccountParams struct {
+	TenantID         string
+	CustomerID       string
+	SubscriptionID   string
+	Plan             string
+	Status           string
+	CurrentPeriodEnd sql.NullTime
+	ReportedPeriod   string
+	ReportedCalls    int64
+	Updated          time.Time
+}
+
+func (q *Queries) UpsertBillingAccount(ctx context.Context, arg UpsertBillingAccountParams) error {
+	_, err := q.db.ExecContext(ctx, upsertBillingAccount, arg.TenantID, arg.CustomerID, arg.SubscriptionID, arg.Plan, arg.Status, arg.CurrentPeriodEnd, arg.ReportedPeriod, arg.ReportedCalls, arg.Updated)
+	return err
+}
+
+const getBillingAccountByTenant = `-- name: GetBillingAccountByTenant :one
+SELECT tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+       reported_period, reported_calls, updated
+FROM billing_accounts
+WHERE tenant_id = $1
+`
+
+func (q *Queries) GetBillingAccountByTenant(ctx context.Context, tenantID string) (BillingAccount, error) {
+	row := q.db.QueryRowContext(ctx, getBillingAccountByTenant, tenantID)
+	var i BillingAccount
+	err := row.Scan(
+		&i.TenantID,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.Plan,
+		&i.Status,
+		&i.CurrentPeriodEnd,
+		&i.ReportedPeriod,
+		&i.ReportedCalls,
+		&i.Updated,
+	)
+	return i, err
+}
+
+const getBillingAccountByCustomer = `-- name: GetBillingAccountByCustomer :one
+SELECT tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+       reported_period, reported_calls, updated
+FROM billing_accounts
+WHERE customer_id = $1
+`
+
+func (q *Queries) GetBillingAccountByCustomer(ctx context.Context, customerID string) (BillingAccount, error) {
+	row := q.db.QueryRowContext(ctx, getBillingAccountByCustomer, customerID)
+	var i BillingAccount
+	err := row.Scan(
+		&i.TenantID,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.Plan,
+		&i.Status,
+		&i.CurrentPeriodEnd,
+		&i.ReportedPeriod,
+		&i.ReportedCalls,
+		&i.Updated,
+	)
+	return i, err
+}
+
+const listBillingAccounts = `-- name: ListBillingAccounts :many
+SELECT tenant_id, customer_id, subscription_id, plan, status, current_period_end,
+       reported_period, reported_calls, updated
+FROM billing_accounts
+ORDER BY tenant_id
+`
+
+func (q *Queries) ListBillingAccounts(ctx context.Context) ([]BillingAccount, error) {
+	rows, err := q.db.QueryContext(ctx, listBillingAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BillingAccount
+	for rows.Next() {
+		var i BillingAccount
+		if err := rows.Scan(
+			&i.TenantID,
+			&i.CustomerID,
+			&i.SubscriptionID,
+			&i.Plan,
+			&i.Status,
+			&i.CurrentPeriodEnd,
+			&i.ReportedPeriod,
+			&i.ReportedCalls,
+			&i.Updated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
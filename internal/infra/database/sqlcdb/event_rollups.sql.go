@@ -0,0 +1,86 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: event_rollups.sql
+
+package sqlcdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const incrementEventRollup = `-- name: IncrementEventRollup :exec
+INSERT INTO event_rollups (day, event, count)
+VALUES ($1, $2, $3)
+ON CONFLICT (day, event) DO UPDATE SET count = event_rollups.count + EXCLUDED.count
+`
+
+type IncrementEventRollupParams struct {
+	Day   time.Time
+	Event string
+	Count int64
+}
+
+func (q *Queries) IncrementEventRollup(ctx context.Context, arg IncrementEventRollupParams) error {
+	_, err := q.db.ExecContext(ctx, incrementEventRollup, arg.Day, arg.Event, arg.Count)
+	return err
+}
+
+const deleteEventRollups = `-- name: DeleteEventRollups :exec
+DELETE FROM event_rollups WHERE day >= $1 AND day < $2
+`
+
+type DeleteEventRollupsParams struct {
+	FromDay time.Time
+	ToDay   time.Time
+}
+
+func (q *Queries) DeleteEventRollups(ctx context.Context, arg DeleteEventRollupsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteEventRollups, arg.FromDay, arg.ToDay)
+	return err
+}
+
+const listEventRollups = `-- name: ListEventRollups :many
+SELECT day, event, count
+FROM event_rollups
+WHERE day >= $1 AND day < $2
+  AND ($3::jsonb = '[]'::jsonb
+       OR event IN (SELECT jsonb_array_elements_text($3::jsonb)))
+ORDER BY day, event
+`
+
+type ListEventRollupsParams struct {
+	FromDay time.Time
+	ToDay   time.Time
+	Names   json.RawMessage
+}
+
+// names : tableau JSON des événements retenus, [] pour tous
+func (q *Queries) ListEventRollups(ctx context.Context, arg ListEventRollupsParams) ([]EventRollup, error) {
+	rows, err := q.db.QueryContext(ctx, listEventRollups, arg.FromDay, arg.ToDay, arg.Names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventRollup
+	for rows.Next() {
+		var i EventRollup
+		if err := rows.Scan(
+			&i.Day,
+			&i.Event,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
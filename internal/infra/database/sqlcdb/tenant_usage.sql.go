@@ -0,0 +1,72 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: tenant_usage.sql
+
+package sqlcdb
+
+import (
+	"context"
+)
+
+const addTenantUsage = `-- name: AddTenantUsage :one
+INSERT INTO tenant_usage (tenant, period, resource, value)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant, period, resource) DO UPDATE SET value = tenant_usage.value + EXCLUDED.value
+RETURNING value
+`
+
+type AddTenantUsageParams struct {
+	Tenant   string
+	Period   string
+	Resource string
+	Value    int64
+}
+
+// Un seul aller-retour : l'upsert retourne la valeur après incrément
+func (q *Queries) AddTenantUsage(ctx context.Context, arg AddTenantUsageParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, addTenantUsage, arg.Tenant, arg.Period, arg.Resource, arg.Value)
+	var value int64
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getTenantUsage = `-- name: GetTenantUsage :many
+SELECT resource, value FROM tenant_usage WHERE tenant = $1 AND period = $2
+`
+
+type GetTenantUsageParams struct {
+	Tenant string
+	Period string
+}
+
+type GetTenantUsageRow struct {
+	Resource string
+	Value    int64
+}
+
+func (q *Queries) GetTenantUsage(ctx context.Context, arg GetTenantUsageParams) ([]GetTenantUsageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTenantUsage, arg.Tenant, arg.Period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTenantUsageRow
+	for rows.Next() {
+		var i GetTenantUsageRow
+		if err := rows.Scan(
+			&i.Resource,
+			&i.Value,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
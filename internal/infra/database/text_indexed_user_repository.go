@@ -0,0 +1,374 @@
+package database
+
+import (
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Poids des champs dans le score de pertinence, alignés sur ts_rank (setweight A pour le nom, B pour l'email)
+const (
+	textWeightName  = 1.0
+	textWeightEmail = 0.4
+	// textPrefixFactor une correspondance par préfixe ("mart" → "martin") compte moins qu'un mot exact
+	textPrefixFactor = 0.5
+)
+
+// textFields champs d'un utilisateur où un mot apparaît
+type textFields uint8
+
+const (
+	textFieldName textFields = 1 << iota
+	textFieldEmail
+)
+
+func (f textFields) weight() float64 {
+	if f&textFieldName != 0 {
+		return textWeightName
+	}
+	return textWeightEmail
+}
+
+// searchTerms découpe un texte en mots indexables : minuscules, lettres et chiffres uniquement
+// "Jean-Pierre" → [jean pierre], "j.dupont+rh@example.fr" → [j dupont rh example fr]
+// Partagé avec SQLUserRepository : les mots d'une requête ne portent jamais de syntaxe tsquery
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// TextIndexedUserRepository décore un repositories.UserRepository sans recherche plein texte
+// (mémoire, état courant event-sourcé) avec un index inversé sur le nom et l'email :
+//   - l'index est mis à jour à chaque écriture réussie passant par le décorateur
+//   - Search avec Query classe les utilisateurs dont chaque mot de la requête préfixe un mot indexé,
+//     les autres critères sont délégués (ou appliqués aux candidats quand Query est renseigné)
+//
+// L'index vit en mémoire du processus : il n'a de sens que pour un stockage lui-même local
+type TextIndexedUserRepository struct {
+	next repositories.UserRepository
+
+	mutex sync.RWMutex
+	// postings mot -> utilisateur -> champs où il apparaît
+	postings map[string]map[int]textFields
+	// terms dictionnaire trié des mots, pour les correspondances par préfixe
+	terms []string
+	// documents mots indexés par utilisateur, pour la désindexation
+	documents map[int][]string
+}
+
+func NewTextIndexedUserRepository(next repositories.UserRepository) *TextIndexedUserRepository {
+	return &TextIndexedUserRepository{
+		next:      next,
+		postings:  make(map[string]map[int]textFields),
+		documents: make(map[int][]string),
+	}
+}
+
+// =============================================================================
+// ÉCRITURES : DÉLÉGUÉES PUIS INDEXÉES
+// =============================================================================
+
+// Les écritures restent sous le verrou de l'index : deux mises à jour concurrentes d'un même
+// utilisateur ne peuvent pas être indexées dans le désordre
+
+func (r *TextIndexedUserRepository) Create(ctx context.Context, user *entities.User) (*entities.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	created, err := r.next.Create(ctx, user)
+	if err == nil {
+		r.index(created)
+	}
+	return created, err
+}
+
+func (r *TextIndexedUserRepository) Update(ctx context.Context, user *entities.User) (*entities.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	updated, err := r.next.Update(ctx, user)
+	if err == nil {
+		r.index(updated)
+	}
+	return updated, err
+}
+
+func (r *TextIndexedUserRepository) UpdateIfUnchanged(ctx context.Context, user *entities.User, expectedUpdated time.Time) (*entities.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	updated, err := r.next.UpdateIfUnchanged(ctx, user, expectedUpdated)
+	if err == nil {
+		r.index(updated)
+	}
+	return updated, err
+}
+
+func (r *TextIndexedUserRepository) DeleteById(ctx context.Context, id int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := r.next.DeleteById(ctx, id)
+	if err == nil {
+		r.unindex(id)
+	}
+	return err
+}
+
+func (r *TextIndexedUserRepository) CreateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	created, err := r.next.CreateMany(ctx, users)
+	if err == nil {
+		for _, user := range created {
+			r.index(user)
+		}
+	}
+	return created, err
+}
+
+func (r *TextIndexedUserRepository) UpdateMany(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	updated, err := r.next.UpdateMany(ctx, users)
+	if err == nil {
+		for _, user := range updated {
+			r.index(user)
+		}
+	}
+	return updated, err
+}
+
+func (r *TextIndexedUserRepository) DeleteByIds(ctx context.Context, ids []int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := r.next.DeleteByIds(ctx, ids)
+	if err == nil {
+		for _, id := range ids {
+			r.unindex(id)
+		}
+	}
+	return err
+}
+
+// index (ré)indexe le nom et l'email de l'utilisateur ; appelé sous verrou exclusif
+func (r *TextIndexedUserRepository) index(user *entities.User) {
+	r.unindex(user.ID)
+
+	fields := make(map[string]textFields)
+	for _, term := range searchTerms(user.Name) {
+		fields[term] |= textFieldName
+	}
+	for _, term := range searchTerms(user.Email) {
+		fields[term] |= textFieldEmail
+	}
+
+	terms := make([]string, 0, len(fields))
+	for term, field := range fields {
+		postings, exists := r.postings[term]
+		if !exists {
+			postings = make(map[int]textFields)
+			r.postings[term] = postings
+			position, _ := slices.BinarySearch(r.terms, term)
+			r.terms = slices.Insert(r.terms, position, term)
+		}
+		postings[user.ID] = field
+		terms = append(terms, term)
+	}
+	r.documents[user.ID] = terms
+}
+
+func (r *TextIndexedUserRepository) unindex(id int) {
+	for _, term := range r.documents[id] {
+		postings := r.postings[term]
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(r.postings, term)
+			if position, found := slices.BinarySearch(r.terms, term); found {
+				r.terms = slices.Delete(r.terms, position, position+1)
+			}
+		}
+	}
+	delete(r.documents, id)
+}
+
+// =============================================================================
+// RECHERCHE
+// =============================================================================
+
+type rankedUser struct {
+	id    int
+	score float64
+}
+
+// Search sans Query : délégué au dépôt décoré s'il implémente UserSearchRepository
+func (r *TextIndexedUserRepository) Search(ctx context.Context, filters repositories.UserRepositoryFilters) ([]*entities.User, error) {
+	if filters.Query == "" {
+		searcher, ok := r.next.(repositories.UserSearchRepository)
+		if !ok {
+			return nil, repositories.ErrSearchNotSupported
+		}
+		return searcher.Search(ctx, filters)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	matches, err := newUserFilterMatcher(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := r.rank(searchTerms(filters.Query))
+	if len(ranked) == 0 {
+		return []*entities.User{}, nil
+	}
+	ids := make([]int, len(ranked))
+	for i, candidate := range ranked {
+		ids[i] = candidate.id
+	}
+	candidates, err := r.next.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*entities.User, len(candidates))
+	for _, user := range candidates {
+		byID[user.ID] = user
+	}
+
+	users := make([]*entities.User, 0, min(len(ranked), max(filters.Limit, 0)))
+	skipped := 0
+	for _, candidate := range ranked {
+		user, ok := byID[candidate.id]
+		if !ok || !matches(user) {
+			continue
+		}
+		if skipped < filters.Offset {
+			skipped++
+			continue
+		}
+		users = append(users, user)
+		if filters.Limit > 0 && len(users) == filters.Limit {
+			break
+		}
+	}
+	return users, nil
+}
+
+// rank score des utilisateurs contenant tous les mots (TF-IDF simplifié) : pour chaque mot de la
+// requête, la meilleure correspondance pondérée par le champ, la rareté du mot indexé et un facteur
+// de préfixe ; tri par score décroissant puis ID
+func (r *TextIndexedUserRepository) rank(query []string) []rankedUser {
+	if len(query) == 0 {
+		return nil
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	documents := float64(len(r.documents))
+	var scores map[int]float64
+	for _, word := range query {
+		best := make(map[int]float64)
+		position, _ := slices.BinarySearch(r.terms, word)
+		for _, term := range r.terms[position:] {
+			if !strings.HasPrefix(term, word) {
+				break
+			}
+			postings := r.postings[term]
+			idf := math.Log(1 + documents/float64(len(postings)))
+			factor := 1.0
+			if term != word {
+				factor = textPrefixFactor
+			}
+			for id, fields := range postings {
+				if score := fields.weight() * idf * factor; score > best[id] {
+					best[id] = score
+				}
+			}
+		}
+
+		// ET : seuls les utilisateurs qui correspondent à tous les mots précédents restent candidats
+		if scores == nil {
+			scores = best
+			continue
+		}
+		for id, score := range scores {
+			if wordScore, ok := best[id]; ok {
+				scores[id] = score + wordScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	ranked := make([]rankedUser, 0, len(scores))
+	for id, score := range scores {
+		ranked = append(ranked, rankedUser{id: id, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].id < ranked[j].id
+	})
+	return ranked
+}
+
+// =============================================================================
+// LECTURES : DÉLÉGUÉES
+// =============================================================================
+
+func (r *TextIndexedUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	return r.next.GetById(ctx, id)
+}
+
+func (r *TextIndexedUserRepository) GetByIds(ctx context.Context, ids []int) ([]*entities.User, error) {
+	return r.next.GetByIds(ctx, ids)
+}
+
+func (r *TextIndexedUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.next.GetByEmail(ctx, email)
+}
+
+func (r *TextIndexedUserRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	return r.next.IsEmailTaken(ctx, email)
+}
+
+func (r *TextIndexedUserRepository) GetByHandle(ctx context.Context, handle string) (*entities.User, error) {
+	return r.next.GetByHandle(ctx, handle)
+}
+
+func (r *TextIndexedUserRepository) IsHandleTaken(ctx context.Context, handle string) (bool, error) {
+	return r.next.IsHandleTaken(ctx, handle)
+}
+
+func (r *TextIndexedUserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	return r.next.List(ctx, limit, offset)
+}
+
+func (r *TextIndexedUserRepository) Count(ctx context.Context) (int, error) {
+	return r.next.Count(ctx)
+}
+
+func (r *TextIndexedUserRepository) EstimateCount(ctx context.Context) (int, error) {
+	return r.next.EstimateCount(ctx)
+}
+
+func (r *TextIndexedUserRepository) ListInactiveSince(ctx context.Context, cutoff time.Time, limit, offset int) ([]*entities.User, error) {
+	return r.next.ListInactiveSince(ctx, cutoff, limit, offset)
+}
+
+func (r *TextIndexedUserRepository) Each(ctx context.Context, filters repositories.UserRepositoryFilters, fn func(*entities.User) error) error {
+	return r.next.Each(ctx, filters, fn)
+}
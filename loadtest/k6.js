@@ -0,0 +1,32 @@
+// Scénario k6 rejouant les cibles générées par `api seed -targets loadtest/targets.jsonl`
+// Usage : k6 run -e TARGETS=loadtest/targets.jsonl -e VUS=20 -e DURATION=30s loadtest/k6.js
+import http from 'k6/http';
+import encoding from 'k6/encoding';
+import { check } from 'k6';
+
+const targets = open(__ENV.TARGETS || './targets.jsonl')
+  .split('\n')
+  .filter((line) => line.trim() !== '')
+  .map((line) => JSON.parse(line));
+
+export const options = {
+  vus: Number(__ENV.VUS || 20),
+  duration: __ENV.DURATION || '30s',
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    http_req_duration: ['p(95)<200'],
+  },
+};
+
+export default function () {
+  // Chaque itération rejoue la cible suivante : l'ordre du fichier est reproductible (-seed)
+  const target = targets[(__VU * 7919 + __ITER) % targets.length];
+  const headers = {};
+  for (const [name, values] of Object.entries(target.header || {})) {
+    headers[name] = values.join(', ');
+  }
+  const body = target.body ? encoding.b64decode(target.body, 'std', 's') : null;
+
+  const res = http.request(target.method, target.url, body, { headers });
+  check(res, { 'status < 400': (r) => r.status < 400 });
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Issues d'un événement envoyé par TrackEvent
+const (
+	TrackAccepted = "accepted"
+	// TrackSampled hors limite de cardinalité, retenu par échantillonnage
+	TrackSampled = "sampled"
+	// TrackDropped hors limite de cardinalité, écarté (pas une erreur : ne pas renvoyer)
+	TrackDropped = "dropped"
+)
+
+// TrackEventRequest entrée de POST /analytics/track ou /collect/track (use case track_event)
+type TrackEventRequest struct {
+	Event string
+	// Properties valeurs scalaires (chaîne, nombre, booléen)
+	Properties map[string]any
+	// Timestamp horodatage de l'événement côté client ; zéro = heure de réception
+	Timestamp time.Time
+}
+
+type trackEventPayload struct {
+	Event      string         `json:"event"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Timestamp  string         `json:"timestamp,omitempty"`
+}
+
+// TrackEventResponse sortie de POST /analytics/track
+type TrackEventResponse struct {
+	// Status TrackAccepted, TrackSampled ou TrackDropped
+	Status string `json:"status"`
+}
+
+// TrackEvent enregistre un événement analytics pour l'utilisateur du jeton (anonyme sans jeton) ;
+// avec WithWriteKey, un événement anonyme du tenant de la clé
+func (c *Client) TrackEvent(ctx context.Context, req TrackEventRequest) (*TrackEventResponse, error) {
+	payload := trackEventPayload{Event: req.Event, Properties: req.Properties}
+	if !req.Timestamp.IsZero() {
+		payload.Timestamp = req.Timestamp.UTC().Format(time.RFC3339)
+	}
+	path := "/v1/analytics/track"
+	if c.writeKey != "" {
+		path = "/collect/track"
+	}
+	var response TrackEventResponse
+	if err := c.do(ctx, http.MethodPost, path, payload, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,342 @@
+// Package client SDK Go de l'API : méthodes typées calquées sur les use cases, nouveaux essais
+// automatiques, clés d'idempotence et gestion du jeton d'accès
+//
+//	api, err := client.New("https://api.example.com", client.WithCredentials("ops@example.com", password))
+//	user, err := api.CreateUser(ctx, client.CreateUserRequest{Email: "...", Name: "...", Password: "..."})
+//
+// Le package ne dépend que de la bibliothèque standard : il ne réutilise pas les DTO internes,
+// son contrat est celui de l'API HTTP (v1)
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 3
+	defaultRetryDelay = 200 * time.Millisecond
+	// maxRetryDelay plafond d'attente entre deux essais, Retry-After compris
+	maxRetryDelay = 30 * time.Second
+	// tokenRefreshMargin le jeton est renouvelé avant son expiration annoncée
+	tokenRefreshMargin = 30 * time.Second
+)
+
+// Client client de l'API, sûr pour un usage concurrent
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+	maxRetries int
+	retryDelay time.Duration
+
+	// token jeton fixe (WithToken) ; email / password connexion à la demande (WithCredentials)
+	token    string
+	email    string
+	password string
+	// writeKey clé d'écriture de l'ingestion publique (WithWriteKey)
+	writeKey string
+
+	mutex     sync.Mutex
+	session   string
+	expiresAt time.Time
+}
+
+// Option configure un Client
+type Option func(*Client)
+
+// WithHTTPClient remplace le client HTTP (transport, proxy, timeout...)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithToken jeton d'accès fixe (jeton de service, jeton obtenu ailleurs)
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithCredentials connexion par POST /auth/login au premier appel ; le jeton est renouvelé
+// avant son expiration et après un 401
+func WithCredentials(email, password string) Option {
+	return func(c *Client) { c.email, c.password = email, password }
+}
+
+// WithWriteKey envoie TrackEvent à l'ingestion publique (POST /collect/track), authentifiée par la
+// clé d'écriture du tenant plutôt que par un jeton : les événements sont anonymes, rattachés au tenant
+func WithWriteKey(key string) Option {
+	return func(c *Client) { c.writeKey = key }
+}
+
+// WithRetries nombre de nouveaux essais (0 = aucun) et délai du premier, doublé à chaque essai
+func WithRetries(maxRetries int, delay time.Duration) Option {
+	return func(c *Client) { c.maxRetries, c.retryDelay = maxRetries, delay }
+}
+
+// WithUserAgent identifie l'application appelante dans les journaux de l'API
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New crée un client ; baseURL sans préfixe de version (ex: https://api.example.com)
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("client: URL de base invalide %q", baseURL)
+	}
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		userAgent:  "clean-archi-analytics-go",
+		maxRetries: defaultMaxRetries,
+		retryDelay: defaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// =============================================================================
+// ERREURS
+// =============================================================================
+
+// Error réponse d'erreur de l'API ; Code est stable (ex: "quota_exceeded"), Message ne l'est pas
+type Error struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"error"`
+	Code       string `json:"code,omitempty"`
+	Parameter  string `json:"parameter,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("api: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("api: %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound vrai si l'API a répondu 404
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// =============================================================================
+// IDEMPOTENCE
+// =============================================================================
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey impose la clé d'idempotence du prochain POST passé avec ce context.
+// Sans clé imposée, chaque appel en tire une au hasard, réutilisée par ses nouveaux essais ;
+// imposer la clé protège aussi contre un rejeu de l'application (redémarrage, file de messages)
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func idempotencyKey(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+	var raw [16]byte
+	_, _ = rand.Read(raw[:])
+	return hex.EncodeToString(raw[:])
+}
+
+// =============================================================================
+// TRANSPORT
+// =============================================================================
+
+// do envoie la requête avec nouveaux essais, décode la réponse dans out (ignorée si nil)
+// Un POST n'est rejoué qu'avec sa clé d'idempotence : l'API renvoie alors la réponse
+// de la première exécution au lieu de recréer la ressource
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	var key string
+	if method == http.MethodPost {
+		key = idempotencyKey(ctx)
+	}
+
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, body, key)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && c.email != "" && !refreshed && !isCollectPath(path) {
+			// Jeton révoqué ou expiré plus tôt qu'annoncé : une reconnexion, sans compter d'essai
+			resp.Body.Close()
+			c.resetSession()
+			refreshed = true
+			attempt--
+			continue
+		}
+
+		retry, delay := c.shouldRetry(ctx, resp, err, attempt)
+		if !retry {
+			if err != nil {
+				return err
+			}
+			return decodeResponse(resp, out)
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, body []byte, key string) (*http.Response, error) {
+	var token string
+	if !isCollectPath(path) {
+		var err error
+		if token, err = c.accessToken(ctx); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if isCollectPath(path) {
+		req.Header.Set("X-Write-Key", c.writeKey)
+	}
+	return c.httpClient.Do(req)
+}
+
+// isCollectPath routes de l'ingestion publique, authentifiées par la clé d'écriture seule
+func isCollectPath(path string) bool {
+	return strings.HasPrefix(path, "/collect/")
+}
+
+// shouldRetry rejoue les erreurs de transport, 429 et 502/503/504, dans la limite de maxRetries ;
+// le délai double à chaque essai (avec une part aléatoire), Retry-After est respecté
+func (c *Client) shouldRetry(ctx context.Context, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= c.maxRetries || ctx.Err() != nil {
+		return false, 0
+	}
+	if err != nil {
+		var apiErr *Error
+		// Échec de connexion (POST /auth/login refusé) : inutile d'insister
+		return !errors.As(err, &apiErr), c.backoff(attempt)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return false, 0
+	}
+	// Quota du tenant épuisé : il ne se libère pas en quelques secondes
+	if resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+		return false, 0
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return true, min(time.Duration(seconds)*time.Second, maxRetryDelay)
+	}
+	return true, c.backoff(attempt)
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := min(c.retryDelay<<attempt, maxRetryDelay)
+	// Part aléatoire : des clients coupés en même temps ne reviennent pas ensemble
+	return delay/2 + mathrand.N(delay/2+1)
+}
+
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(raw, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(raw))
+		}
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("api: réponse invalide : %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+// JETON D'ACCÈS
+// =============================================================================
+
+type loginResponse struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// accessToken jeton fixe, ou jeton de session (connexion si absent ou bientôt expiré)
+// Le mutex est tenu pendant la connexion : les appels concurrents attendent un seul login
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if c.email == "" {
+		return c.token, nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.session != "" && time.Until(c.expiresAt) > tokenRefreshMargin {
+		return c.session, nil
+	}
+
+	body, _ := json.Marshal(map[string]string{"email": c.email, "password": c.password})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String()+"/auth/login", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	var login loginResponse
+	if err := decodeResponse(resp, &login); err != nil {
+		return "", err
+	}
+	c.session, c.expiresAt = login.AccessToken, login.ExpiresAt
+	return c.session, nil
+}
+
+func (c *Client) resetSession() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.session = ""
+}
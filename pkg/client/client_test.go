@@ -0,0 +1,347 @@
+package client_test
+
+import (
+	"clean-archi-analytics/pkg/client"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAPI reproduit le contrat HTTP des routes utilisées par le SDK ; failures liste les
+// statuts à renvoyer, dans l'ordre, avant de traiter normalement les requêtes
+type fakeAPI struct {
+	mutex      sync.Mutex
+	failures   []int
+	retryAfter string
+	logins     int
+	validToken string
+	// keys en-têtes Idempotency-Key reçus par POST /v1/users (un par essai)
+	keys   []string
+	users  map[string]client.CreatedUser
+	events []map[string]any
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *httptest.Server) {
+	api := &fakeAPI{users: make(map[string]client.CreatedUser)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /auth/login", api.login)
+	mux.HandleFunc("POST /v1/users", api.createUser)
+	mux.HandleFunc("GET /v1/users/{id}", api.getUser)
+	mux.HandleFunc("POST /v1/analytics/track", api.track)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func newTestClient(t *testing.T, server *httptest.Server, opts ...client.Option) *client.Client {
+	t.Helper()
+	opts = append([]client.Option{client.WithRetries(3, time.Millisecond)}, opts...)
+	c, err := client.New(server.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func (api *fakeAPI) fail(w http.ResponseWriter) bool {
+	if len(api.failures) == 0 {
+		return false
+	}
+	status := api.failures[0]
+	api.failures = api.failures[1:]
+	if api.retryAfter != "" {
+		w.Header().Set("Retry-After", api.retryAfter)
+	}
+	code := ""
+	if status == http.StatusTooManyRequests {
+		code = "quota_exceeded"
+	}
+	writeJSON(w, status, map[string]string{"error": http.StatusText(status), "code": code})
+	return true
+}
+
+func (api *fakeAPI) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if api.validToken == "" || r.Header.Get("Authorization") == "Bearer "+api.validToken {
+		return true
+	}
+	writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "authentication required", "code": "authentication_required"})
+	return false
+}
+
+func (api *fakeAPI) login(w http.ResponseWriter, r *http.Request) {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	var credentials struct{ Email, Password string }
+	_ = json.NewDecoder(r.Body).Decode(&credentials)
+	if credentials.Password != "secret" {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid credentials", "code": "invalid_credentials"})
+		return
+	}
+	api.logins++
+	api.validToken = "token-" + strconv.Itoa(api.logins)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": api.validToken,
+		"token_type":   "Bearer",
+		"expires_at":   time.Now().Add(time.Hour),
+	})
+}
+
+// createUser rejoue la réponse d'une clé déjà traitée, comme le middleware Idempotency de l'API
+func (api *fakeAPI) createUser(w http.ResponseWriter, r *http.Request) {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	key := r.Header.Get("Idempotency-Key")
+	api.keys = append(api.keys, key)
+	if !api.authorized(w, r) || api.fail(w) {
+		return
+	}
+	if user, ok := api.users[key]; ok {
+		writeJSON(w, http.StatusCreated, user)
+		return
+	}
+	var req client.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "email obligatoire"})
+		return
+	}
+	user := client.CreatedUser{ID: len(api.users) + 1, Email: req.Email, Name: req.Name, Created: time.Now()}
+	api.users[key] = user
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (api *fakeAPI) getUser(w http.ResponseWriter, r *http.Request) {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	if !api.authorized(w, r) || api.fail(w) {
+		return
+	}
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	for _, user := range api.users {
+		if user.ID == id {
+			writeJSON(w, http.StatusOK, client.User{ID: user.ID, Email: user.Email, Name: user.Name, Status: "active"})
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{"error": "utilisateur introuvable"})
+}
+
+func (api *fakeAPI) track(w http.ResponseWriter, r *http.Request) {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	if api.fail(w) {
+		return
+	}
+	var event map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&event)
+	api.events = append(api.events, event)
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestCreateUserRetriesWithSameIdempotencyKey(t *testing.T) {
+	api, server := newFakeAPI(t)
+	api.failures = []int{http.StatusServiceUnavailable, http.StatusBadGateway}
+	c := newTestClient(t, server)
+
+	user, err := c.CreateUser(context.Background(), client.CreateUserRequest{Email: "alice@example.com", Name: "Alice", Password: "Secret123!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 1 || user.Email != "alice@example.com" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+	if len(api.keys) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(api.keys))
+	}
+	if api.keys[0] == "" || api.keys[0] != api.keys[1] || api.keys[1] != api.keys[2] {
+		t.Fatalf("attempts must share one idempotency key, got %q", api.keys)
+	}
+}
+
+func TestCreateUserUsesIdempotencyKeyFromContext(t *testing.T) {
+	api, server := newFakeAPI(t)
+	c := newTestClient(t, server)
+	ctx := client.WithIdempotencyKey(context.Background(), "import-42")
+	req := client.CreateUserRequest{Email: "alice@example.com", Name: "Alice", Password: "Secret123!"}
+
+	first, err := c.CreateUser(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rejeu applicatif avec la même clé : même utilisateur, pas de doublon
+	second, err := c.CreateUser(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != second.ID || len(api.users) != 1 {
+		t.Fatalf("replay created a duplicate: %d / %d (%d users)", first.ID, second.ID, len(api.users))
+	}
+	if api.keys[0] != "import-42" {
+		t.Fatalf("expected context key, got %q", api.keys[0])
+	}
+}
+
+func TestClientErrorsAreNotRetried(t *testing.T) {
+	api, server := newFakeAPI(t)
+	c := newTestClient(t, server)
+
+	_, err := c.CreateUser(context.Background(), client.CreateUserRequest{Name: "Alice"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "email obligatoire" {
+		t.Fatalf("expected 400 API error, got %v", err)
+	}
+	if len(api.keys) != 1 {
+		t.Fatalf("expected a single attempt, got %d", len(api.keys))
+	}
+}
+
+func TestQuotaExceededIsNotRetriedWithoutRetryAfter(t *testing.T) {
+	api, server := newFakeAPI(t)
+	api.failures = []int{http.StatusTooManyRequests}
+	c := newTestClient(t, server)
+
+	_, err := c.CreateUser(context.Background(), client.CreateUserRequest{Email: "alice@example.com"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "quota_exceeded" {
+		t.Fatalf("expected quota_exceeded, got %v", err)
+	}
+	if len(api.keys) != 1 {
+		t.Fatalf("expected a single attempt, got %d", len(api.keys))
+	}
+}
+
+func TestRetryAfterIsHonored(t *testing.T) {
+	api, server := newFakeAPI(t)
+	api.failures = []int{http.StatusTooManyRequests}
+	api.retryAfter = "0"
+	c := newTestClient(t, server)
+
+	if _, err := c.TrackEvent(context.Background(), client.TrackEventRequest{Event: "page_viewed"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.events) != 1 {
+		t.Fatalf("expected the retried event to be stored, got %d", len(api.events))
+	}
+}
+
+func TestRetriesAreBounded(t *testing.T) {
+	api, server := newFakeAPI(t)
+	api.failures = []int{503, 503, 503, 503, 503}
+	c := newTestClient(t, server, client.WithRetries(2, time.Millisecond))
+
+	_, err := c.CreateUser(context.Background(), client.CreateUserRequest{Email: "alice@example.com"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the last 503, got %v", err)
+	}
+	if len(api.keys) != 3 {
+		t.Fatalf("expected 1 attempt + 2 retries, got %d", len(api.keys))
+	}
+}
+
+func TestContextCancellationStopsRetries(t *testing.T) {
+	api, server := newFakeAPI(t)
+	api.failures = []int{503, 503, 503, 503}
+	c := newTestClient(t, server, client.WithRetries(3, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.CreateUser(ctx, client.CreateUserRequest{Email: "alice@example.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if len(api.keys) != 1 {
+		t.Fatalf("expected a single attempt before cancellation, got %d", len(api.keys))
+	}
+}
+
+func TestCredentialsLoginOnceAndRefreshAfterUnauthorized(t *testing.T) {
+	api, server := newFakeAPI(t)
+	c := newTestClient(t, server, client.WithCredentials("ops@example.com", "secret"))
+	ctx := context.Background()
+
+	created, err := c.CreateUser(ctx, client.CreateUserRequest{Email: "alice@example.com", Name: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetUser(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if api.logins != 1 {
+		t.Fatalf("expected the session to be reused, got %d logins", api.logins)
+	}
+
+	// Jeton révoqué côté serveur : une reconnexion, puis l'appel aboutit
+	api.mutex.Lock()
+	api.validToken = "revoked"
+	api.mutex.Unlock()
+	if _, err := c.GetUser(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if api.logins != 2 {
+		t.Fatalf("expected a new login after 401, got %d logins", api.logins)
+	}
+}
+
+func TestInvalidCredentialsAreReported(t *testing.T) {
+	_, server := newFakeAPI(t)
+	c := newTestClient(t, server, client.WithCredentials("ops@example.com", "wrong"))
+
+	_, err := c.GetUser(context.Background(), 1)
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "invalid_credentials" {
+		t.Fatalf("expected invalid_credentials, got %v", err)
+	}
+}
+
+func TestGetUserNotFound(t *testing.T) {
+	_, server := newFakeAPI(t)
+	c := newTestClient(t, server, client.WithToken("static"))
+
+	_, err := c.GetUser(context.Background(), 42)
+	if !client.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestTrackEventSendsTimestampAndProperties(t *testing.T) {
+	api, server := newFakeAPI(t)
+	c := newTestClient(t, server)
+	at := time.Date(2026, 3, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	response, err := c.TrackEvent(context.Background(), client.TrackEventRequest{
+		Event:      "checkout_completed",
+		Properties: map[string]any{"plan": "pro", "amount": 49.0},
+		Timestamp:  at,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != client.TrackAccepted {
+		t.Fatalf("unexpected status %q", response.Status)
+	}
+	event := api.events[0]
+	if event["event"] != "checkout_completed" || event["timestamp"] != "2026-03-01T09:30:00Z" {
+		t.Fatalf("unexpected payload %v", event)
+	}
+	if properties, _ := event["properties"].(map[string]any); properties["plan"] != "pro" {
+		t.Fatalf("unexpected properties %v", event["properties"])
+	}
+}
+
+func TestNewRejectsInvalidBaseURL(t *testing.T) {
+	if _, err := client.New("api.example.com"); err == nil {
+		t.Fatal("expected an error for a base URL without scheme")
+	}
+}
@@ -0,0 +1,83 @@
+package client_test
+
+import (
+	"clean-archi-analytics/pkg/client"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// exampleServer API minimale pour des exemples exécutables
+func exampleServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /auth/login", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "token", "expires_at": time.Now().Add(time.Hour)})
+	})
+	mux.HandleFunc("POST /v1/users", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusCreated, client.CreatedUser{ID: 7, Email: "alice@example.com", Name: "Alice Martin"})
+	})
+	mux.HandleFunc("GET /v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "utilisateur introuvable"})
+	})
+	mux.HandleFunc("POST /v1/analytics/track", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusAccepted, client.TrackEventResponse{Status: client.TrackAccepted})
+	})
+	return httptest.NewServer(mux)
+}
+
+func ExampleClient_CreateUser() {
+	server := exampleServer()
+	defer server.Close()
+
+	api, err := client.New(server.URL, client.WithCredentials("ops@example.com", "secret"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Clé métier : un rejeu de l'import ne recrée pas l'utilisateur
+	ctx := client.WithIdempotencyKey(context.Background(), "hr-import-2026-03-01-alice")
+	user, err := api.CreateUser(ctx, client.CreateUserRequest{
+		Email:    "alice@example.com",
+		Name:     "Alice Martin",
+		Password: "Secret123!",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(user.ID, user.Email)
+	// Output: 7 alice@example.com
+}
+
+func ExampleClient_GetUser() {
+	server := exampleServer()
+	defer server.Close()
+
+	api, err := client.New(server.URL, client.WithToken("service-token"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = api.GetUser(context.Background(), 42)
+	fmt.Println(client.IsNotFound(err))
+	// Output: true
+}
+
+func ExampleClient_TrackEvent() {
+	server := exampleServer()
+	defer server.Close()
+
+	api, err := client.New(server.URL, client.WithRetries(5, 100*time.Millisecond))
+	if err != nil {
+		log.Fatal(err)
+	}
+	response, err := api.TrackEvent(context.Background(), client.TrackEventRequest{
+		Event:      "page_viewed",
+		Properties: map[string]any{"path": "/pricing"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(response.Status)
+	// Output: accepted
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CreateUserRequest entrée de POST /users (use case create_user)
+type CreateUserRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// CreatedUser sortie de POST /users
+type CreatedUser struct {
+	ID      int       `json:"id"`
+	Email   string    `json:"email"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+// User sortie de GET /users/{id} (modèle de lecture : peut retarder de quelques millisecondes
+// sur une écriture)
+type User struct {
+	ID          int            `json:"id"`
+	Email       string         `json:"email"`
+	Name        string         `json:"name"`
+	Created     time.Time      `json:"created"`
+	Updated     time.Time      `json:"updated"`
+	Status      string         `json:"status"`
+	Handle      string         `json:"handle,omitempty"`
+	LastLoginAt *time.Time     `json:"last_login_at,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+// CreateUser crée un utilisateur ; un nouvel essai après une coupure rejoue la réponse
+// de la première création (clé d'idempotence) au lieu d'échouer sur l'email déjà pris
+func (c *Client) CreateUser(ctx context.Context, req CreateUserRequest) (*CreatedUser, error) {
+	var user CreatedUser
+	if err := c.do(ctx, http.MethodPost, "/v1/users", req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUser lit un utilisateur ; IsNotFound(err) s'il n'existe pas
+func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodGet, "/v1/users/"+strconv.Itoa(id), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
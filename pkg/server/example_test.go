@@ -0,0 +1,48 @@
+package server_test
+
+import (
+	"clean-archi-analytics/pkg/server"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+)
+
+// quietLogger les journaux de l'API ne se mêlent pas à la sortie des exemples
+type quietLogger struct{}
+
+func (quietLogger) Info(string, map[string]interface{})         {}
+func (quietLogger) Warn(string, map[string]interface{})         {}
+func (quietLogger) Error(string, error, map[string]interface{}) {}
+
+func ExampleServer_Handler() {
+	os.Setenv("JWT_SECRET", "example-secret-of-at-least-32-bytes!")
+	cfg, err := server.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Pas de serveur HTTP propre : l'API est servie sous /analytics par le routeur de l'hôte
+	srv, err := server.New(cfg, server.WithAddr(""), server.WithLogger(quietLogger{}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		log.Fatal(err)
+	}
+	defer srv.Stop(context.Background())
+
+	mux := http.NewServeMux()
+	mux.Handle("/analytics/", http.StripPrefix("/analytics", srv.Handler()))
+	host := httptest.NewServer(mux)
+	defer host.Close()
+
+	resp, err := http.Get(host.URL + "/analytics/health")
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp.Body.Close()
+	fmt.Println(resp.StatusCode)
+	// Output: 200
+}
@@ -0,0 +1,292 @@
+// Package server embarque l'API (utilisateurs, analytics) dans un autre programme Go : même
+// assemblage que cmd/api, ports remplaçables par l'application hôte
+//
+//	cfg, err := server.LoadConfig()
+//	srv, err := server.New(cfg, server.WithAddr(":9090"), server.WithUserRepository(repo))
+//	err = srv.Start()
+//	defer srv.Stop(ctx)
+//
+// Ou monté sous le routeur de l'hôte, sans serveur HTTP propre :
+//
+//	srv, err := server.New(cfg, server.WithAddr(""))
+//	mux.Handle("/analytics/", http.StripPrefix("/analytics", srv.Handler()))
+//	err = srv.Start() // traitements périodiques uniquement
+package server
+
+import (
+	"clean-archi-analytics/internal/bootstrap"
+	"clean-archi-analytics/internal/config"
+	"clean-archi-analytics/internal/domain/entities"
+	"clean-archi-analytics/internal/domain/repositories"
+	"clean-archi-analytics/internal/domain/usecases"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// PORTS
+// =============================================================================
+
+// Contrats des ports remplaçables, exposés tels quels : une implémentation de l'hôte
+// satisfait directement les interfaces du domaine
+type (
+	Config                = config.Config
+	Logger                = usecases.Logger
+	ErrorReporter         = usecases.ErrorReporter
+	EmailSender           = usecases.EmailSender
+	Clock                 = usecases.Clock
+	UserRepository        = repositories.UserRepository
+	UserRepositoryFilters = repositories.UserRepositoryFilters
+	EventRepository       = repositories.EventRepository
+	EventFilters          = repositories.EventFilters
+	EventCondition        = repositories.EventCondition
+	EventAnd              = repositories.EventAnd
+	EventOr               = repositories.EventOr
+	EventNot              = repositories.EventNot
+	EventComparison       = repositories.EventComparison
+	User                  = entities.User
+	TrackedEvent          = entities.TrackedEvent
+)
+
+// Erreurs attendues des implémentations de UserRepository
+var (
+	ErrUserNotFound         = repositories.ErrUserNotFound
+	ErrHandleTaken          = repositories.ErrHandleTaken
+	ErrFullTextNotSupported = repositories.ErrFullTextNotSupported
+	ErrStopIteration        = repositories.ErrStopIteration
+)
+
+// LoadConfig configuration lue dans les variables d'environnement, comme cmd/api
+func LoadConfig() (*Config, error) {
+	return config.Load()
+}
+
+// =============================================================================
+// HOOKS
+// =============================================================================
+
+// Hooks règles métier de l'hôte, exécutées avant et après les use cases (voir WithHooks)
+//
+//	hooks := server.NewHooks()
+//	server.BeforeUserCreate(hooks, func(ctx context.Context, input server.CreateUserRequest) error {
+//		if !strings.HasSuffix(input.Email, "@acme.com") {
+//			return errors.New("seules les adresses @acme.com sont acceptées") // HTTP 422
+//		}
+//		return nil
+//	})
+type Hooks = usecases.Hooks
+
+// Entrées et sorties des use cases exposés aux hooks
+type (
+	CreateUserRequest  = usecases.CreateUserRequest
+	CreateUserResponse = usecases.CreateUserResponse
+	UpdateUserRequest  = usecases.UpdateUserRequest
+	UpdateUserResponse = usecases.UpdateUserResponse
+)
+
+// ErrRejectedByHook cause des erreurs d'un hook Before (errors.Is) ; les hooks After ne sont alors pas appelés
+var ErrRejectedByHook = usecases.ErrRejectedByHook
+
+func NewHooks() *Hooks {
+	return usecases.NewHooks()
+}
+
+// BeforeUserCreate une erreur refuse la création (le message est renvoyé au client)
+func BeforeUserCreate(h *Hooks, fn func(ctx context.Context, input CreateUserRequest) error) {
+	usecases.BeforeUserCreate(h, fn)
+}
+
+// AfterUserCreate appelé après chaque création, réussie ou non (output nil si err)
+func AfterUserCreate(h *Hooks, fn func(ctx context.Context, input CreateUserRequest, output *CreateUserResponse, err error)) {
+	usecases.AfterUserCreate(h, fn)
+}
+
+func BeforeUserUpdate(h *Hooks, fn func(ctx context.Context, input UpdateUserRequest) error) {
+	usecases.BeforeUserUpdate(h, fn)
+}
+
+func AfterUserUpdate(h *Hooks, fn func(ctx context.Context, input UpdateUserRequest, output *UpdateUserResponse, err error)) {
+	usecases.AfterUserUpdate(h, fn)
+}
+
+func BeforeUserDelete(h *Hooks, fn func(ctx context.Context, id int) error) {
+	usecases.BeforeUserDelete(h, fn)
+}
+
+func AfterUserDelete(h *Hooks, fn func(ctx context.Context, id int, err error)) {
+	usecases.AfterUserDelete(h, fn)
+}
+
+// =============================================================================
+// OPTIONS
+// =============================================================================
+
+// Option configure un Server
+type Option func(*Server)
+
+// WithAddr adresse d'écoute à la place de HTTP_ADDR ; "" désactive le serveur HTTP (Handler monté par l'hôte)
+func WithAddr(addr string) Option {
+	return func(s *Server) { s.addr = addr }
+}
+
+// WithListener écoute sur un listener fourni (port attribué par l'hôte, socket activée...) ; prioritaire sur WithAddr
+func WithListener(listener net.Listener) Option {
+	return func(s *Server) { s.listener = listener }
+}
+
+// WithLogger remplace le logger JSON sur la sortie standard
+func WithLogger(logger Logger) Option {
+	return func(s *Server) { s.ports.Logger = logger }
+}
+
+// WithErrorReporter remplace le rapporteur déduit de ERROR_REPORTER
+func WithErrorReporter(reporter ErrorReporter) Option {
+	return func(s *Server) { s.ports.ErrorReporter = reporter }
+}
+
+// WithUserRepository remplace le stockage des utilisateurs (PERSISTENCE_MODE ignoré pour les utilisateurs) ;
+// le dépôt reste décoré (journalisation, Elasticsearch, injection de pannes) comme celui par défaut
+func WithUserRepository(repo UserRepository) Option {
+	return func(s *Server) { s.ports.UserRepository = repo }
+}
+
+// WithEventRepository remplace le stockage des événements analytics (tampon d'écriture conservé)
+func WithEventRepository(repo EventRepository) Option {
+	return func(s *Server) { s.ports.EventRepository = repo }
+}
+
+// WithEmailSender remplace l'envoi des emails (journalisés par défaut)
+func WithEmailSender(sender EmailSender) Option {
+	return func(s *Server) { s.ports.EmailSender = sender }
+}
+
+// WithHooks installe les règles métier de l'hôte ; d'autres hooks peuvent y être ajoutés après New
+func WithHooks(hooks *Hooks) Option {
+	return func(s *Server) { s.ports.Hooks = hooks }
+}
+
+// WithClock remplace l'horloge système (tests de l'hôte)
+func WithClock(clock Clock) Option {
+	return func(s *Server) { s.ports.Clock = clock }
+}
+
+// =============================================================================
+// SERVEUR
+// =============================================================================
+
+// Server API assemblée ; Start et Stop ne doivent être appelés qu'une fois
+type Server struct {
+	app      *bootstrap.App
+	ports    bootstrap.Ports
+	addr     string
+	listener net.Listener
+
+	mutex      sync.Mutex
+	httpServer *http.Server
+	serveErr   chan error
+}
+
+// New assemble l'API (dépôts, use cases, routeur) sans rien démarrer ; cfg n'est pas revalidée
+// (LoadConfig le fait) et ne doit plus être modifiée ensuite
+func New(cfg *Config, opts ...Option) (*Server, error) {
+	if cfg == nil {
+		return nil, errors.New("server: configuration manquante")
+	}
+	s := &Server{addr: cfg.HTTPAddr}
+	for _, opt := range opts {
+		opt(s)
+	}
+	app, err := bootstrap.Build(context.Background(), cfg, s.ports)
+	if err != nil {
+		return nil, err
+	}
+	s.app = app
+	return s, nil
+}
+
+// Handler API complète (middlewares transverses compris), à monter sous le routeur de l'hôte ;
+// les routes restent celles de l'API (/v1/users...) : http.StripPrefix retire un éventuel préfixe
+func (s *Server) Handler() http.Handler {
+	return s.app.Handler
+}
+
+// Start lance les traitements périodiques puis, sauf WithAddr(""), le serveur HTTP en arrière-plan ;
+// une adresse déjà prise est signalée ici, une panne ultérieure du serveur par Stop
+func (s *Server) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.httpServer != nil {
+		return errors.New("server: déjà démarré")
+	}
+
+	// DEBUG_ADDR : libéré par Stop, même si le démarrage échoue ensuite
+	if err := s.app.ServeDiagnostics(); err != nil {
+		return err
+	}
+	listener := s.listener
+	if listener == nil && s.addr != "" {
+		var err error
+		if listener, err = net.Listen("tcp", s.addr); err != nil {
+			return err
+		}
+	}
+	s.app.StartJobs()
+
+	s.httpServer = &http.Server{Handler: s.app.Handler, ReadHeaderTimeout: 5 * time.Second}
+	s.serveErr = make(chan error, 1)
+	if listener == nil {
+		close(s.serveErr)
+		return nil
+	}
+	s.listener = listener
+	s.app.Logger.Info("HTTP server listening", map[string]interface{}{"addr": listener.Addr().String()})
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.serveErr <- err
+		}
+		close(s.serveErr)
+	}()
+	return nil
+}
+
+// Addr adresse effectivement écoutée (port attribué avec ":0"), nil avant Start ou sans serveur HTTP
+func (s *Server) Addr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.httpServer == nil || s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Reload relit les réglages rechargeables (niveau de log, quotas, feature flags locaux), comme
+// SIGHUP pour cmd/api ; en cas d'erreur, les réglages en place sont conservés
+func (s *Server) Reload() error {
+	return s.app.ReloadSettings()
+}
+
+// Stop arrête le serveur HTTP (requêtes en cours terminées), attend les tâches asynchrones, écrit les
+// événements en tampon puis ferme les dépôts ; ctx borne l'attente. Appelable sans Start
+func (s *Server) Stop(ctx context.Context) error {
+	s.mutex.Lock()
+	httpServer, serveErr := s.httpServer, s.serveErr
+	s.mutex.Unlock()
+
+	var errs []error
+	if httpServer != nil {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		if err := <-serveErr; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := s.app.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}